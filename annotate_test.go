@@ -0,0 +1,187 @@
+package tfprovidertest
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	goanalysis "golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/annotate"
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const annotateWidgetSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+// WidgetResource manages widgets.
+//
+//tftest:exempt drift reason="destroy is a no-op"
+type WidgetResource struct{}
+
+func (r *WidgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{Required: true},
+		},
+	}
+}
+`
+
+const annotateGadgetTypesSrc = `package provider
+
+type (
+	// GadgetResource manages gadgets.
+	GadgetResource struct{}
+
+	gadgetModel struct{}
+)
+`
+
+const annotateGadgetSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+func (r *GadgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{}
+}
+`
+
+const annotateSprocketSrc = `package provider
+
+import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+func resourceSprocket() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{},
+	}
+}
+`
+
+const annotateWidgetTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccWidget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccWidgetConfig},
+			{ResourceName: "example_widget.test", ImportState: true, ImportStateVerify: true},
+		},
+	})
+}
+`
+
+// annotateFixture writes the sources to dir and builds a registry from the files on disk.
+func annotateFixture(t *testing.T, dir string, sources map[string]string) (*token.FileSet, *registry.ResourceRegistry) {
+	t.Helper()
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for name, src := range sources {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(src), 0o644))
+		f, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+		require.NoError(t, err)
+		files = append(files, f)
+	}
+	return fset, discovery.BuildRegistry(&goanalysis.Pass{Fset: fset, Files: files}, config.DefaultSettings())
+}
+
+func TestAnnotate(t *testing.T) {
+	dir := t.TempDir()
+	sources := map[string]string{
+		"resource_widget.go":       annotateWidgetSrc,
+		"resource_widget_test.go":  annotateWidgetTestSrc,
+		"resource_gadget.go":       annotateGadgetSrc,
+		"resource_gadget_types.go": annotateGadgetTypesSrc,
+		"resource_sprocket.go":     annotateSprocketSrc,
+	}
+	fset, reg := annotateFixture(t, dir, sources)
+
+	annotations, err := annotate.Apply(fset, reg, false)
+	require.NoError(t, err)
+	require.Len(t, annotations, 3)
+	statuses := make(map[string]string)
+	for _, a := range annotations {
+		statuses[a.Definition] = a.Status
+	}
+	assert.Equal(t, map[string]string{
+		"resource:gadget":   annotate.StatusAdded,
+		"resource:sprocket": annotate.StatusAdded,
+		"resource:widget":   annotate.StatusAdded,
+	}, statuses)
+
+	read := func(name string) string {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		return string(content)
+	}
+	widgetLine := "// Test coverage: basic ✓ update ✓ import ✓ error ✗ state-check ✗ drift ✗ disappears ✗ (generated by tfprovidertest)"
+	assert.Contains(t, read("resource_widget.go"), "// WidgetResource manages widgets.\n//\n"+widgetLine+
+		"\n//\n//tftest:exempt drift reason=\"destroy is a no-op\"\ntype WidgetResource struct{}\n",
+		"the line goes before trailing directives, which gofmt keeps last")
+	untested := "// Test coverage: basic ✗ update ✗ import ✗ error ✗ state-check ✗ drift ✗ disappears ✗ (generated by tfprovidertest)"
+	assert.Contains(t, read("resource_gadget_types.go"), "\t// GadgetResource manages gadgets.\n\t//\n\t"+untested+"\n\tGadgetResource struct{}\n",
+		"the type is found in another file of the package and keeps its indentation")
+	assert.Contains(t, read("resource_sprocket.go"), "\n"+untested+"\nfunc resourceSprocket() *schema.Resource {",
+		"SDK v2 definitions are annotated on their schema function")
+
+	t.Run("idempotent", func(t *testing.T) {
+		before := read("resource_widget.go") + read("resource_gadget_types.go") + read("resource_sprocket.go")
+		fset, reg := annotateFixture(t, dir, map[string]string{
+			"resource_widget.go":       read("resource_widget.go"),
+			"resource_widget_test.go":  annotateWidgetTestSrc,
+			"resource_gadget.go":       annotateGadgetSrc,
+			"resource_gadget_types.go": read("resource_gadget_types.go"),
+			"resource_sprocket.go":     read("resource_sprocket.go"),
+		})
+		annotations, err := annotate.Apply(fset, reg, false)
+		require.NoError(t, err)
+		for _, a := range annotations {
+			assert.Equal(t, annotate.StatusUnchanged, a.Status, a.Definition)
+		}
+		assert.Equal(t, before, read("resource_widget.go")+read("resource_gadget_types.go")+read("resource_sprocket.go"))
+	})
+
+	t.Run("refreshes an outdated line in place", func(t *testing.T) {
+		stale := strings.Replace(read("resource_widget.go"), "import ✓", "import ✗", 1)
+		fset, reg := annotateFixture(t, dir, map[string]string{
+			"resource_widget.go":      stale,
+			"resource_widget_test.go": annotateWidgetTestSrc,
+		})
+
+		annotations, err := annotate.Apply(fset, reg, true)
+		require.NoError(t, err)
+		require.Len(t, annotations, 1)
+		assert.Equal(t, annotate.StatusUpdated, annotations[0].Status)
+		assert.Equal(t, stale, read("resource_widget.go"), "a dry run writes nothing")
+
+		_, err = annotate.Apply(fset, reg, false)
+		require.NoError(t, err)
+		assert.Equal(t, 1, strings.Count(read("resource_widget.go"), annotate.Marker))
+		assert.Contains(t, read("resource_widget.go"), widgetLine)
+	})
+}
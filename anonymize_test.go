@@ -0,0 +1,67 @@
+package tfprovidertest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/anonymize"
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+func TestAnonymize(t *testing.T) {
+	a := anonymize.New("salt")
+
+	t.Run("names hash consistently per segment", func(t *testing.T) {
+		assert.Equal(t, a.Name("example_widget"), anonymize.New("salt").Name("example_widget"), "stable for a salt")
+		assert.NotEqual(t, a.Name("example_widget"), anonymize.New("pepper").Name("example_widget"))
+
+		widget, bucket := a.Name("example_widget"), a.Name("example_bucket")
+		assert.NotContains(t, widget, "widget")
+		assert.Len(t, strings.Split(widget, "_"), 2)
+		assert.Equal(t, strings.Split(widget, "_")[0], strings.Split(bucket, "_")[0], "shared prefix kept")
+
+		test := a.TestName("TestAccWidget_basic")
+		assert.True(t, strings.HasPrefix(test, "TestAcc"))
+		assert.Equal(t, "TestAcc"+a.Name("widget_basic"), test, "segments hash case-insensitively")
+	})
+
+	t.Run("paths keep their structure", func(t *testing.T) {
+		path := a.Path("/src/internal/service/widget_test.go")
+		assert.True(t, strings.HasPrefix(path, "/"))
+		assert.True(t, strings.HasSuffix(path, "_test.go"))
+		assert.Len(t, strings.Split(path, "/"), 5)
+		assert.Equal(t, a.Path("/src/internal/service/widget.go"), strings.TrimSuffix(path, "_test.go")+".go")
+	})
+
+	t.Run("registry keeps links and counts", func(t *testing.T) {
+		reg := buildRegistryFromSources(t, map[string]string{
+			"/provider/resource_widget.go":      upgradeWidgetResourceSrc,
+			"/provider/data_source_gadget.go":   gadgetDataSourceSrc,
+			"/provider/resource_widget_test.go": builderWidgetTestSrc,
+		})
+		anon := a.Registry(reg)
+
+		require.Len(t, anon.GetAllDefinitions(), len(reg.GetAllDefinitions()))
+		require.Len(t, anon.GetAllTestFunctions(), len(reg.GetAllTestFunctions()))
+		assert.Nil(t, anon.GetDefinition(registry.KindResource, "widget"))
+
+		widget := anon.GetDefinition(registry.KindResource, a.Name("widget"))
+		require.NotNil(t, widget)
+		assert.Equal(t, a.Path("/provider/resource_widget.go"), widget.FilePath)
+
+		tests := anon.GetTests(registry.KindResource, a.Name("widget"))
+		require.Len(t, tests, len(reg.GetTests(registry.KindResource, "widget")))
+		for _, fn := range tests {
+			assert.NotContains(t, fn.Name, "Storage")
+			for _, step := range fn.TestSteps {
+				assert.Empty(t, step.Config)
+				assert.Empty(t, step.ExpectErrorPattern)
+			}
+		}
+		assert.Len(t, anon.GetTests(registry.KindDataSource, a.Name("gadget")), 1)
+		assert.Equal(t, "TestAccStorage_basic", findTest(t, reg, "TestAccStorage_basic").Name, "the original is untouched")
+	})
+}
@@ -0,0 +1,55 @@
+package tfprovidertest
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/artifact"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+func TestSummaryArtifact(t *testing.T) {
+	settings := config.DefaultSettings()
+	first, err := artifact.Fingerprint(settings)
+	require.NoError(t, err)
+	again, err := artifact.Fingerprint(config.DefaultSettings())
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(first, "sha256:"))
+	assert.Equal(t, first, again, "equal settings have the same fingerprint")
+
+	settings.EnableIdempotencyCheck = true
+	changed, err := artifact.Fingerprint(settings)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, changed, "a changed setting changes the fingerprint")
+
+	tool := artifact.CurrentTool()
+	assert.Equal(t, "tfprovidertest", tool.Name)
+	assert.NotEmpty(t, tool.Version)
+	assert.NotEmpty(t, tool.GoVersion)
+
+	path := filepath.Join(t.TempDir(), artifact.DefaultFile)
+	summary := artifact.Summary{
+		SchemaVersion:       artifact.SchemaVersion,
+		Tool:                tool,
+		SettingsFingerprint: first,
+		Rules:               []string{"tfprovider-coverage-basic-test"},
+		Totals:              artifact.Totals{Definitions: 4, Tested: 3, Coverage: 0.75, Findings: 1},
+		TopFindings: []artifact.Finding{
+			{Rule: "tfprovider-coverage-basic-test", Level: "error", File: "resource_widget.go", Line: 12, Message: "resource 'widget' has no acceptance test"},
+		},
+	}
+	require.NoError(t, artifact.Write(path, summary))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var decoded artifact.Summary
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, summary, decoded)
+	assert.Contains(t, string(data), `"schema_version": 1`)
+}
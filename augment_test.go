@@ -0,0 +1,124 @@
+package tfprovidertest
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	goanalysis "golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/augment"
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const augmentWidgetSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+type WidgetResource struct{}
+
+func (r *WidgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{Required: true},
+			"note": schema.StringAttribute{Optional: true},
+		},
+	}
+}
+`
+
+const augmentWidgetTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccWidget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: testAccWidgetConfig,
+			},
+			{
+				Config: testAccWidgetConfig,
+				Check:  resource.TestCheckResourceAttr("example_widget.test", "name", "a"),
+			},
+			{Config: testAccWidgetConfig},
+		},
+	})
+}
+
+const testAccWidgetConfig = ` + "`" + `
+resource "example_widget" "test" {
+  name = "a"
+}
+` + "`" + `
+`
+
+func TestAugmentTests(t *testing.T) {
+	dir := t.TempDir()
+	sources := map[string]string{
+		"resource_widget.go":      augmentWidgetSrc,
+		"resource_widget_test.go": augmentWidgetTestSrc,
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for name, src := range sources {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(src), 0o644))
+		f, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+		require.NoError(t, err)
+		files = append(files, f)
+	}
+	reg := discovery.BuildRegistry(&goanalysis.Pass{Fset: fset, Files: files}, config.DefaultSettings())
+
+	suggestions := augment.Suggest(fset, reg)
+	require.Len(t, suggestions, 2, "the step with Check is left alone")
+	assert.Equal(t, "TestAccWidget_basic", suggestions[0].Test)
+	assert.Equal(t, 13, suggestions[0].Line)
+	assert.Equal(t, []augment.Check{{Address: "example_widget.test", Attribute: "name"}}, suggestions[0].Checks)
+	assert.Equal(t, "ConfigStateChecks: []statecheck.StateCheck{\n"+
+		"\tstatecheck.ExpectKnownValue(\"example_widget.test\", tfjsonpath.New(\"name\"), knownvalue.NotNull()),\n"+
+		"},", suggestions[0].Code)
+
+	patch, skipped, err := augment.Patch(fset, suggestions, dir)
+	require.NoError(t, err)
+	require.Len(t, skipped, 1, "a step written on one line has no line to insert after")
+	assert.Equal(t, 19, skipped[0].Line)
+	assert.Equal(t, `--- a/resource_widget_test.go
++++ b/resource_widget_test.go
+@@ -4,6 +4,9 @@
+ 	"testing"
+ 
+ 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
++	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
++	"github.com/hashicorp/terraform-plugin-testing/statecheck"
++	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+ )
+ 
+ func TestAccWidget_basic(t *testing.T) {
+@@ -11,6 +14,9 @@
+ 		Steps: []resource.TestStep{
+ 			{
+ 				Config: testAccWidgetConfig,
++				ConfigStateChecks: []statecheck.StateCheck{
++					statecheck.ExpectKnownValue("example_widget.test", tfjsonpath.New("name"), knownvalue.NotNull()),
++				},
+ 			},
+ 			{
+ 				Config: testAccWidgetConfig,
+`, patch)
+}
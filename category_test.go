@@ -0,0 +1,35 @@
+package tfprovidertest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+func TestDefinitionCategories(t *testing.T) {
+	documented := strings.Replace(upgradeWidgetResourceSrc, "type WidgetResource struct{}",
+		"// WidgetResource manages widgets.\n//\n// Category: Networking\ntype WidgetResource struct{}", 1)
+	legacy := strings.Replace(upgradeLegacyResourceSrc, "package provider", "package ec2", 1)
+
+	reg := buildRegistryFromSources(t, map[string]string{
+		"/provider/internal/service/vpc/resource_widget.go": documented,
+		"/provider/internal/service/ec2/resource_legacy.go": legacy,
+		"/provider/internal/provider/data_source_gadget.go": gadgetDataSourceSrc,
+	})
+
+	widget := reg.GetDefinition(registry.KindResource, "widget")
+	require.NotNil(t, widget)
+	assert.Equal(t, "Networking", widget.Category, "the doc comment wins over the directory")
+
+	legacyDef := reg.GetDefinition(registry.KindResource, "legacy")
+	require.NotNil(t, legacyDef)
+	assert.Equal(t, "ec2", legacyDef.Category, "inferred from the service directory")
+
+	gadget := reg.GetDefinition(registry.KindDataSource, "gadget")
+	require.NotNil(t, gadget)
+	assert.Empty(t, gadget.Category, "provider is a generic directory")
+}
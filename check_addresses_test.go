@@ -0,0 +1,102 @@
+package tfprovidertest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const checkAddressesTestSrc = `package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+const testAccGadgetConfig = ` + "`" + `
+resource "example_gadget" "main" {
+  name = "a"
+}
+` + "`" + `
+
+func TestAccGadget_basic(t *testing.T) {
+	resourceName := "example_gadget.main"
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGadgetConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", "a"),
+					resource.TestCheckResourceAttr("example_gadget.main.0", "name", "a"),
+					resource.TestCheckResourceAttr("example_gadget.test", "name", "a"),
+					resource.TestCheckResourceAttrPair("example_gadget.main", "id", "data.example_gadget.main", "id"),
+					resource.TestCheckModuleResourceAttr("module.child", "example_gadget.other", "name", "a"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccGadget_dynamic(t *testing.T) {
+	name := fmt.Sprintf("gadget-%d", 1)
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(` + "`" + `resource "example_gadget" "%s" {}` + "`" + `, name),
+				Check:  resource.TestCheckResourceAttr("example_gadget.anything", "name", "a"),
+			},
+			{
+				Config: ` + "`" + `resource "example_sprocket" "one" {}` + "`" + `,
+				Check:  resource.TestCheckResourceAttr(fmt.Sprintf("example_gadget.%s", name), "name", "a"),
+			},
+		},
+	})
+}
+`
+
+func TestCheckAddresses(t *testing.T) {
+	t.Run("checked resource", func(t *testing.T) {
+		for address, want := range map[string]string{
+			"example_widget.test":         "example_widget.test",
+			"example_widget.test.0":       "example_widget.test",
+			`example_widget.test["a"]`:    "example_widget.test",
+			"data.example_widget.test.id": "data.example_widget.test",
+		} {
+			got, ok := discovery.CheckedResource(address)
+			assert.True(t, ok, address)
+			assert.Equal(t, want, got, address)
+		}
+		for _, address := range []string{"module.child.example_widget.test", "example_widget"} {
+			_, ok := discovery.CheckedResource(address)
+			assert.False(t, ok, address)
+		}
+	})
+
+	t.Run("analyzer", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		settings.EnableCheckAddressCheck = true
+		messages := runAnalyzerOnSources(t, analysis.RunCheckAddressesAnalyzer, settings, map[string]string{
+			"/provider/resource_gadget.go":      untestedGadgetResourceSrc,
+			"/provider/resource_gadget_test.go": checkAddressesTestSrc,
+		})
+
+		require.Len(t, messages, 2, strings.Join(messages, "\n\n"))
+		assert.Contains(t, messages[0], "TestCheckResourceAttr in step 1 of test 'TestAccGadget_basic' checks 'example_gadget.test'")
+		assert.Contains(t, messages[0], "Did you mean 'example_gadget.main'?")
+		assert.Contains(t, messages[1], "TestCheckResourceAttrPair in step 1 of test 'TestAccGadget_basic' checks 'data.example_gadget.main'")
+		assert.Contains(t, messages[1], "Declared: example_gadget.main")
+	})
+
+	t.Run("settings", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		assert.False(t, settings.CheckAddressCheckEnabled(), "the rule is opt-in")
+	})
+}
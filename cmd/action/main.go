@@ -0,0 +1,195 @@
+// Command action is the entrypoint of the repository's GitHub Action (action.yml). It scans
+// a provider with the validate command, compares the coverage report with the report of the
+// pull request's base branch, writes the comparison to the job summary, posts or updates a
+// sticky comment on the pull request, and sets the coverage and new_gaps step outputs.
+//
+// Flags default to the action's inputs (INPUT_*) and the variables GitHub sets for each
+// step, so a workflow needs no arguments. The base report is the -base-report file when
+// given, else a scan of -base-ref checked out in a temporary git worktree; the checkout must
+// have that ref's history (actions/checkout with fetch-depth: 0).
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/example/tfprovidertest/internal/ghaction"
+	"github.com/example/tfprovidertest/pkg/metrics"
+)
+
+func main() {
+	provider := flag.String("provider", envOr("INPUT_PROVIDER", "."), "Path to the Terraform provider directory")
+	validate := flag.String("validate", envOr("INPUT_VALIDATE", "validate"), "Path of the validate command")
+	validateArgs := flag.String("args", os.Getenv("INPUT_ARGS"), "Extra validate flags, separated by spaces (e.g., -recursive -profile strict)")
+	baseRef := flag.String("base-ref", envOr("INPUT_BASE_REF", os.Getenv("GITHUB_BASE_REF")), "Git ref of the base branch to compare with (default: the pull request's base)")
+	baseReport := flag.String("base-report", os.Getenv("INPUT_BASE_REPORT"), "Coverage report (validate -report -format json) of the base branch, instead of scanning -base-ref")
+	comment := flag.Bool("comment", envOr("INPUT_COMMENT", "true") == "true", "Post or update a comment on the pull request")
+	summaryPath := flag.String("summary", os.Getenv("GITHUB_STEP_SUMMARY"), "File the Markdown summary is appended to")
+	outputPath := flag.String("output", os.Getenv("GITHUB_OUTPUT"), "File the step outputs are appended to")
+	flag.Parse()
+
+	if err := run(*provider, *validate, strings.Fields(*validateArgs), *baseRef, *baseReport, *comment, *summaryPath, *outputPath); err != nil {
+		// ::error:: makes the message an annotation of the workflow run
+		fmt.Printf("::error::%v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(provider, validate string, validateArgs []string, baseRef, baseReport string, comment bool, summaryPath, outputPath string) error {
+	ctx := context.Background()
+	head, failed, err := scanReport(ctx, validate, provider, validateArgs)
+	if err != nil {
+		return err
+	}
+
+	var base []metrics.Definition
+	baseName := baseRef
+	switch {
+	case baseReport != "":
+		snap, err := metrics.Load(baseReport)
+		if err != nil {
+			return fmt.Errorf("reading -base-report: %w", err)
+		}
+		base = snap.Definitions
+		if baseName == "" {
+			baseName = filepath.Base(baseReport)
+		}
+	case baseRef != "":
+		base, err = scanBaseRef(ctx, validate, provider, validateArgs, baseRef)
+		if err != nil {
+			// Without a base the summary still shows the coverage, just no deltas
+			fmt.Printf("::warning::No comparison with %s: %v\n", baseRef, err)
+		}
+	}
+
+	comparison := ghaction.Compare(head, base, baseName)
+	markdown := comparison.Markdown()
+	fmt.Print(markdown)
+
+	if summaryPath != "" {
+		if err := ghaction.AppendSummary(summaryPath, markdown); err != nil {
+			return fmt.Errorf("writing the job summary: %w", err)
+		}
+	}
+	if outputPath != "" {
+		if err := ghaction.WriteOutputs(outputPath, comparison); err != nil {
+			return fmt.Errorf("writing the step outputs: %w", err)
+		}
+	}
+	if comment {
+		if err := postComment(ctx, markdown); err != nil {
+			return fmt.Errorf("commenting on the pull request: %w", err)
+		}
+	}
+	// Reported last, so a failed scan that produced a report still gets its summary and comment
+	return failed
+}
+
+// scanReport runs validate over the provider and returns the definitions of its coverage
+// report. When validate writes a report but still exits with an error, the definitions are
+// returned along with that error as failed.
+func scanReport(ctx context.Context, validate, provider string, args []string) (defs []metrics.Definition, failed error, err error) {
+	args = append([]string{"-provider", provider, "-report", "-format", "json"}, args...)
+	cmd := exec.CommandContext(ctx, validate, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, runErr := cmd.Output()
+	if runErr != nil {
+		failed = fmt.Errorf("%s %s: %w: %s", validate, strings.Join(args, " "), runErr, strings.TrimSpace(stderr.String()))
+	}
+	snap, err := metrics.Decode(bytes.NewReader(out), time.Now())
+	if err != nil {
+		if failed != nil {
+			return nil, nil, failed
+		}
+		return nil, nil, fmt.Errorf("decoding the coverage report: %w", err)
+	}
+	return snap.Definitions, failed, nil
+}
+
+// scanBaseRef checks ref out in a temporary worktree of the provider's repository and scans
+// the provider there. The ref is tried as a remote-tracking branch of origin first, since
+// CI checkouts rarely have a local branch for the pull request's base.
+func scanBaseRef(ctx context.Context, validate, provider string, args []string, ref string) ([]metrics.Definition, error) {
+	absProvider, err := filepath.Abs(provider)
+	if err != nil {
+		return nil, err
+	}
+	top, err := exec.CommandContext(ctx, "git", "-C", absProvider, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s is not in a git repository", provider)
+	}
+	root := strings.TrimSpace(string(top))
+	rel, err := filepath.Rel(root, absProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "tfprovidertest-base-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	worktree := filepath.Join(dir, "base")
+
+	var addErr error
+	for _, candidate := range []string{"origin/" + ref, ref} {
+		out, err := exec.CommandContext(ctx, "git", "-C", root, "worktree", "add", "--detach", worktree, candidate).CombinedOutput()
+		if err == nil {
+			addErr = nil
+			break
+		}
+		addErr = fmt.Errorf("git worktree add %s: %s", candidate, strings.TrimSpace(string(out)))
+	}
+	if addErr != nil {
+		return nil, addErr
+	}
+	defer exec.Command("git", "-C", root, "worktree", "remove", "--force", worktree).Run()
+
+	defs, failed, err := scanReport(ctx, validate, filepath.Join(worktree, rel), args)
+	if err != nil {
+		return nil, err
+	}
+	if failed != nil {
+		// The report of the base is still usable
+		fmt.Printf("::notice::Scan of %s: %v\n", ref, failed)
+	}
+	return defs, nil
+}
+
+// postComment updates the action's comment on the pull request that triggered the workflow,
+// or posts it. Outside pull requests it does nothing.
+func postComment(ctx context.Context, markdown string) error {
+	eventPath := os.Getenv("GITHUB_EVENT_PATH")
+	if eventPath == "" {
+		return nil
+	}
+	pr, err := ghaction.PullRequestNumber(eventPath)
+	if err != nil || pr == 0 {
+		return err
+	}
+	github := ghaction.GitHub{
+		APIURL:     envOr("GITHUB_API_URL", "https://api.github.com"),
+		Token:      envOr("INPUT_GITHUB_TOKEN", os.Getenv("GITHUB_TOKEN")),
+		Repository: os.Getenv("GITHUB_REPOSITORY"),
+	}
+	if github.Token == "" {
+		return fmt.Errorf("no token; set the github-token input or $GITHUB_TOKEN")
+	}
+	return github.UpsertComment(ctx, pr, markdown)
+}
+
+// envOr returns the environment variable, or fallback when it is unset or empty.
+func envOr(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
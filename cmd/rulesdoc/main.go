@@ -0,0 +1,238 @@
+// Command rulesdoc writes the rules reference, a Markdown page per rule and an index, from
+// the rule catalogue and the default settings, with an example finding for each rule taken
+// from the fixtures. Run it from the repository root after changing a rule:
+//
+//	go run ./cmd/rulesdoc
+//
+// With -check it writes nothing and exits with status 1 when the reference is out of date.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest"
+	"github.com/example/tfprovidertest/internal/rulesdoc"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+func main() {
+	fixtures := flag.String("fixtures", "testdata/src/testlintdata", "Directory of the fixtures the examples are taken from")
+	out := flag.String("out", "docs/rules", "Directory the reference is written to")
+	check := flag.Bool("check", false, "Write nothing; exit with status 1 when the reference in -out is out of date")
+	flag.Parse()
+
+	examples, err := collectExamples(*fixtures)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	pages := rulesdoc.Pages(examples)
+	files := map[string]string{rulesdoc.IndexFile: rulesdoc.Index(pages)}
+	for _, page := range pages {
+		files[page.FileName()] = page.Markdown()
+	}
+
+	if *check {
+		stale, err := staleFiles(*out, files)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(stale) > 0 {
+			fmt.Fprintf(os.Stderr, "The rules reference in %s is out of date (%s); run go run ./cmd/rulesdoc\n", *out, strings.Join(stale, ", "))
+			os.Exit(1)
+		}
+		return
+	}
+	if err := writeFiles(*out, files); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// fixturePackage is a directory of fixtures, which the rules see as one package.
+type fixturePackage struct {
+	fset    *token.FileSet
+	files   []*ast.File
+	sources map[string][]string // Lines of each file, by name
+}
+
+// collectExamples runs every rule, as the strict profile configures it, over each package of
+// fixtures in dir and returns the first finding of each rule, by package path and position.
+// File names are relative to the parent of dir, so the examples do not depend on where the
+// repository is checked out.
+func collectExamples(dir string) (map[string]rulesdoc.Example, error) {
+	packages := make(map[string]*fixturePackage)
+	root := filepath.Dir(dir)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") {
+			return err
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		pkg := packages[filepath.Dir(path)]
+		if pkg == nil {
+			pkg = &fixturePackage{fset: token.NewFileSet(), sources: make(map[string][]string)}
+			packages[filepath.Dir(path)] = pkg
+		}
+		name := filepath.ToSlash(rel)
+		file, err := parser.ParseFile(pkg.fset, name, src, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		pkg.files = append(pkg.files, file)
+		pkg.sources[name] = strings.Split(string(src), "\n")
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	dirs := make([]string, 0, len(packages))
+	for d := range packages {
+		dirs = append(dirs, d)
+	}
+	sort.Strings(dirs)
+
+	settings, err := config.ProfileSettings(config.ProfileStrict)
+	if err != nil {
+		return nil, err
+	}
+
+	examples := make(map[string]rulesdoc.Example)
+	for _, d := range dirs {
+		pkg := packages[d]
+		// A plugin per package, so no registry is shared between packages
+		analyzers, err := tfprovidertest.NewWithSettings(settings).BuildAnalyzers()
+		if err != nil {
+			return nil, err
+		}
+		for _, analyzer := range analyzers {
+			if _, ok := examples[analyzer.Name]; ok {
+				continue
+			}
+			example, ok, err := firstFinding(pkg, analyzer)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s: %w", d, analyzer.Name, err)
+			}
+			if ok {
+				examples[analyzer.Name] = example
+			}
+		}
+	}
+	return examples, nil
+}
+
+// firstFinding runs analyzer over pkg and returns what it reports first by position.
+func firstFinding(pkg *fixturePackage, analyzer *analysis.Analyzer) (rulesdoc.Example, bool, error) {
+	var diags []analysis.Diagnostic
+	pass := &analysis.Pass{
+		Analyzer: analyzer,
+		Fset:     pkg.fset,
+		Files:    pkg.files,
+		Report: func(diag analysis.Diagnostic) {
+			diags = append(diags, diag)
+		},
+	}
+	if _, err := analyzer.Run(pass); err != nil {
+		return rulesdoc.Example{}, false, err
+	}
+	if len(diags) == 0 {
+		return rulesdoc.Example{}, false, nil
+	}
+	sort.SliceStable(diags, func(i, j int) bool {
+		a, b := pkg.fset.Position(diags[i].Pos), pkg.fset.Position(diags[j].Pos)
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Offset < b.Offset
+	})
+	pos := pkg.fset.Position(diags[0].Pos)
+	example := rulesdoc.Example{File: pos.Filename, Line: pos.Line, Message: diags[0].Message}
+	if lines := pkg.sources[pos.Filename]; pos.Line > 0 && pos.Line <= len(lines) {
+		// Drop the expectation analysistest reads from the fixture
+		source, _, _ := strings.Cut(lines[pos.Line-1], "// want ")
+		example.Source = strings.TrimSpace(source)
+	}
+	return example, true, nil
+}
+
+// staleFiles returns the names of the files in dir that differ from files, are missing or
+// are Markdown files no rule produces.
+func staleFiles(dir string, files map[string]string) ([]string, error) {
+	var stale []string
+	for name, content := range files {
+		existing, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if !bytes.Equal(existing, []byte(content)) {
+			stale = append(stale, name)
+		}
+	}
+	extra, err := extraFiles(dir, files)
+	if err != nil {
+		return nil, err
+	}
+	stale = append(stale, extra...)
+	sort.Strings(stale)
+	return stale, nil
+}
+
+// extraFiles returns the Markdown files in dir that are not among files, such as the page
+// of a removed rule.
+func extraFiles(dir string, files map[string]string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var extra []string
+	for _, entry := range entries {
+		if _, ok := files[entry.Name()]; !ok && !entry.IsDir() && strings.HasSuffix(entry.Name(), ".md") {
+			extra = append(extra, entry.Name())
+		}
+	}
+	return extra, nil
+}
+
+// writeFiles writes files to dir and removes the Markdown files no rule produces any more.
+func writeFiles(dir string, files map[string]string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	extra, err := extraFiles(dir, files)
+	if err != nil {
+		return err
+	}
+	for _, name := range extra {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			return err
+		}
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
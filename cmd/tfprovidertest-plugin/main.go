@@ -0,0 +1,52 @@
+//go:build tfprovidertest_legacy
+
+// Command tfprovidertest-plugin is the linter built as a Go plugin (.so) for golangci-lint
+// versions that predate module plugins. Build it with the tfprovidertest_legacy tag, using
+// the same Go toolchain and dependency versions as the golangci-lint binary that loads it:
+//
+//	go build -tags tfprovidertest_legacy -buildmode=plugin -o tfprovidertest.so ./cmd/tfprovidertest-plugin
+//
+// and point linters-settings.custom.tfprovidertest.path at the result.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// New is the entrypoint golangci-lint v1.54 and later look up in a .so plugin. conf holds the
+// linter's settings from the golangci-lint configuration.
+func New(conf any) ([]*analysis.Analyzer, error) {
+	return tfprovidertest.NewAnalyzers(conf)
+}
+
+// analyzerPlugin is the entrypoint of older golangci-lint versions, which pass no settings.
+type analyzerPlugin struct{}
+
+// GetAnalyzers returns the analyzers enabled by the default settings. These golangci-lint
+// versions take no error from it, so a failure is written to stderr rather than loading
+// the plugin without a word.
+func (analyzerPlugin) GetAnalyzers() []*analysis.Analyzer {
+	return loadAnalyzers(tfprovidertest.NewWithSettings(config.DefaultSettings()).BuildAnalyzers, os.Stderr)
+}
+
+// loadAnalyzers returns the analyzers build returns, writing its error, if any, to w.
+func loadAnalyzers(build func() ([]*analysis.Analyzer, error), w io.Writer) []*analysis.Analyzer {
+	analyzers, err := build()
+	if err != nil {
+		fmt.Fprintf(w, "tfprovidertest: loading no analyzers: %v\n", err)
+		return nil
+	}
+	return analyzers
+}
+
+// AnalyzerPlugin is looked up by name by golangci-lint versions before v1.54.
+var AnalyzerPlugin analyzerPlugin
+
+func main() {}
@@ -0,0 +1,52 @@
+//go:build tfprovidertest_legacy
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest"
+	"github.com/example/tfprovidertest/internal/rules"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+func TestLegacyPlugin(t *testing.T) {
+	defaults, err := tfprovidertest.NewWithSettings(config.DefaultSettings()).BuildAnalyzers()
+	require.NoError(t, err)
+	require.NotEmpty(t, defaults)
+
+	t.Run("GetAnalyzers", func(t *testing.T) {
+		analyzers := AnalyzerPlugin.GetAnalyzers()
+		require.Len(t, analyzers, len(defaults))
+		for i, analyzer := range analyzers {
+			assert.Equal(t, defaults[i].Name, analyzer.Name)
+		}
+	})
+
+	t.Run("New", func(t *testing.T) {
+		analyzers, err := New(nil)
+		require.NoError(t, err)
+		assert.Len(t, analyzers, len(defaults), "without settings")
+
+		analyzers, err = New(map[string]any{"EnableBasicTest": true, "EnableQualityRules": false})
+		require.NoError(t, err)
+		require.NotEmpty(t, analyzers)
+		assert.Equal(t, rules.BasicTest, analyzers[0].Name, "the settings golangci-lint passes apply")
+		assert.Less(t, len(analyzers), len(defaults))
+	})
+
+	t.Run("build error", func(t *testing.T) {
+		var stderr bytes.Buffer
+		analyzers := loadAnalyzers(func() ([]*analysis.Analyzer, error) {
+			return nil, errors.New("no rules")
+		}, &stderr)
+		assert.Empty(t, analyzers)
+		assert.Equal(t, "tfprovidertest: loading no analyzers: no rules\n", stderr.String())
+	})
+}
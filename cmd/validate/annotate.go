@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+
+	"github.com/example/tfprovidertest/internal/annotate"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// runAnnotate writes or refreshes the test coverage line in the doc comment of each
+// definition's type, or only lists the changes when dryRun is set
+func runAnnotate(fset *token.FileSet, files []*ast.File, settings config.Settings, format string, dryRun bool) {
+	if format != "text" && format != "json" {
+		fmt.Printf("Error: Invalid format '%s' for -annotate. Must be one of: text, json\n", format)
+		exit(1)
+	}
+
+	annotations, err := annotate.Apply(fset, buildRegistryFromFiles(fset, files, settings), dryRun)
+	if err != nil {
+		fmt.Printf("Error: Could not annotate definitions: %v\n", err)
+		exit(1)
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(annotations); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		}
+		return
+	}
+	outputAnnotateText(annotations, dryRun)
+}
+
+// outputAnnotateText lists the added and updated coverage lines and the definitions that
+// could not be annotated
+func outputAnnotateText(annotations []annotate.Annotation, dryRun bool) {
+	fmt.Println("=== Coverage Annotations ===")
+	fmt.Println()
+	counts := make(map[string]int)
+	for _, a := range annotations {
+		counts[a.Status]++
+		switch a.Status {
+		case annotate.StatusAdded, annotate.StatusUpdated:
+			fmt.Printf("%s:%d  %s (%s, %s)\n", a.File, a.Line, a.Declaration, a.Definition, a.Status)
+			fmt.Printf("    %s\n", a.Comment)
+		case annotate.StatusSkipped:
+			fmt.Printf("%s: not annotated: %s\n", a.Definition, a.Reason)
+		}
+	}
+	if counts[annotate.StatusAdded]+counts[annotate.StatusUpdated]+counts[annotate.StatusSkipped] > 0 {
+		fmt.Println()
+	}
+	verb := "Annotated"
+	if dryRun {
+		verb = "Would annotate"
+	}
+	fmt.Printf("%s %d definition(s): %d added, %d updated, %d unchanged, %d skipped\n",
+		verb, len(annotations), counts[annotate.StatusAdded], counts[annotate.StatusUpdated],
+		counts[annotate.StatusUnchanged], counts[annotate.StatusSkipped])
+}
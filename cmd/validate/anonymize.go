@@ -0,0 +1,37 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"github.com/example/tfprovidertest/internal/anonymize"
+)
+
+// newAnonymizer returns the Anonymizer of the -anonymize flags, or nil when they are off.
+// Anonymizing applies to -report only, and rejects the options that would print real paths
+// next to the hashed names. Without a salt, a random one is used and the names change on
+// every run.
+func newAnonymizer(enabled bool, salt string, report, gitMetadata bool, repoURLTemplate string) (*anonymize.Anonymizer, error) {
+	if !enabled {
+		return nil, nil
+	}
+	switch {
+	case !report:
+		return nil, fmt.Errorf("-anonymize requires -report")
+	case gitMetadata:
+		return nil, fmt.Errorf("-anonymize cannot be combined with -git-metadata")
+	case repoURLTemplate != "":
+		return nil, fmt.Errorf("-anonymize cannot be combined with -repo-url-template, whose links expose file paths")
+	}
+	if salt == "" {
+		random := make([]byte, 16)
+		if _, err := rand.Read(random); err != nil {
+			return nil, fmt.Errorf("generating an -anonymize salt: %w", err)
+		}
+		salt = hex.EncodeToString(random)
+		fmt.Fprintln(os.Stderr, "Warning: -anonymize without -anonymize-salt uses a random salt; names will differ between runs")
+	}
+	return anonymize.New(salt), nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/artifact"
+	"github.com/example/tfprovidertest/internal/enforcement"
+	"github.com/example/tfprovidertest/internal/links"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/internal/routing"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// buildSummaryArtifact assembles the -summary-file document of an analyzer run. Paths are
+// relative to the provider, and top findings list errors before warnings, each in the order
+// they were reported.
+func buildSummaryArtifact(reg *registry.ResourceRegistry, settings config.Settings, providerPath string, ruleNames []string, findings []Finding, summary RunSummary, health *RunHealth, gate *GateResult) (artifact.Summary, error) {
+	fingerprint, err := artifact.Fingerprint(settings)
+	if err != nil {
+		return artifact.Summary{}, err
+	}
+	doc := artifact.Summary{
+		SchemaVersion:       artifact.SchemaVersion,
+		GeneratedAt:         time.Now().UTC().Format(time.RFC3339),
+		Tool:                artifact.CurrentTool(),
+		Commit:              links.HeadCommit(context.Background(), providerPath),
+		SettingsFingerprint: fingerprint,
+		Rules:               ruleNames,
+		Timing:              artifact.Timing{Phases: make(map[string]float64)},
+		TopFindings:         []artifact.Finding{},
+	}
+	if health != nil {
+		for _, phase := range health.Phases {
+			doc.Timing.Phases[phase.Name] += phase.ElapsedMS
+			doc.Timing.TotalMS += phase.ElapsedMS
+		}
+	}
+
+	definitions := len(reg.GetAllDefinitions())
+	untested := len(analysis.NewCoverageCalculator(reg).GetUntestedResources())
+	doc.Totals = artifact.Totals{
+		Definitions: definitions,
+		Tested:      definitions - untested,
+		Findings:    summary.TotalFindings,
+		ByGroup:     summary.ByGroup,
+		ByLevel:     summary.ByLevel,
+	}
+	if definitions > 0 {
+		doc.Totals.Coverage = float64(definitions-untested) / float64(definitions)
+	}
+	if gate != nil {
+		doc.Gate = gate.Status
+	}
+
+	top := append([]Finding(nil), findings...)
+	sort.SliceStable(top, func(i, j int) bool {
+		return top[i].Level == string(enforcement.LevelError) && top[j].Level != string(enforcement.LevelError)
+	})
+	if len(top) > artifact.MaxTopFindings {
+		top = top[:artifact.MaxTopFindings]
+	}
+	for _, f := range top {
+		message, _, _ := strings.Cut(f.Message, "\n")
+		doc.TopFindings = append(doc.TopFindings, artifact.Finding{
+			Rule:     f.Rule,
+			Code:     f.Code,
+			Level:    f.Level,
+			File:     routing.RelativePath(providerPath, f.File),
+			Line:     f.Line,
+			Resource: f.Resource,
+			Message:  message,
+		})
+	}
+	return doc, nil
+}
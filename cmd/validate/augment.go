@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/augment"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// runAugmentTests suggests ConfigStateChecks for test steps that apply a config without
+// checking state, and writes them as a unified diff to patchPath when it is set
+func runAugmentTests(fset *token.FileSet, files []*ast.File, settings config.Settings, format, providerPath, patchPath string) {
+	if format != "text" && format != "json" {
+		fmt.Printf("Error: Invalid format '%s' for -augment-tests. Must be one of: text, json\n", format)
+		exit(1)
+	}
+
+	suggestions := augment.Suggest(fset, buildRegistryFromFiles(fset, files, settings))
+	if patchPath != "" {
+		patch, skipped, err := augment.Patch(fset, suggestions, providerPath)
+		if err != nil {
+			fmt.Printf("Error: Could not build patch: %v\n", err)
+			exit(1)
+		}
+		if err := writeAugmentPatch(patch, patchPath); err != nil {
+			fmt.Printf("Error: Could not write patch: %v\n", err)
+			exit(1)
+		}
+		for _, suggestion := range skipped {
+			fmt.Fprintf(os.Stderr, "Warning: %s:%d (%s step %d) not patched: Config is not on its own line ending in a comma\n",
+				suggestion.File, suggestion.Line, suggestion.Test, suggestion.Step)
+		}
+		if patchPath == "-" {
+			return
+		}
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(suggestions); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		}
+		return
+	}
+	outputAugmentText(suggestions)
+}
+
+// writeAugmentPatch writes the patch to path, or to stdout when path is "-"
+func writeAugmentPatch(patch, path string) error {
+	if path == "-" {
+		_, err := fmt.Print(patch)
+		return err
+	}
+	return os.WriteFile(path, []byte(patch), 0o644)
+}
+
+// outputAugmentText prints each suggested ConfigStateChecks field under its step
+func outputAugmentText(suggestions []augment.Suggestion) {
+	fmt.Println("=== Suggested State Checks ===")
+	fmt.Println()
+	if len(suggestions) == 0 {
+		fmt.Println("Every test step that applies a config checks the resulting state.")
+		return
+	}
+	fmt.Printf("%d test step(s) apply a config without Check or ConfigStateChecks:\n", len(suggestions))
+	for _, suggestion := range suggestions {
+		fmt.Println()
+		fmt.Printf("%s:%d  %s step %d\n", suggestion.File, suggestion.Line, suggestion.Test, suggestion.Step)
+		for _, line := range strings.Split(suggestion.Code, "\n") {
+			fmt.Printf("    %s\n", line)
+		}
+	}
+	fmt.Println()
+	fmt.Printf("Imports: %s\n", strings.Join(augment.Imports, ", "))
+	fmt.Println("knownvalue.NotNull() only asserts that an attribute is set; replace it with the expected value where it is known.")
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// runShowCandidates lists the tests with candidate links the linker did not accept
+func runShowCandidates(fset *token.FileSet, files []*ast.File, settings config.Settings, format string) {
+	if format != "text" && format != "table" && format != "json" {
+		fmt.Printf("Error: Invalid format '%s' for -show-candidates. Must be one of: text, table, json\n", format)
+		exit(1)
+	}
+
+	nearMisses := analysis.NearMisses(buildRegistryFromFiles(fset, files, settings), fset)
+	switch format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(nearMisses); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		}
+	case "table":
+		outputCandidatesTable(nearMisses)
+	default:
+		outputCandidatesText(nearMisses)
+	}
+}
+
+// outputCandidatesText prints each test with its candidates, the accepted one marked ✓
+func outputCandidatesText(nearMisses []analysis.TestCandidates) {
+	fmt.Println("=== Match Candidates ===")
+	fmt.Println()
+	if len(nearMisses) == 0 {
+		fmt.Println("No near misses: every candidate the linker found was accepted.")
+		return
+	}
+
+	for _, tc := range nearMisses {
+		linked := "not linked"
+		if tc.Linked != "" {
+			linked = "linked to " + tc.Linked
+		}
+		fmt.Printf("%s (%s:%d), %s\n", tc.Test, filepath.Base(tc.File), tc.Line, linked)
+		for _, c := range tc.Candidates {
+			mark := "✗"
+			if c.Accepted {
+				mark = "✓"
+			}
+			fmt.Printf("  %s %-30s %-16s %.2f", mark, c.Kind+" "+c.Name, c.Strategy, c.Confidence)
+			if c.Note != "" {
+				fmt.Printf("  %s", c.Note)
+			}
+			fmt.Println()
+		}
+		fmt.Println()
+	}
+	fmt.Printf("%d tests with near misses\n", len(nearMisses))
+}
+
+// outputCandidatesTable prints one row per candidate
+func outputCandidatesTable(nearMisses []analysis.TestCandidates) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TEST\tCANDIDATE\tSTRATEGY\tCONFIDENCE\tACCEPTED\tNOTE")
+	for _, tc := range nearMisses {
+		for _, c := range tc.Candidates {
+			accepted := ""
+			if c.Accepted {
+				accepted = "yes"
+			}
+			fmt.Fprintf(w, "%s\t%s:%s\t%s\t%.2f\t%s\t%s\n", tc.Test, c.Kind, c.Name, c.Strategy, c.Confidence, accepted, c.Note)
+		}
+	}
+	w.Flush()
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// groupByCategory is the -group-by value that breaks the report down by category.
+const groupByCategory = "category"
+
+// uncategorized labels definitions without a category in the category breakdown.
+const uncategorized = "uncategorized"
+
+// CategoryReport summarizes the definitions of one category
+type CategoryReport struct {
+	Category    string  `json:"category"`
+	Definitions int     `json:"definitions"`
+	Untested    int     `json:"untested"`
+	Coverage    float64 `json:"coverage"` // Share of the definitions with a test, 0 to 1
+}
+
+// parseCategoryOptions validates the -group-by flag and turns the -category filter into a
+// set of lowercased category names.
+func parseCategoryOptions(groupBy string, categories []string) (string, map[string]bool, error) {
+	if groupBy != "" && groupBy != groupByCategory {
+		return "", nil, fmt.Errorf("unknown -group-by %q (valid: %s)", groupBy, groupByCategory)
+	}
+	if len(categories) == 0 {
+		return groupBy, nil, nil
+	}
+	filter := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		filter[strings.ToLower(category)] = true
+	}
+	return groupBy, filter, nil
+}
+
+// filterByCategory returns the definitions whose category is in filter, matched without
+// regard to case, or all of them when filter is empty. "uncategorized" selects definitions
+// without a category.
+func filterByCategory(defs []*registry.ResourceInfo, filter map[string]bool) []*registry.ResourceInfo {
+	if len(filter) == 0 {
+		return defs
+	}
+	var kept []*registry.ResourceInfo
+	for _, def := range defs {
+		if filter[strings.ToLower(categoryLabel(def))] {
+			kept = append(kept, def)
+		}
+	}
+	return kept
+}
+
+// categoryLabel returns a definition's category, or "uncategorized".
+func categoryLabel(def *registry.ResourceInfo) string {
+	if def.Category == "" {
+		return uncategorized
+	}
+	return def.Category
+}
+
+// buildCategorySummary counts definitions and untested definitions per category, sorted by
+// name with uncategorized definitions last.
+func buildCategorySummary(reg *registry.ResourceRegistry, groups ...[]*registry.ResourceInfo) []CategoryReport {
+	counts := make(map[string]*CategoryReport)
+	for _, defs := range groups {
+		for _, info := range defs {
+			category := categoryLabel(info)
+			if counts[category] == nil {
+				counts[category] = &CategoryReport{Category: category}
+			}
+			counts[category].Definitions++
+			if len(reg.GetTests(info.Kind, info.Name)) == 0 {
+				counts[category].Untested++
+			}
+		}
+	}
+
+	summary := make([]CategoryReport, 0, len(counts))
+	for _, report := range counts {
+		report.Coverage = float64(report.Definitions-report.Untested) / float64(report.Definitions)
+		summary = append(summary, *report)
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		if (summary[i].Category == uncategorized) != (summary[j].Category == uncategorized) {
+			return summary[j].Category == uncategorized
+		}
+		return summary[i].Category < summary[j].Category
+	})
+	return summary
+}
+
+// printCategoryTable prints the category breakdown of the report summary.
+func printCategoryTable(summary []CategoryReport, view reportView) {
+	if len(summary) == 0 {
+		return
+	}
+	fmt.Println()
+	view.section("CATEGORIES")
+	w := view.newTable()
+	fmt.Fprintln(w, "  CATEGORY\tTOTAL\tUNTESTED\tCOVERAGE")
+	fmt.Fprintln(w, "  ────────\t─────\t────────\t────────")
+	for _, report := range summary {
+		fmt.Fprintf(w, "  %s\t%d\t%d\t%.1f%%\n", report.Category, report.Definitions, report.Untested, report.Coverage*100)
+	}
+	w.Flush()
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+)
+
+// topRiskLimit is how many complex untested definitions -report lists as top risks
+const topRiskLimit = 10
+
+// printTopRisks prints the untested definitions with the most complex schemas, which are the
+// likeliest place for a regression to go unnoticed
+func printTopRisks(risks []analysis.RiskEntry, view reportView) {
+	if len(risks) == 0 {
+		return
+	}
+	fmt.Println()
+	view.section("TOP RISKS (COMPLEX AND UNTESTED)")
+	w := view.newTable()
+	fmt.Fprintln(w, "  NAME\tKIND\tSCORE\tATTRIBUTES\tBLOCKS\tDEPTH\tREQUIRED\tCOMPUTED\tVALIDATORS\tPLAN MODIFIERS")
+	fmt.Fprintln(w, "  ────\t────\t─────\t──────────\t──────\t─────\t────────\t────────\t──────────\t──────────────")
+	for _, risk := range risks {
+		c := risk.Complexity
+		fmt.Fprintf(w, "  %s\t%s\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\n",
+			risk.Name, risk.Kind, c.Score, c.Attributes, c.Blocks, c.MaxDepth,
+			c.Required, c.Computed, c.Validators, c.PlanModifiers)
+	}
+	w.Flush()
+}
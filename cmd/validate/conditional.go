@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// testConditions returns the guards that make a test's coverage conditional, once each
+func testConditions(t *registry.TestFunctionInfo) []string {
+	var conditions []string
+	seen := make(map[string]bool)
+	for _, c := range t.Conditions {
+		if s := c.String(); !seen[s] {
+			seen[s] = true
+			conditions = append(conditions, s)
+		}
+	}
+	return conditions
+}
+
+// conditionalCounts counts the linked tests skipped under some conditions and the tested
+// definitions all of whose tests are
+func conditionalCounts(reg *registry.ResourceRegistry, resources, dataSources, actions []*registry.ResourceInfo) (conditional, conditionallyTested int) {
+	for _, info := range allDefinitions(resources, dataSources, actions) {
+		tests := reg.GetTests(info.Kind, info.Name)
+		n := 0
+		for _, t := range tests {
+			if t.IsConditional() {
+				n++
+			}
+		}
+		conditional += n
+		if n > 0 && n == len(tests) {
+			conditionallyTested++
+		}
+	}
+	return conditional, conditionallyTested
+}
+
+// outputConditionalTable prints the conditional tests of each definition with their guards,
+// marking the definitions no test covers unconditionally
+func outputConditionalTable(reg *registry.ResourceRegistry, defs []*registry.ResourceInfo, view reportView) {
+	type row struct {
+		info          *registry.ResourceInfo
+		conditional   []*registry.TestFunctionInfo
+		unconditional int
+	}
+	var rows []row
+	for _, info := range defs {
+		r := row{info: info}
+		for _, t := range reg.GetTests(info.Kind, info.Name) {
+			if t.IsConditional() {
+				r.conditional = append(r.conditional, t)
+			} else {
+				r.unconditional++
+			}
+		}
+		if len(r.conditional) > 0 {
+			rows = append(rows, r)
+		}
+	}
+	if len(rows) == 0 {
+		return
+	}
+
+	fmt.Println()
+	view.section("CONDITIONAL COVERAGE")
+	w := view.newTable()
+	fmt.Fprintln(w, "  DEFINITION\tKIND\tTEST FUNCTION\tUNCONDITIONAL TESTS\tCONDITIONS")
+	fmt.Fprintln(w, "  ──────────\t────\t─────────────\t───────────────────\t──────────")
+	for _, r := range rows {
+		others := fmt.Sprintf("%d", r.unconditional)
+		if r.unconditional == 0 {
+			others = "0 (conditional only)"
+		}
+		for _, t := range r.conditional {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", r.info.Name, r.info.Kind, t.Name, others, strings.Join(testConditions(t), "; "))
+		}
+	}
+	w.Flush()
+}
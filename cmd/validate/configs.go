@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// maxReusedConfigs limits how many configs are listed as most reused
+const maxReusedConfigs = 10
+
+// ConfigCorpusReport is the -configs result for JSON output
+type ConfigCorpusReport struct {
+	DistinctConfigs int            `json:"distinct_configs"`
+	ConfigSteps     int            `json:"config_steps"`
+	Unresolved      int            `json:"unresolved_steps"` // Steps whose config is not known statically
+	MostReused      []ConfigReport `json:"most_reused"`
+	UsedOnce        []ConfigReport `json:"used_once"`
+}
+
+// ConfigReport describes a distinct config and where it is used
+type ConfigReport struct {
+	Hash      string   `json:"hash"`
+	Uses      int      `json:"uses"`
+	Producers []string `json:"producers"`
+	Tests     []string `json:"tests"`
+	Location  string   `json:"location"` // First use, as file:line
+	Config    string   `json:"config"`
+}
+
+// runConfigCorpus lists the most reused test configs and those used by a single step
+func runConfigCorpus(fset *token.FileSet, files []*ast.File, settings config.Settings, format string) {
+	if format != "text" && format != "json" {
+		fmt.Printf("Error: Invalid format '%s' for -configs. Must be one of: text, json\n", format)
+		exit(1)
+	}
+
+	report := buildConfigCorpusReport(fset, discovery.CatalogConfigs(files, fset), settings)
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		}
+		return
+	}
+	outputConfigCorpusText(report)
+}
+
+// buildConfigCorpusReport splits the corpus into the most reused configs and those used once,
+// with their text redacted as settings ask
+func buildConfigCorpusReport(fset *token.FileSet, corpus discovery.ConfigCorpus, settings config.Settings) ConfigCorpusReport {
+	report := ConfigCorpusReport{
+		DistinctConfigs: len(corpus.Configs),
+		ConfigSteps:     corpus.Steps,
+		Unresolved:      corpus.Unresolved,
+		MostReused:      []ConfigReport{},
+		UsedOnce:        []ConfigReport{},
+	}
+	for _, info := range corpus.Configs {
+		first := info.Uses[0]
+		pos := fset.Position(first.Pos)
+		config := ConfigReport{
+			Hash:      info.Hash,
+			Uses:      len(info.Uses),
+			Producers: info.Producers(),
+			Tests:     info.Tests(),
+			Location:  fmt.Sprintf("%s:%d", first.FilePath, pos.Line),
+			Config:    settings.RedactConfig(info.Config),
+		}
+		if config.Uses == 1 {
+			report.UsedOnce = append(report.UsedOnce, config)
+		} else if len(report.MostReused) < maxReusedConfigs {
+			report.MostReused = append(report.MostReused, config)
+		}
+	}
+	return report
+}
+
+// outputConfigCorpusText prints the most reused configs with a preview, then the configs
+// used by a single step
+func outputConfigCorpusText(report ConfigCorpusReport) {
+	fmt.Println("=== Config Corpus ===")
+	fmt.Println()
+	fmt.Printf("%d distinct configs across %d config steps (%d used once", report.DistinctConfigs, report.ConfigSteps, len(report.UsedOnce))
+	if report.Unresolved > 0 {
+		fmt.Printf(", %d not resolved statically", report.Unresolved)
+	}
+	fmt.Println(")")
+
+	if len(report.MostReused) > 0 {
+		fmt.Println()
+		fmt.Println("Most reused configs:")
+		for i, c := range report.MostReused {
+			fmt.Printf("  %d. %s  %d uses via %s\n", i+1, c.Hash, c.Uses, strings.Join(c.Producers, ", "))
+			fmt.Printf("     First used at %s\n", c.Location)
+			fmt.Printf("     Tests: %s\n", summarizeNames(c.Tests, 5))
+			for _, line := range previewLines(c.Config, 3) {
+				fmt.Printf("     | %s\n", line)
+			}
+		}
+	}
+
+	if len(report.UsedOnce) > 0 {
+		fmt.Println()
+		fmt.Println("Configs used exactly once (candidates for consolidation):")
+		for _, c := range report.UsedOnce {
+			fmt.Printf("  %s  %s via %s (%s)\n", c.Hash, c.Tests[0], c.Producers[0], filepath.Base(c.Location))
+		}
+	}
+}
+
+// summarizeNames joins up to limit names, noting how many were left out
+func summarizeNames(names []string, limit int) string {
+	if len(names) <= limit {
+		return strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%s (+%d more)", strings.Join(names[:limit], ", "), len(names)-limit)
+}
+
+// previewLines returns the first lines of a config, marking when it continues
+func previewLines(config string, limit int) []string {
+	lines := strings.Split(config, "\n")
+	if len(lines) <= limit {
+		return lines
+	}
+	return append(lines[:limit:limit], fmt.Sprintf("... (%d more lines)", len(lines)-limit))
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/example/tfprovidertest/internal/coverprofile"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// runCoverProfile correlates a go test -coverprofile profile with the lifecycle methods of the
+// discovered definitions and reports tested definitions whose methods never ran
+func runCoverProfile(fset *token.FileSet, files []*ast.File, settings config.Settings, format, path string) {
+	if format != "text" && format != "json" {
+		fmt.Printf("Error: Invalid format '%s' for -coverprofile. Must be one of: text, json\n", format)
+		exit(1)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Printf("Error: Could not open coverage profile: %v\n", err)
+		exit(1)
+	}
+	profile, err := coverprofile.Parse(f)
+	f.Close()
+	if err != nil {
+		fmt.Printf("Error: Could not read coverage profile %s: %v\n", path, err)
+		exit(1)
+	}
+
+	report := coverprofile.Correlate(fset, buildRegistryFromFiles(fset, files, settings), profile)
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		}
+		return
+	}
+	outputCoverProfileText(report)
+}
+
+// outputCoverProfileText prints the run-time coverage of each lifecycle method, then the
+// tested definitions with methods that never ran
+func outputCoverProfileText(report coverprofile.Report) {
+	fmt.Println("=== Run-time Coverage of Lifecycle Methods ===")
+	fmt.Println()
+	if len(report.Definitions) == 0 {
+		fmt.Println("No definitions with Create, Read, Update or Delete methods were found.")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  KIND\tNAME\tTESTS\tCREATE\tREAD\tUPDATE\tDELETE")
+	for _, def := range report.Definitions {
+		cells := map[string]string{}
+		for _, method := range def.Methods {
+			switch {
+			case !def.InProfile:
+				cells[method.Name] = "?"
+			case method.Statements == 0:
+				cells[method.Name] = "empty"
+			default:
+				cells[method.Name] = fmt.Sprintf("%d/%d", method.Covered, method.Statements)
+			}
+		}
+		row := []string{def.Kind, def.Name, fmt.Sprint(def.Tests)}
+		for _, name := range []string{"Create", "Read", "Update", "Delete"} {
+			cell, ok := cells[name]
+			if !ok {
+				cell = "-"
+			}
+			row = append(row, cell)
+		}
+		fmt.Fprintf(w, "  %s\n", strings.Join(row, "\t"))
+	}
+	w.Flush()
+	fmt.Println()
+	fmt.Println("Cells show covered/total statements; '-' means no such method, '?' means the file is not in the profile.")
+
+	if len(report.NotInProfile) > 0 {
+		fmt.Println()
+		fmt.Printf("Not in the profile (%d): %s\n", len(report.NotInProfile), strings.Join(report.NotInProfile, ", "))
+		fmt.Println("  Tip: run go test with -coverpkg=./... so the provider packages are instrumented")
+	}
+
+	fmt.Println()
+	if len(report.Gaps) == 0 {
+		fmt.Println("Every tested definition in the profile executed all of its lifecycle methods.")
+		return
+	}
+	fmt.Printf("Tested definitions with lifecycle methods that never ran (%d):\n", len(report.Gaps))
+	for _, def := range report.Gaps {
+		fmt.Printf("  %s %s (%d test(s)): %s never executed\n", def.Kind, def.Name, def.Tests, strings.Join(def.NeverExecuted(), ", "))
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+	"github.com/example/tfprovidertest/pkg/diagnose"
+)
+
+// runDiagnostics prints the -show-matches, -show-unmatched, -show-orphaned and -show-weak
+// views. With -format json they are printed as one object with a key for each view.
+func runDiagnostics(fset *token.FileSet, files []*ast.File, settings config.Settings, format string, showMatches, showUnmatched, showOrphaned, showWeak bool) {
+	if format != "text" && format != "json" && format != "table" {
+		fmt.Printf("Error: Invalid format '%s'. Must be one of: text, json, table\n", format)
+		exit(1)
+	}
+
+	reg := diagnose.BuildRegistry(fset, files, settings)
+	if format == "json" {
+		views := make(map[string]interface{})
+		if showMatches {
+			views["matches"] = nonNil(reg.GetResourceTestMatches())
+		}
+		if showUnmatched {
+			views["unmatched"] = nonNil(reg.GetUnmatchedTests())
+		}
+		if showOrphaned {
+			views["orphaned"] = nonNil(reg.GetOrphanedResources())
+		}
+		if showWeak {
+			views["weak"] = nonNil(reg.GetWeakResources())
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(views); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		}
+		return
+	}
+
+	if showMatches {
+		fmt.Println("=== Resource -> Test Function Associations ===")
+		fmt.Println()
+		outputMatches(reg.GetResourceTestMatches(), format)
+		fmt.Println()
+	}
+	if showUnmatched {
+		fmt.Println("=== Unmatched Test Functions ===")
+		fmt.Println()
+		outputUnmatched(reg.GetUnmatchedTests(), format)
+		fmt.Println()
+	}
+	if showOrphaned {
+		fmt.Println("=== Orphaned Resources (No Tests) ===")
+		fmt.Println()
+		outputOrphaned(reg.GetOrphanedResources(), format)
+		fmt.Println()
+	}
+	if showWeak {
+		fmt.Println("=== Weak Tests (No Assertions) ===")
+		fmt.Println()
+		outputWeak(reg.GetWeakResources(), format)
+		fmt.Println()
+	}
+}
+
+// nonNil returns items, or an empty slice for nil, so empty views encode as [] in JSON
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+// outputMatches prints each test function linked to a definition
+func outputMatches(matches []diagnose.Match, format string) {
+	if len(matches) == 0 {
+		fmt.Println("  No test functions are linked to a definition.")
+		return
+	}
+	if format == "table" {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "RESOURCE\tKIND\tTEST FUNCTION\tCONFIDENCE\tMATCH TYPE\tTEST FILE")
+		fmt.Fprintln(w, "--------\t----\t-------------\t----------\t----------\t---------")
+		for _, m := range matches {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%.0f%%\t%s\t%s\n", m.ResourceName, m.Kind, m.TestFunction, m.Confidence*100, m.MatchType, m.TestFile)
+		}
+		w.Flush()
+		return
+	}
+	for _, m := range matches {
+		fmt.Printf("  %s %s -> %s (%.0f%%, %s)\n", m.Kind, m.ResourceName, m.TestFunction, m.Confidence*100, m.MatchType)
+		fmt.Printf("    File: %s\n", m.TestFile)
+	}
+}
+
+// outputUnmatched prints each acceptance test linked to no definition
+func outputUnmatched(unmatched []diagnose.UnmatchedTest, format string) {
+	if len(unmatched) == 0 {
+		fmt.Println("  Every acceptance test is linked to a definition.")
+		return
+	}
+	if format == "table" {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TEST FUNCTION\tTEST FILE\tLINE\tCONFIG DECLARES")
+		fmt.Fprintln(w, "-------------\t---------\t----\t---------------")
+		for _, u := range unmatched {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", u.TestFunction, u.TestFile, u.Line, listOrDash(u.InferredResources))
+		}
+		w.Flush()
+		return
+	}
+	for _, u := range unmatched {
+		fmt.Printf("  %s (%s:%d)\n", u.TestFunction, u.TestFile, u.Line)
+		if len(u.InferredResources) > 0 {
+			fmt.Printf("    Config declares: %s\n", strings.Join(u.InferredResources, ", "))
+		}
+	}
+}
+
+// outputOrphaned prints each definition without tests with where its test is expected
+func outputOrphaned(orphaned []diagnose.OrphanedResource, format string) {
+	if len(orphaned) == 0 {
+		fmt.Println("  Every definition has a test.")
+		return
+	}
+	if format == "table" {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "RESOURCE\tKIND\tFILE\tEXPECTED TEST FUNCTION\tEXPECTED TEST FILE")
+		fmt.Fprintln(w, "--------\t----\t----\t----------------------\t------------------")
+		for _, o := range orphaned {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", o.ResourceName, o.Kind, o.File, o.ExpectedTestFunction, o.ExpectedTestFile)
+		}
+		w.Flush()
+		return
+	}
+	for _, o := range orphaned {
+		fmt.Printf("  %s %s (%s:%d)\n", o.Kind, o.ResourceName, o.File, o.Line)
+		fmt.Printf("    Expected: %s in %s\n", o.ExpectedTestFunction, o.ExpectedTestFile)
+		if o.QuarantinedTests > 0 {
+			fmt.Printf("    Quarantined tests: %d\n", o.QuarantinedTests)
+		}
+	}
+}
+
+// outputWeak prints each definition with tests that apply configs without asserting anything
+func outputWeak(weak []diagnose.WeakResource, format string) {
+	if len(weak) == 0 {
+		fmt.Println("  Every linked test asserts state, plans or an expected error.")
+		return
+	}
+	if format == "table" {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "RESOURCE\tKIND\tWEAK/TESTS\tWEAK TEST\tCONFIG STEPS\tTEST FILE")
+		fmt.Fprintln(w, "--------\t----\t----------\t---------\t------------\t---------")
+		for _, r := range weak {
+			for _, t := range r.WeakTests {
+				fmt.Fprintf(w, "%s\t%s\t%d/%d\t%s\t%d\t%s\n", r.ResourceName, r.Kind, len(r.WeakTests), r.Tests, t.TestFunction, t.ConfigSteps, t.TestFile)
+			}
+		}
+		w.Flush()
+		return
+	}
+	for _, r := range weak {
+		fmt.Printf("  %s %s: %d of %d test(s) assert nothing\n", r.Kind, r.ResourceName, len(r.WeakTests), r.Tests)
+		for _, t := range r.WeakTests {
+			fmt.Printf("    %s (%s:%d), %d config step(s)\n", t.TestFunction, t.TestFile, t.Line, t.ConfigSteps)
+		}
+	}
+}
+
+// weakCounts counts the linked tests that assert nothing and the tested definitions all of
+// whose tests are weak
+func weakCounts(reg *registry.ResourceRegistry, resources, dataSources, actions []*registry.ResourceInfo) (weak, weaklyTested int) {
+	for _, info := range allDefinitions(resources, dataSources, actions) {
+		tests := reg.GetTests(info.Kind, info.Name)
+		n := 0
+		for _, t := range tests {
+			if t.IsWeak() {
+				n++
+			}
+		}
+		weak += n
+		if n > 0 && n == len(tests) {
+			weaklyTested++
+		}
+	}
+	return weak, weaklyTested
+}
+
+// listOrDash joins items with commas, or returns "-" when there are none
+func listOrDash(items []string) string {
+	if len(items) == 0 {
+		return "-"
+	}
+	return strings.Join(items, ", ")
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"text/tabwriter"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// runEnvVarInventory lists the environment variables the acceptance suite depends on
+func runEnvVarInventory(fset *token.FileSet, files []*ast.File, settings config.Settings, format string) {
+	if format != "text" && format != "table" && format != "json" {
+		fmt.Printf("Error: Invalid format '%s' for -env-vars. Must be one of: text, table, json\n", format)
+		exit(1)
+	}
+
+	inventory := analysis.EnvVarInventory(buildRegistryFromFiles(fset, files, settings))
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(inventory); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		}
+		return
+	}
+	outputEnvVarTable(inventory)
+}
+
+// outputEnvVarTable prints one row per variable with its test counts, and then the tests that
+// interpolate a variable without checking it
+func outputEnvVarTable(inventory []analysis.EnvVarEntry) {
+	fmt.Println("=== Environment Variables ===")
+	fmt.Println()
+	if len(inventory) == 0 {
+		fmt.Println("No environment variables found in test configs, PreChecks or the provider.")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VARIABLE\tIN CONFIGS\tIN PRECHECK\tSET BY TESTS\tPROVIDER\tUNCHECKED")
+	for _, e := range inventory {
+		provider := ""
+		if e.Provider {
+			provider = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\t%d\n", e.Name, len(e.ConfigTests), len(e.PreCheckTests), len(e.SetByTests), provider, len(e.Unchecked))
+	}
+	w.Flush()
+
+	for _, e := range inventory {
+		if len(e.Unchecked) > 0 {
+			fmt.Printf("\n%s is interpolated into configs without a PreCheck check by: %s\n", e.Name, summarizeNames(e.Unchecked, 5))
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// errorCategoryNames returns the categories marked in seen, in report order
+func errorCategoryNames(seen map[registry.ErrorCategory]bool) []string {
+	var names []string
+	for _, category := range registry.ErrorCategories {
+		if seen[category] {
+			names = append(names, string(category))
+		}
+	}
+	return names
+}
+
+// outputErrorPathsTable prints the error categories the ExpectError steps of each definition
+// target, for the definitions with at least one such step
+func outputErrorPathsTable(reg *registry.ResourceRegistry, defs []*registry.ResourceInfo, view reportView) {
+	type row struct {
+		info       *registry.ResourceInfo
+		steps      int
+		categories []string
+	}
+	var rows []row
+	for _, info := range defs {
+		steps := 0
+		seen := make(map[registry.ErrorCategory]bool)
+		for _, t := range reg.GetTests(info.Kind, info.Name) {
+			for _, step := range t.TestSteps {
+				if step.ExpectError {
+					steps++
+					seen[step.ErrorCategory] = true
+				}
+			}
+		}
+		if steps > 0 {
+			rows = append(rows, row{info, steps, errorCategoryNames(seen)})
+		}
+	}
+	if len(rows) == 0 {
+		return
+	}
+
+	fmt.Println()
+	view.section("ERROR PATHS")
+	w := view.newTable()
+	fmt.Fprintln(w, "  DEFINITION\tKIND\tEXPECTERROR STEPS\tCATEGORIES")
+	fmt.Fprintln(w, "  ──────────\t────\t─────────────────\t──────────")
+	for _, r := range rows {
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", r.info.Name, r.info.Kind, strconv.Itoa(r.steps), strings.Join(r.categories, ", "))
+	}
+	w.Flush()
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// runExplain prints the decision trail for a single resource
+func runExplain(fset *token.FileSet, files []*ast.File, settings config.Settings, format string, query string) {
+	if format != "text" && format != "json" {
+		fmt.Printf("Error: Invalid format '%s' for -explain. Must be one of: text, json\n", format)
+		exit(1)
+	}
+
+	reg := buildRegistryFromFiles(fset, files, settings)
+	explanation := analysis.Explain(query, reg, fset, files, &settings)
+
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(explanation); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		}
+		return
+	}
+	outputExplainText(explanation)
+}
+
+// outputExplainText prints an explanation for humans
+func outputExplainText(exp *analysis.Explanation) {
+	if len(exp.Definitions) == 0 {
+		fmt.Printf("No definition matches %q\n", exp.Query)
+	}
+
+	for _, def := range exp.Definitions {
+		fmt.Printf("%s %q\n", def.Kind, def.Name)
+		fmt.Printf("  Discovered: %s:%d (%s)\n", def.File, def.Line, def.DiscoveredBy)
+		if def.RegisteredAt != "" {
+			fmt.Printf("  Registered: %s\n", def.RegisteredAt)
+		}
+		if len(def.Expect) > 0 {
+			fmt.Printf("  Directives: expect %v\n", def.Expect)
+		}
+		if len(def.Exempt) > 0 {
+			fmt.Printf("  Directives: exempt %v\n", def.Exempt)
+		}
+		if def.Manifest != "" {
+			fmt.Printf("  Requirements manifest: %s\n", def.Manifest)
+		}
+
+		fmt.Printf("\n  Linked tests (%d):\n", len(def.Linked))
+		for _, c := range def.Linked {
+			fmt.Printf("    ✓ %s (%s:%d) via %s, confidence %.2f\n", c.Test, filepath.Base(c.File), c.Line, c.MatchType, c.Confidence)
+		}
+
+		if len(def.Rejected) > 0 {
+			fmt.Printf("\n  Rejected candidates (%d):\n", len(def.Rejected))
+			for _, c := range def.Rejected {
+				fmt.Printf("    ✗ %s (%s:%d)\n", c.Test, filepath.Base(c.File), c.Line)
+				fmt.Printf("      Reason: %s\n", c.Reason)
+				for _, r := range c.Matchers {
+					verdict := "no"
+					if r.Matched {
+						verdict = "yes"
+					}
+					fmt.Printf("      %-16s %-3s %.2f  %s\n", r.Matcher, verdict, r.Score, r.Detail)
+				}
+			}
+		}
+
+		if len(def.Suggestions) > 0 {
+			fmt.Println("\n  Suggestions:")
+			for _, s := range def.Suggestions {
+				fmt.Printf("    - %s\n", s)
+			}
+		}
+		if preview := analysis.FormatUpdatePreview(def.UpdatePreview); preview != "" {
+			fmt.Println(preview)
+		}
+		fmt.Println()
+	}
+
+	if len(exp.Excluded) > 0 {
+		fmt.Println("Excluded definitions:")
+		for _, e := range exp.Excluded {
+			fmt.Printf("  %s in %s (%s)\n", e.Kind, e.File, e.Reason)
+		}
+		fmt.Println()
+	}
+	if len(exp.ExcludedTests) > 0 {
+		fmt.Println("Excluded test files:")
+		for _, e := range exp.ExcludedTests {
+			fmt.Printf("  %s (%s)\n", e.File, e.Reason)
+		}
+		fmt.Println()
+	}
+	if len(exp.Suggestions) > 0 {
+		fmt.Println("Suggestions:")
+		for _, s := range exp.Suggestions {
+			fmt.Printf("  - %s\n", s)
+		}
+	}
+}
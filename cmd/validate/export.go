@@ -0,0 +1,260 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/enforcement"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/internal/routing"
+	"github.com/example/tfprovidertest/internal/rules"
+)
+
+// CodeClimateIssue is a finding in the Code Climate issue format, which GitLab code quality
+// reports and SonarQube's generic issue import also accept
+type CodeClimateIssue struct {
+	Type        string              `json:"type"`
+	CheckName   string              `json:"check_name"`
+	Description string              `json:"description"`
+	Content     *CodeClimateContent `json:"content,omitempty"`
+	Categories  []string            `json:"categories"`
+	Severity    string              `json:"severity"`
+	Fingerprint string              `json:"fingerprint"`
+	Location    CodeClimateLocation `json:"location"`
+}
+
+// CodeClimateContent holds the full, multi-line text of a Code Climate issue
+type CodeClimateContent struct {
+	Body string `json:"body"`
+}
+
+// CodeClimateLocation is the file and line a Code Climate issue points at
+type CodeClimateLocation struct {
+	Path  string           `json:"path"`
+	Lines CodeClimateLines `json:"lines"`
+}
+
+// CodeClimateLines is the line range of a Code Climate issue
+type CodeClimateLines struct {
+	Begin int `json:"begin"`
+}
+
+// buildCodeClimateIssues converts findings to Code Climate issues with paths relative to root.
+// Coverage findings are "major" and quality findings "minor"; warnings outside the enforced
+// paths are "info" whatever their group.
+func buildCodeClimateIssues(findings []Finding, root string) []CodeClimateIssue {
+	issues := make([]CodeClimateIssue, 0, len(findings))
+	for _, f := range findings {
+		severity := "minor"
+		if rules.Group(f.Group) == rules.GroupCoverage {
+			severity = "major"
+		}
+		if f.Level == string(enforcement.LevelWarning) {
+			severity = "info"
+		}
+		path := routing.RelativePath(root, f.File)
+		summary, _, multiline := strings.Cut(f.Message, "\n")
+		issue := CodeClimateIssue{
+			Type:        "issue",
+			CheckName:   f.Rule,
+			Description: summary,
+			Categories:  []string{"Bug Risk"},
+			Severity:    severity,
+			Fingerprint: routing.Fingerprint(f.Rule, path, summary),
+			Location: CodeClimateLocation{
+				Path:  path,
+				Lines: CodeClimateLines{Begin: f.Line},
+			},
+		}
+		if multiline {
+			issue.Content = &CodeClimateContent{Body: f.Message}
+		}
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+// outputCodeClimate prints findings as a Code Climate JSON array
+func outputCodeClimate(findings []Finding, root string) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(buildCodeClimateIssues(findings, root)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+	}
+}
+
+// HeatmapRow is one file in the coverage heatmap CSV
+type HeatmapRow struct {
+	Path        string
+	LOC         int
+	Definitions int
+	Tested      int
+	Findings    int
+}
+
+// heatmapHeader lists the CSV columns written by writeHeatmapCSV
+var heatmapHeader = []string{"path", "loc", "definitions", "tested", "findings", "coverage"}
+
+// buildHeatmap returns a row for every non-test Go file, so a treemap sized by loc covers
+// the whole scanned tree. Files without definitions have no coverage score.
+func buildHeatmap(fset *token.FileSet, files []*ast.File, reg *registry.ResourceRegistry, findings []Finding, root string) []HeatmapRow {
+	rows := make(map[string]*HeatmapRow)
+	for _, file := range files {
+		tf := fset.File(file.Pos())
+		if tf == nil || strings.HasSuffix(tf.Name(), "_test.go") {
+			continue
+		}
+		rows[tf.Name()] = &HeatmapRow{Path: routing.RelativePath(root, tf.Name()), LOC: tf.LineCount()}
+	}
+
+	for _, info := range reg.GetSortedDefinitions() {
+		row, ok := rows[info.FilePath]
+		if !ok {
+			continue
+		}
+		row.Definitions++
+		if len(reg.GetTests(info.Kind, info.Name)) > 0 {
+			row.Tested++
+		}
+	}
+	for _, f := range findings {
+		if row, ok := rows[f.File]; ok {
+			row.Findings++
+		}
+	}
+
+	result := make([]HeatmapRow, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, *row)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Path < result[j].Path })
+	return result
+}
+
+// Coverage returns the share of the file's definitions that have tests, or false when the
+// file declares none
+func (r HeatmapRow) Coverage() (float64, bool) {
+	if r.Definitions == 0 {
+		return 0, false
+	}
+	return float64(r.Tested) / float64(r.Definitions), true
+}
+
+// writeHeatmapCSV writes the heatmap to path, or to stdout when path is "-"
+func writeHeatmapCSV(rows []HeatmapRow, path string) error {
+	var out io.Writer = os.Stdout
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	w := csv.NewWriter(out)
+	if err := w.Write(heatmapHeader); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		coverage := ""
+		if score, ok := row.Coverage(); ok {
+			coverage = strconv.FormatFloat(score, 'f', 2, 64)
+		}
+		record := []string{
+			row.Path,
+			strconv.Itoa(row.LOC),
+			strconv.Itoa(row.Definitions),
+			strconv.Itoa(row.Tested),
+			strconv.Itoa(row.Findings),
+			coverage,
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// reportCSVHeader lists the CSV columns written by writeReportCSV
+var reportCSVHeader = []string{
+	"kind", "name", "file", "test_files", "test_count", "tests",
+	"has_check_destroy", "has_check", "has_config_state_checks", "has_plan_check",
+	"has_import_test", "has_update_test", "has_expect_error", "has_opaque_steps",
+	"match_confidence", "match_type", "error_categories",
+}
+
+// writeReportCSV writes one row per resource, data source and action for -report -format csv.
+// Test names and test files are joined with semicolons; match_confidence is empty for
+// untested definitions.
+func writeReportCSV(out io.Writer, reg *registry.ResourceRegistry, resources, dataSources, actions []*registry.ResourceInfo) error {
+	w := csv.NewWriter(out)
+	if err := w.Write(reportCSVHeader); err != nil {
+		return err
+	}
+	groups := []struct {
+		kind  registry.ResourceKind
+		defs  []*registry.ResourceInfo
+		build func(*registry.ResourceRegistry, *registry.ResourceInfo) ResourceReport
+	}{
+		{registry.KindResource, resources, buildResourceReport},
+		{registry.KindDataSource, dataSources, buildResourceReport},
+		{registry.KindAction, actions, buildActionReport},
+	}
+	for _, group := range groups {
+		for _, info := range group.defs {
+			if err := w.Write(reportCSVRecord(group.kind, group.build(reg, info))); err != nil {
+				return err
+			}
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// reportCSVRecord formats a definition's report as a writeReportCSV row
+func reportCSVRecord(kind registry.ResourceKind, report ResourceReport) []string {
+	var names, files []string
+	seen := make(map[string]bool)
+	for _, t := range report.Tests {
+		names = append(names, t.Name)
+		if !seen[t.File] {
+			seen[t.File] = true
+			files = append(files, t.File)
+		}
+	}
+	sort.Strings(files)
+
+	confidence := ""
+	if report.TestCount > 0 {
+		confidence = strconv.FormatFloat(report.MatchConfidence, 'f', 2, 64)
+	}
+	return []string{
+		kind.String(),
+		report.Name,
+		report.File,
+		strings.Join(files, ";"),
+		strconv.Itoa(report.TestCount),
+		strings.Join(names, ";"),
+		strconv.FormatBool(report.HasCheckDestroy),
+		strconv.FormatBool(report.HasCheck),
+		strconv.FormatBool(report.HasConfigStateChecks),
+		strconv.FormatBool(report.HasPlanCheck),
+		strconv.FormatBool(report.HasImportTest),
+		strconv.FormatBool(report.HasUpdateTest),
+		strconv.FormatBool(report.HasExpectError),
+		strconv.FormatBool(report.HasOpaqueSteps),
+		confidence,
+		report.MatchType,
+		strings.Join(report.ErrorCategories, ";"),
+	}
+}
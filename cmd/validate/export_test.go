@@ -0,0 +1,97 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// heatmapSources are the files of the heatmap provider, by path under /provider.
+var heatmapSources = map[string]string{
+	"resource_widget.go":      "package provider\n\ntype WidgetResource struct{}\n\ntype WidgetDataSource struct{}\n",
+	"resource_widget_test.go": "package provider\n",
+	"resource_gadget.go":      "package provider\n\ntype GadgetResource struct{}\n",
+	"internal/util.go":        "package util\n",
+}
+
+// heatmapRegistry parses heatmapSources and returns them with a registry of the widget
+// resource and data source and the gadget resource, of which only the widget resource is
+// tested.
+func heatmapRegistry(t *testing.T) (*token.FileSet, []*ast.File, *registry.ResourceRegistry) {
+	t.Helper()
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for name, src := range heatmapSources {
+		file, err := parser.ParseFile(fset, "/provider/"+name, src, 0)
+		require.NoError(t, err)
+		files = append(files, file)
+	}
+
+	reg := registry.NewResourceRegistry()
+	for _, def := range []*registry.ResourceInfo{
+		{Name: "widget", Kind: registry.KindResource, FilePath: "/provider/resource_widget.go"},
+		{Name: "widget", Kind: registry.KindDataSource, FilePath: "/provider/resource_widget.go"},
+		{Name: "gadget", Kind: registry.KindResource, FilePath: "/provider/resource_gadget.go"},
+		// Declared outside the scanned files, so no row counts it
+		{Name: "gizmo", Kind: registry.KindResource, FilePath: "/elsewhere/resource_gizmo.go"},
+	} {
+		reg.RegisterResource(def)
+	}
+	fn := &registry.TestFunctionInfo{Name: "TestAccWidget_basic", FilePath: "/provider/resource_widget_test.go"}
+	reg.RegisterTestFunction(fn)
+	reg.LinkTest(registry.KindResource, "widget", fn)
+	return fset, files, reg
+}
+
+func TestWriteHeatmapCSV(t *testing.T) {
+	fset, files, reg := heatmapRegistry(t)
+
+	tests := []struct {
+		name     string
+		findings []Finding
+		want     []string
+	}{
+		{
+			name: "no findings",
+			want: []string{
+				"path,loc,definitions,tested,findings,coverage",
+				"internal/util.go,1,0,0,0,",
+				"resource_gadget.go,3,1,0,0,0.00",
+				"resource_widget.go,5,2,1,0,0.50",
+			},
+		},
+		{
+			name: "findings count against their file",
+			findings: []Finding{
+				{Rule: "basic", File: "/provider/resource_gadget.go", Line: 3},
+				{Rule: "import", File: "/provider/resource_widget.go", Line: 3},
+				{Rule: "update", File: "/provider/resource_widget.go", Line: 5},
+				{Rule: "layout", File: "/provider/resource_widget_test.go", Line: 1},
+			},
+			want: []string{
+				"path,loc,definitions,tested,findings,coverage",
+				"internal/util.go,1,0,0,0,",
+				"resource_gadget.go,3,1,0,1,0.00",
+				"resource_widget.go,5,2,1,2,0.50",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "heatmap.csv")
+			require.NoError(t, writeHeatmapCSV(buildHeatmap(fset, files, reg, tt.findings, "/provider"), path))
+			got, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, strings.Join(tt.want, "\n")+"\n", string(got), "sorted by path, without test files")
+		})
+	}
+}
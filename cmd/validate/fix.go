@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// fileEdit is a text edit resolved to byte offsets in its file.
+type fileEdit struct {
+	start, end int
+	text       []byte
+}
+
+// applyFixes applies the text edits of suggested fixes to the files they edit and gofmts the
+// result. Edits that overlap an earlier one in the same file are dropped, as are duplicates,
+// so two analyzers suggesting the same fix apply it once. It returns the files written, in
+// order.
+func applyFixes(fset *token.FileSet, fixes []analysis.SuggestedFix) ([]string, error) {
+	byFile := make(map[string][]fileEdit)
+	for _, fix := range fixes {
+		for _, edit := range fix.TextEdits {
+			tokFile := fset.File(edit.Pos)
+			if tokFile == nil {
+				continue
+			}
+			end := edit.End
+			if !end.IsValid() {
+				end = edit.Pos
+			}
+			byFile[tokFile.Name()] = append(byFile[tokFile.Name()], fileEdit{
+				start: tokFile.Offset(edit.Pos),
+				end:   tokFile.Offset(end),
+				text:  edit.NewText,
+			})
+		}
+	}
+
+	var written []string
+	for path := range byFile {
+		written = append(written, path)
+	}
+	sort.Strings(written)
+	for _, path := range written {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		out, err := format.Source(applyEdits(src, byFile[path]))
+		if err != nil {
+			return nil, fmt.Errorf("fixing %s: %w", path, err)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(path, out, info.Mode().Perm()); err != nil {
+			return nil, err
+		}
+	}
+	return written, nil
+}
+
+// applyEdits returns src with the edits applied, skipping edits that overlap one before it.
+func applyEdits(src []byte, edits []fileEdit) []byte {
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+	var out bytes.Buffer
+	offset := 0
+	for i, edit := range edits {
+		if edit.start < offset || edit.end > len(src) {
+			continue
+		}
+		if i > 0 && edit.start == edits[i-1].start && edit.end == edits[i-1].end && bytes.Equal(edit.text, edits[i-1].text) {
+			continue
+		}
+		out.Write(src[offset:edit.start])
+		out.Write(edit.text)
+		offset = edit.end
+	}
+	out.Write(src[offset:])
+	return out.Bytes()
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+
+	"github.com/example/tfprovidertest/internal/freeze"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// Freeze modes of -freeze.
+const (
+	freezeWrite = "write"
+	freezeCheck = "check"
+)
+
+// runFreeze writes the coverage freeze file, or checks the provider against it and exits with
+// status 1 when a frozen check is no longer covered. path defaults to the freeze file in the
+// provider directory.
+func runFreeze(fset *token.FileSet, files []*ast.File, settings config.Settings, format, mode, path, providerPath string) {
+	if mode != freezeWrite && mode != freezeCheck {
+		fmt.Printf("Error: Invalid -freeze mode '%s'. Must be one of: write, check\n", mode)
+		exit(1)
+	}
+	if format != "text" && format != "json" {
+		fmt.Printf("Error: Invalid format '%s' for -freeze. Must be one of: text, json\n", format)
+		exit(1)
+	}
+	if path == "" {
+		path = filepath.Join(providerPath, freeze.File)
+	}
+
+	reg := buildRegistryFromFiles(fset, files, settings)
+	if mode == freezeWrite {
+		frozen := freeze.Capture(reg)
+		if err := frozen.Write(path); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Could not write freeze file: %v\n", err)
+			exit(1)
+		}
+		fmt.Printf("Froze the coverage of %d definitions in %s\n", len(frozen.Coverage), path)
+		return
+	}
+
+	frozen, err := freeze.Load(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Could not read freeze file: %v\n", err)
+		exit(1)
+	}
+	result := frozen.Check(reg)
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		}
+	} else {
+		outputFreezeText(result, path)
+	}
+	if len(result.Regressions) > 0 {
+		exit(1)
+	}
+}
+
+// outputFreezeText prints the regressions, then what would change if the file were rewritten
+func outputFreezeText(result freeze.Result, path string) {
+	if len(result.Regressions) == 0 {
+		fmt.Printf("Coverage freeze passed: no check frozen in %s was lost\n", path)
+	} else {
+		fmt.Printf("COVERAGE FREEZE FAILED: %d frozen checks are no longer covered\n", len(result.Regressions))
+		for _, r := range result.Regressions {
+			fmt.Printf("  %s %q: %s\n", r.Key.Kind, r.Key.Name, r.Check)
+		}
+		fmt.Println("Restore the tests that covered them, or rewrite the freeze file with -freeze write if the loss is intended.")
+	}
+	if len(result.Removed) > 0 {
+		fmt.Printf("%d frozen definitions no longer exist: %s\n", len(result.Removed), summarizeNames(result.Removed, 5))
+	}
+	if result.Gained > 0 {
+		fmt.Printf("%d newly covered checks are not frozen yet; run -freeze write to lock them in\n", result.Gained)
+	}
+}
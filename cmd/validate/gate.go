@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/ghaction"
+	"github.com/example/tfprovidertest/internal/plan"
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// Gate statuses, from best to worst.
+const (
+	gatePass = "pass"
+	gateWarn = "warn"
+	gateFail = "fail"
+)
+
+// coverageGate holds the coverage thresholds of -fail-under and -warn-under. A threshold of
+// 0 is disabled. The warn threshold is the stricter one, so teams can announce a gate they
+// are about to tighten weeks before builds start failing on it.
+type coverageGate struct {
+	FailUnder  float64
+	WarnUnder  float64
+	OutputPath string
+}
+
+// GateResult is the outcome of the coverage gate, reported as "gate" in -format json.
+type GateResult struct {
+	Status    string  `json:"status"`
+	Coverage  float64 `json:"coverage"`
+	Tested    int     `json:"tested"`
+	Total     int     `json:"total"`
+	FailUnder float64 `json:"fail_under,omitempty"`
+	WarnUnder float64 `json:"warn_under,omitempty"`
+}
+
+// parseCoverageGate parses the -fail-under and -warn-under percentages; either may be empty.
+func parseCoverageGate(failUnder, warnUnder, outputPath string) (coverageGate, error) {
+	gate := coverageGate{OutputPath: outputPath}
+	var err error
+	if failUnder != "" {
+		if gate.FailUnder, err = plan.ParseTarget(failUnder); err != nil {
+			return gate, fmt.Errorf("-fail-under: %w", err)
+		}
+	}
+	if warnUnder != "" {
+		if gate.WarnUnder, err = plan.ParseTarget(warnUnder); err != nil {
+			return gate, fmt.Errorf("-warn-under: %w", err)
+		}
+	}
+	if gate.FailUnder > 0 && gate.WarnUnder > 0 && gate.WarnUnder < gate.FailUnder {
+		return gate, fmt.Errorf("-warn-under (%s) must not be lower than -fail-under (%s)", ghaction.FormatPercent(gate.WarnUnder), ghaction.FormatPercent(gate.FailUnder))
+	}
+	if outputPath != "" && !gate.enabled() {
+		return gate, fmt.Errorf("-gate-output requires -fail-under or -warn-under")
+	}
+	return gate, nil
+}
+
+// enabled reports whether either threshold is set.
+func (g coverageGate) enabled() bool {
+	return g.FailUnder > 0 || g.WarnUnder > 0
+}
+
+// evaluate compares the share of definitions with at least one linked test to the thresholds.
+// A provider without definitions passes.
+func (g coverageGate) evaluate(reg *registry.ResourceRegistry) GateResult {
+	result := GateResult{Status: gatePass, FailUnder: g.FailUnder, WarnUnder: g.WarnUnder}
+	for _, info := range reg.GetSortedDefinitions() {
+		result.Total++
+		if len(reg.GetTests(info.Kind, info.Name)) > 0 {
+			result.Tested++
+		}
+	}
+	if result.Total == 0 {
+		result.Coverage = 1
+		return result
+	}
+	result.Coverage = float64(result.Tested) / float64(result.Total)
+
+	switch {
+	case g.FailUnder > 0 && result.Coverage < g.FailUnder:
+		result.Status = gateFail
+	case g.WarnUnder > 0 && result.Coverage < g.WarnUnder:
+		result.Status = gateWarn
+	}
+	return result
+}
+
+// exitCode returns the exit code of a run with this gate result: 1 when the gate failed and
+// 0 otherwise, since a warning must not fail the build.
+func (r GateResult) exitCode() int {
+	if r.Status == gateFail {
+		return 1
+	}
+	return 0
+}
+
+// printGateResult prints the outcome of the gate. A warning or failure is framed so it
+// stands out in CI logs; with machine-readable formats it goes to stderr.
+func printGateResult(w io.Writer, result GateResult) {
+	if result.Status == gatePass {
+		fmt.Fprintf(w, "Coverage gate passed: %s of definitions tested (%d/%d)\n", ghaction.FormatPercent(result.Coverage), result.Tested, result.Total)
+		return
+	}
+
+	var lines []string
+	if result.Status == gateFail {
+		lines = append(lines,
+			fmt.Sprintf("COVERAGE GATE FAILED: %s of definitions tested (%d/%d)", ghaction.FormatPercent(result.Coverage), result.Tested, result.Total),
+			fmt.Sprintf("The build fails below %s.", ghaction.FormatPercent(result.FailUnder)))
+	} else {
+		lines = append(lines,
+			fmt.Sprintf("COVERAGE WARNING: %s of definitions tested (%d/%d)", ghaction.FormatPercent(result.Coverage), result.Tested, result.Total),
+			fmt.Sprintf("This is below the upcoming gate of %s.", ghaction.FormatPercent(result.WarnUnder)))
+		if result.FailUnder > 0 {
+			lines = append(lines, fmt.Sprintf("The build passes for now; it fails below %s.", ghaction.FormatPercent(result.FailUnder)))
+		} else {
+			lines = append(lines, "The build passes for now.")
+		}
+	}
+	if needed := definitionsNeeded(result); needed > 0 {
+		lines = append(lines, fmt.Sprintf("Cover %d more definition(s) to clear it (see -plan-to).", needed))
+	}
+
+	width := 0
+	for _, line := range lines {
+		width = max(width, len(line))
+	}
+	rule := strings.Repeat("!", width+4)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, rule)
+	for _, line := range lines {
+		fmt.Fprintf(w, "! %-*s !\n", width, line)
+	}
+	fmt.Fprintln(w, rule)
+}
+
+// definitionsNeeded returns how many more definitions must be tested to reach the threshold
+// the result falls short of.
+func definitionsNeeded(result GateResult) int {
+	threshold := result.WarnUnder
+	if result.Status == gateFail {
+		threshold = result.FailUnder
+	}
+	needed := 0
+	for float64(result.Tested+needed)/float64(result.Total) < threshold {
+		needed++
+	}
+	return needed
+}
+
+// writeGateOutput appends the result as key=value lines to path, the format of
+// $GITHUB_OUTPUT, so later workflow steps can comment on a pull request when the gate warns.
+func writeGateOutput(path string, result GateResult) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(f, "coverage_gate=%s\n", result.Status)
+	fmt.Fprintf(f, "coverage_gate_warning=%t\n", result.Status == gateWarn)
+	fmt.Fprintf(f, "coverage=%.1f\n", result.Coverage*100)
+	if result.FailUnder > 0 {
+		fmt.Fprintf(f, "coverage_fail_under=%.1f\n", result.FailUnder*100)
+	}
+	if result.WarnUnder > 0 {
+		fmt.Fprintf(f, "coverage_warn_under=%.1f\n", result.WarnUnder*100)
+	}
+	return f.Close()
+}
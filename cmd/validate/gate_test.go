@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// gateRegistry returns a registry of total resources, the first tested of which have a test.
+func gateRegistry(total, tested int) *registry.ResourceRegistry {
+	reg := registry.NewResourceRegistry()
+	for i := 0; i < total; i++ {
+		name := fmt.Sprintf("r%02d", i)
+		reg.RegisterResource(&registry.ResourceInfo{Name: name, Kind: registry.KindResource, FilePath: "/provider/resource_" + name + ".go"})
+		if i < tested {
+			fn := &registry.TestFunctionInfo{Name: "TestAcc" + name, FilePath: "/provider/resource_" + name + "_test.go"}
+			reg.RegisterTestFunction(fn)
+			reg.LinkTest(registry.KindResource, name, fn)
+		}
+	}
+	return reg
+}
+
+func TestParseCoverageGate(t *testing.T) {
+	tests := []struct {
+		name      string
+		failUnder string
+		warnUnder string
+		output    string
+		want      coverageGate
+		wantErr   string
+	}{
+		{name: "disabled", want: coverageGate{}},
+		{name: "fail only", failUnder: "80%", want: coverageGate{FailUnder: 0.8}},
+		{name: "without percent sign", warnUnder: "90", want: coverageGate{WarnUnder: 0.9}},
+		{name: "warn equal to fail", failUnder: "80%", warnUnder: "80%", want: coverageGate{FailUnder: 0.8, WarnUnder: 0.8}},
+		{name: "100 percent", failUnder: "100%", want: coverageGate{FailUnder: 1}},
+		{name: "gate output", failUnder: "50%", output: "out.txt", want: coverageGate{FailUnder: 0.5, OutputPath: "out.txt"}},
+		{name: "warn below fail", failUnder: "80%", warnUnder: "79.5%", wantErr: "-warn-under (79.5%) must not be lower than -fail-under (80%)"},
+		{name: "zero", failUnder: "0%", wantErr: "-fail-under: invalid coverage target"},
+		{name: "over 100", warnUnder: "100.1%", wantErr: "-warn-under: invalid coverage target"},
+		{name: "not a number", failUnder: "most", wantErr: "-fail-under: invalid coverage target"},
+		{name: "gate output without a threshold", output: "out.txt", wantErr: "-gate-output requires -fail-under or -warn-under"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gate, err := parseCoverageGate(tt.failUnder, tt.warnUnder, tt.output)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, tt.want.FailUnder, gate.FailUnder, 1e-9)
+			assert.InDelta(t, tt.want.WarnUnder, gate.WarnUnder, 1e-9)
+			assert.Equal(t, tt.want.OutputPath, gate.OutputPath)
+		})
+	}
+}
+
+func TestCoverageGateEvaluate(t *testing.T) {
+	tests := []struct {
+		name       string
+		gate       coverageGate
+		total      int
+		tested     int
+		wantStatus string
+		wantCode   int
+		wantNeeded int
+	}{
+		{name: "exactly at fail-under passes", gate: coverageGate{FailUnder: 0.5}, total: 10, tested: 5, wantStatus: gatePass},
+		{name: "just below fail-under fails", gate: coverageGate{FailUnder: 0.5}, total: 10, tested: 4, wantStatus: gateFail, wantCode: 1, wantNeeded: 1},
+		{name: "exactly at warn-under passes", gate: coverageGate{WarnUnder: 0.8}, total: 10, tested: 8, wantStatus: gatePass},
+		{name: "just below warn-under warns", gate: coverageGate{WarnUnder: 0.8}, total: 10, tested: 7, wantStatus: gateWarn, wantNeeded: 1},
+		{name: "between the thresholds warns", gate: coverageGate{FailUnder: 0.5, WarnUnder: 0.8}, total: 10, tested: 6, wantStatus: gateWarn, wantNeeded: 2},
+		{name: "below both fails", gate: coverageGate{FailUnder: 0.5, WarnUnder: 0.8}, total: 10, tested: 2, wantStatus: gateFail, wantCode: 1, wantNeeded: 3},
+		{name: "fraction rounds up the definitions needed", gate: coverageGate{FailUnder: 0.5}, total: 3, tested: 1, wantStatus: gateFail, wantCode: 1, wantNeeded: 1},
+		{name: "100 percent needs every definition", gate: coverageGate{FailUnder: 1}, total: 4, tested: 3, wantStatus: gateFail, wantCode: 1, wantNeeded: 1},
+		{name: "no definitions passes", gate: coverageGate{FailUnder: 1}, wantStatus: gatePass},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.gate.evaluate(gateRegistry(tt.total, tt.tested))
+			assert.Equal(t, tt.wantStatus, result.Status)
+			assert.Equal(t, tt.total, result.Total)
+			assert.Equal(t, tt.tested, result.Tested)
+			assert.Equal(t, tt.wantCode, result.exitCode())
+			if result.Status != gatePass {
+				assert.Equal(t, tt.wantNeeded, definitionsNeeded(result))
+			}
+		})
+	}
+}
+
+func TestPrintGateResult(t *testing.T) {
+	var out bytes.Buffer
+	printGateResult(&out, GateResult{Status: gatePass, Coverage: 0.5, Tested: 5, Total: 10})
+	assert.Equal(t, "Coverage gate passed: 50% of definitions tested (5/10)\n", out.String())
+
+	out.Reset()
+	printGateResult(&out, GateResult{Status: gateWarn, Coverage: 0.6, Tested: 6, Total: 10, FailUnder: 0.5, WarnUnder: 0.8})
+	assert.Contains(t, out.String(), "! COVERAGE WARNING: 60% of definitions tested (6/10)")
+	assert.Contains(t, out.String(), "The build passes for now; it fails below 50%.")
+	assert.Contains(t, out.String(), "Cover 2 more definition(s) to clear it (see -plan-to).")
+
+	out.Reset()
+	printGateResult(&out, GateResult{Status: gateFail, Coverage: 0.4, Tested: 4, Total: 10, FailUnder: 0.5})
+	assert.Contains(t, out.String(), "! COVERAGE GATE FAILED: 40% of definitions tested (4/10)")
+	assert.Contains(t, out.String(), "The build fails below 50%.")
+}
+
+func TestWriteGateOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "github_output")
+	require.NoError(t, os.WriteFile(path, []byte("earlier=step\n"), 0o644))
+	require.NoError(t, writeGateOutput(path, GateResult{Status: gateWarn, Coverage: 0.6, WarnUnder: 0.8}))
+
+	got, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "earlier=step\ncoverage_gate=warn\ncoverage_gate_warning=true\ncoverage=60.0\ncoverage_warn_under=80.0\n", string(got),
+		"appended, without the threshold that is not set")
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/stubs"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// generateCurrentFile is the -resource value that selects the definitions of the file being
+// generated, named by $GOFILE under go:generate
+const generateCurrentFile = "current-file"
+
+// runGenerate writes stub tests for untested definitions selected by target: current-file,
+// all, or a comma-separated list of names
+func runGenerate(fset *token.FileSet, files []*ast.File, settings config.Settings, format string, target string, dryRun bool) {
+	if format != "text" && format != "json" {
+		fmt.Printf("Error: Invalid format '%s' for generate. Must be one of: text, json\n", format)
+		exit(1)
+	}
+
+	opts := stubs.Options{DryRun: dryRun}
+	switch target {
+	case generateCurrentFile:
+		opts.File = os.Getenv("GOFILE")
+		if opts.File == "" {
+			fmt.Println("Error: -resource current-file needs $GOFILE; run it from a //go:generate directive")
+			exit(1)
+		}
+	case "all", "":
+	default:
+		opts.Names = make(map[string]bool)
+		for _, name := range splitList(target) {
+			opts.Names[name] = true
+			if _, unprefixed, ok := strings.Cut(name, "_"); ok {
+				opts.Names[unprefixed] = true
+			}
+		}
+	}
+
+	packages := make(map[string]string, len(files))
+	for _, file := range files {
+		packages[fset.Position(file.Pos()).Filename] = file.Name.Name
+	}
+
+	reg := buildRegistryFromFiles(fset, files, settings)
+	generated, err := stubs.Generate(reg, packages, opts)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(generated); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		}
+		return
+	}
+	// Runs from go:generate stay quiet unless a stub changes; -verbose also lists skips
+	for _, stub := range generated {
+		if stub.Status == stubs.StatusUnchanged || (stub.Status == stubs.StatusSkipped && !settings.Verbose) {
+			continue
+		}
+		line := fmt.Sprintf("%-9s %s (%s)", stub.Status, stub.File, stub.Test)
+		if stub.Reason != "" {
+			line += ": " + stub.Reason
+		}
+		fmt.Println(line)
+	}
+}
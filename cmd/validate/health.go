@@ -0,0 +1,130 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"time"
+
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/internal/scan"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// Phase names recorded in RunHealth
+const (
+	phaseParse     = "parse"
+	phaseDiscovery = discovery.PhaseDiscovery
+	phaseLinking   = discovery.PhaseLinking
+	phaseAnalyzers = "analyzers"
+	phaseReport    = "report"
+)
+
+// reasonParseError is the skip reason of the files of a directory that failed to parse.
+const reasonParseError = "parse error"
+
+// RunHealth describes the run itself rather than the provider: what was read, how each
+// discovery strategy and linker match type fared, and where the time went. A report that
+// shifts while these counts shift with it points at the tool rather than the tests.
+type RunHealth struct {
+	FilesParsed  int            `json:"files_parsed"`
+	FilesSkipped []HealthSkip   `json:"files_skipped,omitempty"`
+	SkipReasons  map[string]int `json:"skip_reasons,omitempty"`
+	Strategies   map[string]int `json:"discovery_strategies"` // Definitions found by each discovery strategy
+	MatchTypes   map[string]int `json:"match_types"`          // Tests linked by each match type; "none" counts unlinked tests
+	Phases       []HealthPhase  `json:"phases"`
+}
+
+// HealthSkip is a file or directory left out of analysis and why
+type HealthSkip struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// HealthPhase is the time spent in one phase of the run
+type HealthPhase struct {
+	Name      string  `json:"name"`
+	ElapsedMS float64 `json:"elapsed_ms"`
+}
+
+// healthRecorder collects RunHealth as the run goes. A nil *healthRecorder records nothing, so code
+// shared with commands that do not report health can take one unconditionally.
+type healthRecorder struct {
+	start   time.Time
+	phases  []HealthPhase
+	skipped []HealthSkip
+	parsed  int
+}
+
+// newHealthRecorder starts timing the first phase
+func newHealthRecorder() *healthRecorder {
+	return &healthRecorder{start: time.Now()}
+}
+
+// lap ends the current phase under name and starts the next one
+func (m *healthRecorder) lap(name string) {
+	if m == nil {
+		return
+	}
+	now := time.Now()
+	m.phases = append(m.phases, HealthPhase{Name: name, ElapsedMS: float64(now.Sub(m.start).Microseconds()) / 1000})
+	m.start = now
+}
+
+// skip records a file or directory left out of analysis
+func (m *healthRecorder) skip(path, reason, detail string) {
+	if m == nil {
+		return
+	}
+	m.skipped = append(m.skipped, HealthSkip{Path: path, Reason: reason, Detail: detail})
+}
+
+// skipLimited records the files a FileLimiter left out
+func (m *healthRecorder) skipLimited(skipped []scan.SkippedFile) {
+	for _, s := range skipped {
+		m.skip(s.Path, s.Reason, "")
+	}
+}
+
+// health returns the metrics with the strategy and match type counts of reg. Files that
+// were parsed but excluded from discovery are counted under their exclusion reason.
+func (m *healthRecorder) health(reg *registry.ResourceRegistry, fset *token.FileSet, files []*ast.File, settings config.Settings) *RunHealth {
+	if m == nil {
+		return nil
+	}
+	h := &RunHealth{
+		FilesParsed:  m.parsed,
+		FilesSkipped: append([]HealthSkip(nil), m.skipped...),
+		Strategies:   make(map[string]int),
+		MatchTypes:   make(map[string]int),
+		Phases:       m.phases,
+	}
+	classifier := discovery.FileClassifier(settings)
+	for _, file := range files {
+		path := fset.Position(file.Pos()).Filename
+		if reason := discovery.ExclusionReason(settings, classifier, path); reason != "" {
+			h.FilesSkipped = append(h.FilesSkipped, HealthSkip{Path: path, Reason: reason})
+		}
+	}
+	sort.SliceStable(h.FilesSkipped, func(i, j int) bool { return h.FilesSkipped[i].Path < h.FilesSkipped[j].Path })
+	for _, s := range h.FilesSkipped {
+		if h.SkipReasons == nil {
+			h.SkipReasons = make(map[string]int)
+		}
+		h.SkipReasons[s.Reason]++
+	}
+
+	for _, def := range reg.GetAllDefinitions() {
+		strategy := def.DiscoveredBy
+		if strategy == "" {
+			strategy = "unknown"
+		}
+		h.Strategies[strategy]++
+	}
+	for _, fn := range reg.GetAllTestFunctions() {
+		h.MatchTypes[fn.MatchType.String()]++
+	}
+	return h
+}
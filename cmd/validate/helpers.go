@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// HelperReport describes a test helper for JSON output
+type HelperReport struct {
+	Name           string   `json:"name"`
+	Kind           string   `json:"kind"`
+	File           string   `json:"file"`
+	Line           int      `json:"line"`
+	HelperOnlyFile bool     `json:"helper_only_file"`
+	UsageCount     int      `json:"usage_count"`
+	UsedBy         []string `json:"used_by"`
+	References     []string `json:"references"`
+	// Detected is why a helper of kind "package" was detected, e.g. "returns resource.TestCase"
+	Detected string `json:"detected,omitempty"`
+}
+
+// runHelperCatalogue lists all discovered local test helpers, followed by the exported
+// helpers of detected helper packages unless the settings ignore them
+func runHelperCatalogue(fset *token.FileSet, files []*ast.File, settings config.Settings, format string) {
+	if format != "text" && format != "json" && format != "table" {
+		fmt.Printf("Error: Invalid format '%s'. Must be one of: text, json, table\n", format)
+		exit(1)
+	}
+
+	helpers := discovery.CatalogTestHelpers(files, fset)
+	reports := make([]HelperReport, 0, len(helpers))
+	for _, h := range helpers {
+		report := HelperReport{
+			Name:           h.Name,
+			Kind:           h.Kind.String(),
+			File:           h.FilePath,
+			Line:           fset.Position(h.Pos).Line,
+			HelperOnlyFile: h.HelperOnlyFile,
+			UsageCount:     len(h.UsedBy),
+			UsedBy:         h.UsedBy,
+			References:     make([]string, 0, len(h.ReferencedBlocks)),
+		}
+		for _, block := range h.ReferencedBlocks {
+			report.References = append(report.References, block.BlockType+"."+block.ResourceType)
+		}
+		reports = append(reports, report)
+	}
+	if !settings.IgnoreHelperPackages {
+		for _, pkg := range discovery.DetectHelperPackages(files, fset) {
+			for _, h := range pkg.Helpers {
+				reports = append(reports, HelperReport{
+					Name:           h.Qualified[0],
+					Kind:           "package",
+					File:           h.File,
+					Line:           fset.Position(h.Pos).Line,
+					HelperOnlyFile: true,
+					UsageCount:     len(h.UsedBy),
+					UsedBy:         h.UsedBy,
+					References:     []string{},
+					Detected:       h.Reason,
+				})
+			}
+		}
+	}
+
+	switch format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(reports); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		}
+	case "table":
+		outputHelpersTable(reports)
+	default:
+		outputHelpersText(reports)
+	}
+}
+
+// outputHelpersText outputs helpers grouped by file
+func outputHelpersText(reports []HelperReport) {
+	fmt.Println("=== Test Helpers ===")
+	fmt.Println()
+	if len(reports) == 0 {
+		fmt.Println("  No local test helpers found")
+		return
+	}
+
+	currentFile := ""
+	for _, r := range reports {
+		if r.File != currentFile {
+			currentFile = r.File
+			label := ""
+			if r.HelperOnlyFile {
+				label = " (helpers only)"
+			}
+			fmt.Printf("%s%s\n", r.File, label)
+		}
+		fmt.Printf("  %s [%s] line %d, used by %d test(s)\n", r.Name, r.Kind, r.Line, r.UsageCount)
+		if r.Detected != "" {
+			fmt.Printf("    Detected: %s\n", r.Detected)
+		}
+		if len(r.References) > 0 {
+			fmt.Printf("    References: %s\n", strings.Join(r.References, ", "))
+		}
+		if r.UsageCount == 0 {
+			fmt.Println("    Unused by any test function - candidate for removal or consolidation")
+		}
+	}
+	fmt.Println()
+	fmt.Printf("Found %d helper(s)\n", len(reports))
+}
+
+// outputHelpersTable outputs helpers in a formatted table
+func outputHelpersTable(reports []HelperReport) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "HELPER\tKIND\tFILE\tUSES\tREFERENCES")
+	fmt.Fprintln(w, "------\t----\t----\t----\t----------")
+	for _, r := range reports {
+		refs := "-"
+		if len(r.References) > 0 {
+			refs = strings.Join(r.References, ", ")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", r.Name, r.Kind, filepath.Base(r.File), r.UsageCount, refs)
+	}
+	w.Flush()
+}
+
+// printHelperPackages writes the helper packages detected in the scanned tree, whose
+// helpers count as resource.Test, for -verbose
+func printHelperPackages(w io.Writer, fset *token.FileSet, packages []discovery.HelperPackage) {
+	if len(packages) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "Detected helper packages: %d\n", len(packages))
+	for _, pkg := range packages {
+		fmt.Fprintf(w, "  %s (%s): %d helper(s)\n", pkg.Name, pkg.Dir, len(pkg.Helpers))
+		for _, h := range pkg.Helpers {
+			fmt.Fprintf(w, "    %s  %s, %s:%d\n", strings.Join(h.Qualified, ", "), h.Reason, filepath.Base(h.File), fset.Position(h.Pos).Line)
+		}
+	}
+	fmt.Fprintln(w)
+}
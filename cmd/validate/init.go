@@ -0,0 +1,148 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/internal/initconfig"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/internal/scan"
+	"github.com/example/tfprovidertest/internal/workspace"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// runInit writes a commented settings file with the values detected in a provider repository
+// and prints the .golangci.yml block that runs the linter with it
+func runInit(args []string) {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	providerPath := fs.String("provider", ".", "Path to the Terraform provider repository")
+	force := fs.Bool("force", false, "Overwrite an existing "+config.File)
+	dryRun := fs.Bool("dry-run", false, "Print the settings file instead of writing it")
+	fs.Usage = func() {
+		fmt.Println("Usage: validate init [-provider dir] [-force] [-dry-run]")
+		fmt.Println()
+		fmt.Println("Inspects a provider repository (module path, provider type name, directory and")
+		fmt.Println("test layout, helper packages) and writes " + config.File + " with the detected")
+		fmt.Println("values and the rule toggles of the " + initconfig.Profile + " profile, then prints the")
+		fmt.Println(".golangci.yml block that runs the linter with it.")
+		fmt.Println()
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	path := filepath.Join(*providerPath, config.File)
+	if _, err := os.Stat(path); err == nil && !*force && !*dryRun {
+		fmt.Printf("Error: %s already exists; use -force to overwrite it\n", path)
+		exit(1)
+	}
+
+	detected, err := detectProvider(*providerPath)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+	content := initconfig.Render(detected)
+	if *dryRun {
+		fmt.Print(content)
+		return
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		fmt.Printf("Error: Could not write %s: %v\n", path, err)
+		exit(1)
+	}
+
+	fmt.Printf("Wrote %s: %d resource(s), %d data source(s), %d action(s), %d linked test(s)\n",
+		path, detected.Resources, detected.DataSources, detected.Actions, detected.Tests)
+	if detected.ProviderPrefix == "" {
+		fmt.Println("No provider Metadata method was found; set provider-prefix in the file.")
+	}
+	fmt.Println()
+	fmt.Println("validate reads the file from the provider directory. To run the linter with")
+	fmt.Println("golangci-lint, add this to .golangci.yml (the path is relative to where")
+	fmt.Println("golangci-lint runs) and build it with the .custom-gcl.yml shown in the README:")
+	fmt.Println()
+	fmt.Print(initconfig.GolangciConfig(config.File))
+}
+
+// detectProvider scans every Go package of a provider repository for the values validate
+// init writes
+func detectProvider(providerPath string) (initconfig.Detected, error) {
+	var d initconfig.Detected
+	if modulePath, err := workspace.ModulePath(filepath.Join(providerPath, "go.mod")); err == nil {
+		d.ModulePath = modulePath
+	}
+	if layout, ok := scan.DetectLayout(providerPath); ok {
+		d.Layout = layout.Name
+	}
+
+	settings := config.DefaultSettings()
+	fset := token.NewFileSet()
+	var files []*ast.File
+	limiter := scan.NewFileLimiter(settings.FileLimits())
+	for _, dir := range findAllGoPackageDirs(providerPath, scan.DefaultOptions()).Dirs() {
+		filter := func(info fs.FileInfo) bool {
+			return limiter.Allow(filepath.Join(dir, info.Name()), info.Size())
+		}
+		pkgs, err := parser.ParseDir(fset, dir, filter, parser.ParseComments)
+		if err != nil {
+			continue
+		}
+		for _, pkg := range pkgs {
+			for _, file := range pkg.Files {
+				files = append(files, file)
+			}
+		}
+	}
+	if len(files) == 0 {
+		return d, errors.New("no Go files found in " + providerPath)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return fset.Position(files[i].Pos()).Filename < fset.Position(files[j].Pos()).Filename
+	})
+
+	reg := buildRegistryFromFiles(fset, files, settings)
+	if provider := reg.GetProvider(); provider != nil {
+		d.ProviderPrefix = provider.TypeName
+	}
+	for _, info := range reg.GetAllDefinitions() {
+		switch info.Kind {
+		case registry.KindResource:
+			d.Resources++
+		case registry.KindDataSource:
+			d.DataSources++
+		case registry.KindAction:
+			d.Actions++
+		}
+	}
+	for _, test := range reg.GetAllTestFunctions() {
+		if test.MatchedResource != "" {
+			d.Tests++
+		}
+	}
+	if d.Tests > 0 {
+		d.TestLayout = analysis.DetectTestLayout(reg)
+	}
+
+	for _, pkg := range discovery.DetectHelperPackages(files, fset) {
+		dir := pkg.Dir
+		if rel, err := filepath.Rel(providerPath, dir); err == nil {
+			dir = rel
+		}
+		helper := initconfig.HelperPackage{Name: pkg.Name, Dir: filepath.ToSlash(dir)}
+		for _, h := range pkg.Helpers {
+			helper.Helpers = append(helper.Helpers, h.Name)
+		}
+		d.HelperPackages = append(d.HelperPackages, helper)
+	}
+	return d, nil
+}
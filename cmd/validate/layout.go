@@ -0,0 +1,284 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+)
+
+// maxBoxWidth is the width of the -report boxes when the terminal is at least as wide.
+const maxBoxWidth = 83
+
+// minColumnWidth is the narrowest a table column is truncated to when fitting the width.
+const minColumnWidth = 6
+
+// asciiReplacer maps the box-drawing characters and marks of the -report tables to ASCII
+// for -no-unicode.
+var asciiReplacer = strings.NewReplacer(
+	"─", "-", "│", "|", "┌", "+", "┐", "+", "└", "+", "┘", "+",
+	"├", "+", "┤", "+", "┬", "+", "┴", "+", "┼", "+",
+	"═", "=", "║", "|", "╔", "+", "╗", "+", "╚", "+", "╝", "+",
+	"✓", "OK", "✗", "X", "⚠", "!", "…", "...",
+)
+
+// detectWidth returns the width of the terminal the report is written to: $COLUMNS when
+// set, else the width of stdout when it is a terminal, else 0 (unlimited) for pipes and CI
+// logs.
+func detectWidth() int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return terminalWidth(os.Stdout)
+}
+
+// text converts s to ASCII with -no-unicode.
+func (v reportView) text(s string) string {
+	if v.ASCII {
+		return asciiReplacer.Replace(s)
+	}
+	return s
+}
+
+// printf prints a line of free text in a -report table section.
+func (v reportView) printf(format string, args ...interface{}) {
+	fmt.Print(v.text(fmt.Sprintf(format, args...)))
+}
+
+// boxWidth returns the width of the -report boxes: maxBoxWidth, narrowed to the terminal.
+func (v reportView) boxWidth() int {
+	if v.Width > 0 && v.Width < maxBoxWidth {
+		return max(v.Width, 20)
+	}
+	return maxBoxWidth
+}
+
+// banner prints the double-lined report title box.
+func (v reportView) banner(title string) {
+	inner := v.boxWidth() - 2
+	title = truncateText(title, inner, v.ASCII)
+	left := (inner - utf8.RuneCountInString(title)) / 2
+	fmt.Println(v.text("╔" + strings.Repeat("═", inner) + "╗"))
+	fmt.Println(v.text("║" + strings.Repeat(" ", left) + padText(title, inner-left) + "║"))
+	fmt.Println(v.text("╚" + strings.Repeat("═", inner) + "╝"))
+}
+
+// section prints the box heading a -report table.
+func (v reportView) section(title string) {
+	inner := v.boxWidth() - 2
+	fmt.Println(v.text("┌" + strings.Repeat("─", inner) + "┐"))
+	fmt.Println(v.text("│ " + padText(truncateText(title, inner-2, v.ASCII), inner-2) + " │"))
+	fmt.Println(v.text("└" + strings.Repeat("─", inner) + "┘"))
+}
+
+// grid prints a titled table with borders between its cells, such as the summary. Cells
+// between the first and last columns are right-aligned, and the last column takes the
+// width the others leave, its cells truncated to fit.
+func (v reportView) grid(title string, headers []string, rows [][]string) {
+	widths := make([]int, len(headers))
+	for i, header := range headers {
+		widths[i] = utf8.RuneCountInString(header)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+	last := len(widths) - 1
+	used := 1
+	for _, width := range widths[:last] {
+		used += width + 3
+	}
+	widths[last] = max(v.boxWidth()-used-3, minColumnWidth)
+	total := used + widths[last] + 3
+
+	rule := func(left, mid, right string) {
+		parts := make([]string, len(widths))
+		for i, width := range widths {
+			parts[i] = strings.Repeat("─", width+2)
+		}
+		fmt.Println(v.text(left + strings.Join(parts, mid) + right))
+	}
+	line := func(cells []string) {
+		parts := make([]string, len(widths))
+		for i, width := range widths {
+			cell := truncateText(cells[i], width, v.ASCII)
+			if i > 0 && i < last {
+				parts[i] = " " + strings.Repeat(" ", width-utf8.RuneCountInString(cell)) + cell + " "
+			} else {
+				parts[i] = " " + padText(cell, width) + " "
+			}
+		}
+		fmt.Println(v.text("│" + strings.Join(parts, "│") + "│"))
+	}
+
+	fmt.Println(v.text("┌" + strings.Repeat("─", total-2) + "┐"))
+	fmt.Println(v.text("│ " + padText(truncateText(title, total-4, v.ASCII), total-4) + " │"))
+	rule("├", "┬", "┤")
+	line(headers)
+	rule("├", "┼", "┤")
+	for _, row := range rows {
+		line(row)
+	}
+	rule("└", "┴", "┘")
+}
+
+// newTable returns a writer for the tab-separated header, rule and rows of a -report table.
+func (v reportView) newTable() *reportTable {
+	return &reportTable{view: v}
+}
+
+// reportTable buffers a tab-separated table until Flush, which folds the rows beyond
+// -max-rows into an "N more..." line and truncates the widest columns until the table fits
+// the terminal width. The first column, which names the row, is truncated only when no
+// other column can be.
+type reportTable struct {
+	view  reportView
+	lines []string
+	buf   strings.Builder
+}
+
+// Write buffers table text; cells are separated by tabs and lines by newlines.
+func (t *reportTable) Write(p []byte) (int, error) {
+	t.buf.Write(p)
+	text := t.buf.String()
+	for {
+		i := strings.IndexByte(text, '\n')
+		if i < 0 {
+			break
+		}
+		t.lines = append(t.lines, text[:i])
+		text = text[i+1:]
+	}
+	t.buf.Reset()
+	t.buf.WriteString(text)
+	return len(p), nil
+}
+
+// Flush writes the table to stdout.
+func (t *reportTable) Flush() {
+	if t.buf.Len() > 0 {
+		t.lines = append(t.lines, t.buf.String())
+		t.buf.Reset()
+	}
+	var rows [][]string
+	for _, line := range t.lines {
+		rows = append(rows, strings.Split(t.view.text(line), "\t"))
+	}
+	header := 0
+	if len(rows) > 1 && isRuleRow(rows[1]) {
+		header = 2
+	}
+
+	folded := 0
+	if maxRows := t.view.MaxRows; maxRows > 0 && len(rows)-header > maxRows {
+		folded = len(rows) - header - maxRows
+		rows = rows[:header+maxRows]
+	}
+
+	widths := columnWidths(rows)
+	if t.view.Width > 0 {
+		for tableWidth(widths) > t.view.Width {
+			widest := len(widths) - 1
+			for i := len(widths) - 1; i > 0; i-- {
+				if widths[i] > widths[widest] {
+					widest = i
+				}
+			}
+			if widths[widest] <= minColumnWidth {
+				// Names come first and are truncated last, and only if that makes the table fit
+				if excess := tableWidth(widths) - t.view.Width; widths[0]-excess >= minColumnWidth {
+					widths[0] -= excess
+				}
+				break
+			}
+			widths[widest]--
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, cells := range rows {
+		rule := isRuleRow(cells)
+		for i, cell := range cells {
+			if i < len(widths) && utf8.RuneCountInString(cell) > widths[i] {
+				if rule {
+					cells[i] = string([]rune(cell)[:widths[i]])
+				} else {
+					cells[i] = truncateText(cell, widths[i], t.view.ASCII)
+				}
+			}
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+	w.Flush()
+	if folded > 0 {
+		fmt.Printf("  ... %d more (raise -max-rows to show them)\n", folded)
+	}
+	t.lines = nil
+}
+
+// columnWidths returns the widest cell of each column.
+func columnWidths(rows [][]string) []int {
+	var widths []int
+	for _, cells := range rows {
+		for i, cell := range cells {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+	return widths
+}
+
+// tableWidth returns the width of a table with these column widths, including the padding
+// between columns.
+func tableWidth(widths []int) int {
+	n := 0
+	for _, width := range widths {
+		n += width + 2
+	}
+	return n - 2
+}
+
+// isRuleRow reports whether every non-empty cell of a row is a horizontal rule.
+func isRuleRow(cells []string) bool {
+	ruled := false
+	for _, cell := range cells {
+		cell = strings.TrimSpace(cell)
+		if cell == "" {
+			continue
+		}
+		if strings.Trim(cell, "─-") != "" {
+			return false
+		}
+		ruled = true
+	}
+	return ruled
+}
+
+// truncateText shortens s to width runes, ending it with an ellipsis when shortened.
+func truncateText(s string, width int, ascii bool) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	ellipsis := "…"
+	if ascii {
+		ellipsis = "..."
+	}
+	keep := width - utf8.RuneCountInString(ellipsis)
+	if keep <= 0 {
+		return string([]rune(s)[:width])
+	}
+	return string([]rune(s)[:keep]) + ellipsis
+}
+
+// padText pads s with spaces to width runes.
+func padText(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
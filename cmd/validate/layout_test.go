@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureStdout returns what fn prints to stdout, which is a pipe rather than a terminal
+// while fn runs.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	out := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		out <- string(b)
+	}()
+	fn()
+	require.NoError(t, w.Close())
+	return <-out
+}
+
+// printTable prints a table of rows, under a header and rule, the way the -report
+// sections do.
+func printTable(view reportView, rows ...string) {
+	w := view.newTable()
+	fmt.Fprintln(w, "  NAME\tTESTS\tFILE")
+	fmt.Fprintln(w, "  ────\t─────\t────")
+	for _, row := range rows {
+		fmt.Fprintln(w, "  "+row)
+	}
+	w.Flush()
+}
+
+func TestReportTableMaxRows(t *testing.T) {
+	rows := []string{"alpha\t1\tresource_alpha.go", "bravo\t2\tresource_bravo.go", "charlie\t0\tresource_charlie.go"}
+
+	t.Run("folds the rows beyond it", func(t *testing.T) {
+		out := captureStdout(t, func() { printTable(reportView{MaxRows: 2}, rows...) })
+		assert.Equal(t, strings.Join([]string{
+			"  NAME   TESTS  FILE",
+			"  ────   ─────  ────",
+			"  alpha  1      resource_alpha.go",
+			"  bravo  2      resource_bravo.go",
+			"  ... 1 more (raise -max-rows to show them)",
+			"",
+		}, "\n"), out, "the header and rule are not counted, and columns fit the rows shown")
+	})
+
+	for _, maxRows := range []int{0, 3, 4} {
+		t.Run(fmt.Sprintf("shows every row with %d", maxRows), func(t *testing.T) {
+			out := captureStdout(t, func() { printTable(reportView{MaxRows: maxRows}, rows...) })
+			assert.Contains(t, out, "  charlie  0      resource_charlie.go\n")
+			assert.NotContains(t, out, "more (raise -max-rows")
+		})
+	}
+
+	t.Run("tables without a header", func(t *testing.T) {
+		out := captureStdout(t, func() {
+			w := reportView{MaxRows: 1}.newTable()
+			fmt.Fprint(w, "a\t1\nb\t2\nc\t3")
+			w.Flush()
+		})
+		assert.Equal(t, "a  1\n  ... 2 more (raise -max-rows to show them)\n", out)
+	})
+}
+
+func TestReportTableNoUnicode(t *testing.T) {
+	view := reportView{ASCII: true, Width: 30}
+	out := captureStdout(t, func() {
+		printTable(view, "alpha\t✓ 1\tinternal/provider/resource_alpha.go")
+		view.section("RESOURCES")
+		view.banner("REPORT")
+	})
+	assert.Equal(t, strings.Join([]string{
+		"  NAME   TESTS  FILE",
+		"  ----   -----  ----",
+		"  alpha  OK 1   internal/pr...",
+		"+----------------------------+",
+		"| RESOURCES                  |",
+		"+----------------------------+",
+		"+============================+",
+		"|           REPORT           |",
+		"+============================+",
+		"",
+	}, "\n"), out, "marks and borders in ASCII, truncated with three dots")
+
+	for _, line := range strings.Split(out, "\n") {
+		for _, r := range line {
+			require.Less(t, r, rune(128), "non-ASCII in %q", line)
+		}
+	}
+
+	out = captureStdout(t, func() { printTable(reportView{Width: 30}, "alpha\t✓ 1\tinternal/provider/resource_alpha.go") })
+	assert.Contains(t, out, "  alpha  ✓ 1    internal/prov…\n", "truncated with an ellipsis otherwise")
+}
+
+func TestReportTableWidth(t *testing.T) {
+	row := "google_compute_instance_template\t12\tinternal/services/compute/resource_compute_instance_template.go"
+
+	t.Run("unlimited", func(t *testing.T) {
+		out := captureStdout(t, func() { printTable(reportView{}, row) })
+		assert.Contains(t, out, row[strings.LastIndex(row, "\t")+1:]+"\n")
+	})
+
+	t.Run("widest column is truncated first", func(t *testing.T) {
+		out := captureStdout(t, func() { printTable(reportView{Width: 60}, row) })
+		assert.Contains(t, out, "  google_compute_instance_template  12     internal/service…\n")
+		for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+			assert.LessOrEqual(t, len([]rune(line)), 60, "%q", line)
+		}
+	})
+
+	t.Run("names are truncated last", func(t *testing.T) {
+		out := captureStdout(t, func() { printTable(reportView{Width: 30}, row) })
+		assert.Contains(t, out, "  google_compu…  12     inter…\n")
+	})
+
+	t.Run("names are kept when truncating them would not fit", func(t *testing.T) {
+		out := captureStdout(t, func() { printTable(reportView{Width: 10}, row) })
+		assert.Contains(t, out, "  google_compute_instance_template  12     inter…\n")
+	})
+}
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		ascii bool
+		want  string
+	}{
+		{s: "resource", width: 8, want: "resource"},
+		{s: "resource", width: 7, want: "resour…"},
+		{s: "resource", width: 7, ascii: true, want: "reso..."},
+		{s: "✓✓✓✓", width: 3, want: "✓✓…"},
+		{s: "resource", width: 2, ascii: true, want: "re"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, truncateText(tt.s, tt.width, tt.ascii), "%q to %d (ascii %t)", tt.s, tt.width, tt.ascii)
+	}
+}
+
+func TestDetectWidth(t *testing.T) {
+	t.Run("COLUMNS", func(t *testing.T) {
+		t.Setenv("COLUMNS", "72")
+		assert.Equal(t, 72, detectWidth())
+	})
+
+	for _, columns := range []string{"", "0", "-5", "wide"} {
+		t.Run(fmt.Sprintf("falls back to stdout with COLUMNS=%q", columns), func(t *testing.T) {
+			t.Setenv("COLUMNS", columns)
+			var width int
+			captureStdout(t, func() { width = detectWidth() })
+			assert.Zero(t, width, "unlimited when stdout is a pipe")
+		})
+	}
+
+	t.Run("a file is not a terminal", func(t *testing.T) {
+		f, err := os.Create(filepath.Join(t.TempDir(), "report.txt"))
+		require.NoError(t, err)
+		defer f.Close()
+		assert.Zero(t, terminalWidth(f))
+	})
+
+	t.Run("box width", func(t *testing.T) {
+		assert.Equal(t, maxBoxWidth, reportView{}.boxWidth(), "unlimited")
+		assert.Equal(t, maxBoxWidth, reportView{Width: 200}.boxWidth())
+		assert.Equal(t, 60, reportView{Width: 60}.boxWidth())
+		assert.Equal(t, 20, reportView{Width: 8}.boxWidth())
+	})
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// outputCheckMigrationTable prints the definitions whose tests still assert state with the
+// legacy Check field, with how many of their steps set Check and ConfigStateChecks, to track
+// the migration to state checks.
+func outputCheckMigrationTable(reg *registry.ResourceRegistry, defs []*registry.ResourceInfo, view reportView) {
+	type row struct {
+		info      *registry.ResourceInfo
+		migration analysis.CheckMigration
+	}
+	var rows []row
+	var total analysis.CheckMigration
+	for _, info := range defs {
+		migration := analysis.CheckMigrationOf(reg.GetTests(info.Kind, info.Name))
+		total.LegacySteps += migration.LegacySteps
+		total.StateCheckSteps += migration.StateCheckSteps
+		if migration.LegacySteps > 0 {
+			rows = append(rows, row{info, migration})
+		}
+	}
+	if len(rows) == 0 {
+		return
+	}
+
+	fmt.Println()
+	view.section("STATE CHECK MIGRATION")
+	w := view.newTable()
+	fmt.Fprintln(w, "  DEFINITION\tKIND\tCHECK STEPS\tSTATE CHECK STEPS")
+	fmt.Fprintln(w, "  ──────────\t────\t───────────\t─────────────────")
+	for _, r := range rows {
+		fmt.Fprintf(w, "  %s\t%s\t%d\t%d\n", r.info.Name, r.info.Kind, r.migration.LegacySteps, r.migration.StateCheckSteps)
+	}
+	w.Flush()
+	fmt.Printf("  %d step(s) of %d definition(s) still set Check; %d step(s) set ConfigStateChecks\n",
+		total.LegacySteps, len(rows), total.StateCheckSteps)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"go/token"
+
+	"github.com/example/tfprovidertest/internal/links"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// newLinker returns the Linker of the repo-url-template setting, with paths relative to the
+// root of the git work tree containing the provider
+func newLinker(settings config.Settings, providerPath string, fset *token.FileSet) links.Linker {
+	if settings.RepoURLTemplate == "" {
+		return links.Linker{}
+	}
+	return links.New(settings.RepoURLTemplate, links.RepoRoot(context.Background(), providerPath), fset)
+}
+
+// linkReport adds the links of a definition's report and of its tests, which are in the
+// order the registry returns them
+func linkReport(report *ResourceReport, reg *registry.ResourceRegistry, info *registry.ResourceInfo, link links.Linker) {
+	if !link.Enabled() {
+		return
+	}
+	report.URL = definitionURL(info, link)
+	linkTestReports(report.Tests, reg.GetTests(info.Kind, info.Name), link)
+	linkTestReports(report.QuarantinedTests, reg.GetQuarantinedTests(info.Kind, info.Name), link)
+}
+
+func linkTestReports(reports []TestReport, tests []*registry.TestFunctionInfo, link links.Linker) {
+	for i := range reports {
+		if i < len(tests) {
+			reports[i].URL = testURL(tests[i], link)
+		}
+	}
+}
+
+// definitionURL links to a definition's schema, or to its file when the schema position is
+// unknown
+func definitionURL(info *registry.ResourceInfo, link links.Linker) string {
+	if url := link.Pos(info.SchemaPos); url != "" {
+		return url
+	}
+	return link.URL(info.FilePath, 0)
+}
+
+// testURL links to a test function, or to its file when the position is unknown
+func testURL(fn *registry.TestFunctionInfo, link links.Linker) string {
+	if url := link.Pos(fn.FunctionPos); url != "" {
+		return url
+	}
+	return link.URL(fn.FilePath, 0)
+}
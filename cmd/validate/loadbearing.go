@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// outputLoadBearingTable prints the tests that alone cover a check of their definition, such
+// as its only test with an import step, which reviewers should know before deleting or
+// skipping them. A definition's only test covers all of its checks and is listed too.
+func outputLoadBearingTable(reg *registry.ResourceRegistry, defs []*registry.ResourceInfo, view reportView) {
+	type row struct {
+		info   *registry.ResourceInfo
+		test   string
+		unique []string
+	}
+	var rows []row
+	for _, info := range defs {
+		tests := reg.GetTests(info.Kind, info.Name)
+		for _, t := range tests {
+			if unique := registry.UniqueChecks(info.Kind, tests, t); len(unique) > 0 {
+				rows = append(rows, row{info, t.Name, unique})
+			}
+		}
+	}
+	if len(rows) == 0 {
+		return
+	}
+
+	fmt.Println()
+	view.section("LOAD-BEARING TESTS")
+	w := view.newTable()
+	fmt.Fprintln(w, "  DEFINITION\tKIND\tTEST FUNCTION\tONLY TEST COVERING")
+	fmt.Fprintln(w, "  ──────────\t────\t─────────────\t──────────────────")
+	for _, r := range rows {
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", r.info.Name, r.info.Kind, r.test, strings.Join(r.unique, ", "))
+	}
+	w.Flush()
+}
@@ -2,48 +2,169 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"io"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"runtime"
 	"sort"
+	"strconv"
 	"strings"
-	"text/tabwriter"
+	"time"
 
-	"github.com/example/tfprovidertest"
+	goanalysis "golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/anonymize"
+	"github.com/example/tfprovidertest/internal/artifact"
 	"github.com/example/tfprovidertest/internal/discovery"
-	"github.com/example/tfprovidertest/internal/matching"
+	"github.com/example/tfprovidertest/internal/enforcement"
+	"github.com/example/tfprovidertest/internal/fileroles"
+	"github.com/example/tfprovidertest/internal/freeze"
+	"github.com/example/tfprovidertest/internal/gitmeta"
+	"github.com/example/tfprovidertest/internal/links"
+	"github.com/example/tfprovidertest/internal/livediscovery"
+	"github.com/example/tfprovidertest/internal/messages"
+	"github.com/example/tfprovidertest/internal/query"
 	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/internal/rules"
+	"github.com/example/tfprovidertest/internal/rulesdoc"
+	"github.com/example/tfprovidertest/internal/scan"
+	"github.com/example/tfprovidertest/internal/sinks"
+	"github.com/example/tfprovidertest/internal/workspace"
 	"github.com/example/tfprovidertest/pkg/config"
-	"golang.org/x/tools/go/analysis"
 )
 
-// MatchInfo represents a resource-test association for diagnostic output
-type MatchInfo struct {
-	ResourceName string  `json:"resource_name"`
-	TestFunction string  `json:"test_function"`
-	TestFile     string  `json:"test_file"`
-	Confidence   float64 `json:"confidence"`
-	MatchType    string  `json:"match_type"`
-}
-
 func main() {
+	// "validate metrics <file.json>..." aggregates exported JSON and scans nothing
+	if len(os.Args) > 1 && os.Args[1] == "metrics" {
+		runMetrics(os.Args[2:])
+		return
+	}
+
+	// "validate org-report -inputs <file.json>,..." merges exported reports of many providers
+	if len(os.Args) > 1 && os.Args[1] == "org-report" {
+		runOrgReport(os.Args[2:])
+		return
+	}
+
+	// "validate init [options]" writes a settings file for a provider and scans nothing else
+	if len(os.Args) > 1 && os.Args[1] == "init" {
+		runInit(os.Args[2:])
+		return
+	}
+
+	// "validate show <name> [options]" prints the detail page of one definition; the name
+	// may also follow the options
+	var showName string
+	if len(os.Args) > 2 && os.Args[1] == "show" {
+		showName = os.Args[2]
+		os.Args = append(os.Args[:1:1], os.Args[3:]...)
+	}
+	// "validate generate [options]" writes stub tests, typically from a //go:generate directive
+	generating := len(os.Args) > 1 && os.Args[1] == "generate"
+	if generating {
+		os.Args = append(os.Args[:1:1], os.Args[2:]...)
+	}
+
 	// Basic flags
 	providerPath := flag.String("provider", "", "Path to the Terraform provider directory")
 	verbose := flag.Bool("verbose", false, "Enable verbose output")
 	recursive := flag.Bool("recursive", false, "Recursively scan all subdirectories for Go packages")
 	scanPath := flag.String("scan-path", "", "Explicit path within provider to scan (overrides auto-detection)")
 
+	// Recursive scan flags
+	followSymlinks := flag.Bool("follow-symlinks", false, "Follow symlinked directories during -recursive scans (cycles are detected)")
+	respectGitignore := flag.Bool("respect-gitignore", true, "Skip files and directories ignored by .gitignore during -recursive scans")
+	layout := flag.String("layout", config.LayoutAuto, "Directory layout for auto-detection: auto, none, or a generated layout ("+strings.Join(scan.LayoutNames(), ", ")+")")
+	resourceDirGlobs := flag.String("resource-dir-globs", "", "Comma-separated globs, relative to the provider, of directories holding resource code (e.g., internal/aws/**)")
+	excludeDirs := flag.String("exclude-dirs", "", "Comma-separated directories to skip during -recursive scans (e.g., tools,examples)")
+	scanManifest := flag.String("scan-manifest", "", "Write a JSON manifest of included/excluded directories to this file ('-' for stderr)")
+
 	// Diagnostic flags
 	showMatches := flag.Bool("show-matches", false, "Show all resource -> test function associations")
 	showUnmatched := flag.Bool("show-unmatched", false, "Show test functions without resource association")
 	showOrphaned := flag.Bool("show-orphaned", false, "Show resources without any test coverage")
+	showWeak := flag.Bool("show-weak", false, "Show resources with tests that apply configs without Check, ConfigStateChecks, ConfigPlanChecks or ExpectError")
+	showCandidates := flag.Bool("show-candidates", false, "Show tests with candidate resource links the linker did not accept")
 	showReport := flag.Bool("report", false, "Show comprehensive coverage report with table views")
-	outputFormat := flag.String("format", "text", "Output format: text, json, or table")
+	explain := flag.String("explain", "", "Explain how a resource was discovered and why tests were or were not linked to it")
+	reproName := flag.String("repro", "", "Write a zip reproducer for a resource: its source and candidate test files with HCL values scrubbed, settings and the decision log")
+	reproOutput := flag.String("repro-output", "", "Path of the -repro zip (default: tfprovidertest-repro-<resource>.zip)")
+	syncManifest := flag.Bool("sync-manifest", false, "Add entries for unlisted definitions to the coverage requirements manifest")
+	requirementsManifest := flag.String("requirements-manifest", "", "Path of the coverage requirements manifest ('off' to disable; default: tfprovidertest.requirements.yaml found upward from the scanned code)")
+	verifyTestList := flag.Bool("verify-test-list", false, "Check that every discovered test function is listed by go test -list")
+	testTags := flag.String("tags", "", "Comma-separated build tags for -verify-test-list")
+	showHelpers := flag.Bool("show-helpers", false, "Show local test helpers with their usage and referenced resources")
+	showConfigs := flag.Bool("configs", false, "List the most reused test configs and those used by a single step")
+	showEnvVars := flag.Bool("env-vars", false, "List the environment variables test configs, PreChecks and the provider depend on")
+	planTo := flag.String("plan-to", "", "Plan the untested definitions to cover to reach this coverage (e.g., 80%)")
+	planWeights := flag.String("plan-weights", "", "With -plan-to, priority weights as name=value: recent, attributes, tag (default 1 each)")
+	planTag := flag.String("plan-tag", registry.MaturityGA, "With -plan-to, the maturity level that marks customer-facing definitions")
+	coverProfile := flag.String("coverprofile", "", "Correlate a go test -coverprofile profile with the CRUD methods of tested definitions")
+	augmentTests := flag.Bool("augment-tests", false, "Suggest ConfigStateChecks for test steps that apply a config without checking state")
+	augmentPatch := flag.String("augment-patch", "", "With -augment-tests, write the suggestions as a unified diff to this file ('-' for stdout)")
+	rerunFailed := flag.Bool("rerun-failed", false, "Run only the analyzers that reported findings in the previous run, and report only the files they reported them in")
+	runStateFlag := flag.String("run-state", "", "Path of the run state -rerun-failed reads ('off' to not record runs; default: a file in the user cache directory)")
+	applyFix := flag.Bool("fix", false, "Apply the findings' automatic fixes, such as import step scaffolds, to the source files")
+	maxFiles := flag.Int("max-files", 0, "Skip and report Go files past this many (0 for no cap)")
+	maxFileSizeKB := flag.Int("max-file-size-kb", config.DefaultMaxFileSizeKB, "Skip and report Go files larger than this many kilobytes (negative for no limit)")
+	force := flag.Bool("force", false, "Analyze every file, ignoring -max-files and -max-file-size-kb")
+	annotateDefs := flag.Bool("annotate", false, "Write or refresh a test coverage line in the doc comment of each definition's type")
+	annotateDryRun := flag.Bool("annotate-dry-run", false, "List the coverage lines -annotate would add or update without writing them")
+	generateResource := flag.String("resource", "all", "With generate, the definitions to write stub tests for: current-file ($GOFILE), all, or comma-separated names")
+	dryRun := flag.Bool("dry-run", false, "With generate, list the stub tests that would be written without writing them")
+	gitMetadata := flag.Bool("git-metadata", false, "With -report, group coverage by each definition file's last commit time")
+	anonymizeReport := flag.Bool("anonymize", false, "With -report, hash definition, test and file names so the report can be shared externally")
+	redactConfigs := flag.Bool("redact-configs", false, "Show digests instead of test config text and the values taken from it, in every output")
+	anonymizeSalt := flag.String("anonymize-salt", os.Getenv("TFPROVIDERTEST_ANONYMIZE_SALT"), "With -anonymize, the secret that keys the hashes; the same salt gives the same names on every run")
+	reportColumns := flag.String("columns", "", "With -report, extra RESOURCES table columns: confidence, match-type, complexity, category (comma-separated)")
+	groupBy := flag.String("group-by", "", "With -report, break coverage down by category")
+	categoryFilter := flag.String("category", "", "With -report, only report definitions in these categories (comma-separated, e.g., Networking,Compute)")
+	reportSort := flag.String("sort", "name", "With -report, order tables by name, confidence (weakest links first) or complexity (most complex first)")
+	tableWidth := flag.Int("width", -1, "With -report, fit tables to this many columns (default: $COLUMNS or the terminal width; 0 for unlimited)")
+	noUnicode := flag.Bool("no-unicode", false, "With -report, draw tables with ASCII characters only")
+	maxRows := flag.Int("max-rows", 0, "With -report, show at most this many rows per table and fold the rest into \"N more...\" (0 for all)")
+	outputFormat := flag.String("format", "text", "Output format: text, json, ndjson, table, codeclimate, or csv (with -report)")
+	repoURLTemplate := flag.String("repo-url-template", "", "Link report entries to the code, e.g. https://github.com/org/repo/blob/main/{path}#L{line}")
+	freezeMode := flag.String("freeze", "", "Write the covered checks of each definition to the freeze file (write), or fail when one is lost (check)")
+	freezeFile := flag.String("freeze-file", "", "Path of the coverage freeze file (default: "+freeze.File+" in the provider directory)")
+	failUnder := flag.String("fail-under", "", "Exit with status 1 when fewer than this share of definitions are tested (e.g., 70%)")
+	warnUnder := flag.String("warn-under", "", "Print a prominent warning, but exit 0, when fewer than this share of definitions are tested (e.g., 80%)")
+	gateOutput := flag.String("gate-output", "", "Append the coverage gate result as key=value lines to this file (e.g., $GITHUB_OUTPUT)")
+	workers := flag.Int("workers", runtime.GOMAXPROCS(0), "Number of analyzers to run at once (1 runs them one after another)")
+	heatmapPath := flag.String("heatmap", "", "Write a treemap-friendly CSV of per-file coverage to this file ('-' for stdout)")
+	routingPath := flag.String("routing", "", "Write a routing file for issue-filing bots (owners, labels, dedup keys) to this file, e.g. routing.json ('-' for stdout)")
+	prSuggestionsPath := flag.String("pr-suggestions", "", "With -since, write labels, a checklist and a test command for the definitions the change touches to this file, for PR bots ('-' for stdout)")
+	summaryPath := flag.String("summary-file", "", "Write a run summary artifact with provenance (tool version, analyzed commit, settings fingerprint, timing, totals, top findings) to this file, e.g. "+artifact.DefaultFile)
+	var outputs, outputHeaders outputList
+	flag.Var(&outputs, "output", "Send the output to this destination: stdout, file=<path> or http=<url> (repeatable)")
+	flag.Var(&outputHeaders, "output-header", "With -output http=<url>, add this \"Name: value\" header to the request, e.g. \"Authorization: Bearer $TOKEN\" (repeatable)")
+	outputRetries := flag.Int("output-retries", sinks.DefaultRetries, "With -output http=<url>, retry a failed request this many times")
+	queryExpr := flag.String("query", "", "Print the results of this jq-style expression over the JSON output instead, e.g. '.summary.untested_resources' with -report")
+	language := flag.String("language", "en", "Language of diagnostic messages: en or ja")
+	messageStyle := flag.String("message-style", config.MessageStyleLong, "Diagnostic message style: long (multi-line with suggestions) or short (one line ending in the rule code)")
+
+	// Rule selection flags
+	configPath := flag.String("config", "", "Settings file, with the setting names of the settings reference ('off' to disable; default: "+config.File+" in the provider directory)")
+	profile := flag.String("profile", "", "Rule profile: minimal, recommended, or strict (default: the built-in defaults)")
+	var analyzerNames analyzerList
+	enforcePaths := flag.String("enforce-paths", "", "Comma-separated globs of files whose findings are errors; findings elsewhere are warnings (e.g., internal/service/s3/**)")
+	warnOnlyPaths := flag.String("warn-only-paths", "", "Comma-separated globs of files whose findings are only warnings")
+	resourcePatterns := flag.String("resources", "", "Comma-separated globs of definition names to analyze (e.g., 'aws_s3_*'); other definitions and their tests are left out, including from the exit code")
+	ignoreDirConfigs := flag.Bool("ignore-dir-configs", false, "Ignore the tfprovidertest.dir.yaml files that override levels and rules for their subtree")
+	ignoreHelperPackages := flag.Bool("ignore-helper-packages", false, "Do not detect scanned packages whose exported functions build or run test cases as test helpers")
+	since := flag.String("since", "", "Git ref the change under review is compared with (e.g., origin/main), for the upgrade-test and legacy-checks rules")
+	listRules := flag.Bool("list-rules", false, "List every rule with its code, group, default and enabling setting, and exit")
+	flag.Var(&analyzerNames, "analyzer", "Run only this analyzer, even if disabled by default (repeatable, e.g., tfprovider-coverage-import-test)")
 
 	// Strategy flags
 	matchStrategy := flag.String("match-strategy", "all", "Matching strategy: function, file, fuzzy, or all")
@@ -53,61 +174,203 @@ func main() {
 	providerPrefix := flag.String("provider-prefix", "", "Provider prefix for function name matching (e.g., AWS, Google)")
 
 	flag.Parse()
+	var q *query.Query
+	if *queryExpr != "" {
+		format, err := queryFormat(*outputFormat)
+		if err == nil {
+			q, err = query.Parse(*queryExpr)
+		}
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		*outputFormat = format
+	}
+	if err := startOutputs(outputs, outputHeaders, *outputRetries, *outputFormat); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+	if err := startQuery(q); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+	defer func() {
+		queryOK := finishQuery()
+		if !closeOutputs() || !queryOK {
+			os.Exit(1)
+		}
+	}()
+	if showName == "" && flag.NArg() == 2 && flag.Arg(0) == "show" {
+		showName = flag.Arg(1)
+	}
+
+	if *listRules {
+		if err := rulesdoc.List(os.Stdout, rulesdoc.Pages(nil)); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(1)
+		}
+		return
+	}
 
+	if generating && *providerPath == "" {
+		// Under go:generate the working directory is the package being generated
+		*providerPath, *scanPath = ".", "."
+	}
 	if *providerPath == "" {
 		printUsage()
-		os.Exit(1)
+		exit(1)
+	}
+	fileSettings, settingsFile, err := loadSettingsFile(*configPath, *providerPath, *profile)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+	selectedAnalyzers, err := resolveAnalyzers(analyzerNames)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+	statePath := runStatePath(*runStateFlag, *providerPath)
+	var rerun *runState
+	if *rerunFailed {
+		if statePath == "" {
+			fmt.Println("Error: -rerun-failed needs the run state, which -run-state off disables")
+			exit(1)
+		}
+		if rerun, err = loadRunState(statePath); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(1)
+		}
+		if selectedAnalyzers = rerun.restrict(selectedAnalyzers); len(selectedAnalyzers) == 0 {
+			fmt.Println("No analyzer reported findings in the previous run.")
+			return
+		}
 	}
 
 	// Determine directories to scan
 	var scanDirs []string
+	layoutNote := ""
 
 	if *scanPath != "" {
 		// Explicit scan path provided
 		fullPath := filepath.Join(*providerPath, *scanPath)
 		if stat, err := os.Stat(fullPath); err != nil || !stat.IsDir() {
 			fmt.Printf("Error: Specified scan path does not exist: %s\n", fullPath)
-			os.Exit(1)
+			exit(1)
 		}
 		scanDirs = []string{fullPath}
 	} else if *recursive {
 		// Recursive scanning - find all directories with Go files
-		scanDirs = findAllGoPackageDirs(*providerPath)
+		opts := scan.DefaultOptions()
+		opts.FollowSymlinks = *followSymlinks
+		opts.RespectGitignore = *respectGitignore
+		opts.ExtraExcludes = splitList(*excludeDirs)
+		manifest := findAllGoPackageDirs(*providerPath, opts)
+		if *scanManifest != "" {
+			if err := writeScanManifest(manifest, *scanManifest); err != nil {
+				fmt.Printf("Error: Could not write scan manifest: %v\n", err)
+				exit(1)
+			}
+		}
+		scanDirs = manifest.Dirs()
 		if len(scanDirs) == 0 {
 			fmt.Printf("Error: No Go packages found in %s (recursive scan)\n", *providerPath)
-			os.Exit(1)
+			exit(1)
 		}
 	} else {
-		// Standard auto-detection
-		providerCodeDir := findProviderCodeDir(*providerPath)
-		if providerCodeDir == "" {
+		// Standard auto-detection: resource directory globs or a generated layout, else the
+		// conventional provider code directory
+		opts := scan.DefaultOptions()
+		opts.RespectGitignore = *respectGitignore
+		opts.ExtraExcludes = splitList(*excludeDirs)
+		dirs, source, err := findLayoutDirs(*providerPath, *layout, splitList(*resourceDirGlobs), opts)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(1)
+		}
+		if len(dirs) > 0 {
+			layoutNote = source
+			scanDirs = dirs
+		} else if providerCodeDir := findProviderCodeDir(*providerPath); providerCodeDir != "" {
+			scanDirs = []string{providerCodeDir}
+		} else {
 			fmt.Printf("Error: Could not find provider code directory in %s\n", *providerPath)
 			fmt.Println("\nTried the following locations:")
 			fmt.Println("  - internal/provider")
 			fmt.Println("  - internal")
 			fmt.Println("  - <provider-name> (extracted from directory name)")
+			fmt.Printf("  - generated layouts: %s\n", strings.Join(scan.LayoutNames(), ", "))
 			fmt.Println("\nTip: Use -recursive flag to scan all subdirectories")
 			fmt.Println("     Use -scan-path to specify an explicit path")
-			os.Exit(1)
+			fmt.Println("     Use -resource-dir-globs to list the directories of generated code")
+			exit(1)
 		}
-		scanDirs = []string{providerCodeDir}
 	}
 
-	// Display what we're scanning
-	if len(scanDirs) == 1 {
-		fmt.Printf("Analyzing provider at: %s\n\n", scanDirs[0])
-	} else {
-		fmt.Printf("Analyzing provider at: %s (%d directories)\n\n", *providerPath, len(scanDirs))
+	// Display what we're scanning (kept off stdout for JSON and patches so output stays parseable)
+	if *outputFormat != "json" && *outputFormat != "ndjson" && *outputFormat != "codeclimate" && *outputFormat != "csv" && *augmentPatch != "-" && !generating && !*anonymizeReport {
+		if settingsFile != "" {
+			fmt.Printf("Using settings from %s\n", settingsFile)
+		}
+		if rerun != nil {
+			fmt.Printf("Re-running %d analyzer(s) that reported findings in the previous run\n", len(selectedAnalyzers))
+		}
+		if layoutNote != "" {
+			fmt.Printf("Using %s\n", layoutNote)
+		}
+		if len(scanDirs) == 1 {
+			fmt.Printf("Analyzing provider at: %s\n\n", scanDirs[0])
+		} else {
+			fmt.Printf("Analyzing provider at: %s (%d directories)\n\n", *providerPath, len(scanDirs))
+		}
 	}
 
-	// Build settings from flags
-	settings := config.DefaultSettings()
+	// Build settings from flags, starting from the settings file or else the selected profile
+	settings, err := config.ProfileSettings(*profile)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+	if fileSettings != nil {
+		settings = *fileSettings
+	}
 	settings.Verbose = *verbose
 	settings.ShowMatchConfidence = *showMatches
 	settings.ShowUnmatchedTests = *showUnmatched
 	settings.ShowOrphanedResources = *showOrphaned
 	settings.FuzzyMatchThreshold = *confidenceThreshold
 	settings.ProviderPrefix = *providerPrefix
+	settings.Language = *language
+	settings.MessageStyle = *messageStyle
+	settings.RedactConfigs = *redactConfigs
+	settings.Layout = *layout
+	settings.ResourceDirGlobs = splitList(*resourceDirGlobs)
+	settings.MaxFiles, settings.MaxFileSizeKB = *maxFiles, *maxFileSizeKB
+	if *force {
+		settings.MaxFiles, settings.MaxFileSizeKB = 0, -1
+	}
+	settings.RequirementsManifest = *requirementsManifest
+	settings.EnforcePaths = splitList(*enforcePaths)
+	settings.WarnOnlyPaths = splitList(*warnOnlyPaths)
+	if *resourcePatterns != "" {
+		settings.Resources = splitList(*resourcePatterns)
+		for _, pattern := range settings.Resources {
+			if _, err := path.Match(pattern, ""); err != nil {
+				fmt.Printf("Error: -resources: invalid pattern %q: %v\n", pattern, err)
+				exit(1)
+			}
+		}
+	}
+	settings.IgnoreDirConfigs = *ignoreDirConfigs
+	settings.IgnoreHelperPackages = *ignoreHelperPackages
+	settings.Since = *since
+	if *prSuggestionsPath != "" && *since == "" {
+		fmt.Println("Error: -pr-suggestions needs -since, the git ref the change is compared with")
+		exit(1)
+	}
+	if *repoURLTemplate != "" {
+		settings.RepoURLTemplate = *repoURLTemplate
+	}
 
 	// Configure matching strategy
 	// Note: Function name matching and file-based matching always run (not configurable)
@@ -120,59 +383,214 @@ func main() {
 		settings.EnableFuzzyMatching = true
 	default:
 		fmt.Printf("Error: Invalid match-strategy '%s'. Must be one of: function, file, fuzzy, all\n", *matchStrategy)
-		os.Exit(1)
+		exit(1)
 	}
 
 	// Validate settings
 	if err := validateSettings(settings); err != nil {
 		fmt.Printf("Error: Invalid settings: %v\n", err)
-		os.Exit(1)
+		exit(1)
+	}
+	view, err := parseReportView(splitList(*reportColumns), *reportSort)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+	view.Width, view.ASCII, view.MaxRows = *tableWidth, *noUnicode, *maxRows
+	view.GroupBy, view.Categories, err = parseCategoryOptions(*groupBy, splitList(*categoryFilter))
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+	if view.Width < 0 {
+		view.Width = detectWidth()
+	}
+	gate, err := parseCoverageGate(*failUnder, *warnUnder, *gateOutput)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+	anonymizer, err := newAnonymizer(*anonymizeReport, *anonymizeSalt, *showReport, *gitMetadata, settings.RepoURLTemplate)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
 	}
 
-	// Parse all Go files from all scan directories
+	// Parse all Go files from all scan directories, skipping those over the file limits
+	// before they are read
 	fset := token.NewFileSet()
 	var allFiles []*ast.File
+	limiter := scan.NewFileLimiter(settings.FileLimits())
+	recorder := newHealthRecorder()
 
 	for _, dir := range scanDirs {
-		pkgs, err := parser.ParseDir(fset, dir, nil, parser.ParseComments)
+		filter := func(info fs.FileInfo) bool {
+			return limiter.Allow(filepath.Join(dir, info.Name()), info.Size())
+		}
+		pkgs, err := parser.ParseDir(fset, dir, filter, parser.ParseComments)
 		if err != nil {
 			if *verbose {
 				fmt.Printf("Warning: Error parsing %s: %v\n", dir, err)
 			}
+			recorder.skip(dir, reasonParseError, err.Error())
 			continue
 		}
 
+		// Parse results are maps; order files by name so output is the same on every run
+		var dirFiles []*ast.File
 		for _, pkg := range pkgs {
 			for _, file := range pkg.Files {
-				allFiles = append(allFiles, file)
+				dirFiles = append(dirFiles, file)
 			}
 		}
+		sort.Slice(dirFiles, func(i, j int) bool {
+			return fset.Position(dirFiles[i].Pos()).Filename < fset.Position(dirFiles[j].Pos()).Filename
+		})
+		allFiles = append(allFiles, dirFiles...)
 	}
 
+	printSkippedFiles(limiter.Skipped())
+	recorder.skipLimited(limiter.Skipped())
+	recorder.parsed = len(allFiles)
+	recorder.lap(phaseParse)
+
 	if len(allFiles) == 0 {
 		fmt.Printf("Error: No Go files found in scanned directories\n")
-		os.Exit(1)
+		exit(1)
+	}
+
+	// Resolve helper packages from sibling modules when the provider uses a Go workspace
+	ws, err := workspace.Find(*providerPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not load go.work: %v\n", err)
+	}
+	if ws != nil {
+		helperPackages := resolveWorkspaceHelpers(ws, fset, allFiles, scanDirs)
+		for _, pkg := range helperPackages {
+			settings.CustomTestHelpers = append(settings.CustomTestHelpers, pkg.Helpers...)
+		}
+		if *verbose {
+			printWorkspace(os.Stderr, ws, helperPackages)
+		}
+	}
+	if *verbose && !settings.IgnoreHelperPackages {
+		printHelperPackages(os.Stderr, fset, discovery.DetectHelperPackages(allFiles, fset))
 	}
 
 	// Handle report command - comprehensive coverage report
 	if *showReport {
-		runReport(fset, allFiles, settings, *outputFormat)
+		runReport(fset, allFiles, settings, *outputFormat, *providerPath, *gitMetadata, view, anonymizer, recorder)
+		return
+	}
+
+	// Handle manifest sync - scaffold requirements for new definitions
+	if *syncManifest {
+		runSyncManifest(fset, allFiles, settings, *outputFormat, *providerPath)
+		return
+	}
+
+	// Handle explain command - decision trail for one resource
+	if *explain != "" {
+		runExplain(fset, allFiles, settings, *outputFormat, *explain)
+		return
+	}
+
+	// Handle repro command - reproducer bundle for a bug report
+	if *reproName != "" {
+		runRepro(fset, allFiles, settings, *reproName, *reproOutput, *providerPath)
+		return
+	}
+
+	// Handle generate command - stub tests for untested definitions
+	if generating {
+		runGenerate(fset, allFiles, settings, *outputFormat, *generateResource, *dryRun)
+		return
+	}
+
+	// Handle show command - detail page for one resource
+	if showName != "" {
+		runShow(fset, allFiles, settings, *outputFormat, showName, *workers, *providerPath)
+		return
+	}
+
+	// Handle toolchain cross-check of discovered tests
+	if *verifyTestList {
+		runVerifyTestList(fset, allFiles, settings, *outputFormat, splitList(*testTags))
+		return
+	}
+
+	// Handle helper catalogue
+	if *showHelpers {
+		runHelperCatalogue(fset, allFiles, settings, *outputFormat)
+		return
+	}
+
+	// Handle config corpus
+	if *showConfigs {
+		runConfigCorpus(fset, allFiles, settings, *outputFormat)
+		return
+	}
+
+	// Handle match candidates
+	if *showCandidates {
+		runShowCandidates(fset, allFiles, settings, *outputFormat)
+		return
+	}
+
+	// Handle environment variable inventory
+	if *showEnvVars {
+		runEnvVarInventory(fset, allFiles, settings, *outputFormat)
+		return
+	}
+
+	// Handle the coverage freeze
+	if *freezeMode != "" {
+		runFreeze(fset, allFiles, settings, *outputFormat, *freezeMode, *freezeFile, *providerPath)
+		return
+	}
+
+	// Handle coverage goal planning
+	if *planTo != "" {
+		runPlan(fset, allFiles, settings, *outputFormat, *planTo, *planWeights, *planTag)
+		return
+	}
+
+	// Handle run-time coverage correlation
+	if *coverProfile != "" {
+		runCoverProfile(fset, allFiles, settings, *outputFormat, *coverProfile)
+		return
+	}
+
+	// Handle state check suggestions for weak tests
+	if *augmentTests || *augmentPatch != "" {
+		runAugmentTests(fset, allFiles, settings, *outputFormat, *providerPath, *augmentPatch)
+		return
+	}
+
+	// Handle coverage annotations in doc comments
+	if *annotateDefs || *annotateDryRun {
+		runAnnotate(fset, allFiles, settings, *outputFormat, *annotateDryRun)
 		return
 	}
 
 	// Handle diagnostic commands
-	if *showMatches || *showUnmatched || *showOrphaned {
-		runDiagnostics(fset, allFiles, settings, *outputFormat, *showMatches, *showUnmatched, *showOrphaned)
+	if *showMatches || *showUnmatched || *showOrphaned || *showWeak {
+		runDiagnostics(fset, allFiles, settings, *outputFormat, *showMatches, *showUnmatched, *showOrphaned, *showWeak)
 		return
 	}
 
 	// Run standard analysis
-	runAnalyzers(fset, allFiles, settings)
+	runAnalyzers(fset, allFiles, settings, *outputFormat, *providerPath, *heatmapPath, *routingPath, *prSuggestionsPath, *summaryPath, selectedAnalyzers, gate, *workers, *applyFix, recorder, statePath, rerun)
 }
 
 // printUsage outputs comprehensive help text for the validate command
 func printUsage() {
 	fmt.Println("Usage: validate -provider <path> [options]")
+	fmt.Println("       validate init [-provider <path>] [-force] [-dry-run]")
+	fmt.Println("       validate show <name> -provider <path> [options]")
+	fmt.Println("       validate generate [-resource current-file|all|<names>] [options]")
+	fmt.Println("       validate metrics [-bucket day|week|month] [-format text|json] <file.json>...")
+	fmt.Println("       validate org-report -inputs <[name=]file.json>,... [-top n] [-format text|json]")
 	fmt.Println()
 	fmt.Println("tfprovidertest validates Terraform provider test coverage by analyzing")
 	fmt.Println("resource definitions and their corresponding acceptance tests.")
@@ -181,17 +599,203 @@ func printUsage() {
 	fmt.Println("  -provider string")
 	fmt.Println("        Path to the Terraform provider directory (required)")
 	fmt.Println("  -verbose")
-	fmt.Println("        Enable verbose diagnostic output")
+	fmt.Println("        Enable verbose diagnostic output, including the go.work modules used to resolve helpers")
+	fmt.Println()
+	fmt.Println("Scanning Options:")
+	fmt.Println("  -scan-path string")
+	fmt.Println("        Explicit path within the provider to scan (overrides auto-detection)")
+	fmt.Println("  -recursive")
+	fmt.Println("        Recursively scan all subdirectories for Go packages")
+	fmt.Println("  -layout string")
+	fmt.Println("        Generated layout for auto-detection: auto (default), none, awscc, oci, plugingen,")
+	fmt.Println("        or pulumi-bridged")
+	fmt.Println("  -resource-dir-globs string")
+	fmt.Println("        Comma-separated globs of directories holding resource code (e.g., internal/aws/**)")
+	fmt.Println("  -follow-symlinks")
+	fmt.Println("        Follow symlinked directories during recursive scans (cycles are detected)")
+	fmt.Println("  -respect-gitignore")
+	fmt.Println("        Skip paths ignored by .gitignore during recursive scans (default: true)")
+	fmt.Println("  -exclude-dirs string")
+	fmt.Println("        Comma-separated directory names or root-relative paths to skip (e.g., tools,examples)")
+	fmt.Println("  -scan-manifest string")
+	fmt.Println("        Write a JSON manifest of included and excluded directories, with reasons ('-' for stderr)")
+	fmt.Println("  -max-files int")
+	fmt.Println("        Skip and report Go files past this many, in scan order (default: 0, no cap)")
+	fmt.Println("  -max-file-size-kb int")
+	fmt.Println("        Skip and report Go files larger than this many kilobytes (default: 1024; negative for no limit)")
+	fmt.Println("  -force")
+	fmt.Println("        Analyze every file, ignoring -max-files and -max-file-size-kb")
 	fmt.Println()
 	fmt.Println("Diagnostic Options:")
 	fmt.Println("  -report")
 	fmt.Println("        Show comprehensive coverage report with table views")
+	fmt.Println("  -git-metadata")
+	fmt.Println("        With -report, group coverage by each definition file's last commit time")
+	fmt.Println("        (from git history) and list recently changed untested definitions first")
+	fmt.Println("  -anonymize")
+	fmt.Println("        With -report, hash definition, test and file names, keeping the report's")
+	fmt.Println("        structure and statistics, so it can be shared outside the organization")
+	fmt.Println("  -anonymize-salt string")
+	fmt.Println("        Secret that keys the -anonymize hashes (default: $TFPROVIDERTEST_ANONYMIZE_SALT);")
+	fmt.Println("        without one, names change on every run")
+	fmt.Println("  -redact-configs")
+	fmt.Println("        Replace the text of test configs, and the values taken from them, with digests")
+	fmt.Println("        in diagnostics, reports, -configs and -repro bundles")
+	fmt.Println("  -columns string")
+	fmt.Println("        With -report, add columns to the RESOURCES table: confidence (of the strongest")
+	fmt.Println("        test link), match-type (how that test was linked), complexity (schema")
+	fmt.Println("        complexity score) and category, comma-separated")
+	fmt.Println("  -group-by string")
+	fmt.Println("        With -report, add a breakdown of coverage by category: the \"Category:\" line of a")
+	fmt.Println("        definition's doc comment, else its service or package directory")
+	fmt.Println("  -category string")
+	fmt.Println("        With -report, only report definitions in these categories, comma-separated and")
+	fmt.Println("        case-insensitive (\"uncategorized\" selects definitions without one)")
+	fmt.Println("  -sort string")
+	fmt.Println("        With -report, order tables by name (default), confidence or complexity;")
+	fmt.Println("        confidence lists weakly linked definitions first, as they may be untested")
+	fmt.Println("        despite a test count, and complexity the most complex schemas first")
 	fmt.Println("  -show-matches")
 	fmt.Println("        Show all resource -> test function associations")
 	fmt.Println("  -show-unmatched")
 	fmt.Println("        Show test functions without resource association")
 	fmt.Println("  -show-orphaned")
 	fmt.Println("        Show resources without any test coverage")
+	fmt.Println("  -show-weak")
+	fmt.Println("        Show resources with tests that apply configs without Check, ConfigStateChecks,")
+	fmt.Println("        ConfigPlanChecks or ExpectError, which only prove apply does not fail")
+	fmt.Println("  -show-candidates")
+	fmt.Println("        Show tests with near-miss links: resources a linker strategy matched but that")
+	fmt.Println("        were outranked, fall below the fuzzy threshold, or need fuzzy matching enabled")
+	fmt.Println("  -show-helpers")
+	fmt.Println("        Show local test helpers, how many tests use them, and the resources they reference,")
+	fmt.Println("        and the helper packages detected in the scanned tree")
+	fmt.Println("  -ignore-helper-packages")
+	fmt.Println("        Do not treat exported functions of scanned packages that return resource.TestCase,")
+	fmt.Println("        accept test steps or run resource.Test as test helpers")
+	fmt.Println("  -configs")
+	fmt.Println("        List distinct test step configs: the most reused ones and those used exactly once")
+	fmt.Println("  -env-vars")
+	fmt.Println("        List the environment variables test configs interpolate, PreChecks check and the")
+	fmt.Println("        provider reads, and the tests interpolating one their PreCheck never checks")
+	fmt.Println("  -freeze string")
+	fmt.Println("        write: record the checks each definition's tests cover in the freeze file; check: exit")
+	fmt.Println("        with status 1 when a recorded check is no longer covered, whatever the overall coverage")
+	fmt.Println("  -freeze-file string")
+	fmt.Println("        Path of the freeze file (default: " + freeze.File + " in the provider directory)")
+	fmt.Println("  -plan-to string")
+	fmt.Println("        Plan the fewest untested definitions to cover to reach a coverage target (e.g., 80%),")
+	fmt.Println("        ordered by priority, with suggested test names")
+	fmt.Println("  -plan-weights string")
+	fmt.Println("        Priority weights for -plan-to: recent, attributes, tag (e.g., recent=2,tag=3; default 1 each)")
+	fmt.Println("  -plan-tag string")
+	fmt.Println("        Maturity level that marks customer-facing definitions for -plan-to (default: ga)")
+	fmt.Println("  -coverprofile string")
+	fmt.Println("        Correlate a coverage profile from running the acceptance tests (go test -coverprofile)")
+	fmt.Println("        with the Create, Read, Update and Delete methods of each definition, and list tested")
+	fmt.Println("        definitions whose methods never ran")
+	fmt.Println("  -augment-tests")
+	fmt.Println("        Suggest a ConfigStateChecks block for each test step that applies a config without")
+	fmt.Println("        Check or ConfigStateChecks, asserting each Required attribute of the tested definition")
+	fmt.Println("  -augment-patch string")
+	fmt.Println("        Write the -augment-tests suggestions as a unified diff for git apply ('-' for stdout)")
+	fmt.Println("  -fix")
+	fmt.Println("        Apply the automatic fixes of findings to the source files, such as an import step")
+	fmt.Println("        appended to the Steps of a resource's existing test; golangci-lint --fix applies")
+	fmt.Println("        the same fixes")
+	fmt.Println("  -annotate")
+	fmt.Println("        Write or refresh a \"// Test coverage: basic ✓ update ✗ ... (generated by tfprovidertest)\"")
+	fmt.Println("        line in the doc comment of each definition's type; other comments are preserved")
+	fmt.Println("  -annotate-dry-run")
+	fmt.Println("        List the coverage lines -annotate would add or update without writing them")
+	fmt.Println("  -explain string")
+	fmt.Println("        Explain one resource: how it was discovered, each matcher's verdict on candidate")
+	fmt.Println("        tests, applied exclusions and suggestions (name, example_name or \"data source:name\")")
+	fmt.Println("  -repro string")
+	fmt.Println("        Package a reproducer for a bug report about one resource as a zip: its definition,")
+	fmt.Println("        registration and candidate test files with HCL string values scrubbed, the effective")
+	fmt.Println("        settings and the -explain decision log with every linker candidate")
+	fmt.Println("  -repro-output string")
+	fmt.Println("        Path of the -repro zip (default: tfprovidertest-repro-<resource>.zip)")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  init")
+	fmt.Println("        Detect the module path, provider type name, layout and helper packages, write a")
+	fmt.Println("        commented tfprovidertest.yaml and print the .golangci.yml block (see validate init -h)")
+	fmt.Println("  show <name>")
+	fmt.Println("        Print everything known about one resource: kind, file, attributes with their flags,")
+	fmt.Println("        CRUD operations, linked tests with their steps and how they were linked, related")
+	fmt.Println("        diagnostics and suggested next tests (text or json)")
+	fmt.Println("  metrics <file.json>...")
+	fmt.Println("        Aggregate exported -report and analyzer run JSON files into per-kind coverage,")
+	fmt.Println("        per-service coverage and rule frequency over time (see validate metrics -h)")
+	fmt.Println("  org-report -inputs <file.json>,...")
+	fmt.Println("        Merge the exported -report JSON of many providers into per-provider coverage,")
+	fmt.Println("        organization totals and the worst offenders (see validate org-report -h)")
+	fmt.Println("  generate")
+	fmt.Println("        Write a skipped, quarantined TestAcc*_basic stub into the expected test file of each")
+	fmt.Println("        untested definition; stubs are regenerated until their marker line is deleted, and")
+	fmt.Println("        other existing files are never touched. Without -provider, scans the current directory")
+	fmt.Println("  -resource string")
+	fmt.Println("        With generate, the definitions to stub: current-file (those in $GOFILE, for")
+	fmt.Println("        //go:generate validate generate -resource current-file), all (default), or names")
+	fmt.Println("  -dry-run")
+	fmt.Println("        With generate, list the stubs that would be written without writing them")
+	fmt.Println("  -verify-test-list")
+	fmt.Println("        Run `go test -list` in each test package and report discovered tests the toolchain")
+	fmt.Println("        does not list (build-constrained files, packages that do not compile); exits 1 if any")
+	fmt.Println("  -tags string")
+	fmt.Println("        Comma-separated build tags passed to go test by -verify-test-list (e.g., integration)")
+	fmt.Println()
+	fmt.Println("Requirements Options:")
+	fmt.Println("  -requirements-manifest string")
+	fmt.Println("        Path of the coverage requirements manifest, or 'off' (default: the nearest")
+	fmt.Println("        tfprovidertest.requirements.yaml between the scanned code and the module root)")
+	fmt.Println("  -sync-manifest")
+	fmt.Println("        Add an entry for every definition the manifest does not list, creating it at")
+	fmt.Println("        the provider root if needed, and list entries that name no definition")
+	fmt.Println()
+	fmt.Println("Environment:")
+	fmt.Println("  TFPROVIDERTEST_LIVE_DISCOVERY=1")
+	fmt.Println("        With -report, build the provider and compare static discovery against")
+	fmt.Println("        `terraform providers schema -json` to score discovery confidence")
+	fmt.Println("  TFPROVIDERTEST_LIVE_PROVIDER_ADDRESS, TFPROVIDERTEST_TERRAFORM_PATH")
+	fmt.Println("        Override the provider source address and terraform binary for live discovery")
+	fmt.Println("  TFPROVIDERTEST_ANONYMIZE_SALT")
+	fmt.Println("        Default of -anonymize-salt")
+	fmt.Println()
+	fmt.Println("Rule Options:")
+	fmt.Println("  -config string")
+	fmt.Println("        Settings file, with the setting names of the settings reference; flags given on the")
+	fmt.Println("        command line win ('off' to disable; default: tfprovidertest.yaml in the provider directory)")
+	fmt.Println("  -profile string")
+	fmt.Println("        Rule profile: minimal (basic and import tests only), recommended (HashiCorp's")
+	fmt.Println("        testing guidance) or strict (every rule, including opt-in quality rules)")
+	fmt.Println("  -analyzer string")
+	fmt.Println("        Run only the named analyzer, even one disabled by the settings; repeat the flag")
+	fmt.Println("        or separate names with commas to run several (legacy rule names are accepted)")
+	fmt.Println("  -rerun-failed")
+	fmt.Println("        Run only the analyzers that reported findings in the previous run, and report only")
+	fmt.Println("        the files they reported them in, to check fixes quickly; run in full before pushing")
+	fmt.Println("  -run-state string")
+	fmt.Println("        Path of the file each run is recorded in for -rerun-failed ('off' to not record runs;")
+	fmt.Println("        default: a file per provider directory in the user cache directory)")
+	fmt.Println("  -list-rules")
+	fmt.Println("        List every rule with its code, group, default and enabling setting, and exit")
+	fmt.Println("  -enforce-paths string")
+	fmt.Println("        Comma-separated globs (e.g., internal/service/s3/**); only findings in matching")
+	fmt.Println("        files are errors, the rest are warnings")
+	fmt.Println("  -warn-only-paths string")
+	fmt.Println("        Comma-separated globs of files whose findings are warnings, even if enforced")
+	fmt.Println("  -resources string")
+	fmt.Println("        Comma-separated globs of definition names, with or without the provider prefix")
+	fmt.Println("        (e.g., 'aws_s3_*'); only matching definitions and their tests are analyzed, reported")
+	fmt.Println("        and counted by -fail-under, so a team can gate its own definitions")
+	fmt.Println("  -since string")
+	fmt.Println("        Git ref the change under review is compared with (e.g., origin/main); with")
+	fmt.Println("        tfprovider-coverage-upgrade-test, resources whose schema version changed since")
+	fmt.Println("        it need an upgrade test, and with tfprovider-quality-legacy-checks, test steps added")
+	fmt.Println("        since it must use ConfigStateChecks rather than Check")
 	fmt.Println()
 	fmt.Println("Matching Options:")
 	fmt.Println("  -match-strategy string")
@@ -208,7 +812,41 @@ func printUsage() {
 	fmt.Println()
 	fmt.Println("Output Options:")
 	fmt.Println("  -format string")
-	fmt.Println("        Output format: text, json, or table (default: text)")
+	fmt.Println("        Output format: text, json, ndjson, table, codeclimate, or csv (default: text)")
+	fmt.Println("        Standard analysis prints a per-rule summary; json includes it with all findings")
+	fmt.Println("        codeclimate emits findings as Code Climate issues (GitLab code quality, SonarQube import)")
+	fmt.Println("        csv (with -report) emits one row per definition with its coverage columns, counts,")
+	fmt.Println("        test files and link confidence, for spreadsheets")
+	fmt.Println("  -heatmap string")
+	fmt.Println("        Write per-file coverage as CSV (path, loc, definitions, tested, findings, coverage)")
+	fmt.Println("        for treemap visualizations ('-' for stdout)")
+	fmt.Println("  -routing string")
+	fmt.Println("        Write findings grouped into one issue per definition, with CODEOWNERS owners,")
+	fmt.Println("        suggested labels and keys stable across runs, for issue-filing bots ('-' for stdout)")
+	fmt.Println("  -summary-file string")
+	fmt.Println("        Write a run summary to archive with a release: tool version, analyzed commit,")
+	fmt.Println("        settings fingerprint, timing, totals and top findings (e.g. tfprovidertest-summary.json)")
+	fmt.Println("  -output string")
+	fmt.Println("        Send the output to this destination instead of stdout; repeat to send it to several:")
+	fmt.Println("        stdout, file=<path>, or http=<url> to POST it when the run completes")
+	fmt.Println("  -output-header string")
+	fmt.Println("        With -output http=<url>, add a \"Name: value\" header, e.g. \"Authorization: Bearer $TOKEN\";")
+	fmt.Println("        $VARIABLES are expanded from the environment (repeatable)")
+	fmt.Println("  -output-retries int")
+	fmt.Println("        With -output http=<url>, retry a failed request this many times, with backoff (default: 3)")
+	fmt.Println("  -query string")
+	fmt.Println("        Print the results of a jq-style expression over the JSON output instead of the output,")
+	fmt.Println("        one per line, e.g. '.summary.untested_resources' or")
+	fmt.Println("        '[.resources[] | select(.test_count == 0) | .name]' with -report; implies -format json")
+	fmt.Println("  -language string")
+	fmt.Println("        Language of diagnostic messages: en or ja (default: en)")
+	fmt.Println("  -message-style string")
+	fmt.Println("        Diagnostic message style: long, with locations and suggestions, or short, one line")
+	fmt.Println("        ending in the rule code such as [TFPT003] (default: long)")
+	fmt.Println("  -repo-url-template string")
+	fmt.Println("        Add a url to each finding (json, ndjson), report entry (-report json, ndjson) and")
+	fmt.Println("        show page, e.g. https://github.com/org/repo/blob/main/{path}#L{line}; {path} is")
+	fmt.Println("        relative to the root of the git work tree")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  # Run standard analysis")
@@ -223,6 +861,30 @@ func printUsage() {
 	fmt.Println("  # Use function-only matching with custom threshold")
 	fmt.Println("  validate -provider ./provider -match-strategy function -confidence-threshold 0.8")
 	fmt.Println()
+	fmt.Println("  # Find recently added or modified resources that lack tests")
+	fmt.Println("  validate -provider ./provider -report -git-metadata")
+	fmt.Println()
+	fmt.Println("  # Find resources whose only tests are weakly linked")
+	fmt.Println("  validate -provider ./provider -report -columns confidence,match-type -sort confidence")
+	fmt.Println()
+	fmt.Println("  # Find out why a resource is reported as untested")
+	fmt.Println("  validate -provider ./provider -explain widget")
+	fmt.Println()
+	fmt.Println("  # Package a scrubbed reproducer when widget is linked to the wrong tests")
+	fmt.Println("  validate -provider ./provider -repro widget")
+	fmt.Println()
+	fmt.Println("  # Confirm every discovered test compiles and would run")
+	fmt.Println("  validate -provider ./provider -verify-test-list -tags integration")
+	fmt.Println()
+	fmt.Println("  # Iterate on missing import tests only")
+	fmt.Println("  validate -provider ./provider -analyzer tfprovider-coverage-import-test")
+	fmt.Println()
+	fmt.Println("  # Run every rule, including the opt-in quality rules")
+	fmt.Println("  validate -provider ./provider -profile strict")
+	fmt.Println()
+	fmt.Println("  # Scaffold coverage requirements for new resources")
+	fmt.Println("  validate -provider ./provider -sync-manifest")
+	fmt.Println()
 	fmt.Println("  # Export all matches as JSON")
 	fmt.Println("  validate -provider ./provider -show-matches -format json > matches.json")
 }
@@ -233,169 +895,226 @@ func validateSettings(settings config.Settings) error {
 	if settings.FuzzyMatchThreshold < 0.0 || settings.FuzzyMatchThreshold > 1.0 {
 		return fmt.Errorf("confidence-threshold must be between 0.0 and 1.0, got %f", settings.FuzzyMatchThreshold)
 	}
+	if !messages.Supported(settings.Language) {
+		return fmt.Errorf("unsupported language %q (supported: en, ja)", settings.Language)
+	}
+	if settings.MessageStyle != config.MessageStyleLong && settings.MessageStyle != config.MessageStyleShort {
+		return fmt.Errorf("unsupported message-style %q (supported: long, short)", settings.MessageStyle)
+	}
+	if err := links.Validate(settings.RepoURLTemplate); err != nil {
+		return err
+	}
+	if _, err := settings.EnforcementPolicy(); err != nil {
+		return err
+	}
 
 	// Function name matching and file-based matching always run (no validation needed)
 	return nil
 }
 
-// runDiagnostics handles diagnostic output modes
-func runDiagnostics(fset *token.FileSet, files []*ast.File, settings config.Settings, format string, showMatches, showUnmatched, showOrphaned bool) {
-	// Validate output format
-	if format != "text" && format != "json" && format != "table" {
-		fmt.Printf("Error: Invalid format '%s'. Must be one of: text, json, table\n", format)
-		os.Exit(1)
+// runAnalyzers executes the standard analysis workflow. When selected names analyzers, only
+// those run, whether or not the settings enable them.
+func runAnalyzers(fset *token.FileSet, files []*ast.File, settings config.Settings, format string, providerPath string, heatmapPath string, routingPath string, prSuggestionsPath string, summaryPath string, selected []string, gate coverageGate, workers int, fix bool, recorder *healthRecorder, statePath string, rerun *runState) {
+	if format == "csv" {
+		fmt.Println("Error: -format csv is only supported with -report")
+		exit(1)
 	}
+	if format != "text" && format != "json" && format != "ndjson" && format != "table" && format != "codeclimate" {
+		fmt.Printf("Error: Invalid format '%s'. Must be one of: text, json, ndjson, table, codeclimate\n", format)
+		exit(1)
+	}
+	jsonOutput := format == "json" || format == "ndjson" || format == "codeclimate"
 
-	// TODO: Build registry and perform resource-test linking
-	// This requires exposing BuildRegistry or creating a diagnostic-specific function
-	// For now, output placeholder information
-
-	if showMatches {
-		fmt.Println("=== Resource -> Test Function Associations ===")
-		fmt.Println()
-		// TODO: Get actual matches from registry
-		// matches := getResourceTestMatches(fset, files, settings)
-		// outputMatches(matches, format)
-		fmt.Println("  (Diagnostic output requires registry access - implementation pending)")
-		fmt.Println("  This will show all detected resource -> test function mappings")
-		fmt.Println("  including confidence scores and match types.")
-		fmt.Println()
+	// With ndjson, findings are written as they are reported and only counted, unless the
+	// heatmap, routing, PR suggestions or summary file needs them
+	var stream *ndjsonWriter
+	if format == "ndjson" {
+		stream = newNDJSONWriter(os.Stdout)
 	}
+	keepFindings := stream == nil || heatmapPath != "" || routingPath != "" || prSuggestionsPath != "" || summaryPath != ""
 
-	if showUnmatched {
-		fmt.Println("=== Unmatched Test Functions ===")
-		fmt.Println()
-		// TODO: Get unmatched tests from registry
-		// unmatched := registry.GetUnmatchedTestFunctions()
-		// outputUnmatched(unmatched, format)
-		fmt.Println("  (Diagnostic output requires registry access - implementation pending)")
-		fmt.Println("  This will show test functions that could not be associated")
-		fmt.Println("  with any known resource definition.")
-		fmt.Println()
+	// Build the analyzers the settings enable, or the selected ones
+	analyzers, err := buildAnalyzers(settings, selected)
+	if err != nil {
+		fmt.Printf("Error building analyzers: %v\n", err)
+		exit(1)
 	}
 
-	if showOrphaned {
-		fmt.Println("=== Orphaned Resources (No Tests) ===")
-		fmt.Println()
-		// TODO: Get orphaned resources from registry
-		// orphaned := registry.GetUntestedResources()
-		// outputOrphaned(orphaned, format)
-		fmt.Println("  (Diagnostic output requires registry access - implementation pending)")
-		fmt.Println("  This will show resources that have no associated test coverage.")
-		fmt.Println()
+	// Build a registry so findings can be attributed to resources in the summary
+	reg := buildRegistryTimed(fset, files, settings, recorder)
+	attributor := newFindingAttributor(reg)
+	link := newLinker(settings, providerPath, fset)
+
+	// Run the analyzers in parallel; each one's findings are printed and streamed in
+	// analyzer order once it and the analyzers before it are done
+	findings := make([]Finding, 0)
+	ruleNames := make([]string, 0, len(analyzers))
+	for _, analyzer := range analyzers {
+		ruleNames = append(ruleNames, analyzer.Name)
 	}
-}
+	tally := newFindingTally(ruleNames)
+	state := newRunState()
+	var fixes []goanalysis.SuggestedFix
+	runAnalyzerPool(fset, files, analyzers, workers, func(result analyzerResult) {
+		analyzer := result.analyzer
+		if !jsonOutput {
+			fmt.Printf("Running %s...\n", analyzer.Name)
+		}
+		for _, diag := range result.diagnostics {
+			pos := fset.Position(diag.Pos)
+			if !rerun.covers(analyzer.Name, pos.Filename, providerPath) {
+				continue
+			}
+			state.add(analyzer.Name, pos.Filename, providerPath)
+			finding := Finding{
+				Rule:    analyzer.Name,
+				Code:    rules.Code(analyzer.Name),
+				Group:   string(rules.GroupOf(analyzer.Name)),
+				Level:   string(enforcement.LevelError),
+				File:    pos.Filename,
+				Line:    pos.Line,
+				Message: diag.Message,
+			}
+			if diag.Category == string(enforcement.LevelWarning) {
+				finding.Level = string(enforcement.LevelWarning)
+			}
+			if info := attributor.resourceFor(diag.Pos, pos.Filename); info != nil {
+				finding.Resource = info.Name
+				finding.Kind = info.Kind.String()
+			}
+			finding.URL = link.URL(pos.Filename, pos.Line)
+			for _, suggested := range diag.SuggestedFixes {
+				finding.Fixes = append(finding.Fixes, suggested.Message)
+				if fix && len(suggested.TextEdits) > 0 {
+					fixes = append(fixes, suggested)
+				}
+			}
+			tally.add(finding)
+			if keepFindings {
+				findings = append(findings, finding)
+			}
+			if stream != nil {
+				stream.write(recordFinding, finding)
+			}
 
-// outputMatchesText outputs matches in human-readable text format
-//
-//nolint:unused // Prepared for future diagnostic output implementation
-func outputMatchesText(matches []MatchInfo) {
-	for _, m := range matches {
-		fmt.Printf("  %s -> %s (%.0f%%, %s)\n", m.ResourceName, m.TestFunction, m.Confidence*100, m.MatchType)
-		if m.TestFile != "" {
-			fmt.Printf("    File: %s\n", m.TestFile)
+			if !jsonOutput {
+				fmt.Printf("\n[%s] %s:%d\n", analyzer.Name, pos.Filename, pos.Line)
+				fmt.Printf("  %s\n", diag.Message)
+				for _, fix := range finding.Fixes {
+					fmt.Printf("  Fix: %s\n", fix)
+				}
+			}
 		}
-	}
-}
+		if result.err != nil {
+			fmt.Fprintf(os.Stderr, "  Error running analyzer %s: %v\n", analyzer.Name, result.err)
+		}
+	})
 
-// outputMatchesTable outputs matches in a formatted table
-//
-//nolint:unused // Prepared for future diagnostic output implementation
-func outputMatchesTable(matches []MatchInfo) {
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "RESOURCE\tTEST FUNCTION\tCONFIDENCE\tMATCH TYPE\tTEST FILE")
-	fmt.Fprintln(w, "--------\t-------------\t----------\t----------\t---------")
-	for _, m := range matches {
-		fmt.Fprintf(w, "%s\t%s\t%.0f%%\t%s\t%s\n", m.ResourceName, m.TestFunction, m.Confidence*100, m.MatchType, m.TestFile)
-	}
-	w.Flush()
-}
+	recorder.lap(phaseAnalyzers)
 
-// outputMatchesJSON outputs matches as formatted JSON
-//
-//nolint:unused // Prepared for future diagnostic output implementation
-func outputMatchesJSON(matches []MatchInfo) {
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-	if err := enc.Encode(matches); err != nil {
-		fmt.Printf("Error encoding JSON: %v\n", err)
+	if statePath != "" {
+		if err := state.write(statePath); err != nil && settings.Verbose {
+			fmt.Fprintf(os.Stderr, "Warning: Could not record the run for -rerun-failed: %v\n", err)
+		}
 	}
-}
 
-// outputMatches routes to the appropriate output formatter
-//
-//nolint:unused // Prepared for future diagnostic output implementation
-func outputMatches(matches []MatchInfo, format string) {
-	switch format {
-	case "json":
-		outputMatchesJSON(matches)
-	case "table":
-		outputMatchesTable(matches)
-	default:
-		outputMatchesText(matches)
+	if len(fixes) > 0 {
+		fixed, err := applyFixes(fset, fixes)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Could not apply fixes: %v\n", err)
+			exit(1)
+		}
+		for _, path := range fixed {
+			fmt.Fprintf(os.Stderr, "Fixed %s\n", path)
+		}
 	}
-}
 
-// runAnalyzers executes the standard analysis workflow
-func runAnalyzers(fset *token.FileSet, files []*ast.File, settings config.Settings) {
-	// Create plugin with settings map
-	settingsMap := map[string]interface{}{
-		"Verbose":               settings.Verbose,
-		"EnableBasicTest":       settings.EnableBasicTest,
-		"EnableUpdateTest":      settings.EnableUpdateTest,
-		"EnableImportTest":      settings.EnableImportTest,
-		"EnableErrorTest":       settings.EnableErrorTest,
-		"EnableStateCheck":      settings.EnableStateCheck,
-		"EnableFuzzyMatching":   settings.EnableFuzzyMatching,
-		"FuzzyMatchThreshold":   settings.FuzzyMatchThreshold,
-		"ProviderPrefix":        settings.ProviderPrefix,
-		"ShowMatchConfidence":   settings.ShowMatchConfidence,
-		"ShowUnmatchedTests":    settings.ShowUnmatchedTests,
-		"ShowOrphanedResources": settings.ShowOrphanedResources,
+	// Merged findings are ordered by position, then rule
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+		if findings[i].Line != findings[j].Line {
+			return findings[i].Line < findings[j].Line
+		}
+		return findings[i].Rule < findings[j].Rule
+	})
+
+	if heatmapPath != "" {
+		if err := writeHeatmapCSV(buildHeatmap(fset, files, reg, findings, providerPath), heatmapPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Could not write heatmap: %v\n", err)
+			exit(1)
+		}
 	}
 
-	plugin, err := tfprovidertest.New(settingsMap)
-	if err != nil {
-		fmt.Printf("Error creating plugin: %v\n", err)
-		os.Exit(1)
+	if routingPath != "" {
+		if err := writeRouting(routingPath, findings, reg, providerPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Could not write routing file: %v\n", err)
+			exit(1)
+		}
 	}
 
-	// Get all analyzers
-	analyzers, err := plugin.BuildAnalyzers()
-	if err != nil {
-		fmt.Printf("Error building analyzers: %v\n", err)
-		os.Exit(1)
+	if prSuggestionsPath != "" {
+		if err := writePRSuggestions(prSuggestionsPath, findings, reg, providerPath, settings.Since); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Could not write PR suggestions: %v\n", err)
+			exit(1)
+		}
 	}
 
-	// Create a simple analysis pass for each analyzer
-	totalIssues := 0
-	for _, analyzer := range analyzers {
-		fmt.Printf("Running %s...\n", analyzer.Name)
-
-		pass := &analysis.Pass{
-			Analyzer: analyzer,
-			Fset:     fset,
-			Files:    files,
-			Report: func(diag analysis.Diagnostic) {
-				pos := fset.Position(diag.Pos)
-				fmt.Printf("\n[%s] %s:%d\n", analyzer.Name, pos.Filename, pos.Line)
-				fmt.Printf("  %s\n", diag.Message)
-				totalIssues++
-			},
+	var gateResult *GateResult
+	if gate.enabled() {
+		result := gate.evaluate(reg)
+		gateResult = &result
+		if gate.OutputPath != "" {
+			if err := writeGateOutput(gate.OutputPath, result); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Could not write gate output: %v\n", err)
+				exit(1)
+			}
 		}
+	}
 
-		_, err := analyzer.Run(pass)
+	summary := tally.summary()
+	health := recorder.health(reg, fset, files, settings)
+	if summaryPath != "" {
+		doc, err := buildSummaryArtifact(reg, settings, providerPath, ruleNames, findings, summary, health, gateResult)
+		if err == nil {
+			err = artifact.Write(summaryPath, doc)
+		}
 		if err != nil {
-			fmt.Printf("  Error running analyzer: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error: Could not write summary file: %v\n", err)
+			exit(1)
 		}
 	}
+	switch {
+	case format == "codeclimate":
+		outputCodeClimate(findings, providerPath)
+	case stream != nil:
+		if gateResult != nil {
+			stream.write(recordGate, gateResult)
+		}
+		if health != nil {
+			stream.write(recordHealth, health)
+		}
+		stream.write(recordSummary, summary)
+		if stream.err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", stream.err)
+		}
+	case jsonOutput:
+		outputRunJSON(AnalyzerRunOutput{Findings: findings, Summary: summary, Gate: gateResult, Health: health})
+	default:
+		outputRunSummaryText(summary)
+	}
 
-	fmt.Println()
-	fmt.Println("=== Summary ===")
-	if totalIssues == 0 {
-		fmt.Println("No issues found - all resources have proper test coverage!")
-	} else {
-		fmt.Printf("Found %d issue(s)\n", totalIssues)
+	if gateResult != nil {
+		// Machine-readable output stays on stdout, so the gate is reported on stderr
+		out := io.Writer(os.Stdout)
+		if jsonOutput {
+			out = os.Stderr
+		}
+		printGateResult(out, *gateResult)
+		if code := gateResult.exitCode(); code != 0 {
+			exit(code)
+		}
 	}
 }
 
@@ -423,62 +1142,60 @@ func findProviderCodeDir(providerPath string) string {
 	return ""
 }
 
-// buildRegistryFromFiles creates a registry from parsed AST files
-func buildRegistryFromFiles(fset *token.FileSet, files []*ast.File, settings config.Settings) *registry.ResourceRegistry {
-	reg := registry.NewResourceRegistry()
-	parserConfig := discovery.DefaultParserConfig()
-
-	for _, file := range files {
-		filePath := fset.Position(file.Pos()).Filename
-
-		// Apply exclusion settings
-		if settings.ExcludeBaseClasses && discovery.IsBaseClassFile(filePath) {
-			continue
-		}
-		if settings.ExcludeSweeperFiles && discovery.IsSweeperFile(filePath) {
-			continue
-		}
-		if settings.ExcludeMigrationFiles && discovery.IsMigrationFile(filePath) {
-			continue
-		}
-
-		if strings.HasSuffix(filePath, "_test.go") {
-			testInfo := discovery.ParseTestFileWithConfig(file, fset, filePath, parserConfig)
-			if testInfo == nil {
-				continue
-			}
-			for i := range testInfo.TestFunctions {
-				reg.RegisterTestFunction(&testInfo.TestFunctions[i])
-			}
-		} else {
-			// Standard resource parsing (from Schema/Metadata methods)
-			resources := discovery.ParseResources(file, fset, filePath)
-			for _, resource := range resources {
-				reg.RegisterResource(resource)
-			}
-
-			// Also check for provider registry maps (e.g., generatedResources, generatedIAMDatasources)
-			// This handles providers like Google that define resources in central map variables
-			registryResources := discovery.ParseProviderRegistryMaps(file, fset, filePath)
-			for _, resource := range registryResources {
-				reg.RegisterResource(resource)
+// findLayoutDirs returns the directories of resource code named by globs, or else by the
+// layout (auto detects a known generated layout, none skips layouts), with a description of
+// where they came from. It returns no directories when neither applies, so the conventional
+// locations are tried.
+func findLayoutDirs(providerPath, layoutName string, globs []string, opts scan.Options) ([]string, string, error) {
+	explicit := len(globs) > 0
+	source := "resource-dir-globs " + strings.Join(globs, ", ")
+	if !explicit {
+		var layout scan.Layout
+		var ok bool
+		switch layoutName {
+		case config.LayoutNone:
+			return nil, "", nil
+		case config.LayoutAuto, "":
+			layout, ok = scan.DetectLayout(providerPath)
+		default:
+			if layout, ok = scan.LookupLayout(layoutName); !ok {
+				return nil, "", fmt.Errorf("unknown layout %q (supported: %s, %s, %s)", layoutName, config.LayoutAuto, config.LayoutNone, strings.Join(scan.LayoutNames(), ", "))
 			}
 		}
+		if !ok {
+			return nil, "", nil
+		}
+		globs = layout.ResourceDirGlobs
+		source = layout.Name + " layout: " + strings.Join(globs, ", ")
 	}
 
-	// Run linking
-	linker := matching.NewLinker(reg, &settings)
-	linker.LinkTestsToResources()
+	dirs, err := scan.ResourceDirs(providerPath, globs, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(dirs) == 0 && explicit {
+		return nil, "", fmt.Errorf("no directories with Go files match %s", source)
+	}
+	return dirs, source, nil
+}
 
-	// Classify all tests to enable filtering of orphans
-	linker.ClassifyAllTests()
+// buildRegistryFromFiles creates a registry from parsed AST files
+func buildRegistryFromFiles(fset *token.FileSet, files []*ast.File, settings config.Settings) *registry.ResourceRegistry {
+	return buildRegistryTimed(fset, files, settings, nil)
+}
 
-	return reg
+// buildRegistryTimed is buildRegistryFromFiles, recording the discovery and linking phases
+// with recorder
+func buildRegistryTimed(fset *token.FileSet, files []*ast.File, settings config.Settings, recorder *healthRecorder) *registry.ResourceRegistry {
+	return discovery.BuildProviderRegistry(fset, files, settings, recorder.lap)
 }
 
 // runReport generates a comprehensive coverage report with table views
-func runReport(fset *token.FileSet, files []*ast.File, settings config.Settings, format string) {
-	reg := buildRegistryFromFiles(fset, files, settings)
+func runReport(fset *token.FileSet, files []*ast.File, settings config.Settings, format string, providerPath string, gitMetadata bool, view reportView, anonymizer *anonymize.Anonymizer, recorder *healthRecorder) {
+	reg := buildRegistryTimed(fset, files, settings, recorder)
+	if anonymizer != nil {
+		reg = anonymizer.Registry(reg)
+	}
 	allDefs := reg.GetAllDefinitions()
 
 	// Group definitions by kind
@@ -494,33 +1211,101 @@ func runReport(fset *token.FileSet, files []*ast.File, settings config.Settings,
 		}
 	}
 
-	// Sort each slice by name
-	sort.Slice(resources, func(i, j int) bool { return resources[i].Name < resources[j].Name })
-	sort.Slice(dataSources, func(i, j int) bool { return dataSources[i].Name < dataSources[j].Name })
-	sort.Slice(actions, func(i, j int) bool { return actions[i].Name < actions[j].Name })
+	// With -category, the report covers only those product areas
+	resources = filterByCategory(resources, view.Categories)
+	dataSources = filterByCategory(dataSources, view.Categories)
+	actions = filterByCategory(actions, view.Categories)
+
+	// Sort each slice by name, or by link confidence with -sort confidence
+	view.sortDefinitions(reg, resources)
+	view.sortDefinitions(reg, dataSources)
+	view.sortDefinitions(reg, actions)
+
+	// Orphan tests belong to no definition, so no category either
+	var orphans []*registry.TestFunctionInfo
+	if len(view.Categories) == 0 {
+		orphans = reg.GetUnmatchedTestFunctions()
+	}
 
-	orphans := reg.GetUnmatchedTestFunctions()
+	// Optionally validate static discovery against the compiled provider; its type names are
+	// the provider's own, so it is skipped when anonymizing
+	var discovery *livediscovery.Result
+	if livediscovery.Enabled() && anonymizer == nil {
+		schemas, err := livediscovery.Discover(context.Background(), livediscovery.OptionsFromEnv(providerPath))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: live discovery failed: %v\n", err)
+		} else {
+			result := livediscovery.Compare(schemas, reg)
+			discovery = &result
+		}
+	}
+
+	roles := buildFileRoleReports(fset, files, settings)
+
+	// Optionally correlate definition age (from git history) with coverage
+	var age *gitmeta.Report
+	if gitMetadata {
+		result := gitmeta.Build(reg, gitmeta.DefaultBuckets(), time.Now(), gitmeta.GitLastModified(context.Background()))
+		age = &result
+	}
+
+	maturity := buildMaturitySummary(reg, settings, resources, dataSources, actions)
+	var categories []CategoryReport
+	if view.GroupBy == groupByCategory {
+		categories = buildCategorySummary(reg, resources, dataSources, actions)
+	}
+
+	recorder.lap(phaseReport)
+	health := recorder.health(reg, fset, files, settings)
+	if health != nil && anonymizer != nil {
+		for i := range health.FilesSkipped {
+			health.FilesSkipped[i].Path = anonymizer.Path(health.FilesSkipped[i].Path)
+			health.FilesSkipped[i].Detail = ""
+		}
+	}
 
 	switch format {
 	case "json":
-		outputReportJSON(reg, resources, dataSources, actions, orphans)
+		outputReportJSON(reg, resources, dataSources, actions, orphans, discovery, age, roles, maturity, categories, health, newLinker(settings, providerPath, fset))
+	case "ndjson":
+		outputReportNDJSON(reg, resources, dataSources, actions, orphans, discovery, age, roles, maturity, categories, health, newLinker(settings, providerPath, fset))
+	case "csv":
+		if err := writeReportCSV(os.Stdout, reg, resources, dataSources, actions); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Could not write CSV: %v\n", err)
+			exit(1)
+		}
 	case "table":
-		outputReportTable(reg, resources, dataSources, actions, orphans)
+		outputReportTable(reg, resources, dataSources, actions, orphans, discovery, age, roles, maturity, categories, view)
 	default:
-		outputReportTable(reg, resources, dataSources, actions, orphans)
+		outputReportTable(reg, resources, dataSources, actions, orphans, discovery, age, roles, maturity, categories, view)
 	}
 }
 
 // ReportData holds all data for JSON output
 type ReportData struct {
-	Summary     ReportSummary      `json:"summary"`
-	Resources   []ResourceReport   `json:"resources"`
-	DataSources []ResourceReport   `json:"data_sources"`
-	Actions     []ResourceReport   `json:"actions"`
-	Orphans     []OrphanReport     `json:"orphan_tests"`
+	Summary     ReportSummary         `json:"summary"`
+	Resources   []ResourceReport      `json:"resources"`
+	DataSources []ResourceReport      `json:"data_sources"`
+	Actions     []ResourceReport      `json:"actions"`
+	Orphans     []OrphanReport        `json:"orphan_tests"`
+	Discovery   *livediscovery.Result `json:"discovery,omitempty"`
+	Age         *gitmeta.Report       `json:"age,omitempty"`
+	FileRoles   []FileRoleReport      `json:"file_roles,omitempty"`
+	TopRisks    []analysis.RiskEntry  `json:"top_risks,omitempty"` // Untested definitions with the most complex schemas
+	Health      *RunHealth            `json:"health,omitempty"`    // How the run itself went; see RunHealth
+}
+
+// FileRoleReport is the number of scanned files with a role and whether they were skipped
+// during definition and test discovery
+type FileRoleReport struct {
+	Role          string `json:"role"`
+	Files         int    `json:"files"`
+	ExcludesDefs  bool   `json:"excludes_definitions"`
+	ExcludesTests bool   `json:"excludes_tests"`
 }
 
 type ReportSummary struct {
+	Provider                string `json:"provider,omitempty"` // Type name of the provider, e.g. "aws"
 	TotalResources          int `json:"total_resources"`
 	UntestedResources       int `json:"untested_resources"`
 	TotalDataSources        int `json:"total_data_sources"`
@@ -530,69 +1315,165 @@ type ReportSummary struct {
 	OrphanTests             int `json:"orphan_tests"`
 	MissingCheckDestroy     int `json:"missing_check_destroy"`
 	MissingStateChecks      int `json:"missing_state_checks"`
+	QuarantinedTests        int `json:"quarantined_tests"`
+	CoverageAtRisk          int `json:"coverage_at_risk"` // Definitions whose only linked tests are quarantined
+	LegacyCheckSteps        int `json:"legacy_check_steps"` // Test steps still setting the legacy Check field
+	StateCheckSteps         int `json:"state_check_steps"` // Test steps setting ConfigStateChecks
+	WeakTests               int `json:"weak_tests"` // Linked tests applying configs without asserting anything
+	WeaklyTested            int `json:"weakly_tested"` // Tested definitions whose tests are all weak
+	ConditionalTests        int `json:"conditional_tests"` // Linked tests skipped under some versions, regions or capabilities
+	ConditionallyTested     int `json:"conditionally_tested"` // Tested definitions whose tests are all conditional
+	Maturity                []MaturityReport `json:"maturity,omitempty"` // Breakdown by maturity level, when any definition is tagged
+	Categories              []CategoryReport `json:"categories,omitempty"` // Breakdown by category, with -group-by category
+	TestLayout              string `json:"test_layout"` // Detected test layout: co-located or centralized
 }
 
 type ResourceReport struct {
 	Name                 string       `json:"name"`
 	File                 string       `json:"file"`
+	URL                  string       `json:"url,omitempty"` // Link to the definition, with -repo-url-template
 	TestFile             string       `json:"test_file"`
 	TestCount            int          `json:"test_count"`
+	ScenarioCount        int          `json:"scenario_count,omitempty"` // Scenarios the tests run, when subtests run TestCases of their own
 	HasCheckDestroy      bool         `json:"has_check_destroy"`
 	HasCheck             bool         `json:"has_check"`              // Legacy Check field
 	HasConfigStateChecks bool         `json:"has_config_state_checks"` // Modern ConfigStateChecks field
+	LegacyCheckSteps     int          `json:"legacy_check_steps"` // Steps still setting Check, to migrate
+	StateCheckSteps      int          `json:"state_check_steps"`  // Steps setting ConfigStateChecks
+	WeakTests            int          `json:"weak_tests,omitempty"` // Tests applying configs without asserting anything
+	ConditionalTests     int          `json:"conditional_tests,omitempty"` // Tests skipped under some versions, regions or capabilities
 	HasPlanCheck         bool         `json:"has_plan_check"`
 	HasImportTest        bool         `json:"has_import_test"`
 	HasUpdateTest        bool         `json:"has_update_test"`
+	HasUpgradeTest       bool         `json:"has_upgrade_test"` // A test starts from a released provider (ExternalProviders)
+	HasIdempotencyStep   bool         `json:"has_idempotency_step"` // A step re-plans the previous config expecting an empty plan
 	HasExpectError       bool         `json:"has_expect_error"`
+	ErrorCategories      []string     `json:"error_categories,omitempty"` // Kinds of error the ExpectError steps target
 	HasPreCheck          bool         `json:"has_pre_check"`
+	HasOpaqueSteps       bool         `json:"has_opaque_steps,omitempty"` // Some steps could not be resolved; missing step patterns are unknown
+	MatchConfidence      float64      `json:"match_confidence"`           // Confidence of the strongest test link (0 when untested)
+	MatchType            string       `json:"match_type,omitempty"`       // How the strongest test was linked
+	Maturity             string       `json:"maturity,omitempty"`         // Maturity level, when tagged
+	Category             string       `json:"category,omitempty"`         // Product area, when known
+	Complexity           analysis.Complexity `json:"complexity"`         // Schema complexity of the definition
 	Tests                []TestReport `json:"tests"`
+	// Pair is the definition of the other kind sharing the name, for a resource and a data
+	// source with the same name
+	Pair *PairReport `json:"pair,omitempty"`
+	// Singular is the definition a plural data source returns items of, such as the widget
+	// data source or resource for widgets
+	Singular *SingularReport `json:"singular,omitempty"`
+	// QuarantinedTests lists the linked tests that are quarantined, which earn no coverage
+	// credit and are left out of TestCount and Tests
+	QuarantinedTests []TestReport `json:"quarantined_tests,omitempty"`
 }
 
 type TestReport struct {
-	Name      string `json:"name"`
-	File      string `json:"file"`
-	MatchType string `json:"match_type"`
+	Name        string `json:"name"`
+	File        string `json:"file"`
+	URL         string `json:"url,omitempty"` // Link to the test function, with -repo-url-template
+	MatchType   string  `json:"match_type"`
+	Confidence  float64 `json:"confidence"`
+	OpaqueSteps bool    `json:"opaque_steps,omitempty"`
+	// Subtests lists the t.Run subtests that run a TestCase of their own ("?" for a name
+	// not known statically)
+	Subtests []string `json:"subtests,omitempty"`
+	// Weak is true when the test applies configs without Check, ConfigStateChecks,
+	// ConfigPlanChecks or ExpectError, so it only shows apply does not fail
+	Weak bool `json:"weak,omitempty"`
+	// Conditions lists the guards that skip the test, or some of its steps, under some
+	// Terraform versions, regions or API capabilities, so its coverage is conditional
+	Conditions []string `json:"conditions,omitempty"`
+	// QuarantineReason is the reason a quarantined test is quarantined, when one was given
+	QuarantineReason string `json:"quarantine_reason,omitempty"`
+	// UniqueChecks lists the coverage checks no other test of the definition covers, such as
+	// "import" for its only test with an import step
+	UniqueChecks []string `json:"unique_checks,omitempty"`
+	// LoadBearing is true when the test has unique checks: deleting or skipping it would
+	// open a coverage gap
+	LoadBearing bool `json:"load_bearing,omitempty"`
 }
 
 type OrphanReport struct {
 	Name              string   `json:"name"`
 	File              string   `json:"file"`
+	URL               string   `json:"url,omitempty"`
 	InferredResources []string `json:"inferred_resources,omitempty"`
 }
 
 func buildResourceReport(reg *registry.ResourceRegistry, info *registry.ResourceInfo) ResourceReport {
-	key := info.Kind.String() + ":" + info.Name
-	tests := reg.GetResourceTests(key)
+	tests := reg.GetTests(info.Kind, info.Name)
 
 	report := ResourceReport{
-		Name:      info.Name,
-		File:      filepath.Base(info.FilePath),
-		TestCount: len(tests),
+		Name:       info.Name,
+		File:       filepath.Base(info.FilePath),
+		TestCount:  len(tests),
+		Maturity:   info.Maturity,
+		Category:   info.Category,
+		Complexity: analysis.ResourceComplexity(info),
+	}
+
+	if pair := analysis.PairOf(reg, info); pair != nil {
+		report.Pair = &PairReport{Kind: pair.Kind.Key(), TestCount: len(reg.GetTests(pair.Kind, pair.Name))}
+	}
+	if singular := analysis.SingularOf(reg, info); singular != nil {
+		report.Singular = &SingularReport{Kind: singular.Kind.Key(), Name: singular.Name}
+	}
+
+	if best := strongestLink(tests); best != nil {
+		report.MatchConfidence = best.MatchConfidence
+		report.MatchType = best.MatchType.String()
 	}
 
 	// Track unique test files
 	testFiles := make(map[string]bool)
+	categories := make(map[registry.ErrorCategory]bool)
+	provider := analysis.ProviderTypeName(reg)
 
 	for _, t := range tests {
 		testFile := filepath.Base(t.FilePath)
 		testFiles[testFile] = true
+		unique := registry.UniqueChecks(info.Kind, tests, t)
 		report.Tests = append(report.Tests, TestReport{
-			Name:      t.Name,
-			File:      testFile,
-			MatchType: t.MatchType.String(),
+			Name:         t.Name,
+			File:         testFile,
+			MatchType:    t.MatchType.String(),
+			Confidence:   t.MatchConfidence,
+			OpaqueSteps:  t.HasOpaqueSteps,
+			Subtests:     subtestNames(t),
+			Weak:         t.IsWeak(),
+			Conditions:   testConditions(t),
+			UniqueChecks: unique,
+			LoadBearing:  len(unique) > 0,
 		})
+		if t.HasOpaqueSteps {
+			report.HasOpaqueSteps = true
+		}
+		if t.IsWeak() {
+			report.WeakTests++
+		}
+		if t.IsConditional() {
+			report.ConditionalTests++
+		}
 		if t.HasCheckDestroy {
 			report.HasCheckDestroy = true
 		}
 		if t.HasImportStep {
 			report.HasImportTest = true
 		}
+		if _, ok := t.UpgradeFrom(provider); ok {
+			report.HasUpgradeTest = true
+		}
 		for _, step := range t.TestSteps {
 			if step.IsRealUpdateStep() {
 				report.HasUpdateTest = true
 			}
+			if step.IsIdempotencyStep() {
+				report.HasIdempotencyStep = true
+			}
 			if step.ExpectError {
 				report.HasExpectError = true
+				categories[step.ErrorCategory] = true
 			}
 			if step.HasPlanCheck {
 				report.HasPlanCheck = true
@@ -607,6 +1488,13 @@ func buildResourceReport(reg *registry.ResourceRegistry, info *registry.Resource
 		}
 	}
 
+	report.ErrorCategories = errorCategoryNames(categories)
+	if scenarios := registry.ScenarioCount(tests); scenarios > len(tests) {
+		report.ScenarioCount = scenarios
+	}
+	migration := analysis.CheckMigrationOf(tests)
+	report.LegacyCheckSteps, report.StateCheckSteps = migration.LegacySteps, migration.StateCheckSteps
+
 	// Consolidate test files into a single string
 	if len(testFiles) == 1 {
 		for f := range testFiles {
@@ -618,32 +1506,58 @@ func buildResourceReport(reg *registry.ResourceRegistry, info *registry.Resource
 	} else {
 		report.TestFile = "-"
 	}
+	report.QuarantinedTests = quarantinedTestReports(reg, info)
 
 	return report
 }
 
 // buildActionReport creates a report for an action, focusing on action-relevant test patterns
 func buildActionReport(reg *registry.ResourceRegistry, info *registry.ResourceInfo) ResourceReport {
-	key := info.Kind.String() + ":" + info.Name
-	tests := reg.GetResourceTests(key)
+	tests := reg.GetTests(info.Kind, info.Name)
 
 	report := ResourceReport{
-		Name:      info.Name,
-		File:      filepath.Base(info.FilePath),
-		TestCount: len(tests),
+		Name:       info.Name,
+		File:       filepath.Base(info.FilePath),
+		TestCount:  len(tests),
+		Maturity:   info.Maturity,
+		Category:   info.Category,
+		Complexity: analysis.ResourceComplexity(info),
+	}
+
+	if best := strongestLink(tests); best != nil {
+		report.MatchConfidence = best.MatchConfidence
+		report.MatchType = best.MatchType.String()
 	}
 
 	// Track unique test files
 	testFiles := make(map[string]bool)
+	categories := make(map[registry.ErrorCategory]bool)
 
 	for _, t := range tests {
 		testFile := filepath.Base(t.FilePath)
 		testFiles[testFile] = true
+		unique := registry.UniqueChecks(info.Kind, tests, t)
 		report.Tests = append(report.Tests, TestReport{
-			Name:      t.Name,
-			File:      testFile,
-			MatchType: t.MatchType.String(),
+			Name:         t.Name,
+			File:         testFile,
+			MatchType:    t.MatchType.String(),
+			Confidence:   t.MatchConfidence,
+			OpaqueSteps:  t.HasOpaqueSteps,
+			Subtests:     subtestNames(t),
+			Weak:         t.IsWeak(),
+			Conditions:   testConditions(t),
+			UniqueChecks: unique,
+			LoadBearing:  len(unique) > 0,
 		})
+		if t.HasOpaqueSteps {
+			report.HasOpaqueSteps = true
+		}
+		if t.IsWeak() {
+			report.WeakTests++
+		}
+		if t.IsConditional() {
+			report.ConditionalTests++
+		}
 		if t.HasPreCheck {
 			report.HasPreCheck = true
 		}
@@ -653,6 +1567,7 @@ func buildActionReport(reg *registry.ResourceRegistry, info *registry.ResourceIn
 			}
 			if step.ExpectError {
 				report.HasExpectError = true
+				categories[step.ErrorCategory] = true
 			}
 			// Track legacy Check vs modern ConfigStateChecks separately
 			if step.HasCheck {
@@ -664,6 +1579,13 @@ func buildActionReport(reg *registry.ResourceRegistry, info *registry.ResourceIn
 		}
 	}
 
+	report.ErrorCategories = errorCategoryNames(categories)
+	if scenarios := registry.ScenarioCount(tests); scenarios > len(tests) {
+		report.ScenarioCount = scenarios
+	}
+	migration := analysis.CheckMigrationOf(tests)
+	report.LegacyCheckSteps, report.StateCheckSteps = migration.LegacySteps, migration.StateCheckSteps
+
 	// Consolidate test files into a single string
 	if len(testFiles) == 1 {
 		for f := range testFiles {
@@ -674,56 +1596,114 @@ func buildActionReport(reg *registry.ResourceRegistry, info *registry.ResourceIn
 	} else {
 		report.TestFile = "-"
 	}
+	report.QuarantinedTests = quarantinedTestReports(reg, info)
 
 	return report
 }
 
-func outputReportJSON(reg *registry.ResourceRegistry, resources, dataSources, actions []*registry.ResourceInfo, orphans []*registry.TestFunctionInfo) {
-	data := ReportData{}
+// add counts the test steps of a definition report in the summary.
+func (s *ReportSummary) add(report ResourceReport) {
+	s.LegacyCheckSteps += report.LegacyCheckSteps
+	s.StateCheckSteps += report.StateCheckSteps
+	s.WeakTests += report.WeakTests
+	if report.TestCount > 0 && report.WeakTests == report.TestCount {
+		s.WeaklyTested++
+	}
+	s.ConditionalTests += report.ConditionalTests
+	if report.TestCount > 0 && report.ConditionalTests == report.TestCount {
+		s.ConditionallyTested++
+	}
+}
+
+// walkReport builds the report of each definition and orphan test, passing them to
+// onDefinition and onOrphan one at a time, and returns the summary counts. Reports link to
+// the code when link is enabled
+func walkReport(reg *registry.ResourceRegistry, resources, dataSources, actions []*registry.ResourceInfo, orphans []*registry.TestFunctionInfo, link links.Linker, onDefinition func(registry.ResourceKind, ResourceReport), onOrphan func(OrphanReport)) ReportSummary {
+	summary := ReportSummary{Provider: analysis.ProviderTypeName(reg), TestLayout: analysis.DetectTestLayout(reg)}
 
-	// Build resource reports
 	for _, info := range resources {
 		report := buildResourceReport(reg, info)
-		data.Resources = append(data.Resources, report)
+		linkReport(&report, reg, info, link)
+		onDefinition(registry.KindResource, report)
+		summary.add(report)
+		if report.AtRisk() {
+			summary.CoverageAtRisk++
+		}
 		if report.TestCount == 0 {
-			data.Summary.UntestedResources++
+			summary.UntestedResources++
 		} else if !report.HasCheckDestroy {
-			data.Summary.MissingCheckDestroy++
+			summary.MissingCheckDestroy++
 		}
 	}
-	data.Summary.TotalResources = len(resources)
+	summary.TotalResources = len(resources)
 
-	// Build data source reports
 	for _, info := range dataSources {
 		report := buildResourceReport(reg, info)
-		data.DataSources = append(data.DataSources, report)
+		linkReport(&report, reg, info, link)
+		onDefinition(registry.KindDataSource, report)
+		summary.add(report)
+		if report.AtRisk() {
+			summary.CoverageAtRisk++
+		}
 		if report.TestCount == 0 {
-			data.Summary.UntestedDataSources++
+			summary.UntestedDataSources++
 		}
 	}
-	data.Summary.TotalDataSources = len(dataSources)
+	summary.TotalDataSources = len(dataSources)
 
-	// Build action reports
 	for _, info := range actions {
 		report := buildActionReport(reg, info)
-		data.Actions = append(data.Actions, report)
+		linkReport(&report, reg, info, link)
+		onDefinition(registry.KindAction, report)
+		summary.add(report)
+		if report.AtRisk() {
+			summary.CoverageAtRisk++
+		}
 		if report.TestCount == 0 {
-			data.Summary.UntestedActions++
+			summary.UntestedActions++
 		} else if !report.HasCheck && !report.HasConfigStateChecks {
-			data.Summary.MissingStateChecks++
+			summary.MissingStateChecks++
+		}
+	}
+	summary.TotalActions = len(actions)
+
+	for _, fn := range reg.GetAllTestFunctions() {
+		if fn.Quarantined {
+			summary.QuarantinedTests++
 		}
 	}
-	data.Summary.TotalActions = len(actions)
 
-	// Build orphan reports
 	for _, fn := range orphans {
-		data.Orphans = append(data.Orphans, OrphanReport{
+		onOrphan(OrphanReport{
 			Name:              fn.Name,
 			File:              filepath.Base(fn.FilePath),
+			URL:               testURL(fn, link),
 			InferredResources: fn.InferredResources,
 		})
 	}
-	data.Summary.OrphanTests = len(orphans)
+	summary.OrphanTests = len(orphans)
+
+	return summary
+}
+
+func outputReportJSON(reg *registry.ResourceRegistry, resources, dataSources, actions []*registry.ResourceInfo, orphans []*registry.TestFunctionInfo, discovery *livediscovery.Result, age *gitmeta.Report, roles []FileRoleReport, maturity []MaturityReport, categories []CategoryReport, health *RunHealth, link links.Linker) {
+	data := ReportData{Discovery: discovery, Age: age, FileRoles: roles, Health: health, TopRisks: analysis.TopRisksOf(reg, topRiskLimit, resources, dataSources, actions)}
+	data.Summary = walkReport(reg, resources, dataSources, actions, orphans, link,
+		func(kind registry.ResourceKind, report ResourceReport) {
+			switch kind {
+			case registry.KindResource:
+				data.Resources = append(data.Resources, report)
+			case registry.KindDataSource:
+				data.DataSources = append(data.DataSources, report)
+			case registry.KindAction:
+				data.Actions = append(data.Actions, report)
+			}
+		},
+		func(report OrphanReport) {
+			data.Orphans = append(data.Orphans, report)
+		})
+	data.Summary.Maturity = maturity
+	data.Summary.Categories = categories
 
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
@@ -732,14 +1712,13 @@ func outputReportJSON(reg *registry.ResourceRegistry, resources, dataSources, ac
 	}
 }
 
-func outputReportTable(reg *registry.ResourceRegistry, resources, dataSources, actions []*registry.ResourceInfo, orphans []*registry.TestFunctionInfo) {
+func outputReportTable(reg *registry.ResourceRegistry, resources, dataSources, actions []*registry.ResourceInfo, orphans []*registry.TestFunctionInfo, discovery *livediscovery.Result, age *gitmeta.Report, roles []FileRoleReport, maturity []MaturityReport, categories []CategoryReport, view reportView) {
 	// Calculate summary stats first
 	var untestedResources, untestedDataSources, untestedActions int
 	var missingCheckDestroy, missingStateCheck int
 
 	for _, info := range resources {
-		key := registry.KindResource.String() + ":" + info.Name
-		tests := reg.GetResourceTests(key)
+		tests := reg.GetTests(registry.KindResource, info.Name)
 		if len(tests) == 0 {
 			untestedResources++
 		} else {
@@ -757,22 +1736,20 @@ func outputReportTable(reg *registry.ResourceRegistry, resources, dataSources, a
 	}
 
 	for _, info := range dataSources {
-		key := registry.KindDataSource.String() + ":" + info.Name
-		tests := reg.GetResourceTests(key)
+		tests := reg.GetTests(registry.KindDataSource, info.Name)
 		if len(tests) == 0 {
 			untestedDataSources++
 		}
 	}
 
 	for _, info := range actions {
-		key := registry.KindAction.String() + ":" + info.Name
-		tests := reg.GetResourceTests(key)
+		tests := reg.GetTests(registry.KindAction, info.Name)
 		if len(tests) == 0 {
 			untestedActions++
 		} else {
 			hasStateCheck := false
 			for _, t := range tests {
-				if t.HasStateOrPlanCheck() {
+				if t.HasStateOrPlanCheck() || t.HasOpaqueSteps {
 					hasStateCheck = true
 					break
 				}
@@ -785,44 +1762,56 @@ func outputReportTable(reg *registry.ResourceRegistry, resources, dataSources, a
 
 	// Print header
 	fmt.Println()
-	fmt.Println("╔════════════════════════════════════════════════════════════════════════════════╗")
-	fmt.Println("║                        TERRAFORM PROVIDER TEST COVERAGE REPORT                 ║")
-	fmt.Println("╚════════════════════════════════════════════════════════════════════════════════╝")
+	view.banner("TERRAFORM PROVIDER TEST COVERAGE REPORT")
+	fmt.Printf("Test layout: %s\n", analysis.DetectTestLayout(reg))
 
 	// Summary table
 	fmt.Println()
-	fmt.Println("┌─────────────────────────────────────────────────────────────────────────────────┐")
-	fmt.Println("│ SUMMARY                                                                         │")
-	fmt.Println("├──────────────┬───────┬──────────┬─────────────────────────────────────────────────┤")
-	fmt.Println("│ Category     │ Total │ Untested │ Issues                                          │")
-	fmt.Println("├──────────────┼───────┼──────────┼─────────────────────────────────────────────────┤")
-	fmt.Printf("│ Resources    │ %5d │ %8d │ %d without CheckDestroy                          │\n", len(resources), untestedResources, missingCheckDestroy)
-	fmt.Printf("│ Data Sources │ %5d │ %8d │ -                                               │\n", len(dataSources), untestedDataSources)
-	fmt.Printf("│ Actions      │ %5d │ %8d │ %d without Check func                            │\n", len(actions), untestedActions, missingStateCheck)
-	fmt.Printf("│ Orphan Tests │ %5d │        - │ -                                               │\n", len(orphans))
-	fmt.Println("└──────────────┴───────┴──────────┴─────────────────────────────────────────────────┘")
+	summary := [][]string{
+		{"Resources", strconv.Itoa(len(resources)), strconv.Itoa(untestedResources), fmt.Sprintf("%d without CheckDestroy", missingCheckDestroy)},
+		{"Data Sources", strconv.Itoa(len(dataSources)), strconv.Itoa(untestedDataSources), "-"},
+		{"Actions", strconv.Itoa(len(actions)), strconv.Itoa(untestedActions), fmt.Sprintf("%d without Check func", missingStateCheck)},
+		{"Orphan Tests", strconv.Itoa(len(orphans)), "-", "-"},
+	}
+	if quarantined, atRisk := quarantineCounts(reg, resources, dataSources, actions); quarantined > 0 {
+		summary = append(summary, []string{"Quarantined", strconv.Itoa(quarantined), "-", fmt.Sprintf("%d definitions with coverage at risk", atRisk)})
+	}
+	if weak, weaklyTested := weakCounts(reg, resources, dataSources, actions); weak > 0 {
+		summary = append(summary, []string{"Weak Tests", strconv.Itoa(weak), "-", fmt.Sprintf("%d definitions with no asserting test", weaklyTested)})
+	}
+	if conditional, conditionallyTested := conditionalCounts(reg, resources, dataSources, actions); conditional > 0 {
+		summary = append(summary, []string{"Conditional Tests", strconv.Itoa(conditional), "-", fmt.Sprintf("%d definitions covered only conditionally", conditionallyTested)})
+	}
+	view.grid("SUMMARY", []string{"Category", "Total", "Untested", "Issues"}, summary)
+	printMaturityTable(maturity, view)
+	printCategoryTable(categories, view)
+	printTopRisks(analysis.TopRisksOf(reg, topRiskLimit, resources, dataSources, actions), view)
 
 	// Resources table
 	if len(resources) > 0 {
 		fmt.Println()
-		fmt.Println("┌─────────────────────────────────────────────────────────────────────────────────┐")
-		fmt.Println("│ RESOURCES                                                                       │")
-		fmt.Println("└─────────────────────────────────────────────────────────────────────────────────┘")
-		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-		fmt.Fprintln(w, "  NAME\tTESTS\tUpdate\tImportState\tCheckDestroy\tExpectError\tCheck\tConfigStateChecks\tPlanChecks\tFILE\tTEST FILE")
-		fmt.Fprintln(w, "  ────\t─────\t──────\t───────────\t────────────\t───────────\t─────\t─────────────────\t──────────\t────\t─────────")
+		view.section("RESOURCES")
+		w := view.newTable()
+		extraHeaders := view.resourceColumns()
+		extraRules := make([]string, len(extraHeaders))
+		for i, header := range extraHeaders {
+			extraRules[i] = strings.Repeat("─", len(header))
+		}
+		fmt.Fprintf(w, "  NAME\tTESTS%s\tUpdate\tImportState\tCheckDestroy\tExpectError\tCheck\tConfigStateChecks\tPlanChecks\tFILE\tTEST FILE\n", tabCells(extraHeaders))
+		fmt.Fprintf(w, "  ────\t─────%s\t──────\t───────────\t────────────\t───────────\t─────\t─────────────────\t──────────\t────\t─────────\n", tabCells(extraRules))
 		for _, info := range resources {
 			report := buildResourceReport(reg, info)
-			fmt.Fprintf(w, "  %s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			fmt.Fprintf(w, "  %s\t%s%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
 				info.Name,
-				report.TestCount,
-				checkMark(report.HasUpdateTest),
-				checkMark(report.HasImportTest),
+				testsCell(report),
+				tabCells(view.resourceCells(report)),
+				stepMark(report.HasUpdateTest, report.HasOpaqueSteps),
+				stepMark(report.HasImportTest, report.HasOpaqueSteps),
 				checkMark(report.HasCheckDestroy),
-				checkMark(report.HasExpectError),
-				checkMark(report.HasCheck),
-				checkMark(report.HasConfigStateChecks),
-				checkMark(report.HasPlanCheck),
+				stepMark(report.HasExpectError, report.HasOpaqueSteps),
+				stepMark(report.HasCheck, report.HasOpaqueSteps),
+				stepMark(report.HasConfigStateChecks, report.HasOpaqueSteps),
+				stepMark(report.HasPlanCheck, report.HasOpaqueSteps),
 				report.File,
 				report.TestFile,
 			)
@@ -833,19 +1822,17 @@ func outputReportTable(reg *registry.ResourceRegistry, resources, dataSources, a
 	// Data Sources table
 	if len(dataSources) > 0 {
 		fmt.Println()
-		fmt.Println("┌─────────────────────────────────────────────────────────────────────────────────┐")
-		fmt.Println("│ DATA SOURCES                                                                    │")
-		fmt.Println("└─────────────────────────────────────────────────────────────────────────────────┘")
-		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		view.section("DATA SOURCES")
+		w := view.newTable()
 		fmt.Fprintln(w, "  NAME\tTESTS\tCheck\tConfigStateChecks\tFILE\tTEST FILE")
 		fmt.Fprintln(w, "  ────\t─────\t─────\t─────────────────\t────\t─────────")
 		for _, info := range dataSources {
 			report := buildResourceReport(reg, info)
-			fmt.Fprintf(w, "  %s\t%d\t%s\t%s\t%s\t%s\n",
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\t%s\n",
 				info.Name,
-				report.TestCount,
-				checkMark(report.HasCheck),
-				checkMark(report.HasConfigStateChecks),
+				testsCell(report),
+				stepMark(report.HasCheck, report.HasOpaqueSteps),
+				stepMark(report.HasConfigStateChecks, report.HasOpaqueSteps),
 				report.File,
 				report.TestFile,
 			)
@@ -853,24 +1840,24 @@ func outputReportTable(reg *registry.ResourceRegistry, resources, dataSources, a
 		w.Flush()
 	}
 
+	outputPairsTable(reg, resources, dataSources, view)
+
 	// Actions table
 	if len(actions) > 0 {
 		fmt.Println()
-		fmt.Println("┌─────────────────────────────────────────────────────────────────────────────────┐")
-		fmt.Println("│ ACTIONS                                                                         │")
-		fmt.Println("└─────────────────────────────────────────────────────────────────────────────────┘")
-		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		view.section("ACTIONS")
+		w := view.newTable()
 		fmt.Fprintln(w, "  NAME\tTESTS\tUpdate\tExpectError\tCheck\tConfigStateChecks\tPreCheck\tFILE\tTEST FILE")
 		fmt.Fprintln(w, "  ────\t─────\t──────\t───────────\t─────\t─────────────────\t────────\t────\t─────────")
 		for _, info := range actions {
 			report := buildActionReport(reg, info)
-			fmt.Fprintf(w, "  %s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
 				info.Name,
-				report.TestCount,
-				checkMark(report.HasUpdateTest),
-				checkMark(report.HasExpectError),
-				checkMark(report.HasCheck),
-				checkMark(report.HasConfigStateChecks),
+				testsCell(report),
+				stepMark(report.HasUpdateTest, report.HasOpaqueSteps),
+				stepMark(report.HasExpectError, report.HasOpaqueSteps),
+				stepMark(report.HasCheck, report.HasOpaqueSteps),
+				stepMark(report.HasConfigStateChecks, report.HasOpaqueSteps),
 				checkMark(report.HasPreCheck),
 				report.File,
 				report.TestFile,
@@ -881,13 +1868,11 @@ func outputReportTable(reg *registry.ResourceRegistry, resources, dataSources, a
 
 	// Orphans table
 	fmt.Println()
-	fmt.Println("┌─────────────────────────────────────────────────────────────────────────────────┐")
-	fmt.Println("│ ORPHAN TESTS                                                                    │")
-	fmt.Println("└─────────────────────────────────────────────────────────────────────────────────┘")
+	view.section("ORPHAN TESTS")
 	if len(orphans) == 0 {
-		fmt.Println("  ✓ All test functions are associated with resources!")
+		view.printf("  ✓ All test functions are associated with resources!\n")
 	} else {
-		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		w := view.newTable()
 		fmt.Fprintln(w, "  TEST FUNCTION\tFILE\tINFERRED RESOURCES")
 		fmt.Fprintln(w, "  ─────────────\t────\t──────────────────")
 		for _, fn := range orphans {
@@ -902,10 +1887,8 @@ func outputReportTable(reg *registry.ResourceRegistry, resources, dataSources, a
 
 	// Test details table
 	fmt.Println()
-	fmt.Println("┌─────────────────────────────────────────────────────────────────────────────────┐")
-	fmt.Println("│ TEST ASSOCIATIONS                                                               │")
-	fmt.Println("└─────────────────────────────────────────────────────────────────────────────────┘")
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	view.section("TEST ASSOCIATIONS")
+	w := view.newTable()
 	fmt.Fprintln(w, "  RESOURCE\tKIND\tTEST FUNCTION\tMATCH TYPE")
 	fmt.Fprintln(w, "  ────────\t────\t─────────────\t──────────")
 
@@ -926,8 +1909,7 @@ func outputReportTable(reg *registry.ResourceRegistry, resources, dataSources, a
 	}
 
 	for _, def := range allDefs {
-		key := def.info.Kind.String() + ":" + def.info.Name
-		tests := reg.GetResourceTests(key)
+		tests := reg.GetTests(def.info.Kind, def.info.Name)
 		if len(tests) == 0 {
 			fmt.Fprintf(w, "  %s\t%s\t-\t-\n", def.info.Name, def.kind)
 		} else {
@@ -943,7 +1925,115 @@ func outputReportTable(reg *registry.ResourceRegistry, resources, dataSources, a
 		}
 	}
 	w.Flush()
+
+	outputErrorPathsTable(reg, allDefinitions(resources, dataSources, actions), view)
+	outputQuarantineTable(reg, allDefinitions(resources, dataSources, actions), view)
+	outputConditionalTable(reg, allDefinitions(resources, dataSources, actions), view)
+	outputLoadBearingTable(reg, allDefinitions(resources, dataSources, actions), view)
+	outputCheckMigrationTable(reg, allDefinitions(resources, dataSources, actions), view)
+
+	if discovery != nil {
+		outputDiscoveryTable(discovery, view)
+	}
+	if age != nil {
+		outputAgeTable(age, view)
+	}
+	if len(roles) > 0 {
+		outputFileRolesTable(roles, view)
+	}
+	fmt.Println()
+}
+
+// outputDiscoveryTable prints how completely static discovery matched the compiled provider
+func outputDiscoveryTable(discovery *livediscovery.Result, view reportView) {
+	fmt.Println()
+	view.section("DISCOVERY CONFIDENCE")
+	fmt.Printf("  Provider: %s\n", discovery.ProviderAddress)
+	fmt.Printf("  Static discovery found %d of %d registered types (%.0f%%)\n",
+		discovery.Matched, discovery.LiveTotal, discovery.Confidence*100)
+	for _, key := range discovery.MissingInStatic {
+		view.printf("  ✗ missed by static discovery: %s\n", key)
+	}
+	for _, key := range discovery.UnknownToLive {
+		fmt.Printf("  ? not registered by provider: %s\n", key)
+	}
+}
+
+// outputAgeTable prints coverage grouped by definition age, then the untested definitions
+// that changed most recently
+// buildFileRoleReports counts scanned files by role, in fileroles.Roles order
+func buildFileRoleReports(fset *token.FileSet, files []*ast.File, settings config.Settings) []FileRoleReport {
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		paths = append(paths, fset.Position(file.Pos()).Filename)
+	}
+	counts := discovery.FileClassifier(settings).Counts(paths)
+
+	var reports []FileRoleReport
+	for _, role := range fileroles.Roles() {
+		if counts[role] == 0 {
+			continue
+		}
+		reports = append(reports, FileRoleReport{
+			Role:          string(role),
+			Files:         counts[role],
+			ExcludesDefs:  settings.ExcludesFileRole(role, false),
+			ExcludesTests: settings.ExcludesFileRole(role, true),
+		})
+	}
+	return reports
+}
+
+// outputFileRolesTable prints the file role counts
+func outputFileRolesTable(roles []FileRoleReport, view reportView) {
+	fmt.Println()
+	view.section("FILE ROLES")
+	w := view.newTable()
+	fmt.Fprintln(w, "  ROLE\tFILES\tDEFINITIONS\tTESTS")
+	fmt.Fprintln(w, "  ----\t-----\t-----------\t-----")
+	for _, r := range roles {
+		fmt.Fprintf(w, "  %s\t%d\t%s\t%s\n", r.Role, r.Files, analyzedLabel(!r.ExcludesDefs), analyzedLabel(!r.ExcludesTests))
+	}
+	w.Flush()
+}
+
+// analyzedLabel describes whether a file role is analyzed or skipped
+func analyzedLabel(analyzed bool) string {
+	if analyzed {
+		return "analyzed"
+	}
+	return "skipped"
+}
+
+func outputAgeTable(age *gitmeta.Report, view reportView) {
 	fmt.Println()
+	view.section("COVERAGE BY LAST MODIFIED")
+	w := view.newTable()
+	fmt.Fprintln(w, "  MODIFIED\tTOTAL\tTESTED\tUNTESTED\tCOVERAGE")
+	fmt.Fprintln(w, "  --------\t-----\t------\t--------\t--------")
+	for _, bucket := range age.Buckets {
+		coverage := "-"
+		if bucket.Total > 0 {
+			coverage = fmt.Sprintf("%.0f%%", bucket.Coverage*100)
+		}
+		fmt.Fprintf(w, "  %s\t%d\t%d\t%d\t%s\n", bucket.Label, bucket.Total, bucket.Tested, bucket.Untested, coverage)
+	}
+	w.Flush()
+	if age.Unknown > 0 {
+		fmt.Printf("  %d definition(s) have no git history and are not included\n", age.Unknown)
+	}
+
+	if len(age.Untested) == 0 {
+		return
+	}
+	fmt.Println()
+	fmt.Println("  Untested, most recently modified first:")
+	w = view.newTable()
+	for _, entry := range age.Untested {
+		fmt.Fprintf(w, "  ✗ %s\t%s\t%s\t%s (%d days ago)\n",
+			entry.Name, entry.Kind, filepath.Base(entry.File), entry.LastModified.Format("2006-01-02"), entry.AgeDays)
+	}
+	w.Flush()
 }
 
 func checkMark(b bool) string {
@@ -953,53 +2043,83 @@ func checkMark(b bool) string {
 	return "✗"
 }
 
-// findAllGoPackageDirs recursively finds all directories containing Go files
-func findAllGoPackageDirs(root string) []string {
-	var dirs []string
-	seen := make(map[string]bool)
-
-	// Directories to exclude from scanning
-	excludeDirs := map[string]bool{
-		"vendor":       true,
-		"testdata":     true,
-		".git":         true,
-		".github":      true,
-		"node_modules": true,
-		".terraform":   true,
+// testsCell is the TESTS cell of a definition: its test count, followed by the number of
+// scenarios when subtests run TestCases of their own.
+func testsCell(report ResourceReport) string {
+	if report.ScenarioCount > 0 {
+		return fmt.Sprintf("%d (%d scenarios)", report.TestCount, report.ScenarioCount)
 	}
+	return strconv.Itoa(report.TestCount)
+}
 
-	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return nil // Skip directories we can't access
+// subtestNames returns the names of a test's subtests for reports, "?" for a name not known
+// statically.
+func subtestNames(test *registry.TestFunctionInfo) []string {
+	var names []string
+	for _, subtest := range test.Subtests {
+		if subtest.Name == "" {
+			names = append(names, "?")
+		} else {
+			names = append(names, subtest.Name)
 		}
+	}
+	return names
+}
 
-		if d.IsDir() {
-			// Skip excluded directories
-			if excludeDirs[d.Name()] {
-				return filepath.SkipDir
-			}
-			return nil
-		}
+// stepMark is checkMark for a step-level pattern, shown as "?" rather than missing when some
+// steps could not be resolved statically.
+func stepMark(b, opaque bool) string {
+	if !b && opaque {
+		return "?"
+	}
+	return checkMark(b)
+}
 
-		// Check if this is a Go file
-		if !strings.HasSuffix(d.Name(), ".go") {
-			return nil
-		}
+// findAllGoPackageDirs recursively finds all directories containing Go files.
+// The returned manifest also records every skipped directory and why it was skipped.
+func findAllGoPackageDirs(root string, opts scan.Options) *scan.Manifest {
+	return scan.GoPackageDirs(root, opts)
+}
 
-		// Add the directory containing this Go file
-		dir := filepath.Dir(path)
-		if !seen[dir] {
-			seen[dir] = true
-			dirs = append(dirs, dir)
+// writeScanManifest writes the scan manifest as JSON to path, or to stderr if path is "-".
+func writeScanManifest(manifest *scan.Manifest, path string) error {
+	out := os.Stderr
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
 		}
-		return nil
-	})
+		defer f.Close()
+		out = f
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(manifest)
+}
 
-	if err != nil {
-		return nil
+// printSkippedFiles reports on stderr the files left out by -max-file-size-kb, each by name,
+// and how many -max-files left out
+func printSkippedFiles(skipped []scan.SkippedFile) {
+	tooMany, maxFiles := 0, 0
+	for _, skip := range skipped {
+		if skip.Reason == scan.ReasonTooManyFiles {
+			tooMany, maxFiles = tooMany+1, skip.Limit
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "Warning: Skipped %s: %d KB exceeds -max-file-size-kb %d (use -force to analyze it)\n", skip.Path, skip.SizeKB, skip.Limit)
+	}
+	if tooMany > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: Skipped %d Go file(s) past -max-files %d; results are partial (use -force to analyze every file)\n", tooMany, maxFiles)
 	}
+}
 
-	// Sort directories for consistent output
-	sort.Strings(dirs)
-	return dirs
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
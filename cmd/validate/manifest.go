@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/internal/requirements"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// ManifestSyncOutput is the JSON output of -sync-manifest
+type ManifestSyncOutput struct {
+	Path    string              `json:"path"`
+	Created bool                `json:"created"`
+	Added   []ManifestSyncEntry `json:"added"`
+	Stale   []ManifestSyncEntry `json:"stale"`
+}
+
+// ManifestSyncEntry is a definition added to, or stale in, the requirements manifest
+type ManifestSyncEntry struct {
+	Kind    string   `json:"kind"`
+	Name    string   `json:"name"`
+	Require []string `json:"require,omitempty"`
+}
+
+// runSyncManifest adds a scaffolded entry to the requirements manifest for every discovered
+// definition it does not list, creating the manifest at the provider root if there is none
+func runSyncManifest(fset *token.FileSet, files []*ast.File, settings config.Settings, format string, providerPath string) {
+	if format != "text" && format != "json" {
+		fmt.Printf("Error: Invalid format '%s' for -sync-manifest. Must be one of: text, json\n", format)
+		exit(1)
+	}
+	if settings.RequirementsManifest == requirements.Off {
+		fmt.Println("Error: -sync-manifest cannot be used with -requirements-manifest off")
+		exit(1)
+	}
+
+	// Look the manifest up the way the analyzers do, from the scanned code upward
+	path := requirements.Resolve(settings.RequirementsManifest, filepath.Dir(fset.Position(files[0].Pos()).Filename))
+	if path == "" {
+		path = filepath.Join(providerPath, requirements.ManifestFile)
+	}
+
+	reg := buildRegistryFromFiles(fset, files, settings)
+	scaffolded := make(map[registry.ResourceKey]requirements.Entry)
+	result, err := requirements.Sync(path, reg.GetSortedDefinitions(), func(def *registry.ResourceInfo) requirements.Entry {
+		entry := scaffoldRequirements(def)
+		scaffolded[def.Key()] = entry
+		return entry
+	})
+	if err != nil {
+		fmt.Printf("Error: Could not sync requirements manifest: %v\n", err)
+		exit(1)
+	}
+
+	output := ManifestSyncOutput{
+		Path:    result.Path,
+		Created: result.Created,
+		Added:   []ManifestSyncEntry{},
+		Stale:   []ManifestSyncEntry{},
+	}
+	for _, key := range result.Added {
+		output.Added = append(output.Added, ManifestSyncEntry{Kind: key.Kind.String(), Name: key.Name, Require: scaffolded[key].Require})
+	}
+	for _, key := range result.Stale {
+		output.Stale = append(output.Stale, ManifestSyncEntry{Kind: key.Kind.String(), Name: key.Name})
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		}
+		return
+	}
+	outputManifestSyncText(output)
+}
+
+// scaffoldRequirements proposes a manifest entry for a definition, requiring the coverage
+// the rules already ask of it so that syncing does not introduce new findings. Stronger
+// requirements such as disappears tests are left for maintainers to add.
+func scaffoldRequirements(def *registry.ResourceInfo) requirements.Entry {
+	entry := requirements.Entry{Require: []string{registry.CheckBasic}}
+	if def.Kind != registry.KindResource {
+		return entry
+	}
+
+	updatable, validated := false, false
+	for _, attr := range def.Attributes {
+		if analysis.IsAttributeUpdatable(attr) {
+			updatable = true
+		}
+		if attr.NeedsValidationTest() {
+			validated = true
+		}
+	}
+	if def.Operations != nil && !def.Operations.Update {
+		updatable = false
+	}
+
+	if updatable {
+		entry.Require = append(entry.Require, registry.CheckUpdate)
+	}
+	if def.HasImportState {
+		entry.Require = append(entry.Require, registry.CheckImport)
+	}
+	if validated {
+		entry.Require = append(entry.Require, registry.CheckError)
+	}
+	return entry
+}
+
+// outputManifestSyncText prints the result of -sync-manifest for humans
+func outputManifestSyncText(output ManifestSyncOutput) {
+	switch {
+	case output.Created:
+		fmt.Printf("Created %s with %d entries\n", output.Path, len(output.Added))
+	case len(output.Added) > 0:
+		fmt.Printf("Updated %s: %d entries added\n", output.Path, len(output.Added))
+	default:
+		fmt.Printf("%s lists every discovered definition\n", output.Path)
+	}
+	for _, entry := range output.Added {
+		fmt.Printf("  + %s %s: require [%s]\n", entry.Kind, entry.Name, strings.Join(entry.Require, ", "))
+	}
+	if len(output.Stale) > 0 {
+		fmt.Printf("\n%d entries name no discovered definition (left in place; remove them if the definition was deleted):\n", len(output.Stale))
+		for _, entry := range output.Stale {
+			fmt.Printf("  ? %s %s\n", entry.Kind, entry.Name)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// untaggedMaturity labels definitions without a maturity level in the maturity breakdown.
+const untaggedMaturity = "untagged"
+
+// MaturityReport summarizes the definitions at one maturity level
+type MaturityReport struct {
+	Level        string   `json:"level"`
+	Definitions  int      `json:"definitions"`
+	Untested     int      `json:"untested"`
+	ExemptChecks []string `json:"exempt_checks,omitempty"` // Checks maturity-exemptions waives at this level
+}
+
+// buildMaturitySummary counts definitions and untested definitions per maturity level, from
+// least to most mature, followed by untagged definitions. It returns nil when no definition
+// is tagged, so reports of providers that do not use maturity levels are unchanged.
+func buildMaturitySummary(reg *registry.ResourceRegistry, settings config.Settings, groups ...[]*registry.ResourceInfo) []MaturityReport {
+	counts := make(map[string]*MaturityReport)
+	tagged := false
+	for _, defs := range groups {
+		for _, info := range defs {
+			level := info.Maturity
+			if level == "" {
+				level = untaggedMaturity
+			} else {
+				tagged = true
+			}
+			if counts[level] == nil {
+				counts[level] = &MaturityReport{Level: level, ExemptChecks: settings.MaturityExemptionsFor(info.Maturity)}
+			}
+			counts[level].Definitions++
+			if len(reg.GetTests(info.Kind, info.Name)) == 0 {
+				counts[level].Untested++
+			}
+		}
+	}
+	if !tagged {
+		return nil
+	}
+
+	var summary []MaturityReport
+	for _, level := range append(registry.MaturityLevels(), untaggedMaturity) {
+		if report := counts[level]; report != nil {
+			summary = append(summary, *report)
+		}
+	}
+	return summary
+}
+
+// printMaturityTable prints the maturity breakdown of the report summary.
+func printMaturityTable(summary []MaturityReport, view reportView) {
+	if len(summary) == 0 {
+		return
+	}
+	fmt.Println()
+	view.section("MATURITY")
+	w := view.newTable()
+	fmt.Fprintln(w, "  LEVEL\tTOTAL\tUNTESTED\tEXEMPT CHECKS")
+	fmt.Fprintln(w, "  ─────\t─────\t────────\t─────────────")
+	for _, report := range summary {
+		exempt := "-"
+		if len(report.ExemptChecks) > 0 {
+			exempt = strings.Join(report.ExemptChecks, ", ")
+		}
+		fmt.Fprintf(w, "  %s\t%d\t%d\t%s\n", report.Level, report.Definitions, report.Untested, exempt)
+	}
+	w.Flush()
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/example/tfprovidertest/pkg/metrics"
+)
+
+// runMetrics is the metrics command: it aggregates exported report and run JSON files into
+// time series, without scanning a provider
+func runMetrics(args []string) {
+	fs := flag.NewFlagSet("metrics", flag.ExitOnError)
+	bucketName := fs.String("bucket", string(metrics.Week), "Time bucket: day, week or month")
+	format := fs.String("format", "text", "Output format: text or json")
+	fs.Usage = func() {
+		fmt.Println("Usage: validate metrics [-bucket day|week|month] [-format text|json] <file.json>...")
+		fmt.Println()
+		fmt.Println("Aggregates exported coverage reports (-report -format json) and analyzer runs")
+		fmt.Println("(-format json) into per-kind coverage, per-service coverage and rule frequency")
+		fmt.Println("over time. Files are dated by a YYYY-MM-DD or YYYYMMDD date in their name, or")
+		fmt.Println("else by their modification time.")
+		fmt.Println()
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	bucket, err := metrics.ParseBucket(*bucketName)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Printf("Error: Invalid format '%s' for metrics. Must be one of: text, json\n", *format)
+		exit(1)
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		exit(1)
+	}
+
+	var snaps []metrics.Snapshot
+	for _, path := range fs.Args() {
+		snap, err := metrics.Load(path)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(1)
+		}
+		snaps = append(snaps, snap)
+	}
+	agg := metrics.Aggregate(snaps, bucket)
+
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(agg); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		}
+		return
+	}
+	outputMetricsText(agg)
+}
+
+// outputMetricsText prints each series as a table
+func outputMetricsText(agg metrics.Aggregation) {
+	const dateFormat = "2006-01-02"
+	fmt.Printf("Coverage by kind (per %s)\n", agg.Bucket)
+	fmt.Printf("  %-10s  %-12s  %6s  %6s  %8s\n", "BUCKET", "KIND", "TOTAL", "TESTED", "COVERAGE")
+	for _, p := range agg.KindCoverage {
+		fmt.Printf("  %-10s  %-12s  %6d  %6d  %7.1f%%\n", p.Bucket.Format(dateFormat), p.Key, p.Total, p.Tested, p.Coverage*100)
+	}
+
+	fmt.Printf("\nCoverage by service (per %s)\n", agg.Bucket)
+	fmt.Printf("  %-10s  %-20s  %6s  %6s  %8s  %8s\n", "BUCKET", "SERVICE", "TOTAL", "TESTED", "COVERAGE", "FINDINGS")
+	for _, p := range agg.ServiceCoverage {
+		fmt.Printf("  %-10s  %-20s  %6d  %6d  %7.1f%%  %8d\n", p.Bucket.Format(dateFormat), p.Key, p.Total, p.Tested, p.Coverage*100, p.Findings)
+	}
+
+	fmt.Printf("\nRule frequency (per %s)\n", agg.Bucket)
+	fmt.Printf("  %-10s  %-45s  %8s\n", "BUCKET", "RULE", "FINDINGS")
+	for _, p := range agg.RuleFrequency {
+		fmt.Printf("  %-10s  %-45s  %8d\n", p.Bucket.Format(dateFormat), p.Rule, p.Findings)
+	}
+}
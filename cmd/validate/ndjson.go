@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/gitmeta"
+	"github.com/example/tfprovidertest/internal/links"
+	"github.com/example/tfprovidertest/internal/livediscovery"
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// NDJSON record types. Every line of -format ndjson output is one record. Definitions are
+// recorded under the key of their kind: resource, data_source or action.
+const (
+	recordFinding    = "finding"
+	recordOrphanTest = "orphan_test"
+	recordFileRole   = "file_role"
+	recordDiscovery  = "discovery"
+	recordAge        = "age"
+	recordRisk       = "risk"
+	recordGate       = "gate"
+	recordHealth     = "health"
+	recordSummary    = "summary"
+)
+
+// NDJSONRecord is one line of -format ndjson output: a record type and its payload, which
+// has the same shape as the matching entry of the -format json document
+type NDJSONRecord struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+// ndjsonWriter writes records one per line as they are produced, so consumers can start
+// processing before the run finishes. Only the first write error is kept.
+type ndjsonWriter struct {
+	enc *json.Encoder
+	err error
+}
+
+// newNDJSONWriter returns a writer that emits records to out
+func newNDJSONWriter(out io.Writer) *ndjsonWriter {
+	return &ndjsonWriter{enc: json.NewEncoder(out)}
+}
+
+// write emits a single record
+func (w *ndjsonWriter) write(recordType string, data interface{}) {
+	if w.err != nil {
+		return
+	}
+	w.err = w.enc.Encode(NDJSONRecord{Type: recordType, Data: data})
+}
+
+// definitionRecordType returns the record type of a definition report: the key of its kind,
+// such as "data_source"
+func definitionRecordType(kind registry.ResourceKind) string {
+	return kind.Key()
+}
+
+// outputReportNDJSON streams the coverage report: one record per definition and orphan test
+// as it is built, then file roles, live discovery and age results, top risks, run health, and
+// the summary last
+func outputReportNDJSON(reg *registry.ResourceRegistry, resources, dataSources, actions []*registry.ResourceInfo, orphans []*registry.TestFunctionInfo, discovery *livediscovery.Result, age *gitmeta.Report, roles []FileRoleReport, maturity []MaturityReport, categories []CategoryReport, health *RunHealth, link links.Linker) {
+	w := newNDJSONWriter(os.Stdout)
+
+	summary := walkReport(reg, resources, dataSources, actions, orphans, link,
+		func(kind registry.ResourceKind, report ResourceReport) {
+			w.write(definitionRecordType(kind), report)
+		},
+		func(report OrphanReport) {
+			w.write(recordOrphanTest, report)
+		})
+	summary.Maturity = maturity
+	summary.Categories = categories
+
+	for _, role := range roles {
+		w.write(recordFileRole, role)
+	}
+	if discovery != nil {
+		w.write(recordDiscovery, discovery)
+	}
+	if age != nil {
+		w.write(recordAge, age)
+	}
+	for _, risk := range analysis.TopRisksOf(reg, topRiskLimit, resources, dataSources, actions) {
+		w.write(recordRisk, risk)
+	}
+	if health != nil {
+		w.write(recordHealth, health)
+	}
+	w.write(recordSummary, summary)
+
+	if w.err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", w.err)
+	}
+}
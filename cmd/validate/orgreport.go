@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/example/tfprovidertest/pkg/metrics"
+)
+
+// defaultOrgTop is how many worst providers and top risks an organization report lists
+const defaultOrgTop = 10
+
+// runOrgReport is the org-report command: it merges the exported coverage reports of many
+// providers into one organization report, without scanning a provider
+func runOrgReport(args []string) {
+	fs := flag.NewFlagSet("org-report", flag.ExitOnError)
+	inputs := fs.String("inputs", "", "Comma-separated coverage reports (-report -format json), each optionally prefixed with its provider name as name=file.json")
+	top := fs.Int("top", defaultOrgTop, "How many worst providers and untested definitions to list")
+	format := fs.String("format", "text", "Output format: text or json")
+	fs.Usage = func() {
+		fmt.Println("Usage: validate org-report -inputs <[name=]file.json>,... [-top n] [-format text|json]")
+		fmt.Println()
+		fmt.Println("Merges the exported coverage reports of many provider repositories into per-provider")
+		fmt.Println("coverage, organization totals, the providers with the lowest coverage and the most")
+		fmt.Println("complex untested definitions across them. Each report is listed under the provider")
+		fmt.Println("it names, or its name= prefix, or else its file name without its date; a provider")
+		fmt.Println("given several reports is represented by its latest. Reports may also follow the flags.")
+		fmt.Println()
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *format != "text" && *format != "json" {
+		fmt.Printf("Error: Invalid format '%s' for org-report. Must be one of: text, json\n", *format)
+		exit(1)
+	}
+	if *top < 1 {
+		fmt.Printf("Error: -top must be at least 1, got %d\n", *top)
+		exit(1)
+	}
+	entries := append(splitList(*inputs), fs.Args()...)
+	if len(entries) == 0 {
+		fs.Usage()
+		exit(1)
+	}
+
+	var snaps []metrics.Snapshot
+	for _, entry := range entries {
+		snap, err := metrics.LoadNamed(entry)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(1)
+		}
+		snaps = append(snaps, snap)
+	}
+	org, err := metrics.Merge(metrics.Latest(snaps), *top)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(org); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		}
+		return
+	}
+	outputOrgReportText(org)
+}
+
+// outputOrgReportText prints the totals, each provider, and the worst offenders
+func outputOrgReportText(org metrics.Organization) {
+	t := org.Totals
+	fmt.Printf("Organization coverage: %d providers, %d definitions, %d tested (%.1f%%)\n", len(org.Providers), t.Total, t.Tested, t.Coverage*100)
+	for _, k := range t.Kinds {
+		fmt.Printf("  %-12s  %6d  %6d  %7.1f%%\n", k.Kind, k.Total, k.Tested, k.Coverage*100)
+	}
+
+	fmt.Println("\nProviders")
+	fmt.Printf("  %-24s  %6s  %6s  %8s  %8s\n", "PROVIDER", "TOTAL", "TESTED", "UNTESTED", "COVERAGE")
+	for _, p := range org.Providers {
+		fmt.Printf("  %-24s  %6d  %6d  %8d  %7.1f%%\n", p.Provider, p.Total, p.Tested, p.Untested, p.Coverage*100)
+	}
+
+	if len(org.WorstProviders) > 0 {
+		fmt.Println("\nLowest coverage")
+		for i, p := range org.WorstProviders {
+			fmt.Printf("  %d. %s: %.1f%%, %d untested\n", i+1, p.Provider, p.Coverage*100, p.Untested)
+		}
+	}
+
+	if len(org.TopRisks) > 0 {
+		fmt.Println("\nMost complex untested definitions")
+		fmt.Printf("  %-24s  %-12s  %-32s  %5s\n", "PROVIDER", "KIND", "NAME", "SCORE")
+		for _, r := range org.TopRisks {
+			fmt.Printf("  %-24s  %-12s  %-32s  %5d\n", r.Provider, r.Kind, r.Name, r.Score)
+		}
+	}
+}
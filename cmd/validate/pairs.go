@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// PairReport is the other side of a resource and data source pair
+type PairReport struct {
+	Kind      string `json:"kind"` // "resource" or "data_source"
+	TestCount int    `json:"test_count"`
+}
+
+// SingularReport identifies the singular counterpart of a plural data source.
+type SingularReport struct {
+	Kind string `json:"kind"` // "resource" or "data_source"
+	Name string `json:"name"`
+}
+
+// pairStatus describes which sides of a pair are tested
+func pairStatus(resourceTests, dataSourceTests int) string {
+	switch {
+	case resourceTests > 0 && dataSourceTests > 0:
+		return "both tested"
+	case resourceTests > 0:
+		return "data source untested"
+	case dataSourceTests > 0:
+		return "resource untested"
+	}
+	return "both untested"
+}
+
+// outputPairsTable prints the resources and data sources sharing a name side by side, with
+// the half-tested pairs first
+func outputPairsTable(reg *registry.ResourceRegistry, resources, dataSources []*registry.ResourceInfo, view reportView) {
+	listed := make(map[string]bool)
+	for _, info := range dataSources {
+		listed[info.Name] = true
+	}
+	type row struct {
+		name                           string
+		resourceTests, dataSourceTests int
+		halfTested                     bool
+	}
+	var half, rest []row
+	for _, info := range resources {
+		if !listed[info.Name] {
+			continue
+		}
+		r := row{
+			name:            info.Name,
+			resourceTests:   len(reg.GetTests(registry.KindResource, info.Name)),
+			dataSourceTests: len(reg.GetTests(registry.KindDataSource, info.Name)),
+		}
+		r.halfTested = (r.resourceTests > 0) != (r.dataSourceTests > 0)
+		if r.halfTested {
+			half = append(half, r)
+		} else {
+			rest = append(rest, r)
+		}
+	}
+	if len(half)+len(rest) == 0 {
+		return
+	}
+
+	fmt.Println()
+	view.section("RESOURCE AND DATA SOURCE PAIRS")
+	w := view.newTable()
+	fmt.Fprintln(w, "  NAME\tRESOURCE TESTS\tDATA SOURCE TESTS\tSTATUS")
+	fmt.Fprintln(w, "  ────\t──────────────\t─────────────────\t──────")
+	for _, r := range append(half, rest...) {
+		status := pairStatus(r.resourceTests, r.dataSourceTests)
+		if r.halfTested {
+			status = "✗ " + status
+		}
+		fmt.Fprintf(w, "  %s\t%d\t%d\t%s\n", r.name, r.resourceTests, r.dataSourceTests, status)
+	}
+	w.Flush()
+}
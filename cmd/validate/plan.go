@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/example/tfprovidertest/internal/gitmeta"
+	"github.com/example/tfprovidertest/internal/plan"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// runPlan prints the smallest ordered set of untested definitions to cover to reach target
+func runPlan(fset *token.FileSet, files []*ast.File, settings config.Settings, format, target, weightsSpec, tag string) {
+	if format != "text" && format != "json" {
+		fmt.Printf("Error: Invalid format '%s' for -plan-to. Must be one of: text, json\n", format)
+		exit(1)
+	}
+	goal, err := plan.ParseTarget(target)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+	weights, err := plan.ParseWeights(weightsSpec)
+	if err != nil {
+		fmt.Printf("Error: -plan-weights: %v\n", err)
+		exit(1)
+	}
+
+	opts := plan.Options{Target: goal, Weights: weights, Tag: tag, Now: time.Now()}
+	// Reading git history costs a process per file, so skip it when recency does not count
+	if weights.Recent > 0 {
+		opts.LastModified = gitmeta.GitLastModified(context.Background())
+	}
+	result := plan.Build(buildRegistryFromFiles(fset, files, settings), opts)
+
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		}
+		return
+	}
+	outputPlanText(result)
+}
+
+// outputPlanText prints the work plan as a numbered table
+func outputPlanText(p plan.Plan) {
+	fmt.Println("=== Coverage Plan ===")
+	fmt.Println()
+	fmt.Printf("Coverage: %d of %d definitions tested (%.1f%%), target %.1f%%\n", p.Tested, p.Total, p.Coverage*100, p.Target*100)
+	if p.Needed == 0 {
+		fmt.Println("The target is already met.")
+		return
+	}
+	fmt.Printf("Cover %d more definition(s), in this order (weights: recent=%g, attributes=%g, tag=%g):\n", p.Needed, p.Weights.Recent, p.Weights.Attributes, p.Weights.Tag)
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  #\tKIND\tNAME\tPRIORITY\tATTRS\tAGE\tTAGGED\tCOVERAGE\tSUGGESTED TEST")
+	for i, step := range p.Steps {
+		age := "-"
+		if step.AgeDays >= 0 {
+			age = fmt.Sprintf("%dd", step.AgeDays)
+		}
+		tagged := ""
+		if step.Tagged {
+			tagged = "yes"
+		}
+		fmt.Fprintf(w, "  %d\t%s\t%s\t%.2f\t%d\t%s\t%s\t%.1f%%\t%s in %s\n", i+1, step.Kind, step.Name, step.Priority, step.Attributes, age, tagged, step.CoverageAfter*100, step.SuggestedTest, step.SuggestedFile)
+	}
+	w.Flush()
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// analyzerResult holds the diagnostics one analyzer reported, in position order.
+type analyzerResult struct {
+	analyzer    *analysis.Analyzer
+	diagnostics []analysis.Diagnostic
+	err         error
+}
+
+// runAnalyzerPool runs the analyzers over the files with at most workers running at once.
+// They share the registry the first of them builds, which is only read afterwards. Each
+// analyzer's diagnostics are sorted by position and passed to done in the order of
+// analyzers, as soon as it and every analyzer before it have finished, so output does not
+// depend on scheduling.
+func runAnalyzerPool(fset *token.FileSet, files []*ast.File, analyzers []*analysis.Analyzer, workers int, done func(analyzerResult)) {
+	workers = max(min(workers, len(analyzers)), 1)
+	jobs := make(chan int)
+	finished := make(chan int)
+	results := make([]analyzerResult, len(analyzers))
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = runAnalyzer(fset, files, analyzers[i])
+				finished <- i
+			}
+		}()
+	}
+	go func() {
+		for i := range analyzers {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+		close(finished)
+	}()
+
+	ready := make([]bool, len(analyzers))
+	next := 0
+	for i := range finished {
+		ready[i] = true
+		for next < len(analyzers) && ready[next] {
+			done(results[next])
+			next++
+		}
+	}
+}
+
+// runAnalyzer runs one analyzer over the files and sorts what it reported by position.
+func runAnalyzer(fset *token.FileSet, files []*ast.File, analyzer *analysis.Analyzer) analyzerResult {
+	result := analyzerResult{analyzer: analyzer}
+	pass := &analysis.Pass{
+		Analyzer: analyzer,
+		Fset:     fset,
+		Files:    files,
+		Report: func(diag analysis.Diagnostic) {
+			result.diagnostics = append(result.diagnostics, diag)
+		},
+	}
+	_, result.err = analyzer.Run(pass)
+	sort.SliceStable(result.diagnostics, func(i, j int) bool {
+		return positionLess(fset.Position(result.diagnostics[i].Pos), fset.Position(result.diagnostics[j].Pos))
+	})
+	return result
+}
+
+// positionLess orders positions by file name, then line and column.
+func positionLess(a, b token.Position) bool {
+	if a.Filename != b.Filename {
+		return a.Filename < b.Filename
+	}
+	if a.Line != b.Line {
+		return a.Line < b.Line
+	}
+	return a.Column < b.Column
+}
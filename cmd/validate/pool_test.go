@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/analysis"
+
+	tfprovidertest "github.com/example/tfprovidertest"
+	internalanalysis "github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/testsrc"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// poolOutput runs the analyzers with the given number of workers and renders what done
+// receives, in the order it receives it.
+func poolOutput(fset *token.FileSet, files []*ast.File, analyzers []*analysis.Analyzer, workers int) []string {
+	internalanalysis.ClearAllRegistryCaches()
+	var out []string
+	runAnalyzerPool(fset, files, analyzers, workers, func(result analyzerResult) {
+		out = append(out, "== "+result.analyzer.Name)
+		if result.err != nil {
+			out = append(out, "error: "+result.err.Error())
+		}
+		for _, diag := range result.diagnostics {
+			out = append(out, fmt.Sprintf("%s: %s", fset.Position(diag.Pos), diag.Message))
+		}
+	})
+	return out
+}
+
+func TestAnalyzerPool(t *testing.T) {
+	t.Cleanup(internalanalysis.ClearAllRegistryCaches)
+
+	t.Run("analyzers finishing out of order", func(t *testing.T) {
+		fset := token.NewFileSet()
+		file := fset.AddFile("/provider/resource_gadget.go", -1, 100)
+		file.SetLinesForContent([]byte("package provider\n\n\n\n\n"))
+		rng := rand.New(rand.NewSource(1))
+
+		var analyzers []*analysis.Analyzer
+		for i := 0; i < 12; i++ {
+			delay := time.Duration(rng.Intn(5)) * time.Millisecond
+			analyzers = append(analyzers, &analysis.Analyzer{
+				Name: fmt.Sprintf("a%02d", i),
+				Doc:  "reports out of position order after a delay",
+				Run: func(pass *analysis.Pass) (interface{}, error) {
+					time.Sleep(delay)
+					for _, line := range []int{4, 1, 3} {
+						pass.Report(analysis.Diagnostic{Pos: file.LineStart(line), Message: fmt.Sprintf("line %d", line)})
+					}
+					return nil, nil
+				},
+			})
+		}
+
+		serial := poolOutput(fset, nil, analyzers, 1)
+		require.Len(t, serial, 12*4)
+		assert.Equal(t, []string{"== a00", "/provider/resource_gadget.go:1:1: line 1", "/provider/resource_gadget.go:3:1: line 3", "/provider/resource_gadget.go:4:1: line 4"}, serial[:4])
+		for _, workers := range []int{2, 8, 32} {
+			assert.Equal(t, serial, poolOutput(fset, nil, analyzers, workers), "%d workers", workers)
+		}
+	})
+
+	t.Run("every rule matches a serial run", func(t *testing.T) {
+		fset, files, _ := parseProvider(t, map[string]string{
+			"/provider/resource_gadget.go":         gadgetResourceSrc,
+			"/provider/resource_gadget_test.go":    testsrc.Acceptance("TestAccGadget_basic", `resource "example_gadget" "g" {}`),
+			"/provider/data_source_gadget.go":      gadgetDataSourceSrc,
+			"/provider/data_source_gadget_test.go": testsrc.Acceptance("TestAccGadgetDataSource_basic", `data "example_gadget" "g" { id = "x" }`),
+		})
+		settings, err := config.ProfileSettings(config.ProfileStrict)
+		require.NoError(t, err)
+		// Each run builds its own plugin, as validate does: a plugin reports each finding once
+		rules := 0
+		run := func(workers int) []string {
+			analyzers, err := tfprovidertest.NewWithSettings(settings).BuildAnalyzers()
+			require.NoError(t, err)
+			rules = len(analyzers)
+			return poolOutput(fset, files, analyzers, workers)
+		}
+
+		serial := run(1)
+		assert.Greater(t, len(serial), rules+5, "the rules report findings")
+		for _, workers := range []int{4, rules} {
+			for i := 0; i < 3; i++ {
+				assert.Equal(t, serial, run(workers), "%d workers, run %d", workers, i)
+			}
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/gitmeta"
+	"github.com/example/tfprovidertest/internal/links"
+	"github.com/example/tfprovidertest/internal/prsuggest"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/internal/routing"
+)
+
+// writePRSuggestions writes the pull request suggestions for the definitions touched since
+// the git ref since to path, or to stdout when path is "-". Paths are relative to the root
+// of the git work tree containing the provider.
+func writePRSuggestions(path string, findings []Finding, reg *registry.ResourceRegistry, providerPath, since string) error {
+	if since == "" {
+		return fmt.Errorf("-pr-suggestions needs -since, the git ref the change is compared with")
+	}
+	ctx := context.Background()
+	root := links.RepoRoot(ctx, providerPath)
+	changed, err := gitmeta.ChangedFiles(ctx, providerPath, since)
+	if err != nil {
+		return err
+	}
+	rel := func(path string) string {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		return routing.RelativePath(root, path)
+	}
+	for i := range changed {
+		changed[i] = rel(changed[i])
+	}
+
+	byDefinition := make(map[string][]prsuggest.Finding)
+	for _, f := range findings {
+		if f.Resource == "" {
+			continue
+		}
+		key := f.Kind + "\x00" + f.Resource
+		byDefinition[key] = append(byDefinition[key], prsuggest.Finding{
+			Rule:    f.Rule,
+			Code:    f.Code,
+			Level:   f.Level,
+			File:    rel(f.File),
+			Line:    f.Line,
+			Message: f.Message,
+		})
+	}
+	var defs []prsuggest.Definition
+	for _, info := range reg.GetSortedDefinitions() {
+		def := prsuggest.Definition{
+			Kind:             info.Kind.String(),
+			Name:             info.Name,
+			File:             rel(info.FilePath),
+			ExpectedTest:     analysis.BuildExpectedTestFunc(info),
+			ExpectedTestFile: filepath.Base(analysis.BuildExpectedTestPath(info)),
+			Findings:         byDefinition[info.Kind.String()+"\x00"+info.Name],
+		}
+		for _, test := range reg.GetTests(info.Kind, info.Name) {
+			def.Tests = append(def.Tests, prsuggest.Test{Name: test.Name, File: rel(test.FilePath)})
+		}
+		defs = append(defs, def)
+	}
+	suggestions := prsuggest.Build(defs, changed, since, rel(providerPath))
+
+	var out io.Writer = os.Stdout
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(suggestions); err != nil {
+		return fmt.Errorf("encoding PR suggestions: %w", err)
+	}
+	return nil
+}
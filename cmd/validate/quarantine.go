@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// quarantinedTestReports returns the reports of the quarantined tests linked to a definition
+func quarantinedTestReports(reg *registry.ResourceRegistry, info *registry.ResourceInfo) []TestReport {
+	var reports []TestReport
+	for _, t := range reg.GetQuarantinedTests(info.Kind, info.Name) {
+		reports = append(reports, TestReport{
+			Name:             t.Name,
+			File:             filepath.Base(t.FilePath),
+			MatchType:        t.MatchType.String(),
+			Confidence:       t.MatchConfidence,
+			OpaqueSteps:      t.HasOpaqueSteps,
+			QuarantineReason: t.QuarantineReason,
+		})
+	}
+	return reports
+}
+
+// AtRisk reports whether the definition is covered only by quarantined tests, so it counts
+// as untested while they stay disabled
+func (r ResourceReport) AtRisk() bool {
+	return r.TestCount == 0 && len(r.QuarantinedTests) > 0
+}
+
+// outputQuarantineTable prints the quarantined tests of each definition, marking the
+// definitions whose coverage is at risk
+func outputQuarantineTable(reg *registry.ResourceRegistry, defs []*registry.ResourceInfo, view reportView) {
+	type row struct {
+		info   *registry.ResourceInfo
+		report ResourceReport
+	}
+	var rows []row
+	for _, info := range defs {
+		report := ResourceReport{TestCount: len(reg.GetTests(info.Kind, info.Name)), QuarantinedTests: quarantinedTestReports(reg, info)}
+		if len(report.QuarantinedTests) > 0 {
+			rows = append(rows, row{info, report})
+		}
+	}
+	if len(rows) == 0 {
+		return
+	}
+
+	fmt.Println()
+	view.section("QUARANTINED TESTS")
+	w := view.newTable()
+	fmt.Fprintln(w, "  DEFINITION\tKIND\tTEST FUNCTION\tOTHER TESTS\tREASON")
+	fmt.Fprintln(w, "  ──────────\t────\t─────────────\t───────────\t──────")
+	for _, r := range rows {
+		others := fmt.Sprintf("%d", r.report.TestCount)
+		if r.report.AtRisk() {
+			others = "0 (at risk)"
+		}
+		for _, t := range r.report.QuarantinedTests {
+			reason := t.QuarantineReason
+			if reason == "" {
+				reason = "-"
+			}
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", r.info.Name, r.info.Kind, t.Name, others, reason)
+		}
+	}
+	w.Flush()
+}
+
+// quarantineCounts returns the number of quarantined tests and of definitions whose
+// coverage is at risk because their only linked tests are quarantined
+func quarantineCounts(reg *registry.ResourceRegistry, resources, dataSources, actions []*registry.ResourceInfo) (quarantined, atRisk int) {
+	for _, fn := range reg.GetAllTestFunctions() {
+		if fn.Quarantined {
+			quarantined++
+		}
+	}
+	for _, info := range allDefinitions(resources, dataSources, actions) {
+		if len(reg.GetTests(info.Kind, info.Name)) == 0 && len(reg.GetQuarantinedTests(info.Kind, info.Name)) > 0 {
+			atRisk++
+		}
+	}
+	return quarantined, atRisk
+}
+
+// allDefinitions concatenates the definitions of each kind in report order
+func allDefinitions(resources, dataSources, actions []*registry.ResourceInfo) []*registry.ResourceInfo {
+	defs := make([]*registry.ResourceInfo, 0, len(resources)+len(dataSources)+len(actions))
+	defs = append(defs, resources...)
+	defs = append(defs, dataSources...)
+	return append(defs, actions...)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/example/tfprovidertest/internal/query"
+)
+
+// queryCapture holds the JSON output -query evaluates its expression against.
+type queryCapture struct {
+	q      *query.Query
+	stdout *os.File // The stdout replaced by the pipe, restored on finish
+	pipe   *os.File // The write end of the pipe replacing stdout
+	buf    bytes.Buffer
+	done   chan struct{}
+}
+
+// capture is the active -query capture, nil without -query.
+var capture *queryCapture
+
+// queryFormat returns the output format to run with -query: text becomes json, and the
+// formats that are not JSON are rejected.
+func queryFormat(format string) (string, error) {
+	switch format {
+	case "text", "json":
+		return "json", nil
+	case "ndjson", "codeclimate":
+		return format, nil
+	}
+	return "", fmt.Errorf("-query needs JSON output; -format %s is not supported with it", format)
+}
+
+// startQuery captures stdout so the query can be evaluated against it once the run
+// completes. Call it after startOutputs, so the query result is what reaches the sinks.
+func startQuery(q *query.Query) error {
+	if q == nil {
+		return nil
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	c := &queryCapture{q: q, stdout: os.Stdout, pipe: w, done: make(chan struct{})}
+	go func() {
+		defer close(c.done)
+		io.Copy(&c.buf, r)
+		r.Close()
+	}()
+	capture = c
+	os.Stdout = w
+	return nil
+}
+
+// finishQuery restores stdout and prints the query results for each JSON value written to
+// it, one per line, so ndjson output is queried line by line. It reports a failed query on
+// stderr and returns false.
+func finishQuery() bool {
+	if capture == nil {
+		return true
+	}
+	c := capture
+	capture = nil
+	c.pipe.Close()
+	<-c.done
+	os.Stdout = c.stdout
+
+	data := c.buf.Bytes()
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var input any
+		if err := dec.Decode(&input); errors.Is(err, io.EOF) {
+			return true
+		} else if err != nil {
+			// Pass the output through, it is usually an error message
+			os.Stdout.Write(data)
+			fmt.Fprintf(os.Stderr, "Error: -query: output is not JSON: %v\n", err)
+			return false
+		}
+		results, err := c.q.Eval(input)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return false
+		}
+		for _, result := range results {
+			fmt.Println(query.Format(result))
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// Optional columns of the -report RESOURCES table, selected with -columns
+const (
+	columnConfidence = "confidence"
+	columnMatchType  = "match-type"
+	columnComplexity = "complexity"
+	columnCategory   = "category"
+)
+
+// Orderings of the -report tables, selected with -sort
+const (
+	sortByName       = "name"
+	sortByConfidence = "confidence"
+	sortByComplexity = "complexity"
+)
+
+// reportView holds the -columns, -sort, -width, -no-unicode, -max-rows, -group-by and
+// -category choices for the -report tables.
+type reportView struct {
+	Columns    map[string]bool
+	SortBy     string
+	Width      int             // Terminal width tables are fitted to; 0 for unlimited
+	ASCII      bool            // Draw tables with ASCII rather than box-drawing characters
+	MaxRows    int             // Rows shown per table before folding the rest; 0 for all
+	GroupBy    string          // groupByCategory to break the report down by category
+	Categories map[string]bool // Lowercased categories the report is limited to; nil for all
+}
+
+// parseReportView validates the -columns and -sort flag values.
+func parseReportView(columns []string, sortBy string) (reportView, error) {
+	view := reportView{Columns: make(map[string]bool), SortBy: sortBy}
+	for _, column := range columns {
+		switch column {
+		case columnConfidence, columnMatchType, columnComplexity, columnCategory:
+			view.Columns[column] = true
+		default:
+			return view, fmt.Errorf("unknown column %q (valid: %s, %s, %s, %s)", column, columnConfidence, columnMatchType, columnComplexity, columnCategory)
+		}
+	}
+	switch sortBy {
+	case "", sortByName:
+		view.SortBy = sortByName
+	case sortByConfidence, sortByComplexity:
+	default:
+		return view, fmt.Errorf("unknown sort %q (valid: %s, %s, %s)", sortBy, sortByName, sortByConfidence, sortByComplexity)
+	}
+	return view, nil
+}
+
+// strongestLink returns the test linked to a definition with the highest match confidence,
+// or nil when the definition has no tests. A definition whose strongest link is weak may be
+// untested even though tests were attributed to it.
+func strongestLink(tests []*registry.TestFunctionInfo) *registry.TestFunctionInfo {
+	var best *registry.TestFunctionInfo
+	for _, t := range tests {
+		if best == nil || t.MatchConfidence > best.MatchConfidence {
+			best = t
+		}
+	}
+	return best
+}
+
+// sortDefinitions orders definitions for the report tables. Definitions are sorted by name;
+// with -sort confidence, tested definitions come first, weakest strongest link first, followed
+// by untested definitions. With -sort complexity, the definitions with the most complex
+// schemas come first.
+func (v reportView) sortDefinitions(reg *registry.ResourceRegistry, defs []*registry.ResourceInfo) {
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	if v.SortBy == sortByComplexity {
+		scores := make(map[*registry.ResourceInfo]int, len(defs))
+		for _, def := range defs {
+			scores[def] = analysis.ResourceComplexity(def).Score
+		}
+		sort.SliceStable(defs, func(i, j int) bool { return scores[defs[i]] > scores[defs[j]] })
+		return
+	}
+	if v.SortBy != sortByConfidence {
+		return
+	}
+
+	best := make(map[*registry.ResourceInfo]*registry.TestFunctionInfo, len(defs))
+	for _, def := range defs {
+		best[def] = strongestLink(reg.GetTests(def.Kind, def.Name))
+	}
+	sort.SliceStable(defs, func(i, j int) bool {
+		a, b := best[defs[i]], best[defs[j]]
+		if a == nil || b == nil {
+			return b == nil && a != nil
+		}
+		return a.MatchConfidence < b.MatchConfidence
+	})
+}
+
+// resourceColumns returns the headers of the optional RESOURCES table columns in display order.
+func (v reportView) resourceColumns() []string {
+	var headers []string
+	if v.Columns[columnConfidence] {
+		headers = append(headers, "CONFIDENCE")
+	}
+	if v.Columns[columnMatchType] {
+		headers = append(headers, "MATCH TYPE")
+	}
+	if v.Columns[columnComplexity] {
+		headers = append(headers, "COMPLEXITY")
+	}
+	if v.Columns[columnCategory] {
+		headers = append(headers, "CATEGORY")
+	}
+	return headers
+}
+
+// resourceCells returns the optional RESOURCES table cells for a definition's report.
+func (v reportView) resourceCells(report ResourceReport) []string {
+	var cells []string
+	if v.Columns[columnConfidence] {
+		if report.TestCount == 0 {
+			cells = append(cells, "-")
+		} else {
+			cells = append(cells, fmt.Sprintf("%.0f%%", report.MatchConfidence*100))
+		}
+	}
+	if v.Columns[columnMatchType] {
+		if report.MatchType == "" {
+			cells = append(cells, "-")
+		} else {
+			cells = append(cells, report.MatchType)
+		}
+	}
+	if v.Columns[columnComplexity] {
+		cells = append(cells, strconv.Itoa(report.Complexity.Score))
+	}
+	if v.Columns[columnCategory] {
+		if report.Category == "" {
+			cells = append(cells, "-")
+		} else {
+			cells = append(cells, report.Category)
+		}
+	}
+	return cells
+}
+
+// tabCells joins cells into tab-separated columns with a leading tab, or returns "" for none.
+func tabCells(cells []string) string {
+	if len(cells) == 0 {
+		return ""
+	}
+	return "\t" + strings.Join(cells, "\t")
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// sortedResource describes a resource of sortRegistry.
+type sortedResource struct {
+	required    int       // Required attributes, which set the complexity score
+	confidences []float64 // Match confidence of each linked test
+}
+
+// sortRegistry returns a registry of the resources and their definitions.
+func sortRegistry(resources map[string]sortedResource) (*registry.ResourceRegistry, []*registry.ResourceInfo) {
+	reg := registry.NewResourceRegistry()
+	var defs []*registry.ResourceInfo
+	for name, r := range resources {
+		def := &registry.ResourceInfo{Name: name, Kind: registry.KindResource, FilePath: "/provider/resource_" + name + ".go"}
+		for i := 0; i < r.required; i++ {
+			def.Attributes = append(def.Attributes, registry.AttributeInfo{Name: "a", Required: true})
+		}
+		reg.RegisterResource(def)
+		defs = append(defs, def)
+		for _, confidence := range r.confidences {
+			fn := &registry.TestFunctionInfo{Name: "TestAcc" + name, FilePath: "/provider/resource_" + name + "_test.go", MatchConfidence: confidence}
+			reg.RegisterTestFunction(fn)
+			reg.LinkTest(registry.KindResource, name, fn)
+		}
+	}
+	return reg, defs
+}
+
+// definitionNames returns the names of defs in order.
+func definitionNames(defs []*registry.ResourceInfo) []string {
+	names := make([]string, len(defs))
+	for i, def := range defs {
+		names[i] = def.Name
+	}
+	return names
+}
+
+func TestParseReportView(t *testing.T) {
+	view, err := parseReportView(nil, "")
+	require.NoError(t, err)
+	assert.Equal(t, sortByName, view.SortBy, "sorted by name by default")
+
+	view, err = parseReportView([]string{columnComplexity, columnConfidence}, sortByComplexity)
+	require.NoError(t, err)
+	assert.Equal(t, sortByComplexity, view.SortBy)
+	assert.Equal(t, []string{"CONFIDENCE", "COMPLEXITY"}, view.resourceColumns(), "in display order, not flag order")
+
+	_, err = parseReportView(nil, "coverage")
+	assert.EqualError(t, err, `unknown sort "coverage" (valid: name, confidence, complexity)`)
+	_, err = parseReportView([]string{"tests"}, "")
+	assert.EqualError(t, err, `unknown column "tests" (valid: confidence, match-type, complexity, category)`)
+}
+
+func TestSortDefinitions(t *testing.T) {
+	reg, defs := sortRegistry(map[string]sortedResource{
+		"delta":   {required: 1, confidences: []float64{0.9}},
+		"alpha":   {required: 3},
+		"charlie": {required: 1, confidences: []float64{0.4, 0.95}},
+		"bravo":   {required: 3, confidences: []float64{0.6}},
+		"echo":    {confidences: []float64{0.6}},
+		"foxtrot": {required: 2},
+	})
+
+	tests := []struct {
+		sortBy string
+		want   []string
+	}{
+		{sortByName, []string{"alpha", "bravo", "charlie", "delta", "echo", "foxtrot"}},
+		// bravo and echo tie at 60%, and alpha and foxtrot are untested: ties keep name order
+		{sortByConfidence, []string{"bravo", "echo", "delta", "charlie", "alpha", "foxtrot"}},
+		// alpha and bravo tie, as do charlie and delta: ties keep name order
+		{sortByComplexity, []string{"alpha", "bravo", "foxtrot", "charlie", "delta", "echo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sortBy, func(t *testing.T) {
+			view, err := parseReportView(nil, tt.sortBy)
+			require.NoError(t, err)
+			// The order defs arrive in must not matter
+			for _, order := range [][]*registry.ResourceInfo{defs, reversed(defs)} {
+				sorted := append([]*registry.ResourceInfo(nil), order...)
+				view.sortDefinitions(reg, sorted)
+				assert.Equal(t, tt.want, definitionNames(sorted))
+			}
+		})
+	}
+
+	t.Run("strongest link", func(t *testing.T) {
+		charlie := reg.GetTests(registry.KindResource, "charlie")
+		require.Len(t, charlie, 2)
+		assert.InDelta(t, 0.95, strongestLink(charlie).MatchConfidence, 1e-9, "a definition is ranked by its strongest link")
+		assert.Nil(t, strongestLink(nil))
+	})
+}
+
+// reversed returns a reversed copy of defs.
+func reversed(defs []*registry.ResourceInfo) []*registry.ResourceInfo {
+	out := make([]*registry.ResourceInfo, len(defs))
+	for i, def := range defs {
+		out[len(defs)-1-i] = def
+	}
+	return out
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/links"
+	"github.com/example/tfprovidertest/internal/repro"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// defaultReproPath names the bundle of a query, e.g. tfprovidertest-repro-data-source-widget.zip
+func defaultReproPath(query string) string {
+	return "tfprovidertest-repro-" + strings.Trim(unsafeFileChars.ReplaceAllString(query, "-"), "-") + ".zip"
+}
+
+// runRepro writes a reproducer bundle for one resource to path
+func runRepro(fset *token.FileSet, files []*ast.File, settings config.Settings, query, path, providerPath string) {
+	if path == "" {
+		path = defaultReproPath(query)
+	}
+
+	reg := buildRegistryFromFiles(fset, files, settings)
+	root := links.RepoRoot(context.Background(), providerPath)
+	bundle, err := repro.Collect(query, reg, fset, files, settings, root)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		fmt.Printf("Error: Could not create %s: %v\n", path, err)
+		exit(1)
+	}
+	if err := bundle.WriteZip(out); err != nil {
+		out.Close()
+		fmt.Printf("Error: Could not write %s: %v\n", path, err)
+		exit(1)
+	}
+	if err := out.Close(); err != nil {
+		fmt.Printf("Error: Could not write %s: %v\n", path, err)
+		exit(1)
+	}
+
+	if settings.RedactConfigs {
+		fmt.Printf("Wrote reproducer for %q to %s (%d files, %d HCL configs redacted)\n", query, path, len(bundle.Files), bundle.Redacted())
+	} else {
+		fmt.Printf("Wrote reproducer for %q to %s (%d files, %d HCL values scrubbed)\n", query, path, len(bundle.Files), bundle.Scrubbed())
+	}
+	fmt.Println("Review it before attaching it to a bug report: check values and helpers outside test configurations are not scrubbed.")
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/example/tfprovidertest/internal/routing"
+)
+
+// runStateVersion is the format version of the run state file.
+const runStateVersion = 1
+
+// runState records which analyzers reported findings, and in which files, so -rerun-failed
+// can limit the next run to them.
+type runState struct {
+	Version int `json:"version"`
+	// Failed maps the name of each analyzer that reported findings to the files, relative
+	// to the provider directory, it reported them in
+	Failed map[string][]string `json:"failed"`
+}
+
+// runStatePath returns the path of the run state file: flagValue when set, else a file in
+// the user cache directory keyed by the provider's absolute path. It returns "" when
+// flagValue is "off" or there is no cache directory.
+func runStatePath(flagValue, providerPath string) string {
+	switch flagValue {
+	case "off":
+		return ""
+	case "":
+	default:
+		return flagValue
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	abs, err := filepath.Abs(providerPath)
+	if err != nil {
+		abs = providerPath
+	}
+	sum := sha256.Sum256([]byte(abs))
+	return filepath.Join(cacheDir, "tfprovidertest", "runs", hex.EncodeToString(sum[:8])+".json")
+}
+
+// loadRunState reads the run state file at path.
+func loadRunState(path string) (*runState, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("no previous run recorded at %s; run validate once without -rerun-failed", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state runState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if state.Version != runStateVersion {
+		return nil, fmt.Errorf("%s: unsupported run state version %d; run validate once without -rerun-failed", path, state.Version)
+	}
+	return &state, nil
+}
+
+// newRunState returns an empty run state.
+func newRunState() *runState {
+	return &runState{Version: runStateVersion, Failed: make(map[string][]string)}
+}
+
+// add records that rule reported a finding in file.
+func (s *runState) add(rule, file, providerPath string) {
+	rel := routing.RelativePath(providerPath, file)
+	for _, existing := range s.Failed[rule] {
+		if existing == rel {
+			return
+		}
+	}
+	s.Failed[rule] = append(s.Failed[rule], rel)
+}
+
+// write saves the run state to path, creating its directory.
+func (s *runState) write(path string) error {
+	for _, files := range s.Failed {
+		sort.Strings(files)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// restrict returns the analyzers of selected that failed, or every analyzer that failed
+// when nothing is selected.
+func (s *runState) restrict(selected []string) []string {
+	var kept []string
+	if len(selected) == 0 {
+		for rule := range s.Failed {
+			kept = append(kept, rule)
+		}
+		sort.Strings(kept)
+		return kept
+	}
+	for _, rule := range selected {
+		if _, ok := s.Failed[rule]; ok {
+			kept = append(kept, rule)
+		}
+	}
+	return kept
+}
+
+// covers reports whether rule reported a finding in file in the recorded run. A nil state
+// covers every file.
+func (s *runState) covers(rule, file, providerPath string) bool {
+	if s == nil {
+		return true
+	}
+	rel := routing.RelativePath(providerPath, file)
+	for _, failed := range s.Failed[rule] {
+		if failed == rel {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/example/tfprovidertest/internal/links"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/internal/routing"
+)
+
+// writeRouting writes the routing file of findings to path, or to stdout when path is "-".
+// Paths and CODEOWNERS patterns are relative to the root of the git work tree containing the
+// provider.
+func writeRouting(path string, findings []Finding, reg *registry.ResourceRegistry, providerPath string) error {
+	root := links.RepoRoot(context.Background(), providerPath)
+	owners, ownersPath, err := routing.LoadCodeowners(root)
+	if err != nil {
+		return err
+	}
+
+	definitionFiles := make(map[string]string)
+	for _, info := range reg.GetAllDefinitions() {
+		definitionFiles[info.Kind.String()+"\x00"+info.Name] = info.FilePath
+	}
+	routed := make([]routing.Finding, 0, len(findings))
+	for _, f := range findings {
+		routed = append(routed, routing.Finding{
+			Rule:           f.Rule,
+			Code:           f.Code,
+			Group:          f.Group,
+			Level:          f.Level,
+			File:           f.File,
+			Line:           f.Line,
+			Message:        f.Message,
+			URL:            f.URL,
+			Resource:       f.Resource,
+			Kind:           f.Kind,
+			DefinitionFile: definitionFiles[f.Kind+"\x00"+f.Resource],
+		})
+	}
+	file := routing.Build(routed, root, owners)
+	if ownersPath != "" {
+		file.Codeowners = routing.RelativePath(root, ownersPath)
+	}
+
+	var out io.Writer = os.Stdout
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(file); err != nil {
+		return fmt.Errorf("encoding routing file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/example/tfprovidertest"
+	"github.com/example/tfprovidertest/internal/rules"
+	"github.com/example/tfprovidertest/pkg/config"
+	"golang.org/x/tools/go/analysis"
+)
+
+// analyzerList collects the values of the repeatable -analyzer flag. Each value may also
+// list several analyzers separated by commas.
+type analyzerList []string
+
+func (l *analyzerList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *analyzerList) Set(value string) error {
+	*l = append(*l, splitList(value)...)
+	return nil
+}
+
+// resolveAnalyzers validates the names given with -analyzer and returns their current rule
+// names. Legacy names (e.g., tfprovider-resource-import-test) are accepted.
+func resolveAnalyzers(names []string) ([]string, error) {
+	var resolved []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		rule, ok := rules.Lookup(name)
+		if !ok {
+			var known []string
+			for _, r := range rules.All() {
+				known = append(known, r.Name)
+			}
+			return nil, fmt.Errorf("unknown analyzer %q (available: %s)", name, strings.Join(known, ", "))
+		}
+		if !seen[rule.Name] {
+			seen[rule.Name] = true
+			resolved = append(resolved, rule.Name)
+		}
+	}
+	return resolved, nil
+}
+
+// buildAnalyzers builds the analyzers of a plugin created from the settings. When selected
+// names analyzers, only those are returned, whether or not the settings enable them.
+func buildAnalyzers(settings config.Settings, selected []string) ([]*analysis.Analyzer, error) {
+	if len(selected) > 0 {
+		// Build every analyzer so that opt-in ones can be selected
+		enabled := true
+		settings.EnableCoverageRules = &enabled
+		settings.EnableQualityRules = &enabled
+	}
+	analyzers, err := tfprovidertest.NewWithSettings(settings).BuildAnalyzers()
+	if err != nil {
+		return nil, err
+	}
+	return selectAnalyzers(analyzers, selected), nil
+}
+
+// selectAnalyzers keeps the analyzers named in selected, in their usual order. With no
+// selection every analyzer is kept.
+func selectAnalyzers(analyzers []*analysis.Analyzer, selected []string) []*analysis.Analyzer {
+	if len(selected) == 0 {
+		return analyzers
+	}
+	wanted := make(map[string]bool, len(selected))
+	for _, name := range selected {
+		wanted[name] = true
+	}
+	var kept []*analysis.Analyzer
+	for _, analyzer := range analyzers {
+		if wanted[analyzer.Name] {
+			kept = append(kept, analyzer)
+		}
+	}
+	return kept
+}
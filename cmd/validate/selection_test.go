@@ -0,0 +1,85 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/rules"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// analyzerNames returns the names of analyzers in order.
+func analyzerNames(analyzers []*analysis.Analyzer) []string {
+	names := make([]string, len(analyzers))
+	for i, analyzer := range analyzers {
+		names[i] = analyzer.Name
+	}
+	return names
+}
+
+func TestAnalyzerListFlag(t *testing.T) {
+	var list analyzerList
+	require.NoError(t, list.Set(rules.BasicTest))
+	require.NoError(t, list.Set(" tfprovider-resource-import-test , ,"+rules.DriftCheck))
+	assert.Equal(t, analyzerList{rules.BasicTest, "tfprovider-resource-import-test", rules.DriftCheck}, list,
+		"repeatable, and each value may list several")
+	assert.Equal(t, rules.BasicTest+",tfprovider-resource-import-test,"+rules.DriftCheck, list.String())
+}
+
+func TestResolveAnalyzers(t *testing.T) {
+	t.Run("legacy names and duplicates", func(t *testing.T) {
+		resolved, err := resolveAnalyzers([]string{rules.DriftCheck, "tfprovider-resource-import-test", rules.ImportTest, rules.DriftCheck})
+		require.NoError(t, err)
+		assert.Equal(t, []string{rules.DriftCheck, rules.ImportTest}, resolved, "in the order given, each once")
+	})
+
+	t.Run("none", func(t *testing.T) {
+		resolved, err := resolveAnalyzers(nil)
+		require.NoError(t, err)
+		assert.Empty(t, resolved)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := resolveAnalyzers([]string{rules.BasicTest, "tfprovider-coverage-everything"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown analyzer "tfprovider-coverage-everything" (available: `+rules.BasicTest+", "+rules.UpdateTest)
+	})
+}
+
+func TestBuildAnalyzers(t *testing.T) {
+	settings := config.DefaultSettings()
+	defaults, err := buildAnalyzers(settings, nil)
+	require.NoError(t, err)
+	require.NotContains(t, analyzerNames(defaults), rules.TestLayout, "test layout is opt-in")
+
+	t.Run("selection keeps the usual order", func(t *testing.T) {
+		selected, err := resolveAnalyzers([]string{rules.TestLayout, rules.DriftCheck, "tfprovider-resource-basic-test"})
+		require.NoError(t, err)
+		analyzers, err := buildAnalyzers(settings, selected)
+		require.NoError(t, err)
+		assert.Equal(t, []string{rules.BasicTest, rules.TestLayout, rules.DriftCheck}, analyzerNames(analyzers),
+			"opt-in analyzers can be selected")
+	})
+
+	t.Run("rules disabled by the settings can be selected", func(t *testing.T) {
+		disabled := false
+		settings := config.DefaultSettings()
+		settings.EnableCoverageRules = &disabled
+		settings.EnableQualityRules = &disabled
+		for _, rule := range rules.All() {
+			analyzers, err := buildAnalyzers(settings, []string{rule.Name})
+			require.NoError(t, err)
+			assert.Equal(t, []string{rule.Name}, analyzerNames(analyzers))
+		}
+	})
+
+	t.Run("no selection builds what the settings enable", func(t *testing.T) {
+		analyzers, err := buildAnalyzers(settings, nil)
+		require.NoError(t, err)
+		assert.Equal(t, analyzerNames(defaults), analyzerNames(analyzers))
+		assert.Contains(t, analyzerNames(analyzers), rules.BasicTest)
+	})
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// loadSettingsFile reads the settings file at configPath, or else the one in the provider
+// directory, and returns its settings and path. It returns nil settings when there is no file
+// or configPath is "off". Flags that map to a setting and were not given on the command line
+// take the file's value, so explicit flags win over the file.
+func loadSettingsFile(configPath, providerPath, profile string) (*config.Settings, string, error) {
+	path := configPath
+	switch configPath {
+	case "off":
+		return nil, "", nil
+	case "":
+		path = filepath.Join(providerPath, config.File)
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			return nil, "", nil
+		}
+	}
+	s, err := config.LoadFile(path, profile)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not load settings file: %w", err)
+	}
+
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	for name, value := range settingsFileFlags(s) {
+		if set[name] || value == "" {
+			continue
+		}
+		if err := flag.Set(name, value); err != nil {
+			return nil, "", fmt.Errorf("%s: invalid value %q for -%s: %w", path, value, name, err)
+		}
+	}
+	return &s, path, nil
+}
+
+// settingsFileFlags returns the flag values of the settings the flags of validate set
+// unconditionally, by flag name
+func settingsFileFlags(s config.Settings) map[string]string {
+	matchStrategy := ""
+	if s.EnableFuzzyMatching {
+		matchStrategy = "fuzzy"
+	}
+	return map[string]string{
+		"verbose":                strconv.FormatBool(s.Verbose),
+		"confidence-threshold":   strconv.FormatFloat(s.FuzzyMatchThreshold, 'g', -1, 64),
+		"provider-prefix":        s.ProviderPrefix,
+		"language":               s.Language,
+		"message-style":          s.MessageStyle,
+		"redact-configs":         strconv.FormatBool(s.RedactConfigs),
+		"layout":                 s.Layout,
+		"resource-dir-globs":     strings.Join(s.ResourceDirGlobs, ","),
+		"max-files":              strconv.Itoa(s.MaxFiles),
+		"max-file-size-kb":       strconv.Itoa(s.MaxFileSizeKB),
+		"requirements-manifest":  s.RequirementsManifest,
+		"enforce-paths":          strings.Join(s.EnforcePaths, ","),
+		"warn-only-paths":        strings.Join(s.WarnOnlyPaths, ","),
+		"resources":              strings.Join(s.Resources, ","),
+		"ignore-dir-configs":     strconv.FormatBool(s.IgnoreDirConfigs),
+		"ignore-helper-packages": strconv.FormatBool(s.IgnoreHelperPackages),
+		"since":                  s.Since,
+		"repo-url-template":      s.RepoURLTemplate,
+		"match-strategy":         matchStrategy,
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/example/tfprovidertest"
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// runShow prints everything known about one resource: the detail page of validate show
+func runShow(fset *token.FileSet, files []*ast.File, settings config.Settings, format string, query string, workers int, providerPath string) {
+	if format != "text" && format != "json" {
+		fmt.Printf("Error: Invalid format '%s' for show. Must be one of: text, json\n", format)
+		exit(1)
+	}
+
+	reg := buildRegistryFromFiles(fset, files, settings)
+	details := analysis.Describe(query, reg, fset, &settings)
+	link := newLinker(settings, providerPath, fset)
+	if len(details) > 0 {
+		analyzers, err := tfprovidertest.NewWithSettings(settings).BuildAnalyzers()
+		if err != nil {
+			fmt.Printf("Error building analyzers: %v\n", err)
+			exit(1)
+		}
+		attributor := newFindingAttributor(reg)
+		runAnalyzerPool(fset, files, analyzers, workers, func(result analyzerResult) {
+			for _, diag := range result.diagnostics {
+				pos := fset.Position(diag.Pos)
+				info := attributor.resourceFor(diag.Pos, pos.Filename)
+				if info == nil {
+					continue
+				}
+				for i := range details {
+					if details[i].Name == info.Name && details[i].Kind == info.Kind.String() {
+						details[i].Diagnostics = append(details[i].Diagnostics, analysis.DiagnosticEntry{
+							Rule: result.analyzer.Name, File: pos.Filename, Line: pos.Line, Message: diag.Message,
+							URL: link.URL(pos.Filename, pos.Line),
+						})
+					}
+				}
+			}
+		})
+	}
+
+	for i := range details {
+		details[i].URL = link.URL(details[i].File, details[i].Line)
+		for j := range details[i].Tests {
+			test := &details[i].Tests[j]
+			test.URL = link.URL(test.File, test.Line)
+		}
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(details); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		}
+		return
+	}
+	if len(details) == 0 {
+		// Fall back to the decision trail, which lists excluded files and naming suggestions
+		outputExplainText(analysis.Explain(query, reg, fset, files, &settings))
+		return
+	}
+	for _, detail := range details {
+		outputShowText(detail)
+	}
+}
+
+// outputShowText prints the detail page of one definition for humans
+func outputShowText(d analysis.ResourceDetail) {
+	fmt.Printf("%s %q\n", d.Kind, d.Name)
+	fmt.Printf("  File: %s:%d (%s)\n", d.File, d.Line, d.DiscoveredBy)
+	if d.URL != "" {
+		fmt.Printf("  Link: %s\n", d.URL)
+	}
+	if d.AliasOf != "" {
+		fmt.Printf("  Shares the implementation of: %s\n", d.AliasOf)
+	}
+	if d.Maturity != "" {
+		fmt.Printf("  Maturity: %s\n", d.Maturity)
+	}
+	if d.Version > 0 {
+		fmt.Printf("  Schema version: %d\n", d.Version)
+	}
+
+	fmt.Printf("\n  Attributes (%d):\n", len(d.Attributes))
+	for _, attr := range d.Attributes {
+		flags := strings.Join(attr.Flags, ", ")
+		if attr.Type != "" {
+			flags = attr.Type + "; " + flags
+		}
+		fmt.Printf("    %-24s %s\n", attr.Name, flags)
+	}
+
+	fmt.Println("\n  Operations:")
+	if len(d.Operations) == 0 {
+		fmt.Println("    none found in the definition's file")
+	}
+	for _, op := range d.Operations {
+		switch {
+		case !op.Implemented:
+			fmt.Printf("    ✗ %s\n", op.Name)
+		case op.NoOp:
+			fmt.Printf("    ✓ %s (no-op)\n", op.Name)
+		case op.Line > 0:
+			fmt.Printf("    ✓ %s (line %d)\n", op.Name, op.Line)
+		default:
+			fmt.Printf("    ✓ %s\n", op.Name)
+		}
+	}
+
+	fmt.Printf("\n  Tests (%d):\n", len(d.Tests))
+	for _, test := range d.Tests {
+		fmt.Printf("    %s (%s:%d) via %s, confidence %.2f\n", test.Test, filepath.Base(test.File), test.Line, test.MatchType, test.Confidence)
+		if test.URL != "" {
+			fmt.Printf("      %s\n", test.URL)
+		}
+		var notes []string
+		if test.CheckDestroy {
+			notes = append(notes, "CheckDestroy")
+		}
+		if test.Quarantined {
+			notes = append(notes, "quarantined")
+		}
+		if test.Upgrade {
+			note := "upgrade test"
+			if test.UpgradeFrom != "" {
+				note += " from " + test.UpgradeFrom
+			}
+			notes = append(notes, note)
+		}
+		if test.Opaque {
+			notes = append(notes, "some steps not resolved statically")
+		}
+		if len(test.Conditions) > 0 {
+			notes = append(notes, "conditional on "+strings.Join(test.Conditions, ", "))
+		}
+		if len(test.UniqueChecks) > 0 {
+			notes = append(notes, "load-bearing: only test covering "+strings.Join(test.UniqueChecks, ", "))
+		}
+		if len(notes) > 0 {
+			fmt.Printf("      %s\n", strings.Join(notes, "; "))
+		}
+		for _, step := range test.Steps {
+			fmt.Printf("      step %d: %s\n", step.Number, step.Summary)
+		}
+		for _, subtest := range test.Subtests {
+			fmt.Printf("      subtest %s: %s\n", subtest.Name, stepNumbers(subtest.Steps))
+		}
+	}
+
+	fmt.Println("\n  Coverage:")
+	for _, check := range d.Checks {
+		mark := "✗"
+		switch {
+		case check.Covered:
+			mark = "✓"
+		case check.Exempt:
+			mark = "-"
+		}
+		fmt.Printf("    %s %s\n", mark, check.Check)
+	}
+
+	fmt.Printf("\n  Diagnostics (%d):\n", len(d.Diagnostics))
+	for _, diag := range d.Diagnostics {
+		message, _, _ := strings.Cut(diag.Message, "\n")
+		fmt.Printf("    [%s] %s:%d\n      %s\n", diag.Rule, filepath.Base(diag.File), diag.Line, message)
+		if diag.URL != "" {
+			fmt.Printf("      %s\n", diag.URL)
+		}
+	}
+
+	if len(d.NextTests) > 0 {
+		fmt.Println("\n  Next tests:")
+		for _, next := range d.NextTests {
+			fmt.Printf("    - %s\n", next)
+		}
+	}
+	fmt.Println()
+}
+
+// stepNumbers describes the steps a subtest runs, e.g. "steps 1, 2".
+func stepNumbers(steps []int) string {
+	if len(steps) == 0 {
+		return "no steps resolved"
+	}
+	numbers := make([]string, len(steps))
+	for i, step := range steps {
+		numbers[i] = strconv.Itoa(step)
+	}
+	if len(steps) == 1 {
+		return "step " + numbers[0]
+	}
+	return "steps " + strings.Join(numbers, ", ")
+}
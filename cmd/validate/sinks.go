@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/sinks"
+)
+
+// outputList collects the values of the repeatable -output and -output-header flags.
+// Values are not split at commas, which URLs and header values may contain.
+type outputList []string
+
+func (l *outputList) String() string {
+	return strings.Join(*l, " ")
+}
+
+func (l *outputList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// outputRouter copies everything written to stdout into the -output sinks.
+type outputRouter struct {
+	stdout *os.File // The process's stdout, restored on close
+	pipe   *os.File // The write end of the pipe replacing stdout
+	sink   sinks.Sink
+	done   chan struct{}
+}
+
+// router is the active -output router, nil when output goes to stdout only.
+var router *outputRouter
+
+// startOutputs routes stdout to the sinks of specs. HTTP sinks send headers, each
+// "Name: value", retry failed requests retries times and label the body as format.
+func startOutputs(specs, headers []string, retries int, format string) error {
+	if len(specs) == 0 {
+		return nil
+	}
+	opts := sinks.HTTPOptions{
+		Headers:     make(http.Header),
+		ContentType: sinks.ContentType(format),
+		Retries:     retries,
+	}
+	for _, header := range headers {
+		name, value, err := sinks.ParseHeader(os.ExpandEnv(header))
+		if err != nil {
+			return err
+		}
+		opts.Headers.Add(name, value)
+	}
+	var targets []sinks.Sink
+	for _, spec := range specs {
+		sink, err := sinks.Parse(spec, os.Stdout, opts)
+		if err != nil {
+			for _, opened := range targets {
+				opened.Close()
+			}
+			return err
+		}
+		targets = append(targets, sink)
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	router = &outputRouter{stdout: os.Stdout, pipe: w, sink: sinks.Tee(targets...), done: make(chan struct{})}
+	go func() {
+		defer close(router.done)
+		io.Copy(router.sink, r)
+		r.Close()
+	}()
+	os.Stdout = w
+	return nil
+}
+
+// closeOutputs delivers the output to the -output sinks and restores stdout. It reports
+// failed sinks on stderr and returns false when any failed.
+func closeOutputs() bool {
+	if router == nil {
+		return true
+	}
+	r := router
+	router = nil
+	r.pipe.Close()
+	<-r.done
+	os.Stdout = r.stdout
+	if err := r.sink.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return false
+	}
+	return true
+}
+
+// exit prints the -query results, delivers the output to the -output sinks and exits with
+// code, or with 1 when the query or a sink failed in an otherwise successful run.
+func exit(code int) {
+	queryOK := finishQuery()
+	if (!closeOutputs() || !queryOK) && code == 0 {
+		code = 1
+	}
+	os.Exit(code)
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/example/tfprovidertest/internal/enforcement"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/internal/rules"
+)
+
+// maxTopOffenders limits how many resources are listed in the run summary
+const maxTopOffenders = 10
+
+// Finding represents a single diagnostic reported by an analyzer
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Code     string   `json:"code,omitempty"` // Short rule code, e.g. TFPT003
+	Group    string   `json:"group"`
+	Level    string   `json:"level"` // "error", or "warning" outside -enforce-paths or in -warn-only-paths
+	File     string   `json:"file"`
+	Line     int      `json:"line"`
+	Resource string   `json:"resource,omitempty"`
+	Kind     string   `json:"kind,omitempty"`
+	Message  string   `json:"message"`
+	URL      string   `json:"url,omitempty"`   // Link to the finding, with -repo-url-template
+	Fixes    []string `json:"fixes,omitempty"` // Suggested fixes, e.g. where to move a test
+}
+
+// RuleCount holds the number of findings reported by a single analyzer
+type RuleCount struct {
+	Rule  string `json:"rule"`
+	Group string `json:"group"`
+	Count int    `json:"count"`
+}
+
+// ResourceCount holds the number of findings attributed to a single resource
+type ResourceCount struct {
+	Resource string `json:"resource"`
+	Kind     string `json:"kind"`
+	Count    int    `json:"count"`
+}
+
+// RunSummary describes the shape of the findings from an analyzer run
+type RunSummary struct {
+	TotalFindings int             `json:"total_findings"`
+	ByRule        []RuleCount     `json:"by_rule"`
+	ByGroup       map[string]int  `json:"by_group"`
+	ByLevel       map[string]int  `json:"by_level"`
+	ByKind        map[string]int  `json:"by_kind"`
+	TopResources  []ResourceCount `json:"top_resources"`
+}
+
+// AnalyzerRunOutput is the JSON document emitted by runAnalyzers with -format json
+type AnalyzerRunOutput struct {
+	Findings []Finding  `json:"findings"`
+	Summary  RunSummary `json:"summary"`
+	// Gate is the coverage gate result; it is omitted without -fail-under or -warn-under
+	Gate *GateResult `json:"gate,omitempty"`
+	// Health describes the run itself: files read, strategy and match counts, phase timings
+	Health *RunHealth `json:"health,omitempty"`
+}
+
+// findingAttributor maps diagnostic positions back to the resource they concern
+type findingAttributor struct {
+	bySchemaPos map[token.Pos]*registry.ResourceInfo
+	reg         *registry.ResourceRegistry
+}
+
+// newFindingAttributor indexes all definitions in the registry by schema position
+func newFindingAttributor(reg *registry.ResourceRegistry) *findingAttributor {
+	a := &findingAttributor{
+		bySchemaPos: make(map[token.Pos]*registry.ResourceInfo),
+		reg:         reg,
+	}
+	for _, info := range reg.GetAllDefinitions() {
+		if info.SchemaPos.IsValid() {
+			a.bySchemaPos[info.SchemaPos] = info
+		}
+	}
+	for _, fn := range reg.GetAllTestFunctions() {
+		if !fn.FunctionPos.IsValid() {
+			continue
+		}
+		if info := reg.MatchedDefinition(fn); info != nil {
+			a.bySchemaPos[fn.FunctionPos] = info
+		}
+	}
+	return a
+}
+
+// resourceFor returns the resource a diagnostic refers to, or nil if it cannot be attributed
+func (a *findingAttributor) resourceFor(pos token.Pos, filename string) *registry.ResourceInfo {
+	if info, ok := a.bySchemaPos[pos]; ok {
+		return info
+	}
+	return a.reg.GetResourceByFile(filename)
+}
+
+// findingTally counts findings as they are reported, so a run summary can be built without
+// keeping the findings themselves
+type findingTally struct {
+	total          int
+	ruleCounts     map[string]int
+	groupCounts    map[string]int
+	levelCounts    map[string]int
+	kindCounts     map[string]int
+	resourceCounts map[registry.ResourceKey]int
+}
+
+// newFindingTally returns a tally that lists every rule that ran, even if it reports nothing
+func newFindingTally(ruleNames []string) *findingTally {
+	t := &findingTally{
+		ruleCounts:     make(map[string]int, len(ruleNames)),
+		groupCounts:    make(map[string]int),
+		levelCounts:    map[string]int{string(enforcement.LevelError): 0},
+		kindCounts:     make(map[string]int),
+		resourceCounts: make(map[registry.ResourceKey]int),
+	}
+	for _, rule := range ruleNames {
+		t.ruleCounts[rule] = 0
+		if group := string(rules.GroupOf(rule)); group != "" {
+			t.groupCounts[group] = 0
+		}
+	}
+	return t
+}
+
+// add counts a single finding
+func (t *findingTally) add(f Finding) {
+	t.total++
+	t.ruleCounts[f.Rule]++
+	t.groupCounts[f.Group]++
+	t.levelCounts[f.Level]++
+	if f.Resource == "" {
+		t.kindCounts["unattributed"]++
+		return
+	}
+	t.kindCounts[f.Kind]++
+	kind, _ := registry.ParseResourceKind(f.Kind)
+	t.resourceCounts[registry.ResourceKey{Kind: kind, Name: f.Resource}]++
+}
+
+// summary aggregates the counted findings per rule, per rule group, per kind, and per resource
+func (t *findingTally) summary() RunSummary {
+	summary := RunSummary{
+		TotalFindings: t.total,
+		ByGroup:       t.groupCounts,
+		ByLevel:       t.levelCounts,
+		ByKind:        t.kindCounts,
+	}
+
+	for rule, count := range t.ruleCounts {
+		summary.ByRule = append(summary.ByRule, RuleCount{
+			Rule:  rule,
+			Group: string(rules.GroupOf(rule)),
+			Count: count,
+		})
+	}
+	sort.Slice(summary.ByRule, func(i, j int) bool {
+		if summary.ByRule[i].Count != summary.ByRule[j].Count {
+			return summary.ByRule[i].Count > summary.ByRule[j].Count
+		}
+		return summary.ByRule[i].Rule < summary.ByRule[j].Rule
+	})
+
+	for key, count := range t.resourceCounts {
+		summary.TopResources = append(summary.TopResources, ResourceCount{
+			Resource: key.Name,
+			Kind:     key.Kind.String(),
+			Count:    count,
+		})
+	}
+	sort.Slice(summary.TopResources, func(i, j int) bool {
+		a, b := summary.TopResources[i], summary.TopResources[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		return a.Kind < b.Kind
+	})
+	if len(summary.TopResources) > maxTopOffenders {
+		summary.TopResources = summary.TopResources[:maxTopOffenders]
+	}
+
+	return summary
+}
+
+// outputRunSummaryText prints the run summary as aligned tables
+func outputRunSummaryText(summary RunSummary) {
+	fmt.Println()
+	fmt.Println("=== Summary ===")
+	if summary.TotalFindings == 0 {
+		fmt.Println("No issues found - all resources have proper test coverage!")
+		return
+	}
+	if warnings := summary.ByLevel[string(enforcement.LevelWarning)]; warnings > 0 {
+		fmt.Printf("Found %d issue(s): %d error(s), %d warning(s) outside enforced paths\n", summary.TotalFindings, summary.ByLevel[string(enforcement.LevelError)], warnings)
+	} else {
+		fmt.Printf("Found %d issue(s)\n", summary.TotalFindings)
+	}
+
+	fmt.Println()
+	fmt.Println("Findings by group:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, group := range []rules.Group{rules.GroupCoverage, rules.GroupQuality} {
+		if count, ok := summary.ByGroup[string(group)]; ok {
+			fmt.Fprintf(w, "  %s\t%d\n", group, count)
+		}
+	}
+	w.Flush()
+
+	fmt.Println()
+	fmt.Println("Findings by rule:")
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  RULE\tGROUP\tFINDINGS")
+	for _, rc := range summary.ByRule {
+		fmt.Fprintf(w, "  %s\t%s\t%d\n", rc.Rule, rc.Group, rc.Count)
+	}
+	w.Flush()
+
+	fmt.Println()
+	fmt.Println("Findings by kind:")
+	kinds := make([]string, 0, len(summary.ByKind))
+	for kind := range summary.ByKind {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, kind := range kinds {
+		fmt.Fprintf(w, "  %s\t%d\n", kind, summary.ByKind[kind])
+	}
+	w.Flush()
+
+	if len(summary.TopResources) == 0 {
+		return
+	}
+	fmt.Println()
+	fmt.Printf("Top %d resources by finding count:\n", len(summary.TopResources))
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for i, rc := range summary.TopResources {
+		fmt.Fprintf(w, "  %d.\t%s\t(%s)\t%d\n", i+1, rc.Resource, rc.Kind, rc.Count)
+	}
+	w.Flush()
+}
+
+// outputRunJSON prints all findings and the run summary as formatted JSON
+func outputRunJSON(output AnalyzerRunOutput) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(output); err != nil {
+		fmt.Printf("Error encoding JSON: %v\n", err)
+	}
+}
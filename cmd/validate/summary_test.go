@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/internal/rules"
+	"github.com/example/tfprovidertest/internal/testsrc"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const gadgetResourceSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+type GadgetResource struct{}
+
+func (r *GadgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{}
+}
+`
+
+const gadgetDataSourceSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+)
+
+type GadgetDataSource struct{}
+
+func (d *GadgetDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{}
+}
+`
+
+// parseProvider parses sources, keyed by file name, in name order and builds the registry
+// validate builds for them.
+func parseProvider(t *testing.T, sources map[string]string) (*token.FileSet, []*ast.File, *registry.ResourceRegistry) {
+	t.Helper()
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range names {
+		file, err := parser.ParseFile(fset, name, sources[name], parser.ParseComments)
+		require.NoError(t, err)
+		files = append(files, file)
+	}
+	return fset, files, discovery.BuildProviderRegistry(fset, files, config.DefaultSettings(), nil)
+}
+
+// testPos returns the position of the named test function.
+func testPos(t *testing.T, reg *registry.ResourceRegistry, name string) token.Pos {
+	t.Helper()
+	for _, fn := range reg.GetAllTestFunctions() {
+		if fn.Name == name {
+			return fn.FunctionPos
+		}
+	}
+	t.Fatalf("no test function %s", name)
+	return token.NoPos
+}
+
+func TestFindingAttributor(t *testing.T) {
+	_, _, reg := parseProvider(t, map[string]string{
+		"/provider/resource_gadget.go":         gadgetResourceSrc,
+		"/provider/resource_gadget_test.go":    testsrc.Acceptance("TestAccGadget_basic", `resource "example_gadget" "g" {}`),
+		"/provider/data_source_gadget.go":      gadgetDataSourceSrc,
+		"/provider/data_source_gadget_test.go": testsrc.Acceptance("TestAccGadgetDataSource_basic", `data "example_gadget" "g" {}`),
+	})
+	resource := reg.GetDefinition(registry.KindResource, "gadget")
+	dataSource := reg.GetDefinition(registry.KindDataSource, "gadget")
+	require.NotNil(t, resource)
+	require.NotNil(t, dataSource)
+	attributor := newFindingAttributor(reg)
+
+	t.Run("schema position", func(t *testing.T) {
+		assert.Same(t, resource, attributor.resourceFor(resource.SchemaPos, resource.FilePath))
+		assert.Same(t, dataSource, attributor.resourceFor(dataSource.SchemaPos, dataSource.FilePath))
+	})
+
+	t.Run("test function of a resource and a data source sharing a name", func(t *testing.T) {
+		pos := testPos(t, reg, "TestAccGadget_basic")
+		assert.Same(t, resource, attributor.resourceFor(pos, "/provider/resource_gadget_test.go"))
+		pos = testPos(t, reg, "TestAccGadgetDataSource_basic")
+		assert.Same(t, dataSource, attributor.resourceFor(pos, "/provider/data_source_gadget_test.go"),
+			"credited to the data source the test is linked to, not the resource of its name")
+	})
+
+	t.Run("file of the definition", func(t *testing.T) {
+		assert.Same(t, dataSource, attributor.resourceFor(token.NoPos, "/provider/data_source_gadget.go"))
+	})
+
+	t.Run("unattributed", func(t *testing.T) {
+		assert.Nil(t, attributor.resourceFor(token.NoPos, "/provider/provider.go"))
+	})
+}
+
+func TestFindingTally(t *testing.T) {
+	t.Run("counts by rule, group, level, kind and resource", func(t *testing.T) {
+		tally := newFindingTally([]string{rules.BasicTest, rules.UpdateTest, rules.DriftCheck})
+		for _, f := range []Finding{
+			{Rule: rules.DriftCheck, Group: "quality", Level: "error", Resource: "gadget", Kind: "resource"},
+			{Rule: rules.DriftCheck, Group: "quality", Level: "warning", Resource: "gadget", Kind: "resource"},
+			{Rule: rules.BasicTest, Group: "coverage", Level: "error", Resource: "gadget", Kind: "data source"},
+			{Rule: rules.BasicTest, Group: "coverage", Level: "error"},
+		} {
+			tally.add(f)
+		}
+
+		summary := tally.summary()
+		assert.Equal(t, 4, summary.TotalFindings)
+		assert.Equal(t, []RuleCount{
+			{Rule: rules.BasicTest, Group: "coverage", Count: 2},
+			{Rule: rules.DriftCheck, Group: "quality", Count: 2},
+			{Rule: rules.UpdateTest, Group: "coverage", Count: 0},
+		}, summary.ByRule, "by count, then rule name, listing rules that reported nothing")
+		assert.Equal(t, map[string]int{"coverage": 2, "quality": 2}, summary.ByGroup)
+		assert.Equal(t, map[string]int{"error": 3, "warning": 1}, summary.ByLevel)
+		assert.Equal(t, map[string]int{"resource": 2, "data source": 1, "unattributed": 1}, summary.ByKind)
+		assert.Equal(t, []ResourceCount{
+			{Resource: "gadget", Kind: "resource", Count: 2},
+			{Resource: "gadget", Kind: "data source", Count: 1},
+		}, summary.TopResources, "a resource and a data source sharing a name are counted apart")
+	})
+
+	t.Run("no findings", func(t *testing.T) {
+		summary := newFindingTally([]string{rules.BasicTest}).summary()
+		assert.Zero(t, summary.TotalFindings)
+		assert.Equal(t, map[string]int{"error": 0}, summary.ByLevel)
+		assert.Equal(t, map[string]int{"coverage": 0}, summary.ByGroup)
+		assert.Empty(t, summary.TopResources)
+	})
+
+	t.Run("top resources are limited, ties by name", func(t *testing.T) {
+		tally := newFindingTally([]string{rules.BasicTest})
+		for i := 0; i < maxTopOffenders+2; i++ {
+			tally.add(Finding{Rule: rules.BasicTest, Group: "coverage", Level: "error", Resource: fmt.Sprintf("r%02d", i), Kind: "resource"})
+		}
+		tally.add(Finding{Rule: rules.BasicTest, Group: "coverage", Level: "error", Resource: "r11", Kind: "resource"})
+
+		top := tally.summary().TopResources
+		require.Len(t, top, maxTopOffenders)
+		assert.Equal(t, ResourceCount{Resource: "r11", Kind: "resource", Count: 2}, top[0])
+		assert.Equal(t, "r00", top[1].Resource)
+		assert.Equal(t, "r08", top[maxTopOffenders-1].Resource)
+	})
+}
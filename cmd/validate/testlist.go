@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/testlist"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// runVerifyTestList checks every discovered test function against `go test -list` and
+// exits non-zero when the toolchain does not list one of them
+func runVerifyTestList(fset *token.FileSet, files []*ast.File, settings config.Settings, format string, tags []string) {
+	if format != "text" && format != "json" {
+		fmt.Printf("Error: Invalid format '%s' for -verify-test-list. Must be one of: text, json\n", format)
+		exit(1)
+	}
+
+	reg := buildRegistryFromFiles(fset, files, settings)
+	opts := testlist.Options{Tags: tags}
+	result := testlist.Verify(context.Background(), fset, reg.GetAllTestFunctions(), testlist.GoTestList(tags), opts)
+
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		}
+	} else {
+		outputTestListText(result)
+	}
+	if len(result.Discrepancies) > 0 {
+		exit(1)
+	}
+}
+
+// outputTestListText prints each discrepancy as a file:line diagnostic followed by a summary
+func outputTestListText(result testlist.Result) {
+	fmt.Println("=== Test Discovery vs go test -list ===")
+	fmt.Println()
+	for _, d := range result.Discrepancies {
+		fmt.Printf("[discovery] %s:%d\n", d.File, d.Line)
+		fmt.Printf("  test '%s' was discovered but is not listed by the toolchain: %s\n", d.Test, d.Describe())
+		switch d.Reason {
+		case testlist.ReasonPackageError:
+			for _, line := range strings.Split(d.Detail, "\n") {
+				fmt.Printf("    %s\n", line)
+			}
+		case testlist.ReasonBuildConstraint:
+			fmt.Println("  Suggestion: Pass the build tags CI runs the tests with via -tags, or remove the constraint")
+		}
+		fmt.Println()
+	}
+	fmt.Printf("%d of %d discovered tests listed by go test across %d packages", result.Listed, result.Discovered, result.Packages)
+	if n := len(result.Discrepancies); n > 0 {
+		fmt.Printf(" (%d discrepancies)", n)
+	}
+	fmt.Println()
+}
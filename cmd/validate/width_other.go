@@ -0,0 +1,10 @@
+//go:build !unix
+
+package main
+
+import "os"
+
+// terminalWidth returns 0 (unlimited) where the terminal size cannot be queried.
+func terminalWidth(f *os.File) int {
+	return 0
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/internal/workspace"
+)
+
+// WorkspaceHelperPackage is a helper package imported by the scanned tests and resolved
+// through go.work to a directory outside the scanned tree
+type WorkspaceHelperPackage struct {
+	ImportPath string
+	Dir        string
+	Helpers    []string // Qualified helper names, e.g. "acctest.RunWidgetTest"
+}
+
+// resolveWorkspaceHelpers parses packages imported by test files that live in other
+// workspace modules and returns the qualified names of the resource.Test wrappers they
+// export, for use as custom test helpers. Packages inside scanDirs are already parsed and
+// are skipped.
+func resolveWorkspaceHelpers(ws *workspace.Workspace, fset *token.FileSet, files []*ast.File, scanDirs []string) []WorkspaceHelperPackage {
+	scanned := make(map[string]bool, len(scanDirs))
+	for _, dir := range scanDirs {
+		if abs, err := filepath.Abs(dir); err == nil {
+			scanned[abs] = true
+		}
+	}
+
+	// Collect the names each helper package is referenced by in the scanned tests
+	names := make(map[string]map[string]bool)
+	for _, file := range files {
+		if !isTestFile(fset, file) {
+			continue
+		}
+		for _, imp := range file.Imports {
+			path, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				continue
+			}
+			if names[path] == nil {
+				names[path] = make(map[string]bool)
+			}
+			if imp.Name != nil && imp.Name.Name != "_" && imp.Name.Name != "." {
+				names[path][imp.Name.Name] = true
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(names))
+	for path := range names {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var result []WorkspaceHelperPackage
+	for _, path := range paths {
+		dir, ok := ws.Resolve(path)
+		if !ok || scanned[dir] {
+			continue
+		}
+		pkgFset := token.NewFileSet()
+		pkgs, err := parser.ParseDir(pkgFset, dir, nil, 0)
+		if err != nil {
+			continue
+		}
+		for pkgName, pkg := range pkgs {
+			pkgFiles := make([]*ast.File, 0, len(pkg.Files))
+			for _, f := range pkg.Files {
+				pkgFiles = append(pkgFiles, f)
+			}
+			helpers := discovery.FindExportedTestHelpers(pkgFiles, pkgFset)
+			if len(helpers) == 0 {
+				continue
+			}
+
+			// Tests may import the package under its own name or an alias
+			qualifiers := []string{pkgName}
+			for alias := range names[path] {
+				if alias != pkgName {
+					qualifiers = append(qualifiers, alias)
+				}
+			}
+			sort.Strings(qualifiers[1:])
+
+			entry := WorkspaceHelperPackage{ImportPath: path, Dir: dir}
+			for _, h := range helpers {
+				for _, q := range qualifiers {
+					entry.Helpers = append(entry.Helpers, q+"."+h.Name)
+				}
+			}
+			sort.Strings(entry.Helpers)
+			result = append(result, entry)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].ImportPath < result[j].ImportPath })
+	return result
+}
+
+// isTestFile reports whether a parsed file is a _test.go file
+func isTestFile(fset *token.FileSet, file *ast.File) bool {
+	return strings.HasSuffix(fset.Position(file.Pos()).Filename, "_test.go")
+}
+
+// printWorkspace writes the workspace composition and resolved helper packages for -verbose
+func printWorkspace(w io.Writer, ws *workspace.Workspace, packages []WorkspaceHelperPackage) {
+	fmt.Fprintf(w, "Go workspace: %s (%d modules)\n", ws.File, len(ws.Modules))
+	for _, m := range ws.Modules {
+		fmt.Fprintf(w, "  %-8s %s => %s\n", m.Source, m.Path, m.Dir)
+	}
+	for _, problem := range ws.Problems {
+		fmt.Fprintf(w, "  Warning: %s\n", problem)
+	}
+	for _, pkg := range packages {
+		fmt.Fprintf(w, "  helpers from %s: %d\n", pkg.ImportPath, len(pkg.Helpers))
+		for _, h := range pkg.Helpers {
+			fmt.Fprintf(w, "    %s\n", h)
+		}
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,149 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+const complexClusterResourceSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+type ClusterResource struct{}
+
+func (r *ClusterResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"name": schema.StringAttribute{
+				Required:   true,
+				Validators: []validator.String{stringvalidator.LengthAtLeast(1)},
+			},
+			"network": schema.SingleNestedAttribute{
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"cidr": schema.StringAttribute{Required: true},
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"node_pool": schema.ListNestedBlock{
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"size": schema.Int64Attribute{Optional: true},
+					},
+					Blocks: map[string]schema.Block{
+						"taint": schema.ListNestedBlock{
+							NestedObject: schema.NestedBlockObject{
+								Attributes: map[string]schema.Attribute{
+									"key": schema.StringAttribute{Required: true},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+`
+
+const complexLegacyResourceSrc = `package provider
+
+import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+func resourceLegacy() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"rule": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"port": {Type: schema.TypeInt, Required: true},
+					},
+				},
+			},
+			"tags": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+`
+
+func TestSchemaComplexity(t *testing.T) {
+	reg := buildRegistryFromSources(t, map[string]string{
+		"/provider/resource_cluster.go":     complexClusterResourceSrc,
+		"/provider/resource_gadget.go":      untestedGadgetResourceSrc,
+		"/provider/resource_legacy.go":      complexLegacyResourceSrc,
+		"/provider/resource_widget.go":      explainWidgetResourceSrc,
+		"/provider/resource_widget_test.go": explainTestSrc,
+	})
+
+	t.Run("framework nested attributes and blocks", func(t *testing.T) {
+		cluster := reg.GetDefinition(registry.KindResource, "cluster")
+		require.NotNil(t, cluster)
+		require.Len(t, cluster.Blocks, 1)
+		assert.Equal(t, "ListNestedBlock", cluster.Blocks[0].Kind)
+		require.Len(t, cluster.Blocks[0].Nested, 2, "the size attribute and the taint block")
+
+		c := analysis.ResourceComplexity(cluster)
+		assert.Equal(t, 6, c.Attributes, "id, name, network, cidr, size and key")
+		assert.Equal(t, 2, c.Blocks, "node_pool and taint")
+		assert.Equal(t, 3, c.MaxDepth, "node_pool > taint > key")
+		assert.Equal(t, 3, c.Required)
+		assert.Equal(t, 2, c.Optional)
+		assert.Equal(t, 1, c.Computed)
+		assert.Equal(t, 1, c.Validators)
+		assert.Equal(t, 1, c.PlanModifiers)
+		assert.Equal(t, map[string]int{
+			"StringAttribute":       4,
+			"Int64Attribute":        1,
+			"SingleNestedAttribute": 1,
+			"ListNestedBlock":       2,
+		}, c.Kinds)
+		// id 1+1, name 1+1+1, network 1, cidr 2+1, node_pool 1, size 2, taint 2, key 3+1
+		assert.Equal(t, 18, c.Score)
+	})
+
+	t.Run("SDK v2 Elem resources nest", func(t *testing.T) {
+		legacy := reg.GetDefinition(registry.KindResource, "legacy")
+		require.NotNil(t, legacy)
+		c := analysis.ResourceComplexity(legacy)
+		assert.Equal(t, 3, c.Attributes, "rule, port and tags; a primitive Elem is not nested")
+		assert.Equal(t, 2, c.MaxDepth)
+		assert.Equal(t, 1, c.Required)
+	})
+
+	t.Run("empty schema", func(t *testing.T) {
+		c := analysis.ResourceComplexity(reg.GetDefinition(registry.KindResource, "gadget"))
+		assert.Equal(t, analysis.Complexity{}, c)
+	})
+
+	t.Run("top risks are untested, most complex first", func(t *testing.T) {
+		risks := analysis.TopRisks(reg, 0)
+		var names []string
+		for _, risk := range risks {
+			names = append(names, risk.Name)
+		}
+		assert.Equal(t, []string{"cluster", "legacy"}, names,
+			"the tested widget and the empty gadget are left out")
+		assert.Len(t, analysis.TopRisks(reg, 1), 1)
+	})
+}
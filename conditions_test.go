@@ -0,0 +1,136 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	goanalysis "golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const gadgetConditionsTestSrc = `package provider
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+
+	"example.com/provider/internal/acctest"
+)
+
+func TestAccGadget_basic(t *testing.T) {
+	if os.Getenv("EXAMPLE_TOKEN") == "" || testing.Short() {
+		t.Skip("EXAMPLE_TOKEN must be set")
+	}
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() { acctest.PreCheck(t) },
+		Steps: []resource.TestStep{{
+			Config: ` + "`" + `resource "example_gadget" "g" {}` + "`" + `,
+		}},
+	})
+}
+
+func TestAccGadget_commercial(t *testing.T) {
+	if os.Getenv("TF_ACC_GOVCLOUD") != "" {
+		t.Skip("not available in GovCloud")
+	}
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{
+			Config: ` + "`" + `resource "example_gadget" "g" {}` + "`" + `,
+		}},
+	})
+}
+
+func TestAccGadget_writeOnly(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+			tfversion.RequireNot(tfversion.Version1_12_0),
+		},
+		Steps: []resource.TestStep{{
+			Config: ` + "`" + `resource "example_gadget" "g" {}` + "`" + `,
+		}},
+	})
+}
+
+func TestAccGadget_region(t *testing.T) {
+	region := os.Getenv("EXAMPLE_REGION")
+	if region != "us-west-2" {
+		t.Skipf("only available in us-west-2, not %s", region)
+	}
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() { acctest.PreCheckPartitionHasService(t, "gadgets") },
+		Steps: []resource.TestStep{{
+			Config:   ` + "`" + `resource "example_gadget" "g" {}` + "`" + `,
+			SkipFunc: acctest.SkipUnlessFeature("gadget-tags"),
+		}},
+	})
+}
+`
+
+func TestConditionalCoverage(t *testing.T) {
+	sources := map[string]string{
+		"/provider/resource_gadget.go":      untestedGadgetResourceSrc,
+		"/provider/resource_gadget_test.go": gadgetConditionsTestSrc,
+	}
+	reg := buildRegistryFromSources(t, sources)
+
+	conditions := func(test *registry.TestFunctionInfo) []string {
+		var result []string
+		for _, c := range test.Conditions {
+			result = append(result, c.String())
+		}
+		return result
+	}
+
+	t.Run("credential checks are not conditions", func(t *testing.T) {
+		test := findTest(t, reg, "TestAccGadget_basic")
+		assert.Empty(t, test.Conditions)
+		assert.False(t, test.IsConditional())
+	})
+
+	t.Run("skips when a variable is set are conditions", func(t *testing.T) {
+		test := findTest(t, reg, "TestAccGadget_commercial")
+		assert.Equal(t, []string{`skip: os.Getenv("TF_ACC_GOVCLOUD") != ""`}, conditions(test))
+		assert.True(t, test.IsConditional())
+	})
+
+	t.Run("Terraform version checks that skip are conditions", func(t *testing.T) {
+		test := findTest(t, reg, "TestAccGadget_writeOnly")
+		assert.Equal(t, []string{"terraform-version: tfversion.SkipBelow(tfversion.Version1_11_0)"}, conditions(test))
+		assert.True(t, test.IsConditional())
+	})
+
+	t.Run("region skips, guard helpers and SkipFunc are conditions", func(t *testing.T) {
+		test := findTest(t, reg, "TestAccGadget_region")
+		assert.Equal(t, []string{
+			`skip: region != "us-west-2"`,
+			"guard: acctest.PreCheckPartitionHasService",
+			"skip-func: acctest.SkipUnlessFeature",
+		}, conditions(test))
+	})
+
+	t.Run("conditional-guards replaces the default guards", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		settings.ConditionalGuards = []string{"PreCheck"}
+		require.NoError(t, settings.Validate())
+		names := []string{"/provider/resource_gadget.go", "/provider/resource_gadget_test.go"}
+		fset, files := parseSources(t, names, sources)
+		reg := discovery.BuildRegistry(&goanalysis.Pass{Fset: fset, Files: files}, settings)
+
+		assert.Equal(t, []string{"guard: acctest.PreCheck"}, conditions(findTest(t, reg, "TestAccGadget_basic")))
+		assert.Equal(t, []string{
+			`skip: region != "us-west-2"`,
+			"skip-func: acctest.SkipUnlessFeature",
+		}, conditions(findTest(t, reg, "TestAccGadget_region")))
+
+		settings.ConditionalGuards = []string{"[PreCheck"}
+		assert.ErrorContains(t, settings.Validate(), "conditional-guards")
+	})
+}
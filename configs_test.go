@@ -0,0 +1,86 @@
+package tfprovidertest
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/discovery"
+)
+
+func TestCatalogConfigs(t *testing.T) {
+	testSrc := `package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+const testAccWidgetTemplate = ` + "`" + `
+resource "example_widget" "test" {
+  name = %q
+}
+` + "`" + `
+
+func TestAccWidget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccWidgetConfig("a")},
+			{Config: fmt.Sprintf(testAccWidgetTemplate, "b")},
+		},
+	})
+}
+
+func TestAccWidget_update(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccWidgetConfig("a")},
+			{Config: "resource \"example_widget\" \"test\" {\n\n  # inline\n  name    = \"a\"\n}"},
+		},
+	})
+}
+
+func TestAccWidget_dynamic(t *testing.T) {
+	name := randomName()
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccWidgetConfig(name)},
+			{Config: configs[0]},
+		},
+	})
+}
+
+func testAccWidgetConfig(name string) string {
+	return fmt.Sprintf(testAccWidgetTemplate, name)
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "/p/resource_widget_test.go", testSrc, 0)
+	require.NoError(t, err)
+
+	corpus := discovery.CatalogConfigs([]*ast.File{file}, fset)
+	assert.Equal(t, 6, corpus.Steps)
+	assert.Equal(t, 1, corpus.Unresolved, "configs[0] cannot be resolved")
+	require.Len(t, corpus.Configs, 3)
+
+	// The helper with "a", and the inline config that differs only in whitespace and comments
+	reused := corpus.Configs[0]
+	assert.Equal(t, "resource \"example_widget\" \"test\" {\nname = \"a\"\n}", reused.Config)
+	assert.Len(t, reused.Uses, 3)
+	assert.Equal(t, []string{"TestAccWidget_update", "testAccWidgetConfig"}, reused.Producers())
+	assert.Equal(t, []string{"TestAccWidget_basic", "TestAccWidget_update"}, reused.Tests())
+
+	byProducer := make(map[string]discovery.ConfigInfo)
+	for _, info := range corpus.Configs[1:] {
+		require.Len(t, info.Uses, 1)
+		byProducer[info.Uses[0].Producer] = info
+	}
+	assert.Contains(t, byProducer["testAccWidgetTemplate"].Config, `name = "b"`)
+	assert.Contains(t, byProducer["testAccWidgetConfig"].Config, "name = "+discovery.DynamicPlaceholder)
+}
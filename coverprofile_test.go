@@ -0,0 +1,109 @@
+package tfprovidertest
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	goanalysis "golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/coverprofile"
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const lifecycleWidgetSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+type WidgetResource struct{}
+
+func (r *WidgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{}
+}
+
+func (r *WidgetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	create()
+}
+
+func (r *WidgetResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	read()
+}
+
+func (r *WidgetResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	update()
+}
+
+func (r *WidgetResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+}
+`
+
+const lifecycleWidgetTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccWidget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: ` + "`" + `resource "example_widget" "w" {}` + "`" + `}},
+	})
+}
+`
+
+// Two test binaries instrumented the package (-coverpkg), so blocks repeat.
+const widgetProfile = `mode: set
+github.com/example/terraform-provider-example/internal/provider/resource_widget.go:16.113,18.2 1 1
+github.com/example/terraform-provider-example/internal/provider/resource_widget.go:20.107,22.2 1 0
+github.com/example/terraform-provider-example/internal/provider/resource_widget.go:24.113,26.2 1 0
+github.com/example/terraform-provider-example/internal/provider/resource_widget.go:20.107,22.2 1 1
+github.com/example/terraform-provider-example/internal/other/resource_widget.go:16.113,18.2 1 0
+`
+
+func TestCoverProfileCorrelation(t *testing.T) {
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for name, src := range map[string]string{
+		"/src/internal/provider/resource_widget.go":      lifecycleWidgetSrc,
+		"/src/internal/provider/resource_widget_test.go": lifecycleWidgetTestSrc,
+	} {
+		f, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+		require.NoError(t, err)
+		files = append(files, f)
+	}
+	reg := discovery.BuildRegistry(&goanalysis.Pass{Fset: fset, Files: files}, config.DefaultSettings())
+
+	profile, err := coverprofile.Parse(strings.NewReader(widgetProfile))
+	require.NoError(t, err)
+	assert.Equal(t, "set", profile.Mode)
+
+	report := coverprofile.Correlate(fset, reg, profile)
+	require.Len(t, report.Definitions, 1)
+	widget := report.Definitions[0]
+	assert.True(t, widget.InProfile)
+	assert.Equal(t, 1, widget.Tests)
+	assert.Equal(t, []coverprofile.Method{
+		{Name: "Create", Line: 16, Statements: 1, Covered: 1},
+		{Name: "Read", Line: 20, Statements: 1, Covered: 1},
+		{Name: "Update", Line: 24, Statements: 1, Covered: 0},
+		{Name: "Delete", Line: 28},
+	}, widget.Methods, "repeated blocks merge, and the empty Delete has no statements")
+
+	require.Len(t, report.Gaps, 1)
+	assert.Equal(t, []string{"Update"}, report.Gaps[0].NeverExecuted())
+	assert.Empty(t, report.NotInProfile)
+
+	_, err = coverprofile.Parse(strings.NewReader("not a profile\n"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,97 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/testsrc"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const lookupDataSourceSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+)
+
+type GadgetDataSource struct{}
+
+func (d *GadgetDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id":         schema.StringAttribute{Computed: true},
+			"name":       schema.StringAttribute{Required: true},
+			"tags":       schema.MapAttribute{Optional: true, Computed: true},
+			"arn":        schema.StringAttribute{Computed: true},
+			"created_at": schema.StringAttribute{Computed: true},
+		},
+	}
+}
+`
+
+func TestDataSourceAssertsAnalyzer(t *testing.T) {
+	settings := config.DefaultSettings()
+	settings.EnableDataSourceAssertCheck = true
+	run := func(t *testing.T, settings config.Settings, field string) []string {
+		return runAnalyzerOnSources(t, analysis.RunDataSourceAssertsAnalyzer, settings, map[string]string{
+			"/provider/data_source_gadget.go": lookupDataSourceSrc,
+			"/provider/data_source_gadget_test.go": testsrc.File(testsrc.Test{
+				Name: "TestAccGadgetDataSource_basic",
+				Imports: []string{
+					"fmt",
+					"github.com/hashicorp/terraform-plugin-testing/knownvalue",
+					"github.com/hashicorp/terraform-plugin-testing/statecheck",
+					"github.com/hashicorp/terraform-plugin-testing/tfjsonpath",
+				},
+				Setup: `address := "data.example_gadget.test"`,
+				Steps: []string{testsrc.Config(`resource "example_gadget" "test" { name = "a" }
+data "example_gadget" "test" { name = example_gadget.test.name }`) + ",\n\t\t\t" + field},
+			}),
+		})
+	}
+
+	t.Run("only id asserted", func(t *testing.T) {
+		messages := run(t, settings, `Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrSet(address, "id"),
+				resource.TestCheckResourceAttr("data.example_gadget.test", "tags.%", "0"),
+			)`)
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "the 1 test(s) of data source 'gadget' assert 0 of its 2 computed attribute(s) besides id, fewer than 1")
+		assert.Contains(t, messages[0], "Data source: /provider/data_source_gadget.go:12")
+		assert.Contains(t, messages[0], "Not asserted: arn, created_at")
+	})
+
+	t.Run("computed attribute asserted by a state check", func(t *testing.T) {
+		field := `ConfigStateChecks: []statecheck.StateCheck{
+				statecheck.ExpectKnownValue(address, tfjsonpath.New("arn"), knownvalue.NotNull()),
+			}`
+		assert.Empty(t, run(t, settings, field))
+
+		strict := settings
+		strict.DataSourceMinComputedAsserts = 2
+		messages := run(t, strict, field)
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "assert 1 of its 2 computed attribute(s) besides id, fewer than 2")
+		assert.Contains(t, messages[0], "Not asserted: created_at")
+	})
+
+	t.Run("minimum capped at the computed attributes", func(t *testing.T) {
+		settings := settings
+		settings.DataSourceMinComputedAsserts = 5
+		assert.Empty(t, run(t, settings, `Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrSet(address, "arn"),
+				resource.TestMatchResourceAttr(address, "created_at", regexp.MustCompile("^2")),
+			)`))
+	})
+
+	t.Run("address not known statically", func(t *testing.T) {
+		assert.Empty(t, run(t, settings, `Check: resource.TestCheckResourceAttrSet(fmt.Sprintf("data.example_gadget.%s", "test"), "arn")`),
+			"the check may assert a computed attribute")
+	})
+}
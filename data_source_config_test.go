@@ -0,0 +1,89 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/testsrc"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const gadgetDataSourceSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+)
+
+type GadgetDataSource struct{}
+
+func (d *GadgetDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{}
+}
+`
+
+const regionsDataSourceSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+)
+
+//tftest:exempt managed-config reason="regions are global"
+type RegionsDataSource struct{}
+
+func (d *RegionsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{}
+}
+`
+
+func TestDataSourceConfigAnalyzer(t *testing.T) {
+	settings := config.DefaultSettings()
+	settings.EnableDataSourceConfigCheck = true
+
+	t.Run("reads a resource the config does not create", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunDataSourceConfigAnalyzer, settings, map[string]string{
+			"/provider/resource_gadget.go":         untestedGadgetResourceSrc,
+			"/provider/data_source_gadget.go":      gadgetDataSourceSrc,
+			"/provider/data_source_gadget_test.go": testsrc.Acceptance("TestAccGadgetDataSource_basic", `data "example_gadget" "g" { id = "existing" }`),
+		})
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], `test 'TestAccGadgetDataSource_basic' of data source 'gadget' declares no resource "example_gadget"`)
+		assert.Contains(t, messages[0], "Test: /provider/data_source_gadget_test.go:9")
+	})
+
+	t.Run("config creates the resource it reads", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunDataSourceConfigAnalyzer, settings, map[string]string{
+			"/provider/resource_gadget.go":    untestedGadgetResourceSrc,
+			"/provider/data_source_gadget.go": gadgetDataSourceSrc,
+			"/provider/data_source_gadget_test.go": testsrc.Acceptance("TestAccGadgetDataSource_basic",
+				`resource "example_gadget" "g" {}
+data "example_gadget" "g" { id = example_gadget.g.id }`),
+		})
+		assert.Empty(t, messages)
+	})
+
+	t.Run("without a resource of its type any managed resource counts", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunDataSourceConfigAnalyzer, settings, map[string]string{
+			"/provider/data_source_gadget.go":      gadgetDataSourceSrc,
+			"/provider/data_source_gadget_test.go": testsrc.Acceptance("TestAccGadgetDataSource_basic", `data "example_gadget" "g" {}`),
+		})
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "of data source 'gadget' declares no managed resource")
+	})
+
+	t.Run("exempt data source", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunDataSourceConfigAnalyzer, settings, map[string]string{
+			"/provider/data_source_regions.go":      regionsDataSourceSrc,
+			"/provider/data_source_regions_test.go": testsrc.Acceptance("TestAccRegionsDataSource_basic", `data "example_regions" "all" {}`),
+		})
+		assert.Empty(t, messages)
+	})
+}
@@ -0,0 +1,75 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const deadTestsSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// testAccWidgetRun wraps resource.Test(t, ...) for widget tests.
+func testAccWidgetRun(t *testing.T, c resource.TestCase) {
+	resource.Test(t, c)
+}
+
+// func TestAccWidget_update(t *testing.T) {
+// 	resource.Test(t, resource.TestCase{
+// 		Steps: []resource.TestStep{
+// 			{Config: testAccWidgetConfig("one")},
+
+// 			{Config: testAccWidgetConfig("two")},
+// 		},
+// 	})
+// }
+
+func TestAccWidget_basic(t *testing.T) {
+	// func TestAccWidget_tiny(t *testing.T) {}
+	testAccWidgetRun(t, resource.TestCase{})
+}
+
+func TestAccWidget_disabled(t *testing.T) {
+	t.Skip("flaky upstream API")
+	/*
+		resource.Test(t, resource.TestCase{
+			Steps: []resource.TestStep{
+				{Config: testAccWidgetConfig("one")},
+			},
+		})
+	*/
+}
+`
+
+func TestDeadTestsAnalyzer(t *testing.T) {
+	sources := map[string]string{
+		"/provider/resource_widget.go":      importIdResourceSrc,
+		"/provider/resource_widget_test.go": deadTestsSrc,
+	}
+
+	settings := config.DefaultSettings()
+	settings.EnableDeadTestCheck = true
+	diags := runAnalyzerOnSources(t, analysis.RunDeadTestsAnalyzer, settings, sources)
+	require.Len(t, diags, 2)
+	assert.Contains(t, diags[0], "commented-out acceptance test 'TestAccWidget_update' (9 lines)")
+	assert.Contains(t, diags[0], "resource_widget_test.go:14")
+	assert.Contains(t, diags[1], "commented-out resource.Test call (7 lines)")
+
+	t.Run("minimum block size", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		settings.EnableDeadTestCheck = true
+		settings.DeadTestMinLines = 8
+		diags := runAnalyzerOnSources(t, analysis.RunDeadTestsAnalyzer, settings, sources)
+		require.Len(t, diags, 1)
+		assert.Contains(t, diags[0], "TestAccWidget_update")
+	})
+}
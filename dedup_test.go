@@ -0,0 +1,72 @@
+package tfprovidertest
+
+import (
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/dedup"
+	"github.com/example/tfprovidertest/internal/rules"
+)
+
+func TestDiagnosticDedup(t *testing.T) {
+	t.Run("reported once across package variants", func(t *testing.T) {
+		plugin, err := New(nil)
+		require.NoError(t, err)
+		messages := runBasicAnalyzerPerPackage(t, plugin.(*Plugin), 2)
+		assert.Len(t, messages, 1)
+	})
+
+	t.Run("can be disabled", func(t *testing.T) {
+		plugin, err := New(map[string]interface{}{"EnableBasicTest": true, "DedupDiagnostics": false})
+		require.NoError(t, err)
+		messages := runBasicAnalyzerPerPackage(t, plugin.(*Plugin), 2)
+		assert.Len(t, messages, 2)
+	})
+
+	t.Run("shared directory coordinates separate plugin instances", func(t *testing.T) {
+		dir := t.TempDir()
+		settings := map[string]interface{}{"EnableBasicTest": true, "DedupDir": dir}
+
+		first, err := New(settings)
+		require.NoError(t, err)
+		second, err := New(settings)
+		require.NoError(t, err)
+
+		assert.Len(t, runBasicAnalyzerPerPackage(t, first.(*Plugin), 1), 1)
+		assert.Empty(t, runBasicAnalyzerPerPackage(t, second.(*Plugin), 1))
+	})
+
+	t.Run("validate reads the dedup settings from the settings file", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("builds the validate command")
+		}
+		binary := buildValidate(t)
+		run := func(settings string) string {
+			path := filepath.Join(t.TempDir(), "tfprovidertest.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(settings), 0o644))
+			return string(runValidate(t, binary, "-provider", "namingdata", "-recursive", "-config", path))
+		}
+
+		shared := "dedup-dir: " + filepath.Join(t.TempDir(), "claims") + "\n"
+		assert.Contains(t, run(shared), "Found 3 issue(s)")
+		assert.Contains(t, run(shared), "No issues found", "a second run sharing dedup-dir reports nothing new")
+
+		disabled := shared + "dedup-diagnostics: false\n"
+		assert.Contains(t, run(disabled), "Found 3 issue(s)")
+		assert.Contains(t, run(disabled), "Found 3 issue(s)")
+	})
+
+	t.Run("claims are keyed by rule, position and message", func(t *testing.T) {
+		c := dedup.New("")
+		pos := token.Position{Filename: "/provider/a.go", Line: 3, Column: 1}
+		assert.True(t, c.Claim(dedup.Key(rules.BasicTest, pos, "msg")))
+		assert.False(t, c.Claim(dedup.Key(rules.BasicTest, pos, "msg")))
+		assert.True(t, c.Claim(dedup.Key(rules.DriftCheck, pos, "msg")))
+		assert.True(t, c.Claim(dedup.Key(rules.BasicTest, pos, "other")))
+	})
+}
@@ -0,0 +1,103 @@
+package tfprovidertest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const defaultValuesResourceSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+)
+
+type WidgetResource struct{}
+
+func (r *WidgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{Required: true},
+			"size": schema.Int64Attribute{
+				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(1),
+			},
+			"mode": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString("fast"),
+			},
+		},
+	}
+}
+`
+
+const defaultValuesTestSrc = `package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccWidget_basic(t *testing.T) {
+	name := "test"
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccWidgetConfig(name, "slow")},
+			{Config: ` + "`" + `
+resource "example_widget" "test" {
+  name = "b"
+  size = 3
+
+  # mode is left to its default
+}
+` + "`" + `},
+		},
+	})
+}
+
+func testAccWidgetConfig(name, mode string) string {
+	return fmt.Sprintf(` + "`" + `
+resource "example_widget" "test" {
+  name = %q
+  size = 2
+  mode = %q
+}
+` + "`" + `, name, mode)
+}
+`
+
+func TestDefaultValuesAnalyzer(t *testing.T) {
+	settings := config.DefaultSettings()
+	settings.EnableDefaultValueCheck = true
+
+	messages := runAnalyzerOnSources(t, analysis.RunDefaultValuesAnalyzer, settings, map[string]string{
+		"/provider/resource_widget.go":      defaultValuesResourceSrc,
+		"/provider/resource_widget_test.go": defaultValuesTestSrc,
+	})
+	require.Len(t, messages, 1, "mode is omitted by the second step")
+	assert.Contains(t, messages[0], "attribute 'size' of resource 'widget' has a default value, but all 2 test configs declaring example_widget set it")
+	assert.Contains(t, messages[0], "Default: /provider/resource_widget.go:21")
+
+	t.Run("unresolved configs may omit the attribute", func(t *testing.T) {
+		unresolved := strings.Replace(defaultValuesTestSrc, "{Config: testAccWidgetConfig(name, \"slow\")}", "{Config: configs[0]}", 1)
+		messages := runAnalyzerOnSources(t, analysis.RunDefaultValuesAnalyzer, settings, map[string]string{
+			"/provider/resource_widget.go":      defaultValuesResourceSrc,
+			"/provider/resource_widget_test.go": unresolved,
+		})
+		assert.Empty(t, messages)
+	})
+}
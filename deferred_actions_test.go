@@ -0,0 +1,122 @@
+package tfprovidertest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const deferringResourceSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+type WidgetResource struct{}
+
+func (r *WidgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{Required: true},
+		},
+	}
+}
+
+func (r *WidgetResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.ClientCapabilities.DeferralAllowed && req.Plan.Raw.IsFullyKnown() == false {
+		resp.Deferred = &resource.Deferred{Reason: resource.DeferredReasonResourceConfigUnknown}
+	}
+}
+`
+
+const deferringTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccWidget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccWidgetConfig("one")},
+		},
+	})
+}
+`
+
+func TestDeferredActionsAnalyzer(t *testing.T) {
+	settings := config.DefaultSettings()
+
+	t.Run("deferring resource without a deferral test", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunDeferredActionsAnalyzer, settings, map[string]string{
+			"/provider/resource_widget.go":      deferringResourceSrc,
+			"/provider/resource_widget_test.go": deferringTestSrc,
+		})
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "resource 'widget' defers changes in ModifyPlan (resp.Deferred) but no acceptance test allows deferral")
+		assert.Contains(t, messages[0], "/provider/resource_widget.go:22")
+	})
+
+	t.Run("a test allowing deferral covers the provider", func(t *testing.T) {
+		deferralTest := strings.Replace(deferringTestSrc, `{Config: testAccWidgetConfig("one")},`,
+			`{
+				Config: testAccWidgetConfig("one"),
+				AdditionalCLIOptions: &resource.AdditionalCLIOptions{
+					Plan: resource.PlanOptions{AllowDeferral: true},
+				},
+			},`, 1)
+		messages := runAnalyzerOnSources(t, analysis.RunDeferredActionsAnalyzer, settings, map[string]string{
+			"/provider/resource_widget.go":      deferringResourceSrc,
+			"/provider/resource_widget_test.go": deferralTest,
+		})
+		assert.Empty(t, messages)
+	})
+
+	t.Run("resources that never defer are not reported", func(t *testing.T) {
+		nonDeferring := strings.Replace(deferringResourceSrc, "resp.Deferred = ", "_ = ", 1)
+		messages := runAnalyzerOnSources(t, analysis.RunDeferredActionsAnalyzer, settings, map[string]string{
+			"/provider/resource_widget.go":      nonDeferring,
+			"/provider/resource_widget_test.go": deferringTestSrc,
+		})
+		assert.Empty(t, messages)
+	})
+
+	t.Run("deferring provider Configure", func(t *testing.T) {
+		providerSrc := `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+)
+
+type ExampleProvider struct{}
+
+func (p *ExampleProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
+	resp.Schema = schema.Schema{}
+}
+
+func (p *ExampleProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	resp.Deferred = &provider.Deferred{Reason: provider.DeferredReasonProviderConfigUnknown}
+}
+`
+		messages := runAnalyzerOnSources(t, analysis.RunDeferredActionsAnalyzer, settings, map[string]string{
+			"/provider/provider.go":             providerSrc,
+			"/provider/resource_widget.go":      strings.Replace(deferringResourceSrc, "resp.Deferred = ", "_ = ", 1),
+			"/provider/resource_widget_test.go": deferringTestSrc,
+		})
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "provider 'ExampleProvider' defers changes in Configure")
+	})
+}
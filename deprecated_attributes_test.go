@@ -0,0 +1,145 @@
+package tfprovidertest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const deprecatedBucketResourceSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+type BucketResource struct{}
+
+func (r *BucketResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{Required: true},
+			"acl": schema.StringAttribute{
+				Optional:           true,
+				DeprecationMessage: "Use ` + "`access_policy`" + ` instead.",
+			},
+			"access_policy": schema.StringAttribute{Optional: true},
+			"region": schema.StringAttribute{
+				Optional:           true,
+				DeprecationMessage: "Set the region on the provider.",
+			},
+			"versioned": schema.BoolAttribute{
+				Optional:           true,
+				DeprecationMessage: "",
+			},
+		},
+	}
+}
+`
+
+const deprecatedBucketTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccBucket_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: ` + "`" + `
+resource "example_bucket" "test" {
+  name   = "test"
+  acl    = "private"
+  region = "us-east-1"
+}
+` + "`" + `,
+			},
+			{
+				Config: ` + "`" + `
+resource "example_bucket" "test" {
+  name = "test"
+  acl  = "public-read"
+}
+` + "`" + `,
+			},
+		},
+	})
+}
+
+func TestAccBucket_name(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: ` + "`" + `
+resource "example_bucket" "test" {
+  name = "other"
+}
+` + "`" + `,
+			},
+		},
+	})
+}
+`
+
+func TestDeprecatedAttributes(t *testing.T) {
+	sources := map[string]string{
+		"/provider/resource_bucket.go":      deprecatedBucketResourceSrc,
+		"/provider/resource_bucket_test.go": deprecatedBucketTestSrc,
+	}
+
+	t.Run("schema deprecations", func(t *testing.T) {
+		reg := buildRegistryFromSources(t, sources)
+		bucket := reg.GetDefinition(registry.KindResource, "bucket")
+		require.NotNil(t, bucket)
+		deprecated := map[string]string{}
+		for _, attr := range bucket.Attributes {
+			if attr.Deprecated {
+				deprecated[attr.Name] = attr.DeprecationMessage
+			}
+		}
+		assert.Equal(t, map[string]string{
+			"acl":    "Use `access_policy` instead.",
+			"region": "Set the region on the provider.",
+		}, deprecated, "an empty message does not deprecate an attribute")
+	})
+
+	t.Run("replacement never set", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		settings.EnableDeprecatedAttributeCheck = true
+		messages := runAnalyzerOnSources(t, analysis.RunDeprecatedAttributesAnalyzer, settings, sources)
+
+		require.Len(t, messages, 1, strings.Join(messages, "\n\n"))
+		assert.Contains(t, messages[0], "test 'TestAccBucket_basic' sets deprecated attribute 'acl' of bucket, but no test sets its replacement 'access_policy'")
+		assert.Contains(t, messages[0], "Deprecation: Use `access_policy` instead.")
+		assert.NotContains(t, strings.Join(messages, "\n"), "'region'", "a deprecation naming no attribute has no replacement to check")
+	})
+
+	t.Run("replacement set by another test", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		settings.EnableDeprecatedAttributeCheck = true
+		covered := map[string]string{
+			"/provider/resource_bucket.go": deprecatedBucketResourceSrc,
+			"/provider/resource_bucket_test.go": strings.Replace(deprecatedBucketTestSrc,
+				`  name = "other"`, `  name          = "other"
+  access_policy = "private"`, 1),
+		}
+		messages := runAnalyzerOnSources(t, analysis.RunDeprecatedAttributesAnalyzer, settings, covered)
+		assert.Empty(t, messages)
+	})
+
+	t.Run("settings", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		assert.False(t, settings.DeprecatedAttributeCheckEnabled(), "the rule is opt-in")
+	})
+}
@@ -0,0 +1,64 @@
+package tfprovidertest
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/pkg/config"
+	"github.com/example/tfprovidertest/pkg/diagnose"
+)
+
+const unmatchedTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccLegacyThing_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: ` + "`" + `resource "example_legacy_thing" "t" {}` + "`" + `}},
+	})
+}
+`
+
+func TestDiagnoseRegistry(t *testing.T) {
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, source := range [][2]string{
+		{"/provider/resource_gadget.go", untestedGadgetResourceSrc},
+		{"/provider/resource_gadget_test.go", gadgetResourceTestSrc},
+		{"/provider/resource_sprocket.go", untestedSprocketResourceSrc},
+		{"/provider/legacy_test.go", unmatchedTestSrc},
+	} {
+		f, err := parser.ParseFile(fset, source[0], source[1], parser.ParseComments)
+		require.NoError(t, err)
+		files = append(files, f)
+	}
+	reg := diagnose.BuildRegistry(fset, files, config.DefaultSettings())
+
+	matches := reg.GetResourceTestMatches()
+	require.Len(t, matches, 1)
+	assert.Equal(t, "resource", matches[0].Kind)
+	assert.Equal(t, "gadget", matches[0].ResourceName)
+	assert.Equal(t, "TestAccGadget_basic", matches[0].TestFunction)
+	assert.Equal(t, "/provider/resource_gadget_test.go", matches[0].TestFile)
+	assert.Positive(t, matches[0].Confidence)
+
+	unmatched := reg.GetUnmatchedTests()
+	require.Len(t, unmatched, 1)
+	assert.Equal(t, "TestAccLegacyThing_basic", unmatched[0].TestFunction)
+	assert.Equal(t, 9, unmatched[0].Line)
+
+	orphaned := reg.GetOrphanedResources()
+	require.Len(t, orphaned, 1)
+	assert.Equal(t, "sprocket", orphaned[0].ResourceName)
+	assert.Equal(t, "TestAccSprocket_basic", orphaned[0].ExpectedTestFunction)
+	assert.Equal(t, "resource_sprocket_test.go", orphaned[0].ExpectedTestFile)
+}
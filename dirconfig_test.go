@@ -0,0 +1,114 @@
+package tfprovidertest
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	goanalysis "golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/dirconfig"
+	"github.com/example/tfprovidertest/internal/enforcement"
+	"github.com/example/tfprovidertest/internal/rules"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+func TestDirConfigs(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel, content string) {
+		path := filepath.Join(root, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	write("go.mod", "module example.com/provider\n")
+	write("internal/legacy/"+dirconfig.File, "level: warning\ndisable: [tfprovider-coverage-basic-test, TFPT004]\n")
+	write("internal/legacy/ready/"+dirconfig.File, "level: error\nenable: [tfprovider-coverage-basic-test]\n")
+	write("internal/broken/"+dirconfig.File, "levle: warning\n")
+
+	t.Run("merged from the root down", func(t *testing.T) {
+		loader := dirconfig.NewLoader()
+
+		resolved, err := loader.ForFile(filepath.Join(root, "internal/legacy/widget/resource_widget.go"))
+		require.NoError(t, err)
+		assert.Equal(t, enforcement.LevelWarning, resolved.Level)
+		assert.Equal(t, []string{rules.BasicTest, rules.ErrorTest}, resolved.DisabledRules())
+
+		resolved, err = loader.ForFile(filepath.Join(root, "internal/legacy/ready/resource_gadget.go"))
+		require.NoError(t, err)
+		assert.Equal(t, enforcement.LevelError, resolved.Level, "the nearest file wins")
+		assert.Equal(t, []string{rules.ErrorTest}, resolved.DisabledRules())
+		assert.Len(t, resolved.Files, 2)
+
+		resolved, err = loader.ForFile(filepath.Join(root, "internal/other/resource_sprocket.go"))
+		require.NoError(t, err)
+		assert.Empty(t, resolved.Level)
+		assert.Empty(t, resolved.Files)
+
+		_, err = loader.ForFile(filepath.Join(root, "internal/broken/resource_broken.go"))
+		assert.ErrorContains(t, err, "field levle not found")
+	})
+
+	t.Run("invalid overrides", func(t *testing.T) {
+		_, err := dirconfig.Parse([]byte("level: info\n"), "x.yaml")
+		assert.ErrorContains(t, err, `unknown level "info"`)
+		_, err = dirconfig.Parse([]byte("disable: [tfprovider-nope]\n"), "x.yaml")
+		assert.ErrorContains(t, err, `disable: unknown rule "tfprovider-nope"`)
+	})
+
+	t.Run("plugin", func(t *testing.T) {
+		analysis.ClearAllRegistryCaches()
+		t.Cleanup(analysis.ClearAllRegistryCaches)
+
+		fset := token.NewFileSet()
+		var files []*ast.File
+		for rel, src := range map[string]string{
+			"internal/legacy/resource_gadget.go":         untestedGadgetResourceSrc,
+			"internal/legacy/ready/resource_sprocket.go": untestedSprocketResourceSrc,
+		} {
+			f, err := parser.ParseFile(fset, filepath.Join(root, rel), src, parser.ParseComments)
+			require.NoError(t, err)
+			files = append(files, f)
+		}
+
+		run := func(settings config.Settings) map[string]goanalysis.Diagnostic {
+			plugin := NewWithSettings(settings)
+			analyzers, err := plugin.BuildAnalyzers()
+			require.NoError(t, err)
+			byFile := make(map[string]goanalysis.Diagnostic)
+			for _, a := range analyzers {
+				if a.Name != rules.BasicTest {
+					continue
+				}
+				_, err := a.Run(&goanalysis.Pass{
+					Analyzer: a,
+					Fset:     fset,
+					Files:    files,
+					Report:   func(d goanalysis.Diagnostic) { byFile[filepath.Base(fset.Position(d.Pos).Filename)] = d },
+				})
+				require.NoError(t, err)
+			}
+			return byFile
+		}
+
+		settings := config.DefaultSettings()
+		settings.WarnOnlyPaths = []string{"internal/**"}
+		byFile := run(settings)
+		require.Len(t, byFile, 1, "the basic-test rule is disabled in internal/legacy")
+		assert.Empty(t, byFile["resource_sprocket.go"].Category, "level: error overrides warn-only-paths")
+
+		settings.IgnoreDirConfigs = true
+		byFile = run(settings)
+		require.Len(t, byFile, 2)
+		for _, d := range byFile {
+			assert.Equal(t, "warning", d.Category)
+			assert.True(t, strings.HasPrefix(d.Message, enforcement.WarningPrefix))
+		}
+	})
+}
@@ -0,0 +1,201 @@
+package tfprovidertest
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const directiveResourceSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+// WidgetResource manages widgets.
+//
+//tftest:expect basic,import
+//tftest:exempt drift reason="destroy is a no-op"
+type WidgetResource struct{}
+
+func (r *WidgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{Optional: true},
+		},
+	}
+}
+`
+
+const directiveTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccWidget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: "resource \"example_widget\" \"test\" {}"},
+		},
+	})
+}
+`
+
+func TestCoverageDirectives(t *testing.T) {
+	sources := map[string]string{
+		"/provider/resource_widget.go":      directiveResourceSrc,
+		"/provider/resource_widget_test.go": directiveTestSrc,
+	}
+	settings := config.DefaultSettings()
+
+	t.Run("exempt suppresses the drift diagnostic", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunDriftCheckAnalyzer, settings, sources)
+		assert.Empty(t, messages)
+
+		unannotated := strings.Replace(directiveResourceSrc, "//tftest:exempt drift", "// exempt drift", 1)
+		messages = runAnalyzerOnSources(t, analysis.RunDriftCheckAnalyzer, settings, map[string]string{
+			"/provider/resource_widget.go":      unannotated,
+			"/provider/resource_widget_test.go": directiveTestSrc,
+		})
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "none include CheckDestroy")
+	})
+
+	t.Run("expect requires import tests without ImportState", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunImportTestAnalyzer, settings, sources)
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "resource 'widget' expects import test coverage (//tftest:expect)")
+	})
+
+	t.Run("valid directives produce no errors", func(t *testing.T) {
+		for _, msg := range runBasicAnalyzerOnSources(t, settings, sources) {
+			assert.NotContains(t, msg, "//tftest:")
+		}
+	})
+}
+
+func TestDirectiveErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		directive string
+		want      string
+	}{
+		{"unknown directive", "//tftest:exemt update", "unknown directive //tftest:exemt (did you mean 'exempt'?)"},
+		{"unknown check", "//tftest:expect imprt", "unknown check 'imprt' in //tftest:expect (did you mean 'import'?)"},
+		{"unknown option", `//tftest:exempt drift reson="x"`, "unknown option 'reson' in //tftest:exempt (did you mean 'reason'?)"},
+		{"option on expect", `//tftest:expect error reason="x"`, "unknown option 'reason' in //tftest:expect"},
+		{"missing checks", "//tftest:expect", "//tftest:expect needs a comma-separated list of checks"},
+		{"unterminated quote", `//tftest:exempt drift reason="x`, "malformed //tftest:exempt directive: unterminated quote"},
+		{"unknown maturity level", "//tftest:maturity experimntal", "unknown maturity level 'experimntal' in //tftest:maturity (did you mean 'experimental'?)"},
+		{"missing maturity level", "//tftest:maturity", "malformed //tftest:maturity directive: expected a single maturity level"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := strings.Replace(directiveResourceSrc, "//tftest:expect basic,import", tt.directive, 1)
+			messages := runBasicAnalyzerOnSources(t, config.DefaultSettings(), map[string]string{
+				"/provider/resource_widget.go":      src,
+				"/provider/resource_widget_test.go": directiveTestSrc,
+			})
+
+			var found []string
+			for _, msg := range messages {
+				if strings.Contains(msg, tt.want) {
+					found = append(found, msg)
+				}
+			}
+			assert.Len(t, found, 1, "messages: %v", messages)
+		})
+	}
+}
+
+func TestApplyDirectives(t *testing.T) {
+	parse := func(t *testing.T, src string) ([]*registry.ResourceInfo, []registry.DirectiveError) {
+		t.Helper()
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "/provider/resource_widget.go", src, parser.ParseComments)
+		require.NoError(t, err)
+		resources := discovery.ParseResources(file, fset, "/provider/resource_widget.go")
+		return resources, discovery.ApplyDirectives(file, resources)
+	}
+
+	t.Run("records expectations and reasons", func(t *testing.T) {
+		resources, errs := parse(t, directiveResourceSrc)
+		require.Empty(t, errs)
+		require.Len(t, resources, 1)
+
+		directives := resources[0].Directives
+		assert.True(t, directives.Expects(registry.CheckImport))
+		assert.True(t, directives.Exempts(registry.CheckDrift))
+		assert.Equal(t, "destroy is a no-op", directives.Exempt[registry.CheckDrift])
+		assert.False(t, directives.Exempts(registry.CheckImport))
+	})
+
+	t.Run("directive on a method applies to the receiver", func(t *testing.T) {
+		src := strings.Replace(directiveResourceSrc, "//tftest:expect basic,import\n", "", 1)
+		src = strings.Replace(src, "func (r *WidgetResource) Schema", "//tftest:expect error\nfunc (r *WidgetResource) Schema", 1)
+		resources, errs := parse(t, src)
+		require.Empty(t, errs)
+		assert.True(t, resources[0].Directives.Expects(registry.CheckError))
+	})
+
+	t.Run("conflicting and unattached directives", func(t *testing.T) {
+		src := strings.Replace(directiveResourceSrc, "//tftest:expect basic,import", "//tftest:expect drift", 1)
+		src += "\n//tftest:exempt drift\ntype helper struct{}\n"
+		_, errs := parse(t, src)
+
+		var problems []registry.DirectiveProblem
+		for _, err := range errs {
+			problems = append(problems, err.Problem)
+		}
+		assert.ElementsMatch(t, []registry.DirectiveProblem{registry.DirectiveConflict, registry.DirectiveUnattached}, problems)
+	})
+
+	t.Run("checks that do not apply to data sources", func(t *testing.T) {
+		src := strings.ReplaceAll(directiveResourceSrc, "WidgetResource", "WidgetDataSource")
+		src = strings.Replace(src, "//tftest:exempt drift", "//tftest:exempt state-check,drift", 1)
+		resources, errs := parse(t, src)
+		require.Len(t, errs, 2)
+		for _, err := range errs {
+			assert.Equal(t, registry.DirectiveInapplicable, err.Problem)
+			assert.Equal(t, registry.KindDataSource, err.TargetKind)
+		}
+		assert.ElementsMatch(t, []string{"import", "drift"}, []string{errs[0].Value, errs[1].Value})
+		assert.True(t, resources[0].Directives.Exempts(registry.CheckStateCheck))
+	})
+}
+
+func TestOpaqueStepsLeaveStepCoverageUnknown(t *testing.T) {
+	opaqueTestSrc := `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccWidget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{Steps: acctest.WidgetSteps(t)})
+}
+`
+	messages := runAnalyzerOnSources(t, analysis.RunImportTestAnalyzer, config.DefaultSettings(), map[string]string{
+		"/provider/resource_widget.go":      directiveResourceSrc,
+		"/provider/resource_widget_test.go": opaqueTestSrc,
+	})
+	assert.Empty(t, messages, "steps built outside the test may include the import step")
+}
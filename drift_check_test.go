@@ -0,0 +1,136 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const sdkv2NoopDeleteSrc = `package provider
+
+import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+func resourceToken() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceTokenCreate,
+		ReadContext:   resourceTokenRead,
+		DeleteContext: schema.NoopContext,
+		Schema: map[string]*schema.Schema{
+			"name": {Type: schema.TypeString, Required: true, ForceNew: true},
+		},
+	}
+}
+`
+
+const tokenCheckDestroyTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccToken_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		CheckDestroy: testAccCheckTokenDestroy,
+		Steps:        []resource.TestStep{{Config: ` + "`" + `resource "example_token" "t" { name = "a" }` + "`" + `}},
+	})
+}
+`
+
+const tokenTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccToken_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: ` + "`" + `resource "example_token" "t" { name = "a" }` + "`" + `}},
+	})
+}
+`
+
+const frameworkEmptyDeleteSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+type TokenResource struct{}
+
+func (r *TokenResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{Required: true},
+		},
+	}
+}
+
+func (r *TokenResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Tokens expire on their own.
+	resp.State.RemoveResource(ctx)
+}
+`
+
+func TestDriftCheckRequiresDelete(t *testing.T) {
+	settings := config.DefaultSettings()
+	settings.EnableBasicTest = true
+
+	t.Run("without the setting every tested resource needs CheckDestroy", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunDriftCheckAnalyzer, settings, map[string]string{
+			"/provider/resource_token.go":      sdkv2NoopDeleteSrc,
+			"/provider/resource_token_test.go": tokenTestSrc,
+		})
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "resource 'token' has 1 test(s) but none include CheckDestroy")
+	})
+
+	required := settings
+	required.CheckDestroyRequiresDelete = true
+
+	t.Run("no-op SDK v2 Delete is not required to have CheckDestroy", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunDriftCheckAnalyzer, required, map[string]string{
+			"/provider/resource_token.go":      sdkv2NoopDeleteSrc,
+			"/provider/resource_token_test.go": tokenTestSrc,
+		})
+		assert.Empty(t, messages)
+	})
+
+	t.Run("CheckDestroy on a no-op SDK v2 Delete", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunDriftCheckAnalyzer, required, map[string]string{
+			"/provider/resource_token.go":      sdkv2NoopDeleteSrc,
+			"/provider/resource_token_test.go": tokenCheckDestroyTestSrc,
+		})
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "test 'TestAccToken_basic' sets CheckDestroy, but resource 'token' has a Delete that only removes it from state")
+		assert.Contains(t, messages[0], "Test: /provider/resource_token_test.go:9")
+	})
+
+	t.Run("CheckDestroy on an empty framework Delete", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunDriftCheckAnalyzer, required, map[string]string{
+			"/provider/resource_token.go":      frameworkEmptyDeleteSrc,
+			"/provider/resource_token_test.go": tokenCheckDestroyTestSrc,
+		})
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "has a Delete that only removes it from state")
+	})
+
+	t.Run("destroyable resources are unaffected", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunDriftCheckAnalyzer, required, map[string]string{
+			"/provider/resource_gadget.go":      untestedGadgetResourceSrc,
+			"/provider/resource_gadget_test.go": gadgetBasicTestSrc,
+		})
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "resource 'gadget' has 1 test(s) but none include CheckDestroy")
+	})
+}
@@ -0,0 +1,109 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+const embeddedBaseSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+type baseResource struct {
+	typeName string
+}
+
+func newBaseResource(typeName string) baseResource {
+	return baseResource{typeName: typeName}
+}
+
+func (b *baseResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + b.typeName
+}
+
+type namer interface{ TypeSuffix() string }
+
+type Base[M namer] struct{}
+
+func (b *Base[M]) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	var zero M
+	resp.TypeName = req.ProviderTypeName + zero.TypeSuffix()
+}
+`
+
+const embeddedResourcesSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+type WidgetResource struct {
+	baseResource
+}
+
+func NewWidgetResource() resource.Resource {
+	return &WidgetResource{baseResource: baseResource{typeName: "widget_item"}}
+}
+
+func (r *WidgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{Required: true},
+		},
+	}
+}
+
+type SprocketResource struct {
+	baseResource
+}
+
+func NewSprocketResource() resource.Resource {
+	return &SprocketResource{baseResource: newBaseResource("sprocket_v2")}
+}
+
+type gizmoModel struct{}
+
+func (gizmoModel) TypeSuffix() string { return "_gizmo" }
+
+type GizmoResource struct {
+	*Base[gizmoModel]
+}
+`
+
+func TestEmbeddedMetadata(t *testing.T) {
+	reg := buildRegistryFromSources(t, map[string]string{
+		"/provider/base.go":      embeddedBaseSrc,
+		"/provider/resources.go": embeddedResourcesSrc,
+	})
+
+	widget := reg.GetDefinition(registry.KindResource, "widget_item")
+	require.NotNil(t, widget, "field set in a composite literal")
+	assert.Equal(t, "EmbeddedMetadata", widget.DiscoveredBy)
+	require.Len(t, widget.Attributes, 1)
+	assert.Equal(t, "name", widget.Attributes[0].Name)
+	assert.Nil(t, reg.GetDefinition(registry.KindResource, "widget"), "renamed from the Go type")
+
+	sprocket := reg.GetDefinition(registry.KindResource, "sprocket_v2")
+	require.NotNil(t, sprocket, "field set by a constructor call")
+	assert.Equal(t, "/provider/resources.go", sprocket.FilePath)
+	assert.Nil(t, reg.GetDefinition(registry.KindResource, "sprocket"), "not named after the Go type")
+
+	gizmo := reg.GetDefinition(registry.KindResource, "gizmo")
+	require.NotNil(t, gizmo, "name from the type argument")
+	assert.Equal(t, "EmbeddedMetadata", gizmo.DiscoveredBy)
+
+	for _, def := range reg.GetAllDefinitions() {
+		assert.NotEqual(t, "base", def.Name, "bases are not definitions")
+	}
+}
@@ -0,0 +1,107 @@
+package tfprovidertest
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	goanalysis "golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/enforcement"
+	"github.com/example/tfprovidertest/internal/rules"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+func TestEnforcementPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		enforce  []string
+		warnOnly []string
+		path     string
+		want     enforcement.Level
+	}{
+		{"no patterns", nil, nil, "/src/internal/service/ec2/instance.go", enforcement.LevelError},
+		{"enforced service", []string{"internal/service/s3/**"}, nil, "/src/internal/service/s3/bucket.go", enforcement.LevelError},
+		{"enforced nested directory", []string{"internal/service/s3/**"}, nil, "/src/internal/service/s3/sub/object.go", enforcement.LevelError},
+		{"other service", []string{"internal/service/s3/**"}, nil, "/src/internal/service/ec2/instance.go", enforcement.LevelWarning},
+		{"prefix is not a match", []string{"internal/service/s3/**"}, nil, "/src/internal/service/s3control/bucket.go", enforcement.LevelWarning},
+		{"base name pattern", []string{"resource_*.go"}, nil, "/src/internal/widget/resource_widget.go", enforcement.LevelError},
+		{"warn-only", nil, []string{"internal/service/ec2/**"}, "/src/internal/service/ec2/instance.go", enforcement.LevelWarning},
+		{"warn-only elsewhere", nil, []string{"internal/service/ec2/**"}, "/src/internal/service/s3/bucket.go", enforcement.LevelError},
+		{"warn-only wins over enforce", []string{"internal/service/**"}, []string{"**/legacy/*.go"}, "/src/internal/service/legacy/old.go", enforcement.LevelWarning},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := enforcement.New(tt.enforce, tt.warnOnly)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, policy.Level(tt.path))
+		})
+	}
+
+	_, err := enforcement.New(nil, []string{""})
+	assert.ErrorContains(t, err, "warn-only-paths: empty pattern")
+}
+
+func TestEnforcePathsMarkWarnings(t *testing.T) {
+	analysis.ClearAllRegistryCaches()
+	t.Cleanup(analysis.ClearAllRegistryCaches)
+
+	var events []config.DiagnosticEvent
+	settings := config.DefaultSettings()
+	settings.EnforcePaths = []string{"service/gadget/**"}
+	settings.Hooks = &config.Hooks{OnDiagnostic: func(e config.DiagnosticEvent) { events = append(events, e) }}
+	plugin := NewWithSettings(settings)
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for name, src := range map[string]string{
+		"/provider/service/gadget/resource_gadget.go":     untestedGadgetResourceSrc,
+		"/provider/service/sprocket/resource_sprocket.go": untestedSprocketResourceSrc,
+	} {
+		f, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+		require.NoError(t, err)
+		files = append(files, f)
+	}
+
+	analyzers, err := plugin.BuildAnalyzers()
+	require.NoError(t, err)
+	byFile := make(map[string]goanalysis.Diagnostic)
+	for _, a := range analyzers {
+		if a.Name != rules.BasicTest {
+			continue
+		}
+		_, err := a.Run(&goanalysis.Pass{
+			Analyzer: a,
+			Fset:     fset,
+			Files:    files,
+			Report:   func(d goanalysis.Diagnostic) { byFile[fset.Position(d.Pos).Filename] = d },
+		})
+		require.NoError(t, err)
+	}
+
+	require.Len(t, byFile, 2)
+	enforced := byFile["/provider/service/gadget/resource_gadget.go"]
+	assert.Empty(t, enforced.Category)
+	assert.False(t, strings.HasPrefix(enforced.Message, enforcement.WarningPrefix))
+
+	warning := byFile["/provider/service/sprocket/resource_sprocket.go"]
+	assert.Equal(t, "warning", warning.Category)
+	assert.True(t, strings.HasPrefix(warning.Message, enforcement.WarningPrefix), warning.Message)
+
+	require.Len(t, events, 2)
+	for _, e := range events {
+		want := "error"
+		if strings.Contains(e.File, "sprocket") {
+			want = "warning"
+		}
+		assert.Equal(t, want, e.Level, e.File)
+	}
+
+	settings.EnforcePaths = []string{"service/[z-a]/**"}
+	assert.ErrorContains(t, settings.Validate(), "enforce-paths: invalid pattern")
+}
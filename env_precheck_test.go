@@ -0,0 +1,130 @@
+package tfprovidertest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const envPreCheckTestSrc = `package provider
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/example/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccPreCheck(t *testing.T) {
+	if os.Getenv("EXAMPLE_TOKEN") == "" {
+		t.Fatal("EXAMPLE_TOKEN must be set")
+	}
+	testAccPreCheckEnv(t, "EXAMPLE_ORG_ID")
+}
+
+func testAccPreCheckEnv(t *testing.T, name string) {
+	if os.Getenv(name) == "" {
+		t.Skip("set " + name)
+	}
+}
+
+func testAccGadgetConfig() string {
+	return fmt.Sprintf(` + "`" + `resource "example_gadget" "test" { region = %q }` + "`" + `, os.Getenv("EXAMPLE_REGION"))
+}
+
+func TestAccGadget_basic(t *testing.T) {
+	org := os.Getenv("EXAMPLE_ORG_ID")
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(` + "`" + `resource "example_gadget" "test" { org = %q }` + "`" + `, org),
+			},
+			{
+				Config: testAccGadgetConfig(),
+			},
+		},
+	})
+}
+
+func TestAccGadget_token(t *testing.T) {
+	t.Setenv("EXAMPLE_REGION", "eu-west-1")
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(` + "`" + `resource "example_gadget" "test" { token = %q }` + "`" + `, os.Getenv("EXAMPLE_TOKEN")),
+			},
+			{
+				Config: testAccGadgetConfig(),
+			},
+		},
+	})
+}
+
+func TestAccGadget_shared(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() { acctest.PreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGadgetConfig(),
+			},
+		},
+	})
+}
+`
+
+func TestEnvPreCheck(t *testing.T) {
+	sources := map[string]string{
+		"/provider/resource_gadget.go":      untestedGadgetResourceSrc,
+		"/provider/resource_gadget_test.go": envPreCheckTestSrc,
+	}
+
+	t.Run("inventory", func(t *testing.T) {
+		reg := buildRegistryFromSources(t, sources)
+		tests := make(map[string]*registry.TestFunctionInfo)
+		for _, fn := range reg.GetAllTestFunctions() {
+			tests[fn.Name] = fn
+		}
+		require.Contains(t, tests, "TestAccGadget_basic")
+		basic := tests["TestAccGadget_basic"]
+		require.Len(t, basic.ConfigEnvVars, 2)
+		assert.Equal(t, "EXAMPLE_ORG_ID", basic.ConfigEnvVars[0].Name, "read through a local variable")
+		assert.Equal(t, "EXAMPLE_REGION", basic.ConfigEnvVars[1].Name, "read in a config helper")
+		assert.Equal(t, []string{"EXAMPLE_ORG_ID", "EXAMPLE_TOKEN"}, basic.PreCheckEnvVars, "checked directly and by name through a helper")
+		assert.True(t, tests["TestAccGadget_shared"].PreCheckUnresolved)
+
+		inventory := analysis.EnvVarInventory(reg)
+		require.Len(t, inventory, 3)
+		assert.Equal(t, analysis.EnvVarEntry{
+			Name:          "EXAMPLE_REGION",
+			ConfigTests:   []string{"TestAccGadget_basic", "TestAccGadget_shared", "TestAccGadget_token"},
+			PreCheckTests: []string{},
+			SetByTests:    []string{"TestAccGadget_token"},
+			Unchecked:     []string{"TestAccGadget_basic"},
+		}, inventory[1])
+		assert.Equal(t, []string{"TestAccGadget_token"}, inventory[2].Unchecked)
+	})
+
+	t.Run("analyzer", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		settings.EnableEnvPreCheckCheck = true
+		messages := runAnalyzerOnSources(t, analysis.RunEnvPreCheckAnalyzer, settings, sources)
+
+		require.Len(t, messages, 2, strings.Join(messages, "\n\n"))
+		assert.Contains(t, messages[0], "test 'TestAccGadget_basic' interpolates environment variable 'EXAMPLE_REGION' into its config")
+		assert.Contains(t, messages[1], "test 'TestAccGadget_token' interpolates environment variable 'EXAMPLE_TOKEN' into its config")
+	})
+
+	t.Run("settings", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		assert.False(t, settings.EnvPreCheckCheckEnabled(), "the rule is opt-in")
+	})
+}
@@ -0,0 +1,95 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+const errorPathsWidgetTestSrc = `package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+var errUnexpected = regexp.MustCompile("boom")
+
+func TestAccWidget_errors(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config:      ` + "`" + `resource "example_widget" "test" { name = "" }` + "`" + `,
+				ExpectError: regexp.MustCompile("Invalid Attribute Value"),
+			},
+			{
+				Config:      ` + "`" + `resource "example_widget" "test" { name = "a" }` + "`" + `,
+				ExpectError: regexp.MustCompile(` + "`" + `(?i)already\s+exists` + "`" + `),
+			},
+			{
+				Config: ` + "`" + `
+resource "example_widget" "one" {
+  name = "taken"
+}
+
+resource "example_widget" "two" {
+  name = "taken"
+}
+` + "`" + `,
+				ExpectError: regexp.MustCompile("Error creating widget"),
+			},
+			{
+				Config: ` + "`" + `
+provider "example" {
+  token = "not-a-token"
+}
+
+resource "example_widget" "test" {
+  name = "a"
+}
+` + "`" + `,
+				ExpectError: regexp.MustCompile("Error creating widget"),
+			},
+			{
+				Config:      ` + "`" + `resource "example_widget" "test" { name = "a" }` + "`" + `,
+				ExpectError: regexp.MustCompile("Widget Not Found"),
+			},
+			{
+				Config:      ` + "`" + `resource "example_widget" "test" { name = "a" }` + "`" + `,
+				ExpectError: errUnexpected,
+			},
+			{
+				Config: ` + "`" + `resource "example_widget" "test" { name = "a" }` + "`" + `,
+			},
+		},
+	})
+}
+`
+
+func TestExpectErrorCategories(t *testing.T) {
+	reg := buildRegistryFromSources(t, map[string]string{
+		"/provider/resource_widget.go":      upgradeWidgetResourceSrc,
+		"/provider/resource_widget_test.go": errorPathsWidgetTestSrc,
+	})
+	steps := findTest(t, reg, "TestAccWidget_errors").TestSteps
+	require.Len(t, steps, 7)
+
+	var categories []registry.ErrorCategory
+	for _, step := range steps {
+		categories = append(categories, step.ErrorCategory)
+	}
+	assert.Equal(t, []registry.ErrorCategory{
+		registry.ErrorCategoryValidation,
+		registry.ErrorCategoryConflict,
+		registry.ErrorCategoryConflict, // Two blocks with the same name
+		registry.ErrorCategoryAuth,     // Credentials set in the provider block
+		registry.ErrorCategoryNotFound,
+		registry.ErrorCategoryOther, // Pattern not known statically
+		"",
+	}, categories)
+}
@@ -0,0 +1,61 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+func TestErrorCheckPresenceAnalyzer(t *testing.T) {
+	settings := config.DefaultSettings()
+	settings.EnableErrorCheckPresence = true
+	errorCheck := "ErrorCheck: acctest.ErrorCheck(t, names.EC2ServiceID),"
+
+	t.Run("TestCase without the package's ErrorCheck", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunErrorCheckPresenceAnalyzer, settings, map[string]string{
+			"/provider/widget_test.go":   factoryTestSrc("Widget", errorCheck, ""),
+			"/provider/gadget_test.go":   factoryTestSrc("Gadget", errorCheck, ""),
+			"/provider/cog_test.go":      factoryTestSrc("Cog", "ErrorCheck: testAccErrorCheckSkip,", ""),
+			"/provider/sprocket_test.go": factoryTestSrc("Sprocket", "", ""),
+			// Another package does not use ErrorCheck
+			"/other/gizmo_test.go": factoryTestSrc("Gizmo", "", ""),
+		})
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "a TestCase of test 'TestAccSprocket_basic' sets no ErrorCheck, but 3 of the 4 TestCases of package provider do")
+		assert.Contains(t, messages[0], "Suggestion: Set ErrorCheck with the package's helper, acctest.ErrorCheck,")
+	})
+
+	t.Run("not established", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunErrorCheckPresenceAnalyzer, settings, map[string]string{
+			"/provider/widget_test.go":   factoryTestSrc("Widget", errorCheck, ""),
+			"/provider/gadget_test.go":   factoryTestSrc("Gadget", "", ""),
+			"/provider/sprocket_test.go": factoryTestSrc("Sprocket", "", ""),
+		})
+		assert.Empty(t, messages, "one TestCase setting ErrorCheck is not a pattern")
+	})
+
+	t.Run("function literals", func(t *testing.T) {
+		literal := "ErrorCheck: func(err error) error { return err },"
+		messages := runAnalyzerOnSources(t, analysis.RunErrorCheckPresenceAnalyzer, settings, map[string]string{
+			"/provider/widget_test.go":   factoryTestSrc("Widget", literal, ""),
+			"/provider/gadget_test.go":   factoryTestSrc("Gadget", literal, ""),
+			"/provider/sprocket_test.go": factoryTestSrc("Sprocket", "", ""),
+		})
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "with the package's helper, a function literal like its other TestCases,")
+
+		ja := settings
+		ja.Language = "ja"
+		messages = runAnalyzerOnSources(t, analysis.RunErrorCheckPresenceAnalyzer, ja, map[string]string{
+			"/provider/widget_test.go":   factoryTestSrc("Widget", literal, ""),
+			"/provider/gadget_test.go":   factoryTestSrc("Gadget", literal, ""),
+			"/provider/sprocket_test.go": factoryTestSrc("Sprocket", "", ""),
+		})
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "パッケージのヘルパー 他の TestCase と同様の関数リテラル で ErrorCheck を設定してください")
+	})
+}
@@ -0,0 +1,80 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const expectErrorTestSrc = `package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+var errInvalidName = regexp.MustCompile("invalid name")
+
+func TestAccWidget_invalidRegex(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccWidgetConfig, ExpectError: regexp.MustCompile("(")},
+		},
+	})
+}
+
+func TestAccWidget_matchesAll(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccWidgetConfig, ExpectError: regexp.MustCompile(".*")},
+		},
+	})
+}
+
+func TestAccWidget_generic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccWidgetConfig, ExpectError: regexp.MustCompile("(?i)error")},
+		},
+	})
+}
+
+func TestAccWidget_specific(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccWidgetConfig, ExpectError: regexp.MustCompile("Attribute name " + "must not be empty")},
+		},
+	})
+}
+
+func TestAccWidget_variable(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccWidgetConfig, ExpectError: errInvalidName},
+		},
+	})
+}
+`
+
+func TestExpectErrorAnalyzer(t *testing.T) {
+	settings := config.DefaultSettings()
+	settings.EnableExpectErrorCheck = true
+
+	diags := runAnalyzerOnSources(t, analysis.RunExpectErrorAnalyzer, settings, map[string]string{
+		"/provider/resource_widget.go":      importIdResourceSrc,
+		"/provider/resource_widget_test.go": expectErrorTestSrc,
+	})
+	require.Len(t, diags, 3)
+	joined := diags[0] + "\n" + diags[1] + "\n" + diags[2]
+	assert.Contains(t, joined, "test 'TestAccWidget_invalidRegex' does not compile")
+	assert.Contains(t, joined, "test 'TestAccWidget_matchesAll' uses the pattern `.*`, which matches any error")
+	assert.Contains(t, joined, "test 'TestAccWidget_generic' uses the pattern `(?i)error`, which only matches the generic word 'error'")
+	assert.NotContains(t, joined, "TestAccWidget_specific")
+	assert.NotContains(t, joined, "TestAccWidget_variable")
+}
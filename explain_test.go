@@ -0,0 +1,133 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	goanalysis "golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const explainWidgetResourceSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+type WidgetResource struct{}
+
+func (r *WidgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{}
+}
+`
+
+const explainTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccWidgit_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: ` + "`" + `resource "example_thing" "x" {}` + "`" + `}},
+	})
+}
+
+func TestAccGadget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: ` + "`" + `
+resource "example_widget" "w" {}
+resource "example_gadget" "g" {}
+` + "`" + `}},
+	})
+}
+
+func TestAccUnrelated_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{})
+}
+`
+
+func TestExplainUntestedResource(t *testing.T) {
+	names := []string{
+		"/provider/resource_widget.go",
+		"/provider/resource_gadget.go",
+		"/provider/base_widget.go",
+		"/provider/resource_gadget_test.go",
+	}
+	sources := map[string]string{
+		"/provider/resource_widget.go":      explainWidgetResourceSrc,
+		"/provider/resource_gadget.go":      untestedGadgetResourceSrc,
+		"/provider/base_widget.go":          explainWidgetResourceSrc,
+		"/provider/resource_gadget_test.go": explainTestSrc,
+	}
+	fset, files := parseSources(t, names, sources)
+	settings := config.DefaultSettings()
+	reg := discovery.BuildRegistry(&goanalysis.Pass{Fset: fset, Files: files}, settings)
+
+	exp := analysis.Explain("example_widget", reg, fset, files, &settings)
+	require.Len(t, exp.Definitions, 1)
+	def := exp.Definitions[0]
+	assert.Equal(t, "widget", def.Name)
+	assert.Equal(t, "/provider/resource_widget.go", def.File)
+	assert.Equal(t, 12, def.Line)
+	assert.Equal(t, "SchemaMethod", def.DiscoveredBy)
+	assert.Empty(t, def.Linked)
+
+	require.Len(t, def.Rejected, 2, "unrelated tests are not candidates")
+	byName := make(map[string]analysis.CandidateTest)
+	for _, c := range def.Rejected {
+		byName[c.Test] = c
+	}
+
+	gadget := byName["TestAccGadget_basic"]
+	assert.Contains(t, gadget.Reason, `linked to "gadget"`)
+	assert.True(t, matcher(t, gadget, analysis.MatcherHCLBlock).Matched)
+
+	typo := byName["TestAccWidgit_basic"]
+	fuzzy := matcher(t, typo, analysis.MatcherFuzzy)
+	assert.False(t, fuzzy.Matched)
+	assert.InDelta(t, 0.83, fuzzy.Score, 0.01)
+	assert.Contains(t, fuzzy.Detail, "fuzzy matching is disabled")
+
+	assert.Contains(t, def.Suggestions, "Rename TestAccWidgit_basic to TestAccWidget_basic, or run with -match-strategy fuzzy")
+
+	require.Len(t, exp.Excluded, 1)
+	assert.Equal(t, "/provider/base_widget.go", exp.Excluded[0].File)
+	assert.Equal(t, "file role base", exp.Excluded[0].Reason)
+}
+
+func TestExplainUnknownAndQualifiedQueries(t *testing.T) {
+	names := []string{"/provider/resource_widget.go"}
+	fset, files := parseSources(t, names, map[string]string{names[0]: explainWidgetResourceSrc})
+	settings := config.DefaultSettings()
+	reg := discovery.BuildRegistry(&goanalysis.Pass{Fset: fset, Files: files}, settings)
+
+	exp := analysis.Explain("sprocket", reg, fset, files, &settings)
+	assert.Empty(t, exp.Definitions)
+	require.Len(t, exp.Suggestions, 1)
+	assert.Contains(t, exp.Suggestions[0], `No definition named "sprocket"`)
+
+	assert.Len(t, analysis.Explain("resource:widget", reg, fset, files, &settings).Definitions, 1)
+	assert.Empty(t, analysis.Explain("data source:widget", reg, fset, files, &settings).Definitions)
+}
+
+// matcher returns the named matcher result of a candidate.
+func matcher(t *testing.T, c analysis.CandidateTest, name string) analysis.MatcherResult {
+	t.Helper()
+	for _, r := range c.Matchers {
+		if r.Matcher == name {
+			return r
+		}
+	}
+	t.Fatalf("candidate %s has no %s matcher", c.Test, name)
+	return analysis.MatcherResult{}
+}
@@ -0,0 +1,185 @@
+package tfprovidertest
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+const aliasProviderSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+type ExampleProvider struct{}
+
+func (p *ExampleProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
+	resp.Schema = schema.Schema{}
+}
+
+func (p *ExampleProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "example"
+}
+
+func (p *ExampleProvider) Resources(ctx context.Context) []func() resource.Resource {
+	return []func() resource.Resource{
+		NewWidgetResource("_widget"),
+		NewWidgetResource("_widget_v2"),
+		NewGadgetResource("example_gadget_a"),
+		NewGadgetResource("example_gadget_b"),
+	}
+}
+
+func (p *ExampleProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
+	return nil
+}
+`
+
+const aliasWidgetSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+type widgetResource struct {
+	typeName string
+}
+
+func NewWidgetResource(typeName string) func() resource.Resource {
+	return func() resource.Resource {
+		return &widgetResource{typeName: typeName}
+	}
+}
+
+func (r *widgetResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + r.typeName
+}
+
+func (r *widgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{Required: true},
+		},
+	}
+}
+`
+
+const aliasGadgetSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+type GadgetResource struct {
+	typeName string
+}
+
+func NewGadgetResource(typeName string) func() resource.Resource {
+	return func() resource.Resource {
+		r := new(GadgetResource)
+		r.typeName = typeName
+		return r
+	}
+}
+
+func (r *GadgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"size": schema.Int64Attribute{Optional: true},
+		},
+	}
+}
+`
+
+func TestFactoryAliasesRegisterEachName(t *testing.T) {
+	reg := buildRegistryFromSources(t, map[string]string{
+		"/provider/provider.go":        aliasProviderSrc,
+		"/provider/resource_widget.go": aliasWidgetSrc,
+		"/provider/resource_gadget.go": aliasGadgetSrc,
+	})
+
+	var names []string
+	for _, def := range reg.GetSortedDefinitions() {
+		names = append(names, def.Name)
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"gadget_a", "gadget_b", "widget", "widget_v2"}, names,
+		"each registered name is a definition; the gadget implementation itself is not")
+
+	v2 := reg.GetDefinition(registry.KindResource, "widget_v2")
+	require.NotNil(t, v2)
+	assert.Equal(t, "widget", v2.AliasOf)
+	assert.Equal(t, "FactoryAlias", v2.DiscoveredBy)
+	require.Len(t, v2.Attributes, 1)
+	assert.Equal(t, "name", v2.Attributes[0].Name, "aliases share the schema attributes")
+
+	gadget := reg.GetDefinition(registry.KindResource, "gadget_b")
+	require.NotNil(t, gadget)
+	assert.Equal(t, "gadget", gadget.AliasOf)
+	assert.Equal(t, "NewGadgetResource", gadget.Factory)
+	require.Len(t, gadget.Attributes, 1)
+	assert.Equal(t, "size", gadget.Attributes[0].Name)
+}
+
+func TestFactoryAliasesIgnoreFactoriesWithoutNames(t *testing.T) {
+	provider := `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+type ExampleProvider struct{}
+
+func (p *ExampleProvider) Resources(ctx context.Context) []func() resource.Resource {
+	return []func() resource.Resource{
+		NewWidgetResource,
+	}
+}
+`
+	widget := `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+type WidgetResource struct{}
+
+func NewWidgetResource() resource.Resource {
+	return &WidgetResource{}
+}
+
+func (r *WidgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{}
+}
+`
+	reg := buildRegistryFromSources(t, map[string]string{
+		"/provider/provider.go":        provider,
+		"/provider/resource_widget.go": widget,
+	})
+
+	defs := reg.GetSortedDefinitions()
+	require.Len(t, defs, 1)
+	assert.Equal(t, "widget", defs[0].Name)
+	assert.Empty(t, defs[0].AliasOf)
+}
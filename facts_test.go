@@ -0,0 +1,167 @@
+package tfprovidertest
+
+import (
+	"bytes"
+	"encoding/gob"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	goanalysis "golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/rules"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const untestedGadgetResourceSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+type GadgetResource struct{}
+
+func (r *GadgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{}
+}
+`
+
+const untestedSprocketResourceSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+type SprocketResource struct{}
+
+func (r *SprocketResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{}
+}
+`
+
+func TestCoverageFactGobRoundTrip(t *testing.T) {
+	fact := &analysis.CoverageFact{Definitions: []analysis.CoverageFactEntry{
+		{Kind: "resource", Name: "widget", TestCount: 2},
+		{Kind: "data source", Name: "widget"},
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(fact))
+	var decoded analysis.CoverageFact
+	require.NoError(t, gob.NewDecoder(&buf).Decode(&decoded))
+
+	assert.Equal(t, *fact, decoded)
+	assert.Equal(t, "coverage(1/2 tested)", decoded.String())
+}
+
+func TestBasicAnalyzerExportsCoverageFact(t *testing.T) {
+	plugin, err := New(nil)
+	require.NoError(t, err)
+	analyzers, err := plugin.(*Plugin).BuildAnalyzers()
+	require.NoError(t, err)
+
+	var basic *goanalysis.Analyzer
+	for _, a := range analyzers {
+		if a.Name == rules.BasicTest {
+			basic = a
+		}
+	}
+	require.NotNil(t, basic)
+	require.Len(t, basic.FactTypes, 1)
+	assert.IsType(t, &analysis.CoverageFact{}, basic.FactTypes[0])
+
+	analysis.ClearAllRegistryCaches()
+	t.Cleanup(analysis.ClearAllRegistryCaches)
+
+	sources := map[string]string{"/provider/resource_gadget.go": untestedGadgetResourceSrc}
+	fset, files := parseSources(t, []string{"/provider/resource_gadget.go"}, sources)
+
+	var exported []goanalysis.Fact
+	pass := &goanalysis.Pass{
+		Analyzer:          basic,
+		Fset:              fset,
+		Files:             files,
+		Pkg:               types.NewPackage("example.com/provider", "provider"),
+		Report:            func(goanalysis.Diagnostic) {},
+		ExportPackageFact: func(fact goanalysis.Fact) { exported = append(exported, fact) },
+	}
+	_, err = basic.Run(pass)
+	require.NoError(t, err)
+
+	require.Len(t, exported, 1)
+	fact, ok := exported[0].(*analysis.CoverageFact)
+	require.True(t, ok)
+	assert.Equal(t, []analysis.CoverageFactEntry{{Kind: "resource", Name: "gadget"}}, fact.Definitions)
+}
+
+func TestAnalyzerOutputIsDeterministic(t *testing.T) {
+	sources := map[string]string{
+		"/provider/resource_gadget.go":   untestedGadgetResourceSrc,
+		"/provider/resource_sprocket.go": untestedSprocketResourceSrc,
+	}
+
+	// File order changes between runs; diagnostics must not.
+	var runs [][]string
+	for _, order := range [][]string{
+		{"/provider/resource_gadget.go", "/provider/resource_sprocket.go"},
+		{"/provider/resource_sprocket.go", "/provider/resource_gadget.go"},
+	} {
+		analysis.ClearAllRegistryCaches()
+		fset, files := parseSources(t, order, sources)
+		var messages []string
+		settings := config.DefaultSettings()
+		pass := &goanalysis.Pass{
+			Fset:   fset,
+			Files:  files,
+			Report: func(d goanalysis.Diagnostic) { messages = append(messages, d.Message) },
+		}
+		_, err := analysis.RunBasicTestAnalyzer(pass, &settings)
+		require.NoError(t, err)
+		runs = append(runs, messages)
+	}
+	analysis.ClearAllRegistryCaches()
+
+	require.Len(t, runs[0], 2)
+	assert.Contains(t, runs[0][0], "gadget")
+	assert.Contains(t, runs[0][1], "sprocket")
+	assert.Equal(t, runs[0], runs[1])
+}
+
+func TestRegistryCacheSharedAcrossAnalyzerPasses(t *testing.T) {
+	analysis.ClearAllRegistryCaches()
+	t.Cleanup(analysis.ClearAllRegistryCaches)
+
+	sources := map[string]string{"/provider/resource_gadget.go": untestedGadgetResourceSrc}
+	fset, files := parseSources(t, []string{"/provider/resource_gadget.go"}, sources)
+	settings := config.DefaultSettings()
+
+	// golangci-lint creates a separate pass per analyzer over the same package.
+	for _, run := range []func(*goanalysis.Pass, *config.Settings) (interface{}, error){
+		analysis.RunBasicTestAnalyzer,
+		analysis.RunUpdateTestAnalyzer,
+		analysis.RunDriftCheckAnalyzer,
+	} {
+		pass := &goanalysis.Pass{Fset: fset, Files: files, Report: func(goanalysis.Diagnostic) {}}
+		_, err := run(pass, &settings)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, analysis.GetCacheSize(), "passes over the same package share one registry")
+
+	// A new parse of the same sources is a different package load.
+	otherFset, otherFiles := parseSources(t, []string{"/provider/resource_gadget.go"}, sources)
+	pass := &goanalysis.Pass{Fset: otherFset, Files: otherFiles, Report: func(goanalysis.Diagnostic) {}}
+	_, err := analysis.RunBasicTestAnalyzer(pass, &settings)
+	require.NoError(t, err)
+	assert.Equal(t, 2, analysis.GetCacheSize())
+
+	analysis.ClearRegistryCache(pass, &settings)
+	assert.Equal(t, 1, analysis.GetCacheSize())
+}
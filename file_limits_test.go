@@ -0,0 +1,104 @@
+package tfprovidertest
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	goanalysis "golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/scan"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+func TestFileLimits(t *testing.T) {
+	// A sprocket resource padded past 2 KB, like a huge generated file
+	hugeSprocketSrc := untestedSprocketResourceSrc + "\n// " + strings.Repeat("generated ", 250) + "\n"
+	sources := map[string]string{
+		"/provider/resource_gadget.go":   untestedGadgetResourceSrc,
+		"/provider/resource_sprocket.go": hugeSprocketSrc,
+	}
+	parse := func(t *testing.T) (*token.FileSet, []*ast.File) {
+		fset := token.NewFileSet()
+		var files []*ast.File
+		for _, name := range []string{"/provider/resource_gadget.go", "/provider/resource_sprocket.go"} {
+			f, err := parser.ParseFile(fset, name, sources[name], parser.ParseComments)
+			require.NoError(t, err)
+			files = append(files, f)
+		}
+		return fset, files
+	}
+
+	t.Run("limiter", func(t *testing.T) {
+		fset, files := parse(t)
+		kept, skipped := scan.LimitFiles(fset, files, 0, 2)
+		assert.Len(t, kept, 1)
+		require.Len(t, skipped, 1)
+		assert.Equal(t, scan.SkippedFile{Path: "/provider/resource_sprocket.go", SizeKB: 3, Reason: scan.ReasonFileTooLarge, Limit: 2}, skipped[0])
+
+		kept, skipped = scan.LimitFiles(fset, files, 1, 0)
+		assert.Equal(t, files[:1], kept, "files past max-files are skipped in order")
+		require.Len(t, skipped, 1)
+		assert.Equal(t, scan.ReasonTooManyFiles, skipped[0].Reason)
+
+		kept, skipped = scan.LimitFiles(fset, files, 0, 0)
+		assert.Equal(t, files, kept)
+		assert.Empty(t, skipped)
+	})
+
+	t.Run("settings", func(t *testing.T) {
+		s := config.Settings{}
+		maxFiles, maxSizeKB := s.FileLimits()
+		assert.Equal(t, 0, maxFiles)
+		assert.Equal(t, config.DefaultMaxFileSizeKB, maxSizeKB, "zero uses the default size limit")
+		s.MaxFileSizeKB = -1
+		_, maxSizeKB = s.FileLimits()
+		assert.Equal(t, 0, maxSizeKB, "a negative size removes the limit")
+
+		s = config.DefaultSettings()
+		s.MaxFiles = -1
+		assert.ErrorContains(t, s.Validate(), "max-files must not be negative")
+	})
+
+	t.Run("plugin skips and reports each file once", func(t *testing.T) {
+		analysis.ClearAllRegistryCaches()
+		t.Cleanup(analysis.ClearAllRegistryCaches)
+
+		settings := config.DefaultSettings()
+		settings.MaxFileSizeKB = 2
+		analyzers, err := NewWithSettings(settings).BuildAnalyzers()
+		require.NoError(t, err)
+
+		fset, files := parse(t)
+		var messages []string
+		for _, a := range analyzers {
+			_, err := a.Run(&goanalysis.Pass{
+				Analyzer: a,
+				Fset:     fset,
+				Files:    files,
+				Report:   func(d goanalysis.Diagnostic) { messages = append(messages, d.Message) },
+			})
+			require.NoError(t, err)
+		}
+
+		var skips, sprockets, gadgets int
+		for _, msg := range messages {
+			switch {
+			case strings.HasPrefix(msg, "file not analyzed: 3 KB exceeds max-file-size-kb (2 KB)"):
+				skips++
+			case strings.Contains(msg, "'sprocket'"):
+				sprockets++
+			case strings.Contains(msg, "'gadget'"):
+				gadgets++
+			}
+		}
+		assert.Equal(t, 1, skips, "reported once, not once per analyzer")
+		assert.Zero(t, sprockets, "the skipped file's resource is not analyzed")
+		assert.NotZero(t, gadgets)
+	})
+}
@@ -0,0 +1,74 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/fileroles"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+func TestFileRolesClassify(t *testing.T) {
+	classifier, err := fileroles.NewClassifier(map[string]string{
+		"internal/models/*.go": "model",
+		"zz_generated_*.go":    "generated",
+		"*_sweeper_test.go":    "sweeper",
+		"base_*.go":            "generated",
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		path string
+		want fileroles.Role
+	}{
+		{"/provider/internal/models/widget.go", fileroles.Model},
+		{"/provider/internal/provider/widget.go", fileroles.None},
+		{"/provider/zz_generated_widget.go", fileroles.Generated},
+		{"/provider/widget_sweeper.go", fileroles.Sweeper},
+		{"/provider/widget_sweeper_test.go", fileroles.Sweeper},
+		{"/provider/widget_state_upgrader.go", fileroles.Migration},
+		{"/provider/base_widget.go", fileroles.Generated}, // Configured pattern replaces the built-in role
+		{"/provider/base.go", fileroles.Base},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, classifier.Classify(tt.path), tt.path)
+	}
+
+	counts := classifier.Counts([]string{"/p/a_sweeper.go", "/p/b_sweeper.go", "/p/widget.go"})
+	assert.Equal(t, map[fileroles.Role]int{fileroles.Sweeper: 2}, counts)
+}
+
+func TestFileRolesValidation(t *testing.T) {
+	settings := config.DefaultSettings()
+	settings.FileRoles = map[string]string{"*_fixture.go": "fixture"}
+	assert.ErrorContains(t, settings.Validate(), `unknown role "fixture"`)
+
+	settings.FileRoles = map[string]string{"[": "docs"}
+	assert.ErrorContains(t, settings.Validate(), "invalid pattern")
+
+	settings.FileRoles = map[string]string{"docs/*.go": "docs"}
+	assert.NoError(t, settings.Validate())
+}
+
+func TestFileRolesExcludeDefinitions(t *testing.T) {
+	sources := map[string]string{
+		"/provider/resource_gadget.go":          untestedGadgetResourceSrc,
+		"/provider/models/resource_sprocket.go": untestedSprocketResourceSrc,
+	}
+
+	settings := config.DefaultSettings()
+	diags := runAnalyzerOnSources(t, analysis.RunBasicTestAnalyzer, settings, sources)
+	assert.Len(t, diags, 2)
+
+	settings.FileRoles = map[string]string{"models/*.go": "model"}
+	diags = runAnalyzerOnSources(t, analysis.RunBasicTestAnalyzer, settings, sources)
+	require.Len(t, diags, 1, "model files declare no definitions")
+	assert.Contains(t, diags[0], "gadget")
+
+	settings.FileRoles = map[string]string{"models/*.go": "generated"}
+	diags = runAnalyzerOnSources(t, analysis.RunBasicTestAnalyzer, settings, sources)
+	assert.Len(t, diags, 2, "generated files are analyzed")
+}
@@ -0,0 +1,106 @@
+package tfprovidertest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/freeze"
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+const freezeBasicTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccWidget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: ` + "`" + `resource "example_widget" "test" {}` + "`" + `}},
+	})
+}
+`
+
+const freezeImportTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccWidget_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: ` + "`" + `resource "example_widget" "test" {}` + "`" + `},
+			{ResourceName: "example_widget.test", ImportState: true, ImportStateVerify: true},
+		},
+	})
+}
+`
+
+func TestCoverageFreeze(t *testing.T) {
+	full := buildRegistryFromSources(t, map[string]string{
+		"/provider/resource_widget.go":             explainWidgetResourceSrc,
+		"/provider/resource_gadget.go":             untestedGadgetResourceSrc,
+		"/provider/resource_widget_test.go":        freezeBasicTestSrc,
+		"/provider/resource_widget_import_test.go": freezeImportTestSrc,
+	})
+	frozen := freeze.Capture(full)
+	assert.Equal(t, map[string][]string{
+		"resource:widget": {registry.CheckBasic, registry.CheckUpdate, registry.CheckImport},
+	}, frozen.Coverage, "untested definitions are left out")
+
+	t.Run("round trip", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), freeze.File)
+		require.NoError(t, frozen.Write(path))
+		loaded, err := freeze.Load(path)
+		require.NoError(t, err)
+		assert.Equal(t, frozen.Coverage, loaded.Coverage)
+
+		data, err := frozen.Marshal()
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "resource:widget: [basic, update, import]\n")
+	})
+
+	t.Run("unchanged coverage passes", func(t *testing.T) {
+		result := frozen.Check(full)
+		assert.Empty(t, result.Regressions)
+		assert.Zero(t, result.Gained)
+	})
+
+	t.Run("lost check fails", func(t *testing.T) {
+		reduced := buildRegistryFromSources(t, map[string]string{
+			"/provider/resource_widget.go":      explainWidgetResourceSrc,
+			"/provider/resource_gadget.go":      untestedGadgetResourceSrc,
+			"/provider/resource_widget_test.go": freezeBasicTestSrc,
+		})
+		result := frozen.Check(reduced)
+		require.Len(t, result.Regressions, 1)
+		assert.Equal(t, "resource:widget", result.Regressions[0].Definition)
+		assert.Equal(t, registry.CheckImport, result.Regressions[0].Check)
+	})
+
+	t.Run("gained coverage and removed definitions pass", func(t *testing.T) {
+		old := &freeze.Freeze{Coverage: map[string][]string{
+			"resource:widget":   {registry.CheckBasic},
+			"resource:sprocket": {registry.CheckBasic},
+		}}
+		result := old.Check(full)
+		assert.Empty(t, result.Regressions)
+		assert.Equal(t, 2, result.Gained)
+		assert.Equal(t, []string{"resource:sprocket"}, result.Removed)
+	})
+
+	t.Run("invalid files", func(t *testing.T) {
+		_, err := freeze.Parse([]byte("coverage:\n  widget: [basic]\n"), "f.yaml")
+		assert.ErrorContains(t, err, `"widget" is not a definition key`)
+		_, err = freeze.Parse([]byte("coverage:\n  resource:widget: [basics]\n"), "f.yaml")
+		assert.ErrorContains(t, err, `unknown check "basics"`)
+	})
+}
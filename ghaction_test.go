@@ -0,0 +1,118 @@
+package tfprovidertest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/ghaction"
+	"github.com/example/tfprovidertest/pkg/metrics"
+)
+
+func TestActionComparison(t *testing.T) {
+	base := []metrics.Definition{
+		{Name: "widget", Kind: "resource", TestCount: 2},
+		{Name: "gadget", Kind: "resource", TestCount: 0},
+		{Name: "info", Kind: "data source", TestCount: 1},
+	}
+	head := []metrics.Definition{
+		{Name: "widget", Kind: "resource", TestCount: 0}, // Lost its tests
+		{Name: "gadget", Kind: "resource", TestCount: 1}, // Gained one
+		{Name: "info", Kind: "data source", TestCount: 1},
+		{Name: "sprocket", Kind: "resource", TestCount: 0}, // New without a test
+	}
+
+	t.Run("gaps versus the base", func(t *testing.T) {
+		c := ghaction.Compare(head, base, "main")
+		assert.Equal(t, ghaction.Coverage{Tested: 2, Total: 4}, c.Head)
+		require.NotNil(t, c.Base)
+		assert.Equal(t, ghaction.Coverage{Tested: 2, Total: 3}, *c.Base)
+		assert.Equal(t, []metrics.Definition{head[3], head[0]}, c.NewGaps, "ordered by kind, then name")
+		assert.Equal(t, []metrics.Definition{head[1]}, c.Closed)
+
+		markdown := c.Markdown()
+		assert.True(t, strings.HasPrefix(markdown, ghaction.CommentMarker+"\n"))
+		assert.Contains(t, markdown, "**50%** of definitions have an acceptance test (2/4), -16.7 pts versus `main` (66.7%).")
+		assert.Contains(t, markdown, "| resource | 1 | 3 | 33.3% | -16.7 pts |")
+		assert.Contains(t, markdown, "### New gaps (2)")
+		assert.Contains(t, markdown, "- `sprocket` (resource)\n- `widget` (resource)\n")
+		assert.Contains(t, markdown, "### Closed gaps (1)\n\n- `gadget` (resource)\n")
+	})
+
+	t.Run("without a base", func(t *testing.T) {
+		c := ghaction.Compare(head, nil, "")
+		assert.Nil(t, c.Base)
+		assert.Empty(t, c.NewGaps)
+		markdown := c.Markdown()
+		assert.Contains(t, markdown, "(2/4).\n")
+		assert.NotContains(t, markdown, "New gaps")
+		assert.Contains(t, markdown, "All untested definitions (2)")
+	})
+
+	t.Run("outputs", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "output")
+		require.NoError(t, os.WriteFile(path, []byte("earlier=step\n"), 0o644))
+		require.NoError(t, ghaction.WriteOutputs(path, ghaction.Compare(head, base, "main")))
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "earlier=step\ncoverage=50.0\nnew_gaps=2\nbase_coverage=66.7\n", string(data))
+	})
+}
+
+func TestActionComment(t *testing.T) {
+	newServer := func(t *testing.T, existing []map[string]any) (*httptest.Server, *[]string) {
+		var requests []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+			requests = append(requests, r.Method+" "+r.URL.Path)
+			if r.Method == http.MethodGet {
+				json.NewEncoder(w).Encode(existing)
+				return
+			}
+			var payload map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			assert.Contains(t, payload["body"], "coverage")
+			w.WriteHeader(http.StatusCreated)
+		}))
+		t.Cleanup(server.Close)
+		return server, &requests
+	}
+	body := ghaction.CommentMarker + "\ncoverage"
+
+	t.Run("posts a new comment", func(t *testing.T) {
+		server, requests := newServer(t, []map[string]any{{"id": 1, "body": "LGTM"}})
+		gh := ghaction.GitHub{APIURL: server.URL, Token: "secret", Repository: "org/provider"}
+		require.NoError(t, gh.UpsertComment(context.Background(), 7, body))
+		assert.Equal(t, []string{"GET /repos/org/provider/issues/7/comments", "POST /repos/org/provider/issues/7/comments"}, *requests)
+	})
+
+	t.Run("updates its earlier comment", func(t *testing.T) {
+		server, requests := newServer(t, []map[string]any{{"id": 1, "body": "LGTM"}, {"id": 42, "body": body}})
+		gh := ghaction.GitHub{APIURL: server.URL, Token: "secret", Repository: "org/provider"}
+		require.NoError(t, gh.UpsertComment(context.Background(), 7, body))
+		assert.Equal(t, []string{"GET /repos/org/provider/issues/7/comments", "PATCH /repos/org/provider/issues/comments/42"}, *requests)
+	})
+
+	t.Run("pull request of the event", func(t *testing.T) {
+		dir := t.TempDir()
+		pr := filepath.Join(dir, "pr.json")
+		push := filepath.Join(dir, "push.json")
+		require.NoError(t, os.WriteFile(pr, []byte(`{"pull_request": {"number": 7}}`), 0o644))
+		require.NoError(t, os.WriteFile(push, []byte(`{"ref": "refs/heads/main"}`), 0o644))
+
+		number, err := ghaction.PullRequestNumber(pr)
+		require.NoError(t, err)
+		assert.Equal(t, 7, number)
+		number, err = ghaction.PullRequestNumber(push)
+		require.NoError(t, err)
+		assert.Zero(t, number, "not a pull request")
+	})
+}
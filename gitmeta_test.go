@@ -0,0 +1,79 @@
+package tfprovidertest
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/gitmeta"
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+func TestGitMetaParseCommitTime(t *testing.T) {
+	modified, err := gitmeta.ParseCommitTime("1700000000\n")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1700000000), modified.Unix())
+
+	_, err = gitmeta.ParseCommitTime("")
+	assert.Error(t, err, "a file without commits has no age")
+
+	_, err = gitmeta.ParseCommitTime("yesterday")
+	assert.Error(t, err)
+}
+
+func TestGitMetaBuild(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	reg := registry.NewResourceRegistry()
+	for _, info := range []*registry.ResourceInfo{
+		{Name: "fresh", Kind: registry.KindResource, FilePath: "fresh.go"},
+		{Name: "recent", Kind: registry.KindResource, FilePath: "recent.go"},
+		{Name: "tested", Kind: registry.KindResource, FilePath: "tested.go"},
+		{Name: "ancient", Kind: registry.KindDataSource, FilePath: "ancient.go"},
+		{Name: "untracked", Kind: registry.KindResource, FilePath: "untracked.go"},
+	} {
+		reg.RegisterResource(info)
+	}
+	fn := &registry.TestFunctionInfo{Name: "TestAccTested_basic", FilePath: "tested_test.go"}
+	reg.RegisterTestFunction(fn)
+	reg.LinkTest(registry.KindResource, "tested", fn)
+
+	ages := map[string]time.Duration{
+		"fresh.go":   2 * day,
+		"recent.go":  20 * day,
+		"tested.go":  3 * day,
+		"ancient.go": 800 * day,
+	}
+	lastModified := func(path string) (time.Time, bool) {
+		age, ok := ages[path]
+		return now.Add(-age), ok
+	}
+
+	report := gitmeta.Build(reg, gitmeta.DefaultBuckets(), now, lastModified)
+
+	assert.Equal(t, 1, report.Unknown)
+	require.Len(t, report.Buckets, 5)
+	assert.Equal(t, gitmeta.BucketReport{Label: "last 7 days", Total: 2, Tested: 1, Untested: 1, Coverage: 0.5}, report.Buckets[0])
+	assert.Equal(t, 1, report.Buckets[1].Untested)
+	assert.Equal(t, 0, report.Buckets[2].Total)
+	assert.Equal(t, 1, report.Buckets[4].Total, "anything past a year lands in the open-ended bucket")
+
+	require.Len(t, report.Untested, 3)
+	assert.Equal(t, "fresh", report.Untested[0].Name, "most recently modified first")
+	assert.Equal(t, 2, report.Untested[0].AgeDays)
+	assert.Equal(t, "recent", report.Untested[1].Name)
+	assert.Equal(t, "ancient", report.Untested[2].Name)
+	assert.Equal(t, "older", report.Untested[2].Bucket)
+}
+
+func TestGitMetaParseChangedFiles(t *testing.T) {
+	assert.Equal(t, []string{
+		filepath.Join("/repo", "internal", "widget.go"),
+		filepath.Join("/repo", "file with spaces.go"),
+	}, gitmeta.ParseChangedFiles("/repo", "internal/widget.go\x00file with spaces.go\x00"))
+	assert.Empty(t, gitmeta.ParseChangedFiles("/repo", ""))
+}
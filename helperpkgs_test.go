@@ -0,0 +1,100 @@
+package tfprovidertest
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const acctestPackageSrc = `package acctest
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func PreCheck(t *testing.T) {}
+
+func NewTestCase(t *testing.T, steps ...resource.TestStep) resource.TestCase {
+	return resource.TestCase{PreCheck: func() { PreCheck(t) }, Steps: steps}
+}
+
+func RunSteps(t *testing.T, steps []resource.TestStep) {
+	resource.ParallelTest(t, NewTestCase(t, steps...))
+}
+
+func runQuietly(t *testing.T, steps []resource.TestStep) {}
+`
+
+const gadgetHelperTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	at "example.com/provider/internal/acctest"
+)
+
+func TestAccGadget_basic(t *testing.T) {
+	at.RunSteps(t, []resource.TestStep{{Config: ` + "`" + `resource "example_gadget" "test" {}` + "`" + `}})
+}
+`
+
+func TestDetectHelperPackages(t *testing.T) {
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range []string{"/provider/internal/acctest/acctest.go", "/provider/resource_gadget_test.go"} {
+		src := acctestPackageSrc
+		if name == "/provider/resource_gadget_test.go" {
+			src = gadgetHelperTestSrc
+		}
+		f, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+		require.NoError(t, err)
+		files = append(files, f)
+	}
+
+	packages := discovery.DetectHelperPackages(files, fset)
+	require.Len(t, packages, 1)
+	assert.Equal(t, "acctest", packages[0].Name)
+	require.Len(t, packages[0].Helpers, 2, "PreCheck and unexported functions are not helpers")
+
+	newCase, runSteps := packages[0].Helpers[0], packages[0].Helpers[1]
+	assert.Equal(t, "NewTestCase", newCase.Name)
+	assert.Equal(t, discovery.HelperReturnsTestCase, newCase.Reason)
+	assert.Equal(t, []string{"acctest.NewTestCase", "at.NewTestCase"}, newCase.Qualified)
+	assert.Empty(t, newCase.UsedBy)
+	assert.Equal(t, discovery.HelperAcceptsSteps, runSteps.Reason)
+	assert.Equal(t, []string{"TestAccGadget_basic"}, runSteps.UsedBy)
+
+	settings := config.DefaultSettings()
+	settings.CustomTestHelpers = []string{"at.RunSteps"}
+	assert.Equal(t, []string{"at.RunSteps", "acctest.NewTestCase", "at.NewTestCase", "acctest.RunSteps"},
+		discovery.CustomHelpers(settings, fset, files))
+	settings.IgnoreHelperPackages = true
+	assert.Equal(t, []string{"at.RunSteps"}, discovery.CustomHelpers(settings, fset, files))
+}
+
+func TestDetectedHelpersCountAsAcceptanceTests(t *testing.T) {
+	sources := map[string]string{
+		"/provider/internal/acctest/acctest.go": acctestPackageSrc,
+		"/provider/resource_gadget.go":          untestedGadgetResourceSrc,
+		"/provider/resource_gadget_test.go":     gadgetHelperTestSrc,
+	}
+	settings := config.DefaultSettings()
+	assert.Empty(t, runAnalyzerOnSources(t, analysis.RunBasicTestAnalyzer, settings, sources))
+
+	settings.IgnoreHelperPackages = true
+	messages := runAnalyzerOnSources(t, analysis.RunBasicTestAnalyzer, settings, sources)
+	require.Len(t, messages, 1)
+	assert.Contains(t, messages[0], "'gadget'")
+}
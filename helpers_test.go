@@ -0,0 +1,116 @@
+package tfprovidertest
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	goanalysis "golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/internal/rules"
+	"github.com/example/tfprovidertest/internal/testsrc"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// parseSources parses sources in the given order into a shared FileSet.
+func parseSources(t *testing.T, names []string, sources map[string]string) (*token.FileSet, []*ast.File) {
+	t.Helper()
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range names {
+		f, err := parser.ParseFile(fset, name, sources[name], parser.ParseComments)
+		require.NoError(t, err)
+		files = append(files, f)
+	}
+	return fset, files
+}
+
+// buildRegistryFromSources parses sources, keyed by file name, and builds their registry
+// with the default settings.
+func buildRegistryFromSources(t *testing.T, sources map[string]string) *registry.ResourceRegistry {
+	t.Helper()
+	fset, files := parseSources(t, slices.Sorted(maps.Keys(sources)), sources)
+	return discovery.BuildRegistry(&goanalysis.Pass{Fset: fset, Files: files}, config.DefaultSettings())
+}
+
+// runAnalyzerOnSources parses the given files into a synthetic pass, runs the analyzer
+// function and returns the reported diagnostic messages.
+func runAnalyzerOnSources(t *testing.T, run func(*goanalysis.Pass, *config.Settings) (interface{}, error), settings config.Settings, sources map[string]string) []string {
+	t.Helper()
+	analysis.ClearAllRegistryCaches()
+	t.Cleanup(analysis.ClearAllRegistryCaches)
+
+	fset, files := parseSources(t, slices.Sorted(maps.Keys(sources)), sources)
+	var messages []string
+	pass := &goanalysis.Pass{
+		Fset:  fset,
+		Files: files,
+		Report: func(d goanalysis.Diagnostic) {
+			messages = append(messages, d.Message)
+		},
+	}
+	_, err := run(pass, &settings)
+	require.NoError(t, err)
+	return messages
+}
+
+// runBasicAnalyzerOnSources parses the given files into a synthetic pass, runs the
+// basic test analyzer and returns the reported diagnostic messages.
+func runBasicAnalyzerOnSources(t *testing.T, settings config.Settings, sources map[string]string) []string {
+	t.Helper()
+	return runAnalyzerOnSources(t, analysis.RunBasicTestAnalyzer, settings, sources)
+}
+
+// runBasicAnalyzerPerPackage runs the plugin's basic-test analyzer once per simulated package
+// variant over the same files, as golangci-lint does for a package and its test variant.
+func runBasicAnalyzerPerPackage(t *testing.T, plugin *Plugin, variants int) []string {
+	t.Helper()
+	analysis.ClearAllRegistryCaches()
+	t.Cleanup(analysis.ClearAllRegistryCaches)
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "/provider/resource_certificate.go", lowConfidenceResourceSrc, parser.ParseComments)
+	require.NoError(t, err)
+
+	analyzers, err := plugin.BuildAnalyzers()
+	require.NoError(t, err)
+	var basic *goanalysis.Analyzer
+	for _, a := range analyzers {
+		if a.Name == rules.BasicTest {
+			basic = a
+		}
+	}
+	require.NotNil(t, basic)
+
+	var messages []string
+	for i := 0; i < variants; i++ {
+		pass := &goanalysis.Pass{
+			Analyzer: basic,
+			Fset:     fset,
+			Files:    []*ast.File{file},
+			Report: func(d goanalysis.Diagnostic) {
+				messages = append(messages, d.Message)
+			},
+		}
+		_, err := basic.Run(pass)
+		require.NoError(t, err)
+	}
+	return messages
+}
+
+// factoryTestSrc returns a test file whose TestAcc<name>_basic test case sets caseFields,
+// and whose steps set the fields of steps, one step per element.
+func factoryTestSrc(name, caseFields string, steps ...string) string {
+	test := testsrc.Test{Name: "TestAcc" + name + "_basic", Case: caseFields}
+	for _, fields := range steps {
+		test.Steps = append(test.Steps, testsrc.Config(`resource "example_`+name+`" "x" {}`)+", "+fields)
+	}
+	return testsrc.File(test)
+}
@@ -0,0 +1,92 @@
+package tfprovidertest
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	goanalysis "golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/rules"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+func TestHooksStreamScanEvents(t *testing.T) {
+	analysis.ClearAllRegistryCaches()
+	t.Cleanup(analysis.ClearAllRegistryCaches)
+
+	var resources []string
+	var links []config.LinkEvent
+	var diagnostics []config.DiagnosticEvent
+	settings := config.DefaultSettings()
+	settings.Hooks = &config.Hooks{
+		OnResourceDiscovered: func(e config.ResourceEvent) { resources = append(resources, e.Kind+":"+e.Name) },
+		OnTestLinked:         func(e config.LinkEvent) { links = append(links, e) },
+		OnDiagnostic:         func(e config.DiagnosticEvent) { diagnostics = append(diagnostics, e) },
+	}
+	plugin := NewWithSettings(settings)
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for name, src := range map[string]string{
+		"/provider/resource_gadget.go":      untestedGadgetResourceSrc,
+		"/provider/resource_gadget_test.go": gadgetBasicTestSrc,
+		"/provider/resource_sprocket.go":    untestedSprocketResourceSrc,
+	} {
+		f, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+		require.NoError(t, err)
+		files = append(files, f)
+	}
+
+	analyzers, err := plugin.BuildAnalyzers()
+	require.NoError(t, err)
+	var reported int
+	for _, a := range analyzers {
+		if a.Name != rules.BasicTest {
+			continue
+		}
+		_, err := a.Run(&goanalysis.Pass{
+			Analyzer: a,
+			Fset:     fset,
+			Files:    files,
+			Report:   func(goanalysis.Diagnostic) { reported++ },
+		})
+		require.NoError(t, err)
+	}
+
+	assert.ElementsMatch(t, []string{"resource:gadget", "resource:sprocket"}, resources)
+	require.Len(t, links, 1)
+	assert.Equal(t, "TestAccGadget_basic", links[0].Test)
+	assert.Equal(t, "gadget", links[0].Resource)
+	assert.Equal(t, "resource", links[0].Kind)
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, 1, reported, "hooks observe diagnostics without consuming them")
+	assert.Equal(t, rules.BasicTest, diagnostics[0].Rule)
+	assert.Equal(t, "/provider/resource_sprocket.go", diagnostics[0].File)
+}
+
+func TestHooksSerializeConcurrentEvents(t *testing.T) {
+	count := 0
+	hooks := &config.Hooks{OnDiagnostic: func(config.DiagnosticEvent) { count++ }}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				hooks.Diagnostic(config.DiagnosticEvent{})
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 800, count)
+
+	var unset *config.Hooks
+	assert.NotPanics(t, func() { unset.TestLinked(config.LinkEvent{}) })
+}
@@ -0,0 +1,148 @@
+package tfprovidertest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// idempotencyResourceSrc declares a resource named after its type with an Optional+Computed
+// attribute; %s is replaced with the type name.
+const idempotencyResourceSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+type %sResource struct{}
+
+func (r *%sResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{Required: true},
+			"tags": schema.MapAttribute{Optional: true, Computed: true},
+		},
+	}
+}
+`
+
+const idempotencyGadgetTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccGadget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGadgetConfig("a"),
+			},
+			{
+				Config:   testAccGadgetConfig("a"),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func testAccGadgetConfig(name string) string {
+	return ` + "`" + `resource "example_gadget" "test" { name = "` + "`" + ` + name + ` + "`" + `" }` + "`" + `
+}
+`
+
+const idempotencySprocketTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+)
+
+func TestAccSprocket_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSprocketConfig("a"),
+			},
+			{
+				Config:             testAccSprocketConfig("a"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccSprocketConfig("b"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+			},
+		},
+	})
+}
+
+func testAccSprocketConfig(name string) string {
+	return ` + "`" + `resource "example_sprocket" "test" { name = "` + "`" + ` + name + ` + "`" + `" }` + "`" + `
+}
+`
+
+func TestIdempotency(t *testing.T) {
+	sources := map[string]string{
+		"/provider/resource_gadget.go":        strings.ReplaceAll(idempotencyResourceSrc, "%s", "Gadget"),
+		"/provider/resource_gadget_test.go":   idempotencyGadgetTestSrc,
+		"/provider/resource_sprocket.go":      strings.ReplaceAll(idempotencyResourceSrc, "%s", "Sprocket"),
+		"/provider/resource_sprocket_test.go": idempotencySprocketTestSrc,
+	}
+
+	t.Run("steps", func(t *testing.T) {
+		reg := buildRegistryFromSources(t, sources)
+
+		gadget := reg.GetTests(registry.KindResource, "gadget")
+		require.Len(t, gadget, 1)
+		require.Len(t, gadget[0].TestSteps, 2)
+		assert.True(t, gadget[0].TestSteps[1].IsIdempotencyStep(), "the same config planned again")
+		assert.False(t, gadget[0].TestSteps[1].IsUpdateStepFlag)
+		assert.True(t, registry.CoveredChecks(gadget)[registry.CheckIdempotency])
+
+		sprocket := reg.GetTests(registry.KindResource, "sprocket")
+		require.Len(t, sprocket, 1)
+		steps := sprocket[0].TestSteps
+		require.Len(t, steps, 3)
+		assert.False(t, steps[1].IsIdempotencyStep(), "a non-empty plan is expected")
+		assert.True(t, steps[2].ExpectsEmptyPlan)
+		assert.False(t, steps[2].IsIdempotencyStep(), "the config changed")
+		assert.False(t, registry.CoveredChecks(sprocket)[registry.CheckIdempotency])
+
+		def := reg.GetDefinition(registry.KindResource, "sprocket")
+		require.NotNil(t, def)
+		assert.Equal(t, []string{"tags"}, analysis.PerpetualDiffAttributes(def))
+		calculator := analysis.NewCoverageCalculator(reg)
+		assert.True(t, calculator.GetResourceCoverage("gadget").HasIdempotencyStep)
+		assert.False(t, calculator.GetResourceCoverage("sprocket").HasIdempotencyStep)
+	})
+
+	t.Run("analyzer", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		settings.EnableIdempotencyCheck = true
+		messages := runAnalyzerOnSources(t, analysis.RunIdempotencyAnalyzer, settings, sources)
+
+		require.Len(t, messages, 1, strings.Join(messages, "\n\n"))
+		assert.Contains(t, messages[0], "resource 'sprocket' has Optional+Computed attribute(s) prone to perpetual diffs (tags), but none of its 1 test(s) plans the same config again expecting no changes")
+	})
+
+	t.Run("settings", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		assert.False(t, settings.IdempotencyCheckEnabled(), "the rule is opt-in")
+	})
+}
@@ -0,0 +1,131 @@
+package tfprovidertest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const importIdResourceSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+type WidgetResource struct{}
+
+func (r *WidgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name":    schema.StringAttribute{Required: true},
+			"project": schema.StringAttribute{Required: true},
+			"tags":    schema.MapAttribute{Optional: true},
+		},
+		Blocks: map[string]schema.Block{
+			"network": schema.ListNestedBlock{},
+		},
+	}
+}
+
+func (r *WidgetResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+}
+`
+
+const importIdTestSrc = `package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccWidget_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccWidgetConfig()},
+			{
+				ResourceName:      "example_widget.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs := s.RootModule().Resources["example_widget.test"]
+					return fmt.Sprintf("%s/%s", rs.Primary.Attributes["projct"], rs.Primary.Attributes["name"]), nil
+				},
+			},
+			{
+				ResourceName:      "example_widget.test",
+				ImportState:       true,
+				ImportStateIdFunc: testAccWidgetImportID("example_widget.test", "nme"),
+			},
+			{
+				ResourceName:      "example_widget.test",
+				ImportState:       true,
+				ImportStateIdFunc: testAccWidgetImportIDFromState,
+			},
+		},
+	})
+}
+
+func testAccWidgetImportID(resourceName, attr string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs := s.RootModule().Resources[resourceName]
+		return rs.Primary.Attributes[attr], nil
+	}
+}
+
+func testAccWidgetConfig() string {
+	return ` + "`" + `resource "example_widget" "test" {}` + "`" + `
+}
+`
+
+const importIdHelpersSrc = `package provider
+
+import "github.com/hashicorp/terraform-plugin-testing/terraform"
+
+func testAccWidgetImportIDFromState(s *terraform.State) (string, error) {
+	rs := s.RootModule().Resources["example_widget.test"]
+	return rs.Primary.Attributes["id"] + rs.Primary.Attributes["tags.%"] + rs.Primary.Attributes["network.0.region"] + rs.Primary.Attributes["zone"], nil
+}
+`
+
+func TestImportStateIdFuncAnalyzer(t *testing.T) {
+	sources := map[string]string{
+		"/provider/resource_widget.go":      importIdResourceSrc,
+		"/provider/resource_widget_test.go": importIdTestSrc,
+		"/provider/helpers_test.go":         importIdHelpersSrc,
+	}
+
+	t.Run("reports reads of attributes missing from the schema", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunImportStateIdFuncAnalyzer, config.DefaultSettings(), sources)
+		require.Len(t, messages, 3, "messages: %v", messages)
+
+		joined := strings.Join(messages, "\n")
+		assert.Contains(t, joined, "ImportStateIdFunc func literal in test 'TestAccWidget_import' reads state attribute 'projct', which is not defined in the schema of resource 'widget' (did you mean 'project'?)")
+		assert.Contains(t, joined, "ImportStateIdFunc testAccWidgetImportID in test 'TestAccWidget_import' reads state attribute 'nme'")
+		assert.Contains(t, joined, "ImportStateIdFunc testAccWidgetImportIDFromState in test 'TestAccWidget_import' reads state attribute 'zone'")
+		assert.NotContains(t, joined, "'tags'")
+		assert.NotContains(t, joined, "'network'")
+	})
+
+	t.Run("skips schemas assembled outside a map literal", func(t *testing.T) {
+		src := strings.Replace(importIdResourceSrc, `Blocks: map[string]schema.Block{
+			"network": schema.ListNestedBlock{},
+		},`, "Blocks: widgetBlocks(),", 1)
+		messages := runAnalyzerOnSources(t, analysis.RunImportStateIdFuncAnalyzer, config.DefaultSettings(), map[string]string{
+			"/provider/resource_widget.go":      src,
+			"/provider/resource_widget_test.go": importIdTestSrc,
+			"/provider/helpers_test.go":         importIdHelpersSrc,
+		})
+		assert.Empty(t, messages)
+	})
+}
@@ -0,0 +1,213 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const importVerifyTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccWidget_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccWidgetConfig},
+			{
+				ResourceName: "example_widget.test",
+				ImportState:  true,
+			},
+		},
+	})
+}
+
+func TestAccWidget_importExplicitFalse(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccWidgetConfig},
+			{
+				ResourceName:      "example_widget.test",
+				ImportState:       true,
+				ImportStateVerify: false,
+			},
+		},
+	})
+}
+
+func TestAccWidget_importVerified(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccWidgetConfig},
+			{
+				ResourceName:      "example_widget.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccWidget_importIgnored(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccWidgetConfig},
+			{
+				ResourceName:            "example_widget.test",
+				ImportState:             true,
+				ImportStateVerifyIgnore: []string{"password"},
+			},
+		},
+	})
+}
+`
+
+func TestImportStateVerifyAnalyzer(t *testing.T) {
+	settings := config.DefaultSettings()
+	settings.EnableImportStateVerifyCheck = true
+
+	diags := runAnalyzerOnSources(t, analysis.RunImportStateVerifyAnalyzer, settings, map[string]string{
+		"/provider/resource_widget.go":      importIdResourceSrc,
+		"/provider/resource_widget_test.go": importVerifyTestSrc,
+	})
+
+	require.Len(t, diags, 2)
+	assert.Contains(t, diags[0], "test 'TestAccWidget_import' sets ImportState without ImportStateVerify")
+	assert.Contains(t, diags[0], "resource_widget_test.go:")
+	assert.Contains(t, diags[1], "TestAccWidget_importExplicitFalse")
+}
+
+const importStateCheckTestSrc = `package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccWidget_importCheckLiteral(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccWidgetConfig},
+			{
+				ResourceName: "example_widget.test",
+				ImportState:  true,
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 {
+						return fmt.Errorf("expected 1 state, got %d", len(states))
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccWidget_importCheckHelper(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccWidgetConfig},
+			{
+				ResourceName:     "example_widget.test",
+				ImportState:      true,
+				ImportStateCheck: testAccCheckWidgetImported("name"),
+			},
+		},
+	})
+}
+
+func TestAccWidget_importCheckNoAssertions(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccWidgetConfig},
+			{
+				ResourceName:     "example_widget.test",
+				ImportState:      true,
+				ImportStateCheck: testAccLogImport,
+			},
+		},
+	})
+}
+
+func TestAccWidget_importCheckExternal(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccWidgetConfig},
+			{
+				ResourceName:     "example_widget.test",
+				ImportState:      true,
+				ImportStateCheck: acctest.ImportCheckCount(1),
+			},
+		},
+	})
+}
+
+func testAccCheckWidgetImported(key string) resource.ImportStateCheckFunc {
+	return func(states []*terraform.InstanceState) error {
+		if states[0].Attributes[key] == "" {
+			return fmt.Errorf("%s not imported", key)
+		}
+		return nil
+	}
+}
+
+func testAccLogImport(states []*terraform.InstanceState) error {
+	fmt.Println("imported")
+	return nil
+}
+`
+
+func TestImportStateCheckVerifiesImport(t *testing.T) {
+	settings := config.DefaultSettings()
+	settings.EnableImportStateVerifyCheck = true
+	sources := map[string]string{
+		"/provider/resource_widget.go":      importIdResourceSrc,
+		"/provider/resource_widget_test.go": importStateCheckTestSrc,
+	}
+
+	diags := runAnalyzerOnSources(t, analysis.RunImportStateVerifyAnalyzer, settings, sources)
+	require.Len(t, diags, 1, "checks that count instances, read attributes or cannot be resolved are credited")
+	assert.Contains(t, diags[0], "test 'TestAccWidget_importCheckNoAssertions' sets ImportStateCheck, but testAccLogImport neither counts the imported instances nor reads their attributes")
+
+	reg := buildRegistryFromSources(t, sources)
+	steps := make(map[string]registry.TestStepInfo)
+	for _, fn := range reg.GetAllTestFunctions() {
+		steps[fn.Name] = fn.TestSteps[1]
+	}
+	literal := steps["TestAccWidget_importCheckLiteral"]
+	assert.True(t, literal.ImportStateCheckResolved)
+	assert.True(t, literal.ImportStateCheckCountsInstances)
+	assert.True(t, literal.IsValidImportStep())
+
+	helper := steps["TestAccWidget_importCheckHelper"]
+	assert.Equal(t, "testAccCheckWidgetImported", helper.ImportStateCheck)
+	assert.False(t, helper.ImportStateCheckCountsInstances)
+	assert.Equal(t, []string{"name"}, helper.ImportStateCheckAttributes)
+
+	external := steps["TestAccWidget_importCheckExternal"]
+	assert.False(t, external.ImportStateCheckResolved)
+	assert.True(t, external.VerifiesImportWithCheck())
+}
+
+func TestImportStateVerifyCheckEnabled(t *testing.T) {
+	settings := config.DefaultSettings()
+	assert.False(t, settings.ImportStateVerifyCheckEnabled(), "opt-in")
+
+	settings.EnableImportStateVerifyCheck = true
+	assert.True(t, settings.ImportStateVerifyCheckEnabled())
+
+	disabled := false
+	settings.EnableQualityRules = &disabled
+	assert.False(t, settings.ImportStateVerifyCheckEnabled(), "quality group override wins")
+}
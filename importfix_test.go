@@ -0,0 +1,96 @@
+package tfprovidertest
+
+import (
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	goanalysis "golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// importMissingInlineTestSrc is the import_missing fixture's test with a one-line Steps literal.
+const importMissingInlineTestSrc = `package import_missing
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccServer_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: ` + "`" + `resource "example_server" "main" { name = "example" }` + "`" + `}},
+	})
+}
+`
+
+// fixImportTest runs the import analyzer over the import_missing fixture's resource and the
+// given test source, and returns the test source with the suggested fix applied.
+func fixImportTest(t *testing.T, testSrc string) (string, goanalysis.SuggestedFix) {
+	t.Helper()
+	analysis.ClearAllRegistryCaches()
+	t.Cleanup(analysis.ClearAllRegistryCaches)
+
+	resourceSrc, err := os.ReadFile("testdata/src/testlintdata/import_missing/resource_server.go")
+	require.NoError(t, err)
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for name, src := range map[string]string{
+		"/provider/resource_server.go":      string(resourceSrc),
+		"/provider/resource_server_test.go": testSrc,
+	} {
+		f, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+		require.NoError(t, err)
+		files = append(files, f)
+	}
+
+	var diags []goanalysis.Diagnostic
+	pass := &goanalysis.Pass{Fset: fset, Files: files, Report: func(d goanalysis.Diagnostic) { diags = append(diags, d) }}
+	settings := config.DefaultSettings()
+	_, err = analysis.RunImportTestAnalyzer(pass, &settings)
+	require.NoError(t, err)
+	require.Len(t, diags, 1)
+	require.Len(t, diags[0].SuggestedFixes, 1)
+
+	fix := diags[0].SuggestedFixes[0]
+	require.Len(t, fix.TextEdits, 1)
+	edit := fix.TextEdits[0]
+	file := fset.File(edit.Pos)
+	require.Equal(t, "/provider/resource_server_test.go", file.Name())
+	fixed := testSrc[:file.Offset(edit.Pos)] + string(edit.NewText) + testSrc[file.Offset(edit.End):]
+	formatted, err := format.Source([]byte(fixed))
+	require.NoError(t, err)
+	assert.Equal(t, fixed, string(formatted), "the fix is already gofmt'd")
+	return fixed, fix
+}
+
+func TestImportStepFix(t *testing.T) {
+	t.Run("appended to a multi-line Steps literal", func(t *testing.T) {
+		src, err := os.ReadFile("testdata/src/testlintdata/import_missing/resource_server_test.go")
+		require.NoError(t, err)
+		fixed, fix := fixImportTest(t, string(src))
+		assert.Equal(t, "Add an import step for example_server.test to TestAccServer_basic", fix.Message)
+		assert.Contains(t, fixed, `				),
+			},
+			{
+				ResourceName:      "example_server.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})`)
+	})
+
+	t.Run("appended to a one-line Steps literal", func(t *testing.T) {
+		fixed, _ := fixImportTest(t, importMissingInlineTestSrc)
+		assert.Contains(t, fixed, `"example" }`+"`"+`}, {ResourceName: "example_server.main", ImportState: true, ImportStateVerify: true}},`)
+	})
+}
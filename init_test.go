@@ -0,0 +1,82 @@
+package tfprovidertest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/initconfig"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+func TestSettingsFile(t *testing.T) {
+	t.Run("settings start from the profile the file names", func(t *testing.T) {
+		s, err := config.ParseFile([]byte("profile: minimal\nenable-update-test: true\nprovider-prefix: example\n"), config.File, "")
+		require.NoError(t, err)
+		assert.Equal(t, config.ProfileMinimal, s.Profile)
+		assert.True(t, s.EnableUpdateTest)
+		assert.False(t, s.EnableErrorTest, "the minimal profile leaves error tests off")
+		assert.Equal(t, "example", s.ProviderPrefix)
+	})
+
+	t.Run("a profile given by the caller wins over the file's", func(t *testing.T) {
+		s, err := config.ParseFile([]byte("profile: minimal\n"), config.File, config.ProfileStrict)
+		require.NoError(t, err)
+		assert.Equal(t, config.ProfileStrict, s.Profile)
+		assert.True(t, s.EnableDeadTestCheck)
+	})
+
+	t.Run("unknown settings and nested config files are errors", func(t *testing.T) {
+		_, err := config.ParseFile([]byte("enable-updat-test: true\n"), config.File, "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "enable-updat-test")
+
+		_, err = config.ParseFile([]byte("config-file: other.yaml\n"), config.File, "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "config-file cannot be set in a settings file")
+	})
+
+	t.Run("the file validate init writes parses to the detected values", func(t *testing.T) {
+		content := initconfig.Render(initconfig.Detected{
+			ModulePath:     "github.com/example/terraform-provider-example",
+			ProviderPrefix: "example",
+			TestLayout:     config.TestLayoutCentralized,
+			HelperPackages: []initconfig.HelperPackage{{Name: "acctest", Dir: "internal/acctest", Helpers: []string{"Test"}}},
+			Resources:      2,
+			Tests:          3,
+		})
+		assert.Contains(t, content, "# tfprovidertest settings for github.com/example/terraform-provider-example")
+		assert.Contains(t, content, "#   acctest (internal/acctest): Test")
+
+		s, err := config.ParseFile([]byte(content), config.File, "")
+		require.NoError(t, err)
+		require.NoError(t, s.Validate())
+		recommended, err := config.ProfileSettings(config.ProfileRecommended)
+		require.NoError(t, err)
+		assert.Equal(t, config.ProfileRecommended, s.Profile)
+		assert.Equal(t, "example", s.ProviderPrefix)
+		assert.Equal(t, config.LayoutAuto, s.Layout)
+		assert.Equal(t, config.TestLayoutCentralized, s.TestLayout)
+		assert.Equal(t, recommended.EnableUpdateTest, s.EnableUpdateTest)
+		assert.Equal(t, recommended.EnableDeadTestCheck, s.EnableDeadTestCheck)
+	})
+
+	t.Run("the plugin reads ConfigFile and settings beside it override the file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), config.File)
+		require.NoError(t, os.WriteFile(path, []byte("profile: minimal\nenable-update-test: true\n"), 0o644))
+
+		plugin, err := New(map[string]interface{}{"ConfigFile": path, "EnableImportTest": false})
+		require.NoError(t, err)
+		s := plugin.(*Plugin).settings
+		assert.Equal(t, config.ProfileMinimal, s.Profile)
+		assert.True(t, s.EnableUpdateTest)
+		assert.False(t, s.EnableImportTest)
+
+		_, err = New(map[string]interface{}{"ConfigFile": filepath.Join(t.TempDir(), "missing.yaml")})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to load config-file")
+	})
+}
@@ -3,7 +3,7 @@
 //
 // # Registry Caching Architecture
 //
-// This package uses a global cache to share ResourceRegistry instances between the 7 analyzers:
+// This package uses a global cache to share ResourceRegistry instances between the analyzers:
 //   1. BasicTestAnalyzer    - Checks for basic test coverage
 //   2. UpdateTestAnalyzer   - Checks for update test coverage
 //   3. ImportTestAnalyzer   - Checks for import test coverage
@@ -11,21 +11,60 @@
 //   5. StateCheckAnalyzer   - Checks for state validation in tests
 //   6. DriftCheckAnalyzer   - Checks for CheckDestroy in tests
 //   7. SweeperAnalyzer      - Checks for test sweeper registrations
+//   8. ProviderConfigAnalyzer - Checks that provider configuration is exercised (opt-in)
+//   9. ImportStateIdFuncAnalyzer - Checks ImportStateIdFunc attribute reads against the schema (opt-in)
+//  10. ParallelFixturesAnalyzer - Checks parallel tests for clashing hard-coded names (opt-in)
+//  11. ImportStateVerifyAnalyzer - Checks that import steps set ImportStateVerify (opt-in)
+//  12. RequirementsAnalyzer - Checks disappears tests and strict mode of the requirements manifest
+//  13. ExpectErrorAnalyzer - Checks ExpectError regexes for compile errors and broad patterns (opt-in)
+//  14. DeadTestsAnalyzer - Checks test files for commented-out acceptance tests (opt-in)
+//  15. DeferredActionsAnalyzer - Checks that providers which defer changes test with deferral allowed
+//  16. ParallelTestsAnalyzer - Recommends resource.ParallelTest for independent serial tests (opt-in)
+//  17. TestPlacementAnalyzer - Checks that tests live in the test file of the resource they test (opt-in)
+//  18. OrphanTestsAnalyzer - Reports acceptance tests linked to no definition at the test function (opt-in)
+//  19. DefaultValuesAnalyzer - Reports attribute defaults that every test config overrides (opt-in)
+//  20. ProviderHygieneAnalyzer - Checks that test packages wire TestMain to run tests and sweepers (opt-in)
+//  21. ProviderAliasAnalyzer - Checks that cross-provider resources are tested with an aliased provider (opt-in)
+//  22. UnknownTypesAnalyzer - Reports test configs declaring types the provider does not define (opt-in)
+//  23. TestHelpersAnalyzer - Reports helpers that call t.Fatal from goroutines or discard errors (opt-in)
+//  24. DataSourceConfigAnalyzer - Checks that data source tests create the resource they read (opt-in)
+//  25. CheckAddressesAnalyzer - Checks that state checks name addresses the step's config declares (opt-in)
+//  26. RefreshDriftAnalyzer - Checks that tested resources have a RefreshState step (opt-in)
+//  27. EnvPreCheckAnalyzer - Checks that env vars interpolated into configs are checked in PreCheck (opt-in)
+//  28. DeprecatedAttributesAnalyzer - Checks that tests of deprecated attributes also cover their replacements (opt-in)
+//  29. UpgradeTestAnalyzer - Checks that resources whose schema version changed since a git ref have an upgrade test (opt-in)
+//  30. DataSourceAssertsAnalyzer - Checks that data source tests assert computed attributes beyond id (opt-in)
+//  31. TestLayoutAnalyzer - Checks that tests live in the directory of the definition they test (opt-in)
+//  32. IdempotencyAnalyzer - Checks that resources prone to perpetual diffs re-plan a config expecting no changes (opt-in)
+//  33. PlaceholderValuesAnalyzer - Reports placeholder values for attributes with format validators (opt-in)
+//  34. PairedDefinitionsAnalyzer - Checks that a resource and a data source sharing a name are both tested (opt-in)
+//  35. PluralDataSourcesAnalyzer - Checks that plural data source tests assert how many items are returned (opt-in)
+//  36. LegacyChecksAnalyzer - Checks that test steps added since a git ref use ConfigStateChecks rather than Check (opt-in)
+//  37. ProviderFactoriesAnalyzer - Checks that a package's tests use one provider factory field and give every step a provider (opt-in)
+//  38. ErrorCheckPresenceAnalyzer - Checks that TestCases set ErrorCheck where it is their package's pattern (opt-in)
 //
 // Without caching, each analyzer would parse the AST independently, resulting in 7x redundant work.
-// The cache ensures buildRegistry() is called only once per analysis.Pass, providing significant
-// performance improvements (typically 6-7x speedup).
+// golangci-lint gives each analyzer its own analysis.Pass, but passes over the same package share
+// the FileSet and parsed files, so the cache is keyed on those. buildRegistry() is then called only
+// once per package, providing significant performance improvements (typically 6-7x speedup).
 //
 // # Cache Implementation
 //
 // The cache uses a two-level structure:
-//   - globalCache: map[*analysis.Pass]*registryCache (protected by globalCacheMu)
+//   - globalCache: map[cacheKey]*registryCache (protected by globalCacheMu)
 //   - registryCache: contains sync.Once, mutex, and the actual ResourceRegistry
 //
 // This design ensures:
 //   - Thread safety for concurrent analysis runs
-//   - Exactly-once initialization per pass (via sync.Once)
-//   - Support for multiple concurrent passes (each gets its own cache entry)
+//   - Exactly-once initialization per package (via sync.Once)
+//   - Support for multiple concurrent packages (each gets its own cache entry)
+//
+// # golangci-lint Result Cache
+//
+// golangci-lint caches diagnostics and facts per package and skips re-analysis of unchanged
+// packages on warm runs. That only works if analyzers are deterministic, so every analyzer
+// iterates definitions in sorted order (ResourceRegistry.GetSortedDefinitions) and
+// facts are plain gob-encodable structs (see facts.go).
 //
 // # Memory Management
 //
@@ -61,7 +100,7 @@
 // Why Mutex is Sufficient:
 //   1. Cache map is small (typically 1-10 entries per golangci-lint run)
 //   2. Lock is released before expensive buildRegistry() call
-//   3. 7 analyzers run sequentially per pass (not concurrently)
+//   3. 7 analyzers run sequentially per package (not concurrently)
 //   4. Contention only occurs across different passes (rare)
 //   5. Profiling shows no lock contention bottleneck
 //
@@ -90,7 +129,11 @@ package analysis
 
 import (
 	"fmt"
+	"go/ast"
+	"go/token"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -98,6 +141,8 @@ import (
 	"golang.org/x/tools/go/analysis"
 
 	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/internal/matching"
+	"github.com/example/tfprovidertest/internal/messages"
 	"github.com/example/tfprovidertest/internal/registry"
 	"github.com/example/tfprovidertest/pkg/config"
 )
@@ -114,7 +159,7 @@ import (
 // Thread Safety:
 //   - globalCacheMu protects access to the globalCache map
 //   - Each registryCache has its own mutex protecting the registry field
-//   - sync.Once ensures buildRegistry is called exactly once per pass
+//   - sync.Once ensures buildRegistry is called exactly once per package
 type registryCache struct {
 	mu        sync.Mutex
 	registry  *registry.ResourceRegistry
@@ -122,9 +167,28 @@ type registryCache struct {
 	createdAt time.Time // Timestamp when this cache entry was created
 }
 
-// Global cache map keyed by analysis.Pass pointer to support multiple concurrent analysis runs.
+// cacheKey identifies the package a pass analyzes. Passes for different analyzers over the
+// same package share the FileSet and parsed files, so they map to the same key. Settings
+// are part of the key because separate plugin instances may analyze the same files.
+type cacheKey struct {
+	fset     *token.FileSet
+	first    *ast.File
+	files    int
+	settings *config.Settings
+	pass     *analysis.Pass // Set only when the pass has no files to identify it by
+}
+
+// passCacheKey returns the cache key for a pass.
+func passCacheKey(pass *analysis.Pass, settings *config.Settings) cacheKey {
+	if len(pass.Files) == 0 {
+		return cacheKey{pass: pass, settings: settings}
+	}
+	return cacheKey{fset: pass.Fset, first: pass.Files[0], files: len(pass.Files), settings: settings}
+}
+
+// Global cache map keyed by package to support multiple concurrent analysis runs.
 // This cache is shared across all 7 analyzers (BasicTest, UpdateTest, ImportTest, ErrorTest,
-// StateCheck, DriftCheck, Sweeper) to avoid re-parsing the AST multiple times per pass.
+// StateCheck, DriftCheck, Sweeper) to avoid re-parsing the AST multiple times per package.
 //
 // Cache Lifecycle:
 //   - Entries are created lazily in getOrBuildRegistry()
@@ -132,11 +196,11 @@ type registryCache struct {
 //   - Use ClearAllRegistryCaches() for global cleanup (e.g., test teardown)
 var (
 	globalCacheMu sync.Mutex
-	globalCache   = make(map[*analysis.Pass]*registryCache)
+	globalCache   = make(map[cacheKey]*registryCache)
 )
 
 // getOrBuildRegistry retrieves a cached registry for the given pass, or builds it if not yet cached.
-// This ensures buildRegistry is called only once per analysis pass, even when all 7 analyzers run.
+// This ensures buildRegistry is called only once per package, even when all analyzers run in separate passes.
 //
 // TTL-based Eviction:
 //   - Checks cache entry age against configured CacheTTL
@@ -150,19 +214,20 @@ var (
 //
 // Usage:
 //   registry := getOrBuildRegistry(pass, settings)
-//   defer ClearRegistryCache(pass) // Recommended for cleanup
+//   defer ClearRegistryCache(pass, settings) // Recommended for cleanup
 func getOrBuildRegistry(pass *analysis.Pass, settings *config.Settings) *registry.ResourceRegistry {
 	cacheTTL := settings.GetCacheTTLDuration()
+	key := passCacheKey(pass, settings)
 
 	globalCacheMu.Lock()
-	cache, exists := globalCache[pass]
+	cache, exists := globalCache[key]
 
 	// Check if cache entry exists and is still valid (TTL check)
 	if exists && cacheTTL > 0 {
 		age := time.Since(cache.createdAt)
 		if age > cacheTTL {
 			// Cache entry expired, remove it
-			delete(globalCache, pass)
+			delete(globalCache, key)
 			exists = false
 		}
 	}
@@ -171,11 +236,11 @@ func getOrBuildRegistry(pass *analysis.Pass, settings *config.Settings) *registr
 		cache = &registryCache{
 			createdAt: time.Now(),
 		}
-		globalCache[pass] = cache
+		globalCache[key] = cache
 	}
 	globalCacheMu.Unlock()
 
-	// Use sync.Once to ensure buildRegistry is called only once per pass
+	// Use sync.Once to ensure buildRegistry is called only once per package
 	cache.once.Do(func() {
 		cache.mu.Lock()
 		defer cache.mu.Unlock()
@@ -187,7 +252,8 @@ func getOrBuildRegistry(pass *analysis.Pass, settings *config.Settings) *registr
 	return cache.registry
 }
 
-// ClearRegistryCache clears the cache entry for a specific analysis pass.
+// ClearRegistryCache clears the cache entry for the package a pass analyzes, built with
+// the given settings.
 // This should be called after all analyzers have completed for a given pass to prevent memory leaks.
 //
 // When to call:
@@ -197,13 +263,13 @@ func getOrBuildRegistry(pass *analysis.Pass, settings *config.Settings) *registr
 //
 // Example:
 //   registry := getOrBuildRegistry(pass, settings)
-//   defer ClearRegistryCache(pass)
+//   defer ClearRegistryCache(pass, settings)
 //
 // Thread-safe: Can be called concurrently from multiple goroutines.
-func ClearRegistryCache(pass *analysis.Pass) {
+func ClearRegistryCache(pass *analysis.Pass, settings *config.Settings) {
 	globalCacheMu.Lock()
 	defer globalCacheMu.Unlock()
-	delete(globalCache, pass)
+	delete(globalCache, passCacheKey(pass, settings))
 }
 
 // ClearAllRegistryCaches clears all cache entries across all analysis passes.
@@ -219,7 +285,7 @@ func ClearRegistryCache(pass *analysis.Pass) {
 func ClearAllRegistryCaches() {
 	globalCacheMu.Lock()
 	defer globalCacheMu.Unlock()
-	globalCache = make(map[*analysis.Pass]*registryCache)
+	globalCache = make(map[cacheKey]*registryCache)
 }
 
 // GetCacheSize returns the number of cached registry entries.
@@ -291,42 +357,195 @@ func RunBasicTestAnalyzer(pass *analysis.Pass, settings *config.Settings) (inter
 	// Report untested resources with enhanced location information
 	untested := calculator.GetUntestedResources()
 	for _, resource := range untested {
-		resourceType := "resource"
-		resourceTypeTitle := "Resource"
-		if resource.Kind == registry.KindDataSource {
-			resourceType = "data source"
-			resourceTypeTitle = "Data source"
+		if resource.Directives.Exempts(registry.CheckBasic) {
+			continue
 		}
+		resourceType, resourceTypeTitle := kindLabels(settings.Language, resource.Kind)
 
 		// Build enhanced message with location details
 		pos := pass.Fset.Position(resource.SchemaPos)
 		expectedTestPath := BuildExpectedTestPath(resource)
-		expectedTestFunc := BuildExpectedTestFunc(resource)
 
 		// Enhanced message with suggestions
-		msg := fmt.Sprintf("%s '%s' has no acceptance test\n"+
-			"  %s: %s:%d\n"+
-			"  Expected test file: %s\n"+
-			"  Expected test function: %s\n"+
-			"  Suggestion: Create %s with function %s",
-			resourceType, resource.Name,
-			resourceTypeTitle, pos.Filename, pos.Line,
-			expectedTestPath, expectedTestFunc,
-			filepath.Base(expectedTestPath), expectedTestFunc)
+		msg := messages.Format(settings.Language, messages.BasicTestMissing, messages.Params{
+			"kind":         resourceType,
+			"kindTitle":    resourceTypeTitle,
+			"name":         resource.Name,
+			"file":         pos.Filename,
+			"line":         pos.Line,
+			"testFile":     expectedTestPath,
+			"testFileBase": filepath.Base(expectedTestPath),
+			"testFunc":     BuildExpectedTestFunc(resource),
+		})
+		if quarantined := reg.GetQuarantinedTests(resource.Kind, resource.Name); len(quarantined) > 0 {
+			var testNames []string
+			for _, fn := range quarantined {
+				testNames = append(testNames, fn.Name)
+			}
+			sort.Strings(testNames)
+			msg += "\n" + messages.Format(settings.Language, messages.BasicTestQuarantined, messages.Params{
+				"tests": strings.Join(testNames, ", "),
+			})
+		}
+
+		pass.Reportf(resource.SchemaPos, "%s", msg)
+	}
+
+	// Report tested definitions that fall short of the minimum test count
+	minTests := func(kind registry.ResourceKind) int {
+		return settings.MinTestsForKind(kind.String())
+	}
+	for _, coverage := range calculator.GetResourcesBelowMinTests(minTests) {
+		resource := coverage.Resource
+		if resource.Directives.Exempts(registry.CheckBasic) {
+			continue
+		}
+		resourceType, resourceTypeTitle := kindLabels(settings.Language, resource.Kind)
+		pos := pass.Fset.Position(resource.SchemaPos)
+		required := minTests(resource.Kind)
 
+		var testNames []string
+		for _, fn := range coverage.Tests {
+			testNames = append(testNames, fn.Name)
+		}
+		sort.Strings(testNames)
+
+		msg := messages.Format(settings.Language, messages.BasicTestBelowMinimum, messages.Params{
+			"kind":      resourceType,
+			"kindTitle": resourceTypeTitle,
+			"name":      resource.Name,
+			"count":     coverage.TestCount,
+			"required":  required,
+			"missing":   required - coverage.TestCount,
+			"file":      pos.Filename,
+			"line":      pos.Line,
+			"tests":     strings.Join(testNames, ", "),
+		})
 		pass.Reportf(resource.SchemaPos, "%s", msg)
 	}
 
+	reportLowConfidenceLinks(pass, reg, settings)
+	reportDirectiveErrors(pass, reg, settings)
+	exportCoverageFact(pass, reg)
+
 	return nil, nil
 }
 
+// kindLabels returns the localized lower-case and title-case labels for a definition kind,
+// the "kind.<key>" messages of the catalog. Kinds without labels, such as actions, are
+// reported as resources.
+func kindLabels(lang string, kind registry.ResourceKind) (string, string) {
+	id := messages.ID("kind." + kind.Key())
+	if !messages.Translated(messages.English, id) {
+		id = messages.KindResource
+	}
+	return messages.Format(lang, id, nil), messages.Format(lang, id+".title", nil)
+}
+
+// reportDirectiveErrors reports //tftest: directives that are malformed, name unknown
+// checks or options, or do not document a resource.
+func reportDirectiveErrors(pass *analysis.Pass, reg *registry.ResourceRegistry, settings *config.Settings) {
+	ids := map[registry.DirectiveProblem]messages.ID{
+		registry.DirectiveUnknown:       messages.DirectiveUnknown,
+		registry.DirectiveUnknownCheck:  messages.DirectiveUnknownCheck,
+		registry.DirectiveUnknownOption: messages.DirectiveUnknownOption,
+		registry.DirectiveMissingChecks: messages.DirectiveMissingChecks,
+		registry.DirectiveMalformed:     messages.DirectiveMalformed,
+		registry.DirectiveConflict:      messages.DirectiveConflict,
+		registry.DirectiveInapplicable:  messages.DirectiveInapplicable,
+		registry.DirectiveUnattached:    messages.DirectiveUnattached,
+		registry.DirectiveUnknownLevel:  messages.DirectiveUnknownLevel,
+	}
+
+	for _, directiveErr := range reg.GetDirectiveErrors() {
+		id, ok := ids[directiveErr.Problem]
+		if !ok || !directiveErr.Pos.IsValid() {
+			continue
+		}
+		hint := ""
+		if directiveErr.Suggestion != "" {
+			hint = messages.Format(settings.Language, messages.DidYouMean, messages.Params{
+				"suggestion": directiveErr.Suggestion,
+			})
+		}
+		kind, _ := kindLabels(settings.Language, directiveErr.TargetKind)
+		msg := messages.Format(settings.Language, id, messages.Params{
+			"directive": directiveErr.Directive,
+			"value":     directiveErr.Value,
+			"target":    directiveErr.Target,
+			"kind":      kind,
+			"hint":      hint,
+			"checks":    strings.Join(registry.DirectiveChecks(), ", "),
+			"levels":    strings.Join(registry.MaturityLevels(), ", "),
+		})
+		pass.Reportf(directiveErr.Pos, "%s", msg)
+	}
+}
+
+// reportExpectedCoverage reports a definition that lacks coverage a //tftest:expect directive
+// or the requirements manifest requires, for checks whose heuristics would not have flagged
+// it on their own.
+func reportExpectedCoverage(pass *analysis.Pass, settings *config.Settings, resource *registry.ResourceInfo, check string) {
+	resourceType, resourceTypeTitle := kindLabels(settings.Language, resource.Kind)
+	pos := pass.Fset.Position(resource.SchemaPos)
+	id := messages.CoverageExpected
+	if resource.Directives.FromManifest[check] {
+		id = messages.CoverageRequired
+	}
+	msg := messages.Format(settings.Language, id, messages.Params{
+		"kind":      resourceType,
+		"kindTitle": resourceTypeTitle,
+		"name":      resource.Name,
+		"check":     check,
+		"file":      pos.Filename,
+		"line":      pos.Line,
+		"manifest":  resource.Directives.ExpectedBy(check),
+	})
+	pass.Reportf(resource.SchemaPos, "%s", msg)
+}
+
+// reportLowConfidenceLinks emits an informational diagnostic on each test function whose
+// link to a resource falls below settings.LinkConfidenceWarningThreshold, so that
+// ambiguous tests can be renamed before they silently attach to the wrong resource.
+func reportLowConfidenceLinks(pass *analysis.Pass, reg *registry.ResourceRegistry, settings *config.Settings) {
+	threshold := settings.LinkConfidenceWarningThreshold
+	if threshold <= 0 {
+		return
+	}
+
+	for _, fn := range reg.GetAllTestFunctions() {
+		if fn.MatchType == registry.MatchTypeNone || fn.MatchedResource == "" || !fn.FunctionPos.IsValid() {
+			continue
+		}
+		if fn.MatchConfidence >= threshold {
+			continue
+		}
+
+		suggestion := messages.Format(settings.Language, messages.LinkSuggestRenameExplicit, nil)
+		if resource := reg.MatchedDefinition(fn); resource != nil {
+			suggestion = messages.Format(settings.Language, messages.LinkSuggestRenameTo, messages.Params{
+				"testFunc": BuildExpectedTestFunc(resource),
+			})
+		}
+
+		pass.Reportf(fn.FunctionPos, "%s", messages.Format(settings.Language, messages.LinkLowConfidence, messages.Params{
+			"test":       fn.Name,
+			"resource":   fn.MatchedResource,
+			"confidence": fmt.Sprintf("%.2f", fn.MatchConfidence),
+			"matchType":  fn.MatchType,
+			"suggestion": suggestion,
+		}))
+	}
+}
+
 func RunUpdateTestAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
 	reg := getOrBuildRegistry(pass, settings)
 
 	// Check for resources with updatable attributes but no update tests
 	// Only check regular resources (not data sources)
-	for name, resource := range reg.GetAllDefinitions() {
-		if resource.Kind != registry.KindResource {
+	for _, resource := range reg.GetSortedDefinitions() {
+		name := resource.Key().String()
+		if resource.Kind != registry.KindResource || resource.Directives.Exempts(registry.CheckUpdate) {
 			continue
 		}
 		// Check if resource has updatable attributes using isAttributeUpdatable
@@ -339,55 +558,44 @@ func RunUpdateTestAnalyzer(pass *analysis.Pass, settings *config.Settings) (inte
 			}
 		}
 
-		if !hasUpdatable {
+		// An SDK v2 resource without an Update function replaces itself on every change
+		if resource.Operations != nil && !resource.Operations.Update {
+			hasUpdatable = false
+		}
+
+		if !hasUpdatable && !resource.Directives.Expects(registry.CheckUpdate) {
 			// Resource doesn't need update tests
 			continue
 		}
 
 		// Get all test functions for this resource
-		testFunctions := reg.GetResourceTests(name)
+		testFunctions := reg.GetTests(resource.Kind, resource.Name)
 
 		// No tests at all - covered by BasicTestAnalyzer
 		if len(testFunctions) == 0 {
 			continue
 		}
 
-		// Check if any test function has a real update step
-		hasUpdateTest := false
-		for _, testFunc := range testFunctions {
-			for _, step := range testFunc.TestSteps {
-				// Use IsRealUpdateStep to properly distinguish real updates
-				// from "Apply -> Import" patterns
-				if step.IsRealUpdateStep() {
-					hasUpdateTest = true
-					break
-				}
-			}
-			// Fallback: if we have multiple config steps (excluding imports), consider it an update test
-			if !hasUpdateTest && len(testFunc.TestSteps) >= 2 {
-				configSteps := 0
-				for _, step := range testFunc.TestSteps {
-					if step.HasConfig && !step.ImportState {
-						configSteps++
-					}
-				}
-				if configSteps >= 2 {
-					hasUpdateTest = true
-				}
-			}
-			if hasUpdateTest {
-				break
-			}
+		hasUpdateTest := hasUpdateStep(testFunctions)
+
+		// Steps that could not be resolved may hold the update, so coverage is unknown
+		if !hasUpdateTest && stepsUnknown(testFunctions) {
+			continue
 		}
 
-		if !hasUpdateTest {
+		if !hasUpdateTest && !hasUpdatable {
+			reportExpectedCoverage(pass, settings, resource, registry.CheckUpdate)
+		} else if !hasUpdateTest {
 			pos := pass.Fset.Position(resource.SchemaPos)
-			msg := fmt.Sprintf("resource '%s' has updatable attributes but no update test coverage\n"+
-				"  Resource: %s:%d\n"+
-				"  Updatable attributes: %s\n"+
-				"  Suggestion: Add a test step that modifies one of these attributes",
-				name, pos.Filename, pos.Line,
-				strings.Join(updatableAttrs, ", "))
+			msg := messages.Format(settings.Language, messages.UpdateTestMissing, messages.Params{
+				"name":       name,
+				"file":       pos.Filename,
+				"line":       pos.Line,
+				"attributes": strings.Join(updatableAttrs, ", "),
+			})
+			if settings.Verbose {
+				msg += FormatUpdatePreview(BuildUpdatePreview(resource, testFunctions, discovery.CatalogConfigs(pass.Files, pass.Fset), settings))
+			}
 			pass.Reportf(resource.SchemaPos, "%s", msg)
 		}
 	}
@@ -395,6 +603,28 @@ func RunUpdateTestAnalyzer(pass *analysis.Pass, settings *config.Settings) (inte
 	return nil, nil
 }
 
+// hasUpdateStep reports whether any of the tests has a real update step, or failing that,
+// two config steps other than imports.
+func hasUpdateStep(tests []*registry.TestFunctionInfo) bool {
+	for _, testFunc := range tests {
+		configSteps := 0
+		for _, step := range testFunc.TestSteps {
+			// Use IsRealUpdateStep to properly distinguish real updates
+			// from "Apply -> Import" patterns
+			if step.IsRealUpdateStep() {
+				return true
+			}
+			if step.HasConfig && !step.ImportState {
+				configSteps++
+			}
+		}
+		if configSteps >= 2 {
+			return true
+		}
+	}
+	return false
+}
+
 // isAttributeUpdatable determines if an attribute needs an update test.
 // It returns false for:
 //   - Non-optional attributes (Computed-only attributes don't need update tests)
@@ -422,22 +652,34 @@ func IsAttributeUpdatable(attr registry.AttributeInfo) bool {
 	return isAttributeUpdatable(attr)
 }
 
+// stepsUnknown reports whether any of the tests builds steps that discovery could not resolve,
+// in which case a step-level pattern such as an import step is unknown rather than missing.
+func stepsUnknown(tests []*registry.TestFunctionInfo) bool {
+	for _, test := range tests {
+		if test.HasOpaqueSteps {
+			return true
+		}
+	}
+	return false
+}
+
 func RunImportTestAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
 	reg := getOrBuildRegistry(pass, settings)
 
 	// Check for resources with ImportState but no import tests
 	// Only check regular resources (not data sources)
-	for name, resource := range reg.GetAllDefinitions() {
-		if resource.Kind != registry.KindResource {
+	for _, resource := range reg.GetSortedDefinitions() {
+		name := resource.Key().String()
+		if resource.Kind != registry.KindResource || resource.Directives.Exempts(registry.CheckImport) {
 			continue
 		}
-		// Only check resources that implement ImportState
-		if !resource.HasImportState {
+		// Only check resources that implement ImportState, unless a directive expects import tests
+		if !resource.HasImportState && !resource.Directives.Expects(registry.CheckImport) {
 			continue
 		}
 
 		// Get all test functions for this resource
-		testFunctions := reg.GetResourceTests(name)
+		testFunctions := reg.GetTests(resource.Kind, resource.Name)
 		if len(testFunctions) == 0 {
 			// No tests at all - but this is covered by BasicTestAnalyzer
 			continue
@@ -452,13 +694,24 @@ func RunImportTestAnalyzer(pass *analysis.Pass, settings *config.Settings) (inte
 			}
 		}
 
-		if !hasImportTest {
+		if !hasImportTest && stepsUnknown(testFunctions) {
+			continue
+		}
+
+		if !hasImportTest && !resource.HasImportState {
+			reportExpectedCoverage(pass, settings, resource, registry.CheckImport)
+		} else if !hasImportTest {
 			pos := pass.Fset.Position(resource.SchemaPos)
-			msg := fmt.Sprintf("resource '%s' implements ImportState but has no import test coverage\n"+
-				"  Resource: %s:%d\n"+
-				"  Suggestion: Add a test step with ImportState: true, ImportStateVerify: true",
-				name, pos.Filename, pos.Line)
-			pass.Reportf(resource.SchemaPos, "%s", msg)
+			msg := messages.Format(settings.Language, messages.ImportTestMissing, messages.Params{
+				"name": name,
+				"file": pos.Filename,
+				"line": pos.Line,
+			})
+			diag := analysis.Diagnostic{Pos: resource.SchemaPos, Message: msg}
+			if fix, ok := importStepFix(pass, settings, resource, testFunctions); ok {
+				diag.SuggestedFixes = []analysis.SuggestedFix{fix}
+			}
+			pass.Report(diag)
 		}
 	}
 
@@ -469,8 +722,9 @@ func RunErrorTestAnalyzer(pass *analysis.Pass, settings *config.Settings) (inter
 	reg := getOrBuildRegistry(pass, settings)
 
 	// Check for resources with validation rules but no error tests
-	for name, resource := range reg.GetAllDefinitions() {
-		if resource.Kind != registry.KindResource {
+	for _, resource := range reg.GetSortedDefinitions() {
+		name := resource.Key().String()
+		if resource.Kind != registry.KindResource || resource.Directives.Exempts(registry.CheckError) {
 			continue
 		}
 		// Check if resource has validation rules
@@ -483,13 +737,13 @@ func RunErrorTestAnalyzer(pass *analysis.Pass, settings *config.Settings) (inter
 			}
 		}
 
-		if !hasValidation {
+		if !hasValidation && !resource.Directives.Expects(registry.CheckError) {
 			// Resource doesn't need error tests
 			continue
 		}
 
 		// Get all test functions for this resource
-		testFunctions := reg.GetResourceTests(name)
+		testFunctions := reg.GetTests(resource.Kind, resource.Name)
 		if len(testFunctions) == 0 {
 			// No tests at all - but this is covered by BasicTestAnalyzer
 			continue
@@ -504,14 +758,20 @@ func RunErrorTestAnalyzer(pass *analysis.Pass, settings *config.Settings) (inter
 			}
 		}
 
-		if !hasErrorTest {
+		if !hasErrorTest && stepsUnknown(testFunctions) {
+			continue
+		}
+
+		if !hasErrorTest && !hasValidation {
+			reportExpectedCoverage(pass, settings, resource, registry.CheckError)
+		} else if !hasErrorTest {
 			pos := pass.Fset.Position(resource.SchemaPos)
-			msg := fmt.Sprintf("resource '%s' has validation rules but no error case tests\n"+
-				"  Resource: %s:%d\n"+
-				"  Validated attributes: %s\n"+
-				"  Suggestion: Add a test step with ExpectError to verify validation",
-				name, pos.Filename, pos.Line,
-				strings.Join(validatedAttrs, ", "))
+			msg := messages.Format(settings.Language, messages.ErrorTestMissing, messages.Params{
+				"name":       name,
+				"file":       pos.Filename,
+				"line":       pos.Line,
+				"attributes": strings.Join(validatedAttrs, ", "),
+			})
 			pass.Reportf(resource.SchemaPos, "%s", msg)
 		}
 	}
@@ -519,59 +779,1769 @@ func RunErrorTestAnalyzer(pass *analysis.Pass, settings *config.Settings) (inter
 	return nil, nil
 }
 
-func RunStateCheckAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+// RunProviderConfigAnalyzer requires acceptance tests that exercise the provider's own
+// configuration. An attribute counts as exercised when a test sets it in a provider block
+// or sets an environment variable named after it (e.g., EXAMPLE_ENDPOINT for "endpoint").
+func RunProviderConfigAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
 	reg := getOrBuildRegistry(pass, settings)
-	calculator := NewCoverageCalculator(reg)
+	provider := reg.GetProvider()
+	if provider == nil {
+		return nil, nil
+	}
 
-	// Report at resource level - only flag resources missing ALL state/plan checks
-	for _, coverage := range calculator.GetResourcesMissingStateChecks() {
-		resourceType := "resource"
-		if coverage.Resource.Kind == registry.KindDataSource {
-			resourceType = "data source"
+	exercised := exercisedProviderAttributes(provider, reg.GetAllTestFunctions())
+	pos := pass.Fset.Position(provider.SchemaPos)
+	name := provider.TypeName
+	if name == "" {
+		name = provider.TypeNameHint
+	}
+
+	if len(settings.RequiredProviderAttributes) == 0 {
+		if len(provider.Attributes) == 0 || len(exercised) > 0 {
+			return nil, nil
+		}
+		var attrNames []string
+		for _, attr := range provider.Attributes {
+			attrNames = append(attrNames, attr.Name)
+		}
+		msg := messages.Format(settings.Language, messages.ProviderConfigUntested, messages.Params{
+			"provider":   name,
+			"file":       pos.Filename,
+			"line":       pos.Line,
+			"attributes": strings.Join(attrNames, ", "),
+		})
+		pass.Reportf(provider.SchemaPos, "%s", msg)
+		return nil, nil
+	}
+
+	for _, attr := range settings.RequiredProviderAttributes {
+		if !provider.HasAttribute(attr) {
+			pass.Reportf(provider.SchemaPos, "%s", messages.Format(settings.Language, messages.ProviderConfigUndefined, messages.Params{
+				"attribute": attr,
+				"provider":  name,
+			}))
+			continue
 		}
+		if exercised[attr] {
+			continue
+		}
+		msg := messages.Format(settings.Language, messages.ProviderConfigAttrUntested, messages.Params{
+			"attribute": attr,
+			"file":      pos.Filename,
+			"line":      pos.Line,
+			"envVar":    providerEnvVarHint(settings.Language, provider, attr),
+		})
+		pass.Reportf(provider.SchemaPos, "%s", msg)
+	}
 
-		msg := fmt.Sprintf("%s '%s' has %d test(s) but none include state validation (Check) or plan checks (ConfigPlanChecks)\n"+
-			"  Suggestion: Add Check: resource.ComposeTestCheckFunc(...) or ConfigPlanChecks to at least one test",
-			resourceType, coverage.Resource.Name, coverage.TestCount)
+	return nil, nil
+}
 
-		pass.Reportf(coverage.Resource.SchemaPos, "%s", msg)
+// exercisedProviderAttributes returns the provider attributes set by any test, either in a
+// provider block or through an environment variable whose name ends with the attribute name.
+func exercisedProviderAttributes(provider *registry.ProviderInfo, tests []*registry.TestFunctionInfo) map[string]bool {
+	exercised := make(map[string]bool)
+	for _, fn := range tests {
+		for _, attr := range fn.ProviderConfigAttributes {
+			if provider.HasAttribute(attr) {
+				exercised[attr] = true
+			}
+		}
+		for _, envVar := range fn.EnvVarsSet {
+			for _, attr := range provider.Attributes {
+				if envVarMatchesAttribute(envVar, attr.Name) {
+					exercised[attr.Name] = true
+				}
+			}
+		}
+	}
+	return exercised
+}
+
+// envVarMatchesAttribute reports whether an environment variable configures an attribute,
+// by convention PREFIX_ATTRIBUTE_NAME or ATTRIBUTE_NAME.
+func envVarMatchesAttribute(envVar, attr string) bool {
+	upperEnv := strings.ToUpper(envVar)
+	upperAttr := strings.ToUpper(attr)
+	return upperEnv == upperAttr || strings.HasSuffix(upperEnv, "_"+upperAttr)
+}
+
+// providerEnvVarHint names the environment variable Configure reads for an attribute, falling
+// back to a generic description when none is found.
+func providerEnvVarHint(lang string, provider *registry.ProviderInfo, attr string) string {
+	for _, envVar := range provider.EnvVars {
+		if envVarMatchesAttribute(envVar, attr) {
+			return envVar
+		}
+	}
+	return messages.Format(lang, messages.ProviderConfigEnvVarFallback, nil)
+}
+
+// RunRequirementsAnalyzer enforces the parts of the requirements manifest that no other
+// rule owns: it reports a manifest that cannot be loaded, checks on which the manifest and
+// a //tftest: directive disagree, definitions a strict manifest does not list, and tested
+// resources expected to have a disappears test that have none. Required update, import
+// and error coverage is reported by the rule for that check.
+func RunRequirementsAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	reg := getOrBuildRegistry(pass, settings)
+
+	for _, issue := range reg.GetRequirementIssues() {
+		kind, _ := kindLabels(settings.Language, issue.TargetKind)
+		params := messages.Params{
+			"kind":     kind,
+			"name":     issue.Target,
+			"manifest": issue.Manifest,
+			"check":    issue.Value,
+			"error":    issue.Value,
+		}
+		switch issue.Problem {
+		case registry.RequirementInvalid:
+			// The manifest is not Go source, so report it on the package
+			if len(pass.Files) > 0 {
+				pass.Reportf(pass.Files[0].Pos(), "%s", messages.Format(settings.Language, messages.RequirementsInvalid, params))
+			}
+		case registry.RequirementConflict:
+			pass.Reportf(issue.Pos, "%s", messages.Format(settings.Language, messages.RequirementsConflict, params))
+		case registry.RequirementUnlisted:
+			pass.Reportf(issue.Pos, "%s", messages.Format(settings.Language, messages.RequirementsUnlisted, params))
+		}
+	}
+
+	for _, resource := range reg.GetSortedDefinitions() {
+		if resource.Kind != registry.KindResource || !resource.Directives.Expects(registry.CheckDisappears) ||
+			resource.Directives.Exempts(registry.CheckDisappears) {
+			continue
+		}
+
+		tests := reg.GetTests(resource.Kind, resource.Name)
+		if len(tests) == 0 {
+			// No tests at all - covered by BasicTestAnalyzer
+			continue
+		}
+		hasDisappears := false
+		for _, fn := range tests {
+			if fn.ChecksDisappears {
+				hasDisappears = true
+				break
+			}
+		}
+		if hasDisappears {
+			continue
+		}
+
+		pos := pass.Fset.Position(resource.SchemaPos)
+		msg := messages.Format(settings.Language, messages.DisappearsTestMissing, messages.Params{
+			"name":     resource.Name,
+			"source":   resource.Directives.ExpectedBy(registry.CheckDisappears),
+			"count":    len(tests),
+			"file":     pos.Filename,
+			"line":     pos.Line,
+			"testFunc": strings.TrimSuffix(BuildExpectedTestFunc(resource), "_basic") + "_disappears",
+		})
+		pass.Reportf(resource.SchemaPos, "%s", msg)
 	}
 
 	return nil, nil
 }
 
-func RunDriftCheckAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+// RunImportStateIdFuncAnalyzer checks that the ImportStateIdFunc of each import step reads
+// only state attributes defined in the tested resource's schema. A misspelled key silently
+// yields an empty string, producing an import ID that fails in confusing ways.
+func RunImportStateIdFuncAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
 	reg := getOrBuildRegistry(pass, settings)
-	calculator := NewCoverageCalculator(reg)
 
-	// Report at resource level - only flag resources missing CheckDestroy
-	// Data sources are excluded as they don't create resources to destroy
-	for _, coverage := range calculator.GetResourcesMissingCheckDestroy() {
-		msg := fmt.Sprintf("resource '%s' has %d test(s) but none include CheckDestroy for drift detection\n"+
-			"  Suggestion: Add CheckDestroy: testAccCheckDestroy to at least one test's resource.TestCase",
-			coverage.Resource.Name, coverage.TestCount)
+	for _, resource := range reg.GetSortedDefinitions() {
+		if resource.Kind != registry.KindResource {
+			continue
+		}
+		tests := reg.GetTests(resource.Kind, resource.Name)
+		if len(tests) == 0 {
+			continue
+		}
 
-		pass.Reportf(coverage.Resource.SchemaPos, "%s", msg)
+		file := fileContaining(pass, resource.SchemaPos)
+		if file == nil {
+			continue
+		}
+		schemaNames, complete := discovery.SchemaFieldNames(file, resource.SchemaPos)
+		if !complete {
+			// The schema is assembled elsewhere; unknown attributes cannot be told apart from missing ones
+			continue
+		}
+		known := make([]string, 0, len(schemaNames))
+		for name := range schemaNames {
+			known = append(known, name)
+		}
+		sort.Strings(known)
+
+		for _, fn := range tests {
+			for _, step := range fn.TestSteps {
+				if !step.HasImportStateIdFunc || !step.ImportStateIdFuncPos.IsValid() {
+					continue
+				}
+				for _, key := range step.ImportStateIdAttributes {
+					attr := discovery.StateAttributeRoot(key)
+					if attr == "id" || schemaNames[attr] {
+						continue
+					}
+					reportUnknownImportStateAttr(pass, settings, resource, fn, step, attr, known)
+				}
+			}
+		}
 	}
 
 	return nil, nil
 }
 
-func RunSweeperAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
-	// Check if any file in the package has sweeper registrations
-	hasSweepers := false
-	for _, file := range pass.Files {
-		if discovery.CheckHasSweepers(file) {
-			hasSweepers = true
-			break
+// reportUnknownImportStateAttr reports an ImportStateIdFunc read of an attribute missing from
+// the resource schema, suggesting the closest defined attribute.
+func reportUnknownImportStateAttr(pass *analysis.Pass, settings *config.Settings, resource *registry.ResourceInfo, fn *registry.TestFunctionInfo, step registry.TestStepInfo, attr string, known []string) {
+	hint := ""
+	best, bestDistance := "", 3 // Suggest only within two edits
+	for _, name := range known {
+		if distance := matching.LevenshteinDistance(attr, name); distance < bestDistance {
+			best, bestDistance = name, distance
+		}
+	}
+	if best != "" {
+		hint = messages.Format(settings.Language, messages.DidYouMean, messages.Params{"suggestion": best})
+	}
+
+	function := step.ImportStateIdFunc
+	if function == "" {
+		function = "func literal"
+	}
+	pos := pass.Fset.Position(resource.SchemaPos)
+	msg := messages.Format(settings.Language, messages.ImportStateIdFuncUnknownAttr, messages.Params{
+		"function":  function,
+		"test":      fn.Name,
+		"attribute": attr,
+		"name":      resource.Name,
+		"hint":      hint,
+		"file":      pos.Filename,
+		"line":      pos.Line,
+	})
+	pass.Reportf(step.ImportStateIdFuncPos, "%s", msg)
+}
+
+// RunImportStateVerifyAnalyzer reports import steps that set ImportState but not
+// ImportStateVerify. Such steps only prove the import succeeds, not that the imported
+// state matches the resource, which gives false confidence. Steps that list
+// ImportStateVerifyIgnore attributes are treated as a deliberate choice and skipped, and an
+// ImportStateCheck that counts the imported instances or reads their attributes verifies
+// the import too; one that does neither is reported as such.
+func RunImportStateVerifyAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	reg := getOrBuildRegistry(pass, settings)
+
+	for _, fn := range reg.GetAllTestFunctions() {
+		for _, step := range fn.TestSteps {
+			if !step.ImportState || step.ImportStateVerify || step.HasImportStateVerifyIgnore || step.VerifiesImportWithCheck() {
+				continue
+			}
+			reportPos := step.ImportStatePos
+			if !reportPos.IsValid() {
+				reportPos = step.StepPos
+			}
+			if !reportPos.IsValid() {
+				reportPos = fn.FunctionPos
+			}
+			id := messages.ImportStateVerifyMissing
+			check := "the func literal"
+			if step.HasImportStateCheck {
+				id = messages.ImportStateCheckNoAssertions
+				if step.ImportStateCheck != "" {
+					check = step.ImportStateCheck
+				}
+			}
+			pos := pass.Fset.Position(reportPos)
+			msg := messages.Format(settings.Language, id, messages.Params{
+				"step":  step.StepNumber,
+				"test":  fn.Name,
+				"check": check,
+				"file":  pos.Filename,
+				"line":  pos.Line,
+			})
+			pass.Reportf(reportPos, "%s", msg)
+		}
+	}
+
+	return nil, nil
+}
+
+// RunParallelFixturesAnalyzer reports parallel acceptance tests whose configs hard-code the
+// same value for a name-like attribute, such as two tests creating the bucket "tf-test".
+// Every test in a clash set is reported, naming the others.
+func RunParallelFixturesAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	reg := getOrBuildRegistry(pass, settings)
+
+	patterns := settings.ParallelFixtureAttributes
+	if len(patterns) == 0 {
+		patterns = config.DefaultParallelFixtureAttributes()
+	}
+
+	users := make(map[registry.FixtureValue][]*registry.TestFunctionInfo)
+	for _, fn := range reg.GetAllTestFunctions() {
+		if !fn.UsesParallelTest {
+			continue
+		}
+		for _, value := range fn.FixtureValues {
+			if matchesAnyPattern(value.Attribute, patterns) {
+				users[value] = append(users[value], fn)
+			}
+		}
+	}
+
+	values := make([]registry.FixtureValue, 0, len(users))
+	for value, fns := range users {
+		if len(fns) > 1 {
+			values = append(values, value)
+		}
+	}
+	sort.Slice(values, func(i, j int) bool {
+		a, b := values[i], values[j]
+		if a.ResourceType != b.ResourceType {
+			return a.ResourceType < b.ResourceType
+		}
+		if a.Attribute != b.Attribute {
+			return a.Attribute < b.Attribute
+		}
+		return a.Value < b.Value
+	})
+
+	for _, value := range values {
+		fns := users[value]
+		for _, fn := range fns {
+			if !fn.FunctionPos.IsValid() {
+				continue
+			}
+			var others []string
+			for _, other := range fns {
+				if other != fn {
+					others = append(others, other.Name)
+				}
+			}
+			sort.Strings(others)
+
+			pos := pass.Fset.Position(fn.FunctionPos)
+			msg := messages.Format(settings.Language, messages.ParallelFixtureClash, messages.Params{
+				"test":         fn.Name,
+				"resourceType": value.ResourceType,
+				"attribute":    value.Attribute,
+				"value":        settings.RedactConfig(value.Value),
+				"others":       strings.Join(others, ", "),
+				"file":         pos.Filename,
+				"line":         pos.Line,
+			})
+			pass.Reportf(fn.FunctionPos, "%s", msg)
+		}
+	}
+
+	return nil, nil
+}
+
+// assumedTestParallelism is the go test -parallel value used to estimate the wall time
+// saved by running serial acceptance tests in parallel.
+const assumedTestParallelism = 4
+
+// RunParallelTestsAnalyzer reports acceptance tests that use resource.Test when their
+// resource's tests are independent: none sets environment variables (t.Setenv cannot be
+// combined with t.Parallel) or has a comment explaining why it runs serially. Each report
+// estimates the package's wall time with and without the serial tests, in test durations.
+func RunParallelTestsAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	reg := getOrBuildRegistry(pass, settings)
+
+	var candidates []*registry.TestFunctionInfo
+	seen := make(map[*registry.TestFunctionInfo]bool)
+	for _, resource := range reg.GetSortedDefinitions() {
+		tests := reg.GetTests(resource.Kind, resource.Name)
+		independent := true
+		for _, fn := range tests {
+			if fn.SerialReason != "" || len(fn.EnvVarsSet) > 0 {
+				independent = false
+				break
+			}
+		}
+		if !independent {
+			continue
+		}
+		for _, fn := range tests {
+			if !fn.UsesParallelTest && fn.FunctionPos.IsValid() && !seen[fn] {
+				seen[fn] = true
+				candidates = append(candidates, fn)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	total, serial := 0, 0
+	for _, fn := range reg.GetAllTestFunctions() {
+		total++
+		if !fn.UsesParallelTest {
+			serial++
 		}
 	}
+	// go test runs serial tests one after another, then the parallel ones in batches
+	before := serial + ceilDiv(total-serial, assumedTestParallelism)
+	after := (serial - len(candidates)) + ceilDiv(total-serial+len(candidates), assumedTestParallelism)
+	savings := 0
+	if before > 0 {
+		savings = (before - after) * 100 / before
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].FunctionPos < candidates[j].FunctionPos })
+	for _, fn := range candidates {
+		pos := pass.Fset.Position(fn.FunctionPos)
+		msg := messages.Format(settings.Language, messages.ParallelTestMissing, messages.Params{
+			"test":        fn.Name,
+			"file":        pos.Filename,
+			"line":        pos.Line,
+			"serial":      serial,
+			"total":       total,
+			"parallelism": assumedTestParallelism,
+			"before":      before,
+			"after":       after,
+			"savings":     savings,
+		})
+		pass.Reportf(fn.FunctionPos, "%s", msg)
+	}
+
+	return nil, nil
+}
+
+// RunTestPlacementAnalyzer reports tests linked to a definition by their function name or
+// config that live in the test file named for another definition, e.g. TestAccGadget_basic
+// in resource_widget_test.go. File names that do not name a definition are not checked.
+func RunTestPlacementAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	reg := getOrBuildRegistry(pass, settings)
+
+	type placement struct {
+		fn           *registry.TestFunctionInfo
+		def          *registry.ResourceInfo
+		fileResource string
+	}
+	var misplaced []placement
+	totals := make(map[string]int)
+	counts := make(map[string]int)
+	for _, fn := range reg.GetAllTestFunctions() {
+		totals[fn.FilePath]++
+		if fn.MatchType != registry.MatchTypeFunctionName && fn.MatchType != registry.MatchTypeInferred {
+			continue
+		}
+		fileResource, _ := discovery.ExtractResourceNameFromPath(fn.FilePath)
+		if fileResource == "" || fileResource == fn.MatchedResource || reg.GetResourceOrDataSource(fileResource) == nil {
+			continue
+		}
+		def := reg.MatchedDefinition(fn)
+		if def == nil || !fn.FunctionPos.IsValid() {
+			continue
+		}
+		misplaced = append(misplaced, placement{fn: fn, def: def, fileResource: fileResource})
+		counts[fn.FilePath]++
+	}
+
+	sort.Slice(misplaced, func(i, j int) bool { return misplaced[i].fn.FunctionPos < misplaced[j].fn.FunctionPos })
+	for _, m := range misplaced {
+		pos := pass.Fset.Position(m.fn.FunctionPos)
+		kind, _ := kindLabels(settings.Language, m.def.Kind)
+		msg := messages.Format(settings.Language, messages.TestMisplaced, messages.Params{
+			"test":         m.fn.Name,
+			"kind":         kind,
+			"name":         m.def.Name,
+			"fileResource": m.fileResource,
+			"testFile":     filepath.Base(m.fn.FilePath),
+			"file":         pos.Filename,
+			"line":         pos.Line,
+			"misplaced":    counts[m.fn.FilePath],
+			"total":        totals[m.fn.FilePath],
+			"expected":     BuildExpectedTestPath(m.def),
+		})
+		pass.Reportf(m.fn.FunctionPos, "%s", msg)
+	}
+
+	return nil, nil
+}
+
+// RunOrphanTestsAnalyzer reports each acceptance test linked to no definition at its own
+// function, so orphans can be cleaned up in the editor rather than from -show-unmatched.
+// The report lists the resource types the test's configs declare and suggests the definition
+// closest to the test's name or those types, when one is similar enough.
+func RunOrphanTestsAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	reg := getOrBuildRegistry(pass, settings)
+	definitions := reg.GetAllDefinitions()
 
+	orphans := reg.GetUnmatchedTestFunctions()
+	sort.Slice(orphans, func(i, j int) bool { return orphans[i].FunctionPos < orphans[j].FunctionPos })
+	for _, fn := range orphans {
+		if !fn.UsesResourceTest || !fn.FunctionPos.IsValid() {
+			continue
+		}
+
+		inferred := ""
+		if blocks := inferredBlockNames(fn); len(blocks) > 0 {
+			inferred = messages.Format(settings.Language, messages.OrphanTestInferred, messages.Params{
+				"resources": strings.Join(blocks, ", "),
+			})
+		}
+
+		suggestion := messages.Format(settings.Language, messages.OrphanTestNoCandidate, nil)
+		if closest, similarity := closestDefinition(fn, definitions, settings); closest != nil {
+			kind, _ := kindLabels(settings.Language, closest.Kind)
+			suggestion = messages.Format(settings.Language, messages.OrphanTestClosest, messages.Params{
+				"kind":         kind,
+				"name":         closest.Name,
+				"similarity":   fmt.Sprintf("%.2f", similarity),
+				"expectedFunc": BuildExpectedTestFunc(closest),
+				"expectedFile": BuildExpectedTestPath(closest),
+			})
+		}
+
+		pos := pass.Fset.Position(fn.FunctionPos)
+		msg := messages.Format(settings.Language, messages.OrphanTest, messages.Params{
+			"test":       fn.Name,
+			"file":       pos.Filename,
+			"line":       pos.Line,
+			"inferred":   inferred,
+			"suggestion": suggestion,
+		})
+		pass.Reportf(fn.FunctionPos, "%s", msg)
+	}
+
+	return nil, nil
+}
+
+// closestDefinition returns the definition whose name is most similar to the resource named
+// by the test function or to a resource type its configs declare (with or without the
+// provider prefix), provided the similarity reaches explainCandidateFloor. Ties go to the
+// name that sorts first, then to resources over data sources.
+func closestDefinition(fn *registry.TestFunctionInfo, definitions map[string]*registry.ResourceInfo, settings *config.Settings) (*registry.ResourceInfo, float64) {
+	var names []string
+	pipeline, _ := settings.NamePipeline()
+	if extracted, ok := matching.ExtractResourceFromFuncNameWith(pipeline, fn.Name); ok {
+		names = append(names, extracted)
+	}
+	for _, typeName := range inferredTypeNames(fn) {
+		names = append(names, typeName)
+		if idx := strings.Index(typeName, "_"); idx != -1 {
+			names = append(names, typeName[idx+1:])
+		}
+	}
+
+	var best *registry.ResourceInfo
+	bestSimilarity := 0.0
+	for _, def := range definitions {
+		for _, name := range names {
+			similarity := matching.CalculateSimilarity(name, def.Name)
+			tie := similarity == bestSimilarity && best != nil &&
+				(def.Name < best.Name || (def.Name == best.Name && def.Kind < best.Kind))
+			if similarity > bestSimilarity || tie {
+				best, bestSimilarity = def, similarity
+			}
+		}
+	}
+	if bestSimilarity < explainCandidateFloor {
+		return nil, 0
+	}
+	return best, bestSimilarity
+}
+
+// inferredBlockNames returns the HCL blocks a test's configs declare as "resource.example_widget",
+// or the resource types inferred from its helpers when no blocks were found.
+func inferredBlockNames(fn *registry.TestFunctionInfo) []string {
+	if len(fn.InferredHCLBlocks) == 0 {
+		return fn.InferredResources
+	}
+	names := make([]string, 0, len(fn.InferredHCLBlocks))
+	for _, block := range fn.InferredHCLBlocks {
+		names = append(names, block.BlockType+"."+block.ResourceType)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// inferredTypeNames returns the resource types a test's configs declare.
+func inferredTypeNames(fn *registry.TestFunctionInfo) []string {
+	names := append([]string(nil), fn.InferredResources...)
+	for _, block := range fn.InferredHCLBlocks {
+		names = append(names, block.ResourceType)
+	}
+	return names
+}
+
+// RunDefaultValuesAnalyzer reports resource attributes declared with a Default or DefaultFunc
+// when every test step whose config declares the resource sets the attribute, so the default
+// is never applied by a test. Only steps whose config resolves statically count; an attribute
+// is not reported when some step's config cannot be resolved, as it may omit the attribute.
+// The rule is informational: defaults are often trivial, so it is opt-in.
+func RunDefaultValuesAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	reg := getOrBuildRegistry(pass, settings)
+
+	for _, resource := range reg.GetSortedDefinitions() {
+		if resource.Kind != registry.KindResource {
+			continue
+		}
+		tests := reg.GetTests(resource.Kind, resource.Name)
+		if len(tests) == 0 || stepsUnknown(tests) {
+			continue
+		}
+
+		for _, attr := range resource.Attributes {
+			if !attr.HasDefault || attr.Required {
+				continue
+			}
+			steps, resourceType, exercised := defaultCoverage(tests, resource.Name, attr.Name)
+			if exercised || steps == 0 {
+				continue
+			}
+
+			reportPos := attr.DefaultPos
+			if !reportPos.IsValid() {
+				reportPos = resource.SchemaPos
+			}
+			pos := pass.Fset.Position(reportPos)
+			msg := messages.Format(settings.Language, messages.DefaultValueUntested, messages.Params{
+				"attribute":    attr.Name,
+				"resource":     resource.Name,
+				"resourceType": resourceType,
+				"steps":        steps,
+				"file":         pos.Filename,
+				"line":         pos.Line,
+			})
+			pass.Reportf(reportPos, "%s", msg)
+		}
+	}
+
+	return nil, nil
+}
+
+// defaultCoverage counts the config steps of tests that declare the resource named name (with
+// or without the provider prefix) and reports whether any of them leaves attribute unset or
+// cannot be resolved, which is treated as exercising the default. resourceType is the type
+// the configs declare, for the message.
+func defaultCoverage(tests []*registry.TestFunctionInfo, name, attribute string) (steps int, resourceType string, exercised bool) {
+	for _, test := range tests {
+		for _, step := range test.TestSteps {
+			if !step.HasConfig {
+				continue
+			}
+			if step.ConfigAttributes == nil {
+				return steps, resourceType, true
+			}
+			for typeName, attributes := range step.ConfigAttributes {
+				if !sameDefinitionName(typeName, name) {
+					continue
+				}
+				steps++
+				resourceType = typeName
+				// ConfigAttributes lists are sorted
+				if i := sort.SearchStrings(attributes, attribute); i == len(attributes) || attributes[i] != attribute {
+					return steps, resourceType, true
+				}
+			}
+		}
+	}
+	return steps, resourceType, false
+}
+
+// ceilDiv returns n / d rounded up.
+func ceilDiv(n, d int) int {
+	return (n + d - 1) / d
+}
+
+// matchesAnyPattern reports whether name equals or glob-matches any of the patterns.
+func matchesAnyPattern(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if pattern == name {
+			return true
+		}
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// fileContaining returns the file of the pass that contains pos.
+func fileContaining(pass *analysis.Pass, pos token.Pos) *ast.File {
+	for _, file := range pass.Files {
+		if pos >= file.Pos() && pos <= file.End() {
+			return file
+		}
+	}
+	return nil
+}
+
+// genericErrorWords are words found in nearly every diagnostic, so an ExpectError pattern
+// made of one of them accepts almost any failure.
+var genericErrorWords = map[string]bool{
+	"error": true, "errors": true, "err": true, "error:": true,
+	"fail": true, "failed": true, "failure": true,
+	"invalid": true, "diag": true, "diagnostic": true,
+}
+
+// matchAllProbes are unrelated messages that only a pattern matching any text matches together.
+var matchAllProbes = []string{"", "x", "Error: unexpected EOF"}
+
+// RunExpectErrorAnalyzer reports ExpectError regular expressions that do not compile, since
+// regexp.MustCompile then panics when the test runs, and patterns too broad to tell the
+// expected error from any other, which let a test pass when it fails for the wrong reason.
+// Only patterns given as string literals are checked.
+func RunExpectErrorAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	reg := getOrBuildRegistry(pass, settings)
+
+	for _, fn := range reg.GetAllTestFunctions() {
+		for _, step := range fn.TestSteps {
+			if !step.ExpectError || !step.HasExpectErrorPattern {
+				continue
+			}
+			reportPos := step.ExpectErrorPos
+			if !reportPos.IsValid() {
+				reportPos = fn.FunctionPos
+			}
+			pos := pass.Fset.Position(reportPos)
+			params := messages.Params{
+				"step":    step.StepNumber,
+				"test":    fn.Name,
+				"pattern": step.ExpectErrorPattern,
+				"file":    pos.Filename,
+				"line":    pos.Line,
+			}
+
+			re, err := regexp.Compile(step.ExpectErrorPattern)
+			if err != nil {
+				params["error"] = err.Error()
+				pass.Reportf(reportPos, "%s", messages.Format(settings.Language, messages.ExpectErrorInvalid, params))
+				continue
+			}
+			if matchesAnyText(re) {
+				pass.Reportf(reportPos, "%s", messages.Format(settings.Language, messages.ExpectErrorMatchesAll, params))
+				continue
+			}
+			if word := genericErrorWord(step.ExpectErrorPattern); word != "" {
+				params["word"] = word
+				pass.Reportf(reportPos, "%s", messages.Format(settings.Language, messages.ExpectErrorGeneric, params))
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// matchesAnyText reports whether a compiled pattern matches every probe message.
+func matchesAnyText(re *regexp.Regexp) bool {
+	for _, probe := range matchAllProbes {
+		if !re.MatchString(probe) {
+			return false
+		}
+	}
+	return true
+}
+
+// genericErrorWord returns the generic word a pattern consists of once flags, anchors and
+// surrounding wildcards are removed (e.g., "(?i)^.*error.*$" yields "error"), or "".
+func genericErrorWord(pattern string) string {
+	word := pattern
+	for _, flags := range []string{"(?i)", "(?s)", "(?is)", "(?si)"} {
+		word = strings.TrimPrefix(word, flags)
+	}
+	word = strings.TrimPrefix(word, "^")
+	word = strings.TrimSuffix(word, "$")
+	for _, wildcard := range []string{".*", ".+"} {
+		word = strings.TrimPrefix(word, wildcard)
+		word = strings.TrimSuffix(word, wildcard)
+	}
+	word = strings.ToLower(strings.TrimSpace(word))
+	if genericErrorWords[word] {
+		return word
+	}
+	return ""
+}
+
+// RunDeferredActionsAnalyzer reports where a provider advertises deferred actions, by setting
+// resp.Deferred in a resource's ModifyPlan or in the provider's Configure, when no acceptance
+// test in the package allows deferral through AdditionalCLIOptions. Packages without tests are
+// left to the basic-test rule.
+func RunDeferredActionsAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	reg := getOrBuildRegistry(pass, settings)
+	tests := reg.GetAllTestFunctions()
+	if len(tests) == 0 {
+		return nil, nil
+	}
+	for _, fn := range tests {
+		if fn.ExercisesDeferral() {
+			return nil, nil
+		}
+	}
+
+	if provider := reg.GetProvider(); provider != nil && provider.DeferredPos.IsValid() {
+		name := provider.TypeName
+		if name == "" {
+			name = provider.TypeNameHint
+		}
+		pos := pass.Fset.Position(provider.DeferredPos)
+		pass.Reportf(provider.DeferredPos, "%s", messages.Format(settings.Language, messages.DeferredProviderUntested, messages.Params{
+			"provider": name,
+			"file":     pos.Filename,
+			"line":     pos.Line,
+		}))
+	}
+
+	for _, resource := range reg.GetSortedDefinitions() {
+		if resource.Kind != registry.KindResource || !resource.DeferredPos.IsValid() {
+			continue
+		}
+		pos := pass.Fset.Position(resource.DeferredPos)
+		pass.Reportf(resource.DeferredPos, "%s", messages.Format(settings.Language, messages.DeferredActionsUntested, messages.Params{
+			"name": resource.Name,
+			"file": pos.Filename,
+			"line": pos.Line,
+		}))
+	}
+
+	return nil, nil
+}
+
+// RunProviderAliasAnalyzer reports resources that span provider instances, because they are
+// listed in provider-alias-resources or have a peer_* attribute, when none of their tests
+// configures an aliased provider block. Resources without tests are left to the basic-test
+// rule.
+func RunProviderAliasAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	reg := getOrBuildRegistry(pass, settings)
+
+	provider := "<provider>"
+	if info := reg.GetProvider(); info != nil && info.TypeName != "" {
+		provider = info.TypeName
+	}
+
+	for _, resource := range reg.GetSortedDefinitions() {
+		if resource.Kind != registry.KindResource {
+			continue
+		}
+		reason := providerAliasReason(resource, settings.ProviderAliasResources)
+		if reason == "" {
+			continue
+		}
+		tests := reg.GetTests(resource.Kind, resource.Name)
+		if len(tests) == 0 || stepsUnknown(tests) || configuresProviderAlias(tests) {
+			continue
+		}
+
+		pos := pass.Fset.Position(resource.SchemaPos)
+		pass.Reportf(resource.SchemaPos, "%s", messages.Format(settings.Language, messages.ProviderAliasTestMissing, messages.Params{
+			"name":     resource.Name,
+			"reason":   reason,
+			"tests":    len(tests),
+			"provider": provider,
+			"file":     pos.Filename,
+			"line":     pos.Line,
+		}))
+	}
+
+	return nil, nil
+}
+
+// providerAliasReason returns why a resource needs a test with an aliased provider, or "" when
+// it does not.
+func providerAliasReason(resource *registry.ResourceInfo, patterns []string) string {
+	if matchesAnyPattern(resource.Name, patterns) {
+		return "listed in provider-alias-resources"
+	}
+	for _, attr := range resource.Attributes {
+		if strings.HasPrefix(attr.Name, "peer_") {
+			return "attribute " + attr.Name
+		}
+	}
+	return ""
+}
+
+// configuresProviderAlias reports whether any of the tests configures an aliased provider.
+func configuresProviderAlias(tests []*registry.TestFunctionInfo) bool {
+	for _, test := range tests {
+		if len(test.ProviderAliases) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// RunDeadTestsAnalyzer reports commented-out blocks in test files that contain an acceptance
+// test function or a resource.Test call. Blocks shorter than dead-test-min-lines are ignored
+// so that short examples in comments are not reported.
+func RunDeadTestsAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	minLines := settings.DeadTestMinLines
+	if minLines <= 0 {
+		minLines = config.DefaultDeadTestMinLines
+	}
+	classifier := discovery.FileClassifier(*settings)
+	scope := scopedFiles(getOrBuildRegistry(pass, settings), settings)
+
+	for _, file := range pass.Files {
+		filename := pass.Fset.Position(file.Pos()).Filename
+		if !strings.HasSuffix(filename, "_test.go") || discovery.ExclusionReason(*settings, classifier, filename) != "" || !inScope(scope, filename) {
+			continue
+		}
+		for _, dead := range discovery.FindCommentedOutTests(file, pass.Fset, minLines) {
+			pos := pass.Fset.Position(dead.Pos)
+			params := messages.Params{
+				"lines": dead.Lines,
+				"file":  pos.Filename,
+				"line":  pos.Line,
+			}
+			id := messages.DeadTestCommentedOutCall
+			if len(dead.Names) > 0 {
+				id = messages.DeadTestCommentedOut
+				params["tests"] = "'" + strings.Join(dead.Names, "', '") + "'"
+			}
+			pass.Reportf(dead.Pos, "%s", messages.Format(settings.Language, id, params))
+		}
+	}
+
+	return nil, nil
+}
+
+// RunStateCheckAnalyzer reports tested definitions none of whose tests validates state or
+// plans and, with fail-on-weak-tests, each linked test that asserts nothing.
+func RunStateCheckAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	reg := getOrBuildRegistry(pass, settings)
+	calculator := NewCoverageCalculator(reg)
+
+	// Report at resource level - only flag resources missing ALL state/plan checks
+	for _, coverage := range calculator.GetResourcesMissingStateChecks() {
+		if coverage.Resource.Directives.Exempts(registry.CheckStateCheck) {
+			continue
+		}
+		resourceType, _ := kindLabels(settings.Language, coverage.Resource.Kind)
+
+		msg := messages.Format(settings.Language, messages.StateCheckMissing, messages.Params{
+			"kind":  resourceType,
+			"name":  coverage.Resource.Name,
+			"count": coverage.TestCount,
+		})
+
+		pass.Reportf(coverage.Resource.SchemaPos, "%s", msg)
+	}
+
+	if settings.FailOnWeakTests {
+		reportWeakTests(pass, settings, reg)
+	}
+
+	return nil, nil
+}
+
+// reportWeakTests reports the linked tests that apply configs without asserting anything, at
+// the test function.
+func reportWeakTests(pass *analysis.Pass, settings *config.Settings, reg *registry.ResourceRegistry) {
+	for _, resource := range reg.GetSortedDefinitions() {
+		if resource.Directives.Exempts(registry.CheckStateCheck) {
+			continue
+		}
+		kind, _ := kindLabels(settings.Language, resource.Kind)
+		for _, fn := range reg.GetTests(resource.Kind, resource.Name) {
+			if !fn.IsWeak() || !fn.FunctionPos.IsValid() {
+				continue
+			}
+			steps := 0
+			for _, step := range fn.TestSteps {
+				if step.HasConfig {
+					steps++
+				}
+			}
+			testPos := pass.Fset.Position(fn.FunctionPos)
+			pass.Reportf(fn.FunctionPos, "%s", messages.Format(settings.Language, messages.StateCheckWeakTest, messages.Params{
+				"test":     fn.Name,
+				"kind":     kind,
+				"name":     resource.Name,
+				"steps":    steps,
+				"testFile": testPos.Filename,
+				"testLine": testPos.Line,
+			}))
+		}
+	}
+}
+
+// RunDriftCheckAnalyzer reports tested resources none of whose tests sets CheckDestroy. With
+// check-destroy-requires-delete, resources that cannot be destroyed are skipped and their
+// tests that set CheckDestroy are reported instead.
+func RunDriftCheckAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	reg := getOrBuildRegistry(pass, settings)
+	calculator := NewCoverageCalculator(reg)
+
+	// Report at resource level - only flag resources missing CheckDestroy
+	// Data sources are excluded as they don't create resources to destroy
+	for _, coverage := range calculator.GetResourcesMissingCheckDestroy() {
+		if coverage.Resource.Directives.Exempts(registry.CheckDrift) {
+			continue
+		}
+		if settings.CheckDestroyRequiresDelete && !destroyable(coverage.Resource) {
+			continue
+		}
+		msg := messages.Format(settings.Language, messages.DriftCheckMissing, messages.Params{
+			"name":  coverage.Resource.Name,
+			"count": coverage.TestCount,
+		})
+
+		pass.Reportf(coverage.Resource.SchemaPos, "%s", msg)
+	}
+
+	if settings.CheckDestroyRequiresDelete {
+		reportNeedlessCheckDestroy(pass, settings, reg)
+	}
+
+	return nil, nil
+}
+
+// destroyable reports whether a resource may destroy something when deleted, which is
+// assumed when discovery cannot tell.
+func destroyable(resource *registry.ResourceInfo) bool {
+	capability := resource.DeleteCapability()
+	return capability != registry.DeleteMissing && capability != registry.DeleteNoOp
+}
+
+// reportNeedlessCheckDestroy reports the tests that set CheckDestroy on resources that
+// cannot be destroyed.
+func reportNeedlessCheckDestroy(pass *analysis.Pass, settings *config.Settings, reg *registry.ResourceRegistry) {
+	for _, resource := range reg.GetSortedDefinitions() {
+		var reason messages.ID
+		switch resource.DeleteCapability() {
+		case registry.DeleteMissing:
+			reason = messages.DriftCheckReasonNoDelete
+		case registry.DeleteNoOp:
+			reason = messages.DriftCheckReasonNoOpDelete
+		default:
+			continue
+		}
+		if resource.Directives.Exempts(registry.CheckDrift) {
+			continue
+		}
+		pos := pass.Fset.Position(resource.SchemaPos)
+		for _, fn := range reg.GetTests(resource.Kind, resource.Name) {
+			if !fn.HasCheckDestroy || !fn.FunctionPos.IsValid() {
+				continue
+			}
+			testPos := pass.Fset.Position(fn.FunctionPos)
+			msg := messages.Format(settings.Language, messages.DriftCheckNotDestroyable, messages.Params{
+				"test":     fn.Name,
+				"name":     resource.Name,
+				"reason":   messages.Format(settings.Language, reason, nil),
+				"testFile": testPos.Filename,
+				"testLine": testPos.Line,
+				"file":     pos.Filename,
+				"line":     pos.Line,
+			})
+			pass.Reportf(fn.FunctionPos, "%s", msg)
+		}
+	}
+}
+
+// RunProviderHygieneAnalyzer checks the entrypoint of each test package (directory) of the
+// pass. Sweepers registered with resource.AddTestSweepers only run when TestMain hands over
+// to resource.TestMain(m), which parses -sweep, so a package registering sweepers without
+// such a TestMain is reported at the first registration, and one whose TestMain does not
+// dispatch is reported at TestMain. A TestMain in a package with acceptance tests that
+// neither dispatches nor calls m.Run() is reported too, as it silently skips every test.
+func RunProviderHygieneAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	reg := getOrBuildRegistry(pass, settings)
+
+	type testPackage struct {
+		testMain   *discovery.TestMainInfo
+		sweepers   []token.Pos
+		acceptance int
+	}
+	packages := make(map[string]*testPackage)
+	pkg := func(dir string) *testPackage {
+		if packages[dir] == nil {
+			packages[dir] = &testPackage{}
+		}
+		return packages[dir]
+	}
+	for _, file := range pass.Files {
+		dir := filepath.Dir(pass.Fset.Position(file.Pos()).Filename)
+		if registrations := discovery.FindSweeperRegistrations(file); len(registrations) > 0 {
+			pkg(dir).sweepers = append(pkg(dir).sweepers, registrations...)
+		}
+		if testMain := discovery.FindTestMain(file); testMain != nil {
+			pkg(dir).testMain = testMain
+		}
+	}
+	for _, fn := range reg.GetAllTestFunctions() {
+		if fn.UsesResourceTest {
+			pkg(filepath.Dir(fn.FilePath)).acceptance++
+		}
+	}
+
+	dirs := make([]string, 0, len(packages))
+	for dir := range packages {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		p := packages[dir]
+		if len(settings.Resources) > 0 && p.acceptance == 0 {
+			// No test of a definition in scope
+			continue
+		}
+		var id messages.ID
+		var reportPos token.Pos
+		switch {
+		case len(p.sweepers) > 0 && p.testMain == nil:
+			id, reportPos = messages.ProviderHygieneNoTestMain, p.sweepers[0]
+		case len(p.sweepers) > 0 && !p.testMain.Dispatches:
+			id, reportPos = messages.ProviderHygieneNoDispatch, p.testMain.Pos
+		case p.acceptance > 0 && p.testMain != nil && !p.testMain.RunsTests:
+			id, reportPos = messages.ProviderHygieneTestsNotRun, p.testMain.Pos
+		default:
+			continue
+		}
+		pos := pass.Fset.Position(reportPos)
+		msg := messages.Format(settings.Language, id, messages.Params{
+			"package":    dir,
+			"sweepers":   len(p.sweepers),
+			"acceptance": p.acceptance,
+			"file":       pos.Filename,
+			"line":       pos.Line,
+		})
+		pass.Reportf(reportPos, "%s", msg)
+	}
+
+	return nil, nil
+}
+
+// RunUnknownTypesAnalyzer reports each resource, data source or action block of a test config
+// whose type carries the provider's prefix but names no discovered definition, e.g. a typo
+// such as example_widgit, at the test function. Types of other providers (random_id) are not
+// checked. The prefix is the provider's type name or, when Metadata does not set it, the
+// prefix most configs use for types that do resolve; without either nothing is reported.
+func RunUnknownTypesAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	reg := getOrBuildRegistry(pass, settings)
+	tests := reg.GetAllTestFunctions()
+	sort.Slice(tests, func(i, j int) bool { return tests[i].FunctionPos < tests[j].FunctionPos })
+
+	prefix := providerTypePrefix(reg, tests)
+	if prefix == "" {
+		return nil, nil
+	}
+	definitions := reg.GetAllDefinitions()
+
+	for _, fn := range tests {
+		if !fn.UsesResourceTest || !fn.FunctionPos.IsValid() {
+			continue
+		}
+		// Inferred blocks come in no particular order; sort them so diagnostics are stable
+		blocks := append([]registry.InferredHCLBlock(nil), fn.InferredHCLBlocks...)
+		sort.Slice(blocks, func(i, j int) bool {
+			if blocks[i].ResourceType != blocks[j].ResourceType {
+				return blocks[i].ResourceType < blocks[j].ResourceType
+			}
+			return blocks[i].BlockType < blocks[j].BlockType
+		})
+		seen := make(map[registry.InferredHCLBlock]bool)
+		for _, block := range blocks {
+			kind, ok := registry.KindFromBlockType(block.BlockType)
+			if !ok || seen[block] || !strings.HasPrefix(block.ResourceType, prefix+"_") || resolveBlockType(reg, kind, block.ResourceType) != nil {
+				continue
+			}
+			seen[block] = true
+
+			suggestion := messages.Format(settings.Language, messages.UnknownTypeNoCandidate, nil)
+			if closest := closestTypeName(block.ResourceType, prefix, kind, definitions); closest != "" {
+				suggestion = messages.Format(settings.Language, messages.UnknownTypeClosest, messages.Params{"name": closest})
+			}
+			pos := pass.Fset.Position(fn.FunctionPos)
+			msg := messages.Format(settings.Language, messages.UnknownType, messages.Params{
+				"test":       fn.Name,
+				"block":      block.BlockType,
+				"type":       block.ResourceType,
+				"file":       pos.Filename,
+				"line":       pos.Line,
+				"suggestion": suggestion,
+			})
+			pass.Reportf(fn.FunctionPos, "%s", msg)
+		}
+	}
+
+	return nil, nil
+}
+
+// resolveBlockType returns the definition an HCL block type names, with or without the
+// provider prefix, or nil.
+func resolveBlockType(reg *registry.ResourceRegistry, kind registry.ResourceKind, typeName string) *registry.ResourceInfo {
+	if def := reg.GetDefinition(kind, typeName); def != nil {
+		return def
+	}
+	if idx := strings.Index(typeName, "_"); idx != -1 {
+		return reg.GetDefinition(kind, typeName[idx+1:])
+	}
+	return nil
+}
+
+// providerTypePrefix returns the provider's type name, or else the prefix that the test
+// configs use most often for block types resolving to a definition. Ties go to the prefix
+// that sorts first.
+func providerTypePrefix(reg *registry.ResourceRegistry, tests []*registry.TestFunctionInfo) string {
+	if provider := reg.GetProvider(); provider != nil && provider.TypeName != "" {
+		return provider.TypeName
+	}
+	counts := make(map[string]int)
+	for _, fn := range tests {
+		for _, block := range fn.InferredHCLBlocks {
+			kind, ok := registry.KindFromBlockType(block.BlockType)
+			idx := strings.Index(block.ResourceType, "_")
+			if !ok || idx == -1 || resolveBlockType(reg, kind, block.ResourceType) == nil {
+				continue
+			}
+			counts[block.ResourceType[:idx]]++
+		}
+	}
+	best := ""
+	for prefix, count := range counts {
+		if count > counts[best] || (count == counts[best] && prefix < best) {
+			best = prefix
+		}
+	}
+	return best
+}
+
+// closestTypeName returns the type name, with the provider prefix, of the definition of the
+// given kind most similar to typeName, provided the similarity reaches explainCandidateFloor.
+func closestTypeName(typeName, prefix string, kind registry.ResourceKind, definitions map[string]*registry.ResourceInfo) string {
+	short := strings.TrimPrefix(typeName, prefix+"_")
+	best, bestSimilarity := "", 0.0
+	for _, def := range definitions {
+		if def.Kind != kind {
+			continue
+		}
+		name := strings.TrimPrefix(def.Name, prefix+"_")
+		similarity := matching.CalculateSimilarity(short, name)
+		if similarity > bestSimilarity || (similarity == bestSimilarity && best != "" && prefix+"_"+name < best) {
+			best, bestSimilarity = prefix+"_"+name, similarity
+		}
+	}
+	if bestSimilarity < explainCandidateFloor {
+		return ""
+	}
+	return best
+}
+
+// RunTestHelpersAnalyzer reports misuses of *testing.T in local test helpers: t.Fatal and
+// its variants called from a goroutine, and setup errors discarded with the blank
+// identifier. Each is reported at the call, with the number of test functions in the pass
+// that call the helper, since a helper bug weakens all of them.
+func RunTestHelpersAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	classifier := discovery.FileClassifier(*settings)
+	scope := scopedFiles(getOrBuildRegistry(pass, settings), settings)
+
+	var testFiles []*ast.File
+	for _, file := range pass.Files {
+		filename := pass.Fset.Position(file.Pos()).Filename
+		if strings.HasSuffix(filename, "_test.go") && discovery.ExclusionReason(*settings, classifier, filename) == "" && inScope(scope, filename) {
+			testFiles = append(testFiles, file)
+		}
+	}
+
+	callers := make(map[string]map[string]bool)
+	for _, file := range testFiles {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Body == nil || !strings.HasPrefix(funcDecl.Name.Name, "Test") {
+				continue
+			}
+			ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+				if call, ok := n.(*ast.CallExpr); ok {
+					if ident, ok := call.Fun.(*ast.Ident); ok {
+						if callers[ident.Name] == nil {
+							callers[ident.Name] = make(map[string]bool)
+						}
+						callers[ident.Name][funcDecl.Name.Name] = true
+					}
+				}
+				return true
+			})
+		}
+	}
+
+	for _, file := range testFiles {
+		for _, misuse := range discovery.FindHelperMisuse(file) {
+			id := messages.TestHelperIgnoredError
+			if misuse.Kind == discovery.HelperFatalInGoroutine {
+				id = messages.TestHelperFatalInGoroutine
+			}
+			pos := pass.Fset.Position(misuse.Pos)
+			pass.Reportf(misuse.Pos, "%s", messages.Format(settings.Language, id, messages.Params{
+				"helper":  misuse.Helper,
+				"call":    misuse.Call,
+				"callers": len(callers[misuse.Helper]),
+				"file":    pos.Filename,
+				"line":    pos.Line,
+			}))
+		}
+	}
+
+	return nil, nil
+}
+
+// RunDataSourceConfigAnalyzer reports the tests of a data source whose config declares no
+// managed resource for the data source to read: a resource of the same type when the
+// provider defines one (data "example_widget" reads resource "example_widget"), or else
+// any resource. Such tests read infrastructure that must already exist, which CI cannot
+// reproduce. Tests whose configs were not resolved statically are skipped, and read-only or
+// global data sources opt out with //tftest:exempt managed-config.
+func RunDataSourceConfigAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	reg := getOrBuildRegistry(pass, settings)
+	prefix := providerTypePrefix(reg, reg.GetAllTestFunctions())
+
+	for _, ds := range reg.GetSortedDefinitions() {
+		if ds.Kind != registry.KindDataSource || ds.Directives.Exempts(registry.CheckManagedConfig) {
+			continue
+		}
+		counterpart := reg.GetDefinition(registry.KindResource, ds.Name)
+		tests := reg.GetTests(registry.KindDataSource, ds.Name)
+		sort.Slice(tests, func(i, j int) bool { return tests[i].FunctionPos < tests[j].FunctionPos })
+
+		for _, fn := range tests {
+			if !fn.UsesResourceTest || fn.HasOpaqueSteps || len(fn.InferredHCLBlocks) == 0 || !fn.FunctionPos.IsValid() {
+				continue
+			}
+			managed := false
+			for _, block := range fn.InferredHCLBlocks {
+				if kind, ok := registry.KindFromBlockType(block.BlockType); ok && kind == registry.KindResource &&
+					(counterpart == nil || resolveBlockType(reg, registry.KindResource, block.ResourceType) == counterpart) {
+					managed = true
+					break
+				}
+			}
+			if managed {
+				continue
+			}
+
+			pos := pass.Fset.Position(fn.FunctionPos)
+			params := messages.Params{
+				"test": fn.Name,
+				"name": ds.Name,
+				"file": pos.Filename,
+				"line": pos.Line,
+			}
+			id := messages.DataSourceConfigNoManaged
+			if counterpart != nil {
+				id = messages.DataSourceConfigNoResource
+				params["type"] = counterpart.Name
+				if prefix != "" && !strings.HasPrefix(counterpart.Name, prefix+"_") {
+					params["type"] = prefix + "_" + counterpart.Name
+				}
+			}
+			pass.Reportf(fn.FunctionPos, "%s", messages.Format(settings.Language, id, params))
+		}
+	}
+
+	return nil, nil
+}
+
+// RunDataSourceAssertsAnalyzer reports data sources whose tests between them assert fewer of
+// the data source's computed attributes besides id than data-source-min-computed-asserts
+// (at most as many as it has), in Check or ConfigStateChecks. Tests asserting only id pass
+// whenever the lookup finds something, whatever it returns. Data sources without computed
+// attributes or tests, and those with checks not resolved statically, are skipped; the
+// state-check exemption opts a data source out.
+func RunDataSourceAssertsAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	reg := getOrBuildRegistry(pass, settings)
+	minimum := settings.DataSourceMinComputedAsserts
+	if minimum == 0 {
+		minimum = config.DefaultDataSourceMinComputedAsserts
+	}
+
+	for _, ds := range reg.GetSortedDefinitions() {
+		if ds.Kind != registry.KindDataSource || ds.Directives.Exempts(registry.CheckStateCheck) || !ds.SchemaPos.IsValid() {
+			continue
+		}
+		computed := make(map[string]bool)
+		for _, attr := range ds.Attributes {
+			if attr.Computed && !attr.Optional && !attr.Required && attr.Name != "id" {
+				computed[attr.Name] = true
+			}
+		}
+		tests := reg.GetTests(registry.KindDataSource, ds.Name)
+		if len(computed) == 0 || len(tests) == 0 || stepsUnknown(tests) {
+			continue
+		}
+
+		asserted := make(map[string]bool)
+		unresolved := false
+		for _, fn := range tests {
+			for _, step := range fn.TestSteps {
+				unresolved = unresolved || step.UnresolvedChecks
+				for _, check := range append(append([]registry.CheckAddress(nil), step.CheckAddresses...), step.StateCheckAddresses...) {
+					address, ok := discovery.CheckedResource(check.Address)
+					if !ok || check.Function == "TestCheckNoResourceAttr" {
+						continue
+					}
+					parts := strings.Split(address, ".")
+					if parts[0] != "data" || resolveBlockType(reg, registry.KindDataSource, parts[1]) != ds {
+						continue
+					}
+					if name := check.TopLevelAttribute(); computed[name] {
+						asserted[name] = true
+					}
+				}
+			}
+		}
+		required := min(minimum, len(computed))
+		if unresolved || len(asserted) >= required {
+			continue
+		}
+
+		var unasserted []string
+		for name := range computed {
+			if !asserted[name] {
+				unasserted = append(unasserted, name)
+			}
+		}
+		sort.Strings(unasserted)
+		pos := pass.Fset.Position(ds.SchemaPos)
+		pass.Reportf(ds.SchemaPos, "%s", messages.Format(settings.Language, messages.DataSourceAssertsTooFew, messages.Params{
+			"name":       ds.Name,
+			"asserted":   len(asserted),
+			"computed":   len(computed),
+			"required":   required,
+			"tests":      len(tests),
+			"unasserted": strings.Join(unasserted, ", "),
+			"file":       pos.Filename,
+			"line":       pos.Line,
+		}))
+	}
+
+	return nil, nil
+}
+
+// RunCheckAddressesAnalyzer reports TestCheckResourceAttr-style checks whose resource
+// address the step's config does not declare. Such a check fails with "Not found", or
+// checks a resource left over from another config, instead of the one the step applies.
+// Steps whose config is not resolved statically, and addresses built at run time (e.g.,
+// with fmt.Sprintf) or of module resources, are skipped.
+func RunCheckAddressesAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	reg := getOrBuildRegistry(pass, settings)
+
+	tests := reg.GetAllTestFunctions()
+	sort.Slice(tests, func(i, j int) bool { return tests[i].FunctionPos < tests[j].FunctionPos })
+	for _, fn := range tests {
+		for _, step := range fn.TestSteps {
+			if step.ConfigAddresses == nil {
+				continue
+			}
+			for _, check := range step.CheckAddresses {
+				address, ok := discovery.CheckedResource(check.Address)
+				if !ok || discovery.ConfigDeclares(step.ConfigAddresses, address) {
+					continue
+				}
+
+				pos := pass.Fset.Position(check.Pos)
+				params := messages.Params{
+					"function": check.Function,
+					"address":  check.Address,
+					"test":     fn.Name,
+					"step":     step.StepNumber,
+					"declared": declaredAddresses(step.ConfigAddresses),
+					"file":     pos.Filename,
+					"line":     pos.Line,
+				}
+				id := messages.CheckAddressUndeclared
+				if similar := similarAddress(step.ConfigAddresses, address); similar != "" {
+					id = messages.CheckAddressSimilar
+					params["similar"] = similar
+				}
+				pass.Reportf(check.Pos, "%s", messages.Format(settings.Language, id, params))
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// declaredAddresses lists a step's config addresses for a message, showing parts not known
+// statically as discovery.DynamicPlaceholder.
+func declaredAddresses(addresses []string) string {
+	if len(addresses) == 0 {
+		return "none"
+	}
+	shown := make([]string, len(addresses))
+	for i, address := range addresses {
+		shown[i] = strings.ReplaceAll(address, "\x00", discovery.DynamicPlaceholder)
+	}
+	return strings.Join(shown, ", ")
+}
+
+// similarAddress returns the first declared address of the same type as address, which is
+// likely the one the check meant, or "" if there is none.
+func similarAddress(declared []string, address string) string {
+	resourceType := address[:strings.LastIndex(address, ".")]
+	for _, candidate := range declared {
+		if strings.HasPrefix(candidate, resourceType+".") && strings.Count(candidate, ".") == strings.Count(address, ".") {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// RunRefreshDriftAnalyzer reports tested resources none of whose tests has a RefreshState
+// step. A refresh step reads the remote object into the state of the previous step, so it is
+// the step that exercises drift detection; update steps always apply a config first. Resources
+// with steps that could not be resolved statically are skipped, as those steps may refresh.
+// Steps that set RefreshPlanChecks without RefreshState are reported too, since their checks
+// never run.
+func RunRefreshDriftAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	reg := getOrBuildRegistry(pass, settings)
+	calculator := NewCoverageCalculator(reg)
+
+	for _, coverage := range calculator.GetResourcesMissingRefreshStep() {
+		if coverage.Resource.Directives.Exempts(registry.CheckRefresh) {
+			continue
+		}
+		msg := messages.Format(settings.Language, messages.RefreshDriftMissing, messages.Params{
+			"name":  coverage.Resource.Name,
+			"count": coverage.TestCount,
+		})
+		pass.Reportf(coverage.Resource.SchemaPos, "%s", msg)
+	}
+
+	tests := reg.GetAllTestFunctions()
+	sort.Slice(tests, func(i, j int) bool { return tests[i].FunctionPos < tests[j].FunctionPos })
+	for _, fn := range tests {
+		for _, step := range fn.TestSteps {
+			if !step.HasRefreshPlanCheck || step.RefreshState || !step.StepPos.IsValid() {
+				continue
+			}
+			pos := pass.Fset.Position(step.StepPos)
+			msg := messages.Format(settings.Language, messages.RefreshPlanChecksIgnored, messages.Params{
+				"test": fn.Name,
+				"step": step.StepNumber,
+				"file": pos.Filename,
+				"line": pos.Line,
+			})
+			pass.Reportf(step.StepPos, "%s", msg)
+		}
+	}
+
+	return nil, nil
+}
+
+// RunIdempotencyAnalyzer reports tested resources with Optional+Computed attributes none of
+// whose tests applies the same config twice and expects an empty plan, with PlanOnly or a
+// plancheck.ExpectEmptyPlan plan check. When the config leaves such an attribute unset the
+// provider fills it in, and a Read that normalizes it differently shows up as a diff on every
+// plan. Resources with steps that could not be resolved statically are skipped.
+func RunIdempotencyAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	reg := getOrBuildRegistry(pass, settings)
+	calculator := NewCoverageCalculator(reg)
+
+	for _, coverage := range calculator.GetResourcesMissingIdempotencyStep() {
+		if coverage.Resource.Directives.Exempts(registry.CheckIdempotency) {
+			continue
+		}
+		msg := messages.Format(settings.Language, messages.IdempotencyMissing, messages.Params{
+			"name":       coverage.Resource.Name,
+			"count":      coverage.TestCount,
+			"attributes": strings.Join(PerpetualDiffAttributes(coverage.Resource), ", "),
+		})
+		pass.Reportf(coverage.Resource.SchemaPos, "%s", msg)
+	}
+	return nil, nil
+}
+
+// RunEnvPreCheckAnalyzer reports environment variables interpolated into test configs that
+// the test's PreCheck never checks. Unset, the variable renders as an empty string in the
+// config, and the test fails at apply with a provider error instead of being skipped.
+func RunEnvPreCheckAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	reg := getOrBuildRegistry(pass, settings)
+
+	tests := reg.GetAllTestFunctions()
+	sort.Slice(tests, func(i, j int) bool { return tests[i].FunctionPos < tests[j].FunctionPos })
+	for _, fn := range tests {
+		for _, use := range fn.ConfigEnvVars {
+			if !use.Pos.IsValid() || !uncheckedEnvVar(fn, use.Name) {
+				continue
+			}
+			pos := pass.Fset.Position(use.Pos)
+			msg := messages.Format(settings.Language, messages.EnvPreCheckMissing, messages.Params{
+				"test": fn.Name,
+				"env":  use.Name,
+				"file": pos.Filename,
+				"line": pos.Line,
+			})
+			pass.Reportf(use.Pos, "%s", msg)
+		}
+	}
+	return nil, nil
+}
+
+// RunDeprecatedAttributesAnalyzer reports tests that set a deprecated resource attribute when
+// no test sets the attribute replacing it, so the replacement would ship untested once the
+// deprecated attribute is removed. Replacements are the other attributes of the schema that the
+// deprecation message names. Only statically resolved configs count: a resource is skipped when
+// some step's config cannot be resolved, or declares more than one block of the resource, as the
+// replacement may be set where it cannot be seen.
+func RunDeprecatedAttributesAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	reg := getOrBuildRegistry(pass, settings)
+
+	for _, resource := range reg.GetSortedDefinitions() {
+		if resource.Kind != registry.KindResource {
+			continue
+		}
+		tests := reg.GetTests(resource.Kind, resource.Name)
+		if len(tests) == 0 || stepsUnknown(tests) {
+			continue
+		}
+		sort.Slice(tests, func(i, j int) bool { return tests[i].FunctionPos < tests[j].FunctionPos })
+
+		for _, attr := range resource.Attributes {
+			if !attr.Deprecated {
+				continue
+			}
+			replacements := deprecationReplacements(resource, attr)
+			if len(replacements) == 0 {
+				continue
+			}
+			uses, covered := deprecatedAttributeUses(tests, resource.Name, attr.Name, replacements)
+			if covered {
+				continue
+			}
+			deprecation := attr.DeprecationMessage
+			if deprecation == "" {
+				deprecation = "-"
+			}
+			for _, use := range uses {
+				pos := pass.Fset.Position(use.pos)
+				msg := messages.Format(settings.Language, messages.DeprecatedReplacementMissing, messages.Params{
+					"test":         use.test.Name,
+					"attribute":    attr.Name,
+					"resource":     resource.Name,
+					"replacements": quotedList(replacements),
+					"deprecation":  deprecation,
+					"file":         pos.Filename,
+					"line":         pos.Line,
+				})
+				pass.Reportf(use.pos, "%s", msg)
+			}
+		}
+	}
+	return nil, nil
+}
+
+// deprecationIdentifier matches the words of a deprecation message that may name an attribute.
+var deprecationIdentifier = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+// deprecationReplacements returns the attributes of a resource, other than attr and other
+// deprecated ones, that attr's deprecation message names, in message order.
+func deprecationReplacements(resource *registry.ResourceInfo, attr registry.AttributeInfo) []string {
+	candidates := make(map[string]bool)
+	for _, other := range resource.Attributes {
+		if other.Name != attr.Name && !other.Deprecated {
+			candidates[other.Name] = true
+		}
+	}
+	var replacements []string
+	for _, word := range deprecationIdentifier.FindAllString(attr.DeprecationMessage, -1) {
+		if candidates[word] {
+			replacements = append(replacements, word)
+			delete(candidates, word)
+		}
+	}
+	return replacements
+}
+
+// deprecatedAttributeUse is the first step of a test whose config sets a deprecated attribute.
+type deprecatedAttributeUse struct {
+	test *registry.TestFunctionInfo
+	pos  token.Pos
+}
+
+// deprecatedAttributeUses returns, per test, the first config step setting attribute on the
+// resource named name. covered is true when some config sets one of the replacements, or may
+// set one without it being seen.
+func deprecatedAttributeUses(tests []*registry.TestFunctionInfo, name, attribute string, replacements []string) (uses []deprecatedAttributeUse, covered bool) {
+	for _, test := range tests {
+		used := false
+		for _, step := range test.TestSteps {
+			if !step.HasConfig {
+				continue
+			}
+			if step.ConfigAttributes == nil {
+				return nil, true
+			}
+			for typeName, attributes := range step.ConfigAttributes {
+				if !sameDefinitionName(typeName, name) {
+					continue
+				}
+				// ConfigAttributes only lists attributes every block of the type sets
+				if blockCount(step.ConfigAddresses, typeName) > 1 {
+					return nil, true
+				}
+				for _, replacement := range replacements {
+					if containsSorted(attributes, replacement) {
+						return nil, true
+					}
+				}
+				if !used && containsSorted(attributes, attribute) {
+					used = true
+					pos := step.ConfigPos
+					if !pos.IsValid() {
+						pos = test.FunctionPos
+					}
+					uses = append(uses, deprecatedAttributeUse{test: test, pos: pos})
+				}
+			}
+		}
+	}
+	return uses, false
+}
+
+// blockCount returns how many resource blocks of resourceType a config declares.
+func blockCount(addresses []string, resourceType string) int {
+	count := 0
+	for _, address := range addresses {
+		if strings.HasPrefix(address, resourceType+".") {
+			count++
+		}
+	}
+	return count
+}
+
+// containsSorted reports whether a sorted list contains s.
+func containsSorted(list []string, s string) bool {
+	i := sort.SearchStrings(list, s)
+	return i < len(list) && list[i] == s
+}
+
+// quotedList formats names as 'a', 'b' or 'c'.
+func quotedList(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = "'" + name + "'"
+	}
+	switch len(quoted) {
+	case 1:
+		return quoted[0]
+	default:
+		return strings.Join(quoted[:len(quoted)-1], ", ") + " or " + quoted[len(quoted)-1]
+	}
+}
+
+func RunSweeperAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	// Check if any file in the package has sweeper registrations
+	hasSweepers := false
+	for _, file := range pass.Files {
+		if discovery.CheckHasSweepers(file) {
+			hasSweepers = true
+			break
+		}
+	}
+
+	if scope := scopedFiles(getOrBuildRegistry(pass, settings), settings); scope != nil && len(scope) == 0 {
+		// Nothing in the package is in scope of the resources setting
+		return nil, nil
+	}
 	if !hasSweepers {
 		// Report at package level (first file position)
 		if len(pass.Files) > 0 {
-			pass.Reportf(pass.Files[0].Pos(), "package has no test sweeper registrations\n"+
-				"  Suggestion: Add resource.AddTestSweepers() calls for cleanup")
+			pass.Reportf(pass.Files[0].Pos(), "%s", messages.Format(settings.Language, messages.SweepersMissing, nil))
 		}
 	}
 
@@ -0,0 +1,84 @@
+package analysis
+
+import (
+	"go/token"
+	"sort"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// TestCandidates lists the definitions the linker matched one test function to.
+type TestCandidates struct {
+	Test string `json:"test"`
+	File string `json:"file"`
+	Line int    `json:"line,omitempty"`
+	// Linked is the definition the test is linked to, e.g. "resource:widget", if any
+	Linked     string           `json:"linked,omitempty"`
+	Candidates []CandidateMatch `json:"candidates"`
+}
+
+// CandidateMatch is one linker strategy's match for a test.
+type CandidateMatch struct {
+	Kind       string  `json:"kind"`
+	Name       string  `json:"name"`
+	Strategy   string  `json:"strategy"`
+	MatchType  string  `json:"match_type"`
+	Confidence float64 `json:"confidence"`
+	Accepted   bool    `json:"accepted"`
+	Note       string  `json:"note,omitempty"`
+}
+
+// NearMisses returns the tests with a candidate definition they were not linked to, ordered by
+// file and test name. Candidates that agree with the linked definition are included with
+// them, but do not make a test a near miss on their own.
+func NearMisses(reg *registry.ResourceRegistry, fset *token.FileSet) []TestCandidates {
+	var result []TestCandidates
+	for _, fn := range reg.GetAllTestFunctions() {
+		candidates := reg.GetMatchCandidates(fn)
+		var linked registry.ResourceKey
+		for _, c := range candidates {
+			if c.Accepted {
+				linked = c.Key
+			}
+		}
+		nearMiss := false
+		for _, c := range candidates {
+			nearMiss = nearMiss || (!c.Accepted && c.Key != linked)
+		}
+		if !nearMiss {
+			continue
+		}
+		result = append(result, MatchCandidates(reg, fset, fn))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].File != result[j].File {
+			return result[i].File < result[j].File
+		}
+		return result[i].Test < result[j].Test
+	})
+	return result
+}
+
+// MatchCandidates returns every definition the linker matched one test function to, whether
+// or not the test was linked to it.
+func MatchCandidates(reg *registry.ResourceRegistry, fset *token.FileSet, fn *registry.TestFunctionInfo) TestCandidates {
+	entry := TestCandidates{Test: fn.Name, File: fn.FilePath, Candidates: []CandidateMatch{}}
+	if fset != nil && fn.FunctionPos.IsValid() {
+		entry.Line = fset.Position(fn.FunctionPos).Line
+	}
+	for _, c := range reg.GetMatchCandidates(fn) {
+		if c.Accepted {
+			entry.Linked = c.Key.String()
+		}
+		entry.Candidates = append(entry.Candidates, CandidateMatch{
+			Kind:       c.Key.Kind.String(),
+			Name:       c.Key.Name,
+			Strategy:   c.Strategy,
+			MatchType:  c.MatchType.String(),
+			Confidence: c.Confidence,
+			Accepted:   c.Accepted,
+			Note:       c.Note,
+		})
+	}
+	return entry
+}
@@ -0,0 +1,139 @@
+package analysis
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// Complexity summarizes the schema of a definition: how many attributes and blocks it has,
+// how deeply they nest and how much behavior they carry. Nested attributes and blocks are
+// counted along with the top-level ones.
+type Complexity struct {
+	Attributes    int `json:"attributes"`
+	Blocks        int `json:"blocks"`
+	MaxDepth      int `json:"max_depth"` // 1 for a flat schema, 0 for an empty one
+	Required      int `json:"required"`
+	Optional      int `json:"optional"`
+	Computed      int `json:"computed"`
+	Validators    int `json:"validators"`     // Attributes with validators
+	PlanModifiers int `json:"plan_modifiers"` // Attributes with plan modifiers
+	Defaults      int `json:"defaults"`       // Attributes with defaults
+	// Kinds counts attributes and blocks by schema type, such as StringAttribute or
+	// ListNestedBlock for the framework and TypeString for SDK v2
+	Kinds map[string]int `json:"kinds,omitempty"`
+	Score int            `json:"score"`
+}
+
+// Each attribute or block scores one point, plus one per level it is nested below the top
+// and one for each of these it carries. Computed-only attributes have no extra weight, as
+// there is little for a test to exercise beyond reading them.
+const (
+	scoreRequired      = 1
+	scoreValidators    = 1
+	scorePlanModifiers = 1
+)
+
+// ResourceComplexity computes the schema complexity of a definition.
+func ResourceComplexity(info *registry.ResourceInfo) Complexity {
+	c := Complexity{Kinds: make(map[string]int)}
+	c.add(info.Attributes, 1, false)
+	c.add(info.Blocks, 1, true)
+	if len(c.Kinds) == 0 {
+		c.Kinds = nil
+	}
+	return c
+}
+
+// add counts attrs, found at depth, and everything nested in them.
+func (c *Complexity) add(attrs []registry.AttributeInfo, depth int, blocks bool) {
+	for _, attr := range attrs {
+		if depth > c.MaxDepth {
+			c.MaxDepth = depth
+		}
+		if blocks || isBlockKind(attr.Kind) {
+			c.Blocks++
+		} else {
+			c.Attributes++
+		}
+		c.Score += depth // one point, plus one per level below the top
+		if attr.Required {
+			c.Required++
+			c.Score += scoreRequired
+		}
+		if attr.Optional {
+			c.Optional++
+		}
+		if attr.Computed {
+			c.Computed++
+		}
+		if attr.HasValidators {
+			c.Validators++
+			c.Score += scoreValidators
+		}
+		if attr.HasPlanModifiers {
+			c.PlanModifiers++
+			c.Score += scorePlanModifiers
+		}
+		if attr.HasDefault {
+			c.Defaults++
+		}
+		switch {
+		case attr.Kind != "":
+			c.Kinds[attr.Kind]++
+		case attr.Type != "":
+			c.Kinds[attr.Type]++
+		}
+		c.add(attr.Nested, depth+1, false)
+	}
+}
+
+// isBlockKind reports whether a framework schema type is a block.
+func isBlockKind(kind string) bool {
+	return strings.HasSuffix(kind, "Block")
+}
+
+// RiskEntry is a definition ranked by how much untested schema it carries.
+type RiskEntry struct {
+	Kind       string     `json:"kind"`
+	Name       string     `json:"name"`
+	Complexity Complexity `json:"complexity"`
+}
+
+// TopRisks returns the untested definitions with a non-empty schema, most complex first,
+// at most limit of them (all when limit is 0). A complex definition without tests is where a
+// regression is most likely to go unnoticed.
+func TopRisks(reg *registry.ResourceRegistry, limit int) []RiskEntry {
+	return TopRisksOf(reg, limit, reg.GetSortedDefinitions())
+}
+
+// TopRisksOf is TopRisks limited to the given definitions, such as those of one category.
+func TopRisksOf(reg *registry.ResourceRegistry, limit int, groups ...[]*registry.ResourceInfo) []RiskEntry {
+	var risks []RiskEntry
+	for _, defs := range groups {
+		for _, info := range defs {
+			if len(reg.GetTests(info.Kind, info.Name)) > 0 {
+				continue
+			}
+			complexity := ResourceComplexity(info)
+			if complexity.Score == 0 {
+				continue
+			}
+			risks = append(risks, RiskEntry{Kind: info.Kind.String(), Name: info.Name, Complexity: complexity})
+		}
+	}
+	sort.Slice(risks, func(i, j int) bool {
+		if risks[i].Complexity.Score != risks[j].Complexity.Score {
+			return risks[i].Complexity.Score > risks[j].Complexity.Score
+		}
+		if risks[i].Kind != risks[j].Kind {
+			return risks[i].Kind < risks[j].Kind
+		}
+		return risks[i].Name < risks[j].Name
+	})
+	if limit > 0 && len(risks) > limit {
+		risks = risks[:limit]
+	}
+	return risks
+}
@@ -26,17 +26,17 @@ func (c *CoverageCalculator) GetResourceCoverage(resourceName string) *registry.
 		return nil
 	}
 
-	tests := c.registry.GetResourceTests(resourceName)
+	tests := c.registry.GetTests(resource.Kind, resource.Name)
 	return c.computeCoverage(resource, tests)
 }
 
 // GetAllResourceCoverage returns coverage information for all resources and data sources.
 func (c *CoverageCalculator) GetAllResourceCoverage() []*registry.ResourceCoverage {
-	definitions := c.registry.GetAllDefinitions()
+	definitions := c.registry.GetSortedDefinitions()
 
 	var coverages []*registry.ResourceCoverage
-	for name, resource := range definitions {
-		tests := c.registry.GetResourceTests(name)
+	for _, resource := range definitions {
+		tests := c.registry.GetTests(resource.Kind, resource.Name)
 		coverage := c.computeCoverage(resource, tests)
 		coverages = append(coverages, coverage)
 	}
@@ -65,6 +65,12 @@ func (c *CoverageCalculator) computeCoverage(resource *registry.ResourceInfo, te
 		if test.HasErrorCase {
 			coverage.HasErrorTest = true
 		}
+		if test.ChecksDisappears {
+			coverage.HasDisappearsTest = true
+		}
+		if test.HasOpaqueSteps {
+			coverage.HasOpaqueSteps = true
+		}
 
 		for _, step := range test.TestSteps {
 			coverage.StepCount++
@@ -82,6 +88,12 @@ func (c *CoverageCalculator) computeCoverage(resource *registry.ResourceInfo, te
 				coverage.HasUpdateTest = true
 				coverage.UpdateStepCount++
 			}
+			if step.IsRefreshStep() {
+				coverage.HasRefreshTest = true
+			}
+			if step.IsIdempotencyStep() {
+				coverage.HasIdempotencyStep = true
+			}
 		}
 	}
 
@@ -90,30 +102,86 @@ func (c *CoverageCalculator) computeCoverage(resource *registry.ResourceInfo, te
 
 // GetUntestedResources returns all resources and data sources that lack test coverage.
 func (c *CoverageCalculator) GetUntestedResources() []*registry.ResourceInfo {
-	definitions := c.registry.GetAllDefinitions()
+	definitions := c.registry.GetSortedDefinitions()
 
 	var untested []*registry.ResourceInfo
-	for name, info := range definitions {
-		if len(c.registry.GetResourceTests(name)) == 0 {
+	for _, info := range definitions {
+		if len(c.registry.GetTests(info.Kind, info.Name)) == 0 {
 			untested = append(untested, info)
 		}
 	}
 	return untested
 }
 
+// GetResourcesBelowMinTests returns definitions that have at least one test but fewer than
+// the minimum returned by minTests for their kind. Untested definitions are not included;
+// see GetUntestedResources.
+func (c *CoverageCalculator) GetResourcesBelowMinTests(minTests func(registry.ResourceKind) int) []*registry.ResourceCoverage {
+	var below []*registry.ResourceCoverage
+	for _, cov := range c.GetAllResourceCoverage() {
+		if cov.TestCount > 0 && cov.TestCount < minTests(cov.Resource.Kind) {
+			below = append(below, cov)
+		}
+	}
+	return below
+}
+
 // GetResourcesMissingStateChecks returns resources that have tests but no state/plan checks.
 func (c *CoverageCalculator) GetResourcesMissingStateChecks() []*registry.ResourceCoverage {
 	coverages := c.GetAllResourceCoverage()
 	var missing []*registry.ResourceCoverage
 	for _, cov := range coverages {
-		// Only report resources that have tests but lack validation
-		if cov.HasBasicTest && !cov.HasStateCheck && !cov.HasPlanCheck {
+		// Only report resources that have tests but lack validation; unresolved steps may hold it
+		if cov.HasBasicTest && !cov.HasStateCheck && !cov.HasPlanCheck && !cov.HasOpaqueSteps {
 			missing = append(missing, cov)
 		}
 	}
 	return missing
 }
 
+// GetResourcesMissingRefreshStep returns resources that have tests but no RefreshState step.
+// Resources with unresolved steps are not included, as those steps may refresh.
+func (c *CoverageCalculator) GetResourcesMissingRefreshStep() []*registry.ResourceCoverage {
+	var missing []*registry.ResourceCoverage
+	for _, cov := range c.GetAllResourceCoverage() {
+		if cov.HasBasicTest && !cov.HasRefreshTest && !cov.HasOpaqueSteps && cov.Resource.Kind == registry.KindResource {
+			missing = append(missing, cov)
+		}
+	}
+	return missing
+}
+
+// GetResourcesMissingIdempotencyStep returns resources with Optional+Computed attributes that
+// have tests but none that re-plans a config and expects an empty plan. Resources with
+// unresolved steps are not included, as those steps may be idempotency steps.
+func (c *CoverageCalculator) GetResourcesMissingIdempotencyStep() []*registry.ResourceCoverage {
+	var missing []*registry.ResourceCoverage
+	for _, cov := range c.GetAllResourceCoverage() {
+		if cov.HasBasicTest && !cov.HasIdempotencyStep && !cov.HasOpaqueSteps && cov.Resource.Kind == registry.KindResource && len(PerpetualDiffAttributes(cov.Resource)) > 0 {
+			missing = append(missing, cov)
+		}
+	}
+	return missing
+}
+
+// PerpetualDiffAttributes returns the paths of a resource's Optional+Computed attributes,
+// nested ones as parent.child, in schema order.
+func PerpetualDiffAttributes(info *registry.ResourceInfo) []string {
+	var names []string
+	var walk func(prefix string, attrs []registry.AttributeInfo)
+	walk = func(prefix string, attrs []registry.AttributeInfo) {
+		for i := range attrs {
+			if attrs[i].ProneToPerpetualDiff() {
+				names = append(names, prefix+attrs[i].Name)
+			}
+			walk(prefix+attrs[i].Name+".", attrs[i].Nested)
+		}
+	}
+	walk("", info.Attributes)
+	walk("", info.Blocks)
+	return names
+}
+
 // GetResourcesMissingCheckDestroy returns resources that have tests but no CheckDestroy.
 func (c *CoverageCalculator) GetResourcesMissingCheckDestroy() []*registry.ResourceCoverage {
 	coverages := c.GetAllResourceCoverage()
@@ -0,0 +1,341 @@
+package analysis
+
+import (
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// ResourceDetail is everything known about one definition, gathered for validate show: its
+// schema, lifecycle surface, linked tests and the checks they cover. Diagnostics are filled
+// in by the caller, which runs the analyzers.
+type ResourceDetail struct {
+	Name         string            `json:"name"`
+	Kind         string            `json:"kind"`
+	File         string            `json:"file"`
+	Line         int               `json:"line"`
+	URL          string            `json:"url,omitempty"`
+	DiscoveredBy string            `json:"discovered_by"`
+	Maturity     string            `json:"maturity,omitempty"`
+	AliasOf      string            `json:"alias_of,omitempty"`
+	Version      int               `json:"schema_version,omitempty"`
+	Attributes   []AttributeDetail `json:"attributes"`
+	Operations   []OperationDetail `json:"operations"`
+	Tests        []TestDetail      `json:"tests"`
+	Checks       []CheckDetail     `json:"checks"`
+	Diagnostics  []DiagnosticEntry `json:"diagnostics"`
+	NextTests    []string          `json:"next_tests,omitempty"`
+}
+
+// AttributeDetail is a schema attribute and its flags (required, optional, computed,
+// updatable, validators, default).
+type AttributeDetail struct {
+	Name  string   `json:"name"`
+	Type  string   `json:"type,omitempty"`
+	Flags []string `json:"flags"`
+}
+
+// OperationDetail is a lifecycle operation (Create, Read, Update or Delete) of a definition.
+// Line is 0 when discovery only knows whether the operation is set, as for SDK v2.
+type OperationDetail struct {
+	Name        string `json:"name"`
+	Implemented bool   `json:"implemented"`
+	NoOp        bool   `json:"no_op,omitempty"`
+	Line        int    `json:"line,omitempty"`
+}
+
+// TestDetail is a test linked to a definition, how it was linked and what its steps do.
+type TestDetail struct {
+	CandidateTest
+	URL          string       `json:"url,omitempty"`
+	Quarantined  bool         `json:"quarantined,omitempty"`
+	Opaque       bool         `json:"opaque_steps,omitempty"`
+	CheckDestroy bool         `json:"check_destroy"`
+	Upgrade      bool         `json:"upgrade,omitempty"`       // An upgrade test, starting from a released provider
+	UpgradeFrom  string       `json:"upgrade_from,omitempty"`  // Version constraint of the release it starts from
+	UniqueChecks []string     `json:"unique_checks,omitempty"` // Checks no other test of the definition covers
+	Conditions   []string     `json:"conditions,omitempty"`    // Guards skipping the test under some versions, regions or capabilities
+	Steps        []StepDetail `json:"steps"`
+	// Subtests lists the t.Run subtests that run a TestCase of their own, each a scenario
+	Subtests []SubtestDetail `json:"subtests,omitempty"`
+}
+
+// SubtestDetail is a t.Run subtest of a test, with the numbers of the steps it runs.
+type SubtestDetail struct {
+	Name  string `json:"name"` // "?" when not known statically
+	Line  int    `json:"line,omitempty"`
+	Steps []int  `json:"steps"`
+}
+
+// StepDetail summarizes one test step, e.g. "config, check" or "import, verify".
+type StepDetail struct {
+	Number  int    `json:"number"`
+	Line    int    `json:"line,omitempty"`
+	Summary string `json:"summary"`
+}
+
+// CheckDetail is whether the definition's tests cover one directive check.
+type CheckDetail struct {
+	Check   string `json:"check"`
+	Covered bool   `json:"covered"`
+	Exempt  bool   `json:"exempt,omitempty"`
+}
+
+// DiagnosticEntry is an analyzer finding attributed to the definition or its tests.
+type DiagnosticEntry struct {
+	Rule    string `json:"rule"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+	URL     string `json:"url,omitempty"`
+}
+
+// lifecycleOperations lists the operations shown for each definition, in order.
+var lifecycleOperations = []string{"Create", "Read", "Update", "Delete"}
+
+// Describe gathers the detail of every definition matching query, which is read as by
+// Explain: a name with or without the provider prefix, optionally qualified by kind. The
+// registry must already be linked.
+func Describe(query string, reg *registry.ResourceRegistry, fset *token.FileSet, settings *config.Settings) []ResourceDetail {
+	kindFilter, names := explainNames(query)
+	details := []ResourceDetail{}
+	for _, info := range reg.GetSortedDefinitions() {
+		if (kindFilter != nil && *kindFilter != info.Kind) || !names[info.Name] {
+			continue
+		}
+		details = append(details, describeDefinition(info, reg, fset, settings))
+	}
+	return details
+}
+
+// describeDefinition gathers the detail of one definition.
+func describeDefinition(info *registry.ResourceInfo, reg *registry.ResourceRegistry, fset *token.FileSet, settings *config.Settings) ResourceDetail {
+	exp := explainDefinition(info, reg, fset, discovery.ConfigCorpus{}, settings)
+	detail := ResourceDetail{
+		Name:         info.Name,
+		Kind:         exp.Kind,
+		File:         info.FilePath,
+		Line:         exp.Line,
+		DiscoveredBy: info.DiscoveredBy,
+		Maturity:     info.Maturity,
+		AliasOf:      info.AliasOf,
+		Version:      info.SchemaVersion,
+		Attributes:   []AttributeDetail{},
+		Operations:   describeOperations(info, fset),
+		Tests:        []TestDetail{},
+		Diagnostics:  []DiagnosticEntry{},
+	}
+
+	attributes := append([]registry.AttributeInfo(nil), info.Attributes...)
+	sort.SliceStable(attributes, func(i, j int) bool { return attributes[i].Name < attributes[j].Name })
+	for _, attr := range attributes {
+		detail.Attributes = append(detail.Attributes, AttributeDetail{Name: attr.Name, Type: attr.Type, Flags: attributeFlags(attr)})
+	}
+
+	tests := reg.GetTests(info.Kind, info.Name)
+	provider := ProviderTypeName(reg)
+	byName := make(map[string]*registry.TestFunctionInfo, len(tests))
+	for _, fn := range tests {
+		byName[fn.Name] = fn
+	}
+	for _, candidate := range exp.Linked {
+		fn := byName[candidate.Test]
+		if fn == nil {
+			continue
+		}
+		test := TestDetail{
+			CandidateTest: candidate,
+			Quarantined:   fn.Quarantined,
+			Opaque:        fn.HasOpaqueSteps,
+			CheckDestroy:  fn.HasCheckDestroy,
+			UniqueChecks:  registry.UniqueChecks(info.Kind, tests, fn),
+			Steps:         []StepDetail{},
+		}
+		test.UpgradeFrom, test.Upgrade = fn.UpgradeFrom(provider)
+		for _, condition := range fn.Conditions {
+			test.Conditions = append(test.Conditions, condition.String())
+		}
+		// The first step of each subtest starts a TestCase of its own
+		firstSteps := make(map[int]bool)
+		for _, subtest := range fn.Subtests {
+			if len(subtest.Steps) > 0 {
+				firstSteps[subtest.Steps[0].StepNumber] = true
+			}
+		}
+		for i := range fn.TestSteps {
+			step := &fn.TestSteps[i]
+			sd := StepDetail{Number: step.StepNumber, Summary: stepSummary(step, i == 0 || firstSteps[step.StepNumber])}
+			if fset != nil && step.StepPos.IsValid() {
+				sd.Line = fset.Position(step.StepPos).Line
+			}
+			test.Steps = append(test.Steps, sd)
+		}
+		for _, subtest := range fn.Subtests {
+			sd := SubtestDetail{Name: subtest.Name, Steps: []int{}}
+			if sd.Name == "" {
+				sd.Name = "?"
+			}
+			if fset != nil && subtest.Pos.IsValid() {
+				sd.Line = fset.Position(subtest.Pos).Line
+			}
+			for _, step := range subtest.Steps {
+				sd.Steps = append(sd.Steps, step.StepNumber)
+			}
+			test.Subtests = append(test.Subtests, sd)
+		}
+		detail.Tests = append(detail.Tests, test)
+	}
+
+	covered := registry.CoveredChecks(tests)
+	spec, _ := info.Kind.Spec()
+	for _, check := range spec.Checks {
+		if check == registry.CheckManagedConfig {
+			continue
+		}
+		detail.Checks = append(detail.Checks, CheckDetail{Check: check, Covered: covered[check], Exempt: info.Directives.Exempts(check)})
+	}
+	detail.NextTests = nextTests(info, detail.Checks, exp)
+	return detail
+}
+
+// describeOperations lists the lifecycle operations of a definition: the methods declared in
+// its file for the framework, or the functions set on the schema.Resource for SDK v2. Data
+// sources only have Read.
+func describeOperations(info *registry.ResourceInfo, fset *token.FileSet) []OperationDetail {
+	operations := []OperationDetail{}
+	names := lifecycleOperations
+	if info.Kind == registry.KindDataSource {
+		names = []string{"Read"}
+	}
+	if ops := info.Operations; ops != nil {
+		set := map[string]bool{"Create": ops.Create, "Read": ops.Read, "Update": ops.Update, "Delete": ops.Delete}
+		for _, name := range names {
+			op := OperationDetail{Name: name, Implemented: set[name]}
+			op.NoOp = name == "Delete" && ops.DeleteNoOp
+			operations = append(operations, op)
+		}
+		return operations
+	}
+	if len(info.Methods) == 0 {
+		return operations
+	}
+	methods := make(map[string]registry.MethodRange, len(info.Methods))
+	for _, m := range info.Methods {
+		methods[m.Name] = m
+	}
+	for _, name := range names {
+		m, ok := methods[name]
+		op := OperationDetail{Name: name, Implemented: ok, NoOp: m.NoOp}
+		if ok && fset != nil && m.Pos.IsValid() {
+			op.Line = fset.Position(m.Pos).Line
+		}
+		operations = append(operations, op)
+	}
+	return operations
+}
+
+// attributeFlags lists the schema flags of an attribute.
+func attributeFlags(attr registry.AttributeInfo) []string {
+	flags := []string{}
+	for _, f := range []struct {
+		set  bool
+		name string
+	}{
+		{attr.Required, "required"},
+		{attr.Optional, "optional"},
+		{attr.Computed, "computed"},
+		{attr.IsUpdatable, "updatable"},
+		{attr.HasValidators, "validators"},
+		{attr.HasDefault, "default"},
+	} {
+		if f.set {
+			flags = append(flags, f.name)
+		}
+	}
+	return flags
+}
+
+// stepSummary describes what a test step does, e.g. "config, check" or "import, verify".
+// A config step after the first is an update.
+func stepSummary(step *registry.TestStepInfo, first bool) string {
+	var parts []string
+	switch {
+	case step.ImportState:
+		parts = append(parts, "import")
+		if step.ImportStateVerify {
+			parts = append(parts, "verify")
+		}
+		if step.ImportStatePersist {
+			parts = append(parts, "persist")
+		}
+	case step.HasConfig && !first:
+		parts = append(parts, "update config")
+	case step.HasConfig:
+		parts = append(parts, "config")
+	}
+	if step.RefreshState {
+		parts = append(parts, "refresh")
+	}
+	if step.ExpectError {
+		parts = append(parts, "expect error")
+	}
+	if step.HasCheck || step.HasConfigStateChecks {
+		parts = append(parts, "check")
+	}
+	if step.HasPlanCheck {
+		parts = append(parts, "plan check")
+	}
+	if step.HasRefreshPlanCheck {
+		parts = append(parts, "refresh plan check")
+	}
+	if step.PlanOnly {
+		parts = append(parts, "plan only")
+	}
+	if step.ExpectNonEmptyPlan {
+		parts = append(parts, "non-empty plan")
+	}
+	if len(parts) == 0 {
+		return "no config"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// nextTests suggests the tests that would cover a definition's uncovered checks, skipping
+// exempt ones, or how to link an existing test when none is linked.
+func nextTests(info *registry.ResourceInfo, checks []CheckDetail, exp DefinitionExplanation) []string {
+	if len(exp.Linked) == 0 {
+		return explainSuggestions(info, exp)
+	}
+	base := strings.TrimSuffix(BuildExpectedTestFunc(info), "_basic")
+	testFile := filepath.Base(exp.Linked[0].File)
+	var next []string
+	for _, check := range checks {
+		if check.Covered || check.Exempt {
+			continue
+		}
+		switch check.Check {
+		case registry.CheckUpdate:
+			next = append(next, base+"_update: apply a config, then change an updatable attribute in a second step")
+		case registry.CheckImport:
+			next = append(next, "Add an ImportState step with ImportStateVerify to a test in "+testFile)
+		case registry.CheckError:
+			next = append(next, base+"_invalid: a step with an invalid config and ExpectError")
+		case registry.CheckStateCheck:
+			next = append(next, "Add ConfigStateChecks to the config steps of a test in "+testFile+" (see -augment-tests)")
+		case registry.CheckDrift:
+			next = append(next, "Set CheckDestroy on a test in "+testFile)
+		case registry.CheckDisappears:
+			next = append(next, base+"_disappears: delete the resource out of band and expect a non-empty plan")
+		case registry.CheckRefresh:
+			next = append(next, "Add a RefreshState step after the config steps of a test in "+testFile)
+		case registry.CheckIdempotency:
+			next = append(next, "Repeat the last config of a test in "+testFile+" in a step with PlanOnly: true")
+		}
+	}
+	return next
+}
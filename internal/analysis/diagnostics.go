@@ -6,30 +6,19 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
-	"unicode"
 
 	"github.com/example/tfprovidertest/internal/matching"
+	"github.com/example/tfprovidertest/internal/naming"
 	"github.com/example/tfprovidertest/internal/registry"
 )
 
-// toTitleCase converts a string to title case.
-// E.g., "example_widget" -> "ExampleWidget"
-func toTitleCase(s string) string {
-	parts := strings.Split(s, "_")
-	for i, part := range parts {
-		if len(part) > 0 {
-			runes := []rune(part)
-			runes[0] = unicode.ToUpper(runes[0])
-			parts[i] = string(runes)
-		}
-	}
-	return strings.Join(parts, "")
-}
-
 // HasMatchingTestFile checks if a resource has matching test functions.
 func HasMatchingTestFile(resourceName string, isDataSource bool, reg *registry.ResourceRegistry) bool {
-	testFunctions := reg.GetResourceTests(resourceName)
-	return len(testFunctions) > 0
+	if isDataSource {
+		return len(reg.GetTests(registry.KindDataSource, resourceName)) > 0
+	}
+	return len(reg.GetTests(registry.KindResource, resourceName)) > 0 ||
+		len(reg.GetTests(registry.KindAction, resourceName)) > 0
 }
 
 // BuildExpectedTestPath constructs the expected test file path for a given resource.
@@ -43,7 +32,7 @@ func BuildExpectedTestPath(resource *registry.ResourceInfo) string {
 
 // BuildExpectedTestFunc constructs the expected test function name for a given resource.
 func BuildExpectedTestFunc(resource *registry.ResourceInfo) string {
-	titleName := toTitleCase(resource.Name)
+	titleName := naming.TitleCase(resource.Name)
 	if resource.Kind == registry.KindDataSource {
 		return fmt.Sprintf("TestAccDataSource%s_basic", titleName)
 	}
@@ -92,7 +81,7 @@ func BuildVerboseDiagnosticInfo(resource *registry.ResourceInfo, reg *registry.R
 		ResourceLine: 0,
 	}
 	expectedTestPath := BuildExpectedTestPath(resource)
-	testFunctions := reg.GetResourceTests(resource.Name)
+	testFunctions := reg.GetTests(resource.Kind, resource.Name)
 
 	// Collect unique test file paths
 	testFilePaths := make(map[string]bool)
@@ -118,7 +107,7 @@ func BuildVerboseDiagnosticInfo(resource *registry.ResourceInfo, reg *registry.R
 		info.TestFilesSearched = []registry.TestFileSearchResult{{FilePath: expectedTestPath, Found: false}}
 	}
 
-	titleName := toTitleCase(resource.Name)
+	titleName := naming.TitleCase(resource.Name)
 	if resource.Kind == registry.KindDataSource {
 		info.ExpectedPatterns = []string{"TestAccDataSource" + titleName + "*", "TestDataSource" + titleName + "*"}
 	} else {
@@ -143,7 +132,7 @@ func buildSuggestedFixes(resource *registry.ResourceInfo, testFunctions []*regis
 		}
 		if testFilePath != "" {
 			fixes = append(fixes, fmt.Sprintf("Option 1: Rename tests to follow convention (%s)", expectedFunc))
-			fixes = append(fixes, "Option 2: Configure custom test patterns in .golangci.yml:\n      test-name-patterns:\n        - \"Test"+toTitleCase(resource.Name)+"\"")
+			fixes = append(fixes, "Option 2: Configure custom test patterns in .golangci.yml:\n      test-name-patterns:\n        - \"Test"+naming.TitleCase(resource.Name)+"\"")
 		} else {
 			fixes = append(fixes, fmt.Sprintf("Add acceptance test function %s", expectedFunc))
 		}
@@ -0,0 +1,83 @@
+package analysis
+
+import (
+	"sort"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// EnvVarEntry describes one environment variable the acceptance suite depends on.
+type EnvVarEntry struct {
+	Name string `json:"name"`
+	// ConfigTests lists the tests interpolating the variable into a step config
+	ConfigTests []string `json:"config_tests"`
+	// PreCheckTests lists the tests whose PreCheck checks the variable
+	PreCheckTests []string `json:"precheck_tests"`
+	// SetByTests lists the tests that set the variable themselves with t.Setenv
+	SetByTests []string `json:"set_by_tests,omitempty"`
+	// Unchecked lists the tests interpolating the variable that neither check nor set it
+	Unchecked []string `json:"unchecked,omitempty"`
+	// Provider is true when the provider's Configure reads the variable
+	Provider bool `json:"provider"`
+}
+
+// EnvVarInventory returns every environment variable the tests interpolate into configs, check
+// in PreCheck or set, and the provider reads, in name order.
+func EnvVarInventory(reg *registry.ResourceRegistry) []EnvVarEntry {
+	entries := make(map[string]*EnvVarEntry)
+	entry := func(name string) *EnvVarEntry {
+		e, ok := entries[name]
+		if !ok {
+			e = &EnvVarEntry{Name: name, ConfigTests: []string{}, PreCheckTests: []string{}}
+			entries[name] = e
+		}
+		return e
+	}
+
+	tests := reg.GetAllTestFunctions()
+	sort.Slice(tests, func(i, j int) bool { return tests[i].Name < tests[j].Name })
+	for _, fn := range tests {
+		for _, use := range fn.ConfigEnvVars {
+			e := entry(use.Name)
+			e.ConfigTests = append(e.ConfigTests, fn.Name)
+			if uncheckedEnvVar(fn, use.Name) {
+				e.Unchecked = append(e.Unchecked, fn.Name)
+			}
+		}
+		for _, name := range fn.PreCheckEnvVars {
+			e := entry(name)
+			e.PreCheckTests = append(e.PreCheckTests, fn.Name)
+		}
+		for _, name := range fn.EnvVarsSet {
+			e := entry(name)
+			e.SetByTests = append(e.SetByTests, fn.Name)
+		}
+	}
+	if provider := reg.GetProvider(); provider != nil {
+		for _, name := range provider.EnvVars {
+			entry(name).Provider = true
+		}
+	}
+
+	inventory := make([]EnvVarEntry, 0, len(entries))
+	for _, e := range entries {
+		inventory = append(inventory, *e)
+	}
+	sort.Slice(inventory, func(i, j int) bool { return inventory[i].Name < inventory[j].Name })
+	return inventory
+}
+
+// uncheckedEnvVar reports whether a test interpolates an environment variable into a config
+// without its PreCheck checking it or the test setting it. A PreCheck calling into another
+// package may check it, so it is given the benefit of the doubt.
+func uncheckedEnvVar(fn *registry.TestFunctionInfo, name string) bool {
+	if fn.PreCheckUnresolved || fn.ChecksEnvVar(name) {
+		return false
+	}
+	for _, set := range fn.EnvVarsSet {
+		if set == name {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+package analysis
+
+import (
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/messages"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// errorCheckEstablishedShare is the share of a package's TestCases that must set ErrorCheck
+// for ErrorCheck to be the package's established pattern.
+const errorCheckEstablishedShare = 0.5
+
+// ErrorCheckPattern is how the TestCases of a package use ErrorCheck.
+type ErrorCheckPattern struct {
+	Helper string // The helper most TestCases setting ErrorCheck use, e.g. "acctest.ErrorCheck"
+	Set    int    // TestCases setting ErrorCheck
+	Total  int    // TestCases passed directly to a test runner
+}
+
+// Established reports whether ErrorCheck is the package's pattern: at least two TestCases,
+// and at least half of them, set it.
+func (p ErrorCheckPattern) Established() bool {
+	return p.Set >= 2 && float64(p.Set) >= errorCheckEstablishedShare*float64(p.Total)
+}
+
+// ErrorCheckPatterns returns the ErrorCheck pattern of the tests of each directory, counting
+// the TestCases passed directly to a test runner. The helper is the one set most often,
+// ties going to the first in name order; function literals count only when no TestCase
+// names a helper.
+func ErrorCheckPatterns(tests []*registry.TestFunctionInfo) map[string]ErrorCheckPattern {
+	type counts struct {
+		helpers map[string]int
+		set     int
+		total   int
+	}
+	byDir := make(map[string]*counts)
+	for _, test := range tests {
+		dir := filepath.Dir(test.FilePath)
+		for _, tc := range test.TestCases {
+			if !tc.Direct {
+				continue
+			}
+			c := byDir[dir]
+			if c == nil {
+				c = &counts{helpers: make(map[string]int)}
+				byDir[dir] = c
+			}
+			c.total++
+			if tc.ErrorCheck != "" {
+				c.set++
+				c.helpers[tc.ErrorCheck]++
+			}
+		}
+	}
+
+	patterns := make(map[string]ErrorCheckPattern, len(byDir))
+	for dir, c := range byDir {
+		helpers := make([]string, 0, len(c.helpers))
+		for helper := range c.helpers {
+			helpers = append(helpers, helper)
+		}
+		sort.Slice(helpers, func(i, j int) bool {
+			a, b := helpers[i], helpers[j]
+			if (a == "func") != (b == "func") {
+				return b == "func"
+			}
+			if c.helpers[a] != c.helpers[b] {
+				return c.helpers[a] > c.helpers[b]
+			}
+			return a < b
+		})
+		p := ErrorCheckPattern{Set: c.set, Total: c.total}
+		if len(helpers) > 0 {
+			p.Helper = helpers[0]
+		}
+		patterns[dir] = p
+	}
+	return patterns
+}
+
+// RunErrorCheckPresenceAnalyzer reports TestCases that set no ErrorCheck in packages where
+// most TestCases do. Providers such as AWS rely on ErrorCheck to skip tests failing with
+// errors expected in some regions or partitions, such as unsupported services; a TestCase
+// without it fails there instead. Only TestCases passed directly to resource.Test,
+// ParallelTest or UnitTest are checked, since a helper may set the field.
+func RunErrorCheckPresenceAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	reg := getOrBuildRegistry(pass, settings)
+	tests := reg.GetAllTestFunctions()
+	sort.Slice(tests, func(i, j int) bool { return tests[i].FunctionPos < tests[j].FunctionPos })
+	patterns := ErrorCheckPatterns(tests)
+
+	for _, test := range tests {
+		dir := filepath.Dir(test.FilePath)
+		p := patterns[dir]
+		if !p.Established() {
+			continue
+		}
+		helper := p.Helper
+		if helper == "func" {
+			helper = messages.Format(settings.Language, messages.ErrorCheckFuncLiteral, nil)
+		}
+		for _, tc := range test.TestCases {
+			if !tc.Direct || tc.ErrorCheck != "" {
+				continue
+			}
+			pos := pass.Fset.Position(tc.Pos)
+			pass.Reportf(tc.Pos, "%s", messages.Format(settings.Language, messages.ErrorCheckMissing, messages.Params{
+				"test":    test.Name,
+				"count":   p.Set,
+				"total":   p.Total,
+				"package": filepath.Base(dir),
+				"helper":  helper,
+				"file":    pos.Filename,
+				"line":    pos.Line,
+			}))
+		}
+	}
+	return nil, nil
+}
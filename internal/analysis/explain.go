@@ -0,0 +1,372 @@
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/internal/matching"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// Matcher names used in explanations, in the order the linker tries them.
+const (
+	MatcherFunctionName  = matching.StrategyFunctionName
+	MatcherHCLBlock      = matching.StrategyHCLBlock
+	MatcherInferred      = matching.StrategyInferred
+	MatcherFileProximity = matching.StrategyFile
+	MatcherFuzzy         = matching.StrategyFuzzy
+)
+
+// explainCandidateFloor is the name similarity at which a test no matcher accepted is still
+// listed as a candidate, so near misses such as typos show up in the explanation.
+const explainCandidateFloor = matching.FuzzyNearMissThreshold
+
+// Explanation is the decision trail for one queried definition name: how it was
+// discovered, which tests each matcher considered, and what to do about it. It is the
+// structured successor of VerboseDiagnosticInfo, encoded directly by validate -explain.
+type Explanation struct {
+	Query       string                  `json:"query"`
+	Definitions []DefinitionExplanation `json:"definitions"`
+	// Excluded lists definitions with the queried name in files skipped during discovery.
+	Excluded []ExcludedFile `json:"excluded,omitempty"`
+	// ExcludedTests lists test files named after the query that were skipped.
+	ExcludedTests []ExcludedFile `json:"excluded_tests,omitempty"`
+	Suggestions   []string       `json:"suggestions,omitempty"`
+}
+
+// DefinitionExplanation explains the coverage of one discovered definition.
+type DefinitionExplanation struct {
+	Name         string          `json:"name"`
+	Kind         string          `json:"kind"`
+	File         string          `json:"file"`
+	Line         int             `json:"line"`
+	DiscoveredBy string          `json:"discovered_by"`
+	RegisteredAt string          `json:"registered_at,omitempty"` // Registry map entry (file:line), when File is the function it calls
+	Expect       []string        `json:"expect,omitempty"`
+	Exempt       []string        `json:"exempt,omitempty"`
+	Manifest     string          `json:"requirements_manifest,omitempty"`
+	Linked       []CandidateTest `json:"linked_tests"`
+	Rejected     []CandidateTest `json:"rejected_candidates"`
+	Suggestions  []string        `json:"suggestions,omitempty"`
+	// UpdatePreview is a second step for a resource whose linked tests only apply one config
+	UpdatePreview *UpdatePreview `json:"update_preview,omitempty"`
+}
+
+// CandidateTest is a test function considered for a definition.
+type CandidateTest struct {
+	Test       string          `json:"test"`
+	File       string          `json:"file"`
+	Line       int             `json:"line"`
+	MatchType  string          `json:"match_type,omitempty"`
+	Confidence float64         `json:"confidence,omitempty"`
+	Reason     string          `json:"reason,omitempty"`
+	Matchers   []MatcherResult `json:"matchers"`
+}
+
+// MatcherResult is one matcher's verdict on a candidate test.
+type MatcherResult struct {
+	Matcher string  `json:"matcher"`
+	Matched bool    `json:"matched"`
+	Score   float64 `json:"score"`
+	Detail  string  `json:"detail"`
+}
+
+// ExcludedFile is a file skipped during discovery and the reason it was skipped.
+type ExcludedFile struct {
+	File   string `json:"file"`
+	Kind   string `json:"kind,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// Explain builds the decision trail for query, a definition name with or without the
+// provider prefix, optionally qualified by kind ("data source:widget"). The registry must
+// already be linked; files are used to find definitions and tests in excluded files.
+func Explain(query string, reg *registry.ResourceRegistry, fset *token.FileSet, files []*ast.File, settings *config.Settings) *Explanation {
+	exp := &Explanation{Query: query, Definitions: []DefinitionExplanation{}}
+	kindFilter, names := explainNames(query)
+	matchesQuery := func(kind registry.ResourceKind, name string) bool {
+		if kindFilter != nil && *kindFilter != kind {
+			return false
+		}
+		return names[name]
+	}
+
+	corpus := discovery.CatalogConfigs(files, fset)
+	for _, info := range reg.GetSortedDefinitions() {
+		if matchesQuery(info.Kind, info.Name) {
+			exp.Definitions = append(exp.Definitions, explainDefinition(info, reg, fset, corpus, settings))
+		}
+	}
+
+	classifier := discovery.FileClassifier(*settings)
+	for _, file := range files {
+		path := fset.Position(file.Pos()).Filename
+		reason := discovery.ExclusionReason(*settings, classifier, path)
+		if reason == "" {
+			continue
+		}
+		if strings.HasSuffix(path, "_test.go") {
+			if name, _ := matching.ExtractResourceNameFromPath(path); names[name] {
+				exp.ExcludedTests = append(exp.ExcludedTests, ExcludedFile{File: path, Reason: reason})
+			}
+			continue
+		}
+		for _, info := range discovery.ParseResources(file, fset, path) {
+			if matchesQuery(info.Kind, info.Name) {
+				exp.Excluded = append(exp.Excluded, ExcludedFile{File: path, Kind: info.Kind.String(), Reason: reason})
+			}
+		}
+	}
+	sort.Slice(exp.Excluded, func(i, j int) bool { return exp.Excluded[i].File < exp.Excluded[j].File })
+	sort.Slice(exp.ExcludedTests, func(i, j int) bool { return exp.ExcludedTests[i].File < exp.ExcludedTests[j].File })
+
+	switch {
+	case len(exp.Definitions) == 0 && len(exp.Excluded) > 0:
+		exp.Suggestions = append(exp.Suggestions,
+			"The definition is only declared in excluded files; adjust file-roles, exclude-paths or exclude-patterns if it is a real resource")
+	case len(exp.Definitions) == 0:
+		exp.Suggestions = append(exp.Suggestions, fmt.Sprintf(
+			"No definition named %q was discovered; check the name (with or without the provider prefix) and that its package is scanned (-scan-path or -recursive)", query))
+	}
+	for _, excluded := range exp.ExcludedTests {
+		exp.Suggestions = append(exp.Suggestions, fmt.Sprintf("Test file %s is excluded (%s)", filepath.Base(excluded.File), excluded.Reason))
+	}
+	return exp
+}
+
+// explainNames parses a query into an optional kind and the definition names it may refer
+// to: the name itself and, for prefixed names, the name without the provider prefix.
+func explainNames(query string) (*registry.ResourceKind, map[string]bool) {
+	var kind *registry.ResourceKind
+	name := query
+	if key, ok := registry.ParseResourceKey(query); ok {
+		kind = &key.Kind
+		name = key.Name
+	}
+	names := map[string]bool{name: true}
+	if idx := strings.Index(name, "_"); idx != -1 {
+		names[name[idx+1:]] = true
+	}
+	return kind, names
+}
+
+// explainDefinition evaluates every registered test against one definition, and previews an
+// update step from the configs of corpus.
+func explainDefinition(info *registry.ResourceInfo, reg *registry.ResourceRegistry, fset *token.FileSet, corpus discovery.ConfigCorpus, settings *config.Settings) DefinitionExplanation {
+	def := DefinitionExplanation{
+		Name:         info.Name,
+		Kind:         info.Kind.String(),
+		File:         info.FilePath,
+		DiscoveredBy: info.DiscoveredBy,
+		Linked:       []CandidateTest{},
+		Rejected:     []CandidateTest{},
+	}
+	if fset != nil && info.SchemaPos.IsValid() {
+		def.Line = fset.Position(info.SchemaPos).Line
+	}
+	if fset != nil && info.RegisteredPos.IsValid() {
+		pos := fset.Position(info.RegisteredPos)
+		def.RegisteredAt = fmt.Sprintf("%s:%d", pos.Filename, pos.Line)
+	}
+	if d := info.Directives; d != nil {
+		def.Expect = sortedChecks(d.Expect)
+		for check := range d.Exempt {
+			def.Exempt = append(def.Exempt, check)
+		}
+		sort.Strings(def.Exempt)
+		def.Manifest = d.Manifest
+	}
+
+	linked := make(map[*registry.TestFunctionInfo]bool)
+	for _, fn := range reg.GetTests(info.Kind, info.Name) {
+		linked[fn] = true
+	}
+
+	for _, fn := range reg.GetAllTestFunctions() {
+		results, similarity := evaluateMatchers(fn, info, settings)
+		candidate := CandidateTest{Test: fn.Name, File: fn.FilePath, Matchers: results}
+		if fset != nil && fn.FunctionPos.IsValid() {
+			candidate.Line = fset.Position(fn.FunctionPos).Line
+		}
+
+		if linked[fn] {
+			candidate.MatchType = fn.MatchType.String()
+			candidate.Confidence = fn.MatchConfidence
+			def.Linked = append(def.Linked, candidate)
+			continue
+		}
+
+		accepted := false
+		for _, r := range results {
+			accepted = accepted || r.Matched
+		}
+		if !accepted && similarity < explainCandidateFloor {
+			continue
+		}
+		candidate.Reason = rejectionReason(fn, accepted, similarity, settings)
+		def.Rejected = append(def.Rejected, candidate)
+	}
+	sort.SliceStable(def.Rejected, func(i, j int) bool {
+		return bestScore(def.Rejected[i]) > bestScore(def.Rejected[j])
+	})
+
+	def.Suggestions = explainSuggestions(info, def)
+	def.UpdatePreview = BuildUpdatePreview(info, reg.GetTests(info.Kind, info.Name), corpus, settings)
+	return def
+}
+
+// evaluateMatchers reports what each linker strategy concludes about fn and info, and the
+// fuzzy name similarity between them.
+func evaluateMatchers(fn *registry.TestFunctionInfo, info *registry.ResourceInfo, settings *config.Settings) ([]MatcherResult, float64) {
+	var results []MatcherResult
+
+	names, _ := settings.NamePipeline()
+	extracted, _ := matching.ExtractResourceFromFuncNameWith(names, fn.Name)
+	nameResult := MatcherResult{Matcher: MatcherFunctionName, Detail: "no resource name in the function name"}
+	if matched, ok := matching.MatchResourceByNameWith(names, fn.Name, map[string]bool{info.Name: true}); ok && matched == info.Name {
+		nameResult.Matched, nameResult.Score = true, 0.95
+		nameResult.Detail = "function name refers to " + info.Name
+	} else if extracted != "" {
+		nameResult.Detail = fmt.Sprintf("function name refers to %q", extracted)
+	}
+	results = append(results, nameResult)
+
+	blockResult := MatcherResult{Matcher: MatcherHCLBlock, Detail: "no HCL blocks found in test configs"}
+	var blocks []string
+	for _, block := range fn.InferredHCLBlocks {
+		blocks = append(blocks, block.BlockType+"."+block.ResourceType)
+		kind, ok := registry.KindFromBlockType(block.BlockType)
+		if ok && kind == info.Kind && sameDefinitionName(block.ResourceType, info.Name) {
+			blockResult.Matched, blockResult.Score = true, 1.0
+		}
+	}
+	if len(blocks) > 0 {
+		blockResult.Detail = "configs declare " + strings.Join(blocks, ", ")
+	}
+	results = append(results, blockResult)
+
+	inferredResult := MatcherResult{Matcher: MatcherInferred, Detail: "no resource types inferred from test configs"}
+	if len(fn.InferredResources) > 0 {
+		inferredResult.Detail = "inferred " + strings.Join(fn.InferredResources, ", ")
+		for _, name := range fn.InferredResources {
+			if sameDefinitionName(name, info.Name) {
+				inferredResult.Matched, inferredResult.Score = true, 0.85
+			}
+		}
+	}
+	results = append(results, inferredResult)
+
+	proximityResult := MatcherResult{Matcher: MatcherFileProximity, Detail: "file name does not name a resource"}
+	if name, _ := matching.ExtractResourceNameFromPath(fn.FilePath); name != "" {
+		proximityResult.Detail = fmt.Sprintf("file name refers to %q", name)
+		if name == info.Name {
+			proximityResult.Matched, proximityResult.Score = true, 0.9
+		}
+	}
+	results = append(results, proximityResult)
+
+	similarity := 0.0
+	if extracted != "" {
+		similarity = matching.CalculateSimilarity(extracted, info.Name)
+	}
+	fuzzyResult := MatcherResult{Matcher: MatcherFuzzy, Score: similarity}
+	switch {
+	case extracted == "":
+		fuzzyResult.Detail = "no resource name in the function name to compare"
+	case !settings.EnableFuzzyMatching:
+		fuzzyResult.Detail = fmt.Sprintf("similarity %.2f; fuzzy matching is disabled", similarity)
+	case similarity < matching.FuzzyLinkThreshold:
+		fuzzyResult.Detail = fmt.Sprintf("similarity %.2f below %.2f", similarity, matching.FuzzyLinkThreshold)
+	default:
+		fuzzyResult.Matched = true
+		fuzzyResult.Detail = fmt.Sprintf("similarity %.2f", similarity)
+	}
+	results = append(results, fuzzyResult)
+
+	return results, similarity
+}
+
+// rejectionReason explains why a candidate is not linked to the definition.
+func rejectionReason(fn *registry.TestFunctionInfo, accepted bool, similarity float64, settings *config.Settings) string {
+	if fn.MatchedResource != "" {
+		return fmt.Sprintf("linked to %q by %s (confidence %.2f), which takes priority",
+			fn.MatchedResource, fn.MatchType.String(), fn.MatchConfidence)
+	}
+	if accepted {
+		return "a matcher accepted the test but it was not linked; the definition name is ambiguous across kinds"
+	}
+	if !settings.EnableFuzzyMatching && similarity >= matching.FuzzyLinkThreshold {
+		return fmt.Sprintf("only fuzzy matching (similarity %.2f) would link it, and it is disabled", similarity)
+	}
+	return fmt.Sprintf("no matcher accepted it (closest name similarity %.2f)", similarity)
+}
+
+// explainSuggestions lists concrete next steps for a definition.
+func explainSuggestions(info *registry.ResourceInfo, def DefinitionExplanation) []string {
+	if len(def.Linked) > 0 {
+		return nil
+	}
+	suggestions := buildSuggestedFixes(info, nil)
+	for _, candidate := range def.Rejected {
+		for _, r := range candidate.Matchers {
+			if r.Matcher == MatcherFuzzy && r.Score >= matching.FuzzyLinkThreshold && !r.Matched {
+				suggestions = append(suggestions, fmt.Sprintf("Rename %s to %s, or run with -match-strategy fuzzy", candidate.Test, BuildExpectedTestFunc(info)))
+			}
+		}
+		if configuresDefinition(candidate) && strings.HasPrefix(candidate.Reason, "linked to") {
+			suggestions = append(suggestions, fmt.Sprintf("%s configures %s only alongside the resource it is linked to; add a test named %s",
+				candidate.Test, info.Name, BuildExpectedTestFunc(info)))
+		}
+	}
+	if info.Directives.Exempts(registry.CheckBasic) {
+		suggestions = append(suggestions, "The basic check is exempted by a //tftest:exempt directive, so no diagnostic is reported")
+	}
+	return suggestions
+}
+
+// configuresDefinition reports whether a candidate's configs declare the definition.
+func configuresDefinition(c CandidateTest) bool {
+	for _, r := range c.Matchers {
+		if r.Matched && (r.Matcher == MatcherHCLBlock || r.Matcher == MatcherInferred) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameDefinitionName reports whether a Terraform type name refers to the definition, with
+// or without the provider prefix.
+func sameDefinitionName(typeName, name string) bool {
+	if typeName == name {
+		return true
+	}
+	idx := strings.Index(typeName, "_")
+	return idx != -1 && typeName[idx+1:] == name
+}
+
+// bestScore returns the highest matcher score of a candidate.
+func bestScore(c CandidateTest) float64 {
+	best := 0.0
+	for _, r := range c.Matchers {
+		if r.Score > best {
+			best = r.Score
+		}
+	}
+	return best
+}
+
+// sortedChecks returns the keys of a directive check set in order.
+func sortedChecks(checks map[string]bool) []string {
+	var result []string
+	for check := range checks {
+		result = append(result, check)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,179 @@
+package analysis
+
+import (
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/messages"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// factoryPatterns are the factory fields a package's tests choose between to serve the
+// provider under test, newest protocol first, which breaks ties for the dominant pattern.
+var factoryPatterns = []string{registry.FieldProtoV6ProviderFactories, registry.FieldProtoV5ProviderFactories, registry.FieldProviderFactories}
+
+// FactoryPattern is the factory field most tests of a package set, with its most common
+// value.
+type FactoryPattern struct {
+	Field string
+	Value string
+	Tests int // Tests setting Field
+	Total int // Tests setting any of the factory patterns
+}
+
+// testFactoryField returns the first factory pattern field the test sets, preferring those
+// set on a TestCase to those set on a step.
+func testFactoryField(test *registry.TestFunctionInfo) (registry.ProviderFactoryField, bool) {
+	var first *registry.ProviderFactoryField
+	for _, tc := range test.TestCases {
+		for i, f := range tc.Fields {
+			if !isFactoryPattern(f.Name) {
+				continue
+			}
+			if f.Step == 0 {
+				return f, true
+			}
+			if first == nil {
+				first = &tc.Fields[i]
+			}
+		}
+	}
+	if first == nil {
+		return registry.ProviderFactoryField{}, false
+	}
+	return *first, true
+}
+
+func isFactoryPattern(field string) bool {
+	for _, p := range factoryPatterns {
+		if p == field {
+			return true
+		}
+	}
+	return false
+}
+
+// DominantFactoryPatterns returns the dominant factory pattern of the tests of each
+// directory: the field set by the most tests, ties going to the newest protocol.
+func DominantFactoryPatterns(tests []*registry.TestFunctionInfo) map[string]FactoryPattern {
+	type counts struct {
+		tests  map[string]int            // By field
+		values map[string]map[string]int // By field, then value
+		total  int
+	}
+	byDir := make(map[string]*counts)
+	for _, test := range tests {
+		f, ok := testFactoryField(test)
+		if !ok {
+			continue
+		}
+		dir := filepath.Dir(test.FilePath)
+		c := byDir[dir]
+		if c == nil {
+			c = &counts{tests: make(map[string]int), values: make(map[string]map[string]int)}
+			byDir[dir] = c
+		}
+		c.total++
+		c.tests[f.Name]++
+		if c.values[f.Name] == nil {
+			c.values[f.Name] = make(map[string]int)
+		}
+		c.values[f.Name][f.Value]++
+	}
+
+	dominant := make(map[string]FactoryPattern, len(byDir))
+	for dir, c := range byDir {
+		p := FactoryPattern{Total: c.total}
+		for _, field := range factoryPatterns {
+			if c.tests[field] > p.Tests {
+				p.Field, p.Tests = field, c.tests[field]
+			}
+		}
+		values := make([]string, 0, len(c.values[p.Field]))
+		for value := range c.values[p.Field] {
+			values = append(values, value)
+		}
+		sort.Slice(values, func(i, j int) bool {
+			a, b := c.values[p.Field][values[i]], c.values[p.Field][values[j]]
+			if a != b {
+				return a > b
+			}
+			return values[i] < values[j]
+		})
+		p.Value = values[0]
+		dominant[dir] = p
+	}
+	return dominant
+}
+
+// RunProviderFactoriesAnalyzer reports tests that serve the provider under test with another
+// factory field than most tests of their package, such as ProviderFactories among tests
+// setting ProtoV6ProviderFactories, and TestCases that give some of their steps no provider
+// at all. Both fail at runtime with errors that do not point at the cause. Only TestCases
+// passed directly to resource.Test, ParallelTest or UnitTest with literal steps are checked
+// for missing fields, since a helper may set them.
+func RunProviderFactoriesAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	reg := getOrBuildRegistry(pass, settings)
+	tests := reg.GetAllTestFunctions()
+	sort.Slice(tests, func(i, j int) bool { return tests[i].FunctionPos < tests[j].FunctionPos })
+	dominant := DominantFactoryPatterns(tests)
+
+	for _, test := range tests {
+		dir := filepath.Dir(test.FilePath)
+		p, known := dominant[dir]
+		if f, ok := testFactoryField(test); ok && known && f.Name != p.Field {
+			pos := pass.Fset.Position(f.Pos)
+			pass.Reportf(f.Pos, "%s", messages.Format(settings.Language, messages.ProviderFactoriesMixed, messages.Params{
+				"test":     test.Name,
+				"field":    f.Name,
+				"dominant": p.Field,
+				"value":    p.Value,
+				"count":    p.Tests,
+				"total":    p.Total,
+				"package":  filepath.Base(dir),
+				"file":     pos.Filename,
+				"line":     pos.Line,
+			}))
+		}
+
+		for _, tc := range test.TestCases {
+			if !tc.Missing() {
+				continue
+			}
+			suggestion := registry.FieldProtoV6ProviderFactories + " or " + registry.FieldProviderFactories
+			if known {
+				suggestion = p.Field + ": " + p.Value
+			}
+			pos := pass.Fset.Position(tc.Pos)
+			pass.Reportf(tc.Pos, "%s", messages.Format(settings.Language, messages.ProviderFactoriesMissing, messages.Params{
+				"test":       test.Name,
+				"steps":      missingFactorySteps(tc),
+				"fields":     strings.Join(registry.ProviderFactoryFields, ", "),
+				"suggestion": suggestion,
+				"file":       pos.Filename,
+				"line":       pos.Line,
+			}))
+		}
+	}
+	return nil, nil
+}
+
+// missingFactorySteps lists the steps of a TestCase given no provider, e.g. "2, 3".
+func missingFactorySteps(tc registry.TestCaseFactories) string {
+	set := make(map[int]bool)
+	for _, f := range tc.Fields {
+		set[f.Step] = true
+	}
+	var steps []string
+	for step := 1; step <= tc.Steps; step++ {
+		if !set[step] {
+			steps = append(steps, strconv.Itoa(step))
+		}
+	}
+	return strings.Join(steps, ", ")
+}
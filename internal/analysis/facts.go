@@ -0,0 +1,88 @@
+package analysis
+
+import (
+	"fmt"
+	"reflect"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// CoverageFact summarizes the definitions a package declares and how many tests cover each.
+// It is exported as a package fact by the basic test analyzer so golangci-lint can cache it
+// alongside the package's diagnostics. The type contains only exported, gob-encodable
+// fields, as the analysis framework requires.
+type CoverageFact struct {
+	Definitions []CoverageFactEntry
+}
+
+// CoverageFactEntry is the coverage of a single definition.
+type CoverageFactEntry struct {
+	Kind      string
+	Name      string
+	TestCount int
+}
+
+// AFact marks CoverageFact as an analysis fact.
+func (*CoverageFact) AFact() {}
+
+// String implements fmt.Stringer for analysistest and -debug output.
+func (f *CoverageFact) String() string {
+	tested := 0
+	for _, entry := range f.Definitions {
+		if entry.TestCount > 0 {
+			tested++
+		}
+	}
+	return fmt.Sprintf("coverage(%d/%d tested)", tested, len(f.Definitions))
+}
+
+// FactTypes returns the facts exported by the basic test analyzer, for its
+// analysis.Analyzer.FactTypes field.
+func FactTypes() []analysis.Fact {
+	return []analysis.Fact{new(CoverageFact)}
+}
+
+// NewCoverageFact builds the coverage fact for a registry. Entries are in registry key
+// order so the encoded fact is identical across runs.
+func NewCoverageFact(reg *registry.ResourceRegistry) *CoverageFact {
+	fact := &CoverageFact{}
+	for _, info := range reg.GetSortedDefinitions() {
+		fact.Definitions = append(fact.Definitions, CoverageFactEntry{
+			Kind:      info.Kind.String(),
+			Name:      info.Name,
+			TestCount: len(reg.GetTests(info.Kind, info.Name)),
+		})
+	}
+	return fact
+}
+
+// exportCoverageFact exports the package's coverage fact when the running analyzer declares
+// it. Drivers that do not support facts (such as the validate CLI) leave
+// ExportPackageFact nil, and packages without definitions export nothing.
+func exportCoverageFact(pass *analysis.Pass, reg *registry.ResourceRegistry) {
+	if pass.ExportPackageFact == nil || pass.Pkg == nil || !declaresFact(pass.Analyzer, new(CoverageFact)) {
+		return
+	}
+	fact := NewCoverageFact(reg)
+	if len(fact.Definitions) == 0 {
+		return
+	}
+	pass.ExportPackageFact(fact)
+}
+
+// declaresFact reports whether an analyzer lists the fact's type in FactTypes; exporting
+// an undeclared fact panics.
+func declaresFact(analyzer *analysis.Analyzer, fact analysis.Fact) bool {
+	if analyzer == nil {
+		return false
+	}
+	want := reflect.TypeOf(fact)
+	for _, declared := range analyzer.FactTypes {
+		if reflect.TypeOf(declared) == want {
+			return true
+		}
+	}
+	return false
+}
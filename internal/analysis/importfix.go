@@ -0,0 +1,126 @@
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/messages"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// importStepFix returns a fix that appends an import step to the Steps literal of one of the
+// definition's tests, importing the block the test's last config step declares for it:
+//
+//	{
+//		ResourceName:      "example_widget.test",
+//		ImportState:       true,
+//		ImportStateVerify: true,
+//	},
+//
+// ok is false when no test has a Steps literal in the package whose configs are known
+// statically and declare the definition.
+func importStepFix(pass *analysis.Pass, settings *config.Settings, resource *registry.ResourceInfo, tests []*registry.TestFunctionInfo) (analysis.SuggestedFix, bool) {
+	for _, fn := range tests {
+		address, stepPos := importAddress(resource, fn)
+		if address == "" {
+			continue
+		}
+		last, steps := stepsLiteral(pass.Files, stepPos)
+		if steps == nil {
+			continue
+		}
+		return analysis.SuggestedFix{
+			Message: messages.Format(settings.Language, messages.ImportTestFix, messages.Params{
+				"test":    fn.Name,
+				"address": address,
+			}),
+			TextEdits: []analysis.TextEdit{{
+				Pos:     last.End(),
+				End:     last.End(),
+				NewText: []byte(importStepText(pass.Fset, address, last, steps)),
+			}},
+		}, true
+	}
+	return analysis.SuggestedFix{}, false
+}
+
+// importAddress returns the address of the definition's block in the last config step of fn
+// that declares one, and the position of that step.
+func importAddress(resource *registry.ResourceInfo, fn *registry.TestFunctionInfo) (string, token.Pos) {
+	for i := len(fn.TestSteps) - 1; i >= 0; i-- {
+		step := fn.TestSteps[i]
+		if !step.HasConfig || step.ExpectError || !step.StepPos.IsValid() {
+			continue
+		}
+		for _, address := range step.ConfigAddresses {
+			if declaresDefinition(address, resource) {
+				return address, step.StepPos
+			}
+		}
+	}
+	return "", token.NoPos
+}
+
+// declaresDefinition reports whether a config address names a resource block of the
+// definition, with or without the provider prefix.
+func declaresDefinition(address string, resource *registry.ResourceInfo) bool {
+	if strings.HasPrefix(address, "data.") {
+		return false
+	}
+	typeName, _, _ := strings.Cut(address, ".")
+	if typeName == resource.Name {
+		return true
+	}
+	_, short, ok := strings.Cut(typeName, "_")
+	return ok && short == resource.Name
+}
+
+// stepsLiteral finds the slice literal of test steps holding the step literal whose first
+// field is at stepPos, and returns its last element and the literal.
+func stepsLiteral(files []*ast.File, stepPos token.Pos) (ast.Expr, *ast.CompositeLit) {
+	for _, file := range files {
+		if stepPos < file.Pos() || stepPos > file.End() {
+			continue
+		}
+		var last ast.Expr
+		var steps *ast.CompositeLit
+		ast.Inspect(file, func(n ast.Node) bool {
+			lit, ok := n.(*ast.CompositeLit)
+			if !ok || steps != nil {
+				return steps == nil
+			}
+			for _, elt := range lit.Elts {
+				if step, ok := elt.(*ast.CompositeLit); ok && len(step.Elts) > 0 && step.Elts[0].Pos() == stepPos {
+					last, steps = lit.Elts[len(lit.Elts)-1], lit
+					return false
+				}
+			}
+			return true
+		})
+		return last, steps
+	}
+	return nil, nil
+}
+
+// importStepText renders the import step inserted after last, the last element of steps:
+// on lines of its own, indented like last, when the literal spans several lines, else
+// inline.
+func importStepText(fset *token.FileSet, address string, last ast.Expr, steps *ast.CompositeLit) string {
+	name := strconv.Quote(address)
+	if fset.Position(steps.Rbrace).Line == fset.Position(last.End()).Line {
+		return fmt.Sprintf(", {ResourceName: %s, ImportState: true, ImportStateVerify: true}", name)
+	}
+	// gofmt indents with tabs, so the column gives the depth
+	indent := strings.Repeat("\t", fset.Position(last.Pos()).Column-1)
+	return ",\n" + indent + "{\n" +
+		indent + "\tResourceName:      " + name + ",\n" +
+		indent + "\tImportState:       true,\n" +
+		indent + "\tImportStateVerify: true,\n" +
+		indent + "}"
+}
@@ -0,0 +1,100 @@
+package analysis
+
+import (
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/messages"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// DetectTestLayout returns where the provider keeps its acceptance tests: centralized when
+// most linked tests live in directories declaring no definitions (e.g., internal/acctest),
+// co-located otherwise. Fuzzy links are not counted, since they may pair a test with an
+// unrelated definition.
+func DetectTestLayout(reg *registry.ResourceRegistry) string {
+	definitionDirs := make(map[string]bool)
+	for _, def := range reg.GetAllDefinitions() {
+		definitionDirs[filepath.Dir(def.FilePath)] = true
+	}
+	colocated, centralized := 0, 0
+	for _, fn := range reg.GetAllTestFunctions() {
+		if !layoutLinked(fn) {
+			continue
+		}
+		if definitionDirs[filepath.Dir(fn.FilePath)] {
+			colocated++
+		} else {
+			centralized++
+		}
+	}
+	if centralized > colocated {
+		return config.TestLayoutCentralized
+	}
+	return config.TestLayoutColocated
+}
+
+// effectiveTestLayout returns the test-layout setting, detecting it when it is auto.
+func effectiveTestLayout(reg *registry.ResourceRegistry, settings *config.Settings) string {
+	switch settings.TestLayout {
+	case config.TestLayoutColocated, config.TestLayoutCentralized:
+		return settings.TestLayout
+	}
+	return DetectTestLayout(reg)
+}
+
+func layoutLinked(fn *registry.TestFunctionInfo) bool {
+	return fn.MatchedResource != "" && fn.MatchType != registry.MatchTypeNone && fn.MatchType != registry.MatchTypeFuzzy
+}
+
+// RunTestLayoutAnalyzer reports tests kept outside the directory of the definition they test
+// when the provider keeps its tests next to its definitions, with the expected location as a
+// suggested fix. It does nothing for a centralized layout, whether set or detected.
+func RunTestLayoutAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	reg := getOrBuildRegistry(pass, settings)
+	layout := effectiveTestLayout(reg, settings)
+	if layout == config.TestLayoutCentralized {
+		return nil, nil
+	}
+
+	tests := reg.GetAllTestFunctions()
+	sort.Slice(tests, func(i, j int) bool { return tests[i].FunctionPos < tests[j].FunctionPos })
+	for _, fn := range tests {
+		if !layoutLinked(fn) || !fn.FunctionPos.IsValid() {
+			continue
+		}
+		def := reg.MatchedDefinition(fn)
+		if def == nil || filepath.Dir(fn.FilePath) == filepath.Dir(def.FilePath) {
+			continue
+		}
+		pos := pass.Fset.Position(fn.FunctionPos)
+		kind, _ := kindLabels(settings.Language, def.Kind)
+		expected := BuildExpectedTestPath(def)
+		msg := messages.Format(settings.Language, messages.TestLayoutMisplaced, messages.Params{
+			"test":        fn.Name,
+			"kind":        kind,
+			"name":        def.Name,
+			"dir":         filepath.Dir(fn.FilePath),
+			"expectedDir": filepath.Dir(def.FilePath),
+			"layout":      layout,
+			"file":        pos.Filename,
+			"line":        pos.Line,
+			"expected":    expected,
+		})
+		pass.Report(analysis.Diagnostic{
+			Pos:     fn.FunctionPos,
+			Message: msg,
+			SuggestedFixes: []analysis.SuggestedFix{{
+				Message: messages.Format(settings.Language, messages.TestLayoutFix, messages.Params{
+					"test":     fn.Name,
+					"expected": expected,
+				}),
+			}},
+		})
+	}
+
+	return nil, nil
+}
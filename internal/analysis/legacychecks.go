@@ -0,0 +1,161 @@
+package analysis
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/internal/gitmeta"
+	"github.com/example/tfprovidertest/internal/messages"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// CheckMigration counts the steps of a definition's tests by how they assert state, to track
+// the migration from the legacy Check field to ConfigStateChecks. A step setting both counts
+// in both.
+type CheckMigration struct {
+	LegacySteps     int // Steps setting Check
+	StateCheckSteps int // Steps setting ConfigStateChecks
+}
+
+// CheckMigrationOf counts the steps of tests.
+func CheckMigrationOf(tests []*registry.TestFunctionInfo) CheckMigration {
+	var m CheckMigration
+	for _, test := range tests {
+		for _, step := range test.TestSteps {
+			if step.HasCheck {
+				m.LegacySteps++
+			}
+			if step.HasConfigStateChecks {
+				m.StateCheckSteps++
+			}
+		}
+	}
+	return m
+}
+
+// LegacyCheckStep is a test step setting the legacy Check field.
+type LegacyCheckStep struct {
+	Test *registry.TestFunctionInfo
+	Step *registry.TestStepInfo
+	// LegacySteps is how many steps of the test set Check, and BaseLegacySteps how many did
+	// at the base of the change
+	LegacySteps, BaseLegacySteps int
+}
+
+// legacyCheckSteps returns the steps of test that set Check.
+func legacyCheckSteps(test *registry.TestFunctionInfo) []*registry.TestStepInfo {
+	var steps []*registry.TestStepInfo
+	for i := range test.TestSteps {
+		if test.TestSteps[i].HasCheck {
+			steps = append(steps, &test.TestSteps[i])
+		}
+	}
+	return steps
+}
+
+// AddedLegacyChecks returns the steps setting Check that were added since the base of the
+// change, read with fileAt, sorted by file and position. A test whose steps set Check more
+// often than the test of the same name at the base did is taken to have added its last
+// steps setting it; every such step of a test or file new since the base is added. Files
+// that do not parse at the base are skipped.
+func AddedLegacyChecks(reg *registry.ResourceRegistry, settings config.Settings, fileAt gitmeta.FileAtFunc) ([]LegacyCheckStep, error) {
+	tests := reg.GetAllTestFunctions()
+	sort.Slice(tests, func(i, j int) bool {
+		if tests[i].FilePath != tests[j].FilePath {
+			return tests[i].FilePath < tests[j].FilePath
+		}
+		return tests[i].FunctionPos < tests[j].FunctionPos
+	})
+
+	type base struct {
+		counts map[string]int // Legacy steps by test name
+		ok     bool           // The file parsed at the base
+	}
+	bases := make(map[string]base) // By file
+	var added []LegacyCheckStep
+	for _, test := range tests {
+		steps := legacyCheckSteps(test)
+		if len(steps) == 0 {
+			continue
+		}
+		b, seen := bases[test.FilePath]
+		if !seen {
+			data, exists, err := fileAt(test.FilePath)
+			if err != nil {
+				return nil, err
+			}
+			b = base{counts: make(map[string]int), ok: true}
+			if exists {
+				b.counts, b.ok = baseLegacyCheckCounts(test.FilePath, data, settings)
+			}
+			bases[test.FilePath] = b
+		}
+		if !b.ok {
+			continue
+		}
+		before := b.counts[test.Name]
+		for _, step := range steps[min(before, len(steps)):] {
+			added = append(added, LegacyCheckStep{Test: test, Step: step, LegacySteps: len(steps), BaseLegacySteps: before})
+		}
+	}
+	return added, nil
+}
+
+// baseLegacyCheckCounts counts the steps setting Check of each test in one test file as it
+// was at the base of the change. ok is false when the file does not parse.
+func baseLegacyCheckCounts(path string, data []byte, settings config.Settings) (counts map[string]int, ok bool) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, data, parser.ParseComments)
+	if err != nil {
+		return nil, false
+	}
+	reg := discovery.BuildRegistry(&analysis.Pass{Fset: fset, Files: []*ast.File{file}}, settings)
+	counts = make(map[string]int)
+	for _, test := range reg.GetAllTestFunctions() {
+		counts[test.Name] += len(legacyCheckSteps(test))
+	}
+	return counts, true
+}
+
+// RunLegacyChecksAnalyzer reports test steps setting the legacy Check field that were added
+// since the git ref in the since setting, so new tests assert state with ConfigStateChecks
+// while the steps that already use Check migrate gradually. It does nothing without since:
+// -report counts the steps still using Check per definition instead.
+func RunLegacyChecksAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	if settings.Since == "" {
+		return nil, nil
+	}
+	reg := getOrBuildRegistry(pass, settings)
+	added, err := AddedLegacyChecks(reg, *settings, gitmeta.GitFileAt(context.Background(), settings.Since))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, legacy := range added {
+		reportPos := legacy.Step.CheckPos
+		if !reportPos.IsValid() {
+			reportPos = legacy.Step.StepPos
+		}
+		if !reportPos.IsValid() {
+			reportPos = legacy.Test.FunctionPos
+		}
+		pos := pass.Fset.Position(reportPos)
+		pass.Reportf(reportPos, "%s", messages.Format(settings.Language, messages.LegacyCheckAdded, messages.Params{
+			"test":   legacy.Test.Name,
+			"step":   legacy.Step.StepNumber,
+			"since":  settings.Since,
+			"legacy": legacy.LegacySteps,
+			"base":   legacy.BaseLegacySteps,
+			"file":   pos.Filename,
+			"line":   pos.Line,
+		}))
+	}
+	return nil, nil
+}
@@ -0,0 +1,114 @@
+package analysis
+
+import (
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/messages"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// DefinitionPair is a resource and a data source sharing a name, such as the widget resource
+// and the widget data source that reads one back.
+type DefinitionPair struct {
+	Name            string
+	Resource        *registry.ResourceInfo
+	DataSource      *registry.ResourceInfo
+	ResourceTests   int
+	DataSourceTests int
+}
+
+// Tested reports whether both sides of the pair have a test.
+func (p DefinitionPair) Tested() bool {
+	return p.ResourceTests > 0 && p.DataSourceTests > 0
+}
+
+// HalfTested returns the tested and the untested side of a pair where only one side has a
+// test.
+func (p DefinitionPair) HalfTested() (tested, untested *registry.ResourceInfo, ok bool) {
+	switch {
+	case p.ResourceTests > 0 && p.DataSourceTests == 0:
+		return p.Resource, p.DataSource, true
+	case p.DataSourceTests > 0 && p.ResourceTests == 0:
+		return p.DataSource, p.Resource, true
+	}
+	return nil, nil, false
+}
+
+// PairOf returns the definition sharing the name of info on the other side of a pair: the
+// data source of a resource, or the resource of a data source. It returns nil for actions
+// and for definitions without a counterpart.
+func PairOf(reg *registry.ResourceRegistry, info *registry.ResourceInfo) *registry.ResourceInfo {
+	switch info.Kind {
+	case registry.KindResource:
+		return reg.GetDefinition(registry.KindDataSource, info.Name)
+	case registry.KindDataSource:
+		return reg.GetDefinition(registry.KindResource, info.Name)
+	}
+	return nil
+}
+
+// DefinitionPairs returns the resources and data sources that share a name, sorted by name.
+func DefinitionPairs(reg *registry.ResourceRegistry) []DefinitionPair {
+	var pairs []DefinitionPair
+	for _, info := range reg.GetSortedDefinitions() {
+		if info.Kind != registry.KindResource {
+			continue
+		}
+		dataSource := PairOf(reg, info)
+		if dataSource == nil {
+			continue
+		}
+		pairs = append(pairs, DefinitionPair{
+			Name:            info.Name,
+			Resource:        info,
+			DataSource:      dataSource,
+			ResourceTests:   len(reg.GetTests(registry.KindResource, info.Name)),
+			DataSourceTests: len(reg.GetTests(registry.KindDataSource, info.Name)),
+		})
+	}
+	sort.SliceStable(pairs, func(i, j int) bool { return pairs[i].Name < pairs[j].Name })
+	return pairs
+}
+
+// RunPairedDefinitionsAnalyzer reports resources and data sources sharing a name where only
+// one side is tested. The untested side is reported, with the tests of the other side, whose
+// configs usually declare what the untested side needs.
+func RunPairedDefinitionsAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	reg := getOrBuildRegistry(pass, settings)
+
+	for _, pair := range DefinitionPairs(reg) {
+		tested, untested, ok := pair.HalfTested()
+		if !ok || untested.Directives.Exempts(registry.CheckBasic) {
+			continue
+		}
+		kind, kindTitle := kindLabels(settings.Language, untested.Kind)
+		testedKind, _ := kindLabels(settings.Language, tested.Kind)
+
+		var testNames []string
+		for _, fn := range reg.GetTests(tested.Kind, tested.Name) {
+			testNames = append(testNames, fn.Name)
+		}
+		sort.Strings(testNames)
+
+		pos := pass.Fset.Position(untested.SchemaPos)
+		testedPos := pass.Fset.Position(tested.SchemaPos)
+		pass.Reportf(untested.SchemaPos, "%s", messages.Format(settings.Language, messages.PairUntested, messages.Params{
+			"kind":       kind,
+			"kindTitle":  kindTitle,
+			"name":       untested.Name,
+			"testedKind": testedKind,
+			"count":      len(testNames),
+			"tests":      strings.Join(testNames, ", "),
+			"file":       pos.Filename,
+			"line":       pos.Line,
+			"testedFile": testedPos.Filename,
+			"testedLine": testedPos.Line,
+			"testFunc":   BuildExpectedTestFunc(untested),
+		}))
+	}
+	return nil, nil
+}
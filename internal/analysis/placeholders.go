@@ -0,0 +1,119 @@
+package analysis
+
+import (
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/messages"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// placeholderValues are config values that stand in for a real one. They are compared without
+// regard to case.
+var placeholderValues = map[string]bool{
+	"test": true, "testing": true, "foo": true, "bar": true, "baz": true, "example": true,
+	"changeme": true, "change-me": true, "replaceme": true, "replace-me": true,
+	"placeholder": true, "todo": true, "dummy": true, "fake": true, "xxx": true,
+	"abc": true, "string": true, "value": true, "123": true, "1234": true, "12345": true,
+}
+
+// valueFormat is a value format a schema validator enforces.
+type valueFormat struct {
+	Name    string   // e.g. "an ARN"
+	Example string   // A realistic value for the message
+	Markers []string // Substrings of validator names that enforce the format
+}
+
+// valueFormats lists the formats recognized from validator names, such as IsCIDR,
+// ValidARN or IsURLWithHTTPS. Markers are matched case-sensitively, so "ARN" does not match
+// a validator named "Warn".
+var valueFormats = []valueFormat{
+	{Name: "an ARN", Example: `"arn:aws:iam::123456789012:role/example"`, Markers: []string{"ARN", "Arn"}},
+	{Name: "a CIDR block", Example: `"10.0.0.0/16"`, Markers: []string{"CIDR", "Cidr"}},
+	{Name: "a URL", Example: `"https://example.com"`, Markers: []string{"URL", "Url", "URI", "Uri"}},
+	{Name: "an IP address", Example: `"192.0.2.10"`, Markers: []string{"IPAddress", "IPv4", "IPv6", "IpAddress"}},
+	{Name: "a UUID", Example: `"123e4567-e89b-12d3-a456-426614174000"`, Markers: []string{"UUID", "Uuid"}},
+	{Name: "an email address", Example: `"user@example.com"`, Markers: []string{"Email"}},
+}
+
+// IsPlaceholderValue reports whether a config value is an obvious placeholder, such as
+// "test", "foo" or "changeme".
+func IsPlaceholderValue(value string) bool {
+	return placeholderValues[strings.ToLower(strings.TrimSpace(value))]
+}
+
+// attributeFormat returns the format an attribute's validators enforce, if any.
+func attributeFormat(attr *registry.AttributeInfo) (valueFormat, bool) {
+	for _, validator := range attr.ValidatorTypes {
+		for _, format := range valueFormats {
+			for _, marker := range format.Markers {
+				if strings.Contains(validator, marker) {
+					return format, true
+				}
+			}
+		}
+	}
+	return valueFormat{}, false
+}
+
+// RunPlaceholderValuesAnalyzer reports test steps that set a required attribute validated as
+// an ARN, CIDR block, URL or similar to a placeholder value such as "test" or "changeme".
+// Such a step either fails validation at runtime or only passes against a mocked API. Steps
+// with ExpectError are skipped, since an invalid value is what they test.
+func RunPlaceholderValuesAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	reg := getOrBuildRegistry(pass, settings)
+
+	for _, resource := range reg.GetSortedDefinitions() {
+		if resource.Kind != registry.KindResource {
+			continue
+		}
+		formats := make(map[string]valueFormat)
+		for i := range resource.Attributes {
+			attr := &resource.Attributes[i]
+			if !attr.Required {
+				continue
+			}
+			if format, ok := attributeFormat(attr); ok {
+				formats[attr.Name] = format
+			}
+		}
+		if len(formats) == 0 {
+			continue
+		}
+
+		tests := append([]*registry.TestFunctionInfo(nil), reg.GetTests(resource.Kind, resource.Name)...)
+		sort.Slice(tests, func(i, j int) bool { return tests[i].FunctionPos < tests[j].FunctionPos })
+		for _, fn := range tests {
+			for _, step := range fn.TestSteps {
+				if step.ExpectError || !step.ConfigPos.IsValid() {
+					continue
+				}
+				reported := make(map[string]bool)
+				for _, value := range step.ConfigValues {
+					format, ok := formats[value.Attribute]
+					if !ok || reported[value.Attribute] || !sameDefinitionName(value.ResourceType, resource.Name) || !IsPlaceholderValue(value.Value) {
+						continue
+					}
+					reported[value.Attribute] = true
+					pos := pass.Fset.Position(step.ConfigPos)
+					msg := messages.Format(settings.Language, messages.PlaceholderValue, messages.Params{
+						"test":         fn.Name,
+						"step":         step.StepNumber,
+						"attribute":    value.Attribute,
+						"resourceType": value.ResourceType,
+						"value":        settings.RedactConfig(value.Value),
+						"format":       format.Name,
+						"example":      format.Example,
+						"file":         pos.Filename,
+						"line":         pos.Line,
+					})
+					pass.Reportf(step.ConfigPos, "%s", msg)
+				}
+			}
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,174 @@
+package analysis
+
+import (
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/internal/messages"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// PluralDataSource is a data source returning a list of items, such as the widgets data
+// source listing what the widget data source reads one of.
+type PluralDataSource struct {
+	DataSource *registry.ResourceInfo
+	// Singular is the data source, or else the resource, named for one item; nil when the
+	// provider defines neither
+	Singular *registry.ResourceInfo
+}
+
+// singularNames returns the names a plural name may be the plural of, most specific first:
+// "policies" -> "policy", "addresses" -> "address", "widgets" -> "widget".
+func singularNames(name string) []string {
+	var names []string
+	if stem, ok := strings.CutSuffix(name, "ies"); ok && stem != "" {
+		names = append(names, stem+"y")
+	}
+	if stem, ok := strings.CutSuffix(name, "es"); ok && stem != "" {
+		names = append(names, stem)
+	}
+	if stem, ok := strings.CutSuffix(name, "s"); ok && stem != "" && !strings.HasSuffix(name, "ss") {
+		names = append(names, stem)
+	}
+	return names
+}
+
+// SingularOf returns the singular counterpart of a data source named as the plural of
+// another definition: the data source of the singular name or, without one, the resource.
+func SingularOf(reg *registry.ResourceRegistry, info *registry.ResourceInfo) *registry.ResourceInfo {
+	if info.Kind != registry.KindDataSource {
+		return nil
+	}
+	for _, name := range singularNames(info.Name) {
+		if singular := reg.GetDefinition(registry.KindDataSource, name); singular != nil {
+			return singular
+		}
+		if singular := reg.GetDefinition(registry.KindResource, name); singular != nil {
+			return singular
+		}
+	}
+	return nil
+}
+
+// PluralDataSources returns the plural data sources of the registry, sorted by name: list
+// resources, and data sources whose name ends in "s" and is the plural of another definition.
+// A name ending in "s" alone, such as "status", does not make a data source plural.
+func PluralDataSources(reg *registry.ResourceRegistry) []PluralDataSource {
+	var plurals []PluralDataSource
+	for _, info := range reg.GetSortedDefinitions() {
+		if info.Kind != registry.KindDataSource {
+			continue
+		}
+		singular := SingularOf(reg, info)
+		if singular == nil && !info.ListResource {
+			continue
+		}
+		plurals = append(plurals, PluralDataSource{DataSource: info, Singular: singular})
+	}
+	return plurals
+}
+
+// assertsListSize reports whether check asserts how many items a collection attribute
+// holds: a legacy check of a count key such as "ids.#", or ExpectKnownValue with a size or
+// whole-list knownvalue such as ListSizeExact or ListExact.
+func assertsListSize(check registry.CheckAddress) bool {
+	if check.Function == "TestCheckNoResourceAttr" {
+		return false
+	}
+	if strings.HasSuffix(check.Attribute, ".#") {
+		return true
+	}
+	switch {
+	case strings.Contains(check.Value, "Size"):
+		return true
+	case check.Value == "ListExact", check.Value == "SetExact":
+		return true
+	}
+	return false
+}
+
+// collectionAttribute returns the list or set attribute or block a plural data source most
+// likely returns its items in, for the suggestion, or "". Required ones are filters rather
+// than results.
+func collectionAttribute(info *registry.ResourceInfo) string {
+	var names []string
+	for _, attr := range append(append([]registry.AttributeInfo(nil), info.Attributes...), info.Blocks...) {
+		if attr.Required {
+			continue
+		}
+		if strings.HasPrefix(attr.Kind, "List") || strings.HasPrefix(attr.Kind, "Set") ||
+			attr.Type == "TypeList" || attr.Type == "TypeSet" {
+			names = append(names, attr.Name)
+		}
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return ""
+	}
+	return names[0]
+}
+
+// RunPluralDataSourcesAnalyzer reports plural data sources whose tests assert no number of
+// items, only attributes of single items. A test checking "items.0.name" passes when the
+// lookup returns one item as well as when it returns all of them. Untested data sources,
+// which basic-test reports, and those with checks not resolved statically are skipped; the
+// state-check exemption opts a data source out.
+func RunPluralDataSourcesAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	reg := getOrBuildRegistry(pass, settings)
+
+	for _, plural := range PluralDataSources(reg) {
+		ds := plural.DataSource
+		if ds.Directives.Exempts(registry.CheckStateCheck) || !ds.SchemaPos.IsValid() {
+			continue
+		}
+		tests := reg.GetTests(registry.KindDataSource, ds.Name)
+		if len(tests) == 0 || stepsUnknown(tests) {
+			continue
+		}
+
+		sized, unresolved := false, false
+		for _, fn := range tests {
+			for _, step := range fn.TestSteps {
+				unresolved = unresolved || step.UnresolvedChecks
+				for _, check := range append(append([]registry.CheckAddress(nil), step.CheckAddresses...), step.StateCheckAddresses...) {
+					address, ok := discovery.CheckedResource(check.Address)
+					if !ok {
+						continue
+					}
+					parts := strings.Split(address, ".")
+					if parts[0] != "data" || resolveBlockType(reg, registry.KindDataSource, parts[1]) != ds {
+						continue
+					}
+					sized = sized || assertsListSize(check)
+				}
+			}
+		}
+		if sized || unresolved {
+			continue
+		}
+
+		attribute := collectionAttribute(ds)
+		if attribute == "" {
+			attribute = "items"
+		}
+		singular := "-"
+		if plural.Singular != nil {
+			kind, _ := kindLabels(settings.Language, plural.Singular.Kind)
+			singular = kind + " '" + plural.Singular.Name + "'"
+		}
+		pos := pass.Fset.Position(ds.SchemaPos)
+		pass.Reportf(ds.SchemaPos, "%s", messages.Format(settings.Language, messages.PluralDataSourceUnsized, messages.Params{
+			"name":      ds.Name,
+			"tests":     len(tests),
+			"singular":  singular,
+			"attribute": attribute,
+			"file":      pos.Filename,
+			"line":      pos.Line,
+		}))
+	}
+	return nil, nil
+}
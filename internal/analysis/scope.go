@@ -0,0 +1,28 @@
+package analysis
+
+import (
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// scopedFiles returns the files of the definitions and tests left in the registry by the
+// resources setting, for rules that inspect files rather than definitions. It returns nil,
+// meaning every file, when the setting is empty.
+func scopedFiles(reg *registry.ResourceRegistry, settings *config.Settings) map[string]bool {
+	if len(settings.Resources) == 0 {
+		return nil
+	}
+	files := make(map[string]bool)
+	for _, info := range reg.GetAllDefinitions() {
+		files[info.FilePath] = true
+	}
+	for _, fn := range reg.GetAllTestFunctions() {
+		files[fn.FilePath] = true
+	}
+	return files
+}
+
+// inScope reports whether filename is analyzed under the files returned by scopedFiles.
+func inScope(files map[string]bool, filename string) bool {
+	return files == nil || files[filename]
+}
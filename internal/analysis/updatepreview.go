@@ -0,0 +1,278 @@
+package analysis
+
+import (
+	"fmt"
+	"go/token"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// UpdatePreview is a concrete second step for a resource whose tests only apply one config:
+// the config of an existing step with one updatable attribute changed, or added when the
+// config leaves every updatable attribute unset. Configs are normalized as in the config
+// corpus, so values not known statically show as discovery.DynamicPlaceholder.
+type UpdatePreview struct {
+	Test      string `json:"test"` // The test the step would be added to
+	Step      int    `json:"step"` // The step whose config the preview changes
+	Attribute string `json:"attribute"`
+	Value     string `json:"value"` // The value the new step sets, as HCL
+	// Diff is the resource block of the step's config, each line prefixed by "  ", or by
+	// "- " and "+ " where the new step differs. It is empty with redact-configs, since it
+	// quotes the test's config.
+	Diff []string `json:"diff,omitempty"`
+}
+
+var (
+	// resourceBlockStart matches the first line of a resource block, capturing its header,
+	// type and the rest of the line.
+	resourceBlockStart = regexp.MustCompile(`^\s*(resource\s+"([^"]+)"\s+"[^"]*"\s*\{)(.*)$`)
+	// attributeLine matches a single-line attribute assignment, capturing its indentation,
+	// name and value.
+	attributeLine = regexp.MustCompile(`^(\s*)([A-Za-z_][A-Za-z0-9_-]*)\s*=\s*(.+?)\s*$`)
+)
+
+// BuildUpdatePreview returns the update step preview of a resource none of whose tests applies
+// a config differing from the one before it, starting from the first config in corpus that
+// one of the tests applies and that declares the resource in a block of its own. It returns
+// nil when the resource needs no update test, no such config is known, or none of its
+// updatable attributes is a string, number or bool.
+func BuildUpdatePreview(info *registry.ResourceInfo, tests []*registry.TestFunctionInfo, corpus discovery.ConfigCorpus, settings *config.Settings) *UpdatePreview {
+	if info.Kind != registry.KindResource || info.Directives.Exempts(registry.CheckUpdate) ||
+		len(tests) == 0 || appliesChangedConfig(tests) || stepsUnknown(tests) {
+		return nil
+	}
+	if info.Operations != nil && !info.Operations.Update {
+		return nil
+	}
+	var attrs []registry.AttributeInfo
+	for _, attr := range info.Attributes {
+		if isAttributeUpdatable(attr) && previewValueType(attr) != "" {
+			attrs = append(attrs, attr)
+		}
+	}
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	for _, test := range tests {
+		type use struct {
+			pos    token.Pos
+			config string
+		}
+		var uses []use
+		for _, c := range corpus.Configs {
+			for _, u := range c.Uses {
+				if u.Test == test.Name && u.FilePath == test.FilePath {
+					uses = append(uses, use{u.Pos, c.Config})
+				}
+			}
+		}
+		sort.Slice(uses, func(i, j int) bool { return uses[i].pos < uses[j].pos })
+		for _, u := range uses {
+			block := resourceBlock(strings.Split(u.config, "\n"), info.Name)
+			if block == nil {
+				continue
+			}
+			preview := previewChange(block, attrs)
+			preview.Test, preview.Step = test.Name, stepAt(test, u.pos)
+			if settings.RedactConfigs {
+				preview.Diff = nil
+			}
+			return preview
+		}
+	}
+	return nil
+}
+
+// stepAt returns the number of the test step a position lies in: the last step starting
+// before it, or 1 when the steps are not known.
+func stepAt(test *registry.TestFunctionInfo, pos token.Pos) int {
+	step := 1
+	for _, s := range test.TestSteps {
+		if s.StepPos.IsValid() && s.StepPos <= pos {
+			step = s.StepNumber
+		}
+	}
+	return step
+}
+
+// appliesChangedConfig reports whether a step of the tests applies a config differing from
+// the one the step before it applied.
+func appliesChangedConfig(tests []*registry.TestFunctionInfo) bool {
+	for _, test := range tests {
+		for _, step := range test.TestSteps {
+			if step.IsUpdateStepFlag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// resourceBlock returns the lines of the first resource block of the definition in a
+// normalized config, from its opening to its closing line and indented by two spaces per
+// level, or nil when the config does not declare one. A block written on one line, such as
+// resource "example_widget" "test" { name = "w1" }, is split into its header, body and
+// closing brace.
+func resourceBlock(lines []string, name string) []string {
+	for i, line := range lines {
+		m := resourceBlockStart.FindStringSubmatch(line)
+		if m == nil || !sameDefinitionName(m[2], name) {
+			continue
+		}
+		if rest := strings.TrimSpace(m[3]); rest != "" {
+			body, ok := strings.CutSuffix(rest, "}")
+			if !ok || !balanced(body) {
+				return nil
+			}
+			lines = []string{m[1], strings.TrimSpace(body), "}"}
+			if lines[1] == "" {
+				lines = []string{m[1], "}"}
+			}
+			i = 0
+		}
+		var block []string
+		depth := 0
+		for _, line := range lines[i:] {
+			line = strings.TrimSpace(line)
+			indent := depth
+			if strings.HasPrefix(line, "}") || strings.HasPrefix(line, "]") {
+				indent--
+			}
+			block = append(block, strings.Repeat("  ", max(indent, 0))+line)
+			depth += strings.Count(line, "{") - strings.Count(line, "}")
+			if depth == 0 {
+				return block
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// previewChange changes the first of attrs the block sets on a line of its own, or else adds
+// the first of them before the block's closing line.
+func previewChange(block []string, attrs []registry.AttributeInfo) *UpdatePreview {
+	set := make(map[string]int) // Top-level single-line attributes by name, to their line
+	indent := "  "
+	depth := 0
+	for i, line := range block {
+		if depth == 1 {
+			if m := attributeLine.FindStringSubmatch(line); m != nil && balanced(m[3]) {
+				if _, ok := set[m[2]]; !ok {
+					set[m[2]] = i
+				}
+				indent = m[1]
+			}
+		}
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+	}
+
+	preview := &UpdatePreview{}
+	for _, attr := range attrs {
+		i, ok := set[attr.Name]
+		if !ok {
+			continue
+		}
+		m := attributeLine.FindStringSubmatch(block[i])
+		preview.Attribute, preview.Value = attr.Name, changedValue(previewValueType(attr), m[3])
+		preview.Diff = diffLines(block[:i], nil)
+		preview.Diff = append(preview.Diff, "- "+block[i], "+ "+m[1]+attr.Name+" = "+preview.Value)
+		preview.Diff = append(preview.Diff, diffLines(block[i+1:], nil)...)
+		return preview
+	}
+
+	attr := attrs[0]
+	preview.Attribute, preview.Value = attr.Name, changedValue(previewValueType(attr), "")
+	last := len(block) - 1
+	preview.Diff = diffLines(block[:last], []string{"+ " + indent + attr.Name + " = " + preview.Value})
+	preview.Diff = append(preview.Diff, diffLines(block[last:], nil)...)
+	return preview
+}
+
+// diffLines prefixes unchanged lines for a diff and appends the added lines.
+func diffLines(lines []string, added []string) []string {
+	var diff []string
+	for _, line := range lines {
+		diff = append(diff, "  "+line)
+	}
+	return append(diff, added...)
+}
+
+// balanced reports whether a value opens no more brackets or braces than it closes, so the
+// assignment ends on its line.
+func balanced(value string) bool {
+	return strings.Count(value, "{") == strings.Count(value, "}") &&
+		strings.Count(value, "[") == strings.Count(value, "]") &&
+		strings.Count(value, "(") == strings.Count(value, ")")
+}
+
+// previewValueType returns the type of value a preview can set for an attribute: "string",
+// "number" or "bool", or "" for collections, objects and unknown types.
+func previewValueType(attr registry.AttributeInfo) string {
+	if len(attr.Nested) > 0 {
+		return ""
+	}
+	switch attr.Kind {
+	case "StringAttribute":
+		return "string"
+	case "BoolAttribute":
+		return "bool"
+	case "Int32Attribute", "Int64Attribute", "Float32Attribute", "Float64Attribute", "NumberAttribute":
+		return "number"
+	}
+	switch {
+	case strings.HasSuffix(attr.Type, "TypeString"):
+		return "string"
+	case strings.HasSuffix(attr.Type, "TypeBool"):
+		return "bool"
+	case strings.HasSuffix(attr.Type, "TypeInt"), strings.HasSuffix(attr.Type, "TypeFloat"):
+		return "number"
+	}
+	return ""
+}
+
+// changedValue returns an HCL value of the type that differs from old, the value the config
+// sets, or a plain value of the type when old is "".
+func changedValue(valueType, old string) string {
+	switch valueType {
+	case "bool":
+		if old == "true" {
+			return "false"
+		}
+		return "true"
+	case "number":
+		if n, err := strconv.ParseInt(old, 10, 64); err == nil {
+			return strconv.FormatInt(n+1, 10)
+		}
+		if f, err := strconv.ParseFloat(old, 64); err == nil {
+			return strconv.FormatFloat(f+1, 'g', -1, 64)
+		}
+		return "2"
+	}
+	if s, err := strconv.Unquote(old); err == nil && !strings.Contains(s, "${") {
+		return strconv.Quote(s + "-updated")
+	}
+	return `"updated"`
+}
+
+// FormatUpdatePreview renders an update step preview for a diagnostic or an explanation, or
+// returns "" for a nil preview.
+func FormatUpdatePreview(preview *UpdatePreview) string {
+	if preview == nil {
+		return ""
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "\n  Update step preview: add a step to %s that repeats step %d with %s = %s",
+		preview.Test, preview.Step, preview.Attribute, preview.Value)
+	for _, line := range preview.Diff {
+		sb.WriteString("\n    " + line)
+	}
+	return sb.String()
+}
@@ -0,0 +1,155 @@
+package analysis
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/internal/gitmeta"
+	"github.com/example/tfprovidertest/internal/messages"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// SchemaVersionChange is a resource whose schema version changed since the base of the
+// change under review.
+type SchemaVersionChange struct {
+	Resource *registry.ResourceInfo
+	From     int // Schema version at the base
+}
+
+// SchemaVersionChanges returns the resources whose schema version differs from the one in
+// their file at the base of the change, read with fileAt, in sorted order. A base file is
+// matched to a resource by name or, when both files declare a single resource, by being the
+// only one. Resources without a match at the base are new: there is no released state to
+// upgrade.
+func SchemaVersionChanges(reg *registry.ResourceRegistry, settings config.Settings, fileAt gitmeta.FileAtFunc) ([]SchemaVersionChange, error) {
+	perFile := make(map[string]int)
+	for _, info := range reg.GetAllDefinitions() {
+		if info.Kind == registry.KindResource {
+			perFile[info.FilePath]++
+		}
+	}
+
+	bases := make(map[string]*registry.ResourceRegistry) // By file; nil when it is new
+	var changes []SchemaVersionChange
+	for _, info := range reg.GetSortedDefinitions() {
+		if info.Kind != registry.KindResource {
+			continue
+		}
+		base, seen := bases[info.FilePath]
+		if !seen {
+			data, ok, err := fileAt(info.FilePath)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				base = baseRegistry(info.FilePath, data, settings)
+			}
+			bases[info.FilePath] = base
+		}
+		if base == nil {
+			continue
+		}
+		old := base.GetDefinition(info.Kind, info.Name)
+		if old == nil && perFile[info.FilePath] == 1 {
+			old = onlyResource(base)
+		}
+		if old != nil && old.SchemaVersion != info.SchemaVersion {
+			changes = append(changes, SchemaVersionChange{Resource: info, From: old.SchemaVersion})
+		}
+	}
+	return changes, nil
+}
+
+// baseRegistry discovers the definitions of one file as it was at the base of the change,
+// or returns nil when it does not parse.
+func baseRegistry(path string, data []byte, settings config.Settings) *registry.ResourceRegistry {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, data, parser.ParseComments)
+	if err != nil {
+		return nil
+	}
+	return discovery.BuildRegistry(&analysis.Pass{Fset: fset, Files: []*ast.File{file}}, settings)
+}
+
+// onlyResource returns the resource of a registry when it holds exactly one.
+func onlyResource(reg *registry.ResourceRegistry) *registry.ResourceInfo {
+	var only *registry.ResourceInfo
+	for _, info := range reg.GetAllDefinitions() {
+		if info.Kind != registry.KindResource {
+			continue
+		}
+		if only != nil {
+			return nil
+		}
+		only = info
+	}
+	return only
+}
+
+// RunUpgradeTestAnalyzer reports resources whose schema version changed since the git ref in
+// the since setting when none of their tests is an upgrade test, so the state upgraders the
+// change needs are never run against state written by a release. It does nothing without
+// since. Resources without tests are left to the basic-test rule.
+func RunUpgradeTestAnalyzer(pass *analysis.Pass, settings *config.Settings) (interface{}, error) {
+	if settings.Since == "" {
+		return nil, nil
+	}
+	reg := getOrBuildRegistry(pass, settings)
+	changes, err := SchemaVersionChanges(reg, *settings, gitmeta.GitFileAt(context.Background(), settings.Since))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := ProviderTypeName(reg)
+	for _, change := range changes {
+		resource := change.Resource
+		tests := reg.GetTests(resource.Kind, resource.Name)
+		if len(tests) == 0 || stepsUnknown(tests) || hasUpgradeTest(tests, provider) {
+			continue
+		}
+
+		reportPos := resource.VersionPos
+		if !reportPos.IsValid() {
+			reportPos = resource.SchemaPos
+		}
+		pos := pass.Fset.Position(reportPos)
+		name := provider
+		if name == "" {
+			name = "<provider>"
+		}
+		pass.Reportf(reportPos, "%s", messages.Format(settings.Language, messages.UpgradeTestMissing, messages.Params{
+			"name":     resource.Name,
+			"from":     change.From,
+			"to":       resource.SchemaVersion,
+			"since":    settings.Since,
+			"tests":    len(tests),
+			"provider": name,
+			"file":     pos.Filename,
+			"line":     pos.Line,
+		}))
+	}
+
+	return nil, nil
+}
+
+// ProviderTypeName returns the provider's type name, which upgrade tests pin in
+// ExternalProviders, or else the prefix the test configs use most for the provider's types.
+func ProviderTypeName(reg *registry.ResourceRegistry) string {
+	return providerTypePrefix(reg, reg.GetAllTestFunctions())
+}
+
+// hasUpgradeTest reports whether any of the tests is an upgrade test of the provider.
+func hasUpgradeTest(tests []*registry.TestFunctionInfo, provider string) bool {
+	for _, test := range tests {
+		if _, ok := test.UpgradeFrom(provider); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,337 @@
+// Package annotate writes a standardized test coverage line into the doc comment of each
+// definition's Go type, so coverage is visible in code review next to the code it describes:
+//
+//	// WidgetResource manages widgets.
+//	//
+//	// Test coverage: basic ✓ update ✗ import ✓ error ✗ state-check ✓ drift ✓ disappears ✗ (generated by tfprovidertest)
+//	type WidgetResource struct{}
+//
+// The line is found again by its "(generated by tfprovidertest)" marker and replaced in
+// place, so annotating twice changes nothing and other comments are never touched. SDK v2
+// definitions, which have no type of their own, are annotated on their schema function.
+package annotate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// Marker ends every coverage line, identifying it as generated.
+const Marker = "(generated by tfprovidertest)"
+
+// linePrefix starts every coverage line.
+const linePrefix = "// Test coverage:"
+
+// Annotation statuses.
+const (
+	StatusAdded     = "added"     // The coverage line was inserted
+	StatusUpdated   = "updated"   // An outdated coverage line was replaced
+	StatusUnchanged = "unchanged" // The coverage line was already current
+	StatusSkipped   = "skipped"   // The definition's declaration could not be found; see Reason
+)
+
+// directive matches comment lines such as //tftest:exempt and //go:generate, which gofmt
+// keeps at the end of a doc comment, as go/ast does.
+var directive = regexp.MustCompile(`^//[a-z0-9]+:[a-z0-9]`)
+
+// Annotation is the coverage line of one definition.
+type Annotation struct {
+	Definition  string `json:"definition"`            // Registry key, e.g. "resource:widget"
+	File        string `json:"file,omitempty"`        // File of the annotated declaration
+	Line        int    `json:"line,omitempty"`        // Line of the declaration, before annotating
+	Declaration string `json:"declaration,omitempty"` // Name of the annotated type or function
+	Comment     string `json:"comment"`
+	Status      string `json:"status"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// Comment returns the coverage line of a definition linked to tests: every check that
+// applies to its kind and that CoveredChecks reports, in the order of the kind's spec. The
+// refresh and idempotency checks belong to opt-in rules, so they are left out of the line.
+func Comment(kind registry.ResourceKind, tests []*registry.TestFunctionInfo) string {
+	covered := registry.CoveredChecks(tests)
+	spec, _ := kind.Spec()
+	parts := []string{linePrefix}
+	for _, check := range spec.Checks {
+		if check == registry.CheckManagedConfig || check == registry.CheckRefresh || check == registry.CheckIdempotency {
+			continue
+		}
+		mark := "✗"
+		if covered[check] {
+			mark = "✓"
+		}
+		parts = append(parts, check, mark)
+	}
+	return strings.Join(append(parts, Marker), " ")
+}
+
+// edit replaces the source bytes [start, end) of a file with text.
+type edit struct {
+	start, end int
+	text       string
+}
+
+// Apply computes the coverage line of every definition in the registry and, unless dryRun
+// is set, writes it into the doc comment of the definition's declaration. Definitions that
+// share another's implementation (AliasOf) are not annotated, since their type is. The
+// annotations are returned sorted by definition key; files are only rewritten when a line
+// was added or updated.
+func Apply(fset *token.FileSet, reg *registry.ResourceRegistry, dryRun bool) ([]Annotation, error) {
+	l := &locator{parsed: make(map[string]*parsedFile)}
+	edits := make(map[string][]edit)
+	var annotations []Annotation
+
+	for _, def := range reg.GetSortedDefinitions() {
+		if def.AliasOf != "" {
+			continue
+		}
+		annotation := Annotation{
+			Definition: def.Key().String(),
+			Comment:    Comment(def.Kind, reg.GetTests(def.Kind, def.Name)),
+		}
+		pos := fset.Position(def.SchemaPos)
+		if !pos.IsValid() {
+			annotation.Status, annotation.Reason = StatusSkipped, "no schema position"
+			annotations = append(annotations, annotation)
+			continue
+		}
+		decl, err := l.declaration(pos.Filename, pos.Offset)
+		if err != nil {
+			return nil, err
+		}
+		if decl == nil {
+			annotation.Status, annotation.Reason = StatusSkipped, "declaration not found"
+			annotations = append(annotations, annotation)
+			continue
+		}
+
+		annotation.File = decl.file.path
+		annotation.Line = decl.file.fset.Position(decl.pos).Line
+		annotation.Declaration = decl.name
+		e, changed := decl.edit(annotation.Comment)
+		switch {
+		case !changed:
+			annotation.Status = StatusUnchanged
+		case e.start == e.end:
+			annotation.Status = StatusAdded
+		default:
+			annotation.Status = StatusUpdated
+		}
+		if changed {
+			edits[decl.file.path] = append(edits[decl.file.path], e)
+		}
+		annotations = append(annotations, annotation)
+	}
+
+	if dryRun {
+		return annotations, nil
+	}
+	files := make([]string, 0, len(edits))
+	for file := range edits {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		if err := writeEdits(l.parsed[file], edits[file]); err != nil {
+			return nil, err
+		}
+	}
+	return annotations, nil
+}
+
+// writeEdits applies edits to a file, last first so earlier offsets stay valid. Two
+// definitions annotating the same declaration produce one edit.
+func writeEdits(file *parsedFile, edits []edit) error {
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	src := file.src
+	for i, e := range edits {
+		if i > 0 && e.start == edits[i-1].start {
+			continue
+		}
+		src = append(src[:e.start:e.start], append([]byte(e.text), src[e.end:]...)...)
+	}
+	info, err := os.Stat(file.path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file.path, src, info.Mode().Perm())
+}
+
+// parsedFile is a source file parsed on its own file set, so offsets index its content.
+type parsedFile struct {
+	path string
+	src  []byte
+	fset *token.FileSet
+	ast  *ast.File
+}
+
+// locator finds declarations, parsing each file at most once.
+type locator struct {
+	parsed map[string]*parsedFile
+}
+
+func (l *locator) parse(path string) (*parsedFile, error) {
+	if file, ok := l.parsed[path]; ok {
+		return file, nil
+	}
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	file := &parsedFile{path: path, src: src, fset: fset, ast: f}
+	l.parsed[path] = file
+	return file, nil
+}
+
+// declaration returns the declaration to annotate for the schema at offset in path: the
+// receiver's type when the schema is a method, looked up in the same file and then in the
+// other files of its directory, or else the enclosing function. It returns nil when there
+// is none.
+func (l *locator) declaration(path string, offset int) (*declaration, error) {
+	file, err := l.parse(path)
+	if err != nil {
+		return nil, err
+	}
+	base := file.fset.File(file.ast.Pos()).Base()
+	pos := token.Pos(base + offset)
+
+	var fn *ast.FuncDecl
+	for _, d := range file.ast.Decls {
+		if d.Pos() <= pos && pos < d.End() {
+			fn, _ = d.(*ast.FuncDecl)
+			break
+		}
+	}
+	if fn == nil {
+		return nil, nil
+	}
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return &declaration{file: file, name: fn.Name.Name, pos: fn.Pos(), doc: fn.Doc}, nil
+	}
+
+	name := receiverType(fn.Recv.List[0].Type)
+	if decl := findType(file, name); decl != nil {
+		return decl, nil
+	}
+	siblings, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*.go"))
+	for _, sibling := range siblings {
+		if sibling == path || strings.HasSuffix(sibling, "_test.go") {
+			continue
+		}
+		other, err := l.parse(sibling)
+		if err != nil {
+			continue
+		}
+		if decl := findType(other, name); decl != nil {
+			return decl, nil
+		}
+	}
+	return nil, nil
+}
+
+// receiverType returns the type name of a method receiver, without pointer or type
+// parameters.
+func receiverType(expr ast.Expr) string {
+	for {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.IndexExpr:
+			expr = t.X
+		case *ast.IndexListExpr:
+			expr = t.X
+		case *ast.Ident:
+			return t.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// findType returns the declaration of a type in a file. The doc comment of a type outside
+// parentheses belongs to its GenDecl.
+func findType(file *parsedFile, name string) *declaration {
+	for _, d := range file.ast.Decls {
+		gen, ok := d.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if ts.Name.Name != name {
+				continue
+			}
+			if gen.Lparen.IsValid() {
+				return &declaration{file: file, name: name, pos: ts.Pos(), doc: ts.Doc}
+			}
+			return &declaration{file: file, name: name, pos: gen.Pos(), doc: gen.Doc}
+		}
+	}
+	return nil
+}
+
+// declaration is a type or function whose doc comment gets the coverage line.
+type declaration struct {
+	file *parsedFile
+	name string
+	pos  token.Pos
+	doc  *ast.CommentGroup
+}
+
+// offset converts a position in the declaration's file to a byte offset.
+func (d *declaration) offset(pos token.Pos) int {
+	return d.file.fset.Position(pos).Offset
+}
+
+// edit returns the change that makes comment the declaration's coverage line, and whether
+// anything changes. An existing coverage line is replaced in place. Otherwise the line is
+// added as the last paragraph of the doc comment, before any trailing directives, or as the
+// whole doc comment when there is none.
+func (d *declaration) edit(comment string) (edit, bool) {
+	start := d.offset(d.pos)
+	lineStart := start
+	for lineStart > 0 && d.file.src[lineStart-1] != '\n' {
+		lineStart--
+	}
+	indent := string(d.file.src[lineStart:start])
+
+	if d.doc == nil {
+		return edit{start: start, end: start, text: comment + "\n" + indent}, true
+	}
+	for _, c := range d.doc.List {
+		if strings.HasPrefix(c.Text, linePrefix) && strings.HasSuffix(c.Text, Marker) {
+			if c.Text == comment {
+				return edit{}, false
+			}
+			return edit{start: d.offset(c.Pos()), end: d.offset(c.End()), text: comment}, true
+		}
+	}
+
+	// Insert after the last comment that is neither a directive nor an empty "//" line
+	last := -1
+	for i, c := range d.doc.List {
+		if !directive.MatchString(c.Text) && strings.TrimSpace(c.Text) != "//" {
+			last = i
+		}
+	}
+	if last < 0 {
+		at := d.offset(d.doc.Pos())
+		return edit{start: at, end: at, text: comment + "\n" + indent}, true
+	}
+	at := d.offset(d.doc.List[last].End())
+	return edit{start: at, end: at, text: "\n" + indent + "//\n" + indent + comment}, true
+}
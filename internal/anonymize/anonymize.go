@@ -0,0 +1,245 @@
+// Package anonymize replaces the names in a registry with keyed hashes, so a coverage report
+// can be shared outside the organization without exposing internal naming. Names are hashed
+// one segment at a time, where segments are separated by underscores or path separators, so
+// definitions of the same service keep a common prefix, files keep their directories and
+// extensions, and a name hashes the same wherever it appears. The same salt gives the same
+// hashes on every run, so reports taken at different times can still be compared.
+//
+// Everything counted by a report (definitions, tests, their links and the checks their steps
+// make) is kept; free text that could carry internal names, such as test configs, quarantine
+// reasons and error patterns, is dropped.
+package anonymize
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// hashLen is the number of hex digits kept of each segment's hash.
+const hashLen = 8
+
+// testPrefixes are the prefixes of test function names kept as they are, longest first, so
+// an anonymized name still reads as an acceptance or unit test.
+var testPrefixes = []string{"TestAcc", "Test"}
+
+// Anonymizer hashes names with a salt.
+type Anonymizer struct {
+	salt []byte
+}
+
+// New returns an Anonymizer keyed with salt.
+func New(salt string) *Anonymizer {
+	return &Anonymizer{salt: []byte(salt)}
+}
+
+// segment returns the hash of one segment of a name. Segments are hashed case-insensitively,
+// so "Widget" in a test name and "widget" in a resource name hash alike.
+func (a *Anonymizer) segment(s string) string {
+	if s == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, a.salt)
+	mac.Write([]byte(strings.ToLower(s)))
+	return hex.EncodeToString(mac.Sum(nil))[:hashLen]
+}
+
+// Name hashes each underscore-separated segment of a definition name or resource type, e.g.
+// example_storage_bucket becomes 1a2b3c4d_5e6f7a8b_9c0d1e2f.
+func (a *Anonymizer) Name(name string) string {
+	parts := strings.Split(name, "_")
+	for i, part := range parts {
+		parts[i] = a.segment(part)
+	}
+	return strings.Join(parts, "_")
+}
+
+// TestName hashes a test function name, keeping its Test or TestAcc prefix.
+func (a *Anonymizer) TestName(name string) string {
+	for _, prefix := range testPrefixes {
+		if rest, ok := strings.CutPrefix(name, prefix); ok && rest != "" {
+			return prefix + a.Name(rest)
+		}
+	}
+	return a.Name(name)
+}
+
+// Path hashes each element of a file path, keeping the separators, the extension and a
+// _test suffix, e.g. internal/service/widget_test.go becomes
+// 1a2b3c4d/5e6f7a8b/9c0d1e2f_test.go. An absolute path stays absolute.
+func (a *Anonymizer) Path(path string) string {
+	if path == "" {
+		return ""
+	}
+	elems := strings.Split(path, "/")
+	for i, elem := range elems {
+		if elem == "" || elem == "." || elem == ".." {
+			continue
+		}
+		ext := ""
+		if dot := strings.LastIndex(elem, "."); dot > 0 {
+			elem, ext = elem[:dot], elem[dot:]
+		}
+		suffix := ""
+		if stem, ok := strings.CutSuffix(elem, "_test"); ok && stem != "" {
+			elem, suffix = stem, "_test"
+		}
+		elems[i] = a.Name(elem) + suffix + ext
+	}
+	return strings.Join(elems, "/")
+}
+
+// Registry returns a copy of reg with every definition, test and file name hashed, and the
+// tests linked to the same definitions as in reg. reg is not modified.
+func (a *Anonymizer) Registry(reg *registry.ResourceRegistry) *registry.ResourceRegistry {
+	out := registry.NewResourceRegistry()
+	if provider := reg.GetProvider(); provider != nil {
+		p := *provider
+		p.TypeName = a.Name(p.TypeName)
+		p.TypeNameHint = ""
+		p.FilePath = a.Path(p.FilePath)
+		p.Attributes = a.attributes(p.Attributes)
+		p.EnvVars = nil
+		out.SetProvider(&p)
+	}
+
+	tests := make(map[*registry.TestFunctionInfo]*registry.TestFunctionInfo)
+	for _, fn := range reg.GetAllTestFunctions() {
+		tests[fn] = a.test(fn)
+		out.RegisterTestFunction(tests[fn])
+	}
+
+	for _, info := range reg.GetSortedDefinitions() {
+		out.RegisterResource(a.definition(info))
+		name := a.Name(info.Name)
+		for _, fn := range reg.GetTests(info.Kind, info.Name) {
+			out.LinkTest(info.Kind, name, linked(tests, fn))
+		}
+		for _, fn := range reg.GetQuarantinedTests(info.Kind, info.Name) {
+			out.LinkTest(info.Kind, name, linked(tests, fn))
+		}
+	}
+	return out
+}
+
+// linked returns the copy of a linked test. Tests are normally registered before they are
+// linked; a test that was only linked is copied on the spot.
+func linked(tests map[*registry.TestFunctionInfo]*registry.TestFunctionInfo, fn *registry.TestFunctionInfo) *registry.TestFunctionInfo {
+	if c, ok := tests[fn]; ok {
+		return c
+	}
+	return fn
+}
+
+// definition returns an anonymized copy of a definition.
+func (a *Anonymizer) definition(info *registry.ResourceInfo) *registry.ResourceInfo {
+	c := *info
+	c.Name = a.Name(info.Name)
+	c.FilePath = a.Path(info.FilePath)
+	c.Attributes = a.attributes(info.Attributes)
+	c.Blocks = a.attributes(info.Blocks)
+	c.Factory = ""
+	if info.Category != "" {
+		c.Category = a.Name(info.Category)
+	}
+	if info.AliasOf != "" {
+		c.AliasOf = a.Name(info.AliasOf)
+	}
+	c.Methods = append([]registry.MethodRange(nil), info.Methods...)
+	if info.Directives != nil {
+		d := *info.Directives
+		d.Exempt = make(map[string]string, len(info.Directives.Exempt))
+		for check := range info.Directives.Exempt {
+			d.Exempt[check] = ""
+		}
+		d.Manifest = a.Path(info.Directives.Manifest)
+		c.Directives = &d
+	}
+	if info.Operations != nil {
+		ops := *info.Operations
+		c.Operations = &ops
+	}
+	return &c
+}
+
+// attributes returns anonymized copies of schema attributes, keeping their types and flags.
+func (a *Anonymizer) attributes(attrs []registry.AttributeInfo) []registry.AttributeInfo {
+	if attrs == nil {
+		return nil
+	}
+	out := make([]registry.AttributeInfo, len(attrs))
+	for i, attr := range attrs {
+		attr.Name = a.Name(attr.Name)
+		attr.ValidatorTypes = nil
+		attr.DeprecationMessage = ""
+		attr.Nested = a.attributes(attr.Nested)
+		out[i] = attr
+	}
+	return out
+}
+
+// test returns an anonymized copy of a test function.
+func (a *Anonymizer) test(fn *registry.TestFunctionInfo) *registry.TestFunctionInfo {
+	c := *fn
+	c.Name = a.TestName(fn.Name)
+	c.FilePath = a.Path(fn.FilePath)
+	if fn.MatchedResource != "" {
+		c.MatchedResource = a.Name(fn.MatchedResource)
+	}
+	if fn.MatchedKey.Name != "" {
+		c.MatchedKey.Name = a.Name(fn.MatchedKey.Name)
+	}
+	c.HelperUsed = ""
+	c.InferredResources = nil
+	for _, name := range fn.InferredResources {
+		c.InferredResources = append(c.InferredResources, a.Name(name))
+	}
+	c.InferredHCLBlocks = nil
+	for _, block := range fn.InferredHCLBlocks {
+		c.InferredHCLBlocks = append(c.InferredHCLBlocks, registry.InferredHCLBlock{BlockType: block.BlockType, ResourceType: a.Name(block.ResourceType)})
+	}
+	c.TestSteps = nil
+	for _, step := range fn.TestSteps {
+		c.TestSteps = append(c.TestSteps, a.step(step))
+	}
+	c.ProviderConfigAttributes = nil
+	c.ProviderAliases = nil
+	c.EnvVarsSet = nil
+	c.ConfigEnvVars = nil
+	c.PreCheckEnvVars = nil
+	c.SerialReason = ""
+	c.Conditions = nil
+	for _, condition := range fn.Conditions {
+		// The guard as written names helpers and values; only its kind is kept
+		c.Conditions = append(c.Conditions, registry.TestCondition{Kind: condition.Kind, Pos: condition.Pos})
+	}
+	c.FixtureValues = nil
+	c.QuarantineReason = ""
+	return &c
+}
+
+// step returns a copy of a test step without its config and the names its checks refer to.
+func (a *Anonymizer) step(step registry.TestStepInfo) registry.TestStepInfo {
+	step.Config = ""
+	step.CheckFunctions = nil
+	step.ImportStateIdFunc = ""
+	step.ImportStateIdAttributes = nil
+	step.ImportStateCheck = ""
+	step.ImportStateCheckAttributes = nil
+	step.ExpectErrorPattern = ""
+	step.ConfigAttributes = nil
+	step.ConfigAddresses = nil
+	step.ConfigValues = nil
+	step.CheckAddresses = nil
+	step.StateCheckAddresses = nil
+	var providers []registry.ExternalProvider
+	for _, p := range step.ExternalProviders {
+		// Hashed like the provider's type name, so upgrade tests are still recognized
+		providers = append(providers, registry.ExternalProvider{Name: a.Name(p.Name), Source: a.Path(p.Source), VersionConstraint: p.VersionConstraint})
+	}
+	step.ExternalProviders = providers
+	return step
+}
@@ -0,0 +1,123 @@
+// Package artifact builds the run summary that release pipelines archive next to their
+// binaries: which version of the tool analyzed which commit with which settings, how long it
+// took, the coverage and finding totals, and the top findings. The document is versioned by
+// SchemaVersion; fields are only added within a version, never renamed or removed.
+package artifact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// SchemaVersion is the version of the summary document layout.
+const SchemaVersion = 1
+
+// DefaultFile is the conventional name of the summary file.
+const DefaultFile = "tfprovidertest-summary.json"
+
+// toolName names the tool in the summary.
+const toolName = "tfprovidertest"
+
+// MaxTopFindings limits the findings listed in a summary.
+const MaxTopFindings = 10
+
+// Summary is the run summary document.
+type Summary struct {
+	SchemaVersion int    `json:"schema_version"`
+	GeneratedAt   string `json:"generated_at"` // RFC 3339, UTC
+	Tool          Tool   `json:"tool"`
+	// Commit is the commit of the analyzed repository checked out at the time of the run;
+	// empty when the provider is not in a git work tree
+	Commit string `json:"commit,omitempty"`
+	// SettingsFingerprint identifies the effective settings, so runs with the same fingerprint
+	// are comparable
+	SettingsFingerprint string    `json:"settings_fingerprint"`
+	Rules               []string  `json:"rules"` // Rules that ran
+	Timing              Timing    `json:"timing"`
+	Totals              Totals    `json:"totals"`
+	TopFindings         []Finding `json:"top_findings"`
+	// Gate is the coverage gate status, pass, warn or fail; empty without a gate
+	Gate string `json:"gate,omitempty"`
+}
+
+// Tool identifies the build of the tool that produced a summary.
+type Tool struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"` // Module version, or "(devel)" for a build from a checkout
+	Revision  string `json:"revision,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+// Timing is the time spent in each phase of the run, in milliseconds.
+type Timing struct {
+	TotalMS float64            `json:"total_ms"`
+	Phases  map[string]float64 `json:"phases_ms"`
+}
+
+// Totals are the coverage and finding counts of a run.
+type Totals struct {
+	Definitions int            `json:"definitions"`
+	Tested      int            `json:"tested"`
+	Coverage    float64        `json:"coverage"` // Share of the definitions with a test, 0 to 1
+	Findings    int            `json:"findings"`
+	ByGroup     map[string]int `json:"findings_by_group"`
+	ByLevel     map[string]int `json:"findings_by_level"`
+}
+
+// Finding is one of the top findings of a run. Message is the first line of the finding's
+// message, which leaves out locations and suggestions.
+type Finding struct {
+	Rule     string `json:"rule"`
+	Code     string `json:"code,omitempty"`
+	Level    string `json:"level"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Resource string `json:"resource,omitempty"`
+	Message  string `json:"message"`
+}
+
+// CurrentTool describes the running build of the tool, from the build information embedded
+// by the go command.
+func CurrentTool() Tool {
+	tool := Tool{Name: toolName, Version: "(devel)", GoVersion: runtime.Version()}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return tool
+	}
+	if info.Main.Version != "" {
+		tool.Version = info.Main.Version
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			tool.Revision = setting.Value
+		}
+	}
+	return tool
+}
+
+// Fingerprint returns a hash of the settings, "sha256:" followed by hex digits. Settings that
+// are equal hash the same on every run and machine.
+func Fingerprint(settings config.Settings) (string, error) {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return "", fmt.Errorf("encoding settings: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// Write writes the summary to path as indented JSON.
+func Write(path string, summary Summary) error {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding summary: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
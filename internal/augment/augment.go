@@ -0,0 +1,301 @@
+// Package augment suggests state assertions for acceptance test steps that apply a config
+// without checking the resulting state: a ConfigStateChecks field with one
+// statecheck.ExpectKnownValue per Required attribute of the definition under test.
+//
+// The suggestions can be rendered as a unified diff that adds the field after each step's
+// Config, plus the statecheck, tfjsonpath and knownvalue imports a file lacks, so weak tests
+// can be upgraded with git apply and then tightened by hand (knownvalue.NotNull only asserts
+// that the attribute is set).
+package augment
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// Imports are the packages the suggested checks use, sorted.
+var Imports = []string{
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue",
+	"github.com/hashicorp/terraform-plugin-testing/statecheck",
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath",
+}
+
+// diffContext is the number of unchanged lines around each change in a patch.
+const diffContext = 3
+
+// Check is one suggested assertion.
+type Check struct {
+	Address   string `json:"address"`
+	Attribute string `json:"attribute"`
+}
+
+// Suggestion is the ConfigStateChecks field suggested for one test step.
+type Suggestion struct {
+	Test   string  `json:"test"`
+	File   string  `json:"file"`
+	Line   int     `json:"line"` // Line of the step's Config
+	Step   int     `json:"step"`
+	Checks []Check `json:"checks"`
+	// Code is the field to add to the step, without indentation.
+	Code string `json:"code"`
+
+	configPos token.Pos
+	configEnd token.Pos
+}
+
+// Suggest returns a suggestion for each test step that applies a config declaring a linked
+// resource or data source but sets neither Check nor ConfigStateChecks, sorted by file and
+// line. Import and ExpectError steps are skipped, as are steps whose config is not known
+// statically and definitions without Required attributes.
+func Suggest(fset *token.FileSet, reg *registry.ResourceRegistry) []Suggestion {
+	byStep := make(map[token.Pos]*Suggestion)
+	for _, def := range reg.GetSortedDefinitions() {
+		if def.Kind == registry.KindAction {
+			continue
+		}
+		var required []string
+		for _, attr := range def.Attributes {
+			if attr.Required {
+				required = append(required, attr.Name)
+			}
+		}
+		if len(required) == 0 {
+			continue
+		}
+
+		for _, fn := range reg.GetTests(def.Kind, def.Name) {
+			for _, step := range fn.TestSteps {
+				if !weakStep(step) {
+					continue
+				}
+				for _, address := range step.ConfigAddresses {
+					if !addressOf(address, def) {
+						continue
+					}
+					suggestion := byStep[step.ConfigPos]
+					if suggestion == nil {
+						suggestion = &Suggestion{
+							Test:      fn.Name,
+							File:      fn.FilePath,
+							Line:      fset.Position(step.ConfigPos).Line,
+							Step:      step.StepNumber,
+							configPos: step.ConfigPos,
+							configEnd: step.ConfigEnd,
+						}
+						byStep[step.ConfigPos] = suggestion
+					}
+					for _, attr := range required {
+						suggestion.Checks = append(suggestion.Checks, Check{Address: address, Attribute: attr})
+					}
+				}
+			}
+		}
+	}
+
+	suggestions := make([]Suggestion, 0, len(byStep))
+	for _, suggestion := range byStep {
+		suggestion.Code = render(suggestion.Checks)
+		suggestions = append(suggestions, *suggestion)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].File != suggestions[j].File {
+			return suggestions[i].File < suggestions[j].File
+		}
+		return suggestions[i].configPos < suggestions[j].configPos
+	})
+	return suggestions
+}
+
+// weakStep reports whether a step applies a statically known config without checking state.
+func weakStep(step registry.TestStepInfo) bool {
+	return step.HasConfig && step.ConfigAddresses != nil && step.ConfigEnd.IsValid() &&
+		!step.HasCheck && !step.HasConfigStateChecks && !step.ImportState && !step.ExpectError
+}
+
+// addressOf reports whether a config address names a block of the definition, with or
+// without the provider prefix.
+func addressOf(address string, def *registry.ResourceInfo) bool {
+	typeName, isData := strings.CutPrefix(address, "data.")
+	if isData != (def.Kind == registry.KindDataSource) {
+		return false
+	}
+	typeName, _, _ = strings.Cut(typeName, ".")
+	if typeName == def.Name {
+		return true
+	}
+	_, short, ok := strings.Cut(typeName, "_")
+	return ok && short == def.Name
+}
+
+// render returns the ConfigStateChecks field asserting each check.
+func render(checks []Check) string {
+	var b strings.Builder
+	b.WriteString("ConfigStateChecks: []statecheck.StateCheck{\n")
+	for _, check := range checks {
+		fmt.Fprintf(&b, "\tstatecheck.ExpectKnownValue(%s, tfjsonpath.New(%s), knownvalue.NotNull()),\n", strconv.Quote(check.Address), strconv.Quote(check.Attribute))
+	}
+	b.WriteString("},")
+	return b.String()
+}
+
+// Patch renders the suggestions as a unified diff with paths relative to root, for git apply
+// from root. A suggestion is skipped, and returned in skipped, when its step does not keep
+// Config on its own line ending in a comma, as there is no line to insert the field after.
+func Patch(fset *token.FileSet, suggestions []Suggestion, root string) (patch string, skipped []Suggestion, err error) {
+	byFile := make(map[string][]Suggestion)
+	var files []string
+	for _, suggestion := range suggestions {
+		if byFile[suggestion.File] == nil {
+			files = append(files, suggestion.File)
+		}
+		byFile[suggestion.File] = append(byFile[suggestion.File], suggestion)
+	}
+	sort.Strings(files)
+
+	var b strings.Builder
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", nil, err
+		}
+		lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+
+		inserts := make(map[int][]string)
+		applied := 0
+		for _, suggestion := range byFile[file] {
+			start, end := fset.Position(suggestion.configPos), fset.Position(suggestion.configEnd)
+			if start.Line < 1 || end.Line > len(lines) {
+				skipped = append(skipped, suggestion)
+				continue
+			}
+			keyLine := lines[start.Line-1]
+			indent := keyLine[:len(keyLine)-len(strings.TrimLeft(keyLine, " \t"))]
+			if !strings.HasPrefix(strings.TrimSpace(keyLine), "Config:") || end.Column < 1 || end.Column-1 > len(lines[end.Line-1]) ||
+				strings.TrimSpace(lines[end.Line-1][end.Column-1:]) != "," {
+				skipped = append(skipped, suggestion)
+				continue
+			}
+			for _, line := range strings.Split(suggestion.Code, "\n") {
+				inserts[end.Line] = append(inserts[end.Line], indent+line)
+			}
+			applied++
+		}
+		if applied == 0 {
+			continue
+		}
+		if err := addImports(file, content, inserts); err != nil {
+			return "", nil, err
+		}
+
+		rel := file
+		if r, err := filepath.Rel(root, file); err == nil {
+			rel = r
+		}
+		rel = filepath.ToSlash(rel)
+		fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", rel, rel)
+		writeHunks(&b, lines, inserts)
+	}
+	return b.String(), skipped, nil
+}
+
+// addImports adds insertions for the Imports the file does not import yet. Each goes after
+// the last spec whose path sorts before it in the file's last parenthesized import
+// declaration, or else as an import declaration of its own after the last import or the
+// package clause.
+func addImports(file string, content []byte, inserts map[int][]string) error {
+	fset := token.NewFileSet()
+	parsed, err := parser.ParseFile(fset, file, content, parser.ImportsOnly)
+	if err != nil {
+		return err
+	}
+	imported := make(map[string]bool)
+	for _, spec := range parsed.Imports {
+		if path, err := strconv.Unquote(spec.Path.Value); err == nil {
+			imported[path] = true
+		}
+	}
+
+	var block *ast.GenDecl
+	lastLine := fset.Position(parsed.Name.End()).Line
+	for _, decl := range parsed.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		lastLine = fset.Position(gen.End()).Line
+		if gen.Lparen.IsValid() && len(gen.Specs) > 0 {
+			block = gen
+		}
+	}
+
+	for _, path := range Imports {
+		if imported[path] {
+			continue
+		}
+		if block == nil {
+			inserts[lastLine] = append(inserts[lastLine], fmt.Sprintf("import %q", path))
+			continue
+		}
+		after := fset.Position(block.Specs[0].Pos()).Line - 1
+		for _, spec := range block.Specs {
+			if existing, _ := strconv.Unquote(spec.(*ast.ImportSpec).Path.Value); existing < path {
+				after = fset.Position(spec.End()).Line
+			}
+		}
+		inserts[after] = append(inserts[after], fmt.Sprintf("\t%q", path))
+	}
+	return nil
+}
+
+// writeHunks writes the unified diff hunks that insert inserts[n] after line n (1-based; 0
+// inserts before the first line).
+func writeHunks(b *strings.Builder, lines []string, inserts map[int][]string) {
+	at := make([]int, 0, len(inserts))
+	for line := range inserts {
+		at = append(at, line)
+	}
+	sort.Ints(at)
+
+	offset := 0
+	for i := 0; i < len(at); {
+		start := max(1, at[i]-diffContext+1)
+		end := min(len(lines), at[i]+diffContext)
+		j := i + 1
+		for j < len(at) && max(1, at[j]-diffContext+1) <= end+1 {
+			end = min(len(lines), at[j]+diffContext)
+			j++
+		}
+
+		var hunk []string
+		added := 0
+		if at[i] == 0 {
+			for _, line := range inserts[0] {
+				hunk = append(hunk, "+"+line)
+				added++
+			}
+		}
+		for n := start; n <= end; n++ {
+			hunk = append(hunk, " "+lines[n-1])
+			for _, line := range inserts[n] {
+				hunk = append(hunk, "+"+line)
+				added++
+			}
+		}
+		oldCount := end - start + 1
+		fmt.Fprintf(b, "@@ -%d,%d +%d,%d @@\n", start, oldCount, start+offset, oldCount+added)
+		for _, line := range hunk {
+			b.WriteString(line + "\n")
+		}
+		offset += added
+		i = j
+	}
+}
@@ -0,0 +1,233 @@
+// Package coverprofile correlates a Go coverage profile, written by running the acceptance
+// tests with go test -coverprofile, with the lifecycle methods of discovered definitions.
+//
+// Static discovery only knows that a test exists for a resource; the profile shows whether
+// running it executed the resource's Create, Read, Update and Delete methods. A tested
+// resource whose Update never ran, for example, has an update test that does not update.
+//
+// Profile paths are import paths (module path plus directory), while discovery knows file
+// system paths, so each source file is matched to the profile file sharing the longest
+// trailing run of path elements, at least the directory and file name.
+package coverprofile
+
+import (
+	"bufio"
+	"fmt"
+	"go/token"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// Block is one basic block of a coverage profile.
+type Block struct {
+	StartLine  int
+	EndLine    int
+	Statements int
+	Count      int
+}
+
+// Profile is a parsed coverage profile.
+type Profile struct {
+	Mode string
+	// Files maps each profile file name (import path and file) to its blocks. A block listed
+	// more than once, as happens when several test binaries instrument a package through
+	// -coverpkg, is merged into one with the counts added up.
+	Files map[string][]Block
+}
+
+// Parse reads a coverage profile in the format written by go test -coverprofile.
+func Parse(r io.Reader) (*Profile, error) {
+	profile := &Profile{Files: make(map[string][]Block)}
+	seen := make(map[string]int) // Index of each block in its file, keyed by file and range
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if mode, ok := strings.CutPrefix(line, "mode:"); ok {
+			profile.Mode = strings.TrimSpace(mode)
+			continue
+		}
+		name, block, err := parseBlock(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		key := line[:strings.LastIndex(line, " ")]
+		if i, ok := seen[key]; ok {
+			profile.Files[name][i].Count += block.Count
+			continue
+		}
+		seen[key] = len(profile.Files[name])
+		profile.Files[name] = append(profile.Files[name], block)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if profile.Mode == "" {
+		return nil, fmt.Errorf("not a coverage profile: missing mode line")
+	}
+	return profile, nil
+}
+
+// parseBlock parses a profile line: name.go:startLine.startCol,endLine.endCol statements count
+func parseBlock(line string) (string, Block, error) {
+	colon := strings.LastIndex(line, ":")
+	if colon == -1 {
+		return "", Block{}, fmt.Errorf("invalid block %q", line)
+	}
+	fields := strings.Fields(line[colon+1:])
+	if len(fields) != 3 {
+		return "", Block{}, fmt.Errorf("invalid block %q", line)
+	}
+	start, end, ok := strings.Cut(fields[0], ",")
+	if !ok {
+		return "", Block{}, fmt.Errorf("invalid range %q", fields[0])
+	}
+	var block Block
+	var err error
+	if block.StartLine, err = rangeLine(start); err != nil {
+		return "", Block{}, err
+	}
+	if block.EndLine, err = rangeLine(end); err != nil {
+		return "", Block{}, err
+	}
+	if block.Statements, err = strconv.Atoi(fields[1]); err != nil {
+		return "", Block{}, fmt.Errorf("invalid statement count %q", fields[1])
+	}
+	if block.Count, err = strconv.Atoi(fields[2]); err != nil {
+		return "", Block{}, fmt.Errorf("invalid count %q", fields[2])
+	}
+	return line[:colon], block, nil
+}
+
+// rangeLine returns the line of a line.column position.
+func rangeLine(pos string) (int, error) {
+	line, _, _ := strings.Cut(pos, ".")
+	n, err := strconv.Atoi(line)
+	if err != nil {
+		return 0, fmt.Errorf("invalid position %q", pos)
+	}
+	return n, nil
+}
+
+// Blocks returns the blocks of the profile file that matches a source file path, and false
+// when no profile file shares at least its directory and file name.
+func (p *Profile) Blocks(path string) ([]Block, bool) {
+	elems := strings.Split(strings.ReplaceAll(path, "\\", "/"), "/")
+	best, bestShared := "", 1
+	for name := range p.Files {
+		shared := discovery.SharedSuffix(elems, strings.Split(name, "/"))
+		if shared > bestShared || (shared == bestShared && shared > 1 && name < best) {
+			best, bestShared = name, shared
+		}
+	}
+	if best == "" {
+		return nil, false
+	}
+	return p.Files[best], true
+}
+
+// Method is the run-time coverage of one lifecycle method.
+type Method struct {
+	Name       string `json:"name"`
+	Line       int    `json:"line"`
+	Statements int    `json:"statements"`
+	Covered    int    `json:"covered"`
+}
+
+// Executed reports whether any statement of the method ran.
+func (m Method) Executed() bool {
+	return m.Covered > 0
+}
+
+// Definition is the run-time coverage of a definition's lifecycle methods.
+type Definition struct {
+	Name  string `json:"name"`
+	Kind  string `json:"kind"`
+	File  string `json:"file"`
+	Tests int    `json:"tests"`
+	// InProfile is false when the profile has no blocks for the definition's file, so the
+	// file's package was not instrumented and nothing is known about its methods.
+	InProfile bool     `json:"in_profile"`
+	Methods   []Method `json:"methods"`
+}
+
+// NeverExecuted returns the names of the methods none of whose statements ran. Methods
+// without statements, such as an empty Delete, are not included.
+func (d Definition) NeverExecuted() []string {
+	var names []string
+	for _, method := range d.Methods {
+		if method.Statements > 0 && !method.Executed() {
+			names = append(names, method.Name)
+		}
+	}
+	return names
+}
+
+// Report is the correlation of a profile with the discovered definitions.
+type Report struct {
+	Mode        string       `json:"mode"`
+	Definitions []Definition `json:"definitions"`
+	// Gaps lists the tested definitions found in the profile with a lifecycle method that
+	// never ran.
+	Gaps []Definition `json:"gaps"`
+	// NotInProfile lists the definitions with lifecycle methods whose file the profile does
+	// not cover.
+	NotInProfile []string `json:"not_in_profile"`
+}
+
+// Correlate computes the run-time coverage of the lifecycle methods of every definition
+// that declares any, sorted by kind and name.
+func Correlate(fset *token.FileSet, reg *registry.ResourceRegistry, profile *Profile) Report {
+	report := Report{Mode: profile.Mode, Definitions: []Definition{}, Gaps: []Definition{}, NotInProfile: []string{}}
+	for _, info := range reg.GetSortedDefinitions() {
+		if len(info.Methods) == 0 {
+			continue
+		}
+		def := Definition{
+			Name:  info.Name,
+			Kind:  info.Kind.String(),
+			File:  info.FilePath,
+			Tests: len(reg.GetTests(info.Kind, info.Name)),
+		}
+		blocks, ok := profile.Blocks(info.FilePath)
+		def.InProfile = ok
+		for _, method := range info.Methods {
+			start, end := fset.Position(method.Pos).Line, fset.Position(method.End).Line
+			covered := Method{Name: method.Name, Line: start}
+			for _, block := range blocks {
+				if block.StartLine < start || block.EndLine > end {
+					continue
+				}
+				covered.Statements += block.Statements
+				if block.Count > 0 {
+					covered.Covered += block.Statements
+				}
+			}
+			def.Methods = append(def.Methods, covered)
+		}
+		sort.SliceStable(def.Methods, func(i, j int) bool { return methodOrder(def.Methods[i].Name) < methodOrder(def.Methods[j].Name) })
+
+		report.Definitions = append(report.Definitions, def)
+		switch {
+		case !def.InProfile:
+			report.NotInProfile = append(report.NotInProfile, def.Kind+" "+def.Name)
+		case def.Tests > 0 && len(def.NeverExecuted()) > 0:
+			report.Gaps = append(report.Gaps, def)
+		}
+	}
+	return report
+}
+
+// methodOrder orders lifecycle methods as Create, Read, Update, Delete.
+func methodOrder(name string) int {
+	return strings.Index("CreateReadUpdateDelete", name)
+}
@@ -0,0 +1,85 @@
+// Package dedup ensures each diagnostic is reported once per run when analysis is sharded.
+//
+// golangci-lint analyzes every package (and the test variant of every package) separately, so
+// a resource visible from several packages can be reported several times. A Coordinator
+// records the diagnostics already reported. Within one process the record is an in-memory
+// set; when a directory is configured, claims are also made there with exclusive file
+// creation so that separate processes sharing the directory (e.g., go vet -vettool, or a
+// CI job sharded across runners with a shared workspace) coordinate as well.
+package dedup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Coordinator records which diagnostics have been reported during a run.
+type Coordinator struct {
+	mu   sync.Mutex
+	seen map[string]bool
+	dir  string
+}
+
+// New returns a Coordinator. If dir is non-empty, claims are shared through files in dir,
+// which is created if needed; use a fresh directory for each run.
+func New(dir string) *Coordinator {
+	return &Coordinator{seen: make(map[string]bool), dir: dir}
+}
+
+// Key identifies a diagnostic by rule, source position and message. Positions are taken from
+// the file set, so the same diagnostic computed for two package variants yields the same key.
+func Key(rule string, pos token.Position, message string) string {
+	return fmt.Sprintf("%s\x00%s:%d:%d\x00%s", rule, pos.Filename, pos.Line, pos.Column, message)
+}
+
+// Claim reports whether key has not been claimed before in this run, claiming it if so.
+// If the shared directory cannot be used, Claim falls back to the in-process record, so a
+// coordination failure may duplicate a diagnostic but never drops one.
+func (c *Coordinator) Claim(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.seen[key] {
+		return false
+	}
+	c.seen[key] = true
+
+	if c.dir == "" {
+		return true
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return true
+	}
+	sum := sha256.Sum256([]byte(key))
+	f, err := os.OpenFile(filepath.Join(c.dir, hex.EncodeToString(sum[:16])), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return !errors.Is(err, fs.ErrExist)
+	}
+	_ = f.Close()
+	return true
+}
+
+// Wrap replaces pass.Report so that only diagnostics not yet claimed are reported.
+// Diagnostics are keyed by the pass's analyzer name, position and message.
+func (c *Coordinator) Wrap(pass *analysis.Pass) *analysis.Pass {
+	report := pass.Report
+	rule := ""
+	if pass.Analyzer != nil {
+		rule = pass.Analyzer.Name
+	}
+	pass.Report = func(d analysis.Diagnostic) {
+		if c.Claim(Key(rule, pass.Fset.Position(d.Pos), d.Message)) {
+			report(d)
+		}
+	}
+	return pass
+}
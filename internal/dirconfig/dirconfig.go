@@ -0,0 +1,218 @@
+// Package dirconfig reads directory-level override files (tfprovidertest.dir.yaml) that
+// tighten or relax the linter for a subtree of a provider, as monorepos often need: a
+// directory of legacy services can report its findings as warnings or turn a rule off,
+// and a directory below it can restore errors once it is ready.
+//
+// The files between a source file's directory and the module root (the first directory
+// containing go.mod) apply to it. They are merged from the root down, so the file nearest to
+// the source file wins, and all of them are applied over the root configuration.
+package dirconfig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/example/tfprovidertest/internal/enforcement"
+	"github.com/example/tfprovidertest/internal/rules"
+)
+
+// File is the name of a directory-level override file.
+const File = "tfprovidertest.dir.yaml"
+
+// Override is the content of one override file.
+type Override struct {
+	// Level is the level of findings in the subtree, overriding enforce-paths and
+	// warn-only-paths. Empty inherits the level of the parent directory.
+	Level enforcement.Level `yaml:"level"`
+	// Disable lists rules, by name or code, whose findings are dropped in the subtree.
+	Disable []string `yaml:"disable"`
+	// Enable lists rules a parent directory disabled that report again in the subtree. A rule
+	// the root configuration does not enable is not run, so it cannot be enabled here.
+	Enable []string `yaml:"enable"`
+	// Path is the file the override was read from.
+	Path string `yaml:"-"`
+}
+
+// Parse parses and validates override data. path is recorded on the override and used in
+// error messages. Rule names are canonicalized, and codes such as TFPT003 are accepted.
+func Parse(data []byte, path string) (*Override, error) {
+	o := &Override{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(o); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	o.Path = path
+	switch o.Level {
+	case "", enforcement.LevelError, enforcement.LevelWarning:
+	default:
+		return nil, fmt.Errorf("%s: unknown level %q (known levels: error, warning)", path, o.Level)
+	}
+	var err error
+	if o.Disable, err = canonicalRules(o.Disable); err != nil {
+		return nil, fmt.Errorf("%s: disable: %w", path, err)
+	}
+	if o.Enable, err = canonicalRules(o.Enable); err != nil {
+		return nil, fmt.Errorf("%s: enable: %w", path, err)
+	}
+	return o, nil
+}
+
+// Load reads and parses the override file at path.
+func Load(path string) (*Override, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data, path)
+}
+
+// canonicalRules translates rule names, legacy names and codes to current rule names.
+func canonicalRules(names []string) ([]string, error) {
+	canonical := make([]string, 0, len(names))
+	for _, name := range names {
+		rule, ok := rules.Lookup(name)
+		if !ok {
+			rule, ok = lookupCode(name)
+		}
+		if !ok {
+			return nil, fmt.Errorf("unknown rule %q", name)
+		}
+		canonical = append(canonical, rule.Name)
+	}
+	return canonical, nil
+}
+
+func lookupCode(code string) (rules.Rule, bool) {
+	for _, rule := range rules.All() {
+		if rule.Code == code {
+			return rule, true
+		}
+	}
+	return rules.Rule{}, false
+}
+
+// Resolved is the merged overrides that apply to one directory.
+type Resolved struct {
+	// Level is the level of findings, or "" when no override sets one
+	Level enforcement.Level
+	// Disabled holds the rules whose findings are dropped
+	Disabled map[string]bool
+	// Files lists the override files applied, from the root down
+	Files []string
+}
+
+// Disables reports whether the named rule is disabled.
+func (r Resolved) Disables(rule string) bool {
+	return r.Disabled[rules.Canonical(rule)]
+}
+
+// merge applies a nested override over r.
+func (r Resolved) merge(o *Override) Resolved {
+	merged := Resolved{Level: r.Level, Disabled: make(map[string]bool, len(r.Disabled)), Files: append(append([]string(nil), r.Files...), o.Path)}
+	for rule := range r.Disabled {
+		merged.Disabled[rule] = true
+	}
+	if o.Level != "" {
+		merged.Level = o.Level
+	}
+	for _, rule := range o.Disable {
+		merged.Disabled[rule] = true
+	}
+	for _, rule := range o.Enable {
+		delete(merged.Disabled, rule)
+	}
+	return merged
+}
+
+// DisabledRules returns the disabled rules in sorted order.
+func (r Resolved) DisabledRules() []string {
+	names := make([]string, 0, len(r.Disabled))
+	for rule := range r.Disabled {
+		names = append(names, rule)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Loader resolves and caches the overrides of directories. It is safe for concurrent use.
+type Loader struct {
+	mu    sync.Mutex
+	dirs  map[string]resolution
+	files map[string]fileResult
+}
+
+type resolution struct {
+	resolved Resolved
+	err      error
+}
+
+type fileResult struct {
+	override *Override
+	err      error
+}
+
+// NewLoader returns an empty loader.
+func NewLoader() *Loader {
+	return &Loader{dirs: make(map[string]resolution), files: make(map[string]fileResult)}
+}
+
+// ForFile returns the overrides that apply to the source file at path. An error names the
+// override file that could not be read or parsed; the overrides above it still apply.
+func (l *Loader) ForFile(path string) (Resolved, error) {
+	dir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return Resolved{}, nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	res := l.resolve(dir)
+	return res.resolved, res.err
+}
+
+// resolve merges the overrides of dir over those of its parent, stopping at the module root.
+func (l *Loader) resolve(dir string) resolution {
+	if res, ok := l.dirs[dir]; ok {
+		return res
+	}
+	var res resolution
+	parent := filepath.Dir(dir)
+	if parent != dir && !isModuleRoot(dir) {
+		res = l.resolve(parent)
+	}
+	if override, err := l.load(filepath.Join(dir, File)); err != nil {
+		if res.err == nil {
+			res.err = err
+		}
+	} else if override != nil {
+		res.resolved = res.resolved.merge(override)
+	}
+	l.dirs[dir] = res
+	return res
+}
+
+// load reads an override file once; a missing file is not an error.
+func (l *Loader) load(path string) (*Override, error) {
+	if result, ok := l.files[path]; ok {
+		return result.override, result.err
+	}
+	var result fileResult
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		result.override, result.err = Load(path)
+	}
+	l.files[path] = result
+	return result.override, result.err
+}
+
+func isModuleRoot(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, "go.mod"))
+	return err == nil
+}
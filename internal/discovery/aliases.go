@@ -0,0 +1,239 @@
+package discovery
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// factoryAlias is a definition name registered by calling a shared factory with a name
+// argument, e.g. NewWidgetResource("_widget_v2") in the provider's Resources method or
+// "google_foo_v2": ResourceFoo("v2") in a registry map.
+type factoryAlias struct {
+	Kind    registry.ResourceKind
+	Name    string
+	Factory string
+	// Suffix is true when the name is appended to the provider type name (as in Resources
+	// methods) rather than being the full Terraform type name (as in registry map keys)
+	Suffix bool
+}
+
+// applyFactoryNames records the factory function of definitions discovered from their
+// methods: a top-level function that constructs the receiver type, including inside a
+// returned closure (func() resource.Resource { return &widgetResource{name: name} }).
+func applyFactoryNames(file *ast.File, state *DiscoveryState) {
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Recv != nil || funcDecl.Body == nil || funcDecl.Type.Results == nil {
+			continue
+		}
+		ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+			var typeExpr ast.Expr
+			switch node := n.(type) {
+			case *ast.CompositeLit:
+				typeExpr = node.Type
+			case *ast.CallExpr:
+				if ident, ok := node.Fun.(*ast.Ident); ok && ident.Name == "new" && len(node.Args) == 1 {
+					typeExpr = node.Args[0]
+				}
+			}
+			ident, ok := typeExpr.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			index, ok := state.RecvTypeToIndex[ident.Name]
+			if ok && index < len(state.Resources) && state.Resources[index].Factory == "" {
+				state.Resources[index].Factory = funcDecl.Name.Name
+			}
+			return true
+		})
+	}
+}
+
+// ApplyFactoryAliases registers a definition for every name a shared factory is registered
+// under, for providers that build several resources from one implementation by passing the
+// name to the factory. Each alias shares the attributes, directives and import support of
+// the definition the factory constructs; that definition is removed unless it is also
+// registered under its own name, so the provider is neither under- nor over-counted.
+// Registry map entries already registered under their key gain the shared attributes.
+func ApplyFactoryAliases(reg *registry.ResourceRegistry, fset *token.FileSet, files []*ast.File) {
+	var aliases []factoryAlias
+	for _, file := range files {
+		filePath := fset.Position(file.Pos()).Filename
+		if strings.HasSuffix(filePath, "_test.go") {
+			continue
+		}
+		aliases = append(aliases, collectFactoryAliases(file)...)
+	}
+	if len(aliases) == 0 {
+		return
+	}
+
+	bases := make(map[string]*registry.ResourceInfo)
+	for _, def := range reg.GetSortedDefinitions() {
+		key := registry.ResourceKey{Kind: def.Kind, Name: def.Factory}.String()
+		if def.Factory != "" && def.AliasOf == "" && bases[key] == nil {
+			bases[key] = def
+		}
+	}
+
+	providerTypeName := ""
+	if provider := reg.GetProvider(); provider != nil {
+		providerTypeName = provider.TypeName
+	}
+
+	aliased := make(map[*registry.ResourceInfo]bool)
+	keepBase := make(map[*registry.ResourceInfo]bool)
+	for _, alias := range aliases {
+		base := bases[registry.ResourceKey{Kind: alias.Kind, Name: alias.Factory}.String()]
+		if base == nil {
+			continue
+		}
+		name := alias.Name
+		if alias.Suffix {
+			name = aliasSuffixName(name, providerTypeName)
+		}
+		if name == "" {
+			continue
+		}
+		aliased[base] = true
+		if name == base.Name {
+			keepBase[base] = true
+			continue
+		}
+
+		if existing := reg.GetDefinition(alias.Kind, name); existing != nil {
+			if len(existing.Attributes) == 0 {
+				existing.Attributes = base.Attributes
+			}
+			existing.Factory = base.Factory
+			existing.AliasOf = base.Name
+			continue
+		}
+		reg.RegisterResource(&registry.ResourceInfo{
+			Name:           name,
+			Kind:           base.Kind,
+			FilePath:       base.FilePath,
+			SchemaPos:      base.SchemaPos,
+			Attributes:     base.Attributes,
+			HasImportState: base.HasImportState,
+			ImportStatePos: base.ImportStatePos,
+			Directives:     base.Directives,
+			Operations:     base.Operations,
+			DiscoveredBy:   "FactoryAlias",
+			DeferredPos:    base.DeferredPos,
+			Factory:        base.Factory,
+			AliasOf:        base.Name,
+			Methods:        base.Methods,
+			SchemaVersion:  base.SchemaVersion,
+			VersionPos:     base.VersionPos,
+		})
+	}
+
+	for base := range aliased {
+		if !keepBase[base] {
+			reg.UnregisterResource(base.Kind, base.Name)
+		}
+	}
+}
+
+// collectFactoryAliases finds factory calls with a name argument in the Resources and
+// DataSources methods of a provider and in provider registry maps.
+func collectFactoryAliases(file *ast.File) []factoryAlias {
+	var aliases []factoryAlias
+
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Recv == nil || funcDecl.Body == nil {
+			continue
+		}
+		var kind registry.ResourceKind
+		switch funcDecl.Name.Name {
+		case "Resources":
+			kind = registry.KindResource
+		case "DataSources":
+			kind = registry.KindDataSource
+		default:
+			continue
+		}
+		results := funcDecl.Type.Results
+		if results == nil || len(results.List) != 1 {
+			continue
+		}
+		if _, ok := results.List[0].Type.(*ast.ArrayType); !ok {
+			continue
+		}
+
+		add := func(expr ast.Expr) {
+			if factory, name, ok := namedFactoryCall(expr); ok {
+				aliases = append(aliases, factoryAlias{Kind: kind, Name: name, Factory: factory, Suffix: true})
+			}
+		}
+		ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.CompositeLit:
+				for _, elt := range node.Elts {
+					add(elt)
+				}
+			case *ast.CallExpr:
+				if ident, ok := node.Fun.(*ast.Ident); ok && ident.Name == "append" && len(node.Args) > 1 {
+					for _, arg := range node.Args[1:] {
+						add(arg)
+					}
+				}
+			}
+			return true
+		})
+	}
+
+	forEachRegistryMapEntry(file, func(kind registry.ResourceKind, name string, _ *ast.BasicLit, value ast.Expr) {
+		if factory, _, ok := namedFactoryCall(value); ok {
+			aliases = append(aliases, factoryAlias{Kind: kind, Name: name, Factory: factory})
+		}
+	})
+
+	return aliases
+}
+
+// namedFactoryCall reports whether expr calls a factory with a string literal as its first
+// argument, returning the factory function name and the argument.
+func namedFactoryCall(expr ast.Expr) (string, string, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return "", "", false
+	}
+	var factory string
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		factory = fun.Name
+	case *ast.SelectorExpr:
+		factory = fun.Sel.Name
+	default:
+		return "", "", false
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", "", false
+	}
+	name, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", "", false
+	}
+	return factory, name, true
+}
+
+// aliasSuffixName converts a name passed to a factory in a Resources method to a registry
+// name: "_widget" (appended to req.ProviderTypeName) and "example_widget" (a full type name
+// of provider "example") both become "widget".
+func aliasSuffixName(name, providerTypeName string) string {
+	if strings.HasPrefix(name, "_") {
+		return strings.TrimPrefix(name, "_")
+	}
+	if providerTypeName != "" {
+		return strings.TrimPrefix(name, providerTypeName+"_")
+	}
+	return name
+}
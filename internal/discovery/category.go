@@ -0,0 +1,99 @@
+package discovery
+
+import (
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// categoryPrefix starts the doc comment line that names a definition's product area, e.g.
+// "// Category: Networking".
+const categoryPrefix = "category:"
+
+// serviceDirs are directories whose subdirectories each hold one service, as in
+// internal/service/ec2.
+var serviceDirs = map[string]bool{"service": true, "services": true}
+
+// genericDirs are package directories that say nothing about a product area.
+var genericDirs = map[string]bool{
+	"provider": true, "internal": true, "resources": true, "resource": true,
+	"datasources": true, "datasource": true, "data_sources": true, "actions": true,
+}
+
+// ApplyCategories sets the category of each definition from a "Category:" line in the doc
+// comment of its type, or of a function or method it was discovered from:
+//
+//	// Category: Networking
+//	type VPCResource struct{}
+//
+// Definitions without one are put in the service directory they live in (ec2 for
+// internal/service/ec2/vpc.go), or else their package directory, unless its name is
+// generic such as provider or internal.
+func ApplyCategories(reg *registry.ResourceRegistry, fset *token.FileSet, files []*ast.File) {
+	byPath := make(map[string]*ast.File)
+	for _, file := range files {
+		byPath[fset.Position(file.Pos()).Filename] = file
+	}
+	docs := make(map[*ast.File]map[string][]*ast.CommentGroup)
+
+	for _, def := range reg.GetAllDefinitions() {
+		if file := byPath[def.FilePath]; file != nil {
+			if docs[file] == nil {
+				docs[file] = declDocs(file)
+			}
+			for _, name := range resourceDeclNames(file, def) {
+				for _, doc := range docs[file][name] {
+					if category := docCategory(doc); category != "" {
+						def.Category = category
+					}
+				}
+			}
+		}
+		if def.Category == "" {
+			def.Category = categoryFromPath(def.FilePath)
+		}
+	}
+}
+
+// declDocs returns the doc comments of a file by the declaration name they document.
+func declDocs(file *ast.File) map[string][]*ast.CommentGroup {
+	byName := make(map[string][]*ast.CommentGroup)
+	for group, names := range directiveTargets(file) {
+		for _, name := range names {
+			byName[name] = append(byName[name], group)
+		}
+	}
+	return byName
+}
+
+// docCategory returns the value of the "Category:" line of a doc comment, if any.
+func docCategory(doc *ast.CommentGroup) string {
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) > len(categoryPrefix) && strings.EqualFold(line[:len(categoryPrefix)], categoryPrefix) {
+			return strings.TrimSpace(line[len(categoryPrefix):])
+		}
+	}
+	return ""
+}
+
+// categoryFromPath infers a category from the directory of a definition's file.
+func categoryFromPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	dirs := strings.Split(filepath.ToSlash(filepath.Dir(path)), "/")
+	for i := len(dirs) - 2; i >= 0; i-- {
+		if serviceDirs[dirs[i]] {
+			return dirs[i+1]
+		}
+	}
+	dir := dirs[len(dirs)-1]
+	if dir == "" || dir == "." || genericDirs[dir] {
+		return ""
+	}
+	return dir
+}
@@ -0,0 +1,328 @@
+package discovery
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// checkAddressArgs maps the resource.TestCheck* and resource.TestMatch* functions to the
+// indexes of their arguments that are resource addresses. Functions not listed take the
+// address as their first argument.
+var checkAddressArgs = map[string][]int{
+	"TestCheckResourceAttrPair": {0, 2},
+}
+
+// stateCheckAddressArgs maps the statecheck functions that check an attribute of a resource
+// to the indexes of their address arguments. The attribute path follows each address.
+var stateCheckAddressArgs = map[string][]int{
+	"ExpectKnownValue":     {0},
+	"ExpectSensitiveValue": {0},
+	"CompareValuePairs":    {0, 2},
+}
+
+// Import paths of the terraform-plugin-testing packages state checks are built with.
+const (
+	statecheckPackage = "github.com/hashicorp/terraform-plugin-testing/statecheck"
+	tfjsonpathPackage = "github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	knownvaluePackage = "github.com/hashicorp/terraform-plugin-testing/knownvalue"
+)
+
+// resolveStepCheckAddresses fills in the addresses checked by each step's Check and
+// ConfigStateChecks, for the steps whose values are in body. resourceAliases names the
+// terraform-plugin-testing resource package in the file, and defaults to "resource" when nil;
+// imports maps the file's import names to paths, and the statecheck, tfjsonpath and
+// knownvalue packages go by their own names when it is nil. Addresses and attribute keys given as string
+// literals, package string constants (templates) or local variables assigned a single
+// constant string are resolved; others, such as fmt.Sprintf results, are left out and mark
+// the step's checks unresolved.
+func resolveStepCheckAddresses(body *ast.BlockStmt, steps []registry.TestStepInfo, resourceAliases map[string]bool, imports map[string]string, templates map[string]string) {
+	if body == nil {
+		return
+	}
+	if resourceAliases == nil {
+		resourceAliases = map[string]bool{"resource": true}
+	}
+	if imports == nil {
+		imports = map[string]string{"statecheck": statecheckPackage, "tfjsonpath": tfjsonpathPackage, "knownvalue": knownvaluePackage}
+	}
+
+	pending := make(map[token.Pos]int)
+	for i := range steps {
+		if steps[i].HasCheck && steps[i].CheckPos.IsValid() {
+			pending[steps[i].CheckPos] = i
+		}
+		if steps[i].HasConfigStateChecks && steps[i].StateCheckPos.IsValid() {
+			pending[steps[i].StateCheckPos] = i
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	locals := localStringConstants(body, templates)
+	ast.Inspect(body, func(n ast.Node) bool {
+		kv, ok := n.(*ast.KeyValueExpr)
+		if !ok {
+			return true
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok || (key.Name != "Check" && key.Name != "ConfigStateChecks") {
+			return true
+		}
+		i, ok := pending[kv.Value.Pos()]
+		if !ok {
+			return true
+		}
+		var unresolved bool
+		if key.Name == "Check" {
+			steps[i].CheckAddresses, unresolved = checkAddresses(kv.Value, resourceAliases, locals)
+		} else {
+			steps[i].StateCheckAddresses, unresolved = stateCheckAddresses(kv.Value, imports, locals)
+		}
+		steps[i].UnresolvedChecks = steps[i].UnresolvedChecks || unresolved
+		return false
+	})
+}
+
+// checkAddresses returns the statically known addresses passed to resource.TestCheck* and
+// resource.TestMatch* calls in a Check value, in source order, with the attribute key that
+// follows each address, and whether some address or key was not known statically. Module
+// checks (TestCheckModuleResourceAttr...) take a module path first and are skipped.
+func checkAddresses(expr ast.Expr, resourceAliases map[string]bool, constants map[string]string) ([]registry.CheckAddress, bool) {
+	var addresses []registry.CheckAddress
+	unresolved := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		pkg, ok := sel.X.(*ast.Ident)
+		name := sel.Sel.Name
+		if !ok || !resourceAliases[pkg.Name] || !(strings.HasPrefix(name, "TestCheck") || strings.HasPrefix(name, "TestMatch")) ||
+			strings.Contains(name, "Module") {
+			return true
+		}
+		indexes, ok := checkAddressArgs[name]
+		if !ok {
+			indexes = []int{0}
+		}
+		for _, index := range indexes {
+			if index >= len(call.Args) {
+				continue
+			}
+			address, ok := constantString(call.Args[index], constants)
+			if !ok || address == "" {
+				unresolved = true
+				continue
+			}
+			check := registry.CheckAddress{
+				Address:  address,
+				Function: name,
+				Pos:      call.Args[index].Pos(),
+			}
+			if index+1 < len(call.Args) && (strings.Contains(name, "Attr") || strings.HasPrefix(name, "TestCheckTypeSet")) {
+				if check.Attribute, ok = constantString(call.Args[index+1], constants); !ok {
+					unresolved = true
+				}
+			}
+			addresses = append(addresses, check)
+		}
+		return true
+	})
+	return addresses, unresolved
+}
+
+// stateCheckAddresses returns the statically known addresses passed to statecheck functions
+// in a ConfigStateChecks value, in source order, with the first step of the attribute path
+// that follows each address and, for ExpectKnownValue, the knownvalue function of the
+// expected value, and whether some address or path was not known statically.
+func stateCheckAddresses(expr ast.Expr, imports map[string]string, constants map[string]string) ([]registry.CheckAddress, bool) {
+	var addresses []registry.CheckAddress
+	unresolved := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		pkg, ok := sel.X.(*ast.Ident)
+		if !ok || imports[pkg.Name] != statecheckPackage {
+			return true
+		}
+		indexes, ok := stateCheckAddressArgs[sel.Sel.Name]
+		if !ok {
+			return true
+		}
+		for _, index := range indexes {
+			if index+1 >= len(call.Args) {
+				continue
+			}
+			address, ok := constantString(call.Args[index], constants)
+			if !ok || address == "" {
+				unresolved = true
+				continue
+			}
+			attribute, ok := attributePathRoot(call.Args[index+1], imports, constants)
+			if !ok {
+				unresolved = true
+			}
+			check := registry.CheckAddress{
+				Address:   address,
+				Function:  sel.Sel.Name,
+				Pos:       call.Args[index].Pos(),
+				Attribute: attribute,
+			}
+			if sel.Sel.Name == "ExpectKnownValue" && index+2 < len(call.Args) {
+				check.Value = knownValueFunc(call.Args[index+2], imports)
+			}
+			addresses = append(addresses, check)
+		}
+		return true
+	})
+	return addresses, unresolved
+}
+
+// knownValueFunc returns the name of the knownvalue function expr calls, such as
+// "ListSizeExact" for knownvalue.ListSizeExact(2), or "".
+func knownValueFunc(expr ast.Expr, imports map[string]string) string {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return ""
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	if pkg, ok := sel.X.(*ast.Ident); ok && imports[pkg.Name] == knownvaluePackage {
+		return sel.Sel.Name
+	}
+	return ""
+}
+
+// attributePathRoot returns the attribute a tfjsonpath path starts at, such as "tags" for
+// tfjsonpath.New("tags").AtMapKey("env").
+func attributePathRoot(expr ast.Expr, imports map[string]string, constants map[string]string) (string, bool) {
+	for {
+		call, ok := expr.(*ast.CallExpr)
+		if !ok {
+			return "", false
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return "", false
+		}
+		if pkg, ok := sel.X.(*ast.Ident); ok && imports[pkg.Name] == tfjsonpathPackage {
+			if sel.Sel.Name != "New" || len(call.Args) != 1 {
+				return "", false
+			}
+			return constantString(call.Args[0], constants)
+		}
+		expr = sel.X // A step appended to the path, e.g. .AtMapKey("env")
+	}
+}
+
+// localStringConstants extends the package string constants with the local variables and
+// constants of a function body that are assigned exactly one constant string, such as
+// resourceName := "example_widget.test". Variables assigned more than once are dropped, as
+// are package constants they shadow.
+func localStringConstants(body *ast.BlockStmt, templates map[string]string) map[string]string {
+	values := make(map[string]string, len(templates))
+	for name, value := range templates {
+		values[name] = value
+	}
+	assigned := make(map[string]int)
+	record := func(name *ast.Ident, value ast.Expr) {
+		if name.Name == "_" {
+			return
+		}
+		assigned[name.Name]++
+		if s, ok := constantString(value, templates); ok && assigned[name.Name] == 1 {
+			values[name.Name] = s
+		} else {
+			delete(values, name.Name)
+		}
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range node.Lhs {
+				ident, ok := lhs.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				var value ast.Expr
+				if len(node.Rhs) == len(node.Lhs) {
+					value = node.Rhs[i]
+				}
+				record(ident, value)
+			}
+		case *ast.ValueSpec:
+			for i, ident := range node.Names {
+				var value ast.Expr
+				if i < len(node.Values) {
+					value = node.Values[i]
+				}
+				record(ident, value)
+			}
+		}
+		return true
+	})
+	return values
+}
+
+// CheckedResource returns the resource address a check address refers to, without the
+// instance key of a counted or for_each resource (example_widget.test.0 and
+// example_widget.test["a"] both refer to example_widget.test). It returns false for module
+// addresses, which a step's config does not declare directly.
+func CheckedResource(address string) (string, bool) {
+	if strings.HasPrefix(address, "module.") {
+		return "", false
+	}
+	if i := strings.Index(address, "["); i > 0 {
+		address = address[:i]
+	}
+	parts := strings.Split(address, ".")
+	size := 2
+	if parts[0] == "data" {
+		size = 3
+	}
+	if len(parts) < size {
+		return "", false
+	}
+	return strings.Join(parts[:size], "."), true
+}
+
+// ConfigDeclares reports whether a resource address is among the addresses a config
+// declares. Parts of a declared address rendered from values not known statically, such as
+// a name passed to fmt.Sprintf, match any value.
+func ConfigDeclares(declared []string, address string) bool {
+	want := strings.Split(address, ".")
+	for _, candidate := range declared {
+		got := strings.Split(candidate, ".")
+		if len(got) != len(want) {
+			continue
+		}
+		match := true
+		for i := range got {
+			if got[i] != want[i] && !strings.Contains(got[i], dynamicValue) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
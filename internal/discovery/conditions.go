@@ -0,0 +1,183 @@
+package discovery
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// skipMethods are the testing.T methods that skip a test.
+var skipMethods = map[string]bool{"Skip": true, "Skipf": true, "SkipNow": true}
+
+// ConditionalGuards returns the name patterns of the guard helpers of the settings, or
+// config.DefaultConditionalGuards when none are set.
+func ConditionalGuards(settings config.Settings) []string {
+	if len(settings.ConditionalGuards) == 0 {
+		return config.DefaultConditionalGuards()
+	}
+	return settings.ConditionalGuards
+}
+
+// extractConditions returns the guards in a test body under which the test, or some of its
+// steps, is skipped: steps setting SkipFunc, TerraformVersionChecks entries that skip, such
+// as tfversion.SkipBelow, t.Skip calls under a condition, and calls to helpers whose name
+// matches one of guards, such as acctest.PreCheckRegion. A t.Skip guarded only by an
+// environment variable being unset, or by testing.Short, is not a condition: every
+// acceptance test is skipped without credentials.
+func extractConditions(body *ast.BlockStmt, guards []string) []registry.TestCondition {
+	if body == nil {
+		return nil
+	}
+	locals := localEnvVars(body)
+	var conditions []registry.TestCondition
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.KeyValueExpr:
+			key, ok := n.Key.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			switch key.Name {
+			case "SkipFunc":
+				conditions = append(conditions, registry.TestCondition{Kind: registry.ConditionSkipFunc, Detail: skipFuncName(n.Value), Pos: n.Value.Pos()})
+				return false
+			case "TerraformVersionChecks":
+				conditions = append(conditions, versionSkips(n.Value)...)
+				return false
+			}
+		case *ast.IfStmt:
+			if skipsTest(n.Body) && !presenceCheck(n.Cond, locals) {
+				conditions = append(conditions, registry.TestCondition{Kind: registry.ConditionSkip, Detail: types.ExprString(n.Cond), Pos: n.Pos()})
+			}
+		case *ast.CallExpr:
+			if name := calledName(n.Fun); name != "" && matchesAnyGlob(name, guards) {
+				conditions = append(conditions, registry.TestCondition{Kind: registry.ConditionGuard, Detail: types.ExprString(n.Fun), Pos: n.Pos()})
+			}
+		}
+		return true
+	})
+	return conditions
+}
+
+// skipFuncName returns the function a SkipFunc value names, or "" for a function literal.
+func skipFuncName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.FuncLit:
+		return ""
+	case *ast.CallExpr:
+		return types.ExprString(e.Fun)
+	}
+	return types.ExprString(expr)
+}
+
+// versionSkips returns a condition for each entry of a TerraformVersionChecks literal that
+// skips the test, such as tfversion.SkipBelow or tfversion.SkipIf. Entries such as
+// tfversion.RequireAbove fail the test instead and are not conditions.
+func versionSkips(expr ast.Expr) []registry.TestCondition {
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+	var conditions []registry.TestCondition
+	for _, elt := range lit.Elts {
+		call, ok := elt.(*ast.CallExpr)
+		if !ok || !strings.HasPrefix(calledName(call.Fun), "Skip") {
+			continue
+		}
+		conditions = append(conditions, registry.TestCondition{Kind: registry.ConditionTerraformVersion, Detail: types.ExprString(call), Pos: call.Pos()})
+	}
+	return conditions
+}
+
+// skipsTest reports whether a block calls t.Skip, t.Skipf or t.SkipNow, outside of function
+// literals.
+func skipsTest(block *ast.BlockStmt) bool {
+	skips := false
+	ast.Inspect(block, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			if sel, ok := n.Fun.(*ast.SelectorExpr); ok && skipMethods[sel.Sel.Name] {
+				if _, ok := sel.X.(*ast.Ident); ok {
+					skips = true
+				}
+			}
+		}
+		return !skips
+	})
+	return skips
+}
+
+// presenceCheck reports whether a condition only checks that environment variables are set,
+// or that tests run with -short: os.Getenv("X") == "", a local read from os.Getenv compared
+// with "", testing.Short(), and their combinations with || and &&. A test skipped when a
+// variable is set, os.Getenv("X") != "", runs only in some environments, so that is no
+// presence check.
+func presenceCheck(expr ast.Expr, locals map[string]string) bool {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return presenceCheck(e.X, locals)
+	case *ast.BinaryExpr:
+		switch e.Op {
+		case token.LOR, token.LAND:
+			return presenceCheck(e.X, locals) && presenceCheck(e.Y, locals)
+		case token.EQL:
+			return isEmptyString(e.Y) && envValue(e.X, locals) || isEmptyString(e.X) && envValue(e.Y, locals)
+		}
+	case *ast.CallExpr:
+		if sel, ok := e.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Short" {
+			if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "testing" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// envValue reports whether expr is the value of an environment variable: an os.Getenv call
+// or a local assigned from one.
+func envValue(expr ast.Expr, locals map[string]string) bool {
+	if _, ok := envReadCall(expr); ok {
+		return true
+	}
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	_, ok = locals[ident.Name]
+	return ok
+}
+
+// isEmptyString reports whether expr is the string literal "".
+func isEmptyString(expr ast.Expr) bool {
+	value, ok := stringLiteralValue(expr)
+	return ok && value == ""
+}
+
+// calledName returns the name of the function a call calls, without its package or
+// receiver, or "" when it is not a plain or selected identifier.
+func calledName(fun ast.Expr) string {
+	switch f := fun.(type) {
+	case *ast.Ident:
+		return f.Name
+	case *ast.SelectorExpr:
+		return f.Sel.Name
+	}
+	return ""
+}
+
+// matchesAnyGlob reports whether name matches one of the glob patterns.
+func matchesAnyGlob(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
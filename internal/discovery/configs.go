@@ -0,0 +1,352 @@
+package discovery
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// DynamicPlaceholder stands in for a value in a normalized config that is not known statically.
+const DynamicPlaceholder = "<dynamic>"
+
+// configAttrRegex matches a line setting an attribute (`name = ...`) or opening a nested block
+// (`rule {`) inside a resource block.
+var configAttrRegex = regexp.MustCompile(`^\s*([A-Za-z_][A-Za-z0-9_-]*)\s*(?:=[^=]|=$|\{)`)
+
+// configAddressRegex captures the block type, resource type and name of a resource or data block.
+var configAddressRegex = regexp.MustCompile(`(?m)^\s*(resource|data)\s+"([^"]+)"\s+"([^"]+)"\s*\{`)
+
+// maxConfigHelperDepth limits how many nested config helpers are followed when resolving a config.
+const maxConfigHelperDepth = 3
+
+// ConfigUse is a single test step that uses a config.
+type ConfigUse struct {
+	// Test is the test function the step belongs to.
+	Test string
+	// Producer is the config helper or template the test took the config from, or the test
+	// itself for a config written inline.
+	Producer string
+	FilePath string
+	Pos      token.Pos
+}
+
+// ConfigInfo is a distinct HCL config and the test steps that use it.
+type ConfigInfo struct {
+	// Hash identifies the normalized config.
+	Hash string
+	// Config is the normalized config text.
+	Config string
+	// Uses lists the steps using the config, sorted by test name.
+	Uses []ConfigUse
+}
+
+// Producers returns the distinct producers of the config, sorted by name.
+func (c ConfigInfo) Producers() []string {
+	return distinctSorted(c.Uses, func(u ConfigUse) string { return u.Producer })
+}
+
+// Tests returns the distinct tests using the config, sorted by name.
+func (c ConfigInfo) Tests() []string {
+	return distinctSorted(c.Uses, func(u ConfigUse) string { return u.Test })
+}
+
+// ConfigCorpus is the index of distinct configs across all test steps.
+type ConfigCorpus struct {
+	// Configs is sorted by use count, most reused first.
+	Configs []ConfigInfo
+	// Steps is the number of test steps that set Config.
+	Steps int
+	// Unresolved is the number of those steps whose config could not be resolved statically.
+	Unresolved int
+}
+
+// CatalogConfigs indexes the configs set by the test steps of all test functions. A config
+// may be a string literal, a package-level template, a fmt.Sprintf call, or a call to a
+// config helper (followed a few levels deep, with string literal arguments bound to its
+// parameters). Configs are normalized before they are compared: lines are trimmed, blank
+// and comment lines dropped, runs of whitespace collapsed, and values that are not known
+// statically replaced with DynamicPlaceholder.
+func CatalogConfigs(files []*ast.File, fset *token.FileSet) ConfigCorpus {
+	packageTemplates := CollectPackageTemplates(files, fset)
+	funcs := make(map[packageFunc]*ast.FuncDecl)
+	for _, file := range files {
+		filePath := fset.Position(file.Pos()).Filename
+		if !strings.HasSuffix(filePath, "_test.go") {
+			continue
+		}
+		for _, decl := range file.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv == nil && funcDecl.Body != nil {
+				funcs[packageFunc{filepath.Dir(filePath), funcDecl.Name.Name}] = funcDecl
+			}
+		}
+	}
+
+	var corpus ConfigCorpus
+	byHash := make(map[string]*ConfigInfo)
+	for _, file := range files {
+		filePath := fset.Position(file.Pos()).Filename
+		if !strings.HasSuffix(filePath, "_test.go") {
+			continue
+		}
+		// Helpers and templates resolve within the file's package
+		dir := filepath.Dir(filePath)
+		lookup := func(name string) *ast.FuncDecl { return funcs[packageFunc{dir, name}] }
+		templates := packageTemplates[dir]
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv != nil || funcDecl.Body == nil || !strings.HasPrefix(funcDecl.Name.Name, "Test") || funcDecl.Name.Name == "TestMain" {
+				continue
+			}
+			test := funcDecl.Name.Name
+			ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+				kv, ok := n.(*ast.KeyValueExpr)
+				if !ok {
+					return true
+				}
+				if key, ok := kv.Key.(*ast.Ident); !ok || key.Name != "Config" {
+					return true
+				}
+				corpus.Steps++
+				config, producer, ok := resolveConfig(kv.Value, nil, lookup, templates, 0)
+				if !ok {
+					corpus.Unresolved++
+					return true
+				}
+				if producer == "" {
+					producer = test
+				}
+				normalized := normalizeConfig(config)
+				hash := configHash(normalized)
+				info := byHash[hash]
+				if info == nil {
+					info = &ConfigInfo{Hash: hash, Config: normalized}
+					byHash[hash] = info
+				}
+				info.Uses = append(info.Uses, ConfigUse{
+					Test:     test,
+					Producer: producer,
+					FilePath: filePath,
+					Pos:      kv.Value.Pos(),
+				})
+				return true
+			})
+		}
+	}
+
+	for _, info := range byHash {
+		sort.SliceStable(info.Uses, func(i, j int) bool { return info.Uses[i].Test < info.Uses[j].Test })
+		corpus.Configs = append(corpus.Configs, *info)
+	}
+	sort.Slice(corpus.Configs, func(i, j int) bool {
+		a, b := corpus.Configs[i], corpus.Configs[j]
+		if len(a.Uses) != len(b.Uses) {
+			return len(a.Uses) > len(b.Uses)
+		}
+		if a.Uses[0].Test != b.Uses[0].Test {
+			return a.Uses[0].Test < b.Uses[0].Test
+		}
+		return a.Hash < b.Hash
+	})
+	return corpus
+}
+
+// resolveConfig renders a Config value to its text. producer names the outermost config
+// helper or template the value came from, and is empty for an inline literal. Helpers are
+// resolved by name with lookup.
+func resolveConfig(expr ast.Expr, params map[string]string, lookup func(string) *ast.FuncDecl, templates map[string]string, depth int) (config, producer string, ok bool) {
+	if ident, isIdent := expr.(*ast.Ident); isIdent {
+		if value, bound := params[ident.Name]; bound {
+			return value, "", true
+		}
+	}
+	if value, isConst := constantString(expr, templates); isConst {
+		if ident, isIdent := expr.(*ast.Ident); isIdent {
+			producer = ident.Name
+		}
+		return value, producer, true
+	}
+
+	call, isCall := expr.(*ast.CallExpr)
+	if !isCall {
+		return "", "", false
+	}
+	if rendered, isSprintf := renderSprintf(call, params, templates); isSprintf {
+		if ident, isIdent := call.Args[0].(*ast.Ident); isIdent {
+			producer = ident.Name
+		}
+		return rendered, producer, true
+	}
+
+	ident, isIdent := call.Fun.(*ast.Ident)
+	if !isIdent || depth >= maxConfigHelperDepth {
+		return "", "", false
+	}
+	helper := lookup(ident.Name)
+	if helper == nil {
+		return "", "", false
+	}
+	result := singleReturnValue(helper)
+	if result == nil {
+		return "", "", false
+	}
+	config, _, ok = resolveConfig(result, stringArguments(helper, call.Args), lookup, templates, depth+1)
+	return config, ident.Name, ok
+}
+
+// resolveStepConfigAttributes fills in the attributes and block addresses of each step's
+// config, for the steps whose Config value resolves statically (see CatalogConfigs), and the
+// error category of its ExpectError steps. Helpers are resolved by name with lookup.
+func resolveStepConfigAttributes(body *ast.BlockStmt, steps []registry.TestStepInfo, lookup func(string) *ast.FuncDecl, templates map[string]string) {
+	if body == nil {
+		return
+	}
+
+	pending := make(map[token.Pos]int)
+	for i := range steps {
+		if steps[i].HasConfig && steps[i].ConfigPos.IsValid() {
+			pending[steps[i].ConfigPos] = i
+		}
+	}
+	configs := make(map[int]string)
+	ast.Inspect(body, func(n ast.Node) bool {
+		if len(pending) == 0 {
+			return false
+		}
+		kv, ok := n.(*ast.KeyValueExpr)
+		if !ok {
+			return true
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok || key.Name != "Config" {
+			return true
+		}
+		i, ok := pending[kv.Value.Pos()]
+		if !ok {
+			return true
+		}
+		if config, _, resolved := resolveConfig(kv.Value, nil, lookup, templates, 0); resolved {
+			configs[i] = config
+			steps[i].ConfigAttributes = configAttributeScans.get(config)
+			steps[i].ConfigAddresses = configAddressScans.get(config)
+			steps[i].ConfigValues = fixtureValueScans.get(config)
+		}
+		return true
+	})
+	classifyExpectErrors(steps, configs)
+}
+
+// configAddresses returns the addresses of the resource and data blocks declared in a config.
+// A config without such blocks yields an empty, non-nil slice.
+func configAddresses(config string) []string {
+	addresses := []string{}
+	for _, m := range configAddressRegex.FindAllStringSubmatch(config, -1) {
+		address := m[2] + "." + m[3]
+		if m[1] == "data" {
+			address = "data." + address
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses
+}
+
+// configAttributes maps each resource type declared in a config to the top-level attributes
+// and nested blocks set by every block of that type. A config with no resource blocks yields
+// an empty, non-nil map.
+func configAttributes(config string) map[string][]string {
+	counts := make(map[string]int)
+	sets := make(map[string]map[string]int)
+	for _, match := range resourceBlockRegex.FindAllStringSubmatchIndex(config, -1) {
+		resourceType := config[match[2]:match[3]]
+		counts[resourceType]++
+		if sets[resourceType] == nil {
+			sets[resourceType] = make(map[string]int)
+		}
+
+		seen := make(map[string]bool)
+		depth, lineDepth := 1, 1
+		lineStart := match[1]
+		for i := match[1]; i < len(config) && depth > 0; i++ {
+			switch config[i] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+			case '\n':
+				if lineDepth == 1 {
+					if m := configAttrRegex.FindStringSubmatch(config[lineStart:i]); m != nil && !seen[m[1]] {
+						seen[m[1]] = true
+						sets[resourceType][m[1]]++
+					}
+				}
+				lineStart, lineDepth = i+1, depth
+			}
+		}
+	}
+
+	attributes := make(map[string][]string, len(counts))
+	for resourceType, count := range counts {
+		names := []string{}
+		for name, n := range sets[resourceType] {
+			if n == count {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		attributes[resourceType] = names
+	}
+	return attributes
+}
+
+// singleReturnValue returns the value of a function whose body ends in a single-value return.
+func singleReturnValue(funcDecl *ast.FuncDecl) ast.Expr {
+	if len(funcDecl.Body.List) == 0 {
+		return nil
+	}
+	ret, ok := funcDecl.Body.List[len(funcDecl.Body.List)-1].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return nil
+	}
+	return ret.Results[0]
+}
+
+// normalizeConfig trims lines, drops blank and comment lines, collapses runs of whitespace,
+// and replaces values that are not known statically with DynamicPlaceholder.
+func normalizeConfig(config string) string {
+	config = strings.ReplaceAll(config, dynamicValue, DynamicPlaceholder)
+	var lines []string
+	for _, line := range strings.Split(config, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// configHash returns a short hash of a normalized config.
+func configHash(normalized string) string {
+	hash := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(hash[:8])
+}
+
+// distinctSorted returns the distinct non-empty keys of uses, sorted.
+func distinctSorted(uses []ConfigUse, key func(ConfigUse) string) []string {
+	seen := make(map[string]bool)
+	var values []string
+	for _, use := range uses {
+		if value := key(use); value != "" && !seen[value] {
+			seen[value] = true
+			values = append(values, value)
+		}
+	}
+	sort.Strings(values)
+	return values
+}
@@ -0,0 +1,119 @@
+package discovery
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// commentedTestFunc matches the signature of a commented-out acceptance test.
+	commentedTestFunc = regexp.MustCompile(`func\s+(TestAcc\w*)\s*\(`)
+	// commentedTestCall matches a commented-out call that runs an acceptance test.
+	commentedTestCall = regexp.MustCompile(`\bresource\.(?:Parallel|Unit)?Test\s*\(`)
+)
+
+// CommentedOutTest is a block of comments in a test file that contains an acceptance test
+// function or a resource.Test call.
+type CommentedOutTest struct {
+	Pos   token.Pos
+	Lines int
+	// Names lists the TestAcc functions declared in the block; it is empty when the block only
+	// holds a resource.Test call.
+	Names []string
+}
+
+// FindCommentedOutTests returns the commented-out acceptance tests in a file that span at
+// least minLines lines (at least one). Comment groups separated only by blank lines are treated as one
+// block, since commenting out code line by line often leaves its blank lines uncommented.
+// Doc comments of declarations are never part of a block.
+func FindCommentedOutTests(file *ast.File, fset *token.FileSet, minLines int) []CommentedOutTest {
+	if minLines < 1 {
+		minLines = 1
+	}
+
+	docs := make(map[*ast.CommentGroup]bool)
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Doc != nil {
+				docs[d.Doc] = true
+			}
+		case *ast.GenDecl:
+			if d.Doc != nil {
+				docs[d.Doc] = true
+			}
+		}
+	}
+
+	// Positions of code, used to tell whether two comment groups are separated by code
+	var code []token.Pos
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n.(type) {
+		case nil, *ast.CommentGroup, *ast.Comment:
+			return false
+		}
+		code = append(code, n.Pos(), n.End())
+		return true
+	})
+	sort.Slice(code, func(i, j int) bool { return code[i] < code[j] })
+
+	var result []CommentedOutTest
+	var block []*ast.CommentGroup
+	flush := func() {
+		if len(block) == 0 {
+			return
+		}
+		pos := block[0].Pos()
+		start := fset.Position(pos).Line
+		end := fset.Position(block[len(block)-1].End()).Line
+		var text strings.Builder
+		for _, group := range block {
+			for _, c := range group.List {
+				text.WriteString(c.Text)
+				text.WriteByte('\n')
+			}
+		}
+		block = nil
+
+		lines := end - start + 1
+		if lines < minLines {
+			return
+		}
+		content := text.String()
+		var names []string
+		for _, m := range commentedTestFunc.FindAllStringSubmatch(content, -1) {
+			names = append(names, m[1])
+		}
+		if len(names) == 0 && !commentedTestCall.MatchString(content) {
+			return
+		}
+		result = append(result, CommentedOutTest{Pos: pos, Lines: lines, Names: names})
+	}
+
+	for _, group := range file.Comments {
+		if docs[group] || group.Pos() < file.Package {
+			flush()
+			continue
+		}
+		if len(block) > 0 && !onlyBlankLinesBetween(fset, code, block[len(block)-1], group) {
+			flush()
+		}
+		block = append(block, group)
+	}
+	flush()
+	return result
+}
+
+// onlyBlankLinesBetween reports whether nothing but blank lines separates two comment
+// groups: no code lies between them and they are at most two lines apart. code holds the
+// sorted start and end positions of the file's syntax nodes.
+func onlyBlankLinesBetween(fset *token.FileSet, code []token.Pos, prev, next *ast.CommentGroup) bool {
+	i := sort.Search(len(code), func(i int) bool { return code[i] >= prev.End() })
+	if i < len(code) && code[i] < next.Pos() {
+		return false
+	}
+	return fset.Position(next.Pos()).Line-fset.Position(prev.End()).Line <= 2
+}
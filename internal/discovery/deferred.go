@@ -0,0 +1,87 @@
+package discovery
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// deferredResponsePos returns the position where a framework method sets the Deferred field of
+// its response (resp.Deferred = &resource.Deferred{...}), which advertises that the provider
+// can defer changes when Terraform allows it. It returns token.NoPos when the method never does.
+func deferredResponsePos(funcDecl *ast.FuncDecl) token.Pos {
+	if funcDecl.Body == nil || funcDecl.Type.Params == nil || len(funcDecl.Type.Params.List) == 0 {
+		return token.NoPos
+	}
+	// The response is the last parameter: (ctx, req, resp)
+	params := funcDecl.Type.Params.List
+	names := params[len(params)-1].Names
+	if len(names) == 0 {
+		return token.NoPos
+	}
+	respName := names[len(names)-1].Name
+
+	pos := token.NoPos
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		if pos.IsValid() {
+			return false
+		}
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok {
+			return true
+		}
+		for _, lhs := range assign.Lhs {
+			sel, ok := lhs.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "Deferred" {
+				continue
+			}
+			if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == respName {
+				pos = assign.Pos()
+			}
+		}
+		return true
+	})
+	return pos
+}
+
+// applyDeferredResponses records where resources discovered from their Schema method defer
+// changes in ModifyPlan (resource.ResourceWithModifyPlan).
+func applyDeferredResponses(file *ast.File, state *DiscoveryState) {
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Recv == nil || funcDecl.Name.Name != "ModifyPlan" {
+			continue
+		}
+		index, ok := state.RecvTypeToIndex[getReceiverTypeName(funcDecl.Recv)]
+		if !ok || index >= len(state.Resources) {
+			continue
+		}
+		resource := state.Resources[index]
+		if resource.Kind != registry.KindResource {
+			continue
+		}
+		resource.DeferredPos = deferredResponsePos(funcDecl)
+	}
+}
+
+// allowsDeferral reports whether a step's AdditionalCLIOptions value sets AllowDeferral: true
+// for plan or apply (resource.PlanOptions, resource.ApplyOptions).
+func allowsDeferral(expr ast.Expr) bool {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		kv, ok := n.(*ast.KeyValueExpr)
+		if !ok || found {
+			return !found
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok || key.Name != "AllowDeferral" {
+			return true
+		}
+		if value, ok := kv.Value.(*ast.Ident); ok && value.Name == "true" {
+			found = true
+		}
+		return true
+	})
+	return found
+}
@@ -0,0 +1,365 @@
+package discovery
+
+import (
+	"go/ast"
+	"go/token"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/matching"
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// directivePrefix introduces a coverage directive comment, e.g. "//tftest:expect import".
+const directivePrefix = "tftest:"
+
+// Directive verbs.
+const (
+	directiveExpect   = "expect"
+	directiveExempt   = "exempt"
+	directiveMaturity = "maturity"
+)
+
+// directiveOptionReason names the only option accepted by //tftest:exempt.
+const directiveOptionReason = "reason"
+
+// directive is a single parsed //tftest: comment.
+type directive struct {
+	pos    token.Pos
+	verb   string
+	checks []string
+	reason string
+	level  string // Maturity level of a //tftest:maturity directive
+}
+
+// ApplyDirectives reads //tftest:expect, //tftest:exempt and //tftest:maturity comments from
+// a file and records them on the resources they document. A directive documents a type, a
+// function, or a method (in which case it applies to the receiver type). The grammar is:
+//
+//	//tftest:expect <check>[,<check>...]
+//	//tftest:exempt <check>[,<check>...] [reason="..."]
+//	//tftest:maturity experimental|beta|ga
+//
+// It returns an error for every directive that is malformed, names an unknown check or
+// option, or does not document a discovered resource.
+func ApplyDirectives(file *ast.File, resources []*registry.ResourceInfo) []registry.DirectiveError {
+	var errs []registry.DirectiveError
+
+	targets := directiveTargets(file)
+	byName := make(map[string][]*directive)
+	var all []*directive
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			d, parseErrs, ok := parseDirective(comment)
+			if !ok {
+				continue
+			}
+			errs = append(errs, parseErrs...)
+			if d == nil {
+				continue
+			}
+			all = append(all, d)
+			for _, name := range targets[group] {
+				byName[name] = append(byName[name], d)
+			}
+		}
+	}
+	if len(all) == 0 {
+		return errs
+	}
+
+	used := make(map[*directive]bool)
+	for _, resource := range resources {
+		seen := make(map[*directive]bool)
+		for _, name := range resourceDeclNames(file, resource) {
+			for _, d := range byName[name] {
+				if seen[d] {
+					continue
+				}
+				seen[d] = true
+				used[d] = true
+				errs = append(errs, applyDirective(resource, d)...)
+			}
+		}
+	}
+
+	for _, d := range all {
+		if !used[d] {
+			errs = append(errs, registry.DirectiveError{
+				Pos:       d.pos,
+				Directive: d.verb,
+				Problem:   registry.DirectiveUnattached,
+			})
+		}
+	}
+
+	return errs
+}
+
+// applyDirective merges a parsed directive into a resource's coverage directives.
+func applyDirective(resource *registry.ResourceInfo, d *directive) []registry.DirectiveError {
+	var errs []registry.DirectiveError
+
+	if d.verb == directiveMaturity {
+		resource.Maturity = d.level
+		return nil
+	}
+
+	if resource.Directives == nil {
+		resource.Directives = &registry.CoverageDirectives{
+			Pos:    d.pos,
+			Expect: make(map[string]bool),
+			Exempt: make(map[string]string),
+		}
+	}
+	directives := resource.Directives
+
+	for _, check := range d.checks {
+		if !registry.CheckApplies(resource.Kind, check) {
+			errs = append(errs, registry.DirectiveError{
+				Pos:        d.pos,
+				Directive:  d.verb,
+				Problem:    registry.DirectiveInapplicable,
+				Value:      check,
+				Target:     resource.Name,
+				TargetKind: resource.Kind,
+			})
+			continue
+		}
+
+		conflict := false
+		switch d.verb {
+		case directiveExpect:
+			conflict = directives.Exempts(check)
+			directives.Expect[check] = true
+		case directiveExempt:
+			conflict = directives.Expects(check)
+			directives.Exempt[check] = d.reason
+		}
+		if conflict {
+			errs = append(errs, registry.DirectiveError{
+				Pos:       d.pos,
+				Directive: d.verb,
+				Problem:   registry.DirectiveConflict,
+				Value:     check,
+				Target:    resource.Name,
+			})
+		}
+	}
+
+	return errs
+}
+
+// parseDirective parses a single comment. ok is false when the comment is not a directive;
+// d is nil when the directive is too malformed to apply.
+func parseDirective(comment *ast.Comment) (d *directive, errs []registry.DirectiveError, ok bool) {
+	if !strings.HasPrefix(comment.Text, "//") {
+		return nil, nil, false
+	}
+	text := strings.TrimSpace(comment.Text[2:])
+	if !strings.HasPrefix(text, directivePrefix) {
+		return nil, nil, false
+	}
+	text = text[len(directivePrefix):]
+
+	verb, args := text, ""
+	if i := strings.IndexAny(text, " \t"); i >= 0 {
+		verb, args = text[:i], text[i+1:]
+	}
+	newErr := func(problem registry.DirectiveProblem, value, suggestion string) registry.DirectiveError {
+		return registry.DirectiveError{
+			Pos:        comment.Pos(),
+			Directive:  verb,
+			Problem:    problem,
+			Value:      value,
+			Suggestion: suggestion,
+		}
+	}
+
+	if verb != directiveExpect && verb != directiveExempt && verb != directiveMaturity {
+		return nil, []registry.DirectiveError{
+			newErr(registry.DirectiveUnknown, verb, closestSpelling(verb, []string{directiveExpect, directiveExempt, directiveMaturity})),
+		}, true
+	}
+
+	tokens, err := splitDirectiveArgs(args)
+	if err != "" {
+		return nil, []registry.DirectiveError{newErr(registry.DirectiveMalformed, err, "")}, true
+	}
+
+	if verb == directiveMaturity {
+		if len(tokens) != 1 {
+			return nil, []registry.DirectiveError{newErr(registry.DirectiveMalformed, "expected a single maturity level", "")}, true
+		}
+		level := strings.ToLower(tokens[0])
+		if !slices.Contains(registry.MaturityLevels(), level) {
+			return nil, []registry.DirectiveError{
+				newErr(registry.DirectiveUnknownLevel, tokens[0], closestSpelling(level, registry.MaturityLevels())),
+			}, true
+		}
+		return &directive{pos: comment.Pos(), verb: verb, level: level}, nil, true
+	}
+
+	if len(tokens) == 0 || strings.Contains(tokens[0], "=") {
+		return nil, []registry.DirectiveError{newErr(registry.DirectiveMissingChecks, "", "")}, true
+	}
+
+	d = &directive{pos: comment.Pos(), verb: verb}
+	known := registry.DirectiveChecks()
+	for _, check := range strings.Split(tokens[0], ",") {
+		check = strings.TrimSpace(check)
+		if check == "" {
+			continue
+		}
+		if !slices.Contains(known, check) {
+			errs = append(errs, newErr(registry.DirectiveUnknownCheck, check, closestSpelling(check, known)))
+			continue
+		}
+		d.checks = append(d.checks, check)
+	}
+
+	for _, option := range tokens[1:] {
+		key, value, found := strings.Cut(option, "=")
+		if !found {
+			errs = append(errs, newErr(registry.DirectiveMalformed, option, ""))
+			continue
+		}
+		if verb != directiveExempt || key != directiveOptionReason {
+			suggestion := ""
+			if verb == directiveExempt {
+				suggestion = closestSpelling(key, []string{directiveOptionReason})
+			}
+			errs = append(errs, newErr(registry.DirectiveUnknownOption, key, suggestion))
+			continue
+		}
+		if strings.HasPrefix(value, `"`) {
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				errs = append(errs, newErr(registry.DirectiveMalformed, option, ""))
+				continue
+			}
+			value = unquoted
+		}
+		d.reason = value
+	}
+
+	if len(d.checks) == 0 {
+		return nil, errs, true
+	}
+	return d, errs, true
+}
+
+// splitDirectiveArgs splits directive arguments on whitespace, keeping double-quoted values
+// (which may contain spaces and escapes) intact. It returns a description of the problem if
+// a quote is left unterminated.
+func splitDirectiveArgs(args string) ([]string, string) {
+	var tokens []string
+	var current strings.Builder
+	inQuotes := false
+	for i := 0; i < len(args); i++ {
+		c := args[i]
+		switch {
+		case inQuotes && c == '\\' && i+1 < len(args):
+			current.WriteByte(c)
+			i++
+			current.WriteByte(args[i])
+		case c == '"':
+			inQuotes = !inQuotes
+			current.WriteByte(c)
+		case !inQuotes && (c == ' ' || c == '\t'):
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteByte(c)
+		}
+	}
+	if inQuotes {
+		return nil, "unterminated quote"
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens, ""
+}
+
+// directiveTargets maps each declaration doc comment to the names it documents. Type
+// declarations document their type names, functions their own name, and methods their
+// receiver type.
+func directiveTargets(file *ast.File) map[*ast.CommentGroup][]string {
+	targets := make(map[*ast.CommentGroup][]string)
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			if d.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range d.Specs {
+				typeSpec, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				if d.Doc != nil {
+					targets[d.Doc] = append(targets[d.Doc], typeSpec.Name.Name)
+				}
+				if typeSpec.Doc != nil && typeSpec.Doc != d.Doc {
+					targets[typeSpec.Doc] = append(targets[typeSpec.Doc], typeSpec.Name.Name)
+				}
+			}
+		case *ast.FuncDecl:
+			if d.Doc == nil {
+				continue
+			}
+			if d.Recv != nil {
+				if recvType := getReceiverTypeName(d.Recv); recvType != "" {
+					targets[d.Doc] = append(targets[d.Doc], recvType)
+				}
+				continue
+			}
+			targets[d.Doc] = append(targets[d.Doc], d.Name.Name)
+		}
+	}
+	return targets
+}
+
+// resourceDeclNames returns the declaration names a resource was discovered from: the
+// receiver type of the method at its schema position, or the function there together with
+// the type it returns.
+func resourceDeclNames(file *ast.File, resource *registry.ResourceInfo) []string {
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || resource.SchemaPos < funcDecl.Pos() || resource.SchemaPos >= funcDecl.End() {
+			continue
+		}
+		if funcDecl.Recv != nil {
+			if recvType := getReceiverTypeName(funcDecl.Recv); recvType != "" {
+				return []string{recvType}
+			}
+			return nil
+		}
+		names := []string{funcDecl.Name.Name}
+		if funcDecl.Body != nil {
+			if returned := extractReturnedTypeName(funcDecl.Body); returned != "" {
+				names = append(names, returned)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// closestSpelling returns the option nearest to word when it is a plausible typo of it.
+func closestSpelling(word string, options []string) string {
+	best := ""
+	bestDistance := 3 // Suggest only within two edits
+	for _, option := range options {
+		distance := matching.LevenshteinDistance(strings.ToLower(word), option)
+		if distance < bestDistance && distance < len(option) {
+			best = option
+			bestDistance = distance
+		}
+	}
+	return best
+}
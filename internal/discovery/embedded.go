@@ -0,0 +1,427 @@
+package discovery
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/naming"
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// embeddedBase is a struct type, often generic, whose Metadata method builds the type name
+// from something the embedding definition supplies: one of its own fields, set when the
+// definition is constructed, or a method of its type argument.
+//
+//	func (b *baseResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+//		resp.TypeName = req.ProviderTypeName + "_" + b.typeName
+//	}
+type embeddedBase struct {
+	kind   registry.ResourceKind
+	field  string // Receiver field holding the name, e.g. "typeName"
+	method string // Method of the type argument returning the name, e.g. "TypeSuffix"
+	suffix bool   // The name is appended to req.ProviderTypeName rather than a full type name
+}
+
+// embeddingType is a struct type embedding an embeddedBase.
+type embeddingType struct {
+	name     string
+	file     string
+	pos      token.Pos
+	base     string // Name of the embedded base type
+	typeArgs []ast.Expr
+}
+
+// ApplyEmbeddedMetadata discovers definitions whose Metadata method is inherited from an
+// embedded base struct, recovering the type name from the constructor or the base's type
+// argument:
+//
+//	type WidgetResource struct{ baseResource }
+//
+//	func NewWidgetResource() resource.Resource {
+//		return &WidgetResource{baseResource: baseResource{typeName: "widget"}}
+//	}
+//
+// The field may also be set through a call, such as newBaseResource("widget"), whose first
+// string argument is taken as the name, and a generic base may call a method of its type
+// argument (Base[widgetModel] with func (widgetModel) TypeSuffix() string { return "_widget" }).
+// A definition already discovered from the embedding type's Schema method under a name
+// derived from the type is renamed, definitions discovered from the base's own Schema
+// method are removed, and embedding types discovered by no other strategy are added. Files
+// are scanned together since bases usually live in a file of their own.
+func ApplyEmbeddedMetadata(reg *registry.ResourceRegistry, fset *token.FileSet, files []*ast.File, names *naming.Pipeline) {
+	var sources []*ast.File
+	for _, file := range files {
+		if !strings.HasSuffix(fset.Position(file.Pos()).Filename, "_test.go") {
+			sources = append(sources, file)
+		}
+	}
+
+	methods := make(map[string]map[string]*ast.FuncDecl)
+	for _, file := range sources {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+				continue
+			}
+			recvType := baseTypeName(funcDecl.Recv.List[0].Type)
+			if methods[recvType] == nil {
+				methods[recvType] = make(map[string]*ast.FuncDecl)
+			}
+			methods[recvType][funcDecl.Name.Name] = funcDecl
+		}
+	}
+
+	bases := make(map[string]embeddedBase)
+	for typeName, byName := range methods {
+		if metadata := byName["Metadata"]; metadata != nil {
+			if base, ok := parseEmbeddedBase(metadata); ok {
+				bases[typeName] = base
+			}
+		}
+	}
+	if len(bases) == 0 {
+		return
+	}
+
+	embeddings := findEmbeddingTypes(sources, fset, bases)
+	embedded := make(map[string]bool)
+	for _, embedding := range embeddings {
+		embedded[embedding.base] = true
+	}
+	for _, def := range reg.GetAllDefinitions() {
+		for typeName := range embedded {
+			if schema := methods[typeName]["Schema"]; schema != nil && def.SchemaPos == schema.Pos() {
+				reg.UnregisterResource(def.Kind, def.Name)
+			}
+		}
+	}
+
+	for _, embedding := range embeddings {
+		if methods[embedding.name]["Metadata"] != nil {
+			continue // Its own Metadata method is handled by MetadataMethodStrategy
+		}
+		base := bases[embedding.base]
+		value := ""
+		if base.field != "" {
+			value = embeddedFieldValue(sources, embedding, base.field)
+		} else if len(embedding.typeArgs) > 0 {
+			if method := methods[baseTypeName(embedding.typeArgs[0])][base.method]; method != nil {
+				value = returnedString(method)
+			}
+		}
+		name := embeddedTypeName(value, base.suffix)
+		if name == "" {
+			continue
+		}
+
+		schema := methods[embedding.name]["Schema"]
+		if def := typeNamedDefinition(reg, embedding, schema, names); def != nil {
+			if (def.Name == name && def.Kind == base.kind) || reg.GetDefinition(base.kind, name) != nil {
+				continue
+			}
+			reg.UnregisterResource(def.Kind, def.Name)
+			def.Name = name
+			def.Kind = base.kind
+			def.DiscoveredBy = "EmbeddedMetadata"
+			reg.RegisterResource(def)
+			continue
+		}
+		if reg.GetDefinition(base.kind, name) != nil {
+			continue
+		}
+
+		def := &registry.ResourceInfo{
+			Name:         name,
+			Kind:         base.kind,
+			FilePath:     embedding.file,
+			SchemaPos:    embedding.pos,
+			DiscoveredBy: "EmbeddedMetadata",
+		}
+		if schema == nil {
+			schema = methods[embedding.base]["Schema"]
+		}
+		if schema != nil && schema.Body != nil {
+			for _, attr := range extractAttributes(schema.Body) {
+				if attr != nil {
+					def.Attributes = append(def.Attributes, *attr)
+				}
+			}
+			def.Blocks = extractBlocks(schema.Body)
+			def.SchemaVersion, def.VersionPos = schemaVersion(schema.Body)
+		}
+		reg.RegisterResource(def)
+	}
+}
+
+// typeNamedDefinition returns the definition other strategies discovered for an embedding
+// type, from its Schema method or under the name derived from the type (e.g., by the
+// ReturnType strategy from its constructor), or nil.
+func typeNamedDefinition(reg *registry.ResourceRegistry, embedding embeddingType, schema *ast.FuncDecl, names *naming.Pipeline) *registry.ResourceInfo {
+	derived := extractResourceName(names, embedding.name)
+	for _, def := range reg.GetAllDefinitions() {
+		if schema != nil && def.SchemaPos == schema.Pos() {
+			return def
+		}
+		if def.FilePath == embedding.file && def.Name == derived && def.AliasOf == "" {
+			return def
+		}
+	}
+	return nil
+}
+
+// parseEmbeddedBase reports whether a Metadata method builds resp.TypeName from a receiver
+// field or from a method of a value that is not the receiver, request or response (a type
+// parameter), and which kind of definition its response type belongs to.
+func parseEmbeddedBase(metadata *ast.FuncDecl) (embeddedBase, bool) {
+	var base embeddedBase
+	kind, ok := metadataKind(metadata)
+	if !ok || metadata.Body == nil {
+		return base, false
+	}
+	base.kind = kind
+
+	recvName := ""
+	if names := metadata.Recv.List[0].Names; len(names) > 0 {
+		recvName = names[0].Name
+	}
+	params := map[string]bool{}
+	for _, field := range metadata.Type.Params.List {
+		for _, name := range field.Names {
+			params[name.Name] = true
+		}
+	}
+
+	for _, stmt := range metadata.Body.List {
+		assign, ok := stmt.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			continue
+		}
+		if lhs, ok := assign.Lhs[0].(*ast.SelectorExpr); !ok || lhs.Sel.Name != "TypeName" {
+			continue
+		}
+		ast.Inspect(assign.Rhs[0], func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.SelectorExpr:
+				ident, ok := node.X.(*ast.Ident)
+				switch {
+				case node.Sel.Name == "ProviderTypeName":
+					base.suffix = true
+				case ok && recvName != "" && ident.Name == recvName && base.field == "":
+					base.field = node.Sel.Name
+				}
+			case *ast.CallExpr:
+				sel, ok := node.Fun.(*ast.SelectorExpr)
+				if !ok || len(node.Args) > 0 {
+					return true
+				}
+				if ident, ok := sel.X.(*ast.Ident); ok && ident.Name != recvName && !params[ident.Name] && base.method == "" {
+					base.method = sel.Sel.Name
+					return false
+				}
+			}
+			return true
+		})
+		break
+	}
+	if base.method != "" && base.field == "" {
+		return base, true
+	}
+	base.method = ""
+	return base, base.field != ""
+}
+
+// metadataKind returns the kind a Metadata method describes, from the package of its
+// response parameter (e.g., *resource.MetadataResponse).
+func metadataKind(metadata *ast.FuncDecl) (registry.ResourceKind, bool) {
+	for _, field := range metadata.Type.Params.List {
+		star, ok := field.Type.(*ast.StarExpr)
+		if !ok {
+			continue
+		}
+		sel, ok := star.X.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "MetadataResponse" {
+			continue
+		}
+		if pkg, ok := sel.X.(*ast.Ident); ok {
+			return registry.KindFromPackage(pkg.Name)
+		}
+	}
+	return 0, false
+}
+
+// findEmbeddingTypes returns the struct types that embed one of bases.
+func findEmbeddingTypes(files []*ast.File, fset *token.FileSet, bases map[string]embeddedBase) []embeddingType {
+	var types []embeddingType
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				typeSpec, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				st, ok := typeSpec.Type.(*ast.StructType)
+				if !ok || bases[typeSpec.Name.Name] != (embeddedBase{}) {
+					continue
+				}
+				for _, field := range st.Fields.List {
+					if len(field.Names) > 0 {
+						continue
+					}
+					name := baseTypeName(field.Type)
+					if _, ok := bases[name]; !ok {
+						continue
+					}
+					types = append(types, embeddingType{
+						name:     typeSpec.Name.Name,
+						file:     fset.Position(typeSpec.Pos()).Filename,
+						pos:      typeSpec.Pos(),
+						base:     name,
+						typeArgs: typeArgs(field.Type),
+					})
+					break
+				}
+			}
+		}
+	}
+	return types
+}
+
+// embeddedFieldValue returns the string a composite literal of the embedding type gives
+// the base's name field, directly or as the first string argument of a constructor call.
+func embeddedFieldValue(files []*ast.File, embedding embeddingType, field string) string {
+	value := ""
+	for _, file := range files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			lit, ok := n.(*ast.CompositeLit)
+			if !ok || value != "" {
+				return value == ""
+			}
+			if lit.Type == nil || baseTypeName(lit.Type) != embedding.name {
+				return true
+			}
+			for i, elt := range lit.Elts {
+				var baseValue ast.Expr
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					if key, ok := kv.Key.(*ast.Ident); ok && key.Name == embedding.base {
+						baseValue = kv.Value
+					}
+				} else if i == 0 {
+					baseValue = elt
+				}
+				if baseValue != nil {
+					value = baseFieldString(baseValue, field)
+					break
+				}
+			}
+			return value == ""
+		})
+		if value != "" {
+			break
+		}
+	}
+	return value
+}
+
+// baseFieldString returns the name set for a base value: the named field of a composite
+// literal, its first string element, or the first string argument of a call.
+func baseFieldString(expr ast.Expr, field string) string {
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		expr = unary.X
+	}
+	var candidates []ast.Expr
+	switch value := expr.(type) {
+	case *ast.CompositeLit:
+		for _, elt := range value.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				if key, ok := kv.Key.(*ast.Ident); ok && key.Name == field {
+					return stringLiteral(kv.Value)
+				}
+				continue
+			}
+			candidates = append(candidates, elt)
+		}
+	case *ast.CallExpr:
+		candidates = value.Args
+	}
+	for _, candidate := range candidates {
+		if s := stringLiteral(candidate); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// returnedString returns the string literal a method returns, or "".
+func returnedString(method *ast.FuncDecl) string {
+	if method.Body == nil || len(method.Body.List) != 1 {
+		return ""
+	}
+	ret, ok := method.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return ""
+	}
+	return stringLiteral(ret.Results[0])
+}
+
+// stringLiteral returns the value of a string literal, or "".
+func stringLiteral(expr ast.Expr) string {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return ""
+	}
+	s, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+// embeddedTypeName converts the value a base builds its type name from into a registry
+// name: a suffix of the provider type name ("_widget" or "widget") or a full type name
+// ("example_widget"), both "widget".
+func embeddedTypeName(value string, suffix bool) string {
+	if suffix {
+		return strings.TrimPrefix(value, "_")
+	}
+	if idx := strings.Index(value, "_"); idx > 0 {
+		return value[idx+1:]
+	}
+	return value
+}
+
+// baseTypeName returns the name of a possibly pointer, qualified or instantiated type, e.g.
+// "Base" for *pkg.Base[T].
+func baseTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return baseTypeName(t.X)
+	case *ast.IndexExpr:
+		return baseTypeName(t.X)
+	case *ast.IndexListExpr:
+		return baseTypeName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// typeArgs returns the type arguments of an instantiated type, e.g. [T] for *Base[T].
+func typeArgs(expr ast.Expr) []ast.Expr {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return typeArgs(t.X)
+	case *ast.IndexExpr:
+		return []ast.Expr{t.Index}
+	case *ast.IndexListExpr:
+		return t.Indices
+	}
+	return nil
+}
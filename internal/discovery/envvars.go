@@ -0,0 +1,198 @@
+package discovery
+
+import (
+	"go/ast"
+	"go/token"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// envReadMethods are the os functions that read an environment variable.
+var envReadMethods = map[string]bool{"Getenv": true, "LookupEnv": true}
+
+// envVarName matches the conventional names of environment variables, so messages passed to
+// t.Skip and the like are not taken for variable names.
+var envVarName = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// maxEnvHelperDepth bounds how deep helper calls are followed from a Config or PreCheck.
+const maxEnvHelperDepth = 3
+
+// resolveTestEnvVars records the environment variables a test interpolates into its step
+// configs and those its PreCheck checks. Config values are inspected for os.Getenv calls,
+// directly, through local variables assigned from them, or in the helpers that build the
+// config. A PreCheck checks a variable it reads or passes by name to a helper; one that calls
+// a function of a non-standard package cannot be followed and is marked unresolved.
+func resolveTestEnvVars(fn *registry.TestFunctionInfo, body *ast.BlockStmt, imports map[string]string, lookup func(string) *ast.FuncDecl) {
+	if body == nil {
+		return
+	}
+	locals := localEnvVars(body)
+
+	seen := make(map[string]bool)
+	checked := make(map[string]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		kv, ok := n.(*ast.KeyValueExpr)
+		if !ok {
+			return true
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		switch key.Name {
+		case "Config":
+			for _, use := range configEnvVars(kv.Value, locals, lookup) {
+				if !seen[use.Name] {
+					seen[use.Name] = true
+					fn.ConfigEnvVars = append(fn.ConfigEnvVars, use)
+				}
+			}
+		case "PreCheck":
+			if !preCheckEnvVars(kv.Value, imports, lookup, checked, make(map[string]bool), 0) {
+				fn.PreCheckUnresolved = true
+			}
+		}
+		return true
+	})
+	fn.PreCheckEnvVars = slices.Sorted(maps.Keys(checked))
+}
+
+// localEnvVars maps the local variables of a function assigned from os.Getenv, such as
+// region := os.Getenv("EXAMPLE_REGION"), to the variable read.
+func localEnvVars(body *ast.BlockStmt) map[string]string {
+	locals := make(map[string]string)
+	ast.Inspect(body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Rhs) != 1 {
+			return true
+		}
+		if name, ok := envReadCall(assign.Rhs[0]); ok {
+			if ident, ok := assign.Lhs[0].(*ast.Ident); ok && ident.Name != "_" {
+				locals[ident.Name] = name
+			}
+		}
+		return true
+	})
+	return locals
+}
+
+// configEnvVars returns the environment variables read in a Config value, each at the position
+// of the read, or of the value when the read is in a helper.
+func configEnvVars(expr ast.Expr, locals map[string]string, lookup func(string) *ast.FuncDecl) []registry.EnvVarUse {
+	var uses []registry.EnvVarUse
+	visited := make(map[string]bool)
+	var inspect func(node ast.Node, pos token.Pos, depth int)
+	inspect = func(node ast.Node, pos token.Pos, depth int) {
+		ast.Inspect(node, func(n ast.Node) bool {
+			switch e := n.(type) {
+			case *ast.CallExpr:
+				if name, ok := envReadCall(e); ok {
+					at := pos
+					if !at.IsValid() {
+						at = e.Pos()
+					}
+					uses = append(uses, registry.EnvVarUse{Name: name, Pos: at})
+					return false
+				}
+				ident, ok := e.Fun.(*ast.Ident)
+				if !ok || depth >= maxEnvHelperDepth || visited[ident.Name] {
+					return true
+				}
+				if funcDecl := lookup(ident.Name); funcDecl != nil && funcDecl.Body != nil {
+					visited[ident.Name] = true
+					helperPos := pos
+					if !helperPos.IsValid() {
+						helperPos = e.Pos()
+					}
+					inspect(funcDecl.Body, helperPos, depth+1)
+				}
+			case *ast.Ident:
+				if name, ok := locals[e.Name]; ok && depth == 0 {
+					uses = append(uses, registry.EnvVarUse{Name: name, Pos: e.Pos()})
+				}
+			}
+			return true
+		})
+	}
+	inspect(expr, token.NoPos, 0)
+	return uses
+}
+
+// preCheckEnvVars adds the environment variables a PreCheck value checks to checked: those it
+// reads and the string literals it passes to calls, which helpers such as
+// testAccPreCheckEnv(t, "EXAMPLE_ORG_ID") check by name. It returns false when the PreCheck
+// calls a function of a non-standard package, whose checks cannot be seen.
+func preCheckEnvVars(node ast.Node, imports map[string]string, lookup func(string) *ast.FuncDecl, checked, visited map[string]bool, depth int) bool {
+	resolved := true
+	ast.Inspect(node, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if name, ok := envReadCall(call); ok {
+			checked[name] = true
+			return false
+		}
+		for _, arg := range call.Args {
+			if lit, ok := arg.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				if value, err := strconv.Unquote(lit.Value); err == nil && envVarName.MatchString(value) {
+					checked[value] = true
+				}
+			}
+		}
+		switch fun := call.Fun.(type) {
+		case *ast.Ident:
+			if visited[fun.Name] {
+				return true
+			}
+			visited[fun.Name] = true
+			if funcDecl := lookup(fun.Name); funcDecl != nil && funcDecl.Body != nil {
+				if depth >= maxEnvHelperDepth || !preCheckEnvVars(funcDecl.Body, imports, lookup, checked, visited, depth+1) {
+					resolved = false
+				}
+			}
+		case *ast.SelectorExpr:
+			if pkg, ok := fun.X.(*ast.Ident); ok {
+				if importPath, ok := imports[pkg.Name]; ok && !standardLibrary(importPath) {
+					resolved = false
+				}
+			}
+		}
+		return true
+	})
+	return resolved
+}
+
+// envReadCall returns the variable an os.Getenv or os.LookupEnv call with a string literal
+// argument reads.
+func envReadCall(expr ast.Expr) (string, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return "", false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !envReadMethods[sel.Sel.Name] {
+		return "", false
+	}
+	if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != "os" {
+		return "", false
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	name, err := strconv.Unquote(lit.Value)
+	return name, err == nil && name != ""
+}
+
+// standardLibrary reports whether an import path belongs to the standard library, whose
+// first element has no dot.
+func standardLibrary(importPath string) bool {
+	first, _, _ := strings.Cut(importPath, "/")
+	return !strings.Contains(first, ".")
+}
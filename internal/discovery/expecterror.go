@@ -0,0 +1,191 @@
+package discovery
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// expectErrorPattern returns the regular expression an ExpectError value compiles, when it
+// is statically known: a regexp.MustCompile or regexp.Compile call whose argument is a
+// string literal or a concatenation of string literals.
+func expectErrorPattern(expr ast.Expr) (string, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return "", false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || (sel.Sel.Name != "MustCompile" && sel.Sel.Name != "Compile") {
+		return "", false
+	}
+	return stringLiteralValue(call.Args[0])
+}
+
+// stringLiteralValue evaluates a string literal or a "+" concatenation of string literals.
+func stringLiteralValue(expr ast.Expr) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+		value, err := strconv.Unquote(e.Value)
+		if err != nil {
+			return "", false
+		}
+		return value, true
+	case *ast.ParenExpr:
+		return stringLiteralValue(e.X)
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return "", false
+		}
+		left, ok := stringLiteralValue(e.X)
+		if !ok {
+			return "", false
+		}
+		right, ok := stringLiteralValue(e.Y)
+		if !ok {
+			return "", false
+		}
+		return left + right, true
+	default:
+		return "", false
+	}
+}
+
+// errorKeyword maps a phrase of an ExpectError pattern to the error category it suggests.
+type errorKeyword struct {
+	phrase   string
+	category registry.ErrorCategory
+}
+
+// errorKeywords are matched against a normalized ExpectError pattern in order, so specific
+// phrases come before the general ones they contain: "invalid credentials" is an auth error,
+// "conflicts with" the schema's ConflictsWith rather than an API conflict.
+var errorKeywords = []errorKeyword{
+	{"invalid credentials", registry.ErrorCategoryAuth},
+	{"invalid token", registry.ErrorCategoryAuth},
+	{"invalid api key", registry.ErrorCategoryAuth},
+	{"unauthorized", registry.ErrorCategoryAuth},
+	{"unauthenticated", registry.ErrorCategoryAuth},
+	{"not authorized", registry.ErrorCategoryAuth},
+	{"authentication", registry.ErrorCategoryAuth},
+	{"forbidden", registry.ErrorCategoryAuth},
+	{"access denied", registry.ErrorCategoryAuth},
+	{"permission denied", registry.ErrorCategoryAuth},
+	{"401", registry.ErrorCategoryAuth},
+	{"403", registry.ErrorCategoryAuth},
+	{"conflicts with", registry.ErrorCategoryValidation},
+	{"conflicting configuration", registry.ErrorCategoryValidation},
+	{"already exists", registry.ErrorCategoryConflict},
+	{"already in use", registry.ErrorCategoryConflict},
+	{"already taken", registry.ErrorCategoryConflict},
+	{"duplicate", registry.ErrorCategoryConflict},
+	{"conflict", registry.ErrorCategoryConflict},
+	{"409", registry.ErrorCategoryConflict},
+	{"not found", registry.ErrorCategoryNotFound},
+	{"does not exist", registry.ErrorCategoryNotFound},
+	{"no such", registry.ErrorCategoryNotFound},
+	{"404", registry.ErrorCategoryNotFound},
+	{"invalid", registry.ErrorCategoryValidation},
+	{"inappropriate value", registry.ErrorCategoryValidation},
+	{"unsupported argument", registry.ErrorCategoryValidation},
+	{"missing required", registry.ErrorCategoryValidation},
+	{"required", registry.ErrorCategoryValidation},
+	{"must be", registry.ErrorCategoryValidation},
+	{"must not", registry.ErrorCategoryValidation},
+	{"must contain", registry.ErrorCategoryValidation},
+	{"cannot be", registry.ErrorCategoryValidation},
+	{"expected", registry.ErrorCategoryValidation},
+	{"validation", registry.ErrorCategoryValidation},
+	{"not a valid", registry.ErrorCategoryValidation},
+	{"at least", registry.ErrorCategoryValidation},
+	{"at most", registry.ErrorCategoryValidation},
+	{"one of", registry.ErrorCategoryValidation},
+}
+
+// patternSeparatorRegex matches the regular expression syntax that usually stands for a space
+// between the words of an error message, e.g. \s+, .* or an escaped space.
+var patternSeparatorRegex = regexp.MustCompile(`\\s[*+?]?|\.[*+?]|\\ |\s+`)
+
+// credentialAttributes are provider arguments whose value in a config suggests a test of
+// rejected credentials.
+var credentialAttributes = map[string]bool{
+	"token": true, "api_key": true, "api_token": true, "access_key": true, "secret_key": true,
+	"password": true, "credentials": true, "client_secret": true,
+}
+
+// classifyExpectErrors sets the error category of each ExpectError step from its pattern and,
+// by index, its resolved config.
+func classifyExpectErrors(steps []registry.TestStepInfo, configs map[int]string) {
+	for i := range steps {
+		if steps[i].ExpectError {
+			steps[i].ErrorCategory = classifyExpectError(steps[i].ExpectErrorPattern, configs[i])
+		}
+	}
+}
+
+// classifyExpectError returns the kind of error an ExpectError step targets. The pattern
+// decides when it names the error; otherwise a config that declares two blocks of a type with
+// the same hard-coded name targets a conflict, and one that sets credentials in a provider
+// block targets an auth error.
+func classifyExpectError(pattern, config string) registry.ErrorCategory {
+	normalized := strings.ToLower(patternSeparatorRegex.ReplaceAllString(pattern, " "))
+	for _, keyword := range errorKeywords {
+		if strings.Contains(normalized, keyword.phrase) {
+			return keyword.category
+		}
+	}
+	switch {
+	case declaresDuplicateNames(config):
+		return registry.ErrorCategoryConflict
+	case setsProviderCredentials(config):
+		return registry.ErrorCategoryAuth
+	}
+	return registry.ErrorCategoryOther
+}
+
+// declaresDuplicateNames reports whether two resource blocks of the same type in a config set
+// a name attribute (name or *_name) to the same hard-coded value.
+func declaresDuplicateNames(config string) bool {
+	seen := make(map[registry.FixtureValue]bool)
+	for _, value := range parseFixtureValues(config) {
+		if value.Attribute != "name" && !strings.HasSuffix(value.Attribute, "_name") {
+			continue
+		}
+		if seen[value] {
+			return true
+		}
+		seen[value] = true
+	}
+	return false
+}
+
+// setsProviderCredentials reports whether a provider block in a config sets a credential
+// argument (see credentialAttributes).
+func setsProviderCredentials(config string) bool {
+	for _, match := range providerBlockRegex.FindAllStringIndex(config, -1) {
+		depth := 1
+		lineStart := match[1]
+		for i := match[1]; i < len(config) && depth > 0; i++ {
+			switch config[i] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+			case '\n':
+				if depth == 1 {
+					if m := configAttrRegex.FindStringSubmatch(config[lineStart:i]); m != nil && credentialAttributes[m[1]] {
+						return true
+					}
+				}
+				lineStart = i + 1
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package discovery
+
+import (
+	"go/ast"
+	"go/types"
+	"slices"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// testRunners are the functions of the resource package that run a TestCase.
+var testRunners = map[string]bool{"Test": true, "ParallelTest": true, "UnitTest": true}
+
+// extractTestCaseFactories records the provider factory fields of each resource.TestCase
+// literal in body and of the step literals in its Steps, and the ErrorCheck of each
+// TestCase. A TestCase is complete when it is
+// passed directly to a test runner of the resource package and all its steps are literals,
+// so no field can be set where discovery does not look.
+func extractTestCaseFactories(body *ast.BlockStmt, resourceAliases map[string]bool) []registry.TestCaseFactories {
+	if body == nil {
+		return nil
+	}
+	if resourceAliases == nil {
+		resourceAliases = map[string]bool{"resource": true}
+	}
+
+	run := make(map[*ast.CompositeLit]bool) // TestCase literals passed to a runner
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || !testRunners[sel.Sel.Name] {
+			return true
+		}
+		if ident, ok := sel.X.(*ast.Ident); ok && resourceAliases[ident.Name] {
+			for _, arg := range call.Args {
+				if lit, ok := arg.(*ast.CompositeLit); ok {
+					run[lit] = true
+				}
+			}
+		}
+		return true
+	})
+
+	var cases []registry.TestCaseFactories
+	ast.Inspect(body, func(n ast.Node) bool {
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok || !hasTestCaseArg(lit, resourceAliases) {
+			return true
+		}
+		tc := registry.TestCaseFactories{Pos: lit.Pos(), Complete: run[lit], Direct: run[lit]}
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			key, ok := kv.Key.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			if key.Name == "ErrorCheck" {
+				tc.ErrorCheck, tc.ErrorCheckPos = errorCheckHelper(kv.Value), kv.Pos()
+				continue
+			}
+			if key.Name != "Steps" {
+				tc.Fields = appendFactoryField(tc.Fields, kv, 0)
+				continue
+			}
+			steps, ok := kv.Value.(*ast.CompositeLit)
+			if !ok {
+				tc.Complete = false
+				continue
+			}
+			for _, s := range steps.Elts {
+				step, ok := s.(*ast.CompositeLit)
+				if !ok {
+					tc.Complete = false
+					continue
+				}
+				tc.Steps++
+				for _, field := range step.Elts {
+					if kv, ok := field.(*ast.KeyValueExpr); ok {
+						tc.Fields = appendFactoryField(tc.Fields, kv, tc.Steps)
+					}
+				}
+			}
+		}
+		cases = append(cases, tc)
+		return true
+	})
+	return cases
+}
+
+// errorCheckHelper returns the function an ErrorCheck value calls to build the check, such as
+// acctest.ErrorCheck in acctest.ErrorCheck(t, names.EC2ServiceID), or the function it names;
+// "func" for a function literal.
+func errorCheckHelper(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.FuncLit:
+		return "func"
+	case *ast.CallExpr:
+		return types.ExprString(e.Fun)
+	}
+	return types.ExprString(expr)
+}
+
+// appendFactoryField appends the field kv sets to fields when it is a provider factory field.
+func appendFactoryField(fields []registry.ProviderFactoryField, kv *ast.KeyValueExpr, step int) []registry.ProviderFactoryField {
+	key, ok := kv.Key.(*ast.Ident)
+	if !ok || !slices.Contains(registry.ProviderFactoryFields, key.Name) {
+		return fields
+	}
+	return append(fields, registry.ProviderFactoryField{
+		Name:  key.Name,
+		Value: types.ExprString(kv.Value),
+		Step:  step,
+		Pos:   kv.Pos(),
+	})
+}
@@ -0,0 +1,303 @@
+package discovery
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// dynamicValue stands in for a format argument whose value is not known statically.
+const dynamicValue = "\x00"
+
+// resourceBlockRegex finds the opening of a resource block in an HCL config, capturing its type.
+var resourceBlockRegex = regexp.MustCompile(`resource\s+"([^"]+)"\s+"[^"]+"\s*\{`)
+
+// fixtureAttrRegex captures a string attribute assignment on a single HCL line.
+var fixtureAttrRegex = regexp.MustCompile(`^\s*([A-Za-z_][A-Za-z0-9_]*)\s*=\s*"((?:[^"\\]|\\.)*)"\s*$`)
+
+// usesParallelTest reports whether a test function runs in parallel, either through
+// resource.ParallelTest or by calling t.Parallel().
+func usesParallelTest(body *ast.BlockStmt, resourceAliases map[string]bool) bool {
+	if body == nil {
+		return false
+	}
+	if resourceAliases == nil {
+		resourceAliases = map[string]bool{"resource": true}
+	}
+
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return !found
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		ident, ok := sel.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		if (sel.Sel.Name == "ParallelTest" && resourceAliases[ident.Name]) ||
+			(sel.Sel.Name == "Parallel" && len(call.Args) == 0 && !resourceAliases[ident.Name]) {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// serialReasonRegex matches comments that document how a test runs, such as
+// "// Serial: modifies account-wide settings" or "// Cannot run in parallel with ...".
+var serialReasonRegex = regexp.MustCompile(`(?i)\b(serial\w*|sequential\w*|parallel\w*)\b`)
+
+// serialReason returns the first comment in the doc comment or body of a test function that
+// explains why it runs serially, or "" when there is none.
+func serialReason(file *ast.File, funcDecl *ast.FuncDecl) string {
+	groups := []*ast.CommentGroup{funcDecl.Doc}
+	for _, group := range file.Comments {
+		if funcDecl.Body != nil && group.Pos() > funcDecl.Body.Lbrace && group.End() < funcDecl.Body.Rbrace {
+			groups = append(groups, group)
+		}
+	}
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+		if text := strings.TrimSpace(group.Text()); serialReasonRegex.MatchString(text) {
+			return text
+		}
+	}
+	return ""
+}
+
+// extractFixtureValues returns the hard-coded string attributes of resource blocks in the
+// HCL configs a test uses. Configs built with fmt.Sprintf, directly or through a helper, are
+// rendered with the literal arguments of the call; attributes whose value depends on a
+// non-literal argument (e.g., a random suffix) are left out.
+func extractFixtureValues(body *ast.BlockStmt, lookup func(string) *ast.FuncDecl, templates map[string]string) []registry.FixtureValue {
+	if body == nil {
+		return nil
+	}
+
+	seen := make(map[registry.FixtureValue]bool)
+	for _, config := range renderedConfigs(body, lookup, templates) {
+		for _, value := range fixtureValueScans.get(config) {
+			seen[value] = true
+		}
+	}
+	if len(seen) == 0 {
+		return nil
+	}
+
+	values := make([]registry.FixtureValue, 0, len(seen))
+	for value := range seen {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		a, b := values[i], values[j]
+		if a.ResourceType != b.ResourceType {
+			return a.ResourceType < b.ResourceType
+		}
+		if a.Attribute != b.Attribute {
+			return a.Attribute < b.Attribute
+		}
+		return a.Value < b.Value
+	})
+	return values
+}
+
+// renderedConfigs collects the configs a test body uses: string literals, referenced templates,
+// and fmt.Sprintf calls rendered with their arguments, in the body and in the helpers it calls
+// (one level deep, with string literal arguments bound to the helper's parameters).
+func renderedConfigs(body *ast.BlockStmt, lookup func(string) *ast.FuncDecl, templates map[string]string) []string {
+	var configs []string
+	collect := func(node ast.Node, params map[string]string) {
+		ast.Inspect(node, func(n ast.Node) bool {
+			switch e := n.(type) {
+			case *ast.BasicLit:
+				if e.Kind == token.STRING {
+					if value, err := strconv.Unquote(e.Value); err == nil {
+						configs = append(configs, value)
+					}
+				}
+			case *ast.Ident:
+				if content, ok := templates[e.Name]; ok {
+					configs = append(configs, content)
+				}
+			case *ast.CallExpr:
+				if rendered, ok := renderSprintf(e, params, templates); ok {
+					configs = append(configs, rendered)
+				}
+			}
+			return true
+		})
+	}
+
+	collect(body, nil)
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		ident, ok := call.Fun.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		if helper := lookup(ident.Name); helper != nil && helper.Body != nil {
+			collect(helper.Body, stringArguments(helper, call.Args))
+		}
+		return true
+	})
+	return configs
+}
+
+// renderSprintf renders a fmt.Sprintf call whose format is a string literal or template.
+// Arguments that are neither literals nor bound parameters render as dynamicValue.
+func renderSprintf(call *ast.CallExpr, params map[string]string, templates map[string]string) (string, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Sprintf" || len(call.Args) == 0 {
+		return "", false
+	}
+	if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != "fmt" {
+		return "", false
+	}
+
+	var format string
+	switch f := call.Args[0].(type) {
+	case *ast.BasicLit:
+		value, err := strconv.Unquote(f.Value)
+		if f.Kind != token.STRING || err != nil {
+			return "", false
+		}
+		format = value
+	case *ast.Ident:
+		content, ok := templates[f.Name]
+		if !ok {
+			return "", false
+		}
+		format = content
+	default:
+		return "", false
+	}
+
+	args := make([]string, 0, len(call.Args)-1)
+	for _, arg := range call.Args[1:] {
+		value := dynamicValue
+		switch a := arg.(type) {
+		case *ast.BasicLit:
+			if a.Kind == token.STRING {
+				if unquoted, err := strconv.Unquote(a.Value); err == nil {
+					value = unquoted
+				}
+			} else {
+				value = a.Value
+			}
+		case *ast.Ident:
+			if bound, ok := params[a.Name]; ok {
+				value = bound
+			}
+		}
+		args = append(args, value)
+	}
+	return renderFormat(format, args), true
+}
+
+// renderFormat substitutes args into a Printf-style format. Flags and widths are ignored,
+// %q quotes its argument, and explicit argument indexes (%[2]s) are honored.
+func renderFormat(format string, args []string) string {
+	var out strings.Builder
+	next := 0
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		if c != '%' || i+1 >= len(format) {
+			out.WriteByte(c)
+			continue
+		}
+		i++
+		if format[i] == '%' {
+			out.WriteByte('%')
+			continue
+		}
+
+		index := -1
+		if format[i] == '[' {
+			if end := strings.IndexByte(format[i:], ']'); end > 0 {
+				if n, err := strconv.Atoi(format[i+1 : i+end]); err == nil {
+					index = n - 1
+				}
+				i += end + 1
+			}
+		}
+		for i < len(format) && strings.IndexByte("+-# 0123456789.", format[i]) >= 0 {
+			i++
+		}
+		if i >= len(format) {
+			break
+		}
+		verb := format[i]
+
+		if index < 0 {
+			index = next
+		}
+		next = index + 1
+		value := dynamicValue
+		if index < len(args) {
+			value = args[index]
+		}
+		if verb == 'q' && value != dynamicValue {
+			value = strconv.Quote(value)
+		}
+		out.WriteString(value)
+	}
+	return out.String()
+}
+
+// parseFixtureValues returns the statically known string attributes set directly inside
+// every resource block of an HCL config.
+func parseFixtureValues(config string) []registry.FixtureValue {
+	var values []registry.FixtureValue
+	for _, match := range resourceBlockRegex.FindAllStringSubmatchIndex(config, -1) {
+		resourceType := config[match[2]:match[3]]
+		depth := 1
+		lineStart := match[1]
+		for i := match[1]; i < len(config) && depth > 0; i++ {
+			switch config[i] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+			case '\n':
+				if depth == 1 {
+					if value, ok := fixtureValue(resourceType, config[lineStart:i]); ok {
+						values = append(values, value)
+					}
+				}
+				lineStart = i + 1
+			}
+		}
+	}
+	return values
+}
+
+// fixtureValue parses a single `attr = "value"` line, rejecting interpolated values.
+func fixtureValue(resourceType, line string) (registry.FixtureValue, bool) {
+	m := fixtureAttrRegex.FindStringSubmatch(line)
+	if m == nil {
+		return registry.FixtureValue{}, false
+	}
+	value, err := strconv.Unquote(`"` + m[2] + `"`)
+	if err != nil {
+		value = m[2]
+	}
+	if value == "" || strings.Contains(value, dynamicValue) || strings.Contains(value, "${") || strings.Contains(value, "%") {
+		return registry.FixtureValue{}, false
+	}
+	return registry.FixtureValue{ResourceType: resourceType, Attribute: m[1], Value: value}, true
+}
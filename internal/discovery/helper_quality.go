@@ -0,0 +1,126 @@
+package discovery
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// HelperMisuseKind classifies a misuse of *testing.T found in a test helper.
+type HelperMisuseKind int
+
+const (
+	// HelperFatalInGoroutine is a call to t.Fatal, t.FailNow, t.Skip or a variant from a
+	// goroutine the helper starts. These only stop the goroutine that runs the test, so the
+	// test carries on (or the call panics once the test has returned).
+	HelperFatalInGoroutine HelperMisuseKind = iota
+	// HelperIgnoredError is an error result discarded with the blank identifier, such as
+	// _ = client.CreateFixture(), so a failed setup goes unnoticed.
+	HelperIgnoredError
+)
+
+// HelperMisuse is one misuse of *testing.T in a test helper.
+type HelperMisuse struct {
+	Kind   HelperMisuseKind
+	Helper string    // Name of the helper function
+	Call   string    // The offending call, e.g. "t.Fatal" or "client.CreateFixture"
+	Pos    token.Pos // Position of the call
+}
+
+// goexitMethods are the *testing.T methods that call runtime.Goexit and so must be called
+// from the goroutine running the test.
+var goexitMethods = map[string]bool{
+	"Fatal": true, "Fatalf": true, "FailNow": true,
+	"Skip": true, "Skipf": true, "SkipNow": true,
+}
+
+// FindHelperMisuse returns the misuses of *testing.T in the local helpers of a test file:
+// top-level functions other than tests that accept a *testing.T or testing.TB. A helper that
+// calls t.Fatal from a goroutine, or discards the error of a setup call, weakens every test
+// built on it without failing any of them.
+func FindHelperMisuse(file *ast.File) []HelperMisuse {
+	var misuses []HelperMisuse
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Recv != nil || funcDecl.Body == nil || strings.HasPrefix(funcDecl.Name.Name, "Test") {
+			continue
+		}
+		param := testingParam(funcDecl)
+		if param == "" {
+			continue
+		}
+		helper := funcDecl.Name.Name
+
+		ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.GoStmt:
+				ast.Inspect(node.Call, func(n ast.Node) bool {
+					call, ok := n.(*ast.CallExpr)
+					if !ok {
+						return true
+					}
+					if sel, ok := call.Fun.(*ast.SelectorExpr); ok && goexitMethods[sel.Sel.Name] {
+						if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == param {
+							misuses = append(misuses, HelperMisuse{Kind: HelperFatalInGoroutine, Helper: helper, Call: param + "." + sel.Sel.Name, Pos: call.Pos()})
+						}
+					}
+					return true
+				})
+				return false
+			case *ast.AssignStmt:
+				if len(node.Rhs) != 1 {
+					return true
+				}
+				call, ok := node.Rhs[0].(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				if last, ok := node.Lhs[len(node.Lhs)-1].(*ast.Ident); ok && last.Name == "_" && returnsError(call) {
+					misuses = append(misuses, HelperMisuse{Kind: HelperIgnoredError, Helper: helper, Call: types.ExprString(call.Fun), Pos: call.Pos()})
+				}
+			}
+			return true
+		})
+	}
+	return misuses
+}
+
+// testingParam returns the name of a function's *testing.T or testing.TB parameter, or "".
+func testingParam(funcDecl *ast.FuncDecl) string {
+	for _, field := range funcDecl.Type.Params.List {
+		expr := field.Type
+		if star, ok := expr.(*ast.StarExpr); ok {
+			expr = star.X
+		}
+		sel, ok := expr.(*ast.SelectorExpr)
+		if !ok || (sel.Sel.Name != "T" && sel.Sel.Name != "TB") {
+			continue
+		}
+		if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "testing" && len(field.Names) == 1 {
+			return field.Names[0].Name
+		}
+	}
+	return ""
+}
+
+// returnsError reports whether a call, whose result is discarded, likely returns an error.
+// Without type information, calls that discard a result other than an error are excluded:
+// type conversions and builtins, and the fmt printing functions whose results are usually
+// ignored.
+func returnsError(call *ast.CallExpr) bool {
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		switch fun.Name {
+		case "append", "len", "cap", "make", "new", "copy", "recover", "string", "int", "int64", "float64", "bool":
+			return false
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := fun.X.(*ast.Ident); ok && pkg.Name == "fmt" {
+			return false
+		}
+	case *ast.FuncLit, *ast.ArrayType, *ast.MapType:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,225 @@
+package discovery
+
+import (
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// Reasons a function is detected as a test case helper.
+const (
+	HelperReturnsTestCase = "returns resource.TestCase"
+	HelperAcceptsTestCase = "accepts resource.TestCase"
+	HelperAcceptsSteps    = "accepts test steps"
+	HelperRunsTest        = "runs resource.Test"
+)
+
+// HelperPackage is a scanned package exporting functions that build or run acceptance test
+// cases, such as an internal acctest package, whose calls in tests count as resource.Test.
+type HelperPackage struct {
+	Name    string // Package name, e.g. "acctest"
+	Dir     string
+	Helpers []DetectedHelper // Sorted by name
+}
+
+// DetectedHelper is an exported function of a helper package.
+type DetectedHelper struct {
+	Name string
+	// Qualified lists the names tests call it by, "acctest.NewTestCase", and under each
+	// alias the scanned tests import its package as
+	Qualified []string
+	File      string
+	Pos       token.Pos
+	Reason    string // Why it was detected, one of the Helper* reasons
+	// UsedBy lists the test functions calling it, sorted by name
+	UsedBy []string
+}
+
+// DetectHelperPackages finds the packages among files exporting functions that return a
+// resource.TestCase, accept one or a list of resource.TestStep, or take a *testing.T and
+// run resource.Test, ParallelTest or UnitTest. Test files are not searched for helpers:
+// exported functions of _test.go files cannot be called from other packages.
+func DetectHelperPackages(files []*ast.File, fset *token.FileSet) []HelperPackage {
+	// Aliases test files import packages under, keyed by the last element of the import path
+	aliases := make(map[string]map[string]bool)
+	for _, file := range files {
+		if !strings.HasSuffix(fset.Position(file.Pos()).Filename, "_test.go") {
+			continue
+		}
+		for _, imp := range file.Imports {
+			path, err := strconv.Unquote(imp.Path.Value)
+			if err != nil || imp.Name == nil || imp.Name.Name == "_" || imp.Name.Name == "." {
+				continue
+			}
+			base := path[strings.LastIndex(path, "/")+1:]
+			if aliases[base] == nil {
+				aliases[base] = make(map[string]bool)
+			}
+			aliases[base][imp.Name.Name] = true
+		}
+	}
+
+	packages := make(map[string]*HelperPackage)
+	for _, file := range files {
+		filePath := fset.Position(file.Pos()).Filename
+		pkgName := file.Name.Name
+		if strings.HasSuffix(filePath, "_test.go") || pkgName == "main" {
+			continue
+		}
+		resourceAliases := ExtractResourcePackageAliases(file)
+		if len(resourceAliases) == 0 {
+			continue
+		}
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv != nil || !funcDecl.Name.IsExported() {
+				continue
+			}
+			reason := testCaseHelperReason(funcDecl, resourceAliases)
+			if reason == "" {
+				continue
+			}
+			dir := filepath.Dir(filePath)
+			pkg := packages[dir]
+			if pkg == nil {
+				pkg = &HelperPackage{Name: pkgName, Dir: dir}
+				packages[dir] = pkg
+			}
+			qualifiers := []string{pkgName}
+			for alias := range aliases[filepath.Base(dir)] {
+				if alias != pkgName {
+					qualifiers = append(qualifiers, alias)
+				}
+			}
+			sort.Strings(qualifiers[1:])
+			helper := DetectedHelper{Name: funcDecl.Name.Name, File: filePath, Pos: funcDecl.Pos(), Reason: reason}
+			for _, q := range qualifiers {
+				helper.Qualified = append(helper.Qualified, q+"."+helper.Name)
+			}
+			pkg.Helpers = append(pkg.Helpers, helper)
+		}
+	}
+	if len(packages) == 0 {
+		return nil
+	}
+
+	// Record the tests calling each helper
+	callers := make(map[string]map[string]bool)
+	for _, file := range files {
+		if !strings.HasSuffix(fset.Position(file.Pos()).Filename, "_test.go") {
+			continue
+		}
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Body == nil || funcDecl.Recv != nil || !strings.HasPrefix(funcDecl.Name.Name, "Test") {
+				continue
+			}
+			ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+					if ident, ok := sel.X.(*ast.Ident); ok {
+						callee := ident.Name + "." + sel.Sel.Name
+						if callers[callee] == nil {
+							callers[callee] = make(map[string]bool)
+						}
+						callers[callee][funcDecl.Name.Name] = true
+					}
+				}
+				return true
+			})
+		}
+	}
+
+	result := make([]HelperPackage, 0, len(packages))
+	for _, pkg := range packages {
+		for i := range pkg.Helpers {
+			helper := &pkg.Helpers[i]
+			for _, name := range helper.Qualified {
+				for test := range callers[name] {
+					helper.UsedBy = append(helper.UsedBy, test)
+				}
+			}
+			sort.Strings(helper.UsedBy)
+		}
+		sort.Slice(pkg.Helpers, func(i, j int) bool { return pkg.Helpers[i].Name < pkg.Helpers[j].Name })
+		result = append(result, *pkg)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Dir < result[j].Dir })
+	return result
+}
+
+// testCaseHelperReason returns why funcDecl is a test case helper, or "" when it is not.
+func testCaseHelperReason(funcDecl *ast.FuncDecl, resourceAliases map[string]bool) string {
+	if funcDecl.Type.Results != nil {
+		for _, result := range funcDecl.Type.Results.List {
+			if isResourceType(result.Type, "TestCase", resourceAliases) {
+				return HelperReturnsTestCase
+			}
+		}
+	}
+	for _, param := range funcDecl.Type.Params.List {
+		switch t := param.Type.(type) {
+		case *ast.ArrayType:
+			if isResourceType(t.Elt, "TestStep", resourceAliases) {
+				return HelperAcceptsSteps
+			}
+		case *ast.Ellipsis:
+			if isResourceType(t.Elt, "TestStep", resourceAliases) {
+				return HelperAcceptsSteps
+			}
+		}
+		if isResourceType(param.Type, "TestCase", resourceAliases) {
+			return HelperAcceptsTestCase
+		}
+	}
+	if funcDecl.Body != nil && acceptsTestingT(funcDecl) && checkUsesResourceTestWithAliases(funcDecl.Body, nil, nil, resourceAliases) {
+		return HelperRunsTest
+	}
+	return ""
+}
+
+// isResourceType reports whether expr is the resource package type name, or a pointer to it.
+func isResourceType(expr ast.Expr, name string, resourceAliases map[string]bool) bool {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	return ok && resourceAliases[ident.Name]
+}
+
+// CustomHelpers returns the custom test helpers of settings together with the qualified
+// names of the helpers DetectHelperPackages finds in files, unless settings ignore helper
+// packages.
+func CustomHelpers(settings config.Settings, fset *token.FileSet, files []*ast.File) []string {
+	if settings.IgnoreHelperPackages {
+		return settings.CustomTestHelpers
+	}
+	helpers := append([]string(nil), settings.CustomTestHelpers...)
+	seen := make(map[string]bool, len(helpers))
+	for _, name := range helpers {
+		seen[name] = true
+	}
+	for _, pkg := range DetectHelperPackages(files, fset) {
+		for _, helper := range pkg.Helpers {
+			for _, name := range helper.Qualified {
+				if !seen[name] {
+					seen[name] = true
+					helpers = append(helpers, name)
+				}
+			}
+		}
+	}
+	return helpers
+}
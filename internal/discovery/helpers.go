@@ -0,0 +1,163 @@
+package discovery
+
+import (
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// HelperKind classifies a discovered test helper function.
+type HelperKind int
+
+const (
+	// HelperKindWrapper is a helper that wraps resource.Test() or resource.ParallelTest().
+	HelperKindWrapper HelperKind = iota
+	// HelperKindConfig is a helper that returns an HCL configuration string.
+	HelperKindConfig
+)
+
+// String returns the string representation of a HelperKind.
+func (k HelperKind) String() string {
+	switch k {
+	case HelperKindWrapper:
+		return "wrapper"
+	case HelperKindConfig:
+		return "config"
+	default:
+		return "unknown"
+	}
+}
+
+// HelperInfo describes a test helper function and how it is used.
+type HelperInfo struct {
+	Name     string
+	Kind     HelperKind
+	FilePath string
+	Pos      token.Pos
+	// UsedBy lists the test functions that call this helper directly, sorted by name.
+	UsedBy []string
+	// ReferencedBlocks lists the HCL blocks found in the helper's returned templates.
+	ReferencedBlocks []InferredResource
+	// HelperOnlyFile is true when the helper's file declares no Test functions.
+	HelperOnlyFile bool
+}
+
+// packageFunc identifies a function by the directory of its package and its name, since
+// packages of one provider often declare functions of the same name.
+type packageFunc struct {
+	dir  string
+	name string
+}
+
+// CatalogTestHelpers discovers local test helpers across all test files: wrappers around
+// resource.Test() and functions that return HCL configuration. For each helper it records
+// which test functions of its package call it and which resource types its templates
+// reference.
+func CatalogTestHelpers(files []*ast.File, fset *token.FileSet) []HelperInfo {
+	helpers := make(map[packageFunc]*HelperInfo)
+	filesWithTests := make(map[string]bool)
+	callers := make(map[packageFunc]map[string]bool)
+
+	for _, helper := range findLocalTestHelpers(files, fset) {
+		helpers[packageFunc{filepath.Dir(helper.FilePath), helper.Name}] = &HelperInfo{
+			Name:     helper.Name,
+			Kind:     HelperKindWrapper,
+			FilePath: helper.FilePath,
+			Pos:      helper.FuncDecl.Pos(),
+		}
+	}
+
+	for _, file := range files {
+		filePath := fset.Position(file.Pos()).Filename
+		if !strings.HasSuffix(filePath, "_test.go") {
+			continue
+		}
+
+		dir := filepath.Dir(filePath)
+		typedPatterns := buildTypedHelperPatternMap(file)
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Body == nil || funcDecl.Recv != nil {
+				continue
+			}
+			name := funcDecl.Name.Name
+
+			if strings.HasPrefix(name, "Test") {
+				filesWithTests[filePath] = true
+				collectCalledIdents(funcDecl.Body, func(callee string) {
+					key := packageFunc{dir, callee}
+					if callers[key] == nil {
+						callers[key] = make(map[string]bool)
+					}
+					callers[key][name] = true
+				})
+				continue
+			}
+
+			blocks := dedupeInferredResources(typedPatterns[name])
+			if len(blocks) == 0 {
+				continue
+			}
+			key := packageFunc{dir, name}
+			if existing, ok := helpers[key]; ok {
+				existing.ReferencedBlocks = blocks
+				continue
+			}
+			helpers[key] = &HelperInfo{
+				Name:             name,
+				Kind:             HelperKindConfig,
+				FilePath:         filePath,
+				Pos:              funcDecl.Pos(),
+				ReferencedBlocks: blocks,
+			}
+		}
+	}
+
+	result := make([]HelperInfo, 0, len(helpers))
+	for key, helper := range helpers {
+		for caller := range callers[key] {
+			helper.UsedBy = append(helper.UsedBy, caller)
+		}
+		sort.Strings(helper.UsedBy)
+		helper.HelperOnlyFile = !filesWithTests[helper.FilePath]
+		result = append(result, *helper)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].FilePath != result[j].FilePath {
+			return result[i].FilePath < result[j].FilePath
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// collectCalledIdents calls fn for every plain identifier call (e.g., testAccConfig()) in body.
+func collectCalledIdents(body *ast.BlockStmt, fn func(string)) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if ident, ok := call.Fun.(*ast.Ident); ok {
+			fn(ident.Name)
+		}
+		return true
+	})
+}
+
+// dedupeInferredResources removes duplicate blocks while preserving first-seen order.
+func dedupeInferredResources(blocks []InferredResource) []InferredResource {
+	seen := make(map[InferredResource]bool)
+	var result []InferredResource
+	for _, block := range blocks {
+		if seen[block] {
+			continue
+		}
+		seen[block] = true
+		result = append(result, block)
+	}
+	return result
+}
@@ -0,0 +1,41 @@
+package discovery
+
+import (
+	"go/token"
+
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// ObserveHooks forwards the definitions registered and tests linked in reg to the hooks in
+// settings, if any. Call it before discovery starts so every event is delivered.
+func ObserveHooks(reg *registry.ResourceRegistry, settings config.Settings, fset *token.FileSet) {
+	hooks := settings.Hooks
+	if hooks == nil {
+		return
+	}
+	reg.SetObserver(registry.Observer{
+		DefinitionRegistered: func(info *registry.ResourceInfo) {
+			event := config.ResourceEvent{
+				Name:         info.Name,
+				Kind:         info.Kind.String(),
+				File:         info.FilePath,
+				DiscoveredBy: info.DiscoveredBy,
+			}
+			if fset != nil && info.SchemaPos.IsValid() {
+				event.Line = fset.Position(info.SchemaPos).Line
+			}
+			hooks.ResourceDiscovered(event)
+		},
+		TestLinked: func(key registry.ResourceKey, fn *registry.TestFunctionInfo) {
+			hooks.TestLinked(config.LinkEvent{
+				Test:       fn.Name,
+				TestFile:   fn.FilePath,
+				Resource:   key.Name,
+				Kind:       key.Kind.String(),
+				MatchType:  fn.MatchType.String(),
+				Confidence: fn.MatchConfidence,
+			})
+		},
+	})
+}
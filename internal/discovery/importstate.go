@@ -0,0 +1,335 @@
+package discovery
+
+import (
+	"go/ast"
+	"go/token"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// CollectPackageFunctions indexes the top-level (non-method) functions declared across the
+// files of a package, so helpers defined in one test file can be resolved from another.
+func CollectPackageFunctions(files []*ast.File) map[string]*ast.FuncDecl {
+	funcs := make(map[string]*ast.FuncDecl)
+	for _, file := range files {
+		for name, funcDecl := range fileFunctions(file) {
+			if _, exists := funcs[name]; !exists {
+				funcs[name] = funcDecl
+			}
+		}
+	}
+	return funcs
+}
+
+// importStateIdFuncName returns the name of the function an ImportStateIdFunc value refers to:
+// the identifier itself, or the helper called to build it. Func literals have no name.
+func importStateIdFuncName(expr ast.Expr) string {
+	if call, ok := expr.(*ast.CallExpr); ok {
+		expr = call.Fun
+	}
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		if pkg, ok := e.X.(*ast.Ident); ok {
+			return pkg.Name + "." + e.Sel.Name
+		}
+		return e.Sel.Name
+	}
+	return ""
+}
+
+// resolveImportStateFuncs fills in what each step's ImportStateIdFunc and ImportStateCheck do
+// with the state: the attributes the ID function reads, and the assertions the check makes.
+// Functions are resolved by name with lookup.
+func resolveImportStateFuncs(body *ast.BlockStmt, steps []registry.TestStepInfo, lookup func(string) *ast.FuncDecl) {
+	if body == nil {
+		return
+	}
+
+	idFuncs := make(map[token.Pos]int)
+	checks := make(map[token.Pos]int)
+	for i := range steps {
+		if steps[i].HasImportStateIdFunc {
+			idFuncs[steps[i].ImportStateIdFuncPos] = i
+		}
+		if steps[i].HasImportStateCheck {
+			checks[steps[i].ImportStateCheckPos] = i
+		}
+	}
+	if len(idFuncs) == 0 && len(checks) == 0 {
+		return
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		kv, ok := n.(*ast.KeyValueExpr)
+		if !ok {
+			return true
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		switch key.Name {
+		case "ImportStateIdFunc":
+			if i, ok := idFuncs[kv.Value.Pos()]; ok {
+				steps[i].ImportStateIdAttributes = importStateIdAttributes(kv.Value, lookup)
+			}
+		case "ImportStateCheck":
+			if i, ok := checks[kv.Value.Pos()]; ok {
+				step := &steps[i]
+				step.ImportStateCheckResolved, step.ImportStateCheckCountsInstances, step.ImportStateCheckAttributes = importStateCheckSummary(kv.Value, lookup)
+			}
+		}
+		return true
+	})
+}
+
+// importStateCheckSummary inspects an ImportStateCheck value: whether it compares the number
+// of imported instance states (len(states) != 1) and which state attribute keys it reads.
+// Like importStateIdAttributes, it handles func literals, named functions, and helper calls
+// with string arguments bound to the helper's parameters. resolved is false when the function
+// body cannot be found, e.g. for a function in another package.
+func importStateCheckSummary(expr ast.Expr, lookup func(string) *ast.FuncDecl) (resolved, countsInstances bool, attributes []string) {
+	var funcType *ast.FuncType
+	var body *ast.BlockStmt
+	var params map[string]string
+	switch e := expr.(type) {
+	case *ast.FuncLit:
+		funcType, body = e.Type, e.Body
+	case *ast.Ident:
+		if funcDecl := lookup(e.Name); funcDecl != nil {
+			funcType, body = funcDecl.Type, funcDecl.Body
+		}
+	case *ast.CallExpr:
+		if ident, ok := e.Fun.(*ast.Ident); ok {
+			if funcDecl := lookup(ident.Name); funcDecl != nil {
+				// The helper returns the check, so its func literal is found in the body
+				body, params = funcDecl.Body, stringArguments(funcDecl, e.Args)
+			}
+		}
+	}
+	if body == nil {
+		return false, false, nil
+	}
+	return true, countsInstanceStates(funcType, body), stateAttributeReads(body, params)
+}
+
+// countsInstanceStates reports whether body compares len() of a []*terraform.InstanceState
+// parameter, either of funcType or of a func literal inside body.
+func countsInstanceStates(funcType *ast.FuncType, body *ast.BlockStmt) bool {
+	states := make(map[string]bool)
+	collect := func(ft *ast.FuncType) {
+		if ft == nil || ft.Params == nil {
+			return
+		}
+		for _, field := range ft.Params.List {
+			if isInstanceStateSlice(field.Type) {
+				for _, name := range field.Names {
+					states[name.Name] = true
+				}
+			}
+		}
+	}
+	collect(funcType)
+	ast.Inspect(body, func(n ast.Node) bool {
+		if lit, ok := n.(*ast.FuncLit); ok {
+			collect(lit.Type)
+		}
+		return true
+	})
+	if len(states) == 0 {
+		return false
+	}
+
+	counts := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		binary, ok := n.(*ast.BinaryExpr)
+		if !ok || counts {
+			return !counts
+		}
+		switch binary.Op {
+		case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+			counts = isLenOf(binary.X, states) || isLenOf(binary.Y, states)
+		}
+		return !counts
+	})
+	return counts
+}
+
+// isInstanceStateSlice reports whether expr is the type []*terraform.InstanceState.
+func isInstanceStateSlice(expr ast.Expr) bool {
+	array, ok := expr.(*ast.ArrayType)
+	if !ok || array.Len != nil {
+		return false
+	}
+	star, ok := array.Elt.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := star.X.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "InstanceState"
+}
+
+// isLenOf reports whether expr is len(name) for one of names.
+func isLenOf(expr ast.Expr, names map[string]bool) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return false
+	}
+	if fn, ok := call.Fun.(*ast.Ident); !ok || fn.Name != "len" {
+		return false
+	}
+	arg, ok := call.Args[0].(*ast.Ident)
+	return ok && names[arg.Name]
+}
+
+// importStateIdAttributes returns the state attribute keys read by an ImportStateIdFunc value.
+// It handles func literals, named functions, and helper calls such as
+// testAccImportID("example_widget.test", "project"), where string arguments are substituted
+// for the helper's parameters.
+func importStateIdAttributes(expr ast.Expr, lookup func(string) *ast.FuncDecl) []string {
+	switch e := expr.(type) {
+	case *ast.FuncLit:
+		return stateAttributeReads(e.Body, nil)
+	case *ast.Ident:
+		if funcDecl := lookup(e.Name); funcDecl != nil {
+			return stateAttributeReads(funcDecl.Body, nil)
+		}
+	case *ast.CallExpr:
+		ident, ok := e.Fun.(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		funcDecl := lookup(ident.Name)
+		if funcDecl == nil {
+			return nil
+		}
+		return stateAttributeReads(funcDecl.Body, stringArguments(funcDecl, e.Args))
+	}
+	return nil
+}
+
+// stringArguments maps a function's parameter names to the string literal arguments of a call.
+func stringArguments(funcDecl *ast.FuncDecl, args []ast.Expr) map[string]string {
+	values := make(map[string]string)
+	if funcDecl.Type.Params == nil {
+		return values
+	}
+
+	i := 0
+	for _, field := range funcDecl.Type.Params.List {
+		for _, name := range field.Names {
+			if i < len(args) {
+				if lit, ok := args[i].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					if value, err := strconv.Unquote(lit.Value); err == nil {
+						values[name.Name] = value
+					}
+				}
+			}
+			i++
+		}
+		if len(field.Names) == 0 {
+			i++
+		}
+	}
+	return values
+}
+
+// stateAttributeReads returns the keys indexed on a .Attributes map (e.g.,
+// rs.Primary.Attributes["project"]), resolving parameter identifiers through params.
+func stateAttributeReads(body *ast.BlockStmt, params map[string]string) []string {
+	if body == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		index, ok := n.(*ast.IndexExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := index.X.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Attributes" {
+			return true
+		}
+		switch key := index.Index.(type) {
+		case *ast.BasicLit:
+			if key.Kind == token.STRING {
+				if value, err := strconv.Unquote(key.Value); err == nil && value != "" {
+					seen[value] = true
+				}
+			}
+		case *ast.Ident:
+			if value, ok := params[key.Name]; ok && value != "" {
+				seen[value] = true
+			}
+		}
+		return true
+	})
+	return slices.Sorted(maps.Keys(seen))
+}
+
+// SchemaFieldNames returns the attribute and block names declared in the schema function
+// containing pos, at any nesting level. complete is false when part of the schema is built
+// outside a map literal (e.g., Attributes: commonAttributes()), in which case the names
+// cannot be trusted to be exhaustive.
+func SchemaFieldNames(file *ast.File, pos token.Pos) (names map[string]bool, complete bool) {
+	var funcDecl *ast.FuncDecl
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && pos >= fn.Pos() && pos < fn.End() {
+			funcDecl = fn
+			break
+		}
+	}
+	if funcDecl == nil || funcDecl.Body == nil {
+		return nil, false
+	}
+
+	names = make(map[string]bool)
+	complete = true
+	found := false
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		kv, ok := n.(*ast.KeyValueExpr)
+		if !ok {
+			return true
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok || (key.Name != "Attributes" && key.Name != "Blocks") {
+			return true
+		}
+		found = true
+		mapLit, ok := kv.Value.(*ast.CompositeLit)
+		if !ok {
+			complete = false
+			return true
+		}
+		for _, elt := range mapLit.Elts {
+			entry, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			lit, ok := entry.Key.(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				complete = false
+				continue
+			}
+			if name, err := strconv.Unquote(lit.Value); err == nil {
+				names[name] = true
+			}
+		}
+		return true
+	})
+	return names, complete && found
+}
+
+// StateAttributeRoot returns the schema attribute a flatmap state key refers to
+// (e.g., "tags.%" -> "tags", "network.0.name" -> "network").
+func StateAttributeRoot(key string) string {
+	root, _, _ := strings.Cut(key, ".")
+	return root
+}
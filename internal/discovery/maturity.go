@@ -0,0 +1,30 @@
+package discovery
+
+import (
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// ApplyMaturity exempts definitions tagged with a maturity level from the checks the
+// maturity-exemptions setting lists for that level, e.g. experimental resources from update
+// and import tests. It runs after ApplyRequirements, so checks a //tftest:expect directive or
+// the requirements manifest requires are still enforced.
+func ApplyMaturity(reg *registry.ResourceRegistry, settings config.Settings) {
+	for _, def := range reg.GetSortedDefinitions() {
+		if def.Maturity == "" {
+			continue
+		}
+		for _, check := range settings.MaturityExemptionsFor(def.Maturity) {
+			if !registry.CheckApplies(def.Kind, check) || def.Directives.Expects(check) || def.Directives.Exempts(check) {
+				continue
+			}
+			if def.Directives == nil {
+				def.Directives = &registry.CoverageDirectives{
+					Expect: make(map[string]bool),
+					Exempt: make(map[string]string),
+				}
+			}
+			def.Directives.Exempt[check] = def.Maturity + " maturity"
+		}
+	}
+}
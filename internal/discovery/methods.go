@@ -0,0 +1,52 @@
+package discovery
+
+import (
+	"go/ast"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// lifecycleMethods are the framework methods recorded in ResourceInfo.Methods.
+var lifecycleMethods = map[string]bool{"Create": true, "Read": true, "Update": true, "Delete": true}
+
+// applyLifecycleMethods records the source ranges of the Create, Read, Update and Delete
+// methods that definitions discovered from their Schema method declare in the same file.
+func applyLifecycleMethods(file *ast.File, state *DiscoveryState) {
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Recv == nil || funcDecl.Body == nil || !lifecycleMethods[funcDecl.Name.Name] {
+			continue
+		}
+		index, ok := state.RecvTypeToIndex[getReceiverTypeName(funcDecl.Recv)]
+		if !ok || index >= len(state.Resources) {
+			continue
+		}
+		resource := state.Resources[index]
+		resource.Methods = append(resource.Methods, registry.MethodRange{
+			Name: funcDecl.Name.Name,
+			Pos:  funcDecl.Pos(),
+			End:  funcDecl.End(),
+			NoOp: isNoOpBody(funcDecl.Body),
+		})
+	}
+}
+
+// isNoOpBody reports whether a method body does nothing but, at most, call
+// resp.State.RemoveResource, which the framework does anyway after Delete returns.
+func isNoOpBody(body *ast.BlockStmt) bool {
+	for _, stmt := range body.List {
+		expr, ok := stmt.(*ast.ExprStmt)
+		if !ok {
+			return false
+		}
+		call, ok := expr.X.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "RemoveResource" {
+			return false
+		}
+	}
+	return true
+}
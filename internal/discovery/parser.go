@@ -15,6 +15,7 @@ import (
 	"golang.org/x/tools/go/analysis"
 
 	"github.com/example/tfprovidertest/internal/matching"
+	"github.com/example/tfprovidertest/internal/naming"
 	"github.com/example/tfprovidertest/internal/registry"
 	"github.com/example/tfprovidertest/pkg/config"
 )
@@ -48,14 +49,17 @@ type LocalHelper struct {
 // This struct consolidates the various parameters that were previously
 // spread across multiple parseTestFile* functions.
 type ParserConfig struct {
-	CustomHelpers         []string      // Custom test helper functions (e.g., "mypackage.AccTest")
-	LocalHelpers          []LocalHelper // Local test helper functions discovered in the codebase
-	TestNamePatterns      []string      // Custom test name patterns (e.g., "TestAcc*", "TestResource*")
-	TestFilePattern       string        // Pattern for test files (e.g., "*_test.go")
-	ResourceNamingPattern string        // Regex pattern for extracting resource names from identifiers
-	ProviderPrefix        string        // Provider prefix for function name matching (e.g., "AWS", "Google")
-	ResourcePathPattern   string        // Pattern for resource files (e.g., "resource_*.go")
-	DataSourcePathPattern string        // Pattern for data source files (e.g., "data_source_*.go")
+	CustomHelpers         []string                     // Custom test helper functions (e.g., "mypackage.AccTest")
+	LocalHelpers          []LocalHelper                // Local test helper functions discovered in the codebase
+	TestNamePatterns      []string                     // Custom test name patterns (e.g., "TestAcc*", "TestResource*")
+	TestFilePattern       string                       // Pattern for test files (e.g., "*_test.go")
+	ResourceNamingPattern string                       // Regex pattern for extracting resource names from identifiers
+	ProviderPrefix        string                       // Provider prefix for function name matching (e.g., "AWS", "Google")
+	ResourcePathPattern   string                       // Pattern for resource files (e.g., "resource_*.go")
+	DataSourcePathPattern string                       // Pattern for data source files (e.g., "data_source_*.go")
+	PackageTemplates      map[string]map[string]string // Package-level HCL templates by package directory and identifier (see CollectPackageTemplates)
+	PackageFunctions      map[string]*ast.FuncDecl     // Package-level functions by name (see CollectPackageFunctions)
+	ConditionalGuards     []string                     // Glob patterns of helpers that skip tests under some conditions (e.g., "*PreCheckRegion*")
 }
 
 // DefaultParserConfig returns a ParserConfig with default/empty values.
@@ -135,6 +139,9 @@ type DiscoveryState struct {
 	ProcessedFactoryFuncs map[string]bool
 	// Resources accumulates all discovered resources across strategies
 	Resources []*registry.ResourceInfo
+	// Names canonicalizes the names derived from Go type and function names; nil applies
+	// the default pipeline
+	Names *naming.Pipeline
 }
 
 // NewDiscoveryState creates a new DiscoveryState with initialized maps.
@@ -151,7 +158,7 @@ func NewDiscoveryState() *DiscoveryState {
 
 // SeenKey generates a unique key for tracking seen resources.
 func (s *DiscoveryState) SeenKey(kind registry.ResourceKind, name string) string {
-	return kind.String() + ":" + name
+	return registry.ResourceKey{Kind: kind, Name: name}.String()
 }
 
 // SchemaMethodStrategy discovers resources by looking for Schema() methods on types
@@ -201,7 +208,7 @@ func (s *SchemaMethodStrategy) Discover(file *ast.File, fset *token.FileSet, fil
 			return true
 		}
 
-		name := extractResourceName(recvType)
+		name := extractResourceName(state.Names, recvType)
 		key := state.SeenKey(kind, name)
 		if name == "" || state.Seen[key] {
 			return true
@@ -222,7 +229,9 @@ func (s *SchemaMethodStrategy) Discover(file *ast.File, fset *token.FileSet, fil
 			FilePath:   filePath,
 			SchemaPos:  funcDecl.Pos(),
 			Attributes: attributes,
+			Blocks:     extractBlocks(funcDecl.Body),
 		}
+		resource.SchemaVersion, resource.VersionPos = schemaVersion(funcDecl.Body)
 
 		resources = append(resources, resource)
 		state.Resources = append(state.Resources, resource)
@@ -279,6 +288,7 @@ func (f *FactoryFunctionStrategy) Discover(file *ast.File, fset *token.FileSet,
 					Kind:      kind,
 					FilePath:  filePath,
 					SchemaPos: funcDecl.Pos(),
+					Factory:   funcName,
 				}
 				resources = append(resources, resource)
 				state.Resources = append(state.Resources, resource)
@@ -456,7 +466,7 @@ func (a *ActionFactoryStrategy) Discover(file *ast.File, fset *token.FileSet, fi
 		if state.ProcessedActionTypes[typeName] {
 			continue
 		}
-		name := extractActionName("New" + typeName)
+		name := extractActionName(state.Names, "New"+typeName)
 		key := state.SeenKey(registry.KindAction, name)
 		if name != "" && !state.Seen[key] {
 			state.Seen[key] = true
@@ -521,7 +531,7 @@ func (r *ReturnTypeStrategy) Discover(file *ast.File, fset *token.FileSet, fileP
 			}
 
 			// Extract resource name from Metadata method body or function name
-			name := r.extractResourceName(funcDecl, file, kind)
+			name := r.extractResourceName(funcDecl, file, kind, state.Names)
 			if name == "" {
 				continue
 			}
@@ -537,6 +547,7 @@ func (r *ReturnTypeStrategy) Discover(file *ast.File, fset *token.FileSet, fileP
 				Kind:      kind,
 				FilePath:  filePath,
 				SchemaPos: funcDecl.Pos(),
+				Factory:   funcName,
 			}
 			resources = append(resources, resource)
 			state.Resources = append(state.Resources, resource)
@@ -617,10 +628,11 @@ func (r *RegistryFactoryStrategy) Discover(file *ast.File, fset *token.FileSet,
 
 		state.Seen[key] = true
 		resource := &registry.ResourceInfo{
-			Name:      name,
-			Kind:      kind,
-			FilePath:  filePath,
-			SchemaPos: callExpr.Pos(),
+			Name:         name,
+			Kind:         kind,
+			FilePath:     filePath,
+			SchemaPos:    callExpr.Pos(),
+			ListResource: sel.Sel.Name == "AddListResourceFactory",
 		}
 		resources = append(resources, resource)
 		state.Resources = append(state.Resources, resource)
@@ -632,8 +644,9 @@ func (r *RegistryFactoryStrategy) Discover(file *ast.File, fset *token.FileSet,
 }
 
 // extractResourceName tries to extract the resource name from the factory function.
-// It first looks for Metadata method calls or TypeName assignments, then falls back to function name parsing.
-func (r *ReturnTypeStrategy) extractResourceName(funcDecl *ast.FuncDecl, file *ast.File, kind registry.ResourceKind) string {
+// It first looks for Metadata method calls or TypeName assignments, then falls back to function name parsing
+// with the names pipeline.
+func (r *ReturnTypeStrategy) extractResourceName(funcDecl *ast.FuncDecl, file *ast.File, kind registry.ResourceKind, names *naming.Pipeline) string {
 	funcName := funcDecl.Name.Name
 
 	// Try to find the type being returned and look for its Metadata method
@@ -650,7 +663,7 @@ func (r *ReturnTypeStrategy) extractResourceName(funcDecl *ast.FuncDecl, file *a
 	}
 
 	// Fall back to extracting name from function name
-	return extractNameFromFactoryFunc(funcName, kind)
+	return extractNameFromFactoryFunc(names, funcName, kind)
 }
 
 // extractImportAliases builds a map from package alias to import path
@@ -789,7 +802,7 @@ func findMetadataTypeNameForType(file *ast.File, typeName string) string {
 }
 
 // extractNameFromFactoryFunc extracts a resource name from a factory function name
-func extractNameFromFactoryFunc(funcName string, kind registry.ResourceKind) string {
+func extractNameFromFactoryFunc(names *naming.Pipeline, funcName string, kind registry.ResourceKind) string {
 	// Handle various patterns:
 	// NewXxxResource -> xxx
 	// NewXxxDataSource -> xxx
@@ -826,7 +839,7 @@ func extractNameFromFactoryFunc(funcName string, kind registry.ResourceKind) str
 	}
 
 	// Convert to snake_case
-	return toSnakeCase(name)
+	return names.SnakeCase(name)
 }
 
 // DefaultNestedSchemaPatterns returns the default patterns for identifying nested schema types.
@@ -895,9 +908,10 @@ func matchesNestedPattern(name, pattern string) bool {
 // 3. Metadata() method with resp.TypeName assignment (preferred over Strategy 1)
 // 4. NewXxxAction factory functions returning action.Action
 // 5. Return type analysis for functions returning resource.Resource, datasource.DataSource, *schema.Resource
-func parseResources(file *ast.File, fset *token.FileSet, filePath string) []*registry.ResourceInfo {
+func parseResources(file *ast.File, fset *token.FileSet, filePath string, names *naming.Pipeline) []*registry.ResourceInfo {
 	// Initialize shared discovery state
 	state := NewDiscoveryState()
+	state.Names = names
 
 	// Define strategies in execution order
 	strategies := []DiscoveryStrategy{
@@ -909,11 +923,20 @@ func parseResources(file *ast.File, fset *token.FileSet, filePath string) []*reg
 		&RegistryFactoryStrategy{},
 	}
 
-	// Execute each strategy in order
+	// Execute each strategy in order, recording which one produced each definition
 	for _, strategy := range strategies {
 		strategy.Discover(file, fset, filePath, state)
+		for _, resource := range state.Resources {
+			if resource.DiscoveredBy == "" {
+				resource.DiscoveredBy = strategy.Name()
+			}
+		}
 	}
 
+	applyDeferredResponses(file, state)
+	applyLifecycleMethods(file, state)
+	applyFactoryNames(file, state)
+
 	// Post-processing: filter out nested schema types and check for ImportState
 	var filtered []*registry.ResourceInfo
 	for _, resource := range state.Resources {
@@ -925,6 +948,7 @@ func parseResources(file *ast.File, fset *token.FileSet, filePath string) []*reg
 		if resource.Kind == registry.KindResource {
 			resource.HasImportState = hasImportStateMethod(file, resource.Name)
 		}
+		applySDKv2Resource(file, resource)
 		filtered = append(filtered, resource)
 	}
 
@@ -935,7 +959,7 @@ func parseResources(file *ast.File, fset *token.FileSet, filePath string) []*reg
 // Examples: NewJobAction -> job, NewWorkflowJobAction -> workflow_job
 // Note: Actions may share base names with resources (e.g., both "job" resource and "job" action exist).
 // The registry uses Kind to differentiate them.
-func extractActionName(funcName string) string {
+func extractActionName(names *naming.Pipeline, funcName string) string {
 	// Remove "New" prefix and "Action" suffix
 	name := strings.TrimPrefix(funcName, "New")
 	name = strings.TrimSuffix(name, "Action")
@@ -944,7 +968,7 @@ func extractActionName(funcName string) string {
 	}
 
 	// Convert PascalCase to snake_case
-	return toSnakeCase(name)
+	return names.SnakeCase(name)
 }
 
 // extractMetadataEntitySlug extracts the resource name from MetadataEntitySlug in a function body.
@@ -1035,11 +1059,40 @@ func ParseTestFileWithConfig(file *ast.File, fset *token.FileSet, filePath strin
 	// Build helper function maps:
 	// - helperPatterns: function name -> resource type names (for legacy InferredResources)
 	// - typedHelperPatterns: function name -> typed blocks (for InferredHCLBlocks)
-	helperPatterns := buildHelperPatternMap(file)
-	typedHelperPatterns := buildTypedHelperPatternMap(file)
+	// Package-level templates (const, var, //go:embed) are resolved when helpers reference them
+	templates := CollectStringTemplates(file, filePath)
+	for name, content := range config.PackageTemplates[filepath.Dir(filePath)] {
+		if _, exists := templates[name]; !exists {
+			templates[name] = content
+		}
+	}
+	tmplPatterns := templatePatterns(templates)
+	// Both helper maps come from one walk of the file
+	typedHelperPatterns := buildTypedHelperPatternMapWithTemplates(file, tmplPatterns)
+	helperPatterns := helperPatternsOf(typedHelperPatterns)
+
+	// Templates used directly as a step's Config resolve the same way as helper calls
+	for name, blocks := range tmplPatterns {
+		if _, exists := typedHelperPatterns[name]; exists {
+			continue
+		}
+		typedHelperPatterns[name] = blocks
+		for _, block := range blocks {
+			helperPatterns[name] = append(helperPatterns[name], block.ResourceType)
+		}
+	}
 
 	// Extract resource package aliases from imports (handles aliased imports like r "...helper/resource")
 	resourceAliases := ExtractResourcePackageAliases(file)
+	fileFuncs := fileFunctions(file)
+	imports := extractImportAliases(file)
+	// Helpers are resolved in this file first, then across the package
+	lookupFunc := func(name string) *ast.FuncDecl {
+		if funcDecl, ok := fileFuncs[name]; ok {
+			return funcDecl
+		}
+		return config.PackageFunctions[name]
+	}
 
 	var testFuncs []registry.TestFunctionInfo
 
@@ -1082,19 +1135,42 @@ func ParseTestFileWithConfig(file *ast.File, fset *token.FileSet, filePath strin
 			}
 		}
 
-		steps, hasCheckDestroy, hasPreCheck, inferred, inferredBlocks := extractTestStepsWithHelpers(funcDecl.Body, helperPatterns, typedHelperPatterns)
+		steps, hasCheckDestroy, hasPreCheck, inferred, inferredBlocks, opaqueSteps := extractTestStepsWithHelpers(funcDecl.Body, helperPatterns, typedHelperPatterns, lookupFunc)
+		testConfigs := providerConfigs(funcDecl.Body, fileFuncs, templates)
 		testFunc := registry.TestFunctionInfo{
 			Name:              funcDecl.Name.Name,
 			FilePath:          filePath,
 			FunctionPos:       funcDecl.Pos(),
 			UsesResourceTest:  true,
 			TestSteps:         steps,
+			HasOpaqueSteps:    opaqueSteps,
 			HelperUsed:        detectHelperUsed(funcDecl.Body, config.LocalHelpers),
 			HasCheckDestroy:   hasCheckDestroy,
 			HasPreCheck:       hasPreCheck,
 			InferredResources: inferred,
 			InferredHCLBlocks: inferredBlocks,
-		}
+
+			ProviderConfigAttributes: extractProviderConfigAttributes(testConfigs),
+			ProviderAliases:          extractProviderAliases(testConfigs),
+			EnvVarsSet:               extractEnvVarWrites(funcDecl.Body),
+			UsesParallelTest:         usesParallelTest(funcDecl.Body, resourceAliases),
+			SerialReason:             serialReason(file, funcDecl),
+			ChecksDisappears:         checksDisappears(name, funcDecl.Body),
+			TestCases:                extractTestCaseFactories(funcDecl.Body, resourceAliases),
+		}
+		testFunc.QuarantineReason, testFunc.Quarantined = quarantineDirective(funcDecl)
+		if testFunc.UsesParallelTest {
+			testFunc.FixtureValues = extractFixtureValues(funcDecl.Body, lookupFunc, templates)
+		}
+
+		resolveImportStateFuncs(funcDecl.Body, testFunc.TestSteps, lookupFunc)
+		resolveStepConfigAttributes(funcDecl.Body, testFunc.TestSteps, lookupFunc, templates)
+		resolveStepCheckAddresses(funcDecl.Body, testFunc.TestSteps, resourceAliases, imports, templates)
+		resolveTestEnvVars(&testFunc, funcDecl.Body, imports, lookupFunc)
+		testFunc.Conditions = extractConditions(funcDecl.Body, config.ConditionalGuards)
+		testFunc.Subtests = extractSubtests(funcDecl.Body, testFunc.TestSteps, func(body *ast.BlockStmt) bool {
+			return checkUsesResourceTestWithAliases(body, config.CustomHelpers, config.LocalHelpers, resourceAliases)
+		})
 
 		for _, step := range testFunc.TestSteps {
 			if step.ExpectError {
@@ -1139,6 +1215,41 @@ func parseTestFileWithHelpers(file *ast.File, fset *token.FileSet, filePath stri
 	return ParseTestFileWithConfig(file, fset, filePath, config)
 }
 
+// findExportedTestHelpers discovers exported functions that accept *testing.T and wrap
+// resource.Test() in a helper package. Unlike findLocalTestHelpers it also considers
+// non-test files, since shared helper packages (e.g. internal/acctest) are imported by
+// tests in other packages.
+func findExportedTestHelpers(files []*ast.File, fset *token.FileSet) []LocalHelper {
+	var helpers []LocalHelper
+
+	for _, file := range files {
+		filePath := fset.Position(file.Pos()).Filename
+		if strings.HasSuffix(filePath, "_test.go") {
+			continue
+		}
+
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Body == nil || funcDecl.Recv != nil {
+				continue
+			}
+			if !funcDecl.Name.IsExported() || !acceptsTestingT(funcDecl) {
+				continue
+			}
+			if !checkUsesResourceTest(funcDecl.Body) {
+				continue
+			}
+			helpers = append(helpers, LocalHelper{
+				Name:     funcDecl.Name.Name,
+				FilePath: filePath,
+				FuncDecl: funcDecl,
+			})
+		}
+	}
+
+	return helpers
+}
+
 // extractResourceNameFromFilePath extracts resource name from file path.
 // This function delegates to ExtractResourceNameFromPath for the actual extraction logic.
 func extractResourceNameFromFilePath(filePath string) (string, bool) {
@@ -1243,9 +1354,16 @@ func matchesExcludePattern(filePath string, patterns []string) ExclusionResult {
 //  3. Link tests to resources using the Linker (function name, file proximity, fuzzy)
 func BuildRegistry(pass *analysis.Pass, settings config.Settings) *registry.ResourceRegistry {
 	reg := registry.NewResourceRegistry()
+	ObserveHooks(reg, settings, pass.Fset)
 
-	// Discover local test helpers first
+	// Discover local test helpers and package-level HCL templates first
 	localHelpers := findLocalTestHelpers(pass.Files, pass.Fset)
+	customHelpers := CustomHelpers(settings, pass.Fset, pass.Files)
+	packageTemplates := CollectPackageTemplates(pass.Files, pass.Fset)
+	packageFunctions := CollectPackageFunctions(pass.Files)
+	classifier := FileClassifier(settings)
+	guards := ConditionalGuards(settings)
+	names, _ := settings.NamePipeline()
 
 	// PHASE 1: Scan for Resources (Type-based discovery via AST)
 	for _, file := range pass.Files {
@@ -1254,30 +1372,28 @@ func BuildRegistry(pass *analysis.Pass, settings config.Settings) *registry.Reso
 		if strings.HasSuffix(filename, "_test.go") {
 			continue
 		}
-		if settings.ExcludeBaseClasses && IsBaseClassFile(filename) {
+		if ExclusionReason(settings, classifier, filename) != "" {
 			continue
 		}
-		if settings.ExcludeSweeperFiles && IsSweeperFile(filename) {
-			continue
-		}
-		if settings.ExcludeMigrationFiles && IsMigrationFile(filename) {
-			continue
-		}
-		if shouldExcludeFile(filename, settings.ExcludePaths) {
-			continue
-		}
-		// Check custom exclude patterns
-		if len(settings.ExcludePatterns) > 0 {
-			if result := matchesExcludePattern(filename, settings.ExcludePatterns); result.Excluded {
-				continue
-			}
-		}
 
-		resources := parseResources(file, pass.Fset, filename)
+		resources := parseResources(file, pass.Fset, filename, names)
+		for _, err := range ApplyDirectives(file, resources) {
+			reg.AddDirectiveError(err)
+		}
 		for _, resource := range resources {
 			reg.RegisterResource(resource)
 		}
+		if reg.GetProvider() == nil {
+			if provider := ParseProvider(file, filename); provider != nil {
+				reg.SetProvider(provider)
+			}
+		}
 	}
+	ApplyEmbeddedMetadata(reg, pass.Fset, pass.Files, names)
+	ApplyFactoryAliases(reg, pass.Fset, pass.Files)
+	ApplyRequirements(reg, settings, pass.Fset, pass.Files)
+	ApplyMaturity(reg, settings)
+	ApplyCategories(reg, pass.Fset, pass.Files)
 
 	// PHASE 2: Scan ALL Test Files (unconditionally)
 	for _, file := range pass.Files {
@@ -1287,21 +1403,14 @@ func BuildRegistry(pass *analysis.Pass, settings config.Settings) *registry.Reso
 			continue
 		}
 
-		// Skip sweeper test files
-		if settings.ExcludeSweeperFiles && IsSweeperFile(filename) {
+		// Skip sweeper (and other excluded role) test files and custom exclude patterns
+		if ExclusionReason(settings, classifier, filename) != "" {
 			continue
 		}
 
-		// Check custom exclude patterns
-		if len(settings.ExcludePatterns) > 0 {
-			if result := matchesExcludePattern(filename, settings.ExcludePatterns); result.Excluded {
-				continue
-			}
-		}
-
 		// Parse test file with custom and local helpers and test name patterns
 		config := ParserConfig{
-			CustomHelpers:         settings.CustomTestHelpers,
+			CustomHelpers:         customHelpers,
 			LocalHelpers:          localHelpers,
 			TestNamePatterns:      settings.TestNamePatterns,
 			TestFilePattern:       settings.TestFilePattern,
@@ -1309,6 +1418,9 @@ func BuildRegistry(pass *analysis.Pass, settings config.Settings) *registry.Reso
 			ProviderPrefix:        settings.ProviderPrefix,
 			ResourcePathPattern:   settings.ResourcePathPattern,
 			DataSourcePathPattern: settings.DataSourcePathPattern,
+			PackageTemplates:      packageTemplates,
+			PackageFunctions:      packageFunctions,
+			ConditionalGuards:     guards,
 		}
 		testFileInfo := ParseTestFileWithConfig(file, pass.Fset, filename, config)
 		if testFileInfo == nil {
@@ -1323,9 +1435,12 @@ func BuildRegistry(pass *analysis.Pass, settings config.Settings) *registry.Reso
 		}
 	}
 
+	ApplyQuarantine(reg)
+
 	// PHASE 3: Link tests to resources using the Linker
-	linker := matching.NewLinker(reg, settings)
+	linker := matching.NewLinker(reg, &settings)
 	linker.LinkTestsToResources()
+	ApplyResourceScope(reg, settings)
 
 	return reg
 }
@@ -1513,7 +1628,7 @@ func checkUsesResourceTestWithAliases(body *ast.BlockStmt, customHelpers []strin
 
 		return true
 	})
-	return found
+	return found || usesTestDataBuilder(body)
 }
 
 // hasTestCaseArg checks if an expression is a resource.TestCase composite literal
@@ -1584,39 +1699,35 @@ func detectHelperUsed(body *ast.BlockStmt, localHelpers []LocalHelper) string {
 // buildHelperPatternMap scans a file for helper functions that return HCL strings
 // and extracts resource/action patterns from them.
 func buildHelperPatternMap(file *ast.File) map[string][]string {
-	patterns := make(map[string][]string)
-
-	ast.Inspect(file, func(n ast.Node) bool {
-		funcDecl, ok := n.(*ast.FuncDecl)
-		if !ok || funcDecl.Body == nil {
-			return true
-		}
-
-		funcName := funcDecl.Name.Name
-
-		// Look for return statements with string literals or fmt.Sprintf
-		ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
-			ret, ok := n.(*ast.ReturnStmt)
-			if !ok || len(ret.Results) == 0 {
-				return true
-			}
+	return buildHelperPatternMapWithTemplates(file, nil)
+}
 
-			for _, result := range ret.Results {
-				extractPatternsFromExpr(result, func(pattern string) {
-					patterns[funcName] = append(patterns[funcName], pattern)
-				})
-			}
-			return true
-		})
-		return true
-	})
+// buildHelperPatternMapWithTemplates is like buildHelperPatternMap but also resolves
+// identifiers that refer to package-level string templates.
+func buildHelperPatternMapWithTemplates(file *ast.File, templates map[string][]InferredResource) map[string][]string {
+	return helperPatternsOf(buildTypedHelperPatternMapWithTemplates(file, templates))
+}
 
+// helperPatternsOf returns the resource types of the typed helper patterns, by helper.
+func helperPatternsOf(typed map[string][]InferredResource) map[string][]string {
+	patterns := make(map[string][]string, len(typed))
+	for funcName, blocks := range typed {
+		for _, block := range blocks {
+			patterns[funcName] = append(patterns[funcName], block.ResourceType)
+		}
+	}
 	return patterns
 }
 
 // buildTypedHelperPatternMap builds a map from helper function names to typed HCL blocks.
 // This preserves both block type (resource/data/action) and resource type information.
 func buildTypedHelperPatternMap(file *ast.File) map[string][]InferredResource {
+	return buildTypedHelperPatternMapWithTemplates(file, nil)
+}
+
+// buildTypedHelperPatternMapWithTemplates is like buildTypedHelperPatternMap but also resolves
+// identifiers that refer to package-level string templates (const, var, or //go:embed).
+func buildTypedHelperPatternMapWithTemplates(file *ast.File, templates map[string][]InferredResource) map[string][]InferredResource {
 	patterns := make(map[string][]InferredResource)
 
 	ast.Inspect(file, func(n ast.Node) bool {
@@ -1635,7 +1746,7 @@ func buildTypedHelperPatternMap(file *ast.File) map[string][]InferredResource {
 			}
 
 			for _, result := range ret.Results {
-				extractTypedPatternsFromExpr(result, func(block InferredResource) {
+				extractTypedPatternsFromExprWithTemplates(result, templates, func(block InferredResource) {
 					patterns[funcName] = append(patterns[funcName], block)
 				})
 			}
@@ -1659,19 +1770,23 @@ func extractPatternsFromExpr(expr ast.Expr, addPattern func(string)) {
 // It handles string literals, any function calls with string arguments (fmt.Sprintf, acctest.Nprintf, etc.),
 // and string concatenation.
 func extractTypedPatternsFromExpr(expr ast.Expr, addBlock func(InferredResource)) {
+	extractTypedPatternsFromExprWithTemplates(expr, nil, addBlock)
+}
+
+// extractTypedPatternsFromExprWithTemplates is like extractTypedPatternsFromExpr but also
+// resolves identifiers through templates, the typed blocks of package-level string templates.
+func extractTypedPatternsFromExprWithTemplates(expr ast.Expr, templates map[string][]InferredResource, addBlock func(InferredResource)) {
 	switch e := expr.(type) {
+	case *ast.Ident:
+		for _, block := range templates[e.Name] {
+			addBlock(block)
+		}
 	case *ast.BasicLit:
 		if e.Kind == token.STRING {
 			content := strings.Trim(e.Value, "`\"")
-			// Use HCLBlockRegex to capture both block type and resource type
-			matches := HCLBlockRegex.FindAllStringSubmatch(content, -1)
-			for _, match := range matches {
-				if len(match) > 2 {
-					addBlock(InferredResource{
-						BlockType:    match[1], // "resource", "data", or "action"
-						ResourceType: match[2], // e.g., "aws_instance"
-					})
-				}
+			// Scanned with HCLBlockRegex to capture both block type and resource type
+			for _, block := range hclBlocks(content) {
+				addBlock(block)
 			}
 		}
 	case *ast.CallExpr:
@@ -1679,7 +1794,7 @@ func extractTypedPatternsFromExpr(expr ast.Expr, addBlock func(InferredResource)
 		// This generically handles: fmt.Sprintf, acctest.Nprintf, custom helpers, etc.
 		// Process all arguments - many formatting functions take the template as first arg
 		for _, arg := range e.Args {
-			extractTypedPatternsFromExpr(arg, addBlock)
+			extractTypedPatternsFromExprWithTemplates(arg, templates, addBlock)
 		}
 		// Also check if it's a function identifier (simple function call like myConfig())
 		if ident, ok := e.Fun.(*ast.Ident); ok {
@@ -1688,21 +1803,26 @@ func extractTypedPatternsFromExpr(expr ast.Expr, addBlock func(InferredResource)
 	case *ast.BinaryExpr:
 		// Handle string concatenation
 		if e.Op == token.ADD {
-			extractTypedPatternsFromExpr(e.X, addBlock)
-			extractTypedPatternsFromExpr(e.Y, addBlock)
+			extractTypedPatternsFromExprWithTemplates(e.X, templates, addBlock)
+			extractTypedPatternsFromExprWithTemplates(e.Y, templates, addBlock)
 		}
 	}
 }
 
 // extractTestStepsWithHelpers is like extractTestSteps but also looks up helper patterns.
-// Returns: steps, hasCheckDestroy, hasPreCheck, inferredResources (legacy), inferredHCLBlocks (typed)
-func extractTestStepsWithHelpers(body *ast.BlockStmt, helperPatterns map[string][]string, typedHelperPatterns map[string][]InferredResource) ([]registry.TestStepInfo, bool, bool, []string, []registry.InferredHCLBlock) {
+// Steps returned by functions are resolved through lookupFunc, which may be nil.
+// Returns: steps, hasCheckDestroy, hasPreCheck, inferredResources (legacy), inferredHCLBlocks (typed),
+// and whether some steps could not be resolved statically (opaque)
+func extractTestStepsWithHelpers(body *ast.BlockStmt, helperPatterns map[string][]string, typedHelperPatterns map[string][]InferredResource, lookupFunc func(name string) *ast.FuncDecl) ([]registry.TestStepInfo, bool, bool, []string, []registry.InferredHCLBlock, bool) {
 	var steps []registry.TestStepInfo
 	var hasCheckDestroy bool
 	var hasPreCheck bool
+	var opaque bool
 	uniqueInferred := make(map[string]bool)
 	uniqueBlocks := make(map[string]registry.InferredHCLBlock) // key: "blockType:resourceType"
 	stepNumber := 1
+	stepVars := collectStepVariables(body, lookupFunc)
+	builders := testDataBuilders(body)
 
 	ast.Inspect(body, func(n ast.Node) bool {
 		callExpr, ok := n.(*ast.CallExpr)
@@ -1710,14 +1830,26 @@ func extractTestStepsWithHelpers(body *ast.BlockStmt, helperPatterns map[string]
 			return true
 		}
 
+		// A test-data builder runner, as in data.ResourceTest(t, r, steps), tests the definition
+		// the builder was built for; its steps are taken from the arguments below
+		if block, runner, ok := testDataRunnerCall(callExpr, builders); ok {
+			uniqueInferred[block.ResourceType] = true
+			uniqueBlocks[block.BlockType+":"+block.ResourceType] = block
+			hasPreCheck = true
+			if runner.checkDestroy {
+				hasCheckDestroy = true
+			}
+		}
+
 		// Check for resource.Test() or resource.ParallelTest()
 		if sel, ok := callExpr.Fun.(*ast.SelectorExpr); ok {
 			if ident, ok := sel.X.(*ast.Ident); ok {
 				if ident.Name == "resource" && (sel.Sel.Name == "Test" || sel.Sel.Name == "ParallelTest" || sel.Sel.Name == "UnitTest") {
 					// Direct resource.Test() call - TestCase is second argument
 					if len(callExpr.Args) >= 2 {
-						testSteps, foundCheckDestroy, foundPreCheck := extractStepsFromTestCaseWithHelpersTyped(callExpr.Args[1], &stepNumber, uniqueInferred, uniqueBlocks, helperPatterns, typedHelperPatterns)
+						testSteps, foundCheckDestroy, foundPreCheck, foundOpaque := extractStepsFromTestCaseWithHelpersTyped(callExpr.Args[1], &stepNumber, uniqueInferred, uniqueBlocks, helperPatterns, typedHelperPatterns, stepVars)
 						steps = append(steps, testSteps...)
+						opaque = opaque || foundOpaque
 						if foundCheckDestroy {
 							hasCheckDestroy = true
 						}
@@ -1733,13 +1865,22 @@ func extractTestStepsWithHelpers(body *ast.BlockStmt, helperPatterns map[string]
 		// Also check for wrapper functions like acctest.VcrTest(t, resource.TestCase{...})
 		// Look for resource.TestCase composite literals in any function call arguments
 		for _, arg := range callExpr.Args {
+			// A step slice variable or step-returning call passed directly, as in td.ResourceTest(t, steps)
+			if _, ok := arg.(*ast.CompositeLit); !ok && !isBuiltinCall(callExpr) {
+				if resolved, ok := stepVars.resolve(arg, callExpr.Pos()); ok {
+					steps = append(steps, extractStepsFromExprs(resolved.exprs, &stepNumber, uniqueInferred, uniqueBlocks, helperPatterns, typedHelperPatterns)...)
+					opaque = opaque || resolved.opaque
+				}
+				continue
+			}
 			if compLit, ok := arg.(*ast.CompositeLit); ok {
 				// Check if it's a resource.TestCase type
 				if sel, ok := compLit.Type.(*ast.SelectorExpr); ok {
 					if ident, ok := sel.X.(*ast.Ident); ok {
 						if ident.Name == "resource" && sel.Sel.Name == "TestCase" {
-							testSteps, foundCheckDestroy, foundPreCheck := extractStepsFromTestCaseWithHelpersTyped(compLit, &stepNumber, uniqueInferred, uniqueBlocks, helperPatterns, typedHelperPatterns)
+							testSteps, foundCheckDestroy, foundPreCheck, foundOpaque := extractStepsFromTestCaseWithHelpersTyped(compLit, &stepNumber, uniqueInferred, uniqueBlocks, helperPatterns, typedHelperPatterns, stepVars)
 							steps = append(steps, testSteps...)
+							opaque = opaque || foundOpaque
 							if foundCheckDestroy {
 								hasCheckDestroy = true
 							}
@@ -1750,11 +1891,12 @@ func extractTestStepsWithHelpers(body *ast.BlockStmt, helperPatterns map[string]
 					}
 				}
 				// Also check for []resource.TestStep slice literals passed directly
-				// This handles patterns like td.ResourceTest(t, []resource.TestStep{...})
+				// This handles patterns like td.ResourceTest(t, []resource.TestStep{...}), and
+				// the acceptance.TestStep alias of test-data builders
 				if arrayType, ok := compLit.Type.(*ast.ArrayType); ok {
 					if sel, ok := arrayType.Elt.(*ast.SelectorExpr); ok {
 						if ident, ok := sel.X.(*ast.Ident); ok {
-							if ident.Name == "resource" && sel.Sel.Name == "TestStep" {
+							if (ident.Name == "resource" || ident.Name == "acceptance") && sel.Sel.Name == "TestStep" {
 								// Extract steps directly from the slice literal
 								extractedSteps := extractStepsFromSliceLiteral(compLit, &stepNumber, uniqueInferred, uniqueBlocks, helperPatterns, typedHelperPatterns)
 								steps = append(steps, extractedSteps...)
@@ -1778,25 +1920,30 @@ func extractTestStepsWithHelpers(body *ast.BlockStmt, helperPatterns map[string]
 		inferredBlocks = append(inferredBlocks, block)
 	}
 
-	return steps, hasCheckDestroy, hasPreCheck, inferredResources, inferredBlocks
+	return steps, hasCheckDestroy, hasPreCheck, inferredResources, inferredBlocks, opaque
 }
 
 // extractStepsFromTestCaseWithHelpers extracts steps and looks up helper patterns.
 func extractStepsFromTestCaseWithHelpers(testCaseExpr ast.Expr, stepNumber *int, inferred map[string]bool, helperPatterns map[string][]string) ([]registry.TestStepInfo, bool, bool) {
 	// Delegate to typed version and ignore the blocks
 	blocks := make(map[string]registry.InferredHCLBlock)
-	return extractStepsFromTestCaseWithHelpersTyped(testCaseExpr, stepNumber, inferred, blocks, helperPatterns, nil)
+	steps, hasCheckDestroy, hasPreCheck, _ := extractStepsFromTestCaseWithHelpersTyped(testCaseExpr, stepNumber, inferred, blocks, helperPatterns, nil, nil)
+	return steps, hasCheckDestroy, hasPreCheck
 }
 
 // extractStepsFromTestCaseWithHelpersTyped extracts steps with typed HCL block information.
-func extractStepsFromTestCaseWithHelpersTyped(testCaseExpr ast.Expr, stepNumber *int, inferred map[string]bool, blocks map[string]registry.InferredHCLBlock, helperPatterns map[string][]string, typedHelperPatterns map[string][]InferredResource) ([]registry.TestStepInfo, bool, bool) {
+// Steps given as a local slice variable (Steps: steps) or a helper call are resolved through
+// stepVars. The final result reports opaque steps: a TestCase or Steps value that cannot be
+// resolved statically, so the step count is unknown rather than zero.
+func extractStepsFromTestCaseWithHelpersTyped(testCaseExpr ast.Expr, stepNumber *int, inferred map[string]bool, blocks map[string]registry.InferredHCLBlock, helperPatterns map[string][]string, typedHelperPatterns map[string][]InferredResource, stepVars *stepVariables) ([]registry.TestStepInfo, bool, bool, bool) {
 	var steps []registry.TestStepInfo
 	hasCheckDestroy := false
 	hasPreCheck := false
+	opaque := false
 
 	compLit, ok := testCaseExpr.(*ast.CompositeLit)
 	if !ok {
-		return steps, hasCheckDestroy, hasPreCheck
+		return steps, hasCheckDestroy, hasPreCheck, true
 	}
 
 	for _, elt := range compLit.Elts {
@@ -1816,12 +1963,17 @@ func extractStepsFromTestCaseWithHelpersTyped(testCaseExpr ast.Expr, stepNumber
 		case "PreCheck":
 			hasPreCheck = true
 		case "Steps":
-			stepsLit, ok := kv.Value.(*ast.CompositeLit)
-			if !ok {
-				continue
+			var stepExprs []ast.Expr
+			if stepsLit, ok := kv.Value.(*ast.CompositeLit); ok {
+				stepExprs = stepsLit.Elts
+			} else if resolved, ok := stepVars.resolve(kv.Value, kv.Pos()); ok {
+				stepExprs = resolved.exprs
+				opaque = resolved.opaque
+			} else {
+				opaque = true
 			}
 
-			for _, stepExpr := range stepsLit.Elts {
+			for _, stepExpr := range stepExprs {
 				step := parseTestStepWithHashAndHelpersTyped(stepExpr, *stepNumber, inferred, blocks, helperPatterns, typedHelperPatterns)
 				steps = append(steps, step)
 				*stepNumber++
@@ -1829,36 +1981,60 @@ func extractStepsFromTestCaseWithHelpersTyped(testCaseExpr ast.Expr, stepNumber
 		}
 	}
 
-	for i := range steps {
-		if i > 0 {
-			steps[i].PreviousConfigHash = steps[i-1].ConfigHash
-			steps[i].IsUpdateStepFlag = steps[i].DetermineIfUpdateStep(&steps[i-1])
-		}
-	}
+	markUpdateSteps(steps)
 
-	return steps, hasCheckDestroy, hasPreCheck
+	return steps, hasCheckDestroy, hasPreCheck, opaque
 }
 
 // extractStepsFromSliceLiteral extracts test steps directly from a []resource.TestStep slice literal.
 // This handles patterns like td.ResourceTest(t, []resource.TestStep{...}) where steps are passed directly.
 func extractStepsFromSliceLiteral(stepsLit *ast.CompositeLit, stepNumber *int, inferred map[string]bool, blocks map[string]registry.InferredHCLBlock, helperPatterns map[string][]string, typedHelperPatterns map[string][]InferredResource) []registry.TestStepInfo {
+	return extractStepsFromExprs(stepsLit.Elts, stepNumber, inferred, blocks, helperPatterns, typedHelperPatterns)
+}
+
+// extractStepsFromExprs extracts test steps from the elements of a step slice.
+func extractStepsFromExprs(stepExprs []ast.Expr, stepNumber *int, inferred map[string]bool, blocks map[string]registry.InferredHCLBlock, helperPatterns map[string][]string, typedHelperPatterns map[string][]InferredResource) []registry.TestStepInfo {
 	var steps []registry.TestStepInfo
 
-	for _, stepExpr := range stepsLit.Elts {
+	for _, stepExpr := range stepExprs {
 		step := parseTestStepWithHashAndHelpersTyped(stepExpr, *stepNumber, inferred, blocks, helperPatterns, typedHelperPatterns)
 		steps = append(steps, step)
 		*stepNumber++
 	}
 
-	// Set previous config hashes for update step detection
+	markUpdateSteps(steps)
+
+	return steps
+}
+
+// markUpdateSteps sets the previous config hash and update flag of each step. Import and
+// refresh steps apply no config, so a step after them is compared with the last step that did.
+func markUpdateSteps(steps []registry.TestStepInfo) {
+	prev := -1
 	for i := range steps {
-		if i > 0 {
-			steps[i].PreviousConfigHash = steps[i-1].ConfigHash
-			steps[i].IsUpdateStepFlag = steps[i].DetermineIfUpdateStep(&steps[i-1])
+		if prev >= 0 {
+			steps[i].PreviousConfigHash = steps[prev].ConfigHash
+			steps[i].IsUpdateStepFlag = steps[i].DetermineIfUpdateStep(&steps[prev])
+		}
+		if steps[i].HasConfig && !steps[i].ImportState && !steps[i].RefreshState {
+			prev = i
 		}
 	}
+}
 
-	return steps
+// callsExpectEmptyPlan reports whether a ConfigPlanChecks value calls plancheck.ExpectEmptyPlan
+// in any of its phases.
+func callsExpectEmptyPlan(expr ast.Expr) bool {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "ExpectEmptyPlan" {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
 }
 
 // parseTestStepWithHashAndHelpers parses a step and looks up helper patterns for Config.
@@ -1873,6 +2049,12 @@ func parseTestStepWithHashAndHelpersTyped(stepExpr ast.Expr, stepNum int, inferr
 		StepNumber: stepNum,
 	}
 
+	if call, ok := stepExpr.(*ast.CallExpr); ok {
+		if builderStep, ok := testDataBuilderStep(call, stepNum); ok {
+			return builderStep
+		}
+	}
+
 	stepLit, ok := stepExpr.(*ast.CompositeLit)
 	if !ok {
 		return step
@@ -1897,9 +2079,12 @@ func parseTestStepWithHashAndHelpersTyped(stepExpr ast.Expr, stepNum int, inferr
 		case "Config":
 			step.HasConfig = true
 			step.ConfigHash = hashConfigExpr(kv.Value)
+			step.ConfigPos = kv.Value.Pos()
+			step.ConfigEnd = kv.Value.End()
 
 			// Extract typed HCL blocks
-			extractTypedPatternsFromExpr(kv.Value, func(block InferredResource) {
+			// Template identifiers are resolved too (e.g., fmt.Sprintf(testAccWidgetTemplate, name))
+			extractTypedPatternsFromExprWithTemplates(kv.Value, typedHelperPatterns, func(block InferredResource) {
 				if inferred != nil {
 					inferred[block.ResourceType] = true
 				}
@@ -1912,27 +2097,29 @@ func parseTestStepWithHashAndHelpersTyped(stepExpr ast.Expr, stepNum int, inferr
 				}
 			})
 
-			// If Config is a function call, look up helper patterns (both legacy and typed)
+			// If Config is a function call or template identifier, look up helper patterns (both legacy and typed)
+			configRef := kv.Value
 			if callExpr, ok := kv.Value.(*ast.CallExpr); ok {
-				if ident, ok := callExpr.Fun.(*ast.Ident); ok {
-					// Legacy string patterns (for InferredResources)
-					if patterns, exists := helperPatterns[ident.Name]; exists {
-						for _, p := range patterns {
-							if inferred != nil {
-								inferred[p] = true
-							}
+				configRef = callExpr.Fun
+			}
+			if ident, ok := configRef.(*ast.Ident); ok {
+				// Legacy string patterns (for InferredResources)
+				if patterns, exists := helperPatterns[ident.Name]; exists {
+					for _, p := range patterns {
+						if inferred != nil {
+							inferred[p] = true
 						}
 					}
-					// Typed patterns (for InferredHCLBlocks)
-					if typedHelperPatterns != nil {
-						if typedPatterns, exists := typedHelperPatterns[ident.Name]; exists {
-							for _, block := range typedPatterns {
-								if blocks != nil {
-									key := block.BlockType + ":" + block.ResourceType
-									blocks[key] = registry.InferredHCLBlock{
-										BlockType:    block.BlockType,
-										ResourceType: block.ResourceType,
-									}
+				}
+				// Typed patterns (for InferredHCLBlocks)
+				if typedHelperPatterns != nil {
+					if typedPatterns, exists := typedHelperPatterns[ident.Name]; exists {
+						for _, block := range typedPatterns {
+							if blocks != nil {
+								key := block.BlockType + ":" + block.ResourceType
+								blocks[key] = registry.InferredHCLBlock{
+									BlockType:    block.BlockType,
+									ResourceType: block.ResourceType,
 								}
 							}
 						}
@@ -1941,31 +2128,64 @@ func parseTestStepWithHashAndHelpersTyped(stepExpr ast.Expr, stepNum int, inferr
 			}
 		case "Check":
 			step.HasCheck = true
+			step.CheckPos = kv.Value.Pos()
 			step.CheckFunctions = extractCheckFunctions(kv.Value)
 		case "ImportState":
 			if ident, ok := kv.Value.(*ast.Ident); ok {
 				step.ImportState = ident.Name == "true"
 			}
+			step.ImportStatePos = kv.Pos()
 		case "ImportStateVerify":
 			if ident, ok := kv.Value.(*ast.Ident); ok {
 				step.ImportStateVerify = ident.Name == "true"
 			}
+		case "ImportStateVerifyIgnore":
+			step.HasImportStateVerifyIgnore = true
 		case "ExpectError":
 			step.ExpectError = true
+			step.ExpectErrorPos = kv.Value.Pos()
+			step.ExpectErrorPattern, step.HasExpectErrorPattern = expectErrorPattern(kv.Value)
 		case "ExpectNonEmptyPlan":
 			if ident, ok := kv.Value.(*ast.Ident); ok {
 				step.ExpectNonEmptyPlan = ident.Name == "true"
 			}
+		case "PlanOnly":
+			if ident, ok := kv.Value.(*ast.Ident); ok {
+				step.PlanOnly = ident.Name == "true"
+			}
 		case "RefreshState":
 			if ident, ok := kv.Value.(*ast.Ident); ok {
 				step.RefreshState = ident.Name == "true"
 			}
+		case "RefreshPlanChecks":
+			// Plan checks run after the refresh of a RefreshState step
+			step.HasRefreshPlanCheck = true
+		case "ImportStatePersist":
+			if ident, ok := kv.Value.(*ast.Ident); ok {
+				step.ImportStatePersist = ident.Name == "true"
+			}
+		case "AdditionalCLIOptions":
+			step.AllowsDeferral = allowsDeferral(kv.Value)
+		case "ExternalProviders":
+			step.ExternalProviders = externalProviders(kv.Value)
 		case "ConfigPlanChecks":
 			// Detect ConfigPlanChecks field (plan validation)
 			step.HasPlanCheck = true
+			step.ExpectsEmptyPlan = callsExpectEmptyPlan(kv.Value)
 		case "ConfigStateChecks":
 			// Detect ConfigStateChecks field (newer state validation pattern)
 			step.HasConfigStateChecks = true
+			step.StateCheckPos = kv.Value.Pos()
+		case "ImportStateIdFunc":
+			// Attributes are resolved later, once the function can be looked up by name
+			step.HasImportStateIdFunc = true
+			step.ImportStateIdFuncPos = kv.Value.Pos()
+			step.ImportStateIdFunc = importStateIdFuncName(kv.Value)
+		case "ImportStateCheck":
+			// Like ImportStateIdFunc, the function is inspected once it can be looked up by name
+			step.HasImportStateCheck = true
+			step.ImportStateCheckPos = kv.Value.Pos()
+			step.ImportStateCheck = importStateIdFuncName(kv.Value)
 		}
 	}
 
@@ -2023,7 +2243,13 @@ func CheckHasSweepers(file *ast.File) bool {
 
 // ParseResources is the public API for parsing resources from a file.
 func ParseResources(file *ast.File, fset *token.FileSet, filePath string) []*registry.ResourceInfo {
-	return parseResources(file, fset, filePath)
+	return parseResources(file, fset, filePath, nil)
+}
+
+// ParseResourcesWithNames is ParseResources with the pipeline that canonicalizes the names
+// derived from Go type and function names, such as Settings.NamePipeline.
+func ParseResourcesWithNames(file *ast.File, fset *token.FileSet, filePath string, names *naming.Pipeline) []*registry.ResourceInfo {
+	return parseResources(file, fset, filePath, names)
 }
 
 // ParseTestFile is the public API for parsing test files.
@@ -2041,6 +2267,11 @@ func FindLocalTestHelpers(files []*ast.File, fset *token.FileSet) []LocalHelper
 	return findLocalTestHelpers(files, fset)
 }
 
+// FindExportedTestHelpers is the public API for discovering helpers exported by a helper package.
+func FindExportedTestHelpers(files []*ast.File, fset *token.FileSet) []LocalHelper {
+	return findExportedTestHelpers(files, fset)
+}
+
 // AcceptsTestingT is the public API for checking if a function accepts *testing.T.
 func AcceptsTestingT(funcDecl *ast.FuncDecl) bool {
 	return acceptsTestingT(funcDecl)
@@ -2080,6 +2311,34 @@ func ParseProviderRegistryMaps(file *ast.File, fset *token.FileSet, filePath str
 	var resources []*registry.ResourceInfo
 	seen := make(map[string]bool)
 
+	forEachRegistryMapEntry(file, func(kind registry.ResourceKind, resourceName string, keyLit *ast.BasicLit, _ ast.Expr) {
+		// Use the full resource name from the registry map
+		// This is the actual Terraform resource type (e.g., "google_bigquery_table")
+		// which matches what appears in HCL configs
+
+		// Skip if already seen (using full name)
+		key := registry.ResourceKey{Kind: kind, Name: resourceName}.String()
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+
+		resources = append(resources, &registry.ResourceInfo{
+			Name:         resourceName,
+			Kind:         kind,
+			FilePath:     filePath,
+			SchemaPos:    keyLit.Pos(),
+			DiscoveredBy: "ProviderRegistryMap",
+		})
+	})
+
+	return resources
+}
+
+// forEachRegistryMapEntry calls fn for each entry of the provider registry map literals in a
+// file (see ParseProviderRegistryMaps) whose key looks like a Terraform type name, with the
+// entry's value (e.g., the compute.ResourceComputeInstance() call).
+func forEachRegistryMapEntry(file *ast.File, fn func(kind registry.ResourceKind, name string, keyLit *ast.BasicLit, value ast.Expr)) {
 	ast.Inspect(file, func(n ast.Node) bool {
 		// Look for variable declarations with map literal values
 		genDecl, ok := n.(*ast.GenDecl)
@@ -2156,29 +2415,11 @@ func ParseProviderRegistryMaps(file *ast.File, fset *token.FileSet, filePath str
 						continue
 					}
 
-					// Use the full resource name from the registry map
-					// This is the actual Terraform resource type (e.g., "google_bigquery_table")
-					// which matches what appears in HCL configs
-
-					// Skip if already seen (using full name)
-					key := kind.String() + ":" + resourceName
-					if seen[key] {
-						continue
-					}
-					seen[key] = true
-
-					resources = append(resources, &registry.ResourceInfo{
-						Name:      resourceName,
-						Kind:      kind,
-						FilePath:  filePath,
-						SchemaPos: keyLit.Pos(),
-					})
+					fn(kind, resourceName, keyLit, kv.Value)
 				}
 			}
 		}
 
 		return true
 	})
-
-	return resources
 }
@@ -0,0 +1,298 @@
+package discovery
+
+import (
+	"go/ast"
+	"go/token"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// frameworkProviderPackage is the import path of the plugin-framework provider package.
+const frameworkProviderPackage = "github.com/hashicorp/terraform-plugin-framework/provider"
+
+// providerBlockRegex finds the opening of a provider block in an HCL config.
+var providerBlockRegex = regexp.MustCompile(`provider\s+"[^"]+"\s*\{`)
+
+// providerAliasRegex captures the alias set in a provider block. The alias attribute is only
+// valid in provider blocks, so it is matched without parsing the enclosing block.
+var providerAliasRegex = regexp.MustCompile(`(?m)^\s*alias\s*=\s*"([^"]+)"`)
+
+// providerAttrRegex captures an attribute or nested block name at the start of an HCL line.
+var providerAttrRegex = regexp.MustCompile(`^\s*([A-Za-z_][A-Za-z0-9_]*)\s*(?:=|\{)`)
+
+// ParseProvider looks for the provider implementation in a file: a type whose Schema method
+// takes a provider.SchemaRequest. It returns nil if the file does not define a provider.
+func ParseProvider(file *ast.File, filePath string) *registry.ProviderInfo {
+	importAliases := extractImportAliases(file)
+
+	var info *registry.ProviderInfo
+	var recvType string
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Recv == nil || funcDecl.Name.Name != "Schema" {
+			continue
+		}
+		if !takesProviderRequest(funcDecl, "SchemaRequest", importAliases) {
+			continue
+		}
+
+		recvType = getReceiverTypeName(funcDecl.Recv)
+		info = &registry.ProviderInfo{
+			TypeNameHint: recvType,
+			FilePath:     filePath,
+			SchemaPos:    funcDecl.Pos(),
+		}
+		for _, attr := range extractAttributes(funcDecl.Body) {
+			if attr != nil {
+				info.Attributes = append(info.Attributes, *attr)
+			}
+		}
+		break
+	}
+	if info == nil {
+		return nil
+	}
+
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Recv == nil || funcDecl.Body == nil {
+			continue
+		}
+		if getReceiverTypeName(funcDecl.Recv) != recvType {
+			continue
+		}
+		switch funcDecl.Name.Name {
+		case "Metadata":
+			info.TypeName = extractProviderTypeName(funcDecl.Body)
+		case "Configure":
+			info.ConfigurePos = funcDecl.Pos()
+			info.EnvVars = extractEnvVarReads(funcDecl.Body)
+			info.DeferredPos = deferredResponsePos(funcDecl)
+		}
+	}
+
+	return info
+}
+
+// takesProviderRequest reports whether a method's second parameter is the named request type
+// from the plugin-framework provider package (e.g., provider.SchemaRequest).
+func takesProviderRequest(funcDecl *ast.FuncDecl, requestType string, importAliases map[string]string) bool {
+	params := funcDecl.Type.Params
+	if params == nil || len(params.List) < 2 {
+		return false
+	}
+
+	// Parameters may be grouped, so flatten them to find the second one
+	var types []ast.Expr
+	for _, field := range params.List {
+		count := len(field.Names)
+		if count == 0 {
+			count = 1
+		}
+		for i := 0; i < count; i++ {
+			types = append(types, field.Type)
+		}
+	}
+	if len(types) < 2 {
+		return false
+	}
+
+	sel, ok := types[1].(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != requestType {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	if importPath, known := importAliases[pkg.Name]; known {
+		return importPath == frameworkProviderPackage
+	}
+	return pkg.Name == "provider"
+}
+
+// extractProviderTypeName returns the string literal assigned to resp.TypeName in a provider's
+// Metadata method. Unlike resource type names, the provider type name has no prefix to strip.
+func extractProviderTypeName(body *ast.BlockStmt) string {
+	name := ""
+	ast.Inspect(body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			return true
+		}
+		sel, ok := assign.Lhs[0].(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "TypeName" {
+			return true
+		}
+		if lit, ok := assign.Rhs[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			if value, err := strconv.Unquote(lit.Value); err == nil {
+				name = value
+				return false
+			}
+		}
+		return true
+	})
+	return name
+}
+
+// extractEnvVarReads returns the environment variables read via os.Getenv or os.LookupEnv.
+func extractEnvVarReads(body *ast.BlockStmt) []string {
+	return collectEnvVarCalls(body, map[string]bool{"Getenv": true, "LookupEnv": true})
+}
+
+// extractEnvVarWrites returns the environment variables set via t.Setenv or os.Setenv.
+func extractEnvVarWrites(body *ast.BlockStmt) []string {
+	return collectEnvVarCalls(body, map[string]bool{"Setenv": true})
+}
+
+// collectEnvVarCalls returns the sorted, de-duplicated string literal first arguments of
+// calls to any of the given selector method names.
+func collectEnvVarCalls(body *ast.BlockStmt, methods map[string]bool) []string {
+	if body == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || !methods[sel.Sel.Name] {
+			return true
+		}
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		if value, err := strconv.Unquote(lit.Value); err == nil && value != "" {
+			seen[value] = true
+		}
+		return true
+	})
+
+	return slices.Sorted(maps.Keys(seen))
+}
+
+// extractProviderConfigAttributes returns the attributes set in provider blocks of the HCL
+// configs a test function uses (see providerConfigs).
+func extractProviderConfigAttributes(configs []string) []string {
+	seen := make(map[string]bool)
+	for _, config := range configs {
+		for _, attr := range providerAttrScans.get(config) {
+			seen[attr] = true
+		}
+	}
+	return slices.Sorted(maps.Keys(seen))
+}
+
+// extractProviderAliases returns the aliases of the provider blocks in the HCL configs a test
+// function uses (see providerConfigs), e.g. "peer" for provider "example" { alias = "peer" }.
+func extractProviderAliases(configs []string) []string {
+	seen := make(map[string]bool)
+	for _, config := range configs {
+		for _, alias := range providerAliasScans.get(config) {
+			seen[alias] = true
+		}
+	}
+	return slices.Sorted(maps.Keys(seen))
+}
+
+// providerConfigs returns the HCL configs a test function uses: string literals in the test
+// body, package templates it references, and same-file functions it calls (one level deep).
+func providerConfigs(body *ast.BlockStmt, fileFuncs map[string]*ast.FuncDecl, templates map[string]string) []string {
+	if body == nil {
+		return nil
+	}
+
+	var configs []string
+	collect := func(node ast.Node) {
+		ast.Inspect(node, func(n ast.Node) bool {
+			switch e := n.(type) {
+			case *ast.BasicLit:
+				if e.Kind == token.STRING {
+					if value, err := strconv.Unquote(e.Value); err == nil {
+						configs = append(configs, value)
+					}
+				}
+			case *ast.Ident:
+				if content, ok := templates[e.Name]; ok {
+					configs = append(configs, content)
+				}
+			}
+			return true
+		})
+	}
+
+	collect(body)
+	visited := make(map[string]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		ident, ok := call.Fun.(*ast.Ident)
+		if !ok || visited[ident.Name] {
+			return true
+		}
+		if helper, ok := fileFuncs[ident.Name]; ok && helper.Body != nil {
+			visited[ident.Name] = true
+			collect(helper.Body)
+		}
+		return true
+	})
+	return configs
+}
+
+// parseProviderBlockAttributes returns the top-level attribute and nested block names set
+// inside every provider block in an HCL config.
+func parseProviderBlockAttributes(config string) []string {
+	var attrs []string
+	for _, loc := range providerBlockRegex.FindAllStringIndex(config, -1) {
+		depth := 1
+		lineStart := loc[1]
+		for i := loc[1]; i < len(config) && depth > 0; i++ {
+			switch config[i] {
+			case '{':
+				if depth == 1 {
+					if m := providerAttrRegex.FindStringSubmatch(config[lineStart : i+1]); m != nil {
+						attrs = append(attrs, m[1])
+					}
+				}
+				depth++
+			case '}':
+				if depth == 1 {
+					// Closing brace of the provider block may share a line with an attribute
+					if m := providerAttrRegex.FindStringSubmatch(config[lineStart:i]); m != nil {
+						attrs = append(attrs, m[1])
+					}
+				}
+				depth--
+			case '\n':
+				if depth == 1 {
+					if m := providerAttrRegex.FindStringSubmatch(config[lineStart:i]); m != nil {
+						attrs = append(attrs, m[1])
+					}
+				}
+				lineStart = i + 1
+			}
+		}
+	}
+	return attrs
+}
+
+// fileFunctions indexes the top-level (non-method) functions declared in a file by name.
+func fileFunctions(file *ast.File) map[string]*ast.FuncDecl {
+	funcs := make(map[string]*ast.FuncDecl)
+	for _, decl := range file.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv == nil {
+			funcs[funcDecl.Name.Name] = funcDecl
+		}
+	}
+	return funcs
+}
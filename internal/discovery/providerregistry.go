@@ -0,0 +1,98 @@
+package discovery
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/matching"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// Phases of BuildProviderRegistry, passed to its lap function as each completes.
+const (
+	PhaseDiscovery = "discovery"
+	PhaseLinking   = "linking"
+)
+
+// BuildProviderRegistry discovers the definitions and test functions of files and links
+// them, as the validate CLI scans a provider: unlike BuildRegistry, which runs on the
+// packages of an analysis pass, it also reads provider registry maps and classifies tests,
+// so provider and function tests are not unmatched. lap, when not nil, is called with each
+// phase as it completes.
+func BuildProviderRegistry(fset *token.FileSet, files []*ast.File, settings config.Settings, lap func(phase string)) *registry.ResourceRegistry {
+	if lap == nil {
+		lap = func(string) {}
+	}
+	reg := registry.NewResourceRegistry()
+	ObserveHooks(reg, settings, fset)
+	parserConfig := DefaultParserConfig()
+	parserConfig.CustomHelpers = CustomHelpers(settings, fset, files)
+	classifier := FileClassifier(settings)
+	parserConfig.PackageTemplates = CollectPackageTemplates(files, fset)
+	parserConfig.PackageFunctions = CollectPackageFunctions(files)
+	parserConfig.ConditionalGuards = ConditionalGuards(settings)
+	names, _ := settings.NamePipeline()
+
+	for _, file := range files {
+		filePath := fset.Position(file.Pos()).Filename
+
+		// Apply exclusion settings
+		if ExclusionReason(settings, classifier, filePath) != "" {
+			continue
+		}
+
+		if strings.HasSuffix(filePath, "_test.go") {
+			testInfo := ParseTestFileWithConfig(file, fset, filePath, parserConfig)
+			if testInfo == nil {
+				continue
+			}
+			for i := range testInfo.TestFunctions {
+				reg.RegisterTestFunction(&testInfo.TestFunctions[i])
+			}
+		} else {
+			// Standard resource parsing (from Schema/Metadata methods)
+			resources := ParseResourcesWithNames(file, fset, filePath, names)
+			for _, err := range ApplyDirectives(file, resources) {
+				reg.AddDirectiveError(err)
+			}
+			for _, resource := range resources {
+				reg.RegisterResource(resource)
+			}
+
+			// Also check for provider registry maps (e.g., generatedResources, generatedIAMDatasources)
+			// This handles providers like Google that define resources in central map variables
+			registryResources := ParseProviderRegistryMaps(file, fset, filePath)
+			for _, resource := range registryResources {
+				reg.RegisterResource(resource)
+			}
+
+			if reg.GetProvider() == nil {
+				if provider := ParseProvider(file, filePath); provider != nil {
+					reg.SetProvider(provider)
+				}
+			}
+		}
+	}
+
+	ApplyEmbeddedMetadata(reg, fset, files, names)
+	ResolveRegistryMapTargets(reg, fset, files)
+	ApplyFactoryAliases(reg, fset, files)
+	ApplyRequirements(reg, settings, fset, files)
+	ApplyMaturity(reg, settings)
+	ApplyCategories(reg, fset, files)
+	ApplyQuarantine(reg)
+	lap(PhaseDiscovery)
+
+	// Run linking
+	linker := matching.NewLinker(reg, &settings)
+	linker.LinkTestsToResources()
+
+	// Classify all tests to enable filtering of orphans
+	linker.ClassifyAllTests()
+	ApplyResourceScope(reg, settings)
+	lap(PhaseLinking)
+
+	return reg
+}
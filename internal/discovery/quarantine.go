@@ -0,0 +1,60 @@
+package discovery
+
+import (
+	"go/ast"
+	"strconv"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// directiveQuarantine marks a test function as quarantined: known to be flaky or disabled,
+// so it earns no coverage credit. Unlike the other directives it documents a test, not a
+// definition:
+//
+//	//tftest:quarantine [reason="..."]
+const directiveQuarantine = "quarantine"
+
+// quarantineDirective reports whether a test function's doc comment quarantines it, and
+// the reason given. An option other than reason, or a reason that cannot be unquoted, is
+// kept as part of the reason rather than dropping the quarantine.
+func quarantineDirective(funcDecl *ast.FuncDecl) (reason string, ok bool) {
+	if funcDecl.Doc == nil {
+		return "", false
+	}
+	for _, comment := range funcDecl.Doc.List {
+		text, found := strings.CutPrefix(comment.Text, "//")
+		if !found {
+			continue
+		}
+		text, found = strings.CutPrefix(strings.TrimSpace(text), directivePrefix+directiveQuarantine)
+		if !found || (text != "" && text[0] != ' ' && text[0] != '\t') {
+			continue
+		}
+		args := strings.TrimSpace(text)
+		value, isReason := strings.CutPrefix(args, directiveOptionReason+"=")
+		if !isReason {
+			return args, true
+		}
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			return unquoted, true
+		}
+		return value, true
+	}
+	return "", false
+}
+
+// ApplyQuarantine marks the registered tests the registry's quarantine list names as
+// quarantined. It must run before linking, which keeps quarantined tests apart from the
+// tests that earn coverage credit.
+func ApplyQuarantine(reg *registry.ResourceRegistry) {
+	for _, fn := range reg.GetAllTestFunctions() {
+		if fn.Quarantined {
+			continue
+		}
+		if reason, ok := reg.QuarantineReason(fn.Name); ok {
+			fn.Quarantined = true
+			fn.QuarantineReason = reason
+		}
+	}
+}
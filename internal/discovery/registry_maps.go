@@ -0,0 +1,152 @@
+package discovery
+
+import (
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// registryMapTarget is a top-level function a registry map entry may call to construct its
+// definition.
+type registryMapTarget struct {
+	decl *ast.FuncDecl
+	file string
+	dir  string
+}
+
+// ResolveRegistryMapTargets moves definitions found by ParseProviderRegistryMaps from the
+// map key to the function that implements them, so diagnostics point at the resource's own
+// file. An entry's value must call a function declared in the scanned files:
+//
+//	"google_compute_instance": compute.ResourceComputeInstance(),
+//
+// A call qualified by a package is resolved to the function of that name in the directory
+// that best matches the import path, and an unqualified call to the function in the map's
+// own directory. Unresolvable entries, such as functions of packages outside the scan or
+// ambiguous matches, keep the key position. The key stays available as RegisteredPos, and
+// a definition discovered from the function itself (an SDK v2 *schema.Resource factory)
+// lends its attributes and lifecycle details.
+func ResolveRegistryMapTargets(reg *registry.ResourceRegistry, fset *token.FileSet, files []*ast.File) {
+	targets := make(map[string][]registryMapTarget)
+	for _, file := range files {
+		path := fset.Position(file.Pos()).Filename
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		for _, decl := range file.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv == nil {
+				targets[funcDecl.Name.Name] = append(targets[funcDecl.Name.Name], registryMapTarget{decl: funcDecl, file: path, dir: filepath.Dir(path)})
+			}
+		}
+	}
+
+	for _, file := range files {
+		path := fset.Position(file.Pos()).Filename
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		forEachRegistryMapEntry(file, func(kind registry.ResourceKind, name string, keyLit *ast.BasicLit, value ast.Expr) {
+			def := reg.GetDefinition(kind, name)
+			if def == nil || def.SchemaPos != keyLit.Pos() {
+				return
+			}
+			target, ok := resolveRegistryMapTarget(file, filepath.Dir(path), value, targets)
+			if !ok {
+				return
+			}
+
+			def.RegisteredPos = keyLit.Pos()
+			def.FilePath = target.file
+			def.SchemaPos = target.decl.Pos()
+			for _, impl := range reg.GetAllDefinitions() {
+				if impl == def || impl.Factory != target.decl.Name.Name || impl.FilePath != target.file {
+					continue
+				}
+				if len(def.Attributes) == 0 {
+					def.Attributes = impl.Attributes
+				}
+				def.HasImportState = impl.HasImportState
+				def.ImportStatePos = impl.ImportStatePos
+				def.Operations = impl.Operations
+				def.Methods = impl.Methods
+				def.SchemaVersion, def.VersionPos = impl.SchemaVersion, impl.VersionPos
+				break
+			}
+		})
+	}
+}
+
+// resolveRegistryMapTarget returns the function a registry map value calls, or false when
+// the call cannot be resolved to exactly one scanned function.
+func resolveRegistryMapTarget(file *ast.File, dir string, value ast.Expr, targets map[string][]registryMapTarget) (registryMapTarget, bool) {
+	call, ok := value.(*ast.CallExpr)
+	if !ok {
+		return registryMapTarget{}, false
+	}
+
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		for _, target := range targets[fun.Name] {
+			if target.dir == dir {
+				return target, true
+			}
+		}
+	case *ast.SelectorExpr:
+		pkg, ok := fun.X.(*ast.Ident)
+		if !ok {
+			return registryMapTarget{}, false
+		}
+		importPath := importPathFor(file, pkg.Name)
+		if importPath == "" {
+			return registryMapTarget{}, false
+		}
+		elems := strings.Split(importPath, "/")
+		var best registryMapTarget
+		bestShared, ambiguous := 0, false
+		for _, target := range targets[fun.Sel.Name] {
+			shared := SharedSuffix(strings.Split(filepath.ToSlash(target.dir), "/"), elems)
+			switch {
+			case shared > bestShared:
+				best, bestShared, ambiguous = target, shared, false
+			case shared == bestShared && shared > 0:
+				ambiguous = true
+			}
+		}
+		if bestShared > 0 && !ambiguous {
+			return best, true
+		}
+	}
+	return registryMapTarget{}, false
+}
+
+// importPathFor returns the path of the import a file refers to by name, using the
+// import's alias or else the last element of its path, or "" when no import matches.
+func importPathFor(file *ast.File, name string) string {
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		local := path[strings.LastIndex(path, "/")+1:]
+		if spec.Name != nil {
+			local = spec.Name.Name
+		}
+		if local == name {
+			return path
+		}
+	}
+	return ""
+}
+
+// SharedSuffix counts the trailing elements two paths have in common.
+func SharedSuffix(a, b []string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[len(a)-1-n] == b[len(b)-1-n] {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,71 @@
+package discovery
+
+import (
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/internal/requirements"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// ApplyRequirements merges the requirements manifest selected by settings into the coverage
+// directives of the registry's definitions. Without an explicit requirements-manifest, the
+// manifest is looked up from the directory of the first file up to the module root. A
+// manifest that cannot be loaded is recorded as a requirement issue.
+func ApplyRequirements(reg *registry.ResourceRegistry, settings config.Settings, fset *token.FileSet, files []*ast.File) {
+	if len(files) == 0 {
+		return
+	}
+	dir := filepath.Dir(fset.Position(files[0].Pos()).Filename)
+	path := requirements.Resolve(settings.RequirementsManifest, dir)
+	if path == "" {
+		return
+	}
+
+	manifest, err := requirements.Load(path)
+	if err != nil {
+		reg.AddRequirementIssue(registry.RequirementIssue{
+			Manifest: path,
+			Problem:  registry.RequirementInvalid,
+			Value:    err.Error(),
+		})
+		return
+	}
+	requirements.Apply(reg, manifest)
+}
+
+// checksDisappears reports whether a test is a "disappears" test: one that deletes the
+// resource out of band and expects Terraform to plan its re-creation. Such tests are
+// recognized by name (TestAccWidget_disappears) or by a call to a check function whose
+// name contains "Disappears" (e.g., acctest.CheckResourceDisappears).
+func checksDisappears(name string, body *ast.BlockStmt) bool {
+	if strings.Contains(strings.ToLower(name), "disappears") {
+		return true
+	}
+	if body == nil {
+		return false
+	}
+
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return !found
+		}
+		var funcName string
+		switch fun := call.Fun.(type) {
+		case *ast.Ident:
+			funcName = fun.Name
+		case *ast.SelectorExpr:
+			funcName = fun.Sel.Name
+		}
+		if strings.Contains(funcName, "Disappears") {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
@@ -0,0 +1,125 @@
+package discovery
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// maxCachedScans bounds the results each scan cache holds; a full cache starts over.
+const maxCachedScans = 4096
+
+// scanCache memoizes the result of scanning config strings with one scanner, keyed by the
+// string. Test files repeat the same configs (a shared helper, a const template, the same
+// literal in every test), and each test's configs are scanned by several extractions, so
+// most scans are of a string scanned before. Results are shared between callers, which
+// must not modify them.
+type scanCache[T any] struct {
+	mu      sync.Mutex
+	scan    func(string) T
+	results map[string]T
+}
+
+func newScanCache[T any](scan func(string) T) *scanCache[T] {
+	return &scanCache[T]{scan: scan, results: make(map[string]T)}
+}
+
+// get returns the result of scanning content, scanning it on the first request.
+func (c *scanCache[T]) get(content string) T {
+	c.mu.Lock()
+	result, ok := c.results[content]
+	c.mu.Unlock()
+	if ok {
+		return result
+	}
+
+	result = c.scan(content)
+	c.mu.Lock()
+	if len(c.results) >= maxCachedScans {
+		clear(c.results)
+	}
+	c.results[content] = result
+	c.mu.Unlock()
+	return result
+}
+
+// len returns how many results the cache holds.
+func (c *scanCache[T]) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.results)
+}
+
+// reset drops every result the cache holds.
+func (c *scanCache[T]) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.results)
+}
+
+// Scan caches of the config scanners. Slices are clipped, so a caller appending to a result
+// gets a copy rather than writing into the shared array.
+var (
+	hclBlockScans = newScanCache(func(content string) []InferredResource {
+		var blocks []InferredResource
+		for _, match := range HCLBlockRegex.FindAllStringSubmatch(content, -1) {
+			if len(match) > 2 {
+				blocks = append(blocks, InferredResource{
+					BlockType:    match[1], // "resource", "data", or "action"
+					ResourceType: match[2], // e.g., "aws_instance"
+				})
+			}
+		}
+		return slices.Clip(blocks)
+	})
+	configAttributeScans = newScanCache(configAttributes)
+	configAddressScans   = newScanCache(func(config string) []string { return slices.Clip(configAddresses(config)) })
+	fixtureValueScans    = newScanCache(func(config string) []registry.FixtureValue { return slices.Clip(parseFixtureValues(config)) })
+	providerAttrScans    = newScanCache(func(config string) []string { return slices.Clip(parseProviderBlockAttributes(config)) })
+	providerAliasScans   = newScanCache(func(config string) []string {
+		var aliases []string
+		for _, m := range providerAliasRegex.FindAllStringSubmatch(config, -1) {
+			aliases = append(aliases, m[1])
+		}
+		return slices.Clip(aliases)
+	})
+)
+
+// hclBlocks returns the typed HCL blocks declared in content.
+func hclBlocks(content string) []InferredResource {
+	return hclBlockScans.get(content)
+}
+
+// scanCaches lists the scan caches for ClearScanCaches and ScanCacheSize.
+var scanCaches = []interface {
+	len() int
+	reset()
+}{hclBlockScans, configAttributeScans, configAddressScans, fixtureValueScans, providerAttrScans, providerAliasScans}
+
+// ClearScanCaches drops the memoized config scans, so the next parse scans every config
+// again, as the first parse of a run does.
+func ClearScanCaches() {
+	for _, c := range scanCaches {
+		c.reset()
+	}
+}
+
+// ScanCacheSize returns the number of config scans memoized across the scan caches.
+func ScanCacheSize() int {
+	n := 0
+	for _, c := range scanCaches {
+		n += c.len()
+	}
+	return n
+}
+
+// MaxCachedScansExported is maxCachedScans, exported for testing.
+const MaxCachedScansExported = maxCachedScans
+
+// NewScanCacheExported returns the get and len functions of a scan cache of scan, exported
+// for testing.
+func NewScanCacheExported(scan func(string) []string) (get func(string) []string, size func() int) {
+	c := newScanCache(scan)
+	return c.get, c.len
+}
@@ -0,0 +1,51 @@
+package discovery
+
+import (
+	"path"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// ApplyResourceScope limits the registry to the definitions the resources setting selects,
+// so a team can gate its own definitions while the rest of the provider is not analyzed.
+// It runs after linking: tests of other definitions are removed with them rather than
+// reported as orphans.
+func ApplyResourceScope(reg *registry.ResourceRegistry, settings config.Settings) {
+	if len(settings.Resources) == 0 {
+		return
+	}
+	providerTypeName := ""
+	if provider := reg.GetProvider(); provider != nil {
+		providerTypeName = provider.TypeName
+	}
+	reg.RetainDefinitions(func(info *registry.ResourceInfo) bool {
+		return InResourceScope(settings.Resources, providerTypeName, info.Name)
+	})
+}
+
+// InResourceScope reports whether a definition name matches one of patterns. Names are
+// registered without the provider prefix, so patterns also match the full type name
+// ("aws_s3_bucket" for "s3_bucket") when providerTypeName is known, and otherwise without
+// their first segment, when it has no wildcard.
+func InResourceScope(patterns []string, providerTypeName, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+		if providerTypeName != "" {
+			if matched, _ := path.Match(pattern, providerTypeName+"_"+name); matched {
+				return true
+			}
+			continue
+		}
+		prefix, rest, ok := strings.Cut(pattern, "_")
+		if ok && !strings.ContainsAny(prefix, `*?[\`) {
+			if matched, _ := path.Match(rest, name); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package discovery
+
+import (
+	"go/ast"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// sdkv2OperationSuffixes are the variants of each SDK v2 lifecycle field, e.g. Update,
+// UpdateContext and UpdateWithoutTimeout.
+var sdkv2OperationSuffixes = []string{"", "Context", "WithoutTimeout"}
+
+// applySDKv2Resource fills in attributes, lifecycle functions and import support for a
+// definition discovered from a function returning an SDK v2 *schema.Resource. Definitions
+// that are not backed by a schema.Resource literal are left unchanged.
+func applySDKv2Resource(file *ast.File, resource *registry.ResourceInfo) {
+	lit := sdkv2ResourceLiteral(file, resource)
+	if lit == nil {
+		return
+	}
+
+	ops := &registry.Operations{}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok || isNilIdent(kv.Value) {
+			continue
+		}
+		switch {
+		case isSDKv2Operation(key.Name, "Create"):
+			ops.Create = true
+		case isSDKv2Operation(key.Name, "Read"):
+			ops.Read = true
+		case isSDKv2Operation(key.Name, "Update"):
+			ops.Update = true
+		case isSDKv2Operation(key.Name, "Delete"):
+			ops.Delete = true
+			ops.DeleteNoOp = isSDKv2NoOp(kv.Value)
+		case key.Name == "Importer":
+			if resource.Kind == registry.KindResource {
+				resource.HasImportState = true
+				resource.ImportStatePos = kv.Pos()
+			}
+		case key.Name == "SchemaVersion":
+			if version, ok := intLiteralValue(kv.Value); ok {
+				resource.SchemaVersion, resource.VersionPos = version, kv.Pos()
+			}
+		case key.Name == "Schema":
+			if mapLit, ok := kv.Value.(*ast.CompositeLit); ok && len(resource.Attributes) == 0 {
+				for _, attr := range parseAttributesMap(mapLit) {
+					resource.Attributes = append(resource.Attributes, *attr)
+				}
+			}
+		}
+	}
+
+	if resource.Kind == registry.KindResource {
+		resource.Operations = ops
+	}
+}
+
+// sdkv2ResourceLiteral returns the outermost schema.Resource composite literal in the
+// function a definition was discovered from.
+func sdkv2ResourceLiteral(file *ast.File, resource *registry.ResourceInfo) *ast.CompositeLit {
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Body == nil || funcDecl.Recv != nil {
+			continue
+		}
+		if resource.SchemaPos < funcDecl.Pos() || resource.SchemaPos >= funcDecl.End() {
+			continue
+		}
+		if !returnsSDKv2Resource(funcDecl) {
+			return nil
+		}
+
+		var found *ast.CompositeLit
+		ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+			if found != nil {
+				return false
+			}
+			lit, ok := n.(*ast.CompositeLit)
+			if !ok {
+				return true
+			}
+			if sel, ok := lit.Type.(*ast.SelectorExpr); ok && sel.Sel.Name == "Resource" {
+				found = lit
+				return false
+			}
+			return true
+		})
+		return found
+	}
+	return nil
+}
+
+// returnsSDKv2Resource reports whether a function's only result is *schema.Resource.
+func returnsSDKv2Resource(funcDecl *ast.FuncDecl) bool {
+	results := funcDecl.Type.Results
+	if results == nil || len(results.List) != 1 {
+		return false
+	}
+	return strings.HasSuffix(typeToString(results.List[0].Type), "schema.Resource")
+}
+
+// isSDKv2Operation reports whether a schema.Resource field sets the named operation.
+func isSDKv2Operation(field, operation string) bool {
+	for _, suffix := range sdkv2OperationSuffixes {
+		if field == operation+suffix {
+			return true
+		}
+	}
+	return false
+}
+
+// sdkv2NoOps are the schema package functions that delete a resource without destroying
+// anything.
+var sdkv2NoOps = map[string]bool{"Noop": true, "NoopContext": true, "RemoveFromState": true}
+
+// isSDKv2NoOp reports whether expr is one of sdkv2NoOps, e.g. schema.NoopContext.
+func isSDKv2NoOp(expr ast.Expr) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	return ok && sdkv2NoOps[sel.Sel.Name]
+}
+
+// isNilIdent reports whether expr is the identifier nil.
+func isNilIdent(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "nil"
+}
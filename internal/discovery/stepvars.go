@@ -0,0 +1,437 @@
+package discovery
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// maxStepHelperDepth bounds how deeply functions returning []resource.TestStep are followed,
+// which also stops recursive helpers.
+const maxStepHelperDepth = 3
+
+// stepVariables records the test steps held by local []resource.TestStep variables, so that
+// a TestCase with `Steps: steps` can be resolved to the step literals the variable was built
+// from. Each assignment records the full contents of the slice at that point in the function.
+type stepVariables struct {
+	assignments map[string][]stepAssignment
+	// tables holds local slice and map literals that loops may range over
+	tables map[string][]tableAssignment
+	// lookupFunc resolves functions that return []resource.TestStep; it may be nil
+	lookupFunc func(name string) *ast.FuncDecl
+	depth      int
+}
+
+// stepList is the statically known contents of a step slice. opaque is set when part of the
+// slice is built in a way that cannot be resolved, such as a loop over a computed table, so
+// the real number of steps is unknown rather than len(exprs).
+type stepList struct {
+	exprs  []ast.Expr
+	opaque bool
+}
+
+// stepAssignment is the contents of a step slice variable after an assignment at pos.
+type stepAssignment struct {
+	pos   token.Pos
+	steps stepList
+}
+
+// tableAssignment is a slice or map literal assigned to a local variable at pos.
+type tableAssignment struct {
+	pos token.Pos
+	lit *ast.CompositeLit
+}
+
+// collectStepVariables finds the local step slice variables of a function body: variables
+// declared or assigned a []resource.TestStep literal or the result of a function returning
+// one, the append() calls that grow them (steps = append(steps, resource.TestStep{...}),
+// steps = append(steps, more...)), and the loops that append to them. lookupFunc resolves
+// step-returning helpers and may be nil.
+func collectStepVariables(body *ast.BlockStmt, lookupFunc func(name string) *ast.FuncDecl) *stepVariables {
+	return newStepVariables(body, lookupFunc, 0)
+}
+
+func newStepVariables(body *ast.BlockStmt, lookupFunc func(name string) *ast.FuncDecl, depth int) *stepVariables {
+	v := &stepVariables{
+		assignments: make(map[string][]stepAssignment),
+		tables:      make(map[string][]tableAssignment),
+		lookupFunc:  lookupFunc,
+		depth:       depth,
+	}
+	if body == nil {
+		return v
+	}
+
+	// Appends inside loops are recorded by collectLoop and skipped when visited
+	handled := make(map[*ast.AssignStmt]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.RangeStmt:
+			v.collectLoop(stmt, stmt.Body, handled)
+		case *ast.ForStmt:
+			v.collectLoop(stmt, stmt.Body, handled)
+		case *ast.AssignStmt:
+			if handled[stmt] || len(stmt.Lhs) != len(stmt.Rhs) {
+				return true
+			}
+			for i, lhs := range stmt.Lhs {
+				ident, ok := lhs.(*ast.Ident)
+				if !ok || ident.Name == "_" {
+					continue
+				}
+				v.record(ident.Name, stmt.Rhs[i], stmt.Pos())
+			}
+		case *ast.ValueSpec:
+			for i, name := range stmt.Names {
+				if i < len(stmt.Values) {
+					v.record(name.Name, stmt.Values[i], stmt.Pos())
+					continue
+				}
+				// var steps []resource.TestStep declares an empty slice
+				if isTestStepSliceType(stmt.Type) {
+					v.assignments[name.Name] = append(v.assignments[name.Name], stepAssignment{pos: stmt.Pos()})
+				}
+			}
+		}
+		return true
+	})
+	return v
+}
+
+// record notes an assignment of value to a local variable, as a step slice or as a table
+// literal that a loop may range over.
+func (v *stepVariables) record(name string, value ast.Expr, pos token.Pos) {
+	if steps, ok := v.resolve(value, pos); ok {
+		v.assignments[name] = append(v.assignments[name], stepAssignment{pos: pos, steps: steps})
+		return
+	}
+	if lit, ok := value.(*ast.CompositeLit); ok && isTableType(lit.Type) {
+		v.tables[name] = append(v.tables[name], tableAssignment{pos: pos, lit: lit})
+	}
+}
+
+// collectLoop records the step variables a loop appends to. A range loop over a literal table
+// whose body appends steps directly is unrolled, binding the loop value in each appended step;
+// any other loop that assigns a step variable leaves it opaque.
+func (v *stepVariables) collectLoop(loop ast.Stmt, body *ast.BlockStmt, handled map[*ast.AssignStmt]bool) {
+	var names []string
+	assigns := make(map[string][]*ast.AssignStmt)
+	ast.Inspect(body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || assign.Tok == token.DEFINE || handled[assign] {
+			return true
+		}
+		for _, lhs := range assign.Lhs {
+			ident, ok := lhs.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			// Only variables declared before the loop carry steps out of it
+			if _, ok := v.at(ident.Name, loop.Pos()); !ok {
+				continue
+			}
+			if _, seen := assigns[ident.Name]; !seen {
+				names = append(names, ident.Name)
+			}
+			assigns[ident.Name] = append(assigns[ident.Name], assign)
+			handled[assign] = true
+		}
+		return true
+	})
+
+	for _, name := range names {
+		base, _ := v.at(name, loop.Pos())
+		steps, ok := v.unroll(loop, body, name, assigns[name], base)
+		if !ok {
+			steps = stepList{exprs: base.exprs, opaque: true}
+		}
+		v.assignments[name] = append(v.assignments[name], stepAssignment{pos: loop.Pos(), steps: steps})
+	}
+}
+
+// unroll returns the contents of a step variable after a range loop over a literal table,
+// where every assignment to the variable is a top-level statement of the loop body of the
+// form name = append(name, steps...). ok is false for any other loop.
+func (v *stepVariables) unroll(loop ast.Stmt, body *ast.BlockStmt, name string, assigns []*ast.AssignStmt, base stepList) (stepList, bool) {
+	rangeStmt, ok := loop.(*ast.RangeStmt)
+	if !ok {
+		return stepList{}, false
+	}
+	entries, ok := v.tableEntries(rangeStmt.X, rangeStmt.Pos())
+	if !ok {
+		return stepList{}, false
+	}
+
+	topLevel := make(map[ast.Stmt]bool)
+	for _, stmt := range body.List {
+		topLevel[stmt] = true
+	}
+	var appended [][]ast.Expr
+	for _, assign := range assigns {
+		if !topLevel[assign] || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			return stepList{}, false
+		}
+		call, ok := assign.Rhs[0].(*ast.CallExpr)
+		if !ok || call.Ellipsis.IsValid() || len(call.Args) == 0 {
+			return stepList{}, false
+		}
+		if fun, ok := call.Fun.(*ast.Ident); !ok || fun.Name != "append" {
+			return stepList{}, false
+		}
+		if arg, ok := call.Args[0].(*ast.Ident); !ok || arg.Name != name {
+			return stepList{}, false
+		}
+		appended = append(appended, call.Args[1:])
+	}
+
+	steps := stepList{exprs: append([]ast.Expr(nil), base.exprs...), opaque: base.opaque}
+	for _, entry := range entries {
+		for _, exprs := range appended {
+			for _, expr := range exprs {
+				steps.exprs = append(steps.exprs, bindLoopValue(expr, rangeStmt.Value, entry))
+			}
+		}
+	}
+	return steps, true
+}
+
+// tableEntries returns the values a range loop over expr iterates: the elements of a slice or
+// map literal, or of a local variable holding one.
+func (v *stepVariables) tableEntries(expr ast.Expr, pos token.Pos) ([]ast.Expr, bool) {
+	var lit *ast.CompositeLit
+	switch e := expr.(type) {
+	case *ast.CompositeLit:
+		lit = e
+	case *ast.Ident:
+		if steps, ok := v.at(e.Name, pos); ok {
+			return steps.exprs, !steps.opaque
+		}
+		for _, a := range v.tables[e.Name] {
+			if a.pos >= pos {
+				break
+			}
+			lit = a.lit
+		}
+	}
+	if lit == nil || !isTableType(lit.Type) {
+		return nil, false
+	}
+
+	entries := make([]ast.Expr, 0, len(lit.Elts))
+	for _, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			elt = kv.Value
+		}
+		entries = append(entries, elt)
+	}
+	return entries, true
+}
+
+// resolve returns the steps a slice expression evaluates to at pos: a []resource.TestStep
+// literal, a known step variable, an append() call built from them, or a call to a function
+// returning []resource.TestStep. ok is false when the expression is not a recognized step slice.
+func (v *stepVariables) resolve(expr ast.Expr, pos token.Pos) (stepList, bool) {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return v.resolve(e.X, pos)
+	case *ast.CompositeLit:
+		if !isTestStepSliceType(e.Type) {
+			return stepList{}, false
+		}
+		return stepList{exprs: e.Elts}, true
+	case *ast.Ident:
+		return v.at(e.Name, pos)
+	case *ast.CallExpr:
+		fun, ok := e.Fun.(*ast.Ident)
+		if !ok {
+			return stepList{}, false
+		}
+		if fun.Name != "append" {
+			return v.resolveCall(fun.Name)
+		}
+		if len(e.Args) == 0 {
+			return stepList{}, false
+		}
+		base, ok := v.resolve(e.Args[0], pos)
+		if !ok {
+			return stepList{}, false
+		}
+		steps := stepList{exprs: append([]ast.Expr(nil), base.exprs...), opaque: base.opaque}
+		rest := e.Args[1:]
+		if e.Ellipsis.IsValid() && len(rest) > 0 {
+			spread, ok := v.resolve(rest[len(rest)-1], pos)
+			steps.exprs = append(steps.exprs, rest[:len(rest)-1]...)
+			steps.exprs = append(steps.exprs, spread.exprs...)
+			steps.opaque = steps.opaque || spread.opaque || !ok
+			return steps, true
+		}
+		steps.exprs = append(steps.exprs, rest...)
+		return steps, true
+	default:
+		return stepList{}, false
+	}
+}
+
+// resolveCall resolves a call to a function declared to return []resource.TestStep. The
+// steps are known when the function has a single return statement that resolves; otherwise
+// the result is opaque. ok is false when the function is unknown or returns something else.
+func (v *stepVariables) resolveCall(name string) (stepList, bool) {
+	if v == nil || v.lookupFunc == nil {
+		return stepList{}, false
+	}
+	decl := v.lookupFunc(name)
+	if decl == nil || decl.Body == nil || !returnsTestSteps(decl) {
+		return stepList{}, false
+	}
+	if v.depth >= maxStepHelperDepth {
+		return stepList{opaque: true}, true
+	}
+
+	var returns []*ast.ReturnStmt
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			returns = append(returns, stmt)
+		}
+		return true
+	})
+	if len(returns) != 1 || len(returns[0].Results) != 1 {
+		return stepList{opaque: true}, true
+	}
+
+	inner := newStepVariables(decl.Body, v.lookupFunc, v.depth+1)
+	steps, ok := inner.resolve(returns[0].Results[0], returns[0].Pos())
+	if !ok {
+		return stepList{opaque: true}, true
+	}
+	return steps, true
+}
+
+// at returns the contents of a step variable as of its last assignment before pos.
+func (v *stepVariables) at(name string, pos token.Pos) (stepList, bool) {
+	if v == nil {
+		return stepList{}, false
+	}
+	var steps stepList
+	found := false
+	for _, a := range v.assignments[name] {
+		if a.pos >= pos {
+			break
+		}
+		steps, found = a.steps, true
+	}
+	return steps, found
+}
+
+// bindLoopValue substitutes a range loop's value variable in an appended step: the step itself
+// (steps = append(steps, tc.step)) or the top-level fields of a step literal that read the
+// value (Config: tc.config). Other expressions are returned unchanged; the AST is not modified.
+func bindLoopValue(expr ast.Expr, value ast.Expr, entry ast.Expr) ast.Expr {
+	ident, ok := value.(*ast.Ident)
+	if !ok || ident.Name == "_" {
+		return expr
+	}
+	if bound, ok := loopValueField(expr, ident.Name, entry); ok {
+		return bound
+	}
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return expr
+	}
+
+	bound := *lit
+	bound.Elts = make([]ast.Expr, len(lit.Elts))
+	for i, elt := range lit.Elts {
+		bound.Elts[i] = elt
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if fieldValue, ok := loopValueField(kv.Value, ident.Name, entry); ok {
+			boundKV := *kv
+			boundKV.Value = fieldValue
+			bound.Elts[i] = &boundKV
+		}
+	}
+	return &bound
+}
+
+// loopValueField resolves expr when it is the loop value itself or a field of it, looked up
+// in the keyed struct literal of the current table entry.
+func loopValueField(expr ast.Expr, name string, entry ast.Expr) (ast.Expr, bool) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return entry, e.Name == name
+	case *ast.SelectorExpr:
+		x, ok := e.X.(*ast.Ident)
+		if !ok || x.Name != name {
+			return nil, false
+		}
+		if unary, ok := entry.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+			entry = unary.X
+		}
+		lit, ok := entry.(*ast.CompositeLit)
+		if !ok {
+			return nil, false
+		}
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			if key, ok := kv.Key.(*ast.Ident); ok && key.Name == e.Sel.Name {
+				return kv.Value, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// returnsTestSteps reports whether a function's only result is []resource.TestStep.
+func returnsTestSteps(decl *ast.FuncDecl) bool {
+	results := decl.Type.Results
+	if results == nil || len(results.List) != 1 || len(results.List[0].Names) > 1 {
+		return false
+	}
+	return isTestStepSliceType(results.List[0].Type)
+}
+
+// isTestStepSliceType reports whether expr is the type []resource.TestStep, or its
+// []acceptance.TestStep alias in providers using test-data builders.
+func isTestStepSliceType(expr ast.Expr) bool {
+	arrayType, ok := expr.(*ast.ArrayType)
+	if !ok || arrayType.Len != nil {
+		return false
+	}
+	sel, ok := arrayType.Elt.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	return ok && (ident.Name == "resource" || ident.Name == "acceptance") && sel.Sel.Name == "TestStep"
+}
+
+// isTableType reports whether expr is a slice, array or map type, the literals of which can
+// serve as a test table.
+func isTableType(expr ast.Expr) bool {
+	switch expr.(type) {
+	case *ast.ArrayType, *ast.MapType:
+		return true
+	}
+	return false
+}
+
+// isBuiltinCall reports whether call invokes a builtin that takes slices, such as append or
+// len, whose arguments are not steps passed to a test.
+func isBuiltinCall(call *ast.CallExpr) bool {
+	ident, ok := call.Fun.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	switch ident.Name {
+	case "append", "len", "cap", "copy":
+		return true
+	}
+	return false
+}
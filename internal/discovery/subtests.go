@@ -0,0 +1,229 @@
+package discovery
+
+import (
+	"go/ast"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// subtestName is the name of a subtest, or of the subtests a t.Run call is nested in.
+type subtestName struct {
+	name  string
+	known bool
+}
+
+// join returns the name of a subtest named child nested in the subtest n.
+func (n subtestName) join(child subtestName) subtestName {
+	if !n.known || !child.known {
+		return subtestName{}
+	}
+	if n.name == "" {
+		return child
+	}
+	return subtestName{name: n.name + "/" + child.name, known: true}
+}
+
+// extractSubtests finds the t.Run subtests of a test body that run a resource.TestCase of
+// their own, as usesResourceTest reports, and gives each the steps that lie inside its
+// function literal. Subtests that only group nested subtests are left out in favour of those.
+// A subtest named by a field of the rows of a table literal the test ranges over, as in
+//
+//	for _, tc := range []struct{ name string }{{name: "basic"}, {name: "update"}} {
+//		t.Run(tc.name, func(t *testing.T) { ... })
+//	}
+//
+// is listed once per row.
+func extractSubtests(body *ast.BlockStmt, steps []registry.TestStepInfo, usesResourceTest func(*ast.BlockStmt) bool) []registry.SubtestInfo {
+	tables := tableLiterals(body)
+	var subtests []registry.SubtestInfo
+	var walk func(node ast.Node, parents []subtestName, ranges []*ast.RangeStmt)
+	walk = func(node ast.Node, parents []subtestName, ranges []*ast.RangeStmt) {
+		ast.Inspect(node, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.RangeStmt:
+				walk(n.Body, parents, append(ranges[:len(ranges):len(ranges)], n))
+				return false
+			case *ast.CallExpr:
+				lit, ok := subtestCall(n)
+				if !ok {
+					return true
+				}
+				if !usesResourceTest(lit.Body) {
+					return false
+				}
+				var names []subtestName
+				for _, parent := range parents {
+					for _, name := range subtestNames(n.Args[0], ranges, tables) {
+						names = append(names, parent.join(name))
+					}
+				}
+				before := len(subtests)
+				walk(lit.Body, names, ranges)
+				if len(subtests) > before {
+					return false
+				}
+				for _, name := range names {
+					subtests = append(subtests, registry.SubtestInfo{Name: name.name, Pos: n.Pos(), Steps: stepsWithin(steps, lit)})
+				}
+				return false
+			}
+			return true
+		})
+	}
+	walk(body, []subtestName{{known: true}}, nil)
+	return subtests
+}
+
+// subtestCall returns the function literal of a t.Run(name, func(t *testing.T) {...}) call.
+func subtestCall(call *ast.CallExpr) (*ast.FuncLit, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Run" || len(call.Args) != 2 {
+		return nil, false
+	}
+	lit, ok := call.Args[1].(*ast.FuncLit)
+	if !ok || lit.Type.Params == nil || len(lit.Type.Params.List) != 1 {
+		return nil, false
+	}
+	star, ok := lit.Type.Params.List[0].Type.(*ast.StarExpr)
+	if !ok {
+		return nil, false
+	}
+	if typ, ok := star.X.(*ast.SelectorExpr); !ok || typ.Sel.Name != "T" {
+		return nil, false
+	}
+	return lit, true
+}
+
+// subtestNames returns the names a t.Run name argument takes: a string literal, or the
+// field of the rows (or the keys) of a table literal ranged over by one of ranges. Names
+// that cannot be resolved are unknown.
+func subtestNames(expr ast.Expr, ranges []*ast.RangeStmt, tables map[string]*ast.CompositeLit) []subtestName {
+	if name, ok := stringLiteralValue(expr); ok {
+		return []subtestName{goTestName(name)}
+	}
+	for i := len(ranges) - 1; i >= 0; i-- {
+		rng := ranges[i]
+		table := rangedTable(rng.X, tables)
+		if table == nil {
+			continue
+		}
+		var pick func(elt ast.Expr) (string, bool)
+		switch e := expr.(type) {
+		case *ast.Ident:
+			if key, ok := rng.Key.(*ast.Ident); !ok || key.Name != e.Name {
+				continue
+			}
+			pick = func(elt ast.Expr) (string, bool) {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					return stringLiteralValue(kv.Key)
+				}
+				return "", false
+			}
+		case *ast.SelectorExpr:
+			value, ok := rng.Value.(*ast.Ident)
+			if x, isIdent := e.X.(*ast.Ident); !ok || !isIdent || x.Name != value.Name {
+				continue
+			}
+			pick = func(elt ast.Expr) (string, bool) {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					elt = kv.Value
+				}
+				return fieldLiteral(elt, e.Sel.Name)
+			}
+		default:
+			continue
+		}
+		var names []subtestName
+		for _, elt := range table.Elts {
+			if name, ok := pick(elt); ok {
+				names = append(names, goTestName(name))
+			} else {
+				names = append(names, subtestName{})
+			}
+		}
+		if len(names) > 0 {
+			return names
+		}
+	}
+	return []subtestName{{}}
+}
+
+// goTestName returns a subtest name as go test reports it.
+func goTestName(name string) subtestName {
+	return subtestName{name: strings.ReplaceAll(name, " ", "_"), known: true}
+}
+
+// rangedTable returns the composite literal a range statement ranges over, given directly
+// or through a variable of the test.
+func rangedTable(expr ast.Expr, tables map[string]*ast.CompositeLit) *ast.CompositeLit {
+	switch e := expr.(type) {
+	case *ast.CompositeLit:
+		return e
+	case *ast.Ident:
+		return tables[e.Name]
+	}
+	return nil
+}
+
+// tableLiterals maps the variables of a test body assigned a composite literal to it.
+func tableLiterals(body *ast.BlockStmt) map[string]*ast.CompositeLit {
+	tables := make(map[string]*ast.CompositeLit)
+	add := func(name *ast.Ident, value ast.Expr) {
+		if lit, ok := value.(*ast.CompositeLit); ok {
+			if _, exists := tables[name.Name]; !exists {
+				tables[name.Name] = lit
+			}
+		}
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if len(n.Lhs) != len(n.Rhs) {
+				return true
+			}
+			for i, lhs := range n.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok {
+					add(ident, n.Rhs[i])
+				}
+			}
+		case *ast.ValueSpec:
+			for i, name := range n.Names {
+				if i < len(n.Values) {
+					add(name, n.Values[i])
+				}
+			}
+		}
+		return true
+	})
+	return tables
+}
+
+// fieldLiteral returns the string literal set for field in a keyed struct literal.
+func fieldLiteral(expr ast.Expr, field string) (string, bool) {
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return "", false
+	}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if key, ok := kv.Key.(*ast.Ident); ok && key.Name == field {
+			return stringLiteralValue(kv.Value)
+		}
+	}
+	return "", false
+}
+
+// stepsWithin returns the steps that lie inside a function literal.
+func stepsWithin(steps []registry.TestStepInfo, lit *ast.FuncLit) []registry.TestStepInfo {
+	var within []registry.TestStepInfo
+	for _, step := range steps {
+		if step.StepPos >= lit.Pos() && step.StepPos < lit.End() {
+			within = append(within, step)
+		}
+	}
+	return within
+}
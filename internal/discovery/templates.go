@@ -0,0 +1,185 @@
+package discovery
+
+import (
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CollectStringTemplates finds package-level string declarations in a file that may hold
+// HCL templates: const/var declarations initialized from string literals (including
+// concatenations of literals and other templates), and string or []byte variables
+// populated by a //go:embed directive. Embedded files are resolved relative to filePath.
+// It returns a map from identifier name to template content.
+func CollectStringTemplates(file *ast.File, filePath string) map[string]string {
+	templates := make(map[string]string)
+	dir := filepath.Dir(filePath)
+
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || (genDecl.Tok != token.CONST && genDecl.Tok != token.VAR) {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+
+			// go:embed applies to a single var declared without a value
+			if genDecl.Tok == token.VAR && len(valueSpec.Values) == 0 && len(valueSpec.Names) == 1 {
+				doc := valueSpec.Doc
+				if doc == nil && len(genDecl.Specs) == 1 {
+					doc = genDecl.Doc
+				}
+				if patterns := embedPatterns(doc); len(patterns) > 0 && isStringOrBytesType(valueSpec.Type) {
+					if content, ok := readEmbeddedFiles(dir, patterns); ok {
+						templates[valueSpec.Names[0].Name] = content
+					}
+				}
+				continue
+			}
+
+			for i, name := range valueSpec.Names {
+				if i >= len(valueSpec.Values) {
+					break
+				}
+				if content, ok := constantString(valueSpec.Values[i], templates); ok {
+					templates[name.Name] = content
+				}
+			}
+		}
+	}
+
+	return templates
+}
+
+// CollectPackageTemplates merges the string templates declared across the files of each
+// package, so files can resolve templates declared in sibling files. It returns a map from
+// package directory to the templates of the package by identifier name; packages often
+// declare templates of the same name.
+func CollectPackageTemplates(files []*ast.File, fset *token.FileSet) map[string]map[string]string {
+	packages := make(map[string]map[string]string)
+	for _, file := range files {
+		filePath := fset.Position(file.Pos()).Filename
+		dir := filepath.Dir(filePath)
+		for name, content := range CollectStringTemplates(file, filePath) {
+			if packages[dir] == nil {
+				packages[dir] = make(map[string]string)
+			}
+			packages[dir][name] = content
+		}
+	}
+	return packages
+}
+
+// constantString evaluates string literals, concatenations, and references to
+// already-collected templates into their string value.
+func constantString(expr ast.Expr, templates map[string]string) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+		value, err := strconv.Unquote(e.Value)
+		if err != nil {
+			return "", false
+		}
+		return value, true
+	case *ast.Ident:
+		value, ok := templates[e.Name]
+		return value, ok
+	case *ast.ParenExpr:
+		return constantString(e.X, templates)
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return "", false
+		}
+		left, ok := constantString(e.X, templates)
+		if !ok {
+			return "", false
+		}
+		right, ok := constantString(e.Y, templates)
+		if !ok {
+			return "", false
+		}
+		return left + right, true
+	}
+	return "", false
+}
+
+// embedPatterns returns the patterns from any //go:embed directives in a comment group.
+func embedPatterns(doc *ast.CommentGroup) []string {
+	if doc == nil {
+		return nil
+	}
+	var patterns []string
+	for _, comment := range doc.List {
+		text := strings.TrimPrefix(comment.Text, "//")
+		if !strings.HasPrefix(text, "go:embed ") {
+			continue
+		}
+		for _, field := range strings.Fields(strings.TrimPrefix(text, "go:embed ")) {
+			if unquoted, err := strconv.Unquote(field); err == nil {
+				field = unquoted
+			}
+			patterns = append(patterns, field)
+		}
+	}
+	return patterns
+}
+
+// isStringOrBytesType reports whether a declared type is string or []byte.
+func isStringOrBytesType(expr ast.Expr) bool {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name == "string"
+	case *ast.ArrayType:
+		ident, ok := t.Elt.(*ast.Ident)
+		return t.Len == nil && ok && ident.Name == "byte"
+	}
+	return false
+}
+
+// readEmbeddedFiles reads and concatenates every file matched by the embed patterns.
+// Unreadable or unmatched patterns are skipped; ok is false if nothing was read.
+func readEmbeddedFiles(dir string, patterns []string) (string, bool) {
+	var matched []string
+	for _, pattern := range patterns {
+		paths, err := filepath.Glob(filepath.Join(dir, filepath.FromSlash(pattern)))
+		if err != nil {
+			continue
+		}
+		matched = append(matched, paths...)
+	}
+	sort.Strings(matched)
+
+	var sb strings.Builder
+	read := false
+	for _, path := range matched {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		sb.Write(data)
+		sb.WriteString("\n")
+		read = true
+	}
+	return sb.String(), read
+}
+
+// templatePatterns extracts the typed HCL blocks from every collected template.
+func templatePatterns(templates map[string]string) map[string][]InferredResource {
+	patterns := make(map[string][]InferredResource, len(templates))
+	for name, content := range templates {
+		if blocks := hclBlocks(content); len(blocks) > 0 {
+			patterns[name] = blocks
+		}
+	}
+	return patterns
+}
@@ -0,0 +1,176 @@
+package discovery
+
+import (
+	"go/ast"
+	"strconv"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// Test-data builders are the acceptance test factories of AzureRM-style providers:
+//
+//	data := acceptance.BuildTestData(t, "azurerm_storage_account", "test")
+//	r := StorageAccountResource{}
+//	data.ResourceTest(t, r, []acceptance.TestStep{
+//		{Config: r.basic(data), Check: ...},
+//		data.ImportStep(),
+//	})
+//
+// The configs come from receiver methods that render them at run time, so the definition a
+// test exercises is taken from the BuildTestData arguments instead. The builder's runner
+// methods set PreCheck and, for resources, CheckDestroy themselves.
+
+// buildTestDataFunc is the function, in any package, that returns a test-data builder.
+const buildTestDataFunc = "BuildTestData"
+
+// testDataRunner describes a builder method that runs test steps.
+type testDataRunner struct {
+	checkDestroy bool // The method checks the resource is destroyed after the steps
+}
+
+// testDataRunners are the builder methods that run test steps, by name.
+var testDataRunners = map[string]testDataRunner{
+	"ResourceTest":                           {checkDestroy: true},
+	"ResourceTestIgnoreRecreate":             {checkDestroy: true},
+	"ResourceSequentialTest":                 {checkDestroy: true},
+	"ResourceTestIgnoreCheckDestroy":         {},
+	"ResourceTestSkipCheckDestroy":           {},
+	"ResourceSequentialTestSkipCheckDestroy": {},
+	"DataSourceTest":                         {},
+	"DataSourceTestInSequence":               {},
+}
+
+// requiresImportErrorPattern is the start of the error a builder's RequiresImportErrorStep
+// expects: the resource already exists and must be imported.
+const requiresImportErrorPattern = "already exists - to be managed via Terraform this resource needs to be imported into the State"
+
+// testDataBuilders returns the definition each test-data builder variable of body was built
+// for, by variable name, as the HCL block a config would declare it with. Builders whose
+// resource type is not a string literal are left out.
+func testDataBuilders(body *ast.BlockStmt) map[string]registry.InferredHCLBlock {
+	builders := make(map[string]registry.InferredHCLBlock)
+	if body == nil {
+		return builders
+	}
+	record := func(name *ast.Ident, value ast.Expr) {
+		if block, ok := buildTestDataBlock(value); ok && name.Name != "_" {
+			builders[name.Name] = block
+		}
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range stmt.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok && i < len(stmt.Rhs) {
+					record(ident, stmt.Rhs[i])
+				}
+			}
+		case *ast.ValueSpec:
+			for i, name := range stmt.Names {
+				if i < len(stmt.Values) {
+					record(name, stmt.Values[i])
+				}
+			}
+		}
+		return true
+	})
+	return builders
+}
+
+// buildTestDataBlock returns the definition a BuildTestData(t, "azurerm_x", "test") call
+// builds test data for. A "data." prefix marks a data source.
+func buildTestDataBlock(expr ast.Expr) (registry.InferredHCLBlock, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) < 2 || calledFuncName(call) != buildTestDataFunc {
+		return registry.InferredHCLBlock{}, false
+	}
+	lit, ok := call.Args[1].(*ast.BasicLit)
+	if !ok {
+		return registry.InferredHCLBlock{}, false
+	}
+	resourceType, err := strconv.Unquote(lit.Value)
+	if err != nil || resourceType == "" {
+		return registry.InferredHCLBlock{}, false
+	}
+	if name, ok := strings.CutPrefix(resourceType, "data."); ok {
+		return registry.InferredHCLBlock{BlockType: "data", ResourceType: name}, true
+	}
+	return registry.InferredHCLBlock{BlockType: "resource", ResourceType: resourceType}, true
+}
+
+// calledFuncName returns the name of the function or method call calls, without its
+// package or receiver.
+func calledFuncName(call *ast.CallExpr) string {
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		return fun.Name
+	case *ast.SelectorExpr:
+		return fun.Sel.Name
+	}
+	return ""
+}
+
+// testDataRunnerCall returns the definition and runner of a call to a runner method of one
+// of builders, as in data.ResourceTest(t, r, steps).
+func testDataRunnerCall(call *ast.CallExpr, builders map[string]registry.InferredHCLBlock) (registry.InferredHCLBlock, testDataRunner, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return registry.InferredHCLBlock{}, testDataRunner{}, false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return registry.InferredHCLBlock{}, testDataRunner{}, false
+	}
+	block, ok := builders[ident.Name]
+	if !ok {
+		return registry.InferredHCLBlock{}, testDataRunner{}, false
+	}
+	runner, ok := testDataRunners[sel.Sel.Name]
+	return block, runner, ok
+}
+
+// usesTestDataBuilder reports whether body runs test steps through a test-data builder.
+func usesTestDataBuilder(body *ast.BlockStmt) bool {
+	builders := testDataBuilders(body)
+	if len(builders) == 0 {
+		return false
+	}
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if _, _, ok := testDataRunnerCall(call, builders); ok {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// testDataBuilderStep parses a step a builder method returns, such as data.ImportStep() or
+// data.RequiresImportErrorStep(r.requiresImport). ok is false for other calls.
+func testDataBuilderStep(call *ast.CallExpr, stepNum int) (registry.TestStepInfo, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return registry.TestStepInfo{}, false
+	}
+	step := registry.TestStepInfo{StepNumber: stepNum, StepPos: call.Pos()}
+	switch sel.Sel.Name {
+	case "ImportStep", "ImportStepFor":
+		step.ImportState = true
+		step.ImportStateVerify = true
+		step.ImportStatePos = call.Pos()
+		step.HasImportStateVerifyIgnore = sel.Sel.Name == "ImportStep" && len(call.Args) > 0
+	case "RequiresImportErrorStep":
+		step.HasConfig = true
+		step.ConfigHash = hashConfigExpr(call)
+		step.ExpectError = true
+		step.ExpectErrorPos = call.Pos()
+		step.ExpectErrorPattern = requiresImportErrorPattern
+		step.HasExpectErrorPattern = true
+	default:
+		return registry.TestStepInfo{}, false
+	}
+	return step, true
+}
@@ -0,0 +1,87 @@
+package discovery
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// TestMainInfo describes the TestMain function of a test package.
+type TestMainInfo struct {
+	Pos token.Pos
+	// Dispatches is true when TestMain hands over to a TestMain of another package, such as
+	// resource.TestMain(m), which parses the -sweep flag and runs the registered sweepers.
+	Dispatches bool
+	// RunsTests is true when TestMain dispatches or calls m.Run() itself.
+	RunsTests bool
+}
+
+// FindTestMain returns the TestMain function declared in a file, or nil.
+func FindTestMain(file *ast.File) *TestMainInfo {
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Recv != nil || funcDecl.Name.Name != "TestMain" || funcDecl.Body == nil {
+			continue
+		}
+
+		info := &TestMainInfo{Pos: funcDecl.Name.Pos()}
+		param := testMainParam(funcDecl)
+		ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			switch {
+			case sel.Sel.Name == "TestMain" && len(call.Args) == 1:
+				info.Dispatches = true
+				info.RunsTests = true
+			case sel.Sel.Name == "Run" && len(call.Args) == 0:
+				if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == param {
+					info.RunsTests = true
+				}
+			}
+			return true
+		})
+		return info
+	}
+	return nil
+}
+
+// testMainParam returns the name of TestMain's *testing.M parameter.
+func testMainParam(funcDecl *ast.FuncDecl) string {
+	if funcDecl.Type.Params == nil || len(funcDecl.Type.Params.List) != 1 {
+		return ""
+	}
+	field := funcDecl.Type.Params.List[0]
+	if len(field.Names) != 1 {
+		return ""
+	}
+	return field.Names[0].Name
+}
+
+// FindSweeperRegistrations returns the positions of the resource.AddTestSweepers calls in a
+// file, including calls through an aliased import of the resource package.
+func FindSweeperRegistrations(file *ast.File) []token.Pos {
+	aliases := ExtractResourcePackageAliases(file)
+	aliases["resource"] = true
+
+	var positions []token.Pos
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "AddTestSweepers" {
+			return true
+		}
+		if ident, ok := sel.X.(*ast.Ident); ok && aliases[ident.Name] {
+			positions = append(positions, call.Pos())
+		}
+		return true
+	})
+	return positions
+}
@@ -0,0 +1,100 @@
+package discovery
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// schemaVersion returns the Version of the schema.Schema literal in a framework Schema()
+// function body and its position, or 0 and token.NoPos when it is unset or not an integer
+// literal.
+func schemaVersion(body *ast.BlockStmt) (int, token.Pos) {
+	version, pos := 0, token.NoPos
+	if body == nil {
+		return version, pos
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		compLit, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return pos == token.NoPos
+		}
+		if sel, ok := compLit.Type.(*ast.SelectorExpr); !ok || sel.Sel.Name != "Schema" {
+			return true
+		}
+		for _, elt := range compLit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			if key, ok := kv.Key.(*ast.Ident); ok && key.Name == "Version" {
+				if v, ok := intLiteralValue(kv.Value); ok {
+					version, pos = v, kv.Pos()
+				}
+			}
+		}
+		return false // Nested attributes have no version of their own
+	})
+	return version, pos
+}
+
+// intLiteralValue evaluates an integer literal.
+func intLiteralValue(expr ast.Expr) (int, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.INT {
+		return 0, false
+	}
+	value, err := strconv.ParseInt(lit.Value, 0, 64)
+	if err != nil {
+		return 0, false
+	}
+	return int(value), true
+}
+
+// externalProviders parses a step's ExternalProviders map literal, e.g.
+// map[string]resource.ExternalProvider{"example": {Source: "hashicorp/example", VersionConstraint: "1.2.0"}}.
+// Entries whose name is not a string literal are skipped.
+func externalProviders(expr ast.Expr) []registry.ExternalProvider {
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+	var providers []registry.ExternalProvider
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		name, ok := stringLiteralValue(kv.Key)
+		if !ok {
+			continue
+		}
+		provider := registry.ExternalProvider{Name: name}
+		value := kv.Value
+		if unary, ok := value.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+			value = unary.X
+		}
+		if fields, ok := value.(*ast.CompositeLit); ok {
+			for _, field := range fields.Elts {
+				fieldKV, ok := field.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+				key, ok := fieldKV.Key.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				switch key.Name {
+				case "Source":
+					provider.Source, _ = stringLiteralValue(fieldKV.Value)
+				case "VersionConstraint":
+					provider.VersionConstraint, _ = stringLiteralValue(fieldKV.Value)
+				}
+			}
+		}
+		providers = append(providers, provider)
+	}
+	return providers
+}
@@ -4,11 +4,14 @@ package discovery
 
 import (
 	"go/ast"
+	"go/token"
 	"path/filepath"
 	"strings"
-	"unicode"
 
+	"github.com/example/tfprovidertest/internal/fileroles"
+	"github.com/example/tfprovidertest/internal/naming"
 	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
 )
 
 // getReceiverTypeName extracts the receiver type name from a function declaration.
@@ -31,14 +34,14 @@ func getReceiverTypeName(recv *ast.FieldList) string {
 
 // extractResourceName extracts the resource name from a type name.
 // For example: "WidgetResource" -> "widget", "HttpDataSource" -> "http", "JobAction" -> "job"
-func extractResourceName(typeName string) string {
+func extractResourceName(names *naming.Pipeline, typeName string) string {
 	// Remove "Resource", "DataSource", or "Action" suffix
 	name := strings.TrimSuffix(typeName, "Resource")
 	name = strings.TrimSuffix(name, "DataSource")
 	name = strings.TrimSuffix(name, "Action")
 
 	// Convert CamelCase to snake_case
-	return toSnakeCase(name)
+	return names.SnakeCase(name)
 }
 
 // isBaseClassType checks if a type name represents a base/infrastructure class
@@ -65,44 +68,6 @@ func isBaseClassType(typeName string) bool {
 	return false
 }
 
-// toSnakeCase converts CamelCase to snake_case (e.g., "MyResource" -> "my_resource")
-func toSnakeCase(s string) string {
-	var result strings.Builder
-	runes := []rune(s)
-	for i, r := range runes {
-		if i > 0 && unicode.IsUpper(r) {
-			// Add underscore before uppercase if:
-			// 1. Previous char is lowercase, OR
-			// 2. Next char exists and is lowercase (handles acronyms like "HTTPServer" -> "http_server")
-			prev := runes[i-1]
-			if unicode.IsLower(prev) || (i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
-				result.WriteRune('_')
-			}
-		}
-		result.WriteRune(unicode.ToLower(r))
-	}
-	return result.String()
-}
-
-// toTitleCase converts snake_case to TitleCase (e.g., "my_resource" -> "MyResource")
-func toTitleCase(s string) string {
-	var result strings.Builder
-	capitalizeNext := true
-	for _, r := range s {
-		if r == '_' {
-			capitalizeNext = true
-			continue
-		}
-		if capitalizeNext {
-			result.WriteRune(unicode.ToUpper(r))
-			capitalizeNext = false
-		} else {
-			result.WriteRune(r)
-		}
-	}
-	return result.String()
-}
-
 // hasImportStateMethod checks if a file has ImportState method for a resource
 func hasImportStateMethod(file *ast.File, resourceName string) bool {
 	found := false
@@ -114,7 +79,7 @@ func hasImportStateMethod(file *ast.File, resourceName string) bool {
 
 		if funcDecl.Recv != nil {
 			recvType := getReceiverTypeName(funcDecl.Recv)
-			expectedType := toTitleCase(resourceName) + "Resource"
+			expectedType := naming.TitleCase(resourceName) + "Resource"
 			if recvType == expectedType || recvType == "*"+expectedType {
 				found = true
 				return false
@@ -127,6 +92,21 @@ func hasImportStateMethod(file *ast.File, resourceName string) bool {
 
 // extractAttributes parses the schema attributes from a Schema() function body
 func extractAttributes(body *ast.BlockStmt) []*registry.AttributeInfo {
+	return extractSchemaField(body, "Attributes")
+}
+
+// extractBlocks parses the top-level schema blocks from a Schema() function body
+func extractBlocks(body *ast.BlockStmt) []registry.AttributeInfo {
+	var blocks []registry.AttributeInfo
+	for _, block := range extractSchemaField(body, "Blocks") {
+		blocks = append(blocks, *block)
+	}
+	return blocks
+}
+
+// extractSchemaField parses the map of the named field (Attributes or Blocks) of the
+// schema.Schema literal in a Schema() function body
+func extractSchemaField(body *ast.BlockStmt, field string) []*registry.AttributeInfo {
 	var attributes []*registry.AttributeInfo
 	if body == nil {
 		return attributes
@@ -147,15 +127,15 @@ func extractAttributes(body *ast.BlockStmt) []*registry.AttributeInfo {
 			}
 		}
 
-		// Find the Attributes field in schema.Schema
+		// Find the field in schema.Schema
 		for _, elt := range compLit.Elts {
 			kv, ok := elt.(*ast.KeyValueExpr)
 			if !ok {
 				continue
 			}
 
-			// Check if this is the Attributes field
-			if ident, ok := kv.Key.(*ast.Ident); ok && ident.Name == "Attributes" {
+			// Check if this is the requested field
+			if ident, ok := kv.Key.(*ast.Ident); ok && ident.Name == field {
 				// Parse the attributes map
 				if mapLit, ok := kv.Value.(*ast.CompositeLit); ok {
 					attributes = parseAttributesMap(mapLit)
@@ -195,8 +175,15 @@ func parseAttributesMap(mapLit *ast.CompositeLit) []*registry.AttributeInfo {
 			IsUpdatable: true, // Default to updatable unless RequiresReplace found
 		}
 
-		// Parse the attribute composite literal
-		if attrLit, ok := kv.Value.(*ast.CompositeLit); ok {
+		// Parse the attribute composite literal (SDK v2 schemas may use &schema.Schema{...})
+		value := kv.Value
+		if unary, ok := value.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+			value = unary.X
+		}
+		if attrLit, ok := value.(*ast.CompositeLit); ok {
+			if sel, ok := attrLit.Type.(*ast.SelectorExpr); ok && sel.Sel.Name != "Schema" {
+				attr.Kind = sel.Sel.Name
+			}
 			for _, attrElt := range attrLit.Elts {
 				attrKV, ok := attrElt.(*ast.KeyValueExpr)
 				if !ok {
@@ -224,10 +211,41 @@ func parseAttributesMap(mapLit *ast.CompositeLit) []*registry.AttributeInfo {
 					attr.HasValidators = true
 					attr.ValidatorTypes = extractValidatorTypes(attrKV.Value)
 				case "PlanModifiers":
+					attr.HasPlanModifiers = true
 					// Check for RequiresReplace
 					if hasRequiresReplace(attrKV.Value) {
 						attr.IsUpdatable = false
 					}
+				case "ForceNew":
+					// SDK v2 equivalent of RequiresReplace
+					if isTrue(attrKV.Value) {
+						attr.IsUpdatable = false
+					}
+				case "ValidateFunc", "ValidateDiagFunc":
+					// SDK v2 validation functions
+					attr.HasValidators = true
+					attr.ValidatorTypes = extractValidateFuncTypes(attrKV.Value)
+				case "Default", "DefaultFunc":
+					// Framework defaults (stringdefault.StaticString(...)) and SDK v2 defaults
+					attr.HasDefault = true
+					attr.DefaultPos = attrKV.Pos()
+				case "DeprecationMessage", "Deprecated":
+					// Framework DeprecationMessage and SDK v2 Deprecated; an empty message does
+					// not deprecate the attribute
+					if message, ok := stringLiteralValue(attrKV.Value); !ok || message != "" {
+						attr.Deprecated = true
+						attr.DeprecationMessage = message
+						attr.DeprecatedPos = attrKV.Pos()
+					}
+				case "Attributes", "Blocks":
+					// SingleNestedAttribute and SingleNestedBlock
+					attr.Nested = append(attr.Nested, nestedAttributes(attrKV.Value)...)
+				case "NestedObject":
+					// ListNestedAttribute, SetNestedBlock and the like
+					attr.Nested = append(attr.Nested, nestedObjectAttributes(attrKV.Value)...)
+				case "Elem":
+					// SDK v2 nested blocks: Elem: &schema.Resource{Schema: map[string]*schema.Schema{...}}
+					attr.Nested = append(attr.Nested, nestedObjectAttributes(attrKV.Value)...)
 				}
 			}
 		}
@@ -238,6 +256,46 @@ func parseAttributesMap(mapLit *ast.CompositeLit) []*registry.AttributeInfo {
 	return attributes
 }
 
+// nestedAttributes parses a map of nested attributes or blocks.
+func nestedAttributes(expr ast.Expr) []registry.AttributeInfo {
+	mapLit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+	var nested []registry.AttributeInfo
+	for _, attr := range parseAttributesMap(mapLit) {
+		nested = append(nested, *attr)
+	}
+	return nested
+}
+
+// nestedObjectAttributes parses the attributes and blocks of a framework NestedObject, or the
+// Schema of an SDK v2 *schema.Resource. Any other value, such as the &schema.Schema{...} Elem
+// of an SDK v2 list of strings, has none.
+func nestedObjectAttributes(expr ast.Expr) []registry.AttributeInfo {
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		expr = unary.X
+	}
+	objLit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+	var nested []registry.AttributeInfo
+	for _, elt := range objLit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if ident, ok := kv.Key.(*ast.Ident); ok {
+			switch ident.Name {
+			case "Attributes", "Blocks", "Schema":
+				nested = append(nested, nestedAttributes(kv.Value)...)
+			}
+		}
+	}
+	return nested
+}
+
 // isTrue checks if an AST expression represents a boolean true value
 func isTrue(expr ast.Expr) bool {
 	if ident, ok := expr.(*ast.Ident); ok {
@@ -277,6 +335,26 @@ func extractValidatorTypes(expr ast.Expr) []string {
 	return validators
 }
 
+// extractValidateFuncTypes extracts the names of the functions an SDK v2 ValidateFunc or
+// ValidateDiagFunc refers to, including those wrapped in validation.All or
+// validation.ToDiagFunc, e.g. IsCIDR for validation.ToDiagFunc(validation.IsCIDR).
+func extractValidateFuncTypes(expr ast.Expr) []string {
+	var validators []string
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			validators = append(validators, n.Sel.Name)
+			return false
+		case *ast.Ident:
+			if n.Name != "true" && n.Name != "false" && n.Name != "nil" {
+				validators = append(validators, n.Name)
+			}
+		}
+		return true
+	})
+	return validators
+}
+
 // hasRequiresReplace checks if a node contains RequiresReplace plan modifier
 func hasRequiresReplace(node ast.Node) bool {
 	found := false
@@ -304,26 +382,50 @@ func hasRequiresReplace(node ast.Node) bool {
 // Base class files typically contain abstract base classes for resources.
 // They follow naming patterns: base_*.go or base.*.go
 func IsBaseClassFile(filePath string) bool {
-	base := filepath.Base(filePath)
-	return strings.HasPrefix(base, "base_") || strings.HasPrefix(base, "base.")
+	return fileroles.Default().Classify(filePath) == fileroles.Base
 }
 
 // IsSweeperFile checks if a file is a sweeper file that should be excluded.
 // Sweeper files are test infrastructure files for cleaning up resources after
 // acceptance tests. They follow the naming pattern *_sweeper.go.
 func IsSweeperFile(filePath string) bool {
-	base := filepath.Base(filePath)
-	return strings.HasSuffix(base, "_sweeper.go")
+	return fileroles.Default().Classify(filePath) == fileroles.Sweeper
 }
 
 // IsMigrationFile checks if a file is a state migration file that should be excluded.
 // Migration files are state migration utilities, not production resources. They follow
 // naming patterns: *_migrate.go, *_migration*.go, *_state_upgrader.go
 func IsMigrationFile(filePath string) bool {
-	base := filepath.Base(filePath)
-	return strings.HasSuffix(base, "_migrate.go") ||
-		strings.Contains(base, "_migration") ||
-		strings.HasSuffix(base, "_state_upgrader.go")
+	return fileroles.Default().Classify(filePath) == fileroles.Migration
+}
+
+// FileClassifier returns the file role classifier for settings. When FileRoles is invalid
+// (Settings.Validate reports why) only the built-in patterns are applied.
+func FileClassifier(settings config.Settings) *fileroles.Classifier {
+	classifier, err := settings.FileClassifier()
+	if err != nil {
+		return fileroles.Default()
+	}
+	return classifier
+}
+
+// ExclusionReason returns why BuildRegistry skips a file, or "" when it is analyzed. Test
+// files are only subject to file roles and exclude-patterns; exclude-paths applies to
+// definition files.
+func ExclusionReason(settings config.Settings, classifier *fileroles.Classifier, filePath string) string {
+	testFile := strings.HasSuffix(filePath, "_test.go")
+	if role := classifier.Classify(filePath); settings.ExcludesFileRole(role, testFile) {
+		return "file role " + string(role)
+	}
+	if !testFile && shouldExcludeFile(filePath, settings.ExcludePaths) {
+		return "exclude-paths"
+	}
+	if len(settings.ExcludePatterns) > 0 {
+		if result := matchesExcludePattern(filePath, settings.ExcludePatterns); result.Excluded {
+			return "exclude-patterns " + result.MatchedPattern
+		}
+	}
+	return ""
 }
 
 // shouldExcludeFile checks if a file path matches any of the exclude patterns
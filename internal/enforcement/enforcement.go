@@ -0,0 +1,131 @@
+// Package enforcement decides, per file, whether findings are enforced as errors or only
+// reported as warnings. Organizations rolling the linter out across a large provider use it
+// to enforce one service directory at a time (Settings.EnforcePaths) or to carve out
+// directories that are not ready yet (Settings.WarnOnlyPaths).
+package enforcement
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/scan"
+)
+
+// Level is how a finding is surfaced.
+type Level string
+
+// Supported levels.
+const (
+	LevelError   Level = "error"
+	LevelWarning Level = "warning"
+)
+
+// WarningPrefix starts the message of every warning-level diagnostic, so golangci-lint
+// severity rules can match warnings by text.
+const WarningPrefix = "[warning] "
+
+// Policy assigns levels to file paths.
+type Policy struct {
+	enforce  []pattern
+	warnOnly []pattern
+}
+
+// pattern is a compiled glob.
+type pattern struct {
+	re       *regexp.Regexp
+	hasSlash bool // Match the path rather than the base name
+}
+
+// New returns a policy for enforce and warnOnly glob patterns. Patterns support *, ?, [...]
+// and **. Patterns without a slash match the file's base name; patterns with a slash match
+// the slash-separated path or any trailing part of it, so "internal/service/s3/**" matches
+// "/src/provider/internal/service/s3/bucket.go".
+//
+// A file matching a warn-only pattern is at LevelWarning. Otherwise, when enforce patterns
+// are given, only files matching one of them are at LevelError; without enforce patterns
+// every file is.
+func New(enforce, warnOnly []string) (*Policy, error) {
+	p := &Policy{}
+	var err error
+	if p.enforce, err = compile("enforce-paths", enforce); err != nil {
+		return nil, err
+	}
+	if p.warnOnly, err = compile("warn-only-paths", warnOnly); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Active reports whether the policy reports any file at LevelWarning.
+func (p *Policy) Active() bool {
+	return p != nil && (len(p.enforce) > 0 || len(p.warnOnly) > 0)
+}
+
+// Level returns the level of findings in the file at path. A nil policy enforces every file.
+func (p *Policy) Level(path string) Level {
+	if !p.Active() {
+		return LevelError
+	}
+	if matchesAny(p.warnOnly, path) {
+		return LevelWarning
+	}
+	if len(p.enforce) > 0 && !matchesAny(p.enforce, path) {
+		return LevelWarning
+	}
+	return LevelError
+}
+
+// DefaultLevel returns the level of findings in files that match no pattern: LevelWarning
+// when enforce patterns are given, otherwise LevelError.
+func (p *Policy) DefaultLevel() Level {
+	if p != nil && len(p.enforce) > 0 {
+		return LevelWarning
+	}
+	return LevelError
+}
+
+// compile converts glob patterns to anchored regular expressions.
+func compile(setting string, globs []string) ([]pattern, error) {
+	patterns := make([]pattern, 0, len(globs))
+	for _, glob := range globs {
+		glob = strings.TrimPrefix(filepath.ToSlash(glob), "./")
+		if glob == "" {
+			return nil, fmt.Errorf("%s: empty pattern", setting)
+		}
+		re, err := regexp.Compile("^" + scan.GlobToRegexp(glob) + "$")
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid pattern %q: %w", setting, glob, err)
+		}
+		patterns = append(patterns, pattern{re: re, hasSlash: strings.Contains(glob, "/")})
+	}
+	return patterns, nil
+}
+
+// matchesAny reports whether any pattern matches the file's base name (for patterns without
+// a slash) or its slash path or any trailing part of it.
+func matchesAny(patterns []pattern, path string) bool {
+	slashPath := filepath.ToSlash(path)
+	base := filepath.Base(path)
+	for _, p := range patterns {
+		re := p.re
+		if !p.hasSlash {
+			if re.MatchString(base) {
+				return true
+			}
+			continue
+		}
+		for suffix := slashPath; ; {
+			if re.MatchString(suffix) {
+				return true
+			}
+			i := strings.Index(suffix, "/")
+			if i < 0 {
+				break
+			}
+			suffix = suffix[i+1:]
+		}
+	}
+	return false
+}
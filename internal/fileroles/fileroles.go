@@ -0,0 +1,166 @@
+// Package fileroles classifies provider source files by role (sweeper, migration, base,
+// model, generated, docs) using glob patterns.
+//
+// Built-in patterns reproduce the filename heuristics the linter has always applied
+// (base_*.go, *_sweeper.go, *_migrate.go, ...). Settings.FileRoles adds patterns for
+// provider-specific layouts; what each role means for analysis is decided by
+// config.Settings.ExcludesFileRole.
+package fileroles
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Role is the role of a source file.
+type Role string
+
+// Supported roles. None is the role of an ordinary file.
+const (
+	None      Role = ""
+	Sweeper   Role = "sweeper"   // Test sweeper registrations and cleanup functions
+	Migration Role = "migration" // State migrations and upgraders
+	Base      Role = "base"      // Shared base implementations embedded by real resources
+	Model     Role = "model"     // Schema model structs without definitions of their own
+	Generated Role = "generated" // Generated code; still analyzed, since it often declares real resources
+	Docs      Role = "docs"      // Documentation generators and examples
+)
+
+// Roles returns every supported role in display order.
+func Roles() []Role {
+	return []Role{Sweeper, Migration, Base, Model, Generated, Docs}
+}
+
+// Valid reports whether name is a supported role.
+func Valid(name string) bool {
+	for _, role := range Roles() {
+		if string(role) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultPatterns returns the built-in pattern to role mapping.
+func DefaultPatterns() map[string]Role {
+	return map[string]Role{
+		"base_*.go":           Base,
+		"base.*":              Base,
+		"*_sweeper.go":        Sweeper,
+		"*_migrate.go":        Migration,
+		"*_migration*":        Migration,
+		"*_state_upgrader.go": Migration,
+	}
+}
+
+// rule is one pattern of a Classifier.
+type rule struct {
+	pattern string
+	role    Role
+	custom  bool
+}
+
+// Classifier assigns roles to file paths.
+type Classifier struct {
+	rules []rule
+}
+
+// NewClassifier returns a classifier for the built-in patterns plus custom, a pattern to
+// role-name mapping such as Settings.FileRoles. A custom pattern that repeats a built-in
+// one replaces its role.
+//
+// Patterns without a slash match the file's base name; patterns with a slash match the
+// slash-separated path or any trailing part of it, so "internal/models/*.go" matches
+// "/src/provider/internal/models/widget.go". When several patterns match, custom patterns
+// win over built-in ones and, within each group, the longest pattern wins.
+func NewClassifier(custom map[string]string) (*Classifier, error) {
+	byPattern := make(map[string]rule)
+	for pattern, role := range DefaultPatterns() {
+		byPattern[pattern] = rule{pattern: pattern, role: role}
+	}
+	for pattern, name := range custom {
+		if !Valid(name) {
+			return nil, fmt.Errorf("file-roles: unknown role %q for pattern %q (expected one of: %s)", name, pattern, roleList())
+		}
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("file-roles: invalid pattern %q: %w", pattern, err)
+		}
+		byPattern[pattern] = rule{pattern: pattern, role: Role(name), custom: true}
+	}
+
+	c := &Classifier{}
+	for _, r := range byPattern {
+		c.rules = append(c.rules, r)
+	}
+	sort.Slice(c.rules, func(i, j int) bool {
+		a, b := c.rules[i], c.rules[j]
+		if a.custom != b.custom {
+			return a.custom
+		}
+		if len(a.pattern) != len(b.pattern) {
+			return len(a.pattern) > len(b.pattern)
+		}
+		return a.pattern < b.pattern
+	})
+	return c, nil
+}
+
+// defaultClassifier is shared by Default; classifiers are immutable once built.
+var defaultClassifier, _ = NewClassifier(nil)
+
+// Default returns a classifier with only the built-in patterns.
+func Default() *Classifier {
+	return defaultClassifier
+}
+
+// Classify returns the role of the file at path, or None.
+func (c *Classifier) Classify(path string) Role {
+	slashPath := filepath.ToSlash(path)
+	base := filepath.Base(path)
+	for _, r := range c.rules {
+		if matches(r.pattern, slashPath, base) {
+			return r.role
+		}
+	}
+	return None
+}
+
+// Counts tallies files by role, omitting files without one.
+func (c *Classifier) Counts(paths []string) map[Role]int {
+	counts := make(map[Role]int)
+	for _, path := range paths {
+		if role := c.Classify(path); role != None {
+			counts[role]++
+		}
+	}
+	return counts
+}
+
+// matches reports whether pattern matches a file given its slash path and base name.
+func matches(pattern, slashPath, base string) bool {
+	if !strings.Contains(pattern, "/") {
+		ok, _ := filepath.Match(pattern, base)
+		return ok
+	}
+	for suffix := slashPath; ; {
+		if ok, _ := filepath.Match(pattern, suffix); ok {
+			return true
+		}
+		i := strings.Index(suffix, "/")
+		if i < 0 {
+			return false
+		}
+		suffix = suffix[i+1:]
+	}
+}
+
+// roleList returns the supported role names for error messages.
+func roleList() string {
+	names := make([]string, 0, len(Roles()))
+	for _, role := range Roles() {
+		names = append(names, string(role))
+	}
+	return strings.Join(names, ", ")
+}
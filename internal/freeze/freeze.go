@@ -0,0 +1,194 @@
+// Package freeze records the coverage checks each definition has at a point in time, in a
+// tfprovidertest.freeze.yaml file checked into the provider repository, and reports the
+// checks that were covered then and are not anymore:
+//
+//	coverage:
+//	  data source:widget: [basic]
+//	  resource:widget: [basic, update, import]
+//
+// It is the lightest coverage gate: it never asks for more coverage than a provider has, and
+// only fails when a test covering a check is deleted, renamed so it no longer links, or loses
+// the step that covered the check. Definitions removed from the provider are not regressions.
+package freeze
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// File is the default name of the freeze file.
+const File = "tfprovidertest.freeze.yaml"
+
+// header is written at the top of every freeze file.
+const header = "# Coverage frozen by validate -freeze write. validate -freeze check fails when a check\n# listed here is no longer covered. Rewrite the file to record new coverage.\n"
+
+// Freeze maps registry keys, such as "resource:widget", to the checks their tests covered.
+type Freeze struct {
+	Coverage map[string][]string `yaml:"coverage"`
+}
+
+// Capture records the checks covered by the tests linked to each definition of reg.
+// Definitions without covered checks are left out.
+func Capture(reg *registry.ResourceRegistry) *Freeze {
+	f := &Freeze{Coverage: make(map[string][]string)}
+	for _, info := range reg.GetSortedDefinitions() {
+		if checks := coveredChecks(reg, info); len(checks) > 0 {
+			f.Coverage[info.Key().String()] = checks
+		}
+	}
+	return f
+}
+
+// coveredChecks returns the checks that apply to a definition and its tests cover, in
+// directive order.
+func coveredChecks(reg *registry.ResourceRegistry, info *registry.ResourceInfo) []string {
+	covered := registry.CoveredChecks(reg.GetTests(info.Kind, info.Name))
+	var checks []string
+	for _, check := range registry.DirectiveChecks() {
+		if covered[check] && registry.CheckApplies(info.Kind, check) {
+			checks = append(checks, check)
+		}
+	}
+	return checks
+}
+
+// Parse parses freeze file data and validates its keys and checks. path is used in error
+// messages.
+func Parse(data []byte, path string) (*Freeze, error) {
+	f := &Freeze{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	known := make(map[string]bool)
+	for _, check := range registry.DirectiveChecks() {
+		known[check] = true
+	}
+	for key, checks := range f.Coverage {
+		if _, ok := registry.ParseResourceKey(key); !ok {
+			return nil, fmt.Errorf("%s: %q is not a definition key such as \"resource:widget\"", path, key)
+		}
+		for _, check := range checks {
+			if !known[check] {
+				return nil, fmt.Errorf("%s: %s: unknown check %q", path, key, check)
+			}
+		}
+	}
+	return f, nil
+}
+
+// Load reads and parses the freeze file at path.
+func Load(path string) (*Freeze, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data, path)
+}
+
+// Marshal encodes the freeze file with sorted keys and one line per definition.
+func (f *Freeze) Marshal() ([]byte, error) {
+	keys := make([]string, 0, len(f.Coverage))
+	for key := range f.Coverage {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	coverage := &yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range keys {
+		checks := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for _, check := range f.Coverage[key] {
+			checks.Content = append(checks.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: check})
+		}
+		coverage.Content = append(coverage.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, checks)
+	}
+	root := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{{Kind: yaml.ScalarNode, Value: "coverage"}, coverage}}
+
+	var buf bytes.Buffer
+	buf.WriteString(header)
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Write writes the freeze file to path.
+func (f *Freeze) Write(path string) error {
+	data, err := f.Marshal()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Regression is a frozen check a definition's tests no longer cover.
+type Regression struct {
+	Key        registry.ResourceKey `json:"-"`
+	Definition string               `json:"definition"`
+	Check      string               `json:"check"`
+}
+
+// Result is the outcome of comparing a registry with a freeze file.
+type Result struct {
+	// Regressions lists the frozen checks that are no longer covered, by definition and check
+	Regressions []Regression `json:"regressions"`
+	// Gained counts the covered checks the freeze file does not list yet
+	Gained int `json:"gained"`
+	// Removed lists the frozen definitions the provider no longer has
+	Removed []string `json:"removed,omitempty"`
+}
+
+// Check compares the coverage of reg with the freeze file.
+func (f *Freeze) Check(reg *registry.ResourceRegistry) Result {
+	result := Result{Regressions: []Regression{}}
+	current := Capture(reg)
+
+	keys := make([]string, 0, len(f.Coverage))
+	for key := range f.Coverage {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		parsed, _ := registry.ParseResourceKey(key)
+		if reg.GetDefinition(parsed.Kind, parsed.Name) == nil {
+			result.Removed = append(result.Removed, key)
+			continue
+		}
+		covered := make(map[string]bool)
+		for _, check := range current.Coverage[key] {
+			covered[check] = true
+		}
+		for _, check := range f.Coverage[key] {
+			if !covered[check] {
+				result.Regressions = append(result.Regressions, Regression{Key: parsed, Definition: key, Check: check})
+			}
+		}
+	}
+
+	for key, checks := range current.Coverage {
+		frozen := make(map[string]bool)
+		for _, check := range f.Coverage[key] {
+			frozen[check] = true
+		}
+		for _, check := range checks {
+			if !frozen[check] {
+				result.Gained++
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,127 @@
+package ghaction
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// GitHub is the part of the GitHub REST API the action uses to keep its pull request
+// comment up to date.
+type GitHub struct {
+	APIURL     string // e.g. https://api.github.com, $GITHUB_API_URL
+	Token      string
+	Repository string // owner/name, $GITHUB_REPOSITORY
+	Client     *http.Client
+}
+
+// issueComment is the part of a GitHub issue comment the action reads.
+type issueComment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+// commentsPerPage is the page size of comment listings, the maximum GitHub allows.
+const commentsPerPage = 100
+
+// UpsertComment updates the comment carrying CommentMarker on pull request pr with body, or
+// posts it when the pull request has none yet.
+func (g GitHub) UpsertComment(ctx context.Context, pr int, body string) error {
+	id, err := g.findComment(ctx, pr)
+	if err != nil {
+		return err
+	}
+	payload := map[string]string{"body": body}
+	if id == 0 {
+		return g.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/issues/%d/comments", g.Repository, pr), payload, nil)
+	}
+	return g.do(ctx, http.MethodPatch, fmt.Sprintf("/repos/%s/issues/comments/%d", g.Repository, id), payload, nil)
+}
+
+// findComment returns the ID of the first comment on pull request pr carrying
+// CommentMarker, or 0.
+func (g GitHub) findComment(ctx context.Context, pr int) (int64, error) {
+	for page := 1; ; page++ {
+		var comments []issueComment
+		path := fmt.Sprintf("/repos/%s/issues/%d/comments?per_page=%d&page=%d", g.Repository, pr, commentsPerPage, page)
+		if err := g.do(ctx, http.MethodGet, path, nil, &comments); err != nil {
+			return 0, err
+		}
+		for _, c := range comments {
+			if strings.Contains(c.Body, CommentMarker) {
+				return c.ID, nil
+			}
+		}
+		if len(comments) < commentsPerPage {
+			return 0, nil
+		}
+	}
+}
+
+// do sends a request to the API and decodes the response into out, if not nil.
+func (g GitHub) do(ctx context.Context, method, path string, payload, out any) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(g.APIURL, "/")+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if g.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.Token)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := g.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// PullRequestNumber returns the number of the pull request of the event payload at path,
+// $GITHUB_EVENT_PATH, or 0 when the workflow was not triggered by a pull request.
+func PullRequestNumber(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	var event struct {
+		PullRequest *struct {
+			Number int `json:"number"`
+		} `json:"pull_request"`
+	}
+	if err := json.Unmarshal(data, &event); err != nil {
+		return 0, fmt.Errorf("%s: %w", path, err)
+	}
+	if event.PullRequest == nil {
+		return 0, nil
+	}
+	return event.PullRequest.Number, nil
+}
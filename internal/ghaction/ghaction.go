@@ -0,0 +1,248 @@
+// Package ghaction implements the GitHub Action of cmd/action: it compares the coverage
+// report of a pull request with the report of its base branch, renders the comparison as
+// Markdown for the job summary and a sticky pull request comment, and writes the step
+// outputs later workflow steps read.
+package ghaction
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/example/tfprovidertest/pkg/metrics"
+)
+
+// CommentMarker is the hidden line that identifies the action's comment on a pull request,
+// so each run updates it instead of adding another.
+const CommentMarker = "<!-- tfprovidertest-coverage -->"
+
+// maxListed is the number of definitions the summary lists per section before folding the
+// rest into a count, keeping comments on large providers readable.
+const maxListed = 50
+
+// Coverage is the share of definitions with at least one acceptance test.
+type Coverage struct {
+	Tested int
+	Total  int
+}
+
+// Fraction returns Tested/Total, or 1 for a provider without definitions.
+func (c Coverage) Fraction() float64 {
+	if c.Total == 0 {
+		return 1
+	}
+	return float64(c.Tested) / float64(c.Total)
+}
+
+// KindCoverage is the coverage of the definitions of one kind.
+type KindCoverage struct {
+	Kind string
+	Head Coverage
+	Base *Coverage // nil without a base report
+}
+
+// Comparison is the coverage of the pull request, compared with its base branch when a base
+// report is available.
+type Comparison struct {
+	BaseRef  string // Name the base is shown by, e.g. main
+	Head     Coverage
+	Base     *Coverage // nil without a base report
+	Kinds    []KindCoverage
+	Untested []metrics.Definition // Definitions without a test in the pull request
+	NewGaps  []metrics.Definition // Untested definitions that were tested, or did not exist, on the base
+	Closed   []metrics.Definition // Definitions tested in the pull request that were untested on the base
+}
+
+// Compare compares the definitions of the head report with those of the base report; base
+// may be nil. Definitions are matched by kind and name.
+func Compare(head []metrics.Definition, base []metrics.Definition, baseRef string) Comparison {
+	c := Comparison{BaseRef: baseRef, Head: coverageOf(head)}
+	baseTests := make(map[string]int)
+	if base != nil {
+		baseCoverage := coverageOf(base)
+		c.Base = &baseCoverage
+		for _, d := range base {
+			baseTests[definitionKey(d)] = d.TestCount
+		}
+	}
+
+	kinds := make(map[string]*KindCoverage)
+	kindOf := func(kind string) *KindCoverage {
+		if kinds[kind] == nil {
+			kinds[kind] = &KindCoverage{Kind: kind}
+			if base != nil {
+				kinds[kind].Base = &Coverage{}
+			}
+		}
+		return kinds[kind]
+	}
+	for _, d := range base {
+		k := kindOf(d.Kind)
+		k.Base.Total++
+		if d.TestCount > 0 {
+			k.Base.Tested++
+		}
+	}
+	for _, d := range sortedDefinitions(head) {
+		k := kindOf(d.Kind)
+		k.Head.Total++
+		tests, existed := baseTests[definitionKey(d)]
+		if d.TestCount > 0 {
+			k.Head.Tested++
+			if base != nil && existed && tests == 0 {
+				c.Closed = append(c.Closed, d)
+			}
+			continue
+		}
+		c.Untested = append(c.Untested, d)
+		if base != nil && (!existed || tests > 0) {
+			c.NewGaps = append(c.NewGaps, d)
+		}
+	}
+	for _, k := range kinds {
+		c.Kinds = append(c.Kinds, *k)
+	}
+	sort.Slice(c.Kinds, func(i, j int) bool { return c.Kinds[i].Kind < c.Kinds[j].Kind })
+	return c
+}
+
+// definitionKey identifies a definition across reports.
+func definitionKey(d metrics.Definition) string {
+	return d.Kind + "\x00" + d.Name
+}
+
+func coverageOf(defs []metrics.Definition) Coverage {
+	var c Coverage
+	for _, d := range defs {
+		c.Total++
+		if d.TestCount > 0 {
+			c.Tested++
+		}
+	}
+	return c
+}
+
+// sortedDefinitions returns defs ordered by kind, then name.
+func sortedDefinitions(defs []metrics.Definition) []metrics.Definition {
+	sorted := append([]metrics.Definition(nil), defs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Kind != sorted[j].Kind {
+			return sorted[i].Kind < sorted[j].Kind
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// FormatPercent formats a fraction as a percentage with at most one decimal.
+func FormatPercent(fraction float64) string {
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", fraction*100), ".0") + "%"
+}
+
+// formatChange formats the change between two coverages in percentage points.
+func formatChange(head, base Coverage) string {
+	points := (head.Fraction() - base.Fraction()) * 100
+	switch {
+	case points > 0.05:
+		return fmt.Sprintf("+%.1f pts", points)
+	case points < -0.05:
+		return fmt.Sprintf("%.1f pts", points)
+	}
+	return "±0 pts"
+}
+
+// Markdown renders the comparison for the job summary and the pull request comment. The
+// comment carries CommentMarker, which the summary may carry as well: it is not displayed.
+func (c Comparison) Markdown() string {
+	var b strings.Builder
+	b.WriteString(CommentMarker + "\n")
+	b.WriteString("## Acceptance test coverage\n\n")
+	fmt.Fprintf(&b, "**%s** of definitions have an acceptance test (%d/%d)", FormatPercent(c.Head.Fraction()), c.Head.Tested, c.Head.Total)
+	if c.Base != nil {
+		fmt.Fprintf(&b, ", %s versus `%s` (%s)", formatChange(c.Head, *c.Base), c.BaseRef, FormatPercent(c.Base.Fraction()))
+	}
+	b.WriteString(".\n\n")
+
+	if len(c.Kinds) > 0 {
+		if c.Base != nil {
+			b.WriteString("| Kind | Tested | Total | Coverage | Change |\n|---|---:|---:|---:|---:|\n")
+		} else {
+			b.WriteString("| Kind | Tested | Total | Coverage |\n|---|---:|---:|---:|\n")
+		}
+		for _, k := range c.Kinds {
+			fmt.Fprintf(&b, "| %s | %d | %d | %s |", k.Kind, k.Head.Tested, k.Head.Total, FormatPercent(k.Head.Fraction()))
+			if k.Base != nil {
+				fmt.Fprintf(&b, " %s |", formatChange(k.Head, *k.Base))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	if c.Base != nil {
+		fmt.Fprintf(&b, "### New gaps (%d)\n\n", len(c.NewGaps))
+		if len(c.NewGaps) == 0 {
+			b.WriteString("No definition lost its tests or was added without one.\n\n")
+		} else {
+			fmt.Fprintf(&b, "Definitions without an acceptance test that were tested on `%s`, or are new:\n\n", c.BaseRef)
+			writeDefinitions(&b, c.NewGaps)
+		}
+		if len(c.Closed) > 0 {
+			fmt.Fprintf(&b, "### Closed gaps (%d)\n\n", len(c.Closed))
+			writeDefinitions(&b, c.Closed)
+		}
+	}
+	if len(c.Untested) > 0 {
+		fmt.Fprintf(&b, "<details>\n<summary>All untested definitions (%d)</summary>\n\n", len(c.Untested))
+		writeDefinitions(&b, c.Untested)
+		b.WriteString("</details>\n")
+	}
+	return b.String()
+}
+
+// writeDefinitions writes defs as a list, folding those past maxListed into a count. Names
+// link to the code when the report has links.
+func writeDefinitions(b *strings.Builder, defs []metrics.Definition) {
+	for i, d := range defs {
+		if i == maxListed {
+			fmt.Fprintf(b, "- ...and %d more\n", len(defs)-maxListed)
+			break
+		}
+		if d.URL != "" {
+			fmt.Fprintf(b, "- [`%s`](%s) (%s)\n", d.Name, d.URL, d.Kind)
+		} else {
+			fmt.Fprintf(b, "- `%s` (%s)\n", d.Name, d.Kind)
+		}
+	}
+	b.WriteString("\n")
+}
+
+// WriteOutputs appends the step outputs to path as key=value lines, the format of
+// $GITHUB_OUTPUT: coverage, the percentage of definitions tested, and new_gaps, the number
+// of new gaps (0 without a base report).
+func WriteOutputs(path string, c Comparison) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(f, "coverage=%.1f\n", c.Head.Fraction()*100)
+	fmt.Fprintf(f, "new_gaps=%d\n", len(c.NewGaps))
+	if c.Base != nil {
+		fmt.Fprintf(f, "base_coverage=%.1f\n", c.Base.Fraction()*100)
+	}
+	return f.Close()
+}
+
+// AppendSummary appends markdown to the job summary file at path, $GITHUB_STEP_SUMMARY.
+func AppendSummary(path, markdown string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(markdown); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,252 @@
+// Package gitmeta correlates the age of resource definitions with their test coverage.
+//
+// A definition's age is the commit time of the last commit that touched its source file,
+// read with `git log -1 --format=%ct`. Files outside a git work tree, or not yet
+// committed, have no known age and are counted separately. Recently added or modified
+// definitions without tests are the most urgent gaps for reviewers, so the report lists
+// untested definitions newest first.
+package gitmeta
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// LastModifiedFunc returns the last commit time of a file. ok is false when the file's
+// age is unknown.
+type LastModifiedFunc func(path string) (modified time.Time, ok bool)
+
+// Bucket is an age range. Definitions fall into the first bucket whose MaxAge is at least
+// their age; a zero MaxAge matches any age.
+type Bucket struct {
+	Label  string
+	MaxAge time.Duration
+}
+
+// DefaultBuckets returns the age ranges used by the coverage report.
+func DefaultBuckets() []Bucket {
+	const day = 24 * time.Hour
+	return []Bucket{
+		{Label: "last 7 days", MaxAge: 7 * day},
+		{Label: "last 30 days", MaxAge: 30 * day},
+		{Label: "last 90 days", MaxAge: 90 * day},
+		{Label: "last year", MaxAge: 365 * day},
+		{Label: "older"},
+	}
+}
+
+// Report summarizes coverage by definition age.
+type Report struct {
+	Buckets []BucketReport `json:"buckets"`
+	// Untested lists definitions without tests, most recently modified first.
+	Untested []Entry `json:"untested"`
+	// Unknown counts definitions whose file has no commit history.
+	Unknown int `json:"unknown_age"`
+}
+
+// BucketReport is the coverage of definitions within one age range.
+type BucketReport struct {
+	Label    string  `json:"label"`
+	Total    int     `json:"total"`
+	Tested   int     `json:"tested"`
+	Untested int     `json:"untested"`
+	Coverage float64 `json:"coverage"`
+}
+
+// Entry is an untested definition with its age.
+type Entry struct {
+	Name         string    `json:"name"`
+	Kind         string    `json:"kind"`
+	File         string    `json:"file"`
+	LastModified time.Time `json:"last_modified"`
+	AgeDays      int       `json:"age_days"`
+	Bucket       string    `json:"bucket"`
+}
+
+// Build groups every definition in the registry by the age of its source file and
+// reports coverage per age range.
+func Build(reg *registry.ResourceRegistry, buckets []Bucket, now time.Time, lastModified LastModifiedFunc) Report {
+	report := Report{Buckets: make([]BucketReport, len(buckets)), Untested: []Entry{}}
+	for i, bucket := range buckets {
+		report.Buckets[i].Label = bucket.Label
+	}
+
+	for _, info := range reg.GetAllDefinitions() {
+		modified, ok := lastModified(info.FilePath)
+		if !ok {
+			report.Unknown++
+			continue
+		}
+		age := now.Sub(modified)
+		if age < 0 {
+			age = 0
+		}
+		index := bucketIndex(buckets, age)
+		if index < 0 {
+			continue
+		}
+
+		bucket := &report.Buckets[index]
+		bucket.Total++
+		if len(reg.GetTests(info.Kind, info.Name)) > 0 {
+			bucket.Tested++
+			continue
+		}
+		bucket.Untested++
+		report.Untested = append(report.Untested, Entry{
+			Name:         info.Name,
+			Kind:         info.Kind.String(),
+			File:         info.FilePath,
+			LastModified: modified,
+			AgeDays:      int(age / (24 * time.Hour)),
+			Bucket:       bucket.Label,
+		})
+	}
+
+	for i := range report.Buckets {
+		if report.Buckets[i].Total > 0 {
+			report.Buckets[i].Coverage = float64(report.Buckets[i].Tested) / float64(report.Buckets[i].Total)
+		}
+	}
+	sort.SliceStable(report.Untested, func(i, j int) bool {
+		a, b := report.Untested[i], report.Untested[j]
+		if !a.LastModified.Equal(b.LastModified) {
+			return a.LastModified.After(b.LastModified)
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+	return report
+}
+
+// bucketIndex returns the index of the first bucket that holds age, or -1.
+func bucketIndex(buckets []Bucket, age time.Duration) int {
+	for i, bucket := range buckets {
+		if bucket.MaxAge == 0 || age <= bucket.MaxAge {
+			return i
+		}
+	}
+	return -1
+}
+
+// GitLastModified returns a LastModifiedFunc that asks git for each file's last commit
+// time. Results are cached per file, since several definitions often share one file.
+func GitLastModified(ctx context.Context) LastModifiedFunc {
+	var mu sync.Mutex
+	cache := make(map[string]*time.Time)
+	return func(path string) (time.Time, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		if cached, seen := cache[path]; seen {
+			if cached == nil {
+				return time.Time{}, false
+			}
+			return *cached, true
+		}
+		modified, err := LastCommitTime(ctx, path)
+		if err != nil {
+			cache[path] = nil
+			return time.Time{}, false
+		}
+		cache[path] = &modified
+		return modified, true
+	}
+}
+
+// LastCommitTime runs `git log -1 --format=%ct` for a file from its own directory, so
+// the file may live in any repository.
+func LastCommitTime(ctx context.Context, path string) (time.Time, error) {
+	cmd := exec.CommandContext(ctx, "git", "-C", filepath.Dir(path), "log", "-1", "--format=%ct", "--", filepath.Base(path))
+	out, err := cmd.Output()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("git log %s: %w", path, err)
+	}
+	return ParseCommitTime(string(out))
+}
+
+// ParseCommitTime parses the output of `git log --format=%ct`. Empty output means the
+// file has no commits.
+func ParseCommitTime(out string) (time.Time, error) {
+	out = strings.TrimSpace(out)
+	if out == "" {
+		return time.Time{}, fmt.Errorf("no commits")
+	}
+	seconds, err := strconv.ParseInt(out, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid commit time %q: %w", out, err)
+	}
+	return time.Unix(seconds, 0), nil
+}
+
+// FileAtFunc returns the content of a file at the base of the change under review. ok is
+// false when the file did not exist there.
+type FileAtFunc func(path string) (data []byte, ok bool, err error)
+
+// GitFileAt returns a FileAtFunc that reads files at ref, such as origin/main, with
+// `git show`, from each file's own directory. Reading fails when the repository does not
+// know ref.
+func GitFileAt(ctx context.Context, ref string) FileAtFunc {
+	var mu sync.Mutex
+	verified := make(map[string]error) // By directory
+	return func(path string) ([]byte, bool, error) {
+		dir := filepath.Dir(path)
+		mu.Lock()
+		err, seen := verified[dir]
+		if !seen {
+			cmd := exec.CommandContext(ctx, "git", "-C", dir, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+			if cmd.Run() != nil {
+				err = fmt.Errorf("git ref %q not found in the repository of %s", ref, dir)
+			}
+			verified[dir] = err
+		}
+		mu.Unlock()
+		if err != nil {
+			return nil, false, err
+		}
+
+		out, err := exec.CommandContext(ctx, "git", "-C", dir, "show", ref+":./"+filepath.Base(path)).Output()
+		if err != nil {
+			return nil, false, nil // Added since ref
+		}
+		return out, true, nil
+	}
+}
+
+// ChangedFiles returns the absolute paths of the files of the git work tree containing dir
+// that differ from ref, committed or not, with `git diff --name-only`. Deleted files are
+// included.
+func ChangedFiles(ctx context.Context, dir, ref string) ([]string, error) {
+	out, err := exec.CommandContext(ctx, "git", "-C", dir, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s is not in a git work tree", dir)
+	}
+	root := strings.TrimSpace(string(out))
+	out, err = exec.CommandContext(ctx, "git", "-C", root, "diff", "--name-only", "-z", ref, "--").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff %s: %w", ref, err)
+	}
+	return ParseChangedFiles(root, string(out)), nil
+}
+
+// ParseChangedFiles parses the output of `git diff --name-only -z`, paths relative to the
+// work tree at root, into absolute paths.
+func ParseChangedFiles(root, out string) []string {
+	var paths []string
+	for _, name := range strings.Split(out, "\x00") {
+		if name != "" {
+			paths = append(paths, filepath.Join(root, filepath.FromSlash(name)))
+		}
+	}
+	return paths
+}
@@ -0,0 +1,142 @@
+// Package initconfig renders the files validate init writes and prints for a provider
+// repository: a commented tfprovidertest.yaml settings file holding the values detected from
+// the repository and the rule toggles of the recommended profile, and the .golangci.yml block
+// that runs the linter with it.
+package initconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/rules"
+	"github.com/example/tfprovidertest/internal/scan"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// Profile is the profile the settings file starts from.
+const Profile = config.ProfileRecommended
+
+// Detected holds what validate init found in a provider repository.
+type Detected struct {
+	ModulePath string // Module path from go.mod, empty when there is none
+	// ProviderPrefix is the provider's type name from its Metadata method, e.g. "example",
+	// empty when no provider definition was found
+	ProviderPrefix string
+	Layout         string // Name of the generated layout detected, empty for none
+	// TestLayout is config.TestLayoutColocated or config.TestLayoutCentralized, or empty when
+	// no test is linked to a definition
+	TestLayout     string
+	HelperPackages []HelperPackage
+	Resources      int
+	DataSources    int
+	Actions        int
+	Tests          int // Acceptance tests linked to a definition
+}
+
+// HelperPackage is a package whose exported functions build or run acceptance test cases.
+type HelperPackage struct {
+	Name    string
+	Dir     string // Relative to the repository, with forward slashes
+	Helpers []string
+}
+
+// Render returns the content of the settings file for d. Every rule toggle is listed,
+// commented out, with its value under Profile, so enabling a rule is a matter of
+// uncommenting its line.
+func Render(d Detected) string {
+	var b strings.Builder
+	b.WriteString("# tfprovidertest settings")
+	if d.ModulePath != "" {
+		fmt.Fprintf(&b, " for %s", d.ModulePath)
+	}
+	b.WriteString(", written by validate init.\n")
+	b.WriteString("# validate reads this file from the provider directory (or -config); golangci-lint\n")
+	b.WriteString("# reads it through the ConfigFile setting. Settings are described in the settings\n")
+	b.WriteString("# reference of the README.\n")
+	fmt.Fprintf(&b, "#\n# Detected %d resource(s), %d data source(s), %d action(s) and %d linked acceptance test(s).\n",
+		d.Resources, d.DataSources, d.Actions, d.Tests)
+
+	b.WriteString("\n# Profile the other settings start from: minimal, recommended or strict.\n")
+	fmt.Fprintf(&b, "profile: %s\n", Profile)
+
+	b.WriteString("\n# Type name of the provider, from its Metadata method, used to match test function and\n")
+	b.WriteString("# config names to definitions.\n")
+	if d.ProviderPrefix != "" {
+		fmt.Fprintf(&b, "provider-prefix: %q\n", d.ProviderPrefix)
+	} else {
+		b.WriteString("# No provider Metadata method was found; set the type name, e.g. \"example\".\n")
+		b.WriteString("# provider-prefix: \"\"\n")
+	}
+
+	b.WriteString("\n# Directory layout validate looks for resource code in: auto, none, or a generated layout\n")
+	fmt.Fprintf(&b, "# (%s).\n", strings.Join(scan.LayoutNames(), ", "))
+	if d.Layout != "" {
+		fmt.Fprintf(&b, "# Detected the %s layout.\n", d.Layout)
+		fmt.Fprintf(&b, "layout: %s\n", d.Layout)
+	} else {
+		b.WriteString("# No generated layout was detected.\n")
+		fmt.Fprintf(&b, "layout: %s\n", config.LayoutAuto)
+	}
+
+	b.WriteString("\n# Where the acceptance tests live: co-located next to the definitions, or centralized in\n")
+	b.WriteString("# packages of their own.\n")
+	if d.TestLayout != "" {
+		fmt.Fprintf(&b, "test-layout: %s\n", d.TestLayout)
+	} else {
+		fmt.Fprintf(&b, "test-layout: %s\n", config.TestLayoutAuto)
+	}
+
+	b.WriteString("\n# Exported functions of scanned packages that build or run test cases count as\n")
+	b.WriteString("# resource.Test without further configuration.\n")
+	if len(d.HelperPackages) == 0 {
+		b.WriteString("# No helper packages were detected.\n")
+	} else {
+		b.WriteString("# Detected helper packages:\n")
+		for _, pkg := range d.HelperPackages {
+			fmt.Fprintf(&b, "#   %s (%s): %s\n", pkg.Name, pkg.Dir, strings.Join(pkg.Helpers, ", "))
+		}
+	}
+	b.WriteString("ignore-helper-packages: false\n")
+
+	b.WriteString("\n# Functions outside the scanned packages that wrap resource.Test, by name.\n")
+	b.WriteString("# custom-test-helpers: []\n")
+
+	fmt.Fprintf(&b, "\n# Rules, with their value under the %s profile. Uncomment a line to change it.\n", Profile)
+	s, _ := config.ProfileSettings(Profile)
+	for _, group := range []rules.Group{rules.GroupCoverage, rules.GroupQuality} {
+		for _, rule := range rules.All() {
+			if rule.Group != group {
+				continue
+			}
+			key, enabled, ok := config.RuleToggleIn(s, rule.Name)
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(&b, "# %s (%s): %s\n", rule.Name, rule.Code, rule.Doc)
+			fmt.Fprintf(&b, "# %s: %t\n", key, enabled)
+		}
+	}
+
+	b.WriteString("\n# Language (en, ja) and style (long, short) of diagnostic messages.\n")
+	b.WriteString("language: en\n")
+	fmt.Fprintf(&b, "message-style: %s\n", config.MessageStyleLong)
+	return b.String()
+}
+
+// GolangciConfig returns the .golangci.yml block that runs the linter as a module plugin
+// with the settings file at configFile, relative to where golangci-lint runs.
+func GolangciConfig(configFile string) string {
+	return `version: "2"
+
+linters:
+  enable:
+    - tfprovidertest
+  settings:
+    custom:
+      tfprovidertest:
+        type: module
+        description: Terraform provider test coverage linter
+        original-url: github.com/example/tfprovidertest
+        settings:
+          ConfigFile: ` + configFile + "\n"
+}
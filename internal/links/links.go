@@ -0,0 +1,105 @@
+// Package links turns positions in the scanned provider into web URLs, such as GitHub blob
+// links, so reports can link every resource, test and finding to its code. URLs come from
+// the repo-url-template setting:
+//
+//	https://github.com/example/terraform-provider-example/blob/main/{path}#L{line}
+//
+// {path} is the file path relative to the repository root, with forward slashes, and
+// {line} is the line number, or 1 when it is unknown.
+package links
+
+import (
+	"context"
+	"fmt"
+	"go/token"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Placeholders of a URL template.
+const (
+	PathPlaceholder = "{path}"
+	LinePlaceholder = "{line}"
+)
+
+// Validate reports a non-empty template without a {path} placeholder.
+func Validate(template string) error {
+	if template != "" && !strings.Contains(template, PathPlaceholder) {
+		return fmt.Errorf("repo-url-template %q has no %s placeholder", template, PathPlaceholder)
+	}
+	return nil
+}
+
+// Linker renders URLs from a template. The zero Linker renders none.
+type Linker struct {
+	template string
+	root     string
+	fset     *token.FileSet
+}
+
+// New returns a Linker for template, resolving file paths relative to root. fset resolves
+// the positions passed to Pos and may be nil if Pos is not used.
+func New(template, root string, fset *token.FileSet) Linker {
+	if abs, err := filepath.Abs(root); err == nil {
+		root = abs
+	}
+	return Linker{template: template, root: root, fset: fset}
+}
+
+// Enabled reports whether the Linker has a template.
+func (l Linker) Enabled() bool {
+	return l.template != ""
+}
+
+// URL returns the URL of a line of the file at path, or "" when there is no template or the
+// file is outside the root.
+func (l Linker) URL(path string, line int) string {
+	if l.template == "" || path == "" {
+		return ""
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	rel, err := filepath.Rel(l.root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	if line <= 0 {
+		line = 1
+	}
+	url := strings.ReplaceAll(l.template, PathPlaceholder, filepath.ToSlash(rel))
+	return strings.ReplaceAll(url, LinePlaceholder, strconv.Itoa(line))
+}
+
+// Pos returns the URL of a position, or "" when it is invalid.
+func (l Linker) Pos(pos token.Pos) string {
+	if l.template == "" || l.fset == nil || !pos.IsValid() {
+		return ""
+	}
+	position := l.fset.Position(pos)
+	return l.URL(position.Filename, position.Line)
+}
+
+// RepoRoot returns the root of the git work tree containing dir, read with
+// `git rev-parse --show-toplevel`, or dir itself when it is not in one or git is not
+// installed.
+func RepoRoot(ctx context.Context, dir string) string {
+	cmd := exec.CommandContext(ctx, "git", "-C", dir, "rev-parse", "--show-toplevel")
+	out, err := cmd.Output()
+	if err != nil {
+		return dir
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// HeadCommit returns the commit checked out in the git work tree containing dir, or "" when
+// it is not in one or git is not installed.
+func HeadCommit(ctx context.Context, dir string) string {
+	out, err := exec.CommandContext(ctx, "git", "-C", dir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
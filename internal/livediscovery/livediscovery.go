@@ -0,0 +1,237 @@
+// Package livediscovery cross-checks static resource discovery against the resource
+// names a compiled provider actually registers.
+//
+// Live discovery is opt-in and gated behind the TFPROVIDERTEST_LIVE_DISCOVERY
+// environment variable because it compiles the provider and executes Terraform.
+// The provider binary is exposed to Terraform through a dev_overrides CLI
+// configuration inside a temporary directory, so the user's Terraform configuration,
+// plugin cache, and working directory are never touched.
+package livediscovery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+const (
+	// EnvEnable enables live discovery when set to "1" or "true".
+	EnvEnable = "TFPROVIDERTEST_LIVE_DISCOVERY"
+	// EnvProviderAddress overrides the provider source address used for the schema run
+	// (e.g., "registry.terraform.io/hashicorp/aws").
+	EnvProviderAddress = "TFPROVIDERTEST_LIVE_PROVIDER_ADDRESS"
+	// EnvTerraformPath overrides the terraform binary used for the schema run.
+	EnvTerraformPath = "TFPROVIDERTEST_TERRAFORM_PATH"
+
+	defaultTimeout = 10 * time.Minute
+)
+
+// Enabled reports whether live discovery has been requested via the environment.
+func Enabled() bool {
+	switch strings.ToLower(os.Getenv(EnvEnable)) {
+	case "1", "true", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
+// Options configures a live discovery run.
+type Options struct {
+	// ProviderDir is the provider module root containing the main package.
+	ProviderDir string
+	// ProviderAddress is the provider source address. Derived from ProviderDir when empty.
+	ProviderAddress string
+	// TerraformPath is the terraform binary to run. Defaults to "terraform" on PATH.
+	TerraformPath string
+	// Timeout bounds the build and schema steps together. Defaults to 10 minutes.
+	Timeout time.Duration
+}
+
+// OptionsFromEnv returns Options for providerDir with overrides taken from the environment.
+func OptionsFromEnv(providerDir string) Options {
+	return Options{
+		ProviderDir:     providerDir,
+		ProviderAddress: os.Getenv(EnvProviderAddress),
+		TerraformPath:   os.Getenv(EnvTerraformPath),
+	}
+}
+
+// Schemas holds the type names a provider registers, as reported by Terraform.
+type Schemas struct {
+	ProviderAddress string
+	Resources       []string
+	DataSources     []string
+	Actions         []string
+}
+
+// Result compares live schemas with statically discovered definitions.
+type Result struct {
+	ProviderAddress string   `json:"provider_address"`
+	LiveTotal       int      `json:"live_total"`
+	Matched         int      `json:"matched"`
+	Confidence      float64  `json:"confidence"`
+	MissingInStatic []string `json:"missing_in_static,omitempty"`
+	UnknownToLive   []string `json:"unknown_to_live,omitempty"`
+}
+
+// DefaultProviderAddress derives a registry address from a terraform-provider-<name> directory.
+func DefaultProviderAddress(providerDir string) string {
+	name := strings.TrimPrefix(filepath.Base(filepath.Clean(providerDir)), "terraform-provider-")
+	return "registry.terraform.io/hashicorp/" + name
+}
+
+// providerTypeName returns the last segment of a provider source address (e.g., "aws").
+func providerTypeName(address string) string {
+	if idx := strings.LastIndex(address, "/"); idx != -1 {
+		return address[idx+1:]
+	}
+	return address
+}
+
+// Discover builds the provider and asks Terraform for its schema.
+func Discover(ctx context.Context, opts Options) (*Schemas, error) {
+	if opts.ProviderAddress == "" {
+		opts.ProviderAddress = DefaultProviderAddress(opts.ProviderDir)
+	}
+	if opts.TerraformPath == "" {
+		opts.TerraformPath = "terraform"
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	workDir, err := os.MkdirTemp("", "tfprovidertest-live-")
+	if err != nil {
+		return nil, fmt.Errorf("creating work directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	typeName := providerTypeName(opts.ProviderAddress)
+	binDir := filepath.Join(workDir, "bin")
+	binary := filepath.Join(binDir, "terraform-provider-"+typeName)
+
+	build := exec.CommandContext(ctx, "go", "build", "-o", binary, ".")
+	build.Dir = opts.ProviderDir
+	if out, err := build.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("building provider: %w\n%s", err, out)
+	}
+
+	cliConfig := filepath.Join(workDir, "terraform.rc")
+	cliConfigBody := fmt.Sprintf("provider_installation {\n  dev_overrides {\n    %q = %q\n  }\n  direct {}\n}\n",
+		opts.ProviderAddress, binDir)
+	if err := os.WriteFile(cliConfig, []byte(cliConfigBody), 0o600); err != nil {
+		return nil, fmt.Errorf("writing CLI config: %w", err)
+	}
+
+	configDir := filepath.Join(workDir, "config")
+	if err := os.MkdirAll(configDir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating config directory: %w", err)
+	}
+	mainTF := fmt.Sprintf("terraform {\n  required_providers {\n    %s = {\n      source = %q\n    }\n  }\n}\n",
+		typeName, opts.ProviderAddress)
+	if err := os.WriteFile(filepath.Join(configDir, "main.tf"), []byte(mainTF), 0o600); err != nil {
+		return nil, fmt.Errorf("writing configuration: %w", err)
+	}
+
+	schemaCmd := exec.CommandContext(ctx, opts.TerraformPath, "providers", "schema", "-json")
+	schemaCmd.Dir = configDir
+	schemaCmd.Env = append(os.Environ(),
+		"TF_CLI_CONFIG_FILE="+cliConfig,
+		"TF_DATA_DIR="+filepath.Join(workDir, ".terraform"),
+		"TF_IN_AUTOMATION=1",
+		"TF_PLUGIN_CACHE_DIR=",
+		"CHECKPOINT_DISABLE=1",
+	)
+	var stdout, stderr bytes.Buffer
+	schemaCmd.Stdout = &stdout
+	schemaCmd.Stderr = &stderr
+	if err := schemaCmd.Run(); err != nil {
+		return nil, fmt.Errorf("running terraform providers schema: %w\n%s", err, stderr.String())
+	}
+
+	return ParseSchemaJSON(stdout.Bytes(), opts.ProviderAddress)
+}
+
+// schemaDocument mirrors the parts of `terraform providers schema -json` output we need.
+type schemaDocument struct {
+	ProviderSchemas map[string]struct {
+		ResourceSchemas   map[string]json.RawMessage `json:"resource_schemas"`
+		DataSourceSchemas map[string]json.RawMessage `json:"data_source_schemas"`
+		ActionSchemas     map[string]json.RawMessage `json:"action_schemas"`
+	} `json:"provider_schemas"`
+}
+
+// ParseSchemaJSON extracts the type names for address from `terraform providers schema -json` output.
+func ParseSchemaJSON(data []byte, address string) (*Schemas, error) {
+	var doc schemaDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing provider schema: %w", err)
+	}
+
+	provider, ok := doc.ProviderSchemas[address]
+	if !ok {
+		return nil, fmt.Errorf("provider %q not found in schema output", address)
+	}
+
+	return &Schemas{
+		ProviderAddress: address,
+		Resources:       slices.Sorted(maps.Keys(provider.ResourceSchemas)),
+		DataSources:     slices.Sorted(maps.Keys(provider.DataSourceSchemas)),
+		Actions:         slices.Sorted(maps.Keys(provider.ActionSchemas)),
+	}, nil
+}
+
+// Compare scores how completely static discovery found the live type names.
+// Static names may be registered with or without the provider prefix, so both forms match.
+// Confidence is the fraction of live type names that static discovery found.
+func Compare(live *Schemas, reg *registry.ResourceRegistry) Result {
+	result := Result{ProviderAddress: live.ProviderAddress}
+	prefix := providerTypeName(live.ProviderAddress) + "_"
+
+	matchedStatic := make(map[registry.ResourceKey]bool)
+	check := func(kind registry.ResourceKind, names []string) {
+		for _, name := range names {
+			result.LiveTotal++
+			short := strings.TrimPrefix(name, prefix)
+			switch {
+			case reg.GetDefinition(kind, name) != nil:
+				matchedStatic[registry.ResourceKey{Kind: kind, Name: name}] = true
+			case reg.GetDefinition(kind, short) != nil:
+				matchedStatic[registry.ResourceKey{Kind: kind, Name: short}] = true
+			default:
+				result.MissingInStatic = append(result.MissingInStatic, registry.ResourceKey{Kind: kind, Name: name}.String())
+				continue
+			}
+			result.Matched++
+		}
+	}
+	check(registry.KindResource, live.Resources)
+	check(registry.KindDataSource, live.DataSources)
+	check(registry.KindAction, live.Actions)
+
+	for _, info := range reg.GetAllDefinitions() {
+		if !matchedStatic[info.Key()] {
+			result.UnknownToLive = append(result.UnknownToLive, info.Key().String())
+		}
+	}
+	sort.Strings(result.UnknownToLive)
+
+	if result.LiveTotal > 0 {
+		result.Confidence = float64(result.Matched) / float64(result.LiveTotal)
+	}
+	return result
+}
@@ -0,0 +1,185 @@
+package matching
+
+import (
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// charBins is the number of character classes counted per name: letters, digits, the
+// underscore and everything else.
+const charBins = 38
+
+// charCounts counts the characters of a name by class. Characters sharing the last class
+// only weaken the distance bound, so it stays a lower bound.
+type charCounts [charBins]uint16
+
+func countChars(s string) charCounts {
+	var counts charCounts
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c >= 'a' && c <= 'z':
+			counts[c-'a']++
+		case c >= '0' && c <= '9':
+			counts[26+c-'0']++
+		case c == '_':
+			counts[36]++
+		default:
+			counts[37]++
+		}
+	}
+	return counts
+}
+
+// distanceBound returns a lower bound of the edit distance between two names from their
+// character counts: every edit removes at most one surplus character from each side.
+func distanceBound(a, b *charCounts) int {
+	surplusA, surplusB := 0, 0
+	for i := range a {
+		if d := int(a[i]) - int(b[i]); d > 0 {
+			surplusA += d
+		} else {
+			surplusB -= d
+		}
+	}
+	return max(surplusA, surplusB)
+}
+
+// indexedName is a definition name with its character counts.
+type indexedName struct {
+	name   string
+	counts charCounts
+}
+
+// nameIndex buckets the simple names of the definitions by length, so that fuzzy ranking
+// only computes edit distances for names that can reach the minimum similarity.
+type nameIndex struct {
+	byLength [][]indexedName
+}
+
+func newNameIndex(names map[string]bool) *nameIndex {
+	idx := &nameIndex{}
+	for name := range names {
+		for len(idx.byLength) <= len(name) {
+			idx.byLength = append(idx.byLength, nil)
+		}
+		idx.byLength[len(name)] = append(idx.byLength[len(name)], indexedName{name: name, counts: countChars(name)})
+	}
+	return idx
+}
+
+// rank returns the definitions whose names have a similarity (see CalculateSimilarity) of at
+// least minimum to name, from the most similar, then by name.
+func (idx *nameIndex) rank(name string, minimum float64) []ResourceMatch {
+	var matches []ResourceMatch
+	if name == "" {
+		return matches
+	}
+	counts := countChars(name)
+	for length, bucket := range idx.byLength {
+		longest := max(length, len(name))
+		// The largest distance that still reaches minimum; the slack absorbs rounding
+		limit := int(math.Floor((1-minimum)*float64(longest) + 1e-9))
+		if abs(length-len(name)) > limit {
+			continue
+		}
+		for i := range bucket {
+			candidate := &bucket[i]
+			if distanceBound(&counts, &candidate.counts) > limit {
+				continue
+			}
+			distance, ok := distanceWithin(name, candidate.name, limit)
+			if !ok {
+				continue
+			}
+			confidence := 1.0
+			if longest > 0 {
+				confidence = 1.0 - float64(distance)/float64(longest)
+			}
+			if confidence >= minimum {
+				matches = append(matches, ResourceMatch{
+					ResourceName: candidate.name,
+					Confidence:   confidence,
+					MatchType:    registry.MatchTypeFuzzy,
+					Strategy:     StrategyFuzzy,
+				})
+			}
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Confidence != matches[j].Confidence {
+			return matches[i].Confidence > matches[j].Confidence
+		}
+		return matches[i].ResourceName < matches[j].ResourceName
+	})
+	return matches
+}
+
+// distanceWithin returns the Levenshtein distance between a and b when it is at most limit,
+// stopping as soon as every path exceeds it.
+func distanceWithin(a, b string, limit int) (int, bool) {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > limit {
+			return 0, false
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)], prev[len(b)] <= limit
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// minParallelJobs is the number of jobs below which forEachParallel runs them in the calling
+// goroutine, where starting workers costs more than it saves.
+const minParallelJobs = 64
+
+// forEachParallel calls fn for every index below n from a pool of up to GOMAXPROCS workers.
+// fn must only write to state owned by its index.
+func forEachParallel(n int, fn func(i int)) {
+	workers := min(runtime.GOMAXPROCS(0), n)
+	if n < minParallelJobs || workers <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
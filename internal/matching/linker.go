@@ -2,10 +2,12 @@
 package matching
 
 import (
+	"fmt"
 	"path/filepath"
 	"reflect"
 	"strings"
 
+	"github.com/example/tfprovidertest/internal/naming"
 	"github.com/example/tfprovidertest/internal/registry"
 )
 
@@ -18,6 +20,7 @@ import (
 type Linker struct {
 	registry *registry.ResourceRegistry
 	settings interface{} // Settings - using interface{} to avoid circular imports during migration
+	names    *naming.Pipeline
 }
 
 // NewLinker creates a new Linker instance.
@@ -25,249 +28,369 @@ func NewLinker(registry *registry.ResourceRegistry, settings interface{}) *Linke
 	return &Linker{
 		registry: registry,
 		settings: settings,
+		names:    namePipeline(settings),
 	}
 }
 
+// namePipeline returns the naming pipeline of settings that provide one, such as
+// *config.Settings, and the default pipeline otherwise. Invalid name settings fall back to
+// the default too; Settings.Validate reports them.
+func namePipeline(settings interface{}) *naming.Pipeline {
+	type settingsWithNames interface {
+		NamePipeline() (*naming.Pipeline, error)
+	}
+	if s, ok := settings.(settingsWithNames); ok {
+		if p, err := s.NamePipeline(); err == nil {
+			return p
+		}
+	}
+	return naming.Default()
+}
+
 // ResourceMatch represents a potential resource match for a test function.
+// Kind is only meaningful when KindKnown is true; otherwise the registry resolves
+// the kind from ResourceName when the test is linked.
 type ResourceMatch struct {
 	ResourceName string
+	Kind         registry.ResourceKind
+	KindKnown    bool
 	Confidence   float64
 	MatchType    registry.MatchType
+	Strategy     string // Strategy names the strategy that produced the match, e.g. StrategyFile
 }
 
 // LinkTestsToResources iterates over all test functions and associates them with resources.
-// It uses multiple strategies in order of confidence to find the best match.
-// Priority order (highest to lowest):
-// 1. Inferred Content - based on actual HCL parsing of Config strings (most reliable)
-// 2. Function name extraction - based on test function naming conventions
-// 3. File proximity - based on test file naming conventions
-// 4. Fuzzy matching - optional, disabled by default
+// Every strategy is evaluated for each test, and the match of the highest-priority strategy
+// that found one is linked. Priority order (highest to lowest):
+// 1. Function name extraction - based on test function naming conventions, validated by
+// the resources inferred from Config strings when possible
+// 2. Typed HCL blocks - based on actual HCL parsing of Config strings
+// 3. Inferred content - the resource names collected from Configs and their helpers
+// 4. File proximity - based on test file naming conventions
+// 5. Fuzzy matching - optional, disabled by default, and only tried when nothing else matched
+//
+// Each strategy's match, chosen or not, is recorded as a candidate in the registry
+// (see ResourceRegistry.GetMatchCandidates), together with fuzzy near misses for tests
+// left unlinked, so users can see which links a configuration change would accept.
+//
+// The exact strategies are lookups in the registry. Fuzzy ranking is the only comparison
+// against every definition, so it runs once per name extracted from the tests left over,
+// over names indexed by length and characters, on a pool of workers. Tests are linked in
+// registry order afterwards, so the result does not depend on scheduling.
 func (l *Linker) LinkTestsToResources() {
-	// Get all definitions and test functions
+	l.linkTests(l.GetAllTestFunctions())
+}
+
+// RelinkFile replaces the test functions registered for the test file at filePath with
+// tests, parsed from its current content, and links and classifies them as
+// LinkTestsToResources and ClassifyAllTests do. Links of the file's previous tests are
+// dropped rather than kept, so a test whose name or configs no longer point at a
+// definition is left unlinked, or linked to the definition they point at now. It returns
+// the tests that were replaced.
+func (l *Linker) RelinkFile(filePath string, tests []*registry.TestFunctionInfo) []*registry.TestFunctionInfo {
+	replaced := l.registry.UnlinkTestsForFile(filePath)
+	for _, fn := range tests {
+		l.registry.RegisterTestFunction(fn)
+	}
+	l.linkTests(tests)
+	for _, fn := range tests {
+		fn.Category = ClassifyTest(fn)
+	}
+	return replaced
+}
+
+// linkTests links allTests as LinkTestsToResources describes.
+func (l *Linker) linkTests(allTests []*registry.TestFunctionInfo) {
+	// Get all definitions
 	allDefinitions := l.GetAllDefinitions()
-	allTests := l.GetAllTestFunctions()
 
 	// Build simple name map for quick lookup: "widget" -> true
 	simpleNames := make(map[string]bool)
-	for key := range allDefinitions {
-		// Extract the simple name from compound keys like "resource:widget"
-		if idx := strings.LastIndex(key, ":"); idx != -1 {
-			simpleNames[key[idx+1:]] = true
+	for _, info := range allDefinitions {
+		simpleNames[info.Name] = true
+	}
+	fuzzyEnabled := l.isFuzzyMatchingEnabled()
+
+	// Evaluate the exact strategies for each test function
+	considered := make([][]*ResourceMatch, len(allTests))
+	forEachParallel(len(allTests), func(i int) {
+		fn := allTests[i]
+		for _, match := range []*ResourceMatch{
+			l.matchByFunctionName(fn, simpleNames),
+			l.matchByHCLBlocks(fn),
+			l.matchByInferredResources(fn, simpleNames),
+			l.matchByFile(fn, simpleNames),
+		} {
+			if match != nil {
+				considered[i] = append(considered[i], match)
+			}
+		}
+	})
+
+	// Fuzzy matching (low confidence, optional). Near misses are ranked even when it is
+	// disabled, but only for tests no other strategy matched, once per extracted name.
+	extracted := make(map[int]string)
+	var fuzzyNames []string
+	fuzzyRanks := make(map[string]int)
+	for i, fn := range allTests {
+		if len(considered[i]) > 0 {
+			continue
+		}
+		name, _ := ExtractResourceFromFuncNameWith(l.names, fn.Name)
+		if name == "" {
+			continue
+		}
+		extracted[i] = name
+		if _, ok := fuzzyRanks[name]; !ok {
+			fuzzyRanks[name] = len(fuzzyNames)
+			fuzzyNames = append(fuzzyNames, name)
 		}
 	}
+	index := newNameIndex(simpleNames)
+	ranked := make([][]ResourceMatch, len(fuzzyNames))
+	forEachParallel(len(fuzzyNames), func(i int) {
+		ranked[i] = index.rank(fuzzyNames[i], FuzzyNearMissThreshold)
+		if len(ranked[i]) > maxFuzzyCandidates {
+			ranked[i] = ranked[i][:maxFuzzyCandidates]
+		}
+	})
 
 	// Process each test function
-	for _, fn := range allTests {
+	for i, fn := range allTests {
 		var bestMatch *ResourceMatch
-		matchFound := false
-
-		// Build a set of inferred resources for quick lookup
-		inferredSet := make(map[string]bool)
-		for _, name := range fn.InferredResources {
-			inferredSet[name] = true
-			// Also add stripped version
-			if idx := strings.Index(name, "_"); idx != -1 {
-				inferredSet[name[idx+1:]] = true
-			}
-		}
-
-		// Strategy 1: Function name extraction validated by InferredContent (HIGHEST confidence)
-		// Combines the reliability of HCL parsing with the intent clarity of function naming
-		// This solves the problem of tests that use multiple resources (e.g., group test uses inventory as dependency)
-		if resourceName, found := matchResourceByName(fn.Name, simpleNames); found {
-			// Determine preferred kind from function name pattern
-			// TestAccInventoryDataSource -> prefer data source
-			// TestAccGroupResource -> prefer resource
-			preferDataSource := strings.Contains(fn.Name, "DataSource")
-
-			// If function name indicates DataSource and there's a data source with this name,
-			// directly link to the data source using compound key
-			if preferDataSource {
-				dataSourceKey := "data source:" + resourceName
-				if _, exists := allDefinitions[dataSourceKey]; exists {
-					fn.MatchType = registry.MatchTypeFunctionName
-					l.registry.LinkTestToResource(dataSourceKey, fn)
-					continue // Skip to next test function
+		if len(considered[i]) > 0 {
+			bestMatch = considered[i][0]
+		} else if name, ok := extracted[i]; ok {
+			fuzzy := ranked[fuzzyRanks[name]]
+			for j := range fuzzy {
+				if fuzzyEnabled && bestMatch == nil && fuzzy[j].Confidence >= FuzzyLinkThreshold {
+					bestMatch = &fuzzy[j]
 				}
-			}
-
-			// If we also have inferred resources, validate that this resource is in the config
-			if len(fn.InferredResources) > 0 {
-				// Check if function-derived resource is in inferred list
-				if inferredSet[resourceName] {
-					// Function name matches an inferred resource - highest confidence
-					bestMatch = &ResourceMatch{
-						ResourceName: resourceName,
-						Confidence:   1.0,
-						MatchType:    registry.MatchTypeInferred, // Use Inferred type since it's validated
-					}
-					matchFound = true
-				}
-			}
-			// If no inferred resources or not in inferred set, still use function name match
-			if !matchFound {
-				bestMatch = &ResourceMatch{
-					ResourceName: resourceName,
-					Confidence:   0.95,
-					MatchType:    registry.MatchTypeFunctionName,
-				}
-				matchFound = true
+				considered[i] = append(considered[i], &fuzzy[j])
 			}
 		}
+		l.recordCandidates(fn, considered[i], bestMatch, fuzzyEnabled)
 
-		// Strategy 2: Typed HCL Block Matching (exact matching using parsed block types)
-		// Uses InferredHCLBlocks which contain both block type (resource/data/action) and resource type
-		// This gives us exact matches without guessing based on function name hints
-		if !matchFound && len(fn.InferredHCLBlocks) > 0 {
-			// Map HCL block types to registry key prefixes
-			blockTypeToPrefix := map[string]string{
-				"resource": "resource:",
-				"data":     "data source:",
-				"action":   "action:",
-			}
+		// Link the test to its matched resource
+		if bestMatch != nil {
+			fn.MatchType = bestMatch.MatchType
+			fn.MatchConfidence = bestMatch.Confidence
+			fn.MatchedResource = bestMatch.ResourceName
+			l.linkMatch(bestMatch, fn)
+		}
+	}
+}
 
-			// Priority order: actions (most specific) > resources > data sources (often dependencies)
-			priorityOrder := []string{"action", "resource", "data"}
+// Strategy names recorded on match candidates.
+const (
+	StrategyFunctionName = "function-name"
+	StrategyHCLBlock     = "hcl-block"
+	StrategyInferred     = "inferred-content"
+	StrategyFile         = "file-proximity"
+	StrategyFuzzy        = "fuzzy"
+)
 
-			for _, blockType := range priorityOrder {
-				if matchFound {
-					break
-				}
-				prefix := blockTypeToPrefix[blockType]
-				for _, block := range fn.InferredHCLBlocks {
-					if block.BlockType != blockType {
-						continue
-					}
-					// Try exact match
-					key := prefix + block.ResourceType
-					if _, exists := allDefinitions[key]; exists {
-						bestMatch = &ResourceMatch{
-							ResourceName: block.ResourceType,
-							Confidence:   1.0, // Exact match from HCL
-							MatchType:    registry.MatchTypeInferred,
-						}
-						matchFound = true
-						break
-					}
-					// Try stripping provider prefix
-					if idx := strings.Index(block.ResourceType, "_"); idx != -1 {
-						shortName := block.ResourceType[idx+1:]
-						key = prefix + shortName
-						if _, exists := allDefinitions[key]; exists {
-							bestMatch = &ResourceMatch{
-								ResourceName: shortName,
-								Confidence:   1.0, // Exact match from HCL
-								MatchType:    registry.MatchTypeInferred,
-							}
-							matchFound = true
-							break
-						}
-					}
-				}
-			}
+// FuzzyNearMissThreshold is the minimum name similarity for a fuzzy match to be recorded as a
+// candidate of a test no strategy linked.
+const FuzzyNearMissThreshold = 0.5
+
+// maxFuzzyCandidates bounds the fuzzy candidates recorded per test.
+const maxFuzzyCandidates = 3
+
+// matchByFunctionName extracts the resource name from the test function name (Strategy 1).
+// The match is validated by the resources inferred from the test's Configs when it is among
+// them, which solves the problem of tests that use multiple resources (e.g., a group test
+// that uses inventory as a dependency).
+func (l *Linker) matchByFunctionName(fn *registry.TestFunctionInfo, simpleNames map[string]bool) *ResourceMatch {
+	resourceName, found := matchResourceByNameWithKeywords(l.names, fn.Name, simpleNames, DefaultFunctionNameKeywordsToStrip())
+	if !found {
+		return nil
+	}
+
+	// If the function name indicates a data source (TestAccInventoryDataSource) and there is
+	// a data source with this name, link to the data source directly
+	if strings.Contains(fn.Name, "DataSource") && l.registry.GetDefinition(registry.KindDataSource, resourceName) != nil {
+		return &ResourceMatch{
+			ResourceName: resourceName,
+			Kind:         registry.KindDataSource,
+			KindKnown:    true,
+			Confidence:   0.95,
+			MatchType:    registry.MatchTypeFunctionName,
+			Strategy:     StrategyFunctionName,
 		}
+	}
 
-		// Strategy 3: Legacy Inferred Content Matching (fallback for helper functions without direct HCL)
-		if !matchFound && len(fn.InferredResources) > 0 {
-			// Helper to match against a specific kind
-			matchKind := func(kindPrefix string) bool {
-				for _, inferredName := range fn.InferredResources {
-					// First try the full name (e.g., "google_bigquery_table")
-					// This matches resources registered with full names from provider registry maps
-					key := kindPrefix + inferredName
-					if _, exists := allDefinitions[key]; exists {
-						bestMatch = &ResourceMatch{
-							ResourceName: inferredName,
-							Confidence:   0.85,
-							MatchType:    registry.MatchTypeInferred,
-						}
-						return true
-					}
-					// Try stripping provider prefix (e.g., google_bigquery_table -> bigquery_table)
-					if idx := strings.Index(inferredName, "_"); idx != -1 {
-						shortName := inferredName[idx+1:]
-						key = kindPrefix + shortName
-						if _, exists := allDefinitions[key]; exists {
-							bestMatch = &ResourceMatch{
-								ResourceName: shortName,
-								Confidence:   0.85,
-								MatchType:    registry.MatchTypeInferred,
-							}
-							return true
-						}
-					}
+	// Check if the function-derived resource is in the inferred list
+	for _, inferredName := range fn.InferredResources {
+		for _, name := range withoutProviderPrefix(inferredName) {
+			if name == resourceName {
+				// Function name matches an inferred resource - highest confidence
+				return &ResourceMatch{
+					ResourceName: resourceName,
+					Confidence:   1.0,
+					MatchType:    registry.MatchTypeInferred, // Use Inferred type since it's validated
+					Strategy:     StrategyFunctionName,
 				}
-				return false
 			}
+		}
+	}
+	return &ResourceMatch{
+		ResourceName: resourceName,
+		Confidence:   0.95,
+		MatchType:    registry.MatchTypeFunctionName,
+		Strategy:     StrategyFunctionName,
+	}
+}
 
-			// Standard priority order: resources > actions > data sources
-			if !matchFound {
-				matchFound = matchKind("resource:")
+// matchByHCLBlocks matches the typed HCL blocks parsed from the test's Configs (Strategy 2).
+// InferredHCLBlocks contain both the block type (resource/data/action) and the resource type,
+// which gives exact matches without guessing based on function name hints.
+func (l *Linker) matchByHCLBlocks(fn *registry.TestFunctionInfo) *ResourceMatch {
+	// Priority order: actions (most specific) > resources > data sources (often dependencies)
+	for _, blockType := range []string{"action", "resource", "data"} {
+		kind, _ := registry.KindFromBlockType(blockType)
+		for _, block := range fn.InferredHCLBlocks {
+			if block.BlockType != blockType {
+				continue
 			}
-			if !matchFound {
-				matchFound = matchKind("action:")
+			// Try the exact name, then the name without the provider prefix
+			names := []string{block.ResourceType}
+			if idx := strings.Index(block.ResourceType, "_"); idx != -1 {
+				names = append(names, block.ResourceType[idx+1:])
 			}
-			if !matchFound {
-				matchFound = matchKind("data source:")
+			for _, name := range names {
+				if l.registry.GetDefinition(kind, name) != nil {
+					return &ResourceMatch{
+						ResourceName: name,
+						Kind:         kind,
+						KindKnown:    true,
+						Confidence:   1.0, // Exact match from HCL
+						MatchType:    registry.MatchTypeInferred,
+						Strategy:     StrategyHCLBlock,
+					}
+				}
 			}
+		}
+	}
+	return nil
+}
 
-			// Fallback: simple name matching (any kind)
-			if !matchFound {
-				for _, inferredName := range fn.InferredResources {
-					if simpleNames[inferredName] {
-						bestMatch = &ResourceMatch{
-							ResourceName: inferredName,
-							Confidence:   0.9,
-							MatchType:    registry.MatchTypeInferred,
-						}
-						matchFound = true
-						break
-					}
-					if idx := strings.Index(inferredName, "_"); idx != -1 {
-						shortName := inferredName[idx+1:]
-						if simpleNames[shortName] {
-							bestMatch = &ResourceMatch{
-								ResourceName: shortName,
-								Confidence:   0.9,
-								MatchType:    registry.MatchTypeInferred,
-							}
-							matchFound = true
-							break
-						}
+// matchByInferredResources matches the resource names inferred from the test's Configs
+// (Strategy 3), the fallback for helper functions without direct HCL.
+func (l *Linker) matchByInferredResources(fn *registry.TestFunctionInfo, simpleNames map[string]bool) *ResourceMatch {
+	if len(fn.InferredResources) == 0 {
+		return nil
+	}
+
+	// Standard priority order: resources > actions > data sources. The full name (e.g.,
+	// "google_bigquery_table") matches resources registered with full names from provider
+	// registry maps; the name without the provider prefix (bigquery_table) is tried next.
+	for _, kind := range []registry.ResourceKind{registry.KindResource, registry.KindAction, registry.KindDataSource} {
+		for _, inferredName := range fn.InferredResources {
+			for _, name := range withoutProviderPrefix(inferredName) {
+				if l.registry.GetDefinition(kind, name) != nil {
+					return &ResourceMatch{
+						ResourceName: name,
+						Kind:         kind,
+						KindKnown:    true,
+						Confidence:   0.85,
+						MatchType:    registry.MatchTypeInferred,
+						Strategy:     StrategyInferred,
 					}
 				}
 			}
 		}
+	}
 
-		// Strategy 3: File proximity (medium confidence)
-		// File names like widget_resource_test.go indicate the target resource
-		if !matchFound {
-			if resourceName := l.MatchByFileProximity(fn.FilePath, simpleNames); resourceName != "" {
-				bestMatch = &ResourceMatch{
-					ResourceName: resourceName,
+	// Fallback: simple name matching (any kind)
+	for _, inferredName := range fn.InferredResources {
+		for _, name := range withoutProviderPrefix(inferredName) {
+			if simpleNames[name] {
+				return &ResourceMatch{
+					ResourceName: name,
 					Confidence:   0.9,
-					MatchType:    registry.MatchTypeFileProximity,
+					MatchType:    registry.MatchTypeInferred,
+					Strategy:     StrategyInferred,
 				}
-				matchFound = true
 			}
 		}
+	}
+	return nil
+}
 
-		// Strategy 4: Fuzzy matching (low confidence, optional)
-		if !matchFound && l.isFuzzyMatchingEnabled() {
-			matches := l.findFuzzyMatches(fn.Name, simpleNames)
-			if len(matches) > 0 {
-				bestMatch = &matches[0]
-				matchFound = true
-			}
-		}
+// withoutProviderPrefix returns name followed by name without its first underscore-separated
+// segment, when it has one.
+func withoutProviderPrefix(name string) []string {
+	if idx := strings.Index(name, "_"); idx != -1 {
+		return []string{name, name[idx+1:]}
+	}
+	return []string{name}
+}
 
-		// Link the test to its matched resource
-		if matchFound && bestMatch != nil {
-			fn.MatchType = bestMatch.MatchType
-			fn.MatchConfidence = bestMatch.Confidence
-			l.LinkTestToResource(bestMatch.ResourceName, fn)
+// matchByFile matches file names like widget_resource_test.go, which indicate the target
+// resource (Strategy 4).
+func (l *Linker) matchByFile(fn *registry.TestFunctionInfo, simpleNames map[string]bool) *ResourceMatch {
+	match := l.MatchByFileProximity(fn.FilePath, simpleNames)
+	if match == "" {
+		return nil
+	}
+	result := &ResourceMatch{
+		ResourceName: match,
+		Confidence:   0.9,
+		MatchType:    registry.MatchTypeFileProximity,
+		Strategy:     StrategyFile,
+	}
+	if key, ok := registry.ParseResourceKey(match); ok {
+		result.ResourceName = key.Name
+		result.Kind = key.Kind
+		result.KindKnown = true
+	}
+	return result
+}
+
+// recordCandidates records the matches considered for a test in the registry, explaining why
+// each one other than best was not used.
+func (l *Linker) recordCandidates(fn *registry.TestFunctionInfo, considered []*ResourceMatch, best *ResourceMatch, fuzzyEnabled bool) {
+	var bestKey registry.ResourceKey
+	if best != nil {
+		bestKey, _ = l.resolveMatchKey(best)
+	}
+	candidates := make([]registry.MatchCandidate, 0, len(considered))
+	for _, match := range considered {
+		key, ok := l.resolveMatchKey(match)
+		if !ok {
+			continue
 		}
+		candidate := registry.MatchCandidate{
+			Key:        key,
+			Confidence: match.Confidence,
+			MatchType:  match.MatchType,
+			Strategy:   match.Strategy,
+			Accepted:   match == best,
+		}
+		switch {
+		case candidate.Accepted:
+		case best != nil && key == bestKey:
+			candidate.Note = "agrees with the " + best.Strategy + " match"
+		case best != nil:
+			candidate.Note = "outranked by the " + best.Strategy + " match"
+		case match.Confidence < FuzzyLinkThreshold:
+			candidate.Note = fmt.Sprintf("similarity %.2f is below the fuzzy link threshold %.2f", match.Confidence, FuzzyLinkThreshold)
+		case !fuzzyEnabled:
+			candidate.Note = "fuzzy matching is disabled (enable-fuzzy-matching)"
+		}
+		candidates = append(candidates, candidate)
+	}
+	l.registry.SetMatchCandidates(fn, candidates)
+}
+
+// resolveMatchKey returns the definition a match refers to, resolving the kind from the
+// registry when the strategy only produced a simple name.
+func (l *Linker) resolveMatchKey(match *ResourceMatch) (registry.ResourceKey, bool) {
+	if match.KindKnown {
+		return registry.ResourceKey{Kind: match.Kind, Name: match.ResourceName}, true
 	}
+	return l.registry.ResolveKey(match.ResourceName)
 }
 
 // isFuzzyMatchingEnabled checks if fuzzy matching is enabled in settings
@@ -318,10 +441,22 @@ func (l *Linker) GetAllTestFunctions() []*registry.TestFunctionInfo {
 }
 
 // LinkTestToResource links a test to a resource in the registry
+//
+// Deprecated: Use the registry's LinkTest, which takes the kind explicitly.
 func (l *Linker) LinkTestToResource(key string, fn *registry.TestFunctionInfo) {
 	l.registry.LinkTestToResource(key, fn)
 }
 
+// linkMatch links a test to the definition chosen by a ResourceMatch, resolving
+// the kind from the registry when the strategy only produced a simple name, and records
+// the definition's key on the test.
+func (l *Linker) linkMatch(match *ResourceMatch, fn *registry.TestFunctionInfo) {
+	if key, ok := l.resolveMatchKey(match); ok {
+		fn.MatchedKey = key
+		l.registry.LinkTest(key.Kind, key.Name, fn)
+	}
+}
+
 // findResourceMatches finds all matching resources for a test function.
 // It tries strategies in order of confidence and returns early on high-confidence matches.
 func (l *Linker) findResourceMatches(fn interface{}, resourceNames map[string]bool) []ResourceMatch {
@@ -378,13 +513,13 @@ func (l *Linker) MatchByFileProximity(testFilePath string, resourceNames map[str
 		// Note: ResourceKind.String() returns "data source" with a space
 		baseName := filepath.Base(testFilePath)
 		if isDataSource {
-			return "data source:" + resourceName
+			return registry.ResourceKey{Kind: registry.KindDataSource, Name: resourceName}.String()
 		}
 		// Check if file indicates an action
 		if strings.Contains(baseName, "_action") {
-			return "action:" + resourceName
+			return registry.ResourceKey{Kind: registry.KindAction, Name: resourceName}.String()
 		}
-		return "resource:" + resourceName
+		return registry.ResourceKey{Kind: registry.KindResource, Name: resourceName}.String()
 	}
 
 	// Also try the raw name without prefix/suffix as fallback (returns simple name)
@@ -399,30 +534,8 @@ func (l *Linker) MatchByFileProximity(testFilePath string, resourceNames map[str
 	return ""
 }
 
-// findFuzzyMatches finds resources with similar names using Levenshtein distance.
-func (l *Linker) findFuzzyMatches(funcName string, resourceNames map[string]bool) []ResourceMatch {
-	var matches []ResourceMatch
-
-	// Extract potential resource name from function
-	resourceFromFunc, _ := ExtractResourceFromFuncName(funcName)
-	if resourceFromFunc == "" {
-		return matches
-	}
-
-	for resourceName := range resourceNames {
-		confidence := CalculateSimilarity(resourceFromFunc, resourceName)
-		// TODO: Use settings.FuzzyMatchThreshold after fixing imports
-		if confidence >= 0.75 {
-			matches = append(matches, ResourceMatch{
-				ResourceName: resourceName,
-				Confidence:   confidence,
-				MatchType:    registry.MatchTypeFuzzy,
-			})
-		}
-	}
-
-	return matches
-}
+// FuzzyLinkThreshold is the minimum name similarity for the fuzzy strategy to link a test.
+const FuzzyLinkThreshold = 0.75
 
 // CalculateSimilarity calculates string similarity using normalized Levenshtein distance.
 // Returns a value between 0.0 (completely different) and 1.0 (identical).
@@ -520,23 +633,14 @@ func DefaultFunctionNameKeywordsToStrip() []string {
 //
 // Returns the matched resource name and whether a match was found.
 func matchResourceByName(funcName string, resourceNames map[string]bool) (string, bool) {
-	return matchResourceByNameWithKeywords(funcName, resourceNames, DefaultFunctionNameKeywordsToStrip())
+	return matchResourceByNameWithKeywords(nil, funcName, resourceNames, DefaultFunctionNameKeywordsToStrip())
 }
 
 // matchResourceByNameWithKeywords attempts to match a test function name to a resource name
-// using configurable keywords to strip from the function name.
-func matchResourceByNameWithKeywords(funcName string, resourceNames map[string]bool, keywordsToStrip []string) (string, bool) {
-	// Strip prefix
-	name := funcName
-	for _, prefix := range TestFunctionPrefixes {
-		if strings.HasPrefix(name, prefix) {
-			name = strings.TrimPrefix(name, prefix)
-			break
-		}
-	}
-
-	// If the name starts with an underscore after stripping prefix, skip it
-	name = strings.TrimPrefix(name, "_")
+// using a naming pipeline and configurable keywords to strip from the function name.
+func matchResourceByNameWithKeywords(p *naming.Pipeline, funcName string, resourceNames map[string]bool, keywordsToStrip []string) (string, bool) {
+	// Strip the test prefix, and an underscore that follows it
+	name := p.StripPrefix(funcName)
 
 	// Extract the resource part before any underscore suffix
 	// e.g., "AwsS3Bucket_basic" -> "AwsS3Bucket"
@@ -558,7 +662,7 @@ func matchResourceByNameWithKeywords(funcName string, resourceNames map[string]b
 	}
 
 	// Convert to snake_case
-	snakeName := toSnakeCase(resourcePart)
+	snakeName := p.SnakeCase(resourcePart)
 
 	// Check if it matches a registered resource
 	if resourceNames[snakeName] {
@@ -590,7 +694,7 @@ func matchResourceByNameWithKeywords(funcName string, resourceNames map[string]b
 
 		// If we modified the name, try matching again
 		if modifiedPart != resourcePart && modifiedPart != "" {
-			modifiedSnake := toSnakeCase(modifiedPart)
+			modifiedSnake := p.SnakeCase(modifiedPart)
 
 			// Check direct match
 			if resourceNames[modifiedSnake] {
@@ -697,6 +801,12 @@ func MatchResourceByName(funcName string, resourceNames map[string]bool) (string
 	return matchResourceByName(funcName, resourceNames)
 }
 
+// MatchResourceByNameWith is MatchResourceByName with a naming pipeline, such as one with
+// the replacements of Settings.
+func MatchResourceByNameWith(p *naming.Pipeline, funcName string, resourceNames map[string]bool) (string, bool) {
+	return matchResourceByNameWithKeywords(p, funcName, resourceNames, DefaultFunctionNameKeywordsToStrip())
+}
+
 // ClassifyTest determines the category of a test function based on its characteristics.
 // This helps separate provider tests, function tests, and resource tests.
 func ClassifyTest(fn *registry.TestFunctionInfo) registry.TestCategory {
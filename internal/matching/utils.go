@@ -7,20 +7,16 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
-	"unicode"
+
+	"github.com/example/tfprovidertest/internal/fileroles"
+	"github.com/example/tfprovidertest/internal/naming"
+	"github.com/example/tfprovidertest/internal/rules"
 )
 
 // TestFunctionPrefixes are the common prefixes used in test function names.
 // These are stripped when matching test functions to resources.
 // The order matters - more specific patterns should come first.
-var TestFunctionPrefixes = []string{
-	"TestAccDataSource",
-	"TestAccResource",
-	"TestAcc",
-	"TestDataSource",
-	"TestResource",
-	"Test",
-}
+var TestFunctionPrefixes = naming.DefaultPrefixes
 
 // TestFunctionSuffixes are the common suffixes used in test function names.
 // These are stripped when matching test functions to resources.
@@ -48,40 +44,7 @@ var TestFunctionSuffixes = []string{
 
 // toSnakeCase converts CamelCase to snake_case (e.g., "MyResource" -> "my_resource")
 func toSnakeCase(s string) string {
-	var result strings.Builder
-	runes := []rune(s)
-	for i, r := range runes {
-		if i > 0 && unicode.IsUpper(r) {
-			// Add underscore before uppercase if:
-			// 1. Previous char is lowercase, OR
-			// 2. Next char exists and is lowercase (handles acronyms like "HTTPServer" -> "http_server")
-			prev := runes[i-1]
-			if unicode.IsLower(prev) || (i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
-				result.WriteRune('_')
-			}
-		}
-		result.WriteRune(unicode.ToLower(r))
-	}
-	return result.String()
-}
-
-// toTitleCase converts snake_case to TitleCase (e.g., "my_resource" -> "MyResource")
-func toTitleCase(s string) string {
-	var result strings.Builder
-	capitalizeNext := true
-	for _, r := range s {
-		if r == '_' {
-			capitalizeNext = true
-			continue
-		}
-		if capitalizeNext {
-			result.WriteRune(unicode.ToUpper(r))
-			capitalizeNext = false
-		} else {
-			result.WriteRune(r)
-		}
-	}
-	return result.String()
+	return naming.Default().SnakeCase(s)
 }
 
 // testFuncPatterns matches various test function naming conventions.
@@ -131,6 +94,12 @@ var actionLifecycleSuffixes = []string{
 //	TestAccAAPJobAction_basic -> "aap_job", true
 //	TestHelper -> "", false
 func ExtractResourceFromFuncName(funcName string) (string, bool) {
+	return ExtractResourceFromFuncNameWith(nil, funcName)
+}
+
+// ExtractResourceFromFuncNameWith is ExtractResourceFromFuncName with the snake_case
+// conversion of a naming pipeline, such as one with the replacements of Settings.
+func ExtractResourceFromFuncNameWith(p *naming.Pipeline, funcName string) (string, bool) {
 	var resourceName string
 
 	// Try data source pattern first (more specific)
@@ -165,7 +134,7 @@ func ExtractResourceFromFuncName(funcName string) (string, bool) {
 		}
 	}
 
-	return toSnakeCase(resourceName), true
+	return p.SnakeCase(resourceName), true
 }
 
 // ExtractResourceFromFuncNameWithoutPrefix extracts a resource name and also tries
@@ -263,8 +232,7 @@ func ExtractProviderFromFuncName(funcName string) string {
 // Base class files typically follow the naming pattern base_*.go and contain
 // abstract/base implementations that are not actual Terraform resources.
 func IsBaseClassFile(filePath string) bool {
-	base := filepath.Base(filePath)
-	return strings.HasPrefix(base, "base_") || strings.HasPrefix(base, "base.")
+	return fileroles.Default().Classify(filePath) == fileroles.Base
 }
 
 // isBaseClassFile is an unexported alias for backward compatibility
@@ -276,18 +244,14 @@ func isBaseClassFile(filePath string) bool {
 // Sweeper files are test infrastructure files for cleaning up resources after
 // acceptance tests. They follow the naming pattern *_sweeper.go.
 func IsSweeperFile(filePath string) bool {
-	base := filepath.Base(filePath)
-	return strings.HasSuffix(base, "_sweeper.go")
+	return fileroles.Default().Classify(filePath) == fileroles.Sweeper
 }
 
 // IsMigrationFile checks if a file is a state migration file that should be excluded.
 // Migration files are state migration utilities, not production resources. They follow
 // naming patterns: *_migrate.go, *_migration*.go, *_state_upgrader.go
 func IsMigrationFile(filePath string) bool {
-	base := filepath.Base(filePath)
-	return strings.HasSuffix(base, "_migrate.go") ||
-		strings.Contains(base, "_migration") ||
-		strings.HasSuffix(base, "_state_upgrader.go")
+	return fileroles.Default().Classify(filePath) == fileroles.Migration
 }
 
 // shouldExcludeFile checks if a file path matches any of the exclude patterns
@@ -345,11 +309,6 @@ func CamelCaseToSnakeCaseExported(s string) string {
 	return toSnakeCase(s)
 }
 
-// SnakeCaseToTitleCaseExported is the exported version of toTitleCase for testing
-func SnakeCaseToTitleCaseExported(s string) string {
-	return toTitleCase(s)
-}
-
 // ExtractResourceNameExported is the exported version of extractResourceName for testing
 func ExtractResourceNameExported(typeName string) string {
 	return extractResourceName(typeName)
@@ -432,7 +391,7 @@ func hasImportStateMethod(file *ast.File, resourceName string) bool {
 
 		if funcDecl.Recv != nil {
 			recvType := getReceiverTypeName(funcDecl.Recv)
-			expectedType := toTitleCase(resourceName) + "Resource"
+			expectedType := naming.TitleCase(resourceName) + "Resource"
 			if recvType == expectedType || recvType == "*"+expectedType {
 				found = true
 				return false
@@ -645,7 +604,8 @@ var suppressionPatterns = []*regexp.Regexp{
 func CheckSuppressionComment(comments []*ast.CommentGroup, checkName string) bool {
 	suppressed := GetSuppressedChecks(comments)
 	for _, s := range suppressed {
-		if s == checkName || s == "all" {
+		// Legacy rule names suppress their renamed rules and vice versa
+		if s == checkName || s == "all" || rules.Canonical(s) == rules.Canonical(checkName) {
 			return true
 		}
 	}
@@ -0,0 +1,279 @@
+package messages
+
+// english is the source catalog. Every message ID must have an entry here.
+var english = map[ID]string{
+	KindResource:        "resource",
+	KindResourceTitle:   "Resource",
+	KindDataSource:      "data source",
+	KindDataSourceTitle: "Data source",
+
+	BasicTestMissing: "{kind} '{name}' has no acceptance test\n" +
+		"  {kindTitle}: {file}:{line}\n" +
+		"  Expected test file: {testFile}\n" +
+		"  Expected test function: {testFunc}\n" +
+		"  Suggestion: Create {testFileBase} with function {testFunc}",
+
+	BasicTestBelowMinimum: "{kind} '{name}' has {count} of required {required} acceptance tests\n" +
+		"  {kindTitle}: {file}:{line}\n" +
+		"  Tests: {tests}\n" +
+		"  Suggestion: Add {missing} more acceptance test(s), e.g. covering an update, import, or error case",
+
+	BasicTestQuarantined: "  Quarantined tests: {tests} (coverage at risk until they are fixed and re-enabled)",
+
+	LinkLowConfidence: "test '{test}' linked to '{resource}' with {confidence} confidence via {matchType} match\n" +
+		"  Suggestion: Consider renaming - {suggestion}",
+	LinkSuggestRenameTo:       "rename the test to {testFunc}",
+	LinkSuggestRenameExplicit: "rename the test so it names its resource explicitly",
+
+	UpdateTestMissing: "resource '{name}' has updatable attributes but no update test coverage\n" +
+		"  Resource: {file}:{line}\n" +
+		"  Updatable attributes: {attributes}\n" +
+		"  Suggestion: Add a test step that modifies one of these attributes",
+
+	ImportTestMissing: "resource '{name}' implements ImportState but has no import test coverage\n" +
+		"  Resource: {file}:{line}\n" +
+		"  Suggestion: Add a test step with ImportState: true, ImportStateVerify: true",
+	ImportTestFix: "Add an import step for {address} to {test}",
+
+	ErrorTestMissing: "resource '{name}' has validation rules but no error case tests\n" +
+		"  Resource: {file}:{line}\n" +
+		"  Validated attributes: {attributes}\n" +
+		"  Suggestion: Add a test step with ExpectError to verify validation",
+
+	ProviderConfigUntested: "provider '{provider}' has configuration attributes but no acceptance test exercises them\n" +
+		"  Provider: {file}:{line}\n" +
+		"  Attributes: {attributes}\n" +
+		"  Suggestion: Add a test whose config sets one of these attributes in a provider block, or that sets its environment variable with t.Setenv",
+	ProviderConfigUndefined: "required provider attribute '{attribute}' is not defined in the schema of provider '{provider}'\n" +
+		"  Suggestion: Remove it from required-provider-attributes or check its spelling",
+	ProviderConfigAttrUntested: "provider attribute '{attribute}' is not exercised by any acceptance test\n" +
+		"  Provider: {file}:{line}\n" +
+		"  Suggestion: Add a test whose config sets '{attribute}' in a provider block, or that sets {envVar} with t.Setenv",
+	ProviderConfigEnvVarFallback: "its environment variable",
+
+	StateCheckMissing: "{kind} '{name}' has {count} test(s) but none include state validation (Check) or plan checks (ConfigPlanChecks)\n" +
+		"  Suggestion: Add Check: resource.ComposeTestCheckFunc(...) or ConfigPlanChecks to at least one test",
+	StateCheckWeakTest: "test '{test}' of {kind} '{name}' applies {steps} config step(s) but asserts nothing: no step sets Check, ConfigStateChecks or ConfigPlanChecks, or expects an error\n" +
+		"  Test: {testFile}:{testLine}\n" +
+		"  Suggestion: Assert the applied state, e.g. ConfigStateChecks with statecheck.ExpectKnownValue(address, tfjsonpath.New(\"name\"), knownvalue.StringExact(\"...\")); validate -augment-tests suggests checks for each step",
+
+	DriftCheckMissing: "resource '{name}' has {count} test(s) but none include CheckDestroy for drift detection\n" +
+		"  Suggestion: Add CheckDestroy: testAccCheckDestroy to at least one test's resource.TestCase",
+
+	DriftCheckNotDestroyable: "test '{test}' sets CheckDestroy, but resource '{name}' {reason}, so there is nothing to check\n" +
+		"  Test: {testFile}:{testLine}\n" +
+		"  Resource: {file}:{line}\n" +
+		"  Suggestion: Remove CheckDestroy; it was likely copied from another resource's test",
+	DriftCheckReasonNoDelete:   "sets no Delete function",
+	DriftCheckReasonNoOpDelete: "has a Delete that only removes it from state",
+
+	SweepersMissing: "package has no test sweeper registrations\n" +
+		"  Suggestion: Add resource.AddTestSweepers() calls for cleanup",
+
+	CoverageExpected: "{kind} '{name}' expects {check} test coverage (//tftest:expect) but none of its tests provide it\n" +
+		"  {kindTitle}: {file}:{line}\n" +
+		"  Suggestion: Add a {check} test or remove '{check}' from the directive",
+	CoverageRequired: "{kind} '{name}' requires {check} test coverage ({manifest}) but none of its tests provide it\n" +
+		"  {kindTitle}: {file}:{line}\n" +
+		"  Suggestion: Add a test providing {check} coverage, or move '{check}' from require to exempt in the manifest with a reason",
+	DisappearsTestMissing: "resource '{name}' requires a disappears test ({source}) but none of its {count} test(s) is one\n" +
+		"  Resource: {file}:{line}\n" +
+		"  Suggestion: Add {testFunc}, which deletes the resource out of band (e.g., acctest.CheckResourceDisappears) and sets ExpectNonEmptyPlan: true",
+
+	RequirementsInvalid: "requirements manifest could not be loaded: {error}\n" +
+		"  Suggestion: Fix the manifest, or set requirements-manifest: off to disable it",
+	RequirementsConflict: "{kind} '{name}': {manifest} and a //tftest: directive disagree on check '{check}'; the directive wins\n" +
+		"  Suggestion: Remove '{check}' from one of them",
+	RequirementsUnlisted: "{kind} '{name}' is not listed in {manifest}, which is strict\n" +
+		"  Suggestion: Declare its required coverage in the manifest, or run validate -sync-manifest to scaffold an entry",
+
+	DirectiveUnknown:       "unknown directive //tftest:{directive}{hint}\n  Known directives: expect, exempt, maturity",
+	DirectiveUnknownCheck:  "unknown check '{value}' in //tftest:{directive}{hint}\n  Known checks: {checks}",
+	DirectiveUnknownOption: "unknown option '{value}' in //tftest:{directive}{hint}\n  Known options: reason (exempt only)",
+	DirectiveMissingChecks: "//tftest:{directive} needs a comma-separated list of checks\n  Known checks: {checks}",
+	DirectiveMalformed:     "malformed //tftest:{directive} directive: {value}",
+	DirectiveConflict:      "check '{value}' is both expected and exempted for '{target}'",
+	DirectiveInapplicable:  "check '{value}' does not apply to {kind} '{target}'",
+	DirectiveUnknownLevel:  "unknown maturity level '{value}' in //tftest:{directive}{hint}\n  Known levels: {levels}",
+	DirectiveUnattached: "//tftest:{directive} does not document a resource, data source, or action\n" +
+		"  Suggestion: Place the directive in the doc comment of the resource type or its Schema method",
+	DidYouMean: " (did you mean '{suggestion}'?)",
+
+	ImportStateIdFuncUnknownAttr: "ImportStateIdFunc {function} in test '{test}' reads state attribute '{attribute}', which is not defined in the schema of resource '{name}'{hint}\n" +
+		"  Resource: {file}:{line}\n" +
+		"  Suggestion: Read an attribute that the resource sets; an unknown key yields an empty import ID",
+
+	ImportStateVerifyMissing: "import step {step} in test '{test}' sets ImportState without ImportStateVerify\n" +
+		"  Test: {file}:{line}\n" +
+		"  Suggestion: Set ImportStateVerify: true so the imported state is compared with the created resource; list attributes that cannot be read back in ImportStateVerifyIgnore, or assert on the imported state in ImportStateCheck",
+	ImportStateCheckNoAssertions: "import step {step} in test '{test}' sets ImportStateCheck, but {check} neither counts the imported instances nor reads their attributes\n" +
+		"  Test: {file}:{line}\n" +
+		"  Suggestion: Check len(states) and compare states[0].Attributes with the expected values, or set ImportStateVerify: true",
+
+	ParallelFixtureClash: "parallel test '{test}' hard-codes {resourceType}.{attribute} = \"{value}\", which is also used by {others}\n" +
+		"  Test: {file}:{line}\n" +
+		"  Suggestion: These tests collide when run in parallel; generate the value with acctest.RandomWithPrefix or add a random suffix",
+
+	ParallelTestMissing: "acceptance test '{test}' uses resource.Test, but no test of its resource documents a reason to run serially\n" +
+		"  Test: {file}:{line}\n" +
+		"  Estimate: {serial} of {total} acceptance tests in this package run serially; in parallel (go test -parallel {parallelism}) the package takes about {after} instead of {before} test durations, {savings}% less wall time\n" +
+		"  Suggestion: Use resource.ParallelTest, or add a comment explaining why the test must run serially (e.g., // Serial: modifies account-wide settings)",
+
+	TestMisplaced: "test '{test}' is for {kind} '{name}' but is in {testFile}, the test file of '{fileResource}'\n" +
+		"  Test: {file}:{line}\n" +
+		"  File: {misplaced} of {total} tests in {testFile} are for other definitions\n" +
+		"  Suggestion: Move the test to {expected}",
+
+	TestLayoutMisplaced: "test '{test}' is for {kind} '{name}' but is in {dir}, not next to it in {expectedDir}\n" +
+		"  Test: {file}:{line}\n" +
+		"  Layout: {layout}\n" +
+		"  Suggestion: Move the test to {expected}, or set test-layout: centralized if the provider keeps its acceptance tests in a package of their own",
+	TestLayoutFix: "Move {test} to {expected}",
+
+	OrphanTest: "acceptance test '{test}' is not linked to any resource, data source or action\n" +
+		"  Test: {file}:{line}{inferred}\n" +
+		"{suggestion}",
+	OrphanTestInferred:    "\n  Inferred from configs: {resources}",
+	OrphanTestClosest:     "  Suggestion: If it tests {kind} '{name}' (name similarity {similarity}), rename it to {expectedFunc} in {expectedFile}; otherwise delete it",
+	OrphanTestNoCandidate: "  Suggestion: Name the test after the definition it tests (e.g., TestAcc<Name>_basic), or delete it if that definition no longer exists",
+
+	DefaultValueUntested: "attribute '{attribute}' of resource '{resource}' has a default value, but all {steps} test configs declaring {resourceType} set it\n" +
+		"  Default: {file}:{line}\n" +
+		"  Suggestion: Add a step that omits '{attribute}' and checks the default, e.g. statecheck.ExpectKnownValue or resource.TestCheckResourceAttr",
+
+	ProviderHygieneNoTestMain: "package {package} registers {sweepers} test sweeper(s) but has no TestMain, so go test -sweep never runs them\n" +
+		"  Sweeper: {file}:{line}\n" +
+		"  Suggestion: Add func TestMain(m *testing.M) { resource.TestMain(m) } to a test file of the package",
+	ProviderHygieneNoDispatch: "TestMain of package {package} does not call resource.TestMain, so its {sweepers} registered test sweeper(s) never run\n" +
+		"  TestMain: {file}:{line}\n" +
+		"  Suggestion: Call resource.TestMain(m) from TestMain; it runs the sweepers for -sweep and the tests otherwise",
+	ProviderHygieneTestsNotRun: "TestMain of package {package} neither calls m.Run() nor resource.TestMain, so its {acceptance} acceptance test(s) never run\n" +
+		"  TestMain: {file}:{line}\n" +
+		"  Suggestion: End TestMain with os.Exit(m.Run()), or call resource.TestMain(m)",
+
+	UnknownType: "test '{test}' declares {block} \"{type}\", which the provider does not define, so the test fails when it runs\n" +
+		"  Test: {file}:{line}\n" +
+		"{suggestion}",
+	UnknownTypeClosest:     "  Suggestion: Did you mean \"{name}\"?",
+	UnknownTypeNoCandidate: "  Suggestion: Fix the type name, or remove the block if the definition no longer exists",
+
+	TestHelperFatalInGoroutine: "test helper '{helper}' calls {call} from a goroutine, which does not stop the test and panics if the test has already returned\n" +
+		"  Helper: {file}:{line}\n" +
+		"  Used by: {callers} test function(s)\n" +
+		"  Suggestion: Report with t.Error in the goroutine, or send the error back and call t.Fatal from the goroutine running the test",
+	TestHelperIgnoredError: "test helper '{helper}' discards the error returned by {call}, so a failed setup goes unnoticed\n" +
+		"  Helper: {file}:{line}\n" +
+		"  Used by: {callers} test function(s)\n" +
+		"  Suggestion: Check the error and call t.Fatal(err), or return it to the caller",
+
+	DataSourceConfigNoResource: "test '{test}' of data source '{name}' declares no resource \"{type}\" for it to read, so it depends on infrastructure that already exists and cannot be reproduced in CI\n" +
+		"  Test: {file}:{line}\n" +
+		"  Suggestion: Create a {type} in the test config and point the data source at it, or exempt the data source with //tftest:exempt managed-config reason=\"...\"",
+	DataSourceConfigNoManaged: "test '{test}' of data source '{name}' declares no managed resource, so it depends on infrastructure that already exists and cannot be reproduced in CI\n" +
+		"  Test: {file}:{line}\n" +
+		"  Suggestion: Create what the data source reads in the test config, or exempt a read-only or global data source with //tftest:exempt managed-config reason=\"...\"",
+
+	DataSourceAssertsTooFew: "the {tests} test(s) of data source '{name}' assert {asserted} of its {computed} computed attribute(s) besides id, fewer than {required}, so they do not show the lookup returns useful fields\n" +
+		"  Data source: {file}:{line}\n" +
+		"  Not asserted: {unasserted}\n" +
+		"  Suggestion: Check computed attributes of the data source with resource.TestCheckResourceAttr or statecheck.ExpectKnownValue, or exempt it with //tftest:exempt state-check reason=\"...\"",
+
+	ExpectErrorInvalid: "ExpectError in step {step} of test '{test}' does not compile: {error}\n" +
+		"  Test: {file}:{line}\n" +
+		"  Suggestion: Fix the regular expression; regexp.MustCompile panics when the test runs",
+	ExpectErrorMatchesAll: "ExpectError in step {step} of test '{test}' uses the pattern `{pattern}`, which matches any error\n" +
+		"  Test: {file}:{line}\n" +
+		"  Suggestion: Match the expected diagnostic's summary or detail so the test fails when a different error occurs",
+	ExpectErrorGeneric: "ExpectError in step {step} of test '{test}' uses the pattern `{pattern}`, which only matches the generic word '{word}'\n" +
+		"  Test: {file}:{line}\n" +
+		"  Suggestion: Match the expected diagnostic's summary or detail (e.g., `Invalid Attribute Value Match`) so the test fails when a different error occurs",
+
+	DeadTestCommentedOut: "commented-out acceptance test {tests} ({lines} lines)\n" +
+		"  Test: {file}:{line}\n" +
+		"  Suggestion: Restore the test, or delete it and record why the coverage was dropped (e.g., a //tftest:exempt directive)",
+	DeadTestCommentedOutCall: "commented-out resource.Test call ({lines} lines)\n" +
+		"  Test: {file}:{line}\n" +
+		"  Suggestion: Restore the test, or delete it and record why the coverage was dropped (e.g., a //tftest:exempt directive)",
+
+	DeferredActionsUntested: "resource '{name}' defers changes in ModifyPlan (resp.Deferred) but no acceptance test allows deferral\n" +
+		"  Resource: {file}:{line}\n" +
+		"  Suggestion: Add a test step with AdditionalCLIOptions: &resource.AdditionalCLIOptions{Plan: resource.PlanOptions{AllowDeferral: true}, Apply: resource.ApplyOptions{AllowDeferral: true}}",
+	DeferredProviderUntested: "provider '{provider}' defers changes in Configure (resp.Deferred) but no acceptance test allows deferral\n" +
+		"  Provider: {file}:{line}\n" +
+		"  Suggestion: Add a test step with AdditionalCLIOptions: &resource.AdditionalCLIOptions{Plan: resource.PlanOptions{AllowDeferral: true}, Apply: resource.ApplyOptions{AllowDeferral: true}}",
+
+	ProviderAliasTestMissing: "resource '{name}' spans provider instances ({reason}) but none of its {tests} test(s) configures an aliased provider\n" +
+		"  Resource: {file}:{line}\n" +
+		"  Suggestion: Add a test config with a second provider block, e.g. provider \"{provider}\" { alias = \"peer\" }, and set provider = {provider}.peer on the peer-side resources",
+
+	UpgradeTestMissing: "resource '{name}' changed its schema version from {from} to {to} since {since}, but none of its {tests} test(s) is an upgrade test\n" +
+		"  Schema version: {file}:{line}\n" +
+		"  Suggestion: Add a test whose first step applies a config with the last release, pinned by an ExternalProviders entry named \"{provider}\" with the release as its VersionConstraint, and whose next step plans the same config with the provider under test and expects no changes, so the state upgrade is exercised",
+
+	PairUntested: "{kind} '{name}' has no acceptance test, but the {testedKind} of the same name has {count} test(s)\n" +
+		"  {kindTitle}: {file}:{line}\n" +
+		"  Tested {testedKind}: {testedFile}:{testedLine} ({tests})\n" +
+		"  Suggestion: Add {testFunc}, starting from the config of a {testedKind} test",
+
+	PluralDataSourceUnsized: "the {tests} test(s) of plural data source '{name}' never assert how many items it returns, so a lookup returning a single item passes\n" +
+		"  Data source: {file}:{line}\n" +
+		"  Singular counterpart: {singular}\n" +
+		"  Suggestion: Assert the item count, e.g. resource.TestCheckResourceAttr(address, \"{attribute}.#\", \"2\") or statecheck.ExpectKnownValue(address, tfjsonpath.New(\"{attribute}\"), knownvalue.ListSizeExact(2)), or exempt it with //tftest:exempt state-check reason=\"...\"",
+
+	LegacyCheckAdded: "step {step} of test '{test}' asserts state with the legacy Check field, added since {since}\n" +
+		"  Check: {file}:{line}\n" +
+		"  Steps setting Check: {legacy} in the test, {base} at {since}\n" +
+		"  Suggestion: Assert state with ConfigStateChecks instead, e.g. statecheck.ExpectKnownValue(address, tfjsonpath.New(\"name\"), knownvalue.StringExact(\"...\")); existing Check fields can migrate gradually",
+
+	ProviderFactoriesMixed: "test '{test}' serves the provider with {field}, but {count} of the {total} tests of package {package} that set a factory field use {dominant}\n" +
+		"  Field: {file}:{line}\n" +
+		"  Suggestion: Use the package's pattern, {dominant}: {value}; mixing factory types in one package usually means the test runs against a provider server other than the one the package ships",
+
+	ProviderFactoriesMissing: "a TestCase of test '{test}' gives step(s) {steps} no provider: neither it nor the step sets any of {fields}\n" +
+		"  TestCase: {file}:{line}\n" +
+		"  Suggestion: Set {suggestion} on the TestCase; without it the test fails at runtime with an error that does not name the missing field",
+
+	ErrorCheckMissing: "a TestCase of test '{test}' sets no ErrorCheck, but {count} of the {total} TestCases of package {package} do\n" +
+		"  TestCase: {file}:{line}\n" +
+		"  Suggestion: Set ErrorCheck with the package's helper, {helper}, so errors the package expects in some regions or partitions, such as an unsupported service, skip the test instead of failing it",
+	ErrorCheckFuncLiteral: "a function literal like its other TestCases",
+
+	CheckAddressUndeclared: "{function} in step {step} of test '{test}' checks '{address}', which the step's config does not declare\n" +
+		"  Check: {file}:{line}\n" +
+		"  Declared: {declared}\n" +
+		"  Suggestion: Check an address the config declares, or add the block to the config; otherwise the check fails or inspects the wrong resource",
+	CheckAddressSimilar: "{function} in step {step} of test '{test}' checks '{address}', which the step's config does not declare\n" +
+		"  Check: {file}:{line}\n" +
+		"  Declared: {declared}\n" +
+		"  Suggestion: Did you mean '{similar}'?",
+
+	RefreshDriftMissing: "resource '{name}' has {count} test(s) but none include a RefreshState step, so drift detection on refresh is never exercised\n" +
+		"  Suggestion: Add a step with RefreshState: true after the config steps of a test, optionally with RefreshPlanChecks asserting an empty plan",
+	RefreshPlanChecksIgnored: "step {step} of test '{test}' sets RefreshPlanChecks without RefreshState: true, so the checks never run\n" +
+		"  Step: {file}:{line}\n" +
+		"  Suggestion: Set RefreshState: true on the step, or move the checks to ConfigPlanChecks",
+
+	PlaceholderValue: "step {step} of test '{test}' sets required attribute '{attribute}' of {resourceType} to the placeholder \"{value}\", but its validators expect {format}\n" +
+		"  Config: {file}:{line}\n" +
+		"  Suggestion: Use a realistic value such as {example}; if the step means to test validation, add ExpectError",
+
+	IdempotencyMissing: "resource '{name}' has Optional+Computed attribute(s) prone to perpetual diffs ({attributes}), but none of its {count} test(s) plans the same config again expecting no changes\n" +
+		"  Suggestion: Repeat the last config step of a test with PlanOnly: true, or add plancheck.ExpectEmptyPlan() to ConfigPlanChecks of a step re-applying it",
+
+	EnvPreCheckMissing: "test '{test}' interpolates environment variable '{env}' into its config, but its PreCheck never checks it\n" +
+		"  Config: {file}:{line}\n" +
+		"  Suggestion: Check it in PreCheck, e.g. if os.Getenv(\"{env}\") == \"\" { t.Skip(\"{env} must be set\") }, so the test is skipped instead of applying a config with an empty value",
+
+	DeprecatedReplacementMissing: "test '{test}' sets deprecated attribute '{attribute}' of {resource}, but no test sets its replacement {replacements}\n" +
+		"  Config: {file}:{line}\n" +
+		"  Deprecation: {deprecation}\n" +
+		"  Suggestion: Set {replacements} in this test or another one, so the replacement is covered before '{attribute}' is removed",
+
+	FileSkippedTooLarge: "file not analyzed: {size} KB exceeds max-file-size-kb ({limit} KB)\n" +
+		"  Suggestion: Exclude the file with exclude-patterns, or raise max-file-size-kb (a negative value removes the limit)",
+	FileSkippedTooMany: "file not analyzed: the package has more than max-files ({limit}) Go files\n" +
+		"  Suggestion: Exclude generated directories with exclude-paths, or raise max-files",
+
+	DirConfigInvalid: "directory config not applied: {error}\n" +
+		"  Suggestion: Fix the file; findings below it use the overrides of the directories above",
+}
@@ -0,0 +1,280 @@
+package messages
+
+// japanese translates the diagnostics. Code identifiers, setting names, and file paths are
+// left untranslated so they can be searched for and copied as-is.
+var japanese = map[ID]string{
+	KindResource:        "リソース",
+	KindResourceTitle:   "リソース",
+	KindDataSource:      "データソース",
+	KindDataSourceTitle: "データソース",
+
+	BasicTestMissing: "{kind} '{name}' に受け入れテストがありません\n" +
+		"  {kindTitle}: {file}:{line}\n" +
+		"  想定されるテストファイル: {testFile}\n" +
+		"  想定されるテスト関数: {testFunc}\n" +
+		"  提案: {testFileBase} を作成し、関数 {testFunc} を追加してください",
+
+	BasicTestBelowMinimum: "{kind} '{name}' の受け入れテストは必要な {required} 件のうち {count} 件です\n" +
+		"  {kindTitle}: {file}:{line}\n" +
+		"  テスト: {tests}\n" +
+		"  提案: 更新、インポート、エラーケースなどを対象とした受け入れテストをあと {missing} 件追加してください",
+
+	BasicTestQuarantined: "  隔離されたテスト: {tests} (修正して再有効化するまでカバレッジが失われるおそれがあります)",
+
+	LinkLowConfidence: "テスト '{test}' は {matchType} マッチにより信頼度 {confidence} で '{resource}' に関連付けられました\n" +
+		"  提案: 名前の変更を検討してください - {suggestion}",
+	LinkSuggestRenameTo:       "テスト名を {testFunc} に変更する",
+	LinkSuggestRenameExplicit: "対象リソースが明確に分かるテスト名に変更する",
+
+	UpdateTestMissing: "リソース '{name}' には更新可能な属性がありますが、更新テストがありません\n" +
+		"  リソース: {file}:{line}\n" +
+		"  更新可能な属性: {attributes}\n" +
+		"  提案: これらの属性のいずれかを変更するテストステップを追加してください",
+
+	ImportTestMissing: "リソース '{name}' は ImportState を実装していますが、インポートテストがありません\n" +
+		"  リソース: {file}:{line}\n" +
+		"  提案: ImportState: true, ImportStateVerify: true を指定したテストステップを追加してください",
+	ImportTestFix: "{test} に {address} のインポートステップを追加してください",
+
+	ErrorTestMissing: "リソース '{name}' にはバリデーションルールがありますが、エラーケースのテストがありません\n" +
+		"  リソース: {file}:{line}\n" +
+		"  バリデーション対象の属性: {attributes}\n" +
+		"  提案: ExpectError を指定したテストステップを追加してバリデーションを検証してください",
+
+	ProviderConfigUntested: "プロバイダー '{provider}' には設定属性がありますが、それらを使用する受け入れテストがありません\n" +
+		"  プロバイダー: {file}:{line}\n" +
+		"  属性: {attributes}\n" +
+		"  提案: provider ブロックでこれらの属性のいずれかを設定するテスト、または t.Setenv で対応する環境変数を設定するテストを追加してください",
+	ProviderConfigUndefined: "必須のプロバイダー属性 '{attribute}' はプロバイダー '{provider}' のスキーマに定義されていません\n" +
+		"  提案: required-provider-attributes から削除するか、綴りを確認してください",
+	ProviderConfigAttrUntested: "プロバイダー属性 '{attribute}' を使用する受け入れテストがありません\n" +
+		"  プロバイダー: {file}:{line}\n" +
+		"  提案: provider ブロックで '{attribute}' を設定するテスト、または t.Setenv で {envVar} を設定するテストを追加してください",
+	ProviderConfigEnvVarFallback: "対応する環境変数",
+
+	StateCheckMissing: "{kind} '{name}' には {count} 件のテストがありますが、状態の検証 (Check) もプランチェック (ConfigPlanChecks) も含まれていません\n" +
+		"  提案: 少なくとも 1 つのテストに Check: resource.ComposeTestCheckFunc(...) または ConfigPlanChecks を追加してください",
+	StateCheckWeakTest: "{kind} '{name}' のテスト '{test}' は {steps} 件の設定ステップを適用しますが、何も検証していません: Check、ConfigStateChecks、ConfigPlanChecks を設定するステップも、エラーを期待するステップもありません\n" +
+		"  テスト: {testFile}:{testLine}\n" +
+		"  提案: 適用後の状態を検証してください (例: ConfigStateChecks で statecheck.ExpectKnownValue(address, tfjsonpath.New(\"name\"), knownvalue.StringExact(\"...\")))。validate -augment-tests で各ステップのチェックを提案できます",
+
+	DriftCheckMissing: "リソース '{name}' には {count} 件のテストがありますが、ドリフト検出のための CheckDestroy が含まれていません\n" +
+		"  提案: 少なくとも 1 つのテストの resource.TestCase に CheckDestroy: testAccCheckDestroy を追加してください",
+
+	DriftCheckNotDestroyable: "テスト '{test}' は CheckDestroy を設定していますが、リソース '{name}' は{reason}ため、確認する対象がありません\n" +
+		"  テスト: {testFile}:{testLine}\n" +
+		"  リソース: {file}:{line}\n" +
+		"  提案: CheckDestroy を削除してください。他のリソースのテストからコピーされた可能性があります",
+	DriftCheckReasonNoDelete:   "Delete 関数を設定していない",
+	DriftCheckReasonNoOpDelete: "ステートから削除するだけの Delete を持つ",
+
+	SweepersMissing: "パッケージにテストスイーパーの登録がありません\n" +
+		"  提案: クリーンアップのために resource.AddTestSweepers() の呼び出しを追加してください",
+
+	CoverageExpected: "{kind} '{name}' は {check} テストのカバレッジを要求していますが (//tftest:expect)、該当するテストがありません\n" +
+		"  {kindTitle}: {file}:{line}\n" +
+		"  提案: {check} テストを追加するか、ディレクティブから '{check}' を削除してください",
+	CoverageRequired: "{kind} '{name}' は {check} テストのカバレッジが必須とされていますが ({manifest})、該当するテストがありません\n" +
+		"  {kindTitle}: {file}:{line}\n" +
+		"  提案: {check} テストを追加するか、理由を添えてマニフェストの require から exempt へ '{check}' を移してください",
+	DisappearsTestMissing: "リソース '{name}' には disappears テストが必須とされていますが ({source})、{count} 件のテストのいずれも該当しません\n" +
+		"  リソース: {file}:{line}\n" +
+		"  提案: リソースをテスト外で削除し (例: acctest.CheckResourceDisappears)、ExpectNonEmptyPlan: true を設定する {testFunc} を追加してください",
+
+	RequirementsInvalid: "要件マニフェストを読み込めませんでした: {error}\n" +
+		"  提案: マニフェストを修正するか、requirements-manifest: off を設定して無効にしてください",
+	RequirementsConflict: "{kind} '{name}': {manifest} と //tftest: ディレクティブでチェック '{check}' の指定が矛盾しています。ディレクティブが優先されます\n" +
+		"  提案: どちらか一方から '{check}' を削除してください",
+	RequirementsUnlisted: "{kind} '{name}' が strict な {manifest} に記載されていません\n" +
+		"  提案: マニフェストに必要なカバレッジを記載するか、validate -sync-manifest を実行してエントリを生成してください",
+
+	DirectiveUnknown:       "不明なディレクティブ //tftest:{directive}{hint}\n  使用可能なディレクティブ: expect, exempt, maturity",
+	DirectiveUnknownCheck:  "//tftest:{directive} に不明なチェック '{value}' があります{hint}\n  使用可能なチェック: {checks}",
+	DirectiveUnknownOption: "//tftest:{directive} に不明なオプション '{value}' があります{hint}\n  使用可能なオプション: reason (exempt のみ)",
+	DirectiveMissingChecks: "//tftest:{directive} にはカンマ区切りのチェック一覧が必要です\n  使用可能なチェック: {checks}",
+	DirectiveMalformed:     "//tftest:{directive} ディレクティブの形式が正しくありません: {value}",
+	DirectiveConflict:      "チェック '{value}' は '{target}' に対して expect と exempt の両方に指定されています",
+	DirectiveInapplicable:  "チェック '{value}' は {kind} '{target}' には適用されません",
+	DirectiveUnknownLevel:  "//tftest:{directive} に不明な成熟度レベル '{value}' があります{hint}\n  使用可能なレベル: {levels}",
+	DirectiveUnattached: "//tftest:{directive} がリソース、データソース、アクションのいずれにも付与されていません\n" +
+		"  提案: リソース型または Schema メソッドのドキュメントコメントにディレクティブを記述してください",
+	DidYouMean: " ('{suggestion}' のことですか?)",
+
+	ImportStateIdFuncUnknownAttr: "テスト '{test}' の ImportStateIdFunc {function} が状態属性 '{attribute}' を参照していますが、リソース '{name}' のスキーマに定義されていません{hint}\n" +
+		"  リソース: {file}:{line}\n" +
+		"  提案: リソースが設定する属性を参照してください。存在しないキーはインポート ID が空になります",
+
+	ImportStateVerifyMissing: "テスト '{test}' のインポートステップ {step} が ImportStateVerify なしで ImportState を設定しています\n" +
+		"  テスト: {file}:{line}\n" +
+		"  提案: ImportStateVerify: true を設定してインポートした状態を作成済みリソースと比較してください。読み戻せない属性は ImportStateVerifyIgnore に列挙するか、ImportStateCheck でインポートした状態を検証してください",
+	ImportStateCheckNoAssertions: "テスト '{test}' のインポートステップ {step} が ImportStateCheck を設定していますが、{check} はインポートしたインスタンスの数も属性も確認していません\n" +
+		"  テスト: {file}:{line}\n" +
+		"  提案: len(states) を確認し、states[0].Attributes を期待値と比較するか、ImportStateVerify: true を設定してください",
+
+	ParallelFixtureClash: "並列テスト '{test}' が {resourceType}.{attribute} = \"{value}\" をハードコードしていますが、{others} でも使われています\n" +
+		"  テスト: {file}:{line}\n" +
+		"  提案: 並列実行時に衝突します。acctest.RandomWithPrefix で値を生成するか、ランダムな接尾辞を付けてください",
+
+	ParallelTestMissing: "受け入れテスト '{test}' が resource.Test を使用していますが、そのリソースのテストには直列実行の理由が記載されていません\n" +
+		"  テスト: {file}:{line}\n" +
+		"  見積もり: このパッケージの受け入れテスト {total} 件中 {serial} 件が直列実行されています。並列実行 (go test -parallel {parallelism}) するとテスト {before} 回分の所要時間が約 {after} 回分になり、実行時間を {savings}% 短縮できます\n" +
+		"  提案: resource.ParallelTest を使用するか、直列実行が必要な理由をコメントで記載してください (例: // Serial: アカウント全体の設定を変更するため)",
+
+	TestMisplaced: "テスト '{test}' は{kind} '{name}' のテストですが、'{fileResource}' のテストファイル {testFile} にあります\n" +
+		"  テスト: {file}:{line}\n" +
+		"  ファイル: {testFile} のテスト {total} 件中 {misplaced} 件が他の定義のテストです\n" +
+		"  提案: テストを {expected} に移動してください",
+
+	TestLayoutMisplaced: "テスト '{test}' は{kind} '{name}' のテストですが、その隣の {expectedDir} ではなく {dir} にあります\n" +
+		"  テスト: {file}:{line}\n" +
+		"  レイアウト: {layout}\n" +
+		"  提案: テストを {expected} に移動するか、受け入れテストを専用のパッケージにまとめている場合は test-layout: centralized を設定してください",
+	TestLayoutFix: "{test} を {expected} に移動してください",
+
+	OrphanTest: "受け入れテスト '{test}' はどのリソース、データソース、アクションにも関連付けられていません\n" +
+		"  テスト: {file}:{line}{inferred}\n" +
+		"{suggestion}",
+	OrphanTestInferred:    "\n  設定から推定: {resources}",
+	OrphanTestClosest:     "  提案: {kind} '{name}' のテスト (名前の類似度 {similarity}) であれば、{expectedFile} の {expectedFunc} に名前を変更してください。そうでなければ削除してください",
+	OrphanTestNoCandidate: "  提案: テスト対象の定義に合わせてテスト名を付けてください (例: TestAcc<Name>_basic)。その定義が存在しない場合は削除してください",
+
+	DefaultValueUntested: "リソース '{resource}' の属性 '{attribute}' にはデフォルト値がありますが、{resourceType} を宣言する {steps} 件のテスト設定すべてで設定されています\n" +
+		"  デフォルト: {file}:{line}\n" +
+		"  提案: '{attribute}' を省略してデフォルト値を確認するステップを追加してください (例: statecheck.ExpectKnownValue や resource.TestCheckResourceAttr)",
+
+	ProviderHygieneNoTestMain: "パッケージ {package} はテストスイーパーを {sweepers} 件登録していますが TestMain がないため、go test -sweep で実行されません\n" +
+		"  スイーパー: {file}:{line}\n" +
+		"  提案: パッケージのテストファイルに func TestMain(m *testing.M) { resource.TestMain(m) } を追加してください",
+	ProviderHygieneNoDispatch: "パッケージ {package} の TestMain が resource.TestMain を呼び出していないため、登録済みのテストスイーパー {sweepers} 件が実行されません\n" +
+		"  TestMain: {file}:{line}\n" +
+		"  提案: TestMain から resource.TestMain(m) を呼び出してください。-sweep 指定時はスイーパーを、それ以外はテストを実行します",
+	ProviderHygieneTestsNotRun: "パッケージ {package} の TestMain が m.Run() も resource.TestMain も呼び出していないため、受け入れテスト {acceptance} 件が実行されません\n" +
+		"  TestMain: {file}:{line}\n" +
+		"  提案: TestMain の最後で os.Exit(m.Run()) を呼ぶか、resource.TestMain(m) を呼び出してください",
+
+	UnknownType: "テスト '{test}' は {block} \"{type}\" を宣言していますが、プロバイダーはこれを定義していないため、テストは実行時に失敗します\n" +
+		"  テスト: {file}:{line}\n" +
+		"{suggestion}",
+	UnknownTypeClosest:     "  提案: \"{name}\" の誤りではありませんか?",
+	UnknownTypeNoCandidate: "  提案: 型名を修正するか、定義が削除済みであればブロックを削除してください",
+
+	TestHelperFatalInGoroutine: "テストヘルパー '{helper}' がゴルーチンから {call} を呼び出しています。テストは停止せず、テスト終了後であればパニックします\n" +
+		"  ヘルパー: {file}:{line}\n" +
+		"  使用箇所: {callers} 件のテスト関数\n" +
+		"  提案: ゴルーチンでは t.Error で報告するか、エラーを送り返してテストを実行しているゴルーチンで t.Fatal を呼び出してください",
+	TestHelperIgnoredError: "テストヘルパー '{helper}' が {call} の返すエラーを破棄しているため、セットアップの失敗が見過ごされます\n" +
+		"  ヘルパー: {file}:{line}\n" +
+		"  使用箇所: {callers} 件のテスト関数\n" +
+		"  提案: エラーを確認して t.Fatal(err) を呼び出すか、呼び出し元に返してください",
+
+	DataSourceConfigNoResource: "データソース '{name}' のテスト '{test}' の設定に読み取り対象の resource \"{type}\" がないため、既存のインフラに依存しており CI で再現できません\n" +
+		"  テスト: {file}:{line}\n" +
+		"  提案: テスト設定で {type} を作成してデータソースから参照するか、//tftest:exempt managed-config reason=\"...\" でデータソースを除外してください",
+	DataSourceConfigNoManaged: "データソース '{name}' のテスト '{test}' の設定にマネージドリソースがないため、既存のインフラに依存しており CI で再現できません\n" +
+		"  テスト: {file}:{line}\n" +
+		"  提案: データソースが読み取る対象をテスト設定で作成するか、読み取り専用またはグローバルなデータソースであれば //tftest:exempt managed-config reason=\"...\" で除外してください",
+
+	DataSourceAssertsTooFew: "データソース '{name}' の {tests} 件のテストが検証している id 以外の計算属性は {computed} 件中 {asserted} 件で、{required} 件に足りないため、検索が有用な値を返すことを確認できていません\n" +
+		"  データソース: {file}:{line}\n" +
+		"  未検証: {unasserted}\n" +
+		"  提案: resource.TestCheckResourceAttr または statecheck.ExpectKnownValue でデータソースの計算属性を検証するか、//tftest:exempt state-check reason=\"...\" で除外してください",
+
+	ExpectErrorInvalid: "テスト '{test}' のステップ {step} の ExpectError がコンパイルできません: {error}\n" +
+		"  テスト: {file}:{line}\n" +
+		"  提案: 正規表現を修正してください。regexp.MustCompile はテスト実行時にパニックします",
+	ExpectErrorMatchesAll: "テスト '{test}' のステップ {step} の ExpectError のパターン `{pattern}` はあらゆるエラーに一致します\n" +
+		"  テスト: {file}:{line}\n" +
+		"  提案: 別のエラーが発生したときにテストが失敗するよう、想定する診断の概要または詳細に一致させてください",
+	ExpectErrorGeneric: "テスト '{test}' のステップ {step} の ExpectError のパターン `{pattern}` は一般的な語 '{word}' にしか一致しません\n" +
+		"  テスト: {file}:{line}\n" +
+		"  提案: 別のエラーが発生したときにテストが失敗するよう、想定する診断の概要または詳細 (例: `Invalid Attribute Value Match`) に一致させてください",
+
+	DeadTestCommentedOut: "コメントアウトされた受け入れテスト {tests} ({lines} 行)\n" +
+		"  テスト: {file}:{line}\n" +
+		"  提案: テストを復元するか、削除してカバレッジを外した理由を記録してください (例: //tftest:exempt ディレクティブ)",
+	DeadTestCommentedOutCall: "コメントアウトされた resource.Test 呼び出し ({lines} 行)\n" +
+		"  テスト: {file}:{line}\n" +
+		"  提案: テストを復元するか、削除してカバレッジを外した理由を記録してください (例: //tftest:exempt ディレクティブ)",
+
+	DeferredActionsUntested: "リソース '{name}' は ModifyPlan で変更を延期 (resp.Deferred) しますが、延期を許可する受け入れテストがありません\n" +
+		"  リソース: {file}:{line}\n" +
+		"  提案: AdditionalCLIOptions: &resource.AdditionalCLIOptions{Plan: resource.PlanOptions{AllowDeferral: true}, Apply: resource.ApplyOptions{AllowDeferral: true}} を指定したテストステップを追加してください",
+	DeferredProviderUntested: "プロバイダー '{provider}' は Configure で変更を延期 (resp.Deferred) しますが、延期を許可する受け入れテストがありません\n" +
+		"  プロバイダー: {file}:{line}\n" +
+		"  提案: AdditionalCLIOptions: &resource.AdditionalCLIOptions{Plan: resource.PlanOptions{AllowDeferral: true}, Apply: resource.ApplyOptions{AllowDeferral: true}} を指定したテストステップを追加してください",
+
+	ProviderAliasTestMissing: "リソース '{name}' は複数のプロバイダーインスタンスにまたがります ({reason}) が、{tests} 件のテストのいずれもエイリアス付きプロバイダーを構成していません\n" +
+		"  リソース: {file}:{line}\n" +
+		"  提案: provider \"{provider}\" { alias = \"peer\" } のような 2 つ目のプロバイダーブロックを持つテスト構成を追加し、ピア側のリソースに provider = {provider}.peer を設定してください",
+
+	UpgradeTestMissing: "リソース '{name}' のスキーマバージョンが {since} 以降 {from} から {to} に変わりましたが、{tests} 件のテストのいずれもアップグレードテストではありません\n" +
+		"  スキーマバージョン: {file}:{line}\n" +
+		"  提案: 最初のステップで VersionConstraint に最新リリースを指定した \"{provider}\" という名前の ExternalProviders エントリにより最新リリースで構成を適用し、次のステップで開発中のプロバイダーで同じ構成をプランして変更がないことを確認するテストを追加し、状態のアップグレードを検証してください",
+
+	PairUntested: "{kind} '{name}' には受け入れテストがありませんが、同じ名前の{testedKind}には {count} 件のテストがあります\n" +
+		"  {kindTitle}: {file}:{line}\n" +
+		"  テスト済みの{testedKind}: {testedFile}:{testedLine} ({tests})\n" +
+		"  提案: {testedKind}のテストの構成をもとに {testFunc} を追加してください",
+
+	PluralDataSourceUnsized: "複数形のデータソース '{name}' の {tests} 件のテストは返される項目数を検証していないため、項目が 1 件だけ返っても成功します\n" +
+		"  データソース: {file}:{line}\n" +
+		"  単数形の対応: {singular}\n" +
+		"  提案: resource.TestCheckResourceAttr(address, \"{attribute}.#\", \"2\") や statecheck.ExpectKnownValue(address, tfjsonpath.New(\"{attribute}\"), knownvalue.ListSizeExact(2)) で項目数を検証するか、//tftest:exempt state-check reason=\"...\" で除外してください",
+
+	LegacyCheckAdded: "テスト '{test}' のステップ {step} は {since} 以降に追加された従来の Check フィールドで状態を検証しています\n" +
+		"  チェック: {file}:{line}\n" +
+		"  Check を設定するステップ: テスト内 {legacy} 件、{since} 時点 {base} 件\n" +
+		"  提案: 代わりに ConfigStateChecks で状態を検証してください (例: statecheck.ExpectKnownValue(address, tfjsonpath.New(\"name\"), knownvalue.StringExact(\"...\")))。既存の Check フィールドは段階的に移行できます",
+
+	ProviderFactoriesMixed: "テスト '{test}' は {field} でプロバイダーを提供していますが、パッケージ {package} でファクトリーフィールドを設定するテスト {total} 件中 {count} 件は {dominant} を使用しています\n" +
+		"  フィールド: {file}:{line}\n" +
+		"  提案: パッケージのパターン {dominant}: {value} を使用してください。1 つのパッケージでファクトリーの種類が混在すると、通常はパッケージが提供するものとは別のプロバイダーサーバーに対してテストが実行されます",
+
+	ProviderFactoriesMissing: "テスト '{test}' の TestCase はステップ {steps} にプロバイダーを与えていません: TestCase もステップも {fields} のいずれも設定していません\n" +
+		"  TestCase: {file}:{line}\n" +
+		"  提案: TestCase に {suggestion} を設定してください。設定しないと、テストは不足しているフィールドを示さないエラーで実行時に失敗します",
+
+	ErrorCheckMissing: "テスト '{test}' の TestCase は ErrorCheck を設定していませんが、パッケージ {package} の TestCase {total} 件中 {count} 件は設定しています\n" +
+		"  TestCase: {file}:{line}\n" +
+		"  提案: パッケージのヘルパー {helper} で ErrorCheck を設定してください。サービス未対応など、一部のリージョンやパーティションで想定されるエラーが発生したとき、テストは失敗せずスキップされます",
+	ErrorCheckFuncLiteral: "他の TestCase と同様の関数リテラル",
+
+	CheckAddressUndeclared: "テスト '{test}' のステップ {step} の {function} は '{address}' をチェックしていますが、ステップの構成はこれを宣言していません\n" +
+		"  チェック: {file}:{line}\n" +
+		"  宣言済み: {declared}\n" +
+		"  提案: 構成が宣言しているアドレスをチェックするか、構成にブロックを追加してください。そうしないとチェックは失敗するか、別のリソースを検査します",
+	CheckAddressSimilar: "テスト '{test}' のステップ {step} の {function} は '{address}' をチェックしていますが、ステップの構成はこれを宣言していません\n" +
+		"  チェック: {file}:{line}\n" +
+		"  宣言済み: {declared}\n" +
+		"  提案: '{similar}' のことですか?",
+
+	RefreshDriftMissing: "リソース '{name}' には {count} 件のテストがありますが、RefreshState ステップを含むものがないため、リフレッシュ時のドリフト検出が一度も実行されません\n" +
+		"  提案: テストの構成ステップの後に RefreshState: true のステップを追加し、必要に応じて空のプランを確認する RefreshPlanChecks を設定してください",
+	RefreshPlanChecksIgnored: "テスト '{test}' のステップ {step} は RefreshState: true なしで RefreshPlanChecks を設定しているため、チェックは実行されません\n" +
+		"  ステップ: {file}:{line}\n" +
+		"  提案: ステップに RefreshState: true を設定するか、チェックを ConfigPlanChecks に移動してください",
+
+	PlaceholderValue: "テスト '{test}' のステップ {step} は {resourceType} の必須属性 '{attribute}' にプレースホルダー \"{value}\" を設定していますが、バリデーターは {format} を期待しています\n" +
+		"  構成: {file}:{line}\n" +
+		"  提案: {example} のような現実的な値を使用してください。検証をテストするステップであれば ExpectError を追加してください",
+
+	IdempotencyMissing: "リソース '{name}' には永続的な差分が生じやすい Optional かつ Computed の属性 ({attributes}) がありますが、{count} 件のテストのいずれも同じ構成を再度プランして変更がないことを確認していません\n" +
+		"  提案: テストの最後の構成ステップを PlanOnly: true で繰り返すか、同じ構成を再適用するステップの ConfigPlanChecks に plancheck.ExpectEmptyPlan() を追加してください",
+
+	EnvPreCheckMissing: "テスト '{test}' は環境変数 '{env}' を構成に埋め込んでいますが、PreCheck でこれを確認していません\n" +
+		"  構成: {file}:{line}\n" +
+		"  提案: PreCheck で確認してください (例: if os.Getenv(\"{env}\") == \"\" { t.Skip(\"{env} must be set\") })。空の値の構成を適用する代わりにテストがスキップされます",
+
+	DeprecatedReplacementMissing: "テスト '{test}' は {resource} の非推奨属性 '{attribute}' を設定していますが、置き換え先の {replacements} を設定するテストがありません\n" +
+		"  構成: {file}:{line}\n" +
+		"  非推奨: {deprecation}\n" +
+		"  提案: '{attribute}' が削除される前に置き換え先がカバーされるよう、このテストまたは別のテストで {replacements} を設定してください",
+
+	FileSkippedTooLarge: "ファイルは解析されませんでした: {size} KB が max-file-size-kb ({limit} KB) を超えています\n" +
+		"  提案: exclude-patterns でファイルを除外するか、max-file-size-kb を引き上げてください (負の値で制限を解除します)",
+	FileSkippedTooMany: "ファイルは解析されませんでした: パッケージの Go ファイルが max-files ({limit}) を超えています\n" +
+		"  提案: exclude-paths で生成コードのディレクトリを除外するか、max-files を引き上げてください",
+
+	DirConfigInvalid: "ディレクトリ設定は適用されませんでした: {error}\n" +
+		"  提案: ファイルを修正してください。その下の検出結果には上位ディレクトリの設定が使われます",
+}
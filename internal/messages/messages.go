@@ -0,0 +1,252 @@
+// Package messages holds the catalog of user-facing diagnostic messages.
+//
+// Every diagnostic is identified by a stable ID and written as a template with named
+// {placeholders}. Translations live in per-language catalogs; a message missing from a
+// catalog falls back to English so a partial translation never drops a diagnostic.
+package messages
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ID identifies a message in the catalog.
+type ID string
+
+// Language is a catalog language code.
+type Language string
+
+const (
+	// English is the default language and the fallback for missing translations.
+	English Language = "en"
+	// Japanese is the Japanese translation.
+	Japanese Language = "ja"
+)
+
+// Params holds the named values substituted into a message template.
+type Params map[string]any
+
+// Kind labels.
+const (
+	KindResource        ID = "kind.resource"
+	KindResourceTitle   ID = "kind.resource.title"
+	KindDataSource      ID = "kind.data_source"
+	KindDataSourceTitle ID = "kind.data_source.title"
+)
+
+// Diagnostic messages.
+const (
+	BasicTestMissing             ID = "basic_test.missing"
+	BasicTestBelowMinimum        ID = "basic_test.below_minimum"
+	BasicTestQuarantined         ID = "basic_test.quarantined"
+	LinkLowConfidence            ID = "link.low_confidence"
+	LinkSuggestRenameTo          ID = "link.suggest_rename_to"
+	LinkSuggestRenameExplicit    ID = "link.suggest_rename_explicit"
+	UpdateTestMissing            ID = "update_test.missing"
+	ImportTestMissing            ID = "import_test.missing"
+	ImportTestFix                ID = "import_test.fix"
+	ErrorTestMissing             ID = "error_test.missing"
+	ProviderConfigUntested       ID = "provider_config.untested"
+	ProviderConfigUndefined      ID = "provider_config.undefined"
+	ProviderConfigAttrUntested   ID = "provider_config.attribute_untested"
+	ProviderConfigEnvVarFallback ID = "provider_config.env_var_fallback"
+	StateCheckMissing            ID = "state_check.missing"
+	StateCheckWeakTest           ID = "state_check.weak_test"
+	DriftCheckMissing            ID = "drift_check.missing"
+	DriftCheckNotDestroyable     ID = "drift_check.not_destroyable"
+	DriftCheckReasonNoDelete     ID = "drift_check.reason_no_delete"
+	DriftCheckReasonNoOpDelete   ID = "drift_check.reason_noop_delete"
+	SweepersMissing              ID = "sweepers.missing"
+	CoverageExpected             ID = "coverage.expected"
+	CoverageRequired             ID = "coverage.required"
+	DisappearsTestMissing        ID = "disappears_test.missing"
+	ImportStateIdFuncUnknownAttr ID = "import_state_id_func.unknown_attribute"
+	ImportStateVerifyMissing     ID = "import_state_verify.missing"
+	ImportStateCheckNoAssertions ID = "import_state_verify.check_no_assertions"
+	ParallelFixtureClash         ID = "parallel_fixtures.clash"
+	ParallelTestMissing          ID = "parallel_tests.missing"
+	TestMisplaced                ID = "test_placement.misplaced"
+	TestLayoutMisplaced          ID = "test_layout.misplaced"
+	TestLayoutFix                ID = "test_layout.fix"
+	OrphanTest                   ID = "orphan_tests.unlinked"
+	OrphanTestInferred           ID = "orphan_tests.inferred"
+	OrphanTestClosest            ID = "orphan_tests.closest"
+	OrphanTestNoCandidate        ID = "orphan_tests.no_candidate"
+	DefaultValueUntested         ID = "default_values.untested"
+	ProviderHygieneNoTestMain    ID = "provider_hygiene.no_test_main"
+	ProviderHygieneNoDispatch    ID = "provider_hygiene.no_dispatch"
+	ProviderHygieneTestsNotRun   ID = "provider_hygiene.tests_not_run"
+	UnknownType                  ID = "unknown_types.undefined"
+	UnknownTypeClosest           ID = "unknown_types.closest"
+	UnknownTypeNoCandidate       ID = "unknown_types.no_candidate"
+	TestHelperFatalInGoroutine   ID = "test_helpers.fatal_in_goroutine"
+	TestHelperIgnoredError       ID = "test_helpers.ignored_error"
+	DataSourceConfigNoResource   ID = "data_source_config.no_resource"
+	DataSourceConfigNoManaged    ID = "data_source_config.no_managed"
+	DataSourceAssertsTooFew      ID = "data_source_asserts.too_few"
+	ExpectErrorInvalid           ID = "expect_error.invalid"
+	ExpectErrorMatchesAll        ID = "expect_error.matches_all"
+	ExpectErrorGeneric           ID = "expect_error.generic"
+	DeadTestCommentedOut         ID = "dead_tests.commented_out"
+	DeadTestCommentedOutCall     ID = "dead_tests.commented_out_call"
+	DeferredActionsUntested      ID = "deferred_actions.untested"
+	DeferredProviderUntested     ID = "deferred_actions.provider_untested"
+	ProviderAliasTestMissing     ID = "provider_aliases.test_missing"
+	UpgradeTestMissing           ID = "upgrade_test.missing"
+	PairUntested                 ID = "paired_definitions.untested"
+	PluralDataSourceUnsized      ID = "plural_data_sources.unsized"
+	LegacyCheckAdded             ID = "legacy_checks.added"
+	ProviderFactoriesMixed       ID = "provider_factories.mixed"
+	ProviderFactoriesMissing     ID = "provider_factories.missing"
+	ErrorCheckMissing            ID = "error_check.missing"
+	ErrorCheckFuncLiteral        ID = "error_check.func_literal"
+	CheckAddressUndeclared       ID = "check_addresses.undeclared"
+	CheckAddressSimilar          ID = "check_addresses.similar"
+	RefreshDriftMissing          ID = "refresh_drift.missing"
+	RefreshPlanChecksIgnored     ID = "refresh_drift.plan_checks_ignored"
+	IdempotencyMissing           ID = "idempotency.missing"
+	PlaceholderValue             ID = "placeholder_values.placeholder"
+	EnvPreCheckMissing           ID = "env_precheck.missing"
+	DeprecatedReplacementMissing ID = "deprecated_attributes.replacement_untested"
+	FileSkippedTooLarge          ID = "file_limits.too_large"
+	FileSkippedTooMany           ID = "file_limits.too_many"
+	DirConfigInvalid             ID = "dir_config.invalid"
+)
+
+// Directive errors.
+const (
+	DirectiveUnknown       ID = "directive.unknown"
+	DirectiveUnknownCheck  ID = "directive.unknown_check"
+	DirectiveUnknownOption ID = "directive.unknown_option"
+	DirectiveMissingChecks ID = "directive.missing_checks"
+	DirectiveMalformed     ID = "directive.malformed"
+	DirectiveConflict      ID = "directive.conflict"
+	DirectiveInapplicable  ID = "directive.inapplicable"
+	DirectiveUnattached    ID = "directive.unattached"
+	DirectiveUnknownLevel  ID = "directive.unknown_level"
+)
+
+// Requirements manifest problems.
+const (
+	RequirementsInvalid  ID = "requirements.invalid"
+	RequirementsConflict ID = "requirements.conflict"
+	RequirementsUnlisted ID = "requirements.unlisted"
+)
+
+// Hints appended to other messages.
+const (
+	DidYouMean ID = "hint.did_you_mean"
+)
+
+// catalogs maps each supported language to its message templates.
+var catalogs = map[Language]map[ID]string{
+	English:  english,
+	Japanese: japanese,
+}
+
+// Languages returns the supported language codes in sorted order.
+func Languages() []Language {
+	langs := make([]Language, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Slice(langs, func(i, j int) bool { return langs[i] < langs[j] })
+	return langs
+}
+
+// IDs returns every message ID in the catalog in sorted order.
+func IDs() []ID {
+	ids := make([]ID, 0, len(english))
+	for id := range english {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// Translated reports whether a message has a translation in the given language.
+func Translated(lang Language, id ID) bool {
+	_, ok := catalogs[lang][id]
+	return ok
+}
+
+// Supported reports whether a language code has a catalog. The empty string selects English.
+func Supported(lang string) bool {
+	if lang == "" {
+		return true
+	}
+	_, ok := catalogs[Language(lang)]
+	return ok
+}
+
+// Template returns the raw template for a message, falling back to English when the
+// language or the message is not translated. It returns the ID itself for unknown messages.
+func Template(lang string, id ID) string {
+	if catalog, ok := catalogs[Language(lang)]; ok {
+		if tmpl, ok := catalog[id]; ok {
+			return tmpl
+		}
+	}
+	if tmpl, ok := english[id]; ok {
+		return tmpl
+	}
+	return string(id)
+}
+
+// Format renders a message in the given language, replacing each {name} placeholder with
+// the matching parameter. Placeholders without a parameter are left as-is.
+func Format(lang string, id ID, params Params) string {
+	tmpl := Template(lang, id)
+	if len(params) == 0 {
+		return tmpl
+	}
+
+	var sb strings.Builder
+	for {
+		start := strings.IndexByte(tmpl, '{')
+		if start == -1 {
+			break
+		}
+		end := strings.IndexByte(tmpl[start:], '}')
+		if end == -1 {
+			break
+		}
+		end += start
+
+		sb.WriteString(tmpl[:start])
+		if value, ok := params[tmpl[start+1:end]]; ok {
+			sb.WriteString(fmt.Sprint(value))
+		} else {
+			sb.WriteString(tmpl[start : end+1])
+		}
+		tmpl = tmpl[end+1:]
+	}
+	sb.WriteString(tmpl)
+	return sb.String()
+}
+
+// countedNoun matches a count followed by up to three words, the last ending in "(s)", as in
+// "2 test(s)" or "1 more acceptance test(s)".
+var countedNoun = regexp.MustCompile(`\b(\d+)((?: [A-Za-z-]+){1,3})\(s\)`)
+
+// Compact shortens a rendered message to its first line for output that shows one line per
+// diagnostic, such as golangci-lint's. Counted nouns are phrased for their count ("1 test",
+// "2 tests" rather than "test(s)"), and a non-empty rule code is appended as "[TFPT003]".
+func Compact(msg, code string) string {
+	if i := strings.IndexByte(msg, '\n'); i >= 0 {
+		msg = msg[:i]
+	}
+	msg = countedNoun.ReplaceAllStringFunc(strings.TrimSpace(msg), func(match string) string {
+		parts := countedNoun.FindStringSubmatch(match)
+		if parts[1] == "1" {
+			return parts[1] + parts[2]
+		}
+		return parts[1] + parts[2] + "s"
+	})
+	if code != "" {
+		msg += " [" + code + "]"
+	}
+	return msg
+}
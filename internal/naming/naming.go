@@ -0,0 +1,223 @@
+// Package naming canonicalizes CamelCase Go names, such as test function names, into the
+// snake_case names of Terraform definitions. A Pipeline runs these stages in order:
+//
+//  1. Prefix stripping removes the first matching test prefix, such as TestAccDataSource
+//     or TestAcc (StripPrefix)
+//  2. Replacements rewrite CamelCase fragments into fixed snake_case words, such as
+//     "EventStream" -> "eventstream"; an acronym is a replacement of a word by its lower
+//     case, such as "OAuth" -> "oauth"
+//  3. snake_case conversion splits what is left at case changes, keeping runs of capitals
+//     together, so "HTTPServer" becomes "http_server"
+//
+// Stages 2 and 3 make up SnakeCase. The default pipeline has no replacements; providers
+// whose names do not follow the case changes of their Go names, such as "EDAEventStream"
+// for eda_eventstream, configure replacements and acronyms in Settings (name-replacements,
+// name-acronyms).
+package naming
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultPrefixes are the test function prefixes stripped by the default pipeline. More
+// specific prefixes come first, since only the first match is stripped.
+var DefaultPrefixes = []string{
+	"TestAccDataSource",
+	"TestAccResource",
+	"TestAcc",
+	"TestDataSource",
+	"TestResource",
+	"Test",
+}
+
+// snakeWords matches the replacement of a fragment: lowercase snake_case words.
+var snakeWords = regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`)
+
+// Replacement rewrites the CamelCase fragment From into the snake_case words To.
+type Replacement struct {
+	From string // e.g. "EventStream"
+	To   string // e.g. "eventstream"
+}
+
+// Pipeline canonicalizes names. The zero value strips no prefixes and only converts to
+// snake_case; a nil *Pipeline behaves like Default().
+type Pipeline struct {
+	Prefixes     []string      // Tried in order; the first match is stripped
+	Replacements []Replacement // Longest From first, see New
+}
+
+var defaultPipeline = &Pipeline{Prefixes: DefaultPrefixes}
+
+// Default returns the pipeline used without configuration.
+func Default() *Pipeline {
+	return defaultPipeline
+}
+
+// New returns the default pipeline plus replacements, a CamelCase fragment to snake_case
+// mapping such as Settings.NameReplacements, and acronyms, words such as "OAuth" that are
+// kept whole. An acronym is the replacement of the word by its lower case; an explicit
+// replacement of the same fragment wins.
+func New(replacements map[string]string, acronyms []string) (*Pipeline, error) {
+	byFrom := make(map[string]string)
+	for _, acronym := range acronyms {
+		if acronym == "" || !isAlphanumeric(acronym) {
+			return nil, fmt.Errorf("invalid name acronym %q (expected letters and digits, e.g. OAuth)", acronym)
+		}
+		byFrom[acronym] = strings.ToLower(acronym)
+	}
+	for from, to := range replacements {
+		if from == "" || !isAlphanumeric(from) {
+			return nil, fmt.Errorf("invalid name replacement %q (expected a CamelCase fragment, e.g. EventStream)", from)
+		}
+		if !snakeWords.MatchString(to) {
+			return nil, fmt.Errorf("invalid name replacement %q: %q is not snake_case", from, to)
+		}
+		byFrom[from] = to
+	}
+	if len(byFrom) == 0 {
+		return defaultPipeline, nil
+	}
+
+	p := &Pipeline{Prefixes: DefaultPrefixes}
+	for from, to := range byFrom {
+		p.Replacements = append(p.Replacements, Replacement{From: from, To: to})
+	}
+	// Longer fragments first, so "EDAEventStream" is tried before "EventStream"
+	sort.Slice(p.Replacements, func(i, j int) bool {
+		a, b := p.Replacements[i].From, p.Replacements[j].From
+		if len(a) != len(b) {
+			return len(a) > len(b)
+		}
+		return a < b
+	})
+	return p, nil
+}
+
+// StripPrefix removes the first of the pipeline's prefixes that name starts with, and any
+// underscore that follows it: "TestAccDataSourceHTTP_basic" -> "HTTP_basic".
+func (p *Pipeline) StripPrefix(name string) string {
+	if p == nil {
+		p = defaultPipeline
+	}
+	for _, prefix := range p.Prefixes {
+		if strings.HasPrefix(name, prefix) {
+			name = strings.TrimPrefix(name, prefix)
+			break
+		}
+	}
+	return strings.TrimPrefix(name, "_")
+}
+
+// SnakeCase converts a CamelCase name to snake_case, applying the pipeline's replacements
+// first: with the replacement "EventStream" -> "eventstream", "EDAEventStream" becomes
+// "eda_eventstream" rather than "eda_event_stream".
+//
+// A replacement only matches a whole word: it starts the name or at an uppercase letter,
+// digit or underscore, and is not followed by a lowercase letter.
+func (p *Pipeline) SnakeCase(name string) string {
+	if p == nil || len(p.Replacements) == 0 {
+		return toSnakeCase(name)
+	}
+	var words []string
+	var pending strings.Builder
+	flush := func() {
+		if pending.Len() > 0 {
+			words = append(words, toSnakeCase(pending.String()))
+			pending.Reset()
+		}
+	}
+	for i := 0; i < len(name); {
+		if r, ok := p.replacementAt(name, i); ok {
+			flush()
+			words = append(words, r.To)
+			i += len(r.From)
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(name[i:])
+		pending.WriteString(name[i : i+size])
+		i += size
+	}
+	flush()
+
+	var out []string
+	for _, word := range words {
+		if word = strings.Trim(word, "_"); word != "" {
+			out = append(out, word)
+		}
+	}
+	return strings.Join(out, "_")
+}
+
+// replacementAt returns the replacement matching a whole word at byte offset i of name.
+func (p *Pipeline) replacementAt(name string, i int) (Replacement, bool) {
+	if i > 0 {
+		r, _ := utf8.DecodeRuneInString(name[i:])
+		if !unicode.IsUpper(r) && !unicode.IsDigit(r) && name[i-1] != '_' {
+			return Replacement{}, false
+		}
+	}
+	for _, r := range p.Replacements {
+		if !strings.HasPrefix(name[i:], r.From) {
+			continue
+		}
+		if next, _ := utf8.DecodeRuneInString(name[i+len(r.From):]); unicode.IsLower(next) {
+			continue
+		}
+		return r, true
+	}
+	return Replacement{}, false
+}
+
+// toSnakeCase converts CamelCase to snake_case (e.g., "MyResource" -> "my_resource")
+func toSnakeCase(s string) string {
+	var result strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			// Add underscore before uppercase if:
+			// 1. Previous char is lowercase, OR
+			// 2. Next char exists and is lowercase (handles acronyms like "HTTPServer" -> "http_server")
+			prev := runes[i-1]
+			if unicode.IsLower(prev) || (i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				result.WriteRune('_')
+			}
+		}
+		result.WriteRune(unicode.ToLower(r))
+	}
+	return result.String()
+}
+
+// TitleCase converts snake_case to TitleCase (e.g., "my_resource" -> "MyResource"), the
+// Go type name a definition name is usually derived from
+func TitleCase(s string) string {
+	var result strings.Builder
+	capitalizeNext := true
+	for _, r := range s {
+		if r == '_' {
+			capitalizeNext = true
+			continue
+		}
+		if capitalizeNext {
+			result.WriteRune(unicode.ToUpper(r))
+			capitalizeNext = false
+		} else {
+			result.WriteRune(r)
+		}
+	}
+	return result.String()
+}
+
+// isAlphanumeric reports whether s only has letters and digits.
+func isAlphanumeric(s string) bool {
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,200 @@
+// Package plan builds a work plan for reaching a coverage goal: the fewest untested
+// definitions to cover so that the share of definitions with tests reaches a target,
+// chosen and ordered by priority.
+//
+// A definition's priority is a weighted sum of three signals, each between 0 and 1:
+//   - recent: how recently its file was last committed, halving every RecencyHalfLife
+//   - attributes: its attribute count relative to the largest untested definition
+//   - tag: whether it carries the customer-facing maturity level (ga by default)
+package plan
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/gitmeta"
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// RecencyHalfLife is the age at which the recent signal of a definition drops to one half.
+const RecencyHalfLife = 90 * 24 * time.Hour
+
+// Weights are the multipliers of the priority signals.
+type Weights struct {
+	Recent     float64 `json:"recent"`
+	Attributes float64 `json:"attributes"`
+	Tag        float64 `json:"tag"`
+}
+
+// DefaultWeights weighs all signals equally.
+func DefaultWeights() Weights {
+	return Weights{Recent: 1, Attributes: 1, Tag: 1}
+}
+
+// ParseWeights parses a comma-separated list such as "recent=2,tag=3" over the default
+// weights. Signals that are not listed keep their default weight.
+func ParseWeights(spec string) (Weights, error) {
+	weights := DefaultWeights()
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return weights, fmt.Errorf("invalid weight %q: expected name=value", part)
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || weight < 0 {
+			return weights, fmt.Errorf("invalid weight %q: value must be a non-negative number", part)
+		}
+		switch strings.TrimSpace(name) {
+		case "recent":
+			weights.Recent = weight
+		case "attributes":
+			weights.Attributes = weight
+		case "tag":
+			weights.Tag = weight
+		default:
+			return weights, fmt.Errorf("invalid weight %q: unknown signal (want recent, attributes, or tag)", part)
+		}
+	}
+	return weights, nil
+}
+
+// ParseTarget parses a coverage target written as a percentage, with or without the percent
+// sign ("80%", "80"), and returns it as a fraction.
+func ParseTarget(spec string) (float64, error) {
+	value, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(spec), "%"), 64)
+	if err != nil || value <= 0 || value > 100 {
+		return 0, fmt.Errorf("invalid coverage target %q: expected a percentage between 0 and 100, e.g. 80%%", spec)
+	}
+	return value / 100, nil
+}
+
+// Options configure Build.
+type Options struct {
+	// Target is the coverage goal as a fraction of all definitions.
+	Target  float64
+	Weights Weights
+	// Tag is the maturity level that marks customer-facing definitions.
+	Tag string
+	// LastModified returns the last commit time of a file; nil disables the recent signal.
+	LastModified gitmeta.LastModifiedFunc
+	Now          time.Time
+}
+
+// Plan is the ordered work plan for reaching a coverage target.
+type Plan struct {
+	Target   float64 `json:"target"`
+	Total    int     `json:"total"`
+	Tested   int     `json:"tested"`
+	Coverage float64 `json:"coverage"`
+	// Needed is the number of definitions to cover; it is 0 when the target is already met.
+	Needed  int     `json:"needed"`
+	Weights Weights `json:"weights"`
+	Steps   []Step  `json:"steps"`
+}
+
+// Step is one definition to cover, in plan order.
+type Step struct {
+	Name       string  `json:"name"`
+	Kind       string  `json:"kind"`
+	File       string  `json:"file"`
+	Priority   float64 `json:"priority"`
+	Attributes int     `json:"attributes"`
+	// AgeDays is the age of the definition's file in days, or -1 when it is unknown.
+	AgeDays       int     `json:"age_days"`
+	Tagged        bool    `json:"tagged"`
+	SuggestedTest string  `json:"suggested_test"`
+	SuggestedFile string  `json:"suggested_file"`
+	CoverageAfter float64 `json:"coverage_after"` // Coverage once this and the earlier steps are done
+}
+
+// Build selects the untested definitions with the highest priority until covering them
+// reaches the target, and returns them in that order. Ties go to the name that sorts first.
+func Build(reg *registry.ResourceRegistry, opts Options) Plan {
+	plan := Plan{Target: opts.Target, Weights: opts.Weights, Steps: []Step{}}
+
+	var untested []*registry.ResourceInfo
+	for _, info := range reg.GetSortedDefinitions() {
+		plan.Total++
+		if len(reg.GetTests(info.Kind, info.Name)) > 0 {
+			plan.Tested++
+		} else {
+			untested = append(untested, info)
+		}
+	}
+	if plan.Total == 0 {
+		return plan
+	}
+	plan.Coverage = float64(plan.Tested) / float64(plan.Total)
+
+	// A small tolerance keeps targets such as 70% of 10 definitions from rounding up to 8
+	plan.Needed = int(math.Ceil(opts.Target*float64(plan.Total)-1e-9)) - plan.Tested
+	if plan.Needed <= 0 {
+		plan.Needed = 0
+		return plan
+	}
+
+	maxAttributes := 0
+	for _, info := range untested {
+		if len(info.Attributes) > maxAttributes {
+			maxAttributes = len(info.Attributes)
+		}
+	}
+
+	steps := make([]Step, 0, len(untested))
+	for _, info := range untested {
+		step := Step{
+			Name:          info.Name,
+			Kind:          info.Kind.String(),
+			File:          info.FilePath,
+			Attributes:    len(info.Attributes),
+			AgeDays:       -1,
+			Tagged:        opts.Tag != "" && info.Maturity == opts.Tag,
+			SuggestedTest: analysis.BuildExpectedTestFunc(info),
+			SuggestedFile: analysis.BuildExpectedTestPath(info),
+		}
+		if opts.LastModified != nil {
+			if modified, ok := opts.LastModified(info.FilePath); ok {
+				age := opts.Now.Sub(modified)
+				if age < 0 {
+					age = 0
+				}
+				step.AgeDays = int(age / (24 * time.Hour))
+				step.Priority += opts.Weights.Recent * math.Pow(0.5, float64(age)/float64(RecencyHalfLife))
+			}
+		}
+		if maxAttributes > 0 {
+			step.Priority += opts.Weights.Attributes * float64(step.Attributes) / float64(maxAttributes)
+		}
+		if step.Tagged {
+			step.Priority += opts.Weights.Tag
+		}
+		steps = append(steps, step)
+	}
+	sort.SliceStable(steps, func(i, j int) bool {
+		if steps[i].Priority != steps[j].Priority {
+			return steps[i].Priority > steps[j].Priority
+		}
+		if steps[i].Name != steps[j].Name {
+			return steps[i].Name < steps[j].Name
+		}
+		return steps[i].Kind < steps[j].Kind
+	})
+
+	if plan.Needed > len(steps) {
+		plan.Needed = len(steps)
+	}
+	for i, step := range steps[:plan.Needed] {
+		step.CoverageAfter = float64(plan.Tested+i+1) / float64(plan.Total)
+		plan.Steps = append(plan.Steps, step)
+	}
+	return plan
+}
@@ -0,0 +1,231 @@
+// Package prsuggest builds the suggestions a bot decorating provider pull requests applies:
+// labels, a title prefix, a checklist of the acceptance test patterns missing for the
+// definitions the change touches, and a command running the acceptance tests of those
+// definitions. A definition is touched when the change modifies its source file or the file
+// of one of its tests.
+package prsuggest
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/enforcement"
+)
+
+// Version is the version of the suggestions format.
+const Version = 1
+
+// Labels suggested for the pull request.
+const (
+	LabelMissingTests    = "missing-acceptance-tests"    // A touched definition has no test
+	LabelIncompleteTests = "incomplete-acceptance-tests" // A touched definition's tests have findings
+)
+
+// Title prefixes suggested for the pull request, by its most severe gap.
+const (
+	TitleMissingTests    = "[missing acceptance tests]"
+	TitleIncompleteTests = "[incomplete acceptance tests]"
+)
+
+// Definition is a definition with its tests and findings. Paths are relative to the root of
+// the repository, with forward slashes.
+type Definition struct {
+	Kind string // e.g. "data source"
+	Name string
+	File string
+	// ExpectedTest and ExpectedTestFile are where a conventional test would be
+	ExpectedTest     string
+	ExpectedTestFile string
+	Tests            []Test
+	Findings         []Finding
+}
+
+// Test is a test function linked to a definition.
+type Test struct {
+	Name string
+	File string
+}
+
+// Finding is a finding attributed to a definition.
+type Finding struct {
+	Rule    string
+	Code    string
+	Level   string
+	File    string
+	Line    int
+	Message string
+}
+
+// Suggestions is the content of a suggestions file.
+type Suggestions struct {
+	Version int    `json:"version"`
+	Since   string `json:"since"` // Git ref the change is compared with
+	// TitlePrefix is empty when every touched definition is tested without findings
+	TitlePrefix string   `json:"title_prefix,omitempty"`
+	Labels      []string `json:"labels"`
+	Body        string   `json:"body"` // Markdown checklist
+	// TestCommand runs the tests of the touched definitions, from the provider directory
+	TestCommand string    `json:"test_command,omitempty"`
+	Definitions []Touched `json:"definitions"`
+}
+
+// Touched is a definition the change touches.
+type Touched struct {
+	Kind    string   `json:"kind"`
+	Name    string   `json:"name"`
+	File    string   `json:"file"`
+	Tests   []string `json:"tests"`
+	Missing []string `json:"missing"` // Checklist items, empty when nothing is missing
+}
+
+// Build returns the suggestions for the definitions of defs whose file, or the file of one
+// of their tests, is in changed. providerDir is the provider directory relative to the
+// repository root ("" or "." for the root), which the test command runs from. Definitions
+// are ordered by kind and name, so an unchanged pull request gets identical suggestions.
+func Build(defs []Definition, changed []string, since, providerDir string) Suggestions {
+	isChanged := make(map[string]bool, len(changed))
+	for _, file := range changed {
+		isChanged[file] = true
+	}
+	var touched []Definition
+	for _, def := range defs {
+		if isChanged[def.File] || testsChanged(def.Tests, isChanged) {
+			touched = append(touched, def)
+		}
+	}
+	sort.Slice(touched, func(i, j int) bool {
+		if touched[i].Kind != touched[j].Kind {
+			return touched[i].Kind < touched[j].Kind
+		}
+		return touched[i].Name < touched[j].Name
+	})
+
+	s := Suggestions{Version: Version, Since: since, Labels: []string{}, Definitions: make([]Touched, 0, len(touched))}
+	var untested, incomplete int
+	for _, def := range touched {
+		entry := Touched{Kind: def.Kind, Name: def.Name, File: def.File, Tests: []string{}, Missing: missing(def)}
+		for _, test := range def.Tests {
+			entry.Tests = append(entry.Tests, test.Name)
+		}
+		switch {
+		case len(def.Tests) == 0:
+			untested++
+		case len(entry.Missing) > 0:
+			incomplete++
+		}
+		s.Definitions = append(s.Definitions, entry)
+	}
+	if untested > 0 {
+		s.Labels = append(s.Labels, LabelMissingTests)
+		s.TitlePrefix = TitleMissingTests
+	}
+	if incomplete > 0 {
+		s.Labels = append(s.Labels, LabelIncompleteTests)
+		if s.TitlePrefix == "" {
+			s.TitlePrefix = TitleIncompleteTests
+		}
+	}
+	s.TestCommand = testCommand(touched, providerDir)
+	s.Body = body(s, untested, incomplete)
+	return s
+}
+
+func testsChanged(tests []Test, isChanged map[string]bool) bool {
+	for _, test := range tests {
+		if isChanged[test.File] {
+			return true
+		}
+	}
+	return false
+}
+
+// missing returns the checklist items of a definition: adding a test when it has none, and
+// otherwise the summary line of each of its findings.
+func missing(def Definition) []string {
+	if len(def.Tests) == 0 {
+		return []string{fmt.Sprintf("add an acceptance test, e.g. %s in %s", def.ExpectedTest, def.ExpectedTestFile)}
+	}
+	findings := append([]Finding(nil), def.Findings...)
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+		return findings[i].Line < findings[j].Line
+	})
+	items := []string{}
+	seen := make(map[string]bool)
+	for _, f := range findings {
+		summary, _, _ := strings.Cut(f.Message, "\n")
+		summary = strings.TrimPrefix(summary, enforcement.WarningPrefix)
+		if f.Code != "" {
+			summary += " (" + f.Code + ")"
+		}
+		if !seen[summary] {
+			seen[summary] = true
+			items = append(items, summary)
+		}
+	}
+	return items
+}
+
+// testCommand returns a go test command running the tests of defs with TF_ACC set, or ""
+// when none has tests.
+func testCommand(defs []Definition, providerDir string) string {
+	dirs := make(map[string]bool)
+	names := make(map[string]bool)
+	for _, def := range defs {
+		for _, test := range def.Tests {
+			dirs[packageDir(test.File, providerDir)] = true
+			names[test.Name] = true
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("TF_ACC=1 go test %s -run '^(%s)$' -v", strings.Join(slices.Sorted(maps.Keys(dirs)), " "), strings.Join(slices.Sorted(maps.Keys(names)), "|"))
+}
+
+// packageDir returns the directory of file as a go test package pattern relative to
+// providerDir, e.g. "./internal/widget".
+func packageDir(file, providerDir string) string {
+	dir := path.Dir(file)
+	if providerDir != "" && providerDir != "." {
+		if rel, ok := strings.CutPrefix(dir, providerDir); ok && (rel == "" || rel[0] == '/') {
+			dir = strings.TrimPrefix(rel, "/")
+		}
+	}
+	if dir == "" || dir == "." {
+		return "."
+	}
+	return "./" + dir
+}
+
+// body returns the Markdown body of s: a summary line, then a checklist with an item per
+// missing pattern, checked for definitions with nothing missing, and the test command.
+func body(s Suggestions, untested, incomplete int) string {
+	var b strings.Builder
+	b.WriteString("### Acceptance test coverage\n\n")
+	if len(s.Definitions) == 0 {
+		fmt.Fprintf(&b, "The changes since `%s` touch no resources, data sources or actions.\n", s.Since)
+		return b.String()
+	}
+	fmt.Fprintf(&b, "The changes since `%s` touch %d definition(s): %d without tests, %d with incomplete tests.\n\n",
+		s.Since, len(s.Definitions), untested, incomplete)
+	for _, def := range s.Definitions {
+		if len(def.Missing) == 0 {
+			fmt.Fprintf(&b, "- [x] %s `%s`: tested by %s\n", def.Kind, def.Name, strings.Join(def.Tests, ", "))
+			continue
+		}
+		for _, item := range def.Missing {
+			fmt.Fprintf(&b, "- [ ] %s `%s`: %s\n", def.Kind, def.Name, item)
+		}
+	}
+	if s.TestCommand != "" {
+		fmt.Fprintf(&b, "\nRun the acceptance tests of these definitions with:\n\n```sh\n%s\n```\n", s.TestCommand)
+	}
+	return b.String()
+}
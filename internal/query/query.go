@@ -0,0 +1,644 @@
+// Package query evaluates jq-like expressions over decoded JSON, so CI scripts can pull a
+// single number out of a report without an external jq:
+//
+//	.summary.untested_resources
+//	.resources[] | select(.test_count == 0) | .name
+//	[.resources[] | select(.has_import_test | not)] | length
+//
+// The supported subset is paths (.a.b, .a[0], .a[-1], .a[], .a["key"]), pipes, array
+// construction ([...]), parentheses, literals, the comparisons == != < <= > >=, and, or, and
+// the functions length, keys, first, last, not and select(cond). JSONPath's $ root and [*]
+// wildcard are accepted as spellings of . and [].
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Query is a parsed expression.
+type Query struct {
+	expr string
+	root node
+}
+
+// Parse parses expr.
+func Parse(expr string) (*Query, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, fmt.Errorf("query %q: %w", expr, err)
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.pipeline()
+	if err == nil && p.peek().kind != tokEOF {
+		err = fmt.Errorf("unexpected %s", p.peek())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query %q: %w", expr, err)
+	}
+	return &Query{expr: expr, root: root}, nil
+}
+
+// String returns the expression the query was parsed from.
+func (q *Query) String() string { return q.expr }
+
+// Eval evaluates the query against input, a value decoded by encoding/json into any, and
+// returns the values it produces.
+func (q *Query) Eval(input any) ([]any, error) {
+	out, err := q.root.eval(input)
+	if err != nil {
+		return nil, fmt.Errorf("query %q: %w", q.expr, err)
+	}
+	return out, nil
+}
+
+// Format renders a result the way jq -r does: strings unquoted, everything else as JSON,
+// with objects and arrays indented.
+func Format(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	var data []byte
+	switch v.(type) {
+	case map[string]any, []any:
+		data, _ = json.MarshalIndent(v, "", "  ")
+	default:
+		data, _ = json.Marshal(v)
+	}
+	return string(data)
+}
+
+// Tokens
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokPunct
+	tokIdent
+	tokNumber
+	tokString
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return strconv.Quote(t.text)
+	}
+	return "'" + t.text + "'"
+}
+
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(expr[i:], "=="), strings.HasPrefix(expr[i:], "!="),
+			strings.HasPrefix(expr[i:], "<="), strings.HasPrefix(expr[i:], ">="):
+			tokens = append(tokens, token{kind: tokPunct, text: expr[i : i+2]})
+			i += 2
+		case strings.ContainsRune(".[]|()<>$*,", rune(c)):
+			tokens = append(tokens, token{kind: tokPunct, text: string(c)})
+			i++
+		case c == '"':
+			end := i + 1
+			for end < len(expr) && expr[end] != '"' {
+				if expr[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(expr) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			s, err := strconv.Unquote(expr[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string %s", expr[i:end+1])
+			}
+			tokens = append(tokens, token{kind: tokString, text: s})
+			i = end + 1
+		case c == '-' || (c >= '0' && c <= '9'):
+			end := i + 1
+			for end < len(expr) && (expr[end] >= '0' && expr[end] <= '9' || expr[end] == '.' || expr[end] == 'e' || expr[end] == 'E') {
+				end++
+			}
+			n, err := strconv.ParseFloat(expr[i:end], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q", expr[i:end])
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: expr[i:end], num: n})
+			i = end
+		case c == '_' || unicode.IsLetter(rune(c)):
+			end := i + 1
+			for end < len(expr) && (expr[end] == '_' || unicode.IsLetter(rune(expr[end])) || unicode.IsDigit(rune(expr[end]))) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: expr[i:end]})
+			i = end
+		default:
+			r, _ := utf8.DecodeRuneInString(expr[i:])
+			return nil, fmt.Errorf("unexpected character %q", r)
+		}
+	}
+	return append(tokens, token{kind: tokEOF}), nil
+}
+
+// Parser
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token when it is the punctuation or keyword text.
+func (p *parser) accept(text string) bool {
+	t := p.peek()
+	if (t.kind == tokPunct || t.kind == tokIdent) && t.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.accept(text) {
+		return fmt.Errorf("expected '%s', found %s", text, p.peek())
+	}
+	return nil
+}
+
+// pipeline := or ('|' or)*
+func (p *parser) pipeline() (node, error) {
+	left, err := p.or()
+	for err == nil && p.accept("|") {
+		var right node
+		if right, err = p.or(); err == nil {
+			left = pipe{left, right}
+		}
+	}
+	return left, err
+}
+
+// or := and ('or' and)*
+func (p *parser) or() (node, error) {
+	left, err := p.and()
+	for err == nil && p.accept("or") {
+		var right node
+		if right, err = p.and(); err == nil {
+			left = logical{op: "or", left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+// and := comparison ('and' comparison)*
+func (p *parser) and() (node, error) {
+	left, err := p.comparison()
+	for err == nil && p.accept("and") {
+		var right node
+		if right, err = p.comparison(); err == nil {
+			left = logical{op: "and", left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+// comparison := postfix (op postfix)?
+func (p *parser) comparison() (node, error) {
+	left, err := p.postfix()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.accept(op) {
+			right, err := p.postfix()
+			if err != nil {
+				return nil, err
+			}
+			return compare{op: op, left: left, right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+// postfix := primary suffix*
+func (p *parser) postfix() (node, error) {
+	n, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.peek().text == "." && p.peek().kind == tokPunct && p.isKey(p.pos+1):
+			p.next()
+			n = pipe{n, field{p.next().text}}
+		case p.peek().text == "[" && p.peek().kind == tokPunct:
+			p.next()
+			suffix, err := p.bracket()
+			if err != nil {
+				return nil, err
+			}
+			n = pipe{n, suffix}
+		default:
+			return n, nil
+		}
+	}
+}
+
+// isKey reports whether the token at i names an object key after a dot.
+func (p *parser) isKey(i int) bool {
+	t := p.tokens[i]
+	return t.kind == tokIdent || t.kind == tokString
+}
+
+// bracket parses what follows '[' in a suffix: ']', '*]', an index or a key.
+func (p *parser) bracket() (node, error) {
+	if p.accept("]") {
+		return iterate{}, nil
+	}
+	if p.accept("*") {
+		return iterate{}, p.expect("]")
+	}
+	t := p.next()
+	var n node
+	switch t.kind {
+	case tokNumber:
+		if t.num != math.Trunc(t.num) {
+			return nil, fmt.Errorf("invalid index %s", t.text)
+		}
+		n = index{int(t.num)}
+	case tokString:
+		n = field{t.text}
+	default:
+		return nil, fmt.Errorf("expected an index or key, found %s", t)
+	}
+	return n, p.expect("]")
+}
+
+func (p *parser) primary() (node, error) {
+	t := p.next()
+	switch {
+	case t.kind == tokPunct && t.text == ".":
+		if p.isKey(p.pos) {
+			return field{p.next().text}, nil
+		}
+		return identity{}, nil
+	case t.kind == tokPunct && t.text == "$":
+		if p.peek().text == "." && p.peek().kind == tokPunct && p.isKey(p.pos+1) {
+			p.next()
+			return field{p.next().text}, nil
+		}
+		return identity{}, nil
+	case t.kind == tokPunct && t.text == "[":
+		if p.accept("]") {
+			return literal{[]any{}}, nil
+		}
+		inner, err := p.pipeline()
+		if err != nil {
+			return nil, err
+		}
+		return collect{inner}, p.expect("]")
+	case t.kind == tokPunct && t.text == "(":
+		inner, err := p.pipeline()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(")")
+	case t.kind == tokNumber:
+		return literal{t.num}, nil
+	case t.kind == tokString:
+		return literal{t.text}, nil
+	case t.kind == tokIdent:
+		return p.call(t.text)
+	}
+	return nil, fmt.Errorf("unexpected %s", t)
+}
+
+// call parses a literal keyword or a function named name.
+func (p *parser) call(name string) (node, error) {
+	switch name {
+	case "true":
+		return literal{true}, nil
+	case "false":
+		return literal{false}, nil
+	case "null":
+		return literal{nil}, nil
+	case "length", "keys", "first", "last", "not":
+		return function{name: name}, nil
+	case "select":
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		cond, err := p.pipeline()
+		if err != nil {
+			return nil, err
+		}
+		return selectNode{cond}, p.expect(")")
+	}
+	return nil, fmt.Errorf("unknown function %s", name)
+}
+
+// Evaluation
+
+type node interface {
+	eval(input any) ([]any, error)
+}
+
+type identity struct{}
+
+func (identity) eval(input any) ([]any, error) { return []any{input}, nil }
+
+type literal struct{ value any }
+
+func (n literal) eval(any) ([]any, error) { return []any{n.value}, nil }
+
+type pipe struct{ left, right node }
+
+func (n pipe) eval(input any) ([]any, error) {
+	values, err := n.left.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	var out []any
+	for _, v := range values {
+		results, err := n.right.eval(v)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, results...)
+	}
+	return out, nil
+}
+
+type field struct{ name string }
+
+func (n field) eval(input any) ([]any, error) {
+	switch v := input.(type) {
+	case nil:
+		return []any{nil}, nil
+	case map[string]any:
+		return []any{v[n.name]}, nil
+	}
+	return nil, fmt.Errorf("cannot index %s with %q", typeName(input), n.name)
+}
+
+type index struct{ i int }
+
+func (n index) eval(input any) ([]any, error) {
+	switch v := input.(type) {
+	case nil:
+		return []any{nil}, nil
+	case []any:
+		i := n.i
+		if i < 0 {
+			i += len(v)
+		}
+		if i < 0 || i >= len(v) {
+			return []any{nil}, nil
+		}
+		return []any{v[i]}, nil
+	}
+	return nil, fmt.Errorf("cannot index %s with %d", typeName(input), n.i)
+}
+
+type iterate struct{}
+
+func (iterate) eval(input any) ([]any, error) {
+	switch v := input.(type) {
+	case []any:
+		return v, nil
+	case map[string]any:
+		out := make([]any, 0, len(v))
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			out = append(out, v[key])
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("cannot iterate over %s", typeName(input))
+}
+
+type collect struct{ inner node }
+
+func (n collect) eval(input any) ([]any, error) {
+	values, err := n.inner.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	if values == nil {
+		values = []any{}
+	}
+	return []any{values}, nil
+}
+
+type selectNode struct{ cond node }
+
+func (n selectNode) eval(input any) ([]any, error) {
+	results, err := n.cond.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range results {
+		if truthy(r) {
+			return []any{input}, nil
+		}
+	}
+	return nil, nil
+}
+
+type logical struct {
+	op          string
+	left, right node
+}
+
+func (n logical) eval(input any) ([]any, error) {
+	lefts, err := n.left.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	var out []any
+	for _, l := range lefts {
+		if n.op == "and" && !truthy(l) || n.op == "or" && truthy(l) {
+			out = append(out, n.op == "or")
+			continue
+		}
+		rights, err := n.right.eval(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rights {
+			out = append(out, truthy(r))
+		}
+	}
+	return out, nil
+}
+
+type compare struct {
+	op          string
+	left, right node
+}
+
+func (n compare) eval(input any) ([]any, error) {
+	lefts, err := n.left.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	rights, err := n.right.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	var out []any
+	for _, l := range lefts {
+		for _, r := range rights {
+			result, err := compareValues(n.op, l, r)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, result)
+		}
+	}
+	return out, nil
+}
+
+func compareValues(op string, l, r any) (bool, error) {
+	switch op {
+	case "==":
+		return reflect.DeepEqual(l, r), nil
+	case "!=":
+		return !reflect.DeepEqual(l, r), nil
+	}
+	var c int
+	switch lv := l.(type) {
+	case float64:
+		rv, ok := r.(float64)
+		if !ok {
+			return false, fmt.Errorf("cannot compare %s with %s", typeName(l), typeName(r))
+		}
+		c = cmpOrdered(lv, rv)
+	case string:
+		rv, ok := r.(string)
+		if !ok {
+			return false, fmt.Errorf("cannot compare %s with %s", typeName(l), typeName(r))
+		}
+		c = strings.Compare(lv, rv)
+	default:
+		return false, fmt.Errorf("cannot compare %s with %s", typeName(l), typeName(r))
+	}
+	switch op {
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	}
+	return c >= 0, nil
+}
+
+func cmpOrdered(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+type function struct{ name string }
+
+func (n function) eval(input any) ([]any, error) {
+	switch n.name {
+	case "not":
+		return []any{!truthy(input)}, nil
+	case "length":
+		switch v := input.(type) {
+		case nil:
+			return []any{float64(0)}, nil
+		case string:
+			return []any{float64(utf8.RuneCountInString(v))}, nil
+		case []any:
+			return []any{float64(len(v))}, nil
+		case map[string]any:
+			return []any{float64(len(v))}, nil
+		case float64:
+			return []any{math.Abs(v)}, nil
+		}
+	case "keys":
+		switch v := input.(type) {
+		case map[string]any:
+			keys := make([]any, 0, len(v))
+			for _, key := range slices.Sorted(maps.Keys(v)) {
+				keys = append(keys, key)
+			}
+			return []any{keys}, nil
+		case []any:
+			keys := make([]any, len(v))
+			for i := range v {
+				keys[i] = float64(i)
+			}
+			return []any{keys}, nil
+		}
+	case "first", "last":
+		if v, ok := input.([]any); ok {
+			i := 0
+			if n.name == "last" {
+				i = len(v) - 1
+			}
+			return index{i}.eval(v)
+		}
+		if input == nil {
+			return []any{nil}, nil
+		}
+	}
+	return nil, fmt.Errorf("%s has no %s", typeName(input), n.name)
+}
+
+func truthy(v any) bool {
+	return v != nil && v != false
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,171 @@
+package registry
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// RegistryDiff describes how one registry differs from another, for example between two
+// commits of a provider. All slices are sorted by definition key, then check or test name.
+type RegistryDiff struct {
+	Added    []ResourceKey        // Definitions only in the new registry
+	Removed  []ResourceKey        // Definitions only in the old registry
+	Coverage []CoverageTransition // Checks that became covered or uncovered on definitions in both
+	Links    []LinkChange         // Tests linked, unlinked or relinked with another match type
+}
+
+// CoverageTransition is a coverage check of a definition whose state differs between two
+// registries. Checks are the names used by //tftest: directives (CheckBasic, CheckUpdate...).
+type CoverageTransition struct {
+	Key    ResourceKey
+	Check  string
+	Before bool
+	After  bool
+}
+
+// LinkChange is a test whose link to a definition differs between two registries. Before is
+// MatchTypeNone for a new link and After is MatchTypeNone for a removed one.
+type LinkChange struct {
+	Key    ResourceKey
+	Test   string
+	Before MatchType
+	After  MatchType
+}
+
+// Empty reports whether the registries have the same definitions, coverage and links.
+func (d RegistryDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Coverage) == 0 && len(d.Links) == 0
+}
+
+// Diff compares two registries. Coverage transitions and link changes are only reported for
+// definitions present in both; tests of added or removed definitions are implied by Added
+// and Removed. Tests are identified by function name.
+func Diff(oldReg, newReg *ResourceRegistry) RegistryDiff {
+	var diff RegistryDiff
+
+	oldDefs := oldReg.GetAllDefinitions()
+	newDefs := newReg.GetAllDefinitions()
+	for key, info := range newDefs {
+		if _, ok := oldDefs[key]; !ok {
+			diff.Added = append(diff.Added, info.Key())
+		}
+	}
+	for key, info := range oldDefs {
+		if _, ok := newDefs[key]; !ok {
+			diff.Removed = append(diff.Removed, info.Key())
+		}
+	}
+
+	for _, info := range newReg.GetSortedDefinitions() {
+		if _, ok := oldDefs[info.Key().String()]; !ok {
+			continue
+		}
+		oldTests := oldReg.GetTests(info.Kind, info.Name)
+		newTests := newReg.GetTests(info.Kind, info.Name)
+
+		before := CoveredChecks(oldTests)
+		after := CoveredChecks(newTests)
+		for _, check := range DirectiveChecks() {
+			if CheckApplies(info.Kind, check) && before[check] != after[check] {
+				diff.Coverage = append(diff.Coverage, CoverageTransition{
+					Key:    info.Key(),
+					Check:  check,
+					Before: before[check],
+					After:  after[check],
+				})
+			}
+		}
+
+		diff.Links = append(diff.Links, linkChanges(info.Key(), oldTests, newTests)...)
+	}
+
+	// In key string order, as GetSortedDefinitions orders Coverage and Links
+	byKey := func(a, b ResourceKey) int { return strings.Compare(a.String(), b.String()) }
+	slices.SortFunc(diff.Added, byKey)
+	slices.SortFunc(diff.Removed, byKey)
+	return diff
+}
+
+// CoveredChecks returns the directive checks the tests linked to a definition cover. The
+// managed-config check depends on the provider's other definitions and is never included.
+func CoveredChecks(tests []*TestFunctionInfo) map[string]bool {
+	covered := make(map[string]bool)
+	for _, t := range tests {
+		covered[CheckBasic] = true
+		if t.HasImportStep {
+			covered[CheckImport] = true
+		}
+		if t.HasErrorCase {
+			covered[CheckError] = true
+		}
+		if t.HasCheckDestroy {
+			covered[CheckDrift] = true
+		}
+		if t.ChecksDisappears {
+			covered[CheckDisappears] = true
+		}
+		if t.HasStateOrPlanCheck() {
+			covered[CheckStateCheck] = true
+		}
+		if t.ExercisesRefresh() {
+			covered[CheckRefresh] = true
+		}
+		if t.ExercisesIdempotency() {
+			covered[CheckIdempotency] = true
+		}
+		for i := range t.TestSteps {
+			if t.TestSteps[i].IsRealUpdateStep() {
+				covered[CheckUpdate] = true
+			}
+		}
+	}
+	return covered
+}
+
+// UniqueChecks returns the checks, in DirectiveChecks order, that test alone covers among
+// the tests linked to a definition of kind: removing or skipping it would uncover them. A
+// test with unique checks is load-bearing.
+func UniqueChecks(kind ResourceKind, tests []*TestFunctionInfo, test *TestFunctionInfo) []string {
+	others := make([]*TestFunctionInfo, 0, len(tests))
+	for _, t := range tests {
+		if t != test {
+			others = append(others, t)
+		}
+	}
+	with := CoveredChecks(append(others, test))
+	without := CoveredChecks(others)
+	var unique []string
+	for _, check := range DirectiveChecks() {
+		if CheckApplies(kind, check) && with[check] && !without[check] {
+			unique = append(unique, check)
+		}
+	}
+	return unique
+}
+
+// linkChanges compares the tests linked to one definition in two registries.
+func linkChanges(key ResourceKey, oldTests, newTests []*TestFunctionInfo) []LinkChange {
+	before := make(map[string]MatchType, len(oldTests))
+	for _, t := range oldTests {
+		before[t.Name] = t.MatchType
+	}
+	after := make(map[string]MatchType, len(newTests))
+	for _, t := range newTests {
+		after[t.Name] = t.MatchType
+	}
+
+	var changes []LinkChange
+	for name, matchType := range after {
+		if prev, ok := before[name]; !ok || prev != matchType {
+			changes = append(changes, LinkChange{Key: key, Test: name, Before: prev, After: matchType})
+		}
+	}
+	for name, matchType := range before {
+		if _, ok := after[name]; !ok {
+			changes = append(changes, LinkChange{Key: key, Test: name, Before: matchType, After: MatchTypeNone})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Test < changes[j].Test })
+	return changes
+}
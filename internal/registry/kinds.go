@@ -0,0 +1,110 @@
+package registry
+
+import "fmt"
+
+// KindSpec describes a kind of definition: how it is named in output and registry keys,
+// declared in test configs, and recognized in provider code. Code that handles kinds through
+// their spec, rather than by switching on KindResource, KindDataSource and KindAction, works
+// unchanged for kinds added with RegisterKind, such as ephemeral resources, list resources
+// or provider functions.
+type KindSpec struct {
+	// Name is the kind's String value, e.g. "data source". It prefixes registry keys
+	// ("data source:widget"), so it must be unique.
+	Name string
+	// Key is Name as an identifier, e.g. "data_source", for JSON record types and message IDs.
+	Key string
+	// BlockType is the HCL block type that declares the kind in test configs, e.g. "data",
+	// or "" when configs cannot declare it.
+	BlockType string
+	// Package is the name of the plugin framework package whose request and response types,
+	// such as datasource.MetadataResponse, identify the kind; "" when there is none.
+	Package string
+	// Checks lists the directive checks (see DirectiveChecks) meaningful for the kind.
+	Checks []string
+}
+
+// kindSpecs holds the spec of every kind, indexed by ResourceKind. The built-in kinds come
+// first, in the order of their constants, so their values and String output never change.
+var kindSpecs = []KindSpec{
+	KindResource: {
+		Name:      "resource",
+		Key:       "resource",
+		BlockType: "resource",
+		Package:   "resource",
+		Checks:    []string{CheckBasic, CheckUpdate, CheckImport, CheckError, CheckStateCheck, CheckDrift, CheckDisappears, CheckRefresh, CheckIdempotency},
+	},
+	KindDataSource: {
+		Name:      "data source",
+		Key:       "data_source",
+		BlockType: "data",
+		Package:   "datasource",
+		Checks:    []string{CheckBasic, CheckStateCheck, CheckManagedConfig},
+	},
+	KindAction: {
+		Name:      "action",
+		Key:       "action",
+		BlockType: "action",
+		Package:   "action",
+		Checks:    []string{CheckBasic, CheckStateCheck},
+	},
+}
+
+// RegisterKind adds a kind of definition and returns its ResourceKind. Its name, key, block
+// type and package must not be used by another kind. Kinds are global, so register them from
+// an init function, before any analysis runs.
+func RegisterKind(spec KindSpec) (ResourceKind, error) {
+	if spec.Name == "" || spec.Key == "" {
+		return 0, fmt.Errorf("kind %q: name and key are required", spec.Name)
+	}
+	for _, existing := range kindSpecs {
+		switch {
+		case existing.Name == spec.Name:
+			return 0, fmt.Errorf("kind %q is already registered", spec.Name)
+		case existing.Key == spec.Key:
+			return 0, fmt.Errorf("kind %q: key %q is used by kind %q", spec.Name, spec.Key, existing.Name)
+		case spec.BlockType != "" && existing.BlockType == spec.BlockType:
+			return 0, fmt.Errorf("kind %q: block type %q is used by kind %q", spec.Name, spec.BlockType, existing.Name)
+		case spec.Package != "" && existing.Package == spec.Package:
+			return 0, fmt.Errorf("kind %q: package %q is used by kind %q", spec.Name, spec.Package, existing.Name)
+		}
+	}
+	kindSpecs = append(kindSpecs, spec)
+	return ResourceKind(len(kindSpecs) - 1), nil
+}
+
+// Kinds returns every registered kind in lookup priority order: the built-in kinds, then
+// registered ones in the order they were added.
+func Kinds() []ResourceKind {
+	kinds := make([]ResourceKind, len(kindSpecs))
+	for i := range kindSpecs {
+		kinds[i] = ResourceKind(i)
+	}
+	return kinds
+}
+
+// Spec returns the spec of a kind, or false for a value no kind was registered with.
+func (k ResourceKind) Spec() (KindSpec, bool) {
+	if k < 0 || int(k) >= len(kindSpecs) {
+		return KindSpec{}, false
+	}
+	return kindSpecs[k], true
+}
+
+// Key returns the identifier form of the kind's name, e.g. "data_source", or "unknown".
+func (k ResourceKind) Key() string {
+	if spec, ok := k.Spec(); ok {
+		return spec.Key
+	}
+	return "unknown"
+}
+
+// KindFromPackage maps the name of a plugin framework package, such as "datasource" in
+// datasource.MetadataResponse, to the kind it defines.
+func KindFromPackage(pkg string) (ResourceKind, bool) {
+	for i, spec := range kindSpecs {
+		if spec.Package != "" && spec.Package == pkg {
+			return ResourceKind(i), true
+		}
+	}
+	return KindResource, false
+}
@@ -3,7 +3,10 @@
 package registry
 
 import (
+	"cmp"
 	"go/token"
+	"path"
+	"sort"
 	"strings"
 	"sync"
 )
@@ -41,6 +44,18 @@ func (m MatchType) String() string {
 	}
 }
 
+// MatchCandidate is a definition a linker strategy matched a test function to, whether or not
+// the test was linked to it. Candidates that were not accepted are near misses: a different
+// naming convention or matching setting would have linked them.
+type MatchCandidate struct {
+	Key        ResourceKey
+	Confidence float64
+	MatchType  MatchType
+	Strategy   string // Strategy names the linker strategy, e.g. "function-name" or "fuzzy"
+	Accepted   bool   // Accepted is true for the candidate the test was linked to
+	Note       string // Note explains why a candidate was not accepted
+}
+
 // ResourceRegistry maintains thread-safe mappings of resources, data sources,
 // and their associated test functions discovered during AST analysis.
 type ResourceRegistry struct {
@@ -48,7 +63,22 @@ type ResourceRegistry struct {
 	definitions    map[string]*ResourceInfo // Unified map of all resources and data sources
 	testFunctions  []*TestFunctionInfo
 	resourceTests  map[string][]*TestFunctionInfo
+	quarantined    map[string][]*TestFunctionInfo // Quarantined tests, linked without coverage credit
+	quarantine     map[string]string              // Quarantine list: test name or glob -> reason
+	candidates     map[*TestFunctionInfo][]MatchCandidate
 	fileToResource map[string]string
+	provider       *ProviderInfo
+	directiveErrs  []DirectiveError
+	requirements   []RequirementIssue
+	observer       Observer
+}
+
+// Observer receives registry changes as they happen, for example to stream scan progress.
+// Either function may be nil. They are called after the registry has released its lock, so
+// they may read the registry.
+type Observer struct {
+	DefinitionRegistered func(info *ResourceInfo)
+	TestLinked           func(key ResourceKey, fn *TestFunctionInfo)
 }
 
 // NewResourceRegistry creates a new empty resource registry.
@@ -57,24 +87,244 @@ func NewResourceRegistry() *ResourceRegistry {
 		definitions:    make(map[string]*ResourceInfo),
 		testFunctions:  make([]*TestFunctionInfo, 0),
 		resourceTests:  make(map[string][]*TestFunctionInfo),
+		quarantined:    make(map[string][]*TestFunctionInfo),
+		quarantine:     make(map[string]string),
+		candidates:     make(map[*TestFunctionInfo][]MatchCandidate),
 		fileToResource: make(map[string]string),
 	}
 }
 
+// ResourceKey identifies a definition in the registry by its kind and name.
+// Use it instead of hand-building "kind:name" strings so that key formats stay
+// consistent across packages.
+type ResourceKey struct {
+	Kind ResourceKind
+	Name string
+}
+
+// String returns the compound key form used internally by the registry (e.g., "data source:widget").
+func (k ResourceKey) String() string {
+	return k.Kind.String() + ":" + k.Name
+}
+
+// Compare orders keys by kind, then name, for slices.SortFunc.
+func (k ResourceKey) Compare(other ResourceKey) int {
+	return cmp.Or(cmp.Compare(k.Kind, other.Kind), cmp.Compare(k.Name, other.Name))
+}
+
+// ParseResourceKey parses a compound key produced by ResourceKey.String.
+// It returns false if the string has no recognized kind prefix.
+func ParseResourceKey(s string) (ResourceKey, bool) {
+	idx := strings.Index(s, ":")
+	if idx == -1 {
+		return ResourceKey{}, false
+	}
+	kind, ok := ParseResourceKind(s[:idx])
+	if !ok {
+		return ResourceKey{}, false
+	}
+	return ResourceKey{Kind: kind, Name: s[idx+1:]}, true
+}
+
+// ParseResourceKind converts the output of ResourceKind.String back into a ResourceKind.
+func ParseResourceKind(s string) (ResourceKind, bool) {
+	for _, kind := range Kinds() {
+		if kind.String() == s {
+			return kind, true
+		}
+	}
+	return KindResource, false
+}
+
+// KindFromBlockType maps an HCL block type ("resource", "data", "action") to a ResourceKind.
+func KindFromBlockType(blockType string) (ResourceKind, bool) {
+	for _, kind := range Kinds() {
+		if spec, _ := kind.Spec(); spec.BlockType != "" && spec.BlockType == blockType {
+			return kind, true
+		}
+	}
+	return KindResource, false
+}
+
 // registryKey creates a unique key for a resource in the registry.
 // This allows resources, data sources, and actions with the same base name to coexist.
 func registryKey(kind ResourceKind, name string) string {
-	return kind.String() + ":" + name
+	return ResourceKey{Kind: kind, Name: name}.String()
 }
 
 // RegisterResource adds a resource, data source, or action to the registry.
 func (r *ResourceRegistry) RegisterResource(info *ResourceInfo) {
 	r.mu.Lock()
-	defer r.mu.Unlock()
-
 	key := registryKey(info.Kind, info.Name)
 	r.definitions[key] = info
 	r.fileToResource[info.FilePath] = key
+	notify := r.observer.DefinitionRegistered
+	r.mu.Unlock()
+
+	if notify != nil {
+		notify(info)
+	}
+}
+
+// UnregisterResource removes a definition from the registry. Tests already linked to it
+// are kept with the test index but are no longer returned for the definition.
+func (r *ResourceRegistry) UnregisterResource(kind ResourceKind, name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := registryKey(kind, name)
+	delete(r.definitions, key)
+	delete(r.resourceTests, key)
+	delete(r.quarantined, key)
+	for file, fileKey := range r.fileToResource {
+		if fileKey == key {
+			delete(r.fileToResource, file)
+		}
+	}
+}
+
+// RetainDefinitions removes the definitions keep rejects and every test function not linked
+// to a remaining definition: tests of removed definitions, and tests linked to none, such as
+// orphans and provider tests.
+func (r *ResourceRegistry) RetainDefinitions(keep func(info *ResourceInfo) bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key, info := range r.definitions {
+		if keep(info) {
+			continue
+		}
+		delete(r.definitions, key)
+		delete(r.resourceTests, key)
+		delete(r.quarantined, key)
+	}
+	for file, key := range r.fileToResource {
+		if _, ok := r.definitions[key]; !ok {
+			delete(r.fileToResource, file)
+		}
+	}
+	for key, info := range r.definitions {
+		if _, ok := r.fileToResource[info.FilePath]; !ok {
+			r.fileToResource[info.FilePath] = key
+		}
+	}
+
+	linked := make(map[*TestFunctionInfo]bool)
+	for _, tests := range r.resourceTests {
+		for _, fn := range tests {
+			linked[fn] = true
+		}
+	}
+	for _, tests := range r.quarantined {
+		for _, fn := range tests {
+			linked[fn] = true
+		}
+	}
+	kept := r.testFunctions[:0]
+	for _, fn := range r.testFunctions {
+		if linked[fn] {
+			kept = append(kept, fn)
+		} else {
+			delete(r.candidates, fn)
+		}
+	}
+	r.testFunctions = kept
+}
+
+// UnlinkTestsForFile removes the test functions declared in the file at filePath, with their
+// links and match candidates, and returns them with their match reset. A long-running
+// session calls it when a test file changes, before registering and linking the tests
+// parsed from its new content, so links whose evidence no longer holds do not linger.
+func (r *ResourceRegistry) UnlinkTestsForFile(filePath string) []*TestFunctionInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var removed []*TestFunctionInfo
+	kept := r.testFunctions[:0]
+	for _, fn := range r.testFunctions {
+		if fn.FilePath != filePath {
+			kept = append(kept, fn)
+			continue
+		}
+		removed = append(removed, fn)
+		delete(r.candidates, fn)
+		fn.MatchType = MatchTypeNone
+		fn.MatchConfidence = 0
+		fn.MatchedResource = ""
+		fn.MatchedKey = ResourceKey{}
+	}
+	r.testFunctions = kept
+	if len(removed) == 0 {
+		return nil
+	}
+
+	for _, links := range []map[string][]*TestFunctionInfo{r.resourceTests, r.quarantined} {
+		for key, tests := range links {
+			var keptTests []*TestFunctionInfo
+			for _, fn := range tests {
+				if fn.FilePath != filePath {
+					keptTests = append(keptTests, fn)
+				}
+			}
+			if len(keptTests) == 0 {
+				delete(links, key)
+			} else if len(keptTests) < len(tests) {
+				links[key] = keptTests
+			}
+		}
+	}
+	return removed
+}
+
+// SetObserver installs an observer for definitions registered and tests linked from now on.
+func (r *ResourceRegistry) SetObserver(o Observer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.observer = o
+}
+
+// SetProvider records the provider implementation discovered in the package.
+func (r *ResourceRegistry) SetProvider(info *ProviderInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.provider = info
+}
+
+// GetProvider returns the provider implementation, or nil if the package does not define one.
+func (r *ResourceRegistry) GetProvider() *ProviderInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.provider
+}
+
+// AddDirectiveError records a malformed or misplaced //tftest: directive.
+func (r *ResourceRegistry) AddDirectiveError(err DirectiveError) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.directiveErrs = append(r.directiveErrs, err)
+}
+
+// GetDirectiveErrors returns the directive errors found during discovery.
+func (r *ResourceRegistry) GetDirectiveErrors() []DirectiveError {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]DirectiveError, len(r.directiveErrs))
+	copy(result, r.directiveErrs)
+	return result
+}
+
+// AddRequirementIssue records a problem found while applying the requirements manifest.
+func (r *ResourceRegistry) AddRequirementIssue(issue RequirementIssue) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requirements = append(r.requirements, issue)
+}
+
+// GetRequirementIssues returns the requirements manifest problems found during discovery.
+func (r *ResourceRegistry) GetRequirementIssues() []RequirementIssue {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]RequirementIssue, len(r.requirements))
+	copy(result, r.requirements)
+	return result
 }
 
 // GetResourceByFile retrieves a resource by its file path.
@@ -100,6 +350,23 @@ func (r *ResourceRegistry) GetAllDefinitions() map[string]*ResourceInfo {
 	return result
 }
 
+// GetSortedDefinitions returns all definitions ordered by registry key (thread-safe). Use it
+// instead of GetAllDefinitions when output must not depend on map iteration order.
+func (r *ResourceRegistry) GetSortedDefinitions() []*ResourceInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	keys := make([]string, 0, len(r.definitions))
+	for k := range r.definitions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	result := make([]*ResourceInfo, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, r.definitions[k])
+	}
+	return result
+}
+
 // GetResourceOrDataSource retrieves a resource or data source by name using the unified definitions map.
 // It accepts either a simple name ("widget") or a compound key ("resource:widget").
 // For simple names, it returns the first matching definition found.
@@ -113,7 +380,7 @@ func (r *ResourceRegistry) GetResourceOrDataSource(name string) *ResourceInfo {
 	}
 
 	// For simple names, try each kind in order
-	for _, kind := range []ResourceKind{KindResource, KindDataSource, KindAction} {
+	for _, kind := range Kinds() {
 		key := registryKey(kind, name)
 		if info := r.definitions[key]; info != nil {
 			return info
@@ -122,6 +389,35 @@ func (r *ResourceRegistry) GetResourceOrDataSource(name string) *ResourceInfo {
 	return nil
 }
 
+// GetDefinition retrieves the definition registered under the given kind and name.
+func (r *ResourceRegistry) GetDefinition(kind ResourceKind, name string) *ResourceInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.definitions[registryKey(kind, name)]
+}
+
+// MatchedDefinition returns the definition the linker linked a test to, or nil if the test
+// is not linked.
+func (r *ResourceRegistry) MatchedDefinition(fn *TestFunctionInfo) *ResourceInfo {
+	if fn.MatchedKey.Name == "" {
+		return nil
+	}
+	return r.GetDefinition(fn.MatchedKey.Kind, fn.MatchedKey.Name)
+}
+
+// ResolveKey finds the key of a definition registered under a simple name, trying
+// resources, then data sources, then actions. It returns false if no kind matches.
+func (r *ResourceRegistry) ResolveKey(name string) (ResourceKey, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, kind := range Kinds() {
+		if _, exists := r.definitions[registryKey(kind, name)]; exists {
+			return ResourceKey{Kind: kind, Name: name}, true
+		}
+	}
+	return ResourceKey{}, false
+}
+
 // RegisterTestFunction adds a test function to the global index.
 func (r *ResourceRegistry) RegisterTestFunction(fn *TestFunctionInfo) {
 	r.mu.Lock()
@@ -138,47 +434,117 @@ func (r *ResourceRegistry) GetAllTestFunctions() []*TestFunctionInfo {
 	return result
 }
 
+// LinkTest associates a test function with the definition of the given kind and name.
+// Quarantined tests are kept apart: they are returned by GetQuarantinedTests rather than
+// GetTests, so they earn the definition no coverage credit.
+func (r *ResourceRegistry) LinkTest(kind ResourceKind, name string, fn *TestFunctionInfo) {
+	r.mu.Lock()
+	key := registryKey(kind, name)
+	if fn.Quarantined {
+		r.quarantined[key] = append(r.quarantined[key], fn)
+	} else {
+		r.resourceTests[key] = append(r.resourceTests[key], fn)
+	}
+	notify := r.observer.TestLinked
+	r.mu.Unlock()
+
+	if notify != nil {
+		notify(ResourceKey{Kind: kind, Name: name}, fn)
+	}
+}
+
+// SetMatchCandidates records the candidates the linker considered for a test function.
+func (r *ResourceRegistry) SetMatchCandidates(fn *TestFunctionInfo, candidates []MatchCandidate) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.candidates[fn] = candidates
+}
+
+// GetMatchCandidates returns every candidate the linker considered for a test function, in
+// strategy priority order, including the accepted one.
+func (r *ResourceRegistry) GetMatchCandidates(fn *TestFunctionInfo) []MatchCandidate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.candidates[fn]
+}
+
+// GetTests returns all test functions linked to the definition of the given kind and name.
+func (r *ResourceRegistry) GetTests(kind ResourceKind, name string) []*TestFunctionInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.resourceTests[registryKey(kind, name)]
+}
+
+// GetQuarantinedTests returns the quarantined test functions linked to the definition of
+// the given kind and name.
+func (r *ResourceRegistry) GetQuarantinedTests(kind ResourceKind, name string) []*TestFunctionInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.quarantined[registryKey(kind, name)]
+}
+
+// AddQuarantine adds a test name, or a glob such as "TestAccWidget_*", to the quarantine list.
+func (r *ResourceRegistry) AddQuarantine(pattern, reason string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.quarantine[pattern] = reason
+}
+
+// QuarantineReason returns the reason the quarantine list gives for a test, preferring an
+// exact name over globs (tried in sorted order), and false when no entry matches.
+func (r *ResourceRegistry) QuarantineReason(testName string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if reason, ok := r.quarantine[testName]; ok {
+		return reason, true
+	}
+	patterns := make([]string, 0, len(r.quarantine))
+	for pattern := range r.quarantine {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, testName); matched {
+			return r.quarantine[pattern], true
+		}
+	}
+	return "", false
+}
+
 // LinkTestToResource associates a test function with a resource.
 // It accepts either a simple name ("widget") or a compound key ("resource:widget").
 // For simple names, it finds the first matching definition and uses its compound key.
+//
+// Deprecated: Use LinkTest, which takes the kind explicitly.
 func (r *ResourceRegistry) LinkTestToResource(resourceName string, fn *TestFunctionInfo) {
+	if key, ok := ParseResourceKey(resourceName); ok {
+		r.LinkTest(key.Kind, key.Name, fn)
+		return
+	}
+	if key, ok := r.ResolveKey(resourceName); ok {
+		r.LinkTest(key.Kind, key.Name, fn)
+		return
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
-
-	key := resourceName
-	// If it's not already a compound key, try to find the right one
-	if !strings.Contains(resourceName, ":") {
-		// Try each kind in order of priority
-		for _, kind := range []ResourceKind{KindResource, KindDataSource, KindAction} {
-			candidateKey := registryKey(kind, resourceName)
-			if _, exists := r.definitions[candidateKey]; exists {
-				key = candidateKey
-				break
-			}
-		}
-	}
-	r.resourceTests[key] = append(r.resourceTests[key], fn)
+	r.resourceTests[resourceName] = append(r.resourceTests[resourceName], fn)
 }
 
 // GetResourceTests returns all test functions associated with a resource.
 // It accepts either a simple name ("widget") or a compound key ("resource:widget").
 // For simple names, it aggregates tests from all matching kinds (resource, datasource, action).
+//
+// Deprecated: Use GetTests, which takes the kind explicitly.
 func (r *ResourceRegistry) GetResourceTests(resourceName string) []*TestFunctionInfo {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	// If it's already a compound key (contains ":"), use it directly
-	if strings.Contains(resourceName, ":") {
-		return r.resourceTests[resourceName]
+	if key, ok := ParseResourceKey(resourceName); ok {
+		return r.GetTests(key.Kind, key.Name)
 	}
 
 	// For simple names, aggregate tests from all kinds
 	var allTests []*TestFunctionInfo
-	for _, kind := range []ResourceKind{KindResource, KindDataSource, KindAction} {
-		key := registryKey(kind, resourceName)
-		if tests := r.resourceTests[key]; len(tests) > 0 {
-			allTests = append(allTests, tests...)
-		}
+	for _, kind := range Kinds() {
+		allTests = append(allTests, r.GetTests(kind, resourceName)...)
 	}
 	return allTests
 }
@@ -203,7 +569,8 @@ func (r *ResourceRegistry) GetUnmatchedTestFunctions() []*TestFunctionInfo {
 	return unmatched
 }
 
-// ResourceKind represents the type of Terraform provider component.
+// ResourceKind represents the type of Terraform provider component. The built-in kinds are
+// constants; others are added with RegisterKind and described by their KindSpec.
 type ResourceKind int
 
 const (
@@ -245,18 +612,13 @@ func (c TestCategory) String() string {
 	}
 }
 
-// String returns the string representation of a ResourceKind.
+// String returns the string representation of a ResourceKind: the Name of its KindSpec, or
+// "unknown".
 func (k ResourceKind) String() string {
-	switch k {
-	case KindResource:
-		return "resource"
-	case KindDataSource:
-		return "data source"
-	case KindAction:
-		return "action"
-	default:
-		return "unknown"
+	if spec, ok := k.Spec(); ok {
+		return spec.Name
 	}
+	return "unknown"
 }
 
 // ResourceInfo holds metadata about a Terraform resource, data source, or action.
@@ -266,20 +628,270 @@ type ResourceInfo struct {
 	FilePath       string
 	SchemaPos      token.Pos
 	Attributes     []AttributeInfo
+	Blocks         []AttributeInfo // Top-level schema blocks of a framework definition
 	HasImportState bool
 	ImportStatePos token.Pos
+	Directives     *CoverageDirectives // Expectations from //tftest: directives, if any
+	Operations     *Operations         // CRUD functions set on an SDK v2 schema.Resource; nil for the framework
+	DiscoveredBy   string              // Discovery strategy that found the definition (e.g., "SchemaMethod")
+	DeferredPos    token.Pos           // Where ModifyPlan sets resp.Deferred; NoPos if the resource never defers
+	Factory        string              // Factory function that constructs the definition, if known (e.g., "NewWidgetResource")
+	AliasOf        string              // Definition whose implementation this one shares, when registered by calling its factory with a name
+	Maturity       string              // Maturity level from //tftest:maturity or the requirements manifest (e.g., MaturityBeta); "" when untagged
+	Category       string              // Product area from a "Category:" doc comment line or the service directory (e.g., "Networking"); "" when unknown
+	Methods        []MethodRange       // Lifecycle methods (Create, Read, Update, Delete) declared in the definition's file
+	RegisteredPos  token.Pos           // Registry map key that registers the definition, when SchemaPos was resolved to the function it calls
+	SchemaVersion  int                 // Schema version (framework schema.Schema Version, SDK v2 SchemaVersion); 0 when unset
+	VersionPos     token.Pos           // Position of the schema version field; NoPos when unset
+	ListResource   bool                // A data source registered as a list resource (AWSCC AddListResourceFactory), returning many items
+}
+
+// MethodRange is the source range of a lifecycle method of a framework definition.
+type MethodRange struct {
+	Name string // Create, Read, Update or Delete
+	Pos  token.Pos
+	End  token.Pos
+	NoOp bool // The body does nothing but, at most, remove the resource from state
+}
+
+// Operations records which lifecycle functions an SDK v2 schema.Resource sets. Each field
+// is true when any variant is set (e.g. Update, UpdateContext or UpdateWithoutTimeout).
+type Operations struct {
+	Create bool
+	Read   bool
+	Update bool
+	Delete bool
+	// DeleteNoOp is true when Delete is set to a function that only forgets the resource,
+	// schema.Noop, schema.NoopContext or schema.RemoveFromState
+	DeleteNoOp bool
+}
+
+// DeleteCapability describes what deleting a resource does, as far as discovery can tell.
+type DeleteCapability int
+
+const (
+	// DeleteUnknown means discovery found no Delete to inspect, e.g. a framework resource
+	// whose methods live in another file.
+	DeleteUnknown DeleteCapability = iota
+	// DeleteImplemented means Delete does work to destroy the remote object.
+	DeleteImplemented
+	// DeleteMissing means the SDK v2 resource sets no Delete function.
+	DeleteMissing
+	// DeleteNoOp means Delete only removes the resource from state.
+	DeleteNoOp
+)
+
+// DeleteCapability reports what deleting the definition does. Only resources can be
+// destroyed; other kinds report DeleteUnknown.
+func (r *ResourceInfo) DeleteCapability() DeleteCapability {
+	if r.Kind != KindResource {
+		return DeleteUnknown
+	}
+	if ops := r.Operations; ops != nil {
+		switch {
+		case !ops.Delete:
+			return DeleteMissing
+		case ops.DeleteNoOp:
+			return DeleteNoOp
+		default:
+			return DeleteImplemented
+		}
+	}
+	for _, method := range r.Methods {
+		if method.Name != "Delete" {
+			continue
+		}
+		if method.NoOp {
+			return DeleteNoOp
+		}
+		return DeleteImplemented
+	}
+	return DeleteUnknown
+}
+
+// Key returns the registry key for this definition.
+func (r *ResourceInfo) Key() ResourceKey {
+	return ResourceKey{Kind: r.Kind, Name: r.Name}
+}
+
+// Coverage checks that //tftest: directives can expect or exempt.
+const (
+	CheckBasic      = "basic"
+	CheckUpdate     = "update"
+	CheckImport     = "import"
+	CheckError      = "error"
+	CheckStateCheck = "state-check"
+	CheckDrift      = "drift"
+	CheckDisappears = "disappears"
+	// CheckRefresh is met by a test with a RefreshState step, which detects drift
+	CheckRefresh = "refresh"
+	// CheckIdempotency is met by a test that plans its last config again and expects no changes
+	CheckIdempotency = "idempotency"
+	// CheckManagedConfig is met by a data source test whose config creates what it reads
+	CheckManagedConfig = "managed-config"
+)
+
+// Maturity levels accepted by //tftest:maturity directives and the requirements manifest.
+const (
+	MaturityExperimental = "experimental"
+	MaturityBeta         = "beta"
+	MaturityGA           = "ga"
+)
+
+// MaturityLevels returns the maturity levels, from least to most mature.
+func MaturityLevels() []string {
+	return []string{MaturityExperimental, MaturityBeta, MaturityGA}
+}
+
+// DirectiveChecks returns the check names accepted by //tftest: directives.
+func DirectiveChecks() []string {
+	return []string{CheckBasic, CheckUpdate, CheckImport, CheckError, CheckStateCheck, CheckDrift, CheckDisappears, CheckRefresh, CheckIdempotency, CheckManagedConfig}
+}
+
+// CheckApplies reports whether a check is meaningful for a definition kind, as listed by
+// its KindSpec. Only basic and state-check coverage applies to data sources and actions, and
+// managed-config applies only to data sources.
+func CheckApplies(kind ResourceKind, check string) bool {
+	spec, _ := kind.Spec()
+	for _, applies := range spec.Checks {
+		if applies == check {
+			return true
+		}
+	}
+	return false
+}
+
+// CoverageDirectives holds the per-resource overrides declared with //tftest:expect and
+// //tftest:exempt comments or in the requirements manifest. A nil *CoverageDirectives
+// expects and exempts nothing.
+type CoverageDirectives struct {
+	Pos    token.Pos
+	Expect map[string]bool   // Checks required even when the analyzer heuristics would skip them
+	Exempt map[string]string // Checks never reported, mapped to the stated reason
+	// Manifest is the requirements manifest that contributed checks, if any
+	Manifest string
+	// FromManifest lists the checks of Expect and Exempt that came from Manifest
+	FromManifest map[string]bool
+}
+
+// Expects reports whether the check was listed in a //tftest:expect directive.
+func (d *CoverageDirectives) Expects(check string) bool {
+	return d != nil && d.Expect[check]
+}
+
+// Exempts reports whether the check was listed in a //tftest:exempt directive.
+func (d *CoverageDirectives) Exempts(check string) bool {
+	if d == nil {
+		return false
+	}
+	_, ok := d.Exempt[check]
+	return ok
+}
+
+// ExpectedBy returns where an expected check was declared: the requirements manifest path
+// or "//tftest:expect".
+func (d *CoverageDirectives) ExpectedBy(check string) string {
+	if d != nil && d.FromManifest[check] {
+		return d.Manifest
+	}
+	return "//tftest:expect"
+}
+
+// DirectiveProblem classifies a directive error.
+type DirectiveProblem string
+
+const (
+	DirectiveUnknown       DirectiveProblem = "unknown directive"
+	DirectiveUnknownCheck  DirectiveProblem = "unknown check"
+	DirectiveUnknownOption DirectiveProblem = "unknown option"
+	DirectiveMissingChecks DirectiveProblem = "missing checks"
+	DirectiveMalformed     DirectiveProblem = "malformed"
+	DirectiveConflict      DirectiveProblem = "conflict"
+	DirectiveInapplicable  DirectiveProblem = "inapplicable check"
+	DirectiveUnattached    DirectiveProblem = "unattached"
+	DirectiveUnknownLevel  DirectiveProblem = "unknown maturity level"
+)
+
+// DirectiveError describes a //tftest: directive that could not be applied.
+type DirectiveError struct {
+	Pos        token.Pos
+	Directive  string // Directive verb as written (e.g., "expect", or a typo such as "exmpt")
+	Problem    DirectiveProblem
+	Value      string       // Offending check, option or text, when applicable
+	Suggestion string       // Closest known spelling, when one is near enough
+	Target     string       // Name of the definition the directive is attached to, if any
+	TargetKind ResourceKind // Kind of that definition
+}
+
+// RequirementProblem classifies a requirements manifest issue.
+type RequirementProblem string
+
+const (
+	RequirementInvalid  RequirementProblem = "invalid manifest"
+	RequirementConflict RequirementProblem = "conflict"
+	RequirementUnlisted RequirementProblem = "unlisted"
+)
+
+// RequirementIssue describes a requirements manifest that could not be loaded, or a
+// definition the manifest disagrees with.
+type RequirementIssue struct {
+	Manifest   string // Path of the manifest
+	Problem    RequirementProblem
+	Value      string       // Load error or conflicting check, when applicable
+	Target     string       // Name of the definition concerned, if any
+	TargetKind ResourceKind // Kind of that definition
+	Pos        token.Pos    // Schema position of that definition; NoPos for the manifest itself
+}
+
+// ProviderInfo holds metadata about the provider implementation itself.
+type ProviderInfo struct {
+	TypeName     string // Provider type name from Metadata (e.g., "example"), if known
+	TypeNameHint string // Receiver type name (e.g., "ExampleProvider")
+	FilePath     string
+	SchemaPos    token.Pos
+	ConfigurePos token.Pos // Position of the Configure method, if present
+	Attributes   []AttributeInfo
+	EnvVars      []string // Environment variables read by Configure (os.Getenv, os.LookupEnv)
+	DeferredPos  token.Pos // Where Configure sets resp.Deferred, if it does
+}
+
+// HasAttribute reports whether the provider schema defines the named attribute.
+func (p *ProviderInfo) HasAttribute(name string) bool {
+	for _, attr := range p.Attributes {
+		if attr.Name == name {
+			return true
+		}
+	}
+	return false
 }
 
 // AttributeInfo represents a single attribute from a resource schema.
 type AttributeInfo struct {
-	Name           string
-	Type           string
-	Required       bool
-	Optional       bool
-	Computed       bool
-	IsUpdatable    bool
-	HasValidators  bool
-	ValidatorTypes []string
+	Name               string
+	Type               string
+	Required           bool
+	Optional           bool
+	Computed           bool
+	IsUpdatable        bool
+	HasValidators      bool
+	ValidatorTypes     []string
+	HasDefault         bool      // HasDefault is true when the schema sets Default or DefaultFunc
+	DefaultPos         token.Pos // Position of the Default or DefaultFunc field
+	Kind               string    // Framework schema type of the attribute or block (e.g., "StringAttribute", "ListNestedBlock"); "" for SDK v2
+	HasPlanModifiers   bool      // HasPlanModifiers is true when the schema sets PlanModifiers
+	Deprecated         bool      // Deprecated is true when the schema sets DeprecationMessage (Deprecated for SDK v2)
+	DeprecationMessage string    // The deprecation message, when it is a string literal
+	DeprecatedPos      token.Pos // Position of the DeprecationMessage or Deprecated field
+	// Nested holds the attributes and blocks of a nested attribute or block, or of an SDK v2
+	// attribute whose Elem is a *schema.Resource
+	Nested []AttributeInfo
+}
+
+// ProneToPerpetualDiff returns true if the attribute is both Optional and Computed: when the
+// config leaves it unset the provider fills it in, and a Read that normalizes the value
+// differently from the config shows up as a diff on every plan.
+func (a *AttributeInfo) ProneToPerpetualDiff() bool {
+	return a.Optional && a.Computed
 }
 
 // NeedsUpdateTest returns true if the attribute is optional and updatable.
@@ -309,23 +921,233 @@ type InferredHCLBlock struct {
 
 // TestFunctionInfo represents a single TestAcc function and its test steps.
 type TestFunctionInfo struct {
-	Name              string
-	FilePath          string
-	FunctionPos       token.Pos
-	UsesResourceTest  bool
-	TestSteps         []TestStepInfo
+	Name             string
+	FilePath         string
+	FunctionPos      token.Pos
+	UsesResourceTest bool
+	TestSteps        []TestStepInfo
+	// HasOpaqueSteps is true when some of the test's steps are built in a way discovery cannot
+	// resolve statically (a computed loop, an unresolvable helper), so TestSteps may be incomplete
+	HasOpaqueSteps    bool
 	HasErrorCase      bool
 	HasImportStep     bool
 	InferredResources []string           // Legacy: just resource type names
 	InferredHCLBlocks []InferredHCLBlock // New: typed HCL blocks with block type
 	MatchConfidence   float64
 	MatchType         MatchType
+	MatchedResource   string       // MatchedResource is the resource name the linker chose for this test
+	MatchedKey        ResourceKey  // MatchedKey is the definition the linker chose, telling a resource from a data source of the same name
 	HelperUsed        string       // Name of helper function used (e.g., "resource.Test", "AccTestHelper")
 	HasCheckDestroy   bool         // HasCheckDestroy tracks presence of CheckDestroy in resource.TestCase
 	HasPreCheck       bool         // HasPreCheck tracks presence of PreCheck function
 	Category          TestCategory // Category classifies test type (resource, provider, function, integration)
+	// ProviderConfigAttributes lists attributes set in provider blocks of the test's HCL configs
+	ProviderConfigAttributes []string
+	// ProviderAliases lists the aliases of provider blocks in the test's HCL configs, so a
+	// non-empty list means the test configures more than one provider instance
+	ProviderAliases []string
+	// EnvVarsSet lists environment variables the test sets via t.Setenv or os.Setenv
+	EnvVarsSet []string
+	// ConfigEnvVars lists the environment variables read with os.Getenv and interpolated into
+	// the test's step configs, in source order
+	ConfigEnvVars []EnvVarUse
+	// PreCheckEnvVars lists the environment variables the test's PreCheck reads or passes by
+	// name to a helper
+	PreCheckEnvVars []string
+	// PreCheckUnresolved is true when the PreCheck calls functions of another package, so
+	// PreCheckEnvVars may be incomplete
+	PreCheckUnresolved bool
+	// UsesParallelTest is true when the test runs in parallel (resource.ParallelTest or t.Parallel)
+	UsesParallelTest bool
+	// SerialReason is the comment, in the test's doc comment or body, that explains why it
+	// does not run in parallel (one mentioning serial, sequential or parallel execution)
+	SerialReason string
+	// FixtureValues lists hard-coded string attributes of resource blocks in the test's configs
+	FixtureValues []FixtureValue
+	// ChecksDisappears is true when the test deletes the resource out of band and expects a
+	// non-empty plan (a "disappears" test), detected by name or by a *Disappears check call
+	ChecksDisappears bool
+	// Quarantined is true when the test is known to be flaky or disabled, by a
+	// //tftest:quarantine doc comment or the quarantine list of the requirements manifest
+	Quarantined bool
+	// QuarantineReason explains the quarantine, when one was given
+	QuarantineReason string
+	// TestCases lists the provider factory fields and the ErrorCheck of each
+	// resource.TestCase literal of the test, in source order
+	TestCases []TestCaseFactories
+	// Subtests lists the t.Run subtests that run a TestCase of their own, in source order.
+	// Each is a scenario of its own; a table-driven subtest has one entry per table row.
+	Subtests []SubtestInfo
+	// Conditions lists the guards that skip the test, or some of its steps, under some
+	// Terraform versions, regions or API capabilities, in source order. The coverage the
+	// test earns is conditional on them.
+	Conditions []TestCondition
+}
+
+// ConditionKind is the kind of guard that runs a test only under some conditions.
+type ConditionKind string
+
+const (
+	// ConditionSkipFunc is a step setting SkipFunc.
+	ConditionSkipFunc ConditionKind = "skip-func"
+	// ConditionTerraformVersion is a TerraformVersionChecks entry skipping some Terraform
+	// versions, such as tfversion.SkipBelow(tfversion.Version1_8_0).
+	ConditionTerraformVersion ConditionKind = "terraform-version"
+	// ConditionSkip is a call to t.Skip under a condition other than an environment
+	// variable being unset, such as a region or version comparison.
+	ConditionSkip ConditionKind = "skip"
+	// ConditionGuard is a call to a helper the conditional-guards setting names, such as
+	// acctest.PreCheckRegion.
+	ConditionGuard ConditionKind = "guard"
+)
+
+// TestCondition is a guard under which a test, or some of its steps, is skipped.
+type TestCondition struct {
+	Kind   ConditionKind
+	Detail string    // The guard as written, e.g. "tfversion.SkipBelow(tfversion.Version1_8_0)"
+	Pos    token.Pos // The guard, or the SkipFunc value
 }
 
+// String returns the kind of the condition followed by its detail, e.g.
+// "terraform-version: tfversion.SkipBelow(tfversion.Version1_8_0)".
+func (c TestCondition) String() string {
+	if c.Detail == "" {
+		return string(c.Kind)
+	}
+	return string(c.Kind) + ": " + c.Detail
+}
+
+// SubtestInfo is a t.Run subtest of an acceptance test that runs a resource.TestCase of its
+// own.
+type SubtestInfo struct {
+	// Name is the subtest name as go test reports it, with spaces replaced by underscores
+	// and nested subtests joined by "/"; empty when it is not known statically
+	Name string
+	Pos  token.Pos // The t.Run call
+	// Steps are the test's steps that lie inside the subtest, numbered as in TestSteps
+	Steps []TestStepInfo
+}
+
+// Provider factory fields of resource.TestCase and resource.TestStep.
+const (
+	FieldProviderFactories        = "ProviderFactories"        // SDKv2 providers served without muxing
+	FieldProtoV5ProviderFactories = "ProtoV5ProviderFactories" // Protocol 5 servers, e.g. muxed SDKv2
+	FieldProtoV6ProviderFactories = "ProtoV6ProviderFactories" // Protocol 6 servers, e.g. the plugin framework
+	FieldExternalProviders        = "ExternalProviders"        // Released providers downloaded from a registry
+	FieldProviders                = "Providers"                // Deprecated SDKv2 provider instances
+)
+
+// ProviderFactoryFields lists the fields that give a test case or step its providers.
+var ProviderFactoryFields = []string{FieldProviderFactories, FieldProtoV5ProviderFactories, FieldProtoV6ProviderFactories, FieldExternalProviders, FieldProviders}
+
+// TestCaseFactories records the provider factory fields of a resource.TestCase literal and
+// of the step literals in it, and the ErrorCheck the TestCase sets.
+type TestCaseFactories struct {
+	Pos    token.Pos // The TestCase literal
+	Fields []ProviderFactoryField
+	// Steps is the number of step literals in the TestCase
+	Steps int
+	// Complete is true when the TestCase is passed directly to resource.Test, ParallelTest or
+	// UnitTest and all its steps are literals in it, so a field it does not show is not set
+	// elsewhere
+	Complete bool
+	// Direct is true when the TestCase is passed directly to a test runner, so a TestCase
+	// field it does not show is not set elsewhere, whatever its steps
+	Direct bool
+	// ErrorCheck is the function the TestCase's ErrorCheck calls to build its check, or
+	// names, e.g. "acctest.ErrorCheck"; "func" for a function literal and "" when the
+	// TestCase sets no ErrorCheck
+	ErrorCheck    string
+	ErrorCheckPos token.Pos
+}
+
+// ProviderFactoryField is a provider factory field set in a TestCase or one of its steps.
+type ProviderFactoryField struct {
+	Name  string // e.g. FieldProtoV6ProviderFactories
+	Value string // Source of the value, e.g. "acctest.ProtoV6ProviderFactories"
+	Step  int    // Number of the step literal setting it, 0 for the TestCase itself
+	Pos   token.Pos
+}
+
+// Missing returns whether the TestCase is known to give no providers to some of its steps:
+// it sets no factory field itself, and one of its steps sets none.
+func (c TestCaseFactories) Missing() bool {
+	if !c.Complete {
+		return false
+	}
+	stepsSet := make(map[int]bool)
+	for _, f := range c.Fields {
+		if f.Step == 0 {
+			return false
+		}
+		stepsSet[f.Step] = true
+	}
+	return len(stepsSet) < c.Steps
+}
+
+// UpgradeFrom returns the version constraint of the released provider an upgrade test of the
+// named provider starts from: a step applies its config with that release, taken from
+// ExternalProviders, and a later step runs the provider under test on the state it left.
+// ok is false when the test is not an upgrade test.
+func (t *TestFunctionInfo) UpgradeFrom(provider string) (version string, ok bool) {
+	for i := range t.TestSteps {
+		released, pinned := t.TestSteps[i].ExternalProvider(provider)
+		if !pinned {
+			continue
+		}
+		for j := i + 1; j < len(t.TestSteps); j++ {
+			if _, pinned := t.TestSteps[j].ExternalProvider(provider); !pinned {
+				return released.VersionConstraint, true
+			}
+		}
+	}
+	return "", false
+}
+
+// EnvVarUse is an environment variable read into a test config.
+type EnvVarUse struct {
+	Name string    // e.g., "EXAMPLE_REGION"
+	Pos  token.Pos // The os.Getenv call, the variable holding its value, or the helper call reading it
+}
+
+// ChecksEnvVar reports whether the test's PreCheck checks the environment variable.
+func (t *TestFunctionInfo) ChecksEnvVar(name string) bool {
+	for _, checked := range t.PreCheckEnvVars {
+		if checked == name {
+			return true
+		}
+	}
+	return false
+}
+
+// FixtureValue is a statically known attribute value in a test config, such as a bucket name.
+type FixtureValue struct {
+	ResourceType string // e.g., "aws_s3_bucket"
+	Attribute    string // e.g., "bucket"
+	Value        string
+}
+
+// ErrorCategory is the kind of error an ExpectError step targets.
+type ErrorCategory string
+
+const (
+	// ErrorCategoryValidation is an invalid attribute value or argument rejected by the schema
+	// or the provider before any API call.
+	ErrorCategoryValidation ErrorCategory = "validation"
+	// ErrorCategoryConflict is the API rejecting an object that already exists, e.g. a
+	// duplicate name.
+	ErrorCategoryConflict ErrorCategory = "conflict"
+	// ErrorCategoryAuth is the API rejecting the credentials or their permissions.
+	ErrorCategoryAuth ErrorCategory = "auth"
+	// ErrorCategoryNotFound is a reference to an object that does not exist.
+	ErrorCategoryNotFound ErrorCategory = "not-found"
+	// ErrorCategoryOther is an error whose kind could not be told from the step.
+	ErrorCategoryOther ErrorCategory = "other"
+)
+
+// ErrorCategories lists the error categories in report order.
+var ErrorCategories = []ErrorCategory{ErrorCategoryValidation, ErrorCategoryConflict, ErrorCategoryAuth, ErrorCategoryNotFound, ErrorCategoryOther}
+
 // TestStepInfo represents a single step within a resource.TestCase.
 type TestStepInfo struct {
 	StepNumber           int
@@ -343,7 +1165,117 @@ type TestStepInfo struct {
 	HasPlanCheck         bool // HasPlanCheck tracks presence of ConfigPlanChecks
 	HasConfigStateChecks bool // HasConfigStateChecks tracks presence of ConfigStateChecks (newer pattern)
 	ExpectNonEmptyPlan   bool // ExpectNonEmptyPlan tracks if step expects non-empty plan
+	PlanOnly             bool // PlanOnly tracks if step only plans its config without applying it
+	ExpectsEmptyPlan     bool // ExpectsEmptyPlan tracks if ConfigPlanChecks include plancheck.ExpectEmptyPlan
 	RefreshState         bool // RefreshState tracks if step uses refresh mode
+	HasRefreshPlanCheck  bool // HasRefreshPlanCheck tracks presence of RefreshPlanChecks
+	ImportStatePersist   bool // ImportStatePersist tracks if an import step keeps the imported state
+
+	// ImportStateIdFunc analysis
+	HasImportStateIdFunc    bool      // Step sets ImportStateIdFunc
+	ImportStateIdFunc       string    // Function or helper named by ImportStateIdFunc; empty for a func literal
+	ImportStateIdFuncPos    token.Pos // Position of the ImportStateIdFunc value
+	ImportStateIdAttributes []string  // State attribute keys the function reads (rs.Primary.Attributes["..."])
+
+	// ImportStateCheck analysis
+	HasImportStateCheck             bool      // Step sets ImportStateCheck
+	ImportStateCheck                string    // Function or helper named by ImportStateCheck; empty for a func literal
+	ImportStateCheckPos             token.Pos // Position of the ImportStateCheck value
+	ImportStateCheckResolved        bool      // The function body was found, so the fields below are complete
+	ImportStateCheckCountsInstances bool      // The function compares len() of the imported instance states
+	ImportStateCheckAttributes      []string  // State attribute keys the function reads (states[0].Attributes["..."])
+
+	// Import verification
+	ImportStatePos             token.Pos // Position of the ImportState field
+	HasImportStateVerifyIgnore bool      // Step lists attributes in ImportStateVerifyIgnore
+
+	// ExpectError analysis
+	ExpectErrorPos        token.Pos // Position of the ExpectError value
+	ExpectErrorPattern    string    // Regular expression passed to regexp.MustCompile, when statically known
+	HasExpectErrorPattern bool      // ExpectErrorPattern was resolved from string literals
+	// ErrorCategory is the kind of error an ExpectError step targets, classified from its
+	// pattern and config; empty for steps without ExpectError
+	ErrorCategory ErrorCategory
+
+	// AllowsDeferral is true when AdditionalCLIOptions sets AllowDeferral for plan or apply,
+	// so the step exercises deferred actions
+	AllowsDeferral bool
+	// ExternalProviders lists the entries of the step's ExternalProviders: released providers
+	// Terraform downloads for the step instead of using the provider factories
+	ExternalProviders []ExternalProvider
+
+	// Config attribute analysis
+	ConfigPos token.Pos // Position of the Config value
+	ConfigEnd token.Pos // End of the Config value
+	// ConfigAttributes maps each resource type declared in the step's config to the top-level
+	// attributes its blocks set. It is nil when the config cannot be resolved statically.
+	ConfigAttributes map[string][]string
+	// ConfigAddresses lists the addresses of the resource and data blocks declared in the
+	// step's config, e.g. example_widget.test or data.example_widget.test, in config order.
+	// It is nil when the config cannot be resolved statically.
+	ConfigAddresses []string
+	// ConfigValues lists the statically known string attributes set directly in the resource
+	// blocks of the step's config, in config order. It is nil when the config cannot be
+	// resolved statically.
+	ConfigValues []FixtureValue
+
+	// CheckPos is the position of the Check value
+	CheckPos token.Pos
+	// StateCheckPos is the position of the ConfigStateChecks value
+	StateCheckPos token.Pos
+	// CheckAddresses lists the statically known addresses passed to resource.TestCheck* and
+	// resource.TestMatch* calls in the step's Check, in source order
+	CheckAddresses []CheckAddress
+	// StateCheckAddresses lists the statically known addresses passed to statecheck functions
+	// such as statecheck.ExpectKnownValue in the step's ConfigStateChecks, in source order
+	StateCheckAddresses []CheckAddress
+	// UnresolvedChecks is true when the address or attribute of some check in the step's Check
+	// or ConfigStateChecks is not known statically, so the lists above are incomplete
+	UnresolvedChecks bool
+}
+
+// ExternalProvider is an entry of a test step's ExternalProviders map.
+type ExternalProvider struct {
+	Name              string // Key of the entry, the provider's local name, e.g. "example"
+	Source            string // e.g. "hashicorp/example"; "" when unset
+	VersionConstraint string // e.g. "1.2.0"; "" when unset or not a string literal
+}
+
+// Provides reports whether the entry is the named provider, by local name or source address.
+func (p ExternalProvider) Provides(provider string) bool {
+	return provider != "" && (p.Name == provider || strings.HasSuffix(p.Source, "/"+provider))
+}
+
+// ExternalProvider returns the entry of the step's ExternalProviders for the named provider.
+func (t *TestStepInfo) ExternalProvider(provider string) (ExternalProvider, bool) {
+	for _, p := range t.ExternalProviders {
+		if p.Provides(provider) {
+			return p, true
+		}
+	}
+	return ExternalProvider{}, false
+}
+
+// CheckAddress is a resource address passed to a state check function such as
+// resource.TestCheckResourceAttr.
+type CheckAddress struct {
+	Address  string    // e.g., "example_widget.test" or "data.example_widget.test"
+	Function string    // e.g., "TestCheckResourceAttr" or "ExpectKnownValue"
+	Pos      token.Pos // Position of the address argument
+	// Attribute is the attribute key or the first step of the attribute path checked at the
+	// address, e.g. "name" or "tags.%"; "" when the function takes none or it is not known
+	// statically
+	Attribute string
+	// Value is the knownvalue function building the value ExpectKnownValue expects, e.g.
+	// "ListSizeExact"; "" for other functions or when it is not known statically
+	Value string
+}
+
+// TopLevelAttribute returns the top-level attribute of a checked attribute key, e.g. "tags"
+// for "tags.%" or "rule" for "rule.0.name".
+func (c CheckAddress) TopLevelAttribute() string {
+	name, _, _ := strings.Cut(c.Attribute, ".")
+	return name
 }
 
 // IsUpdateStep returns true if this is not the first step and has a config.
@@ -352,10 +1284,31 @@ func (t *TestStepInfo) IsUpdateStep() bool {
 }
 
 // IsRealUpdateStep returns true if this step is a genuine update step,
-// excluding import steps and steps without configs.
+// excluding import steps, refresh steps and steps without configs.
 // This is used to distinguish real update tests from "Apply -> Import" patterns.
 func (t *TestStepInfo) IsRealUpdateStep() bool {
-	return t.StepNumber > 0 && t.HasConfig && !t.ImportState
+	return t.StepNumber > 0 && t.HasConfig && !t.ImportState && !t.RefreshState
+}
+
+// IsRefreshStep returns true if the step runs in refresh mode (RefreshState), which
+// refreshes the state of the previous step without applying a config. A difference between
+// the refreshed state and the remote object is the drift the step detects.
+func (t *TestStepInfo) IsRefreshStep() bool {
+	return t.RefreshState && !t.ImportState
+}
+
+// IsIdempotencyStep returns true if the step applies or plans the same config as the last
+// config step and expects the plan to be empty: PlanOnly without ExpectNonEmptyPlan, or a
+// plancheck.ExpectEmptyPlan plan check. Such a step shows that applying a config twice
+// converges instead of producing a perpetual diff.
+func (t *TestStepInfo) IsIdempotencyStep() bool {
+	if t.StepNumber == 0 || !t.HasConfig || t.ImportState || t.RefreshState || t.ExpectError || t.ExpectNonEmptyPlan {
+		return false
+	}
+	if t.ConfigHash == "" || t.ConfigHash != t.PreviousConfigHash {
+		return false
+	}
+	return t.PlanOnly || t.ExpectsEmptyPlan
 }
 
 // DetermineIfUpdateStep checks if a step is an update step.
@@ -363,7 +1316,7 @@ func (t *TestStepInfo) DetermineIfUpdateStep(prevStep *TestStepInfo) bool {
 	if t.StepNumber == 0 {
 		return false
 	}
-	if t.ImportState {
+	if t.ImportState || t.RefreshState {
 		return false
 	}
 	if !t.HasConfig {
@@ -380,7 +1333,48 @@ func (t *TestStepInfo) DetermineIfUpdateStep(prevStep *TestStepInfo) bool {
 
 // IsValidImportStep returns true if this step properly tests ImportState.
 func (t *TestStepInfo) IsValidImportStep() bool {
-	return t.ImportState && t.ImportStateVerify
+	return t.ImportState && (t.ImportStateVerify || t.VerifiesImportWithCheck())
+}
+
+// VerifiesImportWithCheck returns true if the step's ImportStateCheck asserts on the imported
+// state: it checks the number of instances or reads their attributes. A function whose body
+// cannot be found is given the benefit of the doubt.
+func (t *TestStepInfo) VerifiesImportWithCheck() bool {
+	if !t.HasImportStateCheck {
+		return false
+	}
+	return !t.ImportStateCheckResolved || t.ImportStateCheckCountsInstances || len(t.ImportStateCheckAttributes) > 0
+}
+
+// ExercisesDeferral reports whether any step of the test lets Terraform defer changes.
+func (t *TestFunctionInfo) ExercisesDeferral() bool {
+	for _, step := range t.TestSteps {
+		if step.AllowsDeferral {
+			return true
+		}
+	}
+	return false
+}
+
+// ExercisesRefresh reports whether any step of the test runs in refresh mode.
+func (t *TestFunctionInfo) ExercisesRefresh() bool {
+	for i := range t.TestSteps {
+		if t.TestSteps[i].IsRefreshStep() {
+			return true
+		}
+	}
+	return false
+}
+
+// ExercisesIdempotency reports whether any step of the test re-plans the previous config and
+// expects an empty plan.
+func (t *TestFunctionInfo) ExercisesIdempotency() bool {
+	for i := range t.TestSteps {
+		if t.TestSteps[i].IsIdempotencyStep() {
+			return true
+		}
+	}
+	return false
 }
 
 // HasStateOrPlanCheck returns true if this test function has at least one step
@@ -394,6 +1388,53 @@ func (t *TestFunctionInfo) HasStateOrPlanCheck() bool {
 	return false
 }
 
+// Asserts returns true if the step asserts anything beyond its config applying: it sets
+// Check, ConfigStateChecks or ConfigPlanChecks, or expects an error.
+func (t *TestStepInfo) Asserts() bool {
+	return t.HasCheck || t.HasConfigStateChecks || t.HasPlanCheck || t.ExpectError
+}
+
+// IsWeak returns true if the test applies configs but none of its steps asserts anything, so
+// it only shows that apply does not fail. Tests with unresolved steps are not weak, as those
+// steps may assert.
+func (t *TestFunctionInfo) IsWeak() bool {
+	if t.HasOpaqueSteps {
+		return false
+	}
+	applies := false
+	for i := range t.TestSteps {
+		if t.TestSteps[i].Asserts() {
+			return false
+		}
+		if t.TestSteps[i].HasConfig {
+			applies = true
+		}
+	}
+	return applies
+}
+
+// IsConditional reports whether the test only runs, in full, under some conditions, so the
+// coverage it earns is conditional.
+func (t *TestFunctionInfo) IsConditional() bool {
+	return len(t.Conditions) > 0
+}
+
+// Scenarios returns the number of scenarios the test runs: one per subtest running a
+// TestCase of its own, or one for a test without any.
+func (t *TestFunctionInfo) Scenarios() int {
+	return max(len(t.Subtests), 1)
+}
+
+// ScenarioCount returns the number of scenarios the tests run, counting each subtest that
+// runs a TestCase of its own.
+func ScenarioCount(tests []*TestFunctionInfo) int {
+	count := 0
+	for _, test := range tests {
+		count += test.Scenarios()
+	}
+	return count
+}
+
 // ResourceCoverage represents aggregated test coverage for a single resource or data source.
 type ResourceCoverage struct {
 	Resource         *ResourceInfo
@@ -405,6 +1446,10 @@ type ResourceCoverage struct {
 	HasImportTest    bool // At least one test has ImportState step
 	HasUpdateTest    bool // At least one test has update steps (multiple configs)
 	HasErrorTest     bool // At least one test has ExpectError
+	HasDisappearsTest bool // At least one test is a disappears test
+	HasRefreshTest   bool // At least one test has a RefreshState step
+	HasIdempotencyStep bool // At least one test re-plans its config and expects an empty plan
+	HasOpaqueSteps   bool // At least one test has steps that could not be resolved statically
 	TestCount        int
 	StepCount        int
 	UpdateStepCount  int
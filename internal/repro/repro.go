@@ -0,0 +1,256 @@
+// Package repro builds reproducer bundles for bug reports: a zip of the source files that
+// decide how one definition is discovered and linked, with the HCL values of their test
+// configurations scrubbed, the effective settings, and the decisions the discovery and the
+// linker made about them.
+package repro
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// Version is the version of the bundle layout.
+const Version = 1
+
+// Names of the entries of a bundle. Source files are stored under SourceDir with their paths
+// relative to the repository root.
+const (
+	ReadmeFile    = "README.txt"
+	DecisionsFile = "decisions.json"
+	SettingsFile  = "settings.yaml"
+	SourceDir     = "provider"
+)
+
+// Roles of the files of a bundle.
+const (
+	RoleDefinition   = "definition"   // Declares a queried definition
+	RoleRegistration = "registration" // Registers a queried definition with the provider
+	RoleTest         = "test"         // Holds a linked or rejected candidate test
+	RoleCompeting    = "competing"    // Declares a definition a bundled test is linked to instead
+	RoleExcluded     = "excluded"     // Skipped during discovery
+)
+
+// rolePriority orders roles when one file plays several.
+var rolePriority = map[string]int{RoleDefinition: 0, RoleRegistration: 1, RoleTest: 2, RoleCompeting: 3, RoleExcluded: 4}
+
+// Bundle is a reproducer for one -explain query. Encoded as JSON it is the decision log of
+// the bundle.
+type Bundle struct {
+	Version     int                       `json:"version"`
+	Query       string                    `json:"query"`
+	Explanation *analysis.Explanation     `json:"explanation"`
+	Candidates  []analysis.TestCandidates `json:"candidates"` // Linker candidates of each test in the bundle
+	Files       []File                    `json:"files"`
+	Settings    config.Settings           `json:"-"` // Written as SettingsFile
+}
+
+// File is a source file of a bundle.
+type File struct {
+	Path     string `json:"path"` // Slash-separated, relative to the repository root
+	Role     string `json:"role"`
+	Scrubbed int    `json:"scrubbed_values"` // HCL string values replaced by Placeholder
+	// Redacted is the number of HCL configurations replaced as a whole by their digest,
+	// with the redact-configs setting
+	Redacted int    `json:"redacted_configs,omitempty"`
+	Data     []byte `json:"-"`
+}
+
+// Collect builds the bundle of a query, reading the files that declare or register the
+// matching definitions, the files of their candidate tests, the files declaring the other
+// definitions those tests are linked to and the matching files skipped during discovery.
+// Paths are made relative to root, the repository root.
+func Collect(query string, reg *registry.ResourceRegistry, fset *token.FileSet, files []*ast.File, settings config.Settings, root string) (*Bundle, error) {
+	exp := analysis.Explain(query, reg, fset, files, &settings)
+
+	roles := make(map[string]string)
+	add := func(path, role string) {
+		if path == "" {
+			return
+		}
+		if current, ok := roles[path]; ok && rolePriority[current] <= rolePriority[role] {
+			return
+		}
+		roles[path] = role
+	}
+	tests := make(map[string]bool) // file + "\x00" + test name
+	for _, def := range exp.Definitions {
+		add(def.File, RoleDefinition)
+		if def.RegisteredAt != "" {
+			add(def.RegisteredAt[:strings.LastIndex(def.RegisteredAt, ":")], RoleRegistration)
+		}
+		for _, c := range append(append([]analysis.CandidateTest(nil), def.Linked...), def.Rejected...) {
+			add(c.File, RoleTest)
+			tests[c.File+"\x00"+c.Test] = true
+		}
+	}
+	for _, e := range exp.Excluded {
+		add(e.File, RoleExcluded)
+	}
+	for _, e := range exp.ExcludedTests {
+		add(e.File, RoleExcluded)
+	}
+	if len(roles) == 0 {
+		return nil, fmt.Errorf("nothing to bundle for %q: no definition, candidate test or excluded file matches", query)
+	}
+
+	bundle := &Bundle{Version: Version, Query: query, Explanation: exp, Candidates: []analysis.TestCandidates{}, Settings: settings}
+	for _, fn := range reg.GetAllTestFunctions() {
+		if !tests[fn.FilePath+"\x00"+fn.Name] {
+			continue
+		}
+		candidates := analysis.MatchCandidates(reg, fset, fn)
+		bundle.Candidates = append(bundle.Candidates, candidates)
+		// Without the definition a test is linked to, it could link to the queried one
+		if key, ok := registry.ParseResourceKey(candidates.Linked); ok {
+			if info := reg.GetDefinition(key.Kind, key.Name); info != nil {
+				add(info.FilePath, RoleCompeting)
+			}
+		}
+	}
+	sort.Slice(bundle.Candidates, func(i, j int) bool {
+		a, b := bundle.Candidates[i], bundle.Candidates[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Test < b.Test
+	})
+
+	for path, role := range roles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		file := File{Path: bundlePath(root, path), Role: role, Data: data}
+		if strings.HasSuffix(path, ".go") && settings.RedactConfigs {
+			redacted, count, err := RedactGoSource(data, settings.RedactConfig)
+			if err != nil {
+				return nil, fmt.Errorf("redacting %s: %w", path, err)
+			}
+			file.Data, file.Redacted = redacted, count
+		} else if strings.HasSuffix(path, ".go") {
+			scrubbed, count, err := ScrubGoSource(data)
+			if err != nil {
+				return nil, fmt.Errorf("scrubbing %s: %w", path, err)
+			}
+			file.Data, file.Scrubbed = scrubbed, count
+		}
+		bundle.Files = append(bundle.Files, file)
+	}
+	sort.Slice(bundle.Files, func(i, j int) bool { return bundle.Files[i].Path < bundle.Files[j].Path })
+	return bundle, nil
+}
+
+// Scrubbed returns how many HCL string values were scrubbed from the files of the bundle.
+func (b *Bundle) Scrubbed() int {
+	total := 0
+	for _, f := range b.Files {
+		total += f.Scrubbed
+	}
+	return total
+}
+
+// Redacted returns how many HCL configurations were replaced by their digest in the files of
+// the bundle.
+func (b *Bundle) Redacted() int {
+	total := 0
+	for _, f := range b.Files {
+		total += f.Redacted
+	}
+	return total
+}
+
+// WriteZip writes the bundle as a zip archive.
+func (b *Bundle) WriteZip(w io.Writer) error {
+	zw := zip.NewWriter(w)
+	write := func(name string, data []byte) error {
+		entry, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = entry.Write(data)
+		return err
+	}
+
+	if err := write(ReadmeFile, []byte(b.readme())); err != nil {
+		return err
+	}
+	decisions, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding decisions: %w", err)
+	}
+	if err := write(DecisionsFile, append(decisions, '\n')); err != nil {
+		return err
+	}
+	settings, err := yaml.Marshal(b.Settings)
+	if err != nil {
+		return fmt.Errorf("encoding settings: %w", err)
+	}
+	if err := write(SettingsFile, settings); err != nil {
+		return err
+	}
+	for _, f := range b.Files {
+		if err := write(SourceDir+"/"+f.Path, f.Data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// readme describes the bundle to whoever triages it.
+func (b *Bundle) readme() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Reproducer for: validate -explain %q\n\n", b.Query)
+	fmt.Fprintf(&sb, "%-16s explanation and linker candidates of each bundled test\n", DecisionsFile)
+	fmt.Fprintf(&sb, "%-16s effective settings of the run\n", SettingsFile)
+	fmt.Fprintf(&sb, "%-16s source files, paths relative to the repository root:\n", SourceDir+"/")
+	for _, f := range b.Files {
+		fmt.Fprintf(&sb, "  %s (%s", f.Path, f.Role)
+		if f.Scrubbed > 0 {
+			fmt.Fprintf(&sb, ", %d HCL values scrubbed", f.Scrubbed)
+		}
+		if f.Redacted > 0 {
+			fmt.Fprintf(&sb, ", %d HCL configs redacted", f.Redacted)
+		}
+		sb.WriteString(")\n")
+	}
+	if b.Settings.RedactConfigs {
+		sb.WriteString("\nWith redact-configs, each test configuration was replaced as a whole by its digest,\n")
+		sb.WriteString("so tests that link through their configs may not link as they did. Only the files\n")
+	} else {
+		fmt.Fprintf(&sb, "\nHCL string values in test configurations were replaced by %q; block labels,\n", Placeholder)
+		sb.WriteString("interpolations and format verbs were kept, so tests link as they did. Only the files\n")
+	}
+	sb.WriteString("above are included, so helpers defined elsewhere are missing.\n\n")
+	fmt.Fprintf(&sb, "To reproduce: validate -provider %s -explain %q\n", SourceDir, b.Query)
+	return sb.String()
+}
+
+// bundlePath returns path relative to root with forward slashes. Paths outside root are
+// stored under their base name.
+func bundlePath(root, path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if abs, err := filepath.Abs(root); err == nil {
+		root = abs
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.Base(path)
+	}
+	return filepath.ToSlash(rel)
+}
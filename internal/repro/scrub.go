@@ -0,0 +1,201 @@
+package repro
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Placeholder replaces the text of a scrubbed HCL string value.
+const Placeholder = "redacted"
+
+var (
+	// hclBlockHeader matches a block header line such as `resource "example_widget" "test" {`,
+	// whose labels decide how a test is linked and are kept. Labels may be format verbs.
+	hclBlockHeader = regexp.MustCompile(`^\s*[A-Za-z_][\w-]*(\s+("[^"]*"|[^\s"{]+))*\s*\{\s*$`)
+	// hclAssignment matches an attribute set to a string, such as `name = "test"`.
+	hclAssignment = regexp.MustCompile(`(?m)^\s*[A-Za-z_][\w-]*\s*=\s*"`)
+	// formatVerb matches a fmt verb, such as %s or %[1]q, at the start of a string.
+	formatVerb = regexp.MustCompile(`^%(\[\d+\])?[-+# 0]*(\d+|\*)?(\.\d+)?[a-zA-Z%]`)
+)
+
+// ScrubGoSource replaces the string values of the HCL configurations held in the string
+// literals of a Go source file, returning the scrubbed source and how many values were
+// replaced. Everything outside those literals, including check values, is left as is.
+func ScrubGoSource(src []byte) ([]byte, int, error) {
+	return rewriteHCLLiterals(src, ScrubHCL)
+}
+
+// RedactGoSource replaces each HCL configuration held in a string literal of a Go source
+// file as a whole with redact of its text, returning the redacted source and how many
+// configurations were replaced.
+func RedactGoSource(src []byte, redact func(string) string) ([]byte, int, error) {
+	return rewriteHCLLiterals(src, func(config string) (string, int) {
+		return redact(config), 1
+	})
+}
+
+// rewriteHCLLiterals rewrites the string literals of a Go source file that hold HCL
+// configurations with rewrite, which returns the new text and how many changes it made.
+// Literals it leaves unchanged are kept as written.
+func rewriteHCLLiterals(src []byte, rewrite func(string) (string, int)) ([]byte, int, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	type edit struct {
+		start, end int
+		text       string
+	}
+	var edits []edit
+	total := 0
+	ast.Inspect(file, func(n ast.Node) bool {
+		lit, ok := n.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		value, err := strconv.Unquote(lit.Value)
+		if err != nil || !looksLikeHCL(value) {
+			return true
+		}
+		scrubbed, count := rewrite(value)
+		if count == 0 {
+			return true
+		}
+		text := strconv.Quote(scrubbed)
+		if strings.HasPrefix(lit.Value, "`") {
+			text = "`" + scrubbed + "`"
+		}
+		start := fset.Position(lit.Pos()).Offset
+		edits = append(edits, edit{start: start, end: start + len(lit.Value), text: text})
+		total += count
+		return true
+	})
+
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	out := append([]byte(nil), src...)
+	for _, e := range edits {
+		out = append(out[:e.start], append([]byte(e.text), out[e.end:]...)...)
+	}
+	return out, total, nil
+}
+
+// looksLikeHCL reports whether a string holds (part of) an HCL configuration.
+func looksLikeHCL(s string) bool {
+	if hclAssignment.MatchString(s) {
+		return true
+	}
+	for _, line := range strings.Split(s, "\n") {
+		if hclBlockHeader.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// ScrubHCL replaces the text of the quoted strings of an HCL configuration with Placeholder,
+// returning the scrubbed configuration and how many strings were changed. Block labels,
+// ${...} and %{...} templates and fmt verbs are kept, so the configuration still links to
+// the same definitions; heredocs are not scrubbed.
+func ScrubHCL(config string) (string, int) {
+	var out strings.Builder
+	total := 0
+	for _, line := range strings.SplitAfter(config, "\n") {
+		if hclBlockHeader.MatchString(strings.TrimRight(line, "\r\n")) {
+			out.WriteString(line)
+			continue
+		}
+		scrubbed, count := scrubLine(line)
+		out.WriteString(scrubbed)
+		total += count
+	}
+	return out.String(), total
+}
+
+// scrubLine scrubs the quoted strings of one line. An unterminated string is left as is.
+func scrubLine(line string) (string, int) {
+	var out strings.Builder
+	count := 0
+	for i := 0; i < len(line); {
+		if line[i] != '"' {
+			out.WriteByte(line[i])
+			i++
+			continue
+		}
+		value, end, ok := scrubString(line, i+1)
+		if !ok {
+			out.WriteString(line[i:])
+			break
+		}
+		out.WriteString(`"` + value + `"`)
+		if value != line[i+1:end] {
+			count++
+		}
+		i = end + 1
+	}
+	return out.String(), count
+}
+
+// scrubString scrubs the string starting at start, just after its opening quote, returning
+// the scrubbed text and the index of the closing quote.
+func scrubString(line string, start int) (string, int, bool) {
+	var out strings.Builder
+	literal := false // Whether text has been dropped since the last kept part
+	flush := func() {
+		if literal {
+			out.WriteString(Placeholder)
+			literal = false
+		}
+	}
+	for i := start; i < len(line); {
+		switch {
+		case line[i] == '"':
+			flush()
+			return out.String(), i, true
+		case line[i] == '\\' && i+1 < len(line):
+			literal = true
+			i += 2
+		case strings.HasPrefix(line[i:], "${") || strings.HasPrefix(line[i:], "%{"):
+			end := templateEnd(line, i+2)
+			if end == -1 {
+				return "", 0, false
+			}
+			flush()
+			out.WriteString(line[i : end+1])
+			i = end + 1
+		case line[i] == '%' && formatVerb.MatchString(line[i:]):
+			verb := formatVerb.FindString(line[i:])
+			flush()
+			out.WriteString(verb)
+			i += len(verb)
+		default:
+			literal = true
+			i++
+		}
+	}
+	return "", 0, false
+}
+
+// templateEnd returns the index of the brace closing a template whose body starts at start,
+// or -1 when it is not closed on the line.
+func templateEnd(line string, start int) int {
+	depth := 1
+	for i := start; i < len(line); i++ {
+		switch line[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,440 @@
+// Package requirements loads the coverage requirements manifest, a
+// tfprovidertest.requirements.yaml file checked into the provider repository in which
+// maintainers declare the tests each resource, data source and action must have:
+//
+//	strict: true
+//	resources:
+//	  widget:
+//	    require: [basic, update, import, error, disappears]
+//	    exempt:
+//	      error: "schema has no validators"
+//	  widget_v2:
+//	    maturity: experimental
+//	data-sources:
+//	  widget:
+//	    require: [basic]
+//	quarantine:
+//	  TestAccWidget_timeouts: "flaky upstream API"
+//
+// Apply merges the manifest into the coverage directives of a registry, alongside any
+// //tftest:expect and //tftest:exempt comments, so each coverage rule enforces the checks it
+// owns, and adds its quarantine list to the registry. Sync scaffolds entries for definitions the manifest does not list yet.
+package requirements
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// ManifestFile is the file name looked up by Find.
+const ManifestFile = "tfprovidertest.requirements.yaml"
+
+// Off disables the manifest when used as the requirements-manifest setting.
+const Off = "off"
+
+// Manifest sections, one per definition kind.
+const (
+	sectionResources   = "resources"
+	sectionDataSources = "data-sources"
+	sectionActions     = "actions"
+)
+
+// Manifest is a parsed requirements manifest.
+type Manifest struct {
+	// Path is the file the manifest was loaded from.
+	Path string `yaml:"-"`
+	// Strict reports definitions without an entry, so new resources cannot be added
+	// without deciding what coverage they need.
+	Strict      bool             `yaml:"strict,omitempty"`
+	Resources   map[string]Entry `yaml:"resources,omitempty"`
+	DataSources map[string]Entry `yaml:"data-sources,omitempty"`
+	Actions     map[string]Entry `yaml:"actions,omitempty"`
+	// Quarantine maps test names, or globs such as TestAccWidget_*, to the reason the tests
+	// are known to be flaky or disabled. Quarantined tests earn no coverage credit.
+	Quarantine map[string]string `yaml:"quarantine,omitempty"`
+}
+
+// Entry declares the coverage required of one definition.
+type Entry struct {
+	// Require lists checks (see registry.DirectiveChecks) the definition's tests must provide.
+	Require []string `yaml:"require,omitempty,flow"`
+	// Exempt maps checks that are never reported for the definition to the reason why.
+	Exempt map[string]string `yaml:"exempt,omitempty"`
+	// Maturity is the definition's maturity level (see registry.MaturityLevels), which
+	// selects the checks the maturity-exemptions setting exempts it from.
+	Maturity string `yaml:"maturity,omitempty"`
+}
+
+// Section returns the entries for a definition kind, or nil for an unknown kind.
+func (m *Manifest) Section(kind registry.ResourceKind) map[string]Entry {
+	switch kind {
+	case registry.KindResource:
+		return m.Resources
+	case registry.KindDataSource:
+		return m.DataSources
+	case registry.KindAction:
+		return m.Actions
+	default:
+		return nil
+	}
+}
+
+// Lookup returns the entry for a definition.
+func (m *Manifest) Lookup(kind registry.ResourceKind, name string) (Entry, bool) {
+	entry, ok := m.Section(kind)[name]
+	return entry, ok
+}
+
+// Keys returns every definition the manifest lists, sorted by kind and name.
+func (m *Manifest) Keys() []registry.ResourceKey {
+	var keys []registry.ResourceKey
+	for _, kind := range kinds() {
+		for name := range m.Section(kind) {
+			keys = append(keys, registry.ResourceKey{Kind: kind, Name: name})
+		}
+	}
+	slices.SortFunc(keys, registry.ResourceKey.Compare)
+	return keys
+}
+
+// Parse parses and validates manifest data. path is recorded on the manifest and used in
+// error messages.
+func Parse(data []byte, path string) (*Manifest, error) {
+	m := &Manifest{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(m); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	m.Path = path
+	if err := m.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+// Load reads and parses the manifest at path.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data, path)
+}
+
+// validate reports unknown checks, checks that do not apply to a section's kind, and
+// checks that an entry both requires and exempts.
+func (m *Manifest) validate() error {
+	for _, kind := range kinds() {
+		section := sectionName(kind)
+		for _, name := range slices.Sorted(maps.Keys(m.Section(kind))) {
+			entry := m.Section(kind)[name]
+			if strings.TrimSpace(name) == "" {
+				return fmt.Errorf("%s: empty definition name", section)
+			}
+			if entry.Maturity != "" && !knownMaturity(entry.Maturity) {
+				return fmt.Errorf("%s.%s: unknown maturity %q (known levels: %s)", section, name, entry.Maturity, strings.Join(registry.MaturityLevels(), ", "))
+			}
+			checks := append(slices.Clone(entry.Require), slices.Sorted(maps.Keys(entry.Exempt))...)
+			for _, check := range checks {
+				if !knownCheck(check) {
+					return fmt.Errorf("%s.%s: unknown check %q (known checks: %s)", section, name, check, strings.Join(registry.DirectiveChecks(), ", "))
+				}
+				if !registry.CheckApplies(kind, check) {
+					return fmt.Errorf("%s.%s: check %q does not apply to %s", section, name, check, section)
+				}
+			}
+			for _, check := range entry.Require {
+				if _, ok := entry.Exempt[check]; ok {
+					return fmt.Errorf("%s.%s: check %q is both required and exempt", section, name, check)
+				}
+			}
+		}
+	}
+	for _, pattern := range slices.Sorted(maps.Keys(m.Quarantine)) {
+		if strings.TrimSpace(pattern) == "" {
+			return fmt.Errorf("quarantine: empty test name")
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("quarantine.%s: invalid pattern: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Find looks for ManifestFile in dir and its parents, stopping at the module root (the first
+// directory containing go.mod). It returns "" when there is no manifest.
+func Find(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		candidate := filepath.Join(dir, ManifestFile)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate
+		}
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return ""
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// Resolve returns the manifest path selected by the requirements-manifest setting: the
+// configured path, the manifest Find locates from dir when the setting is empty, or ""
+// when the setting is Off or no manifest exists.
+func Resolve(setting, dir string) string {
+	switch setting {
+	case Off:
+		return ""
+	case "":
+		return Find(dir)
+	default:
+		return setting
+	}
+}
+
+// Apply merges the manifest into the coverage directives of the registry's definitions.
+// Entries naming definitions outside the registry are ignored, since a registry usually
+// covers a single package. A check that a //tftest: directive declares the opposite way
+// keeps the directive's setting and is recorded as a conflict, and a //tftest:maturity
+// directive takes precedence over the entry's maturity; in strict mode, definitions without
+// an entry are recorded as unlisted. The quarantine list is added to the registry for
+// discovery.ApplyQuarantine.
+func Apply(reg *registry.ResourceRegistry, m *Manifest) {
+	for pattern, reason := range m.Quarantine {
+		reg.AddQuarantine(pattern, reason)
+	}
+	for _, def := range reg.GetSortedDefinitions() {
+		entry, ok := m.Lookup(def.Kind, def.Name)
+		if !ok {
+			if m.Strict {
+				reg.AddRequirementIssue(issue(m, def, registry.RequirementUnlisted, ""))
+			}
+			continue
+		}
+
+		if def.Maturity == "" {
+			def.Maturity = entry.Maturity
+		}
+		if def.Directives == nil {
+			def.Directives = &registry.CoverageDirectives{
+				Expect: make(map[string]bool),
+				Exempt: make(map[string]string),
+			}
+		}
+		directives := def.Directives
+		directives.Manifest = m.Path
+		if directives.FromManifest == nil {
+			directives.FromManifest = make(map[string]bool)
+		}
+
+		for _, check := range entry.Require {
+			if directives.Exempts(check) {
+				reg.AddRequirementIssue(issue(m, def, registry.RequirementConflict, check))
+				continue
+			}
+			if !directives.Expects(check) {
+				directives.Expect[check] = true
+				directives.FromManifest[check] = true
+			}
+		}
+		for _, check := range slices.Sorted(maps.Keys(entry.Exempt)) {
+			if directives.Expects(check) {
+				reg.AddRequirementIssue(issue(m, def, registry.RequirementConflict, check))
+				continue
+			}
+			if !directives.Exempts(check) {
+				directives.Exempt[check] = entry.Exempt[check]
+				directives.FromManifest[check] = true
+			}
+		}
+	}
+}
+
+// issue builds a RequirementIssue about a definition.
+func issue(m *Manifest, def *registry.ResourceInfo, problem registry.RequirementProblem, value string) registry.RequirementIssue {
+	return registry.RequirementIssue{
+		Manifest:   m.Path,
+		Problem:    problem,
+		Value:      value,
+		Target:     def.Name,
+		TargetKind: def.Kind,
+		Pos:        def.SchemaPos,
+	}
+}
+
+// SyncResult reports what Sync changed.
+type SyncResult struct {
+	Path    string
+	Created bool                   // The manifest did not exist before
+	Added   []registry.ResourceKey // Definitions given a scaffolded entry
+	Stale   []registry.ResourceKey // Entries naming no discovered definition; left in place
+}
+
+// Sync adds an entry built by scaffold for every definition the manifest at path does not
+// list, creating the file when it does not exist. Existing entries, their order and
+// comments are preserved; entries for definitions that no longer exist are reported as
+// stale rather than removed, since the definition may live outside the scanned tree.
+func Sync(path string, defs []*registry.ResourceInfo, scaffold func(*registry.ResourceInfo) Entry) (*SyncResult, error) {
+	result := &SyncResult{Path: path}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		result.Created = true
+	case err != nil:
+		return nil, err
+	}
+
+	m, err := Parse(data, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, HeadComment: fmt.Sprintf(
+			"Coverage requirements enforced by tfprovidertest.\n"+
+				"Checks: %s", strings.Join(registry.DirectiveChecks(), ", "))}
+	}
+	if len(doc.Content) == 0 {
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s: expected a mapping at the top level", path)
+	}
+
+	present := make(map[registry.ResourceKey]bool)
+	sorted := append([]*registry.ResourceInfo(nil), defs...)
+	slices.SortFunc(sorted, func(a, b *registry.ResourceInfo) int { return a.Key().Compare(b.Key()) })
+	for _, def := range sorted {
+		key := def.Key()
+		if sectionName(def.Kind) == "" || present[key] {
+			continue
+		}
+		present[key] = true
+		if _, ok := m.Lookup(def.Kind, def.Name); ok {
+			continue
+		}
+
+		var value yaml.Node
+		if err := value.Encode(scaffold(def)); err != nil {
+			return nil, err
+		}
+		section := sectionNode(root, sectionName(def.Kind))
+		section.Content = append(section.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: def.Name},
+			&value,
+		)
+		result.Added = append(result.Added, key)
+	}
+
+	for _, key := range m.Keys() {
+		if !present[key] {
+			result.Stale = append(result.Stale, key)
+		}
+	}
+
+	if len(result.Added) == 0 && !result.Created {
+		return result, nil
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// sectionNode returns the mapping node of a top-level section, appending it if missing.
+func sectionNode(root *yaml.Node, name string) *yaml.Node {
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == name {
+			value := root.Content[i+1]
+			if value.Kind != yaml.MappingNode {
+				// An empty section ("resources:") decodes as null
+				*value = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			}
+			return value
+		}
+	}
+	value := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name}, value)
+	return value
+}
+
+// kinds returns the definition kinds with a manifest section, in file order. Kinds added
+// with registry.RegisterKind have no section.
+func kinds() []registry.ResourceKind {
+	var result []registry.ResourceKind
+	for _, kind := range registry.Kinds() {
+		if sectionName(kind) != "" {
+			result = append(result, kind)
+		}
+	}
+	return result
+}
+
+// sectionName returns the manifest section for a kind, or "" if it has none.
+func sectionName(kind registry.ResourceKind) string {
+	switch kind {
+	case registry.KindResource:
+		return sectionResources
+	case registry.KindDataSource:
+		return sectionDataSources
+	case registry.KindAction:
+		return sectionActions
+	default:
+		return ""
+	}
+}
+
+// knownCheck reports whether check is a recognized check name.
+func knownCheck(check string) bool {
+	for _, known := range registry.DirectiveChecks() {
+		if check == known {
+			return true
+		}
+	}
+	return false
+}
+
+// knownMaturity reports whether level is a recognized maturity level.
+func knownMaturity(level string) bool {
+	for _, known := range registry.MaturityLevels() {
+		if level == known {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package routing
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// CodeownersPaths are the locations GitHub reads a CODEOWNERS file from, relative to the
+// repository root, in the order it looks for them.
+var CodeownersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// Codeowners holds the rules of a CODEOWNERS file. A nil *Codeowners owns nothing.
+type Codeowners struct {
+	rules []ownerRule
+}
+
+type ownerRule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+// ParseCodeowners parses CODEOWNERS data: one gitignore-style pattern per line followed by its
+// owners, with # comments. A pattern without owners removes the ownership an earlier pattern
+// gave the paths it matches.
+func ParseCodeowners(data []byte) (*Codeowners, error) {
+	c := &Codeowners{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if i := strings.Index(text, "#"); i != -1 {
+			text = text[:i]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		pattern, err := ownerPattern(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		c.rules = append(c.rules, ownerRule{pattern: pattern, owners: fields[1:]})
+	}
+	return c, scanner.Err()
+}
+
+// LoadCodeowners reads the CODEOWNERS file of the repository at root from the first of
+// CodeownersPaths that exists, returning its path. Without one it returns nil and no error.
+func LoadCodeowners(root string) (*Codeowners, string, error) {
+	for _, rel := range CodeownersPaths {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, path, err
+		}
+		c, err := ParseCodeowners(data)
+		if err != nil {
+			return nil, path, fmt.Errorf("%s: %w", path, err)
+		}
+		return c, path, nil
+	}
+	return nil, "", nil
+}
+
+// Owners returns the owners of a slash-separated path relative to the repository root. As on
+// GitHub, the last matching pattern wins.
+func (c *Codeowners) Owners(path string) []string {
+	if c == nil {
+		return nil
+	}
+	for i := len(c.rules) - 1; i >= 0; i-- {
+		if c.rules[i].pattern.MatchString(path) {
+			return c.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// ownerPattern translates a CODEOWNERS pattern to a regular expression over slash-separated
+// paths. A pattern with a leading or inner slash is anchored at the root, others match at any
+// depth; a pattern naming a directory matches everything inside it.
+func ownerPattern(pattern string) (*regexp.Regexp, error) {
+	if strings.HasPrefix(pattern, "!") || strings.Contains(pattern, "[") {
+		return nil, fmt.Errorf("unsupported pattern %q", pattern)
+	}
+	dirOnly := strings.HasSuffix(pattern, "/")
+	trimmed := strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(trimmed, "/")
+	trimmed = strings.TrimPrefix(trimmed, "/")
+
+	var expr strings.Builder
+	if anchored {
+		expr.WriteString("^")
+	} else {
+		expr.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(trimmed); i++ {
+		switch {
+		case strings.HasPrefix(trimmed[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(trimmed[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case trimmed[i] == '*':
+			expr.WriteString("[^/]*")
+		case trimmed[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(trimmed[i : i+1]))
+		}
+	}
+	if dirOnly {
+		expr.WriteString("/.*$")
+	} else {
+		expr.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(expr.String())
+}
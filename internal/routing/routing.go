@@ -0,0 +1,236 @@
+// Package routing builds the routing file (routing.json) that lets an issue-filing bot track
+// findings: findings are grouped into one issue per definition, or per file for findings no
+// definition is attributed to, with the owners CODEOWNERS assigns, suggested GitHub labels and
+// keys that stay the same across runs, so the bot can update an existing issue instead of
+// opening a new one.
+package routing
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/enforcement"
+	"github.com/example/tfprovidertest/internal/rules"
+)
+
+// Version is the version of the routing file format.
+const Version = 1
+
+// Labels suggested for the issues of the routing file.
+const (
+	LabelTool     = "tfprovidertest"
+	LabelCoverage = "tests/coverage"
+	LabelQuality  = "tests/quality"
+	LabelWarning  = "warning-only" // Every finding of the issue is a warning
+)
+
+// Finding is a finding to route. Paths are absolute or relative to the working directory.
+type Finding struct {
+	Rule     string
+	Code     string
+	Group    string
+	Level    string
+	File     string
+	Line     int
+	Message  string
+	URL      string
+	Resource string // Definition the finding is attributed to, if any
+	Kind     string // Kind of the definition, e.g. "data source"
+	// DefinitionFile is the file declaring the definition, whose owners own the issue
+	DefinitionFile string
+}
+
+// File is the content of a routing file.
+type File struct {
+	Version    int     `json:"version"`
+	Codeowners string  `json:"codeowners,omitempty"` // CODEOWNERS file owners were read from
+	Issues     []Issue `json:"issues"`
+}
+
+// Issue is the tracking issue of one definition, or of one file for findings attributed to no
+// definition.
+type Issue struct {
+	// Key identifies the issue across runs, e.g. "tfprovidertest/resource/widget"
+	Key      string   `json:"key"`
+	Title    string   `json:"title"`
+	Kind     string   `json:"kind,omitempty"`
+	Resource string   `json:"resource,omitempty"`
+	File     string   `json:"file"` // Definition file, or the file of the findings
+	Owners   []string `json:"owners"`
+	Labels   []string `json:"labels"`
+	Findings []Entry  `json:"findings"`
+}
+
+// Entry is a finding of an issue.
+type Entry struct {
+	// Key identifies the finding across runs: it does not change when lines above it move
+	Key     string   `json:"key"`
+	Rule    string   `json:"rule"`
+	Code    string   `json:"code,omitempty"`
+	Level   string   `json:"level"`
+	File    string   `json:"file"`
+	Line    int      `json:"line"`
+	Message string   `json:"message"`
+	URL     string   `json:"url,omitempty"`
+	Owners  []string `json:"owners"` // Owners of the finding's file, which may differ from the issue's
+}
+
+// Build groups findings into issues, with paths relative to root, the repository root
+// CODEOWNERS patterns are relative to. Issues are ordered by key and their findings by
+// position, so unchanged findings produce an identical file.
+func Build(findings []Finding, root string, owners *Codeowners) File {
+	issues := make(map[string]*Issue)
+	for _, f := range findings {
+		path := RelativePath(root, f.File)
+		key, issueFile := "", path
+		if f.Resource != "" {
+			key = LabelTool + "/" + strings.ReplaceAll(f.Kind, " ", "_") + "/" + f.Resource
+			if f.DefinitionFile != "" {
+				issueFile = RelativePath(root, f.DefinitionFile)
+			}
+		} else {
+			key = LabelTool + "/file/" + path
+		}
+		issue, ok := issues[key]
+		if !ok {
+			issue = &Issue{
+				Key:      key,
+				Title:    issueTitle(f.Kind, f.Resource, path),
+				Kind:     f.Kind,
+				Resource: f.Resource,
+				File:     issueFile,
+				Owners:   ownersOf(owners, issueFile),
+			}
+			issues[key] = issue
+		}
+		summary, _, _ := strings.Cut(f.Message, "\n")
+		issue.Findings = append(issue.Findings, Entry{
+			Key:     Fingerprint(f.Rule, path, summary),
+			Rule:    f.Rule,
+			Code:    f.Code,
+			Level:   f.Level,
+			File:    path,
+			Line:    f.Line,
+			Message: f.Message,
+			URL:     f.URL,
+			Owners:  ownersOf(owners, path),
+		})
+		issue.Labels = mergeLabels(issue.Labels, f)
+	}
+
+	file := File{Version: Version, Issues: make([]Issue, 0, len(issues))}
+	for _, issue := range issues {
+		sort.SliceStable(issue.Findings, func(i, j int) bool {
+			a, b := issue.Findings[i], issue.Findings[j]
+			if a.File != b.File {
+				return a.File < b.File
+			}
+			if a.Line != b.Line {
+				return a.Line < b.Line
+			}
+			return a.Rule < b.Rule
+		})
+		if !allWarnings(issue.Findings) {
+			issue.Labels = removeLabel(issue.Labels, LabelWarning)
+		}
+		file.Issues = append(file.Issues, *issue)
+	}
+	sort.Slice(file.Issues, func(i, j int) bool { return file.Issues[i].Key < file.Issues[j].Key })
+	return file
+}
+
+// Fingerprint identifies a finding by rule, file and the summary line of its message but not
+// by line number, so it keeps its identity when unrelated lines above it change, or when the
+// finding moves between error and warning.
+func Fingerprint(rule, path, summary string) string {
+	summary = strings.TrimPrefix(summary, enforcement.WarningPrefix)
+	sum := sha256.Sum256([]byte(rule + "\x00" + path + "\x00" + summary))
+	return hex.EncodeToString(sum[:16])
+}
+
+func issueTitle(kind, resource, path string) string {
+	if resource != "" {
+		return "Acceptance test findings for " + kind + " " + resource
+	}
+	return "Acceptance test findings in " + path
+}
+
+// mergeLabels adds the labels a finding suggests. LabelWarning is added for every warning
+// and removed once the issue is known to hold an error.
+func mergeLabels(labels []string, f Finding) []string {
+	add := []string{LabelTool}
+	switch rules.Group(f.Group) {
+	case rules.GroupCoverage:
+		add = append(add, LabelCoverage)
+	case rules.GroupQuality:
+		add = append(add, LabelQuality)
+	}
+	if f.Level == string(enforcement.LevelWarning) {
+		add = append(add, LabelWarning)
+	}
+	for _, label := range add {
+		if !containsLabel(labels, label) {
+			labels = append(labels, label)
+		}
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+func allWarnings(entries []Entry) bool {
+	for _, e := range entries {
+		if e.Level != string(enforcement.LevelWarning) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+func removeLabel(labels []string, label string) []string {
+	kept := labels[:0]
+	for _, l := range labels {
+		if l != label {
+			kept = append(kept, l)
+		}
+	}
+	return kept
+}
+
+// ownersOf returns the owners of path, never nil, so the JSON lists unowned paths as [].
+func ownersOf(c *Codeowners, path string) []string {
+	if owners := c.Owners(path); owners != nil {
+		return owners
+	}
+	return []string{}
+}
+
+// RelativePath returns path relative to root using forward slashes, or path unchanged when
+// it is not inside root.
+func RelativePath(root, path string) string {
+	if root == "" {
+		return filepath.ToSlash(path)
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if abs, err := filepath.Abs(root); err == nil {
+		root = abs
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,375 @@
+// Package rules defines the catalogue of tfprovidertest analyzer rules.
+//
+// Rules are organised into two groups with distinct name prefixes:
+//   - coverage (tfprovider-coverage-*): a resource is missing a kind of test entirely
+//   - quality (tfprovider-quality-*): tests exist but do not follow best practices
+//
+// Rules were previously named tfprovider-resource-* and tfprovider-test-*. Those legacy
+// names remain recognized anywhere a rule name is accepted (e.g., suppression comments).
+//
+// Every rule also has a short, stable code such as TFPT003 for compact output. Codes are
+// never reused or renumbered: new rules take the next free code wherever they appear in the
+// catalogue.
+package rules
+
+// Group classifies a rule as a coverage or quality rule.
+type Group string
+
+const (
+	// GroupCoverage contains rules that report missing test coverage.
+	GroupCoverage Group = "coverage"
+	// GroupQuality contains rules that report test quality problems.
+	GroupQuality Group = "quality"
+)
+
+// Rule names.
+const (
+	BasicTest         = "tfprovider-coverage-basic-test"
+	UpdateTest        = "tfprovider-coverage-update-test"
+	ImportTest        = "tfprovider-coverage-import-test"
+	ErrorTest         = "tfprovider-coverage-error-test"
+	ProviderConfig    = "tfprovider-coverage-provider-config"
+	Requirements      = "tfprovider-coverage-requirements"
+	DeferredActions   = "tfprovider-coverage-deferred-actions"
+	ProviderAliases   = "tfprovider-coverage-provider-aliases"
+	UpgradeTest       = "tfprovider-coverage-upgrade-test"
+	PairedDefinitions = "tfprovider-coverage-paired-definitions"
+	CheckFunctions    = "tfprovider-quality-check-functions"
+	ImportStateIdFunc = "tfprovider-quality-import-state-id-func"
+	ImportStateVerify = "tfprovider-quality-import-state-verify"
+	ParallelFixtures  = "tfprovider-quality-parallel-fixtures"
+	ParallelTests     = "tfprovider-quality-parallel-tests"
+	TestPlacement     = "tfprovider-quality-test-placement"
+	TestLayout        = "tfprovider-quality-test-layout"
+	OrphanTests       = "tfprovider-quality-orphan-tests"
+	DefaultValues     = "tfprovider-quality-default-values"
+	Placeholders      = "tfprovider-quality-placeholder-values"
+	ProviderHygiene   = "tfprovider-quality-provider-hygiene"
+	UnknownTypes      = "tfprovider-quality-unknown-types"
+	TestHelpers       = "tfprovider-quality-test-helpers"
+	DataSourceConfig  = "tfprovider-quality-data-source-config"
+	DataSourceAsserts = "tfprovider-quality-data-source-asserts"
+	PluralDataSources = "tfprovider-quality-plural-data-sources"
+	LegacyChecks      = "tfprovider-quality-legacy-checks"
+	ProviderFactories = "tfprovider-quality-provider-factories"
+	ErrorCheck        = "tfprovider-quality-error-check"
+	ExpectError       = "tfprovider-quality-expect-error-pattern"
+	DeadTests         = "tfprovider-quality-dead-tests"
+	DriftCheck        = "tfprovider-quality-drift-check"
+	Sweepers          = "tfprovider-quality-sweepers"
+	CheckAddresses    = "tfprovider-quality-check-addresses"
+	RefreshDrift      = "tfprovider-quality-refresh-drift"
+	Idempotency       = "tfprovider-quality-idempotency"
+	EnvPreCheck       = "tfprovider-quality-env-precheck"
+	DeprecatedAttrs   = "tfprovider-quality-deprecated-attributes"
+)
+
+// Rule describes a single analyzer rule.
+type Rule struct {
+	Name       string
+	LegacyName string
+	Code       string
+	Group      Group
+	Doc        string
+	// Settings lists the keys of the settings that tune the rule, besides the one that
+	// enables it.
+	Settings []string
+}
+
+// catalogue lists every rule in the order analyzers are built.
+var catalogue = []Rule{
+	{
+		Name:       BasicTest,
+		LegacyName: "tfprovider-resource-basic-test",
+		Code:       "TFPT001",
+		Group:      GroupCoverage,
+		Doc:        "Checks that every resource and data source has at least one acceptance test.",
+		Settings:   []string{"min-tests-per-resource", "min-tests-per-kind"},
+	},
+	{
+		Name:       UpdateTest,
+		LegacyName: "tfprovider-resource-update-test",
+		Code:       "TFPT002",
+		Group:      GroupCoverage,
+		Doc:        "Checks that resources with updatable attributes have multi-step update tests.",
+		Settings:   []string{"maturity-exemptions"},
+	},
+	{
+		Name:       ImportTest,
+		LegacyName: "tfprovider-resource-import-test",
+		Code:       "TFPT003",
+		Group:      GroupCoverage,
+		Doc:        "Checks that resources implementing ImportState have import tests.",
+		Settings:   []string{"maturity-exemptions"},
+	},
+	{
+		Name:       ErrorTest,
+		LegacyName: "tfprovider-test-error-cases",
+		Code:       "TFPT004",
+		Group:      GroupCoverage,
+		Doc:        "Checks that resources with validation rules have error case tests.",
+	},
+	{
+		Name:     ProviderConfig,
+		Code:     "TFPT005",
+		Group:    GroupCoverage,
+		Doc:      "Checks that acceptance tests exercise provider-level configuration attributes.",
+		Settings: []string{"required-provider-attributes"},
+	},
+	{
+		Name:     Requirements,
+		Code:     "TFPT006",
+		Group:    GroupCoverage,
+		Doc:      "Checks coverage declared in the requirements manifest that no other rule enforces, such as disappears tests.",
+		Settings: []string{"requirements-manifest"},
+	},
+	{
+		Name:  DeferredActions,
+		Code:  "TFPT007",
+		Group: GroupCoverage,
+		Doc:   "Checks that providers which defer changes (resp.Deferred) have an acceptance test that allows deferral.",
+	},
+	{
+		Name:     ProviderAliases,
+		Code:     "TFPT008",
+		Group:    GroupCoverage,
+		Doc:      "Checks that resources spanning provider instances, such as peering resources, are tested with an aliased provider.",
+		Settings: []string{"provider-alias-resources"},
+	},
+	{
+		Name:     UpgradeTest,
+		Code:     "TFPT029",
+		Group:    GroupCoverage,
+		Doc:      "Checks that resources whose schema version changed since a git ref have an upgrade test starting from a released provider.",
+		Settings: []string{"since"},
+	},
+	{
+		Name:  PairedDefinitions,
+		Code:  "TFPT034",
+		Group: GroupCoverage,
+		Doc:   "Checks that a resource and a data source sharing a name are either both tested or both untested.",
+	},
+	{
+		Name:       CheckFunctions,
+		LegacyName: "tfprovider-test-check-functions",
+		Code:       "TFPT009",
+		Group:      GroupQuality,
+		Doc:        "Checks that test steps include state validation check functions.",
+		Settings:   []string{"fail-on-weak-tests"},
+	},
+	{
+		Name:  ImportStateIdFunc,
+		Code:  "TFPT010",
+		Group: GroupQuality,
+		Doc:   "Checks that ImportStateIdFunc functions only read state attributes defined in the resource schema.",
+	},
+	{
+		Name:  ImportStateVerify,
+		Code:  "TFPT011",
+		Group: GroupQuality,
+		Doc:   "Checks that import test steps set ImportStateVerify so the imported state is compared with the created resource.",
+	},
+	{
+		Name:     ParallelFixtures,
+		Code:     "TFPT012",
+		Group:    GroupQuality,
+		Doc:      "Checks that parallel acceptance tests do not hard-code the same resource names or global values.",
+		Settings: []string{"parallel-fixture-attributes"},
+	},
+	{
+		Name:  ParallelTests,
+		Code:  "TFPT013",
+		Group: GroupQuality,
+		Doc:   "Checks that acceptance tests use resource.ParallelTest unless a comment documents why they run serially.",
+	},
+	{
+		Name:  TestPlacement,
+		Code:  "TFPT014",
+		Group: GroupQuality,
+		Doc:   "Checks that tests live in the test file of the resource they test rather than one named for another resource.",
+	},
+	{
+		Name:     TestLayout,
+		Code:     "TFPT031",
+		Group:    GroupQuality,
+		Doc:      "Checks that tests live in the directory of the definition they test, unless the provider keeps its acceptance tests in a centralized package.",
+		Settings: []string{"test-layout"},
+	},
+	{
+		Name:  OrphanTests,
+		Code:  "TFPT015",
+		Group: GroupQuality,
+		Doc:   "Reports acceptance tests linked to no resource, data source or action, with the closest definition by name.",
+	},
+	{
+		Name:  DefaultValues,
+		Code:  "TFPT016",
+		Group: GroupQuality,
+		Doc:   "Reports resource attributes with a default value that every test config sets, so the default is never exercised.",
+	},
+	{
+		Name:  Placeholders,
+		Code:  "TFPT033",
+		Group: GroupQuality,
+		Doc:   "Reports test configs setting required attributes validated as ARNs, CIDR blocks, URLs or similar to placeholders such as \"test\" or \"changeme\".",
+	},
+	{
+		Name:  ProviderHygiene,
+		Code:  "TFPT017",
+		Group: GroupQuality,
+		Doc:   "Checks that test packages have a TestMain that runs their acceptance tests and dispatches to resource.TestMain when sweepers are registered.",
+	},
+	{
+		Name:       UnknownTypes,
+		LegacyName: "tfprovider-test-unknown-type",
+		Code:       "TFPT018",
+		Group:      GroupQuality,
+		Doc:        "Reports test configs declaring a type with the provider's prefix that no discovered resource, data source or action defines.",
+	},
+	{
+		Name:  TestHelpers,
+		Code:  "TFPT019",
+		Group: GroupQuality,
+		Doc:   "Reports local test helpers that call t.Fatal from a goroutine or discard the error of a setup call.",
+	},
+	{
+		Name:  DataSourceConfig,
+		Code:  "TFPT020",
+		Group: GroupQuality,
+		Doc:   "Checks that data source tests create the managed resource the data source reads rather than relying on existing infrastructure.",
+	},
+	{
+		Name:     DataSourceAsserts,
+		Code:     "TFPT030",
+		Group:    GroupQuality,
+		Doc:      "Checks that the tests of a data source assert computed attributes beyond id, so they show the lookup returns useful fields.",
+		Settings: []string{"data-source-min-computed-asserts"},
+	},
+	{
+		Name:  PluralDataSources,
+		Code:  "TFPT035",
+		Group: GroupQuality,
+		Doc:   "Checks that the tests of a plural data source, such as widgets next to widget, assert how many items it returns.",
+	},
+	{
+		Name:     LegacyChecks,
+		Code:     "TFPT036",
+		Group:    GroupQuality,
+		Doc:      "Checks that test steps added since a git ref assert state with ConfigStateChecks rather than the legacy Check field.",
+		Settings: []string{"since"},
+	},
+	{
+		Name:  ProviderFactories,
+		Code:  "TFPT037",
+		Group: GroupQuality,
+		Doc:   "Checks that the tests of a package serve the provider with the same factory field, and that every TestCase gives its steps a provider.",
+	},
+	{
+		Name:  ErrorCheck,
+		Code:  "TFPT038",
+		Group: GroupQuality,
+		Doc:   "Checks that TestCases set ErrorCheck in packages where most TestCases do, so expected regional errors skip rather than fail tests.",
+	},
+	{
+		Name:       ExpectError,
+		LegacyName: "tfprovider-test-expecterror-quality",
+		Code:       "TFPT021",
+		Group:      GroupQuality,
+		Doc:        "Checks that ExpectError regular expressions compile and are specific enough to identify the expected error.",
+	},
+	{
+		Name:       DeadTests,
+		LegacyName: "tfprovider-test-dead-code",
+		Code:       "TFPT022",
+		Group:      GroupQuality,
+		Doc:        "Checks test files for commented-out acceptance tests, which silently drop coverage.",
+		Settings:   []string{"dead-test-min-lines"},
+	},
+	{
+		Name:       DriftCheck,
+		LegacyName: "tfprovider-test-drift-check",
+		Code:       "TFPT023",
+		Group:      GroupQuality,
+		Doc:        "Checks that acceptance tests include CheckDestroy for drift detection.",
+		Settings:   []string{"check-destroy-requires-delete"},
+	},
+	{
+		Name:       Sweepers,
+		LegacyName: "tfprovider-test-sweepers",
+		Code:       "TFPT024",
+		Group:      GroupQuality,
+		Doc:        "Checks that packages have test sweeper registrations for cleanup.",
+	},
+	{
+		Name:  CheckAddresses,
+		Code:  "TFPT025",
+		Group: GroupQuality,
+		Doc:   "Checks that the resource addresses passed to TestCheckResourceAttr-style checks are declared by the step's config.",
+	},
+	{
+		Name:  RefreshDrift,
+		Code:  "TFPT026",
+		Group: GroupQuality,
+		Doc:   "Checks that tested resources have a RefreshState step exercising drift detection.",
+	},
+	{
+		Name:  Idempotency,
+		Code:  "TFPT032",
+		Group: GroupQuality,
+		Doc:   "Checks that resources with Optional+Computed attributes have a test that plans its config again and expects an empty plan.",
+	},
+	{
+		Name:  EnvPreCheck,
+		Code:  "TFPT027",
+		Group: GroupQuality,
+		Doc:   "Checks that environment variables interpolated into test configs are checked in PreCheck.",
+	},
+	{
+		Name:  DeprecatedAttrs,
+		Code:  "TFPT028",
+		Group: GroupQuality,
+		Doc:   "Checks that tests setting a deprecated attribute are not the only coverage of the attribute replacing it.",
+	},
+}
+
+// All returns a copy of every rule in the catalogue.
+func All() []Rule {
+	result := make([]Rule, len(catalogue))
+	copy(result, catalogue)
+	return result
+}
+
+// Lookup finds a rule by its current or legacy name.
+func Lookup(name string) (Rule, bool) {
+	for _, rule := range catalogue {
+		if rule.Name == name || (rule.LegacyName != "" && rule.LegacyName == name) {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// Canonical returns the current name for a rule, translating legacy names.
+// Unknown names are returned unchanged.
+func Canonical(name string) string {
+	if rule, ok := Lookup(name); ok {
+		return rule.Name
+	}
+	return name
+}
+
+// Code returns the short code of the named rule, or "" if the rule is unknown.
+func Code(name string) string {
+	if rule, ok := Lookup(name); ok {
+		return rule.Code
+	}
+	return ""
+}
+
+// GroupOf returns the group of the named rule, or "" if the rule is unknown.
+func GroupOf(name string) Group {
+	if rule, ok := Lookup(name); ok {
+		return rule.Group
+	}
+	return ""
+}
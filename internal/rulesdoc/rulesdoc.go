@@ -0,0 +1,200 @@
+// Package rulesdoc renders the rules reference from the rule catalogue (see internal/rules)
+// and the default settings, so the documentation cannot drift from the code. cmd/rulesdoc
+// writes it as Markdown pages; validate -list-rules prints it as a table.
+package rulesdoc
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/example/tfprovidertest/internal/rules"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// DefaultSeverity is the level of every finding, unless enforce-paths, warn-only-paths or a
+// directory config lowers it to a warning.
+const DefaultSeverity = "error"
+
+// IndexFile is the name of the page listing every rule.
+const IndexFile = "README.md"
+
+// Setting is a setting that tunes a rule, with its default value.
+type Setting struct {
+	Key     string
+	Default string
+}
+
+// Example is a finding a rule reports on the fixtures.
+type Example struct {
+	File    string // Path of the fixture file
+	Line    int
+	Source  string // The fixture line the finding is reported at
+	Message string
+}
+
+// Page documents a single rule.
+type Page struct {
+	Rule           rules.Rule
+	Toggle         string // Setting that enables the rule; empty for drift-check and sweepers
+	DefaultEnabled bool
+	Severity       string
+	Settings       []Setting // Toggle first, then the settings tuning the rule
+	Example        *Example  // nil when the rule reports nothing on the fixtures
+}
+
+// FileName returns the name of the page's Markdown file.
+func (p Page) FileName() string {
+	return p.Rule.Name + ".md"
+}
+
+// Pages returns a page for every rule, in catalogue order, with the example for each rule
+// found in examples, keyed by rule name.
+func Pages(examples map[string]Example) []Page {
+	defaults := config.SettingDefaults()
+	settings := config.DefaultSettings()
+	var pages []Page
+	for _, rule := range rules.All() {
+		page := Page{Rule: rule, Severity: DefaultSeverity}
+		if key, enabled, ok := config.RuleToggle(rule.Name); ok {
+			page.Toggle = key
+			page.DefaultEnabled = enabled
+			page.Settings = append(page.Settings, Setting{Key: key, Default: FormatValue(enabled)})
+		} else {
+			page.DefaultEnabled = settings.DriftCheckEnabled()
+		}
+		for _, key := range rule.Settings {
+			page.Settings = append(page.Settings, Setting{Key: key, Default: FormatValue(defaults[key])})
+		}
+		if example, ok := examples[rule.Name]; ok {
+			page.Example = &example
+		}
+		pages = append(pages, page)
+	}
+	return pages
+}
+
+// FormatValue formats a default setting value as configuration files spell it: strings bare
+// (an empty one as ""), lists as [a, b], maps as {key: value} and unset pointers as unset.
+func FormatValue(value any) string {
+	return formatValue(reflect.ValueOf(value))
+}
+
+func formatValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return "unset"
+	case reflect.Pointer:
+		if v.IsNil() {
+			return "unset"
+		}
+		return formatValue(v.Elem())
+	case reflect.String:
+		if v.String() == "" {
+			return `""`
+		}
+		return v.String()
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	case reflect.Slice:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = formatValue(v.Index(i))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case reflect.Map:
+		items := make([]string, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			items = append(items, formatValue(key)+": "+formatValue(v.MapIndex(key)))
+		}
+		sort.Strings(items)
+		return "{" + strings.Join(items, ", ") + "}"
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// enabledText describes whether a rule runs with the default settings.
+func (p Page) enabledText() string {
+	switch {
+	case p.Toggle == "":
+		return "yes, whenever another rule runs"
+	case p.DefaultEnabled:
+		return "yes"
+	default:
+		return "no (opt-in via `" + p.Toggle + "`)"
+	}
+}
+
+// Markdown renders the page.
+func (p Page) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", p.Rule.Name)
+	fmt.Fprintf(&b, "%s\n\n", p.Rule.Doc)
+	b.WriteString("| | |\n|---|---|\n")
+	fmt.Fprintf(&b, "| Code | `%s` |\n", p.Rule.Code)
+	fmt.Fprintf(&b, "| Group | %s |\n", p.Rule.Group)
+	if p.Rule.LegacyName != "" {
+		fmt.Fprintf(&b, "| Legacy name | `%s` |\n", p.Rule.LegacyName)
+	}
+	fmt.Fprintf(&b, "| Enabled by default | %s |\n", p.enabledText())
+	fmt.Fprintf(&b, "| Default severity | %s |\n", p.Severity)
+
+	b.WriteString("\n## Settings\n\n")
+	if len(p.Settings) == 0 {
+		b.WriteString("The rule has no settings of its own; `enable-quality-rules: false` turns it off.\n")
+	} else {
+		b.WriteString("| Setting | Default |\n|---|---|\n")
+		for _, s := range p.Settings {
+			fmt.Fprintf(&b, "| `%s` | `%s` |\n", s.Key, s.Default)
+		}
+	}
+
+	b.WriteString("\n## Example\n\n")
+	if p.Example == nil {
+		b.WriteString("The fixtures have no finding for this rule.\n")
+	} else {
+		fmt.Fprintf(&b, "Reported at `%s:%d` of the fixtures:\n\n", p.Example.File, p.Example.Line)
+		fmt.Fprintf(&b, "```go\n%s\n```\n\n", p.Example.Source)
+		fmt.Fprintf(&b, "```\n%s\n```\n", p.Example.Message)
+	}
+	return b.String()
+}
+
+// Index renders the page listing every rule, linking to their pages.
+func Index(pages []Page) string {
+	var b strings.Builder
+	b.WriteString("# Rules\n\n")
+	b.WriteString("Generated by `go run ./cmd/rulesdoc` from the rule catalogue; do not edit.\n\n")
+	b.WriteString("| Code | Rule | Group | Enabled by default | Description |\n|---|---|---|---|---|\n")
+	for _, p := range pages {
+		enabled := "no"
+		if p.DefaultEnabled {
+			enabled = "yes"
+		}
+		fmt.Fprintf(&b, "| `%s` | [%s](%s) | %s | %s | %s |\n", p.Rule.Code, p.Rule.Name, p.FileName(), p.Rule.Group, enabled, p.Rule.Doc)
+	}
+	return b.String()
+}
+
+// List prints the rules as a table, for validate -list-rules.
+func List(w io.Writer, pages []Page) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CODE\tRULE\tGROUP\tDEFAULT\tSETTING\tDESCRIPTION")
+	for _, p := range pages {
+		enabled := "off"
+		if p.DefaultEnabled {
+			enabled = "on"
+		}
+		toggle := p.Toggle
+		if toggle == "" {
+			toggle = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", p.Rule.Code, p.Rule.Name, p.Rule.Group, enabled, toggle, p.Rule.Doc)
+	}
+	return tw.Flush()
+}
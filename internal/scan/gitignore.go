@@ -0,0 +1,196 @@
+package scan
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// gitignore holds the rules from a single .gitignore file.
+type gitignore struct {
+	dir   string // Absolute directory containing the .gitignore; patterns are relative to it
+	path  string
+	rules []gitignoreRule
+}
+
+// gitignoreRule is a single parsed .gitignore pattern.
+type gitignoreRule struct {
+	raw      string
+	negate   bool
+	dirOnly  bool
+	anchored bool // Pattern contains a slash, so it matches the path relative to dir
+	re       *regexp.Regexp
+}
+
+// loadGitignore parses dir/.gitignore, returning nil if it does not exist or has no rules.
+func loadGitignore(dir string) *gitignore {
+	path := filepath.Join(dir, ".gitignore")
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil
+	}
+	gi := &gitignore{dir: abs, path: path}
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if rule, ok := parseGitignoreLine(sc.Text()); ok {
+			gi.rules = append(gi.rules, rule)
+		}
+	}
+	if len(gi.rules) == 0 {
+		return nil
+	}
+	return gi
+}
+
+// loadParentGitignores loads .gitignore files from the ancestors of root, stopping at the
+// repository root (the first ancestor containing .git). Outermost files come first.
+// Nothing is loaded when root is itself a repository root or is not inside a repository.
+func loadParentGitignores(root string) []*gitignore {
+	abs, err := filepath.Abs(root)
+	if err != nil || exists(filepath.Join(abs, ".git")) {
+		return nil
+	}
+
+	var chain []*gitignore
+	for dir := filepath.Dir(abs); ; {
+		if gi := loadGitignore(dir); gi != nil {
+			chain = append([]*gitignore{gi}, chain...)
+		}
+		if exists(filepath.Join(dir, ".git")) {
+			return chain
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil
+		}
+		dir = parent
+	}
+}
+
+// exists reports whether a file or directory exists at path.
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// parseGitignoreLine parses one line of a .gitignore file.
+func parseGitignoreLine(line string) (gitignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return gitignoreRule{}, false
+	}
+
+	rule := gitignoreRule{raw: line}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	}
+	line = strings.TrimPrefix(line, `\`)
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimSuffix(line, "/")
+	}
+	if strings.Contains(line, "/") {
+		rule.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	if line == "" {
+		return gitignoreRule{}, false
+	}
+
+	re, err := regexp.Compile("^" + GlobToRegexp(line) + "$")
+	if err != nil {
+		return gitignoreRule{}, false
+	}
+	rule.re = re
+	return rule, true
+}
+
+// GlobToRegexp converts a gitignore glob (supporting *, ?, [...], and **) to a regular expression.
+func GlobToRegexp(glob string) string {
+	var sb strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "/**") && i+3 == len(glob):
+			sb.WriteString("(?:/.*)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i:], ']')
+			if end <= 1 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + class + "]")
+			i += end
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return sb.String()
+}
+
+// match reports whether the file's rules decide path, and if so whether it is ignored.
+// The last matching rule wins, as in git.
+func (gi *gitignore) match(path string, isDir bool) (ignored, decided bool, rule string) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false, false, ""
+	}
+	rel, err := filepath.Rel(gi.dir, abs)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return false, false, ""
+	}
+	rel = filepath.ToSlash(rel)
+	base := filepath.Base(path)
+
+	for _, r := range gi.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		target := base
+		if r.anchored {
+			target = rel
+		}
+		if r.re.MatchString(target) {
+			ignored, decided, rule = !r.negate, true, r.raw
+		}
+	}
+	return ignored, decided, rule
+}
+
+// matchGitignores applies a chain of .gitignore files, outermost first, so that rules in
+// deeper files override those in their ancestors. It returns the deciding file and pattern.
+func matchGitignores(chain []*gitignore, path string, isDir bool) (bool, string) {
+	ignored := false
+	source := ""
+	for _, gi := range chain {
+		if ig, decided, rule := gi.match(path, isDir); decided {
+			ignored = ig
+			source = gi.path + ": " + rule
+		}
+	}
+	return ignored, source
+}
@@ -0,0 +1,149 @@
+package scan
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Layout describes where a family of providers keeps its resource code, for providers whose
+// code generators place it outside the internal/provider directory auto-detection expects.
+type Layout struct {
+	Name        string
+	Description string
+	// Markers are globs, relative to the provider root, of paths whose presence identifies
+	// the layout; the first layout with a matching marker is detected.
+	Markers []string
+	// ResourceDirGlobs are globs, relative to the provider root, of the directories that hold
+	// resource, data source and test code.
+	ResourceDirGlobs []string
+}
+
+// layouts lists the known generated layouts in detection order.
+var layouts = []Layout{
+	{
+		Name:             "awscc",
+		Description:      "Cloud Control generated providers: internal/aws/<service>/*_gen.go",
+		Markers:          []string{"internal/aws/*/*_gen.go"},
+		ResourceDirGlobs: []string{"internal/aws/**", "internal/provider"},
+	},
+	{
+		Name:             "oci",
+		Description:      "Service-per-directory providers such as OCI: internal/service/<service>/",
+		Markers:          []string{"internal/service/*/*_resource.go", "internal/service/*/*_data_source.go"},
+		ResourceDirGlobs: []string{"internal/service/**", "internal/provider"},
+	},
+	{
+		Name:             "plugingen",
+		Description:      "tfplugingen-framework output: internal/resource_<name>/ and internal/datasource_<name>/",
+		Markers:          []string{"internal/resource_*/*_gen.go", "internal/datasource_*/*_gen.go"},
+		ResourceDirGlobs: []string{"internal/resource_*", "internal/datasource_*", "internal/provider"},
+	},
+	{
+		Name:             "pulumi-bridged",
+		Description:      "Pulumi bridged providers: the Terraform provider in the upstream/ submodule",
+		Markers:          []string{"upstream/go.mod"},
+		ResourceDirGlobs: []string{"upstream/internal/**"},
+	},
+}
+
+// Layouts returns the known generated layouts in detection order.
+func Layouts() []Layout {
+	result := make([]Layout, len(layouts))
+	copy(result, layouts)
+	return result
+}
+
+// LookupLayout returns the known layout with the given name.
+func LookupLayout(name string) (Layout, bool) {
+	for _, layout := range layouts {
+		if layout.Name == name {
+			return layout, true
+		}
+	}
+	return Layout{}, false
+}
+
+// LayoutNames returns the names of the known layouts in detection order.
+func LayoutNames() []string {
+	names := make([]string, 0, len(layouts))
+	for _, layout := range layouts {
+		names = append(names, layout.Name)
+	}
+	return names
+}
+
+// DetectLayout returns the first known layout with a marker under root.
+func DetectLayout(root string) (Layout, bool) {
+	for _, layout := range layouts {
+		for _, marker := range layout.Markers {
+			if matches, _ := filepath.Glob(filepath.Join(root, filepath.FromSlash(marker))); len(matches) > 0 {
+				return layout, true
+			}
+		}
+	}
+	return Layout{}, false
+}
+
+// CompileDirGlobs checks resource directory globs, returning an error naming the first
+// invalid one.
+func CompileDirGlobs(globs []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(globs))
+	for _, glob := range globs {
+		glob = strings.Trim(strings.TrimPrefix(filepath.ToSlash(glob), "./"), "/")
+		if glob == "" {
+			return nil, fmt.Errorf("resource-dir-globs: empty pattern")
+		}
+		re, err := regexp.Compile("^" + GlobToRegexp(glob) + "$")
+		if err != nil {
+			return nil, fmt.Errorf("resource-dir-globs: invalid pattern %q: %w", glob, err)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+// ResourceDirs returns the directories with Go files under root whose slash path relative to
+// root matches one of globs, in sorted order. Only the part of the tree below each glob's
+// literal prefix is walked, so a deep generated tree is found without scanning the whole
+// repository.
+func ResourceDirs(root string, globs []string, opts Options) ([]string, error) {
+	res, err := CompileDirGlobs(globs)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var dirs []string
+	for i, glob := range globs {
+		base := filepath.Join(root, filepath.FromSlash(literalPrefix(glob)))
+		if stat, err := os.Stat(base); err != nil || !stat.IsDir() {
+			continue
+		}
+		for _, dir := range GoPackageDirs(base, opts).Dirs() {
+			rel, err := filepath.Rel(root, dir)
+			if err != nil || seen[dir] || !res[i].MatchString(filepath.ToSlash(rel)) {
+				continue
+			}
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// literalPrefix returns the leading path segments of a glob that contain no wildcard.
+func literalPrefix(glob string) string {
+	glob = strings.Trim(strings.TrimPrefix(filepath.ToSlash(glob), "./"), "/")
+	var prefix []string
+	for _, segment := range strings.Split(glob, "/") {
+		if strings.ContainsAny(segment, "*?[") {
+			break
+		}
+		prefix = append(prefix, segment)
+	}
+	return strings.Join(prefix, "/")
+}
@@ -0,0 +1,75 @@
+package scan
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// File limit reasons recorded for skipped files.
+const (
+	ReasonFileTooLarge = "max-file-size-kb"
+	ReasonTooManyFiles = "max-files"
+)
+
+// SkippedFile is a Go file left out of analysis by a FileLimiter.
+type SkippedFile struct {
+	Path   string `json:"path"`
+	SizeKB int    `json:"size_kb"`
+	Reason string `json:"reason"` // ReasonFileTooLarge or ReasonTooManyFiles
+	Limit  int    `json:"limit"`  // The limit that was exceeded
+}
+
+// FileLimiter admits Go files for analysis up to a count and a per-file size, so a
+// directory of huge generated files degrades to a skip report instead of stalling a run.
+// A zero limit disables the check.
+type FileLimiter struct {
+	MaxFiles  int
+	MaxSizeKB int
+
+	admitted int
+	skipped  []SkippedFile
+}
+
+// NewFileLimiter returns a limiter with the given limits.
+func NewFileLimiter(maxFiles, maxSizeKB int) *FileLimiter {
+	return &FileLimiter{MaxFiles: maxFiles, MaxSizeKB: maxSizeKB}
+}
+
+// Allow reports whether the file at path, of size bytes, is analyzed, recording it as
+// skipped otherwise. Files are counted against MaxFiles in the order Allow is called, and
+// files too large to analyze do not count.
+func (l *FileLimiter) Allow(path string, size int64) bool {
+	sizeKB := int((size + 1023) / 1024)
+	if l.MaxSizeKB > 0 && sizeKB > l.MaxSizeKB {
+		l.skipped = append(l.skipped, SkippedFile{Path: path, SizeKB: sizeKB, Reason: ReasonFileTooLarge, Limit: l.MaxSizeKB})
+		return false
+	}
+	if l.MaxFiles > 0 && l.admitted >= l.MaxFiles {
+		l.skipped = append(l.skipped, SkippedFile{Path: path, SizeKB: sizeKB, Reason: ReasonTooManyFiles, Limit: l.MaxFiles})
+		return false
+	}
+	l.admitted++
+	return true
+}
+
+// Skipped returns the files Allow rejected, in the order they were offered.
+func (l *FileLimiter) Skipped() []SkippedFile {
+	return l.skipped
+}
+
+// LimitFiles returns the parsed files a limiter with the given limits admits, in order, and
+// the files it skipped. Sizes are those of the files' content as parsed.
+func LimitFiles(fset *token.FileSet, files []*ast.File, maxFiles, maxSizeKB int) ([]*ast.File, []SkippedFile) {
+	if maxFiles <= 0 && maxSizeKB <= 0 {
+		return files, nil
+	}
+	limiter := NewFileLimiter(maxFiles, maxSizeKB)
+	kept := make([]*ast.File, 0, len(files))
+	for _, file := range files {
+		tf := fset.File(file.Pos())
+		if tf == nil || limiter.Allow(tf.Name(), int64(tf.Size())) {
+			kept = append(kept, file)
+		}
+	}
+	return kept, limiter.Skipped()
+}
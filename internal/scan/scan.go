@@ -0,0 +1,233 @@
+// Package scan locates Go package directories beneath a provider root for recursive analysis.
+//
+// Scanning skips well-known non-source directories (vendor, testdata, .git, ...), can honor
+// .gitignore files and an extra exclude list, and can follow symlinked directories with cycle
+// detection. Every directory visited is recorded in a Manifest so users can see why a
+// directory (and therefore a resource) was or was not analyzed.
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultExcludeDirs lists directory names that are never scanned.
+var DefaultExcludeDirs = []string{"vendor", "testdata", ".git", ".github", "node_modules", ".terraform"}
+
+// Exclusion reasons recorded in the manifest.
+const (
+	ReasonDefaultExclude = "default exclude"
+	ReasonExtraExclude   = "extra exclude"
+	ReasonGitignore      = "gitignore"
+	ReasonSymlink        = "symlink not followed"
+	ReasonSymlinkCycle   = "symlink cycle"
+	ReasonUnreadable     = "unreadable"
+	ReasonNoGoFiles      = "no Go files"
+)
+
+// Options controls how directories are scanned.
+type Options struct {
+	// FollowSymlinks descends into symlinked directories. Each real directory is visited at
+	// most once, so symlink cycles terminate.
+	FollowSymlinks bool
+	// RespectGitignore skips files and directories ignored by .gitignore files found while scanning.
+	RespectGitignore bool
+	// ExtraExcludes lists additional directories to skip, given as a directory name
+	// (e.g., "examples") or a slash-separated path relative to the root (e.g., "tools/gen").
+	// Glob patterns are matched with filepath.Match.
+	ExtraExcludes []string
+}
+
+// DefaultOptions returns the scan options used when none are configured.
+func DefaultOptions() Options {
+	return Options{RespectGitignore: true}
+}
+
+// Entry records a single directory visited during a scan.
+type Entry struct {
+	Path    string `json:"path"`
+	GoFiles int    `json:"go_files,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+	Detail  string `json:"detail,omitempty"`
+	Symlink string `json:"symlink_target,omitempty"`
+}
+
+// Manifest lists the directories a scan included and excluded, with the reason for each exclusion.
+type Manifest struct {
+	Root     string  `json:"root"`
+	Options  Options `json:"options"`
+	Included []Entry `json:"included"`
+	Excluded []Entry `json:"excluded"`
+}
+
+// Dirs returns the included directory paths in sorted order.
+func (m *Manifest) Dirs() []string {
+	dirs := make([]string, 0, len(m.Included))
+	for _, e := range m.Included {
+		dirs = append(dirs, e.Path)
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// scanner holds the state of a single scan.
+type scanner struct {
+	root     string
+	opts     Options
+	defaults map[string]bool
+	visited  map[string]bool // real paths of directories already scanned
+	manifest *Manifest
+}
+
+// GoPackageDirs walks root and returns a manifest of every directory containing Go files.
+func GoPackageDirs(root string, opts Options) *Manifest {
+	s := &scanner{
+		root:     root,
+		opts:     opts,
+		defaults: make(map[string]bool, len(DefaultExcludeDirs)),
+		visited:  make(map[string]bool),
+		manifest: &Manifest{Root: root, Options: opts},
+	}
+	for _, name := range DefaultExcludeDirs {
+		s.defaults[name] = true
+	}
+
+	var ignores []*gitignore
+	if opts.RespectGitignore {
+		ignores = loadParentGitignores(root)
+	}
+	if real, err := filepath.EvalSymlinks(root); err == nil {
+		s.visited[real] = true
+	}
+	s.walk(root, ignores)
+
+	sort.Slice(s.manifest.Included, func(i, j int) bool {
+		return s.manifest.Included[i].Path < s.manifest.Included[j].Path
+	})
+	sort.Slice(s.manifest.Excluded, func(i, j int) bool {
+		return s.manifest.Excluded[i].Path < s.manifest.Excluded[j].Path
+	})
+	return s.manifest
+}
+
+// walk scans a single directory and recurses into its subdirectories.
+func (s *scanner) walk(dir string, ignores []*gitignore) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		s.exclude(dir, ReasonUnreadable, err.Error(), "")
+		return
+	}
+
+	if s.opts.RespectGitignore {
+		if gi := loadGitignore(dir); gi != nil {
+			ignores = append(ignores[:len(ignores):len(ignores)], gi)
+		}
+	}
+
+	goFiles := 0
+	var subdirs []string
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		isDir := entry.IsDir()
+		symlinkTarget := ""
+
+		if entry.Type()&os.ModeSymlink != 0 {
+			info, err := os.Stat(path)
+			if err != nil {
+				continue // Dangling symlink
+			}
+			isDir = info.IsDir()
+			if isDir {
+				symlinkTarget, _ = filepath.EvalSymlinks(path)
+			}
+		}
+
+		if ignored, source := matchGitignores(ignores, path, isDir); ignored {
+			if isDir {
+				s.exclude(path, ReasonGitignore, source, symlinkTarget)
+			}
+			continue
+		}
+
+		if !isDir {
+			if strings.HasSuffix(entry.Name(), ".go") {
+				goFiles++
+			}
+			continue
+		}
+
+		if s.defaults[entry.Name()] {
+			s.exclude(path, ReasonDefaultExclude, entry.Name(), symlinkTarget)
+			continue
+		}
+		if pattern, ok := s.extraExclude(path); ok {
+			s.exclude(path, ReasonExtraExclude, pattern, symlinkTarget)
+			continue
+		}
+		if symlinkTarget != "" && !s.opts.FollowSymlinks {
+			s.exclude(path, ReasonSymlink, "", symlinkTarget)
+			continue
+		}
+		subdirs = append(subdirs, path)
+	}
+
+	if goFiles > 0 {
+		s.manifest.Included = append(s.manifest.Included, Entry{Path: dir, GoFiles: goFiles})
+	} else {
+		s.exclude(dir, ReasonNoGoFiles, "", "")
+	}
+
+	for _, sub := range subdirs {
+		real, err := filepath.EvalSymlinks(sub)
+		if err != nil {
+			s.exclude(sub, ReasonUnreadable, err.Error(), "")
+			continue
+		}
+		if s.visited[real] {
+			s.exclude(sub, ReasonSymlinkCycle, "already scanned", real)
+			continue
+		}
+		s.visited[real] = true
+		s.walk(sub, ignores)
+	}
+}
+
+// extraExclude reports which configured extra-exclude pattern matches a directory, if any.
+func (s *scanner) extraExclude(path string) (string, bool) {
+	rel, err := filepath.Rel(s.root, path)
+	if err != nil {
+		return "", false
+	}
+	rel = filepath.ToSlash(rel)
+	name := filepath.Base(path)
+
+	for _, raw := range s.opts.ExtraExcludes {
+		pattern := strings.Trim(filepath.ToSlash(strings.TrimSpace(raw)), "/")
+		if pattern == "" {
+			continue
+		}
+		target := name
+		if strings.Contains(pattern, "/") {
+			target = rel
+		}
+		if target == pattern {
+			return raw, true
+		}
+		if matched, _ := filepath.Match(pattern, target); matched {
+			return raw, true
+		}
+	}
+	return "", false
+}
+
+// exclude records a skipped directory in the manifest.
+func (s *scanner) exclude(path, reason, detail, symlink string) {
+	s.manifest.Excluded = append(s.manifest.Excluded, Entry{
+		Path:    path,
+		Reason:  reason,
+		Detail:  detail,
+		Symlink: symlink,
+	})
+}
@@ -0,0 +1,260 @@
+// Package sinks delivers the output of a run to one or more destinations at once: stdout, a
+// file, or an HTTP endpoint such as a CI ingestion service. A destination is given as a spec
+// (see Parse): "stdout", "file=<path>" or "http=<url>".
+//
+// The stdout and file sinks write through as output is produced. The HTTP sink buffers the
+// output and POSTs it once, on Close, so the endpoint receives a complete document.
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Sink is a destination of the output of a run. Close delivers anything buffered and
+// reports whether the output reached the destination.
+type Sink interface {
+	io.Writer
+	Close() error
+	// String names the destination in error messages, e.g. "file=report.json"
+	String() string
+}
+
+// HTTPOptions configures the HTTP sinks created by Parse.
+type HTTPOptions struct {
+	// Headers are added to each request, e.g. Authorization for a bearer token
+	Headers http.Header
+	// ContentType is the Content-Type of the request body (default: text/plain)
+	ContentType string
+	// Retries is the number of times a failed request is retried
+	Retries int
+	// Backoff is the wait before the first retry, doubled for each following one
+	// (default: DefaultBackoff)
+	Backoff time.Duration
+	// Timeout limits each request (default: DefaultTimeout)
+	Timeout time.Duration
+	Client  *http.Client
+}
+
+// DefaultRetries is the number of retries of the HTTP sink when none is configured.
+const DefaultRetries = 3
+
+// DefaultBackoff is the wait before the first retry of a failed HTTP request.
+const DefaultBackoff = 500 * time.Millisecond
+
+// DefaultTimeout limits each HTTP request.
+const DefaultTimeout = 30 * time.Second
+
+// ContentType returns the Content-Type of output in format, one of the -format values.
+func ContentType(format string) string {
+	switch format {
+	case "json", "codeclimate":
+		return "application/json"
+	case "ndjson":
+		return "application/x-ndjson"
+	case "csv":
+		return "text/csv"
+	}
+	return "text/plain; charset=utf-8"
+}
+
+// Parse returns the sink of spec: "stdout", "file=<path>" or "http=<url>" (https URLs too).
+// stdout is the process's standard output at the time of the call. A file sink creates or
+// truncates its file right away, so an unwritable path is reported before the run.
+func Parse(spec string, stdout io.Writer, opts HTTPOptions) (Sink, error) {
+	kind, target, hasTarget := strings.Cut(spec, "=")
+	switch kind {
+	case "stdout":
+		if hasTarget {
+			return nil, fmt.Errorf("invalid output %q: stdout takes no value", spec)
+		}
+		return &writerSink{name: "stdout", w: stdout}, nil
+	case "file":
+		if target == "" {
+			return nil, fmt.Errorf("invalid output %q (expected file=<path>)", spec)
+		}
+		f, err := os.Create(target)
+		if err != nil {
+			return nil, fmt.Errorf("output %s: %w", spec, err)
+		}
+		return &writerSink{name: spec, w: f, closer: f}, nil
+	case "http":
+		u, err := url.Parse(target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid output %q (expected http=<http or https URL>)", spec)
+		}
+		return NewHTTP(target, opts), nil
+	}
+	return nil, fmt.Errorf("invalid output %q (expected stdout, file=<path> or http=<url>)", spec)
+}
+
+// writerSink writes through to w, closing closer, if any, on Close.
+type writerSink struct {
+	name   string
+	w      io.Writer
+	closer io.Closer
+}
+
+func (s *writerSink) Write(p []byte) (int, error) { return s.w.Write(p) }
+
+func (s *writerSink) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	if err := s.closer.Close(); err != nil {
+		return fmt.Errorf("output %s: %w", s.name, err)
+	}
+	return nil
+}
+
+func (s *writerSink) String() string { return s.name }
+
+// HTTP is a sink POSTing the output to an endpoint on Close.
+type HTTP struct {
+	URL  string
+	opts HTTPOptions
+	buf  bytes.Buffer
+}
+
+// NewHTTP returns a sink POSTing the output to endpoint.
+func NewHTTP(endpoint string, opts HTTPOptions) *HTTP {
+	if opts.Backoff <= 0 {
+		opts.Backoff = DefaultBackoff
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.ContentType == "" {
+		opts.ContentType = ContentType("")
+	}
+	return &HTTP{URL: endpoint, opts: opts}
+}
+
+func (s *HTTP) Write(p []byte) (int, error) { return s.buf.Write(p) }
+
+// Close POSTs the buffered output, retrying failed requests with exponential backoff.
+// Connection errors, 429 and 5xx responses are retried; other responses are final.
+func (s *HTTP) Close() error {
+	wait := s.opts.Backoff
+	var err error
+	for attempt := 0; ; attempt++ {
+		var retry bool
+		retry, err = s.post()
+		if err == nil || !retry || attempt >= s.opts.Retries {
+			break
+		}
+		time.Sleep(wait)
+		wait *= 2
+	}
+	if err != nil {
+		return fmt.Errorf("output %s: %w", s, err)
+	}
+	return nil
+}
+
+// post sends one request, reporting whether a failure is worth retrying.
+func (s *HTTP) post() (retry bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.opts.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(s.buf.Bytes()))
+	if err != nil {
+		return false, err
+	}
+	for name, values := range s.opts.Headers {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	req.Header.Set("Content-Type", s.opts.ContentType)
+
+	client := s.opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return false, nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("%s", resp.Status)
+	if text := strings.TrimSpace(string(msg)); text != "" {
+		err = fmt.Errorf("%s: %s", resp.Status, text)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
+
+// String names the sink without the URL's query and credentials, which may carry tokens.
+func (s *HTTP) String() string {
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return "http"
+	}
+	u.User, u.RawQuery, u.Fragment = nil, "", ""
+	return "http=" + u.String()
+}
+
+// ParseHeader parses a "Name: value" header, such as "Authorization: Bearer $TOKEN".
+func ParseHeader(header string) (name, value string, err error) {
+	name, value, ok := strings.Cut(header, ":")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" || strings.ContainsAny(name, " \t") {
+		return "", "", fmt.Errorf("invalid output header %q (expected \"Name: value\")", header)
+	}
+	return http.CanonicalHeaderKey(name), strings.TrimSpace(value), nil
+}
+
+// Tee returns a sink writing to each of sinks. A sink failing a write is skipped from then on
+// without affecting the others; Close closes every sink and returns the errors of all of them.
+func Tee(sinks ...Sink) Sink {
+	return &tee{sinks: sinks, errs: make([]error, len(sinks))}
+}
+
+type tee struct {
+	sinks []Sink
+	errs  []error // The first write error of each sink
+}
+
+func (t *tee) Write(p []byte) (int, error) {
+	for i, s := range t.sinks {
+		if t.errs[i] != nil {
+			continue
+		}
+		if _, err := s.Write(p); err != nil {
+			t.errs[i] = fmt.Errorf("output %s: %w", s, err)
+		}
+	}
+	return len(p), nil
+}
+
+func (t *tee) Close() error {
+	var errs []error
+	for i, s := range t.sinks {
+		if t.errs[i] != nil {
+			errs = append(errs, t.errs[i])
+		}
+		if err := s.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (t *tee) String() string {
+	names := make([]string, len(t.sinks))
+	for i, s := range t.sinks {
+		names[i] = s.String()
+	}
+	return strings.Join(names, ", ")
+}
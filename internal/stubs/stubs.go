@@ -0,0 +1,165 @@
+// Package stubs generates acceptance test stubs for untested definitions. It is meant to run
+// from a go:generate directive in a definition's file:
+//
+//	//go:generate validate generate -resource current-file
+//
+// Each stub is a skipped TestAcc*_basic function in the definition's expected test file
+// (resource_widget.go gets resource_widget_test.go). Stubs carry a marker line; a stub file
+// that still has it is regenerated, and any other file at that path is left alone, so a team
+// takes ownership of a stub by writing the test and deleting the marker. Stubs are
+// quarantined, so they are linked to their definition without counting as coverage.
+package stubs
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// Marker is the first line of every stub file. A file without it is never overwritten.
+const Marker = "// Stub generated by tfprovidertest. Delete this line once the test is written; the file is then never regenerated."
+
+// Stub statuses.
+const (
+	StatusCreated   = "created"   // The stub file did not exist and was written
+	StatusUpdated   = "updated"   // An outdated stub was rewritten
+	StatusUnchanged = "unchanged" // The stub was already current
+	StatusSkipped   = "skipped"   // No stub was written; see Reason
+)
+
+// Stub is the stub test of one definition.
+type Stub struct {
+	Definition string `json:"definition"` // Registry key, e.g. "resource:widget"
+	Test       string `json:"test"`
+	File       string `json:"file"`
+	Status     string `json:"status"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// Options selects the definitions to generate stubs for.
+type Options struct {
+	// File restricts generation to definitions declared in files with this base name, such
+	// as $GOFILE under go:generate. Empty selects every file.
+	File string
+	// Names restricts generation to definitions with one of these names. Empty selects
+	// every definition.
+	Names map[string]bool
+	// ProviderTypeName prefixes resource types in stub configs, e.g. "example" gives
+	// "example_widget". Empty uses the registry's provider type name, if known.
+	ProviderTypeName string
+	// DryRun reports what would be written without writing anything.
+	DryRun bool
+}
+
+// Generate writes the stub tests of the selected definitions and returns them sorted by
+// definition key. Definitions that already have tests, other than their own stub, and those
+// whose test file exists without the marker are skipped. Output depends only on the
+// definition, so running Generate again changes nothing.
+func Generate(reg *registry.ResourceRegistry, packages map[string]string, opts Options) ([]Stub, error) {
+	typeName := opts.ProviderTypeName
+	if typeName == "" && reg.GetProvider() != nil {
+		typeName = reg.GetProvider().TypeName
+	}
+
+	var stubs []Stub
+	for _, info := range reg.GetSortedDefinitions() {
+		if info.AliasOf != "" || (opts.File != "" && filepath.Base(info.FilePath) != opts.File) ||
+			(len(opts.Names) > 0 && !opts.Names[info.Name]) {
+			continue
+		}
+		stub := Stub{
+			Definition: info.Key().String(),
+			Test:       analysis.BuildExpectedTestFunc(info),
+			File:       analysis.BuildExpectedTestPath(info),
+		}
+		stubs = append(stubs, stub)
+		last := &stubs[len(stubs)-1]
+
+		if tested := testedOutside(reg.GetTests(info.Kind, info.Name), stub.File); tested != "" {
+			last.Status, last.Reason = StatusSkipped, "already tested by "+tested
+			continue
+		}
+		existing, err := os.ReadFile(stub.File)
+		switch {
+		case os.IsNotExist(err):
+			existing = nil
+		case err != nil:
+			return nil, err
+		case !bytes.HasPrefix(existing, []byte(Marker+"\n")):
+			last.Status, last.Reason = StatusSkipped, "test file exists"
+			continue
+		}
+
+		src, err := Source(info, packages[info.FilePath], typeName)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case existing == nil:
+			last.Status = StatusCreated
+		case bytes.Equal(existing, src):
+			last.Status = StatusUnchanged
+			continue
+		default:
+			last.Status = StatusUpdated
+		}
+		if !opts.DryRun {
+			if err := os.WriteFile(stub.File, src, 0o644); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return stubs, nil
+}
+
+// testedOutside returns the name of a test linked to a definition outside its stub file, or
+// "" if there is none.
+func testedOutside(tests []*registry.TestFunctionInfo, stubFile string) string {
+	names := make([]string, 0, len(tests))
+	for _, fn := range tests {
+		if filepath.Clean(fn.FilePath) != filepath.Clean(stubFile) {
+			names = append(names, fn.Name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	return names[0]
+}
+
+// Source returns the gofmt-formatted stub test file of a definition in package pkg.
+// typeName is the provider type name used to build the config's resource type; when it is
+// empty the definition's name is used as is.
+func Source(info *registry.ResourceInfo, pkg, typeName string) ([]byte, error) {
+	if pkg == "" {
+		pkg = "provider"
+	}
+	resourceType := info.Name
+	if typeName != "" && !strings.HasPrefix(info.Name, typeName+"_") {
+		resourceType = typeName + "_" + info.Name
+	}
+	blockType := "resource"
+	if spec, ok := info.Kind.Spec(); ok && spec.BlockType != "" {
+		blockType = spec.BlockType
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\npackage %s\n\n", Marker, pkg)
+	b.WriteString("import (\n\t\"testing\"\n\n\t\"github.com/hashicorp/terraform-plugin-testing/helper/resource\"\n)\n\n")
+	fmt.Fprintf(&b, "// %s is a stub; write its config and checks.\n", analysis.BuildExpectedTestFunc(info))
+	b.WriteString("//\n//tftest:quarantine reason=\"stub generated by tfprovidertest\"\n")
+	fmt.Fprintf(&b, "func %s(t *testing.T) {\n", analysis.BuildExpectedTestFunc(info))
+	fmt.Fprintf(&b, "\tt.Skip(%q)\n\n", "stub: write the config and checks for "+resourceType)
+	b.WriteString("\tresource.Test(t, resource.TestCase{\n\t\tSteps: []resource.TestStep{\n\t\t\t{\n")
+	fmt.Fprintf(&b, "\t\t\t\tConfig: `%s %q \"test\" {}`,\n", blockType, resourceType)
+	b.WriteString("\t\t\t},\n\t\t},\n\t})\n}\n")
+	return format.Source([]byte(b.String()))
+}
@@ -0,0 +1,226 @@
+// Package testlist cross-checks the test functions found by static discovery against the
+// tests the Go toolchain actually compiles.
+//
+// Static discovery parses every _test.go file it is given, including files the toolchain
+// skips because of build constraints or GOOS/GOARCH file name suffixes, and files in
+// packages that no longer compile. Running `go test -list` in each test package returns
+// the tests that would really run, so every discovered test missing from that list
+// counts toward coverage without ever being executed.
+package testlist
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/build"
+	"go/token"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+const defaultTimeout = 10 * time.Minute
+
+// Reason explains why a discovered test is not listed by the toolchain.
+type Reason string
+
+const (
+	// ReasonBuildConstraint means the test's file is excluded by a //go:build line or a
+	// GOOS/GOARCH file name suffix under the tags in effect.
+	ReasonBuildConstraint Reason = "build-constraint"
+	// ReasonPackageError means `go test -list` failed for the package, usually because it
+	// does not compile.
+	ReasonPackageError Reason = "package-error"
+	// ReasonNotTestName means the function name is not one `go test` runs (e.g.,
+	// Testaccwidget, whose "Test" prefix is followed by a lowercase letter).
+	ReasonNotTestName Reason = "not-a-test-name"
+	// ReasonNotListed means the toolchain compiled the package but did not list the test.
+	ReasonNotListed Reason = "not-listed"
+)
+
+// ListFunc returns the names `go test -list` prints for the package in dir.
+type ListFunc func(ctx context.Context, dir string) ([]string, error)
+
+// Options configures a verification run.
+type Options struct {
+	// Tags are the build tags passed to `go test -tags` and used to evaluate build
+	// constraints when explaining a missing test.
+	Tags []string
+	// Timeout bounds all `go test -list` runs together. Defaults to 10 minutes.
+	Timeout time.Duration
+}
+
+// Discrepancy is a discovered test the toolchain does not list.
+type Discrepancy struct {
+	Test    string `json:"test"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Package string `json:"package"`
+	Reason  Reason `json:"reason"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// Result summarizes a verification run.
+type Result struct {
+	Packages      int           `json:"packages"`
+	Discovered    int           `json:"discovered"`
+	Listed        int           `json:"listed"`
+	Discrepancies []Discrepancy `json:"discrepancies"`
+}
+
+// GoTestList returns a ListFunc that runs `go test -list . .` in each package directory.
+func GoTestList(tags []string) ListFunc {
+	return func(ctx context.Context, dir string) ([]string, error) {
+		args := []string{"test", "-list", "."}
+		if len(tags) > 0 {
+			args = append(args, "-tags", strings.Join(tags, ","))
+		}
+		args = append(args, ".")
+		cmd := exec.CommandContext(ctx, "go", args...)
+		cmd.Dir = dir
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			detail := strings.TrimSpace(stderr.String())
+			if detail == "" {
+				detail = strings.TrimSpace(stdout.String())
+			}
+			return nil, fmt.Errorf("go test -list: %w\n%s", err, detail)
+		}
+		return ParseListOutput(stdout.String()), nil
+	}
+}
+
+// ParseListOutput extracts test names from `go test -list` output, skipping the package
+// status lines ("ok  example.com/p 0.01s", "?   example.com/p [no test files]").
+func ParseListOutput(out string) []string {
+	var names []string
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line == "PASS" || line == "FAIL" || strings.ContainsAny(line, " \t") {
+			continue
+		}
+		names = append(names, line)
+	}
+	return names
+}
+
+// Verify runs list once per directory holding discovered tests and reports every test
+// the toolchain does not list.
+func Verify(ctx context.Context, fset *token.FileSet, tests []*registry.TestFunctionInfo, list ListFunc, opts Options) Result {
+	if opts.Timeout == 0 {
+		opts.Timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	byDir := make(map[string][]*registry.TestFunctionInfo)
+	for _, fn := range tests {
+		dir := filepath.Dir(fn.FilePath)
+		byDir[dir] = append(byDir[dir], fn)
+	}
+	dirs := make([]string, 0, len(byDir))
+	for dir := range byDir {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	buildCtx := build.Default
+	buildCtx.BuildTags = append([]string(nil), opts.Tags...)
+
+	result := Result{Packages: len(dirs), Discovered: len(tests), Discrepancies: []Discrepancy{}}
+	for _, dir := range dirs {
+		names, listErr := list(ctx, dir)
+		listed := make(map[string]bool, len(names))
+		for _, name := range names {
+			listed[name] = true
+		}
+
+		for _, fn := range byDir[dir] {
+			if listErr == nil && listed[fn.Name] {
+				result.Listed++
+				continue
+			}
+			d := Discrepancy{
+				Test:    fn.Name,
+				File:    fn.FilePath,
+				Package: dir,
+			}
+			if fset != nil && fn.FunctionPos.IsValid() {
+				d.Line = fset.Position(fn.FunctionPos).Line
+			}
+			switch {
+			case !isTestName(fn.Name):
+				d.Reason = ReasonNotTestName
+			case !fileMatches(&buildCtx, fn.FilePath):
+				d.Reason = ReasonBuildConstraint
+				if len(opts.Tags) > 0 {
+					d.Detail = "tags: " + strings.Join(opts.Tags, ",")
+				}
+			case listErr != nil:
+				d.Reason = ReasonPackageError
+				d.Detail = listErr.Error()
+			default:
+				d.Reason = ReasonNotListed
+			}
+			result.Discrepancies = append(result.Discrepancies, d)
+		}
+	}
+
+	sort.SliceStable(result.Discrepancies, func(i, j int) bool {
+		a, b := result.Discrepancies[i], result.Discrepancies[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	return result
+}
+
+// Describe returns a one-line explanation of a discrepancy for text output.
+func (d Discrepancy) Describe() string {
+	switch d.Reason {
+	case ReasonBuildConstraint:
+		msg := "file is excluded by build constraints, so the test is never compiled"
+		if d.Detail != "" {
+			msg += " (" + d.Detail + ")"
+		}
+		return msg
+	case ReasonPackageError:
+		return "go test -list failed for the package, so the test does not compile"
+	case ReasonNotTestName:
+		return "name is not run by go test (\"Test\" must be followed by an uppercase letter, digit or underscore)"
+	default:
+		return "go test -list does not list the test"
+	}
+}
+
+// isTestName reports whether go test treats name as a test: "Test" followed by nothing or
+// by a character that is not a lowercase letter.
+func isTestName(name string) bool {
+	if !strings.HasPrefix(name, "Test") {
+		return false
+	}
+	if len(name) == len("Test") {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(name[len("Test"):])
+	return !unicode.IsLower(r)
+}
+
+// fileMatches reports whether the toolchain would compile path under ctxt. Files that
+// cannot be read are assumed to match, leaving the decision to the listing.
+func fileMatches(ctxt *build.Context, path string) bool {
+	match, err := ctxt.MatchFile(filepath.Dir(path), filepath.Base(path))
+	if err != nil {
+		return true
+	}
+	return match
+}
@@ -0,0 +1,77 @@
+// Package testsrc builds the source of provider test files for the tests of the analyzers
+// and of validate, which parse test files rather than compile them. Every file is in
+// package provider and imports testing and the SDK's helper/resource package.
+package testsrc
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ResourceImport is the import path of the SDK's helper/resource package.
+const ResourceImport = "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+// Test is an acceptance test function that runs one resource.TestCase.
+type Test struct {
+	Name    string   // Function name, e.g. TestAccWidget_basic
+	Imports []string // Import paths besides testing and helper/resource, e.g. fmt
+	Setup   string   // Statements before resource.Test, e.g. address := "example_widget.test"
+	Case    string   // Fields of the TestCase besides Steps, e.g. ErrorCheck: testAccErrorCheck,
+	Steps   []string // Fields of each TestStep, e.g. Config: `...`, ImportState: true
+}
+
+// Config returns the Config field of a step applying config.
+func Config(config string) string {
+	return "Config: `" + config + "`"
+}
+
+// Acceptance returns a file with the test name, whose single step applies config.
+func Acceptance(name, config string) string {
+	return File(Test{Name: name, Steps: []string{Config(config)}})
+}
+
+// File returns a file declaring tests, importing what they import with the standard
+// library before other packages.
+func File(tests ...Test) string {
+	std := map[string]bool{"testing": true}
+	other := map[string]bool{ResourceImport: true}
+	for _, test := range tests {
+		for _, path := range test.Imports {
+			if strings.Contains(path, ".") {
+				other[path] = true
+			} else {
+				std[path] = true
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("package provider\n\nimport (\n")
+	writeImports(&b, std)
+	b.WriteString("\n")
+	writeImports(&b, other)
+	b.WriteString(")\n")
+	for _, test := range tests {
+		b.WriteString("\nfunc " + test.Name + "(t *testing.T) {\n")
+		if test.Setup != "" {
+			b.WriteString("\t" + test.Setup + "\n")
+		}
+		b.WriteString("\tresource.Test(t, resource.TestCase{\n")
+		if test.Case != "" {
+			b.WriteString("\t\t" + test.Case + "\n")
+		}
+		b.WriteString("\t\tSteps: []resource.TestStep{\n")
+		for _, step := range test.Steps {
+			b.WriteString("\t\t\t{" + step + "},\n")
+		}
+		b.WriteString("\t\t},\n\t})\n}\n")
+	}
+	return b.String()
+}
+
+func writeImports(b *strings.Builder, paths map[string]bool) {
+	for _, path := range slices.Sorted(maps.Keys(paths)) {
+		b.WriteString("\t\"" + path + "\"\n")
+	}
+}
@@ -0,0 +1,243 @@
+// Package workspace resolves import paths through a Go workspace (go.work).
+//
+// Providers developed alongside their SDK or shared test helpers often use a go.work file
+// whose use directives (and local replace directives) point at sibling modules. The
+// validate CLI parses directories directly rather than through go/packages, so it uses
+// this package to find where an imported helper package lives on disk. golangci-lint
+// loads packages with go/packages, which already honors go.work.
+package workspace
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// EnvGoWork is the environment variable the go command uses to select a workspace file.
+// "off" disables workspace mode; a path selects that file.
+const EnvGoWork = "GOWORK"
+
+// Module sources.
+const (
+	SourceUse     = "use"
+	SourceReplace = "replace"
+)
+
+// Module is a module made available by the workspace.
+type Module struct {
+	Path   string // Module path from go.mod, or the replaced path
+	Dir    string // Absolute directory containing the module
+	Source string // SourceUse or SourceReplace
+}
+
+// Workspace is a parsed go.work file.
+type Workspace struct {
+	File    string   // Absolute path of the go.work file
+	Modules []Module // Sorted by path; replace directives take precedence over use directives
+	// Problems lists use directives that could not be resolved, e.g. a directory without go.mod.
+	Problems []string
+}
+
+// Find locates the workspace file for dir the way the go command does: GOWORK when set,
+// otherwise the nearest go.work in dir or a parent. It returns nil when there is none or
+// workspace mode is off.
+func Find(dir string) (*Workspace, error) {
+	switch env := os.Getenv(EnvGoWork); env {
+	case "off":
+		return nil, nil
+	case "":
+	default:
+		return Load(env)
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		candidate := filepath.Join(abs, "go.work")
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return Load(candidate)
+		}
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return nil, nil
+		}
+		abs = parent
+	}
+}
+
+// Load parses a go.work file and the go.mod of each module it uses.
+func Load(path string) (*Workspace, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, err
+	}
+	uses, replaces, err := parseWork(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", abs, err)
+	}
+
+	ws := &Workspace{File: abs}
+	root := filepath.Dir(abs)
+	byPath := make(map[string]Module)
+	for _, use := range uses {
+		dir := resolveDir(root, use)
+		modPath, err := ModulePath(filepath.Join(dir, "go.mod"))
+		if err != nil {
+			ws.Problems = append(ws.Problems, fmt.Sprintf("use %s: %v", use, err))
+			continue
+		}
+		byPath[modPath] = Module{Path: modPath, Dir: dir, Source: SourceUse}
+	}
+	for oldPath, newPath := range replaces {
+		if !isLocalPath(newPath) {
+			continue // Version replacements do not change where the source lives locally
+		}
+		byPath[oldPath] = Module{Path: oldPath, Dir: resolveDir(root, newPath), Source: SourceReplace}
+	}
+
+	for _, m := range byPath {
+		ws.Modules = append(ws.Modules, m)
+	}
+	sort.Slice(ws.Modules, func(i, j int) bool { return ws.Modules[i].Path < ws.Modules[j].Path })
+	return ws, nil
+}
+
+// Resolve returns the directory of an imported package when it belongs to a workspace
+// module. The longest matching module path wins, as with nested modules.
+func (w *Workspace) Resolve(importPath string) (string, bool) {
+	if w == nil {
+		return "", false
+	}
+	best := -1
+	for i, m := range w.Modules {
+		if importPath != m.Path && !strings.HasPrefix(importPath, m.Path+"/") {
+			continue
+		}
+		if best < 0 || len(m.Path) > len(w.Modules[best].Path) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return "", false
+	}
+	m := w.Modules[best]
+	rest := strings.TrimPrefix(strings.TrimPrefix(importPath, m.Path), "/")
+	return filepath.Join(m.Dir, filepath.FromSlash(rest)), true
+}
+
+// parseWork extracts use directories and replace directives from go.work source.
+// Both the single-line and block forms are accepted.
+func parseWork(src string) (uses []string, replaces map[string]string, err error) {
+	replaces = make(map[string]string)
+	block := ""
+	scanner := bufio.NewScanner(strings.NewReader(src))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := stripComment(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if block != "" {
+			if line == ")" {
+				block = ""
+				continue
+			}
+			if err := parseDirective(block, line, &uses, replaces); err != nil {
+				return nil, nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			continue
+		}
+
+		verb, args, _ := strings.Cut(line, " ")
+		args = strings.TrimSpace(args)
+		if verb != "use" && verb != "replace" {
+			continue // go, toolchain, godebug
+		}
+		if args == "(" {
+			block = verb
+			continue
+		}
+		if err := parseDirective(verb, args, &uses, replaces); err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+	}
+	if block != "" {
+		return nil, nil, fmt.Errorf("unterminated %s block", block)
+	}
+	return uses, replaces, scanner.Err()
+}
+
+// parseDirective records the arguments of a single use or replace directive.
+func parseDirective(verb, args string, uses *[]string, replaces map[string]string) error {
+	fields := strings.Fields(args)
+	for i := range fields {
+		fields[i] = strings.Trim(fields[i], "\"`")
+	}
+	switch verb {
+	case "use":
+		if len(fields) != 1 {
+			return fmt.Errorf("use expects one directory, got %q", args)
+		}
+		*uses = append(*uses, fields[0])
+	case "replace":
+		// old [version] => new [version]
+		arrow := -1
+		for i, f := range fields {
+			if f == "=>" {
+				arrow = i
+			}
+		}
+		if arrow < 1 || arrow == len(fields)-1 {
+			return fmt.Errorf("malformed replace %q", args)
+		}
+		replaces[fields[0]] = fields[arrow+1]
+	}
+	return nil
+}
+
+// ModulePath reads the module directive of a go.mod file.
+func ModulePath(goMod string) (string, error) {
+	data, err := os.ReadFile(goMod)
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = stripComment(line)
+		if rest, ok := strings.CutPrefix(line, "module"); ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t') {
+			if path := strings.Trim(strings.TrimSpace(rest), "\"`"); path != "" {
+				return path, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%s has no module directive", goMod)
+}
+
+// stripComment removes a // comment and surrounding whitespace from a line.
+func stripComment(line string) string {
+	if i := strings.Index(line, "//"); i >= 0 {
+		line = line[:i]
+	}
+	return strings.TrimSpace(line)
+}
+
+// isLocalPath reports whether a replacement target is a directory rather than a module path.
+func isLocalPath(path string) bool {
+	return filepath.IsAbs(path) || path == "." || path == ".." ||
+		strings.HasPrefix(path, "./") || strings.HasPrefix(path, "../")
+}
+
+// resolveDir makes a go.work-relative directory absolute.
+func resolveDir(root, dir string) string {
+	if filepath.IsAbs(dir) {
+		return filepath.Clean(dir)
+	}
+	return filepath.Join(root, filepath.FromSlash(dir))
+}
@@ -0,0 +1,77 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+// kindTestEphemeral is registered once per test binary, since kinds are global.
+var kindTestEphemeral, kindTestEphemeralErr = registry.RegisterKind(registry.KindSpec{
+	Name:      "test ephemeral resource",
+	Key:       "test_ephemeral_resource",
+	BlockType: "test_ephemeral",
+	Package:   "testephemeral",
+	Checks:    []string{registry.CheckBasic},
+})
+
+func TestKindRegistry(t *testing.T) {
+	t.Run("built-in kinds keep their values and strings", func(t *testing.T) {
+		assert.Equal(t, registry.ResourceKind(0), registry.KindResource)
+		assert.Equal(t, "resource", registry.KindResource.String())
+		assert.Equal(t, "data source", registry.KindDataSource.String())
+		assert.Equal(t, "action", registry.KindAction.String())
+		assert.Equal(t, "unknown", registry.ResourceKind(-1).String())
+		assert.Equal(t, "data_source", registry.KindDataSource.Key())
+
+		kind, ok := registry.KindFromBlockType("data")
+		assert.True(t, ok)
+		assert.Equal(t, registry.KindDataSource, kind)
+		kind, ok = registry.KindFromPackage("action")
+		assert.True(t, ok)
+		assert.Equal(t, registry.KindAction, kind)
+
+		assert.True(t, registry.CheckApplies(registry.KindResource, registry.CheckImport))
+		assert.False(t, registry.CheckApplies(registry.KindResource, registry.CheckManagedConfig))
+		assert.True(t, registry.CheckApplies(registry.KindDataSource, registry.CheckManagedConfig))
+		assert.False(t, registry.CheckApplies(registry.KindAction, registry.CheckUpdate))
+	})
+
+	t.Run("registered kind works through the generic lookups", func(t *testing.T) {
+		require.NoError(t, kindTestEphemeralErr)
+		assert.Contains(t, registry.Kinds(), kindTestEphemeral)
+		assert.Equal(t, "test ephemeral resource", kindTestEphemeral.String())
+
+		kind, ok := registry.KindFromBlockType("test_ephemeral")
+		assert.True(t, ok)
+		assert.Equal(t, kindTestEphemeral, kind)
+		kind, ok = registry.KindFromPackage("testephemeral")
+		assert.True(t, ok)
+		assert.Equal(t, kindTestEphemeral, kind)
+
+		key, ok := registry.ParseResourceKey(registry.ResourceKey{Kind: kindTestEphemeral, Name: "session"}.String())
+		assert.True(t, ok)
+		assert.Equal(t, registry.ResourceKey{Kind: kindTestEphemeral, Name: "session"}, key)
+
+		assert.True(t, registry.CheckApplies(kindTestEphemeral, registry.CheckBasic))
+		assert.False(t, registry.CheckApplies(kindTestEphemeral, registry.CheckDrift))
+
+		reg := registry.NewResourceRegistry()
+		reg.RegisterResource(&registry.ResourceInfo{Name: "session", Kind: kindTestEphemeral})
+		reg.RegisterResource(&registry.ResourceInfo{Name: "session", Kind: registry.KindResource})
+		assert.Len(t, reg.GetAllDefinitions(), 2, "same name, different kinds")
+		assert.Equal(t, kindTestEphemeral, reg.GetDefinition(kindTestEphemeral, "session").Kind)
+	})
+
+	t.Run("conflicting kinds are rejected", func(t *testing.T) {
+		_, err := registry.RegisterKind(registry.KindSpec{Name: "data source", Key: "other"})
+		assert.ErrorContains(t, err, `kind "data source" is already registered`)
+		_, err = registry.RegisterKind(registry.KindSpec{Name: "list resource", Key: "list_resource", BlockType: "data"})
+		assert.ErrorContains(t, err, `block type "data" is used by kind "data source"`)
+		_, err = registry.RegisterKind(registry.KindSpec{Name: "function"})
+		assert.ErrorContains(t, err, "name and key are required")
+	})
+}
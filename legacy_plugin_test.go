@@ -0,0 +1,18 @@
+package tfprovidertest
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestLegacyPluginBuild runs the tests of the plugin for golangci-lint versions that load
+// analyzers through GetAnalyzers, which build only with the tfprovidertest_legacy tag.
+func TestLegacyPluginBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the legacy plugin")
+	}
+	out, err := exec.Command("go", "test", "-count=1", "-tags", "tfprovidertest_legacy", "./cmd/tfprovidertest-plugin").CombinedOutput()
+	require.NoError(t, err, "testing the legacy plugin: %s", out)
+}
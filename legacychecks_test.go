@@ -0,0 +1,93 @@
+package tfprovidertest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// gadgetChecksTestSrc has a test asserting state with Check in its first step and with
+// ConfigStateChecks in its second.
+const gadgetChecksTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+)
+
+func TestAccGadget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: ` + "`" + `resource "example_gadget" "g" {}` + "`" + `,
+				Check:  resource.TestCheckResourceAttrSet("example_gadget.g", "id"),
+			},
+			{
+				Config:            ` + "`" + `resource "example_gadget" "g" { name = "x" }` + "`" + `,
+				ConfigStateChecks: []statecheck.StateCheck{},
+			},
+		},
+	})
+}
+`
+
+func TestLegacyCheckMigration(t *testing.T) {
+	// The second step of the current test sets Check as well
+	current := strings.Replace(gadgetChecksTestSrc, "ConfigStateChecks: []statecheck.StateCheck{},",
+		`Check:             resource.TestCheckResourceAttr("example_gadget.g", "name", "x"),`, 1)
+	sources := map[string]string{
+		"/provider/resource_gadget.go":      untestedGadgetResourceSrc,
+		"/provider/resource_gadget_test.go": current,
+	}
+	reg := buildRegistryFromSources(t, sources)
+
+	t.Run("counts", func(t *testing.T) {
+		migration := analysis.CheckMigrationOf(reg.GetAllTestFunctions())
+		assert.Equal(t, analysis.CheckMigration{LegacySteps: 2}, migration)
+
+		base := buildRegistryFromSources(t, map[string]string{
+			"/provider/resource_gadget.go":      untestedGadgetResourceSrc,
+			"/provider/resource_gadget_test.go": gadgetChecksTestSrc,
+		})
+		assert.Equal(t, analysis.CheckMigration{LegacySteps: 1, StateCheckSteps: 1}, analysis.CheckMigrationOf(base.GetAllTestFunctions()))
+	})
+
+	t.Run("added since the base", func(t *testing.T) {
+		base := map[string]string{"/provider/resource_gadget_test.go": gadgetChecksTestSrc}
+		fileAt := func(path string) ([]byte, bool, error) {
+			src, ok := base[path]
+			return []byte(src), ok, nil
+		}
+		added, err := analysis.AddedLegacyChecks(reg, config.DefaultSettings(), fileAt)
+		require.NoError(t, err)
+		require.Len(t, added, 1, "the Check the base already had is not reported")
+		assert.Equal(t, "TestAccGadget_basic", added[0].Test.Name)
+		assert.Equal(t, 2, added[0].Step.StepNumber)
+		assert.Equal(t, 1, added[0].BaseLegacySteps)
+
+		base["/provider/resource_gadget_test.go"] = current
+		added, err = analysis.AddedLegacyChecks(reg, config.DefaultSettings(), fileAt)
+		require.NoError(t, err)
+		assert.Empty(t, added, "unchanged tests keep their Check fields")
+
+		delete(base, "/provider/resource_gadget_test.go")
+		added, err = analysis.AddedLegacyChecks(reg, config.DefaultSettings(), fileAt)
+		require.NoError(t, err)
+		assert.Len(t, added, 2, "every Check of a new file is added")
+	})
+
+	t.Run("settings", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		assert.False(t, settings.LegacyCheckMigrationEnabled(), "the rule is opt-in")
+		settings.EnableLegacyCheckMigration = true
+		assert.Empty(t, runAnalyzerOnSources(t, analysis.RunLegacyChecksAnalyzer, settings, sources),
+			"without since the legacy corpus is not reported")
+	})
+}
@@ -0,0 +1,102 @@
+package tfprovidertest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/matching"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const lowConfidenceResourceSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+type CertificateResource struct{}
+
+func (r *CertificateResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{Required: true},
+		},
+	}
+}
+`
+
+const lowConfidenceTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccCertifcat_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: "# no blocks"},
+		},
+	})
+}
+`
+
+func TestLinkerRecordsMatchedResource(t *testing.T) {
+	reg := registry.NewResourceRegistry()
+	reg.RegisterResource(&registry.ResourceInfo{Name: "widget"})
+	fn := &registry.TestFunctionInfo{Name: "TestAccWidget_basic", FilePath: "/test.go"}
+	reg.RegisterTestFunction(fn)
+
+	matching.NewLinker(reg, config.DefaultSettings()).LinkTestsToResources()
+
+	assert.Equal(t, "widget", fn.MatchedResource)
+	assert.Equal(t, 0.95, fn.MatchConfidence)
+}
+
+func TestLowConfidenceLinkWarnings(t *testing.T) {
+	sources := map[string]string{
+		"/provider/resource_certificate.go": lowConfidenceResourceSrc,
+		"/provider/misc_test.go":            lowConfidenceTestSrc,
+	}
+
+	t.Run("fuzzy link below threshold is reported", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		settings.EnableFuzzyMatching = true
+
+		messages := runBasicAnalyzerOnSources(t, settings, sources)
+
+		var found string
+		for _, msg := range messages {
+			if strings.HasPrefix(msg, "test 'TestAccCertifcat_basic'") {
+				found = msg
+			}
+		}
+		require.NotEmpty(t, found, "expected a low-confidence diagnostic, got %v", messages)
+		assert.Contains(t, found, "linked to 'certificate' with 0.82 confidence via fuzzy match")
+		assert.Contains(t, found, "TestAccCertificate_basic")
+	})
+
+	t.Run("disabled when threshold is zero", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		settings.EnableFuzzyMatching = true
+		settings.LinkConfidenceWarningThreshold = 0
+
+		for _, msg := range runBasicAnalyzerOnSources(t, settings, sources) {
+			assert.NotContains(t, msg, "confidence via")
+		}
+	})
+
+	t.Run("threshold outside range is rejected", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		settings.LinkConfidenceWarningThreshold = 1.5
+		assert.Error(t, settings.Validate())
+	})
+}
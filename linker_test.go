@@ -3,11 +3,14 @@
 package tfprovidertest
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 	"testing"
 
 	"github.com/example/tfprovidertest/internal/analysis"
 	"github.com/example/tfprovidertest/internal/matching"
+	"github.com/example/tfprovidertest/internal/naming"
 	"github.com/example/tfprovidertest/internal/registry"
 	"github.com/example/tfprovidertest/pkg/config"
 )
@@ -759,3 +762,97 @@ func TestLinkerResourceSuffixStripping(t *testing.T) {
 		t.Errorf("expected 1 host test, got %d (TestAccHostResource should match 'host')", len(hostTests))
 	}
 }
+
+// largeLinkerRegistry registers resources with names built from common provider words and
+// tests for each of them, every fourth one named after a misspelled resource so that only
+// the fuzzy strategy ranks it.
+func largeLinkerRegistry(resources, tests int) *registry.ResourceRegistry {
+	words := []string{"compute", "network", "storage", "bucket", "instance", "policy", "role", "group", "subnet", "gateway", "cluster", "node", "pool", "key", "secret", "record", "zone", "alert", "rule", "endpoint"}
+	reg := registry.NewResourceRegistry()
+	names := make([]string, resources)
+	for i := range names {
+		names[i] = fmt.Sprintf("%s_%s_%s%d", words[i%len(words)], words[(i/len(words))%len(words)], words[(i/7)%len(words)], i)
+		reg.RegisterResource(&registry.ResourceInfo{Name: names[i], Kind: registry.KindResource})
+	}
+	for i := 0; i < tests; i++ {
+		name := names[i%len(names)]
+		if i%4 == 3 {
+			name = strings.Replace(name, "_", "", 1) + "x"
+		}
+		reg.RegisterTestFunction(&registry.TestFunctionInfo{
+			Name:     "TestAcc" + naming.TitleCase(name) + fmt.Sprintf("_case%d", i),
+			FilePath: fmt.Sprintf("/provider/tests_%d_test.go", i%500),
+		})
+	}
+	return reg
+}
+
+func TestLinkerFuzzyCandidatesMatchPairwiseSimilarity(t *testing.T) {
+	reg := largeLinkerRegistry(300, 1200)
+	settings := config.DefaultSettings()
+	settings.EnableFuzzyMatching = true
+	matching.NewLinker(reg, settings).LinkTestsToResources()
+
+	names := make([]string, 0, len(reg.GetAllDefinitions()))
+	for _, info := range reg.GetAllDefinitions() {
+		names = append(names, info.Name)
+	}
+	fuzzyTests := 0
+	for _, fn := range reg.GetAllTestFunctions() {
+		var got []string
+		for _, c := range reg.GetMatchCandidates(fn) {
+			if c.Strategy == matching.StrategyFuzzy {
+				got = append(got, fmt.Sprintf("%s %.4f", c.Key.Name, c.Confidence))
+			}
+		}
+		if fn.MatchType != registry.MatchTypeNone && fn.MatchType != registry.MatchTypeFuzzy {
+			if len(got) != 0 {
+				t.Errorf("%s: fuzzy candidates recorded for a test linked by another strategy: %v", fn.Name, got)
+			}
+			continue
+		}
+		fuzzyTests++
+
+		// Rank every definition pairwise, as the linker did before indexing names
+		extracted, _ := matching.ExtractResourceFromFuncName(fn.Name)
+		type ranked struct {
+			name       string
+			similarity float64
+		}
+		var all []ranked
+		for _, name := range names {
+			if extracted == "" {
+				break
+			}
+			if s := matching.CalculateSimilarity(extracted, name); s >= matching.FuzzyNearMissThreshold {
+				all = append(all, ranked{name, s})
+			}
+		}
+		sort.Slice(all, func(i, j int) bool {
+			if all[i].similarity != all[j].similarity {
+				return all[i].similarity > all[j].similarity
+			}
+			return all[i].name < all[j].name
+		})
+		var want []string
+		for i := 0; i < len(all) && i < 3; i++ {
+			want = append(want, fmt.Sprintf("%s %.4f", all[i].name, all[i].similarity))
+		}
+		if strings.Join(got, ", ") != strings.Join(want, ", ") {
+			t.Errorf("%s: fuzzy candidates %v, pairwise ranking %v", fn.Name, got, want)
+		}
+	}
+	if fuzzyTests == 0 {
+		t.Fatal("no test was left to the fuzzy strategy")
+	}
+}
+
+func BenchmarkLinker_LinkTestsToResources(b *testing.B) {
+	settings := config.DefaultSettings()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		reg := largeLinkerRegistry(5000, 20000)
+		b.StartTimer()
+		matching.NewLinker(reg, settings).LinkTestsToResources()
+	}
+}
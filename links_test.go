@@ -0,0 +1,41 @@
+package tfprovidertest
+
+import (
+	"go/token"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/example/tfprovidertest/internal/links"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+func TestRepoURLLinks(t *testing.T) {
+	root := t.TempDir()
+	link := links.New("https://github.com/org/repo/blob/main/{path}#L{line}", root, nil)
+
+	assert.Equal(t, "https://github.com/org/repo/blob/main/internal/service/widget.go#L42",
+		link.URL(filepath.Join(root, "internal", "service", "widget.go"), 42))
+	assert.Equal(t, "https://github.com/org/repo/blob/main/widget.go#L1",
+		link.URL(filepath.Join(root, "widget.go"), 0), "an unknown line links to the first")
+	assert.Empty(t, link.URL(filepath.Join(filepath.Dir(root), "other", "widget.go"), 3),
+		"files outside the root are not linked")
+
+	fset := token.NewFileSet()
+	file := fset.AddFile(filepath.Join(root, "resource_widget.go"), -1, 100)
+	file.SetLines([]int{0, 10, 20})
+	link = links.New("https://example.com/{path}?line={line}", root, fset)
+	assert.Equal(t, "https://example.com/resource_widget.go?line=2", link.Pos(file.Pos(15)))
+	assert.Empty(t, link.Pos(token.NoPos))
+
+	var disabled links.Linker
+	assert.False(t, disabled.Enabled())
+	assert.Empty(t, disabled.URL(filepath.Join(root, "widget.go"), 1))
+
+	settings := config.DefaultSettings()
+	settings.RepoURLTemplate = "https://github.com/org/repo/blob/main/"
+	assert.ErrorContains(t, settings.Validate(), "has no {path} placeholder")
+	settings.RepoURLTemplate = "https://github.com/org/repo/blob/main/{path}"
+	assert.NoError(t, settings.Validate())
+}
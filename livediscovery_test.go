@@ -0,0 +1,67 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/livediscovery"
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+const liveSchemaJSON = `{
+  "format_version": "1.0",
+  "provider_schemas": {
+    "registry.terraform.io/hashicorp/example": {
+      "provider": {},
+      "resource_schemas": {
+        "example_widget": {},
+        "example_gadget": {}
+      },
+      "data_source_schemas": {
+        "example_widget": {}
+      }
+    }
+  }
+}`
+
+func TestLiveDiscoveryParseSchemaJSON(t *testing.T) {
+	schemas, err := livediscovery.ParseSchemaJSON([]byte(liveSchemaJSON), "registry.terraform.io/hashicorp/example")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example_gadget", "example_widget"}, schemas.Resources)
+	assert.Equal(t, []string{"example_widget"}, schemas.DataSources)
+	assert.Empty(t, schemas.Actions)
+
+	_, err = livediscovery.ParseSchemaJSON([]byte(liveSchemaJSON), "registry.terraform.io/hashicorp/other")
+	assert.Error(t, err)
+}
+
+func TestLiveDiscoveryCompare(t *testing.T) {
+	schemas, err := livediscovery.ParseSchemaJSON([]byte(liveSchemaJSON), "registry.terraform.io/hashicorp/example")
+	require.NoError(t, err)
+
+	reg := registry.NewResourceRegistry()
+	// Short and full names both count as matches
+	reg.RegisterResource(&registry.ResourceInfo{Name: "widget", Kind: registry.KindResource})
+	reg.RegisterResource(&registry.ResourceInfo{Name: "example_widget", Kind: registry.KindDataSource})
+	// Discovered statically but not registered by the provider
+	reg.RegisterResource(&registry.ResourceInfo{Name: "legacy", Kind: registry.KindResource})
+
+	result := livediscovery.Compare(schemas, reg)
+	assert.Equal(t, 3, result.LiveTotal)
+	assert.Equal(t, 2, result.Matched)
+	assert.InDelta(t, 2.0/3.0, result.Confidence, 0.0001)
+	assert.Equal(t, []string{"resource:example_gadget"}, result.MissingInStatic)
+	assert.Equal(t, []string{"resource:legacy"}, result.UnknownToLive)
+}
+
+func TestLiveDiscoveryEnvironment(t *testing.T) {
+	t.Setenv(livediscovery.EnvEnable, "")
+	assert.False(t, livediscovery.Enabled())
+	t.Setenv(livediscovery.EnvEnable, "1")
+	assert.True(t, livediscovery.Enabled())
+
+	assert.Equal(t, "registry.terraform.io/hashicorp/aws",
+		livediscovery.DefaultProviderAddress("/src/terraform-provider-aws"))
+}
@@ -0,0 +1,105 @@
+package tfprovidertest
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	goanalysis "golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/internal/matching"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// candidatesTestSrc is explainTestSrc with TestAccGadget_basic declaring only the widget, so
+// the config strategies agree on one definition whatever order its blocks are recorded in.
+var candidatesTestSrc = strings.Replace(explainTestSrc, `resource "example_widget" "w" {}
+resource "example_gadget" "g" {}`, `resource "example_widget" "w" {}`, 1)
+
+func TestMatchCandidates(t *testing.T) {
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for name, src := range map[string]string{
+		"/provider/resource_widget.go": explainWidgetResourceSrc,
+		"/provider/resource_gadget.go": untestedGadgetResourceSrc,
+		"/provider/misc_test.go":       candidatesTestSrc,
+	} {
+		f, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+		require.NoError(t, err)
+		files = append(files, f)
+	}
+	build := func(settings config.Settings) *registry.ResourceRegistry {
+		return discovery.BuildRegistry(&goanalysis.Pass{Fset: fset, Files: files}, settings)
+	}
+	widget := registry.ResourceKey{Kind: registry.KindResource, Name: "widget"}
+	gadget := registry.ResourceKey{Kind: registry.KindResource, Name: "gadget"}
+
+	t.Run("every strategy is recorded", func(t *testing.T) {
+		reg := build(config.DefaultSettings())
+		fn := findTest(t, reg, "TestAccGadget_basic")
+		candidates := reg.GetMatchCandidates(fn)
+		require.Len(t, candidates, 3)
+
+		assert.Equal(t, gadget, candidates[0].Key)
+		assert.Equal(t, matching.StrategyFunctionName, candidates[0].Strategy)
+		assert.True(t, candidates[0].Accepted)
+		for _, c := range candidates[1:] {
+			assert.Equal(t, widget, c.Key, c.Strategy)
+			assert.False(t, c.Accepted)
+			assert.Equal(t, "outranked by the function-name match", c.Note)
+		}
+		assert.Equal(t, "gadget", fn.MatchedResource, "the linked resource is unchanged")
+	})
+
+	t.Run("fuzzy near misses", func(t *testing.T) {
+		reg := build(config.DefaultSettings())
+		fn := findTest(t, reg, "TestAccWidgit_basic")
+		assert.Equal(t, registry.MatchTypeNone, fn.MatchType)
+		candidates := reg.GetMatchCandidates(fn)
+		require.NotEmpty(t, candidates)
+		assert.Equal(t, widget, candidates[0].Key, "the most similar name comes first")
+		assert.Equal(t, "fuzzy matching is disabled (enable-fuzzy-matching)", candidates[0].Note)
+		for _, c := range candidates[1:] {
+			assert.Less(t, c.Confidence, matching.FuzzyLinkThreshold)
+			assert.Contains(t, c.Note, "below the fuzzy link threshold")
+		}
+
+		settings := config.DefaultSettings()
+		settings.EnableFuzzyMatching = true
+		reg = build(settings)
+		fn = findTest(t, reg, "TestAccWidgit_basic")
+		assert.Equal(t, "widget", fn.MatchedResource)
+		assert.True(t, reg.GetMatchCandidates(fn)[0].Accepted)
+	})
+
+	t.Run("near misses report", func(t *testing.T) {
+		nearMisses := analysis.NearMisses(build(config.DefaultSettings()), fset)
+		var tests []string
+		for _, tc := range nearMisses {
+			tests = append(tests, tc.Test)
+		}
+		assert.Equal(t, []string{"TestAccGadget_basic", "TestAccWidgit_basic"}, tests,
+			"tests without candidates are left out")
+		assert.Equal(t, "resource:gadget", nearMisses[0].Linked)
+		assert.Equal(t, 9, nearMisses[1].Line)
+		assert.Empty(t, nearMisses[1].Linked)
+	})
+}
+
+func findTest(t *testing.T, reg *registry.ResourceRegistry, name string) *registry.TestFunctionInfo {
+	t.Helper()
+	for _, fn := range reg.GetAllTestFunctions() {
+		if fn.Name == name {
+			return fn
+		}
+	}
+	t.Fatalf("test %s not found", name)
+	return nil
+}
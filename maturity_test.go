@@ -0,0 +1,105 @@
+package tfprovidertest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const maturityResourceSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+type WidgetResource struct{}
+
+func (r *WidgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{Optional: true},
+		},
+	}
+}
+
+func (r *WidgetResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+`
+
+func TestMaturityExemptions(t *testing.T) {
+	tagged := func(directives string) map[string]string {
+		return map[string]string{
+			"/provider/resource_widget.go":      strings.Replace(maturityResourceSrc, "type WidgetResource struct{}", directives+"\ntype WidgetResource struct{}", 1),
+			"/provider/resource_widget_test.go": directiveTestSrc,
+		}
+	}
+	settings := config.DefaultSettings()
+
+	t.Run("untagged resources are checked", func(t *testing.T) {
+		sources := tagged("")
+		assert.Len(t, runAnalyzerOnSources(t, analysis.RunImportTestAnalyzer, settings, sources), 1)
+		assert.Len(t, runAnalyzerOnSources(t, analysis.RunDriftCheckAnalyzer, settings, sources), 1)
+	})
+
+	t.Run("experimental resources skip import by default", func(t *testing.T) {
+		sources := tagged("//tftest:maturity experimental")
+		assert.Empty(t, runAnalyzerOnSources(t, analysis.RunImportTestAnalyzer, settings, sources))
+		assert.Len(t, runAnalyzerOnSources(t, analysis.RunDriftCheckAnalyzer, settings, sources), 1)
+	})
+
+	t.Run("levels without exemptions are checked", func(t *testing.T) {
+		sources := tagged("//tftest:maturity ga")
+		assert.Len(t, runAnalyzerOnSources(t, analysis.RunImportTestAnalyzer, settings, sources), 1)
+	})
+
+	t.Run("configured exemptions replace the defaults", func(t *testing.T) {
+		custom := config.DefaultSettings()
+		custom.MaturityExemptions = map[string][]string{registry.MaturityBeta: {registry.CheckDrift}}
+
+		beta := tagged("//tftest:maturity beta")
+		assert.Empty(t, runAnalyzerOnSources(t, analysis.RunDriftCheckAnalyzer, custom, beta))
+		assert.Len(t, runAnalyzerOnSources(t, analysis.RunImportTestAnalyzer, custom, beta), 1)
+
+		experimental := tagged("//tftest:maturity experimental")
+		assert.Len(t, runAnalyzerOnSources(t, analysis.RunImportTestAnalyzer, custom, experimental), 1)
+	})
+
+	t.Run("expected checks are still enforced", func(t *testing.T) {
+		sources := tagged("//tftest:maturity experimental\n//tftest:expect import")
+		assert.Len(t, runAnalyzerOnSources(t, analysis.RunImportTestAnalyzer, settings, sources), 1)
+	})
+
+	t.Run("maturity from the requirements manifest", func(t *testing.T) {
+		manifest := writeManifest(t, "resources:\n  widget:\n    maturity: experimental\n")
+		sources := tagged("")
+		assert.Empty(t, runAnalyzerOnSources(t, analysis.RunImportTestAnalyzer, manifest, sources))
+
+		// A directive takes precedence over the manifest
+		sources = tagged("//tftest:maturity ga")
+		assert.Len(t, runAnalyzerOnSources(t, analysis.RunImportTestAnalyzer, manifest, sources), 1)
+	})
+}
+
+func TestMaturityExemptionsValidation(t *testing.T) {
+	settings := config.DefaultSettings()
+	settings.MaturityExemptions = map[string][]string{"alpha": {registry.CheckUpdate}}
+	err := settings.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown maturity level "alpha"`)
+
+	settings.MaturityExemptions = map[string][]string{registry.MaturityBeta: {"updates"}}
+	err = settings.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown check "updates"`)
+}
@@ -0,0 +1,137 @@
+package tfprovidertest
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	goanalysis "golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/messages"
+	"github.com/example/tfprovidertest/internal/rules"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+var placeholderRegex = regexp.MustCompile(`\{[A-Za-z]+\}`)
+
+func placeholders(tmpl string) []string {
+	found := placeholderRegex.FindAllString(tmpl, -1)
+	unique := make(map[string]bool)
+	for _, p := range found {
+		unique[p] = true
+	}
+	var result []string
+	for p := range unique {
+		result = append(result, p)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func TestMessageCatalog(t *testing.T) {
+	t.Run("translations use the same placeholders as English", func(t *testing.T) {
+		for _, lang := range messages.Languages() {
+			for _, id := range messages.IDs() {
+				if !messages.Translated(lang, id) {
+					continue
+				}
+				assert.Equal(t, placeholders(messages.Template(string(messages.English), id)),
+					placeholders(messages.Template(string(lang), id)), "%s/%s", lang, id)
+			}
+		}
+	})
+
+	t.Run("Japanese catalog is complete", func(t *testing.T) {
+		for _, id := range messages.IDs() {
+			assert.True(t, messages.Translated(messages.Japanese, id), "missing ja translation for %s", id)
+		}
+	})
+
+	t.Run("parameter substitution", func(t *testing.T) {
+		msg := messages.Format("en", messages.DriftCheckMissing, messages.Params{"name": "widget", "count": 2})
+		assert.True(t, strings.HasPrefix(msg, "resource 'widget' has 2 test(s) but none include CheckDestroy"))
+	})
+
+	t.Run("unknown language falls back to English", func(t *testing.T) {
+		assert.Equal(t, messages.Format("en", messages.SweepersMissing, nil), messages.Format("fr", messages.SweepersMissing, nil))
+		assert.False(t, messages.Supported("fr"))
+		assert.True(t, messages.Supported(""))
+	})
+
+	t.Run("unsupported language is rejected by settings", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		settings.Language = "fr"
+		assert.Error(t, settings.Validate())
+		settings.Language = "ja"
+		assert.NoError(t, settings.Validate())
+	})
+}
+
+func TestLocalizedDiagnostics(t *testing.T) {
+	sources := map[string]string{
+		"/provider/provider.go":             providerSrc,
+		"/provider/resource_widget_test.go": unrelatedTestSrc,
+	}
+
+	settings := config.DefaultSettings()
+	settings.Language = "ja"
+	messagesJa := runAnalyzerOnSources(t, analysis.RunProviderConfigAnalyzer, settings, sources)
+	require.Len(t, messagesJa, 1)
+	assert.Contains(t, messagesJa[0], "プロバイダー 'example' には設定属性がありますが")
+	assert.Contains(t, messagesJa[0], "endpoint, token, insecure")
+}
+
+func TestCompactMessages(t *testing.T) {
+	t.Run("first line with counted nouns and the rule code", func(t *testing.T) {
+		msg := messages.Format("en", messages.DriftCheckMissing, messages.Params{"name": "widget", "count": 1})
+		assert.Equal(t, "resource 'widget' has 1 test but none include CheckDestroy for drift detection [TFPT023]",
+			messages.Compact(msg, rules.Code(rules.DriftCheck)))
+
+		msg = messages.Format("en", messages.ProviderHygieneNoTestMain, messages.Params{"package": "p", "sweepers": 3})
+		assert.Contains(t, messages.Compact(msg, ""), "registers 3 test sweepers but has no TestMain")
+	})
+
+	t.Run("short message style through the plugin", func(t *testing.T) {
+		analysis.ClearAllRegistryCaches()
+		t.Cleanup(analysis.ClearAllRegistryCaches)
+
+		settings := config.DefaultSettings()
+		settings.MessageStyle = config.MessageStyleShort
+		require.NoError(t, settings.Validate())
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "/provider/resource_gadget.go", untestedGadgetResourceSrc, parser.ParseComments)
+		require.NoError(t, err)
+
+		analyzers, err := NewWithSettings(settings).BuildAnalyzers()
+		require.NoError(t, err)
+		var reported []string
+		for _, a := range analyzers {
+			if a.Name != rules.BasicTest {
+				continue
+			}
+			_, err := a.Run(&goanalysis.Pass{
+				Analyzer: a,
+				Fset:     fset,
+				Files:    []*ast.File{file},
+				Report:   func(d goanalysis.Diagnostic) { reported = append(reported, d.Message) },
+			})
+			require.NoError(t, err)
+		}
+		require.Len(t, reported, 1)
+		assert.Equal(t, "resource 'gadget' has no acceptance test [TFPT001]", reported[0])
+	})
+
+	t.Run("unknown style is rejected by settings", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		settings.MessageStyle = "terse"
+		assert.ErrorContains(t, settings.Validate(), `unsupported message-style "terse"`)
+	})
+}
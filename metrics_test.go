@@ -0,0 +1,71 @@
+package tfprovidertest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/pkg/metrics"
+)
+
+func TestMetrics(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+	day := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		require.NoError(t, err)
+		return d
+	}
+
+	paths := []string{
+		// Monday and Wednesday of one week: the later report represents the week
+		write("report-2024-05-06.json", `{"resources": [{"name": "s3_bucket", "test_count": 0}]}`),
+		write("report-2024-05-08.json", `{"resources": [{"name": "s3_bucket", "test_count": 2}, {"name": "s3_object", "test_count": 0}],
+			"data_sources": [{"name": "s3_bucket", "test_count": 1}]}`),
+		write("run_20240508.json", `{"findings": [
+			{"rule": "tfprovider-coverage-basic-test", "group": "coverage", "resource": "s3_object", "kind": "resource"},
+			{"rule": "tfprovider-quality-drift-check", "group": "quality", "resource": "s3_bucket", "kind": "resource"},
+			{"rule": "tfprovider-quality-drift-check", "group": "quality"}
+		]}`),
+		write("report-2024-05-13.json", `{"resources": [{"name": "s3_bucket", "test_count": 2}, {"name": "s3_object", "test_count": 1}]}`),
+	}
+	var snaps []metrics.Snapshot
+	for _, path := range paths {
+		snap, err := metrics.Load(path)
+		require.NoError(t, err)
+		snaps = append(snaps, snap)
+	}
+	assert.Equal(t, day("2024-05-08"), snaps[2].Time, "dated by the compact date in the name")
+
+	agg := metrics.Aggregate(snaps, metrics.Week)
+	assert.Equal(t, []metrics.CoveragePoint{
+		{Bucket: day("2024-05-06"), Key: "data source", Total: 1, Tested: 1, Coverage: 1},
+		{Bucket: day("2024-05-06"), Key: "resource", Total: 2, Tested: 1, Coverage: 0.5},
+		{Bucket: day("2024-05-13"), Key: "resource", Total: 2, Tested: 2, Coverage: 1},
+	}, agg.KindCoverage)
+	assert.Equal(t, []metrics.CoveragePoint{
+		{Bucket: day("2024-05-06"), Key: "s3", Total: 3, Tested: 2, Coverage: 2.0 / 3, Findings: 2},
+		{Bucket: day("2024-05-13"), Key: "s3", Total: 2, Tested: 2, Coverage: 1},
+	}, agg.ServiceCoverage, "findings without a resource are not attributed to a service")
+	assert.Equal(t, []metrics.RulePoint{
+		{Bucket: day("2024-05-06"), Rule: "tfprovider-coverage-basic-test", Group: "coverage", Findings: 1},
+		{Bucket: day("2024-05-06"), Rule: "tfprovider-quality-drift-check", Group: "quality", Findings: 2},
+	}, agg.RuleFrequency)
+
+	daily := metrics.KindCoverage(snaps, metrics.Day)
+	require.NotEmpty(t, daily)
+	assert.Equal(t, metrics.CoveragePoint{Bucket: day("2024-05-06"), Key: "resource", Total: 1}, daily[0])
+
+	assert.Equal(t, day("2024-05-01"), metrics.Month.Start(day("2024-05-31")))
+	assert.Equal(t, day("2024-05-06"), metrics.Week.Start(day("2024-05-12")), "weeks start on Monday")
+	_, err := metrics.ParseBucket("year")
+	assert.Error(t, err)
+}
@@ -0,0 +1,128 @@
+package tfprovidertest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const minTestsResourceSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+type WidgetResource struct{}
+
+func (r *WidgetResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_widget"
+}
+
+func (r *WidgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{Required: true},
+		},
+	}
+}
+`
+
+const minTestsTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccWidget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: ` + "`" + `resource "example_widget" "test" { name = "a" }` + "`" + `},
+		},
+	})
+}
+`
+
+func belowMinimumMessages(messages []string) []string {
+	var found []string
+	for _, msg := range messages {
+		if strings.Contains(msg, "of required") {
+			found = append(found, msg)
+		}
+	}
+	return found
+}
+
+func TestMinTestsPerResource(t *testing.T) {
+	sources := map[string]string{
+		"/provider/resource_widget.go":      minTestsResourceSrc,
+		"/provider/resource_widget_test.go": minTestsTestSrc,
+	}
+
+	t.Run("default minimum of one is satisfied", func(t *testing.T) {
+		messages := runBasicAnalyzerOnSources(t, config.DefaultSettings(), sources)
+		assert.Empty(t, belowMinimumMessages(messages))
+	})
+
+	t.Run("reports counts when below the global minimum", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		settings.MinTestsPerResource = 2
+
+		found := belowMinimumMessages(runBasicAnalyzerOnSources(t, settings, sources))
+		require.Len(t, found, 1)
+		assert.Contains(t, found[0], "resource 'widget' has 1 of required 2 acceptance tests")
+		assert.Contains(t, found[0], "Tests: TestAccWidget_basic")
+		assert.Contains(t, found[0], "Add 1 more acceptance test(s)")
+	})
+
+	t.Run("per-kind override takes precedence", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		settings.MinTestsPerResource = 3
+		settings.MinTestsPerKind = map[string]int{"resource": 1}
+		assert.Empty(t, belowMinimumMessages(runBasicAnalyzerOnSources(t, settings, sources)))
+
+		settings.MinTestsPerResource = 1
+		settings.MinTestsPerKind = map[string]int{"resource": 3}
+		found := belowMinimumMessages(runBasicAnalyzerOnSources(t, settings, sources))
+		require.Len(t, found, 1)
+		assert.Contains(t, found[0], "has 1 of required 3 acceptance tests")
+	})
+}
+
+func TestMinTestsForKind(t *testing.T) {
+	settings := config.DefaultSettings()
+	settings.MinTestsPerResource = 2
+	settings.MinTestsPerKind = map[string]int{"data-source": 1, "action": 0}
+
+	assert.Equal(t, 2, settings.MinTestsForKind("resource"))
+	assert.Equal(t, 1, settings.MinTestsForKind("data source"))
+	assert.Equal(t, 1, settings.MinTestsForKind("action"), "values below 1 are treated as 1")
+
+	settings.MinTestsPerResource = 0
+	settings.MinTestsPerKind = nil
+	assert.Equal(t, 1, settings.MinTestsForKind("resource"))
+
+	t.Run("validation", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		settings.MinTestsPerResource = -1
+		assert.Error(t, settings.Validate())
+
+		settings = config.DefaultSettings()
+		settings.MinTestsPerKind = map[string]int{"module": 2}
+		assert.Error(t, settings.Validate())
+
+		settings.MinTestsPerKind = map[string]int{"data_source": -1}
+		assert.Error(t, settings.Validate())
+
+		settings.MinTestsPerKind = map[string]int{"data_source": 2, "resource": 2}
+		assert.NoError(t, settings.Validate())
+	})
+}
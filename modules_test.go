@@ -11,6 +11,7 @@ import (
 	"github.com/example/tfprovidertest/internal/analysis"
 	"github.com/example/tfprovidertest/internal/discovery"
 	"github.com/example/tfprovidertest/internal/matching"
+	"github.com/example/tfprovidertest/internal/naming"
 	"github.com/example/tfprovidertest/internal/registry"
 	"github.com/example/tfprovidertest/pkg/config"
 )
@@ -114,7 +115,7 @@ func TestUtils_Module(t *testing.T) {
 		}
 
 		for _, tc := range tests {
-			result := matching.SnakeCaseToTitleCaseExported(tc.input)
+			result := naming.TitleCase(tc.input)
 			assert.Equal(t, tc.expected, result, "Input: %s", tc.input)
 		}
 	})
@@ -181,11 +182,11 @@ func TestAnalyzer_Module(t *testing.T) {
 		for _, a := range analyzers {
 			analyzerNames[a.Name] = true
 		}
-		assert.True(t, analyzerNames["tfprovider-resource-basic-test"])
-		assert.True(t, analyzerNames["tfprovider-resource-update-test"])
-		assert.True(t, analyzerNames["tfprovider-resource-import-test"])
-		assert.True(t, analyzerNames["tfprovider-test-error-cases"])
-		assert.True(t, analyzerNames["tfprovider-test-check-functions"])
+		assert.True(t, analyzerNames["tfprovider-coverage-basic-test"])
+		assert.True(t, analyzerNames["tfprovider-coverage-update-test"])
+		assert.True(t, analyzerNames["tfprovider-coverage-import-test"])
+		assert.True(t, analyzerNames["tfprovider-coverage-error-test"])
+		assert.True(t, analyzerNames["tfprovider-quality-check-functions"])
 	})
 
 	t.Run("Plugin BuildAnalyzers returns enabled analyzers", func(t *testing.T) {
@@ -217,9 +218,9 @@ func TestAnalyzer_Module(t *testing.T) {
 		for _, a := range analyzers {
 			analyzerNames[a.Name] = true
 		}
-		assert.True(t, analyzerNames["tfprovider-resource-basic-test"])
-		assert.True(t, analyzerNames["tfprovider-test-drift-check"])
-		assert.True(t, analyzerNames["tfprovider-test-sweepers"])
+		assert.True(t, analyzerNames["tfprovider-coverage-basic-test"])
+		assert.True(t, analyzerNames["tfprovider-quality-drift-check"])
+		assert.True(t, analyzerNames["tfprovider-quality-sweepers"])
 	})
 }
 
@@ -376,6 +377,56 @@ func TestLinkTestToResource(t *testing.T) {
 	})
 }
 
+// Test typed ResourceKey lookups
+func TestResourceKeyLookups(t *testing.T) {
+	t.Run("LinkTest and GetTests keep kinds separate", func(t *testing.T) {
+		reg := registry.NewResourceRegistry()
+		reg.RegisterResource(&registry.ResourceInfo{Name: "widget", Kind: registry.KindResource})
+		reg.RegisterResource(&registry.ResourceInfo{Name: "widget", Kind: registry.KindDataSource})
+
+		resourceTest := &registry.TestFunctionInfo{Name: "TestAccWidget_basic"}
+		dataSourceTest := &registry.TestFunctionInfo{Name: "TestAccWidgetDataSource_basic"}
+		reg.LinkTest(registry.KindResource, "widget", resourceTest)
+		reg.LinkTest(registry.KindDataSource, "widget", dataSourceTest)
+
+		assert.Equal(t, []*registry.TestFunctionInfo{resourceTest}, reg.GetTests(registry.KindResource, "widget"))
+		assert.Equal(t, []*registry.TestFunctionInfo{dataSourceTest}, reg.GetTests(registry.KindDataSource, "widget"))
+		assert.Empty(t, reg.GetTests(registry.KindAction, "widget"))
+
+		// The deprecated string-keyed lookup still aggregates simple names
+		assert.Len(t, reg.GetResourceTests("widget"), 2)
+	})
+
+	t.Run("ResourceKey round-trips through its string form", func(t *testing.T) {
+		key := registry.ResourceKey{Kind: registry.KindDataSource, Name: "widget"}
+		assert.Equal(t, "data source:widget", key.String())
+
+		parsed, ok := registry.ParseResourceKey(key.String())
+		assert.True(t, ok)
+		assert.Equal(t, key, parsed)
+
+		_, ok = registry.ParseResourceKey("widget")
+		assert.False(t, ok)
+		_, ok = registry.ParseResourceKey("data:widget")
+		assert.False(t, ok)
+	})
+
+	t.Run("ResolveKey and GetDefinition", func(t *testing.T) {
+		reg := registry.NewResourceRegistry()
+		info := &registry.ResourceInfo{Name: "job", Kind: registry.KindAction}
+		reg.RegisterResource(info)
+
+		key, ok := reg.ResolveKey("job")
+		assert.True(t, ok)
+		assert.Equal(t, info.Key(), key)
+		assert.Same(t, info, reg.GetDefinition(registry.KindAction, "job"))
+		assert.Nil(t, reg.GetDefinition(registry.KindResource, "job"))
+
+		_, ok = reg.ResolveKey("missing")
+		assert.False(t, ok)
+	})
+}
+
 // Test GetUnmatchedTestFunctions
 func TestGetUnmatchedTestFunctions(t *testing.T) {
 	t.Run("should return functions with MatchTypeNone", func(t *testing.T) {
@@ -1023,6 +1074,14 @@ func TestTestStepInfo_IsRealUpdateStep(t *testing.T) {
 			},
 			expected: true,
 		},
+		{
+			name: "not update step - refresh step",
+			step: registry.TestStepInfo{
+				StepNumber:   2,
+				RefreshState: true,
+			},
+			expected: false,
+		},
 		{
 			name: "not update step - import step even with config",
 			step: registry.TestStepInfo{
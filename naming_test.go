@@ -0,0 +1,118 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	goanalysis "golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/internal/matching"
+	"github.com/example/tfprovidertest/internal/naming"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+func TestNamePipeline(t *testing.T) {
+	defaults := naming.Default()
+	assert.Equal(t, "HTTP_basic", defaults.StripPrefix("TestAccDataSourceHTTP_basic"))
+	assert.Equal(t, "http_server", defaults.SnakeCase("HTTPServer"))
+	assert.Equal(t, "eda_event_stream", defaults.SnakeCase("EDAEventStream"))
+
+	p, err := naming.New(map[string]string{"EventStream": "eventstream", "EDAEventStream": "eda_eventstream_v2"}, []string{"OAuth", "DNS", "VPC"})
+	require.NoError(t, err)
+	assert.Equal(t, "eda_eventstream_v2", p.SnakeCase("EDAEventStream"), "longer fragments win")
+	assert.Equal(t, "aap_eventstream", p.SnakeCase("AAPEventStream"))
+	assert.Equal(t, "oauth_dns_vpc_link", p.SnakeCase("OAuthDNSVPCLink"))
+	assert.Equal(t, "event_streams", p.SnakeCase("EventStreams"), "a fragment followed by a lowercase letter is not a word")
+	assert.Equal(t, "my_dns_record", p.SnakeCase("MyDNSRecord"), "words start at case changes")
+	assert.Equal(t, "subdnsvpc", p.SnakeCase("subdnsvpc"), "fragments inside a lowercase word do not match")
+
+	_, err = naming.New(map[string]string{"EventStream": "EventStream"}, nil)
+	assert.ErrorContains(t, err, "is not snake_case")
+	_, err = naming.New(nil, []string{"O-Auth"})
+	assert.ErrorContains(t, err, "invalid name acronym")
+
+	settings := config.DefaultSettings()
+	settings.NameReplacements = map[string]string{"EventStream": "Event_Stream"}
+	assert.ErrorContains(t, settings.Validate(), "not snake_case")
+}
+
+func TestLinkerNameReplacements(t *testing.T) {
+	link := func(settings config.Settings) []*registry.TestFunctionInfo {
+		reg := registry.NewResourceRegistry()
+		reg.RegisterResource(&registry.ResourceInfo{Name: "eventstream"})
+		reg.RegisterTestFunction(&registry.TestFunctionInfo{Name: "TestAccEDAEventStream_basic", FilePath: "/test.go"})
+		matching.NewLinker(reg, &settings).LinkTestsToResources()
+		return reg.GetResourceTests("eventstream")
+	}
+
+	assert.Empty(t, link(config.DefaultSettings()), "EDAEventStream splits into eda_event_stream by default")
+
+	settings := config.DefaultSettings()
+	settings.NameReplacements = map[string]string{"EventStream": "eventstream"}
+	tests := link(settings)
+	require.Len(t, tests, 1)
+	assert.Equal(t, registry.MatchTypeFunctionName, tests[0].MatchType)
+}
+
+func TestDefinitionNameReplacements(t *testing.T) {
+	sources := map[string]string{
+		// Named from the type of its Schema method
+		"/provider/resource_eda_eventstream.go": `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+type EDAEventStreamResource struct{}
+
+func (r *EDAEventStreamResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {}
+`,
+		// Named from its factory function
+		"/provider/resource_oauth_client.go": `package provider
+
+import "github.com/hashicorp/terraform-plugin-framework/resource"
+
+func NewOAuthClientResource() resource.Resource {
+	return nil
+}
+`,
+		// Named from its factory function, without a Metadata method
+		"/provider/action_eventstream_post.go": `package provider
+
+import "github.com/hashicorp/terraform-plugin-framework/action"
+
+type EventStreamPostAction struct{}
+
+func NewEventStreamPostAction() action.Action {
+	return &EventStreamPostAction{}
+}
+`,
+	}
+	names := []string{"/provider/action_eventstream_post.go", "/provider/resource_eda_eventstream.go", "/provider/resource_oauth_client.go"}
+	definitions := func(settings config.Settings) []string {
+		fset, files := parseSources(t, names, sources)
+		var keys []string
+		for _, def := range discovery.BuildProviderRegistry(fset, files, settings, nil).GetSortedDefinitions() {
+			keys = append(keys, def.Key().String())
+		}
+		return keys
+	}
+
+	assert.ElementsMatch(t, []string{"resource:eda_event_stream", "resource:o_auth_client", "action:event_stream_post"}, definitions(config.DefaultSettings()))
+
+	settings := config.DefaultSettings()
+	settings.NameReplacements = map[string]string{"EventStream": "eventstream"}
+	settings.NameAcronyms = []string{"OAuth"}
+	assert.ElementsMatch(t, []string{"resource:eda_eventstream", "resource:oauth_client", "action:eventstream_post"}, definitions(settings))
+
+	// The analyzers build their registry the same way
+	fset, files := parseSources(t, names, sources)
+	reg := discovery.BuildRegistry(&goanalysis.Pass{Fset: fset, Files: files}, settings)
+	assert.NotNil(t, reg.GetDefinition(registry.KindResource, "eda_eventstream"))
+	assert.NotNil(t, reg.GetDefinition(registry.KindResource, "oauth_client"))
+}
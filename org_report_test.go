@@ -0,0 +1,105 @@
+package tfprovidertest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/pkg/metrics"
+)
+
+func TestOrgReport(t *testing.T) {
+	dir := t.TempDir()
+	load := func(name, content string) metrics.Snapshot {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		snap, err := metrics.Load(path)
+		require.NoError(t, err)
+		return snap
+	}
+
+	snaps := []metrics.Snapshot{
+		// An older report of aws is superseded by the later one
+		load("aws-2024-05-01.json", `{"resources": [{"name": "s3_bucket", "test_count": 0}]}`),
+		load("aws-2024-05-08.json", `{"resources": [{"name": "s3_bucket", "test_count": 2}, {"name": "s3_object", "test_count": 0}],
+			"top_risks": [{"name": "s3_object", "kind": "resource", "complexity": {"score": 12}}]}`),
+		load("report.json", `{"summary": {"provider": "google"},
+			"resources": [{"name": "compute_instance", "test_count": 1}],
+			"data_sources": [{"name": "compute_image", "test_count": 0}, {"name": "compute_zones", "test_count": 0}],
+			"top_risks": [{"name": "compute_image", "kind": "data source", "complexity": {"score": 20}},
+				{"name": "compute_zones", "kind": "data source", "complexity": {"score": 3}}]}`),
+		load("azure.json", `{"resources": [{"name": "storage_account", "test_count": 3}]}`),
+	}
+	assert.Equal(t, "aws", metrics.ProviderName(snaps[0]), "named by the file without its date")
+	assert.Equal(t, "google", metrics.ProviderName(snaps[2]), "named by the provider in the summary")
+
+	reports := metrics.Latest(snaps)
+	require.Len(t, reports, 3)
+	assert.Equal(t, snaps[1].Source, reports["aws"].Source)
+
+	org, err := metrics.Merge(reports, 2)
+	require.NoError(t, err)
+	var names []string
+	for _, p := range org.Providers {
+		names = append(names, p.Provider)
+	}
+	assert.Equal(t, []string{"aws", "azure", "google"}, names)
+	assert.Equal(t, 6, org.Totals.Total)
+	assert.Equal(t, 3, org.Totals.Tested)
+	assert.Equal(t, []metrics.KindSummary{
+		{Kind: "data source", Total: 2, Tested: 0, Coverage: 0},
+		{Kind: "resource", Total: 4, Tested: 3, Coverage: 0.75},
+	}, org.Totals.Kinds)
+
+	require.Len(t, org.WorstProviders, 2, "fully covered providers are not listed")
+	assert.Equal(t, "google", org.WorstProviders[0].Provider)
+	assert.Equal(t, 2, org.WorstProviders[0].Untested)
+	assert.Equal(t, "aws", org.WorstProviders[1].Provider)
+
+	assert.Equal(t, []metrics.ProviderRisk{
+		{Provider: "google", Risk: metrics.Risk{Name: "compute_image", Kind: "data source", Score: 20}},
+		{Provider: "aws", Risk: metrics.Risk{Name: "s3_object", Kind: "resource", Score: 12}},
+	}, org.TopRisks, "limited to the top risks across providers")
+
+	run := load("run_20240508.json", `{"findings": []}`)
+	_, err = metrics.Merge(map[string]metrics.Snapshot{"aws": run}, 10)
+	assert.ErrorContains(t, err, "analyzer run")
+}
+
+func TestOrgReportNamedInputs(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(`{"resources": [{"name": "s3_bucket", "test_count": 1}]}`), 0o644))
+		return path
+	}
+	plain := write("aws-2024-05-08.json")
+	equals := write(filepath.Join("runs", "id=3", "aws.json"))
+
+	tests := []struct {
+		name     string
+		entry    string
+		provider string
+		source   string
+	}{
+		{name: "file", entry: plain, provider: "aws", source: plain},
+		{name: "named file", entry: "amazon=" + plain, provider: "amazon", source: plain},
+		{name: "file with = in its path", entry: equals, provider: "aws", source: equals},
+		{name: "named file with = in its path", entry: "amazon=" + equals, provider: "amazon", source: equals},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snap, err := metrics.LoadNamed(tt.entry)
+			require.NoError(t, err)
+			assert.Equal(t, tt.provider, metrics.ProviderName(snap))
+			assert.Equal(t, tt.source, snap.Source)
+		})
+	}
+
+	_, err := metrics.LoadNamed("amazon=" + filepath.Join(dir, "missing.json"))
+	assert.ErrorContains(t, err, "missing.json")
+}
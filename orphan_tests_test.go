@@ -0,0 +1,55 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const orphanTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccWidgt_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: ` + "`" + `resource "example_gizmo" "test" {}` + "`" + `}},
+	})
+}
+
+func TestAccQuux_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: "provider \"example\" {}"}},
+	})
+}
+
+func TestParseLegacyID(t *testing.T) {}
+`
+
+func TestOrphanTestsAnalyzer(t *testing.T) {
+	settings := config.DefaultSettings()
+	settings.EnableOrphanTestCheck = true
+
+	messages := runAnalyzerOnSources(t, analysis.RunOrphanTestsAnalyzer, settings, map[string]string{
+		"/provider/resource_widget.go": directiveResourceSrc,
+		"/provider/legacy_test.go":     orphanTestSrc,
+	})
+	require.Len(t, messages, 2, "only acceptance tests are reported")
+
+	assert.Contains(t, messages[0], "acceptance test 'TestAccWidgt_basic' is not linked to any resource, data source or action")
+	assert.Contains(t, messages[0], "Test: /provider/legacy_test.go:9")
+	assert.Contains(t, messages[0], "Inferred from configs: resource.example_gizmo")
+	assert.Contains(t, messages[0], "If it tests resource 'widget' (name similarity")
+	assert.Contains(t, messages[0], "rename it to TestAccWidget_basic in /provider/resource_widget_test.go")
+
+	assert.Contains(t, messages[1], "acceptance test 'TestAccQuux_basic' is not linked")
+	assert.NotContains(t, messages[1], "Inferred from configs")
+	assert.Contains(t, messages[1], "Name the test after the definition it tests")
+}
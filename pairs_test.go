@@ -0,0 +1,91 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/testsrc"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const gadgetResourceTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccGadget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: ` + "`" + `resource "example_gadget" "g" {}` + "`" + `}},
+	})
+}
+`
+
+func TestPairedDefinitionsAnalyzer(t *testing.T) {
+	settings := config.DefaultSettings()
+	settings.EnablePairedDefinitionTest = true
+
+	t.Run("data source tested, resource untested", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunPairedDefinitionsAnalyzer, settings, map[string]string{
+			"/provider/resource_gadget.go":         untestedGadgetResourceSrc,
+			"/provider/data_source_gadget.go":      gadgetDataSourceSrc,
+			"/provider/data_source_gadget_test.go": testsrc.Acceptance("TestAccGadgetDataSource_basic", `data "example_gadget" "g" {}`),
+		})
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "resource 'gadget' has no acceptance test, but the data source of the same name has 1 test(s)")
+		assert.Contains(t, messages[0], "(TestAccGadgetDataSource_basic)")
+	})
+
+	t.Run("resource tested, data source untested", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunPairedDefinitionsAnalyzer, settings, map[string]string{
+			"/provider/resource_gadget.go":      untestedGadgetResourceSrc,
+			"/provider/resource_gadget_test.go": gadgetResourceTestSrc,
+			"/provider/data_source_gadget.go":   gadgetDataSourceSrc,
+		})
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "data source 'gadget' has no acceptance test, but the resource of the same name has 1 test(s)")
+		assert.Contains(t, messages[0], "Data source: /provider/data_source_gadget.go:")
+	})
+
+	t.Run("both sides tested or untested", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunPairedDefinitionsAnalyzer, settings, map[string]string{
+			"/provider/resource_gadget.go":         untestedGadgetResourceSrc,
+			"/provider/resource_gadget_test.go":    gadgetResourceTestSrc,
+			"/provider/data_source_gadget.go":      gadgetDataSourceSrc,
+			"/provider/data_source_gadget_test.go": testsrc.Acceptance("TestAccGadgetDataSource_basic", `data "example_gadget" "g" {}`),
+		})
+		assert.Empty(t, messages)
+
+		messages = runAnalyzerOnSources(t, analysis.RunPairedDefinitionsAnalyzer, settings, map[string]string{
+			"/provider/resource_gadget.go":    untestedGadgetResourceSrc,
+			"/provider/data_source_gadget.go": gadgetDataSourceSrc,
+		})
+		assert.Empty(t, messages, "basic-test reports definitions untested on both sides")
+	})
+}
+
+func TestDefinitionPairs(t *testing.T) {
+	reg := buildRegistryFromSources(t, map[string]string{
+		"/provider/resource_gadget.go":         untestedGadgetResourceSrc,
+		"/provider/data_source_gadget.go":      gadgetDataSourceSrc,
+		"/provider/data_source_gadget_test.go": testsrc.Acceptance("TestAccGadgetDataSource_basic", `data "example_gadget" "g" {}`),
+		"/provider/data_source_regions.go":     regionsDataSourceSrc,
+	})
+	pairs := analysis.DefinitionPairs(reg)
+	require.Len(t, pairs, 1, "a data source without a resource of its name is no pair")
+	assert.Equal(t, "gadget", pairs[0].Name)
+	assert.Equal(t, 0, pairs[0].ResourceTests)
+	assert.Equal(t, 1, pairs[0].DataSourceTests)
+	assert.False(t, pairs[0].Tested())
+
+	tested, untested, ok := pairs[0].HalfTested()
+	require.True(t, ok)
+	assert.Equal(t, pairs[0].DataSource, tested)
+	assert.Equal(t, pairs[0].Resource, untested)
+	assert.Equal(t, pairs[0].DataSource, analysis.PairOf(reg, pairs[0].Resource))
+}
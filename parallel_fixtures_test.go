@@ -0,0 +1,119 @@
+package tfprovidertest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const parallelFixturesTestSrc = `package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+const testAccRoleTemplate = ` + "`" + `
+resource "aws_iam_role" "test" {
+  name = %q
+}
+` + "`" + `
+
+func TestAccBucket_basic(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccBucketConfig("tf-acc-shared")},
+		},
+	})
+}
+
+func TestAccBucket_tags(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: ` + "`" + `
+resource "aws_s3_bucket" "test" {
+  bucket = "tf-acc-shared"
+  tags = {
+    name = "nested"
+  }
+}
+` + "`" + `},
+		},
+	})
+}
+
+func TestAccBucket_random(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-acc")
+	resource.ParallelTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccBucketConfig(rName)},
+		},
+	})
+}
+
+func TestAccBucket_serial(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccBucketConfig("tf-acc-shared")},
+		},
+	})
+}
+
+func TestAccRole_one(t *testing.T) {
+	t.Parallel()
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: fmt.Sprintf(testAccRoleTemplate, "shared-role")},
+		},
+	})
+}
+
+func TestAccRole_two(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: fmt.Sprintf(testAccRoleTemplate, "shared-role")},
+		},
+	})
+}
+
+func testAccBucketConfig(name string) string {
+	return fmt.Sprintf(` + "`" + `
+resource "aws_s3_bucket" "test" {
+  bucket = %[1]q
+  region = "us-east-1"
+}
+` + "`" + `, name)
+}
+`
+
+func TestParallelFixturesAnalyzer(t *testing.T) {
+	sources := map[string]string{"/provider/resource_bucket_test.go": parallelFixturesTestSrc}
+
+	messages := runAnalyzerOnSources(t, analysis.RunParallelFixturesAnalyzer, config.DefaultSettings(), sources)
+	require.Len(t, messages, 4, "messages: %v", messages)
+
+	joined := strings.Join(messages, "\n")
+	assert.Contains(t, joined, `parallel test 'TestAccBucket_basic' hard-codes aws_s3_bucket.bucket = "tf-acc-shared", which is also used by TestAccBucket_tags`)
+	assert.Contains(t, joined, `parallel test 'TestAccBucket_tags' hard-codes aws_s3_bucket.bucket = "tf-acc-shared", which is also used by TestAccBucket_basic`)
+	assert.Contains(t, joined, `parallel test 'TestAccRole_one' hard-codes aws_iam_role.name = "shared-role", which is also used by TestAccRole_two`)
+	assert.NotContains(t, joined, "TestAccBucket_serial", "serial tests do not run alongside parallel ones")
+	assert.NotContains(t, joined, "TestAccBucket_random")
+	assert.NotContains(t, joined, "region", "only name-like attributes are compared")
+	assert.NotContains(t, joined, "nested", "nested block attributes are not compared")
+
+	t.Run("attribute patterns are configurable", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		settings.ParallelFixtureAttributes = []string{"region"}
+		messages := runAnalyzerOnSources(t, analysis.RunParallelFixturesAnalyzer, settings, sources)
+		require.Len(t, messages, 2, "messages: %v", messages)
+		assert.Contains(t, messages[0], `aws_s3_bucket.region = "us-east-1"`)
+	})
+}
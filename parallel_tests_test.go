@@ -0,0 +1,112 @@
+package tfprovidertest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const parallelTestsResourceSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+type WidgetResource struct{}
+
+func (r *WidgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{Required: true},
+		},
+	}
+}
+`
+
+const parallelTestsTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccWidget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccWidgetConfig("one")},
+		},
+	})
+}
+
+func TestAccWidget_update(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccWidgetConfig("one")},
+			{Config: testAccWidgetConfig("two")},
+		},
+	})
+}
+
+func TestAccWidget_tags(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccWidgetConfig("one")},
+		},
+	})
+}
+`
+
+func TestParallelTestsAnalyzer(t *testing.T) {
+	settings := config.DefaultSettings()
+	settings.EnableParallelTestCheck = true
+
+	run := func(t *testing.T, testSrc string) []string {
+		return runAnalyzerOnSources(t, analysis.RunParallelTestsAnalyzer, settings, map[string]string{
+			"/provider/resource_widget.go":      parallelTestsResourceSrc,
+			"/provider/resource_widget_test.go": testSrc,
+		})
+	}
+
+	t.Run("serial tests of an independent resource are reported", func(t *testing.T) {
+		messages := run(t, parallelTestsTestSrc)
+		require.Len(t, messages, 2)
+		assert.Contains(t, messages[0], "acceptance test 'TestAccWidget_basic' uses resource.Test")
+		assert.Contains(t, messages[0], "/provider/resource_widget_test.go:9")
+		assert.Contains(t, messages[1], "acceptance test 'TestAccWidget_update' uses resource.Test")
+		// 2 serial tests, then 1 parallel batch; all 3 fit in one batch of 4
+		assert.Contains(t, messages[0], "2 of 3 acceptance tests in this package run serially")
+		assert.Contains(t, messages[0], "about 1 instead of 3 test durations, 66% less wall time")
+	})
+
+	t.Run("a documented serialization reason exempts the resource", func(t *testing.T) {
+		src := strings.Replace(parallelTestsTestSrc, "func TestAccWidget_update(",
+			"// Serial: widgets share an account-wide quota.\nfunc TestAccWidget_update(", 1)
+		assert.Empty(t, run(t, src))
+	})
+
+	t.Run("a comment in the test body is a reason", func(t *testing.T) {
+		src := strings.Replace(parallelTestsTestSrc, "func TestAccWidget_basic(t *testing.T) {",
+			"func TestAccWidget_basic(t *testing.T) {\n\t// Runs sequentially: the API rate-limits widget creation.", 1)
+		assert.Empty(t, run(t, src))
+	})
+
+	t.Run("tests setting environment variables must stay serial", func(t *testing.T) {
+		src := strings.Replace(parallelTestsTestSrc, "func TestAccWidget_basic(t *testing.T) {",
+			"func TestAccWidget_basic(t *testing.T) {\n\tt.Setenv(\"EXAMPLE_REGION\", \"eu\")", 1)
+		assert.Empty(t, run(t, src))
+	})
+
+	t.Run("parallel tests are not reported", func(t *testing.T) {
+		src := strings.ReplaceAll(parallelTestsTestSrc, "resource.Test(", "resource.ParallelTest(")
+		assert.Empty(t, run(t, src))
+	})
+}
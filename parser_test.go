@@ -3,9 +3,13 @@
 package tfprovidertest
 
 import (
+	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/example/tfprovidertest/internal/discovery"
@@ -1368,3 +1372,673 @@ func NewEDAEventStreamDataSource() datasource.DataSource {
 		t.Errorf("Expected 'eda_eventstream' data source to be discovered from MetadataEntitySlug, found: %v", foundNames)
 	}
 }
+
+func TestCatalogTestHelpers(t *testing.T) {
+	testSrc := `package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccWidget_basic(t *testing.T) {
+	RunAccTest(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: testAccWidgetConfig("a")}},
+	})
+}
+
+func TestAccWidget_update(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: testAccWidgetConfig("b")}},
+	})
+}
+`
+	helperSrc := `package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func RunAccTest(t *testing.T, tc resource.TestCase) {
+	resource.Test(t, tc)
+}
+
+func testAccWidgetConfig(name string) string {
+	return fmt.Sprintf(` + "`" + `
+data "example_zone" "z" {}
+resource "example_widget" "test" {
+  name = %q
+}
+resource "example_widget" "other" {}
+` + "`" + `, name)
+}
+
+func testAccUnusedConfig() string {
+	return ` + "`" + `resource "example_gadget" "test" {}` + "`" + `
+}
+`
+	fset := token.NewFileSet()
+	testFile, err := parser.ParseFile(fset, "/p/resource_widget_test.go", testSrc, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	helperFile, err := parser.ParseFile(fset, "/p/helpers_test.go", helperSrc, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	helpers := discovery.CatalogTestHelpers([]*ast.File{testFile, helperFile}, fset)
+	byName := make(map[string]discovery.HelperInfo)
+	for _, h := range helpers {
+		byName[h.Name] = h
+	}
+	if len(byName) != 3 {
+		t.Fatalf("expected 3 helpers, got %d: %v", len(byName), helpers)
+	}
+
+	wrapper := byName["RunAccTest"]
+	if wrapper.Kind != discovery.HelperKindWrapper {
+		t.Errorf("expected RunAccTest to be a wrapper, got %s", wrapper.Kind)
+	}
+	if len(wrapper.UsedBy) != 1 || wrapper.UsedBy[0] != "TestAccWidget_basic" {
+		t.Errorf("unexpected RunAccTest usage: %v", wrapper.UsedBy)
+	}
+	if !wrapper.HelperOnlyFile {
+		t.Error("expected helpers_test.go to be classified as helper-only")
+	}
+
+	config := byName["testAccWidgetConfig"]
+	if config.Kind != discovery.HelperKindConfig {
+		t.Errorf("expected testAccWidgetConfig to be a config helper, got %s", config.Kind)
+	}
+	if len(config.UsedBy) != 2 {
+		t.Errorf("expected testAccWidgetConfig used by 2 tests, got %v", config.UsedBy)
+	}
+	expectedBlocks := []discovery.InferredResource{
+		{BlockType: "data", ResourceType: "example_zone"},
+		{BlockType: "resource", ResourceType: "example_widget"},
+	}
+	if len(config.ReferencedBlocks) != len(expectedBlocks) {
+		t.Fatalf("expected blocks %v, got %v", expectedBlocks, config.ReferencedBlocks)
+	}
+	for i, block := range expectedBlocks {
+		if config.ReferencedBlocks[i] != block {
+			t.Errorf("block %d: expected %v, got %v", i, block, config.ReferencedBlocks[i])
+		}
+	}
+
+	if unused := byName["testAccUnusedConfig"]; len(unused.UsedBy) != 0 {
+		t.Errorf("expected testAccUnusedConfig to be unused, got %v", unused.UsedBy)
+	}
+}
+
+func TestCatalogTestHelpers_SameNameInTwoPackages(t *testing.T) {
+	packageSrc := func(pkg, resourceType string) string {
+		return `package ` + pkg + `
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAcc` + resourceType + `_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: testAccConfig()}},
+	})
+}
+
+func testAccConfig() string {
+	return ` + "`" + `resource "example_` + strings.ToLower(resourceType) + `" "test" {}` + "`" + `
+}
+`
+	}
+	fset := token.NewFileSet()
+	widgetFile, err := parser.ParseFile(fset, "/p/widget/resource_widget_test.go", packageSrc("widget", "Widget"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gadgetFile, err := parser.ParseFile(fset, "/p/gadget/resource_gadget_test.go", packageSrc("gadget", "Gadget"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	helpers := discovery.CatalogTestHelpers([]*ast.File{widgetFile, gadgetFile}, fset)
+	if len(helpers) != 2 {
+		t.Fatalf("expected a testAccConfig helper per package, got %v", helpers)
+	}
+	for _, h := range helpers {
+		pkg := filepath.Base(filepath.Dir(h.FilePath))
+		wantCaller := map[string]string{"widget": "TestAccWidget_basic", "gadget": "TestAccGadget_basic"}[pkg]
+		if len(h.UsedBy) != 1 || h.UsedBy[0] != wantCaller {
+			t.Errorf("%s: expected testAccConfig used by %s only, got %v", pkg, wantCaller, h.UsedBy)
+		}
+		wantType := "example_" + pkg
+		if len(h.ReferencedBlocks) != 1 || h.ReferencedBlocks[0].ResourceType != wantType {
+			t.Errorf("%s: expected testAccConfig to reference %s, got %v", pkg, wantType, h.ReferencedBlocks)
+		}
+	}
+}
+
+func TestParseTestFileWithConfig_TemplateResolution(t *testing.T) {
+	hasBlock := func(blocks []registry.InferredHCLBlock, blockType, resourceType string) bool {
+		for _, b := range blocks {
+			if b.BlockType == blockType && b.ResourceType == resourceType {
+				return true
+			}
+		}
+		return false
+	}
+
+	t.Run("resolves const and var templates in helpers and steps", func(t *testing.T) {
+		src := `package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+const widgetTemplate = ` + "`" + `resource "example_widget" "test" { name = %q }` + "`" + `
+
+var zoneTemplate = "data \"example_zone\" \"z\" {}\n" + widgetTemplate
+
+func testAccWidgetConfig(name string) string {
+	return fmt.Sprintf(zoneTemplate, name)
+}
+
+func TestAccWidget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccWidgetConfig("a")},
+			{Config: gadgetTemplate},
+		},
+	})
+}
+`
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "/p/resource_widget_test.go", src, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		config := discovery.DefaultParserConfig()
+		config.PackageTemplates = map[string]map[string]string{
+			"/p": {"gadgetTemplate": `resource "example_gadget" "test" {}`},
+		}
+		info := discovery.ParseTestFileWithConfig(file, fset, "/p/resource_widget_test.go", config)
+		if info == nil || len(info.TestFunctions) != 1 {
+			t.Fatalf("expected one test function, got %+v", info)
+		}
+		blocks := info.TestFunctions[0].InferredHCLBlocks
+		for _, want := range [][2]string{{"resource", "example_widget"}, {"data", "example_zone"}, {"resource", "example_gadget"}} {
+			if !hasBlock(blocks, want[0], want[1]) {
+				t.Errorf("expected %s.%s in inferred blocks, got %v", want[0], want[1], blocks)
+			}
+		}
+	})
+
+	t.Run("resolves go:embed fixtures", func(t *testing.T) {
+		dir := t.TempDir()
+		fixture := `resource "example_bucket" "test" {}`
+		if err := os.MkdirAll(filepath.Join(dir, "testdata"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "testdata", "bucket.tf"), []byte(fixture), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		src := `package provider
+
+import (
+	_ "embed"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+//go:embed testdata/bucket.tf
+var bucketConfig string
+
+func TestAccBucket_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: bucketConfig}},
+	})
+}
+`
+		filePath := filepath.Join(dir, "resource_bucket_test.go")
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, filePath, src, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		templates := discovery.CollectStringTemplates(file, filePath)
+		if templates["bucketConfig"] == "" {
+			t.Fatalf("expected embedded template to be read, got %v", templates)
+		}
+
+		info := discovery.ParseTestFileWithConfig(file, fset, filePath, discovery.DefaultParserConfig())
+		if info == nil || len(info.TestFunctions) != 1 {
+			t.Fatalf("expected one test function, got %+v", info)
+		}
+		if !hasBlock(info.TestFunctions[0].InferredHCLBlocks, "resource", "example_bucket") {
+			t.Errorf("expected resource.example_bucket from embedded fixture, got %v", info.TestFunctions[0].InferredHCLBlocks)
+		}
+	})
+
+	t.Run("resolves templates within their package", func(t *testing.T) {
+		testSrc := func(pkg, name string) string {
+			return `package ` + pkg + `
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAcc` + name + `_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: testAccConfig}},
+	})
+}
+`
+		}
+		templateSrc := func(pkg, resourceType string) string {
+			return "package " + pkg + "\n\nconst testAccConfig = `resource \"" + resourceType + "\" \"test\" {}`\n"
+		}
+		sources := [][2]string{
+			{"/p/widget/resource_widget_test.go", testSrc("widget", "Widget")},
+			{"/p/widget/templates_test.go", templateSrc("widget", "example_widget")},
+			{"/p/gadget/resource_gadget_test.go", testSrc("gadget", "Gadget")},
+			{"/p/gadget/templates_test.go", templateSrc("gadget", "example_gadget")},
+		}
+		fset := token.NewFileSet()
+		var files []*ast.File
+		for _, source := range sources {
+			file, err := parser.ParseFile(fset, source[0], source[1], parser.ParseComments)
+			if err != nil {
+				t.Fatal(err)
+			}
+			files = append(files, file)
+		}
+
+		config := discovery.DefaultParserConfig()
+		config.PackageTemplates = discovery.CollectPackageTemplates(files, fset)
+		for i, want := range map[int]string{0: "example_widget", 2: "example_gadget"} {
+			info := discovery.ParseTestFileWithConfig(files[i], fset, sources[i][0], config)
+			if info == nil || len(info.TestFunctions) != 1 {
+				t.Fatalf("expected one test function in %s, got %+v", sources[i][0], info)
+			}
+			if blocks := info.TestFunctions[0].InferredHCLBlocks; len(blocks) != 1 || !hasBlock(blocks, "resource", want) {
+				t.Errorf("%s: expected only resource.%s from its package's template, got %v", sources[i][0], want, blocks)
+			}
+		}
+
+		corpus := discovery.CatalogConfigs(files, fset)
+		if len(corpus.Configs) != 2 {
+			t.Fatalf("expected a config per package, got %+v", corpus.Configs)
+		}
+		for _, c := range corpus.Configs {
+			test := c.Uses[0].Test
+			if want := strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(test, "TestAcc"), "_basic")); !strings.Contains(c.Config, "example_"+want) {
+				t.Errorf("%s: expected its package's config, got %q", test, c.Config)
+			}
+		}
+	})
+}
+
+func TestParseTestFileWithConfig_StepsFromVariable(t *testing.T) {
+	src := `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccWidget_assigned(t *testing.T) {
+	steps := []resource.TestStep{
+		{Config: testAccWidgetConfig("one")},
+		{Config: testAccWidgetConfig("two")},
+	}
+	resource.Test(t, resource.TestCase{Steps: steps})
+}
+
+func TestAccWidget_appended(t *testing.T) {
+	var steps []resource.TestStep
+	steps = append(steps, resource.TestStep{Config: testAccWidgetConfig("one")})
+	importSteps := []resource.TestStep{{ResourceName: "example_widget.test", ImportState: true}}
+	steps = append(steps, importSteps...)
+	resource.Test(t, resource.TestCase{Steps: steps})
+	steps = append(steps, resource.TestStep{Config: testAccWidgetConfig("ignored")})
+}
+
+func TestAccWidget_inlineAppend(t *testing.T) {
+	base := []resource.TestStep{{Config: testAccWidgetConfig("one")}}
+	resource.Test(t, resource.TestCase{
+		Steps: append(base, resource.TestStep{Config: testAccWidgetConfig("two"), ExpectError: regexp.MustCompile("invalid")}),
+	})
+}
+
+func TestAccWidget_wrapper(t *testing.T) {
+	steps := []resource.TestStep{{Config: testAccWidgetConfig("one")}}
+	acctest.ResourceTest(t, steps)
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "resource_widget_test.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("failed to parse source: %v", err)
+	}
+
+	config := discovery.DefaultParserConfig()
+	config.CustomHelpers = []string{"acctest.ResourceTest"}
+	info := discovery.ParseTestFileWithConfig(file, fset, "resource_widget_test.go", config)
+	if info == nil {
+		t.Fatal("ParseTestFileWithConfig returned nil")
+	}
+
+	byName := make(map[string]registry.TestFunctionInfo)
+	for _, fn := range info.TestFunctions {
+		byName[fn.Name] = fn
+	}
+	tests := []struct {
+		name          string
+		steps         int
+		hasImportStep bool
+		hasErrorCase  bool
+	}{
+		{name: "TestAccWidget_assigned", steps: 2},
+		{name: "TestAccWidget_appended", steps: 2, hasImportStep: true},
+		{name: "TestAccWidget_inlineAppend", steps: 2, hasErrorCase: true},
+		{name: "TestAccWidget_wrapper", steps: 1},
+	}
+	for _, tc := range tests {
+		fn, ok := byName[tc.name]
+		if !ok {
+			t.Errorf("%s: not discovered", tc.name)
+			continue
+		}
+		if len(fn.TestSteps) != tc.steps {
+			t.Errorf("%s: got %d steps, want %d", tc.name, len(fn.TestSteps), tc.steps)
+		}
+		if fn.HasImportStep != tc.hasImportStep {
+			t.Errorf("%s: HasImportStep = %v, want %v", tc.name, fn.HasImportStep, tc.hasImportStep)
+		}
+		if fn.HasErrorCase != tc.hasErrorCase {
+			t.Errorf("%s: HasErrorCase = %v, want %v", tc.name, fn.HasErrorCase, tc.hasErrorCase)
+		}
+	}
+	if steps := byName["TestAccWidget_assigned"].TestSteps; len(steps) == 2 && !steps[1].IsUpdateStepFlag {
+		t.Error("TestAccWidget_assigned: expected the second step to be detected as an update")
+	}
+}
+
+func TestParseTestFileWithConfig_StepsFromLoopsAndHelpers(t *testing.T) {
+	src := `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccWidgetSteps(name string) []resource.TestStep {
+	steps := []resource.TestStep{{Config: testAccWidgetConfig(name)}}
+	steps = append(steps, resource.TestStep{ResourceName: "example_widget.test", ImportState: true})
+	return steps
+}
+
+func testAccWidgetBranchingSteps(update bool) []resource.TestStep {
+	if update {
+		return []resource.TestStep{{Config: testAccWidgetConfig("one")}, {Config: testAccWidgetConfig("two")}}
+	}
+	return nil
+}
+
+func TestAccWidget_helper(t *testing.T) {
+	resource.Test(t, resource.TestCase{Steps: testAccWidgetSteps("one")})
+}
+
+func TestAccWidget_table(t *testing.T) {
+	cases := []struct {
+		config string
+	}{
+		{config: testAccWidgetConfig("one")},
+		{config: testAccWidgetConfig("two")},
+		{config: testAccWidgetConfig("three")},
+	}
+	var steps []resource.TestStep
+	for _, tc := range cases {
+		steps = append(steps, resource.TestStep{Config: tc.config})
+	}
+	resource.Test(t, resource.TestCase{Steps: steps})
+}
+
+func TestAccWidget_computedLoop(t *testing.T) {
+	var steps []resource.TestStep
+	for i := 0; i < widgetCount(); i++ {
+		steps = append(steps, resource.TestStep{Config: testAccWidgetConfig("one")})
+	}
+	resource.Test(t, resource.TestCase{Steps: steps})
+}
+
+func TestAccWidget_branchingHelper(t *testing.T) {
+	resource.Test(t, resource.TestCase{Steps: testAccWidgetBranchingSteps(true)})
+}
+
+func TestAccWidget_externalHelper(t *testing.T) {
+	resource.Test(t, resource.TestCase{Steps: acctest.WidgetSteps(t)})
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "resource_widget_test.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("failed to parse source: %v", err)
+	}
+
+	info := discovery.ParseTestFileWithConfig(file, fset, "resource_widget_test.go", discovery.DefaultParserConfig())
+	if info == nil {
+		t.Fatal("ParseTestFileWithConfig returned nil")
+	}
+
+	byName := make(map[string]registry.TestFunctionInfo)
+	for _, fn := range info.TestFunctions {
+		byName[fn.Name] = fn
+	}
+	tests := []struct {
+		name          string
+		steps         int
+		opaque        bool
+		hasImportStep bool
+	}{
+		{name: "TestAccWidget_helper", steps: 2, hasImportStep: true},
+		{name: "TestAccWidget_table", steps: 3},
+		{name: "TestAccWidget_computedLoop", steps: 0, opaque: true},
+		{name: "TestAccWidget_branchingHelper", steps: 0, opaque: true},
+		{name: "TestAccWidget_externalHelper", steps: 0, opaque: true},
+	}
+	for _, tc := range tests {
+		fn, ok := byName[tc.name]
+		if !ok {
+			t.Errorf("%s: not discovered", tc.name)
+			continue
+		}
+		if len(fn.TestSteps) != tc.steps {
+			t.Errorf("%s: got %d steps, want %d", tc.name, len(fn.TestSteps), tc.steps)
+		}
+		if fn.HasOpaqueSteps != tc.opaque {
+			t.Errorf("%s: HasOpaqueSteps = %v, want %v", tc.name, fn.HasOpaqueSteps, tc.opaque)
+		}
+		if fn.HasImportStep != tc.hasImportStep {
+			t.Errorf("%s: HasImportStep = %v, want %v", tc.name, fn.HasImportStep, tc.hasImportStep)
+		}
+	}
+
+	// Each table entry binds its own config, so the unrolled steps are updates
+	if steps := byName["TestAccWidget_table"].TestSteps; len(steps) == 3 && (!steps[1].IsUpdateStepFlag || !steps[2].IsUpdateStepFlag) {
+		t.Error("TestAccWidget_table: expected the unrolled steps after the first to be detected as updates")
+	}
+}
+
+func TestScanCache(t *testing.T) {
+	scans := 0
+	get, size := discovery.NewScanCacheExported(func(config string) []string {
+		scans++
+		return strings.Fields(config)
+	})
+
+	first := get(`resource "example_widget" "a" {}`)
+	again := get(`resource "example_widget" "a" {}`)
+	if scans != 1 || strings.Join(again, " ") != strings.Join(first, " ") {
+		t.Errorf("a repeated config should be a hit: %d scans, got %q then %q", scans, first, again)
+	}
+
+	other := get(`resource "example_widget" "b" {}`)
+	if scans != 2 || size() != 2 {
+		t.Errorf("a config differing in one character should be scanned and cached apart: %d scans, %d cached", scans, size())
+	}
+	if other[2] != `"b"` || first[2] != `"a"` {
+		t.Errorf("configs differing in one character should keep their own results: got %q and %q", first, other)
+	}
+
+	for i := 0; size() < discovery.MaxCachedScansExported; i++ {
+		get(fmt.Sprintf("config %d", i))
+	}
+	if size() != discovery.MaxCachedScansExported {
+		t.Fatalf("cache holds %d scans, want %d", size(), discovery.MaxCachedScansExported)
+	}
+	get("one config too many")
+	if size() != 1 {
+		t.Errorf("a full cache should start over, holding %d scans", size())
+	}
+	scans = 0
+	get(`resource "example_widget" "a" {}`)
+	if scans != 1 {
+		t.Errorf("a config dropped when the cache started over should be scanned again")
+	}
+
+	t.Run("config scanners", func(t *testing.T) {
+		discovery.ClearScanCaches()
+		if n := discovery.ScanCacheSize(); n != 0 {
+			t.Fatalf("ClearScanCaches left %d scans", n)
+		}
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "/provider/widget_test.go", largeTestFileSrc(10), parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		discovery.ParseTestFileWithConfig(file, fset, "/provider/widget_test.go", discovery.DefaultParserConfig())
+		cached := discovery.ScanCacheSize()
+		if cached == 0 {
+			t.Error("parsing the file cached no scans")
+		}
+		discovery.ParseTestFileWithConfig(file, fset, "/provider/widget_test.go", discovery.DefaultParserConfig())
+		if n := discovery.ScanCacheSize(); n != cached {
+			t.Errorf("parsing the file again should only hit the caches: %d scans cached, then %d", cached, n)
+		}
+	})
+}
+
+// largeTestFileSrc returns a test file with n acceptance tests that share config helpers and
+// repeat the same config literals, as generated test suites do.
+func largeTestFileSrc(n int) string {
+	var b strings.Builder
+	b.WriteString(`package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+const widgetBaseConfig = ` + "`" + `
+provider "example" {
+  region = "us-east-1"
+}
+
+resource "example_network" "base" {
+  cidr = "10.0.0.0/16"
+}
+` + "`" + `
+
+func testAccWidgetConfig(name string) string {
+	return widgetBaseConfig + fmt.Sprintf(` + "`" + `
+resource "example_widget" "test" {
+  name       = %q
+  network_id = example_network.base.id
+}
+
+data "example_widget" "test" {
+  name = example_widget.test.name
+}
+` + "`" + `, name)
+}
+`)
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, `
+func TestAccWidget_case%d(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: testAccWidgetConfig("w%d"),
+				Check:  resource.TestCheckResourceAttr("example_widget.test", "name", "w%d"),
+			},
+			{
+				Config: `+"`"+`
+resource "example_widget" "test" {
+  name = "renamed"
+}
+`+"`"+`,
+			},
+		},
+	})
+}
+`, i, i, i)
+	}
+	return b.String()
+}
+
+func BenchmarkParseTestFileWithConfig(b *testing.B) {
+	for _, n := range []int{10, 300} {
+		b.Run(fmt.Sprintf("%d_tests", n), func(b *testing.B) {
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, "/provider/widget_test.go", largeTestFileSrc(n), parser.ParseComments)
+			if err != nil {
+				b.Fatal(err)
+			}
+			// Cold runs scan every config, as the first parse of a run does; warm runs find
+			// them in the scan caches
+			for _, cold := range []bool{true, false} {
+				name := "warm"
+				if cold {
+					name = "cold"
+				}
+				b.Run(name, func(b *testing.B) {
+					discovery.ClearScanCaches()
+					b.ReportAllocs()
+					b.ResetTimer()
+					for i := 0; i < b.N; i++ {
+						if cold {
+							b.StopTimer()
+							discovery.ClearScanCaches()
+							b.StartTimer()
+						}
+						info := discovery.ParseTestFileWithConfig(file, fset, "/provider/widget_test.go", discovery.DefaultParserConfig())
+						if len(info.TestFunctions) != n {
+							b.Fatalf("parsed %d tests, want %d", len(info.TestFunctions), n)
+						}
+					}
+				})
+			}
+		})
+	}
+}
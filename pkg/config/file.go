@@ -0,0 +1,57 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File is the name of the settings file validate reads from the provider directory, and
+// that validate init writes.
+const File = "tfprovidertest.yaml"
+
+// LoadFile reads the settings file at path. See ParseFile.
+func LoadFile(path, profile string) (Settings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Settings{}, err
+	}
+	return ParseFile(data, path, profile)
+}
+
+// ParseFile parses a settings file, which sets the settings of the settings reference by
+// name (e.g. enable-update-test: false). The settings start from profile, or else from the
+// profile the file names, so settings the file leaves out keep the profile's values. path is
+// used in error messages. The settings are not validated, as the caller may still override
+// some of them.
+func ParseFile(data []byte, path, profile string) (Settings, error) {
+	var head struct {
+		Profile    string `yaml:"profile"`
+		ConfigFile string `yaml:"config-file"`
+	}
+	if err := yaml.Unmarshal(data, &head); err != nil {
+		return Settings{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if head.ConfigFile != "" {
+		return Settings{}, fmt.Errorf("%s: config-file cannot be set in a settings file", path)
+	}
+	if profile == "" {
+		profile = head.Profile
+	}
+	s, err := ProfileSettings(profile)
+	if err != nil {
+		return Settings{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&s); err != nil && !errors.Is(err, io.EOF) {
+		return Settings{}, fmt.Errorf("%s: %w", path, err)
+	}
+	s.Profile = profile
+	return s, nil
+}
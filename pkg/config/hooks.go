@@ -0,0 +1,81 @@
+package config
+
+import "sync"
+
+// Hooks lets programs that embed the engine (dashboards, bots) observe a scan as it runs
+// instead of waiting for the final report. Any hook may be nil.
+//
+// Packages may be scanned concurrently, so hooks can be called from several goroutines.
+// Calls are serialized: a Hooks value delivers one event at a time, so the functions need
+// no locking of their own. Scanning waits for each hook to return; hand slow work off to
+// another goroutine.
+type Hooks struct {
+	// OnResourceDiscovered is called for each resource, data source and action as it is
+	// registered.
+	OnResourceDiscovered func(ResourceEvent)
+	// OnTestLinked is called each time a test function is linked to a definition.
+	OnTestLinked func(LinkEvent)
+	// OnDiagnostic is called for each diagnostic a rule reports, after deduplication.
+	OnDiagnostic func(DiagnosticEvent)
+
+	mu sync.Mutex
+}
+
+// ResourceEvent describes a discovered definition.
+type ResourceEvent struct {
+	Name         string // e.g., "widget"
+	Kind         string // "resource", "data source" or "action"
+	File         string
+	Line         int
+	DiscoveredBy string // Discovery strategy, e.g., "SchemaMethod"
+}
+
+// LinkEvent describes a test function linked to a definition.
+type LinkEvent struct {
+	Test       string // e.g., "TestAccWidget_basic"
+	TestFile   string
+	Resource   string
+	Kind       string // "resource", "data source" or "action"
+	MatchType  string // e.g., "function_name"
+	Confidence float64
+}
+
+// DiagnosticEvent describes a reported diagnostic.
+type DiagnosticEvent struct {
+	Rule    string // e.g., "tfprovider-coverage-basic-test"
+	File    string
+	Line    int
+	Column  int
+	Message string
+	Level   string // "error", or "warning" in files enforce-paths and warn-only-paths leave unenforced
+}
+
+// ResourceDiscovered delivers e to OnResourceDiscovered. It is safe to call on a nil Hooks.
+func (h *Hooks) ResourceDiscovered(e ResourceEvent) {
+	if h == nil || h.OnResourceDiscovered == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.OnResourceDiscovered(e)
+}
+
+// TestLinked delivers e to OnTestLinked. It is safe to call on a nil Hooks.
+func (h *Hooks) TestLinked(e LinkEvent) {
+	if h == nil || h.OnTestLinked == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.OnTestLinked(e)
+}
+
+// Diagnostic delivers e to OnDiagnostic. It is safe to call on a nil Hooks.
+func (h *Hooks) Diagnostic(e DiagnosticEvent) {
+	if h == nil || h.OnDiagnostic == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.OnDiagnostic(e)
+}
@@ -0,0 +1,291 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/example/tfprovidertest/internal/rules"
+)
+
+// Option configures the Settings built by NewSettings. An option returns an error for an
+// argument it cannot apply, such as an unknown analyzer name.
+type Option func(*Settings) error
+
+// NewSettings returns DefaultSettings with opts applied in order, then validated. It is meant
+// for Go programs that embed the engine (see tfprovidertest.NewWithSettings), which would
+// otherwise build the map of settings golangci-lint decodes:
+//
+//	settings, err := config.NewSettings(
+//		config.WithProfile(config.ProfileRecommended),
+//		config.WithProviderPrefix("example"),
+//		config.WithThreshold(0.8),
+//	)
+func NewSettings(opts ...Option) (Settings, error) {
+	s := DefaultSettings()
+	for _, opt := range opts {
+		if err := opt(&s); err != nil {
+			return s, err
+		}
+	}
+	if err := s.Validate(); err != nil {
+		return s, err
+	}
+	return s, nil
+}
+
+// WithProfile starts from the settings of the named profile (see ProfileSettings). It
+// replaces everything set before it, so pass it first.
+func WithProfile(name string) Option {
+	return func(s *Settings) error {
+		profile, err := ProfileSettings(name)
+		if err != nil {
+			return err
+		}
+		*s = profile
+		return nil
+	}
+}
+
+// WithProviderPrefix sets the provider prefix used in function name matching, e.g. "AWS".
+func WithProviderPrefix(prefix string) Option {
+	return func(s *Settings) error {
+		s.ProviderPrefix = prefix
+		return nil
+	}
+}
+
+// ruleToggles maps each rule to the setting that enables it. The drift-check and sweepers
+// rules have none: they run whenever another rule does.
+var ruleToggles = map[string]func(*Settings) *bool{
+	rules.BasicTest:         func(s *Settings) *bool { return &s.EnableBasicTest },
+	rules.UpdateTest:        func(s *Settings) *bool { return &s.EnableUpdateTest },
+	rules.ImportTest:        func(s *Settings) *bool { return &s.EnableImportTest },
+	rules.ErrorTest:         func(s *Settings) *bool { return &s.EnableErrorTest },
+	rules.ProviderConfig:    func(s *Settings) *bool { return &s.EnableProviderConfigTest },
+	rules.Requirements:      func(s *Settings) *bool { return &s.EnableRequirementsCheck },
+	rules.DeferredActions:   func(s *Settings) *bool { return &s.EnableDeferredActionsTest },
+	rules.ProviderAliases:   func(s *Settings) *bool { return &s.EnableProviderAliasTest },
+	rules.UpgradeTest:       func(s *Settings) *bool { return &s.EnableUpgradeTest },
+	rules.PairedDefinitions: func(s *Settings) *bool { return &s.EnablePairedDefinitionTest },
+	rules.CheckFunctions:    func(s *Settings) *bool { return &s.EnableStateCheck },
+	rules.ImportStateIdFunc: func(s *Settings) *bool { return &s.EnableImportStateIdCheck },
+	rules.ImportStateVerify: func(s *Settings) *bool { return &s.EnableImportStateVerifyCheck },
+	rules.ParallelFixtures:  func(s *Settings) *bool { return &s.EnableParallelFixtureCheck },
+	rules.ParallelTests:     func(s *Settings) *bool { return &s.EnableParallelTestCheck },
+	rules.TestPlacement:     func(s *Settings) *bool { return &s.EnableTestPlacementCheck },
+	rules.TestLayout:        func(s *Settings) *bool { return &s.EnableTestLayoutCheck },
+	rules.OrphanTests:       func(s *Settings) *bool { return &s.EnableOrphanTestCheck },
+	rules.DefaultValues:     func(s *Settings) *bool { return &s.EnableDefaultValueCheck },
+	rules.Placeholders:      func(s *Settings) *bool { return &s.EnablePlaceholderValueCheck },
+	rules.ProviderHygiene:   func(s *Settings) *bool { return &s.EnableProviderHygieneCheck },
+	rules.UnknownTypes:      func(s *Settings) *bool { return &s.EnableUnknownTypeCheck },
+	rules.TestHelpers:       func(s *Settings) *bool { return &s.EnableTestHelperCheck },
+	rules.DataSourceConfig:  func(s *Settings) *bool { return &s.EnableDataSourceConfigCheck },
+	rules.DataSourceAsserts: func(s *Settings) *bool { return &s.EnableDataSourceAssertCheck },
+	rules.PluralDataSources: func(s *Settings) *bool { return &s.EnablePluralDataSourceCheck },
+	rules.LegacyChecks:      func(s *Settings) *bool { return &s.EnableLegacyCheckMigration },
+	rules.ProviderFactories: func(s *Settings) *bool { return &s.EnableProviderFactoriesCheck },
+	rules.ErrorCheck:        func(s *Settings) *bool { return &s.EnableErrorCheckPresence },
+	rules.ExpectError:       func(s *Settings) *bool { return &s.EnableExpectErrorCheck },
+	rules.DeadTests:         func(s *Settings) *bool { return &s.EnableDeadTestCheck },
+	rules.CheckAddresses:    func(s *Settings) *bool { return &s.EnableCheckAddressCheck },
+	rules.RefreshDrift:      func(s *Settings) *bool { return &s.EnableRefreshDriftCheck },
+	rules.Idempotency:       func(s *Settings) *bool { return &s.EnableIdempotencyCheck },
+	rules.EnvPreCheck:       func(s *Settings) *bool { return &s.EnableEnvPreCheckCheck },
+	rules.DeprecatedAttrs:   func(s *Settings) *bool { return &s.EnableDeprecatedAttributeCheck },
+}
+
+// ruleGroup returns the setting that enables or disables every rule of a group at once.
+func (s *Settings) ruleGroup(group rules.Group) *bool {
+	if group == rules.GroupCoverage {
+		return s.EnableCoverageRules
+	}
+	return s.EnableQualityRules
+}
+
+// anyRuleEnabled reports whether any rule with a toggle of its own should run, applying
+// the rule group settings.
+func (s *Settings) anyRuleEnabled() bool {
+	for _, rule := range rules.All() {
+		if toggle, ok := ruleToggles[rule.Name]; ok && groupOverride(s.ruleGroup(rule.Group), *toggle(s)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleToggleKeys returns the keys of the settings that enable rules, in catalogue order.
+func ruleToggleKeys() []string {
+	var keys []string
+	for _, rule := range rules.All() {
+		if key, _, ok := RuleToggle(rule.Name); ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// RuleToggle returns the key of the setting that enables the named rule, e.g.
+// enable-basic-test, and whether DefaultSettings enables it. ok is false for the drift-check
+// and sweepers rules, which have no toggle of their own, and for unknown rules.
+func RuleToggle(name string) (key string, enabled bool, ok bool) {
+	return RuleToggleIn(DefaultSettings(), name)
+}
+
+// RuleToggleIn is RuleToggle for the settings s, such as those of a profile, instead of
+// DefaultSettings. The rule groups of s are not applied.
+func RuleToggleIn(s Settings, name string) (key string, enabled bool, ok bool) {
+	toggle, ok := ruleToggles[rules.Canonical(name)]
+	if !ok {
+		return "", false, false
+	}
+	field := toggle(&s)
+	v := reflect.ValueOf(&s).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).Addr().Interface() == any(field) {
+			return settingKey(v.Type().Field(i)), *field, true
+		}
+	}
+	return "", false, false
+}
+
+// SettingDefaults returns the values of DefaultSettings by their key in configuration
+// files, e.g. "min-tests-per-resource". Fields that cannot be configured, such as Hooks, are
+// left out.
+func SettingDefaults() map[string]any {
+	s := DefaultSettings()
+	v := reflect.ValueOf(s)
+	defaults := make(map[string]any, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		if key := settingKey(v.Type().Field(i)); key != "" && key != "-" {
+			defaults[key] = v.Field(i).Interface()
+		}
+	}
+	return defaults
+}
+
+// settingKey returns the key of a Settings field in configuration files, from its yaml tag.
+func settingKey(field reflect.StructField) string {
+	key, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	return key
+}
+
+// WithAnalyzers enables exactly the named rules and disables the others, clearing the rule
+// group settings. Legacy rule names are accepted. The drift-check and sweepers rules cannot
+// be selected alone, since they run whenever another rule does.
+func WithAnalyzers(names ...string) Option {
+	return func(s *Settings) error {
+		var enabled []func(*Settings) *bool
+		for _, name := range names {
+			rule, ok := rules.Lookup(name)
+			if !ok {
+				var known []string
+				for _, r := range rules.All() {
+					known = append(known, r.Name)
+				}
+				return fmt.Errorf("unknown analyzer %q (available: %s)", name, strings.Join(known, ", "))
+			}
+			if toggle, ok := ruleToggles[rule.Name]; ok {
+				enabled = append(enabled, toggle)
+			}
+		}
+		if len(enabled) == 0 {
+			return fmt.Errorf("analyzers must name at least one rule besides %s and %s, which run alongside the others", rules.DriftCheck, rules.Sweepers)
+		}
+
+		s.EnableCoverageRules = nil
+		s.EnableQualityRules = nil
+		for _, toggle := range ruleToggles {
+			*toggle(s) = false
+		}
+		for _, toggle := range enabled {
+			*toggle(s) = true
+		}
+		return nil
+	}
+}
+
+// WithThreshold sets the minimum similarity (0.0-1.0) of fuzzy test-to-resource matches.
+func WithThreshold(threshold float64) Option {
+	return func(s *Settings) error {
+		if threshold < 0.0 || threshold > 1.0 {
+			return fmt.Errorf("threshold must be between 0.0 and 1.0, got %f", threshold)
+		}
+		s.FuzzyMatchThreshold = threshold
+		return nil
+	}
+}
+
+// WithFuzzyMatching enables fuzzy test-to-resource matching with the given threshold.
+func WithFuzzyMatching(threshold float64) Option {
+	return func(s *Settings) error {
+		if err := WithThreshold(threshold)(s); err != nil {
+			return err
+		}
+		s.EnableFuzzyMatching = true
+		return nil
+	}
+}
+
+// WithMinTests sets the minimum number of acceptance tests per definition.
+func WithMinTests(n int) Option {
+	return func(s *Settings) error {
+		if n < 0 {
+			return fmt.Errorf("minimum tests must not be negative, got %d", n)
+		}
+		s.MinTestsPerResource = n
+		return nil
+	}
+}
+
+// WithMinTestsForKind sets the minimum number of acceptance tests per definition of a kind:
+// resource, data-source or action.
+func WithMinTestsForKind(kind string, n int) Option {
+	return func(s *Settings) error {
+		normalized, ok := normalizeKind(kind)
+		if !ok {
+			return fmt.Errorf("unknown kind %q (expected resource, data-source, or action)", kind)
+		}
+		if n < 0 {
+			return fmt.Errorf("minimum tests for %s must not be negative, got %d", normalized, n)
+		}
+		if s.MinTestsPerKind == nil {
+			s.MinTestsPerKind = make(map[string]int)
+		}
+		s.MinTestsPerKind[normalized] = n
+		return nil
+	}
+}
+
+// WithExcludePaths adds path patterns excluded from analysis.
+func WithExcludePaths(patterns ...string) Option {
+	return func(s *Settings) error {
+		s.ExcludePaths = append(s.ExcludePaths, patterns...)
+		return nil
+	}
+}
+
+// WithLanguage selects the language of diagnostic messages, e.g. "ja".
+func WithLanguage(language string) Option {
+	return func(s *Settings) error {
+		s.Language = language
+		return nil
+	}
+}
+
+// WithMessageStyle selects MessageStyleLong or MessageStyleShort diagnostic messages.
+func WithMessageStyle(style string) Option {
+	return func(s *Settings) error {
+		s.MessageStyle = style
+		return nil
+	}
+}
+
+// WithHooks streams discovery, linking and diagnostic events to h.
+func WithHooks(h *Hooks) Option {
+	return func(s *Settings) error {
+		s.Hooks = h
+		return nil
+	}
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Named analyzer profiles, selected with Settings.Profile.
+const (
+	// ProfileMinimal runs only the basic-test and import-test coverage rules.
+	ProfileMinimal = "minimal"
+	// ProfileRecommended runs the rules behind HashiCorp's acceptance testing guidance:
+	// basic, update, import and error coverage, state checks and verified imports.
+	ProfileRecommended = "recommended"
+	// ProfileStrict runs every rule, including the opt-in quality rules.
+	ProfileStrict = "strict"
+)
+
+// Profiles returns the names of the supported profiles.
+func Profiles() []string {
+	return []string{ProfileMinimal, ProfileRecommended, ProfileStrict}
+}
+
+// ProfileSettings returns the settings a profile starts from: DefaultSettings with the
+// profile's rule toggles and thresholds applied. An empty name returns DefaultSettings.
+// Settings configured alongside the profile override these values.
+func ProfileSettings(name string) (Settings, error) {
+	s := DefaultSettings()
+	s.Profile = name
+
+	switch name {
+	case "":
+	case ProfileMinimal:
+		s.EnableUpdateTest = false
+		s.EnableErrorTest = false
+		s.EnableStateCheck = false
+		s.EnableRequirementsCheck = false
+		s.EnableDeferredActionsTest = false
+		s.LinkConfidenceWarningThreshold = 0 // No informational link diagnostics
+	case ProfileRecommended:
+		s.EnableImportStateVerifyCheck = true
+	case ProfileStrict:
+		s.EnableProviderConfigTest = true
+		s.EnableImportStateIdCheck = true
+		s.EnableImportStateVerifyCheck = true
+		s.EnableParallelFixtureCheck = true
+		s.EnableParallelTestCheck = true
+		s.EnableTestPlacementCheck = true
+		s.EnableTestLayoutCheck = true
+		s.EnableOrphanTestCheck = true
+		s.EnableDefaultValueCheck = true
+		s.EnablePlaceholderValueCheck = true
+		s.EnableProviderHygieneCheck = true
+		s.EnableUnknownTypeCheck = true
+		s.EnableTestHelperCheck = true
+		s.EnableDataSourceConfigCheck = true
+		s.EnableDataSourceAssertCheck = true
+		s.EnablePluralDataSourceCheck = true
+		s.EnableLegacyCheckMigration = true
+		s.EnableProviderFactoriesCheck = true
+		s.EnableErrorCheckPresence = true
+		s.EnableExpectErrorCheck = true
+		s.EnableDeadTestCheck = true
+		s.EnableCheckAddressCheck = true
+		s.EnableRefreshDriftCheck = true
+		s.EnableIdempotencyCheck = true
+		s.EnableEnvPreCheckCheck = true
+		s.EnableDeprecatedAttributeCheck = true
+		s.EnableProviderAliasTest = true
+		s.EnableUpgradeTest = true
+		s.EnablePairedDefinitionTest = true
+		s.DeadTestMinLines = 3
+	default:
+		return s, fmt.Errorf("unknown profile %q (supported: %s)", name, strings.Join(Profiles(), ", "))
+	}
+	return s, nil
+}
@@ -2,9 +2,22 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"path"
 	"regexp"
+	"slices"
+	"strings"
 	"time"
+
+	"github.com/example/tfprovidertest/internal/enforcement"
+	"github.com/example/tfprovidertest/internal/fileroles"
+	"github.com/example/tfprovidertest/internal/links"
+	"github.com/example/tfprovidertest/internal/messages"
+	"github.com/example/tfprovidertest/internal/naming"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/internal/scan"
 )
 
 // Settings configures which analyzers are enabled and file path patterns to match.
@@ -16,12 +29,251 @@ type Settings struct {
 	EnableImportTest bool `yaml:"enable-import-test"`
 	EnableErrorTest  bool `yaml:"enable-error-test"`
 	EnableStateCheck bool `yaml:"enable-state-check"`
+	// EnableProviderConfigTest requires acceptance tests that exercise the provider's own
+	// configuration (e.g., a provider block setting a custom endpoint, or auth via environment).
+	// Disabled by default.
+	EnableProviderConfigTest bool `yaml:"enable-provider-config-test"`
+	// RequiredProviderAttributes lists provider schema attributes that at least one acceptance
+	// test must set, either in a provider block or via its environment variable.
+	// When empty, any exercised provider attribute satisfies the provider-config rule.
+	RequiredProviderAttributes []string `yaml:"required-provider-attributes"`
+	// EnableImportStateIdCheck verifies that ImportStateIdFunc functions in import steps only read
+	// state attributes that exist in the resource schema. Disabled by default.
+	EnableImportStateIdCheck bool `yaml:"enable-import-state-id-check"`
+	// EnableImportStateVerifyCheck reports import steps (ImportState: true) that do not set
+	// ImportStateVerify: true or list any ImportStateVerifyIgnore attributes. Disabled by default.
+	EnableImportStateVerifyCheck bool `yaml:"enable-import-state-verify-check"`
+	// EnableParallelFixtureCheck reports parallel tests whose configs hard-code the same value for a
+	// name-like attribute (e.g., the same S3 bucket name), which collide when run together.
+	// Disabled by default.
+	EnableParallelFixtureCheck bool `yaml:"enable-parallel-fixture-check"`
+	// EnableParallelTestCheck recommends resource.ParallelTest for acceptance tests that use
+	// resource.Test without a comment explaining why they run serially, when no test of the
+	// same resource sets environment variables or documents a reason. Disabled by default.
+	EnableParallelTestCheck bool `yaml:"enable-parallel-test-check"`
+	// EnableTestPlacementCheck reports tests linked to one definition (by function name or
+	// config) that live in the test file named for another, e.g. TestAccGadget_basic in
+	// resource_widget_test.go. Disabled by default.
+	EnableTestPlacementCheck bool `yaml:"enable-test-placement-check"`
+	// EnableTestLayoutCheck reports tests kept outside the directory of the definition they
+	// test, unless TestLayout is centralized. Disabled by default.
+	EnableTestLayoutCheck bool `yaml:"enable-test-layout-check"`
+	// TestLayout is where the provider keeps its acceptance tests: "co-located" next to the
+	// definitions, "centralized" in packages of their own (e.g., internal/acctest), or "auto"
+	// (default) to detect it from where the linked tests live.
+	TestLayout string `yaml:"test-layout"`
+	// EnableOrphanTestCheck reports each acceptance test linked to no definition at its own
+	// function, with the resource types its configs declare and the closest definition by
+	// name. Disabled by default.
+	EnableOrphanTestCheck bool `yaml:"enable-orphan-test-check"`
+	// EnableDefaultValueCheck reports resource attributes with a Default or DefaultFunc that
+	// every statically resolved test config sets, so the default path is never exercised.
+	// Informational; disabled by default.
+	EnableDefaultValueCheck bool `yaml:"enable-default-value-check"`
+	// EnablePlaceholderValueCheck reports test configs that set a required attribute validated
+	// as an ARN, CIDR block, URL or similar to a placeholder such as "test" or "changeme".
+	// Disabled by default.
+	EnablePlaceholderValueCheck bool `yaml:"enable-placeholder-value-check"`
+	// EnableProviderHygieneCheck reports test packages that register sweepers without a
+	// TestMain dispatching to resource.TestMain, and TestMain functions that never run the
+	// package's acceptance tests. Disabled by default.
+	EnableProviderHygieneCheck bool `yaml:"enable-provider-hygiene-check"`
+	// EnableUnknownTypeCheck reports test configs that declare a resource, data source or
+	// action type with the provider's prefix that the provider does not define, such as a
+	// typo like example_widgit, which otherwise only fails when the test runs. Disabled by
+	// default.
+	EnableUnknownTypeCheck bool `yaml:"enable-unknown-type-check"`
+	// EnableTestHelperCheck reports local test helpers that accept a *testing.T but call
+	// t.Fatal from a goroutine or discard the error of a setup call, which weakens every test
+	// built on them. Disabled by default.
+	EnableTestHelperCheck bool `yaml:"enable-test-helper-check"`
+	// EnableDataSourceConfigCheck reports data source tests whose config creates no managed
+	// resource for the data source to read, so they depend on infrastructure that already
+	// exists. Disabled by default.
+	EnableDataSourceConfigCheck bool `yaml:"enable-data-source-config-check"`
+	// EnableDataSourceAssertCheck reports data sources whose tests assert fewer computed
+	// attributes than DataSourceMinComputedAsserts beyond id, so they do not show the lookup
+	// returns anything useful. Disabled by default.
+	EnableDataSourceAssertCheck bool `yaml:"enable-data-source-assert-check"`
+	// DataSourceMinComputedAsserts is the number of computed attributes other than id the tests
+	// of a data source must assert between them. Defaults to
+	// DefaultDataSourceMinComputedAsserts when zero.
+	DataSourceMinComputedAsserts int `yaml:"data-source-min-computed-asserts"`
+	// EnablePluralDataSourceCheck reports plural data sources, list resources and data
+	// sources named as the plural of another definition, whose tests never assert how many
+	// items they return. Disabled by default.
+	EnablePluralDataSourceCheck bool `yaml:"enable-plural-data-source-check"`
+	// EnableLegacyCheckMigration reports test steps setting the legacy Check field that were
+	// added since the git ref in Since, so new tests use ConfigStateChecks while existing ones
+	// migrate gradually. It has no effect without Since. Disabled by default.
+	EnableLegacyCheckMigration bool `yaml:"enable-legacy-check-migration"`
+	// EnableProviderFactoriesCheck reports tests serving the provider with another factory
+	// field than most tests of their package, such as ProviderFactories next to
+	// ProtoV6ProviderFactories, and TestCases leaving steps without a provider. Disabled by
+	// default.
+	EnableProviderFactoriesCheck bool `yaml:"enable-provider-factories-check"`
+	// EnableErrorCheckPresence reports TestCases setting no ErrorCheck in packages where most
+	// TestCases set one, as providers skipping unsupported-region errors with a helper such
+	// as acctest.ErrorCheck do. Disabled by default.
+	EnableErrorCheckPresence bool `yaml:"enable-error-check-presence"`
+	// EnableExpectErrorCheck reports ExpectError regular expressions that do not compile or
+	// are too broad to tell the expected error from any other (e.g., ".*" or "error").
+	// Disabled by default.
+	EnableExpectErrorCheck bool `yaml:"enable-expect-error-check"`
+	// EnableDeadTestCheck reports commented-out blocks in test files that contain an acceptance
+	// test function or a resource.Test call, which often mean coverage was dropped silently.
+	// Disabled by default.
+	EnableDeadTestCheck bool `yaml:"enable-dead-test-check"`
+	// DeadTestMinLines is the smallest commented-out block, in lines, that the dead-tests rule
+	// reports. Defaults to DefaultDeadTestMinLines when zero.
+	DeadTestMinLines int `yaml:"dead-test-min-lines"`
+	// EnableCheckAddressCheck reports TestCheckResourceAttr-style checks whose resource address
+	// is not declared by the step's config, so the check cannot pass or checks something else.
+	// Disabled by default.
+	EnableCheckAddressCheck bool `yaml:"enable-check-address-check"`
+	// EnableRefreshDriftCheck reports tested resources without a RefreshState step, so the
+	// drift a refresh would detect is never exercised. Disabled by default.
+	EnableRefreshDriftCheck bool `yaml:"enable-refresh-drift-check"`
+	// EnableIdempotencyCheck reports tested resources with Optional+Computed attributes whose
+	// tests never plan the same config a second time and expect an empty plan, so a perpetual
+	// diff goes unnoticed. Disabled by default.
+	EnableIdempotencyCheck bool `yaml:"enable-idempotency-check"`
+	// EnableEnvPreCheckCheck reports environment variables that tests interpolate into configs
+	// with os.Getenv without checking them in PreCheck. Disabled by default.
+	EnableEnvPreCheckCheck bool `yaml:"enable-env-precheck-check"`
+	// EnableDeprecatedAttributeCheck reports tests that set a deprecated attribute when no test
+	// sets the attribute its deprecation message names as the replacement. Disabled by default.
+	EnableDeprecatedAttributeCheck bool `yaml:"enable-deprecated-attribute-check"`
+	// ParallelFixtureAttributes lists the attributes compared by the parallel-fixtures rule, as
+	// names or glob patterns. Defaults to DefaultParallelFixtureAttributes when empty.
+	ParallelFixtureAttributes []string `yaml:"parallel-fixture-attributes"`
+	// ConditionalGuards lists glob patterns of the helpers that skip a test under some
+	// conditions, such as acctest.PreCheckRegion(t, "us-west-2"), matched against the
+	// function name without its package. Tests calling one earn conditional coverage.
+	// Defaults to DefaultConditionalGuards when empty.
+	ConditionalGuards []string `yaml:"conditional-guards"`
+	// EnableRequirementsCheck enforces the parts of the requirements manifest no other rule
+	// covers: disappears tests, strict mode, and conflicts with //tftest: directives. It has
+	// no effect without a manifest or a //tftest:expect disappears directive.
+	EnableRequirementsCheck bool `yaml:"enable-requirements-check"`
+	// RequirementsManifest is the path of the coverage requirements manifest. When empty,
+	// tfprovidertest.requirements.yaml is looked up from each package directory up to the
+	// module root; "off" disables the manifest.
+	RequirementsManifest string `yaml:"requirements-manifest"`
+	// EnableDeferredActionsTest reports resources and providers that set resp.Deferred (deferred
+	// actions) when no acceptance test allows deferral through AdditionalCLIOptions. It has no
+	// effect on providers that never defer changes.
+	EnableDeferredActionsTest bool `yaml:"enable-deferred-actions-test"`
+	// EnableProviderAliasTest reports resources that work across provider instances, such as
+	// peering resources, when no acceptance test configures an aliased provider block.
+	EnableProviderAliasTest bool `yaml:"enable-provider-alias-test"`
+	// ProviderAliasResources lists the resources (names or glob patterns) that need a test with
+	// an aliased provider. Resources with a peer_* attribute are always included.
+	ProviderAliasResources []string `yaml:"provider-alias-resources"`
+	// EnableUpgradeTest reports resources whose schema version changed since the git ref in
+	// Since when none of their tests is an upgrade test, which applies a config with a
+	// released provider from ExternalProviders and then runs the provider under test on the
+	// state it left. It has no effect without Since. Disabled by default.
+	EnableUpgradeTest bool `yaml:"enable-upgrade-test"`
+	// Since is the git ref the change under review is compared with, such as origin/main.
+	// Empty compares with nothing.
+	Since string `yaml:"since"`
+	// EnablePairedDefinitionTest reports a resource and a data source sharing a name when only
+	// one of them is tested. Disabled by default.
+	EnablePairedDefinitionTest bool `yaml:"enable-paired-definition-test"`
+	// CheckDestroyRequiresDelete limits the drift-check rule to resources whose Delete
+	// destroys something. Resources without a Delete, or whose Delete only removes them from
+	// state (schema.Noop, schema.RemoveFromState, or an empty framework Delete method), are
+	// not required to have CheckDestroy; instead their tests that set it are reported, as the
+	// check was most likely copied from another resource's test. Disabled by default.
+	CheckDestroyRequiresDelete bool `yaml:"check-destroy-requires-delete"`
+	// FailOnWeakTests makes the check-functions rule also report each linked test that applies
+	// configs without Check, ConfigStateChecks, ConfigPlanChecks or ExpectError, even when
+	// other tests of the definition assert state. Such tests only show that apply does not
+	// fail. Disabled by default.
+	FailOnWeakTests bool `yaml:"fail-on-weak-tests"`
+	// MaturityExemptions maps maturity levels ("experimental", "beta", "ga") to the checks
+	// exempted for definitions tagged with that level by a //tftest:maturity directive or the
+	// requirements manifest. Checks a definition explicitly expects are still enforced.
+	// Defaults to DefaultMaturityExemptions when unset.
+	MaturityExemptions map[string][]string `yaml:"maturity-exemptions"`
+
+	// Test count policy
+	// MinTestsPerResource is the minimum number of acceptance tests each resource, data source,
+	// and action must have. Values below 1 are treated as 1.
+	MinTestsPerResource int `yaml:"min-tests-per-resource"`
+	// MinTestsPerKind overrides MinTestsPerResource for a kind. Keys are "resource",
+	// "data-source", or "action". Example: {"resource": 2, "data-source": 1}
+	MinTestsPerKind map[string]int `yaml:"min-tests-per-kind"`
+
+	// Profile names the bundle of rule toggles and thresholds the other settings start from:
+	// "minimal", "recommended" or "strict" (see ProfileSettings). Settings configured
+	// alongside it override the profile. When empty, DefaultSettings apply.
+	Profile string `yaml:"profile"`
+	// ConfigFile is the path of a settings file, such as the tfprovidertest.yaml validate init
+	// writes, whose settings the other settings configured alongside it override. It lets the
+	// golangci-lint configuration and validate share one file. Relative paths are resolved
+	// from the working directory.
+	ConfigFile string `yaml:"config-file"`
+
+	// Rule groups
+	// EnableCoverageRules, when set, enables (true) or disables (false) every coverage rule
+	// (tfprovider-coverage-*) regardless of the individual toggles above. Leave unset to use
+	// the individual toggles.
+	EnableCoverageRules *bool `yaml:"enable-coverage-rules"`
+	// EnableQualityRules, when set, enables (true) or disables (false) every quality rule
+	// (tfprovider-quality-*). When unset, enable-state-check controls the check-functions rule
+	// and the drift-check and sweepers rules run whenever any other rule is enabled.
+	EnableQualityRules *bool `yaml:"enable-quality-rules"`
+
+	// Diagnostic deduplication
+	// DedupDiagnostics, when unset or true, reports each diagnostic once per run even when the
+	// same files are analyzed as part of several packages (e.g., a package and its test variant).
+	DedupDiagnostics *bool `yaml:"dedup-diagnostics"`
+	// DedupDir shares deduplication across processes through files in this directory. Use a
+	// fresh directory per run. When empty, deduplication covers a single process.
+	DedupDir string `yaml:"dedup-dir"`
 
 	// Path patterns
 	ResourcePathPattern   string   `yaml:"resource-path-pattern"`
 	DataSourcePathPattern string   `yaml:"data-source-path-pattern"`
 	TestFilePattern       string   `yaml:"test-file-pattern"`
 	ExcludePaths          []string `yaml:"exclude-paths"`
+	// Layout names the directory layout validate's auto-detection looks for resource code in:
+	// "auto" (default) detects a known generated layout (see scan.Layouts), "none" only tries
+	// internal/provider and its fallbacks, and a layout name such as "awscc" forces it.
+	Layout string `yaml:"layout"`
+	// ResourceDirGlobs are globs, relative to the provider root, of the directories holding
+	// resource code (e.g., "internal/aws/**"). They take precedence over Layout.
+	ResourceDirGlobs []string `yaml:"resource-dir-globs"`
+	// MaxFiles caps the Go files analyzed per run (validate) or package (golangci-lint); files
+	// past the cap are skipped and reported. Zero means no cap.
+	MaxFiles int `yaml:"max-files"`
+	// MaxFileSizeKB skips and reports Go files larger than this many kilobytes, such as huge
+	// generated or vendored files that would stall a run. Defaults to DefaultMaxFileSizeKB
+	// when zero; a negative value analyzes files of any size.
+	MaxFileSizeKB int `yaml:"max-file-size-kb"`
+
+	// Enforcement rollout
+	// EnforcePaths, when set, limits errors to files matching these glob patterns (e.g.,
+	// "internal/service/s3/**"); findings in other files are reported as warnings. Patterns
+	// with a slash match any trailing part of the path; others match the base name.
+	EnforcePaths []string `yaml:"enforce-paths"`
+	// WarnOnlyPaths reports findings in files matching these glob patterns as warnings, even
+	// when they also match EnforcePaths.
+	WarnOnlyPaths []string `yaml:"warn-only-paths"`
+	// Resources, when set, limits the analysis to definitions whose names match one of these
+	// glob patterns (e.g., "aws_s3_*"), with or without the provider prefix. Other
+	// definitions, and tests not linked to a matching definition, are left out of every
+	// rule, report and coverage gate.
+	Resources []string `yaml:"resources"`
+	// IgnoreDirConfigs skips the tfprovidertest.dir.yaml files that otherwise override the
+	// level of findings and disable rules for the subtree they are in.
+	IgnoreDirConfigs bool `yaml:"ignore-dir-configs"`
+	// RepoURLTemplate links reports to the code on the web, e.g.
+	// "https://github.com/org/repo/blob/main/{path}#L{line}". {path} is replaced by the file
+	// path relative to the repository root and {line} by the line. Empty adds no links.
+	RepoURLTemplate string `yaml:"repo-url-template"`
 
 	// File exclusions
 	// ExcludeBaseClasses excludes files named base_*.go which are typically abstract base classes
@@ -35,6 +287,11 @@ type Settings struct {
 	// ExcludePatterns defines glob patterns for files to exclude from analysis
 	// Examples: ["*_sweeper.go", "*_test_helpers.go"]
 	ExcludePatterns []string `yaml:"exclude-patterns"`
+	// FileRoles maps glob patterns to file roles (sweeper, migration, base, model, generated,
+	// docs), extending the built-in patterns behind the exclusions above. Patterns without a
+	// slash match base names. See ExcludesFileRole for what each role excludes.
+	// Example: {"internal/models/*.go": "model", "zz_generated_*.go": "generated"}
+	FileRoles map[string]string `yaml:"file-roles"`
 	// IncludeHelperPatterns defines patterns to identify helper functions
 	// Examples: ["*Helper*", "*Wrapper*", "AccTest*"]
 	IncludeHelperPatterns []string `yaml:"include-helper-patterns"`
@@ -49,6 +306,10 @@ type Settings struct {
 	// By default, only resource.Test() is recognized. Add custom wrappers here.
 	// Example: ["testhelper.AccTest", "internal.RunAccTest"]
 	CustomTestHelpers []string `yaml:"custom-test-helpers"`
+	// IgnoreHelperPackages turns off the detection of scanned packages exporting functions
+	// that return resource.TestCase or accept test steps, whose calls otherwise count as
+	// resource.Test without listing them in CustomTestHelpers.
+	IgnoreHelperPackages bool `yaml:"ignore-helper-packages"`
 
 	// Matching strategies
 	// EnableFuzzyMatching enables fuzzy string matching for resource-to-test associations.
@@ -89,6 +350,15 @@ type Settings struct {
 	// Default includes: _basic, _update, _import, _withCondition, etc.
 	// If empty, uses built-in defaults. To disable suffix stripping, set to ["-"].
 	TestFunctionSuffixes []string `yaml:"test-function-suffixes"`
+	// NameReplacements maps CamelCase fragments of test function names to the snake_case
+	// words they stand for in definition names, for names that do not split at their case
+	// changes. Longer fragments are tried first.
+	// Example: {"EventStream": "eventstream"} links TestAccEDAEventStream_basic to eda_eventstream
+	NameReplacements map[string]string `yaml:"name-replacements"`
+	// NameAcronyms lists words kept whole when converting test function names to snake_case,
+	// such as mixed-case acronyms or acronyms next to each other.
+	// Example: ["OAuth", "DNS", "VPC"] turns "OAuthDNSVPCLink" into "oauth_dns_vpc_link"
+	NameAcronyms []string `yaml:"name-acronyms"`
 
 	// Provider configuration
 	// ProviderPrefix specifies the provider prefix for function name matching (e.g., "AWS", "Google")
@@ -97,6 +367,14 @@ type Settings struct {
 	ResourceNamingPattern string `yaml:"resource-naming-pattern"`
 
 	// Output options
+	// Language selects the language of diagnostic messages. Supported: "en" (default), "ja".
+	// Messages without a translation fall back to English.
+	Language string `yaml:"language"`
+	// MessageStyle selects how diagnostic messages are written: "long" (default) spans
+	// several lines with locations and suggestions, for the validate CLI; "short" keeps the
+	// first line, phrases counts as "1 test" or "2 tests", and appends the rule code, e.g.
+	// "[TFPT003]", for golangci-lint's one-line output.
+	MessageStyle string `yaml:"message-style"`
 	// Verbose enables detailed diagnostic output explaining why issues were flagged.
 	// When enabled, diagnostic messages include test files searched, functions found,
 	// why they didn't match, and suggested fixes.
@@ -107,6 +385,14 @@ type Settings struct {
 	ShowUnmatchedTests bool `yaml:"show-unmatched-tests"`
 	// ShowOrphanedResources when true, reports resources without any test coverage
 	ShowOrphanedResources bool `yaml:"show-orphaned-resources"`
+	// LinkConfidenceWarningThreshold emits an informational diagnostic on any test function
+	// linked to a resource with a confidence below this value (0.0-1.0). Set to 0 to disable.
+	LinkConfidenceWarningThreshold float64 `yaml:"link-confidence-warning-threshold"`
+	// RedactConfigs keeps the text of test configs, and the attribute values taken from them,
+	// out of every output: diagnostics, reports, -configs, -explain and repro bundles show a
+	// digest in their place (see RedactConfig). Block types, labels and attribute names are
+	// kept, since they decide how tests link. Disabled by default.
+	RedactConfigs bool `yaml:"redact-configs"`
 
 	// Cache configuration
 	// CacheTTL specifies how long cache entries remain valid before automatic eviction.
@@ -114,6 +400,11 @@ type Settings struct {
 	// Default is 5 minutes. Set to 0 to disable TTL-based eviction.
 	// Format: duration string like "5m", "1h", "30s"
 	CacheTTL string `yaml:"cache-ttl"`
+
+	// Embedding
+	// Hooks streams discovery, linking and diagnostic events to programs that embed the
+	// engine. It cannot be set from golangci-lint configuration; see tfprovidertest.NewWithSettings.
+	Hooks *Hooks `yaml:"-" json:"-"`
 }
 
 // DefaultSettings returns the default configuration with all analyzers enabled.
@@ -126,11 +417,57 @@ func DefaultSettings() Settings {
 		EnableErrorTest:  true,
 		EnableStateCheck: true,
 
+		EnableProviderConfigTest:       false, // Opt-in: not every provider has configuration worth testing
+		RequiredProviderAttributes:     []string{},
+		EnableImportStateIdCheck:       false, // Opt-in
+		EnableImportStateVerifyCheck:   false, // Opt-in
+		EnableParallelFixtureCheck:     false, // Opt-in
+		EnableParallelTestCheck:        false, // Opt-in
+		EnableTestPlacementCheck:       false, // Opt-in
+		EnableTestLayoutCheck:          false, // Opt-in
+		TestLayout:                     TestLayoutAuto,
+		EnableOrphanTestCheck:          false, // Opt-in
+		EnableDefaultValueCheck:        false, // Opt-in
+		EnablePlaceholderValueCheck:    false, // Opt-in
+		EnableProviderHygieneCheck:     false, // Opt-in
+		EnableUnknownTypeCheck:         false, // Opt-in
+		EnableTestHelperCheck:          false, // Opt-in
+		EnableDataSourceConfigCheck:    false, // Opt-in
+		EnableDataSourceAssertCheck:    false, // Opt-in
+		EnablePluralDataSourceCheck:    false, // Opt-in
+		EnableLegacyCheckMigration:     false, // Opt-in
+		EnableProviderFactoriesCheck:   false, // Opt-in
+		EnableErrorCheckPresence:       false, // Opt-in
+		DataSourceMinComputedAsserts:   DefaultDataSourceMinComputedAsserts,
+		EnableExpectErrorCheck:         false, // Opt-in
+		EnableDeadTestCheck:            false, // Opt-in
+		EnableCheckAddressCheck:        false, // Opt-in
+		EnableRefreshDriftCheck:        false, // Opt-in
+		EnableIdempotencyCheck:         false, // Opt-in
+		EnableEnvPreCheckCheck:         false, // Opt-in
+		EnableDeprecatedAttributeCheck: false, // Opt-in
+		DeadTestMinLines:               DefaultDeadTestMinLines,
+		ParallelFixtureAttributes:      DefaultParallelFixtureAttributes(),
+		EnableRequirementsCheck:        true,  // No-op without a requirements manifest
+		EnableDeferredActionsTest:      true,  // No-op unless the provider defers changes
+		EnableProviderAliasTest:        false, // Opt-in
+		ProviderAliasResources:         []string{},
+		EnableUpgradeTest:              false, // Opt-in
+		EnablePairedDefinitionTest:     false, // Opt-in
+		MaturityExemptions:             DefaultMaturityExemptions(),
+
+		// Test count policy
+		MinTestsPerResource: 1,
+		MinTestsPerKind:     map[string]int{},
+
 		// Path patterns
 		ResourcePathPattern:   "resource_*.go",
 		DataSourcePathPattern: "data_source_*.go",
 		TestFilePattern:       "*_test.go",
 		ExcludePaths:          []string{},
+		Layout:                LayoutAuto,
+		MaxFiles:              0, // No cap
+		MaxFileSizeKB:         DefaultMaxFileSizeKB,
 
 		// File exclusions
 		ExcludeBaseClasses:    true, // Exclude base_*.go by default
@@ -190,11 +527,15 @@ func DefaultSettings() Settings {
 		ResourceNamingPattern: "",
 
 		// Output options
+		Language:              "en",
+		MessageStyle:          MessageStyleLong,
 		Verbose:               false, // Verbose mode disabled by default
 		ShowMatchConfidence:   false,
 		ShowUnmatchedTests:    false,
 		ShowOrphanedResources: true, // Show orphaned resources by default
 
+		LinkConfidenceWarningThreshold: 0.85, // Only fuzzy links fall below this by default
+
 		// Cache configuration
 		CacheTTL: "5m", // 5 minutes default TTL
 	}
@@ -206,6 +547,96 @@ func (s *Settings) Validate() error {
 	if s.FuzzyMatchThreshold < 0.0 || s.FuzzyMatchThreshold > 1.0 {
 		return fmt.Errorf("fuzzy-match-threshold must be between 0.0 and 1.0, got %f", s.FuzzyMatchThreshold)
 	}
+	if s.LinkConfidenceWarningThreshold < 0.0 || s.LinkConfidenceWarningThreshold > 1.0 {
+		return fmt.Errorf("link-confidence-warning-threshold must be between 0.0 and 1.0, got %f", s.LinkConfidenceWarningThreshold)
+	}
+
+	if s.DeadTestMinLines < 0 {
+		return fmt.Errorf("dead-test-min-lines must not be negative, got %d", s.DeadTestMinLines)
+	}
+	if s.DataSourceMinComputedAsserts < 0 {
+		return fmt.Errorf("data-source-min-computed-asserts must not be negative, got %d", s.DataSourceMinComputedAsserts)
+	}
+
+	if s.MaxFiles < 0 {
+		return fmt.Errorf("max-files must not be negative, got %d", s.MaxFiles)
+	}
+
+	if s.MinTestsPerResource < 0 {
+		return fmt.Errorf("min-tests-per-resource must not be negative, got %d", s.MinTestsPerResource)
+	}
+	for kind, min := range s.MinTestsPerKind {
+		if _, ok := normalizeKind(kind); !ok {
+			return fmt.Errorf("min-tests-per-kind: unknown kind %q (expected resource, data-source, or action)", kind)
+		}
+		if min < 0 {
+			return fmt.Errorf("min-tests-per-kind: %s must not be negative, got %d", kind, min)
+		}
+	}
+
+	for level, checks := range s.MaturityExemptions {
+		if !slices.Contains(registry.MaturityLevels(), level) {
+			return fmt.Errorf("maturity-exemptions: unknown maturity level %q (expected %s)", level, strings.Join(registry.MaturityLevels(), ", "))
+		}
+		for _, check := range checks {
+			if !slices.Contains(registry.DirectiveChecks(), check) {
+				return fmt.Errorf("maturity-exemptions: %s: unknown check %q (known checks: %s)", level, check, strings.Join(registry.DirectiveChecks(), ", "))
+			}
+		}
+	}
+
+	if _, err := ProfileSettings(s.Profile); err != nil {
+		return err
+	}
+
+	if !messages.Supported(s.Language) {
+		return fmt.Errorf("unsupported language %q (supported: %s)", s.Language, supportedLanguages())
+	}
+
+	switch s.TestLayout {
+	case "", TestLayoutAuto, TestLayoutColocated, TestLayoutCentralized:
+	default:
+		return fmt.Errorf("unknown test-layout %q (supported: %s, %s, %s)", s.TestLayout, TestLayoutAuto, TestLayoutColocated, TestLayoutCentralized)
+	}
+
+	if s.Layout != "" && s.Layout != LayoutAuto && s.Layout != LayoutNone {
+		if _, ok := scan.LookupLayout(s.Layout); !ok {
+			return fmt.Errorf("unknown layout %q (supported: %s, %s, %s)", s.Layout, LayoutAuto, LayoutNone, strings.Join(scan.LayoutNames(), ", "))
+		}
+	}
+	if _, err := scan.CompileDirGlobs(s.ResourceDirGlobs); err != nil {
+		return err
+	}
+	for _, pattern := range s.Resources {
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			return fmt.Errorf("resources: invalid pattern %q (expected a glob such as aws_s3_*)", pattern)
+		}
+	}
+	for _, pattern := range s.ConditionalGuards {
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			return fmt.Errorf("conditional-guards: invalid pattern %q (expected a glob such as *PreCheckRegion*)", pattern)
+		}
+	}
+
+	if s.MessageStyle != "" && s.MessageStyle != MessageStyleLong && s.MessageStyle != MessageStyleShort {
+		return fmt.Errorf("unsupported message-style %q (supported: %s, %s)", s.MessageStyle, MessageStyleLong, MessageStyleShort)
+	}
+
+	if err := links.Validate(s.RepoURLTemplate); err != nil {
+		return err
+	}
+
+	if _, err := s.FileClassifier(); err != nil {
+		return err
+	}
+
+	if _, err := s.NamePipeline(); err != nil {
+		return err
+	}
+
+	if _, err := s.EnforcementPolicy(); err != nil {
+		return err
+	}
 
 	// Validate regex pattern (ResourceNamingPattern is a regex, not a glob)
 	if s.ResourceNamingPattern != "" {
@@ -219,9 +650,8 @@ func (s *Settings) Validate() error {
 	// so we don't validate them here. Invalid glob patterns will fail at runtime with clear errors.
 
 	// Validate that at least one analyzer is enabled
-	if !s.EnableBasicTest && !s.EnableUpdateTest && !s.EnableImportTest &&
-		!s.EnableErrorTest && !s.EnableStateCheck {
-		return fmt.Errorf("at least one analyzer must be enabled (enable-basic-test, enable-update-test, enable-import-test, enable-error-test, or enable-state-check)")
+	if !s.anyRuleEnabled() {
+		return fmt.Errorf("at least one analyzer must be enabled (%s, enable-coverage-rules, or enable-quality-rules)", strings.Join(ruleToggleKeys(), ", "))
 	}
 
 	// Cross-field validation: if fuzzy matching is enabled, threshold must be reasonable
@@ -239,6 +669,86 @@ func (s *Settings) Validate() error {
 	return nil
 }
 
+// FileClassifier returns the classifier for the built-in file role patterns plus FileRoles.
+func (s *Settings) FileClassifier() (*fileroles.Classifier, error) {
+	return fileroles.NewClassifier(s.FileRoles)
+}
+
+// NamePipeline returns the pipeline that canonicalizes test function names into definition
+// names, with NameReplacements and NameAcronyms.
+func (s *Settings) NamePipeline() (*naming.Pipeline, error) {
+	return naming.New(s.NameReplacements, s.NameAcronyms)
+}
+
+// EnforcementPolicy returns the policy that decides from EnforcePaths and WarnOnlyPaths
+// whether findings in a file are errors or warnings.
+func (s *Settings) EnforcementPolicy() (*enforcement.Policy, error) {
+	return enforcement.New(s.EnforcePaths, s.WarnOnlyPaths)
+}
+
+// ExcludesFileRole reports whether files with the given role are skipped, either when
+// discovering definitions or, when testFile is true, when discovering tests. Sweepers are
+// test infrastructure and base, migration and model files declare no definitions of their
+// own; docs files are skipped entirely and generated files are always analyzed.
+func (s *Settings) ExcludesFileRole(role fileroles.Role, testFile bool) bool {
+	switch role {
+	case fileroles.Sweeper:
+		return s.ExcludeSweeperFiles
+	case fileroles.Base:
+		return !testFile && s.ExcludeBaseClasses
+	case fileroles.Migration:
+		return !testFile && s.ExcludeMigrationFiles
+	case fileroles.Model:
+		return !testFile
+	case fileroles.Docs:
+		return true
+	default:
+		return false
+	}
+}
+
+// MinTestsForKind returns the minimum number of acceptance tests required for a definition
+// of the given kind ("resource", "data source", or "action"), applying any per-kind override.
+// The result is always at least 1.
+func (s *Settings) MinTestsForKind(kind string) int {
+	min := s.MinTestsPerResource
+	if normalized, ok := normalizeKind(kind); ok {
+		for key, override := range s.MinTestsPerKind {
+			if k, ok := normalizeKind(key); ok && k == normalized {
+				min = override
+				break
+			}
+		}
+	}
+	if min < 1 {
+		return 1
+	}
+	return min
+}
+
+// normalizeKind maps the accepted spellings of a definition kind to a canonical form.
+func normalizeKind(kind string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(kind)) {
+	case "resource", "resources":
+		return "resource", true
+	case "data source", "data-source", "data_source", "datasource", "data sources", "data-sources", "data_sources", "datasources":
+		return "data-source", true
+	case "action", "actions":
+		return "action", true
+	default:
+		return "", false
+	}
+}
+
+// supportedLanguages lists the message catalog languages for error messages.
+func supportedLanguages() string {
+	var langs []string
+	for _, lang := range messages.Languages() {
+		langs = append(langs, string(lang))
+	}
+	return strings.Join(langs, ", ")
+}
+
 // GetCacheTTLDuration returns the parsed cache TTL duration.
 // Returns 5 minutes if CacheTTL is empty or invalid.
 // Returns 0 if TTL-based eviction should be disabled.
@@ -255,3 +765,292 @@ func (s *Settings) GetCacheTTLDuration() time.Duration {
 	}
 	return duration
 }
+
+// groupOverride applies a rule group setting to an individual rule toggle.
+func groupOverride(group *bool, enabled bool) bool {
+	if group != nil {
+		return *group
+	}
+	return enabled
+}
+
+// BasicTestEnabled reports whether the coverage-basic-test rule should run.
+func (s *Settings) BasicTestEnabled() bool {
+	return groupOverride(s.EnableCoverageRules, s.EnableBasicTest)
+}
+
+// UpdateTestEnabled reports whether the coverage-update-test rule should run.
+func (s *Settings) UpdateTestEnabled() bool {
+	return groupOverride(s.EnableCoverageRules, s.EnableUpdateTest)
+}
+
+// ImportTestEnabled reports whether the coverage-import-test rule should run.
+func (s *Settings) ImportTestEnabled() bool {
+	return groupOverride(s.EnableCoverageRules, s.EnableImportTest)
+}
+
+// ErrorTestEnabled reports whether the coverage-error-test rule should run.
+func (s *Settings) ErrorTestEnabled() bool {
+	return groupOverride(s.EnableCoverageRules, s.EnableErrorTest)
+}
+
+// ProviderConfigTestEnabled reports whether the coverage-provider-config rule should run.
+func (s *Settings) ProviderConfigTestEnabled() bool {
+	return groupOverride(s.EnableCoverageRules, s.EnableProviderConfigTest)
+}
+
+// RequirementsCheckEnabled reports whether the coverage-requirements rule should run.
+func (s *Settings) RequirementsCheckEnabled() bool {
+	return groupOverride(s.EnableCoverageRules, s.EnableRequirementsCheck)
+}
+
+// DeferredActionsTestEnabled reports whether the coverage-deferred-actions rule should run.
+func (s *Settings) DeferredActionsTestEnabled() bool {
+	return groupOverride(s.EnableCoverageRules, s.EnableDeferredActionsTest)
+}
+
+// ProviderAliasTestEnabled reports whether the coverage-provider-aliases rule should run.
+func (s *Settings) ProviderAliasTestEnabled() bool {
+	return groupOverride(s.EnableCoverageRules, s.EnableProviderAliasTest)
+}
+
+// UpgradeTestEnabled reports whether the coverage-upgrade-test rule should run.
+func (s *Settings) UpgradeTestEnabled() bool {
+	return groupOverride(s.EnableCoverageRules, s.EnableUpgradeTest)
+}
+
+// PairedDefinitionTestEnabled reports whether the coverage-paired-definitions rule should run.
+func (s *Settings) PairedDefinitionTestEnabled() bool {
+	return groupOverride(s.EnableCoverageRules, s.EnablePairedDefinitionTest)
+}
+
+// StateCheckEnabled reports whether the quality-check-functions rule should run.
+func (s *Settings) StateCheckEnabled() bool {
+	return groupOverride(s.EnableQualityRules, s.EnableStateCheck)
+}
+
+// ImportStateIdCheckEnabled reports whether the quality-import-state-id-func rule should run.
+func (s *Settings) ImportStateIdCheckEnabled() bool {
+	return groupOverride(s.EnableQualityRules, s.EnableImportStateIdCheck)
+}
+
+// ImportStateVerifyCheckEnabled reports whether the quality-import-state-verify rule should run.
+func (s *Settings) ImportStateVerifyCheckEnabled() bool {
+	return groupOverride(s.EnableQualityRules, s.EnableImportStateVerifyCheck)
+}
+
+// Layout values other than the name of a known layout.
+const (
+	LayoutAuto = "auto"
+	LayoutNone = "none"
+)
+
+// TestLayout values.
+const (
+	TestLayoutAuto        = "auto"
+	TestLayoutColocated   = "co-located"
+	TestLayoutCentralized = "centralized"
+)
+
+// Message styles for MessageStyle.
+const (
+	MessageStyleLong  = "long"
+	MessageStyleShort = "short"
+)
+
+// ShortMessages reports whether diagnostics use the one-line "short" message style.
+func (s *Settings) ShortMessages() bool {
+	return s.MessageStyle == MessageStyleShort
+}
+
+// RedactConfig returns text, a test config or a value taken from one, as outputs may show
+// it: text itself, or with RedactConfigs its digest, "sha256:" and the first 12 hex digits of
+// its SHA-256. Equal texts keep equal digests, so outputs can still be compared.
+func (s *Settings) RedactConfig(text string) string {
+	if !s.RedactConfigs {
+		return text
+	}
+	sum := sha256.Sum256([]byte(text))
+	return "sha256:" + hex.EncodeToString(sum[:6])
+}
+
+// DedupEnabled reports whether diagnostics are deduplicated across packages. It defaults to true.
+func (s *Settings) DedupEnabled() bool {
+	return s.DedupDiagnostics == nil || *s.DedupDiagnostics
+}
+
+// ParallelFixtureCheckEnabled reports whether the quality-parallel-fixtures rule should run.
+func (s *Settings) ParallelFixtureCheckEnabled() bool {
+	return groupOverride(s.EnableQualityRules, s.EnableParallelFixtureCheck)
+}
+
+// ParallelTestCheckEnabled reports whether the quality-parallel-tests rule should run.
+func (s *Settings) ParallelTestCheckEnabled() bool {
+	return groupOverride(s.EnableQualityRules, s.EnableParallelTestCheck)
+}
+
+// TestPlacementCheckEnabled reports whether the quality-test-placement rule should run.
+func (s *Settings) TestPlacementCheckEnabled() bool {
+	return groupOverride(s.EnableQualityRules, s.EnableTestPlacementCheck)
+}
+
+// TestLayoutCheckEnabled reports whether the quality-test-layout rule should run.
+func (s *Settings) TestLayoutCheckEnabled() bool {
+	return groupOverride(s.EnableQualityRules, s.EnableTestLayoutCheck)
+}
+
+// OrphanTestCheckEnabled reports whether the quality-orphan-tests rule should run.
+func (s *Settings) OrphanTestCheckEnabled() bool {
+	return groupOverride(s.EnableQualityRules, s.EnableOrphanTestCheck)
+}
+
+// DefaultValueCheckEnabled reports whether the quality-default-values rule should run.
+func (s *Settings) DefaultValueCheckEnabled() bool {
+	return groupOverride(s.EnableQualityRules, s.EnableDefaultValueCheck)
+}
+
+// PlaceholderValueCheckEnabled reports whether the quality-placeholder-values rule should run.
+func (s *Settings) PlaceholderValueCheckEnabled() bool {
+	return groupOverride(s.EnableQualityRules, s.EnablePlaceholderValueCheck)
+}
+
+// ProviderHygieneCheckEnabled reports whether the quality-provider-hygiene rule should run.
+func (s *Settings) ProviderHygieneCheckEnabled() bool {
+	return groupOverride(s.EnableQualityRules, s.EnableProviderHygieneCheck)
+}
+
+// UnknownTypeCheckEnabled reports whether the quality-unknown-types rule should run.
+func (s *Settings) UnknownTypeCheckEnabled() bool {
+	return groupOverride(s.EnableQualityRules, s.EnableUnknownTypeCheck)
+}
+
+// TestHelperCheckEnabled reports whether the quality-test-helpers rule should run.
+func (s *Settings) TestHelperCheckEnabled() bool {
+	return groupOverride(s.EnableQualityRules, s.EnableTestHelperCheck)
+}
+
+// DataSourceConfigCheckEnabled reports whether the quality-data-source-config rule should run.
+func (s *Settings) DataSourceConfigCheckEnabled() bool {
+	return groupOverride(s.EnableQualityRules, s.EnableDataSourceConfigCheck)
+}
+
+// DataSourceAssertCheckEnabled reports whether the quality-data-source-asserts rule should run.
+func (s *Settings) DataSourceAssertCheckEnabled() bool {
+	return groupOverride(s.EnableQualityRules, s.EnableDataSourceAssertCheck)
+}
+
+// PluralDataSourceCheckEnabled reports whether the quality-plural-data-sources rule should
+// run.
+func (s *Settings) PluralDataSourceCheckEnabled() bool {
+	return groupOverride(s.EnableQualityRules, s.EnablePluralDataSourceCheck)
+}
+
+// LegacyCheckMigrationEnabled reports whether the quality-legacy-checks rule should run.
+func (s *Settings) LegacyCheckMigrationEnabled() bool {
+	return groupOverride(s.EnableQualityRules, s.EnableLegacyCheckMigration)
+}
+
+// ProviderFactoriesCheckEnabled reports whether the quality-provider-factories rule should run.
+func (s *Settings) ProviderFactoriesCheckEnabled() bool {
+	return groupOverride(s.EnableQualityRules, s.EnableProviderFactoriesCheck)
+}
+
+// ErrorCheckPresenceEnabled reports whether the quality-error-check rule should run.
+func (s *Settings) ErrorCheckPresenceEnabled() bool {
+	return groupOverride(s.EnableQualityRules, s.EnableErrorCheckPresence)
+}
+
+// DefaultDataSourceMinComputedAsserts is the number of computed attributes besides id the
+// data-source-asserts rule requires when data-source-min-computed-asserts is not set.
+const DefaultDataSourceMinComputedAsserts = 1
+
+// DefaultMaturityExemptions returns the checks exempted per maturity level when
+// maturity-exemptions is not set: experimental definitions need no update or import tests.
+func DefaultMaturityExemptions() map[string][]string {
+	return map[string][]string{
+		registry.MaturityExperimental: {registry.CheckUpdate, registry.CheckImport},
+	}
+}
+
+// MaturityExemptionsFor returns the checks exempted for definitions at a maturity level.
+func (s *Settings) MaturityExemptionsFor(level string) []string {
+	if s.MaturityExemptions == nil {
+		return DefaultMaturityExemptions()[level]
+	}
+	return s.MaturityExemptions[level]
+}
+
+// DefaultParallelFixtureAttributes returns the name-like attributes compared across parallel tests.
+func DefaultParallelFixtureAttributes() []string {
+	return []string{"name", "*_name", "bucket", "identifier", "domain", "email"}
+}
+
+// DefaultConditionalGuards returns the name patterns of the helpers that skip tests under
+// some regions, partitions, versions or API capabilities.
+func DefaultConditionalGuards() []string {
+	return []string{"*SkipIf*", "*SkipUnless*", "*PreCheckRegion*", "*PreCheckPartition*", "*PreCheck*Version*", "*PreCheck*Available*"}
+}
+
+// ExpectErrorCheckEnabled reports whether the quality-expect-error-pattern rule should run.
+func (s *Settings) ExpectErrorCheckEnabled() bool {
+	return groupOverride(s.EnableQualityRules, s.EnableExpectErrorCheck)
+}
+
+// DefaultMaxFileSizeKB is the largest Go file analyzed when max-file-size-kb is not set.
+const DefaultMaxFileSizeKB = 1024
+
+// FileLimits returns the file count and per-file size limits to apply, where zero means
+// no limit.
+func (s *Settings) FileLimits() (maxFiles, maxSizeKB int) {
+	maxSizeKB = s.MaxFileSizeKB
+	switch {
+	case maxSizeKB == 0:
+		maxSizeKB = DefaultMaxFileSizeKB
+	case maxSizeKB < 0:
+		maxSizeKB = 0
+	}
+	return s.MaxFiles, maxSizeKB
+}
+
+// DefaultDeadTestMinLines is the smallest commented-out block the dead-tests rule reports
+// when dead-test-min-lines is not set.
+const DefaultDeadTestMinLines = 5
+
+// DeadTestCheckEnabled reports whether the quality-dead-tests rule should run.
+func (s *Settings) DeadTestCheckEnabled() bool {
+	return groupOverride(s.EnableQualityRules, s.EnableDeadTestCheck)
+}
+
+// CheckAddressCheckEnabled reports whether the quality-check-addresses rule should run.
+func (s *Settings) CheckAddressCheckEnabled() bool {
+	return groupOverride(s.EnableQualityRules, s.EnableCheckAddressCheck)
+}
+
+// RefreshDriftCheckEnabled reports whether the quality-refresh-drift rule should run.
+func (s *Settings) RefreshDriftCheckEnabled() bool {
+	return groupOverride(s.EnableQualityRules, s.EnableRefreshDriftCheck)
+}
+
+// IdempotencyCheckEnabled reports whether the quality-idempotency rule should run.
+func (s *Settings) IdempotencyCheckEnabled() bool {
+	return groupOverride(s.EnableQualityRules, s.EnableIdempotencyCheck)
+}
+
+// EnvPreCheckCheckEnabled reports whether the quality-env-precheck rule should run.
+func (s *Settings) EnvPreCheckCheckEnabled() bool {
+	return groupOverride(s.EnableQualityRules, s.EnableEnvPreCheckCheck)
+}
+
+// DeprecatedAttributeCheckEnabled reports whether the quality-deprecated-attributes rule should run.
+func (s *Settings) DeprecatedAttributeCheckEnabled() bool {
+	return groupOverride(s.EnableQualityRules, s.EnableDeprecatedAttributeCheck)
+}
+
+// DriftCheckEnabled reports whether the quality-drift-check and quality-sweepers rules should run.
+// Without a quality group override they run whenever any other rule is enabled.
+func (s *Settings) DriftCheckEnabled() bool {
+	if s.EnableQualityRules != nil {
+		return *s.EnableQualityRules
+	}
+	return s.anyRuleEnabled()
+}
@@ -0,0 +1,215 @@
+// Package diagnose exposes how the engine links acceptance tests to the definitions of a
+// provider: the test functions each resource, data source and action is linked to, the
+// tests linked to none, the definitions without tests, and the definitions with tests that
+// assert nothing. It is the API behind the validate -show-matches, -show-unmatched,
+// -show-orphaned and -show-weak views, for tools that need the same data without parsing the
+// CLI's output.
+//
+// The types of this package are its own and stay stable; the engine's registry behind them
+// is internal and may change.
+package diagnose
+
+import (
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"sort"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// Registry holds the definitions and test functions discovered in a provider's sources,
+// with the tests linked to definitions.
+type Registry struct {
+	reg  *registry.ResourceRegistry
+	fset *token.FileSet
+}
+
+// BuildRegistry discovers the definitions and test functions of files, parsed with
+// comments into fset, and links the tests to the definitions as validate does.
+func BuildRegistry(fset *token.FileSet, files []*ast.File, settings config.Settings) *Registry {
+	return &Registry{reg: discovery.BuildProviderRegistry(fset, files, settings, nil), fset: fset}
+}
+
+// Match is a test function linked to a definition.
+type Match struct {
+	Kind         string  `json:"kind"` // "resource", "data_source" or "action"
+	ResourceName string  `json:"resource_name"`
+	TestFunction string  `json:"test_function"`
+	TestFile     string  `json:"test_file"`
+	Confidence   float64 `json:"confidence"` // 0.0-1.0
+	MatchType    string  `json:"match_type"` // How the test was linked, e.g. "function_name"
+}
+
+// UnmatchedTest is an acceptance test linked to no definition.
+type UnmatchedTest struct {
+	TestFunction string `json:"test_function"`
+	TestFile     string `json:"test_file"`
+	Line         int    `json:"line"`
+	// InferredResources lists the resource types the test's configs declare, which match no
+	// definition
+	InferredResources []string `json:"inferred_resources,omitempty"`
+}
+
+// OrphanedResource is a definition without tests.
+type OrphanedResource struct {
+	Kind         string `json:"kind"`
+	ResourceName string `json:"resource_name"`
+	File         string `json:"file"`
+	Line         int    `json:"line,omitempty"`
+	// ExpectedTestFile and ExpectedTestFunction are where a conventional test would be
+	ExpectedTestFile     string `json:"expected_test_file"`
+	ExpectedTestFunction string `json:"expected_test_function"`
+	// QuarantinedTests is the number of linked tests that are quarantined, which earn no
+	// coverage
+	QuarantinedTests int `json:"quarantined_tests,omitempty"`
+}
+
+// WeakResource is a definition with linked tests that apply configs without asserting
+// anything: no step sets Check, ConfigStateChecks or ConfigPlanChecks, or expects an error.
+// Such tests only show that apply does not fail.
+type WeakResource struct {
+	Kind         string     `json:"kind"`
+	ResourceName string     `json:"resource_name"`
+	File         string     `json:"file"`
+	Line         int        `json:"line,omitempty"`
+	Tests        int        `json:"tests"` // Linked tests, weak or not
+	WeakTests    []WeakTest `json:"weak_tests"`
+}
+
+// OnlyWeak reports whether every linked test of the definition is weak.
+func (w WeakResource) OnlyWeak() bool {
+	return len(w.WeakTests) == w.Tests
+}
+
+// WeakTest is a linked test that asserts nothing.
+type WeakTest struct {
+	TestFunction string `json:"test_function"`
+	TestFile     string `json:"test_file"`
+	Line         int    `json:"line"`
+	ConfigSteps  int    `json:"config_steps"` // Steps applying a config
+}
+
+// GetResourceTestMatches returns the test functions linked to each definition, ordered by
+// kind and name, then as the definition's tests are linked.
+func (r *Registry) GetResourceTestMatches() []Match {
+	var matches []Match
+	for _, info := range r.definitions() {
+		for _, test := range r.reg.GetTests(info.Kind, info.Name) {
+			matches = append(matches, Match{
+				Kind:         info.Kind.Key(),
+				ResourceName: info.Name,
+				TestFunction: test.Name,
+				TestFile:     test.FilePath,
+				Confidence:   test.MatchConfidence,
+				MatchType:    test.MatchType.String(),
+			})
+		}
+	}
+	return matches
+}
+
+// GetUnmatchedTests returns the acceptance tests linked to no definition, ordered by file
+// and line. Provider and function tests are left out.
+func (r *Registry) GetUnmatchedTests() []UnmatchedTest {
+	var unmatched []UnmatchedTest
+	for _, test := range r.reg.GetUnmatchedTestFunctions() {
+		unmatched = append(unmatched, UnmatchedTest{
+			TestFunction:      test.Name,
+			TestFile:          test.FilePath,
+			Line:              r.line(test.FunctionPos),
+			InferredResources: test.InferredResources,
+		})
+	}
+	sort.Slice(unmatched, func(i, j int) bool {
+		if unmatched[i].TestFile != unmatched[j].TestFile {
+			return unmatched[i].TestFile < unmatched[j].TestFile
+		}
+		return unmatched[i].Line < unmatched[j].Line
+	})
+	return unmatched
+}
+
+// GetOrphanedResources returns the definitions without tests, ordered by kind and name.
+// Definitions whose only tests are quarantined are included.
+func (r *Registry) GetOrphanedResources() []OrphanedResource {
+	var orphaned []OrphanedResource
+	for _, info := range r.definitions() {
+		if len(r.reg.GetTests(info.Kind, info.Name)) > 0 {
+			continue
+		}
+		orphaned = append(orphaned, OrphanedResource{
+			Kind:                 info.Kind.Key(),
+			ResourceName:         info.Name,
+			File:                 info.FilePath,
+			Line:                 r.line(info.SchemaPos),
+			ExpectedTestFile:     filepath.Base(analysis.BuildExpectedTestPath(info)),
+			ExpectedTestFunction: analysis.BuildExpectedTestFunc(info),
+			QuarantinedTests:     len(r.reg.GetQuarantinedTests(info.Kind, info.Name)),
+		})
+	}
+	return orphaned
+}
+
+// GetWeakResources returns the definitions with at least one weak linked test, ordered by kind
+// and name. Tests with steps discovery could not resolve are not weak, as those steps may
+// assert.
+func (r *Registry) GetWeakResources() []WeakResource {
+	var weak []WeakResource
+	for _, info := range r.definitions() {
+		tests := r.reg.GetTests(info.Kind, info.Name)
+		var weakTests []WeakTest
+		for _, test := range tests {
+			if !test.IsWeak() {
+				continue
+			}
+			configSteps := 0
+			for _, step := range test.TestSteps {
+				if step.HasConfig {
+					configSteps++
+				}
+			}
+			weakTests = append(weakTests, WeakTest{
+				TestFunction: test.Name,
+				TestFile:     test.FilePath,
+				Line:         r.line(test.FunctionPos),
+				ConfigSteps:  configSteps,
+			})
+		}
+		if len(weakTests) == 0 {
+			continue
+		}
+		weak = append(weak, WeakResource{
+			Kind:         info.Kind.Key(),
+			ResourceName: info.Name,
+			File:         info.FilePath,
+			Line:         r.line(info.SchemaPos),
+			Tests:        len(tests),
+			WeakTests:    weakTests,
+		})
+	}
+	return weak
+}
+
+// definitions returns the definitions ordered by kind and name.
+func (r *Registry) definitions() []*registry.ResourceInfo {
+	defs := r.reg.GetSortedDefinitions()
+	sort.SliceStable(defs, func(i, j int) bool {
+		if defs[i].Kind != defs[j].Kind {
+			return defs[i].Kind < defs[j].Kind
+		}
+		return defs[i].Name < defs[j].Name
+	})
+	return defs
+}
+
+// line returns the line of pos, or 0 when it is not known.
+func (r *Registry) line(pos token.Pos) int {
+	if !pos.IsValid() {
+		return 0
+	}
+	return r.fset.Position(pos).Line
+}
@@ -0,0 +1,312 @@
+// Package metrics aggregates exported validate JSON documents over time, so dashboards do
+// not each reimplement the math. It reads coverage reports (validate -report -format json)
+// and analyzer runs (validate -format json), and computes per-kind coverage, per-service
+// coverage and finding counts, and rule frequency, bucketed by day, week or month.
+//
+// Each bucket is represented by the latest snapshot of each type taken in it, so a bucket
+// shows the state at the end of the period however many times CI ran.
+//
+// Merge combines the coverage reports of many providers into an organization report instead.
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Bucket is the width of the time buckets snapshots are grouped into.
+type Bucket string
+
+// Buckets.
+const (
+	Day   Bucket = "day"
+	Week  Bucket = "week" // Weeks start on Monday
+	Month Bucket = "month"
+)
+
+// ParseBucket parses "day", "week" or "month".
+func ParseBucket(s string) (Bucket, error) {
+	switch b := Bucket(s); b {
+	case Day, Week, Month:
+		return b, nil
+	}
+	return "", fmt.Errorf("invalid bucket %q: must be day, week or month", s)
+}
+
+// Start returns the start of the bucket containing t, in UTC.
+func (b Bucket) Start(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch b {
+	case Week:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case Month:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+// Snapshot is one exported document taken at Time. Exactly one of Definitions (a coverage
+// report) and Findings (an analyzer run) is set; the other is nil.
+type Snapshot struct {
+	Time        time.Time
+	Source      string // File the snapshot was loaded from, if any
+	Definitions []Definition
+	Findings    []Finding
+	// Provider is the type name of the provider a coverage report covers, e.g. "aws"; empty
+	// for reports exported before it was recorded
+	Provider string
+	// Risks are the untested definitions with the most complex schemas of a coverage report
+	Risks []Risk
+}
+
+// Definition is a resource, data source or action of a coverage report.
+type Definition struct {
+	Name      string
+	Kind      string // "resource", "data source" or "action"
+	TestCount int
+	URL       string // Link to the definition, when the report was made with repo-url-template
+}
+
+// Risk is an untested definition of a coverage report, with its schema complexity score.
+type Risk struct {
+	Name  string `json:"name"`
+	Kind  string `json:"kind"`
+	Score int    `json:"score"`
+}
+
+// Finding is a diagnostic of an analyzer run.
+type Finding struct {
+	Rule     string `json:"rule"`
+	Group    string `json:"group"`
+	File     string `json:"file"`
+	Resource string `json:"resource"`
+	Kind     string `json:"kind"`
+}
+
+// document holds the fields of both export formats that metrics reads.
+type document struct {
+	Summary struct {
+		Provider string `json:"provider"`
+	} `json:"summary"`
+	Resources   []reportEntry `json:"resources"`
+	DataSources []reportEntry `json:"data_sources"`
+	Actions     []reportEntry `json:"actions"`
+	TopRisks    []riskEntry   `json:"top_risks"`
+	Findings    *[]Finding    `json:"findings"`
+}
+
+type riskEntry struct {
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	Complexity struct {
+		Score int `json:"score"`
+	} `json:"complexity"`
+}
+
+type reportEntry struct {
+	Name      string `json:"name"`
+	TestCount int    `json:"test_count"`
+	URL       string `json:"url"`
+}
+
+// Decode reads an exported document taken at t.
+func Decode(r io.Reader, t time.Time) (Snapshot, error) {
+	var doc document
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return Snapshot{}, err
+	}
+	snap := Snapshot{Time: t}
+	if doc.Findings != nil {
+		snap.Findings = *doc.Findings
+		return snap, nil
+	}
+	snap.Definitions = []Definition{}
+	snap.Provider = doc.Summary.Provider
+	for _, r := range doc.TopRisks {
+		snap.Risks = append(snap.Risks, Risk{Name: r.Name, Kind: r.Kind, Score: r.Complexity.Score})
+	}
+	for _, group := range []struct {
+		kind    string
+		entries []reportEntry
+	}{{"resource", doc.Resources}, {"data source", doc.DataSources}, {"action", doc.Actions}} {
+		for _, e := range group.entries {
+			snap.Definitions = append(snap.Definitions, Definition{Name: e.Name, Kind: group.kind, TestCount: e.TestCount, URL: e.URL})
+		}
+	}
+	return snap, nil
+}
+
+// fileDate matches a date in a file name, such as report-2024-05-01.json or run_20240501.json.
+var fileDate = regexp.MustCompile(`(\d{4})-?(\d{2})-?(\d{2})`)
+
+// Load reads an exported document from a file. It is dated by the first date in its name
+// (YYYY-MM-DD or YYYYMMDD, in UTC), as CI artifacts usually are, or else by its
+// modification time.
+func Load(path string) (Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	defer f.Close()
+
+	var t time.Time
+	if m := fileDate.FindStringSubmatch(filepath.Base(path)); m != nil {
+		t, _ = time.Parse("20060102", m[1]+m[2]+m[3])
+	}
+	if t.IsZero() {
+		info, err := f.Stat()
+		if err != nil {
+			return Snapshot{}, err
+		}
+		t = info.ModTime()
+	}
+	snap, err := Decode(f, t)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("%s: %w", path, err)
+	}
+	snap.Source = path
+	return snap, nil
+}
+
+// ServiceOf returns the service a definition belongs to: the first segment of its name, so
+// "s3_bucket" and "s3_object" belong to "s3".
+func ServiceOf(name string) string {
+	service, _, _ := strings.Cut(name, "_")
+	return service
+}
+
+// CoveragePoint is the coverage of one group of definitions (a kind or a service) in one
+// bucket. Findings is only set for services.
+type CoveragePoint struct {
+	Bucket   time.Time `json:"bucket"`
+	Key      string    `json:"key"`
+	Total    int       `json:"total"`
+	Tested   int       `json:"tested"`
+	Coverage float64   `json:"coverage"` // Tested/Total, 0 when Total is 0
+	Findings int       `json:"findings,omitempty"`
+}
+
+// RulePoint is the number of findings a rule reported in one bucket.
+type RulePoint struct {
+	Bucket   time.Time `json:"bucket"`
+	Rule     string    `json:"rule"`
+	Group    string    `json:"group,omitempty"`
+	Findings int       `json:"findings"`
+}
+
+// Aggregation is every series computed from a set of snapshots.
+type Aggregation struct {
+	Bucket          Bucket          `json:"bucket"`
+	KindCoverage    []CoveragePoint `json:"kind_coverage"`
+	ServiceCoverage []CoveragePoint `json:"service_coverage"`
+	RuleFrequency   []RulePoint     `json:"rule_frequency"`
+}
+
+// Aggregate computes every series, in bucket order and then by key.
+func Aggregate(snaps []Snapshot, bucket Bucket) Aggregation {
+	return Aggregation{
+		Bucket:          bucket,
+		KindCoverage:    KindCoverage(snaps, bucket),
+		ServiceCoverage: ServiceCoverage(snaps, bucket, ServiceOf),
+		RuleFrequency:   RuleFrequency(snaps, bucket),
+	}
+}
+
+// KindCoverage returns the coverage of each kind per bucket.
+func KindCoverage(snaps []Snapshot, bucket Bucket) []CoveragePoint {
+	reports, _ := latest(snaps, bucket)
+	var points []CoveragePoint
+	for _, start := range slices.SortedFunc(maps.Keys(reports), time.Time.Compare) {
+		points = append(points, coverage(start, reports[start].Definitions, func(d Definition) string { return d.Kind }, nil)...)
+	}
+	return points
+}
+
+// ServiceCoverage returns the coverage and finding count of each service per bucket. service
+// maps a definition name to its service; ServiceOf is the usual choice. Findings are counted
+// from the bucket's latest analyzer run, by the resource they are attributed to.
+func ServiceCoverage(snaps []Snapshot, bucket Bucket, service func(name string) string) []CoveragePoint {
+	reports, runs := latest(snaps, bucket)
+	var points []CoveragePoint
+	for _, start := range slices.SortedFunc(maps.Keys(reports), time.Time.Compare) {
+		findings := make(map[string]int)
+		if run, ok := runs[start]; ok {
+			for _, f := range run.Findings {
+				if f.Resource != "" {
+					findings[service(f.Resource)]++
+				}
+			}
+		}
+		points = append(points, coverage(start, reports[start].Definitions, func(d Definition) string { return service(d.Name) }, findings)...)
+	}
+	return points
+}
+
+// RuleFrequency returns the number of findings of each rule per bucket.
+func RuleFrequency(snaps []Snapshot, bucket Bucket) []RulePoint {
+	_, runs := latest(snaps, bucket)
+	var points []RulePoint
+	for _, start := range slices.SortedFunc(maps.Keys(runs), time.Time.Compare) {
+		counts := make(map[string]int)
+		groups := make(map[string]string)
+		for _, f := range runs[start].Findings {
+			counts[f.Rule]++
+			groups[f.Rule] = f.Group
+		}
+		for _, rule := range slices.Sorted(maps.Keys(counts)) {
+			points = append(points, RulePoint{Bucket: start, Rule: rule, Group: groups[rule], Findings: counts[rule]})
+		}
+	}
+	return points
+}
+
+// latest returns the latest coverage report and analyzer run of each bucket.
+func latest(snaps []Snapshot, bucket Bucket) (reports, runs map[time.Time]Snapshot) {
+	reports = make(map[time.Time]Snapshot)
+	runs = make(map[time.Time]Snapshot)
+	for _, snap := range snaps {
+		into := reports
+		if snap.Findings != nil {
+			into = runs
+		}
+		start := bucket.Start(snap.Time)
+		if prev, ok := into[start]; !ok || !snap.Time.Before(prev.Time) {
+			into[start] = snap
+		}
+	}
+	return reports, runs
+}
+
+// coverage groups definitions by key and computes the coverage of each group.
+func coverage(start time.Time, defs []Definition, key func(Definition) string, findings map[string]int) []CoveragePoint {
+	groups := make(map[string]*CoveragePoint)
+	for _, d := range defs {
+		k := key(d)
+		p, ok := groups[k]
+		if !ok {
+			p = &CoveragePoint{Bucket: start, Key: k}
+			groups[k] = p
+		}
+		p.Total++
+		if d.TestCount > 0 {
+			p.Tested++
+		}
+	}
+	points := make([]CoveragePoint, 0, len(groups))
+	for _, k := range slices.Sorted(maps.Keys(groups)) {
+		p := groups[k]
+		p.Coverage = float64(p.Tested) / float64(p.Total)
+		p.Findings = findings[k]
+		points = append(points, *p)
+	}
+	return points
+}
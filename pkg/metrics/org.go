@@ -0,0 +1,165 @@
+package metrics
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ProviderCoverage is the coverage of one provider, or of the whole organization, in an
+// organization report.
+type ProviderCoverage struct {
+	Provider string        `json:"provider"`
+	Source   string        `json:"source,omitempty"` // File the report was loaded from
+	Total    int           `json:"total"`
+	Tested   int           `json:"tested"`
+	Untested int           `json:"untested"`
+	Coverage float64       `json:"coverage"` // Tested/Total, 0 when Total is 0
+	Kinds    []KindSummary `json:"kinds"`
+}
+
+// KindSummary is the coverage of the definitions of one kind.
+type KindSummary struct {
+	Kind     string  `json:"kind"`
+	Total    int     `json:"total"`
+	Tested   int     `json:"tested"`
+	Coverage float64 `json:"coverage"`
+}
+
+// ProviderRisk is an untested definition of a provider, with its schema complexity score.
+type ProviderRisk struct {
+	Provider string `json:"provider"`
+	Risk
+}
+
+// Organization is the coverage of a fleet of providers, merged from their exported
+// coverage reports.
+type Organization struct {
+	Providers []ProviderCoverage `json:"providers"` // By provider name
+	Totals    ProviderCoverage   `json:"totals"`
+	// WorstProviders are the providers with the lowest coverage, most untested definitions
+	// first among equals
+	WorstProviders []ProviderCoverage `json:"worst_providers"`
+	// TopRisks are the untested definitions with the most complex schemas across the fleet,
+	// from the top risks of each report
+	TopRisks []ProviderRisk `json:"top_risks"`
+}
+
+// ProviderName returns the name a coverage report is listed under in an organization report:
+// the provider recorded in it, or else its file name without the extension and any date, so
+// aws-2024-05-01.json is listed as "aws".
+func ProviderName(snap Snapshot) string {
+	if snap.Provider != "" {
+		return snap.Provider
+	}
+	name := strings.TrimSuffix(filepath.Base(snap.Source), filepath.Ext(snap.Source))
+	name = strings.Trim(fileDate.ReplaceAllString(name, ""), "-_.")
+	if name == "" {
+		return filepath.Base(snap.Source)
+	}
+	return name
+}
+
+// LoadNamed loads a coverage report given to org-report as file.json or name=file.json,
+// listing it under the name when one is given. The text before "=" is a name only when it
+// contains no path separator, so runs/id=3/aws.json is a file.
+func LoadNamed(entry string) (Snapshot, error) {
+	name, path, named := strings.Cut(entry, "=")
+	if !named || strings.ContainsAny(name, "/"+string(filepath.Separator)) {
+		return Load(entry)
+	}
+	snap, err := Load(path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	snap.Provider = name
+	return snap, nil
+}
+
+// Merge merges the coverage reports of a fleet of providers, keyed by provider name, into an
+// organization report listing the worst top providers and top risks. A provider with
+// several reports is represented by its latest. It fails when a snapshot is an analyzer run.
+func Merge(reports map[string]Snapshot, top int) (Organization, error) {
+	org := Organization{Providers: []ProviderCoverage{}, WorstProviders: []ProviderCoverage{}, TopRisks: []ProviderRisk{}}
+	var all []Definition
+	for _, name := range slices.Sorted(maps.Keys(reports)) {
+		snap := reports[name]
+		if snap.Findings != nil {
+			return Organization{}, fmt.Errorf("%s: an analyzer run, not a coverage report (-report -format json)", snap.Source)
+		}
+		org.Providers = append(org.Providers, providerCoverage(name, snap.Source, snap.Definitions))
+		all = append(all, snap.Definitions...)
+		for _, risk := range snap.Risks {
+			org.TopRisks = append(org.TopRisks, ProviderRisk{Provider: name, Risk: risk})
+		}
+	}
+	org.Totals = providerCoverage("", "", all)
+
+	worst := append([]ProviderCoverage(nil), org.Providers...)
+	sort.SliceStable(worst, func(i, j int) bool {
+		if worst[i].Coverage != worst[j].Coverage {
+			return worst[i].Coverage < worst[j].Coverage
+		}
+		return worst[i].Untested > worst[j].Untested
+	})
+	for _, p := range worst {
+		if p.Untested > 0 && len(org.WorstProviders) < top {
+			org.WorstProviders = append(org.WorstProviders, p)
+		}
+	}
+
+	sort.SliceStable(org.TopRisks, func(i, j int) bool { return org.TopRisks[i].Score > org.TopRisks[j].Score })
+	if len(org.TopRisks) > top {
+		org.TopRisks = org.TopRisks[:top]
+	}
+	return org, nil
+}
+
+// Latest keys coverage reports by ProviderName, keeping the latest report of each provider.
+func Latest(snaps []Snapshot) map[string]Snapshot {
+	reports := make(map[string]Snapshot)
+	for _, snap := range snaps {
+		name := ProviderName(snap)
+		if prev, ok := reports[name]; !ok || !snap.Time.Before(prev.Time) {
+			reports[name] = snap
+		}
+	}
+	return reports
+}
+
+// providerCoverage computes the coverage of a provider's definitions, overall and per kind.
+func providerCoverage(name, source string, defs []Definition) ProviderCoverage {
+	p := ProviderCoverage{Provider: name, Source: source, Kinds: []KindSummary{}}
+	kinds := make(map[string]*KindSummary)
+	for _, d := range defs {
+		k, ok := kinds[d.Kind]
+		if !ok {
+			k = &KindSummary{Kind: d.Kind}
+			kinds[d.Kind] = k
+		}
+		k.Total++
+		p.Total++
+		if d.TestCount > 0 {
+			k.Tested++
+			p.Tested++
+		}
+	}
+	p.Untested = p.Total - p.Tested
+	p.Coverage = ratio(p.Tested, p.Total)
+	for _, kind := range slices.Sorted(maps.Keys(kinds)) {
+		k := kinds[kind]
+		k.Coverage = ratio(k.Tested, k.Total)
+		p.Kinds = append(p.Kinds, *k)
+	}
+	return p
+}
+
+func ratio(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
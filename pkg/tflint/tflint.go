@@ -0,0 +1,272 @@
+// Package tflint exposes the engine's findings through the shape of a tflint ruleset, so
+// organizations that standardize on tflint can surface provider test coverage findings
+// through the same tool as their configuration linting.
+//
+// A Ruleset mirrors the methods tflint-plugin-sdk expects of a ruleset (RuleSetName,
+// RuleSetVersion, RuleNames) and a Rule those of a rule (Name, Enabled, Severity, Link,
+// Check), without importing the SDK: the package is pure Go, builds with CGO_ENABLED=0 and
+// adds no dependencies to programs that embed it. A tflint plugin wraps each Rule in a type
+// embedding tflint.DefaultRule and implements Runner over the SDK's runner, converting
+// Range to hcl.Range (see the README).
+//
+// The provider sources are scanned once per Ruleset, the first time a rule is checked:
+// every rule reads its findings from that scan and the registry it built.
+package tflint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"sync"
+
+	analysislib "golang.org/x/tools/go/analysis"
+
+	tfprovidertest "github.com/example/tfprovidertest"
+	"github.com/example/tfprovidertest/internal/enforcement"
+	"github.com/example/tfprovidertest/internal/rules"
+	"github.com/example/tfprovidertest/internal/scan"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// Name is the ruleset name reported to tflint.
+const Name = "tfprovidertest"
+
+// DefaultDocsURL is the base URL rule links point at, followed by "<rule>.md".
+const DefaultDocsURL = "https://github.com/example/tfprovidertest/blob/main/docs/rules/"
+
+// Severity is the severity of a rule or issue, named as tflint names them.
+type Severity string
+
+// Severities.
+const (
+	Error   Severity = "ERROR"
+	Warning Severity = "WARNING"
+	Notice  Severity = "NOTICE"
+)
+
+// Pos is a position in a source file. Line and Column are 1-based, Byte is a 0-based offset.
+type Pos struct {
+	Line   int
+	Column int
+	Byte   int
+}
+
+// Range is the source range of an issue, the same shape as hcl.Range.
+type Range struct {
+	Filename string
+	Start    Pos
+	End      Pos
+}
+
+// Issue is a finding of the engine.
+type Issue struct {
+	Rule    string
+	Code    string // Stable rule code, e.g. "TFPT001"
+	Message string
+	// Severity is Warning for findings under warn-only-paths, otherwise Error
+	Severity Severity
+	Range    Range
+}
+
+// Runner receives the issues of a rule when it is checked. A tflint plugin implements it
+// over tflint.Runner.EmitIssue.
+type Runner interface {
+	EmitIssue(rule *Rule, message string, issueRange Range) error
+}
+
+// Options configures a Ruleset.
+type Options struct {
+	// ProviderDir is the directory of the provider's Go sources
+	ProviderDir string
+	// Recursive scans the subdirectories of ProviderDir too, honoring .gitignore files
+	Recursive bool
+	// Settings selects the rules enabled by default and configures them
+	Settings config.Settings
+	// Version is reported as the ruleset version
+	Version string
+	// DocsURL is the base URL of rule links (default: DefaultDocsURL)
+	DocsURL string
+}
+
+// Ruleset is the engine's rules in the shape of a tflint ruleset.
+type Ruleset struct {
+	opts  Options
+	rules []*Rule
+
+	once   sync.Once
+	issues map[string][]Issue
+	err    error
+}
+
+// NewRuleset returns the ruleset of every rule of the catalogue. Rules the settings do not
+// enable are disabled by default but run when enabled, e.g. by a tflint rule block.
+func NewRuleset(opts Options) (*Ruleset, error) {
+	if opts.ProviderDir == "" {
+		return nil, fmt.Errorf("tflint: ProviderDir is required")
+	}
+	if err := opts.Settings.Validate(); err != nil {
+		return nil, fmt.Errorf("tflint: %w", err)
+	}
+	if opts.DocsURL == "" {
+		opts.DocsURL = DefaultDocsURL
+	}
+
+	enabled := make(map[string]bool)
+	defaults, err := tfprovidertest.NewWithSettings(opts.Settings).BuildAnalyzers()
+	if err != nil {
+		return nil, fmt.Errorf("tflint: %w", err)
+	}
+	for _, analyzer := range defaults {
+		enabled[analyzer.Name] = true
+	}
+
+	set := &Ruleset{opts: opts}
+	for _, rule := range rules.All() {
+		set.rules = append(set.rules, &Rule{set: set, rule: rule, enabled: enabled[rule.Name]})
+	}
+	return set, nil
+}
+
+// RuleSetName returns the ruleset name.
+func (s *Ruleset) RuleSetName() string { return Name }
+
+// RuleSetVersion returns the version given in Options.
+func (s *Ruleset) RuleSetVersion() string { return s.opts.Version }
+
+// RuleNames returns the names of all rules, in catalogue order.
+func (s *Ruleset) RuleNames() []string {
+	names := make([]string, len(s.rules))
+	for i, rule := range s.rules {
+		names[i] = rule.Name()
+	}
+	return names
+}
+
+// Rules returns all rules, in catalogue order.
+func (s *Ruleset) Rules() []*Rule {
+	return s.rules
+}
+
+// Issues returns the findings of every rule, enabled by default or not, keyed by rule name.
+// The provider is scanned on the first call; later calls return the same result.
+func (s *Ruleset) Issues() (map[string][]Issue, error) {
+	s.once.Do(func() {
+		s.issues, s.err = s.scan()
+	})
+	return s.issues, s.err
+}
+
+// scan parses the provider sources and runs every analyzer over them. The analyzers share
+// the registry the first of them builds.
+func (s *Ruleset) scan() (map[string][]Issue, error) {
+	dirs := []string{s.opts.ProviderDir}
+	if s.opts.Recursive {
+		dirs = scan.GoPackageDirs(s.opts.ProviderDir, scan.DefaultOptions()).Dirs()
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, dir := range dirs {
+		pkgs, err := parser.ParseDir(fset, dir, nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("tflint: parsing %s: %w", dir, err)
+		}
+		var dirFiles []*ast.File
+		for _, pkg := range pkgs {
+			for _, file := range pkg.Files {
+				dirFiles = append(dirFiles, file)
+			}
+		}
+		sort.Slice(dirFiles, func(i, j int) bool {
+			return fset.Position(dirFiles[i].Pos()).Filename < fset.Position(dirFiles[j].Pos()).Filename
+		})
+		files = append(files, dirFiles...)
+	}
+
+	// Build every analyzer so that rules disabled by default can be enabled in tflint
+	settings := s.opts.Settings
+	all := true
+	settings.EnableCoverageRules, settings.EnableQualityRules = &all, &all
+	analyzers, err := tfprovidertest.NewWithSettings(settings).BuildAnalyzers()
+	if err != nil {
+		return nil, fmt.Errorf("tflint: %w", err)
+	}
+
+	issues := make(map[string][]Issue)
+	for _, analyzer := range analyzers {
+		pass := &analysislib.Pass{
+			Analyzer: analyzer,
+			Fset:     fset,
+			Files:    files,
+			Report: func(diag analysislib.Diagnostic) {
+				issues[analyzer.Name] = append(issues[analyzer.Name], newIssue(fset, analyzer.Name, diag))
+			},
+		}
+		if _, err := analyzer.Run(pass); err != nil {
+			return nil, fmt.Errorf("tflint: running %s: %w", analyzer.Name, err)
+		}
+	}
+	return issues, nil
+}
+
+// newIssue converts a diagnostic of rule to an issue.
+func newIssue(fset *token.FileSet, rule string, diag analysislib.Diagnostic) Issue {
+	start := fset.Position(diag.Pos)
+	end := start
+	if diag.End.IsValid() {
+		end = fset.Position(diag.End)
+	}
+	issue := Issue{
+		Rule:     rule,
+		Code:     rules.Code(rule),
+		Message:  diag.Message,
+		Severity: Error,
+		Range: Range{
+			Filename: start.Filename,
+			Start:    Pos{Line: start.Line, Column: start.Column, Byte: start.Offset},
+			End:      Pos{Line: end.Line, Column: end.Column, Byte: end.Offset},
+		},
+	}
+	if diag.Category == string(enforcement.LevelWarning) {
+		issue.Severity = Warning
+	}
+	return issue
+}
+
+// Rule is one rule of the catalogue in the shape of a tflint rule.
+type Rule struct {
+	set     *Ruleset
+	rule    rules.Rule
+	enabled bool
+}
+
+// Name returns the rule name, e.g. "tfprovider-coverage-basic-test".
+func (r *Rule) Name() string { return r.rule.Name }
+
+// Code returns the stable rule code, e.g. "TFPT001".
+func (r *Rule) Code() string { return r.rule.Code }
+
+// Enabled reports whether the settings enable the rule by default.
+func (r *Rule) Enabled() bool { return r.enabled }
+
+// Severity returns Error: rules report gaps the settings ask to close. Findings demoted by
+// warn-only-paths carry Warning in their Issue.
+func (r *Rule) Severity() Severity { return Error }
+
+// Link returns the URL of the rule's reference page.
+func (r *Rule) Link() string { return r.set.opts.DocsURL + r.rule.Name + ".md" }
+
+// Check emits the rule's findings to runner, scanning the provider if no rule has yet.
+func (r *Rule) Check(runner Runner) error {
+	issues, err := r.set.Issues()
+	if err != nil {
+		return err
+	}
+	for _, issue := range issues[r.rule.Name] {
+		if err := runner.EmitIssue(r, issue.Message, issue.Range); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package tfprovidertest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const placeholderSubnetResourceSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+
+	"example.com/provider/internal/validators"
+)
+
+type SubnetResource struct{}
+
+func (r *SubnetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required:   true,
+				Validators: []validator.String{stringvalidator.LengthAtLeast(1)},
+			},
+			"cidr_block": schema.StringAttribute{
+				Required:   true,
+				Validators: []validator.String{validators.IsCIDR()},
+			},
+		},
+	}
+}
+`
+
+const placeholderRoleResourceSrc = `package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceRole() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"policy_arn": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: verify.ValidARN,
+			},
+			"endpoint": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IsURLWithHTTPS),
+			},
+		},
+	}
+}
+`
+
+const placeholderSubnetTestSrc = `package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccSubnet_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: ` + "`" + `
+resource "example_subnet" "test" {
+  name       = "test"
+  cidr_block = "changeme"
+}
+` + "`" + `,
+			},
+			{
+				Config: ` + "`" + `
+resource "example_subnet" "test" {
+  name       = "test"
+  cidr_block = "foo"
+}
+` + "`" + `,
+				ExpectError: regexp.MustCompile("invalid CIDR"),
+			},
+			{
+				Config: ` + "`" + `
+resource "example_subnet" "test" {
+  name       = "test"
+  cidr_block = "10.0.0.0/16"
+}
+` + "`" + `,
+			},
+		},
+	})
+}
+`
+
+const placeholderRoleTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccRole_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: ` + "`" + `
+resource "example_role" "test" {
+  policy_arn = "TEST"
+  endpoint   = "foo"
+}
+` + "`" + `,
+			},
+		},
+	})
+}
+`
+
+func TestPlaceholderValues(t *testing.T) {
+	sources := map[string]string{
+		"/provider/resource_subnet.go":      placeholderSubnetResourceSrc,
+		"/provider/resource_subnet_test.go": placeholderSubnetTestSrc,
+		"/provider/resource_role.go":        placeholderRoleResourceSrc,
+		"/provider/resource_role_test.go":   placeholderRoleTestSrc,
+	}
+
+	t.Run("step values and validators", func(t *testing.T) {
+		reg := buildRegistryFromSources(t, sources)
+		tests := reg.GetTests(registry.KindResource, "subnet")
+		require.Len(t, tests, 1)
+		assert.Contains(t, tests[0].TestSteps[0].ConfigValues, registry.FixtureValue{ResourceType: "example_subnet", Attribute: "cidr_block", Value: "changeme"})
+
+		role := reg.GetDefinition(registry.KindResource, "role")
+		require.NotNil(t, role)
+		for _, attr := range role.Attributes {
+			switch attr.Name {
+			case "policy_arn":
+				assert.Contains(t, attr.ValidatorTypes, "ValidARN")
+			case "endpoint":
+				assert.Contains(t, attr.ValidatorTypes, "IsURLWithHTTPS", "wrapped in ToDiagFunc")
+			}
+		}
+
+		assert.True(t, analysis.IsPlaceholderValue("ChangeMe"))
+		assert.False(t, analysis.IsPlaceholderValue("10.0.0.0/16"))
+	})
+
+	t.Run("analyzer", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		settings.EnablePlaceholderValueCheck = true
+		messages := runAnalyzerOnSources(t, analysis.RunPlaceholderValuesAnalyzer, settings, sources)
+
+		// name has no format validator, endpoint is optional and the ExpectError step tests validation
+		require.Len(t, messages, 2, strings.Join(messages, "\n\n"))
+		assert.Contains(t, messages[0], `of test 'TestAccRole_basic' sets required attribute 'policy_arn' of example_role to the placeholder "TEST", but its validators expect an ARN`)
+		assert.Contains(t, messages[1], `of test 'TestAccSubnet_basic' sets required attribute 'cidr_block' of example_subnet to the placeholder "changeme", but its validators expect a CIDR block`)
+	})
+
+	t.Run("settings", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		assert.False(t, settings.PlaceholderValueCheckEnabled(), "the rule is opt-in")
+	})
+}
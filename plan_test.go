@@ -0,0 +1,90 @@
+package tfprovidertest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/plan"
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+func TestPlanParse(t *testing.T) {
+	target, err := plan.ParseTarget("80%")
+	require.NoError(t, err)
+	assert.Equal(t, 0.8, target)
+
+	target, err = plan.ParseTarget("75")
+	require.NoError(t, err)
+	assert.Equal(t, 0.75, target)
+
+	for _, invalid := range []string{"", "0%", "101%", "most"} {
+		_, err := plan.ParseTarget(invalid)
+		assert.Error(t, err, invalid)
+	}
+
+	weights, err := plan.ParseWeights("recent=2, tag=0")
+	require.NoError(t, err)
+	assert.Equal(t, plan.Weights{Recent: 2, Attributes: 1, Tag: 0}, weights)
+
+	_, err = plan.ParseWeights("popularity=1")
+	assert.ErrorContains(t, err, "unknown signal")
+	_, err = plan.ParseWeights("tag=-1")
+	assert.ErrorContains(t, err, "non-negative")
+}
+
+func TestPlanBuild(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	attrs := func(n int) []registry.AttributeInfo { return make([]registry.AttributeInfo, n) }
+
+	reg := registry.NewResourceRegistry()
+	for _, info := range []*registry.ResourceInfo{
+		{Name: "tested", Kind: registry.KindResource, FilePath: "tested.go"},
+		{Name: "big", Kind: registry.KindResource, FilePath: "big.go", Attributes: attrs(10)},
+		{Name: "fresh", Kind: registry.KindResource, FilePath: "fresh.go", Attributes: attrs(2)},
+		{Name: "public", Kind: registry.KindDataSource, FilePath: "public.go", Attributes: attrs(3), Maturity: registry.MaturityGA},
+		{Name: "stale", Kind: registry.KindResource, FilePath: "stale.go", Attributes: attrs(1)},
+	} {
+		reg.RegisterResource(info)
+	}
+	fn := &registry.TestFunctionInfo{Name: "TestAccTested_basic", FilePath: "tested_test.go"}
+	reg.RegisterTestFunction(fn)
+	reg.LinkTest(registry.KindResource, "tested", fn)
+
+	ages := map[string]time.Duration{"fresh.go": 0, "big.go": 900 * day, "public.go": 900 * day, "stale.go": 900 * day}
+	opts := plan.Options{
+		Target:  0.8,
+		Weights: plan.DefaultWeights(),
+		Tag:     registry.MaturityGA,
+		Now:     now,
+		LastModified: func(path string) (time.Time, bool) {
+			age, ok := ages[path]
+			return now.Add(-age), ok
+		},
+	}
+
+	result := plan.Build(reg, opts)
+	assert.Equal(t, 5, result.Total)
+	assert.Equal(t, 1, result.Tested)
+	assert.Equal(t, 3, result.Needed, "4 of 5 definitions reach 80%")
+	require.Len(t, result.Steps, 3)
+	assert.Equal(t, "public", result.Steps[0].Name, "the customer-facing tag outweighs the other signals")
+	assert.Equal(t, "fresh", result.Steps[1].Name)
+	assert.Equal(t, "big", result.Steps[2].Name)
+	assert.Equal(t, 0.8, result.Steps[2].CoverageAfter)
+	assert.Equal(t, "TestAccDataSourcePublic_basic", result.Steps[0].SuggestedTest)
+	assert.Equal(t, "public_test.go", result.Steps[0].SuggestedFile)
+	assert.Equal(t, 0, result.Steps[1].AgeDays)
+
+	opts.Weights = plan.Weights{Attributes: 1}
+	result = plan.Build(reg, opts)
+	assert.Equal(t, "big", result.Steps[0].Name, "only the attribute count counts")
+
+	opts.Target = 0.2
+	result = plan.Build(reg, opts)
+	assert.Zero(t, result.Needed, "the target is already met")
+	assert.Empty(t, result.Steps)
+}
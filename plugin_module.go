@@ -0,0 +1,12 @@
+//go:build !tfprovidertest_legacy
+
+package tfprovidertest
+
+import "github.com/golangci/plugin-module-register/register"
+
+// The module plugin registers itself when imported by a golangci-lint binary built with
+// golangci-lint custom. Building with the tfprovidertest_legacy tag leaves it out, for the
+// .so plugin in cmd/tfprovidertest-plugin.
+func init() {
+	register.Plugin("tfprovidertest", New)
+}
@@ -0,0 +1,118 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/testsrc"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const gadgetsDataSourceSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+)
+
+type GadgetsDataSource struct{}
+
+func (d *GadgetsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name_prefix": schema.StringAttribute{Optional: true},
+			"gadgets": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{Computed: true},
+					},
+				},
+			},
+		},
+	}
+}
+`
+
+const statusDataSourceSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+)
+
+type StatusDataSource struct{}
+
+func (d *StatusDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{}
+}
+`
+
+func TestPluralDataSources(t *testing.T) {
+	reg := buildRegistryFromSources(t, map[string]string{
+		"/provider/resource_gadget.go":     untestedGadgetResourceSrc,
+		"/provider/data_source_gadgets.go": gadgetsDataSourceSrc,
+		"/provider/data_source_status.go":  statusDataSourceSrc,
+	})
+	plurals := analysis.PluralDataSources(reg)
+	require.Len(t, plurals, 1, "status ends in s but is not the plural of a definition")
+	assert.Equal(t, "gadgets", plurals[0].DataSource.Name)
+	require.NotNil(t, plurals[0].Singular)
+	assert.Equal(t, "gadget", plurals[0].Singular.Name, "the resource stands in for a missing singular data source")
+}
+
+func TestPluralDataSourcesAnalyzer(t *testing.T) {
+	settings := config.DefaultSettings()
+	settings.EnablePluralDataSourceCheck = true
+	run := func(t *testing.T, field string) []string {
+		return runAnalyzerOnSources(t, analysis.RunPluralDataSourcesAnalyzer, settings, map[string]string{
+			"/provider/resource_gadget.go":     untestedGadgetResourceSrc,
+			"/provider/data_source_gadgets.go": gadgetsDataSourceSrc,
+			"/provider/data_source_gadgets_test.go": testsrc.File(testsrc.Test{
+				Name: "TestAccGadgetsDataSource_basic",
+				Imports: []string{
+					"github.com/hashicorp/terraform-plugin-testing/knownvalue",
+					"github.com/hashicorp/terraform-plugin-testing/statecheck",
+					"github.com/hashicorp/terraform-plugin-testing/tfjsonpath",
+				},
+				Setup: `address := "data.example_gadgets.all"`,
+				Steps: []string{testsrc.Config(`data "example_gadgets" "all" {}`) + ",\n\t\t\t" + field},
+			}),
+		})
+	}
+
+	t.Run("only single items asserted", func(t *testing.T) {
+		messages := run(t, `Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrSet(address, "gadgets.0.name"),
+			)`)
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "the 1 test(s) of plural data source 'gadgets' never assert how many items it returns")
+		assert.Contains(t, messages[0], "Data source: /provider/data_source_gadgets.go:12")
+		assert.Contains(t, messages[0], "Singular counterpart: resource 'gadget'")
+		assert.Contains(t, messages[0], `"gadgets.#"`)
+	})
+
+	t.Run("count key asserted", func(t *testing.T) {
+		assert.Empty(t, run(t, `Check: resource.TestCheckResourceAttr(address, "gadgets.#", "2")`))
+	})
+
+	t.Run("list size asserted by a state check", func(t *testing.T) {
+		assert.Empty(t, run(t, `ConfigStateChecks: []statecheck.StateCheck{
+				statecheck.ExpectKnownValue(address, tfjsonpath.New("gadgets"), knownvalue.ListSizeExact(2)),
+			}`))
+	})
+
+	t.Run("item asserted by a state check", func(t *testing.T) {
+		messages := run(t, `ConfigStateChecks: []statecheck.StateCheck{
+				statecheck.ExpectKnownValue(address, tfjsonpath.New("gadgets").AtSliceIndex(0), knownvalue.NotNull()),
+			}`)
+		assert.Len(t, messages, 1)
+	})
+}
@@ -0,0 +1,118 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const peeringResourceSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+type PeeringResource struct{}
+
+func (r *PeeringResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"vpc_id":      schema.StringAttribute{Required: true},
+			"peer_vpc_id": schema.StringAttribute{Required: true},
+		},
+	}
+}
+`
+
+const peeringSingleProviderTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccPeering_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: ` + "`" + `
+resource "example_peering" "test" {
+  vpc_id      = "a"
+  peer_vpc_id = "b"
+}
+` + "`" + `}},
+	})
+}
+`
+
+const peeringAliasTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccPeering_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: ` + "`" + `
+provider "example" {
+  alias  = "peer"
+  region = "us-west-2"
+}
+
+resource "example_peering" "test" {
+  vpc_id      = "a"
+  peer_vpc_id = "b"
+}
+` + "`" + `}},
+	})
+}
+`
+
+func TestProviderAliasAnalyzer(t *testing.T) {
+	settings := config.DefaultSettings()
+	settings.EnableProviderAliasTest = true
+
+	t.Run("peer attribute without an aliased provider", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunProviderAliasAnalyzer, settings, map[string]string{
+			"/provider/resource_peering.go":      peeringResourceSrc,
+			"/provider/resource_peering_test.go": peeringSingleProviderTestSrc,
+		})
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "resource 'peering' spans provider instances (attribute peer_vpc_id) but none of its 1 test(s) configures an aliased provider")
+		assert.Contains(t, messages[0], "Resource: /provider/resource_peering.go:12")
+	})
+
+	t.Run("aliased provider block", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunProviderAliasAnalyzer, settings, map[string]string{
+			"/provider/resource_peering.go":      peeringResourceSrc,
+			"/provider/resource_peering_test.go": peeringAliasTestSrc,
+		})
+		assert.Empty(t, messages)
+	})
+
+	t.Run("resource listed in settings", func(t *testing.T) {
+		listed := settings
+		listed.ProviderAliasResources = []string{"gad*"}
+		messages := runAnalyzerOnSources(t, analysis.RunProviderAliasAnalyzer, listed, map[string]string{
+			"/provider/resource_gadget.go":      untestedGadgetResourceSrc,
+			"/provider/resource_gadget_test.go": gadgetBasicTestSrc,
+		})
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "resource 'gadget' spans provider instances (listed in provider-alias-resources)")
+	})
+
+	t.Run("untested resources are left to the basic-test rule", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunProviderAliasAnalyzer, settings, map[string]string{
+			"/provider/resource_peering.go": peeringResourceSrc,
+		})
+		assert.Empty(t, messages)
+	})
+}
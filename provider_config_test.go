@@ -0,0 +1,150 @@
+package tfprovidertest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const providerSrc = `package provider
+
+import (
+	"context"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+)
+
+type ExampleProvider struct{}
+
+func (p *ExampleProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "example"
+}
+
+func (p *ExampleProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"endpoint": schema.StringAttribute{Optional: true},
+			"token":    schema.StringAttribute{Optional: true, Sensitive: true},
+			"insecure": schema.BoolAttribute{Optional: true},
+		},
+	}
+}
+
+func (p *ExampleProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	_ = os.Getenv("EXAMPLE_TOKEN")
+	_, _ = os.LookupEnv("EXAMPLE_INSECURE")
+}
+`
+
+const providerConfigTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccProvider_customEndpoint(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccProviderEndpointConfig("https://example.test")},
+		},
+	})
+}
+
+func TestAccProvider_tokenFromEnv(t *testing.T) {
+	t.Setenv("EXAMPLE_TOKEN", "secret")
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: "provider \"example\" {}"},
+		},
+	})
+}
+
+func testAccProviderEndpointConfig(endpoint string) string {
+	return fmt.Sprintf(` + "`" + `
+provider "example" {
+  endpoint = %q
+  retry {
+    attempts = 3
+  }
+}
+` + "`" + `, endpoint)
+}
+`
+
+const unrelatedTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccWidget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: "resource \"example_widget\" \"test\" {}"},
+		},
+	})
+}
+`
+
+func TestProviderConfigAnalyzer(t *testing.T) {
+	run := analysis.RunProviderConfigAnalyzer
+
+	t.Run("no provider config tests reported", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, run, config.DefaultSettings(), map[string]string{
+			"/provider/provider.go":             providerSrc,
+			"/provider/resource_widget_test.go": unrelatedTestSrc,
+		})
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "provider 'example' has configuration attributes but no acceptance test exercises them")
+		assert.Contains(t, messages[0], "endpoint, token, insecure")
+	})
+
+	t.Run("any exercised attribute satisfies the default rule", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, run, config.DefaultSettings(), map[string]string{
+			"/provider/provider.go":      providerSrc,
+			"/provider/provider_test.go": providerConfigTestSrc,
+		})
+		assert.Empty(t, messages)
+	})
+
+	t.Run("required attributes are checked individually", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		settings.RequiredProviderAttributes = []string{"endpoint", "token", "insecure", "region"}
+
+		messages := runAnalyzerOnSources(t, run, settings, map[string]string{
+			"/provider/provider.go":      providerSrc,
+			"/provider/provider_test.go": providerConfigTestSrc,
+		})
+		require.Len(t, messages, 2, "got %v", messages)
+		joined := strings.Join(messages, "\n")
+		assert.Contains(t, joined, "provider attribute 'insecure' is not exercised by any acceptance test")
+		assert.Contains(t, joined, "sets EXAMPLE_INSECURE with t.Setenv")
+		assert.Contains(t, joined, "required provider attribute 'region' is not defined in the schema of provider 'example'")
+	})
+
+	t.Run("packages without a provider are skipped", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, run, config.DefaultSettings(), map[string]string{
+			"/provider/resource_widget_test.go": unrelatedTestSrc,
+		})
+		assert.Empty(t, messages)
+	})
+}
+
+func TestProviderConfigRuleIsOptIn(t *testing.T) {
+	settings := config.DefaultSettings()
+	assert.False(t, settings.ProviderConfigTestEnabled())
+
+	settings.EnableProviderConfigTest = true
+	assert.True(t, settings.ProviderConfigTestEnabled())
+}
@@ -0,0 +1,86 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+func TestProviderFactoriesAnalyzer(t *testing.T) {
+	settings := config.DefaultSettings()
+	settings.EnableProviderFactoriesCheck = true
+	protoV6 := "ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,"
+
+	t.Run("minority pattern of the package", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunProviderFactoriesAnalyzer, settings, map[string]string{
+			"/provider/widget_test.go":   factoryTestSrc("Widget", protoV6, ""),
+			"/provider/gadget_test.go":   factoryTestSrc("Gadget", protoV6, ""),
+			"/provider/sprocket_test.go": factoryTestSrc("Sprocket", "ProviderFactories: testAccProviderFactories,", ""),
+			// Another package has its own pattern
+			"/other/cog_test.go": factoryTestSrc("Cog", "ProviderFactories: testAccProviderFactories,", ""),
+		})
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "test 'TestAccSprocket_basic' serves the provider with ProviderFactories, but 2 of the 3 tests of package provider that set a factory field use ProtoV6ProviderFactories")
+		assert.Contains(t, messages[0], "Suggestion: Use the package's pattern, ProtoV6ProviderFactories: testAccProtoV6ProviderFactories;")
+	})
+
+	t.Run("ties go to the newest protocol", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunProviderFactoriesAnalyzer, settings, map[string]string{
+			"/provider/widget_test.go": factoryTestSrc("Widget", protoV6, ""),
+			"/provider/gadget_test.go": factoryTestSrc("Gadget", "ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,", ""),
+		})
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "test 'TestAccGadget_basic' serves the provider with ProtoV5ProviderFactories")
+	})
+
+	t.Run("TestCase without provider", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunProviderFactoriesAnalyzer, settings, map[string]string{
+			"/provider/widget_test.go": factoryTestSrc("Widget", protoV6, ""),
+			"/provider/gadget_test.go": factoryTestSrc("Gadget", "", "", ""),
+		})
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "a TestCase of test 'TestAccGadget_basic' gives step(s) 1, 2 no provider")
+		assert.Contains(t, messages[0], "Suggestion: Set ProtoV6ProviderFactories: testAccProtoV6ProviderFactories on the TestCase")
+	})
+
+	t.Run("steps without provider", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunProviderFactoriesAnalyzer, settings, map[string]string{
+			// An upgrade test gives each step its providers
+			"/provider/widget_test.go": factoryTestSrc("Widget", "",
+				`ExternalProviders: map[string]resource.ExternalProvider{"example": {VersionConstraint: "1.0.0"}}`, protoV6),
+			"/provider/gadget_test.go": factoryTestSrc("Gadget", "", protoV6, ""),
+		})
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "a TestCase of test 'TestAccGadget_basic' gives step(s) 2 no provider")
+	})
+
+	t.Run("TestCases a helper may complete are not checked", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunProviderFactoriesAnalyzer, settings, map[string]string{
+			"/provider/widget_test.go": `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccWidget_basic(t *testing.T) {
+	acctest.ParallelTest(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: ` + "`" + `resource "example_widget" "w" {}` + "`" + `}},
+	})
+}
+
+func TestAccWidget_update(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: widgetSteps(),
+	})
+}
+`,
+		})
+		assert.Empty(t, messages)
+	})
+}
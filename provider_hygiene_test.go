@@ -0,0 +1,87 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const hygieneSweeperSrc = `package widget
+
+import (
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func init() {
+	resource.AddTestSweepers("example_widget", &resource.Sweeper{Name: "example_widget"})
+}
+`
+
+const hygieneDispatchSrc = `package widget
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestMain(m *testing.M) {
+	resource.TestMain(m)
+}
+`
+
+const hygieneRunOnlySrc = `package widget
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(m.Run())
+}
+`
+
+const hygieneNoRunSrc = `package gadget
+
+import (
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	setup()
+}
+`
+
+func TestProviderHygieneAnalyzer(t *testing.T) {
+	settings := config.DefaultSettings()
+	settings.EnableProviderHygieneCheck = true
+
+	messages := runAnalyzerOnSources(t, analysis.RunProviderHygieneAnalyzer, settings, map[string]string{
+		// Sweepers with a dispatching TestMain
+		"/provider/ok/widget_sweeper_test.go": hygieneSweeperSrc,
+		"/provider/ok/main_test.go":           hygieneDispatchSrc,
+		// Sweepers without TestMain
+		"/provider/missing/widget_sweeper_test.go": hygieneSweeperSrc,
+		// Sweepers with a TestMain that only runs the tests
+		"/provider/nodispatch/widget_sweeper_test.go": hygieneSweeperSrc,
+		"/provider/nodispatch/main_test.go":           hygieneRunOnlySrc,
+		// Acceptance tests with a TestMain that never runs them
+		"/provider/norun/resource_gadget.go":      untestedGadgetResourceSrc,
+		"/provider/norun/resource_gadget_test.go": gadgetBasicTestSrc,
+		"/provider/norun/main_test.go":            hygieneNoRunSrc,
+	})
+	require.Len(t, messages, 3)
+
+	assert.Contains(t, messages[0], "package /provider/missing registers 1 test sweeper(s) but has no TestMain")
+	assert.Contains(t, messages[0], "Sweeper: /provider/missing/widget_sweeper_test.go:8")
+
+	assert.Contains(t, messages[1], "TestMain of package /provider/nodispatch does not call resource.TestMain")
+	assert.Contains(t, messages[1], "TestMain: /provider/nodispatch/main_test.go:8")
+
+	assert.Contains(t, messages[2], "TestMain of package /provider/norun neither calls m.Run() nor resource.TestMain, so its 1 acceptance test(s) never run")
+}
@@ -0,0 +1,76 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/prsuggest"
+)
+
+func TestPRSuggestions(t *testing.T) {
+	defs := []prsuggest.Definition{
+		{
+			Kind: "resource", Name: "widget", File: "provider/internal/widget/resource_widget.go",
+			Tests: []prsuggest.Test{{Name: "TestAccWidget_basic", File: "provider/internal/widget/resource_widget_test.go"}},
+			Findings: []prsuggest.Finding{
+				{Rule: "tfprovider-coverage-update-test", Code: "TFPT002", File: "provider/internal/widget/resource_widget.go", Line: 30,
+					Message: "[warning] resource 'widget' has no update test\n  Suggestion: Add a step changing an attribute"},
+				{Rule: "tfprovider-coverage-import-test", Code: "TFPT003", File: "provider/internal/widget/resource_widget.go", Line: 12,
+					Message: "resource 'widget' has no import test"},
+			},
+		},
+		{
+			Kind: "resource", Name: "gadget", File: "provider/internal/gadget/resource_gadget.go",
+			ExpectedTest: "TestAccGadget_basic", ExpectedTestFile: "resource_gadget_test.go",
+		},
+		{
+			Kind: "data source", Name: "gadget", File: "provider/internal/gadget/data_source_gadget.go",
+			Tests: []prsuggest.Test{{Name: "TestAccDataSourceGadget_basic", File: "provider/internal/gadget/data_source_gadget_test.go"}},
+		},
+		{
+			Kind: "resource", Name: "sprocket", File: "provider/internal/sprocket/resource_sprocket.go",
+		},
+	}
+
+	t.Run("touched definitions", func(t *testing.T) {
+		s := prsuggest.Build(defs, []string{
+			"provider/internal/widget/resource_widget_test.go", // Touches widget through its test
+			"provider/internal/gadget/resource_gadget.go",
+			"provider/internal/gadget/data_source_gadget.go",
+			"README.md",
+		}, "origin/main", "provider")
+
+		var names []string
+		for _, def := range s.Definitions {
+			names = append(names, def.Kind+" "+def.Name)
+		}
+		assert.Equal(t, []string{"data source gadget", "resource gadget", "resource widget"}, names, "sprocket is not touched")
+
+		assert.Equal(t, []string{prsuggest.LabelMissingTests, prsuggest.LabelIncompleteTests}, s.Labels)
+		assert.Equal(t, prsuggest.TitleMissingTests, s.TitlePrefix)
+		assert.Equal(t, []string{"add an acceptance test, e.g. TestAccGadget_basic in resource_gadget_test.go"}, s.Definitions[1].Missing)
+		assert.Equal(t, []string{
+			"resource 'widget' has no import test (TFPT003)",
+			"resource 'widget' has no update test (TFPT002)",
+		}, s.Definitions[2].Missing, "summary lines in source order, without the warning prefix")
+		assert.Empty(t, s.Definitions[0].Missing)
+
+		assert.Equal(t, "TF_ACC=1 go test ./internal/gadget ./internal/widget -run '^(TestAccDataSourceGadget_basic|TestAccWidget_basic)$' -v", s.TestCommand)
+		assert.Contains(t, s.Body, "touch 3 definition(s): 1 without tests, 1 with incomplete tests")
+		assert.Contains(t, s.Body, "- [x] data source `gadget`: tested by TestAccDataSourceGadget_basic\n")
+		assert.Contains(t, s.Body, "- [ ] resource `widget`: resource 'widget' has no import test (TFPT003)\n")
+		assert.Contains(t, s.Body, s.TestCommand)
+	})
+
+	t.Run("nothing touched", func(t *testing.T) {
+		s := prsuggest.Build(defs, []string{"README.md"}, "origin/main", ".")
+		require.NotNil(t, s.Labels)
+		assert.Empty(t, s.Labels)
+		assert.Empty(t, s.TitlePrefix)
+		assert.Empty(t, s.TestCommand)
+		assert.Empty(t, s.Definitions)
+		assert.Contains(t, s.Body, "touch no resources")
+	})
+}
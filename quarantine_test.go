@@ -0,0 +1,78 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/requirements"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const gadgetQuarantinedTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// TestAccGadget_basic is flaky against the sandbox API.
+//
+//tftest:quarantine reason="sandbox API times out"
+func TestAccGadget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: ` + "`" + `resource "example_gadget" "g" {}` + "`" + `}},
+	})
+}
+`
+
+func TestQuarantinedTests(t *testing.T) {
+	t.Run("directive removes coverage credit", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		settings.RequirementsManifest = requirements.Off
+		messages := runAnalyzerOnSources(t, analysis.RunBasicTestAnalyzer, settings, map[string]string{
+			"/provider/resource_gadget.go":      untestedGadgetResourceSrc,
+			"/provider/resource_gadget_test.go": gadgetQuarantinedTestSrc,
+		})
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "resource 'gadget' has no acceptance test")
+		assert.Contains(t, messages[0], "Quarantined tests: TestAccGadget_basic (coverage at risk")
+
+		reg := buildRegistryFromSources(t, map[string]string{
+			"/provider/resource_gadget.go":      untestedGadgetResourceSrc,
+			"/provider/resource_gadget_test.go": gadgetQuarantinedTestSrc,
+		})
+		fns := reg.GetAllTestFunctions()
+		require.Len(t, fns, 1)
+		assert.True(t, fns[0].Quarantined)
+		assert.Equal(t, "sandbox API times out", fns[0].QuarantineReason)
+	})
+
+	t.Run("manifest quarantine list", func(t *testing.T) {
+		settings := writeManifest(t, "quarantine:\n  TestAccGadget_*: flaky\n")
+		messages := runAnalyzerOnSources(t, analysis.RunBasicTestAnalyzer, settings, map[string]string{
+			"/provider/resource_gadget.go":      untestedGadgetResourceSrc,
+			"/provider/resource_gadget_test.go": gadgetBasicTestSrc,
+		})
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "Quarantined tests: TestAccGadget_basic")
+	})
+
+	t.Run("unquarantined tests keep their credit", func(t *testing.T) {
+		settings := writeManifest(t, "quarantine:\n  TestAccWidget_*: flaky\n")
+		messages := runAnalyzerOnSources(t, analysis.RunBasicTestAnalyzer, settings, map[string]string{
+			"/provider/resource_gadget.go":      untestedGadgetResourceSrc,
+			"/provider/resource_gadget_test.go": gadgetBasicTestSrc,
+		})
+		assert.Empty(t, messages)
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		_, err := requirements.Parse([]byte("quarantine:\n  \"TestAcc[\": flaky\n"), "manifest.yaml")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "quarantine.TestAcc[: invalid pattern")
+	})
+}
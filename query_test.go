@@ -0,0 +1,72 @@
+package tfprovidertest
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/query"
+)
+
+const queryReportJSON = `{
+	"summary": {"total_resources": 3, "untested_resources": 1},
+	"resources": [
+		{"name": "gadget", "test_count": 2, "has_import_test": true},
+		{"name": "sprocket", "test_count": 0, "has_import_test": false},
+		{"name": "widget", "test_count": 1, "has_import_test": false}
+	]
+}`
+
+func TestQueryEval(t *testing.T) {
+	var report any
+	require.NoError(t, json.Unmarshal([]byte(queryReportJSON), &report))
+
+	tests := []struct {
+		expr string
+		want []any
+	}{
+		{".summary.untested_resources", []any{1.0}},
+		{"$.summary.untested_resources", []any{1.0}},
+		{`.summary["total_resources"]`, []any{3.0}},
+		{".resources[-1].name", []any{"widget"}},
+		{".resources[*].name", []any{"gadget", "sprocket", "widget"}},
+		{".resources[] | select(.test_count == 0) | .name", []any{"sprocket"}},
+		{"[.resources[] | select(.has_import_test | not)] | length", []any{2.0}},
+		{"[.resources[] | select(.test_count >= 1 and .has_import_test == false) | .name]", []any{[]any{"widget"}}},
+		{".resources | first | .name", []any{"gadget"}},
+		{".summary | keys", []any{[]any{"total_resources", "untested_resources"}}},
+		{".missing.field", []any{nil}},
+		{".resources[9]", []any{nil}},
+		{"[.resources[] | select(.test_count > 5)]", []any{[]any{}}},
+	}
+	for _, tt := range tests {
+		q, err := query.Parse(tt.expr)
+		require.NoError(t, err, tt.expr)
+		got, err := q.Eval(report)
+		require.NoError(t, err, tt.expr)
+		assert.Equal(t, tt.want, got, tt.expr)
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	for _, expr := range []string{".summary[", "length(", ".a |", "frobnicate", `"unterminated`} {
+		_, err := query.Parse(expr)
+		assert.Error(t, err, expr)
+	}
+
+	var report any
+	require.NoError(t, json.Unmarshal([]byte(queryReportJSON), &report))
+	q, err := query.Parse(".summary[0]")
+	require.NoError(t, err)
+	_, err = q.Eval(report)
+	assert.ErrorContains(t, err, "cannot index object with 0")
+}
+
+func TestQueryFormat(t *testing.T) {
+	assert.Equal(t, "sprocket", query.Format("sprocket"))
+	assert.Equal(t, "3", query.Format(3.0))
+	assert.Equal(t, "null", query.Format(nil))
+	assert.Equal(t, "[\n  \"a\"\n]", query.Format([]any{"a"}))
+}
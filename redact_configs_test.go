@@ -0,0 +1,88 @@
+package tfprovidertest
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	goanalysis "golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/internal/repro"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+func TestRedactConfigs(t *testing.T) {
+	t.Run("settings", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		assert.Equal(t, "changeme", settings.RedactConfig("changeme"), "off by default")
+
+		settings.RedactConfigs = true
+		digest := settings.RedactConfig("changeme")
+		assert.Regexp(t, `^sha256:[0-9a-f]{12}$`, digest)
+		assert.Equal(t, digest, settings.RedactConfig("changeme"))
+		assert.NotEqual(t, digest, settings.RedactConfig("change-me"))
+	})
+
+	t.Run("diagnostics show digests of config values", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		settings.EnablePlaceholderValueCheck = true
+		settings.RedactConfigs = true
+		messages := runAnalyzerOnSources(t, analysis.RunPlaceholderValuesAnalyzer, settings, map[string]string{
+			"/provider/resource_subnet.go":      placeholderSubnetResourceSrc,
+			"/provider/resource_subnet_test.go": placeholderSubnetTestSrc,
+		})
+
+		require.Len(t, messages, 1, strings.Join(messages, "\n\n"))
+		assert.Contains(t, messages[0], `sets required attribute 'cidr_block' of example_subnet to the placeholder "`+settings.RedactConfig("changeme")+`"`)
+		assert.NotContains(t, messages[0], "changeme")
+	})
+
+	t.Run("repro bundles replace whole configs", func(t *testing.T) {
+		root := t.TempDir()
+		dir := filepath.Join(root, "internal", "provider")
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		fset := token.NewFileSet()
+		var files []*ast.File
+		for name, src := range map[string]string{
+			"resource_widget.go":      explainWidgetResourceSrc,
+			"resource_gadget.go":      untestedGadgetResourceSrc,
+			"resource_widget_test.go": reproTestSrc,
+		} {
+			path := filepath.Join(dir, name)
+			require.NoError(t, os.WriteFile(path, []byte(src), 0o644))
+			f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+			require.NoError(t, err)
+			files = append(files, f)
+		}
+		settings := config.DefaultSettings()
+		settings.RedactConfigs = true
+		reg := discovery.BuildRegistry(&goanalysis.Pass{Fset: fset, Files: files}, settings)
+
+		bundle, err := repro.Collect("widget", reg, fset, files, settings, root)
+		require.NoError(t, err)
+		assert.Equal(t, 2, bundle.Redacted())
+		assert.Zero(t, bundle.Scrubbed())
+
+		var test []byte
+		for _, f := range bundle.Files {
+			if f.Path == "internal/provider/resource_widget_test.go" {
+				test = f.Data
+			}
+		}
+		require.NotNil(t, test)
+		for _, secret := range []string{"acme-prod", "cost-center", "team-payments", "example_widget"} {
+			assert.NotContains(t, string(test), secret)
+		}
+		assert.Contains(t, string(test), "Config: \"sha256:")
+		_, err = parser.ParseFile(token.NewFileSet(), "", test, 0)
+		assert.NoError(t, err, "the redacted test file still parses")
+	})
+}
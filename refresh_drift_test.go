@@ -0,0 +1,109 @@
+package tfprovidertest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const refreshDriftGadgetTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+)
+
+func TestAccGadget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: ` + "`" + `resource "example_gadget" "test" { name = "a" }` + "`" + `,
+			},
+			{
+				RefreshState: true,
+				RefreshPlanChecks: resource.RefreshPlanChecks{
+					PostRefresh: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+			},
+			{
+				Config: ` + "`" + `resource "example_gadget" "test" { name = "b" }` + "`" + `,
+			},
+			{
+				ResourceName:       "example_gadget.test",
+				ImportState:        true,
+				ImportStatePersist: true,
+			},
+		},
+	})
+}
+`
+
+const refreshDriftSprocketTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+)
+
+func TestAccSprocket_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: ` + "`" + `resource "example_sprocket" "test" {}` + "`" + `,
+				RefreshPlanChecks: resource.RefreshPlanChecks{
+					PostRefresh: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+			},
+		},
+	})
+}
+`
+
+func TestRefreshDrift(t *testing.T) {
+	sources := map[string]string{
+		"/provider/resource_gadget.go":        untestedGadgetResourceSrc,
+		"/provider/resource_gadget_test.go":   refreshDriftGadgetTestSrc,
+		"/provider/resource_sprocket.go":      untestedSprocketResourceSrc,
+		"/provider/resource_sprocket_test.go": refreshDriftSprocketTestSrc,
+	}
+
+	t.Run("steps", func(t *testing.T) {
+		reg := buildRegistryFromSources(t, sources)
+		tests := reg.GetTests(registry.KindResource, "gadget")
+		require.Len(t, tests, 1)
+		steps := tests[0].TestSteps
+		require.Len(t, steps, 4)
+
+		assert.True(t, steps[1].IsRefreshStep())
+		assert.True(t, steps[1].HasRefreshPlanCheck)
+		assert.False(t, steps[1].IsRealUpdateStep(), "a refresh step is not an update")
+		assert.True(t, steps[2].IsUpdateStepFlag, "the step after a refresh is compared with the last config")
+		assert.True(t, steps[3].ImportStatePersist)
+		assert.True(t, registry.CoveredChecks(tests)[registry.CheckRefresh])
+	})
+
+	t.Run("analyzer", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		settings.EnableRefreshDriftCheck = true
+		messages := runAnalyzerOnSources(t, analysis.RunRefreshDriftAnalyzer, settings, sources)
+
+		require.Len(t, messages, 2, strings.Join(messages, "\n\n"))
+		assert.Contains(t, messages[0], "resource 'sprocket' has 1 test(s) but none include a RefreshState step")
+		assert.Contains(t, messages[1], "step 1 of test 'TestAccSprocket_basic' sets RefreshPlanChecks without RefreshState: true")
+	})
+
+	t.Run("settings", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		assert.False(t, settings.RefreshDriftCheckEnabled(), "the rule is opt-in")
+	})
+}
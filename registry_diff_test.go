@@ -0,0 +1,70 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+func TestRegistryDiff(t *testing.T) {
+	newRegistry := func(defs []string, links map[string][]*registry.TestFunctionInfo) *registry.ResourceRegistry {
+		reg := registry.NewResourceRegistry()
+		for _, name := range defs {
+			reg.RegisterResource(&registry.ResourceInfo{Name: name, Kind: registry.KindResource})
+		}
+		for name, tests := range links {
+			for _, fn := range tests {
+				reg.RegisterTestFunction(fn)
+				reg.LinkTest(registry.KindResource, name, fn)
+			}
+		}
+		return reg
+	}
+
+	basic := &registry.TestFunctionInfo{Name: "TestAccWidget_basic", MatchType: registry.MatchTypeInferred}
+	oldReg := newRegistry([]string{"widget", "gadget"}, map[string][]*registry.TestFunctionInfo{
+		"widget": {basic},
+		"gadget": {{Name: "TestAccGadget_basic", MatchType: registry.MatchTypeFuzzy}},
+	})
+	newReg := newRegistry([]string{"widget", "sprocket"}, map[string][]*registry.TestFunctionInfo{
+		"widget": {
+			{Name: "TestAccWidget_basic", MatchType: registry.MatchTypeFunctionName},
+			{Name: "TestAccWidget_import", MatchType: registry.MatchTypeInferred, HasImportStep: true},
+		},
+	})
+
+	diff := registry.Diff(oldReg, newReg)
+	assert.False(t, diff.Empty())
+	assert.Equal(t, []registry.ResourceKey{{Kind: registry.KindResource, Name: "sprocket"}}, diff.Added)
+	assert.Equal(t, []registry.ResourceKey{{Kind: registry.KindResource, Name: "gadget"}}, diff.Removed)
+
+	widget := registry.ResourceKey{Kind: registry.KindResource, Name: "widget"}
+	assert.Equal(t, []registry.CoverageTransition{
+		{Key: widget, Check: registry.CheckImport, Before: false, After: true},
+	}, diff.Coverage)
+	assert.Equal(t, []registry.LinkChange{
+		{Key: widget, Test: "TestAccWidget_basic", Before: registry.MatchTypeInferred, After: registry.MatchTypeFunctionName},
+		{Key: widget, Test: "TestAccWidget_import", Before: registry.MatchTypeNone, After: registry.MatchTypeInferred},
+	}, diff.Links)
+
+	assert.True(t, registry.Diff(newReg, newReg).Empty())
+}
+
+func TestUniqueChecks(t *testing.T) {
+	basic := &registry.TestFunctionInfo{Name: "TestAccWidget_basic", HasCheckDestroy: true}
+	imported := &registry.TestFunctionInfo{Name: "TestAccWidget_import", HasImportStep: true, HasCheckDestroy: true}
+	disappears := &registry.TestFunctionInfo{Name: "TestAccWidget_disappears", ChecksDisappears: true}
+	tests := []*registry.TestFunctionInfo{basic, imported, disappears}
+
+	assert.Empty(t, registry.UniqueChecks(registry.KindResource, tests, basic), "drift is also covered by the import test")
+	assert.Equal(t, []string{registry.CheckImport}, registry.UniqueChecks(registry.KindResource, tests, imported))
+	assert.Equal(t, []string{registry.CheckDisappears}, registry.UniqueChecks(registry.KindResource, tests, disappears))
+
+	only := []*registry.TestFunctionInfo{imported}
+	assert.Equal(t, []string{registry.CheckBasic, registry.CheckImport, registry.CheckDrift},
+		registry.UniqueChecks(registry.KindResource, only, imported), "the only test covers everything its definition has")
+	assert.Equal(t, []string{registry.CheckBasic}, registry.UniqueChecks(registry.KindDataSource, only, imported),
+		"checks that do not apply to the kind are left out")
+}
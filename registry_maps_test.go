@@ -0,0 +1,107 @@
+package tfprovidertest
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+const registryMapProviderSrc = `package google
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/example/terraform-provider-google/google/services/compute"
+	storage "github.com/example/terraform-provider-google/google/services/storagev1"
+)
+
+var generatedResources = map[string]*schema.Resource{
+	"google_compute_instance": compute.ResourceComputeInstance(),
+	"google_storage_bucket":   storage.ResourceStorageBucket(),
+	"google_project":          resourceProject(),
+	"google_vendored_thing":   vendored.ResourceThing(),
+}
+
+func resourceProject() *schema.Resource {
+	return &schema.Resource{}
+}
+`
+
+const registryMapComputeSrc = `package compute
+
+import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+func ResourceComputeInstance() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceComputeInstanceCreate,
+		Read:   resourceComputeInstanceRead,
+		Schema: map[string]*schema.Schema{
+			"name": {Type: schema.TypeString, Required: true},
+		},
+	}
+}
+`
+
+const registryMapStorageSrc = `package storage
+
+import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+func ResourceStorageBucket() *schema.Resource {
+	return &schema.Resource{}
+}
+`
+
+func TestResolveRegistryMapTargets(t *testing.T) {
+	fset := token.NewFileSet()
+	sources := []struct{ path, src string }{
+		{"/provider/google/provider.go", registryMapProviderSrc},
+		{"/provider/google/services/compute/resource_compute_instance.go", registryMapComputeSrc},
+		{"/provider/google/services/storagev1/resource_storage_bucket.go", registryMapStorageSrc},
+	}
+	var files []*ast.File
+	reg := registry.NewResourceRegistry()
+	for _, source := range sources {
+		file, err := parser.ParseFile(fset, source.path, source.src, parser.ParseComments)
+		require.NoError(t, err)
+		files = append(files, file)
+		for _, info := range discovery.ParseResources(file, fset, source.path) {
+			reg.RegisterResource(info)
+		}
+		for _, info := range discovery.ParseProviderRegistryMaps(file, fset, source.path) {
+			reg.RegisterResource(info)
+		}
+	}
+
+	discovery.ResolveRegistryMapTargets(reg, fset, files)
+
+	instance := reg.GetDefinition(registry.KindResource, "google_compute_instance")
+	require.NotNil(t, instance)
+	assert.Equal(t, "/provider/google/services/compute/resource_compute_instance.go", instance.FilePath)
+	assert.Equal(t, 5, fset.Position(instance.SchemaPos).Line)
+	registered := fset.Position(instance.RegisteredPos)
+	assert.Equal(t, "/provider/google/provider.go", registered.Filename)
+	assert.Equal(t, 11, registered.Line)
+	assert.NotEmpty(t, instance.Attributes, "attributes come from the function's own definition")
+
+	bucket := reg.GetDefinition(registry.KindResource, "google_storage_bucket")
+	require.NotNil(t, bucket)
+	assert.Equal(t, "/provider/google/services/storagev1/resource_storage_bucket.go", bucket.FilePath, "aliased import resolves by path")
+
+	project := reg.GetDefinition(registry.KindResource, "google_project")
+	require.NotNil(t, project)
+	assert.Equal(t, "/provider/google/provider.go", project.FilePath)
+	assert.Equal(t, 17, fset.Position(project.SchemaPos).Line, "unqualified call resolves in the map's package")
+
+	vendored := reg.GetDefinition(registry.KindResource, "google_vendored_thing")
+	require.NotNil(t, vendored)
+	assert.Equal(t, "/provider/google/provider.go", vendored.FilePath)
+	assert.Equal(t, 14, fset.Position(vendored.SchemaPos).Line, "functions outside the scan keep the key position")
+	assert.False(t, vendored.RegisteredPos.IsValid())
+}
@@ -0,0 +1,84 @@
+package tfprovidertest
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	goanalysis "golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/internal/matching"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/internal/testsrc"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+// relinkedTestSrc is gadgetResourceTestSrc after an edit that renames the test and points
+// its config at the sprocket, so neither piece of evidence for the gadget link holds.
+var relinkedTestSrc = strings.NewReplacer(
+	"TestAccGadget_basic", "TestAccScenario_basic",
+	`resource "example_gadget" "g" {}`, `resource "example_sprocket" "s" {}`,
+).Replace(gadgetResourceTestSrc)
+
+func TestRelinkFile(t *testing.T) {
+	const testFile = "/provider/misc_test.go"
+	settings := config.DefaultSettings()
+	reg := buildRegistryFromSources(t, map[string]string{
+		"/provider/resource_gadget.go":         untestedGadgetResourceSrc,
+		"/provider/resource_sprocket.go":       untestedSprocketResourceSrc,
+		testFile:                               gadgetResourceTestSrc,
+		"/provider/data_source_gadget.go":      gadgetDataSourceSrc,
+		"/provider/data_source_gadget_test.go": testsrc.Acceptance("TestAccGadgetDataSource_basic", `data "example_gadget" "g" {}`),
+	})
+	old := findTest(t, reg, "TestAccGadget_basic")
+	require.Len(t, reg.GetTests(registry.KindResource, "gadget"), 1)
+	require.NotEmpty(t, reg.GetMatchCandidates(old))
+
+	// parseTests parses src as the test file and returns its test functions
+	parseTests := func(src string) []*registry.TestFunctionInfo {
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, testFile, src, parser.ParseComments)
+		require.NoError(t, err)
+		return discovery.BuildRegistry(&goanalysis.Pass{Fset: fset, Files: []*ast.File{f}}, settings).GetAllTestFunctions()
+	}
+	linker := matching.NewLinker(reg, &settings)
+
+	t.Run("stale link is replaced", func(t *testing.T) {
+		replaced := linker.RelinkFile(testFile, parseTests(relinkedTestSrc))
+		require.Equal(t, []*registry.TestFunctionInfo{old}, replaced)
+		assert.Equal(t, registry.MatchTypeNone, old.MatchType)
+		assert.Empty(t, old.MatchedResource)
+		assert.Zero(t, old.MatchConfidence)
+		assert.Nil(t, reg.GetMatchCandidates(old), "candidates of the replaced test are dropped")
+
+		assert.Empty(t, reg.GetTests(registry.KindResource, "gadget"), "the gadget link no longer holds")
+		fn := findTest(t, reg, "TestAccScenario_basic")
+		assert.Equal(t, "sprocket", fn.MatchedResource)
+		assert.Equal(t, []*registry.TestFunctionInfo{fn}, reg.GetTests(registry.KindResource, "sprocket"))
+		assert.Equal(t, registry.TestCategoryResource, fn.Category)
+		assert.NotEmpty(t, reg.GetMatchCandidates(fn))
+
+		assert.Len(t, reg.GetTests(registry.KindDataSource, "gadget"), 1, "tests of other files keep their links")
+		assert.Len(t, reg.GetAllTestFunctions(), 2)
+	})
+
+	t.Run("relinking unchanged content is stable", func(t *testing.T) {
+		linker.RelinkFile(testFile, parseTests(relinkedTestSrc))
+		linker.RelinkFile(testFile, parseTests(relinkedTestSrc))
+		assert.Len(t, reg.GetTests(registry.KindResource, "sprocket"), 1)
+		assert.Len(t, reg.GetAllTestFunctions(), 2)
+	})
+
+	t.Run("removed file", func(t *testing.T) {
+		replaced := linker.RelinkFile(testFile, nil)
+		require.Len(t, replaced, 1)
+		assert.Empty(t, reg.GetTests(registry.KindResource, "sprocket"))
+		assert.Len(t, reg.GetAllTestFunctions(), 1)
+		assert.Nil(t, reg.UnlinkTestsForFile(testFile), "nothing is left to unlink")
+	})
+}
@@ -0,0 +1,159 @@
+package tfprovidertest
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	goanalysis "golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/internal/repro"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const reproTestSrc = `package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccWidget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: testAccWidgetConfig("test")}},
+	})
+}
+
+func TestAccGadget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: "resource \"example_widget\" \"w\" {\n  owner = \"team-payments\"\n}\n"}},
+	})
+}
+
+func testAccWidgetConfig(name string) string {
+	return fmt.Sprintf(` + "`" + `
+resource "example_widget" %[1]q {
+  name   = "acme-prod-${var.env}"
+  tags   = ["internal", "%[1]s"]
+  labels = {
+    "cost-center" = "4711"
+  }
+}
+` + "`" + `, name)
+}
+`
+
+func TestScrubHCL(t *testing.T) {
+	scrubbed, count := repro.ScrubHCL(`resource "example_widget" %[1]q {
+  name = "acme-${var.env}-%s"
+  note = "say \"hi\""
+  list = ["a", ""]
+  dynamic "rule" {
+    content {}
+  }
+}
+`)
+	assert.Equal(t, `resource "example_widget" %[1]q {
+  name = "redacted${var.env}redacted%s"
+  note = "redacted"
+  list = ["redacted", ""]
+  dynamic "rule" {
+    content {}
+  }
+}
+`, scrubbed, "labels, interpolations, verbs and empty strings are kept")
+	assert.Equal(t, 3, count)
+}
+
+func TestReproBundle(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "internal", "provider")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	sources := map[string]string{
+		"resource_widget.go":      explainWidgetResourceSrc,
+		"resource_gadget.go":      untestedGadgetResourceSrc,
+		"resource_widget_test.go": reproTestSrc,
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for name, src := range sources {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(src), 0o644))
+		f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		require.NoError(t, err)
+		files = append(files, f)
+	}
+	settings := config.DefaultSettings()
+	reg := discovery.BuildRegistry(&goanalysis.Pass{Fset: fset, Files: files}, settings)
+
+	bundle, err := repro.Collect("widget", reg, fset, files, settings, root)
+	require.NoError(t, err)
+
+	roles := map[string]string{}
+	for _, f := range bundle.Files {
+		roles[f.Path] = f.Role
+	}
+	assert.Equal(t, map[string]string{
+		"internal/provider/resource_widget.go":      repro.RoleDefinition,
+		"internal/provider/resource_widget_test.go": repro.RoleTest,
+		"internal/provider/resource_gadget.go":      repro.RoleCompeting,
+	}, roles, "the gadget a rejected candidate is linked to comes along")
+	assert.Equal(t, 5, bundle.Scrubbed())
+
+	var tests []string
+	for _, c := range bundle.Candidates {
+		tests = append(tests, c.Test)
+	}
+	assert.ElementsMatch(t, []string{"TestAccWidget_basic", "TestAccGadget_basic"}, tests)
+
+	var buf bytes.Buffer
+	require.NoError(t, bundle.WriteZip(&buf))
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	entries := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		entries[f.Name] = string(data)
+	}
+	require.Contains(t, entries, repro.ReadmeFile)
+	require.Contains(t, entries, repro.SettingsFile)
+	assert.Contains(t, entries[repro.SettingsFile], "enable-basic-test: true")
+
+	var decisions struct {
+		Query       string `json:"query"`
+		Explanation struct {
+			Definitions []struct {
+				Name string `json:"name"`
+			} `json:"definitions"`
+		} `json:"explanation"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(entries[repro.DecisionsFile]), &decisions))
+	assert.Equal(t, "widget", decisions.Query)
+	require.Len(t, decisions.Explanation.Definitions, 1)
+
+	test := entries["provider/internal/provider/resource_widget_test.go"]
+	for _, secret := range []string{"acme-prod", "internal", "cost-center", "4711", "team-payments"} {
+		assert.NotContains(t, test, secret)
+	}
+	assert.Contains(t, test, `resource "example_widget" %[1]q {`)
+	assert.Contains(t, test, `"redacted${var.env}"`)
+	assert.Contains(t, test, `owner = \"redacted\"`, "interpreted string literals stay escaped")
+	_, err = parser.ParseFile(token.NewFileSet(), "", test, 0)
+	assert.NoError(t, err, "the scrubbed test file still parses")
+}
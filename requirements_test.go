@@ -0,0 +1,252 @@
+package tfprovidertest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/internal/requirements"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const gadgetBasicTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccGadget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: ` + "`" + `resource "example_gadget" "g" {}` + "`" + `}},
+	})
+}
+`
+
+const gadgetDisappearsTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccGadget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: ` + "`" + `resource "example_gadget" "g" {}` + "`" + `}},
+	})
+}
+
+func TestAccGadget_gone(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{
+			Config:             ` + "`" + `resource "example_gadget" "g" {}` + "`" + `,
+			Check:              acctest.CheckResourceDisappears(t, "example_gadget.g"),
+			ExpectNonEmptyPlan: true,
+		}},
+	})
+}
+`
+
+// writeManifest writes a requirements manifest to a temporary directory and returns settings
+// that point at it.
+func writeManifest(t *testing.T, content string) config.Settings {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), requirements.ManifestFile)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	settings := config.DefaultSettings()
+	settings.RequirementsManifest = path
+	return settings
+}
+
+func TestRequirementsManifestValidation(t *testing.T) {
+	tests := map[string]struct {
+		manifest string
+		problem  string
+	}{
+		"unknown check": {
+			manifest: "resources:\n  widget:\n    require: [basic, imprt]\n",
+			problem:  `unknown check "imprt"`,
+		},
+		"inapplicable check": {
+			manifest: "data-sources:\n  widget:\n    require: [import]\n",
+			problem:  `check "import" does not apply to data-sources`,
+		},
+		"required and exempt": {
+			manifest: "resources:\n  widget:\n    require: [error]\n    exempt:\n      error: no validators\n",
+			problem:  `check "error" is both required and exempt`,
+		},
+		"unknown maturity": {
+			manifest: "resources:\n  widget:\n    maturity: alpha\n",
+			problem:  `unknown maturity "alpha"`,
+		},
+		"unknown field": {
+			manifest: "resources:\n  widget:\n    requires: [basic]\n",
+			problem:  "field requires not found",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := requirements.Parse([]byte(tc.manifest), "reqs.yaml")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.problem)
+		})
+	}
+
+	t.Run("empty manifest", func(t *testing.T) {
+		m, err := requirements.Parse(nil, "reqs.yaml")
+		require.NoError(t, err)
+		assert.Empty(t, m.Keys())
+	})
+}
+
+func TestRequirementsManifestFind(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.mod":                        "module example.com/provider\n",
+		requirements.ManifestFile:       "strict: true\n",
+		"internal/provider/provider.go": "package provider\n",
+	})
+
+	assert.Equal(t, filepath.Join(root, requirements.ManifestFile), requirements.Find(filepath.Join(root, "internal", "provider")))
+	assert.Empty(t, requirements.Resolve(requirements.Off, filepath.Join(root, "internal", "provider")))
+
+	// The search stops at the module root
+	nested := filepath.Join(root, "tools")
+	writeFiles(t, nested, map[string]string{"go.mod": "module example.com/tools\n"})
+	assert.Empty(t, requirements.Find(nested))
+}
+
+func TestRequirementsManifestEnforced(t *testing.T) {
+	sources := map[string]string{
+		"/provider/resource_gadget.go":      untestedGadgetResourceSrc,
+		"/provider/resource_gadget_test.go": gadgetBasicTestSrc,
+	}
+
+	t.Run("required import without ImportState", func(t *testing.T) {
+		settings := writeManifest(t, "resources:\n  gadget:\n    require: [basic, import]\n")
+		diags := runAnalyzerOnSources(t, analysis.RunImportTestAnalyzer, settings, sources)
+		require.Len(t, diags, 1)
+		assert.Contains(t, diags[0], "resource 'gadget' requires import test coverage ("+settings.RequirementsManifest+")")
+	})
+
+	t.Run("exempt check is not reported", func(t *testing.T) {
+		settings := writeManifest(t, "resources:\n  gadget:\n    exempt:\n      basic: covered by the integration suite\n")
+		diags := runAnalyzerOnSources(t, analysis.RunBasicTestAnalyzer, settings, map[string]string{
+			"/provider/resource_gadget.go": untestedGadgetResourceSrc,
+		})
+		assert.Empty(t, diags)
+	})
+
+	t.Run("missing disappears test", func(t *testing.T) {
+		settings := writeManifest(t, "resources:\n  gadget:\n    require: [basic, disappears]\n")
+		diags := runAnalyzerOnSources(t, analysis.RunRequirementsAnalyzer, settings, sources)
+		require.Len(t, diags, 1)
+		assert.Contains(t, diags[0], "resource 'gadget' requires a disappears test")
+		assert.Contains(t, diags[0], "TestAccGadget_disappears")
+	})
+
+	t.Run("disappears test by check function", func(t *testing.T) {
+		settings := writeManifest(t, "resources:\n  gadget:\n    require: [basic, disappears]\n")
+		diags := runAnalyzerOnSources(t, analysis.RunRequirementsAnalyzer, settings, map[string]string{
+			"/provider/resource_gadget.go":      untestedGadgetResourceSrc,
+			"/provider/resource_gadget_test.go": gadgetDisappearsTestSrc,
+		})
+		assert.Empty(t, diags)
+	})
+
+	t.Run("strict manifest reports unlisted definitions", func(t *testing.T) {
+		settings := writeManifest(t, "strict: true\nresources:\n  gadget:\n    require: [basic]\n")
+		diags := runAnalyzerOnSources(t, analysis.RunRequirementsAnalyzer, settings, map[string]string{
+			"/provider/resource_gadget.go":   untestedGadgetResourceSrc,
+			"/provider/resource_sprocket.go": untestedSprocketResourceSrc,
+		})
+		require.Len(t, diags, 1)
+		assert.Contains(t, diags[0], "resource 'sprocket' is not listed in")
+	})
+
+	t.Run("directive wins a conflict", func(t *testing.T) {
+		settings := writeManifest(t, "resources:\n  gadget:\n    require: [basic, import]\n")
+		directiveSrc := strings.Replace(untestedGadgetResourceSrc, "type GadgetResource struct{}",
+			"//tftest:exempt import reason=\"imported by ID only\"\ntype GadgetResource struct{}", 1)
+		srcs := map[string]string{
+			"/provider/resource_gadget.go":      directiveSrc,
+			"/provider/resource_gadget_test.go": gadgetBasicTestSrc,
+		}
+		diags := runAnalyzerOnSources(t, analysis.RunRequirementsAnalyzer, settings, srcs)
+		require.Len(t, diags, 1)
+		assert.Contains(t, diags[0], "disagree on check 'import'; the directive wins")
+
+		assert.Empty(t, runAnalyzerOnSources(t, analysis.RunImportTestAnalyzer, settings, srcs))
+	})
+
+	t.Run("invalid manifest", func(t *testing.T) {
+		settings := writeManifest(t, "resources:\n  gadget:\n    require: [nope]\n")
+		diags := runAnalyzerOnSources(t, analysis.RunRequirementsAnalyzer, settings, sources)
+		require.Len(t, diags, 1)
+		assert.Contains(t, diags[0], "requirements manifest could not be loaded")
+	})
+
+	t.Run("manifest off", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		settings.RequirementsManifest = requirements.Off
+		assert.Empty(t, runAnalyzerOnSources(t, analysis.RunRequirementsAnalyzer, settings, sources))
+	})
+}
+
+func TestRequirementsManifestSync(t *testing.T) {
+	path := filepath.Join(t.TempDir(), requirements.ManifestFile)
+	require.NoError(t, os.WriteFile(path, []byte(`# Maintained by the provider team
+resources:
+  # Widgets are the flagship resource
+  widget:
+    require: [basic, disappears]
+  removed:
+    require: [basic]
+`), 0o644))
+
+	defs := []*registry.ResourceInfo{
+		{Name: "widget", Kind: registry.KindResource},
+		{Name: "gadget", Kind: registry.KindResource},
+		{Name: "gadget", Kind: registry.KindDataSource},
+	}
+	scaffold := func(def *registry.ResourceInfo) requirements.Entry {
+		return requirements.Entry{Require: []string{registry.CheckBasic}}
+	}
+
+	result, err := requirements.Sync(path, defs, scaffold)
+	require.NoError(t, err)
+	assert.False(t, result.Created)
+	assert.Equal(t, []registry.ResourceKey{
+		{Kind: registry.KindResource, Name: "gadget"},
+		{Kind: registry.KindDataSource, Name: "gadget"},
+	}, result.Added)
+	assert.Equal(t, []registry.ResourceKey{{Kind: registry.KindResource, Name: "removed"}}, result.Stale)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	content := string(data)
+	assert.Contains(t, content, "# Widgets are the flagship resource")
+	assert.Contains(t, content, "removed:")
+
+	m, err := requirements.Load(path)
+	require.NoError(t, err)
+	entry, ok := m.Lookup(registry.KindDataSource, "gadget")
+	require.True(t, ok)
+	assert.Equal(t, []string{"basic"}, entry.Require)
+	entry, ok = m.Lookup(registry.KindResource, "widget")
+	require.True(t, ok)
+	assert.Equal(t, []string{"basic", "disappears"}, entry.Require)
+
+	// A second sync has nothing to add
+	result, err = requirements.Sync(path, defs, scaffold)
+	require.NoError(t, err)
+	assert.Empty(t, result.Added)
+}
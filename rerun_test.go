@@ -0,0 +1,65 @@
+package tfprovidertest
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRerunFailed(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the validate command")
+	}
+	binary := filepath.Join(t.TempDir(), "validate")
+	out, err := exec.Command("go", "build", "-o", binary, "./cmd/validate").CombinedOutput()
+	require.NoError(t, err, "building validate: %s", out)
+
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	run := func(args ...string) []rerunFinding {
+		t.Helper()
+		args = append([]string{"-provider", "testlintdata", "-recursive", "-format", "json", "-run-state", statePath}, args...)
+		cmd := exec.Command(binary, args...)
+		cmd.Dir = filepath.Join("testdata", "src")
+		out, err := cmd.Output()
+		require.NoError(t, err)
+		var output struct {
+			Findings []rerunFinding `json:"findings"`
+		}
+		require.NoError(t, json.Unmarshal(out, &output), "output: %s", out)
+		return output.Findings
+	}
+
+	full := run("-analyzer", "tfprovider-coverage-basic-test,tfprovider-coverage-import-test,tfprovider-quality-check-functions")
+	failed := make(map[string]bool)
+	for _, f := range full {
+		failed[f.Rule] = true
+	}
+	require.True(t, failed["tfprovider-coverage-basic-test"])
+	require.False(t, failed["tfprovider-coverage-import-test"], "the fixture has no import test findings")
+
+	data, err := os.ReadFile(statePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"basic_missing/data_source_info.go"`)
+
+	rerun := run("-rerun-failed")
+	assert.ElementsMatch(t, full, rerun, "a rerun reports the same findings while nothing is fixed")
+
+	// Restricting the rerun to one analyzer records only its findings, so the next rerun
+	// runs it alone
+	run("-rerun-failed", "-analyzer", "tfprovider-coverage-basic-test")
+	for _, f := range run("-rerun-failed") {
+		assert.Equal(t, "tfprovider-coverage-basic-test", f.Rule)
+	}
+}
+
+// rerunFinding is the part of a validate JSON finding TestRerunFailed reads.
+type rerunFinding struct {
+	Rule string `json:"rule"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
@@ -0,0 +1,117 @@
+package tfprovidertest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/routing"
+)
+
+func TestCodeowners(t *testing.T) {
+	owners, err := routing.ParseCodeowners([]byte(`# Default owners
+*                            @org/providers
+/internal/service/widget/    @org/widgets @alice
+*_test.go                    @org/testing
+docs/**/*.md                 @org/docs
+/internal/service/widget/legacy.go
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"@org/providers"}, owners.Owners("main.go"))
+	assert.Equal(t, []string{"@org/widgets", "@alice"}, owners.Owners("internal/service/widget/resource.go"))
+	assert.Equal(t, []string{"@org/testing"}, owners.Owners("internal/service/widget/resource_test.go"),
+		"the last matching pattern wins")
+	assert.Equal(t, []string{"@org/docs"}, owners.Owners("docs/resources/guides/widget.md"))
+	assert.Empty(t, owners.Owners("internal/service/widget/legacy.go"), "a pattern without owners unassigns")
+	assert.Equal(t, []string{"@org/providers"}, owners.Owners("other/internal/service/widget/resource.go"),
+		"a pattern with a slash is anchored at the root")
+
+	var none *routing.Codeowners
+	assert.Nil(t, none.Owners("main.go"))
+
+	_, err = routing.ParseCodeowners([]byte("!vendor/ @org/none\n"))
+	assert.ErrorContains(t, err, "line 1: unsupported pattern")
+
+	root := t.TempDir()
+	loaded, path, err := routing.LoadCodeowners(root)
+	require.NoError(t, err)
+	assert.Nil(t, loaded)
+	assert.Empty(t, path)
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".github"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".github", "CODEOWNERS"), []byte("* @org/all\n"), 0o644))
+	loaded, path, err = routing.LoadCodeowners(root)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, ".github", "CODEOWNERS"), path)
+	assert.Equal(t, []string{"@org/all"}, loaded.Owners("any/file.go"))
+}
+
+func TestRoutingBuild(t *testing.T) {
+	root := t.TempDir()
+	service := filepath.Join(root, "internal", "service")
+	owners, err := routing.ParseCodeowners([]byte("* @org/providers\n*_test.go @org/testing\n"))
+	require.NoError(t, err)
+
+	findings := []routing.Finding{
+		{
+			Rule: "tfprovider-quality-drift-check", Group: "quality", Level: "error",
+			File: filepath.Join(service, "resource_widget.go"), Line: 20,
+			Message:  "resource 'widget' has 1 test(s) but none include CheckDestroy\n  Suggestion: ...",
+			Resource: "widget", Kind: "resource", DefinitionFile: filepath.Join(service, "resource_widget.go"),
+		},
+		{
+			Rule: "tfprovider-coverage-update-test", Group: "coverage", Level: "error",
+			File: filepath.Join(service, "resource_widget_test.go"), Line: 8,
+			Message:  "resource 'widget' has no update test",
+			Resource: "widget", Kind: "resource", DefinitionFile: filepath.Join(service, "resource_widget.go"),
+		},
+		{
+			Rule: "tfprovider-quality-check-functions", Group: "quality", Level: "warning",
+			File: filepath.Join(service, "data_source_widget.go"), Line: 5,
+			Message:  "[warning] data source 'widget' has no checks",
+			Resource: "widget", Kind: "data source", DefinitionFile: filepath.Join(service, "data_source_widget.go"),
+		},
+		{
+			Rule: "tfprovider-quality-sweepers", Group: "quality", Level: "error",
+			File: filepath.Join(service, "sweep_test.go"), Line: 1,
+			Message: "package has no test sweeper registrations",
+		},
+	}
+	file := routing.Build(findings, root, owners)
+	assert.Equal(t, routing.Version, file.Version)
+
+	var keys []string
+	for _, issue := range file.Issues {
+		keys = append(keys, issue.Key)
+	}
+	assert.Equal(t, []string{
+		"tfprovidertest/data_source/widget",
+		"tfprovidertest/file/internal/service/sweep_test.go",
+		"tfprovidertest/resource/widget",
+	}, keys, "one issue per definition, and per file for unattributed findings")
+
+	dataSource := file.Issues[0]
+	assert.Equal(t, []string{"tests/quality", "tfprovidertest", "warning-only"}, dataSource.Labels)
+
+	resource := file.Issues[2]
+	assert.Equal(t, "Acceptance test findings for resource widget", resource.Title)
+	assert.Equal(t, "internal/service/resource_widget.go", resource.File)
+	assert.Equal(t, []string{"@org/providers"}, resource.Owners, "the issue belongs to the owners of the definition")
+	assert.Equal(t, []string{"tests/coverage", "tests/quality", "tfprovidertest"}, resource.Labels)
+	require.Len(t, resource.Findings, 2)
+	assert.Equal(t, "internal/service/resource_widget.go", resource.Findings[0].File, "findings are ordered by position")
+	assert.Equal(t, []string{"@org/testing"}, resource.Findings[1].Owners)
+
+	// Keys do not depend on line numbers or on the finding becoming a warning
+	moved := append([]routing.Finding(nil), findings...)
+	moved[0].Line = 42
+	moved[0].Level = "warning"
+	moved[0].Message = "[warning] " + moved[0].Message
+	again := routing.Build(moved, root, owners)
+	assert.Equal(t, resource.Findings[0].Key, again.Issues[2].Findings[0].Key)
+	assert.Equal(t, routing.Fingerprint("tfprovider-quality-drift-check", "internal/service/resource_widget.go",
+		"resource 'widget' has 1 test(s) but none include CheckDestroy"), resource.Findings[0].Key)
+}
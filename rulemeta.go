@@ -0,0 +1,64 @@
+package tfprovidertest
+
+import (
+	"fmt"
+
+	"github.com/example/tfprovidertest/internal/rules"
+)
+
+// RuleMetadata describes a rule for integrators that build their own runners around the
+// analyzers.
+type RuleMetadata struct {
+	Name       string `json:"name"`
+	LegacyName string `json:"legacy_name,omitempty"`
+	Code       string `json:"code"`     // Stable rule ID, e.g. "TFPT001"
+	Category   string `json:"category"` // "coverage" or "quality"
+	// Severity is the level of the rule's findings in files no enforce-paths or
+	// warn-only-paths pattern matches: "error", or "warning" when enforce-paths is set.
+	// Directory configs may still change it for their subtree.
+	Severity string `json:"severity"`
+	Doc      string `json:"doc"`
+	// Enabled reports whether BuildAnalyzers builds the rule's analyzer under the
+	// plugin's settings
+	Enabled bool `json:"enabled"`
+}
+
+// Trailer returns the metadata lines BuildAnalyzers appends, after a blank line, to the Doc
+// of each analyzer, so runners that only see the analyzers can read them back:
+//
+//	rule-id: TFPT001
+//	severity: error
+//	category: coverage
+func (m RuleMetadata) Trailer() string {
+	return fmt.Sprintf("rule-id: %s\nseverity: %s\ncategory: %s", m.Code, m.Severity, m.Category)
+}
+
+// Rules returns the metadata of every rule, in the order BuildAnalyzers builds their
+// analyzers, including the rules the settings disable.
+func (p *Plugin) Rules() []RuleMetadata {
+	enabled := make(map[string]bool)
+	if analyzers, err := p.BuildAnalyzers(); err == nil {
+		for _, analyzer := range analyzers {
+			enabled[analyzer.Name] = true
+		}
+	}
+	var result []RuleMetadata
+	for _, rule := range rules.All() {
+		m := p.ruleMetadata(rule)
+		m.Enabled = enabled[rule.Name]
+		result = append(result, m)
+	}
+	return result
+}
+
+// ruleMetadata returns the metadata of rule under the plugin's settings, without Enabled.
+func (p *Plugin) ruleMetadata(rule rules.Rule) RuleMetadata {
+	return RuleMetadata{
+		Name:       rule.Name,
+		LegacyName: rule.LegacyName,
+		Code:       rule.Code,
+		Category:   string(rule.Group),
+		Severity:   string(p.enforcement.DefaultLevel()),
+		Doc:        rule.Doc,
+	}
+}
@@ -0,0 +1,60 @@
+package tfprovidertest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/rules"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+func TestRuleMetadata(t *testing.T) {
+	plugin := NewWithSettings(config.DefaultSettings())
+	analyzers, err := plugin.BuildAnalyzers()
+	require.NoError(t, err)
+
+	metadata := plugin.Rules()
+	require.Len(t, metadata, len(rules.All()))
+	byName := make(map[string]RuleMetadata)
+	enabled := 0
+	for _, m := range metadata {
+		byName[m.Name] = m
+		if m.Enabled {
+			enabled++
+		}
+	}
+	assert.Equal(t, len(analyzers), enabled)
+
+	basic := byName[rules.BasicTest]
+	assert.Equal(t, RuleMetadata{
+		Name:       rules.BasicTest,
+		LegacyName: "tfprovider-resource-basic-test",
+		Code:       "TFPT001",
+		Category:   "coverage",
+		Severity:   "error",
+		Doc:        "Checks that every resource and data source has at least one acceptance test.",
+		Enabled:    true,
+	}, basic)
+	assert.False(t, byName[rules.DeadTests].Enabled)
+
+	t.Run("each analyzer's Doc ends in the trailer of its rule", func(t *testing.T) {
+		for _, analyzer := range analyzers {
+			m := byName[analyzer.Name]
+			doc, trailer, ok := strings.Cut(analyzer.Doc, "\n\n")
+			require.True(t, ok, analyzer.Name)
+			assert.Equal(t, m.Doc, doc)
+			assert.Equal(t, "rule-id: "+m.Code+"\nseverity: error\ncategory: "+m.Category, trailer)
+		}
+	})
+
+	t.Run("enforce-paths lowers the default severity to warning", func(t *testing.T) {
+		s := config.DefaultSettings()
+		s.EnforcePaths = []string{"internal/service/s3/**"}
+		for _, m := range NewWithSettings(s).Rules() {
+			assert.Equal(t, "warning", m.Severity, m.Name)
+		}
+	})
+}
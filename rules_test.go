@@ -0,0 +1,119 @@
+package tfprovidertest_test
+
+import (
+	"go/ast"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest"
+	"github.com/example/tfprovidertest/internal/matching"
+	"github.com/example/tfprovidertest/internal/rules"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+func analyzerNames(t *testing.T, settings any) []string {
+	t.Helper()
+	plugin, err := tfprovidertest.New(settings)
+	require.NoError(t, err)
+	analyzers, err := plugin.BuildAnalyzers()
+	require.NoError(t, err)
+
+	var names []string
+	for _, a := range analyzers {
+		names = append(names, a.Name)
+	}
+	return names
+}
+
+func TestRuleCatalogue(t *testing.T) {
+	t.Run("every analyzer is catalogued with a group prefix", func(t *testing.T) {
+		for _, name := range analyzerNames(t, nil) {
+			rule, ok := rules.Lookup(name)
+			require.True(t, ok, "analyzer %s missing from catalogue", name)
+			assert.Contains(t, name, "tfprovider-"+string(rule.Group)+"-")
+			assert.NotEmpty(t, rule.Doc)
+		}
+	})
+
+	t.Run("every rule has a unique code", func(t *testing.T) {
+		seen := make(map[string]string)
+		for _, rule := range rules.All() {
+			assert.Regexp(t, `^TFPT\d{3}$`, rule.Code, rule.Name)
+			assert.NotContains(t, seen, rule.Code, "%s reuses the code of %s", rule.Name, seen[rule.Code])
+			seen[rule.Code] = rule.Name
+		}
+		assert.Equal(t, "TFPT003", rules.Code(rules.ImportTest))
+		assert.Equal(t, "TFPT003", rules.Code("tfprovider-resource-import-test"))
+	})
+
+	t.Run("every rule names settings that exist", func(t *testing.T) {
+		defaults := config.SettingDefaults()
+		for _, rule := range rules.All() {
+			for _, key := range rule.Settings {
+				assert.Contains(t, defaults, key, rule.Name)
+			}
+			key, _, ok := config.RuleToggle(rule.Name)
+			if rule.Name == rules.DriftCheck || rule.Name == rules.Sweepers {
+				assert.False(t, ok, "%s runs alongside the other rules", rule.Name)
+				continue
+			}
+			require.True(t, ok, "%s has no toggle", rule.Name)
+			assert.Contains(t, defaults, key, rule.Name)
+		}
+		key, enabled, _ := config.RuleToggle("tfprovider-resource-basic-test")
+		assert.Equal(t, "enable-basic-test", key)
+		assert.True(t, enabled)
+	})
+
+	t.Run("legacy names resolve to renamed rules", func(t *testing.T) {
+		assert.Equal(t, rules.BasicTest, rules.Canonical("tfprovider-resource-basic-test"))
+		assert.Equal(t, rules.ErrorTest, rules.Canonical("tfprovider-test-error-cases"))
+		assert.Equal(t, rules.GroupQuality, rules.GroupOf("tfprovider-test-sweepers"))
+		assert.Equal(t, "custom-rule", rules.Canonical("custom-rule"))
+	})
+
+	t.Run("legacy suppression comments still apply", func(t *testing.T) {
+		comments := []*ast.CommentGroup{{List: []*ast.Comment{{Text: "// nolint:tfprovider-test-drift-check"}}}}
+		assert.True(t, matching.CheckSuppressionComment(comments, rules.DriftCheck))
+		assert.False(t, matching.CheckSuppressionComment(comments, rules.Sweepers))
+	})
+}
+
+func TestPlugin_RuleGroups(t *testing.T) {
+	t.Run("coverage group only", func(t *testing.T) {
+		names := analyzerNames(t, map[string]interface{}{
+			"EnableCoverageRules": true,
+			"EnableQualityRules":  false,
+		})
+		assert.Equal(t, []string{rules.BasicTest, rules.UpdateTest, rules.ImportTest, rules.ErrorTest, rules.ProviderConfig, rules.Requirements, rules.DeferredActions, rules.ProviderAliases, rules.UpgradeTest, rules.PairedDefinitions}, names)
+	})
+
+	t.Run("quality group only", func(t *testing.T) {
+		names := analyzerNames(t, map[string]interface{}{
+			"EnableQualityRules": true,
+		})
+		assert.Equal(t, []string{rules.CheckFunctions, rules.ImportStateIdFunc, rules.ImportStateVerify, rules.ParallelFixtures, rules.ParallelTests, rules.TestPlacement, rules.TestLayout, rules.OrphanTests, rules.DefaultValues, rules.Placeholders, rules.ProviderHygiene, rules.UnknownTypes, rules.TestHelpers, rules.DataSourceConfig, rules.DataSourceAsserts, rules.PluralDataSources, rules.LegacyChecks, rules.ProviderFactories, rules.ErrorCheck, rules.ExpectError, rules.DeadTests, rules.CheckAddresses, rules.RefreshDrift, rules.Idempotency, rules.EnvPreCheck, rules.DeprecatedAttrs, rules.DriftCheck, rules.Sweepers}, names)
+	})
+
+	t.Run("group overrides individual toggles", func(t *testing.T) {
+		names := analyzerNames(t, map[string]interface{}{
+			"EnableBasicTest":     true,
+			"EnableStateCheck":    true,
+			"EnableCoverageRules": false,
+		})
+		assert.Equal(t, []string{rules.CheckFunctions, rules.DriftCheck, rules.Sweepers}, names)
+	})
+
+	t.Run("validation accounts for groups", func(t *testing.T) {
+		enabled, disabled := true, false
+		settings := config.DefaultSettings()
+		settings.EnableCoverageRules = &disabled
+		settings.EnableQualityRules = &disabled
+		assert.Error(t, settings.Validate())
+
+		settings.EnableQualityRules = &enabled
+		assert.NoError(t, settings.Validate())
+	})
+}
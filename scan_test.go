@@ -0,0 +1,162 @@
+package tfprovidertest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/scan"
+)
+
+// writeScanFixture creates files (and their parent directories) under root.
+func writeScanFixture(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+// excludedReasons maps each excluded directory (relative to root) to its reason.
+func excludedReasons(t *testing.T, root string, manifest *scan.Manifest) map[string]string {
+	t.Helper()
+	reasons := make(map[string]string)
+	for _, e := range manifest.Excluded {
+		rel, err := filepath.Rel(root, e.Path)
+		require.NoError(t, err)
+		reasons[filepath.ToSlash(rel)] = e.Reason
+	}
+	return reasons
+}
+
+func relDirs(t *testing.T, root string, dirs []string) []string {
+	t.Helper()
+	var rels []string
+	for _, dir := range dirs {
+		rel, err := filepath.Rel(root, dir)
+		require.NoError(t, err)
+		rels = append(rels, filepath.ToSlash(rel))
+	}
+	return rels
+}
+
+func TestScanGoPackageDirs(t *testing.T) {
+	root := t.TempDir()
+	writeScanFixture(t, root, map[string]string{
+		".git/HEAD":                        "ref: refs/heads/main",
+		".gitignore":                       "/generated/\n*.tmp\nbuild/\n!build/keep/\n",
+		"main.go":                          "package main",
+		"internal/provider/provider.go":    "package provider",
+		"internal/provider/README.md":      "docs",
+		"internal/docs/notes.md":           "no go here",
+		"generated/client/client.go":       "package client",
+		"tools/tools.go":                   "package tools",
+		"examples/basic/main.go":           "package main",
+		"vendor/github.com/x/x.go":         "package x",
+		"internal/provider/sub/.gitignore": "skip/\n",
+		"internal/provider/sub/skip/a.go":  "package skip",
+		"internal/provider/sub/b.go":       "package sub",
+	})
+
+	t.Run("gitignore, defaults and extra excludes", func(t *testing.T) {
+		opts := scan.DefaultOptions()
+		opts.ExtraExcludes = []string{"tools", "examples/"}
+
+		manifest := scan.GoPackageDirs(root, opts)
+
+		assert.Equal(t, []string{".", "internal/provider", "internal/provider/sub"}, relDirs(t, root, manifest.Dirs()))
+		reasons := excludedReasons(t, root, manifest)
+		assert.Equal(t, scan.ReasonGitignore, reasons["generated"])
+		assert.Equal(t, scan.ReasonGitignore, reasons["internal/provider/sub/skip"])
+		assert.Equal(t, scan.ReasonExtraExclude, reasons["tools"])
+		assert.Equal(t, scan.ReasonExtraExclude, reasons["examples"])
+		assert.Equal(t, scan.ReasonDefaultExclude, reasons["vendor"])
+		assert.Equal(t, scan.ReasonDefaultExclude, reasons[".git"])
+		assert.Equal(t, scan.ReasonNoGoFiles, reasons["internal/docs"])
+	})
+
+	t.Run("gitignore can be disabled", func(t *testing.T) {
+		manifest := scan.GoPackageDirs(root, scan.Options{})
+		assert.Contains(t, relDirs(t, root, manifest.Dirs()), "generated/client")
+	})
+}
+
+func TestScanGoPackageDirs_Symlinks(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	writeScanFixture(t, root, map[string]string{
+		"internal/provider/provider.go": "package provider",
+	})
+	writeScanFixture(t, outside, map[string]string{
+		"shared/shared.go": "package shared",
+	})
+	if err := os.Symlink(filepath.Join(outside, "shared"), filepath.Join(root, "shared")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	// A cycle back to the root must not be followed forever
+	require.NoError(t, os.Symlink(root, filepath.Join(root, "internal", "loop")))
+
+	t.Run("not followed by default", func(t *testing.T) {
+		manifest := scan.GoPackageDirs(root, scan.DefaultOptions())
+		assert.Equal(t, []string{"internal/provider"}, relDirs(t, root, manifest.Dirs()))
+		reasons := excludedReasons(t, root, manifest)
+		assert.Equal(t, scan.ReasonSymlink, reasons["shared"])
+		assert.Equal(t, scan.ReasonSymlink, reasons["internal/loop"])
+	})
+
+	t.Run("followed with cycle detection", func(t *testing.T) {
+		opts := scan.DefaultOptions()
+		opts.FollowSymlinks = true
+
+		manifest := scan.GoPackageDirs(root, opts)
+		assert.Equal(t, []string{"internal/provider", "shared"}, relDirs(t, root, manifest.Dirs()))
+		assert.Equal(t, scan.ReasonSymlinkCycle, excludedReasons(t, root, manifest)["internal/loop"])
+	})
+}
+
+func TestGeneratedLayouts(t *testing.T) {
+	t.Run("detects a generated layout and finds its directories", func(t *testing.T) {
+		root := t.TempDir()
+		writeScanFixture(t, root, map[string]string{
+			"internal/aws/s3/bucket_resource_gen.go":      "package s3",
+			"internal/aws/s3/bucket_resource_gen_test.go": "package s3",
+			"internal/aws/ec2/vpc/vpc_resource_gen.go":    "package vpc",
+			"internal/provider/provider.go":               "package provider",
+			"internal/generic/generic.go":                 "package generic",
+			"docs/docs.go":                                "package docs",
+		})
+		layout, ok := scan.DetectLayout(root)
+		require.True(t, ok)
+		assert.Equal(t, "awscc", layout.Name)
+
+		dirs, err := scan.ResourceDirs(root, layout.ResourceDirGlobs, scan.DefaultOptions())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"internal/aws/ec2/vpc", "internal/aws/s3", "internal/provider"}, relDirs(t, root, dirs))
+	})
+
+	t.Run("conventional provider has no layout", func(t *testing.T) {
+		root := t.TempDir()
+		writeScanFixture(t, root, map[string]string{"internal/provider/resource_widget.go": "package provider"})
+		_, ok := scan.DetectLayout(root)
+		assert.False(t, ok)
+	})
+
+	t.Run("explicit globs", func(t *testing.T) {
+		root := t.TempDir()
+		writeScanFixture(t, root, map[string]string{
+			"gen/services/compute/instance.go":   "package compute",
+			"gen/services/compute/testdata/x.go": "package testdata",
+			"gen/models/model.go":                "package models",
+		})
+		dirs, err := scan.ResourceDirs(root, []string{"gen/services/*"}, scan.DefaultOptions())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"gen/services/compute"}, relDirs(t, root, dirs))
+
+		_, err = scan.ResourceDirs(root, []string{"./"}, scan.DefaultOptions())
+		assert.ErrorContains(t, err, "resource-dir-globs: empty pattern")
+	})
+}
@@ -0,0 +1,90 @@
+package tfprovidertest
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	goanalysis "golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+func TestInResourceScope(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		provider string
+		name     string
+		want     bool
+	}{
+		{[]string{"s3_*"}, "", "s3_bucket", true},
+		{[]string{"aws_s3_*"}, "aws", "s3_bucket", true},
+		{[]string{"aws_s3_*"}, "aws", "ec2_instance", false},
+		{[]string{"aws_s3_*"}, "", "s3_bucket", true},
+		{[]string{"aws_s3_*"}, "google", "s3_bucket", false},
+		{[]string{"*_s3_*"}, "", "s3_bucket", false},
+		{[]string{"ec2_*", "s3_bucket"}, "", "s3_bucket", true},
+		{[]string{"s3_bucket_?"}, "", "s3_bucket_policy", false},
+	}
+	for _, tt := range tests {
+		got := discovery.InResourceScope(tt.patterns, tt.provider, tt.name)
+		assert.Equal(t, tt.want, got, "%v, provider %q, %s", tt.patterns, tt.provider, tt.name)
+	}
+}
+
+func TestResourceScopeRestrictsAnalyzers(t *testing.T) {
+	sources := map[string]string{
+		"/provider/resource_gadget.go":      untestedGadgetResourceSrc,
+		"/provider/resource_gadget_test.go": gadgetResourceTestSrc,
+		"/provider/resource_sprocket.go":    untestedSprocketResourceSrc,
+	}
+	settings := config.DefaultSettings()
+	messages := runAnalyzerOnSources(t, analysis.RunBasicTestAnalyzer, settings, sources)
+	require.Len(t, messages, 1)
+	assert.Contains(t, messages[0], "'sprocket'")
+
+	settings.Resources = []string{"example_gadget*"}
+	messages = runAnalyzerOnSources(t, analysis.RunBasicTestAnalyzer, settings, sources)
+	assert.Empty(t, messages, "sprocket is out of scope")
+
+	settings.Resources = []string{"sprocket"}
+	messages = runAnalyzerOnSources(t, analysis.RunBasicTestAnalyzer, settings, sources)
+	require.Len(t, messages, 1)
+	assert.Contains(t, messages[0], "'sprocket'")
+}
+
+func TestResourceScopeRemovesOtherTests(t *testing.T) {
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for name, src := range map[string]string{
+		"/provider/resource_gadget.go":      untestedGadgetResourceSrc,
+		"/provider/resource_gadget_test.go": gadgetResourceTestSrc,
+		"/provider/resource_sprocket.go":    untestedSprocketResourceSrc,
+	} {
+		f, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+		require.NoError(t, err)
+		files = append(files, f)
+	}
+	settings := config.DefaultSettings()
+	settings.Resources = []string{"sprocket"}
+	reg := discovery.BuildRegistry(&goanalysis.Pass{Fset: fset, Files: files}, settings)
+
+	require.Len(t, reg.GetAllDefinitions(), 1)
+	assert.NotNil(t, reg.GetResourceByFile("/provider/resource_sprocket.go"))
+	assert.Nil(t, reg.GetResourceByFile("/provider/resource_gadget.go"))
+	assert.Empty(t, reg.GetAllTestFunctions(), "the gadget test is removed with gadget rather than left as an orphan")
+}
+
+func TestResourceScopeSettingValidation(t *testing.T) {
+	settings := config.DefaultSettings()
+	settings.Resources = []string{"aws_s3_*"}
+	require.NoError(t, settings.Validate())
+
+	settings.Resources = []string{"aws_[s3"}
+	assert.ErrorContains(t, settings.Validate(), "resources: invalid pattern")
+}
@@ -0,0 +1,125 @@
+package tfprovidertest
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const sdkv2WidgetSrc = `package provider
+
+import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+func resourceWidget() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceWidgetCreate,
+		ReadContext:   resourceWidgetRead,
+		UpdateContext: resourceWidgetUpdate,
+		DeleteContext: resourceWidgetDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"description": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateDescription,
+			},
+		},
+	}
+}
+`
+
+const sdkv2ImmutableSrc = `package provider
+
+import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+func resourceImmutable() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceImmutableCreate,
+		Read:   resourceImmutableRead,
+		Update: nil,
+		Delete: resourceImmutableDelete,
+		Schema: map[string]*schema.Schema{
+			"content": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+	}
+}
+`
+
+const sdkv2ImmutableTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccImmutable_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{Config: testAccImmutableConfig},
+		},
+	})
+}
+`
+
+func TestSDKv2ResourceDiscovery(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "resource_widget.go", sdkv2WidgetSrc, parser.ParseComments)
+	require.NoError(t, err)
+
+	resources := discovery.ParseResources(file, fset, "resource_widget.go")
+	require.Len(t, resources, 1)
+	widget := resources[0]
+
+	assert.Equal(t, registry.KindResource, widget.Kind)
+	assert.True(t, widget.HasImportState, "Importer implies import support")
+	assert.True(t, widget.ImportStatePos.IsValid())
+	require.NotNil(t, widget.Operations)
+	assert.Equal(t, registry.Operations{Create: true, Read: true, Update: true, Delete: true}, *widget.Operations)
+
+	attrs := make(map[string]registry.AttributeInfo)
+	for _, attr := range widget.Attributes {
+		attrs[attr.Name] = attr
+	}
+	require.Len(t, attrs, 2)
+	assert.False(t, attrs["name"].IsUpdatable, "ForceNew attributes are not updatable")
+	assert.True(t, attrs["name"].Required)
+	assert.True(t, attrs["description"].IsUpdatable)
+	assert.True(t, attrs["description"].HasValidators, "ValidateFunc counts as a validator")
+}
+
+func TestSDKv2ResourceWithoutUpdate(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "resource_immutable.go", sdkv2ImmutableSrc, parser.ParseComments)
+	require.NoError(t, err)
+
+	resources := discovery.ParseResources(file, fset, "resource_immutable.go")
+	require.Len(t, resources, 1)
+	require.NotNil(t, resources[0].Operations)
+	assert.False(t, resources[0].Operations.Update, "Update: nil is not an update function")
+	assert.False(t, resources[0].HasImportState)
+
+	diags := runAnalyzerOnSources(t, analysis.RunUpdateTestAnalyzer, config.DefaultSettings(), map[string]string{
+		"resource_immutable.go":      sdkv2ImmutableSrc,
+		"resource_immutable_test.go": sdkv2ImmutableTestSrc,
+	})
+	assert.Empty(t, diags, "no update test is expected without an Update function")
+}
@@ -3,8 +3,10 @@
 package tfprovidertest
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/example/tfprovidertest/internal/rules"
 	"github.com/example/tfprovidertest/pkg/config"
 )
 
@@ -205,6 +207,8 @@ func TestSettingsValidate_AtLeastOneAnalyzerEnabled(t *testing.T) {
 		settings.EnableUpdateTest = false
 		settings.EnableImportTest = false
 		settings.EnableErrorTest = false
+		settings.EnableRequirementsCheck = false
+		settings.EnableDeferredActionsTest = false
 		settings.EnableStateCheck = false
 
 		err := settings.Validate()
@@ -244,6 +248,42 @@ func TestSettingsValidate_AtLeastOneAnalyzerEnabled(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("every rule in the catalogue counts", func(t *testing.T) {
+		for _, rule := range rules.All() {
+			if _, _, ok := config.RuleToggle(rule.Name); !ok {
+				continue
+			}
+			settings, err := config.NewSettings(config.WithAnalyzers(rule.Name))
+			if err != nil {
+				t.Errorf("Validate() returned error when only %s is enabled: %v", rule.Name, err)
+				continue
+			}
+			if !settings.DriftCheckEnabled() {
+				t.Errorf("DriftCheckEnabled() should be true when only %s is enabled", rule.Name)
+			}
+		}
+	})
+
+	t.Run("rule groups disabled should fail naming every toggle", func(t *testing.T) {
+		disabled := false
+		settings := config.DefaultSettings()
+		settings.EnableCoverageRules = &disabled
+		settings.EnableQualityRules = &disabled
+		if settings.DriftCheckEnabled() {
+			t.Error("DriftCheckEnabled() should be false when both rule groups are disabled")
+		}
+
+		err := settings.Validate()
+		if err == nil {
+			t.Fatal("Validate() should return error when both rule groups are disabled")
+		}
+		for _, rule := range rules.All() {
+			if key, _, ok := config.RuleToggle(rule.Name); ok && !strings.Contains(err.Error(), key+", ") {
+				t.Errorf("Validate() error does not name %s: %v", key, err)
+			}
+		}
+	})
 }
 
 // TestSettingsValidate_FuzzyMatchingThreshold verifies cross-field validation
@@ -281,3 +321,113 @@ func TestSettingsValidate_FuzzyMatchingThreshold(t *testing.T) {
 		}
 	})
 }
+
+func TestProfileSettings(t *testing.T) {
+	minimal, err := config.ProfileSettings(config.ProfileMinimal)
+	if err != nil {
+		t.Fatalf("ProfileSettings(minimal) returned error: %v", err)
+	}
+	if !minimal.BasicTestEnabled() || !minimal.ImportTestEnabled() {
+		t.Error("minimal profile should enable the basic and import test rules")
+	}
+	if minimal.UpdateTestEnabled() || minimal.ErrorTestEnabled() || minimal.StateCheckEnabled() {
+		t.Error("minimal profile should disable the update, error and state check rules")
+	}
+
+	recommended, err := config.ProfileSettings(config.ProfileRecommended)
+	if err != nil {
+		t.Fatalf("ProfileSettings(recommended) returned error: %v", err)
+	}
+	if !recommended.ImportStateVerifyCheckEnabled() {
+		t.Error("recommended profile should enable the import-state-verify rule")
+	}
+
+	strict, err := config.ProfileSettings(config.ProfileStrict)
+	if err != nil {
+		t.Fatalf("ProfileSettings(strict) returned error: %v", err)
+	}
+	if !strict.DeadTestCheckEnabled() || !strict.ProviderConfigTestEnabled() {
+		t.Error("strict profile should enable the opt-in rules")
+	}
+	if err := strict.Validate(); err != nil {
+		t.Errorf("strict profile settings should be valid, got %v", err)
+	}
+
+	defaults, err := config.ProfileSettings("")
+	if err != nil || defaults.EnableImportStateVerifyCheck {
+		t.Errorf("empty profile should return the default settings (err: %v)", err)
+	}
+
+	if _, err := config.ProfileSettings("lenient"); err == nil {
+		t.Error("unknown profile should return an error")
+	}
+	settings := config.DefaultSettings()
+	settings.Profile = "lenient"
+	if err := settings.Validate(); err == nil {
+		t.Error("Validate should reject an unknown profile")
+	}
+}
+
+func TestNewSettings(t *testing.T) {
+	settings, err := config.NewSettings(
+		config.WithProfile(config.ProfileRecommended),
+		config.WithProviderPrefix("Example"),
+		config.WithFuzzyMatching(0.8),
+		config.WithMinTestsForKind("data source", 2),
+	)
+	if err != nil {
+		t.Fatalf("NewSettings returned error: %v", err)
+	}
+	if settings.Profile != config.ProfileRecommended || !settings.ImportStateVerifyCheckEnabled() {
+		t.Error("WithProfile should start from the profile's settings")
+	}
+	if settings.ProviderPrefix != "Example" || !settings.EnableFuzzyMatching || settings.FuzzyMatchThreshold != 0.8 {
+		t.Errorf("options should be applied, got prefix %q, fuzzy %v, threshold %f", settings.ProviderPrefix, settings.EnableFuzzyMatching, settings.FuzzyMatchThreshold)
+	}
+	if got := settings.MinTestsForKind("data-source"); got != 2 {
+		t.Errorf("MinTestsForKind(data-source) = %d, want 2", got)
+	}
+
+	invalid := map[string][]config.Option{
+		"threshold out of range": {config.WithThreshold(1.5)},
+		"unknown analyzer":       {config.WithAnalyzers("tfprovider-coverage-nothing")},
+		"only drift-check":       {config.WithAnalyzers(rules.DriftCheck)},
+		"unknown kind":           {config.WithMinTestsForKind("module", 1)},
+		"invalid after options":  {config.WithLanguage("fr")},
+	}
+	for name, opts := range invalid {
+		if _, err := config.NewSettings(opts...); err == nil {
+			t.Errorf("%s: NewSettings should return an error", name)
+		}
+	}
+}
+
+func TestNewSettings_WithAnalyzers(t *testing.T) {
+	for _, rule := range rules.All() {
+		if rule.Name == rules.DriftCheck || rule.Name == rules.Sweepers {
+			continue
+		}
+		settings, err := config.NewSettings(config.WithProfile(config.ProfileStrict), config.WithAnalyzers(rule.Name))
+		if err != nil {
+			t.Fatalf("WithAnalyzers(%s) returned error: %v", rule.Name, err)
+		}
+		analyzers, err := NewWithSettings(settings).BuildAnalyzers()
+		if err != nil {
+			t.Fatalf("BuildAnalyzers returned error: %v", err)
+		}
+		var names []string
+		for _, analyzer := range analyzers {
+			if analyzer.Name != rules.DriftCheck && analyzer.Name != rules.Sweepers {
+				names = append(names, analyzer.Name)
+			}
+		}
+		if len(names) != 1 || names[0] != rule.Name {
+			t.Errorf("WithAnalyzers(%s) built %v", rule.Name, names)
+		}
+	}
+
+	settings, err := config.NewSettings(config.WithAnalyzers("tfprovider-resource-import-test"))
+	if err != nil || !settings.ImportTestEnabled() || settings.BasicTestEnabled() {
+		t.Errorf("WithAnalyzers should accept legacy names (err: %v)", err)
+	}
+}
@@ -0,0 +1,72 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	goanalysis "golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const showGadgetTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccGadget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		CheckDestroy: testAccCheckGadgetDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: ` + "`" + `resource "example_gadget" "test" {}` + "`" + `,
+				Check:  resource.TestCheckResourceAttr("example_gadget.test", "name", "a"),
+			},
+			{ResourceName: "example_gadget.test", ImportState: true, ImportStateVerify: true},
+		},
+	})
+}
+`
+
+func TestDescribe(t *testing.T) {
+	names := []string{"/provider/resource_gadget.go", "/provider/resource_gadget_test.go"}
+	fset, files := parseSources(t, names, map[string]string{
+		"/provider/resource_gadget.go":      untestedGadgetResourceSrc,
+		"/provider/resource_gadget_test.go": showGadgetTestSrc,
+	})
+	settings := config.DefaultSettings()
+	reg := discovery.BuildRegistry(&goanalysis.Pass{Fset: fset, Files: files}, settings)
+
+	details := analysis.Describe("example_gadget", reg, fset, &settings)
+	require.Len(t, details, 1)
+	d := details[0]
+	assert.Equal(t, "gadget", d.Name)
+	assert.Equal(t, "resource", d.Kind)
+
+	require.Len(t, d.Tests, 1)
+	test := d.Tests[0]
+	assert.Equal(t, "TestAccGadget_basic", test.Test)
+	assert.NotEmpty(t, test.MatchType, "the match provenance is kept")
+	assert.True(t, test.CheckDestroy)
+	require.Len(t, test.Steps, 2)
+	assert.Equal(t, "config, check", test.Steps[0].Summary)
+	assert.Equal(t, "import, verify", test.Steps[1].Summary)
+
+	covered := make(map[string]bool)
+	for _, check := range d.Checks {
+		covered[check.Check] = check.Covered
+	}
+	assert.True(t, covered["import"])
+	assert.True(t, covered["drift"])
+	assert.False(t, covered["error"])
+	assert.Contains(t, d.NextTests, "TestAccGadget_invalid: a step with an invalid config and ExpectError")
+	assert.NotContains(t, d.NextTests, "Set CheckDestroy on a test in resource_gadget_test.go")
+
+	assert.Empty(t, analysis.Describe("sprocket", reg, fset, &settings))
+}
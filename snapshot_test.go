@@ -0,0 +1,171 @@
+package tfprovidertest
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// updateSnapshotsEnv rewrites the golden files instead of comparing against them when set to
+// a non-empty value: UPDATE_SNAPSHOTS=1 go test -run TestOutputSnapshots .
+const updateSnapshotsEnv = "UPDATE_SNAPSHOTS"
+
+// snapshotDir holds one golden file per output snapshot.
+const snapshotDir = "testdata/golden"
+
+// elapsedPattern matches the phase timings of the run health in JSON output, which differ on
+// every run and are pinned to 0 in golden files.
+var elapsedPattern = regexp.MustCompile(`("elapsed_ms": ?)[0-9.e+-]+`)
+
+// outputSnapshots are the validate invocations whose output is pinned, run from testdata/src
+// against the testlintdata fixture provider. Add a case here with every new output format.
+var outputSnapshots = []struct {
+	name string
+	args []string
+}{
+	{"analyze.txt", nil},
+	{"analyze-short.txt", []string{"-message-style", "short"}},
+	{"analyze.json", []string{"-format", "json"}},
+	{"analyze.ndjson", []string{"-format", "ndjson"}},
+	{"analyze.codeclimate.json", []string{"-format", "codeclimate"}},
+	{"analyze-gate.txt", []string{"-warn-under", "95%", "-fail-under", "50%"}},
+	{"matches-table.txt", []string{"-show-matches", "-format", "table"}},
+	{"matches.json", []string{"-show-matches", "-format", "json"}},
+	{"report.txt", []string{"-report"}},
+	{"report-narrow.txt", []string{"-report", "-width", "60", "-no-unicode", "-max-rows", "5"}},
+	{"report.json", []string{"-report", "-format", "json"}},
+	{"report.csv", []string{"-report", "-format", "csv"}},
+	{"report-categories.txt", []string{"-report", "-group-by", "category", "-columns", "category", "-category", "basic_passing,error_passing,uncategorized"}},
+	{"show.txt", []string{"show", "widget"}},
+	{"list-rules.txt", []string{"-list-rules"}},
+}
+
+// settingsSnapshots are validate runs with a settings file from testdata/settings, pinning
+// that the settings only a settings file can set reach the analyzers. Provider names the
+// fixture provider under testdata/src.
+var settingsSnapshots = []struct {
+	name     string
+	provider string
+	settings string
+}{
+	{"analyze-settings-file.txt", "testlintdata", "fail-on-weak-tests.yaml"},
+	{"analyze-check-destroy-requires-delete.txt", "testlintdata", "check-destroy-requires-delete.yaml"},
+	{"analyze-provider-alias-resources.txt", "testlintdata", "provider-alias-resources.yaml"},
+	{"analyze-file-roles.txt", "testlintdata", "file-roles.yaml"},
+	{"analyze-name-replacements.txt", "namingdata", "name-replacements.yaml"},
+}
+
+// summarySnapshots are the Markdown summaries cmd/action writes for the job summary and the
+// pull request comment, over the testlintdata fixture provider.
+var summarySnapshots = []struct {
+	name string
+	args []string
+}{
+	{"action-summary.md", nil},
+	{"action-summary-base.md", []string{"-base-report", filepath.Join("..", "golden", "report.json")}},
+	{"action-summary-links.md", []string{"-args", "-recursive -repo-url-template https://github.com/example/terraform-provider-example/blob/main/{path}#L{line}"}},
+}
+
+func TestOutputSnapshots(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the validate command")
+	}
+	binary := buildValidate(t)
+
+	for _, tt := range outputSnapshots {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"-provider", "testlintdata", "-recursive"}, tt.args...)
+			assertSnapshot(t, filepath.Join(snapshotDir, tt.name), runValidate(t, binary, args...))
+		})
+	}
+	for _, tt := range settingsSnapshots {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := filepath.Join("..", "settings", tt.settings)
+			got := runValidate(t, binary, "-provider", tt.provider, "-recursive", "-config", settings)
+			assertSnapshot(t, filepath.Join(snapshotDir, tt.name), got)
+		})
+	}
+
+	action := buildCommand(t, "action")
+	for _, tt := range summarySnapshots {
+		t.Run(tt.name, func(t *testing.T) {
+			// Empty flags keep the GitHub Actions environment of a CI run from posting a
+			// comment, writing files or scanning the base branch.
+			args := append([]string{"-provider", "testlintdata", "-validate", binary, "-args", "-recursive",
+				"-comment=false", "-summary=", "-output=", "-base-ref="}, tt.args...)
+			assertSnapshot(t, filepath.Join(snapshotDir, tt.name), runValidate(t, action, args...))
+		})
+	}
+}
+
+// buildValidate builds the validate command into a temporary directory and returns its path.
+func buildValidate(t *testing.T) string {
+	t.Helper()
+	return buildCommand(t, "validate")
+}
+
+// buildCommand builds the command under cmd/name into a temporary directory and returns its
+// path.
+func buildCommand(t *testing.T, name string) string {
+	t.Helper()
+	binary := filepath.Join(t.TempDir(), name)
+	out, err := exec.Command("go", "build", "-o", binary, "./cmd/"+name).CombinedOutput()
+	require.NoError(t, err, "building %s: %s", name, out)
+	return binary
+}
+
+// runValidate runs validate, or another command that runs it, from testdata/src and returns
+// its output, with phase timings pinned to 0.
+func runValidate(t *testing.T, binary string, args ...string) []byte {
+	t.Helper()
+	cmd := exec.Command(binary, args...)
+	cmd.Dir = filepath.Join("testdata", "src")
+	// Tables fit $COLUMNS when it is set; pin them to unlimited width. Runs are recorded
+	// for -rerun-failed in the cache directory, so keep them out of the user's cache
+	// directory.
+	cmd.Env = append(os.Environ(), "COLUMNS=", "XDG_CACHE_HOME="+t.TempDir())
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	got, err := cmd.Output()
+	require.NoError(t, err, "stderr: %s", stderr.String())
+	return elapsedPattern.ReplaceAll(got, []byte("${1}0"))
+}
+
+// TestRulesReference checks that docs/rules matches what cmd/rulesdoc generates from the rule
+// catalogue, or regenerates it when UPDATE_SNAPSHOTS is set.
+func TestRulesReference(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the rulesdoc command")
+	}
+	args := []string{"run", "./cmd/rulesdoc"}
+	if os.Getenv(updateSnapshotsEnv) == "" {
+		args = append(args, "-check")
+	}
+	out, err := exec.Command("go", args...).CombinedOutput()
+	require.NoError(t, err, "%s", out)
+}
+
+// assertSnapshot compares output with its golden file, or writes the file when
+// UPDATE_SNAPSHOTS is set. A missing golden file fails with instructions to create it.
+func assertSnapshot(t *testing.T, path string, got []byte) {
+	t.Helper()
+	if os.Getenv(updateSnapshotsEnv) != "" {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, got, 0o644))
+		return
+	}
+	want, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("no snapshot %s; run %s=1 go test -run %s . to create it", path, updateSnapshotsEnv, strings.Split(t.Name(), "/")[0])
+	}
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got), "output differs from %s; if the change is intended, run %s=1 go test -run %s . and review the diff", path, updateSnapshotsEnv, strings.Split(t.Name(), "/")[0])
+}
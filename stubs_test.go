@@ -0,0 +1,77 @@
+package tfprovidertest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/internal/stubs"
+)
+
+func TestGenerateStubs(t *testing.T) {
+	dir := t.TempDir()
+	sources := map[string]string{
+		"resource_gadget.go":   untestedGadgetResourceSrc,
+		"resource_sprocket.go": untestedSprocketResourceSrc,
+	}
+	_, reg := annotateFixture(t, dir, sources)
+	stubPath := filepath.Join(dir, "resource_gadget_test.go")
+	packages := map[string]string{filepath.Join(dir, "resource_gadget.go"): "provider"}
+
+	generated, err := stubs.Generate(reg, packages, stubs.Options{File: "resource_gadget.go"})
+	require.NoError(t, err)
+	require.Len(t, generated, 1, "only the definitions of the current file are stubbed")
+	assert.Equal(t, stubs.Stub{Definition: "resource:gadget", Test: "TestAccGadget_basic", File: stubPath, Status: stubs.StatusCreated}, generated[0])
+
+	content, err := os.ReadFile(stubPath)
+	require.NoError(t, err)
+	stub := string(content)
+	assert.True(t, strings.HasPrefix(stub, stubs.Marker+"\n\npackage provider\n"))
+	assert.Contains(t, stub, "func TestAccGadget_basic(t *testing.T) {\n\tt.Skip(")
+	assert.Contains(t, stub, "Config: `resource \"gadget\" \"test\" {}`")
+
+	t.Run("stubs are linked without counting as coverage", func(t *testing.T) {
+		sources["resource_gadget_test.go"] = stub
+		_, reg := annotateFixture(t, dir, sources)
+		assert.Empty(t, reg.GetTests(registry.KindResource, "gadget"))
+		assert.Len(t, reg.GetQuarantinedTests(registry.KindResource, "gadget"), 1)
+
+		generated, err := stubs.Generate(reg, packages, stubs.Options{File: "resource_gadget.go"})
+		require.NoError(t, err)
+		require.Len(t, generated, 1)
+		assert.Equal(t, stubs.StatusUnchanged, generated[0].Status, "output is stable")
+	})
+
+	t.Run("owned test files are never overwritten", func(t *testing.T) {
+		owned := strings.TrimPrefix(stub, stubs.Marker+"\n")
+		sources["resource_gadget_test.go"] = owned
+		_, reg := annotateFixture(t, dir, sources)
+
+		generated, err := stubs.Generate(reg, packages, stubs.Options{Names: map[string]bool{"gadget": true}})
+		require.NoError(t, err)
+		require.Len(t, generated, 1)
+		assert.Equal(t, stubs.StatusSkipped, generated[0].Status)
+		assert.Equal(t, "test file exists", generated[0].Reason)
+
+		content, err := os.ReadFile(stubPath)
+		require.NoError(t, err)
+		assert.Equal(t, owned, string(content))
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		generated, err := stubs.Generate(reg, packages, stubs.Options{File: "resource_sprocket.go", ProviderTypeName: "example", DryRun: true})
+		require.NoError(t, err)
+		require.Len(t, generated, 1)
+		assert.Equal(t, stubs.StatusCreated, generated[0].Status)
+		assert.NoFileExists(t, filepath.Join(dir, "resource_sprocket_test.go"))
+
+		src, err := stubs.Source(reg.GetDefinition(registry.KindResource, "sprocket"), "provider", "example")
+		require.NoError(t, err)
+		assert.Contains(t, string(src), "Config: `resource \"example_sprocket\" \"test\" {}`")
+	})
+}
@@ -0,0 +1,130 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+const gadgetSubtestsSrc = `package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccGadget_scenarios(t *testing.T) {
+	t.Run("basic", func(t *testing.T) {
+		resource.Test(t, resource.TestCase{
+			Steps: []resource.TestStep{{
+				Config: ` + "`" + `resource "example_gadget" "g" {}` + "`" + `,
+				Check:  resource.TestCheckResourceAttrSet("example_gadget.g", "id"),
+			}},
+		})
+	})
+	t.Run("with tags", func(t *testing.T) {
+		resource.Test(t, resource.TestCase{
+			Steps: []resource.TestStep{
+				{Config: ` + "`" + `resource "example_gadget" "g" { tags = {} }` + "`" + `},
+				{ResourceName: "example_gadget.g", ImportState: true, ImportStateVerify: true},
+			},
+		})
+	})
+	t.Run("notes", func(t *testing.T) {
+		t.Log("no TestCase, not a scenario")
+	})
+}
+
+func TestAccGadget_table(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+	}{
+		{name: "small", size: 1},
+		{name: "large", size: 100},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resource.Test(t, resource.TestCase{
+				Steps: []resource.TestStep{{
+					Config: fmt.Sprintf(` + "`" + `resource "example_gadget" "g" { size = %d }` + "`" + `, tc.size),
+				}},
+			})
+		})
+	}
+}
+
+func TestAccGadget_grouped(t *testing.T) {
+	t.Run("network", func(t *testing.T) {
+		for name, size := range map[string]int{"private": 1, "public": 2} {
+			t.Run(name, func(t *testing.T) {
+				resource.Test(t, resource.TestCase{
+					Steps: []resource.TestStep{{
+						Config: fmt.Sprintf(` + "`" + `resource "example_gadget" "g" { size = %d }` + "`" + `, size),
+					}},
+				})
+			})
+		}
+		t.Run(fmt.Sprintf("size-%d", 3), func(t *testing.T) {
+			resource.Test(t, resource.TestCase{
+				Steps: []resource.TestStep{{
+					Config: ` + "`" + `resource "example_gadget" "g" { size = 3 }` + "`" + `,
+				}},
+			})
+		})
+	})
+}
+`
+
+func TestSubtests(t *testing.T) {
+	reg := buildRegistryFromSources(t, map[string]string{
+		"/provider/resource_gadget.go":      untestedGadgetResourceSrc,
+		"/provider/resource_gadget_test.go": gadgetSubtestsSrc,
+	})
+
+	subtestNames := func(test *registry.TestFunctionInfo) []string {
+		var names []string
+		for _, subtest := range test.Subtests {
+			names = append(names, subtest.Name)
+		}
+		return names
+	}
+
+	t.Run("each t.Run with a TestCase of its own is a scenario", func(t *testing.T) {
+		test := findTest(t, reg, "TestAccGadget_scenarios")
+		assert.Equal(t, []string{"basic", "with_tags"}, subtestNames(test))
+		assert.Equal(t, 2, test.Scenarios())
+		require.Len(t, test.TestSteps, 3)
+
+		require.Len(t, test.Subtests[0].Steps, 1)
+		assert.Equal(t, 1, test.Subtests[0].Steps[0].StepNumber)
+		assert.True(t, test.Subtests[0].Steps[0].HasCheck)
+		require.Len(t, test.Subtests[1].Steps, 2)
+		assert.Equal(t, []int{2, 3}, []int{test.Subtests[1].Steps[0].StepNumber, test.Subtests[1].Steps[1].StepNumber})
+		assert.True(t, test.Subtests[1].Steps[1].ImportState)
+	})
+
+	t.Run("table-driven subtests are listed once per row", func(t *testing.T) {
+		test := findTest(t, reg, "TestAccGadget_table")
+		assert.Equal(t, []string{"small", "large"}, subtestNames(test))
+		assert.Equal(t, test.Subtests[0].Pos, test.Subtests[1].Pos)
+		assert.Len(t, test.Subtests[1].Steps, 1)
+	})
+
+	t.Run("nested subtests are named by their path and unknown names are kept", func(t *testing.T) {
+		test := findTest(t, reg, "TestAccGadget_grouped")
+		assert.ElementsMatch(t, []string{"network/private", "network/public", ""}, subtestNames(test))
+		assert.Equal(t, 3, test.Scenarios())
+	})
+
+	t.Run("scenarios are counted per definition", func(t *testing.T) {
+		tests := reg.GetTests(registry.KindResource, "gadget")
+		require.Len(t, tests, 3)
+		assert.Equal(t, 7, registry.ScenarioCount(tests))
+	})
+}
@@ -0,0 +1,78 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const helperMisuseTestSrc = `package provider
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccGadget_basic(t *testing.T) {
+	id := testAccCreateFixture(t)
+	testAccWaitForFixtures(t, id)
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: ` + "`" + `resource "example_gadget" "g" {}` + "`" + `}},
+	})
+}
+
+func TestAccGadget_update(t *testing.T) {
+	testAccCreateFixture(t)
+}
+
+func testAccCreateFixture(t *testing.T) string {
+	t.Helper()
+	id, _ := createFixture()
+	_, _ = fmt.Println("created", id)
+	return id
+}
+
+func testAccWaitForFixtures(tb testing.TB, ids ...string) {
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := waitFor(id); err != nil {
+				tb.Fatalf("waiting for %s: %s", id, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func testAccCheckedFixture(t *testing.T) string {
+	id, err := createFixture()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+`
+
+func TestTestHelpersAnalyzer(t *testing.T) {
+	settings := config.DefaultSettings()
+	settings.EnableTestHelperCheck = true
+
+	messages := runAnalyzerOnSources(t, analysis.RunTestHelpersAnalyzer, settings, map[string]string{
+		"/provider/resource_gadget_test.go": helperMisuseTestSrc,
+	})
+	require.Len(t, messages, 2)
+	assert.Contains(t, messages[0], "test helper 'testAccCreateFixture' discards the error returned by createFixture")
+	assert.Contains(t, messages[0], "Helper: /provider/resource_gadget_test.go:25")
+	assert.Contains(t, messages[0], "Used by: 2 test function(s)")
+	assert.Contains(t, messages[1], "test helper 'testAccWaitForFixtures' calls tb.Fatalf from a goroutine")
+	assert.Contains(t, messages[1], "Used by: 1 test function(s)")
+}
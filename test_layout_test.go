@@ -0,0 +1,90 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const layoutWidgetTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccWidget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: ` + "`" + `resource "example_widget" "test" { name = "a" }` + "`" + `}},
+	})
+}
+`
+
+const layoutGadgetTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccGadget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: ` + "`" + `resource "example_gadget" "test" { name = "a" }` + "`" + `}},
+	})
+}
+`
+
+func TestTestLayoutAnalyzer(t *testing.T) {
+	settings := config.DefaultSettings()
+	settings.EnableTestLayoutCheck = true
+
+	colocated := map[string]string{
+		"/provider/internal/widget/resource_widget.go":      upgradeWidgetResourceSrc,
+		"/provider/internal/gadget/resource_gadget.go":      untestedGadgetResourceSrc,
+		"/provider/internal/gadget/resource_gadget_test.go": layoutGadgetTestSrc,
+		"/provider/internal/acctest/widget_test.go":         layoutWidgetTestSrc,
+	}
+	centralized := map[string]string{
+		"/provider/internal/widget/resource_widget.go": upgradeWidgetResourceSrc,
+		"/provider/internal/acctest/widget_test.go":    layoutWidgetTestSrc,
+	}
+
+	t.Run("detection", func(t *testing.T) {
+		assert.Equal(t, config.TestLayoutColocated, analysis.DetectTestLayout(buildRegistryFromSources(t, colocated)),
+			"a tie is co-located")
+		assert.Equal(t, config.TestLayoutCentralized, analysis.DetectTestLayout(buildRegistryFromSources(t, centralized)))
+	})
+
+	t.Run("test outside the directory of its resource", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunTestLayoutAnalyzer, settings, colocated)
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "test 'TestAccWidget_basic' is for resource 'widget' but is in /provider/internal/acctest, not next to it in /provider/internal/widget")
+		assert.Contains(t, messages[0], "Layout: co-located")
+		assert.Contains(t, messages[0], "Move the test to /provider/internal/widget/resource_widget_test.go")
+	})
+
+	t.Run("centralized layout", func(t *testing.T) {
+		assert.Empty(t, runAnalyzerOnSources(t, analysis.RunTestLayoutAnalyzer, settings, centralized),
+			"the detected layout is centralized")
+
+		explicit := settings
+		explicit.TestLayout = config.TestLayoutCentralized
+		assert.Empty(t, runAnalyzerOnSources(t, analysis.RunTestLayoutAnalyzer, explicit, colocated))
+
+		explicit.TestLayout = config.TestLayoutColocated
+		assert.Len(t, runAnalyzerOnSources(t, analysis.RunTestLayoutAnalyzer, explicit, centralized), 1)
+	})
+
+	t.Run("settings", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		assert.False(t, settings.TestLayoutCheckEnabled(), "the rule is opt-in")
+		settings.TestLayout = "flat"
+		assert.ErrorContains(t, settings.Validate(), `unknown test-layout "flat"`)
+	})
+}
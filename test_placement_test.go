@@ -0,0 +1,75 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const placementGadgetSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+type GadgetResource struct{}
+
+func (r *GadgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{}
+}
+`
+
+const placementTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccWidget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: "resource \"example_widget\" \"test\" {}"}},
+	})
+}
+
+func TestAccGadget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: "resource \"example_gadget\" \"test\" {}"}},
+	})
+}
+`
+
+func TestTestPlacementAnalyzer(t *testing.T) {
+	settings := config.DefaultSettings()
+	settings.EnableTestPlacementCheck = true
+
+	t.Run("test in another resource's test file", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunTestPlacementAnalyzer, settings, map[string]string{
+			"/provider/resource_widget.go":      directiveResourceSrc,
+			"/provider/resource_gadget.go":      placementGadgetSrc,
+			"/provider/resource_widget_test.go": placementTestSrc,
+		})
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "test 'TestAccGadget_basic' is for resource 'gadget' but is in resource_widget_test.go, the test file of 'widget'")
+		assert.Contains(t, messages[0], "1 of 2 tests in resource_widget_test.go are for other definitions")
+		assert.Contains(t, messages[0], "Move the test to /provider/resource_gadget_test.go")
+	})
+
+	t.Run("files not named for a definition are not checked", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunTestPlacementAnalyzer, settings, map[string]string{
+			"/provider/resource_widget.go":      directiveResourceSrc,
+			"/provider/resource_gadget.go":      placementGadgetSrc,
+			"/provider/resource_shared_test.go": placementTestSrc,
+			"/provider/provider_test.go":        "package provider\n",
+		})
+		assert.Empty(t, messages)
+	})
+}
@@ -0,0 +1,39 @@
+package namingdata
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// Only the function names can link these tests: their file is not named after a resource and
+// their configs are not known statically.
+
+// EventStream splits into event_stream unless name-replacements maps it to eventstream
+func TestAccEventStream_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: os.Getenv("EVENTSTREAM_CONFIG"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("example_eventstream.test", "id"),
+				),
+			},
+		},
+	})
+}
+
+// OAuth splits into o_auth unless name-acronyms keeps it whole
+func TestAccOAuthClient_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: os.Getenv("OAUTH_CLIENT_CONFIG"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("example_oauth_client.test", "id"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,97 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/registry"
+)
+
+const builderWidgetTestSrc = `package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/example/terraform-provider-example/internal/acceptance"
+)
+
+type WidgetTestResource struct{}
+
+func TestAccStorage_basic(t *testing.T) {
+	data := acceptance.BuildTestData(t, "example_widget", "test")
+	r := WidgetTestResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.basic(data),
+		},
+		data.ImportStep(),
+		data.RequiresImportErrorStep(r.requiresImport),
+	})
+}
+
+func TestAccStorage_sequential(t *testing.T) {
+	data := acceptance.BuildTestData(t, "example_widget", "test")
+	r := WidgetTestResource{}
+	steps := []acceptance.TestStep{
+		{Config: r.basic(data)},
+		{Config: r.complete(data)},
+	}
+	data.ResourceTestSkipCheckDestroy(t, steps)
+}
+
+func TestAccLookup_basic(t *testing.T) {
+	data := acceptance.BuildTestData(t, "data.example_gadget", "test")
+	data.DataSourceTest(t, []acceptance.TestStep{{Config: gadgetConfig(data)}})
+}
+
+func (WidgetTestResource) basic(data acceptance.TestData) string {
+	return fmt.Sprintf(` + "`" + `resource "example_widget" "test" { name = "acctest-%d" }` + "`" + `, data.RandomInteger)
+}
+`
+
+func TestTestDataBuilders(t *testing.T) {
+	reg := buildRegistryFromSources(t, map[string]string{
+		"/provider/resource_widget.go":      upgradeWidgetResourceSrc,
+		"/provider/data_source_gadget.go":   gadgetDataSourceSrc,
+		"/provider/resource_widget_test.go": builderWidgetTestSrc,
+	})
+
+	t.Run("steps passed to the runner", func(t *testing.T) {
+		fn := findTest(t, reg, "TestAccStorage_basic")
+		assert.True(t, fn.HasCheckDestroy, "ResourceTest checks destroy")
+		assert.True(t, fn.HasPreCheck)
+		assert.True(t, fn.HasImportStep)
+		assert.True(t, fn.HasErrorCase)
+		assert.Contains(t, fn.InferredHCLBlocks, registry.InferredHCLBlock{BlockType: "resource", ResourceType: "example_widget"})
+
+		require.Len(t, fn.TestSteps, 3)
+		assert.True(t, fn.TestSteps[1].ImportState)
+		assert.True(t, fn.TestSteps[1].ImportStateVerify)
+		assert.True(t, fn.TestSteps[2].ExpectError)
+		assert.Equal(t, registry.ErrorCategoryConflict, fn.TestSteps[2].ErrorCategory)
+	})
+
+	t.Run("steps in a variable", func(t *testing.T) {
+		fn := findTest(t, reg, "TestAccStorage_sequential")
+		assert.False(t, fn.HasCheckDestroy, "the runner skips the destroy check")
+		require.Len(t, fn.TestSteps, 2)
+		assert.True(t, fn.TestSteps[1].IsUpdateStepFlag)
+	})
+
+	t.Run("tests credited to the built definitions", func(t *testing.T) {
+		var widgetTests []string
+		for _, fn := range reg.GetTests(registry.KindResource, "widget") {
+			widgetTests = append(widgetTests, fn.Name)
+		}
+		assert.ElementsMatch(t, []string{"TestAccStorage_basic", "TestAccStorage_sequential"}, widgetTests)
+
+		gadgetTests := reg.GetTests(registry.KindDataSource, "gadget")
+		require.Len(t, gadgetTests, 1)
+		assert.Equal(t, "TestAccLookup_basic", gadgetTests[0].Name)
+		assert.False(t, gadgetTests[0].HasCheckDestroy)
+	})
+}
@@ -0,0 +1,71 @@
+package tfprovidertest
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/internal/testlist"
+)
+
+func TestTestListParseOutput(t *testing.T) {
+	out := "TestAccWidget_basic\nTestAccWidget_update\nExampleWidget\nok  \texample.com/provider/internal/provider\t0.012s\n"
+	assert.Equal(t, []string{"TestAccWidget_basic", "TestAccWidget_update", "ExampleWidget"}, testlist.ParseListOutput(out))
+
+	assert.Empty(t, testlist.ParseListOutput("?   \texample.com/provider/internal/provider\t[no test files]\n"))
+}
+
+func TestTestListVerify(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"widget/resource_widget_test.go":             "package widget\n",
+		"widget/resource_widget_integration_test.go": "//go:build integration\n\npackage widget\n",
+		"broken/resource_gadget_test.go":             "package broken\n",
+	})
+	widgetDir := filepath.Join(root, "widget")
+	brokenDir := filepath.Join(root, "broken")
+
+	tests := []*registry.TestFunctionInfo{
+		{Name: "TestAccWidget_basic", FilePath: filepath.Join(widgetDir, "resource_widget_test.go")},
+		{Name: "Testaccwidget_lower", FilePath: filepath.Join(widgetDir, "resource_widget_test.go")},
+		{Name: "TestAccWidget_update", FilePath: filepath.Join(widgetDir, "resource_widget_integration_test.go")},
+		{Name: "TestAccGadget_basic", FilePath: filepath.Join(brokenDir, "resource_gadget_test.go")},
+	}
+	var listedDirs []string
+	list := func(_ context.Context, dir string) ([]string, error) {
+		listedDirs = append(listedDirs, dir)
+		if dir == brokenDir {
+			return nil, errors.New("undefined: resource")
+		}
+		return []string{"TestAccWidget_basic", "TestUnit"}, nil
+	}
+
+	result := testlist.Verify(context.Background(), nil, tests, list, testlist.Options{})
+	assert.Equal(t, []string{brokenDir, widgetDir}, listedDirs, "each package is listed once")
+	assert.Equal(t, 2, result.Packages)
+	assert.Equal(t, 4, result.Discovered)
+	assert.Equal(t, 1, result.Listed)
+
+	reasons := make(map[string]testlist.Reason)
+	for _, d := range result.Discrepancies {
+		reasons[d.Test] = d.Reason
+	}
+	assert.Equal(t, map[string]testlist.Reason{
+		"Testaccwidget_lower":  testlist.ReasonNotTestName,
+		"TestAccWidget_update": testlist.ReasonBuildConstraint,
+		"TestAccGadget_basic":  testlist.ReasonPackageError,
+	}, reasons)
+
+	t.Run("build tags", func(t *testing.T) {
+		result := testlist.Verify(context.Background(), nil, tests[2:3], func(context.Context, string) ([]string, error) {
+			return nil, nil
+		}, testlist.Options{Tags: []string{"integration"}})
+		require.Len(t, result.Discrepancies, 1)
+		assert.Equal(t, testlist.ReasonNotListed, result.Discrepancies[0].Reason)
+	})
+}
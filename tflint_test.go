@@ -0,0 +1,80 @@
+package tfprovidertest_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/rules"
+	"github.com/example/tfprovidertest/pkg/config"
+	"github.com/example/tfprovidertest/pkg/tflint"
+)
+
+// issueRecorder is a tflint.Runner recording the issues emitted to it.
+type issueRecorder struct {
+	rules    []string
+	messages []string
+	ranges   []tflint.Range
+}
+
+func (r *issueRecorder) EmitIssue(rule *tflint.Rule, message string, issueRange tflint.Range) error {
+	r.rules = append(r.rules, rule.Name())
+	r.messages = append(r.messages, message)
+	r.ranges = append(r.ranges, issueRange)
+	return nil
+}
+
+func TestTFLintRuleset(t *testing.T) {
+	analysis.ClearAllRegistryCaches()
+	t.Cleanup(analysis.ClearAllRegistryCaches)
+
+	set, err := tflint.NewRuleset(tflint.Options{
+		ProviderDir: filepath.Join("testdata", "src", "testlintdata"),
+		Recursive:   true,
+		Settings:    config.DefaultSettings(),
+		Version:     "1.2.3",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "tfprovidertest", set.RuleSetName())
+	assert.Equal(t, "1.2.3", set.RuleSetVersion())
+	require.Len(t, set.RuleNames(), len(rules.All()))
+
+	byName := make(map[string]*tflint.Rule)
+	for _, rule := range set.Rules() {
+		byName[rule.Name()] = rule
+	}
+	basic := byName[rules.BasicTest]
+	assert.True(t, basic.Enabled())
+	assert.Equal(t, "TFPT001", basic.Code())
+	assert.Equal(t, tflint.DefaultDocsURL+"tfprovider-coverage-basic-test.md", basic.Link())
+	assert.False(t, byName[rules.PluralDataSources].Enabled(), "opt-in rules are disabled by default")
+
+	var runner issueRecorder
+	require.NoError(t, basic.Check(&runner))
+	require.Len(t, runner.messages, 1)
+	assert.Contains(t, runner.messages[0], "data source 'info' has no acceptance test")
+	assert.Equal(t, "data_source_info.go", filepath.Base(runner.ranges[0].Filename))
+	assert.Equal(t, 16, runner.ranges[0].Start.Line)
+
+	// Every rule reads the same scan, including rules disabled by default
+	first, err := set.Issues()
+	require.NoError(t, err)
+	again, err := set.Issues()
+	require.NoError(t, err)
+	assert.Equal(t, len(first), len(again))
+	assert.Equal(t, len(first[rules.BasicTest]), 1)
+	assert.NotEmpty(t, first[rules.ErrorTest])
+}
+
+func TestTFLintRulesetRejectsInvalidSettings(t *testing.T) {
+	_, err := tflint.NewRuleset(tflint.Options{Settings: config.DefaultSettings()})
+	assert.ErrorContains(t, err, "ProviderDir is required")
+
+	settings := config.DefaultSettings()
+	settings.Resources = []string{"aws_[s3"}
+	_, err = tflint.NewRuleset(tflint.Options{ProviderDir: ".", Settings: settings})
+	assert.ErrorContains(t, err, "resources: invalid pattern")
+}
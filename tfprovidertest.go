@@ -1,12 +1,49 @@
 // Package tfprovidertest implements a golangci-lint plugin that identifies test coverage gaps
 // in Terraform providers built with terraform-plugin-framework.
 //
-// The plugin provides five analyzers that enforce HashiCorp's testing best practices:
+// The plugin provides analyzers in two groups that enforce HashiCorp's testing best practices.
+// Coverage rules (tfprovider-coverage-*) report missing tests:
 //   - Basic Test Coverage: Detects resources without acceptance tests
 //   - Update Test Coverage: Validates multi-step tests for updatable attributes
 //   - Import Test Coverage: Ensures ImportState methods have import tests
 //   - Error Test Coverage: Verifies validation rules have error case tests
+//   - Provider Config Coverage: Verifies provider configuration attributes are exercised (opt-in)
+//   - Requirements: Enforces disappears tests and strict mode of the requirements manifest
+//   - Deferred Actions: Verifies providers that defer changes test with deferral allowed
+//   - Provider Aliases: Verifies cross-provider resources are tested with an aliased provider (opt-in)
+//   - Upgrade Tests: Verifies resources whose schema version changed have an upgrade test (opt-in)
+//   - Paired Definitions: Verifies a resource and a data source sharing a name are both tested (opt-in)
+//
+// Quality rules (tfprovider-quality-*) report weaknesses in existing tests:
 //   - State Check Validation: Confirms test steps include state validation functions
+//   - ImportStateIdFunc: Confirms import ID functions read attributes the schema defines (opt-in)
+//   - ImportStateVerify: Confirms import steps verify the imported state (opt-in)
+//   - Parallel Fixtures: Confirms parallel tests do not share hard-coded resource names (opt-in)
+//   - Parallel Tests: Recommends resource.ParallelTest for independent serial tests (opt-in)
+//   - Test Placement: Finds tests kept in the test file of a different resource (opt-in)
+//   - Test Layout: Finds tests kept outside the directory of the definition they test (opt-in)
+//   - Orphan Tests: Reports acceptance tests linked to no definition at the test (opt-in)
+//   - Default Values: Finds attribute defaults that no test config leaves unset (opt-in)
+//   - Provider Hygiene: Checks TestMain runs the tests and dispatches to sweepers (opt-in)
+//   - Unknown Types: Finds test configs declaring types the provider does not define (opt-in)
+//   - Test Helpers: Finds helpers that call t.Fatal from goroutines or discard errors (opt-in)
+//   - Data Source Config: Confirms data source tests create the resource they read (opt-in)
+//   - Data Source Asserts: Confirms data source tests assert computed attributes beyond id (opt-in)
+//   - Plural Data Sources: Confirms plural data source tests assert how many items are returned (opt-in)
+//   - Legacy Checks: Confirms test steps added since a git ref use ConfigStateChecks rather than Check (opt-in)
+//   - Provider Factories: Confirms a package's tests share one provider factory field and give every step a provider (opt-in)
+//   - ErrorCheck: Confirms TestCases set ErrorCheck in packages where most TestCases do (opt-in)
+//   - ExpectError Patterns: Confirms ExpectError regexes compile and are not overly broad (opt-in)
+//   - Dead Tests: Finds commented-out acceptance tests in test files (opt-in)
+//   - Check Addresses: Confirms state checks name addresses the step's config declares (opt-in)
+//   - Refresh Drift: Confirms tested resources have a RefreshState step (opt-in)
+//   - Env PreCheck: Confirms env vars interpolated into test configs are checked in PreCheck (opt-in)
+//   - Deprecated Attributes: Confirms tests of deprecated attributes also cover their replacements (opt-in)
+//   - Drift Check: Confirms tests include CheckDestroy
+//   - Sweepers: Confirms packages register test sweepers
+//
+// Each diagnostic is reported once per run, even when golangci-lint analyzes the same files
+// as part of several packages (see internal/dedup).
 //
 // This implementation uses a simplified "File-First" approach for test association:
 // - Resources are identified by AST analysis (Schema() methods)
@@ -15,9 +52,19 @@
 package tfprovidertest
 
 import (
+	"encoding/json"
 	"fmt"
+	"go/ast"
+	"go/token"
+	"sync"
 
 	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/dedup"
+	"github.com/example/tfprovidertest/internal/dirconfig"
+	"github.com/example/tfprovidertest/internal/enforcement"
+	"github.com/example/tfprovidertest/internal/messages"
+	"github.com/example/tfprovidertest/internal/rules"
+	"github.com/example/tfprovidertest/internal/scan"
 	"github.com/example/tfprovidertest/pkg/config"
 	"github.com/golangci/plugin-module-register/register"
 	analysislib "golang.org/x/tools/go/analysis"
@@ -25,7 +72,12 @@ import (
 
 // Plugin implements the golangci-lint plugin interface.
 type Plugin struct {
-	settings config.Settings
+	settings    config.Settings
+	dedup       *dedup.Coordinator
+	enforcement *enforcement.Policy
+	dirConfigs  *dirconfig.Loader
+	skipReports sync.Map // Files already reported as skipped by the file limits
+	dirReports  sync.Map // Directory configs already reported as invalid
 }
 
 // New creates a new plugin instance with the given settings.
@@ -37,8 +89,208 @@ func New(settings any) (register.LinterPlugin, error) {
 			return nil, fmt.Errorf("failed to decode settings: %w", err)
 		}
 		s = decoded
+		switch {
+		case decoded.ConfigFile != "":
+			if s, err = applyConfigFile(decoded.ConfigFile, decoded.Profile, settings); err != nil {
+				return nil, err
+			}
+		case decoded.Profile != "":
+			if s, err = applyProfile(decoded.Profile, settings); err != nil {
+				return nil, err
+			}
+		}
+	}
+	policy, err := s.EnforcementPolicy()
+	if err != nil {
+		return nil, err
+	}
+	return &Plugin{settings: s, dedup: dedup.New(s.DedupDir), enforcement: policy, dirConfigs: newDirConfigLoader(s)}, nil
+}
+
+// applyProfile decodes raw settings over the settings of the named profile, so that only
+// the settings actually configured override the profile.
+func applyProfile(profile string, settings any) (config.Settings, error) {
+	s, err := config.ProfileSettings(profile)
+	if err != nil {
+		return s, err
+	}
+	return overlaySettings(s, settings)
+}
+
+// applyConfigFile decodes raw settings over the settings of a settings file, so that the
+// settings configured alongside config-file override the file.
+func applyConfigFile(path, profile string, settings any) (config.Settings, error) {
+	s, err := config.LoadFile(path, profile)
+	if err != nil {
+		return s, fmt.Errorf("failed to load config-file: %w", err)
+	}
+	return overlaySettings(s, settings)
+}
+
+// overlaySettings decodes raw settings over s.
+func overlaySettings(s config.Settings, settings any) (config.Settings, error) {
+	raw, err := json.Marshal(settings)
+	if err != nil {
+		return s, fmt.Errorf("failed to decode settings: %w", err)
+	}
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return s, fmt.Errorf("failed to decode settings: %w", err)
+	}
+	return s, nil
+}
+
+// NewWithSettings creates a plugin from settings that are already decoded. Programs that
+// embed the engine use it to set fields golangci-lint configuration cannot carry, such as
+// Hooks. Invalid enforce-paths or warn-only-paths patterns are ignored, so every finding is
+// an error; call Settings.Validate first to catch them.
+func NewWithSettings(s config.Settings) *Plugin {
+	policy, _ := s.EnforcementPolicy()
+	return &Plugin{settings: s, dedup: dedup.New(s.DedupDir), enforcement: policy, dirConfigs: newDirConfigLoader(s)}
+}
+
+// newDirConfigLoader returns the loader of tfprovidertest.dir.yaml files, or nil when the
+// settings ignore them.
+func newDirConfigLoader(s config.Settings) *dirconfig.Loader {
+	if s.IgnoreDirConfigs {
+		return nil
+	}
+	return dirconfig.NewLoader()
+}
+
+// dedupPass arranges for the pass to drop diagnostics already reported during this run,
+// unless deduplication is disabled.
+func (p *Plugin) dedupPass(pass *analysislib.Pass) *analysislib.Pass {
+	if !p.settings.DedupEnabled() || p.dedup == nil {
+		return pass
+	}
+	return p.dedup.Wrap(pass)
+}
+
+// enforcementPass marks diagnostics in files that enforce-paths and warn-only-paths leave
+// unenforced as warnings: their category is "warning" and their message starts with
+// enforcement.WarningPrefix. The tfprovidertest.dir.yaml files above a file override its
+// level and drop the diagnostics of the rules they disable.
+func (p *Plugin) enforcementPass(pass *analysislib.Pass) *analysislib.Pass {
+	if !p.enforcement.Active() && p.dirConfigs == nil {
+		return pass
+	}
+	rule := ""
+	if pass.Analyzer != nil {
+		rule = pass.Analyzer.Name
+	}
+	report := pass.Report
+	pass.Report = func(d analysislib.Diagnostic) {
+		filename := pass.Fset.Position(d.Pos).Filename
+		level := p.enforcement.Level(filename)
+		if p.dirConfigs != nil {
+			resolved, err := p.dirConfigs.ForFile(filename)
+			if err != nil {
+				p.reportDirConfigError(report, d.Pos, err)
+			}
+			if resolved.Disables(rule) {
+				return
+			}
+			if resolved.Level != "" {
+				level = resolved.Level
+			}
+		}
+		if level == enforcement.LevelWarning {
+			d.Category = string(enforcement.LevelWarning)
+			d.Message = enforcement.WarningPrefix + d.Message
+		}
+		report(d)
+	}
+	return pass
+}
+
+// reportDirConfigError reports a directory config that cannot be applied, once per run, at
+// the first finding it would have applied to.
+func (p *Plugin) reportDirConfigError(report func(analysislib.Diagnostic), pos token.Pos, err error) {
+	if _, reported := p.dirReports.LoadOrStore(err.Error(), true); reported {
+		return
+	}
+	report(analysislib.Diagnostic{
+		Pos:     pos,
+		Message: messages.Format(p.settings.Language, messages.DirConfigInvalid, messages.Params{"error": err.Error()}),
+	})
+}
+
+// stylePass shortens diagnostic messages to one line ending in the rule code when the
+// short message style is selected.
+func (p *Plugin) stylePass(pass *analysislib.Pass) *analysislib.Pass {
+	if !p.settings.ShortMessages() || pass.Analyzer == nil {
+		return pass
+	}
+	code := rules.Code(pass.Analyzer.Name)
+	report := pass.Report
+	pass.Report = func(d analysislib.Diagnostic) {
+		d.Message = messages.Compact(d.Message, code)
+		report(d)
 	}
-	return &Plugin{settings: s}, nil
+	return pass
+}
+
+// wrapPass prepares a pass for a rule: files over the file limits are skipped, duplicate
+// diagnostics are dropped, the rest are marked as warnings where enforcement does not apply,
+// and delivered to the OnDiagnostic hook before being reported. Messages are shortened first
+// with the short message style.
+func (p *Plugin) wrapPass(pass *analysislib.Pass) *analysislib.Pass {
+	pass = p.dedupPass(pass)
+	hooks := p.settings.Hooks
+	if hooks == nil || hooks.OnDiagnostic == nil {
+		return p.limitPass(p.stylePass(p.enforcementPass(pass)))
+	}
+	report := pass.Report
+	rule := ""
+	if pass.Analyzer != nil {
+		rule = pass.Analyzer.Name
+	}
+	pass.Report = func(d analysislib.Diagnostic) {
+		pos := pass.Fset.Position(d.Pos)
+		level := enforcement.LevelError
+		if d.Category == string(enforcement.LevelWarning) {
+			level = enforcement.LevelWarning
+		}
+		hooks.Diagnostic(config.DiagnosticEvent{
+			Rule:    rule,
+			File:    pos.Filename,
+			Line:    pos.Line,
+			Column:  pos.Column,
+			Message: d.Message,
+			Level:   string(level),
+		})
+		report(d)
+	}
+	return p.limitPass(p.stylePass(p.enforcementPass(pass)))
+}
+
+// limitPass drops the files max-files and max-file-size-kb leave out of analysis from the
+// pass, reporting each skipped file once per run at its package clause.
+func (p *Plugin) limitPass(pass *analysislib.Pass) *analysislib.Pass {
+	maxFiles, maxSizeKB := p.settings.FileLimits()
+	files, skipped := scan.LimitFiles(pass.Fset, pass.Files, maxFiles, maxSizeKB)
+	if len(skipped) == 0 {
+		return pass
+	}
+	byPath := make(map[string]*ast.File, len(pass.Files))
+	for _, file := range pass.Files {
+		byPath[pass.Fset.Position(file.Pos()).Filename] = file
+	}
+	pass.Files = files
+	for _, skip := range skipped {
+		if _, reported := p.skipReports.LoadOrStore(skip.Path, true); reported {
+			continue
+		}
+		id := messages.FileSkippedTooLarge
+		if skip.Reason == scan.ReasonTooManyFiles {
+			id = messages.FileSkippedTooMany
+		}
+		pass.Report(analysislib.Diagnostic{
+			Pos:     byPath[skip.Path].Package,
+			Message: messages.Format(p.settings.Language, id, messages.Params{"size": skip.SizeKB, "limit": skip.Limit}),
+		})
+	}
+	return pass
 }
 
 // BuildAnalyzers returns the list of enabled analyzers based on settings.
@@ -46,23 +298,115 @@ func New(settings any) (register.LinterPlugin, error) {
 func (p *Plugin) BuildAnalyzers() ([]*analysislib.Analyzer, error) {
 	var analyzers []*analysislib.Analyzer
 
-	if p.settings.EnableBasicTest {
+	if p.settings.BasicTestEnabled() {
 		analyzers = append(analyzers, p.createBasicTestAnalyzer())
 	}
-	if p.settings.EnableUpdateTest {
+	if p.settings.UpdateTestEnabled() {
 		analyzers = append(analyzers, p.createUpdateTestAnalyzer())
 	}
-	if p.settings.EnableImportTest {
+	if p.settings.ImportTestEnabled() {
 		analyzers = append(analyzers, p.createImportTestAnalyzer())
 	}
-	if p.settings.EnableErrorTest {
+	if p.settings.ErrorTestEnabled() {
 		analyzers = append(analyzers, p.createErrorTestAnalyzer())
 	}
-	if p.settings.EnableStateCheck {
+	if p.settings.ProviderConfigTestEnabled() {
+		analyzers = append(analyzers, p.createProviderConfigAnalyzer())
+	}
+	if p.settings.RequirementsCheckEnabled() {
+		analyzers = append(analyzers, p.createRequirementsAnalyzer())
+	}
+	if p.settings.DeferredActionsTestEnabled() {
+		analyzers = append(analyzers, p.createDeferredActionsAnalyzer())
+	}
+	if p.settings.ProviderAliasTestEnabled() {
+		analyzers = append(analyzers, p.createProviderAliasAnalyzer())
+	}
+	if p.settings.UpgradeTestEnabled() {
+		analyzers = append(analyzers, p.createUpgradeTestAnalyzer())
+	}
+	if p.settings.PairedDefinitionTestEnabled() {
+		analyzers = append(analyzers, p.createPairedDefinitionsAnalyzer())
+	}
+	if p.settings.StateCheckEnabled() {
 		analyzers = append(analyzers, p.createStateCheckAnalyzer())
 	}
-	if p.settings.EnableBasicTest || p.settings.EnableUpdateTest ||
-	   p.settings.EnableImportTest || p.settings.EnableErrorTest || p.settings.EnableStateCheck {
+	if p.settings.ImportStateIdCheckEnabled() {
+		analyzers = append(analyzers, p.createImportStateIdFuncAnalyzer())
+	}
+	if p.settings.ImportStateVerifyCheckEnabled() {
+		analyzers = append(analyzers, p.createImportStateVerifyAnalyzer())
+	}
+	if p.settings.ParallelFixtureCheckEnabled() {
+		analyzers = append(analyzers, p.createParallelFixturesAnalyzer())
+	}
+	if p.settings.ParallelTestCheckEnabled() {
+		analyzers = append(analyzers, p.createParallelTestsAnalyzer())
+	}
+	if p.settings.TestPlacementCheckEnabled() {
+		analyzers = append(analyzers, p.createTestPlacementAnalyzer())
+	}
+	if p.settings.TestLayoutCheckEnabled() {
+		analyzers = append(analyzers, p.createTestLayoutAnalyzer())
+	}
+	if p.settings.OrphanTestCheckEnabled() {
+		analyzers = append(analyzers, p.createOrphanTestsAnalyzer())
+	}
+	if p.settings.DefaultValueCheckEnabled() {
+		analyzers = append(analyzers, p.createDefaultValuesAnalyzer())
+	}
+	if p.settings.PlaceholderValueCheckEnabled() {
+		analyzers = append(analyzers, p.createPlaceholderValuesAnalyzer())
+	}
+	if p.settings.ProviderHygieneCheckEnabled() {
+		analyzers = append(analyzers, p.createProviderHygieneAnalyzer())
+	}
+	if p.settings.UnknownTypeCheckEnabled() {
+		analyzers = append(analyzers, p.createUnknownTypesAnalyzer())
+	}
+	if p.settings.TestHelperCheckEnabled() {
+		analyzers = append(analyzers, p.createTestHelpersAnalyzer())
+	}
+	if p.settings.DataSourceConfigCheckEnabled() {
+		analyzers = append(analyzers, p.createDataSourceConfigAnalyzer())
+	}
+	if p.settings.DataSourceAssertCheckEnabled() {
+		analyzers = append(analyzers, p.createDataSourceAssertsAnalyzer())
+	}
+	if p.settings.PluralDataSourceCheckEnabled() {
+		analyzers = append(analyzers, p.createPluralDataSourcesAnalyzer())
+	}
+	if p.settings.LegacyCheckMigrationEnabled() {
+		analyzers = append(analyzers, p.createLegacyChecksAnalyzer())
+	}
+	if p.settings.ProviderFactoriesCheckEnabled() {
+		analyzers = append(analyzers, p.createProviderFactoriesAnalyzer())
+	}
+	if p.settings.ErrorCheckPresenceEnabled() {
+		analyzers = append(analyzers, p.createErrorCheckPresenceAnalyzer())
+	}
+	if p.settings.ExpectErrorCheckEnabled() {
+		analyzers = append(analyzers, p.createExpectErrorAnalyzer())
+	}
+	if p.settings.DeadTestCheckEnabled() {
+		analyzers = append(analyzers, p.createDeadTestsAnalyzer())
+	}
+	if p.settings.CheckAddressCheckEnabled() {
+		analyzers = append(analyzers, p.createCheckAddressesAnalyzer())
+	}
+	if p.settings.RefreshDriftCheckEnabled() {
+		analyzers = append(analyzers, p.createRefreshDriftAnalyzer())
+	}
+	if p.settings.IdempotencyCheckEnabled() {
+		analyzers = append(analyzers, p.createIdempotencyAnalyzer())
+	}
+	if p.settings.EnvPreCheckCheckEnabled() {
+		analyzers = append(analyzers, p.createEnvPreCheckAnalyzer())
+	}
+	if p.settings.DeprecatedAttributeCheckEnabled() {
+		analyzers = append(analyzers, p.createDeprecatedAttributesAnalyzer())
+	}
+	if p.settings.DriftCheckEnabled() {
 		analyzers = append(analyzers, p.createDriftCheckAnalyzer())
 		analyzers = append(analyzers, p.createSweeperAnalyzer())
 	}
@@ -73,10 +417,11 @@ func (p *Plugin) BuildAnalyzers() ([]*analysislib.Analyzer, error) {
 // createBasicTestAnalyzer creates an analyzer with settings captured via closure.
 func (p *Plugin) createBasicTestAnalyzer() *analysislib.Analyzer {
 	return &analysislib.Analyzer{
-		Name: "tfprovider-resource-basic-test",
-		Doc:  "Checks that every resource and data source has at least one acceptance test.",
+		Name:      rules.BasicTest,
+		Doc:       p.ruleDoc(rules.BasicTest),
+		FactTypes: analysis.FactTypes(),
 		Run: func(pass *analysislib.Pass) (interface{}, error) {
-			return analysis.RunBasicTestAnalyzer(pass, &p.settings)
+			return analysis.RunBasicTestAnalyzer(p.wrapPass(pass), &p.settings)
 		},
 	}
 }
@@ -84,10 +429,10 @@ func (p *Plugin) createBasicTestAnalyzer() *analysislib.Analyzer {
 // createUpdateTestAnalyzer creates an analyzer with settings captured via closure.
 func (p *Plugin) createUpdateTestAnalyzer() *analysislib.Analyzer {
 	return &analysislib.Analyzer{
-		Name: "tfprovider-resource-update-test",
-		Doc:  "Checks that resources with updatable attributes have multi-step update tests.",
+		Name: rules.UpdateTest,
+		Doc:  p.ruleDoc(rules.UpdateTest),
 		Run: func(pass *analysislib.Pass) (interface{}, error) {
-			return analysis.RunUpdateTestAnalyzer(pass, &p.settings)
+			return analysis.RunUpdateTestAnalyzer(p.wrapPass(pass), &p.settings)
 		},
 	}
 }
@@ -95,10 +440,10 @@ func (p *Plugin) createUpdateTestAnalyzer() *analysislib.Analyzer {
 // createImportTestAnalyzer creates an analyzer with settings captured via closure.
 func (p *Plugin) createImportTestAnalyzer() *analysislib.Analyzer {
 	return &analysislib.Analyzer{
-		Name: "tfprovider-resource-import-test",
-		Doc:  "Checks that resources implementing ImportState have import tests.",
+		Name: rules.ImportTest,
+		Doc:  p.ruleDoc(rules.ImportTest),
 		Run: func(pass *analysislib.Pass) (interface{}, error) {
-			return analysis.RunImportTestAnalyzer(pass, &p.settings)
+			return analysis.RunImportTestAnalyzer(p.wrapPass(pass), &p.settings)
 		},
 	}
 }
@@ -106,10 +451,32 @@ func (p *Plugin) createImportTestAnalyzer() *analysislib.Analyzer {
 // createErrorTestAnalyzer creates an analyzer with settings captured via closure.
 func (p *Plugin) createErrorTestAnalyzer() *analysislib.Analyzer {
 	return &analysislib.Analyzer{
-		Name: "tfprovider-test-error-cases",
-		Doc:  "Checks that resources with validation rules have error case tests.",
+		Name: rules.ErrorTest,
+		Doc:  p.ruleDoc(rules.ErrorTest),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunErrorTestAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createProviderConfigAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createProviderConfigAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.ProviderConfig,
+		Doc:  p.ruleDoc(rules.ProviderConfig),
 		Run: func(pass *analysislib.Pass) (interface{}, error) {
-			return analysis.RunErrorTestAnalyzer(pass, &p.settings)
+			return analysis.RunProviderConfigAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createRequirementsAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createRequirementsAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.Requirements,
+		Doc:  p.ruleDoc(rules.Requirements),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunRequirementsAnalyzer(p.wrapPass(pass), &p.settings)
 		},
 	}
 }
@@ -117,10 +484,329 @@ func (p *Plugin) createErrorTestAnalyzer() *analysislib.Analyzer {
 // createStateCheckAnalyzer creates an analyzer with settings captured via closure.
 func (p *Plugin) createStateCheckAnalyzer() *analysislib.Analyzer {
 	return &analysislib.Analyzer{
-		Name: "tfprovider-test-check-functions",
-		Doc:  "Checks that test steps include state validation check functions.",
+		Name: rules.CheckFunctions,
+		Doc:  p.ruleDoc(rules.CheckFunctions),
 		Run: func(pass *analysislib.Pass) (interface{}, error) {
-			return analysis.RunStateCheckAnalyzer(pass, &p.settings)
+			return analysis.RunStateCheckAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createImportStateIdFuncAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createImportStateIdFuncAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.ImportStateIdFunc,
+		Doc:  p.ruleDoc(rules.ImportStateIdFunc),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunImportStateIdFuncAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createImportStateVerifyAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createImportStateVerifyAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.ImportStateVerify,
+		Doc:  p.ruleDoc(rules.ImportStateVerify),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunImportStateVerifyAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createParallelFixturesAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createParallelFixturesAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.ParallelFixtures,
+		Doc:  p.ruleDoc(rules.ParallelFixtures),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunParallelFixturesAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createParallelTestsAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createParallelTestsAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.ParallelTests,
+		Doc:  p.ruleDoc(rules.ParallelTests),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunParallelTestsAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createTestPlacementAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createTestPlacementAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.TestPlacement,
+		Doc:  p.ruleDoc(rules.TestPlacement),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunTestPlacementAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createTestLayoutAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createTestLayoutAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.TestLayout,
+		Doc:  p.ruleDoc(rules.TestLayout),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunTestLayoutAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createOrphanTestsAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createOrphanTestsAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.OrphanTests,
+		Doc:  p.ruleDoc(rules.OrphanTests),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunOrphanTestsAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createDefaultValuesAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createDefaultValuesAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.DefaultValues,
+		Doc:  p.ruleDoc(rules.DefaultValues),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunDefaultValuesAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createPlaceholderValuesAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createPlaceholderValuesAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.Placeholders,
+		Doc:  p.ruleDoc(rules.Placeholders),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunPlaceholderValuesAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createProviderHygieneAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createProviderHygieneAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.ProviderHygiene,
+		Doc:  p.ruleDoc(rules.ProviderHygiene),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunProviderHygieneAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createUnknownTypesAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createUnknownTypesAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.UnknownTypes,
+		Doc:  p.ruleDoc(rules.UnknownTypes),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunUnknownTypesAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createTestHelpersAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createTestHelpersAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.TestHelpers,
+		Doc:  p.ruleDoc(rules.TestHelpers),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunTestHelpersAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createDataSourceConfigAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createDataSourceConfigAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.DataSourceConfig,
+		Doc:  p.ruleDoc(rules.DataSourceConfig),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunDataSourceConfigAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createPluralDataSourcesAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createPluralDataSourcesAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.PluralDataSources,
+		Doc:  p.ruleDoc(rules.PluralDataSources),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunPluralDataSourcesAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createLegacyChecksAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createLegacyChecksAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.LegacyChecks,
+		Doc:  p.ruleDoc(rules.LegacyChecks),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunLegacyChecksAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createProviderFactoriesAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createProviderFactoriesAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.ProviderFactories,
+		Doc:  p.ruleDoc(rules.ProviderFactories),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunProviderFactoriesAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createErrorCheckPresenceAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createErrorCheckPresenceAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.ErrorCheck,
+		Doc:  p.ruleDoc(rules.ErrorCheck),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunErrorCheckPresenceAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createDataSourceAssertsAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createDataSourceAssertsAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.DataSourceAsserts,
+		Doc:  p.ruleDoc(rules.DataSourceAsserts),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunDataSourceAssertsAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createExpectErrorAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createExpectErrorAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.ExpectError,
+		Doc:  p.ruleDoc(rules.ExpectError),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunExpectErrorAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createDeferredActionsAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createDeferredActionsAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.DeferredActions,
+		Doc:  p.ruleDoc(rules.DeferredActions),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunDeferredActionsAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createProviderAliasAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createProviderAliasAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.ProviderAliases,
+		Doc:  p.ruleDoc(rules.ProviderAliases),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunProviderAliasAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createUpgradeTestAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createUpgradeTestAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.UpgradeTest,
+		Doc:  p.ruleDoc(rules.UpgradeTest),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunUpgradeTestAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createPairedDefinitionsAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createPairedDefinitionsAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.PairedDefinitions,
+		Doc:  p.ruleDoc(rules.PairedDefinitions),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunPairedDefinitionsAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createDeadTestsAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createDeadTestsAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.DeadTests,
+		Doc:  p.ruleDoc(rules.DeadTests),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunDeadTestsAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createCheckAddressesAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createCheckAddressesAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.CheckAddresses,
+		Doc:  p.ruleDoc(rules.CheckAddresses),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunCheckAddressesAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createRefreshDriftAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createRefreshDriftAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.RefreshDrift,
+		Doc:  p.ruleDoc(rules.RefreshDrift),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunRefreshDriftAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createIdempotencyAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createIdempotencyAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.Idempotency,
+		Doc:  p.ruleDoc(rules.Idempotency),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunIdempotencyAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createEnvPreCheckAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createEnvPreCheckAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.EnvPreCheck,
+		Doc:  p.ruleDoc(rules.EnvPreCheck),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunEnvPreCheckAnalyzer(p.wrapPass(pass), &p.settings)
+		},
+	}
+}
+
+// createDeprecatedAttributesAnalyzer creates an analyzer with settings captured via closure.
+func (p *Plugin) createDeprecatedAttributesAnalyzer() *analysislib.Analyzer {
+	return &analysislib.Analyzer{
+		Name: rules.DeprecatedAttrs,
+		Doc:  p.ruleDoc(rules.DeprecatedAttrs),
+		Run: func(pass *analysislib.Pass) (interface{}, error) {
+			return analysis.RunDeprecatedAttributesAnalyzer(p.wrapPass(pass), &p.settings)
 		},
 	}
 }
@@ -128,10 +814,10 @@ func (p *Plugin) createStateCheckAnalyzer() *analysislib.Analyzer {
 // createDriftCheckAnalyzer creates an analyzer with settings captured via closure.
 func (p *Plugin) createDriftCheckAnalyzer() *analysislib.Analyzer {
 	return &analysislib.Analyzer{
-		Name: "tfprovider-test-drift-check",
-		Doc:  "Checks that acceptance tests include CheckDestroy for drift detection.",
+		Name: rules.DriftCheck,
+		Doc:  p.ruleDoc(rules.DriftCheck),
 		Run: func(pass *analysislib.Pass) (interface{}, error) {
-			return analysis.RunDriftCheckAnalyzer(pass, &p.settings)
+			return analysis.RunDriftCheckAnalyzer(p.wrapPass(pass), &p.settings)
 		},
 	}
 }
@@ -139,20 +825,34 @@ func (p *Plugin) createDriftCheckAnalyzer() *analysislib.Analyzer {
 // createSweeperAnalyzer creates an analyzer with settings captured via closure.
 func (p *Plugin) createSweeperAnalyzer() *analysislib.Analyzer {
 	return &analysislib.Analyzer{
-		Name: "tfprovider-test-sweepers",
-		Doc:  "Checks that packages have test sweeper registrations for cleanup.",
+		Name: rules.Sweepers,
+		Doc:  p.ruleDoc(rules.Sweepers),
 		Run: func(pass *analysislib.Pass) (interface{}, error) {
-			return analysis.RunSweeperAnalyzer(pass, &p.settings)
+			return analysis.RunSweeperAnalyzer(p.wrapPass(pass), &p.settings)
 		},
 	}
 }
 
+// ruleDoc returns the catalogue documentation for a rule, followed by the trailer of its
+// metadata.
+func (p *Plugin) ruleDoc(name string) string {
+	rule, _ := rules.Lookup(name)
+	return rule.Doc + "\n\n" + p.ruleMetadata(rule).Trailer()
+}
+
 // GetLoadMode returns the AST load mode required by the analyzers.
 func (p *Plugin) GetLoadMode() string {
 	return register.LoadModeSyntax
 }
 
-func init() {
-	register.Plugin("tfprovidertest", New)
+// NewAnalyzers decodes settings and returns the enabled analyzers. It backs the legacy .so
+// plugin in cmd/tfprovidertest-plugin, which golangci-lint versions without module plugin
+// support load; module plugins are registered in plugin_module.go instead.
+func NewAnalyzers(settings any) ([]*analysislib.Analyzer, error) {
+	plugin, err := New(settings)
+	if err != nil {
+		return nil, err
+	}
+	return plugin.BuildAnalyzers()
 }
 
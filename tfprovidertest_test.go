@@ -330,17 +330,19 @@ func TestPlugin_BuildAnalyzers(t *testing.T) {
 
 		analyzers, err := plugin.BuildAnalyzers()
 		require.NoError(t, err)
-		require.Len(t, analyzers, 7, "should return exactly 7 analyzers when all are enabled (5 main + drift-check + sweepers)")
+		require.Len(t, analyzers, 9, "should return exactly 9 analyzers when all are enabled (7 main + drift-check + sweepers)")
 
 		// Verify analyzer names
 		expectedNames := map[string]bool{
-			"tfprovider-resource-basic-test":  false,
-			"tfprovider-resource-update-test": false,
-			"tfprovider-resource-import-test": false,
-			"tfprovider-test-error-cases":     false,
-			"tfprovider-test-check-functions": false,
-			"tfprovider-test-drift-check":     false,
-			"tfprovider-test-sweepers":        false,
+			"tfprovider-coverage-basic-test":       false,
+			"tfprovider-coverage-update-test":      false,
+			"tfprovider-coverage-import-test":      false,
+			"tfprovider-coverage-error-test":       false,
+			"tfprovider-coverage-requirements":     false,
+			"tfprovider-coverage-deferred-actions": false,
+			"tfprovider-quality-check-functions":   false,
+			"tfprovider-quality-drift-check":       false,
+			"tfprovider-quality-sweepers":          false,
 		}
 
 		for _, analyzer := range analyzers {
@@ -382,11 +384,11 @@ func TestPlugin_Settings(t *testing.T) {
 			enabledNames[analyzer.Name] = true
 		}
 
-		assert.True(t, enabledNames["tfprovider-resource-basic-test"], "basic test should be enabled")
-		assert.False(t, enabledNames["tfprovider-resource-update-test"], "update test should be disabled")
-		assert.True(t, enabledNames["tfprovider-resource-import-test"], "import test should be enabled")
-		assert.False(t, enabledNames["tfprovider-test-error-cases"], "error test should be disabled")
-		assert.True(t, enabledNames["tfprovider-test-check-functions"], "state check should be enabled")
+		assert.True(t, enabledNames["tfprovider-coverage-basic-test"], "basic test should be enabled")
+		assert.False(t, enabledNames["tfprovider-coverage-update-test"], "update test should be disabled")
+		assert.True(t, enabledNames["tfprovider-coverage-import-test"], "import test should be enabled")
+		assert.False(t, enabledNames["tfprovider-coverage-error-test"], "error test should be disabled")
+		assert.True(t, enabledNames["tfprovider-quality-check-functions"], "state check should be enabled")
 	})
 
 	t.Run("should disable all analyzers when all settings are false", func(t *testing.T) {
@@ -412,8 +414,72 @@ func TestPlugin_Settings(t *testing.T) {
 
 		analyzers, err := plugin.BuildAnalyzers()
 		require.NoError(t, err)
-		require.Len(t, analyzers, 7, "default settings should enable all 7 analyzers (5 main + drift-check + sweepers)")
+		require.Len(t, analyzers, 9, "default settings should enable all 9 analyzers (7 main + drift-check + sweepers)")
 	})
+
+	t.Run("minimal profile should enable only basic and import tests", func(t *testing.T) {
+		plugin, err := tfprovidertest.New(map[string]interface{}{"Profile": "minimal"})
+		require.NoError(t, err)
+
+		analyzers, err := plugin.BuildAnalyzers()
+		require.NoError(t, err)
+		var names []string
+		for _, analyzer := range analyzers {
+			names = append(names, analyzer.Name)
+		}
+		assert.ElementsMatch(t, []string{
+			"tfprovider-coverage-basic-test",
+			"tfprovider-coverage-import-test",
+			"tfprovider-quality-drift-check",
+			"tfprovider-quality-sweepers",
+		}, names)
+	})
+
+	t.Run("strict profile should enable every rule", func(t *testing.T) {
+		plugin, err := tfprovidertest.New(map[string]interface{}{"Profile": "strict"})
+		require.NoError(t, err)
+
+		analyzers, err := plugin.BuildAnalyzers()
+		require.NoError(t, err)
+		require.Len(t, analyzers, 38, "strict profile should enable all 38 analyzers")
+	})
+
+	t.Run("settings configured with a profile should override it", func(t *testing.T) {
+		plugin, err := tfprovidertest.New(map[string]interface{}{
+			"Profile":             "strict",
+			"EnableDeadTestCheck": false,
+			"DeadTestMinLines":    10,
+		})
+		require.NoError(t, err)
+
+		analyzers, err := plugin.BuildAnalyzers()
+		require.NoError(t, err)
+		require.Len(t, analyzers, 37)
+		for _, analyzer := range analyzers {
+			assert.NotEqual(t, "tfprovider-quality-dead-tests", analyzer.Name)
+		}
+	})
+
+	t.Run("unknown profile should fail", func(t *testing.T) {
+		_, err := tfprovidertest.New(map[string]interface{}{"Profile": "lenient"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown profile "lenient"`)
+	})
+}
+
+func TestNewAnalyzers(t *testing.T) {
+	analyzers, err := tfprovidertest.NewAnalyzers(map[string]interface{}{
+		"EnableUpdateTest": false,
+	})
+	require.NoError(t, err)
+	for _, analyzer := range analyzers {
+		assert.NotEqual(t, "tfprovider-coverage-update-test", analyzer.Name)
+	}
+
+	_, err = tfprovidertest.NewAnalyzers(map[string]interface{}{
+		"EnforcePaths": []string{"service/[z-a]/**"},
+	})
+	assert.Error(t, err, "invalid settings fail the legacy plugin too")
 }
 
 // T300: Test for custom test helpers
@@ -0,0 +1,66 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const gadgetTypoTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccGadget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: ` + "`" + `resource "example_gadget" "g" {}` + "`" + `}},
+	})
+}
+
+func TestAccGadget_typo(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: ` + "`" + `
+resource "random_id" "suffix" {}
+
+resource "example_gadgte" "g" {}
+
+data "example_gizmo" "z" {}
+` + "`" + `}},
+	})
+}
+`
+
+func TestUnknownTypesAnalyzer(t *testing.T) {
+	settings := config.DefaultSettings()
+	settings.EnableUnknownTypeCheck = true
+
+	t.Run("types with the provider prefix", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunUnknownTypesAnalyzer, settings, map[string]string{
+			"/provider/resource_gadget.go":      untestedGadgetResourceSrc,
+			"/provider/resource_gadget_test.go": gadgetTypoTestSrc,
+		})
+		require.Len(t, messages, 2)
+
+		assert.Contains(t, messages[0], `test 'TestAccGadget_typo' declares resource "example_gadgte", which the provider does not define`)
+		assert.Contains(t, messages[0], "Test: /provider/resource_gadget_test.go:15")
+		assert.Contains(t, messages[0], `Did you mean "example_gadget"?`)
+
+		assert.Contains(t, messages[1], `declares data "example_gizmo"`)
+		assert.Contains(t, messages[1], "Fix the type name")
+	})
+
+	t.Run("no config type resolves, so the prefix is unknown", func(t *testing.T) {
+		messages := runAnalyzerOnSources(t, analysis.RunUnknownTypesAnalyzer, settings, map[string]string{
+			"/provider/resource_sprocket.go":    untestedSprocketResourceSrc,
+			"/provider/resource_gadget_test.go": gadgetTypoTestSrc,
+		})
+		assert.Empty(t, messages)
+	})
+}
@@ -0,0 +1,109 @@
+package tfprovidertest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	goanalysis "golang.org/x/tools/go/analysis"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/internal/testsrc"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const previewWidgetResourceSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+type WidgetResource struct{}
+
+func (r *WidgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name":        schema.StringAttribute{Required: true},
+			"tags":        schema.MapAttribute{Optional: true},
+			"description": schema.StringAttribute{Optional: true},
+			"enabled":     schema.BoolAttribute{Optional: true},
+		},
+	}
+}
+`
+
+func TestUpdateStepPreview(t *testing.T) {
+	// importStep follows the step a test applies its config in
+	importStep := `ResourceName: "example_widget.test", ImportState: true`
+	// explain previews the update step of a widget test with steps
+	explain := func(t *testing.T, settings config.Settings, steps ...string) *analysis.UpdatePreview {
+		names := []string{"/provider/resource_widget.go", "/provider/resource_widget_test.go"}
+		fset, files := parseSources(t, names, map[string]string{
+			"/provider/resource_widget.go":      previewWidgetResourceSrc,
+			"/provider/resource_widget_test.go": testsrc.File(testsrc.Test{Name: "TestAccWidget_basic", Steps: steps}),
+		})
+		reg := discovery.BuildRegistry(&goanalysis.Pass{Fset: fset, Files: files}, settings)
+		exp := analysis.Explain("widget", reg, fset, files, &settings)
+		require.Len(t, exp.Definitions, 1)
+		return exp.Definitions[0].UpdatePreview
+	}
+
+	t.Run("changes an attribute the config sets", func(t *testing.T) {
+		preview := explain(t, config.DefaultSettings(), testsrc.Config(`
+resource "example_widget" "test" {
+  name        = "w1"
+  description = "first"
+}
+`), importStep)
+		require.NotNil(t, preview)
+		assert.Equal(t, "TestAccWidget_basic", preview.Test)
+		assert.Equal(t, 1, preview.Step)
+		assert.Equal(t, "description", preview.Attribute)
+		assert.Equal(t, `"first-updated"`, preview.Value)
+		assert.Equal(t, []string{
+			`  resource "example_widget" "test" {`,
+			`    name = "w1"`,
+			`-   description = "first"`,
+			`+   description = "first-updated"`,
+			`  }`,
+		}, preview.Diff)
+		assert.Contains(t, analysis.FormatUpdatePreview(preview), "add a step to TestAccWidget_basic that repeats step 1 with description = \"first-updated\"")
+	})
+
+	t.Run("adds the first simple updatable attribute", func(t *testing.T) {
+		preview := explain(t, config.DefaultSettings(), testsrc.Config(`resource "example_widget" "test" { name = "w1" }`), importStep)
+		require.NotNil(t, preview)
+		assert.Equal(t, "description", preview.Attribute, "maps are not previewed")
+		assert.Equal(t, []string{
+			`  resource "example_widget" "test" {`,
+			`    name = "w1"`,
+			`+   description = "updated"`,
+			`  }`,
+		}, preview.Diff, "a block on one line is split")
+	})
+
+	t.Run("redact-configs drops the diff", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		settings.RedactConfigs = true
+		preview := explain(t, settings, testsrc.Config(`
+resource "example_widget" "test" {
+  name    = "w1"
+  enabled = true
+}
+`), importStep)
+		require.NotNil(t, preview)
+		assert.Equal(t, "enabled", preview.Attribute)
+		assert.Equal(t, "false", preview.Value)
+		assert.Empty(t, preview.Diff)
+	})
+
+	t.Run("no preview once a step changes the config", func(t *testing.T) {
+		assert.Nil(t, explain(t, config.DefaultSettings(),
+			testsrc.Config(`resource "example_widget" "test" { name = "w1" }`),
+			testsrc.Config(`resource "example_widget" "test" { name = "w2" }`)))
+	})
+}
@@ -0,0 +1,211 @@
+package tfprovidertest
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/internal/gitmeta"
+	"github.com/example/tfprovidertest/internal/registry"
+	"github.com/example/tfprovidertest/pkg/config"
+)
+
+const upgradeWidgetResourceSrc = `package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+type WidgetResource struct{}
+
+func (r *WidgetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version: 2,
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{Required: true},
+		},
+	}
+}
+`
+
+const upgradeLegacyResourceSrc = `package provider
+
+import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+func resourceLegacy() *schema.Resource {
+	return &schema.Resource{
+		SchemaVersion: 1,
+		Schema: map[string]*schema.Schema{
+			"name": {Type: schema.TypeString, Required: true},
+		},
+	}
+}
+`
+
+const upgradeWidgetTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccWidget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{Config: ` + "`" + `resource "example_widget" "test" { name = "a" }` + "`" + `}},
+	})
+}
+
+func TestAccWidget_upgradeFromV1(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				ExternalProviders: map[string]resource.ExternalProvider{
+					"example": {Source: "hashicorp/example", VersionConstraint: "1.4.0"},
+				},
+				Config: ` + "`" + `resource "example_widget" "test" { name = "a" }` + "`" + `,
+			},
+			{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Config:                   ` + "`" + `resource "example_widget" "test" { name = "a" }` + "`" + `,
+			},
+		},
+	})
+}
+
+func TestAccLegacy_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				ExternalProviders: map[string]resource.ExternalProvider{
+					"random": {Source: "hashicorp/random"},
+				},
+				Config: ` + "`" + `resource "example_legacy" "test" { name = "a" }` + "`" + `,
+			},
+			{
+				Config: ` + "`" + `resource "example_legacy" "test" { name = "b" }` + "`" + `,
+			},
+		},
+	})
+}
+`
+
+func TestUpgradeTests(t *testing.T) {
+	sources := map[string]string{
+		"/provider/resource_widget.go":      upgradeWidgetResourceSrc,
+		"/provider/resource_legacy.go":      upgradeLegacyResourceSrc,
+		"/provider/resource_widget_test.go": upgradeWidgetTestSrc,
+	}
+	reg := buildRegistryFromSources(t, sources)
+
+	t.Run("schema versions", func(t *testing.T) {
+		widget := reg.GetDefinition(registry.KindResource, "widget")
+		require.NotNil(t, widget)
+		assert.Equal(t, 2, widget.SchemaVersion)
+		assert.True(t, widget.VersionPos.IsValid())
+		legacy := reg.GetDefinition(registry.KindResource, "legacy")
+		require.NotNil(t, legacy)
+		assert.Equal(t, 1, legacy.SchemaVersion, "SDK v2 SchemaVersion")
+	})
+
+	t.Run("external providers pinning the provider itself", func(t *testing.T) {
+		upgrade := findTest(t, reg, "TestAccWidget_upgradeFromV1")
+		require.Len(t, upgrade.TestSteps, 2)
+		assert.Equal(t, []registry.ExternalProvider{
+			{Name: "example", Source: "hashicorp/example", VersionConstraint: "1.4.0"},
+		}, upgrade.TestSteps[0].ExternalProviders)
+		version, ok := upgrade.UpgradeFrom("example")
+		assert.True(t, ok)
+		assert.Equal(t, "1.4.0", version)
+
+		_, ok = findTest(t, reg, "TestAccLegacy_basic").UpgradeFrom("example")
+		assert.False(t, ok, "another provider from the registry is no upgrade")
+		_, ok = findTest(t, reg, "TestAccWidget_basic").UpgradeFrom("example")
+		assert.False(t, ok)
+		assert.Equal(t, "example", analysis.ProviderTypeName(reg))
+	})
+
+	t.Run("schema version changes", func(t *testing.T) {
+		base := map[string]string{
+			"/provider/resource_widget.go": strings.Replace(upgradeWidgetResourceSrc, "Version: 2", "Version: 1", 1),
+			"/provider/resource_legacy.go": upgradeLegacyResourceSrc,
+		}
+		fileAt := func(path string) ([]byte, bool, error) {
+			src, ok := base[path]
+			return []byte(src), ok, nil
+		}
+		changes, err := analysis.SchemaVersionChanges(reg, config.DefaultSettings(), fileAt)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		assert.Equal(t, "widget", changes[0].Resource.Name)
+		assert.Equal(t, 1, changes[0].From)
+
+		delete(base, "/provider/resource_widget.go")
+		changes, err = analysis.SchemaVersionChanges(reg, config.DefaultSettings(), fileAt)
+		require.NoError(t, err)
+		assert.Empty(t, changes, "a resource added since the base has no released state to upgrade")
+	})
+
+	t.Run("settings", func(t *testing.T) {
+		settings := config.DefaultSettings()
+		assert.False(t, settings.UpgradeTestEnabled(), "the rule is opt-in")
+		settings.EnableUpgradeTest = true
+		assert.Empty(t, runAnalyzerOnSources(t, analysis.RunUpgradeTestAnalyzer, settings, sources),
+			"without since there is nothing to compare with")
+	})
+}
+
+func TestUpgradeTestAnalyzer(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	write := func(name, src string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(src), 0o644))
+		return path
+	}
+
+	git("init", "-q")
+	legacyV0 := strings.Replace(upgradeLegacyResourceSrc, "SchemaVersion: 1,\n\t\t", "", 1)
+	write("resource_legacy.go", legacyV0)
+	write("resource_widget.go", upgradeWidgetResourceSrc)
+	git("add", ".")
+	git("commit", "-q", "-m", "base")
+	git("tag", "base")
+	legacy := write("resource_legacy.go", upgradeLegacyResourceSrc)
+	widget := write("resource_widget.go", strings.Replace(upgradeWidgetResourceSrc, "Version: 2", "Version: 3", 1))
+	test := filepath.Join(dir, "resource_widget_test.go")
+
+	settings := config.DefaultSettings()
+	settings.EnableUpgradeTest = true
+	settings.Since = "base"
+	messages := runAnalyzerOnSources(t, analysis.RunUpgradeTestAnalyzer, settings, map[string]string{
+		legacy: upgradeLegacyResourceSrc,
+		widget: strings.Replace(upgradeWidgetResourceSrc, "Version: 2", "Version: 3", 1),
+		test:   upgradeWidgetTestSrc,
+	})
+	require.Len(t, messages, 1, strings.Join(messages, "\n\n"))
+	assert.Contains(t, messages[0], "resource 'legacy' changed its schema version from 0 to 1 since base, but none of its 1 test(s) is an upgrade test")
+	assert.Contains(t, messages[0], `ExternalProviders entry named "example"`)
+
+	_, _, err := gitmeta.GitFileAt(context.Background(), "no-such-ref")(legacy)
+	assert.ErrorContains(t, err, `git ref "no-such-ref" not found`)
+}
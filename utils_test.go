@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/example/tfprovidertest/internal/matching"
+	"github.com/example/tfprovidertest/internal/naming"
 	"github.com/example/tfprovidertest/internal/registry"
 )
 
@@ -150,9 +151,9 @@ func TestToTitleCase(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
-			got := matching.SnakeCaseToTitleCaseExported(tt.input)
+			got := naming.TitleCase(tt.input)
 			if got != tt.expected {
-				t.Errorf("toTitleCase(%q) = %q, want %q", tt.input, got, tt.expected)
+				t.Errorf("TitleCase(%q) = %q, want %q", tt.input, got, tt.expected)
 			}
 		})
 	}
@@ -0,0 +1,107 @@
+package tfprovidertest
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/analysis"
+	"github.com/example/tfprovidertest/pkg/config"
+	"github.com/example/tfprovidertest/pkg/diagnose"
+)
+
+const gadgetCheckedTestSrc = `package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccGadget_checked(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{
+			Config: ` + "`" + `resource "example_gadget" "g" {}` + "`" + `,
+			Check:  resource.TestCheckResourceAttrSet("example_gadget.g", "id"),
+		}},
+	})
+}
+`
+
+const sprocketExpectErrorTestSrc = `package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccSprocket_invalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Steps: []resource.TestStep{{
+			Config:      ` + "`" + `resource "example_sprocket" "s" {}` + "`" + `,
+			ExpectError: regexp.MustCompile("name is required"),
+		}},
+	})
+}
+`
+
+func TestWeakTests(t *testing.T) {
+	settings := config.DefaultSettings()
+	reg := buildRegistryFromSources(t, map[string]string{
+		"/provider/resource_gadget.go":           untestedGadgetResourceSrc,
+		"/provider/resource_gadget_test.go":      gadgetResourceTestSrc,
+		"/provider/resource_gadget_more_test.go": gadgetCheckedTestSrc,
+		"/provider/resource_sprocket.go":         untestedSprocketResourceSrc,
+		"/provider/resource_sprocket_test.go":    sprocketExpectErrorTestSrc,
+	})
+
+	assert.True(t, findTest(t, reg, "TestAccGadget_basic").IsWeak())
+	assert.False(t, findTest(t, reg, "TestAccGadget_checked").IsWeak())
+	assert.False(t, findTest(t, reg, "TestAccSprocket_invalid").IsWeak(), "ExpectError is an assertion")
+
+	t.Run("the check-functions rule reports weak tests with fail-on-weak-tests", func(t *testing.T) {
+		sources := map[string]string{
+			"/provider/resource_gadget.go":           untestedGadgetResourceSrc,
+			"/provider/resource_gadget_test.go":      gadgetResourceTestSrc,
+			"/provider/resource_gadget_more_test.go": gadgetCheckedTestSrc,
+		}
+		assert.Empty(t, runAnalyzerOnSources(t, analysis.RunStateCheckAnalyzer, settings, sources))
+
+		failing := settings
+		failing.FailOnWeakTests = true
+		messages := runAnalyzerOnSources(t, analysis.RunStateCheckAnalyzer, failing, sources)
+		require.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "test 'TestAccGadget_basic' of resource 'gadget' applies 1 config step(s) but asserts nothing")
+		assert.Contains(t, messages[0], "Test: /provider/resource_gadget_test.go:9")
+	})
+
+	t.Run("diagnose lists definitions with weak tests", func(t *testing.T) {
+		fset := token.NewFileSet()
+		var files []*ast.File
+		for _, source := range [][2]string{
+			{"/provider/resource_gadget.go", untestedGadgetResourceSrc},
+			{"/provider/resource_gadget_test.go", gadgetResourceTestSrc},
+			{"/provider/resource_gadget_more_test.go", gadgetCheckedTestSrc},
+			{"/provider/resource_sprocket.go", untestedSprocketResourceSrc},
+			{"/provider/resource_sprocket_test.go", sprocketExpectErrorTestSrc},
+		} {
+			f, err := parser.ParseFile(fset, source[0], source[1], parser.ParseComments)
+			require.NoError(t, err)
+			files = append(files, f)
+		}
+		weak := diagnose.BuildRegistry(fset, files, settings).GetWeakResources()
+		require.Len(t, weak, 1)
+		assert.Equal(t, "gadget", weak[0].ResourceName)
+		assert.Equal(t, 2, weak[0].Tests)
+		assert.False(t, weak[0].OnlyWeak())
+		require.Len(t, weak[0].WeakTests, 1)
+		assert.Equal(t, "TestAccGadget_basic", weak[0].WeakTests[0].TestFunction)
+		assert.Equal(t, 1, weak[0].WeakTests[0].ConfigSteps)
+	})
+}
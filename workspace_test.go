@@ -0,0 +1,142 @@
+package tfprovidertest
+
+import (
+	"go/ast"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/example/tfprovidertest/internal/discovery"
+	"github.com/example/tfprovidertest/internal/workspace"
+)
+
+// writeFiles creates files (relative path to content) under root.
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+func TestWorkspaceFindAndResolve(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.work": `go 1.24
+
+use ./provider
+use (
+	./helpers // shared acceptance helpers
+	./missing
+)
+
+replace (
+	example.com/sdk v1.2.0 => ../sdk
+	example.com/remote => example.com/fork v1.0.0
+)
+`,
+		"provider/go.mod":                   "module example.com/provider\n\ngo 1.24\n",
+		"provider/internal/provider/doc.go": "package provider\n",
+		"helpers/go.mod":                    "// Shared helpers\nmodule \"example.com/provider/helpers\"\n",
+	})
+	t.Setenv(workspace.EnvGoWork, "")
+
+	ws, err := workspace.Find(filepath.Join(root, "provider", "internal", "provider"))
+	require.NoError(t, err)
+	require.NotNil(t, ws)
+	assert.Equal(t, filepath.Join(root, "go.work"), ws.File)
+
+	require.Len(t, ws.Modules, 3, "version-only replacements are not local modules")
+	assert.Equal(t, workspace.Module{Path: "example.com/provider", Dir: filepath.Join(root, "provider"), Source: workspace.SourceUse}, ws.Modules[0])
+	assert.Equal(t, workspace.Module{Path: "example.com/sdk", Dir: filepath.Join(filepath.Dir(root), "sdk"), Source: workspace.SourceReplace}, ws.Modules[2])
+	require.Len(t, ws.Problems, 1)
+	assert.Contains(t, ws.Problems[0], "./missing")
+
+	// The nested helpers module wins over its parent module path
+	dir, ok := ws.Resolve("example.com/provider/helpers/acctest")
+	require.True(t, ok)
+	assert.Equal(t, filepath.Join(root, "helpers", "acctest"), dir)
+
+	dir, ok = ws.Resolve("example.com/provider/internal/provider")
+	require.True(t, ok)
+	assert.Equal(t, filepath.Join(root, "provider", "internal", "provider"), dir)
+
+	_, ok = ws.Resolve("example.com/providerx")
+	assert.False(t, ok, "module paths match on path elements only")
+	_, ok = ws.Resolve("github.com/hashicorp/terraform-plugin-testing/helper/resource")
+	assert.False(t, ok)
+}
+
+func TestWorkspaceFindHonorsGOWORK(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.work":        "go 1.24\n\nuse ./mod\n",
+		"mod/go.mod":     "module example.com/mod\n",
+		"alt/other.work": "go 1.24\n",
+	})
+
+	t.Setenv(workspace.EnvGoWork, "off")
+	ws, err := workspace.Find(filepath.Join(root, "mod"))
+	require.NoError(t, err)
+	assert.Nil(t, ws)
+
+	t.Setenv(workspace.EnvGoWork, filepath.Join(root, "alt", "other.work"))
+	ws, err = workspace.Find(filepath.Join(root, "mod"))
+	require.NoError(t, err)
+	require.NotNil(t, ws)
+	assert.Empty(t, ws.Modules)
+
+	t.Setenv(workspace.EnvGoWork, "")
+	ws, err = workspace.Find(t.TempDir())
+	require.NoError(t, err)
+	assert.Nil(t, ws, "no go.work above the directory")
+}
+
+func TestWorkspaceLoadRejectsMalformedFile(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"go.work": "go 1.24\n\nuse (\n\t./a\n"})
+	_, err := workspace.Load(filepath.Join(root, "go.work"))
+	assert.ErrorContains(t, err, "unterminated use block")
+}
+
+func TestFindExportedTestHelpers(t *testing.T) {
+	sources := map[string]string{
+		"/helpers/acctest/acctest.go": `package acctest
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func RunWidgetTest(t *testing.T, config string) {
+	resource.ParallelTest(t, resource.TestCase{})
+}
+
+func runInternal(t *testing.T) {
+	resource.Test(t, resource.TestCase{})
+}
+
+func PreCheck(t *testing.T) {}
+`,
+		"/helpers/acctest/acctest_test.go": `package acctest
+
+func TestOnlyHelper(t *testing.T) {
+	resource.Test(t, resource.TestCase{})
+}
+`,
+	}
+	fset, files := parseSources(t, []string{"/helpers/acctest/acctest.go", "/helpers/acctest/acctest_test.go"}, sources)
+
+	helpers := discovery.FindExportedTestHelpers(files, fset)
+	names := make([]string, 0, len(helpers))
+	for _, h := range helpers {
+		names = append(names, h.Name)
+		assert.IsType(t, &ast.FuncDecl{}, h.FuncDecl)
+	}
+	assert.Equal(t, []string{"RunWidgetTest"}, names)
+}